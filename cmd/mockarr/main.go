@@ -0,0 +1,61 @@
+// Command mockarr runs a fake Radarr or Sonarr instance for trying Healarr
+// without touching a real *arr setup. Point it at a media directory and wire
+// a scan path at it with the seeder:
+//
+//	mockarr --type radarr --library /media/movies --port 7878
+//	seeder --mockarr-url http://localhost:7878 --library /media/movies
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mescon/Healarr/internal/mockarr"
+)
+
+func main() {
+	port := flag.Int("port", 7878, "Port to listen on")
+	instanceType := flag.String("type", mockarr.TypeRadarr, "Instance type to emulate: radarr or sonarr")
+	apiKey := flag.String("api-key", "mockarr", "API key clients must send (empty disables the check)")
+	library := flag.String("library", "", "Media directory the library is built from")
+	latency := flag.Duration("latency", 0, "Delay added to every request (e.g. 200ms)")
+	errorRate := flag.Float64("error-rate", 0, "Fraction of requests answered with HTTP 500 (0-1)")
+	downloadTime := flag.Duration("download-time", 30*time.Second, "How long a searched release takes to download")
+	replacementFile := flag.String("replacement-file", "", "Healthy file copied over deleted media on import (modifies the library on disk)")
+	seed := flag.Int64("seed", 0, "Random seed for error injection (0 = time-based)")
+	flag.Parse()
+
+	server, err := mockarr.New(mockarr.Config{
+		Type:            *instanceType,
+		APIKey:          *apiKey,
+		Library:         *library,
+		Latency:         *latency,
+		ErrorRate:       *errorRate,
+		DownloadTime:    *downloadTime,
+		ReplacementFile: *replacementFile,
+		Seed:            *seed,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mockarr: %v\n", err)
+		os.Exit(1)
+	}
+
+	media, files := server.Counts()
+	log.Printf("Mock %s listening on :%d with %d media items and %d files from %q", *instanceType, *port, media, files, *library)
+	if *replacementFile == "" {
+		log.Printf("Library on disk is read-only; pass --replacement-file to simulate real deletes and imports")
+	} else {
+		log.Printf("WARNING: deletes remove files from %s and imports overwrite them with %s", *library, *replacementFile)
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Fatal(httpServer.ListenAndServe())
+}
@@ -1,26 +1,46 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"path/filepath"
 	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3" // Register CGo SQLite driver for database/sql
+
+	"github.com/mescon/Healarr/internal/crypto"
+	healarrdb "github.com/mescon/Healarr/internal/db"
 )
 
 // sqlInsertEvent is the SQL statement for inserting events.
 const sqlInsertEvent = "INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES (?, ?, ?, ?)"
 
 func main() {
-	db, err := sql.Open("sqlite3", "./healarr.db")
+	dbPath := flag.String("db", "./healarr.db", "Database to seed (run the server once first to create it)")
+	mockURL := flag.String("mockarr-url", "", "Wire a scan path at a mockarr instance instead of seeding demo events")
+	mockType := flag.String("mockarr-type", "radarr", "Type of the mockarr instance: radarr or sonarr")
+	mockAPIKey := flag.String("mockarr-api-key", "mockarr", "API key of the mockarr instance")
+	library := flag.String("library", "", "Media directory served by mockarr, added as the scan path")
+	flag.Parse()
+
+	db, err := sql.Open("sqlite3", *dbPath)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
 
+	if *mockURL != "" {
+		if err := seedMockArr(db, *mockURL, *mockType, *mockAPIKey, *library); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	fmt.Println("Seeding database...")
 
 	// Seed Scans
@@ -102,5 +122,58 @@ func main() {
 		}
 	}
 
+	// The events were written without the event bus, so project them now
+	if _, err := healarrdb.RebuildCorruptionSummary(context.Background(), db); err != nil {
+		log.Printf("Failed to rebuild corruption summary: %v", err)
+	}
+
 	fmt.Println("Seeding complete.")
 }
+
+// seedMockArr adds (or updates) an *arr instance pointing at mockarr and a scan
+// path for its library, with auto-remediation on so the whole pipeline runs.
+func seedMockArr(db *sql.DB, url, instanceType, apiKey, library string) error {
+	if library == "" {
+		return fmt.Errorf("--library is required with --mockarr-url")
+	}
+	library, err := filepath.Abs(library)
+	if err != nil {
+		return err
+	}
+	encryptedKey, err := crypto.Encrypt(apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt API key: %w", err)
+	}
+
+	var instanceID int64
+	err = db.QueryRow("SELECT id FROM arr_instances WHERE url = ?", url).Scan(&instanceID)
+	switch {
+	case err == sql.ErrNoRows:
+		res, err := db.Exec("INSERT INTO arr_instances (name, type, url, api_key, enabled) VALUES (?, ?, ?, ?, 1)",
+			"Mock "+instanceType, instanceType, url, encryptedKey)
+		if err != nil {
+			return fmt.Errorf("failed to insert arr instance: %w", err)
+		}
+		if instanceID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	case err != nil:
+		return fmt.Errorf("failed to look up arr instance: %w", err)
+	default:
+		if _, err := db.Exec("UPDATE arr_instances SET type = ?, api_key = ?, enabled = 1 WHERE id = ?", instanceType, encryptedKey, instanceID); err != nil {
+			return fmt.Errorf("failed to update arr instance: %w", err)
+		}
+	}
+
+	// mockarr reads the same directory, so local and *arr paths are identical
+	_, err = db.Exec(`INSERT INTO scan_paths (local_path, arr_path, arr_instance_id, enabled, auto_remediate, dry_run)
+		VALUES (?, ?, ?, 1, 1, 0)
+		ON CONFLICT(local_path) DO UPDATE SET arr_path = excluded.arr_path, arr_instance_id = excluded.arr_instance_id`,
+		library, library, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to insert scan path: %w", err)
+	}
+
+	fmt.Printf("Wired scan path %s to mock %s at %s (instance %d)\n", library, instanceType, url, instanceID)
+	return nil
+}
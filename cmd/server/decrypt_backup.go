@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/backup"
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/crypto"
+)
+
+// decryptBackup decrypts an encrypted backup downloaded from a backup target
+// next to it, with the .enc suffix removed. The passphrase is read from
+// HEALARR_BACKUP_ENCRYPTION_KEY (or the env file).
+func decryptBackup(path string) error {
+	passphrase := config.Load().BackupEncryptionKey
+	if passphrase == "" {
+		return errors.New("HEALARR_BACKUP_ENCRYPTION_KEY is not set")
+	}
+
+	output := strings.TrimSuffix(path, backup.EncryptedSuffix)
+	if output == path {
+		output = path + ".db"
+	}
+	in, err := os.Open(path) // #nosec G304 -- path given on the command line
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec G304 -- derived from the command line path
+	if err != nil {
+		return err
+	}
+
+	if err := crypto.DecryptStream(out, in, passphrase); err != nil {
+		out.Close()
+		os.Remove(output)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Decrypted backup written to %s\n", output)
+	return nil
+}
@@ -9,19 +9,27 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/mescon/Healarr/internal/api"
+	"github.com/mescon/Healarr/internal/backup"
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/i18n"
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
 	"github.com/mescon/Healarr/internal/metrics"
 	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/remote"
 	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/supervisor"
 	"github.com/mescon/Healarr/internal/web"
 )
 
@@ -37,6 +45,8 @@ type cliFlags struct {
 	databasePath         *string
 	webDir               *string
 	dryRun               *bool
+	testMode             *bool
+	offline              *bool
 	retentionDays        *int
 	maxRetries           *int
 	verificationTimeout  *time.Duration
@@ -44,6 +54,10 @@ type cliFlags struct {
 	staleThreshold       *time.Duration
 	arrRateLimitRPS      *float64
 	arrRateLimitBurst    *int
+	service              *string
+	decryptBackup        *string
+	recoverDB            *bool
+	diagnoseDB           *bool
 }
 
 // parseFlags defines and parses command line flags
@@ -57,6 +71,8 @@ func parseFlags() cliFlags {
 		databasePath:         flag.String("database-path", "", "Database file path (env: HEALARR_DATABASE_PATH)"),
 		webDir:               flag.String("web-dir", "", "Web assets directory (env: HEALARR_WEB_DIR)"),
 		dryRun:               flag.Bool("dry-run", false, "Dry run mode - no files deleted (env: HEALARR_DRY_RUN)"),
+		testMode:             flag.Bool("test-mode", false, "Enable the failure-injection API for pipeline testing (env: HEALARR_TEST_MODE)"),
+		offline:              flag.Bool("offline", false, "Offline mode - no update checks or other calls beyond configured services (env: HEALARR_OFFLINE_MODE)"),
 		retentionDays:        flag.Int("retention-days", -1, "Days to keep old data, 0 to disable pruning (env: HEALARR_RETENTION_DAYS, default: 90)"),
 		maxRetries:           flag.Int("max-retries", 0, "Default max remediation retries (env: HEALARR_DEFAULT_MAX_RETRIES, default: 3)"),
 		verificationTimeout:  flag.Duration("verification-timeout", 0, "Max time to wait for file replacement (env: HEALARR_VERIFICATION_TIMEOUT, default: 72h)"),
@@ -64,6 +80,10 @@ func parseFlags() cliFlags {
 		staleThreshold:       flag.Duration("stale-threshold", 0, "Auto-fix items Healarr lost track of after this time (env: HEALARR_STALE_THRESHOLD, default: 24h)"),
 		arrRateLimitRPS:      flag.Float64("arr-rate-limit", 0, "Max requests per second to *arr APIs (env: HEALARR_ARR_RATE_LIMIT_RPS, default: 5)"),
 		arrRateLimitBurst:    flag.Int("arr-rate-burst", 0, "Burst size for *arr rate limiting (env: HEALARR_ARR_RATE_LIMIT_BURST, default: 10)"),
+		service:              flag.String("service", "", "Manage the Windows service: install, uninstall, start, stop, restart"),
+		decryptBackup:        flag.String("decrypt-backup", "", "Decrypt an uploaded .db.enc backup with HEALARR_BACKUP_ENCRYPTION_KEY and exit"),
+		recoverDB:            flag.Bool("recover", false, "If the database fails to open, checkpoint its write-ahead log or restore the latest backup"),
+		diagnoseDB:           flag.Bool("diagnose-db", false, "Check why the database fails to open and exit"),
 	}
 	flag.BoolVar(flags.showVersion, "v", false, "Print version and exit (shorthand)")
 	flag.Parse()
@@ -80,6 +100,8 @@ func applyFlagOverrides(flags cliFlags) {
 		DatabasePath:         flags.databasePath,
 		WebDir:               flags.webDir,
 		DryRunMode:           flags.dryRun,
+		TestMode:             flags.testMode,
+		OfflineMode:          flags.offline,
 		DefaultMaxRetries:    flags.maxRetries,
 		VerificationTimeout:  flags.verificationTimeout,
 		VerificationInterval: flags.verificationInterval,
@@ -94,33 +116,72 @@ func applyFlagOverrides(flags cliFlags) {
 	config.ApplyFlags(flagOverrides)
 }
 
-// logConfiguration logs the current configuration
-func logConfiguration(cfg *config.Config) {
+// logConfiguration logs the current configuration and records it in the
+// startup report.
+func logConfiguration(cfg *config.Config, report *services.StartupReport) {
 	logger.Infof("Configuration:")
-	logger.Infof("  Port: %s", cfg.Port)
-	logger.Infof("  Log Level: %s", cfg.LogLevel)
-	logger.Infof("  Data Directory: %s", cfg.DataDir)
-	logger.Infof("  Database: %s", cfg.DatabasePath)
-	logger.Infof("  Log Directory: %s", cfg.LogDir)
+	if cfg.ListenSocket != "" {
+		report.Setting("Listen Socket", "%s", cfg.ListenSocket)
+	} else {
+		report.Setting("Port", "%s", cfg.Port)
+	}
+	if len(cfg.TrustedProxies) > 0 {
+		report.Setting("Trusted Proxies", "%s", strings.Join(cfg.TrustedProxies, ", "))
+	}
+	report.Setting("Log Level", "%s", cfg.LogLevel)
+	report.Setting("Locale", "%s", cfg.Locale)
+	report.Setting("Data Directory", "%s", cfg.DataDir)
+	report.Setting("Database", "%s", cfg.DatabasePath)
+	report.Setting("Log Directory", "%s", cfg.LogDir)
 	if !web.HasEmbeddedAssets() {
-		logger.Infof("  Web Directory: %s", cfg.WebDir)
+		report.Setting("Web Directory", "%s", cfg.WebDir)
 	}
-	logger.Infof("  Verification Timeout: %s", cfg.VerificationTimeout)
-	logger.Infof("  Verification Interval: %s", cfg.VerificationInterval)
-	logger.Infof("  Stale Threshold: %s", cfg.StaleThreshold)
-	logger.Infof("  Default Max Retries: %d", cfg.DefaultMaxRetries)
-	logger.Infof("  *arr API Rate Limit: %.1f req/s (burst: %d)", cfg.ArrRateLimitRPS, cfg.ArrRateLimitBurst)
+	report.Setting("Verification Timeout", "%s", cfg.VerificationTimeout)
+	report.Setting("Verification Interval", "%s", cfg.VerificationInterval)
+	report.Setting("Stale Threshold", "%s", cfg.StaleThreshold)
+	report.Setting("Default Max Retries", "%d", cfg.DefaultMaxRetries)
+	report.Setting("*arr API Rate Limit", "%.1f req/s (burst: %d)", cfg.ArrRateLimitRPS, cfg.ArrRateLimitBurst)
 	if cfg.RetentionDays > 0 {
-		logger.Infof("  Data Retention: %d days", cfg.RetentionDays)
+		report.Setting("Data Retention", "%d days", cfg.RetentionDays)
+	} else {
+		report.Setting("Data Retention", "disabled (no automatic pruning)")
+	}
+	policy := retentionPolicy(cfg)
+	if policy != db.UniformRetention(cfg.RetentionDays) {
+		report.Setting("Retention Overrides", "resolved %s, failed %s, scan events %s, scans %s, scan summaries %s",
+			formatRetention(policy.ResolvedDays), formatRetention(policy.FailedDays), formatRetention(policy.ScanEventDays),
+			formatRetention(policy.ScanDays), formatRetention(policy.ScanSummaryDays))
+	}
+	if cfg.DeletedRetentionDays > 0 {
+		report.Setting("Deleted Config Retention", "%d days", cfg.DeletedRetentionDays)
 	} else {
-		logger.Infof("  Data Retention: disabled (no automatic pruning)")
+		report.Setting("Deleted Config Retention", "kept until restored")
+	}
+	if cfg.SampleExtraction {
+		report.Setting("Sample Extraction", "%d MB per file to %s (%d MB / %d days kept)",
+			cfg.SampleMaxMB, cfg.SampleDir, cfg.SampleDirMaxMB, cfg.SampleRetentionDays)
 	}
 	if cfg.DryRunMode {
-		logger.Infof("  ⚠️  DRY-RUN MODE: ENABLED (no files will be deleted)")
+		report.Setting("⚠️  DRY-RUN MODE", "ENABLED (no files will be deleted)")
+	}
+	if cfg.OfflineMode {
+		report.Setting("Offline Mode", "ENABLED (update checks disabled)")
 	}
-	if !crypto.EncryptionEnabled() {
+	if cfg.TestMode {
+		report.Setting("Test Mode", "ENABLED")
+		logger.Warnf("  ⚠️  TEST MODE: ENABLED (failure injection API at /api/test-mode - do not use in production)")
+	}
+	if crypto.EncryptionEnabled() {
+		report.Setting("Encryption at Rest", "enabled")
+	} else {
+		report.Setting("Encryption at Rest", "disabled")
 		logger.Warnf("HEALARR_ENCRYPTION_KEY is not set — *arr API keys and notification secrets are stored in plaintext. Set this variable to enable AES-256 encryption at rest.")
 	}
+
+	// Configuration warnings are printed to stderr; log them for the report too
+	for _, w := range config.ValidateAndWarn() {
+		report.Warn("%s (current: %s, recommended: %s)", w.Message, w.Current, w.Recommended)
+	}
 }
 
 // serviceDeps holds all initialized services for dependency injection
@@ -130,6 +191,9 @@ type serviceDeps struct {
 	pathMapper           integration.PathMapper
 	healthChecker        integration.HealthChecker
 	arrClient            integration.ArrClient
+	faults               *integration.FaultInjector
+	toolChecker          *integration.ToolChecker
+	remotePaths          *remote.Registry
 	scannerService       *services.ScannerService
 	remediatorService    *services.RemediatorService
 	verifierService      *services.VerifierService
@@ -139,156 +203,334 @@ type serviceDeps struct {
 	schedulerService     *services.SchedulerService
 	eventReplayService   *services.EventReplayService
 	notifierService      *notifier.Notifier
+	webhookOutbox        *notifier.WebhookOutbox
+	mqttPublisher        atomic.Pointer[notifier.MQTTPublisher] // replaced on config reload
 	metricsService       *metrics.MetricsService
+	reportService        *services.ReportService
+	maintenanceService   *services.MaintenanceService
+	backupService        *backup.Service
+	systemPause          *services.SystemPause
+	pathGroups           *services.PathGroupService
+	dashboard            *services.DashboardSummary
+	telemetry            *services.TelemetryService
+	samples              *services.SampleExtractor
+	mappingOverrides     *services.MappingOverrides
+	resolutionAudit      *services.ResolutionAuditService
 	stopCheckpoint       func()
 }
 
-// initDatabase initializes the database and starts background maintenance goroutines.
-func initDatabase(cfg *config.Config) (*db.Repository, func()) {
+// initDatabase initializes the database and starts background maintenance
+// goroutines. Backups are uploaded to the configured backup targets.
+func initDatabase(cfg *config.Config, attemptRecovery bool, report *services.StartupReport) (*db.Repository, *backup.Service, func()) {
 	logger.Infof("Initializing database: %s", cfg.DatabasePath)
-	repo, err := db.NewRepository(cfg.DatabasePath)
+	open := func() (*db.Repository, error) {
+		return db.NewRepositoryWithOptions(cfg.DatabasePath, db.SQLiteOptions{
+			JournalMode: cfg.DBJournalMode,
+			Synchronous: cfg.DBSynchronous,
+			CacheSizeMB: cfg.DBCacheSizeMB,
+			MmapSizeMB:  cfg.DBMmapSizeMB,
+			BusyTimeout: cfg.DBBusyTimeout,
+		})
+	}
+	repo, err := open()
 	if err != nil {
 		logger.Errorf("Failed to initialize database: %v", err)
-		os.Exit(1)
+		if repo, err = recoverDatabase(cfg, attemptRecovery, open, report); err != nil {
+			os.Exit(1)
+		}
 	}
-	logger.Infof("✓ Database initialized successfully")
+	report.Component("Database", "schema up to date")
+
+	// Separate query-only pool so the UI stays responsive while scans write events
+	if cfg.DBReadConnections > 0 {
+		if err := repo.OpenReadPool(cfg.DBReadConnections, cfg.DBReadBusyTimeout); err != nil {
+			logger.Warnf("Failed to open database read pool, reads will share the main pool: %v", err)
+		}
+	}
+
+	backupService := backup.NewService(repo.DB, func() (string, error) { return repo.Backup(cfg.DatabasePath) })
+	backupService.SetEncryptionKey(cfg.BackupEncryptionKey)
 
 	// Create a database backup on startup
 	if backupPath, err := repo.Backup(cfg.DatabasePath); err != nil {
-		logger.Errorf("Failed to create startup backup: %v", err)
+		report.Failed("Startup backup", err)
 	} else {
-		logger.Infof("✓ Database backup created: %s", backupPath)
+		report.Component("Startup backup", backupPath)
+		go backupService.UploadAll(context.Background(), backupPath)
 	}
 
 	// Start scheduled backup goroutine (every 6 hours)
-	go runScheduledBackups(repo, cfg.DatabasePath)
+	go runScheduledBackups(repo, cfg.DatabasePath, backupService)
 
 	// Start periodic WAL checkpoint (every 5 minutes)
 	stopCheckpoint := repo.StartPeriodicCheckpoint(5 * time.Minute)
 	logger.Debugf("✓ Periodic WAL checkpoint started (every 5 minutes)")
 
-	// Start scheduled maintenance goroutine (daily at 3 AM local time)
-	go runScheduledMaintenance(repo, cfg.RetentionDays)
-
-	return repo, stopCheckpoint
+	return repo, backupService, stopCheckpoint
 }
 
-// runScheduledBackups runs database backups every 6 hours.
-func runScheduledBackups(repo *db.Repository, dbPath string) {
+// runScheduledBackups runs database backups every 6 hours and uploads them to
+// the backup targets.
+func runScheduledBackups(repo *db.Repository, dbPath string, backupService *backup.Service) {
 	ticker := time.NewTicker(6 * time.Hour)
 	defer ticker.Stop()
 	for range ticker.C {
-		if _, err := repo.Backup(dbPath); err != nil {
+		backupPath, err := repo.Backup(dbPath)
+		if err != nil {
 			logger.Errorf("Scheduled backup failed: %v", err)
+			continue
 		}
+		backupService.UploadAll(context.Background(), backupPath)
 	}
 }
 
-// runScheduledMaintenance runs database maintenance daily at 3 AM local time.
-func runScheduledMaintenance(repo *db.Repository, retentionDays int) {
-	for {
-		sleepDuration := timeUntilNext3AM()
-		logger.Debugf("Next database maintenance scheduled in %v", sleepDuration)
-		time.Sleep(sleepDuration)
-
-		if err := repo.RunMaintenance(retentionDays); err != nil {
-			logger.Errorf("Scheduled maintenance failed: %v", err)
-		}
+// retentionPolicy builds the maintenance retention policy from the config.
+// Category overrides below zero use the default retention.
+func retentionPolicy(cfg *config.Config) db.RetentionPolicy {
+	policy := db.UniformRetention(cfg.RetentionDays)
+	if cfg.ResolvedRetentionDays >= 0 {
+		policy.ResolvedDays = cfg.ResolvedRetentionDays
 	}
+	if cfg.FailedRetentionDays >= 0 {
+		policy.FailedDays = cfg.FailedRetentionDays
+	}
+	if cfg.ScanEventRetentionDays >= 0 {
+		policy.ScanEventDays = cfg.ScanEventRetentionDays
+	}
+	if cfg.ScanRetentionDays >= 0 {
+		policy.ScanDays = cfg.ScanRetentionDays
+	}
+	policy.ScanSummaryDays = cfg.ScanSummaryRetentionDays
+	return policy
 }
 
-// timeUntilNext3AM calculates the duration until the next 3 AM local time.
-func timeUntilNext3AM() time.Duration {
-	now := time.Now()
-	next3AM := time.Date(now.Year(), now.Month(), now.Day(), 3, 0, 0, 0, now.Location())
-	if now.After(next3AM) {
-		next3AM = next3AM.Add(24 * time.Hour)
+// formatRetention describes a retention period for the startup log.
+func formatRetention(days int) string {
+	if days <= 0 {
+		return "forever"
 	}
-	return next3AM.Sub(now)
+	return fmt.Sprintf("%d days", days)
 }
 
-// initIntegration initializes integration components (path mapper, health checker, arr client).
-func initIntegration(sqlDB *sql.DB, cfg *config.Config) (integration.PathMapper, integration.HealthChecker, integration.ArrClient) {
+// initIntegration initializes integration components (path mapper, health checker, arr client)
+// and the registry of remote scan paths, whose files the health checker checks over WebDAV or SFTP.
+func initIntegration(sqlDB *sql.DB, eb *eventbus.EventBus, cfg *config.Config, report *services.StartupReport) (integration.PathMapper, integration.HealthChecker, integration.ArrClient, *remote.Registry) {
 	logger.Infof("Initializing Path Mapper (maps *arr paths to local paths)...")
 	pathMapper, err := integration.NewPathMapper(sqlDB)
 	if err != nil {
-		logger.Infof("⚠ Path Mapper initialized with no configured paths (configure in /config)")
+		report.Degraded("Path Mapper", "no configured paths (configure in /config)")
 	} else {
-		logger.Infof("✓ Path Mapper initialized")
+		report.Component("Path Mapper", "")
 	}
 
 	logger.Infof("Initializing Health Checker (corruption detection engine)...")
 	healthChecker := integration.NewHealthCheckerWithPaths(
 		cfg.FFprobePath, cfg.FFmpegPath, cfg.MediaInfoPath, cfg.HandBrakePath,
 	)
-	logger.Infof("✓ Health Checker initialized (ffprobe, mediainfo, handbrake)")
+	healthChecker.SampleSegments = cfg.SampleSegments
+	healthChecker.SampleDuration = cfg.SampleDuration
+	healthChecker.TransportStream = &integration.TransportStreamLimits{
+		MaxContinuityErrors: cfg.TSMaxContinuityErrors,
+		MaxDecodeErrors:     cfg.TSMaxDecodeErrors,
+		MaxTimestampGap:     cfg.TSMaxTimestampGap,
+	}
+	healthChecker.Supervisor = integration.NewToolSupervisor(integration.ToolLimits{
+		MaxRSSBytes:      uint64(max(cfg.ToolMaxRSSMB, 0)) << 20,
+		Retries:          cfg.ToolRetries,
+		FailureThreshold: cfg.ToolFailureThreshold,
+		DisableFor:       cfg.ToolDisableDuration,
+	})
+	remotePaths := remote.NewRegistry(sqlDB)
+	report.Component("Health Checker", "ffprobe, mediainfo, handbrake, remote header checks")
 
 	logger.Infof("Initializing *arr Client (Sonarr/Radarr/Whisparr integration)...")
 	arrClient := integration.NewArrClient(sqlDB)
-	logger.Infof("✓ *arr Client initialized")
+	if cfg.ArrRecordDir != "" {
+		if err := arrClient.EnableRecording(cfg.ArrRecordDir); err != nil {
+			report.Failed("*arr API recording", err)
+		} else {
+			logger.Warnf("⚠️  Recording *arr API traffic to %s (debugging only - turn off when done)", cfg.ArrRecordDir)
+		}
+	}
+	arrClient.RequestBudgets().SetWarningHandler(func(w integration.RequestBudgetWarning) {
+		publishRequestBudgetWarning(eb, w)
+	})
+	report.Component("*arr Client", "")
+
+	return pathMapper, remote.NewChecker(healthChecker, remotePaths), arrClient, remotePaths
+}
+
+// publishRequestBudgetWarning publishes an ArrRequestBudgetWarning for an
+// instance nearing or at its daily request budget.
+func publishRequestBudgetWarning(eb *eventbus.EventBus, w integration.RequestBudgetWarning) {
+	reason := fmt.Sprintf("%s used %d of its %d daily requests on %s", w.InstanceName, w.Used, w.Limit, w.Day)
+	if w.Exhausted {
+		reason += " - the budget is used up"
+	}
+	if err := eb.Publish(domain.Event{
+		AggregateType: "health",
+		AggregateID:   "instance_" + w.InstanceName,
+		EventType:     domain.ArrRequestBudgetWarning,
+		EventData: map[string]interface{}{
+			"instance_id":   w.InstanceID,
+			"instance_name": w.InstanceName,
+			"day":           w.Day,
+			"used":          w.Used,
+			"limit":         w.Limit,
+			"exhausted":     w.Exhausted,
+			"reason":        reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish ArrRequestBudgetWarning for %s: %v", w.InstanceName, err)
+	}
+}
 
-	return pathMapper, healthChecker, arrClient
+// initToolChecker checks which detection tools are installed. Healarr still
+// starts without them, but scans of paths that need a missing tool are skipped.
+func initToolChecker(cfg *config.Config, report *services.StartupReport) *integration.ToolChecker {
+	toolChecker := integration.NewToolCheckerWithPaths(
+		cfg.FFprobePath, cfg.FFmpegPath, cfg.MediaInfoPath, cfg.HandBrakePath,
+	)
+	report.SetTools(toolChecker.CheckAllTools())
+	if missing := toolChecker.GetMissingRequiredTools(); len(missing) > 0 {
+		sort.Strings(missing)
+		report.Degraded("Detection tools", fmt.Sprintf("not found: %s — scan paths that need them will be skipped", strings.Join(missing, ", ")))
+	}
+	return toolChecker
 }
 
 // initCoreServices initializes all core services.
 func initCoreServices(
 	sqlDB *sql.DB, eb *eventbus.EventBus,
 	healthChecker integration.HealthChecker, pathMapper integration.PathMapper,
-	arrClient integration.ArrClient, cfg *config.Config,
+	arrClient integration.ArrClient, faults *integration.FaultInjector, cfg *config.Config, report *services.StartupReport,
 ) (*services.ScannerService, *services.RemediatorService, *services.VerifierService,
 	*services.MonitorService, *services.HealthMonitorService, *services.RecoveryService,
 	*services.SchedulerService, *services.EventReplayService) {
 	logger.Infof("Initializing core services...")
 
-	scannerService := services.NewScannerService(sqlDB, eb, healthChecker, pathMapper)
-	logger.Infof("✓ Scanner Service (detects corrupted files)")
+	scannerService := services.NewScannerService(sqlDB, eb, faults.WrapHealthChecker(healthChecker, integration.StageDetect), pathMapper)
+	scannerService.SetFalsePositiveSuppression(cfg.SuppressFalsePositives)
+	scannerService.SetScanResultsRetention(cfg.ScanResultsPerPath)
+	scannerService.SetFileQueueMemory(cfg.ScanFileQueueMemory)
+	scannerService.SetScanCheckpoint(cfg.ScanCheckpointFiles, cfg.ScanCheckpointInterval)
+	scannerService.SetHDRMetadataPolicy(cfg.HDRMetadataPolicy)
+	scannerService.SetDetectionConsensus(cfg.DetectionConsensus)
+	scannerService.SetArrClient(arrClient)
+	report.Component("Scanner Service", "detects corrupted files")
 
 	remediatorService := services.NewRemediatorService(eb, arrClient, pathMapper, sqlDB)
-	logger.Infof("✓ Remediator Service (fixes corrupted files via *arr)")
+	if err := remediatorService.SetThrottle(services.RemediationThrottleConfig{
+		MaxConcurrent:   cfg.RemediationMaxConcurrent,
+		SearchesPerHour: cfg.RemediationSearchesPerHour,
+		Window:          cfg.RemediationWindow,
+	}); err != nil {
+		report.Failed("Remediation window", err)
+	} else if cfg.RemediationWindow != "" {
+		report.Component("Remediation window", cfg.RemediationWindow)
+	}
+	if breakers, ok := arrClient.(services.CircuitBreakerSource); ok {
+		remediatorService.SetDetectionOnly(breakers, cfg.DetectionOnlyAfter)
+	}
+	remediatorService.SetUntrackedFileAction(cfg.UntrackedFileAction, cfg.QuarantineDir)
+	remediatorService.SetDataBudget(int64(cfg.RemediationMonthlyBudgetGB * 1e9))
+	remediatorService.SetDataBudgetAction(cfg.RemediationBudgetAction)
+	report.Component("Remediator Service", "fixes corrupted files via *arr")
 
-	verifierService := services.NewVerifierService(eb, healthChecker, pathMapper, arrClient, sqlDB)
-	logger.Infof("✓ Verifier Service (verifies remediation success)")
+	verifierService := services.NewVerifierService(eb, faults.WrapHealthChecker(healthChecker, integration.StageVerify), pathMapper, arrClient, sqlDB)
+	report.Component("Verifier Service", "verifies remediation success")
 
 	monitorService := services.NewMonitorService(eb, sqlDB)
-	logger.Infof("✓ Monitor Service (tracks corruption lifecycle)")
+	report.Component("Monitor Service", "tracks corruption lifecycle")
 
 	healthMonitorService := services.NewHealthMonitorService(sqlDB, eb, arrClient, cfg.StaleThreshold)
-	logger.Infof("✓ Health Monitor Service (detects stuck remediations)")
+	healthMonitorService.SetResolutionSLA(cfg.ResolutionSLA)
+	healthMonitorService.SetInstanceHealthInterval(cfg.ArrHealthInterval)
+	report.Component("Health Monitor Service", "detects stuck remediations")
 
 	recoveryService := services.NewRecoveryService(sqlDB, eb, arrClient, pathMapper, healthChecker, cfg.StaleThreshold)
-	logger.Infof("✓ Recovery Service (recovers stale remediations on startup)")
+	report.Component("Recovery Service", "recovers stale remediations on startup")
 
 	schedulerService := services.NewSchedulerService(sqlDB, scannerService)
-	logger.Infof("✓ Scheduler Service (cron-based scans)")
+	schedulerService.SetConcurrency(cfg.ScheduledScanConcurrency, cfg.ScheduledScanPreemption)
+	report.Component("Scheduler Service", "cron-based scans")
 
 	eventReplayService := services.NewEventReplayService(sqlDB, eb)
-	logger.Infof("✓ Event Replay Service (replays unprocessed events on startup)")
+	report.Component("Event Replay Service", "replays unprocessed events on startup")
 
 	return scannerService, remediatorService, verifierService, monitorService,
 		healthMonitorService, recoveryService, schedulerService, eventReplayService
 }
 
 // initNotifierAndMetrics initializes the notification and metrics services.
-func initNotifierAndMetrics(sqlDB *sql.DB, eb *eventbus.EventBus) (*notifier.Notifier, *metrics.MetricsService) {
+func initNotifierAndMetrics(sqlDB *sql.DB, eb *eventbus.EventBus, report *services.StartupReport) (*notifier.Notifier, *metrics.MetricsService) {
 	logger.Infof("Initializing Notification Service...")
 	notifierService := notifier.NewNotifier(sqlDB, eb)
 	if err := notifierService.Start(); err != nil {
-		logger.Errorf("Failed to start notification service: %v", err)
+		report.Failed("Notification Service", err)
 	} else {
-		logger.Infof("✓ Notification Service (alerts for events)")
+		report.Component("Notification Service", "alerts for events")
 	}
 
 	logger.Infof("Initializing Metrics Service...")
 	metricsService := metrics.NewMetricsService(eb)
 	metricsService.Start()
-	logger.Infof("✓ Metrics Service (Prometheus endpoint at /metrics)")
+	report.Component("Metrics Service", "Prometheus endpoint at /metrics")
 
 	return notifierService, metricsService
 }
 
+// initWebhookOutbox initializes the outgoing webhook outbox.
+func initWebhookOutbox(sqlDB *sql.DB, eb *eventbus.EventBus, report *services.StartupReport) *notifier.WebhookOutbox {
+	logger.Infof("Initializing Webhook Outbox...")
+	outbox := notifier.NewWebhookOutbox(sqlDB, eb)
+	if err := outbox.Start(); err != nil {
+		report.Failed("Webhook Outbox", err)
+		return nil
+	}
+	report.Component("Webhook Outbox", "event forwarding to external automation")
+	return outbox
+}
+
+// initMQTT initializes MQTT event publishing when a broker is configured.
+// The report is nil when the publisher is started again on a reload.
+func initMQTT(sqlDB *sql.DB, eb *eventbus.EventBus, cfg *config.Config, report *services.StartupReport) *notifier.MQTTPublisher {
+	if cfg.MQTTBroker == "" {
+		return nil
+	}
+
+	events, unknown := notifier.ParseEventList(cfg.MQTTEvents)
+	if len(unknown) > 0 {
+		logger.Warnf("HEALARR_MQTT_EVENTS contains unknown event types (ignored): %s", strings.Join(unknown, ", "))
+	}
+
+	logger.Infof("Initializing MQTT Publisher...")
+	publisher := notifier.NewMQTTPublisher(sqlDB, eb, notifier.MQTTConfig{
+		Broker:             cfg.MQTTBroker,
+		Username:           cfg.MQTTUsername,
+		Password:           cfg.MQTTPassword,
+		ClientID:           cfg.MQTTClientID,
+		TopicPrefix:        cfg.MQTTTopicPrefix,
+		QoS:                byte(cfg.MQTTQoS),
+		Events:             events,
+		CAFile:             cfg.MQTTCAFile,
+		InsecureSkipVerify: cfg.MQTTTLSInsecure,
+		Discovery:          cfg.MQTTDiscovery,
+		DiscoveryPrefix:    cfg.MQTTDiscoveryPrefix,
+		SoftwareVersion:    config.Version,
+	})
+	if err := publisher.Start(); err != nil {
+		report.Failed("MQTT Publisher", err)
+		return nil
+	}
+	report.Component("MQTT Publisher", fmt.Sprintf("%s, %d event types", cfg.MQTTBroker, len(events)))
+	return publisher
+}
+
 // startBackgroundServices starts all background services and performs initial recovery.
-func startBackgroundServices(deps *serviceDeps) {
+func startBackgroundServices(deps *serviceDeps, report *services.StartupReport) {
 	logger.Infof("Starting background services...")
+	watchScanPathChanges(deps)
 	deps.remediatorService.Start()
 	deps.verifierService.Start()
 	deps.monitorService.Start()
@@ -303,6 +545,22 @@ func startBackgroundServices(deps *serviceDeps) {
 
 	logger.Infof("Starting Scheduler Service...")
 	deps.schedulerService.Start()
+	if err := deps.reportService.Start(config.Get().ReportSchedule); err != nil {
+		report.Failed("Weekly reports", err)
+	} else if config.Get().ReportSchedule != "" {
+		report.Component("Weekly reports", config.Get().ReportSchedule)
+	}
+	if err := deps.resolutionAudit.Start(config.Get().ResolutionAuditSchedule); err != nil {
+		report.Failed("Resolution audit", err)
+	} else if config.Get().ResolutionAuditSchedule != "" {
+		report.Component("Resolution audit", config.Get().ResolutionAuditSchedule)
+	}
+	if err := deps.maintenanceService.Start(config.Get().MaintenanceSchedule, config.Get().MaintenancePeakHours); err != nil {
+		report.Failed("Database maintenance", err)
+	} else if config.Get().MaintenanceSchedule != "" {
+		report.Component("Database maintenance", config.Get().MaintenanceSchedule)
+	}
+	deps.telemetry.Start()
 	logger.Infof("✓ All background services started")
 
 	// Replay unprocessed events AFTER subscribers are ready but BEFORE recovery.
@@ -319,25 +577,84 @@ func startBackgroundServices(deps *serviceDeps) {
 
 	// Run recovery service to reconcile stale in-progress items
 	deps.recoveryService.Run()
+
+	// Refresh *arr tags so tag-bound scan paths follow re-created instances
+	go syncArrTags(deps)
+}
+
+// syncArrTags refreshes the cached *arr tags and re-binds tag-bound scan paths.
+func syncArrTags(deps *serviceDeps) {
+	result, err := services.SyncArrTags(deps.repo.DB, deps.arrClient)
+	if err != nil {
+		logger.Warnf("Failed to sync *arr tags: %v", err)
+		return
+	}
+	if result.Rebound > 0 {
+		if err := deps.eb.Publish(domain.Event{
+			AggregateType: "scan_path",
+			AggregateID:   "scan_paths",
+			EventType:     domain.ScanPathsChanged,
+			EventData:     map[string]interface{}{"action": "rebound", "path_ids": []int64{}},
+		}); err != nil {
+			logger.Errorf("Failed to announce scan paths re-bound by tag sync: %v", err)
+		}
+	}
+}
+
+// watchScanPathChanges reloads the shared path mappings and remote scan paths
+// whenever scan paths change, whoever changed them. Scans keep using the old
+// mappings until the new ones are swapped in.
+func watchScanPathChanges(deps *serviceDeps) {
+	deps.eb.Subscribe(domain.ScanPathsChanged, func(e domain.Event) {
+		logger.Debugf("Scan paths %s, reloading path mappings", e.GetStringOr("action", "changed"))
+		deps.remotePaths.Invalidate()
+		if err := deps.pathMapper.Reload(); err != nil {
+			logger.Errorf("Failed to reload path mappings: %v", err)
+		}
+	})
 }
 
 // startAPIServer initializes and starts the API server in a goroutine.
 func startAPIServer(deps *serviceDeps, cfg *config.Config) *api.RESTServer {
 	logger.Infof("Initializing REST API and WebSocket server...")
 	apiServer := api.NewRESTServer(api.ServerDeps{
-		DB:         deps.repo.DB,
-		EventBus:   deps.eb,
-		Scanner:    deps.scannerService,
-		PathMapper: deps.pathMapper,
-		ArrClient:  deps.arrClient,
-		Scheduler:  deps.schedulerService,
-		Notifier:   deps.notifierService,
-		Metrics:    deps.metricsService,
+		DB:            deps.repo.DB,
+		ReadDB:        deps.repo.ReadDB,
+		EventBus:      deps.eb,
+		Scanner:       deps.scannerService,
+		PathMapper:    deps.pathMapper,
+		ArrClient:     deps.arrClient,
+		Scheduler:     deps.schedulerService,
+		Notifier:      deps.notifierService,
+		Outbox:        deps.webhookOutbox,
+		Metrics:       deps.metricsService,
+		Remediator:    deps.remediatorService,
+		HealthMonitor: deps.healthMonitorService,
+		Reports:       deps.reportService,
+		Maintenance:   deps.maintenanceService,
+		Backups:       deps.backupService,
+
+		ArrKeyEncryption: deps.repo.ArrKeyEncryption,
+		FaultInjector:    deps.faults,
+		ToolChecker:      deps.toolChecker,
+		RemotePaths:      deps.remotePaths,
+		SystemPause:      deps.systemPause,
+		PathGroups:       deps.pathGroups,
+		Dashboard:        deps.dashboard,
+		Telemetry:        deps.telemetry,
+		Samples:          deps.samples,
+		MappingOverrides: deps.mappingOverrides,
+		ResolutionAudit:  deps.resolutionAudit,
 	})
 
 	go func() {
-		addr := ":" + cfg.Port
-		if err := apiServer.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if cfg.ListenSocket != "" {
+			err = apiServer.StartSocket(cfg.ListenSocket)
+		} else {
+			err = apiServer.Start(":" + cfg.Port)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Errorf("Failed to start API server: %v", err)
 			os.Exit(1)
 		}
@@ -346,15 +663,26 @@ func startAPIServer(deps *serviceDeps, cfg *config.Config) *api.RESTServer {
 	return apiServer
 }
 
-// logStartupComplete logs the successful startup message.
-func logStartupComplete(cfg *config.Config) {
+// logStartupComplete logs the successful startup message and stores the
+// startup report.
+func logStartupComplete(cfg *config.Config, report *services.StartupReport, sqlDB *sql.DB) {
 	logger.Infof(logSeparator)
 	logger.Infof("✓ Healarr %s started successfully", config.Version)
-	logger.Infof("✓ Server listening on port %s", cfg.Port)
+	if cfg.ListenSocket != "" {
+		logger.Infof("✓ Server listening on socket %s", cfg.ListenSocket)
+	} else {
+		logger.Infof("✓ Server listening on port %s", cfg.Port)
+	}
 	if cfg.BasePath != "/" {
 		logger.Infof("✓ Web UI available at base path: %s", cfg.BasePath)
 	}
 	logger.Infof(logSeparator)
+
+	if err := report.Complete(sqlDB); err != nil {
+		logger.Errorf("Failed to store startup report: %v", err)
+	} else if len(report.Warnings) > 0 {
+		logger.Warnf("⚠ Started with %d warning(s), see GET /api/system/startup-report", len(report.Warnings))
+	}
 }
 
 // gracefulShutdown handles the graceful shutdown of all services.
@@ -364,6 +692,12 @@ func gracefulShutdown(deps *serviceDeps, apiServer *api.RESTServer) {
 
 	logger.Infof("Stopping Scheduler Service...")
 	deps.schedulerService.Stop()
+	deps.systemPause.Stop()
+	deps.dashboard.Stop()
+	deps.telemetry.Stop()
+	deps.reportService.Stop()
+	deps.resolutionAudit.Stop()
+	deps.maintenanceService.Stop()
 	logger.Infof("✓ Scheduler Service stopped")
 
 	logger.Infof("Stopping Scanner Service (saving state for interrupted scans)...")
@@ -374,6 +708,18 @@ func gracefulShutdown(deps *serviceDeps, apiServer *api.RESTServer) {
 	deps.notifierService.Stop()
 	logger.Infof("✓ Notification Service stopped")
 
+	if deps.webhookOutbox != nil {
+		logger.Infof("Stopping Webhook Outbox...")
+		deps.webhookOutbox.Stop()
+		logger.Infof("✓ Webhook Outbox stopped")
+	}
+
+	if mqttPublisher := deps.mqttPublisher.Swap(nil); mqttPublisher != nil {
+		logger.Infof("Stopping MQTT Publisher...")
+		mqttPublisher.Stop()
+		logger.Infof("✓ MQTT Publisher stopped")
+	}
+
 	logger.Infof("Stopping Health Monitor Service...")
 	deps.healthMonitorService.Shutdown()
 	logger.Infof("✓ Health Monitor Service stopped")
@@ -407,6 +753,20 @@ func gracefulShutdown(deps *serviceDeps, apiServer *api.RESTServer) {
 	logger.Infof(logSeparator)
 }
 
+// shutdownSignals returns a channel that receives the name of the first
+// SIGINT or SIGTERM delivered to the process.
+func shutdownSignals() <-chan string {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	stop := make(chan string, 1)
+	go func() {
+		sig := <-quit
+		stop <- "signal " + sig.String()
+	}()
+	return stop
+}
+
 func main() {
 	flags := parseFlags()
 
@@ -415,6 +775,37 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *flags.service != "" {
+		if err := controlService(*flags.service); err != nil {
+			fmt.Fprintf(os.Stderr, "Service %s failed: %v\n", *flags.service, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Service %s succeeded\n", *flags.service)
+		return
+	}
+
+	if *flags.decryptBackup != "" {
+		if err := decryptBackup(*flags.decryptBackup); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to decrypt backup: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flags.diagnoseDB {
+		if err := diagnoseDatabase(flags); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	runPlatform(flags)
+}
+
+// run starts Healarr and blocks until stop yields the reason for shutting
+// down, then stops every service gracefully.
+func run(flags cliFlags, stop <-chan string) {
 	// Load configuration
 	config.Load()
 	applyFlagOverrides(flags)
@@ -423,39 +814,119 @@ func main() {
 	// Initialize logger
 	logger.Init(cfg.LogDir)
 	logger.SetLevel(cfg.LogLevel)
+	if err := i18n.SetDefault(cfg.Locale); err != nil {
+		logger.Warnf("Invalid locale: %v", err)
+	}
 
 	logger.Infof(logSeparator)
 	logger.Infof("Starting Healarr %s...", config.Version)
 	logger.Infof("Health Evaluation And Library Auto-Recovery for *aRR")
 	logger.Infof(logSeparator)
 
-	logConfiguration(cfg)
-	config.ValidateAndWarn()
+	// Versions, configuration, component results and warnings of this
+	// startup, stored for GET /api/system/startup-report
+	report := services.NewStartupReport(config.Version)
+
+	logConfiguration(cfg, report)
 
 	// Initialize database with background maintenance
-	repo, stopCheckpoint := initDatabase(cfg)
+	repo, backupService, stopCheckpoint := initDatabase(cfg, *flags.recoverDB, report)
 	defer stopCheckpoint()
 
 	// Load base path from database if not set via environment
 	config.LoadBasePathFromDB(repo.DB)
 	cfg = config.Get()
-	logger.Infof("  Base Path: %s (source: %s)", cfg.BasePath, cfg.BasePathSource)
+	report.Setting("Base Path", "%s (source: %s)", cfg.BasePath, cfg.BasePathSource)
 
 	// Initialize event bus
 	logger.Infof("Initializing Event Bus...")
 	eb := eventbus.NewEventBus(repo.DB)
-	logger.Infof("✓ Event Bus initialized")
+	eb.SetStrictValidation(cfg.StrictEventValidation)
+	eb.AddProjector(db.CorruptionSummaryProjector(repo.DB))
+	eb.AddProjector(db.ProjectCorruptionLinks)
+	// The dashboard summary is kept in memory, so the dashboard needs no queries
+	dashboard := services.NewDashboardSummary(repo.DB)
+	eb.AddProjector(dashboard.Project)
+	dashboard.Start()
+	// Scans publish a progress event per file; store them in batches instead of
+	// one transaction each. Detections are stored right away, so a failed store
+	// reaches the scanner's retry and a crash can't lose them.
+	eb.EnableBatching(cfg.EventBatchSize, cfg.EventBatchInterval, domain.ScanProgress)
+	report.Component("Event Bus", "")
 
 	// Initialize integration components
-	pathMapper, healthChecker, arrClient := initIntegration(repo.DB, cfg)
+	pathMapper, healthChecker, arrClient, remotePaths := initIntegration(repo.DB, eb, cfg, report)
+
+	// Per-corruption overrides for files the path mappings get wrong
+	mappingOverrides := services.NewMappingOverrides(repo.DB, pathMapper)
+	pathMapper = mappingOverrides.PathMapper()
+	arrClient = mappingOverrides.WrapArrClient(arrClient)
+
+	// Test mode: route *arr calls and health checks through the failure injector
+	var faults *integration.FaultInjector
+	if cfg.TestMode {
+		faults = integration.NewFaultInjector()
+		arrClient = faults.WrapArrClient(arrClient)
+	}
 
 	// Initialize core services
 	scannerService, remediatorService, verifierService,
 		monitorService, healthMonitorService, recoveryService,
-		schedulerService, eventReplayService := initCoreServices(repo.DB, eb, healthChecker, pathMapper, arrClient, cfg)
+		schedulerService, eventReplayService := initCoreServices(repo.DB, eb, healthChecker, pathMapper, arrClient, faults, cfg, report)
+
+	// Paths whose detection tools are missing are skipped instead of scanned
+	toolChecker := initToolChecker(cfg, report)
+	scannerService.SetToolChecker(toolChecker)
+	verifierService.SetRemotePaths(remotePaths)
+
+	// Global pause for maintenance on the *arr stack or the storage array
+	systemPause := services.NewSystemPause(repo.DB, eb)
+	remediatorService.SetSystemPause(systemPause)
+	verifierService.SetSystemPause(systemPause)
+	schedulerService.SetSystemPause(systemPause)
+	healthMonitorService.SetSystemPause(systemPause)
+
+	// Scan path groups can hold the remediation of their paths
+	pathGroups := services.NewPathGroupService(repo.DB)
+	remediatorService.SetPathGroups(pathGroups)
 
 	// Initialize notification and metrics
-	notifierService, metricsService := initNotifierAndMetrics(repo.DB, eb)
+	notifierService, metricsService := initNotifierAndMetrics(repo.DB, eb, report)
+	repo.SetPruneObserver(metricsService.RecordPruned)
+	db.SetLatencyObserver(metricsService.RecordDBLatency)
+	// Gauges behind the recommended alert rules, read on every scrape
+	if breakers, ok := arrClient.(metrics.CircuitBreakerSource); ok {
+		metricsService.SetCircuitBreakerSource(breakers)
+	}
+	metricsService.SetLastScansSource(services.LastScheduledScans(repo.DB))
+
+	// Re-checks of recently resolved corruptions, feeding the durability gauges
+	resolutionAudit := services.NewResolutionAuditService(repo.DB, eb, cfg.ResolutionAuditDays, cfg.ResolutionAuditSample)
+	resolutionAudit.SetSystemPause(systemPause)
+	metricsService.SetDurabilitySource(resolutionAudit.DurabilitySource())
+	webhookOutbox := initWebhookOutbox(repo.DB, eb, report)
+	mqttPublisher := initMQTT(repo.DB, eb, cfg, report)
+	reportService := services.NewReportService(repo.DB, eb)
+	maintenanceService := services.NewMaintenanceService(repo, retentionPolicy(cfg), cfg.DeletedRetentionDays)
+	// Samples of corrupt files; extracted on request, or for every corruption found when enabled
+	samples := services.NewSampleExtractor(repo.DB, services.SampleConfig{
+		Dir:           cfg.SampleDir,
+		FFmpegPath:    cfg.FFmpegPath,
+		MaxBytes:      int64(cfg.SampleMaxMB) << 20,
+		DirMaxBytes:   int64(cfg.SampleDirMaxMB) << 20,
+		RetentionDays: cfg.SampleRetentionDays,
+	})
+	maintenanceService.SetSampleExtractor(samples)
+	if cfg.SampleExtraction {
+		scannerService.SetSampleExtractor(samples)
+	}
+	// Anonymous usage statistics, only sent after the user opted in
+	telemetry := services.NewTelemetryService(repo.DB, services.TelemetryConfig{
+		URL:      cfg.TelemetryURL,
+		Interval: cfg.TelemetryInterval,
+		Disabled: cfg.TelemetryDisabled || cfg.OfflineMode,
+		Version:  config.Version,
+	}, toolChecker)
 
 	// Bundle all services for dependency injection
 	deps := &serviceDeps{
@@ -464,6 +935,9 @@ func main() {
 		pathMapper:           pathMapper,
 		healthChecker:        healthChecker,
 		arrClient:            arrClient,
+		faults:               faults,
+		toolChecker:          toolChecker,
+		remotePaths:          remotePaths,
 		scannerService:       scannerService,
 		remediatorService:    remediatorService,
 		verifierService:      verifierService,
@@ -473,25 +947,59 @@ func main() {
 		schedulerService:     schedulerService,
 		eventReplayService:   eventReplayService,
 		notifierService:      notifierService,
+		webhookOutbox:        webhookOutbox,
 		metricsService:       metricsService,
+		reportService:        reportService,
+		maintenanceService:   maintenanceService,
+		backupService:        backupService,
+		systemPause:          systemPause,
+		pathGroups:           pathGroups,
+		dashboard:            dashboard,
+		telemetry:            telemetry,
+		samples:              samples,
+		mappingOverrides:     mappingOverrides,
+		resolutionAudit:      resolutionAudit,
 		stopCheckpoint:       stopCheckpoint,
 	}
+	deps.mqttPublisher.Store(mqttPublisher)
 
 	// Start all background services
-	startBackgroundServices(deps)
+	startBackgroundServices(deps, report)
 
 	// Start API server
 	apiServer := startAPIServer(deps, cfg)
-	logStartupComplete(cfg)
+	logStartupComplete(cfg, report, repo.DB)
 
-	// Wait for shutdown signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	sig := <-quit
+	// Tell systemd (Type=notify) we're up and keep its watchdog fed while the
+	// database still answers
+	if _, err := supervisor.Notify(supervisor.StateReady); err != nil {
+		logger.Warnf("Failed to notify service manager of readiness: %v", err)
+	}
+	stopWatchdog := supervisor.StartWatchdog(func(ctx context.Context) error {
+		var one int
+		return repo.DB.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+	})
+
+	// Wait for shutdown signal or service stop request, reloading the
+	// configuration on SIGHUP
+	reloads := reloadSignals()
+	var reason string
+	for reason == "" {
+		select {
+		case reason = <-stop:
+		case <-reloads:
+			reloadConfig(deps)
+		}
+	}
 
 	logger.Infof(logSeparator)
-	logger.Infof("Received signal %v, initiating graceful shutdown...", sig)
+	logger.Infof("Received %s, initiating graceful shutdown...", reason)
 	logger.Infof(logSeparator)
 
+	stopWatchdog()
+	if _, err := supervisor.Notify(supervisor.StateStopping); err != nil {
+		logger.Debugf("Failed to notify service manager of shutdown: %v", err)
+	}
+
 	gracefulShutdown(deps, apiServer)
 }
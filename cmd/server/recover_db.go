@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// recoverDatabase diagnoses why the database failed to open and logs each
+// cause. With attemptRecovery set it attempts the safe recovery steps and
+// opens the database again; otherwise it tells the user how to start one.
+func recoverDatabase(cfg *config.Config, attemptRecovery bool, open func() (*db.Repository, error), report *services.StartupReport) (*db.Repository, error) {
+	diagnosis := db.Diagnose(cfg.DatabasePath)
+	if diagnosis.Healthy() {
+		logger.Errorf("Database diagnosis found no known cause")
+	}
+	for _, finding := range diagnosis.Findings {
+		logger.Errorf("Database diagnosis: %s", finding)
+	}
+	if !attemptRecovery {
+		logger.Errorf("Start Healarr with --recover to attempt a safe recovery (checkpoint the write-ahead log, restore the latest backup)")
+		return nil, errors.New("database can't be opened")
+	}
+
+	logger.Infof("Attempting database recovery...")
+	steps, err := db.Recover(diagnosis)
+	if err != nil {
+		logger.Errorf("Database recovery failed: %v", err)
+		return nil, err
+	}
+	repo, err := open()
+	if err != nil {
+		logger.Errorf("Database still fails to open after recovery: %v", err)
+		return nil, err
+	}
+	detail := "no recovery needed"
+	if len(steps) > 0 {
+		detail = steps[len(steps)-1].Detail
+	}
+	report.Degraded("Database recovery", detail)
+	return repo, nil
+}
+
+// diagnoseDatabase prints the diagnosis of the configured database for
+// --diagnose-db and returns an error if it found a problem.
+func diagnoseDatabase(flags cliFlags) error {
+	config.Load()
+	applyFlagOverrides(flags)
+	path := config.Get().DatabasePath
+
+	diagnosis := db.Diagnose(path)
+	if diagnosis.Healthy() {
+		fmt.Printf("%s: no problems found\n", path)
+		return nil
+	}
+	for _, finding := range diagnosis.Findings {
+		fmt.Printf("%s\n  %s\n  fix: %s\n", finding.Problem, finding.Detail, finding.Hint)
+	}
+	return fmt.Errorf("%d problem(s) found in %s", len(diagnosis.Findings), path)
+}
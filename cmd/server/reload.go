@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// rateLimitSetter is implemented by *arr clients whose rate limit can change
+// at runtime.
+type rateLimitSetter interface {
+	SetRateLimit(rps float64, burst int)
+}
+
+// reloadSignals returns a channel that receives a value for every SIGHUP
+// delivered to the process. Windows never delivers one.
+func reloadSignals() <-chan os.Signal {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	return hup
+}
+
+// reloadConfig re-reads the configuration, logs what changed and
+// re-initializes the components whose settings changed. Settings that need a
+// restart are logged and left alone.
+func reloadConfig(deps *serviceDeps) {
+	logger.Infof("Reloading configuration...")
+	changes, err := config.Reload()
+	if err != nil {
+		logger.Errorf("Config reload failed, keeping the current configuration: %v", err)
+		return
+	}
+	if len(changes) == 0 {
+		logger.Infof("Config reload: nothing changed")
+		return
+	}
+
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if change.Applied {
+			changed[change.Field] = true
+			logger.Infof("Config changed: %s", change)
+		} else {
+			logger.Warnf("Config changed: %s (restart required)", change)
+		}
+	}
+	applyConfigChanges(deps, config.Get(), changed)
+	logger.Infof("✓ Configuration reloaded (%d applied, %d need a restart)", len(changed), len(changes)-len(changed))
+}
+
+// applyConfigChanges re-initializes the components affected by the changed fields.
+func applyConfigChanges(deps *serviceDeps, cfg *config.Config, changed map[string]bool) {
+	if changed["LogLevel"] {
+		logger.SetLevel(cfg.LogLevel)
+	}
+
+	if changed["ArrRateLimitRPS"] || changed["ArrRateLimitBurst"] {
+		if client, ok := deps.arrClient.(rateLimitSetter); ok {
+			client.SetRateLimit(cfg.ArrRateLimitRPS, cfg.ArrRateLimitBurst)
+		} else {
+			logger.Warnf("The *arr rate limit can't change at runtime in test mode; restart to apply it")
+		}
+	}
+
+	if changed["RemediationMaxConcurrent"] || changed["RemediationSearchesPerHour"] || changed["RemediationWindow"] {
+		if err := deps.remediatorService.SetThrottle(services.RemediationThrottleConfig{
+			MaxConcurrent:   cfg.RemediationMaxConcurrent,
+			SearchesPerHour: cfg.RemediationSearchesPerHour,
+			Window:          cfg.RemediationWindow,
+		}); err != nil {
+			logger.Errorf("Remediation window disabled: %v", err)
+		}
+	}
+
+	if changed["ReportSchedule"] {
+		deps.reportService.Stop()
+		if err := deps.reportService.Start(cfg.ReportSchedule); err != nil {
+			logger.Errorf("Scheduled reports disabled: %v", err)
+		}
+	}
+
+	for field := range changed {
+		if strings.HasPrefix(field, "MQTT") {
+			// Swap first, so shutdown stops the new publisher, never the old one twice
+			if old := deps.mqttPublisher.Swap(initMQTT(deps.repo.DB, deps.eb, cfg, nil)); old != nil {
+				old.Stop()
+			}
+			break
+		}
+	}
+}
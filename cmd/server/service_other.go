@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import "errors"
+
+// controlService only manages Windows services; elsewhere the unit file or
+// init script of the service manager does the job.
+func controlService(action string) error {
+	return errors.New("-service is only supported on Windows; use the healarr.service systemd unit instead")
+}
+
+// runPlatform runs Healarr in the foreground until SIGINT or SIGTERM.
+func runPlatform(flags cliFlags) {
+	run(flags, shutdownSignals())
+}
@@ -0,0 +1,95 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/kardianos/service"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// windowsService adapts run to the service control manager: Start launches
+// Healarr in the background and Stop asks it to shut down gracefully, waiting
+// until it has.
+type windowsService struct {
+	flags cliFlags
+	stop  chan string
+	done  chan struct{}
+}
+
+func (p *windowsService) Start(s service.Service) error {
+	go func() {
+		defer close(p.done)
+		run(p.flags, p.stop)
+	}()
+	return nil
+}
+
+func (p *windowsService) Stop(s service.Service) error {
+	select {
+	case p.stop <- "service stop request":
+	default:
+	}
+	<-p.done
+	return nil
+}
+
+// newService builds the service definition. The arguments Healarr was
+// installed with (minus -service) are passed to it on every start.
+func newService(program service.Interface) (service.Service, error) {
+	return service.New(program, &service.Config{
+		Name:        "Healarr",
+		DisplayName: "Healarr",
+		Description: "Health Evaluation And Library Auto-Recovery for *aRR",
+		Arguments:   serviceArguments(os.Args[1:]),
+	})
+}
+
+// serviceArguments strips the -service flag and its value from args.
+func serviceArguments(args []string) []string {
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		switch {
+		case name == "service":
+			i++ // skip the value
+		case strings.HasPrefix(name, "service="):
+		default:
+			kept = append(kept, args[i])
+		}
+	}
+	return kept
+}
+
+// controlService installs, uninstalls, starts, stops or restarts the Windows
+// service.
+func controlService(action string) error {
+	s, err := newService(&windowsService{})
+	if err != nil {
+		return err
+	}
+	return service.Control(s, action)
+}
+
+// runPlatform runs Healarr under the service control manager when started by
+// it, and in the foreground otherwise.
+func runPlatform(flags cliFlags) {
+	if service.Interactive() {
+		run(flags, shutdownSignals())
+		return
+	}
+
+	program := &windowsService{flags: flags, stop: make(chan string, 1), done: make(chan struct{})}
+	s, err := newService(program)
+	if err != nil {
+		logger.Errorf("Failed to create Windows service: %v", err)
+		os.Exit(1)
+	}
+	if err := s.Run(); err != nil {
+		logger.Errorf("Windows service failed: %v", err)
+		os.Exit(1)
+	}
+}
@@ -17,6 +17,7 @@ const Logs = lazy(() => import('./pages/Logs'));
 const Config = lazy(() => import('./pages/Config'));
 const Help = lazy(() => import('./pages/Help'));
 const Login = lazy(() => import('./pages/Login'));
+const Status = lazy(() => import('./pages/Status'));
 
 // Loading fallback for lazy-loaded components
 const PageLoader = () => (
@@ -33,6 +34,7 @@ function App() {
           <Suspense fallback={<PageLoader />}>
             <Routes>
               <Route path="/login" element={<Login />} />
+              <Route path="/status" element={<Status />} />
               <Route path="/" element={
                 <ProtectedRoute>
                   <Layout />
@@ -1,13 +1,16 @@
 import { useState } from 'react';
-import { useQuery } from '@tanstack/react-query';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { motion, AnimatePresence } from 'framer-motion';
 import {
     ArrowUpCircle, Check, ExternalLink, ChevronDown, Download,
     Server, Monitor, Clock, HardDrive, Github, Bug,
-    CheckCircle, XCircle, AlertTriangle, Info
+    CheckCircle, XCircle, AlertTriangle, Info, LifeBuoy, WifiOff
 } from 'lucide-react';
 import clsx from 'clsx';
-import { checkForUpdates, getSystemInfo, type ToolStatus } from '../lib/api';
+import {
+    checkForUpdates, getSystemInfo, getSystemStatus, getStartupReport, getOfflineCheck, downloadSystemDiagnostics,
+    getTelemetry, setTelemetry, getTelemetryPreview, type ToolStatus
+} from '../lib/api';
 
 // Platform icons
 const DockerIcon = ({ className }: { className?: string }) => (
@@ -53,6 +56,56 @@ const AboutSection = ({ showAsAccordion = false }: AboutSectionProps) => {
         retry: 1,
     });
 
+    // Only run on demand: it reads every web asset
+    const { data: offlineCheck, isFetching: offlineChecking, refetch: runOfflineCheck } = useQuery({
+        queryKey: ['offlineCheck'],
+        queryFn: getOfflineCheck,
+        enabled: false,
+    });
+
+    const { data: systemStatus } = useQuery({
+        queryKey: ['systemStatus'],
+        queryFn: getSystemStatus,
+        staleTime: 300000, // startup checks only change on restart
+        retry: 1,
+    });
+
+    const { data: startupReport } = useQuery({
+        queryKey: ['startupReport'],
+        queryFn: getStartupReport,
+        enabled: (systemStatus?.startup_warnings ?? 0) > 0,
+        staleTime: 300000,
+        retry: 1,
+    });
+
+    const queryClient = useQueryClient();
+    const { data: telemetry } = useQuery({
+        queryKey: ['telemetry'],
+        queryFn: getTelemetry,
+        retry: 1,
+    });
+    // On demand, so the user sees exactly what would be sent
+    const { data: telemetryPreview, isFetching: telemetryPreviewing, refetch: previewTelemetry } = useQuery({
+        queryKey: ['telemetryPreview'],
+        queryFn: getTelemetryPreview,
+        enabled: false,
+    });
+    const telemetryMutation = useMutation({
+        mutationFn: setTelemetry,
+        onSuccess: (status) => queryClient.setQueryData(['telemetry'], status),
+    });
+
+    const [diagnosticsState, setDiagnosticsState] = useState<'idle' | 'loading' | 'error'>('idle');
+    const handleDownloadDiagnostics = async () => {
+        setDiagnosticsState('loading');
+        try {
+            await downloadSystemDiagnostics();
+            setDiagnosticsState('idle');
+        } catch {
+            setDiagnosticsState('error');
+        }
+    };
+
     // Determine platform from system info
     const currentPlatform = systemInfo?.environment === 'docker' ? 'docker' : systemInfo?.os || 'unknown';
 
@@ -268,6 +321,32 @@ const AboutSection = ({ showAsAccordion = false }: AboutSectionProps) => {
                 </div>
             )}
 
+            {/* Startup check warnings (e.g. undecryptable *arr API keys) */}
+            {systemStatus && systemStatus.warnings.length > 0 && (
+                <div className="p-4 rounded-xl bg-amber-50 dark:bg-amber-900/20 border border-amber-200 dark:border-amber-700">
+                    <div className="flex items-start gap-3">
+                        <AlertTriangle className="w-5 h-5 text-amber-500 flex-shrink-0 mt-0.5" />
+                        <ul className="space-y-1">
+                            {systemStatus.warnings.map(warning => (
+                                <li key={warning} className="text-sm text-amber-700 dark:text-amber-300">{warning}</li>
+                            ))}
+                            {startupReport && startupReport.warnings.length > 0 && (
+                                <li>
+                                    <details className="text-sm text-amber-700 dark:text-amber-300">
+                                        <summary className="cursor-pointer">Startup warnings</summary>
+                                        <ul className="mt-1 ml-4 list-disc space-y-1 font-mono text-xs">
+                                            {startupReport.warnings.map((warning, i) => (
+                                                <li key={i}>{warning}</li>
+                                            ))}
+                                        </ul>
+                                    </details>
+                                </li>
+                            )}
+                        </ul>
+                    </div>
+                </div>
+            )}
+
             {/* Version Status */}
             <div className="flex items-center justify-between p-4 rounded-xl bg-slate-100 dark:bg-slate-800/50 border border-slate-200 dark:border-slate-700">
                 <div className="flex items-center gap-4">
@@ -299,7 +378,12 @@ const AboutSection = ({ showAsAccordion = false }: AboutSectionProps) => {
                                 Latest: {updateInfo.latest_version} (released {updateInfo.published_at})
                             </p>
                         )}
-                        {!updateInfo?.update_available && (
+                        {updateInfo?.checks_disabled ? (
+                            <p className="text-sm text-slate-500 dark:text-slate-400 mt-1 flex items-center gap-1.5">
+                                <WifiOff className="w-4 h-4" />
+                                Update checks are disabled in offline mode
+                            </p>
+                        ) : !updateInfo?.update_available && (
                             <p className="text-sm text-green-600 dark:text-green-400 mt-1">
                                 You're running the latest version
                             </p>
@@ -372,6 +456,131 @@ const AboutSection = ({ showAsAccordion = false }: AboutSectionProps) => {
                 </div>
             )}
 
+            {/* Air-gap verification */}
+            <div className="rounded-xl border border-slate-200 dark:border-slate-700 bg-white/80 dark:bg-slate-900/40 overflow-hidden">
+                <div className="px-4 py-3 bg-slate-100 dark:bg-slate-800/50 border-b border-slate-200 dark:border-slate-700 flex items-center justify-between">
+                    <h4 className="font-semibold text-slate-900 dark:text-white">Offline Check</h4>
+                    <button
+                        onClick={() => runOfflineCheck()}
+                        disabled={offlineChecking}
+                        className="px-3 py-1.5 text-sm bg-slate-200 dark:bg-slate-700 hover:bg-slate-300 dark:hover:bg-slate-600 text-slate-700 dark:text-slate-300 rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                    >
+                        {offlineChecking ? 'Checking...' : 'Run Check'}
+                    </button>
+                </div>
+                <div className="p-4 space-y-3 text-sm">
+                    {!offlineCheck ? (
+                        <p className="text-slate-500 dark:text-slate-400">
+                            Verifies that the web UI loads nothing from the internet and lists every host Healarr connects to.
+                        </p>
+                    ) : (
+                        <>
+                            <div className="flex items-center gap-2">
+                                {offlineCheck.passed ? (
+                                    <CheckCircle className="w-5 h-5 text-green-500" />
+                                ) : (
+                                    <AlertTriangle className="w-5 h-5 text-amber-500" />
+                                )}
+                                <span className="text-slate-900 dark:text-white">
+                                    {offlineCheck.passed
+                                        ? 'Ready for air-gapped use'
+                                        : 'Healarr may connect to hosts you did not configure'}
+                                </span>
+                            </div>
+                            <p className="text-slate-500 dark:text-slate-400">
+                                Web UI ({offlineCheck.web_assets.source}):{' '}
+                                {offlineCheck.web_assets.error
+                                    ? offlineCheck.web_assets.error
+                                    : offlineCheck.web_assets.external_references.length === 0
+                                    ? 'no external resources'
+                                    : `${offlineCheck.web_assets.external_references.length} external resources`}
+                            </p>
+                            {offlineCheck.web_assets.external_references.length > 0 && (
+                                <ul className="space-y-1 font-mono text-xs text-amber-600 dark:text-amber-400">
+                                    {offlineCheck.web_assets.external_references.map(ref => (
+                                        <li key={`${ref.file}:${ref.url}`}>{ref.file}: {ref.url}</li>
+                                    ))}
+                                </ul>
+                            )}
+                            <ul className="space-y-1">
+                                {offlineCheck.outbound.map(target => (
+                                    <li key={`${target.kind}:${target.name}`} className="flex items-center justify-between gap-4">
+                                        <span className={clsx(
+                                            target.enabled && !target.configured
+                                                ? 'text-amber-600 dark:text-amber-400'
+                                                : 'text-slate-600 dark:text-slate-400'
+                                        )}>
+                                            {target.name}
+                                        </span>
+                                        <span className="font-mono text-xs text-slate-500">
+                                            {target.host}{!target.enabled && ' (disabled)'}
+                                        </span>
+                                    </li>
+                                ))}
+                            </ul>
+                            {!offlineCheck.offline_mode && (
+                                <p className="text-xs text-slate-500">
+                                    Set <code>HEALARR_OFFLINE_MODE=true</code> to stop update checks.
+                                </p>
+                            )}
+                        </>
+                    )}
+                </div>
+            </div>
+
+            {/* Opt-in usage statistics */}
+            {telemetry && (
+                <div className="rounded-xl border border-slate-200 dark:border-slate-700 bg-white/80 dark:bg-slate-900/40 overflow-hidden">
+                    <div className="px-4 py-3 bg-slate-100 dark:bg-slate-800/50 border-b border-slate-200 dark:border-slate-700 flex items-center justify-between">
+                        <h4 className="font-semibold text-slate-900 dark:text-white">Anonymous Usage Statistics</h4>
+                        <div className="flex items-center gap-2">
+                            <button
+                                onClick={() => previewTelemetry()}
+                                disabled={telemetryPreviewing}
+                                className="px-3 py-1.5 text-sm bg-slate-200 dark:bg-slate-700 hover:bg-slate-300 dark:hover:bg-slate-600 text-slate-700 dark:text-slate-300 rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                            >
+                                {telemetryPreviewing ? 'Loading...' : 'Preview'}
+                            </button>
+                            <button
+                                onClick={() => telemetryMutation.mutate(!telemetry.enabled)}
+                                disabled={telemetry.hard_disabled || telemetryMutation.isPending}
+                                className={clsx(
+                                    "px-3 py-1.5 text-sm rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed",
+                                    telemetry.enabled
+                                        ? "bg-slate-200 dark:bg-slate-700 hover:bg-slate-300 dark:hover:bg-slate-600 text-slate-700 dark:text-slate-300"
+                                        : "bg-green-600 hover:bg-green-700 text-white"
+                                )}
+                            >
+                                {telemetry.enabled ? 'Turn Off' : 'Opt In'}
+                            </button>
+                        </div>
+                    </div>
+                    <div className="p-4 space-y-3 text-sm">
+                        <p className="text-slate-500 dark:text-slate-400">
+                            Helps prioritize features with bucketed library sizes, tool versions and remediation success
+                            rates. No paths, names, URLs or keys are included, and nothing is sent unless you opt in.
+                        </p>
+                        <p className="text-slate-600 dark:text-slate-400">
+                            {telemetry.hard_disabled
+                                ? 'Disabled by HEALARR_TELEMETRY_DISABLED or offline mode.'
+                                : telemetry.enabled
+                                ? telemetry.endpoint
+                                    ? `Sent every ${telemetry.interval_days} days${telemetry.last_sent_at ? `, last on ${new Date(telemetry.last_sent_at).toLocaleDateString()}` : ''}.`
+                                    : 'Opted in, but no HEALARR_TELEMETRY_URL is set, so nothing is sent.'
+                                : 'Off.'}
+                        </p>
+                        {telemetryMutation.isError && (
+                            <p className="text-red-500">Failed to change usage statistics</p>
+                        )}
+                        {telemetryPreview && (
+                            <pre className="p-3 max-h-64 overflow-auto rounded-lg bg-slate-100 dark:bg-slate-800 font-mono text-xs text-slate-700 dark:text-slate-300">
+                                {JSON.stringify(telemetryPreview, null, 2)}
+                            </pre>
+                        )}
+                    </div>
+                </div>
+            )}
+
             {/* Changelog */}
             {updateInfo?.changelog && (
                 <div className="rounded-xl border border-slate-200 dark:border-slate-700 bg-white/80 dark:bg-slate-900/40 overflow-hidden">
@@ -692,6 +901,20 @@ brew install ffmpeg`}</pre>
                                     <Download className="w-4 h-4" />
                                     Releases
                                 </a>
+                                <button
+                                    onClick={handleDownloadDiagnostics}
+                                    disabled={diagnosticsState === 'loading'}
+                                    title="Download a diagnostics bundle to attach to issue reports (secrets are redacted)"
+                                    className={clsx(
+                                        "inline-flex items-center gap-1.5 px-3 py-1.5 rounded-lg text-sm transition-colors disabled:opacity-50",
+                                        diagnosticsState === 'error'
+                                            ? "bg-red-100 dark:bg-red-500/20 text-red-600 dark:text-red-400"
+                                            : "bg-slate-100 dark:bg-slate-800 hover:bg-slate-200 dark:hover:bg-slate-700 text-slate-700 dark:text-slate-300"
+                                    )}
+                                >
+                                    <LifeBuoy className={clsx("w-4 h-4", diagnosticsState === 'loading' && "animate-spin")} />
+                                    {diagnosticsState === 'error' ? 'Diagnostics failed' : 'Diagnostics bundle'}
+                                </button>
                             </div>
                         </div>
                     </div>
@@ -1,11 +1,11 @@
 import React, { useState, useEffect, useMemo } from 'react';
-import { useQuery } from '@tanstack/react-query';
-import { getCorruptionHistory } from '../lib/api';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { getCorruptionHistory, getRelatedCorruptions, downloadCorruptionSupportBundle, downloadCorruptionSample, reverifyCorruption, getMediaHistory, getScanPaths, getDeletionPlan, type DeletionForensics } from '../lib/api';
 import { useDateFormat } from '../lib/useDateFormat';
 import { formatCorruptionState, getEventDescription, getEventColorClass, formatBytes, formatDuration, formatQuality, getDownloadClientIcon } from '../lib/formatters';
 import {
     CheckCircle, AlertTriangle, Clock, Search, Trash2,
-    FileSearch, Activity, Shield, FileCheck, ChevronDown, Settings, Bell, BellOff, EyeOff, XCircle, Download, RefreshCw, Film, Tv, Copy, Check
+    FileSearch, Activity, Shield, FileCheck, ChevronDown, Settings, Bell, BellOff, EyeOff, XCircle, Download, RefreshCw, Film, Tv, Copy, Check, LifeBuoy, RotateCcw, Scissors
 } from 'lucide-react';
 import { motion, AnimatePresence } from 'framer-motion';
 import clsx from 'clsx';
@@ -69,6 +69,76 @@ const getStatusLabel = (status: string): string => {
     return label;
 };
 
+// Download progress from a DownloadProgress event (raw event_data keys)
+const DownloadProgressInfo = ({ data }: { data: Record<string, unknown> }) => {
+    const progress = data.progress as number;
+    const downloadClient = data.download_client as string;
+    const downloadProtocol = data.protocol as string;
+    const indexer = data.indexer as string;
+    const downloadSize = data.size_bytes as number;
+    const downloadRemaining = data.size_remaining_bytes as number;
+    const etaSeconds = data.eta_seconds as number;
+    const timeLeft = data.time_left as string;
+
+    return (
+        <div className="mt-2 space-y-2">
+            {/* Progress bar */}
+            <div className="flex items-center gap-2">
+                <div className="flex-1 h-2 bg-slate-200 dark:bg-slate-800 rounded-full overflow-hidden">
+                    <div
+                        className="h-full bg-blue-500 rounded-full transition-all duration-300"
+                        style={{ width: `${Math.min(100, Math.max(0, progress))}%` }}
+                    />
+                </div>
+                <span className="text-xs font-medium text-blue-400 w-12 text-right">
+                    {progress.toFixed(1)}%
+                </span>
+            </div>
+
+            {/* Download info */}
+            <div className="flex items-center gap-3 text-xs text-slate-500 flex-wrap">
+                {downloadClient && (
+                    <span className="flex items-center gap-1.5">
+                        <img
+                            src={getDownloadClientIcon(downloadClient)}
+                            alt={downloadClient}
+                            className="w-4 h-4 object-contain"
+                            onError={(e) => { (e.target as HTMLImageElement).src = '/icons/download-clients/generic.svg'; }}
+                        />
+                        <span>{downloadClient}</span>
+                        {downloadProtocol && (
+                            <span className="text-slate-600 dark:text-slate-400">({downloadProtocol})</span>
+                        )}
+                    </span>
+                )}
+                {indexer && (
+                    <span className="text-slate-600 dark:text-slate-400">
+                        via {indexer}
+                    </span>
+                )}
+                {downloadSize > 0 && (
+                    <span>
+                        {downloadRemaining ? (
+                            <>{formatBytes(downloadSize - downloadRemaining)} / {formatBytes(downloadSize)}</>
+                        ) : (
+                            formatBytes(downloadSize)
+                        )}
+                    </span>
+                )}
+                {etaSeconds > 0 ? (
+                    <span className="text-slate-600 dark:text-slate-400">
+                        ~{formatDuration(etaSeconds)} remaining
+                    </span>
+                ) : timeLeft && (
+                    <span className="text-slate-600 dark:text-slate-400">
+                        ~{timeLeft} remaining
+                    </span>
+                )}
+            </div>
+        </div>
+    );
+};
+
 const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, onClose }) => {
     const { formatFull } = useDateFormat();
     
@@ -99,6 +169,18 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
         const isResolved = status === 'VerificationSuccess';
         const isFailed = status === 'MaxRetriesReached' || status.includes('Failed');
         const isIgnored = status === 'CorruptionIgnored';
+
+        // Replacement still downloading: show a live progress bar in the header
+        const lastData = lastEvent?.data as Record<string, unknown> | undefined;
+        const activeDownload = status === 'DownloadProgress' && typeof lastData?.progress === 'number' ? lastData : null;
+
+        // Latest *arr media ID, used to look up earlier corruptions of the same movie/series
+        const pathId = (detected?.data as Record<string, unknown>)?.path_id as number | undefined;
+        let mediaId: number | undefined;
+        for (const e of history) {
+            const id = (e.data as Record<string, unknown> | undefined)?.media_id;
+            if (typeof id === 'number' && id > 0) mediaId = id;
+        }
         
         return {
             originalFilename,
@@ -111,9 +193,65 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
             isFailed,
             isIgnored,
             filesAreDifferent: newFilename && newFilename !== originalFilename,
+            activeDownload,
+            pathId,
+            mediaId,
         };
     }, [history]);
 
+    const { data: scanPaths } = useQuery({
+        queryKey: ['scanPaths'],
+        queryFn: getScanPaths,
+        enabled: !!summary?.mediaId,
+    });
+    const arrInstanceId = scanPaths?.find(p => p.id === summary?.pathId)?.arr_instance_id ?? undefined;
+
+    const { data: mediaHistory } = useQuery({
+        queryKey: ['mediaHistory', arrInstanceId, summary?.mediaId],
+        queryFn: () => getMediaHistory(arrInstanceId!, summary!.mediaId!),
+        enabled: !!arrInstanceId && !!summary?.mediaId,
+        retry: false,
+    });
+
+    const { data: related } = useQuery({
+        queryKey: ['relatedCorruptions', corruptionId],
+        queryFn: () => getRelatedCorruptions(corruptionId),
+        enabled: !!summary?.mediaId,
+        retry: false,
+    });
+
+    // e.g. "3 other episodes from this season are also being replaced"
+    const relatedNote = useMemo(() => {
+        if (!related || related.related.length === 0) return null;
+        const count = (n: number, one: string, many: string) => `${n} other ${n === 1 ? one : many}`;
+        if (related.active.season > 0) {
+            return `${count(related.active.season, 'episode', 'episodes')} from this season ${related.active.season === 1 ? 'is' : 'are'} also being replaced`;
+        }
+        if (related.active.download > 0) {
+            return `${count(related.active.download, 'file', 'files')} ${related.active.download === 1 ? 'is' : 'are'} being replaced by the same download`;
+        }
+        if (related.active.media > 0) {
+            return `${count(related.active.media, 'file', 'files')} of this item ${related.active.media === 1 ? 'is' : 'are'} also being replaced`;
+        }
+        return `${count(related.related.length, 'corruption', 'corruptions')} of this item, all finished`;
+    }, [related]);
+
+    const { data: deletionPlan } = useQuery({
+        queryKey: ['deletionPlan', corruptionId, history?.length],
+        queryFn: () => getDeletionPlan(corruptionId),
+        enabled: !!history?.some(e => e.event_type === 'RemediationQueued'),
+        retry: false,
+    });
+
+    // Progress is re-emitted while a download runs; only the latest update of
+    // each run of DownloadProgress events is shown in the timeline
+    const timeline = useMemo(() => {
+        if (!history) return [];
+        return history.filter((event, idx) =>
+            event.event_type !== 'DownloadProgress' || history[idx + 1]?.event_type !== 'DownloadProgress'
+        );
+    }, [history]);
+
     // Persistent setting for showing details by default
     const [showDetailsDefault, setShowDetailsDefault] = useState(() => {
         return localStorage.getItem('healarr_remediation_details_default') === 'true';
@@ -147,6 +285,47 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
         }
     };
 
+    // State for support bundle download
+    const [bundleState, setBundleState] = useState<'idle' | 'loading' | 'error'>('idle');
+
+    const downloadSupportBundle = async () => {
+        setBundleState('loading');
+        try {
+            await downloadCorruptionSupportBundle(corruptionId);
+            setBundleState('idle');
+        } catch {
+            setBundleState('error');
+        }
+    };
+
+    // State for sample download
+    const [sampleState, setSampleState] = useState<'idle' | 'loading' | 'error'>('idle');
+
+    const downloadSample = async () => {
+        setSampleState('loading');
+        try {
+            await downloadCorruptionSample(corruptionId);
+            setSampleState('idle');
+        } catch {
+            setSampleState('error');
+        }
+    };
+
+    // State for reverifying a resolved corruption
+    const queryClient = useQueryClient();
+    const [reverifyState, setReverifyState] = useState<'idle' | 'loading' | 'error'>('idle');
+
+    const reverify = async () => {
+        setReverifyState('loading');
+        try {
+            await reverifyCorruption(corruptionId);
+            setReverifyState('idle');
+            queryClient.invalidateQueries({ queryKey: ['history', corruptionId] });
+        } catch {
+            setReverifyState('error');
+        }
+    };
+
     const toggleItem = (idx: number) => {
         setExpandedItems(prev => {
             const current = prev[idx] ?? showDetailsDefault;
@@ -211,11 +390,97 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                                             </span>
                                         </div>
                                     )}
+                                    {mediaHistory && mediaHistory.summary.total_corruptions > 1 && (
+                                        <div className="flex items-start gap-2 text-sm" title="All corruptions recorded for this media item">
+                                            <span className="text-slate-500 shrink-0">Media history:</span>
+                                            <span className="text-amber-500 dark:text-amber-400 text-xs">
+                                                {mediaHistory.media_title ?? 'This item'} has been corrupted {mediaHistory.summary.total_corruptions} times
+                                                {mediaHistory.summary.times_replaced > 0 && `, replaced ${mediaHistory.summary.times_replaced}×`}
+                                                {mediaHistory.summary.failed > 0 && `, ${mediaHistory.summary.failed} failed`}
+                                            </span>
+                                        </div>
+                                    )}
+                                    {related && relatedNote && (
+                                        <div className="flex items-start gap-2 text-sm" title={related.related.map(r => `${r.file_path} (${r.state})`).join('\n')}>
+                                            <span className="text-slate-500 shrink-0">Related:</span>
+                                            <span className="text-blue-500 dark:text-blue-400 text-xs">{relatedNote}</span>
+                                        </div>
+                                    )}
+                                    {deletionPlan && (
+                                        <div className="flex items-start gap-2 text-sm" title={`Planned ${formatFull(deletionPlan.planned_at)}`}>
+                                            <span className="text-slate-500 shrink-0">{deletionPlan.dry_run ? 'Dry run would delete:' : 'Deletion plan:'}</span>
+                                            <div className="min-w-0 flex-1 text-xs">
+                                                {deletionPlan.disk_paths.length === 0 ? (
+                                                    <span className="text-slate-500">Nothing - the file isn't tracked by *arr</span>
+                                                ) : deletionPlan.disk_paths.map(path => (
+                                                    <div key={path} className="text-slate-700 dark:text-slate-300 font-mono break-all">{path}</div>
+                                                ))}
+                                                {deletionPlan.arr_files.map(file => (
+                                                    <div key={file.id} className="text-slate-500">
+                                                        *arr file #{file.id} ({formatBytes(file.size)})
+                                                    </div>
+                                                ))}
+                                                {deletionPlan.action === 'quarantine_untracked' && (
+                                                    <div className="text-slate-500">Moved to quarantine, not tracked by *arr</div>
+                                                )}
+                                            </div>
+                                        </div>
+                                    )}
+                                    {summary.activeDownload && (
+                                        <div className="text-sm">
+                                            <span className="text-slate-500">Downloading replacement</span>
+                                            <DownloadProgressInfo data={summary.activeDownload} />
+                                        </div>
+                                    )}
                                 </div>
                             )}
                         </div>
                         
                         <div className="flex items-center gap-3 shrink-0">
+                            {summary?.isResolved && (
+                                <button
+                                    onClick={reverify}
+                                    disabled={reverifyState === 'loading'}
+                                    className={clsx(
+                                        "flex items-center gap-2 px-3 py-1.5 rounded-lg text-xs font-medium transition-colors border disabled:opacity-50",
+                                        reverifyState === 'error'
+                                            ? "bg-red-500/20 border-red-500/30 text-red-400"
+                                            : "bg-slate-800 border-slate-300 dark:border-slate-700 text-slate-600 dark:text-slate-400 hover:text-slate-700 dark:text-slate-300"
+                                    )}
+                                    title="Check the replacement again; if it fails, the item is remediated again"
+                                >
+                                    <RotateCcw className={clsx("w-3 h-3", reverifyState === 'loading' && "animate-spin")} />
+                                    {reverifyState === 'error' ? "Reverify failed" : "Reverify"}
+                                </button>
+                            )}
+                            <button
+                                onClick={downloadSupportBundle}
+                                disabled={bundleState === 'loading'}
+                                className={clsx(
+                                    "flex items-center gap-2 px-3 py-1.5 rounded-lg text-xs font-medium transition-colors border disabled:opacity-50",
+                                    bundleState === 'error'
+                                        ? "bg-red-500/20 border-red-500/30 text-red-400"
+                                        : "bg-slate-800 border-slate-300 dark:border-slate-700 text-slate-600 dark:text-slate-400 hover:text-slate-700 dark:text-slate-300"
+                                )}
+                                title="Download a support bundle for bug reports (API keys are redacted)"
+                            >
+                                <LifeBuoy className={clsx("w-3 h-3", bundleState === 'loading' && "animate-spin")} />
+                                {bundleState === 'error' ? "Bundle failed" : "Support bundle"}
+                            </button>
+                            <button
+                                onClick={downloadSample}
+                                disabled={sampleState === 'loading'}
+                                className={clsx(
+                                    "flex items-center gap-2 px-3 py-1.5 rounded-lg text-xs font-medium transition-colors border disabled:opacity-50",
+                                    sampleState === 'error'
+                                        ? "bg-red-500/20 border-red-500/30 text-red-400"
+                                        : "bg-slate-800 border-slate-300 dark:border-slate-700 text-slate-600 dark:text-slate-400 hover:text-slate-700 dark:text-slate-300"
+                                )}
+                                title="Download a few MB of the file around its first error, extracted if needed while the file still exists"
+                            >
+                                <Scissors className={clsx("w-3 h-3", sampleState === 'loading' && "animate-pulse")} />
+                                {sampleState === 'error' ? "No sample" : "File sample"}
+                            </button>
                             <button
                                 onClick={toggleAll}
                                 className={clsx(
@@ -245,11 +510,11 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                     ) : (
                         <div className="relative py-4">
                             <div className="space-y-8">
-                                {history?.map((event, idx) => {
+                                {timeline.map((event, idx) => {
                                     const hasDetails = !!(event.data && typeof event.data === 'object' && Object.keys(event.data as object).length > 0);
                                     const isExpanded = expandedItems[idx] ?? showDetailsDefault;
                                     const isFirst = idx === 0;
-                                    const isLast = idx === (history.length - 1);
+                                    const isLast = idx === (timeline.length - 1);
 
                                     // Special handling for DeletionCompleted to show path
                                     let primaryInfo = null;
@@ -278,6 +543,30 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                                         }
                                     }
 
+                                    // DeletionFailed with forensics: show what the *arr answered and whether it retries
+                                    if (event.event_type === 'DeletionFailed' && event.data && typeof event.data === 'object') {
+                                        const data = event.data as Record<string, unknown>;
+                                        const forensics = data.forensics as DeletionForensics | undefined;
+                                        if (forensics) {
+                                            primaryInfo = (
+                                                <div className="text-xs text-slate-600 dark:text-slate-400 mt-1 space-y-0.5">
+                                                    {forensics.arr_status ? (
+                                                        <div className="font-mono break-all">
+                                                            *arr HTTP {forensics.arr_status}
+                                                            {forensics.arr_response && <span className="text-slate-700 dark:text-slate-300">: {forensics.arr_response.slice(0, 200)}</span>}
+                                                        </div>
+                                                    ) : null}
+                                                    <div>
+                                                        File {forensics.file.exists ? 'still on disk' : 'not on disk'}, folder {forensics.mount.accessible ? (forensics.mount.writable ? 'writable' : 'not writable') : 'not reachable'}
+                                                    </div>
+                                                    {!forensics.retryable && (
+                                                        <div className="text-orange-500 dark:text-orange-400">Not retried automatically - fix the cause, then retry</div>
+                                                    )}
+                                                </div>
+                                            );
+                                        }
+                                    }
+
                                     // Enriched SearchCompleted: show download client, protocol, indexer
                                     if (event.event_type === 'SearchCompleted' && event.data && typeof event.data === 'object') {
                                         const data = event.data as Record<string, unknown>;
@@ -337,62 +626,8 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                                     // Enriched DownloadProgress: show progress bar and client info
                                     if (event.event_type === 'DownloadProgress' && event.data && typeof event.data === 'object') {
                                         const data = event.data as Record<string, unknown>;
-                                        const progress = data.download_progress as number;
-                                        const downloadClient = data.download_client as string;
-                                        const downloadProtocol = data.download_protocol as string;
-                                        const downloadSize = data.download_size as number;
-                                        const downloadRemaining = data.download_remaining as number;
-                                        const timeLeft = data.download_time_left as string;
-
-                                        if (progress !== undefined) {
-                                            primaryInfo = (
-                                                <div className="mt-2 space-y-2">
-                                                    {/* Progress bar */}
-                                                    <div className="flex items-center gap-2">
-                                                        <div className="flex-1 h-2 bg-slate-200 dark:bg-slate-800 rounded-full overflow-hidden">
-                                                            <div
-                                                                className="h-full bg-blue-500 rounded-full transition-all duration-300"
-                                                                style={{ width: `${Math.min(100, Math.max(0, progress))}%` }}
-                                                            />
-                                                        </div>
-                                                        <span className="text-xs font-medium text-blue-400 w-12 text-right">
-                                                            {progress.toFixed(1)}%
-                                                        </span>
-                                                    </div>
-
-                                                    {/* Download info */}
-                                                    <div className="flex items-center gap-3 text-xs text-slate-500 flex-wrap">
-                                                        {downloadClient && (
-                                                            <span className="flex items-center gap-1.5">
-                                                                <img
-                                                                    src={getDownloadClientIcon(downloadClient)}
-                                                                    alt={downloadClient}
-                                                                    className="w-4 h-4 object-contain"
-                                                                    onError={(e) => { (e.target as HTMLImageElement).src = '/icons/download-clients/generic.svg'; }}
-                                                                />
-                                                                <span>{downloadClient}</span>
-                                                                {downloadProtocol && (
-                                                                    <span className="text-slate-600 dark:text-slate-400">({downloadProtocol})</span>
-                                                                )}
-                                                            </span>
-                                                        )}
-                                                        {downloadSize && (
-                                                            <span>
-                                                                {downloadRemaining ? (
-                                                                    <>{formatBytes(downloadSize - downloadRemaining)} / {formatBytes(downloadSize)}</>
-                                                                ) : (
-                                                                    formatBytes(downloadSize)
-                                                                )}
-                                                            </span>
-                                                        )}
-                                                        {timeLeft && (
-                                                            <span className="text-slate-600 dark:text-slate-400">
-                                                                ~{timeLeft} remaining
-                                                            </span>
-                                                        )}
-                                                    </div>
-                                                </div>
-                                            );
+                                        if (typeof data.progress === 'number') {
+                                            primaryInfo = <DownloadProgressInfo data={data} />;
                                         }
                                     }
 
@@ -405,8 +640,10 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                                         const originalFileSize = data.file_size as number;
                                         const totalDuration = data.total_duration_seconds as number;
                                         const downloadDuration = data.download_duration_seconds as number;
+                                        const rescanTriggered = data.rescan_triggered === true;
+                                        const rescanError = data.rescan_error as string;
 
-                                        const hasEnrichedData = quality || releaseGroup || newFileSize || totalDuration;
+                                        const hasEnrichedData = quality || releaseGroup || newFileSize || totalDuration || rescanTriggered || rescanError;
 
                                         if (hasEnrichedData) {
                                             const qualityInfo = quality ? formatQuality(quality) : null;
@@ -456,6 +693,14 @@ const RemediationJourney: React.FC<RemediationJourneyProps> = ({ corruptionId, o
                                                                 )}
                                                             </span>
                                                         )}
+                                                        {rescanTriggered && (
+                                                            <span>*arr rescanned</span>
+                                                        )}
+                                                        {rescanError && (
+                                                            <span className="text-amber-400" title={rescanError}>
+                                                                *arr rescan failed
+                                                            </span>
+                                                        )}
                                                     </div>
                                                 </div>
                                             );
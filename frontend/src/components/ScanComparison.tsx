@@ -0,0 +1,112 @@
+import { useState } from 'react';
+import { useQuery } from '@tanstack/react-query';
+import { useNavigate } from 'react-router-dom';
+import { GitCompare } from 'lucide-react';
+import clsx from 'clsx';
+import { compareScans, type ScanComparison as Comparison, type ScanCompareFile } from '../lib/api';
+import { formatCorruptionType } from '../lib/formatters';
+import { useDateFormat } from '../lib/useDateFormat';
+
+type Category = 'newly_corrupted' | 'newly_healthy' | 'still_corrupted' | 'removed';
+
+const categories: { key: Category; label: string; color: string }[] = [
+    { key: 'newly_corrupted', label: 'Newly Corrupted', color: 'text-red-400' },
+    { key: 'newly_healthy', label: 'Newly Healthy', color: 'text-emerald-400' },
+    { key: 'still_corrupted', label: 'Still Corrupted', color: 'text-amber-400' },
+    { key: 'removed', label: 'Removed', color: 'text-slate-400' },
+];
+
+// Only the first files of a category are listed; the rest are counted
+const maxListed = 100;
+
+const describe = (category: Category, file: ScanCompareFile) => {
+    switch (category) {
+        case 'newly_corrupted':
+            return `${formatCorruptionType(file.corruption_type ?? '')} · ${file.previous_status ? `was ${file.previous_status}` : 'new file'}`;
+        case 'newly_healthy':
+            return `was ${formatCorruptionType(file.previous_corruption_type ?? '')}`;
+        case 'still_corrupted':
+            return file.corruption_type === file.previous_corruption_type
+                ? formatCorruptionType(file.corruption_type ?? '')
+                : `${formatCorruptionType(file.corruption_type ?? '')} · was ${formatCorruptionType(file.previous_corruption_type ?? '')}`;
+        case 'removed':
+            return `was ${file.status}`;
+    }
+};
+
+// Changes in the per-file results since the previous completed scan of the path
+const ScanComparison = ({ scanId }: { scanId: number }) => {
+    const navigate = useNavigate();
+    const { formatCompact } = useDateFormat();
+    const [category, setCategory] = useState<Category>('newly_corrupted');
+
+    const { data, error } = useQuery<Comparison>({
+        queryKey: ['scan-compare', scanId],
+        queryFn: () => compareScans(scanId),
+        retry: false,
+    });
+
+    if (error) {
+        const err = error as { response?: { data?: { error?: string } } };
+        return (
+            <div className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/50 p-4 text-sm text-slate-500">
+                <GitCompare className="w-4 h-4 inline mr-2" />
+                {err.response?.data?.error ?? 'No comparison with a previous scan available'}
+            </div>
+        );
+    }
+    if (!data) return null;
+
+    const files = data[category];
+
+    return (
+        <div className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/50 overflow-hidden">
+            <div className="p-4 border-b border-slate-200 dark:border-slate-800 flex items-center justify-between gap-4 flex-wrap">
+                <div className="flex items-center gap-2">
+                    <GitCompare className="w-5 h-5 text-blue-400" />
+                    <h2 className="text-lg font-semibold text-slate-900 dark:text-white">Changes Since Previous Scan</h2>
+                </div>
+                <button
+                    onClick={() => navigate(`/scans/${data.a.id}`)}
+                    className="text-xs text-slate-500 hover:text-blue-400 cursor-pointer"
+                >
+                    Scan #{data.a.id} · {formatCompact(data.a.started_at)}
+                </button>
+            </div>
+            <div className="grid grid-cols-2 md:grid-cols-4 border-b border-slate-200 dark:border-slate-800">
+                {categories.map(c => (
+                    <button
+                        key={c.key}
+                        onClick={() => setCategory(c.key)}
+                        className={clsx(
+                            "p-3 text-left transition-colors cursor-pointer",
+                            category === c.key ? "bg-slate-100 dark:bg-slate-800/50" : "hover:bg-slate-50 dark:hover:bg-slate-800/30"
+                        )}
+                    >
+                        <p className={clsx("text-xl font-bold", c.color)}>{data[c.key].length}</p>
+                        <p className="text-xs text-slate-600 dark:text-slate-400">{c.label}</p>
+                    </button>
+                ))}
+            </div>
+            {files.length === 0 ? (
+                <div className="p-6 text-center text-sm text-slate-500 italic">No files</div>
+            ) : (
+                <ul className="divide-y divide-slate-200 dark:divide-slate-800/50 max-h-96 overflow-y-auto">
+                    {files.slice(0, maxListed).map(file => (
+                        <li key={file.file_path} className="px-4 py-2 flex items-center justify-between gap-4 text-sm">
+                            <span className="font-mono text-xs text-slate-700 dark:text-slate-300 truncate" title={file.file_path}>
+                                {file.file_path}
+                            </span>
+                            <span className="text-xs text-slate-500 shrink-0">{describe(category, file)}</span>
+                        </li>
+                    ))}
+                    {files.length > maxListed && (
+                        <li className="px-4 py-2 text-xs text-slate-500">and {files.length - maxListed} more</li>
+                    )}
+                </ul>
+            )}
+        </div>
+    );
+};
+
+export default ScanComparison;
@@ -25,7 +25,7 @@ import {
 import FileBrowser from './ui/FileBrowser';
 import { ProviderSelect, ProviderFields, EventSelector } from './notifications';
 import { PROVIDER_CONFIGS, getProviderLabel } from '../lib/notificationProviders';
-import type { RootFolder, ConfigExport, EventGroup } from '../lib/api';
+import type { RootFolder, ConfigExport, EventGroup, SetupRootFolder, SetupWizardStep } from '../lib/api';
 import api, {
     getSetupStatus,
     dismissSetup,
@@ -41,6 +41,11 @@ import api, {
     getArrInstances,
     getScanPaths,
     getNotifications,
+    getSetupWizard,
+    saveSetupWizard,
+    clearSetupWizard,
+    getSetupRootFolders,
+    startFirstScan,
     type NotificationConfig,
 } from '../lib/api';
 
@@ -49,7 +54,7 @@ interface SetupWizardProps {
     onSkip: () => void;
 }
 
-type WizardStep = 'welcome' | 'password' | 'arr' | 'path' | 'notifications' | 'complete';
+type WizardStep = SetupWizardStep;
 
 interface ArrFormData {
     name: string;
@@ -64,7 +69,14 @@ interface PathFormData {
     arr_instance_id: number | null;
 }
 
-const STEPS: WizardStep[] = ['welcome', 'password', 'arr', 'path', 'notifications', 'complete'];
+const STEPS: WizardStep[] = ['welcome', 'password', 'arr', 'path', 'schedule', 'notifications', 'complete'];
+
+// Schedule presets offered for the first scan path
+const SCHEDULE_PRESETS = [
+    { label: 'Daily at 3 AM', cron: '0 3 * * *' },
+    { label: 'Weekly on Sunday at 3 AM', cron: '0 3 * * 0' },
+    { label: 'No schedule', cron: '' },
+];
 
 export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
     const [step, setStep] = useState<WizardStep>('welcome');
@@ -97,6 +109,12 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
     const [rootFolders, setRootFolders] = useState<RootFolder[]>([]);
     const [loadingRootFolders, setLoadingRootFolders] = useState(false);
     const [fileBrowserOpen, setFileBrowserOpen] = useState(false);
+    const [folderSuggestions, setFolderSuggestions] = useState<SetupRootFolder[]>([]);
+    const [createdPathId, setCreatedPathId] = useState<number | null>(null);
+
+    // Schedule step
+    const [scheduleCron, setScheduleCron] = useState(SCHEDULE_PRESETS[0].cron);
+    const [scanNow, setScanNow] = useState(true);
 
     // Notifications step - full configuration like Config page
     const [notificationData, setNotificationData] = useState<{
@@ -198,9 +216,21 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
         }
     }, []);
 
-    // Check setup status on load and determine starting step
+    // Check setup status on load and determine starting step. Logged-in users
+    // resume where they left the wizard, which the server keeps track of.
     useEffect(() => {
         const checkStatus = async () => {
+            if (localStorage.getItem('healarr_token')) {
+                try {
+                    const progress = await getSetupWizard();
+                    if (progress.state.instance_id) setCreatedArrId(progress.state.instance_id);
+                    if (progress.state.path_id) setCreatedPathId(progress.state.path_id);
+                    setStep(progress.next_step);
+                    return;
+                } catch (err) {
+                    console.error('Failed to load setup wizard progress:', err);
+                }
+            }
             try {
                 const status = await getSetupStatus();
                 // If password is already set, skip to next needed step
@@ -218,22 +248,61 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
         checkStatus();
     }, []);
 
+    // Persist progress so the wizard resumes here if the browser is closed
+    useEffect(() => {
+        if (step === 'welcome' || !localStorage.getItem('healarr_token')) return;
+        if (step === 'complete') {
+            clearSetupWizard().catch(err => console.error('Failed to clear setup wizard progress:', err));
+            return;
+        }
+        saveSetupWizard({
+            step,
+            instance_id: createdArrId ?? undefined,
+            path_id: createdPathId ?? undefined,
+        }).catch(err => console.error('Failed to save setup wizard progress:', err));
+    }, [step, createdArrId, createdPathId]);
+
+    // Find the scan path to schedule when resuming without one recorded
+    useEffect(() => {
+        if (step === 'schedule' && !createdPathId) {
+            getScanPaths()
+                .then(paths => {
+                    if (paths.length > 0) setCreatedPathId(paths[0].id);
+                })
+                .catch(err => console.error('Failed to load scan paths:', err));
+        }
+    }, [step, createdPathId]);
+
+    // Use the local path suggested for a root folder unless one was entered
+    const selectArrPath = useCallback((arrPath: string, suggestions: SetupRootFolder[]) => {
+        const suggestion = suggestions.find(f => f.path === arrPath.replace(/\/+$/, ''));
+        setPathData(prev => ({
+            ...prev,
+            arr_path: arrPath,
+            local_path: prev.local_path || suggestion?.suggested_local_path || '',
+        }));
+    }, []);
+
     // Load root folders when ARR instance is created
     const loadRootFolders = useCallback(async (instanceId: number) => {
         setLoadingRootFolders(true);
         try {
-            const folders = await getArrRootFolders(instanceId);
+            const [folders, suggestions] = await Promise.all([
+                getArrRootFolders(instanceId),
+                getSetupRootFolders(instanceId).catch(() => [] as SetupRootFolder[]),
+            ]);
             setRootFolders(folders);
+            setFolderSuggestions(suggestions);
             // Auto-fill arr_path if only one root folder
             if (folders.length === 1) {
-                setPathData(prev => ({ ...prev, arr_path: folders[0].path }));
+                selectArrPath(folders[0].path, suggestions);
             }
         } catch (err) {
             console.error('Failed to load root folders:', err);
         } finally {
             setLoadingRootFolders(false);
         }
-    }, []);
+    }, [selectArrPath]);
 
     useEffect(() => {
         if (createdArrId) {
@@ -417,7 +486,10 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
                 enabled: true,
                 auto_remediate: true,
             });
-            setStep('notifications');
+            const paths = await getScanPaths();
+            const created = paths.find(p => p.local_path === pathData.local_path);
+            if (created) setCreatedPathId(created.id);
+            setStep('schedule');
         } catch (err: unknown) {
             const error = err as { response?: { data?: { error?: string } } };
             setError(error.response?.data?.error || 'Failed to create scan path');
@@ -920,15 +992,18 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
                     ) : rootFolders.length > 0 ? (
                         <select
                             value={pathData.arr_path}
-                            onChange={(e) => setPathData(prev => ({ ...prev, arr_path: e.target.value }))}
+                            onChange={(e) => selectArrPath(e.target.value, folderSuggestions)}
                             className="w-full px-4 py-3 bg-slate-100 dark:bg-slate-800/50 border border-slate-300 dark:border-slate-700 rounded-xl text-slate-900 dark:text-white focus:outline-none focus:ring-2 focus:ring-green-500/50 focus:border-green-500 font-mono"
                         >
                             <option value="">Select a root folder...</option>
-                            {rootFolders.map(folder => (
-                                <option key={folder.id} value={folder.path}>
-                                    {folder.path}
-                                </option>
-                            ))}
+                            {rootFolders.map(folder => {
+                                const suggestion = folderSuggestions.find(f => f.path === folder.path.replace(/\/+$/, ''));
+                                return (
+                                    <option key={folder.id} value={folder.path} disabled={!!suggestion?.scan_path_id}>
+                                        {folder.path}{suggestion?.scan_path_id ? ' (already configured)' : ''}
+                                    </option>
+                                );
+                            })}
                         </select>
                     ) : (
                         <input
@@ -985,6 +1060,113 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
         </motion.div>
     );
 
+    const handleFirstScan = async () => {
+        if (!createdPathId) {
+            setStep('notifications');
+            return;
+        }
+
+        setLoading(true);
+        setError('');
+
+        try {
+            await startFirstScan({
+                path_id: createdPathId,
+                cron_expression: scheduleCron || undefined,
+                timezone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+                scan_now: scanNow,
+            });
+            setStep('notifications');
+        } catch (err: unknown) {
+            const error = err as { response?: { data?: { error?: string } } };
+            setError(error.response?.data?.error || 'Failed to schedule scans');
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const renderSchedule = () => (
+        <motion.div
+            key="schedule"
+            initial={{ opacity: 0, x: 20 }}
+            animate={{ opacity: 1, x: 0 }}
+            exit={{ opacity: 0, x: -20 }}
+            className="space-y-6"
+        >
+            <div className="text-center">
+                <h2 className="text-2xl font-bold text-slate-900 dark:text-white mb-2">
+                    Schedule Scans
+                </h2>
+                <p className="text-slate-600 dark:text-slate-400">
+                    Choose how often Healarr checks the path, and run the first scan now.
+                </p>
+            </div>
+
+            <div className="space-y-2">
+                {SCHEDULE_PRESETS.map(preset => (
+                    <button
+                        key={preset.label}
+                        type="button"
+                        onClick={() => setScheduleCron(preset.cron)}
+                        className={`w-full flex items-center gap-3 p-3 rounded-xl border-2 transition-colors text-left cursor-pointer ${
+                            scheduleCron === preset.cron
+                                ? 'border-green-500 bg-green-50 dark:bg-green-900/20'
+                                : 'border-slate-200 dark:border-slate-700 hover:border-green-500 dark:hover:border-green-500'
+                        }`}
+                    >
+                        <Clock className="w-5 h-5 text-amber-500 flex-shrink-0" />
+                        <span className="text-slate-900 dark:text-white">{preset.label}</span>
+                        {preset.cron && (
+                            <span className="ml-auto font-mono text-xs text-slate-500 dark:text-slate-400">{preset.cron}</span>
+                        )}
+                    </button>
+                ))}
+            </div>
+
+            <label className="flex items-center gap-3 text-slate-700 dark:text-slate-300 cursor-pointer">
+                <input
+                    type="checkbox"
+                    checked={scanNow}
+                    onChange={(e) => setScanNow(e.target.checked)}
+                    className="w-4 h-4 rounded accent-green-500"
+                />
+                <PlayCircle className="w-5 h-5 text-green-500" />
+                Start the first scan now
+            </label>
+
+            <div className="flex gap-3">
+                <button
+                    onClick={() => setStep('path')}
+                    className="px-4 py-3 rounded-xl border border-slate-300 dark:border-slate-600 text-slate-700 dark:text-slate-300 hover:bg-slate-100 dark:hover:bg-slate-800 transition-colors flex items-center gap-2 cursor-pointer"
+                >
+                    <ArrowLeft className="w-4 h-4" />
+                    Back
+                </button>
+                <button
+                    onClick={handleFirstScan}
+                    disabled={loading || (!scheduleCron && !scanNow)}
+                    className="flex-1 py-3 px-4 bg-gradient-to-r from-green-500 to-emerald-600 hover:from-green-600 hover:to-emerald-700 text-white font-semibold rounded-xl transition-all shadow-lg shadow-green-500/20 flex items-center justify-center gap-2 disabled:opacity-50 cursor-pointer disabled:cursor-not-allowed"
+                >
+                    {loading ? (
+                        <div className="w-5 h-5 border-2 border-white/30 border-t-white rounded-full animate-spin" />
+                    ) : (
+                        <>
+                            <span>Continue</span>
+                            <ArrowRight className="w-5 h-5" />
+                        </>
+                    )}
+                </button>
+            </div>
+
+            <button
+                onClick={() => setStep('notifications')}
+                className="w-full text-sm text-slate-500 dark:text-slate-400 hover:text-slate-700 dark:hover:text-slate-300 transition-colors cursor-pointer"
+            >
+                Skip for now
+            </button>
+        </motion.div>
+    );
+
     const handleTestNotification = async () => {
         if (notificationData.provider_type === 'none') return;
 
@@ -1208,7 +1390,7 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
 
             <div className="flex gap-3">
                 <button
-                    onClick={() => setStep('path')}
+                    onClick={() => setStep(createdPathId ? 'schedule' : 'path')}
                     className="px-4 py-3 rounded-xl border border-slate-300 dark:border-slate-600 text-slate-700 dark:text-slate-300 hover:bg-slate-100 dark:hover:bg-slate-800 transition-colors flex items-center gap-2 cursor-pointer"
                 >
                     <ArrowLeft className="w-4 h-4" />
@@ -1370,6 +1552,7 @@ export default function SetupWizard({ onComplete, onSkip }: SetupWizardProps) {
                         {step === 'password' && renderPassword()}
                         {step === 'arr' && renderArr()}
                         {step === 'path' && renderPath()}
+                        {step === 'schedule' && renderSchedule()}
                         {step === 'notifications' && renderNotifications()}
                         {step === 'complete' && renderComplete()}
                     </AnimatePresence>
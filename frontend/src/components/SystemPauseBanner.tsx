@@ -0,0 +1,84 @@
+import { PauseCircle, Play } from 'lucide-react';
+import { motion } from 'framer-motion';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import clsx from 'clsx';
+import { getSystemPause, resumeSystem } from '../lib/api';
+import { useDateFormat } from '../lib/useDateFormat';
+import { useToast } from '../contexts/ToastContext';
+
+/**
+ * SystemPauseBanner is shown on every page while automation is paused
+ * (POST /api/system/pause), so a forgotten maintenance pause doesn't leave
+ * corruptions unremediated. Pausing and resuming are broadcast over the
+ * WebSocket, which refreshes the banner.
+ */
+export default function SystemPauseBanner() {
+    const { formatCompact } = useDateFormat();
+    const toast = useToast();
+    const queryClient = useQueryClient();
+    const { data: pause } = useQuery({
+        queryKey: ['systemPause'],
+        queryFn: getSystemPause,
+        refetchInterval: 60000,
+        retry: 1,
+    });
+
+    const resumeMutation = useMutation({
+        mutationFn: resumeSystem,
+        onSuccess: () => {
+            toast.success('Automation resumed');
+            queryClient.invalidateQueries({ queryKey: ['systemPause'] });
+            queryClient.invalidateQueries({ queryKey: ['remediationQueue'] });
+        },
+        onError: (error: unknown) => {
+            const err = error as { response?: { data?: { error?: string } }; message?: string };
+            toast.error(`Failed to resume automation: ${err.response?.data?.error || err.message}`);
+        }
+    });
+
+    if (!pause?.paused) {
+        return null;
+    }
+
+    const held = pause.detection_only
+        ? 'Remediation and verification are on hold, scheduled scans continue.'
+        : 'Remediation, verification and scheduled scans are on hold.';
+
+    return (
+        <motion.div
+            initial={{ opacity: 0, y: -20 }}
+            animate={{ opacity: 1, y: 0 }}
+            className={clsx(
+                "mb-6 rounded-xl border-2 p-4",
+                "bg-amber-50 dark:bg-amber-950/30 border-amber-300 dark:border-amber-700/50"
+            )}
+        >
+            <div className="flex items-center gap-3 flex-wrap">
+                <div className="flex-shrink-0 p-2 rounded-lg bg-amber-100 dark:bg-amber-900/50">
+                    <PauseCircle className="w-5 h-5 text-amber-600 dark:text-amber-400" />
+                </div>
+
+                <div className="flex-1 min-w-0">
+                    <h3 className="font-semibold text-amber-800 dark:text-amber-200">
+                        Automation Paused{pause.reason ? `: ${pause.reason}` : ''}
+                    </h3>
+                    <p className="text-sm text-amber-700 dark:text-amber-300">
+                        {held}{' '}
+                        {pause.resumes_at
+                            ? `Resumes automatically at ${formatCompact(pause.resumes_at)}.`
+                            : 'Paused until resumed.'}
+                    </p>
+                </div>
+
+                <button
+                    onClick={() => resumeMutation.mutate()}
+                    disabled={resumeMutation.isPending}
+                    className="flex items-center gap-2 px-4 py-2 bg-green-500/10 hover:bg-green-500/20 text-green-600 dark:text-green-400 rounded-lg transition-colors border border-green-500/20 cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                    <Play className="w-4 h-4" />
+                    {resumeMutation.isPending ? 'Resuming...' : 'Resume Now'}
+                </button>
+            </div>
+        </motion.div>
+    );
+}
@@ -1,6 +1,7 @@
 import { useEffect, useState } from 'react';
 import { Navigate } from 'react-router-dom';
 import { getAuthStatus } from '../../lib/api';
+import { syncPreferences } from '../../lib/preferences';
 import { useWebSocket } from '../../contexts/WebSocketProvider';
 
 interface ProtectedRouteProps {
@@ -44,6 +45,7 @@ const ProtectedRoute = ({ children }: ProtectedRouteProps) => {
                     // Now that we've verified the token is valid, connect WebSocket
                     // This ensures we don't try to connect with stale tokens
                     reconnect();
+                    syncPreferences();
                 }
             } catch {
                 // Token might be invalid
@@ -0,0 +1,94 @@
+import { useState } from 'react';
+import { Treemap, Tooltip, ResponsiveContainer } from 'recharts';
+import { useQuery } from '@tanstack/react-query';
+import { getStatsHeatmap, type HeatmapDirectory } from '../../lib/api';
+
+const DEPTHS = [
+    { value: 1, label: 'Show / Movie' },
+    { value: 2, label: 'Season' },
+    { value: 3, label: '3 levels' },
+];
+
+// Treemap cells are sized by corruptions and colored by the share still unresolved
+const cellColor = (dir: HeatmapDirectory) => {
+    const unresolved = dir.corruptions > 0 ? dir.unresolved / dir.corruptions : 0;
+    if (unresolved >= 0.66) return '#ef4444';
+    if (unresolved >= 0.33) return '#f97316';
+    if (unresolved > 0) return '#eab308';
+    return '#22c55e';
+};
+
+interface CellProps {
+    x?: number;
+    y?: number;
+    width?: number;
+    height?: number;
+    name?: string;
+    fill?: string;
+}
+
+const HeatmapCell = ({ x = 0, y = 0, width = 0, height = 0, name, fill }: CellProps) => (
+    <g>
+        <rect x={x} y={y} width={width} height={height} fill={fill} stroke="#0f172a" strokeWidth={2} rx={4} />
+        {width > 70 && height > 24 && (
+            <text x={x + 6} y={y + 16} fill="#0f172a" fontSize={12} fontWeight={600}>
+                {name && name.length > width / 7 ? `${name.slice(0, Math.floor(width / 7) - 1)}…` : name}
+            </text>
+        )}
+    </g>
+);
+
+const CorruptionHeatmap = () => {
+    const [depth, setDepth] = useState(2);
+    const { data: heatmap, isLoading } = useQuery({
+        queryKey: ['statsHeatmap', depth],
+        queryFn: () => getStatsHeatmap(depth),
+    });
+
+    const cells = (heatmap?.directories ?? []).map(dir => ({
+        ...dir,
+        name: dir.name === '.' ? dir.directory : dir.name,
+        size: dir.corruptions,
+        fill: cellColor(dir),
+    }));
+
+    return (
+        <div>
+            <div className="flex gap-2 mb-4">
+                {DEPTHS.map(d => (
+                    <button
+                        key={d.value}
+                        onClick={() => setDepth(d.value)}
+                        className={`px-3 py-1 rounded-lg text-sm transition-colors ${depth === d.value
+                            ? 'bg-blue-500 text-white'
+                            : 'bg-slate-100 dark:bg-slate-800 text-slate-600 dark:text-slate-300 hover:bg-slate-200 dark:hover:bg-slate-700'}`}
+                    >
+                        {d.label}
+                    </button>
+                ))}
+            </div>
+            {isLoading ? (
+                <div className="h-72 flex items-center justify-center text-slate-500">Loading heatmap...</div>
+            ) : cells.length === 0 ? (
+                <div className="h-72 flex items-center justify-center text-slate-500">No corruptions recorded</div>
+            ) : (
+                <div className="h-72 w-full min-h-[250px]">
+                    <ResponsiveContainer width="100%" height="100%" minWidth={200} minHeight={200}>
+                        <Treemap data={cells} dataKey="size" nameKey="name" isAnimationActive={false} content={<HeatmapCell />}>
+                            <Tooltip
+                                contentStyle={{ backgroundColor: '#1e293b', borderColor: '#334155', color: '#f8fafc' }}
+                                itemStyle={{ color: '#f8fafc' }}
+                                formatter={(_value, _name, item) => {
+                                    const dir = item.payload as HeatmapDirectory;
+                                    return [`${dir.corruptions} corruptions, ${dir.unresolved} unresolved`, dir.directory];
+                                }}
+                            />
+                        </Treemap>
+                    </ResponsiveContainer>
+                </div>
+            )}
+        </div>
+    );
+};
+
+export default CorruptionHeatmap;
@@ -4,7 +4,7 @@ import { Server, Plus, Trash2, ChevronDown, Pencil, Save, Copy, Activity } from
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import {
     getArrInstances, createArrInstance, updateArrInstance, deleteArrInstance,
-    getAPIKey, testArrConnection,
+    getAPIKey, testArrConnection, getArrInstanceHealth,
     type ArrInstance
 } from '../../lib/api';
 import clsx from 'clsx';
@@ -13,10 +13,10 @@ import CollapsibleSection from './CollapsibleSection';
 import ConfirmDialog from '../ui/ConfirmDialog';
 
 // Server Status Component
-const ServerStatus = ({ url, apiKey, isManuallyTesting }: { url: string; apiKey: string; isManuallyTesting?: boolean }) => {
+const ServerStatus = ({ id, url, apiKey, isManuallyTesting }: { id: number; url: string; apiKey: string; isManuallyTesting?: boolean }) => {
     const { data, isLoading, isError, isFetching } = useQuery({
-        queryKey: ['serverStatus', url, apiKey],
-        queryFn: () => testArrConnection(url, apiKey),
+        queryKey: ['serverStatus', id, url, apiKey],
+        queryFn: () => testArrConnection(url, apiKey, id),
         retry: false,
         refetchInterval: 600000, // Check every 10 minutes
         refetchOnWindowFocus: true,
@@ -49,6 +49,52 @@ const ServerStatus = ({ url, apiKey, isManuallyTesting }: { url: string; apiKey:
     );
 };
 
+// Uptime over the last 24 hours from the periodic health checks
+const InstanceUptime = ({ id }: { id: number }) => {
+    const { data } = useQuery({
+        queryKey: ['arrHealth', id],
+        queryFn: () => getArrInstanceHealth(id),
+        refetchInterval: 300000,
+    });
+
+    if (!data || data.uptime_percent === null) {
+        return <span className="text-sm text-slate-500">—</span>;
+    }
+
+    const title = [
+        `Breaker: ${data.breaker.state}`,
+        `Avg response: ${Math.round(data.avg_response_ms)} ms`,
+        `p95 response: ${data.p95_response_ms} ms`,
+        `${data.samples} checks`,
+        data.latest?.error ? `Last error: ${data.latest.error}` : '',
+    ].filter(Boolean).join('\n');
+
+    return (
+        <div className="flex flex-col gap-1" title={title}>
+            <span className={clsx(
+                "text-sm font-medium",
+                data.status === 'healthy' ? "text-green-400" :
+                data.status === 'degraded' ? "text-yellow-400" :
+                data.status === 'down' ? "text-red-400" : "text-slate-500"
+            )}>
+                {data.uptime_percent.toFixed(1)}%
+            </span>
+            <div className="flex items-end gap-px h-3">
+                {data.periods.map((p) => (
+                    <div
+                        key={p.period}
+                        className={clsx(
+                            "w-1 h-full rounded-sm",
+                            p.uptime_percent >= 99 ? "bg-green-500/70" :
+                            p.uptime_percent >= 90 ? "bg-yellow-500/70" : "bg-red-500/70"
+                        )}
+                    />
+                ))}
+            </div>
+        </div>
+    );
+};
+
 const ArrServersSection = () => {
     const queryClient = useQueryClient();
     const toast = useToast();
@@ -112,6 +158,7 @@ const ArrServersSection = () => {
         mutationFn: deleteArrInstance,
         onSuccess: () => {
             queryClient.invalidateQueries({ queryKey: ['arrInstances'] });
+            queryClient.invalidateQueries({ queryKey: ['deletedConfig'] });
             toast.success('Server deleted');
             setDeleteConfirm({ isOpen: false, arr: null });
         },
@@ -127,7 +174,7 @@ const ArrServersSection = () => {
         }
         setIsTesting(true);
         try {
-            const result = await testArrConnection(newArr.url, newArr.api_key);
+            const result = await testArrConnection(newArr.url, newArr.api_key, editingId ?? undefined);
             setTestStatus({
                 success: result.success,
                 message: result.success ? 'Connection successful' : result.error
@@ -182,14 +229,15 @@ const ArrServersSection = () => {
             type: arr.type,
             url: arr.url,
             api_key: arr.api_key,
-            enabled: arr.enabled
+            enabled: arr.enabled,
+            daily_request_budget: arr.daily_request_budget ?? 0
         });
         setEditingId(arr.id);
         setIsAddExpanded(true);
     };
 
     const resetForm = () => {
-        setNewArr({ type: 'sonarr', enabled: true, name: '', url: '', api_key: '' });
+        setNewArr({ type: 'sonarr', enabled: true, name: '', url: '', api_key: '', daily_request_budget: 0 });
         setTestStatus({});
         setIsAddExpanded(false);
         setEditingId(null);
@@ -294,6 +342,17 @@ const ArrServersSection = () => {
                                             />
                                             <p className="mt-1 text-xs text-slate-500">Find in *arr Settings → General. Required for webhooks even if local auth is disabled.</p>
                                         </div>
+                                        <div>
+                                            <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">Daily API Budget</label>
+                                            <input
+                                                type="number"
+                                                min={0}
+                                                value={newArr.daily_request_budget ?? 0}
+                                                onChange={e => setNewArr({ ...newArr, daily_request_budget: Math.max(0, parseInt(e.target.value) || 0) })}
+                                                className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500"
+                                            />
+                                            <p className="mt-1 text-xs text-slate-500">Requests per day (UTC), 0 for unlimited. From 90% on, history polls and listing fallbacks wait for the next day.</p>
+                                        </div>
                                     </div>
                                     <div className="flex items-center gap-3 pb-2">
                                         <input
@@ -361,6 +420,7 @@ const ArrServersSection = () => {
                                         <th className="px-6 py-3 text-left text-xs font-medium text-slate-600 dark:text-slate-400 uppercase">URL</th>
                                         <th className="px-6 py-3 text-left text-xs font-medium text-slate-600 dark:text-slate-400 uppercase">Enabled</th>
                                         <th className="px-6 py-3 text-left text-xs font-medium text-slate-600 dark:text-slate-400 uppercase">Status</th>
+                                        <th className="px-6 py-3 text-left text-xs font-medium text-slate-600 dark:text-slate-400 uppercase">Uptime (24h)</th>
                                         <th className="px-6 py-3 text-left text-xs font-medium text-slate-600 dark:text-slate-400 uppercase cursor-help" title="Add this URL to Sonarr/Radarr's Connect settings to notify Healarr of new imports">Webhook URL</th>
                                         <th className="px-6 py-3"></th>
                                     </tr>
@@ -380,7 +440,20 @@ const ArrServersSection = () => {
                                                     {arr.type === 'whisparr-v2' ? 'Whisparr v2' : arr.type === 'whisparr-v3' ? 'Whisparr v3' : arr.type}
                                                 </span>
                                             </td>
-                                            <td className="px-6 py-4 text-slate-700 dark:text-slate-300">{arr.name}</td>
+                                            <td className="px-6 py-4 text-slate-700 dark:text-slate-300">
+                                                {arr.name}
+                                                {!!arr.daily_request_budget && (
+                                                    <div
+                                                        className={clsx(
+                                                            "text-xs",
+                                                            (arr.requests_today ?? 0) >= arr.daily_request_budget * 0.9 ? "text-yellow-400" : "text-slate-500"
+                                                        )}
+                                                        title={arr.deferred_today ? `${arr.deferred_today} non-urgent request(s) deferred today` : undefined}
+                                                    >
+                                                        {arr.requests_today ?? 0} / {arr.daily_request_budget} requests today
+                                                    </div>
+                                                )}
+                                            </td>
                                             <td className="px-6 py-4 text-xs text-slate-600 dark:text-slate-400 font-mono">{arr.url}</td>
                                             <td className="px-6 py-4">
                                                 {arr.enabled ? (
@@ -391,11 +464,15 @@ const ArrServersSection = () => {
                                             </td>
                                             <td className="px-6 py-4">
                                                 <ServerStatus
+                                                    id={arr.id}
                                                     url={arr.url}
                                                     apiKey={arr.api_key}
                                                     isManuallyTesting={manualTestingServer === `${arr.url}-${arr.api_key}`}
                                                 />
                                             </td>
+                                            <td className="px-6 py-4">
+                                                <InstanceUptime id={arr.id} />
+                                            </td>
                                             <td className="px-6 py-4">
                                                 <div className="relative group">
                                                     <input
@@ -453,7 +530,7 @@ const ArrServersSection = () => {
             <ConfirmDialog
                 isOpen={deleteConfirm.isOpen}
                 title="Delete Server"
-                message={`Are you sure you want to delete "${deleteConfirm.arr?.name}"? This action cannot be undone.`}
+                message={`Are you sure you want to delete "${deleteConfirm.arr?.name}"? It can be restored from Recently Deleted until it is purged.`}
                 confirmLabel="Delete"
                 variant="danger"
                 isLoading={deleteMutation.isPending}
@@ -0,0 +1,145 @@
+import { useState } from 'react';
+import { motion, AnimatePresence } from 'framer-motion';
+import { Upload, ChevronDown, Check, X, AlertTriangle } from 'lucide-react';
+import { useMutation, useQueryClient } from '@tanstack/react-query';
+import { bulkCreateScanPaths, type BulkScanPathResponse } from '../../lib/api';
+import clsx from 'clsx';
+import { useToast } from '../../contexts/ToastContext';
+
+const CSV_EXAMPLE = `local_path,arr_path,instance,enabled,auto_remediate
+/media/tv,/tv,Sonarr,true,true
+/media/movies,/movies,Radarr,true,false`;
+
+// Imports many scan paths at once from CSV or JSON, validating before creating
+const BulkPathImport = () => {
+    const queryClient = useQueryClient();
+    const toast = useToast();
+    const [isExpanded, setIsExpanded] = useState(false);
+    const [data, setData] = useState('');
+    const [result, setResult] = useState<BulkScanPathResponse | null>(null);
+
+    // JSON starts with an array; anything else is treated as CSV
+    const format = data.trimStart().startsWith('[') ? 'json' : 'csv';
+
+    const importMutation = useMutation({
+        mutationFn: (dryRun: boolean) => bulkCreateScanPaths(data, format, dryRun),
+        onSuccess: (resp) => {
+            setResult(resp);
+            if (!resp.dry_run && resp.created > 0) {
+                queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+                toast.success(`Imported ${resp.created} scan path${resp.created === 1 ? '' : 's'}`);
+            }
+        },
+        onError: (error: Error) => {
+            setResult(null);
+            toast.error(`Import failed: ${error.message}`);
+        },
+    });
+
+    const handleFile = async (file: File | undefined) => {
+        if (!file) return;
+        setData(await file.text());
+        setResult(null);
+    };
+
+    return (
+        <div className="rounded-xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl overflow-hidden">
+            <button
+                onClick={() => setIsExpanded(!isExpanded)}
+                className="w-full px-6 py-4 flex items-center justify-between hover:bg-slate-100 dark:hover:bg-slate-800/30 transition-colors cursor-pointer"
+            >
+                <div className="flex items-center gap-3">
+                    <Upload className="w-5 h-5 text-blue-400" />
+                    <h3 className="text-lg font-semibold text-slate-900 dark:text-white">Bulk Import</h3>
+                </div>
+                <ChevronDown className={clsx(
+                    "w-5 h-5 text-slate-600 dark:text-slate-400 transition-transform duration-200",
+                    isExpanded && "rotate-180"
+                )} />
+            </button>
+
+            <AnimatePresence>
+                {isExpanded && (
+                    <motion.div
+                        initial={{ height: 0, opacity: 0 }}
+                        animate={{ height: 'auto', opacity: 1 }}
+                        exit={{ height: 0, opacity: 0 }}
+                        className="border-t border-slate-200 dark:border-slate-800/50"
+                    >
+                        <div className="p-6 space-y-4">
+                            <p className="text-sm text-slate-600 dark:text-slate-400">
+                                Paste a CSV with a header row or a JSON array of scan paths. <code>instance</code> is the
+                                *arr instance name; columns left out use the same defaults as a single path.
+                            </p>
+                            <textarea
+                                value={data}
+                                onChange={(e) => { setData(e.target.value); setResult(null); }}
+                                placeholder={CSV_EXAMPLE}
+                                rows={8}
+                                className="w-full bg-white dark:bg-slate-900/50 border border-slate-300 dark:border-slate-700 rounded-lg px-3 py-2 text-sm text-slate-700 dark:text-slate-300 font-mono focus:outline-none focus:border-blue-500"
+                            />
+                            <div className="flex flex-wrap items-center gap-3">
+                                <label className="px-4 py-2 rounded-lg border border-slate-300 dark:border-slate-700 text-sm text-slate-700 dark:text-slate-300 hover:bg-slate-100 dark:hover:bg-slate-800/50 cursor-pointer">
+                                    Choose File
+                                    <input
+                                        type="file"
+                                        accept=".csv,.json,text/csv,application/json"
+                                        className="hidden"
+                                        onChange={(e) => handleFile(e.target.files?.[0])}
+                                    />
+                                </label>
+                                <button
+                                    onClick={() => importMutation.mutate(true)}
+                                    disabled={!data.trim() || importMutation.isPending}
+                                    className="px-4 py-2 rounded-lg border border-slate-300 dark:border-slate-700 text-sm text-slate-700 dark:text-slate-300 hover:bg-slate-100 dark:hover:bg-slate-800/50 disabled:opacity-50 cursor-pointer"
+                                >
+                                    Validate
+                                </button>
+                                <button
+                                    onClick={() => importMutation.mutate(false)}
+                                    disabled={!data.trim() || importMutation.isPending}
+                                    className="flex items-center gap-2 px-4 py-2 bg-blue-500 hover:bg-blue-600 text-white rounded-lg text-sm disabled:opacity-50 cursor-pointer"
+                                >
+                                    <Upload className="w-4 h-4" />
+                                    Import
+                                </button>
+                                <span className="text-xs text-slate-500 uppercase">{data.trim() ? format : ''}</span>
+                            </div>
+
+                            {result && (
+                                <div className="space-y-2">
+                                    <p className="text-sm text-slate-700 dark:text-slate-300">
+                                        {result.dry_run
+                                            ? `${result.results.length - result.failed} valid, ${result.failed} with errors`
+                                            : `${result.created} imported, ${result.failed} failed`}
+                                    </p>
+                                    <ul className="max-h-64 overflow-y-auto divide-y divide-slate-200 dark:divide-slate-800/50 text-sm">
+                                        {result.results.map((r) => (
+                                            <li key={r.row} className="py-2 flex items-start gap-2">
+                                                {r.status === 'failed' ? (
+                                                    <X className="w-4 h-4 mt-0.5 text-red-400 shrink-0" />
+                                                ) : r.warnings?.length ? (
+                                                    <AlertTriangle className="w-4 h-4 mt-0.5 text-yellow-400 shrink-0" />
+                                                ) : (
+                                                    <Check className="w-4 h-4 mt-0.5 text-green-400 shrink-0" />
+                                                )}
+                                                <div>
+                                                    <span className="text-slate-500 mr-2">#{r.row}</span>
+                                                    <span className="font-mono text-slate-700 dark:text-slate-300">{r.local_path || '(no path)'}</span>
+                                                    {r.error && <p className="text-red-400">{r.error}</p>}
+                                                    {r.warnings?.map((w) => <p key={w} className="text-yellow-400">{w}</p>)}
+                                                </div>
+                                            </li>
+                                        ))}
+                                    </ul>
+                                </div>
+                            )}
+                        </div>
+                    </motion.div>
+                )}
+            </AnimatePresence>
+        </div>
+    );
+};
+
+export default BulkPathImport;
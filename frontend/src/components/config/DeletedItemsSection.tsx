@@ -0,0 +1,114 @@
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { FolderOpen, RotateCcw, Server, Trash2 } from 'lucide-react';
+import { getDeletedConfig, restoreArrInstance, restoreScanPath } from '../../lib/api';
+import { formatDistanceToNow } from '../../lib/formatters';
+import { useToast } from '../../contexts/ToastContext';
+import CollapsibleSection from './CollapsibleSection';
+
+// SQLite timestamps are UTC without a zone suffix
+const toDate = (timestamp: string) => new Date(timestamp.replace(' ', 'T') + 'Z');
+
+const DeletedItemsSection = () => {
+    const queryClient = useQueryClient();
+    const toast = useToast();
+
+    const { data: deleted } = useQuery({
+        queryKey: ['deletedConfig'],
+        queryFn: getDeletedConfig,
+    });
+
+    const onRestoreError = (error: unknown) => {
+        const err = error as { response?: { data?: { error?: string } }; message?: string };
+        toast.error(`Failed to restore: ${err.response?.data?.error || err.message}`);
+    };
+
+    const restorePathMutation = useMutation({
+        mutationFn: restoreScanPath,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['deletedConfig'] });
+            queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+            queryClient.invalidateQueries({ queryKey: ['schedules'] });
+            toast.success('Scan path restored');
+        },
+        onError: onRestoreError,
+    });
+
+    const restoreArrMutation = useMutation({
+        mutationFn: restoreArrInstance,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['deletedConfig'] });
+            queryClient.invalidateQueries({ queryKey: ['arrInstances'] });
+            queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+            toast.success('Server restored');
+        },
+        onError: onRestoreError,
+    });
+
+    const total = (deleted?.scan_paths.length ?? 0) + (deleted?.arr_instances.length ?? 0);
+    if (total === 0) {
+        return null;
+    }
+
+    const purgeNote = (purgeAt?: string) =>
+        purgeAt ? `purged on ${toDate(purgeAt).toLocaleDateString()}` : 'kept until restored';
+
+    const restoreButton = (onClick: () => void, disabled: boolean, label: string) => (
+        <button
+            onClick={onClick}
+            disabled={disabled}
+            className="flex items-center gap-2 px-3 py-1.5 rounded-lg text-xs font-medium bg-green-500/10 text-green-400 border border-green-500/20 hover:bg-green-500/20 transition-colors cursor-pointer disabled:opacity-50"
+            aria-label={label}
+        >
+            <RotateCcw className="w-3 h-3" aria-hidden="true" />
+            Restore
+        </button>
+    );
+
+    return (
+        <CollapsibleSection
+            id="recently-deleted"
+            icon={Trash2}
+            iconColor="text-red-400"
+            title="Recently Deleted"
+            subtitle={deleted?.retention_days
+                ? `Deleted servers and scan paths are purged after ${deleted.retention_days} days`
+                : 'Deleted servers and scan paths are kept until restored'}
+            defaultExpanded={false}
+            delay={0.35}
+        >
+            <div className="rounded-xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl overflow-hidden divide-y divide-slate-800/50">
+                {deleted?.arr_instances.map(inst => (
+                    <div key={`arr-${inst.id}`} className="p-4 flex items-center justify-between hover:bg-slate-100 dark:hover:bg-slate-800/30 transition-colors">
+                        <div className="flex items-center gap-4">
+                            <Server className="w-4 h-4 text-slate-500" aria-hidden="true" />
+                            <div>
+                                <div className="font-medium text-slate-900 dark:text-white">{inst.name}</div>
+                                <div className="text-sm text-slate-600 dark:text-slate-400 mt-0.5">
+                                    {inst.url} · deleted {formatDistanceToNow(toDate(inst.deleted_at))} · {purgeNote(inst.purge_at)}
+                                    {inst.scan_paths > 0 && ` · ${inst.scan_paths} scan path(s) waiting for it`}
+                                </div>
+                            </div>
+                        </div>
+                        {restoreButton(() => restoreArrMutation.mutate(inst.id), restoreArrMutation.isPending, `Restore ${inst.name}`)}
+                    </div>
+                ))}
+                {deleted?.scan_paths.map(path => (
+                    <div key={`path-${path.id}`} className="p-4 flex items-center justify-between hover:bg-slate-100 dark:hover:bg-slate-800/30 transition-colors">
+                        <div className="flex items-center gap-4">
+                            <FolderOpen className="w-4 h-4 text-slate-500" aria-hidden="true" />
+                            <div>
+                                <div className="font-medium text-slate-900 dark:text-white font-mono">{path.local_path}</div>
+                                <div className="text-sm text-slate-600 dark:text-slate-400 mt-0.5">
+                                    {path.scans} scan(s), {path.corruptions} corruption(s) · deleted {formatDistanceToNow(toDate(path.deleted_at))} · {purgeNote(path.purge_at)}
+                                </div>
+                            </div>
+                        </div>
+                        {restoreButton(() => restorePathMutation.mutate(path.id), restorePathMutation.isPending, `Restore ${path.local_path}`)}
+                    </div>
+                ))}
+            </div>
+        </CollapsibleSection>
+    );
+};
+
+export default DeletedItemsSection;
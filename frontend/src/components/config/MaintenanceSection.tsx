@@ -0,0 +1,152 @@
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { Play, Square, Wrench } from 'lucide-react';
+import clsx from 'clsx';
+import { abortMaintenance, getMaintenanceStatus, triggerMaintenance, type MaintenanceRun } from '../../lib/api';
+import { formatCronExpression, formatDistanceToNow, formatDuration } from '../../lib/formatters';
+import { useToast } from '../../contexts/ToastContext';
+
+const stepLabels: Record<string, string> = {
+    purge_deleted: 'Purge deleted config',
+    prune_samples: 'Prune file samples',
+    prune: 'Prune history',
+    summary: 'Check corruption summary',
+    vacuum: 'Incremental vacuum',
+    analyze: 'Analyze',
+    checkpoint: 'WAL checkpoint',
+};
+
+const statusColors: Record<string, string> = {
+    pending: 'text-slate-500',
+    running: 'text-blue-400',
+    completed: 'text-green-400',
+    skipped: 'text-slate-400',
+    failed: 'text-red-400',
+    aborted: 'text-amber-400',
+};
+
+const RunSteps = ({ run }: { run: MaintenanceRun }) => (
+    <ul className="space-y-1 text-xs">
+        {run.steps.map(step => (
+            <li key={step.name} className="flex items-center justify-between gap-4">
+                <span className="text-slate-600 dark:text-slate-400">{stepLabels[step.name] ?? step.name}</span>
+                <span className={clsx('font-mono', statusColors[step.status])}>
+                    {step.status}
+                    {step.duration_ms > 0 && ` · ${formatDuration(step.duration_ms / 1000)}`}
+                    {step.reason && ` · ${step.reason}`}
+                </span>
+            </li>
+        ))}
+    </ul>
+);
+
+const MaintenanceSection = () => {
+    const queryClient = useQueryClient();
+    const toast = useToast();
+
+    const { data: status } = useQuery({
+        queryKey: ['maintenance'],
+        queryFn: getMaintenanceStatus,
+        refetchInterval: (query) => (query.state.data?.current ? 2000 : 60000),
+    });
+
+    const onError = (action: string) => (error: unknown) => {
+        const err = error as { response?: { data?: { error?: string } }; message?: string };
+        toast.error(`Failed to ${action} maintenance: ${err.response?.data?.error || err.message}`);
+    };
+
+    const triggerMutation = useMutation({
+        mutationFn: triggerMaintenance,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['maintenance'] });
+            toast.success('Database maintenance started');
+        },
+        onError: onError('start'),
+    });
+
+    const abortMutation = useMutation({
+        mutationFn: abortMaintenance,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['maintenance'] });
+            toast.success('Database maintenance aborted');
+        },
+        onError: onError('abort'),
+    });
+
+    const current = status?.current;
+    const last = status?.last_run;
+
+    return (
+        <div className="space-y-4">
+            <div className="flex items-center gap-3 mb-4">
+                <div className="p-2 rounded-lg bg-teal-500/10 border border-teal-500/20">
+                    <Wrench className="w-5 h-5 text-teal-400" />
+                </div>
+                <div>
+                    <h4 className="text-sm font-semibold text-slate-900 dark:text-white">Database Maintenance</h4>
+                    <p className="text-xs text-slate-500">
+                        {status?.schedule ? formatCronExpression(status.schedule) : 'Not scheduled'}
+                        {status?.next_run_at && ` · next run ${formatDistanceToNow(status.next_run_at)}`}
+                        {status?.peak_hours && ` · no vacuum during ${status.peak_hours}`}
+                    </p>
+                </div>
+            </div>
+
+            {current ? (
+                <div className="space-y-3 p-4 rounded-lg border border-blue-500/20 bg-blue-500/5">
+                    <div className="flex items-center justify-between text-sm">
+                        <span className="text-slate-900 dark:text-white">
+                            Running {stepLabels[current.current_step ?? ''] ?? current.current_step} · {formatDuration(current.duration_ms / 1000)}
+                        </span>
+                        <span className="text-slate-500">{current.progress}%</span>
+                    </div>
+                    <div className="h-1.5 rounded-full bg-slate-200 dark:bg-slate-800 overflow-hidden">
+                        <div className="h-full bg-blue-500 transition-all" style={{ width: `${current.progress}%` }} />
+                    </div>
+                    <RunSteps run={current} />
+                </div>
+            ) : last && (
+                <div className="space-y-2 p-4 rounded-lg border border-slate-200 dark:border-slate-800/50">
+                    <div className="text-sm text-slate-600 dark:text-slate-400">
+                        Last run ({last.trigger}) <span className={statusColors[last.status]}>{last.status}</span>
+                        {' '}{formatDistanceToNow(last.finished_at ?? last.started_at)} in {formatDuration(last.duration_ms / 1000)}
+                    </div>
+                    <RunSteps run={last} />
+                </div>
+            )}
+
+            <div className="flex items-center gap-3 flex-wrap">
+                {current ? (
+                    <button
+                        onClick={() => abortMutation.mutate()}
+                        disabled={abortMutation.isPending}
+                        className="flex items-center gap-2 px-4 py-2 bg-red-500/10 hover:bg-red-500/20 text-red-400 rounded-lg transition-colors border border-red-500/20 cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                    >
+                        <Square className="w-4 h-4" />
+                        Abort
+                    </button>
+                ) : (
+                    <>
+                        <button
+                            onClick={() => triggerMutation.mutate(false)}
+                            disabled={triggerMutation.isPending}
+                            className="flex items-center gap-2 px-4 py-2 bg-teal-500/10 hover:bg-teal-500/20 text-teal-400 rounded-lg transition-colors border border-teal-500/20 cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                        >
+                            <Play className="w-4 h-4" />
+                            Run Now
+                        </button>
+                        <button
+                            onClick={() => triggerMutation.mutate(true)}
+                            disabled={triggerMutation.isPending}
+                            className="flex items-center gap-2 px-4 py-2 bg-slate-500/10 hover:bg-slate-500/20 text-slate-400 rounded-lg transition-colors border border-slate-500/20 cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                        >
+                            <Play className="w-4 h-4" />
+                            Run Without Vacuum
+                        </button>
+                    </>
+                )}
+            </div>
+        </div>
+    );
+};
+
+export default MaintenanceSection;
@@ -0,0 +1,81 @@
+import { useState } from 'react';
+import { AlertTriangle, ArrowRight } from 'lucide-react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { getMappingDrift, remapScanPaths, type MappingDrift } from '../../lib/api';
+import { useToast } from '../../contexts/ToastContext';
+
+// Warns about scan paths whose *arr path no longer matches a root folder of
+// their instance, e.g. after the library moved, and remaps them in one go
+const MappingDriftBanner = () => {
+    const queryClient = useQueryClient();
+    const toast = useToast();
+    const [targets, setTargets] = useState<Record<number, string>>({});
+
+    const { data: drifts } = useQuery({
+        queryKey: ['mappingDrift'],
+        queryFn: getMappingDrift,
+        retry: false,
+        staleTime: 60_000,
+    });
+
+    const remapMutation = useMutation({
+        mutationFn: (d: MappingDrift) => remapScanPaths({
+            instance_id: d.instance_id,
+            from: d.arr_path,
+            to: targets[d.path_id] ?? d.suggested_arr_path ?? '',
+        }),
+        onSuccess: (resp) => {
+            queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+            queryClient.invalidateQueries({ queryKey: ['mappingDrift'] });
+            toast.success(`Remapped ${resp.remapped.length} scan path${resp.remapped.length === 1 ? '' : 's'}`);
+        },
+        onError: (error: Error) => {
+            toast.error(`Remap failed: ${error.message}`);
+        },
+    });
+
+    if (!drifts || drifts.length === 0) return null;
+
+    return (
+        <div className="rounded-xl border border-amber-500/30 bg-amber-500/10 p-6 space-y-4">
+            <div className="flex items-start gap-3">
+                <AlertTriangle className="w-5 h-5 text-amber-500 shrink-0 mt-0.5" />
+                <div>
+                    <h3 className="text-lg font-semibold text-slate-900 dark:text-white">Path Mapping Drift</h3>
+                    <p className="text-sm text-slate-600 dark:text-slate-400">
+                        These scan paths map to *arr paths outside the root folders of their instance, so their
+                        files can't be matched to *arr media. Remapping keeps each path's history.
+                    </p>
+                </div>
+            </div>
+            {drifts.map((d) => (
+                <div key={d.path_id} className="flex flex-wrap items-center gap-2 text-sm">
+                    <span className="font-mono text-slate-700 dark:text-slate-300">{d.local_path}</span>
+                    <span className="text-slate-500">({d.instance_name}:</span>
+                    <span className="font-mono text-slate-700 dark:text-slate-300">{d.arr_path}</span>
+                    <ArrowRight className="w-4 h-4 text-slate-500" />
+                    <select
+                        value={targets[d.path_id] ?? d.suggested_arr_path ?? ''}
+                        onChange={(e) => setTargets({ ...targets, [d.path_id]: e.target.value })}
+                        className="bg-white dark:bg-slate-900/50 border border-slate-300 dark:border-slate-700 rounded-lg px-2 py-1 font-mono text-slate-700 dark:text-slate-300 focus:outline-none focus:border-blue-500"
+                    >
+                        <option value="">Choose a root folder</option>
+                        {d.root_folders.map((root) => (
+                            <option key={root} value={root}>{root}</option>
+                        ))}
+                    </select>
+                    <span className="text-slate-500">)</span>
+                    <button
+                        onClick={() => remapMutation.mutate(d)}
+                        disabled={remapMutation.isPending || !(targets[d.path_id] ?? d.suggested_arr_path)}
+                        className="px-3 py-1 rounded-lg bg-amber-500/20 text-amber-600 dark:text-amber-400 hover:bg-amber-500/30 disabled:opacity-50 transition-colors cursor-pointer"
+                    >
+                        Remap
+                    </button>
+                </div>
+            ))}
+        </div>
+    );
+};
+
+export default MappingDriftBanner;
@@ -0,0 +1,277 @@
+import { useState } from 'react';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { FolderTree, Pause, Pencil, Play, Plus, Save, Search, Trash2, X } from 'lucide-react';
+import clsx from 'clsx';
+import {
+    createPathGroup, deletePathGroup, getPathGroups, getScanPaths, scanPathGroup, setPathGroupPaused, updatePathGroup,
+    type PathGroup, type PathGroupInput
+} from '../../lib/api';
+import { useToast } from '../../contexts/ToastContext';
+import ConfirmDialog from '../ui/ConfirmDialog';
+import CollapsibleSection from './CollapsibleSection';
+
+const inputClass = "w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500";
+
+const emptyGroup: PathGroupInput = { name: '', remediation_paused: false, path_ids: [] };
+
+const PathGroupsSection = () => {
+    const queryClient = useQueryClient();
+    const toast = useToast();
+
+    const [form, setForm] = useState<PathGroupInput | null>(null);
+    const [editingId, setEditingId] = useState<number | null>(null);
+    const [deleteConfirm, setDeleteConfirm] = useState<PathGroup | null>(null);
+
+    const { data: groups = [] } = useQuery({
+        queryKey: ['pathGroups'],
+        queryFn: getPathGroups,
+        refetchInterval: 30000,
+    });
+
+    const { data: scanPaths = [] } = useQuery({
+        queryKey: ['scanPaths'],
+        queryFn: getScanPaths,
+    });
+
+    const onError = (action: string) => (error: unknown) => {
+        const err = error as { response?: { data?: { error?: string } }; message?: string };
+        toast.error(`Failed to ${action}: ${err.response?.data?.error || err.message}`);
+    };
+
+    const closeForm = () => {
+        setForm(null);
+        setEditingId(null);
+    };
+
+    const saveMutation = useMutation({
+        mutationFn: (group: PathGroupInput) =>
+            editingId ? updatePathGroup(editingId, group) : createPathGroup(group),
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['pathGroups'] });
+            toast.success(editingId ? 'Path group updated' : 'Path group added');
+            closeForm();
+        },
+        onError: onError('save path group'),
+    });
+
+    const deleteMutation = useMutation({
+        mutationFn: deletePathGroup,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ['pathGroups'] });
+            toast.success('Path group deleted');
+            setDeleteConfirm(null);
+        },
+        onError: onError('delete path group'),
+    });
+
+    const pauseMutation = useMutation({
+        mutationFn: ({ id, paused }: { id: number; paused: boolean }) => setPathGroupPaused(id, paused),
+        onSuccess: (group) => {
+            queryClient.invalidateQueries({ queryKey: ['pathGroups'] });
+            toast.success(group.remediation_paused
+                ? `Remediation of ${group.name} paused`
+                : `Remediation of ${group.name} resumed`);
+        },
+        onError: onError('change group pause'),
+    });
+
+    const scanMutation = useMutation({
+        mutationFn: scanPathGroup,
+        onSuccess: (result) => toast.success(result.message),
+        onError: onError('start group scan'),
+    });
+
+    const pathLabel = (id: number) => scanPaths.find(p => p.id === id)?.local_path ?? `#${id}`;
+
+    const groupOf = (pathId: number) => groups.find(g => g.id !== editingId && g.path_ids.includes(pathId));
+
+    const togglePath = (pathId: number) => {
+        if (!form) return;
+        const path_ids = form.path_ids.includes(pathId)
+            ? form.path_ids.filter(id => id !== pathId)
+            : [...form.path_ids, pathId];
+        setForm({ ...form, path_ids });
+    };
+
+    const handleEdit = (group: PathGroup) => {
+        setForm({ name: group.name, remediation_paused: group.remediation_paused, path_ids: [...group.path_ids] });
+        setEditingId(group.id);
+    };
+
+    const handleSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        if (form) saveMutation.mutate(form);
+    };
+
+    return (
+        <CollapsibleSection
+            id="path-groups"
+            icon={FolderTree}
+            iconColor="text-indigo-400"
+            title="Path Groups"
+            subtitle="Scan, pause and report on sets of scan paths such as Movies, TV or Kids"
+            defaultExpanded={false}
+            delay={0.3}
+        >
+            <div className="space-y-4">
+                {!form && (
+                    <div className="flex justify-end">
+                        <button
+                            onClick={() => setForm({ ...emptyGroup, path_ids: [] })}
+                            className="flex items-center gap-2 px-4 py-2 bg-indigo-500/10 hover:bg-indigo-500/20 text-indigo-400 rounded-lg transition-colors border border-indigo-500/20 cursor-pointer"
+                        >
+                            <Plus className="w-4 h-4" />
+                            Add Group
+                        </button>
+                    </div>
+                )}
+
+                {form && (
+                    <form onSubmit={handleSubmit} className="space-y-4 p-4 rounded-lg border border-slate-200 dark:border-slate-800/50">
+                        <div>
+                            <label className="block text-sm font-medium text-slate-600 dark:text-slate-400 mb-2">Name</label>
+                            <input
+                                type="text"
+                                value={form.name}
+                                onChange={e => setForm({ ...form, name: e.target.value })}
+                                placeholder="Kids"
+                                className={inputClass}
+                                required
+                            />
+                        </div>
+                        <div>
+                            <label className="block text-sm font-medium text-slate-600 dark:text-slate-400 mb-2">Scan Paths</label>
+                            {scanPaths.length === 0 ? (
+                                <p className="text-xs text-slate-500">Add scan paths first.</p>
+                            ) : (
+                                <div className="space-y-2">
+                                    {scanPaths.map(path => {
+                                        const other = groupOf(path.id);
+                                        return (
+                                            <label key={path.id} className="flex items-center gap-3 text-sm text-slate-700 dark:text-slate-300 cursor-pointer">
+                                                <input
+                                                    type="checkbox"
+                                                    checked={form.path_ids.includes(path.id)}
+                                                    onChange={() => togglePath(path.id)}
+                                                    className="w-4 h-4 text-blue-500 bg-white dark:bg-slate-800 border-slate-300 dark:border-slate-700 rounded focus:ring-blue-500"
+                                                />
+                                                <span className="font-mono">{path.local_path}</span>
+                                                {other && !form.path_ids.includes(path.id) && (
+                                                    <span className="text-xs text-slate-500">in {other.name}</span>
+                                                )}
+                                            </label>
+                                        );
+                                    })}
+                                    <p className="text-xs text-slate-500">A scan path is in at most one group; selecting it here moves it.</p>
+                                </div>
+                            )}
+                        </div>
+                        <div className="flex items-center justify-end gap-3">
+                            <button
+                                type="button"
+                                onClick={closeForm}
+                                className="flex items-center gap-2 px-4 py-2 text-slate-600 dark:text-slate-400 hover:text-slate-900 dark:hover:text-white rounded-lg transition-colors cursor-pointer"
+                            >
+                                <X className="w-4 h-4" />
+                                Cancel
+                            </button>
+                            <button
+                                type="submit"
+                                disabled={saveMutation.isPending}
+                                className="flex items-center gap-2 px-4 py-2 bg-blue-500 hover:bg-blue-600 text-slate-900 dark:text-white rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                            >
+                                {editingId ? <Save className="w-4 h-4" /> : <Plus className="w-4 h-4" />}
+                                {editingId ? 'Update Group' : 'Add Group'}
+                            </button>
+                        </div>
+                    </form>
+                )}
+
+                {groups.length > 0 && (
+                    <ul className="divide-y divide-slate-200 dark:divide-slate-800/50 rounded-lg border border-slate-200 dark:border-slate-800/50">
+                        {groups.map(group => (
+                            <li key={group.id} className="flex items-center justify-between gap-4 p-4">
+                                <div className="min-w-0 space-y-1">
+                                    <div className="flex items-center gap-2 text-sm">
+                                        <span className="font-medium text-slate-900 dark:text-white">{group.name}</span>
+                                        {group.remediation_paused && (
+                                            <span className="px-2 py-0.5 rounded text-xs font-medium bg-amber-500/10 text-amber-400">Remediation paused</span>
+                                        )}
+                                    </div>
+                                    <div className="text-xs text-slate-500 truncate font-mono">
+                                        {group.path_ids.length > 0 ? group.path_ids.map(pathLabel).join(', ') : 'No scan paths'}
+                                    </div>
+                                    <div className="text-xs text-slate-500">
+                                        {group.stats.total_corruptions} corruption(s)
+                                        {' · '}{group.stats.pending_corruptions + group.stats.in_progress_corruptions} active
+                                        {' · '}{group.stats.resolved_corruptions} resolved
+                                        {group.stats.failed_corruptions > 0 && ` · ${group.stats.failed_corruptions} failed`}
+                                        {group.stats.total_corruptions > 0 && ` · ${group.stats.success_rate}% success`}
+                                    </div>
+                                </div>
+                                <div className="flex items-center gap-2 shrink-0">
+                                    <button
+                                        onClick={() => scanMutation.mutate(group.id)}
+                                        disabled={scanMutation.isPending || group.path_ids.length === 0}
+                                        className="p-2 hover:bg-green-500/10 text-green-400 rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                                        title="Scan Group"
+                                        aria-label={`Scan ${group.name}`}
+                                    >
+                                        <Search className="w-4 h-4" aria-hidden="true" />
+                                    </button>
+                                    <button
+                                        onClick={() => pauseMutation.mutate({ id: group.id, paused: !group.remediation_paused })}
+                                        disabled={pauseMutation.isPending}
+                                        className={clsx(
+                                            "p-2 rounded-lg transition-colors cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed",
+                                            group.remediation_paused ? "hover:bg-green-500/10 text-green-400" : "hover:bg-amber-500/10 text-amber-400"
+                                        )}
+                                        title={group.remediation_paused ? 'Resume Remediation' : 'Pause Remediation'}
+                                        aria-label={`${group.remediation_paused ? 'Resume' : 'Pause'} remediation of ${group.name}`}
+                                    >
+                                        {group.remediation_paused
+                                            ? <Play className="w-4 h-4" aria-hidden="true" />
+                                            : <Pause className="w-4 h-4" aria-hidden="true" />}
+                                    </button>
+                                    <button
+                                        onClick={() => handleEdit(group)}
+                                        className="p-2 hover:bg-blue-500/10 text-blue-400 rounded-lg transition-colors cursor-pointer"
+                                        title="Edit"
+                                        aria-label={`Edit ${group.name}`}
+                                    >
+                                        <Pencil className="w-4 h-4" aria-hidden="true" />
+                                    </button>
+                                    <button
+                                        onClick={() => setDeleteConfirm(group)}
+                                        className="p-2 hover:bg-red-500/10 text-red-400 rounded-lg transition-colors cursor-pointer"
+                                        title="Delete"
+                                        aria-label={`Delete ${group.name}`}
+                                    >
+                                        <Trash2 className="w-4 h-4" aria-hidden="true" />
+                                    </button>
+                                </div>
+                            </li>
+                        ))}
+                    </ul>
+                )}
+            </div>
+
+            <ConfirmDialog
+                isOpen={deleteConfirm !== null}
+                title="Delete Path Group"
+                message={`Are you sure you want to delete "${deleteConfirm?.name}"? Its scan paths are kept, without a group.`}
+                confirmLabel="Delete"
+                variant="danger"
+                isLoading={deleteMutation.isPending}
+                onConfirm={() => {
+                    if (deleteConfirm) {
+                        deleteMutation.mutate(deleteConfirm.id);
+                    }
+                }}
+                onCancel={() => setDeleteConfirm(null)}
+            />
+        </CollapsibleSection>
+    );
+};
+
+export default PathGroupsSection;
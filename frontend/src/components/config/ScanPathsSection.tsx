@@ -5,13 +5,15 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import {
     getArrInstances, getScanPaths, createScanPath, updateScanPath, deleteScanPath,
     triggerScan, getDetectionPreview, validateScanPath, getSystemInfo,
-    type ScanPath
+    type ScanPath, type QualityPinMode, type ProtocolPreference, type DurationCheck, type SymlinkPolicy
 } from '../../lib/api';
 import clsx from 'clsx';
 import { useToast } from '../../contexts/ToastContext';
 import CollapsibleSection from './CollapsibleSection';
 import FileBrowser from '../ui/FileBrowser';
 import ConfirmDialog from '../ui/ConfirmDialog';
+import BulkPathImport from './BulkPathImport';
+import MappingDriftBanner from './MappingDriftBanner';
 
 // Path Validation Status Component
 const PathValidationStatus = ({ pathId }: { pathId: number }) => {
@@ -81,7 +83,21 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
         detection_method: 'ffprobe',
         detection_mode: 'quick',
         max_retries: 3,
-        verification_timeout_hours: null
+        verification_timeout_hours: null,
+        quality_pin: 'off',
+        priority: 0,
+        research_interval_days: 0,
+        research_period_days: 90,
+        protocol_preference: 'any',
+        remote_url: '',
+        remote_requests_per_minute: 60,
+        media_extensions: '',
+        min_file_size_mb: 0,
+        duration_check: 'off',
+        symlink_policy: 'skip',
+        rollout_daily_limit: 0,
+        rollout_days: 7,
+        min_confidence: 0
     });
 
     // Delete confirmation state
@@ -151,6 +167,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
         mutationFn: deleteScanPath,
         onSuccess: () => {
             queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+            queryClient.invalidateQueries({ queryKey: ['deletedConfig'] });
             toast.success('Scan path deleted');
             setDeleteConfirm({ isOpen: false, path: null });
         },
@@ -173,7 +190,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
 
     const handleSubmit = (e: React.FormEvent) => {
         e.preventDefault();
-        if (!newPath.local_path || !newPath.arr_instance_id) {
+        if (!newPath.local_path || (!newPath.arr_instance_id && !newPath.arr_instance_tag?.trim())) {
             return;
         }
 
@@ -213,13 +230,31 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
             local_path: path.local_path,
             arr_path: path.arr_path,
             arr_instance_id: path.arr_instance_id,
+            arr_instance_tag: path.arr_instance_tag || '',
             enabled: path.enabled,
             auto_remediate: path.auto_remediate,
             detection_method: path.detection_method || 'ffprobe',
             detection_mode: path.detection_mode || 'quick',
             detection_args: detectionArgsStr,
             max_retries: path.max_retries ?? 3,
-            verification_timeout_hours: path.verification_timeout_hours ?? null
+            verification_timeout_hours: path.verification_timeout_hours ?? null,
+            quality_pin: path.quality_pin || 'off',
+            priority: path.priority ?? 0,
+            research_interval_days: path.research_interval_days ?? 0,
+            research_period_days: path.research_period_days ?? 90,
+            protocol_preference: path.protocol_preference || 'any',
+            remote_url: path.remote_url || '',
+            remote_username: path.remote_username || '',
+            remote_password: path.remote_password || '',
+            remote_host_key_fingerprint: path.remote_host_key_fingerprint || '',
+            remote_requests_per_minute: path.remote_requests_per_minute ?? 60,
+            media_extensions: path.media_extensions || '',
+            min_file_size_mb: path.min_file_size_mb ?? 0,
+            duration_check: path.duration_check || 'off',
+            symlink_policy: path.symlink_policy || 'skip',
+            rollout_daily_limit: path.rollout_daily_limit ?? 0,
+            rollout_days: path.rollout_days ?? 7,
+            min_confidence: path.min_confidence ?? 0
         });
         setEditingId(path.id);
         setIsAddExpanded(true);
@@ -232,10 +267,25 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
             local_path: '',
             arr_path: '',
             arr_instance_id: null,
+            arr_instance_tag: '',
             detection_method: 'ffprobe',
             detection_mode: 'quick',
             max_retries: 3,
-            verification_timeout_hours: null
+            verification_timeout_hours: null,
+            quality_pin: 'off',
+            priority: 0,
+            research_interval_days: 0,
+            research_period_days: 90,
+            protocol_preference: 'any',
+            remote_url: '',
+            remote_requests_per_minute: 60,
+            media_extensions: '',
+            min_file_size_mb: 0,
+            duration_check: 'off',
+            symlink_policy: 'skip',
+            rollout_daily_limit: 0,
+            rollout_days: 7,
+            min_confidence: 0
         });
         setIsAddExpanded(false);
         setEditingId(null);
@@ -332,7 +382,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                 value={newPath.arr_instance_id || ''}
                                                 onChange={e => setNewPath({ ...newPath, arr_instance_id: e.target.value ? parseInt(e.target.value) : null })}
                                                 className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
-                                                required
+                                                required={!newPath.arr_instance_tag?.trim()}
                                             >
                                                 <option value="">Select a server...</option>
                                                 {arrInstances?.map(arr => (
@@ -340,6 +390,17 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                 ))}
                                             </select>
                                         </div>
+                                        <div>
+                                            <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">*arr Tag (optional)</label>
+                                            <input
+                                                type="text"
+                                                value={newPath.arr_instance_tag || ''}
+                                                onChange={e => setNewPath({ ...newPath, arr_instance_tag: e.target.value })}
+                                                placeholder="e.g. uhd"
+                                                className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500"
+                                            />
+                                            <p className="mt-1 text-xs text-slate-500">Route to whichever server has this tag, even after it is re-created with a new ID</p>
+                                        </div>
                                     </div>
                                     <div className="flex items-center gap-6 pb-2">
                                         <div className="flex items-center gap-3">
@@ -399,6 +460,257 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                         </p>
                                     </div>
 
+                                    {/* Quality Pin */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-quality-pin" className="text-sm text-slate-700 dark:text-slate-300">Quality Pin:</label>
+                                        <select
+                                            id="path-quality-pin"
+                                            value={newPath.quality_pin || 'off'}
+                                            onChange={e => setNewPath({ ...newPath, quality_pin: e.target.value as QualityPinMode })}
+                                            className="w-48 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                        >
+                                            <option value="off">Off</option>
+                                            <option value="flag">Flag lower quality</option>
+                                            <option value="search">Keep searching</option>
+                                        </select>
+                                        <p className="text-xs text-slate-500">
+                                            Compare replacements with the original file's resolution. Lower-quality replacements are flagged, or searched again.
+                                        </p>
+                                    </div>
+
+                                    {/* Download Protocol */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-protocol" className="text-sm text-slate-700 dark:text-slate-300">Download Protocol:</label>
+                                        <select
+                                            id="path-protocol"
+                                            value={newPath.protocol_preference || 'any'}
+                                            onChange={e => setNewPath({ ...newPath, protocol_preference: e.target.value as ProtocolPreference })}
+                                            className="w-48 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                        >
+                                            <option value="any">Any</option>
+                                            <option value="usenet">Usenet only</option>
+                                            <option value="torrent">Torrents only</option>
+                                        </select>
+                                        <p className="text-xs text-slate-500">
+                                            Replacement grabs of the other protocol are removed from the *arr queue and blocklisted, so the *arr picks another release.
+                                        </p>
+                                    </div>
+
+                                    {/* Remote Library */}
+                                    <div className="space-y-2 pb-2">
+                                        <div className="flex items-center gap-4">
+                                            <label htmlFor="path-remote-url" className="text-sm text-slate-700 dark:text-slate-300">Remote URL:</label>
+                                            <input
+                                                type="text"
+                                                id="path-remote-url"
+                                                value={newPath.remote_url || ''}
+                                                onChange={e => setNewPath({ ...newPath, remote_url: e.target.value })}
+                                                placeholder="Leave empty for a local path"
+                                                className="flex-1 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500"
+                                            />
+                                        </div>
+                                        <p className="text-xs text-slate-500">
+                                            Scan a library that is only reachable over WebDAV (https://host/path) or SFTP (sftp://host/path), e.g. on a seedbox. Only file headers are checked; the local path is the path the *arr reports.
+                                        </p>
+                                        {newPath.remote_url && (
+                                            <div className="grid grid-cols-2 gap-4">
+                                                <input
+                                                    type="text"
+                                                    aria-label="Remote username"
+                                                    value={newPath.remote_username || ''}
+                                                    onChange={e => setNewPath({ ...newPath, remote_username: e.target.value })}
+                                                    placeholder="Username"
+                                                    className="px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500"
+                                                />
+                                                <input
+                                                    type="password"
+                                                    aria-label="Remote password"
+                                                    autoComplete="new-password"
+                                                    value={newPath.remote_password || ''}
+                                                    onChange={e => setNewPath({ ...newPath, remote_password: e.target.value })}
+                                                    placeholder="Password"
+                                                    className="px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 focus:ring-2 focus:ring-blue-500"
+                                                />
+                                                {newPath.remote_url.startsWith('sftp://') && (
+                                                    <input
+                                                        type="text"
+                                                        aria-label="SFTP host key fingerprint"
+                                                        value={newPath.remote_host_key_fingerprint || ''}
+                                                        onChange={e => setNewPath({ ...newPath, remote_host_key_fingerprint: e.target.value })}
+                                                        placeholder="Host key, e.g. SHA256:... (ssh-keygen -lf)"
+                                                        className="col-span-2 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 font-mono text-sm focus:ring-2 focus:ring-blue-500"
+                                                    />
+                                                )}
+                                                <div className="col-span-2 flex items-center gap-4">
+                                                    <label htmlFor="path-remote-rate" className="text-sm text-slate-700 dark:text-slate-300">Requests per Minute:</label>
+                                                    <input
+                                                        type="number"
+                                                        id="path-remote-rate"
+                                                        min="1"
+                                                        max="600"
+                                                        value={newPath.remote_requests_per_minute ?? 60}
+                                                        onChange={e => setNewPath({ ...newPath, remote_requests_per_minute: Math.min(600, Math.max(1, parseInt(e.target.value) || 60)) })}
+                                                        className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                                    />
+                                                    <p className="text-xs text-slate-500">
+                                                        Every listing and header read is a request. Paths on the same server share the strictest limit.
+                                                    </p>
+                                                </div>
+                                            </div>
+                                        )}
+                                    </div>
+
+                                    {/* Media Filters */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-media-extensions" className="text-sm text-slate-700 dark:text-slate-300">Media Extensions:</label>
+                                        <input
+                                            type="text"
+                                            id="path-media-extensions"
+                                            value={newPath.media_extensions || ''}
+                                            onChange={e => setNewPath({ ...newPath, media_extensions: e.target.value })}
+                                            placeholder="Built-in list"
+                                            className="w-48 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white placeholder-slate-500 font-mono text-sm focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <p className="text-xs text-slate-500">
+                                            Only scan files with these extensions, e.g. .mkv,.mp4. Leave empty to scan all common video and audio formats.
+                                        </p>
+                                    </div>
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-min-file-size" className="text-sm text-slate-700 dark:text-slate-300">Minimum Size (MB):</label>
+                                        <input
+                                            type="number"
+                                            id="path-min-file-size"
+                                            min="0"
+                                            value={newPath.min_file_size_mb ?? 0}
+                                            onChange={e => setNewPath({ ...newPath, min_file_size_mb: Math.max(0, parseInt(e.target.value) || 0) })}
+                                            className="w-24 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <p className="text-xs text-slate-500">
+                                            Files smaller than this are reported as Undersized even when they play, e.g. a 1080p movie under 200 MB. 0 turns it off.
+                                        </p>
+                                    </div>
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-duration-check" className="text-sm text-slate-700 dark:text-slate-300">Duration Check:</label>
+                                        <select
+                                            id="path-duration-check"
+                                            value={newPath.duration_check || 'off'}
+                                            onChange={e => setNewPath({ ...newPath, duration_check: e.target.value as DurationCheck })}
+                                            className="w-48 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                        >
+                                            <option value="off">Off</option>
+                                            <option value="flag">Flag mismatches</option>
+                                            <option value="remediate">Remediate mismatches</option>
+                                        </select>
+                                        <p className="text-xs text-slate-500">
+                                            Compare each video's duration with the runtime the *arr expects. Files running less than half or more than twice as long, e.g. 20 minutes for a 2-hour movie, are reported as Duration Mismatch.
+                                        </p>
+                                    </div>
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-symlink-policy" className="text-sm text-slate-700 dark:text-slate-300">Symlinks:</label>
+                                        <select
+                                            id="path-symlink-policy"
+                                            value={newPath.symlink_policy || 'skip'}
+                                            onChange={e => setNewPath({ ...newPath, symlink_policy: e.target.value as SymlinkPolicy })}
+                                            className="w-48 px-3 py-1.5 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                        >
+                                            <option value="skip">Skip</option>
+                                            <option value="follow">Follow</option>
+                                            <option value="verify_target_once">Verify target once</option>
+                                        </select>
+                                        <p className="text-xs text-slate-500">
+                                            Skip ignores symbolic links. Follow scans linked files and folders wherever they point. Verify target once checks linked files inside this path once, through the file they point to. Files reached twice, e.g. through a link loop, are scanned once.
+                                        </p>
+                                    </div>
+
+                                    {/* Staged Rollout */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-rollout-limit" className="text-sm text-slate-700 dark:text-slate-300">Staged Rollout:</label>
+                                        <input
+                                            type="number"
+                                            id="path-rollout-limit"
+                                            min="0"
+                                            value={newPath.rollout_daily_limit ?? 0}
+                                            onChange={e => setNewPath({ ...newPath, rollout_daily_limit: Math.max(0, parseInt(e.target.value) || 0) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <span className="text-sm text-slate-500">per day for the first</span>
+                                        <input
+                                            type="number"
+                                            id="path-rollout-days"
+                                            aria-label="Break-in period in days"
+                                            min="0"
+                                            value={newPath.rollout_days ?? 7}
+                                            onChange={e => setNewPath({ ...newPath, rollout_days: Math.max(0, parseInt(e.target.value) || 0) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <span className="text-sm text-slate-500">days</span>
+                                        <p className="text-xs text-slate-500">
+                                            For a large new library: after the path is added, at most this many remediations start per day and the rest wait for the next day, e.g. 25 per day for the first week. 0 turns it off.
+                                        </p>
+                                    </div>
+
+                                    {/* Minimum Detection Confidence */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-min-confidence" className="text-sm text-slate-700 dark:text-slate-300">Min. Confidence:</label>
+                                        <input
+                                            type="number"
+                                            id="path-min-confidence"
+                                            min="0"
+                                            max="100"
+                                            value={newPath.min_confidence ?? 0}
+                                            onChange={e => setNewPath({ ...newPath, min_confidence: Math.min(100, Math.max(0, parseInt(e.target.value) || 0)) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <span className="text-sm text-slate-500">%</span>
+                                        <p className="text-xs text-slate-500">
+                                            Each corruption is scored by how sure the detection is. Findings below this score aren't remediated but wait in Needs Attention for you to retry or ignore them. Above 0, other detectors cross-check every finding. 0 remediates everything.
+                                        </p>
+                                    </div>
+
+                                    {/* Scan Priority */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-priority" className="text-sm text-slate-700 dark:text-slate-300">Scan Priority:</label>
+                                        <input
+                                            type="number"
+                                            id="path-priority"
+                                            min="0"
+                                            max="100"
+                                            value={newPath.priority ?? 0}
+                                            onChange={e => setNewPath({ ...newPath, priority: Math.min(100, Math.max(0, parseInt(e.target.value) || 0)) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <p className="text-xs text-slate-500">
+                                            When scheduled scans fire together, paths with a higher priority (0-100) are scanned first.
+                                        </p>
+                                    </div>
+
+                                    {/* Re-search Schedule */}
+                                    <div className="flex items-center gap-4 pb-2">
+                                        <label htmlFor="path-research-interval" className="text-sm text-slate-700 dark:text-slate-300">Search Again Every:</label>
+                                        <input
+                                            type="number"
+                                            id="path-research-interval"
+                                            min="0"
+                                            max="365"
+                                            value={newPath.research_interval_days ?? 0}
+                                            onChange={e => setNewPath({ ...newPath, research_interval_days: Math.min(365, Math.max(0, parseInt(e.target.value) || 0)) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <label htmlFor="path-research-period" className="text-sm text-slate-700 dark:text-slate-300">days, for</label>
+                                        <input
+                                            type="number"
+                                            id="path-research-period"
+                                            min="1"
+                                            max="3650"
+                                            value={newPath.research_period_days ?? 90}
+                                            onChange={e => setNewPath({ ...newPath, research_period_days: Math.min(3650, Math.max(1, parseInt(e.target.value) || 90)) })}
+                                            className="w-20 px-3 py-1 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white text-center focus:ring-2 focus:ring-blue-500"
+                                        />
+                                        <p className="text-xs text-slate-500">
+                                            days. When no replacement is found, keep searching on this schedule so rare content is replaced once it shows up. 0 turns it off.
+                                        </p>
+                                    </div>
+
                                     {/* Detection Configuration */}
                                     <div className="space-y-4 pt-4 border-t border-slate-200 dark:border-slate-800">
                                         <div>
@@ -506,7 +818,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                         name="detection-mode"
                                                         value="quick"
                                                         checked={(newPath.detection_mode || 'quick') === 'quick'}
-                                                        onChange={e => setNewPath({ ...newPath, detection_mode: e.target.value as 'quick' | 'thorough' })}
+                                                        onChange={e => setNewPath({ ...newPath, detection_mode: e.target.value as 'quick' | 'thorough' | 'sampled' })}
                                                         className="w-4 h-4 text-blue-500 bg-white dark:bg-slate-800 border-slate-300 dark:border-slate-700 focus:ring-blue-500"
                                                     />
                                                     <label htmlFor="mode-quick" className="text-sm text-slate-700 dark:text-slate-300 cursor-pointer">Quick - Header check</label>
@@ -518,16 +830,30 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                         name="detection-mode"
                                                         value="thorough"
                                                         checked={newPath.detection_mode === 'thorough'}
-                                                        onChange={e => setNewPath({ ...newPath, detection_mode: e.target.value as 'quick' | 'thorough' })}
+                                                        onChange={e => setNewPath({ ...newPath, detection_mode: e.target.value as 'quick' | 'thorough' | 'sampled' })}
                                                         className="w-4 h-4 text-blue-500 bg-white dark:bg-slate-800 border-slate-300 dark:border-slate-700 focus:ring-blue-500"
                                                     />
                                                     <label htmlFor="mode-thorough" className="text-sm text-slate-700 dark:text-slate-300 cursor-pointer">Thorough - Full file decode (slow)</label>
                                                 </div>
+                                                <div className="flex items-center gap-2">
+                                                    <input
+                                                        type="radio"
+                                                        id="mode-sampled"
+                                                        name="detection-mode"
+                                                        value="sampled"
+                                                        checked={newPath.detection_mode === 'sampled'}
+                                                        onChange={e => setNewPath({ ...newPath, detection_mode: e.target.value as 'quick' | 'thorough' | 'sampled' })}
+                                                        className="w-4 h-4 text-blue-500 bg-white dark:bg-slate-800 border-slate-300 dark:border-slate-700 focus:ring-blue-500"
+                                                    />
+                                                    <label htmlFor="mode-sampled" className="text-sm text-slate-700 dark:text-slate-300 cursor-pointer">Sampled - Decode segments (large files)</label>
+                                                </div>
                                             </div>
                                             <p className="mt-2 text-xs text-slate-500">
                                                 <span className="font-semibold">Quick:</span> Checks file headers and stream info. Fast, catches most issues.
                                                 <br />
                                                 <span className="font-semibold">Thorough:</span> Decodes the entire file to find mid-file corruption. Much slower.
+                                                <br />
+                                                <span className="font-semibold">Sampled:</span> Decodes short segments spread across the file. Built for very large remuxes.
                                             </p>
                                         </div>
 
@@ -599,6 +925,10 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                     </AnimatePresence>
                 </div>
 
+                <MappingDriftBanner />
+
+                <BulkPathImport />
+
                 {/* Paths List */}
                 <div className="rounded-xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl overflow-hidden">
                     {isLoading ? (
@@ -624,6 +954,38 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                 <div className="flex items-center gap-3">
                                                     <span className="text-slate-700 dark:text-slate-300 font-mono text-sm">{path.local_path}</span>
                                                     <PathValidationStatus pathId={path.id} />
+                                                    {path.capability && !path.capability.scannable && (
+                                                        <span
+                                                            className="text-xs bg-red-500/10 text-red-400 px-2 py-1 rounded-full border border-red-500/20"
+                                                            title={`Scans are skipped until these tools are installed: ${path.capability.missing_tools.join(', ')}`}
+                                                        >
+                                                            Unscannable
+                                                        </span>
+                                                    )}
+                                                    {path.remote_capabilities && (
+                                                        <span
+                                                            className="text-xs bg-sky-500/10 text-sky-400 px-2 py-1 rounded-full border border-sky-500/20"
+                                                            title={`Remote library (${path.remote_url}). Limitations:\n- ${path.remote_capabilities.limitations.join('\n- ')}`}
+                                                        >
+                                                            {path.remote_capabilities.protocol === 'sftp' ? 'SFTP' : 'WebDAV'} · headers only
+                                                        </span>
+                                                    )}
+                                                    {path.rollout_ends_at && new Date(path.rollout_ends_at) > new Date() && (
+                                                        <span
+                                                            className="text-xs bg-violet-500/10 text-violet-400 px-2 py-1 rounded-full border border-violet-500/20"
+                                                            title={`At most ${path.rollout_daily_limit} remediations per day until ${new Date(path.rollout_ends_at).toLocaleString()}`}
+                                                        >
+                                                            Rollout · {path.rollout_daily_limit}/day
+                                                        </span>
+                                                    )}
+                                                    {path.capability?.degraded && (
+                                                        <span
+                                                            className="text-xs bg-amber-500/10 text-amber-400 px-2 py-1 rounded-full border border-amber-500/20"
+                                                            title={`Using a fallback detector; missing: ${path.capability.missing_tools.join(', ')}`}
+                                                        >
+                                                            Fallback
+                                                        </span>
+                                                    )}
                                                 </div>
                                             </td>
                                             <td className="px-6 py-4 text-slate-600 dark:text-slate-400 font-mono text-sm">
@@ -673,7 +1035,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
                                                         className="text-green-400 hover:text-green-300 disabled:opacity-50 disabled:cursor-not-allowed cursor-pointer"
                                                         title="Scan Now"
                                                         aria-label="Scan now"
-                                                        disabled={!path.enabled || scanMutation.isPending}
+                                                        disabled={!path.enabled || path.capability?.scannable === false || scanMutation.isPending}
                                                     >
                                                         <Play className="w-4 h-4" aria-hidden="true" />
                                                     </button>
@@ -728,7 +1090,7 @@ const ScanPathsSection = ({ onScrollToDetectionTools }: ScanPathsSectionProps) =
             <ConfirmDialog
                 isOpen={deleteConfirm.isOpen}
                 title="Remove Scan Path"
-                message={`Remove scan path "${deleteConfirm.path?.local_path}"?\n\nThis will remove the path from Healarr scanning only. No files will be deleted from your disk.`}
+                message={`Remove scan path "${deleteConfirm.path?.local_path}"?\n\nThis will remove the path from Healarr scanning only. No files will be deleted from your disk, and the path can be restored from Recently Deleted until it is purged.`}
                 confirmLabel="Remove"
                 variant="danger"
                 isLoading={deleteMutation.isPending}
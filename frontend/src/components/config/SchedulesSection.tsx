@@ -1,28 +1,66 @@
 import { useState } from 'react';
 import { motion, AnimatePresence } from 'framer-motion';
-import { Clock, Plus, Trash2, ChevronDown } from 'lucide-react';
+import { Clock, Plus, Trash2, ChevronDown, Globe } from 'lucide-react';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import {
-    getScanPaths, getSchedules, addSchedule, updateSchedule, deleteSchedule
+    getScanPaths, getSchedules, addSchedule, updateSchedule, deleteSchedule,
+    type ScheduleTiming, type DSTGapPolicy, type DSTOverlapPolicy
 } from '../../lib/api';
-import { formatCronExpression } from '../../lib/formatters';
+import { formatCronExpression, formatDistanceToNow } from '../../lib/formatters';
+import { useDateFormat } from '../../lib/useDateFormat';
 import clsx from 'clsx';
 import { useToast } from '../../contexts/ToastContext';
 import CollapsibleSection from './CollapsibleSection';
 import CronTimeBuilder from './CronTimeBuilder';
 import ConfirmDialog from '../ui/ConfirmDialog';
 
+const defaultTiming: ScheduleTiming = { timezone: '', dst_gap: 'run', dst_overlap: 'once' };
+
+const selectClass = "w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-purple-500";
+
+// Timezone the cron expression is read in and the daylight saving time policies
+const TimingFields = ({ value, onChange }: { value: ScheduleTiming; onChange: (timing: Partial<ScheduleTiming>) => void }) => (
+    <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+        <div>
+            <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">Timezone</label>
+            <select value={value.timezone} onChange={e => onChange({ timezone: e.target.value })} className={selectClass}>
+                <option value="">Server time</option>
+                {Intl.supportedValuesOf('timeZone').map(tz => (
+                    <option key={tz} value={tz}>{tz}</option>
+                ))}
+            </select>
+        </div>
+        <div>
+            <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">When Clocks Go Forward</label>
+            <select value={value.dst_gap} onChange={e => onChange({ dst_gap: e.target.value as DSTGapPolicy })} className={selectClass}>
+                <option value="run">Run skipped times after the jump</option>
+                <option value="skip">Skip times that don't exist</option>
+            </select>
+        </div>
+        <div>
+            <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">When Clocks Go Back</label>
+            <select value={value.dst_overlap} onChange={e => onChange({ dst_overlap: e.target.value as DSTOverlapPolicy })} className={selectClass}>
+                <option value="once">Run repeated times once</option>
+                <option value="twice">Run repeated times twice</option>
+            </select>
+        </div>
+    </div>
+);
+
 const SchedulesSection = () => {
     const queryClient = useQueryClient();
     const toast = useToast();
+    const { formatCompact, timezone: displayTimezone } = useDateFormat();
 
     // Local state
     const [isAddExpanded, setIsAddExpanded] = useState(false);
-    const [newSchedule, setNewSchedule] = useState<{ scan_path_id: number; cron_expression: string }>({
+    const [newSchedule, setNewSchedule] = useState<{ scan_path_id: number; cron_expression: string } & ScheduleTiming>({
         scan_path_id: 0,
-        cron_expression: '0 3 * * *'
+        cron_expression: '0 3 * * *',
+        ...defaultTiming
     });
     const [schedulePreset, setSchedulePreset] = useState('daily');
+    const [editingTiming, setEditingTiming] = useState<number | null>(null);
 
     // Delete confirmation state
     const [deleteConfirm, setDeleteConfirm] = useState<{ isOpen: boolean; scheduleId: number | null }>({
@@ -54,7 +92,7 @@ const SchedulesSection = () => {
     });
 
     const updateMutation = useMutation({
-        mutationFn: ({ id, schedule }: { id: number; schedule: { cron_expression?: string; enabled?: boolean } }) =>
+        mutationFn: ({ id, schedule }: { id: number; schedule: { cron_expression?: string; enabled?: boolean } & Partial<ScheduleTiming> }) =>
             updateSchedule(id, schedule),
         onSuccess: () => {
             queryClient.invalidateQueries({ queryKey: ['schedules'] });
@@ -105,7 +143,7 @@ const SchedulesSection = () => {
         e.preventDefault();
         if (newSchedule.scan_path_id && newSchedule.cron_expression) {
             addMutation.mutate(newSchedule);
-            setNewSchedule({ scan_path_id: 0, cron_expression: '0 3 * * *' });
+            setNewSchedule({ scan_path_id: 0, cron_expression: '0 3 * * *', ...defaultTiming });
             setIsAddExpanded(false);
         }
     };
@@ -197,6 +235,10 @@ const SchedulesSection = () => {
                                             )}
                                         </div>
                                     </div>
+                                    <TimingFields
+                                        value={newSchedule}
+                                        onChange={timing => setNewSchedule({ ...newSchedule, ...timing })}
+                                    />
                                     <button
                                         type="submit"
                                         className="flex items-center gap-2 px-4 py-2 bg-purple-500 hover:bg-purple-600 text-slate-900 dark:text-white rounded-lg transition-colors cursor-pointer"
@@ -221,45 +263,74 @@ const SchedulesSection = () => {
                             {schedules?.map(schedule => {
                                 const path = scanPaths?.find(p => p.id === schedule.scan_path_id);
                                 return (
-                                    <div key={schedule.id} className="p-4 flex items-center justify-between hover:bg-slate-100 dark:hover:bg-slate-800/30 transition-colors">
-                                        <div className="flex items-center gap-4">
-                                            <div className={clsx(
-                                                "w-2 h-2 rounded-full",
-                                                schedule.enabled ? "bg-green-500 shadow-[0_0_8px_rgba(34,197,94,0.5)]" : "bg-slate-600"
-                                            )} />
-                                            <div>
-                                                <div className="font-medium text-slate-900 dark:text-white flex items-center gap-2">
-                                                    {path?.local_path || `Path ID: ${schedule.scan_path_id}`}
-                                                    {!path && <span className="text-xs text-red-400">(Path not found)</span>}
-                                                </div>
-                                                <div className="text-sm text-slate-600 dark:text-slate-400 mt-0.5 flex items-center gap-2">
-                                                    <Clock className="w-3 h-3" />
-                                                    <span>{formatCronExpression(schedule.cron_expression)}</span>
-                                                    <span className="text-xs font-mono text-slate-500">({schedule.cron_expression})</span>
+                                    <div key={schedule.id} className="hover:bg-slate-100 dark:hover:bg-slate-800/30 transition-colors">
+                                        <div className="p-4 flex items-center justify-between">
+                                            <div className="flex items-center gap-4">
+                                                <div className={clsx(
+                                                    "w-2 h-2 rounded-full",
+                                                    schedule.enabled ? "bg-green-500 shadow-[0_0_8px_rgba(34,197,94,0.5)]" : "bg-slate-600"
+                                                )} />
+                                                <div>
+                                                    <div className="font-medium text-slate-900 dark:text-white flex items-center gap-2">
+                                                        {path?.local_path || `Path ID: ${schedule.scan_path_id}`}
+                                                        {!path && <span className="text-xs text-red-400">(Path not found)</span>}
+                                                    </div>
+                                                    <div className="text-sm text-slate-600 dark:text-slate-400 mt-0.5 flex items-center gap-2">
+                                                        <Clock className="w-3 h-3" />
+                                                        <span>{formatCronExpression(schedule.cron_expression)}</span>
+                                                        <span className="text-xs font-mono text-slate-500">({schedule.cron_expression})</span>
+                                                        {schedule.timezone && <span className="text-xs text-slate-500">{schedule.timezone}</span>}
+                                                    </div>
+                                                    {schedule.next_run_at && (
+                                                        <div className="text-xs text-slate-500 mt-0.5" title={displayTimezone || undefined}>
+                                                            Next run {formatCompact(schedule.next_run_at)} ({formatDistanceToNow(schedule.next_run_at)})
+                                                        </div>
+                                                    )}
                                                 </div>
                                             </div>
+                                            <div className="flex items-center gap-2">
+                                                <button
+                                                    onClick={() => setEditingTiming(editingTiming === schedule.id ? null : schedule.id)}
+                                                    className={clsx(
+                                                        "p-2 rounded-lg transition-colors cursor-pointer",
+                                                        editingTiming === schedule.id
+                                                            ? "text-purple-400 bg-purple-500/10"
+                                                            : "text-slate-600 dark:text-slate-400 hover:text-purple-400 hover:bg-purple-500/10"
+                                                    )}
+                                                    title="Timezone and daylight saving time"
+                                                    aria-label="Edit timezone and daylight saving time"
+                                                >
+                                                    <Globe className="w-4 h-4" aria-hidden="true" />
+                                                </button>
+                                                <button
+                                                    onClick={() => handleToggle(schedule)}
+                                                    className={clsx(
+                                                        "px-3 py-1.5 rounded-lg text-xs font-medium transition-colors border cursor-pointer",
+                                                        schedule.enabled
+                                                            ? "bg-green-500/10 text-green-400 border-green-500/20 hover:bg-green-500/20"
+                                                            : "bg-slate-200 dark:bg-slate-800 text-slate-600 dark:text-slate-400 border-slate-300 dark:border-slate-700 hover:bg-slate-300 dark:hover:bg-slate-700 hover:text-slate-900 dark:hover:text-white"
+                                                    )}
+                                                >
+                                                    {schedule.enabled ? 'Enabled' : 'Disabled'}
+                                                </button>
+                                                <button
+                                                    onClick={() => setDeleteConfirm({ isOpen: true, scheduleId: schedule.id })}
+                                                    className="p-2 text-slate-600 dark:text-slate-400 hover:text-red-400 hover:bg-red-500/10 rounded-lg transition-colors cursor-pointer"
+                                                    title="Delete Schedule"
+                                                    aria-label="Delete schedule"
+                                                >
+                                                    <Trash2 className="w-4 h-4" aria-hidden="true" />
+                                                </button>
+                                            </div>
                                         </div>
-                                        <div className="flex items-center gap-2">
-                                            <button
-                                                onClick={() => handleToggle(schedule)}
-                                                className={clsx(
-                                                    "px-3 py-1.5 rounded-lg text-xs font-medium transition-colors border cursor-pointer",
-                                                    schedule.enabled
-                                                        ? "bg-green-500/10 text-green-400 border-green-500/20 hover:bg-green-500/20"
-                                                        : "bg-slate-200 dark:bg-slate-800 text-slate-600 dark:text-slate-400 border-slate-300 dark:border-slate-700 hover:bg-slate-300 dark:hover:bg-slate-700 hover:text-slate-900 dark:hover:text-white"
-                                                )}
-                                            >
-                                                {schedule.enabled ? 'Enabled' : 'Disabled'}
-                                            </button>
-                                            <button
-                                                onClick={() => setDeleteConfirm({ isOpen: true, scheduleId: schedule.id })}
-                                                className="p-2 text-slate-600 dark:text-slate-400 hover:text-red-400 hover:bg-red-500/10 rounded-lg transition-colors cursor-pointer"
-                                                title="Delete Schedule"
-                                                aria-label="Delete schedule"
-                                            >
-                                                <Trash2 className="w-4 h-4" aria-hidden="true" />
-                                            </button>
-                                        </div>
+                                        {editingTiming === schedule.id && (
+                                            <div className="px-4 pb-4">
+                                                <TimingFields
+                                                    value={schedule}
+                                                    onChange={timing => updateMutation.mutate({ id: schedule.id, schedule: { ...timing, enabled: schedule.enabled } })}
+                                                />
+                                            </div>
+                                        )}
                                     </div>
                                 );
                             })}
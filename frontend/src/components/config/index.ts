@@ -5,4 +5,8 @@ export { default as CronTimeBuilder } from './CronTimeBuilder';
 // Section components
 export { default as ArrServersSection } from './ArrServersSection';
 export { default as ScanPathsSection } from './ScanPathsSection';
+export { default as PathGroupsSection } from './PathGroupsSection';
 export { default as SchedulesSection } from './SchedulesSection';
+export { default as DeletedItemsSection } from './DeletedItemsSection';
+export { default as MaintenanceSection } from './MaintenanceSection';
+export { default as BackupTargetsSection } from './BackupTargetsSection';
@@ -1,6 +1,7 @@
 
 import { Outlet } from 'react-router-dom';
 import Sidebar from './Sidebar';
+import SystemPauseBanner from '../SystemPauseBanner';
 
 const Layout = () => {
     return (
@@ -15,6 +16,7 @@ const Layout = () => {
 
             <main className="ml-64 min-h-screen relative z-10">
                 <div className="max-w-7xl mx-auto p-8">
+                    <SystemPauseBanner />
                     <Outlet />
                 </div>
             </main>
@@ -1,5 +1,6 @@
 /* eslint-disable react-refresh/only-export-components -- context provider exports both component and hook */
 import { createContext, useContext, useEffect, useState, type ReactNode } from 'react';
+import { savePreferences } from '../lib/preferences';
 
 type Theme = 'dark' | 'light';
 
@@ -30,8 +31,17 @@ export const ThemeProvider = ({ children }: { children: ReactNode }) => {
         localStorage.setItem('healarr_theme', theme);
     }, [theme]);
 
+    // Theme saved in another browser, applied when preferences are synced
+    useEffect(() => {
+        const handleChange = (e: CustomEvent<Theme>) => setTheme(e.detail);
+        window.addEventListener('themeChange', handleChange as EventListener);
+        return () => window.removeEventListener('themeChange', handleChange as EventListener);
+    }, []);
+
     const toggleTheme = () => {
-        setTheme(prev => prev === 'dark' ? 'light' : 'dark');
+        const next = theme === 'dark' ? 'light' : 'dark';
+        setTheme(next);
+        savePreferences({ theme: next });
     };
 
     return (
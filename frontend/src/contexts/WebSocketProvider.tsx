@@ -3,10 +3,22 @@ import React, { createContext, useContext, useEffect, useState, useRef, useCallb
 import { useQueryClient } from '@tanstack/react-query';
 import { getWebSocketUrl } from '../lib/basePath';
 
+// Values of the dashboard's real-time graphs, pushed on the "metrics" topic
+export interface LiveMetrics {
+    timestamp: number; // Unix milliseconds
+    active_scans?: number;
+    scan_throughput?: number; // files per second
+    active_remediations?: number;
+    queue_depth?: number;
+}
+
 interface WebSocketContextType {
     isConnected: boolean;
     lastMessage: unknown;
     reconnect: () => void;
+    liveMetrics: LiveMetrics | null;
+    // Subscribes to the live metrics; returns the unsubscribe function
+    subscribeLiveMetrics: () => () => void;
 }
 
 export const WebSocketContext = createContext<WebSocketContextType | undefined>(undefined);
@@ -19,9 +31,18 @@ export const useWebSocket = () => {
     return context;
 };
 
+// useLiveMetrics subscribes to the live metrics while the component is mounted
+export const useLiveMetrics = () => {
+    const { liveMetrics, subscribeLiveMetrics } = useWebSocket();
+    useEffect(() => subscribeLiveMetrics(), [subscribeLiveMetrics]);
+    return liveMetrics;
+};
+
 export const WebSocketProvider = ({ children }: { children: React.ReactNode }) => {
     const [isConnected, setIsConnected] = useState(false);
     const [lastMessage, setLastMessage] = useState<unknown>(null);
+    const [liveMetrics, setLiveMetrics] = useState<LiveMetrics | null>(null);
+    const metricsSubscribersRef = useRef(0);
     const wsRef = useRef<WebSocket | null>(null);
     const retryCountRef = useRef(0);
     const queryClient = useQueryClient();
@@ -66,6 +87,10 @@ export const WebSocketProvider = ({ children }: { children: React.ReactNode }) =
             console.log('WebSocket Connected');
             setIsConnected(true);
             retryCountRef.current = 0; // Reset backoff on successful connection
+            // Subscriptions don't survive a reconnect
+            if (metricsSubscribersRef.current > 0) {
+                ws.send(JSON.stringify({ type: 'subscribe', topic: 'metrics' }));
+            }
         };
 
         ws.onclose = () => {
@@ -92,6 +117,13 @@ export const WebSocketProvider = ({ children }: { children: React.ReactNode }) =
             try {
                 const rawMessage = JSON.parse(event.data);
 
+                // Live metrics carry only the values that changed, unless marked full
+                if (rawMessage.type === 'metrics') {
+                    const data = rawMessage.data as LiveMetrics & { full?: boolean };
+                    setLiveMetrics(prev => (data.full || !prev ? data : { ...prev, ...data }));
+                    return;
+                }
+
                 // Transform event messages to use event_type as the type
                 // Backend sends: {"type": "event", "data": {"event_type": "ScanProgress", "event_data": {...}}}
                 // Transform to: {"type": "ScanProgress", "data": {...event_data fields...}}
@@ -117,25 +149,44 @@ export const WebSocketProvider = ({ children }: { children: React.ReactNode }) =
                     queryClient.invalidateQueries({ queryKey: ['dashboardStats'] });
                 }
 
+                // Skipped scans (e.g. missing detection tools) - refresh warnings
+                if (eventType === 'ScanSkipped') {
+                    queryClient.invalidateQueries({ queryKey: ['systemStatus'] });
+                    queryClient.invalidateQueries({ queryKey: ['scanPaths'] });
+                }
+
+                // Global automation pause - refresh the pause banner and queue
+                if (eventType === 'AutomationPaused' || eventType === 'AutomationResumed') {
+                    queryClient.invalidateQueries({ queryKey: ['systemPause'] });
+                    queryClient.invalidateQueries({ queryKey: ['systemStatus'] });
+                    queryClient.invalidateQueries({ queryKey: ['remediationQueue'] });
+                }
+
                 // Corruption lifecycle events - refresh corruption list and stats
                 // These are all the events that can change a corruption's status
                 const corruptionEvents = [
                     'CorruptionDetected',
                     'CorruptionIgnored',
                     'RemediationQueued',
-                    'DeletionStarted', 'DeletionCompleted', 'DeletionFailed',
-                    'SearchStarted', 'SearchCompleted', 'SearchFailed', 'SearchExhausted',
-                    'FileDetected',
-                    'VerificationStarted', 'VerificationSuccess', 'VerificationFailed',
-                    'DownloadTimeout', 'DownloadProgress', 'DownloadFailed',
+                    'DeletionStarted', 'DeletionCompleted', 'DeletionFailed', 'DeletionPlanChanged',
+                    'SearchStarted', 'SearchCompleted', 'SearchFailed', 'SearchExhausted', 'ScheduledResearch',
+                    'FileDetected', 'ReverificationRequested',
+                    'VerificationStarted', 'VerificationSuccess', 'VerificationFailed', 'QualityRegression', 'AudioTrackMissing',
+                    'DownloadTimeout', 'DownloadProgress', 'DownloadFailed', 'DownloadRejected',
                     'ImportBlocked', 'ManuallyRemoved', 'DownloadIgnored',
+                    'RemediationDeferred', 'BudgetApprovalRequired', 'LowConfidenceDetection',
                     'RetryScheduled', 'MaxRetriesReached',
-                    'StuckRemediation',
+                    'StuckRemediation', 'SLABreached',
                     'NotificationSent', 'NotificationFailed'
                 ];
                 if (corruptionEvents.includes(eventType)) {
                     queryClient.invalidateQueries({ queryKey: ['corruptions'] });
                     queryClient.invalidateQueries({ queryKey: ['dashboardStats'] });
+                    // Keep an open Remediation Journey live (e.g. download progress bar)
+                    const aggregateId = rawMessage.data?.aggregate_id;
+                    if (aggregateId) {
+                        queryClient.invalidateQueries({ queryKey: ['history', aggregateId] });
+                    }
                 }
 
             } catch (e) {
@@ -168,8 +219,27 @@ export const WebSocketProvider = ({ children }: { children: React.ReactNode }) =
         };
     }, []);
 
+    const subscribeLiveMetrics = useCallback(() => {
+        const send = (type: 'subscribe' | 'unsubscribe') => {
+            if (wsRef.current?.readyState === WebSocket.OPEN) {
+                wsRef.current.send(JSON.stringify({ type, topic: 'metrics' }));
+            }
+        };
+        metricsSubscribersRef.current++;
+        if (metricsSubscribersRef.current === 1) {
+            send('subscribe');
+        }
+        return () => {
+            metricsSubscribersRef.current--;
+            if (metricsSubscribersRef.current === 0) {
+                send('unsubscribe');
+                setLiveMetrics(null);
+            }
+        };
+    }, []);
+
     return (
-        <WebSocketContext.Provider value={{ isConnected, lastMessage, reconnect: connect }}>
+        <WebSocketContext.Provider value={{ isConnected, lastMessage, reconnect: connect, liveMetrics, subscribeLiveMetrics }}>
             {children}
         </WebSocketContext.Provider>
     );
@@ -48,12 +48,20 @@ export const getCorruptions = async (
     sortBy = 'detected_at',
     sortOrder = 'desc',
     statusFilter = 'all',
-    pathId?: number
+    pathId?: number,
+    tags?: string[],
+    savedFilterId?: number
 ): Promise<PaginatedResponse<Corruption>> => {
     const params: Record<string, string | number> = { page, limit, sort_by: sortBy, sort_order: sortOrder, status: statusFilter };
     if (pathId !== undefined) {
         params.path_id = pathId;
     }
+    if (tags && tags.length > 0) {
+        params.tag = tags.join(',');
+    }
+    if (savedFilterId !== undefined) {
+        params.filter = savedFilterId;
+    }
     const { data } = await api.get<PaginatedResponse<Corruption>>('/corruptions', { params });
     return data;
 };
@@ -90,6 +98,7 @@ export interface ScanDetails {
     corrupt_files: number;
     skipped_files: number;
     inaccessible_files: number;
+    error_files: number;
 }
 
 export const getScanDetails = async (scanId: number): Promise<ScanDetails> => {
@@ -105,6 +114,8 @@ export interface ScanFile {
     error_details: string;
     file_size: number;
     scanned_at: string;
+    duration_ms: number | null;  // How long the health check took, null if none ran
+    detection_tool: string;      // Tool whose verdict was used, e.g. a fallback
 }
 
 export const getScanFiles = async (
@@ -119,6 +130,29 @@ export const getScanFiles = async (
     return data;
 };
 
+export interface ScanCompareFile {
+    file_path: string;
+    status: ScanFile['status'];                 // in scan b (scan a for removed files)
+    corruption_type?: string;
+    previous_status?: ScanFile['status'];       // missing for files new in scan b
+    previous_corruption_type?: string;
+}
+
+export interface ScanComparison {
+    a: { id: number; path: string; started_at: string; completed_at: string; files_scanned: number };
+    b: { id: number; path: string; started_at: string; completed_at: string; files_scanned: number };
+    newly_corrupted: ScanCompareFile[];
+    newly_healthy: ScanCompareFile[];
+    still_corrupted: ScanCompareFile[];
+    removed: ScanCompareFile[];
+}
+
+// Compares scan b with scan a, or with the previous completed scan of its path
+export const compareScans = async (b: number, a?: number): Promise<ScanComparison> => {
+    const { data } = await api.get<ScanComparison>('/scans/compare', { params: { a, b } });
+    return data;
+};
+
 export const triggerScan = async (path_id: number) => {
     const response = await api.post('/scan', { path_id });
     return response.data;
@@ -178,6 +212,340 @@ export const getCorruptionHistory = async (id: string): Promise<CorruptionHistor
     return data;
 };
 
+// Corruptions of the same movie/series, season or download (e.g. a season pack)
+export type CorruptionRelation = 'media' | 'season' | 'download';
+
+export interface RelatedCorruption {
+    corruption_id: string;
+    file_path: string;
+    state: string;
+    active: boolean;  // still being remediated
+    relations: CorruptionRelation[];
+    detected_at: string;
+    last_updated_at: string;
+}
+
+export interface RelatedCorruptions {
+    corruption_id: string;
+    related: RelatedCorruption[];
+    counts: Record<CorruptionRelation, number>;
+    active: Record<CorruptionRelation, number>;
+}
+
+export const getRelatedCorruptions = async (id: string): Promise<RelatedCorruptions> => {
+    const { data } = await api.get<RelatedCorruptions>(`/corruptions/${id}/related`);
+    return data;
+};
+
+// All corruptions of one movie/series across time (GET /api/media/:instance/:media_id/history)
+export type MediaHistoryOutcome = 'replaced' | 'failed' | 'manual_action' | 'ignored' | 'in_progress';
+
+export interface MediaHistory {
+    arr_instance_id: number;
+    media_id: number;
+    media_title?: string;
+    media_year?: number;
+    summary: {
+        total_corruptions: number;
+        times_replaced: number;
+        failed: number;
+        manual_action: number;
+        in_progress: number;
+        ignored: number;
+        first_detected_at?: string;
+        last_detected_at?: string;
+    };
+    corruptions: {
+        corruption_id: string;
+        file_path: string;
+        corruption_type?: string;
+        state: string;
+        outcome: MediaHistoryOutcome;
+        retry_count: number;
+        detected_at: string;
+        last_updated_at: string;
+    }[];
+}
+
+export const getMediaHistory = async (arrInstanceId: number, mediaId: number): Promise<MediaHistory> => {
+    const { data } = await api.get<MediaHistory>(`/media/${arrInstanceId}/${mediaId}/history`);
+    return data;
+};
+
+export interface ToolRun {
+    tool: string;
+    args: string[];
+    exit_code: number;  // -1 when the tool did not exit normally
+    duration_ms: number;
+    stdout?: string;
+    stderr?: string;
+    error?: string;
+}
+
+export type DeletionCause =
+    | 'permission_denied'
+    | 'read_only'
+    | 'file_locked'
+    | 'file_missing'
+    | 'mount_lost'
+    | 'arr_error'
+    | 'arr_unreachable'
+    | 'not_in_arr'
+    | 'unknown';
+
+export interface DeletionForensics {
+    cause: DeletionCause;
+    retryable: boolean;
+    error: string;
+    arr_status?: number;
+    arr_response?: string;
+    file: {
+        exists: boolean;
+        size?: number;
+        mode?: string;
+        mod_time?: string;
+        error?: string;
+    };
+    mount: {
+        dir: string;
+        accessible: boolean;
+        writable: boolean;
+        read_only?: boolean;
+        error?: string;
+    };
+    captured_at: string;
+}
+
+export interface CorruptionDiagnostics {
+    id: number;
+    file_path: string;
+    created_at: string;
+    error_type: string;
+    message: string;
+    runs: ToolRun[];
+    deletion?: DeletionForensics;  // set on entries recorded for a failed deletion
+}
+
+export const getCorruptionDiagnostics = async (id: string): Promise<CorruptionDiagnostics[]> => {
+    const { data } = await api.get<{ corruption_id: string; diagnostics: CorruptionDiagnostics[] }>(`/corruptions/${id}/diagnostics`);
+    return data.diagnostics;
+};
+
+// A few MB of a corrupt file around its first error, for sharing in bug reports
+export interface CorruptionSample {
+    corruption_id: string;
+    file_path: string;
+    file_name: string;
+    method: 'ffmpeg' | 'bytes';  // remuxed clip or raw byte range
+    offset_seconds?: number;
+    offset_bytes?: number;
+    size_bytes: number;
+    created_at: string;
+}
+
+// Extract a sample of the corrupt file, replacing an earlier one; the file must still exist
+export const extractCorruptionSample = async (id: string): Promise<CorruptionSample> => {
+    const { data } = await api.post<CorruptionSample>(`/corruptions/${id}/sample`);
+    return data;
+};
+
+// Download the sample of a corruption, extracting one first if it has none
+export const downloadCorruptionSample = async (id: string): Promise<void> => {
+    let sample: CorruptionSample;
+    try {
+        ({ data: sample } = await api.get<CorruptionSample>(`/corruptions/${id}/sample`));
+    } catch (error) {
+        if (!axios.isAxiosError(error) || error.response?.status !== 404) {
+            throw error;
+        }
+        sample = await extractCorruptionSample(id);
+    }
+    const response = await api.get(`/corruptions/${id}/sample/download`, {
+        responseType: 'blob',
+    });
+
+    const url = URL.createObjectURL(new Blob([response.data]));
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = sample.file_name;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    URL.revokeObjectURL(url);
+};
+
+// --- Deletion plans ---
+
+export interface ArrFileRecord {
+    id: number;
+    path: string;
+    size: number;
+}
+
+// What the latest remediation of a corruption planned to delete. The deletion
+// only goes ahead if the *arr instance's files still match the plan.
+export interface DeletionPlan {
+    action: 'delete_via_arr' | 'delete_untracked' | 'quarantine_untracked' | 'none';
+    instance_id: number;
+    media_id: number;
+    arr_files: ArrFileRecord[];
+    disk_paths: string[];
+    dry_run: boolean;
+    planned_at: string;
+}
+
+// Fails with 404 until a remediation planned a deletion
+export const getDeletionPlan = async (id: string): Promise<DeletionPlan> => {
+    const { data } = await api.get<DeletionPlan>(`/corruptions/${id}/deletion-plan`);
+    return data;
+};
+
+// --- Corruption lifecycle ---
+
+export interface LifecycleTransition {
+    from: string;
+    to: string;
+}
+
+// The state machine corruption events are validated against. Events in
+// any_state may follow every state, as may events carrying an override key.
+export interface CorruptionLifecycle {
+    states: string[];
+    initial: string;
+    terminal: string[];
+    transitions: LifecycleTransition[];
+    any_state: string[];
+    override_keys: string[];
+    diagram: string;  // Mermaid stateDiagram-v2
+}
+
+export const getCorruptionLifecycle = async (): Promise<CorruptionLifecycle> => {
+    const { data } = await api.get<CorruptionLifecycle>('/corruptions/lifecycle');
+    return data;
+};
+
+// --- Quality pinning ---
+
+// off: accept any replacement; flag: report a lower resolution as QualityRegression;
+// search: as flag, and retry the search
+export type QualityPinMode = 'off' | 'flag' | 'search';
+
+export type ProtocolPreference = 'any' | 'usenet' | 'torrent';
+
+// off: durations aren't compared; flag: report a mismatch with the *arr's runtime
+// as DurationMismatch without remediating; remediate: follow auto-remediate
+export type DurationCheck = 'off' | 'flag' | 'remediate';
+
+// skip: symlinks are ignored; follow: linked files and directories are scanned
+// wherever they point; verify_target_once: linked files inside the scan path
+// are checked once, through their target
+export type SymlinkPolicy = 'skip' | 'follow' | 'verify_target_once';
+
+export interface QualityPin {
+    mode: QualityPinMode;
+    source: 'path' | 'corruption';
+}
+
+export const getQualityPin = async (id: string): Promise<QualityPin> => {
+    const { data } = await api.get<QualityPin>(`/corruptions/${id}/quality-pin`);
+    return data;
+};
+
+export const setQualityPin = async (id: string, mode: QualityPinMode): Promise<QualityPin> => {
+    const { data } = await api.put<QualityPin>(`/corruptions/${id}/quality-pin`, { mode });
+    return data;
+};
+
+// Removes the corruption's override so its scan path's setting applies again
+export const clearQualityPin = async (id: string): Promise<QualityPin> => {
+    const { data } = await api.delete<QualityPin>(`/corruptions/${id}/quality-pin`);
+    return data;
+};
+
+// --- Interactive search ---
+
+export interface Release {
+    guid: string;
+    indexer_id: number;
+    indexer: string;
+    title: string;
+    quality: string;
+    size: number;
+    age_hours: number;
+    protocol: 'usenet' | 'torrent' | string;
+    seeders?: number;
+    leechers?: number;
+    custom_format_score: number;
+    approved: boolean;          // Meets the instance's quality profile
+    download_allowed: boolean;
+    rejections: string[];
+}
+
+// Runs an interactive search on the *arr instance; can take as long as its indexers do
+export const getCorruptionReleases = async (id: string): Promise<Release[]> => {
+    const { data } = await api.get<Release[]>(`/corruptions/${id}/releases`);
+    return data;
+};
+
+// The download is then tracked and verified like one from an automatic search
+export const grabCorruptionRelease = async (id: string, release: Pick<Release, 'guid' | 'indexer_id'>): Promise<Release> => {
+    const { data } = await api.post<Release>(`/corruptions/${id}/releases/grab`, {
+        guid: release.guid,
+        indexer_id: release.indexer_id,
+    });
+    return data;
+};
+
+// Re-opens a resolved corruption; the outcome is added to its history
+export const reverifyCorruption = async (id: string): Promise<{ message: string; reverification_of: number; file_paths: string[] }> => {
+    const { data } = await api.post(`/corruptions/${id}/reverify`);
+    return data;
+};
+
+// Download a zip with versions, config, tool availability, DB stats, circuit breakers,
+// recent errors and the log tail for issue reports (secrets redacted)
+export const downloadSystemDiagnostics = async (): Promise<void> => {
+    const response = await api.get('/system/diagnostics', {
+        responseType: 'blob',
+    });
+
+    const contentDisposition = response.headers['content-disposition'];
+    let filename = 'healarr_diagnostics.zip';
+    if (contentDisposition) {
+        const filenameMatch = contentDisposition.match(/filename=([^;]+)/);
+        if (filenameMatch) {
+            filename = filenameMatch[1].replace(/"/g, '');
+        }
+    }
+
+    const blob = new Blob([response.data], { type: 'application/zip' });
+    const url = URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = filename;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    URL.revokeObjectURL(url);
+};
+
+// Download a zip with one corruption's events, diagnostics, *arr state and config (API keys redacted)
+export const downloadCorruptionSupportBundle = async (id: string): Promise<void> => {
+    const response = await api.post(`/corruptions/${id}/support-bundle`, null, {
+        responseType: 'blob',
+    });
+
+    const blob = new Blob([response.data], { type: 'application/zip' });
+    const url = URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = `healarr_support_${id}.zip`;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    URL.revokeObjectURL(url);
+};
+
 export interface LogEntry {
     timestamp: string;
     level: 'INFO' | 'ERROR' | 'DEBUG';
@@ -214,6 +582,9 @@ export interface ArrInstance {
     url: string;
     api_key: string;
     enabled: boolean;
+    daily_request_budget?: number; // requests per UTC day; 0 is unlimited
+    requests_today?: number;
+    deferred_today?: number; // non-urgent requests held back near the budget
 }
 
 export interface ScanPath {
@@ -221,14 +592,56 @@ export interface ScanPath {
     local_path: string;
     arr_path: string;
     arr_instance_id: number | null;
+    arr_instance_tag?: string;  // Bind to the instance carrying this *arr tag; overrides arr_instance_id
+    quality_pin?: QualityPinMode;  // Require replacements to match the original's resolution
+    priority?: number;  // 0-100; higher-priority paths are scanned first when schedules collide
+    research_interval_days?: number;  // Search again every N days when no replacement was found; 0 = off
+    research_period_days?: number;  // How long to keep searching again (default 90 days)
+    protocol_preference?: ProtocolPreference;  // Blocklist replacement grabs of the other protocol
+    remote_url?: string;  // WebDAV (http/https) or SFTP (sftp) URL of the library; empty = local path
+    remote_username?: string;
+    remote_password?: string;
+    remote_host_key_fingerprint?: string;  // SHA256 fingerprint of the SFTP server's host key
+    remote_requests_per_minute?: number;  // 1-600, shared by all paths on the same server
+    remote_capabilities?: RemoteCapabilities;  // Read-only: what a remote path can be checked for
+    media_extensions?: string;  // Comma-separated extensions to scan, e.g. ".mkv,.mp4"; empty = built-in list
+    min_file_size_mb?: number;  // Files below this size are flagged as Undersized; 0 = off
+    duration_check?: DurationCheck;  // Compare durations with the runtime the *arr expects
+    symlink_policy?: SymlinkPolicy;  // How scans treat symbolic links; default skip
+    rollout_daily_limit?: number;  // Remediations per day during the break-in period; 0 = off
+    rollout_days?: number;  // Break-in period after the path was added
+    rollout_ends_at?: string;  // Read-only: end of the break-in period
+    min_confidence?: number;  // Detection confidence (0-100) needed for auto-remediation; 0 = off
     enabled: boolean;
     auto_remediate: boolean;
     dry_run?: boolean;  // Per-path dry run mode
     detection_method?: 'zero_byte' | 'ffprobe' | 'mediainfo' | 'handbrake';
     detection_args?: string;  // JSON string from API
-    detection_mode?: 'quick' | 'thorough';
+    detection_mode?: 'quick' | 'thorough' | 'sampled';
     max_retries?: number;
     verification_timeout_hours?: number | null;  // NULL = use global setting
+    detection_fallbacks?: ('zero_byte' | 'ffprobe' | 'mediainfo' | 'handbrake')[] | null;  // NULL = built-in fallback chain
+    min_file_age_minutes?: number;  // Skip files modified more recently than this; 0 = disabled
+    size_stability_seconds?: number;  // Delay between size checks; 0 = quick built-in check
+    capability?: ScanCapability;  // Read-only: whether the detection tools this path needs are installed
+}
+
+export interface RemoteCapabilities {
+    protocol: 'webdav' | 'sftp';
+    header_check: boolean;
+    stream_decode: boolean;
+    content_analysis: boolean;
+    hdr_validation: boolean;
+    audio_tracks: boolean;
+    size_stability: boolean;
+    untracked_files: boolean;
+    limitations: string[];
+}
+
+export interface ScanCapability {
+    scannable: boolean;  // false = no configured detector can run, scans are skipped
+    degraded: boolean;   // primary detector missing, a fallback runs instead
+    missing_tools: string[];
 }
 
 export const getArrInstances = async (): Promise<ArrInstance[]> => {
@@ -250,8 +663,50 @@ export const deleteArrInstance = async (id: number) => {
     await api.delete(`/config/arr/${id}`);
 };
 
-export const testArrConnection = async (url: string, api_key: string): Promise<{ success: boolean; message?: string; error?: string }> => {
-    const response = await api.post('/config/arr/test', { url, api_key });
+// id lets a saved instance be tested with its stored key, which is returned masked
+export const testArrConnection = async (url: string, api_key: string, id?: number): Promise<{ success: boolean; message?: string; error?: string }> => {
+    const response = await api.post('/config/arr/test', { url, api_key, id });
+    return response.data;
+};
+
+// Health check history of an *arr instance
+export interface ArrHealthSample {
+    checked_at: string;
+    reachable: boolean;
+    response_ms: number;
+    error?: string;
+}
+
+export interface ArrHealthPeriod {
+    period: string;  // "YYYY-MM-DD HH" (hourly) or "YYYY-MM-DD" (daily)
+    samples: number;
+    uptime_percent: number;
+    avg_response_ms: number;
+}
+
+export interface ArrInstanceHealth {
+    instance_id: number;
+    name: string;
+    type: string;
+    status: 'unknown' | 'healthy' | 'degraded' | 'down';
+    hours: number;
+    breaker: {
+        state: 'closed' | 'open' | 'half-open';
+        consecutive_failures: number;
+        open_since?: string;
+        last_failure?: string;
+        total_rejected: number;
+    };
+    samples: number;
+    uptime_percent: number | null;  // null until the instance has been checked in the window
+    avg_response_ms: number;
+    p95_response_ms: number;
+    latest: ArrHealthSample | null;
+    periods: ArrHealthPeriod[];
+}
+
+export const getArrInstanceHealth = async (id: number, hours = 24): Promise<ArrInstanceHealth> => {
+    const response = await api.get(`/arr/instances/${id}/health`, { params: { hours } });
     return response.data;
 };
 
@@ -274,6 +729,185 @@ export const deleteScanPath = async (id: number) => {
     await api.delete(`/config/paths/${id}`);
 };
 
+// Scan path groups (e.g. Movies, TV, Kids) for group-level scans, remediation pause and stats
+export interface PathGroupStats {
+    total_corruptions: number;
+    pending_corruptions: number;
+    in_progress_corruptions: number;
+    resolved_corruptions: number;
+    orphaned_corruptions: number;
+    failed_corruptions: number;
+    manual_intervention_corruptions: number;
+    ignored_corruptions: number;
+    success_rate: number;
+}
+
+export interface PathGroup {
+    id: number;
+    name: string;
+    remediation_paused: boolean;
+    path_ids: number[];
+    created_at: string;
+    updated_at: string;
+    stats: PathGroupStats;
+}
+
+export type PathGroupInput = Pick<PathGroup, 'name' | 'remediation_paused' | 'path_ids'>;
+
+export const getPathGroups = async (): Promise<PathGroup[]> => {
+    const { data } = await api.get<PathGroup[]>('/config/path-groups');
+    return data || [];
+};
+
+export const createPathGroup = async (group: PathGroupInput): Promise<PathGroup> => {
+    const { data } = await api.post<PathGroup>('/config/path-groups', group);
+    return data;
+};
+
+export const updatePathGroup = async (id: number, group: PathGroupInput): Promise<PathGroup> => {
+    const { data } = await api.put<PathGroup>(`/config/path-groups/${id}`, group);
+    return data;
+};
+
+export const deletePathGroup = async (id: number): Promise<void> => {
+    await api.delete(`/config/path-groups/${id}`);
+};
+
+export const setPathGroupPaused = async (id: number, paused: boolean): Promise<PathGroup> => {
+    const { data } = await api.post<PathGroup>(`/config/path-groups/${id}/${paused ? 'pause' : 'resume'}`);
+    return data;
+};
+
+export const scanPathGroup = async (id: number): Promise<{ message: string; started: number; skipped: number }> => {
+    const { data } = await api.post(`/config/path-groups/${id}/scan`);
+    return data;
+};
+
+// Bulk scan path import: a JSON array of rows or a CSV with a header row
+export interface BulkScanPathResult {
+    row: number;  // 1-based, not counting the CSV header
+    local_path: string;
+    status: 'created' | 'valid' | 'failed';
+    id?: number;
+    error?: string;
+    warnings?: string[];
+}
+
+export interface BulkScanPathResponse {
+    dry_run: boolean;
+    created: number;
+    failed: number;
+    results: BulkScanPathResult[];
+}
+
+export const bulkCreateScanPaths = async (data: string, format: 'csv' | 'json', dryRun = false): Promise<BulkScanPathResponse> => {
+    const response = await api.post('/config/paths/bulk', data, {
+        params: dryRun ? { dry_run: true } : undefined,
+        headers: { 'Content-Type': format === 'csv' ? 'text/csv' : 'application/json' },
+    });
+    return response.data;
+};
+
+// Scan paths whose *arr path is outside the root folders of their instance
+export interface MappingDrift {
+    path_id: number;
+    local_path: string;
+    arr_path: string;
+    instance_id: number;
+    instance_name: string;
+    root_folders: string[];
+    suggested_arr_path?: string;
+}
+
+export interface RemappedScanPath {
+    path_id: number;
+    local_path: string;
+    old_arr_path: string;
+    new_arr_path: string;
+}
+
+export const getMappingDrift = async (): Promise<MappingDrift[]> => {
+    const response = await api.get('/config/paths/drift');
+    return response.data;
+};
+
+export const remapScanPaths = async (
+    req: { instance_id: number; from: string; to: string; dry_run?: boolean }
+): Promise<{ dry_run: boolean; remapped: RemappedScanPath[] }> => {
+    const response = await api.post('/config/paths/remap', req);
+    return response.data;
+};
+
+// Scan paths moved to another *arr instance, with their open corruptions'
+// media looked up again on it
+export interface LibraryMigration {
+    dry_run: boolean;
+    from_instance: string;
+    to_instance: string;
+    paths: { path_id: number; local_path: string; arr_path: string; unbound_tag?: string }[];
+    corruptions: {
+        corruption_id: string;
+        file_path: string;
+        path_id: number;
+        state: string;
+        old_media_id: number;
+        media_id?: number;
+        error?: string;
+    }[];
+    reresolved: number;
+    unresolved: number;
+}
+
+export const migrateLibrary = async (
+    fromInstanceId: number,
+    req: { to_instance_id: number; path_ids?: number[]; dry_run?: boolean }
+): Promise<LibraryMigration> => {
+    const response = await api.post(`/config/arr/${fromInstanceId}/migrate`, req);
+    return response.data;
+};
+
+// Soft-deleted scan paths and *arr instances, restorable until purged
+export interface DeletedScanPath {
+    id: number;
+    local_path: string;
+    arr_path: string;
+    deleted_at: string;
+    purge_at?: string;
+    scans: number;
+    corruptions: number;
+}
+
+export interface DeletedArrInstance {
+    id: number;
+    name: string;
+    type: string;
+    url: string;
+    deleted_at: string;
+    purge_at?: string;
+    scan_paths: number;
+}
+
+export interface DeletedConfig {
+    retention_days: number;
+    scan_paths: DeletedScanPath[];
+    arr_instances: DeletedArrInstance[];
+}
+
+export const getDeletedConfig = async (): Promise<DeletedConfig> => {
+    const response = await api.get('/config/deleted');
+    return response.data;
+};
+
+export const restoreScanPath = async (id: number) => {
+    const response = await api.post(`/config/paths/${id}/restore`);
+    return response.data;
+};
+
+export const restoreArrInstance = async (id: number) => {
+    const response = await api.post(`/config/arr/${id}/restore`);
+    return response.data;
+};
+
 // Path validation response
 export interface PathValidation {
     accessible: boolean;
@@ -301,45 +935,315 @@ export interface BrowseResponse {
     error?: string | null;
 }
 
-export const browseDirectory = async (path: string = '/'): Promise<BrowseResponse> => {
-    const { data } = await api.get<BrowseResponse>('/config/browse', {
-        params: { path }
-    });
+export const browseDirectory = async (path: string = '/'): Promise<BrowseResponse> => {
+    const { data } = await api.get<BrowseResponse>('/config/browse', {
+        params: { path }
+    });
+    return data;
+};
+
+// --- Detection Preview API ---
+export interface DetectionPreview {
+    method: string;
+    mode: string;
+    command: string;
+    timeout: string;
+    mode_description: string;
+}
+
+export const getDetectionPreview = async (
+    method: string,
+    mode: string,
+    args?: string
+): Promise<DetectionPreview> => {
+    const params = new URLSearchParams({ method, mode });
+    if (args) params.set('args', args);
+    const { data } = await api.get<DetectionPreview>(`/config/detection-preview?${params}`);
+    return data;
+};
+
+export interface StatsHistory {
+    date: string;
+    count: number;
+}
+
+export interface StatsType {
+    type: string;
+    count: number;
+}
+
+export const getStatsHistory = async (): Promise<StatsHistory[]> => {
+    const { data } = await api.get<StatsHistory[]>('/stats/history');
+    return data;
+};
+
+export interface BandwidthUsage {
+    period: string;                  // YYYY-MM-DD or YYYY-MM (UTC)
+    deleted_bytes: number;
+    downloaded_bytes: number;
+    deletions: number;
+    replacements: number;
+}
+
+export interface DataBudgetStatus {
+    month: string;
+    downloaded_bytes: number;
+    estimated_pending_bytes: number; // Deleted files not yet replaced
+    used_bytes: number;
+    budget_bytes: number;            // 0 when no budget is set
+    exceeded: boolean;
+}
+
+export interface RemediationBudget {
+    enabled: boolean;
+    action: 'defer' | 'approve';
+    paused: boolean;
+    deferred_month: string;          // Month a deferred remediation didn't fit in, '' if none
+    deferred: number;
+    awaiting_approval: number;
+    current_month: DataBudgetStatus;
+}
+
+export const getRemediationBudget = async (): Promise<RemediationBudget> => {
+    const { data } = await api.get<RemediationBudget>('/remediation/budget');
+    return data;
+};
+
+export const approveBudgetRemediations = async (ids: string[]): Promise<{ message: string; approved: number; skipped: number }> => {
+    const { data } = await api.post('/remediation/budget/approve', { ids });
+    return data;
+};
+
+export interface StatsBandwidth {
+    group: 'day' | 'month';
+    periods: BandwidthUsage[];
+    total: Omit<BandwidthUsage, 'period'>;
+    current_month: DataBudgetStatus;
+}
+
+export const getStatsBandwidth = async (group: 'day' | 'month' = 'day', days = 30): Promise<StatsBandwidth> => {
+    const { data } = await api.get<StatsBandwidth>('/stats/bandwidth', { params: { group, days } });
+    return data;
+};
+
+export interface ReportPath {
+    path_id: number;
+    local_path: string;
+    corruptions: number;
+}
+
+export interface HealthScoreWeekly {
+    week_start: string;
+    files_scanned: number;
+    corruptions_found: number;
+    score: number | null;            // 0-100, null when nothing was scanned
+}
+
+export interface Report {
+    id: number;
+    period_start: string;
+    period_end: string;
+    generated_at: string;
+    new_corruptions: number;
+    resolved: number;
+    failures: number;
+    scan_failures: number;
+    unresolved: number;
+    top_paths: ReportPath[];
+    health_trend: HealthScoreWeekly[];
+}
+
+export const getReports = async (): Promise<Report[]> => {
+    const { data } = await api.get<Report[]>('/reports');
+    return data;
+};
+
+export const generateReport = async (): Promise<Report> => {
+    const { data } = await api.post<Report>('/reports');
+    return data;
+};
+
+export const downloadReport = async (id: number, format: 'markdown' | 'html'): Promise<Blob> => {
+    const { data } = await api.get(`/reports/${id}`, { params: { format }, responseType: 'blob' });
+    return data;
+};
+
+export type MaintenanceState = 'pending' | 'running' | 'completed' | 'skipped' | 'failed' | 'aborted';
+
+export interface MaintenanceStep {
+    name: 'purge_deleted' | 'prune_samples' | 'prune' | 'summary' | 'vacuum' | 'analyze' | 'checkpoint' | string;
+    status: MaintenanceState;
+    duration_ms: number;
+    reason?: string;                 // why the step was skipped, failed or aborted
+}
+
+export interface MaintenanceRun {
+    trigger: 'scheduled' | 'manual';
+    status: MaintenanceState;
+    started_at: string;
+    finished_at?: string;
+    duration_ms: number;             // so far while running
+    current_step?: string;
+    progress: number;                // percent of steps finished
+    steps: MaintenanceStep[];
+}
+
+export interface MaintenanceStatus {
+    schedule: string;                // empty when scheduled maintenance is off
+    peak_hours?: string;             // HH:MM-HH:MM window in which vacuum doesn't run
+    next_run_at?: string;
+    current?: MaintenanceRun;
+    last_run?: MaintenanceRun;
+}
+
+export const getMaintenanceStatus = async (): Promise<MaintenanceStatus> => {
+    const { data } = await api.get<MaintenanceStatus>('/system/maintenance');
+    return data;
+};
+
+export const triggerMaintenance = async (skipVacuum = false): Promise<MaintenanceRun> => {
+    const { data } = await api.post<MaintenanceRun>('/system/maintenance', null, {
+        params: skipVacuum ? { skip_vacuum: true } : undefined,
+    });
+    return data;
+};
+
+export const abortMaintenance = async (): Promise<MaintenanceStatus> => {
+    const { data } = await api.post<MaintenanceStatus>('/system/maintenance/abort');
+    return data;
+};
+
+export interface SystemPauseStatus {
+    paused: boolean;
+    reason?: string;
+    detection_only: boolean;         // Scheduled scans keep running
+    paused_at?: string;
+    resumes_at?: string;             // Unset: paused until resumed by hand
+}
+
+export interface PauseSystemRequest {
+    reason?: string;
+    detection_only?: boolean;
+    duration_minutes?: number;       // 0: until resumed by hand
+}
+
+export const getSystemPause = async (): Promise<SystemPauseStatus> => {
+    const { data } = await api.get<SystemPauseStatus>('/system/pause');
+    return data;
+};
+
+export const pauseSystem = async (request: PauseSystemRequest): Promise<SystemPauseStatus> => {
+    const { data } = await api.post<SystemPauseStatus>('/system/pause', request);
+    return data;
+};
+
+export const resumeSystem = async (): Promise<SystemPauseStatus> => {
+    const { data } = await api.post<SystemPauseStatus>('/system/resume');
+    return data;
+};
+
+export type BackupTargetType = 's3' | 'webdav' | 'sftp' | 'rclone';
+
+export interface BackupTargetConfig {
+    path?: string;
+    // S3-compatible storage
+    endpoint?: string;
+    region?: string;
+    bucket?: string;
+    access_key_id?: string;
+    secret_access_key?: string;
+    path_style?: boolean;
+    // WebDAV (username and password are also used by SFTP)
+    url?: string;
+    username?: string;
+    password?: string;
+    // SFTP
+    host?: string;
+    private_key?: string;
+    host_key_fingerprint?: string;
+    // rclone
+    remote?: string;
+    config_file?: string;
+}
+
+export interface BackupTarget {
+    id: number;
+    name: string;
+    type: BackupTargetType;
+    config: BackupTargetConfig;
+    keep_last: number;               // 0 = no limit
+    keep_days: number;               // 0 = no limit
+    enabled: boolean;
+    last_upload_at?: string;
+    last_error?: string;
+    created_at: string;
+    updated_at: string;
+}
+
+export type BackupTargetInput = Pick<BackupTarget, 'name' | 'type' | 'config' | 'keep_last' | 'keep_days' | 'enabled'>;
+
+export interface RemoteFile {
+    name: string;
+    size: number;
+    mod_time: string;
+}
+
+export interface BackupTargetsResponse {
+    targets: BackupTarget[];
+    types: BackupTargetType[];
+    encryption_enabled: boolean;     // HEALARR_BACKUP_ENCRYPTION_KEY is set
+}
+
+export const getBackupTargets = async (): Promise<BackupTargetsResponse> => {
+    const { data } = await api.get<BackupTargetsResponse>('/config/backup-targets');
     return data;
 };
 
-// --- Detection Preview API ---
-export interface DetectionPreview {
-    method: string;
-    mode: string;
-    command: string;
-    timeout: string;
-    mode_description: string;
-}
+export const createBackupTarget = async (target: BackupTargetInput) => {
+    const { data } = await api.post('/config/backup-targets', target);
+    return data;
+};
 
-export const getDetectionPreview = async (
-    method: string,
-    mode: string,
-    args?: string
-): Promise<DetectionPreview> => {
-    const params = new URLSearchParams({ method, mode });
-    if (args) params.set('args', args);
-    const { data } = await api.get<DetectionPreview>(`/config/detection-preview?${params}`);
+export const updateBackupTarget = async (id: number, target: BackupTargetInput) => {
+    const { data } = await api.put(`/config/backup-targets/${id}`, target);
     return data;
 };
 
-export interface StatsHistory {
-    date: string;
-    count: number;
-}
+export const deleteBackupTarget = async (id: number) => {
+    const { data } = await api.delete(`/config/backup-targets/${id}`);
+    return data;
+};
 
-export interface StatsType {
-    type: string;
-    count: number;
+export const testBackupTarget = async (target: Partial<BackupTargetInput> & { id?: number }): Promise<{ success: boolean; backups?: RemoteFile[]; error?: string }> => {
+    const { data } = await api.post('/config/backup-targets/test', target);
+    return data;
+};
+
+export const uploadBackupNow = async (id: number) => {
+    const { data } = await api.post(`/config/backup-targets/${id}/upload`);
+    return data;
+};
+
+export interface CorruptionSummaryConsistency {
+    corruptions: number;             // corruptions with events
+    missing: string[];               // corruption IDs with events but no summary row
+    orphaned: string[];              // summary rows without events
+    mismatched: string[];            // summary rows that differ from their events
+    repaired: number;
 }
 
-export const getStatsHistory = async (): Promise<StatsHistory[]> => {
-    const { data } = await api.get<StatsHistory[]>('/stats/history');
+export const checkCorruptionSummary = async (): Promise<{ consistent: boolean; result: CorruptionSummaryConsistency }> => {
+    const { data } = await api.get<{ consistent: boolean; result: CorruptionSummaryConsistency }>('/system/corruption-summary');
+    return data;
+};
+
+export const repairCorruptionSummary = async (): Promise<CorruptionSummaryConsistency> => {
+    const { data } = await api.post<CorruptionSummaryConsistency>('/system/corruption-summary/repair');
+    return data;
+};
+
+export const rebuildCorruptionSummary = async (): Promise<{ rebuilt: number }> => {
+    const { data } = await api.post<{ rebuilt: number }>('/system/corruption-summary/repair', null, { params: { full: true } });
     return data;
 };
 
@@ -348,6 +1252,37 @@ export const getStatsTypes = async (): Promise<StatsType[]> => {
     return data;
 };
 
+export interface HeatmapDirectory {
+    path_id?: number;
+    directory: string;               // absolute
+    name: string;                    // relative to the scan path, "." for its own files
+    depth: number;
+    corruptions: number;
+    unresolved: number;
+    resolved: number;
+    files: number;                   // distinct corrupted files
+    last_detected_at: string;
+}
+
+export interface CorruptionHeatmap {
+    depth: number;
+    days?: number;                   // unset: all time
+    total: number;
+    directories: HeatmapDirectory[]; // most corrupted first
+}
+
+export const getStatsHeatmap = async (depth = 2, pathId?: number, days?: number): Promise<CorruptionHeatmap> => {
+    const params: Record<string, number> = { depth };
+    if (pathId !== undefined) {
+        params.path_id = pathId;
+    }
+    if (days !== undefined) {
+        params.days = days;
+    }
+    const { data } = await api.get<CorruptionHeatmap>('/stats/heatmap', { params });
+    return data;
+};
+
 // --- Auth API ---
 export const getAPIKey = async (): Promise<{ api_key: string }> => {
     const { data } = await api.get<{ api_key: string }>('/auth/key');
@@ -372,13 +1307,52 @@ export const getAuthStatus = async (): Promise<{ is_setup: boolean }> => {
     return data;
 };
 
+// UI preferences stored server-side so every browser behaves the same.
+// Empty values (0, '') leave the choice to the browser.
+export interface UIPreferences {
+    page_size: number;
+    timezone: string;                // IANA name, '' = the browser's
+    date_format: '' | 'time-first' | 'date-first' | 'iso';
+    theme: '' | 'dark' | 'light';
+    default_filters: Record<string, Record<string, string>>;  // by page, e.g. { corruptions: { status: 'resolved' } }
+}
+
+export interface PreferencesResponse {
+    preferences: UIPreferences;
+    updated_at: string | null;       // null until preferences are first saved
+}
+
+export const getPreferences = async (): Promise<PreferencesResponse> => {
+    const { data } = await api.get<PreferencesResponse>('/preferences');
+    return data;
+};
+
+// Fields left out keep their value; default_filters replaces all default filters
+export const updatePreferences = async (update: Partial<UIPreferences>): Promise<PreferencesResponse> => {
+    const { data } = await api.put<PreferencesResponse>('/preferences', update);
+    return data;
+};
+
 export default api;
-export interface Schedule {
+// What happens to a run whose time falls into a daylight saving time change:
+// a time skipped when clocks go forward runs right after the jump or not at all,
+// a time repeated when clocks go back runs once or twice
+export type DSTGapPolicy = 'run' | 'skip';
+export type DSTOverlapPolicy = 'once' | 'twice';
+
+export interface ScheduleTiming {
+    timezone: string; // IANA name, empty for the server's timezone
+    dst_gap: DSTGapPolicy;
+    dst_overlap: DSTOverlapPolicy;
+}
+
+export interface Schedule extends ScheduleTiming {
     id: number;
     scan_path_id: number;
     local_path: string;
     cron_expression: string;
     enabled: boolean;
+    next_run_at: string | null; // null when disabled
 }
 
 export const getSchedules = async () => {
@@ -386,12 +1360,12 @@ export const getSchedules = async () => {
     return data;
 };
 
-export const addSchedule = async (schedule: { scan_path_id: number; cron_expression: string }) => {
+export const addSchedule = async (schedule: { scan_path_id: number; cron_expression: string } & Partial<ScheduleTiming>) => {
     const response = await api.post('/config/schedules', schedule);
     return response.data;
 };
 
-export const updateSchedule = async (id: number, schedule: { cron_expression?: string; enabled?: boolean }) => {
+export const updateSchedule = async (id: number, schedule: { cron_expression?: string; enabled?: boolean } & Partial<ScheduleTiming>) => {
     const response = await api.put(`/config/schedules/${id}`, schedule);
     return response.data;
 };
@@ -405,6 +1379,7 @@ export const deleteSchedule = async (id: number) => {
 export interface RuntimeConfig {
     base_path: string;
     base_path_source: string;  // "environment", "database", or "default"
+    public_dashboard?: boolean; // HEALARR_PUBLIC_DASHBOARD - read-only /status page without login
 }
 
 export const getRuntimeConfig = async (): Promise<RuntimeConfig> => {
@@ -412,6 +1387,36 @@ export const getRuntimeConfig = async (): Promise<RuntimeConfig> => {
     return data;
 };
 
+// Public read-only dashboard (no authentication, only when HEALARR_PUBLIC_DASHBOARD is enabled)
+export interface PublicDashboard {
+    status: 'healthy' | 'warning' | 'critical' | 'unknown' | 'disabled';
+    corruptions: {
+        total: number;
+        pending: number;
+        in_progress: number;
+        resolved: number;
+        failed: number;
+        success_rate: number;
+    };
+    libraries: {
+        name: string;
+        status: 'healthy' | 'warning' | 'critical' | 'unknown' | 'disabled';
+        active_corruptions: number;
+        last_scan_time?: string;
+    }[];
+    recent_remediations: {
+        file_name: string;
+        completed_at: string;
+    }[];
+    active_scans: number;
+    generated_at: string;
+}
+
+export const getPublicDashboard = async (): Promise<PublicDashboard> => {
+    const { data } = await api.get<PublicDashboard>('/public/dashboard');
+    return data;
+};
+
 // Settings update
 export interface SettingsUpdateRequest {
     base_path: string;
@@ -451,12 +1456,22 @@ export interface NotificationConfig {
     provider_type: string;
     config: Record<string, unknown>;
     events: string[];
+    group_ids?: number[];  // limit to events of these scan path groups; empty = all paths
     enabled: boolean;
     throttle_seconds: number;
+    locale?: string;      // language of the messages; empty = HEALARR_LOCALE
     created_at?: string;
     updated_at?: string;
 }
 
+// Languages notifications can be sent in
+export const NOTIFICATION_LOCALES = [
+    { code: 'en', label: 'English' },
+    { code: 'de', label: 'Deutsch' },
+    { code: 'fr', label: 'Français' },
+    { code: 'es', label: 'Español' },
+] as const;
+
 export interface EventInfo {
     name: string;        // Event type name (e.g., "ScanStarted")
     label: string;       // Friendly display name (e.g., "Scan Started")
@@ -519,8 +1534,8 @@ export const getNotificationLog = async (id: number, limit = 50): Promise<Notifi
 
 // --- Corruption Bulk Actions API ---
 
-export const retryCorruptions = async (ids: string[]): Promise<{ message: string; retried: number }> => {
-    const { data } = await api.post<{ message: string; retried: number }>('/corruptions/retry', { ids });
+export const retryCorruptions = async (ids: string[]): Promise<{ message: string; retried: number; skipped_protected: number }> => {
+    const { data } = await api.post<{ message: string; retried: number; skipped_protected: number }>('/corruptions/retry', { ids });
     return data;
 };
 
@@ -534,6 +1549,176 @@ export const deleteCorruptions = async (ids: string[]): Promise<{ message: strin
     return data;
 };
 
+// --- Corruption tags and saved filters ---
+
+export interface TagCount {
+    tag: string;
+    corruptions: number;
+}
+
+// Matches corruptions in any of the statuses and scan paths that carry all of the tags;
+// empty lists match everything
+export interface SavedFilter {
+    id: number;
+    name: string;
+    statuses: string[];
+    tags: string[];
+    path_ids: number[];
+    created_at: string;
+    updated_at: string;
+}
+
+export type SavedFilterInput = Pick<SavedFilter, 'name'> & Partial<Pick<SavedFilter, 'statuses' | 'tags' | 'path_ids'>>;
+
+export const getCorruptionTags = async (): Promise<TagCount[]> => {
+    const { data } = await api.get<TagCount[]>('/corruptions/tags');
+    return data;
+};
+
+export const tagCorruptions = async (ids: string[], tags: string[]): Promise<{ message: string }> => {
+    const { data } = await api.post<{ message: string }>('/corruptions/tags', { ids, tags });
+    return data;
+};
+
+export const untagCorruptions = async (ids: string[], tags: string[]): Promise<{ message: string }> => {
+    const { data } = await api.post<{ message: string }>('/corruptions/tags/remove', { ids, tags });
+    return data;
+};
+
+export const getSavedFilters = async (): Promise<SavedFilter[]> => {
+    const { data } = await api.get<SavedFilter[]>('/corruptions/filters');
+    return data;
+};
+
+export const createSavedFilter = async (filter: SavedFilterInput): Promise<SavedFilter> => {
+    const { data } = await api.post<SavedFilter>('/corruptions/filters', filter);
+    return data;
+};
+
+export const updateSavedFilter = async (id: number, filter: SavedFilterInput): Promise<SavedFilter> => {
+    const { data } = await api.put<SavedFilter>(`/corruptions/filters/${id}`, filter);
+    return data;
+};
+
+export const deleteSavedFilter = async (id: number): Promise<void> => {
+    await api.delete(`/corruptions/filters/${id}`);
+};
+
+export interface RemediationQueueInstance {
+    instance_id: number;
+    name: string;
+    active: number;
+    queued: number;
+    searches_in_window: number;
+    next_slot_at?: string;
+    detection_only: boolean;         // Remediation paused while the instance is down
+}
+
+export interface QueuedRemediation {
+    corruption_id: string;
+    file_path: string;
+    instance_id: number;
+    instance_name: string;
+    position: number;
+    queued_at: string;
+}
+
+export interface RemediationQueue {
+    max_concurrent: number;
+    searches_per_hour: number;
+    window?: string;                 // Daily HH:MM-HH:MM window in which remediations start
+    window_opens_at?: string;        // Set while outside the window
+    budget_paused: boolean;          // Monthly data budget used up
+    system_paused: boolean;          // All automation paused (see pauseSystem)
+    instances: RemediationQueueInstance[];
+    queue: QueuedRemediation[];
+}
+
+export const getRemediationQueue = async (): Promise<RemediationQueue> => {
+    const { data } = await api.get<RemediationQueue>('/remediation/queue');
+    return data;
+};
+
+export const markFalsePositives = async (ids: string[], note?: string): Promise<{ message: string; marked: number }> => {
+    const { data } = await api.post<{ message: string; marked: number }>('/corruptions/false-positive', { ids, note });
+    return data;
+};
+
+export interface FalsePositive {
+    id: number;
+    corruption_id: string;
+    file_path: string;
+    file_size: number;
+    fingerprint: string;
+    corruption_type: string;
+    error_details: string;
+    signature: string;
+    detection_tool: string;
+    tool_version: string;
+    note: string;
+    suppressed_count: number;
+    last_suppressed_at: string | null;
+    created_at: string;
+}
+
+export interface FalsePositiveCount {
+    key: string;
+    count: number;
+    suppressed: number;
+}
+
+export interface FalsePositiveReport {
+    total: number;
+    suppressed: number;
+    by_corruption_type: FalsePositiveCount[];
+    by_tool: FalsePositiveCount[];
+    top_findings: {
+        signature: string;
+        corruption_type: string;
+        sample_error: string;
+        count: number;
+        suppressed: number;
+    }[];
+}
+
+export const getFalsePositives = async (limit?: number): Promise<FalsePositive[]> => {
+    const { data } = await api.get<FalsePositive[]>('/false-positives', { params: limit ? { limit } : undefined });
+    return data;
+};
+
+export const getFalsePositiveReport = async (): Promise<FalsePositiveReport> => {
+    const { data } = await api.get<FalsePositiveReport>('/false-positives/report');
+    return data;
+};
+
+export const deleteFalsePositive = async (id: number): Promise<void> => {
+    await api.delete(`/false-positives/${id}`);
+};
+
+// Protected files and media are scanned and reported but never remediated
+export interface ProtectedItem {
+    id: number;
+    file_path?: string;
+    arr_instance_id?: number;
+    media_id?: number;
+    note: string;
+    created_at: string;
+}
+
+export const getProtectedItems = async (): Promise<ProtectedItem[]> => {
+    const { data } = await api.get<ProtectedItem[]>('/protected');
+    return data;
+};
+
+export const protectItem = async (item: { file_path?: string; arr_instance_id?: number; media_id?: number; note?: string }): Promise<{ message: string; id: number }> => {
+    const { data } = await api.post<{ message: string; id: number }>('/protected', item);
+    return data;
+};
+
+export const deleteProtectedItem = async (id: number): Promise<void> => {
+    await api.delete(`/protected/${id}`);
+};
+
 // --- Health API ---
 
 export interface HealthStatus {
@@ -603,11 +1788,13 @@ export interface ConfigExport {
         events: string[];
         enabled: boolean;
         throttle_seconds: number;
+        locale?: string;
     }>;
 }
 
-export const exportConfig = async (): Promise<ConfigExport> => {
-    const { data } = await api.get<ConfigExport>('/config/export');
+// Secrets are masked unless includeSecrets is set, e.g. for a backup to restore from
+export const exportConfig = async (includeSecrets = false): Promise<ConfigExport> => {
+    const { data } = await api.get<ConfigExport>('/config/export', { params: includeSecrets ? { include_secrets: true } : undefined });
     return data;
 };
 
@@ -639,6 +1826,36 @@ export const downloadDatabaseBackup = async (): Promise<void> => {
     URL.revokeObjectURL(url);
 };
 
+export type RemediationExportFormat = 'csv' | 'json' | 'radarr' | 'sonarr';
+
+// Download the remediation history as CSV or JSON, or the remediated movies/series
+// as an *arr custom import list
+export const downloadRemediationExport = async (format: RemediationExportFormat = 'csv'): Promise<void> => {
+    const response = await api.get('/export/remediations', {
+        params: { format },
+        responseType: 'blob',
+    });
+
+    const contentDisposition = response.headers['content-disposition'];
+    let filename = `healarr_remediations_${format}.${format === 'csv' ? 'csv' : 'json'}`;
+    if (contentDisposition) {
+        const filenameMatch = contentDisposition.match(/filename=([^;]+)/);
+        if (filenameMatch) {
+            filename = filenameMatch[1].replace(/"/g, '');
+        }
+    }
+
+    const blob = new Blob([response.data], { type: format === 'csv' ? 'text/csv' : 'application/json' });
+    const url = URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = filename;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    URL.revokeObjectURL(url);
+};
+
 export interface ConfigImportResult {
     message: string;
     imported: {
@@ -671,6 +1888,7 @@ export interface UpdateCheckResponse {
     download_urls: Record<string, string>;
     docker_pull_cmd: string;
     update_instructions: UpdateInstructions;
+    checks_disabled?: boolean;
 }
 
 export const checkForUpdates = async (): Promise<UpdateCheckResponse> => {
@@ -685,10 +1903,12 @@ export interface SystemConfigInfo {
     base_path: string;
     base_path_source: string;
     log_level: string;
+    locale: string;
     data_dir: string;
     database_path: string;
     log_dir: string;
     dry_run_mode: boolean;
+    offline_mode: boolean;
     retention_days: number;
     default_max_retries: number;
     verification_timeout: string;
@@ -741,6 +1961,110 @@ export const getSystemInfo = async (): Promise<SystemInfo> => {
     return data;
 };
 
+export interface SystemStatus {
+    version: string;
+    started_at: string;
+    arr_key_encryption: {
+        encryption_enabled: boolean;
+        instances: number;
+        migrated: string[];
+        plaintext: string[];
+        undecryptable: string[];
+        checked_at: string;
+    } | null;
+    unscannable_paths: (ScanCapability & { path_id: number; local_path: string })[];
+    pause: SystemPauseStatus;
+    startup_warnings: number;
+    warnings: string[];
+}
+
+export const getSystemStatus = async (): Promise<SystemStatus> => {
+    const { data } = await api.get<SystemStatus>('/system/status');
+    return data;
+};
+
+export interface StartupReport {
+    version: string;
+    go_version: string;
+    platform: string;
+    tools?: Record<string, string>;
+    started_at: string;
+    completed_at?: string;
+    config: { name: string; value: string }[];
+    components: { name: string; status: 'ok' | 'degraded' | 'failed'; detail?: string }[];
+    warnings: string[];
+}
+
+// Versions, config, component results and warnings of the last startup
+export const getStartupReport = async (): Promise<StartupReport> => {
+    const { data } = await api.get<StartupReport>('/system/startup-report');
+    return data;
+};
+
+export interface OutboundTarget {
+    kind: 'arr' | 'notification' | 'webhook' | 'mqtt' | 'update_check' | 'telemetry';
+    name: string;
+    host?: string;
+    enabled: boolean;
+    configured: boolean;
+}
+
+export interface OfflineCheck {
+    offline_mode: boolean;
+    passed: boolean;
+    web_assets: {
+        source: 'embedded' | 'filesystem' | 'none';
+        external_references: { file: string; url: string }[];
+        error?: string;
+    };
+    outbound: OutboundTarget[];
+}
+
+export const getOfflineCheck = async (): Promise<OfflineCheck> => {
+    const { data } = await api.get<OfflineCheck>('/system/offline-check');
+    return data;
+};
+
+export interface TelemetryStatus {
+    enabled: boolean;                // Opted in
+    hard_disabled: boolean;          // HEALARR_TELEMETRY_DISABLED or offline mode
+    endpoint?: string;               // Unset: nothing is sent
+    interval_days: number;
+    last_sent_at?: string;
+}
+
+// Everything a usage statistics report contains
+export interface TelemetryReport {
+    schema_version: number;
+    install_id: string;
+    version: string;
+    os: string;
+    arch: string;
+    scan_paths: string;              // Bucket, e.g. "2-5"
+    library_files: string;
+    remote_paths: boolean;
+    detection_methods: Record<string, number>;
+    arr_instances: Record<string, number>;
+    tools: Record<string, string>;
+    corruptions: string;
+    remediation_success_rate: number | null;
+}
+
+export const getTelemetry = async (): Promise<TelemetryStatus> => {
+    const { data } = await api.get<TelemetryStatus>('/system/telemetry');
+    return data;
+};
+
+export const setTelemetry = async (enabled: boolean): Promise<TelemetryStatus> => {
+    const { data } = await api.put<TelemetryStatus>('/system/telemetry', { enabled });
+    return data;
+};
+
+export const getTelemetryPreview = async (): Promise<TelemetryReport> => {
+    const { data } = await api.get<TelemetryReport>('/system/telemetry/preview');
+    return data;
+};
+
 // --- Setup/Onboarding API ---
 
 export interface SetupStatus {
@@ -762,6 +2086,60 @@ export const dismissSetup = async (): Promise<{ message: string }> => {
     return data;
 };
 
+// Setup wizard progress, stored server-side so the wizard resumes after the browser is closed
+export type SetupWizardStep = 'welcome' | 'password' | 'arr' | 'path' | 'schedule' | 'notifications' | 'complete';
+
+export interface SetupWizardState {
+    step: SetupWizardStep;
+    instance_id?: number;
+    path_id?: number;
+    schedule_id?: number;
+    scan_started?: boolean;
+}
+
+export interface SetupWizardProgress {
+    state: SetupWizardState;
+    status: SetupStatus;
+    next_step: SetupWizardStep;
+}
+
+export const getSetupWizard = async (): Promise<SetupWizardProgress> => {
+    const { data } = await api.get<SetupWizardProgress>('/setup/wizard');
+    return data;
+};
+
+export const saveSetupWizard = async (state: SetupWizardState): Promise<SetupWizardProgress> => {
+    const { data } = await api.put<SetupWizardProgress>('/setup/wizard', state);
+    return data;
+};
+
+export const clearSetupWizard = async (): Promise<void> => {
+    await api.delete('/setup/wizard');
+};
+
+export interface SetupRootFolder {
+    path: string;
+    free_space: number;
+    suggested_local_path: string;
+    local_exists: boolean;
+    scan_path_id?: number;
+}
+
+export const getSetupRootFolders = async (instanceId: number): Promise<SetupRootFolder[]> => {
+    const { data } = await api.get<SetupRootFolder[]>('/setup/root-folders', { params: { instance_id: instanceId } });
+    return data;
+};
+
+export const startFirstScan = async (req: {
+    path_id: number;
+    cron_expression?: string;
+    timezone?: string;
+    scan_now: boolean;
+}): Promise<SetupWizardProgress> => {
+    const { data } = await api.post<SetupWizardProgress>('/setup/first-scan', req);
+    return data;
+};
+
 // Import config during setup
 // Uses authenticated endpoint if user has a token, otherwise uses public endpoint
 export const importConfigPublic = async (config: Partial<ConfigExport>): Promise<ConfigImportResult> => {
@@ -807,3 +2185,28 @@ export const getArrRootFolders = async (instanceId: number): Promise<RootFolder[
     const { data } = await api.get<RootFolder[]>(`/config/arr/${instanceId}/rootfolders`);
     return data;
 };
+
+// --- ARR Tags API ---
+
+export interface ArrTag {
+    id: number;
+    label: string;
+}
+
+export interface ArrTagSyncResult {
+    instances: number;
+    failed: string[];
+    rebound: number;
+    unresolved?: string[];
+}
+
+export const getArrTags = async (instanceId: number): Promise<ArrTag[]> => {
+    const { data } = await api.get<ArrTag[]>(`/config/arr/${instanceId}/tags`);
+    return data;
+};
+
+// Refreshes tags of all instances and re-binds tag-bound scan paths
+export const syncArrTags = async (): Promise<ArrTagSyncResult> => {
+    const { data } = await api.post<ArrTagSyncResult>('/config/arr/tags/sync');
+    return data;
+};
@@ -68,6 +68,23 @@ export const setDateFormatPreset = (preset: DateFormatPreset): void => {
   window.dispatchEvent(new CustomEvent('dateFormatChange', { detail: preset }));
 };
 
+// Timezone dates are shown in, empty for the browser's (see preferences.ts)
+export const TIMEZONE_STORAGE_KEY = 'healarr_timezone';
+
+export const getTimezone = (): string => localStorage.getItem(TIMEZONE_STORAGE_KEY) ?? '';
+
+// toTimezone shifts a date so date-fns, which formats in the browser's
+// timezone, shows the wall clock time of the preferred timezone
+const toTimezone = (date: Date): Date => {
+  const timeZone = getTimezone();
+  if (!timeZone) return date;
+  try {
+    return new Date(date.toLocaleString('en-US', { timeZone }));
+  } catch {
+    return date;
+  }
+};
+
 // Get the format config for current preset
 export const getDateFormatConfig = (): DateFormatConfig => {
   return DATE_FORMATS[getDateFormatPreset()];
@@ -81,7 +98,7 @@ export const formatDateTime = (date: string | Date | null | undefined, type: 'ti
   // Check for invalid date
   if (isNaN(dateObj.getTime())) return '-';
   const formatStr = config[type] || config.full;
-  return formatStr ? dateFnsFormat(dateObj, formatStr) : '';
+  return formatStr ? dateFnsFormat(toTimezone(dateObj), formatStr) : '';
 };
 
 // Format just the time portion (always HH:mm:ss)
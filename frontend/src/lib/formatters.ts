@@ -13,19 +13,40 @@ export function formatCorruptionType(type: string): string {
         'BlackVideo': 'Black Video',
         'FrozenVideo': 'Frozen Video',
         'SilentAudio': 'Silent Audio',
+        'HDRMetadata': 'HDR Metadata',
+        'Undersized': 'Undersized File',
+        'DurationMismatch': 'Duration Mismatch',
         'Unknown': 'Unknown Issue',
     };
 
     return typeMap[type] || type.replace(/([A-Z])/g, ' $1').trim();
 }
 
+/**
+ * Format the cause of a failed deletion into a human-friendly label
+ */
+export function formatDeletionCause(cause: string): string {
+    const causeMap: Record<string, string> = {
+        'permission_denied': 'Permission denied',
+        'read_only': 'Read-only file system',
+        'file_locked': 'File in use by another process',
+        'file_missing': 'File already gone',
+        'mount_lost': 'Folder not reachable (mount lost?)',
+        'arr_error': '*arr returned an error',
+        'arr_unreachable': '*arr not reachable',
+        'not_in_arr': 'File not tracked by *arr',
+    };
+
+    return causeMap[cause] || 'Unknown cause';
+}
+
 /**
  * Format corruption state (event type) into human-friendly label and color
  * This is the single source of truth for state display across the app
  * 
  * Color scheme based on parent status:
  * - Pending (amber): CorruptionDetected
- * - In Progress (blue): RemediationQueued, DeletionStarted, DeletionCompleted, SearchStarted, SearchCompleted, FileDetected, VerificationStarted
+ * - In Progress (blue): RemediationQueued, DeletionStarted, DeletionCompleted, SearchStarted, SearchCompleted, FileDetected, ReverificationRequested, VerificationStarted
  * - Resolved (green/emerald): VerificationSuccess
  * - Failed/Retrying (orange): *Failed states (temporary)
  * - Max Retries (red): MaxRetriesReached (permanent failure)
@@ -60,6 +81,9 @@ export function formatCorruptionState(state: string): { label: string; colorClas
     if (state === 'SearchExhausted') {
         return { label: 'No Replacement Found', colorClass: 'bg-amber-500/10 text-amber-400 border-amber-500/20' };
     }
+    if (state === 'ScheduledResearch') {
+        return { label: 'Searching Later', colorClass: 'bg-amber-500/10 text-amber-400 border-amber-500/20' };
+    }
 
     // Stuck remediation - item hasn't progressed in 24+ hours (orange - needs attention)
     if (state === 'StuckRemediation') {
@@ -76,6 +100,21 @@ export function formatCorruptionState(state: string): { label: string; colorClas
     if (state === 'DownloadIgnored') {
         return { label: 'Ignored by User', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
     }
+    if (state === 'BudgetApprovalRequired') {
+        return { label: 'Needs Approval', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
+    }
+    if (state === 'LowConfidenceDetection') {
+        return { label: 'Unconfirmed', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
+    }
+    if (state === 'QualityRegression') {
+        return { label: 'Lower Quality', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
+    }
+    if (state === 'AudioTrackMissing') {
+        return { label: 'Missing Audio', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
+    }
+    if (state === 'DeletionPlanChanged') {
+        return { label: 'Deletion Stopped', colorClass: 'bg-purple-500/10 text-purple-400 border-purple-500/20' };
+    }
 
     // Pending - just detected (amber)
     if (state === 'CorruptionDetected') {
@@ -86,6 +125,9 @@ export function formatCorruptionState(state: string): { label: string; colorClas
     if (state === 'RemediationQueued') {
         return { label: 'Queued', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
     }
+    if (state === 'RemediationDeferred') {
+        return { label: 'Deferred', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
+    }
     if (state === 'DeletionStarted') {
         return { label: 'Deleting', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
     }
@@ -104,9 +146,15 @@ export function formatCorruptionState(state: string): { label: string; colorClas
     if (state === 'VerificationStarted') {
         return { label: 'Verifying', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
     }
+    if (state === 'ReverificationRequested') {
+        return { label: 'Reverifying', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
+    }
     if (state === 'RetryScheduled') {
         return { label: 'Retry Scheduled', colorClass: 'bg-orange-500/10 text-orange-400 border-orange-500/20' };
     }
+    if (state === 'DownloadRejected') {
+        return { label: 'Searching Again', colorClass: 'bg-blue-500/10 text-blue-400 border-blue-500/20' };
+    }
     if (state === 'DownloadTimeout') {
         return { label: 'Download Timeout', colorClass: 'bg-orange-500/10 text-orange-400 border-orange-500/20' };
     }
@@ -148,17 +196,17 @@ export function getEventColorClass(eventType: string): string {
     if (eventType === 'MaxRetriesReached') {
         return 'bg-red-500/20 border-red-500/30 text-red-400';
     }
-    if (eventType.endsWith('Failed') || eventType === 'RetryScheduled' || eventType === 'DownloadTimeout') {
+    if (eventType.endsWith('Failed') || eventType === 'RetryScheduled' || eventType === 'DownloadTimeout' || eventType === 'DownloadRejected') {
         return 'bg-orange-500/20 border-orange-500/30 text-orange-400';
     }
 
     // No replacement found (amber - can be retried)
-    if (eventType === 'SearchExhausted') {
+    if (eventType === 'SearchExhausted' || eventType === 'ScheduledResearch') {
         return 'bg-amber-500/20 border-amber-500/30 text-amber-400';
     }
 
-    // Stuck remediation (orange - needs attention)
-    if (eventType === 'StuckRemediation') {
+    // Stuck remediation or SLA breach (orange - needs attention)
+    if (eventType === 'StuckRemediation' || eventType === 'SLABreached') {
         return 'bg-orange-500/20 border-orange-500/30 text-orange-400';
     }
 
@@ -170,7 +218,12 @@ export function getEventColorClass(eventType: string): string {
     // Manual intervention required (purple - needs user attention)
     if (eventType === 'ImportBlocked' ||
         eventType === 'ManuallyRemoved' ||
-        eventType === 'DownloadIgnored') {
+        eventType === 'DownloadIgnored' ||
+        eventType === 'BudgetApprovalRequired' ||
+        eventType === 'LowConfidenceDetection' ||
+        eventType === 'QualityRegression' ||
+        eventType === 'AudioTrackMissing' ||
+        eventType === 'DeletionPlanChanged') {
         return 'bg-purple-500/20 border-purple-500/30 text-purple-400';
     }
 
@@ -198,6 +251,21 @@ export function getEventDescription(eventType: string, data?: Record<string, unk
     if (eventType === 'NotificationFailed' && data?.provider) {
         return `Notification failed via ${data.provider}`;
     }
+    if (eventType === 'DeletionFailed' && typeof data?.cause === 'string') {
+        return `File deletion failed: ${formatDeletionCause(data.cause)}`;
+    }
+    if (eventType === 'DownloadRejected' && data?.reason === 'protocol' && typeof data?.protocol === 'string') {
+        return `Grabbed a ${data.protocol} release the path doesn't accept - blocklisted, *arr is searching again`;
+    }
+    if (eventType === 'LowConfidenceDetection' && typeof data?.confidence === 'number' && typeof data?.min_confidence === 'number') {
+        return `Detection confidence ${data.confidence}% is below the path's ${data.min_confidence}% - retry to remediate, or ignore`;
+    }
+    if (eventType === 'RemediationDeferred' && typeof data?.rollout_daily_limit === 'number') {
+        return `Staged rollout: ${data.rollout_daily_limit} remediations per day reached - deferred to the next day`;
+    }
+    if (eventType === 'ScheduledResearch' && typeof data?.next_search_at === 'string') {
+        return `No copies yet - searching again on ${new Date(data.next_search_at).toLocaleDateString()}`;
+    }
     
     const descriptions: Record<string, string> = {
         'CorruptionDetected': 'Corruption Detected',
@@ -209,18 +277,27 @@ export function getEventDescription(eventType: string, data?: Record<string, unk
         'SearchCompleted': 'Replacement found, downloading',
         'SearchFailed': 'Search for replacement failed',
         'SearchExhausted': 'No copies found - try manual search in *arr',
+        'ScheduledResearch': 'No copies yet - searching again later',
         'StuckRemediation': 'Taking too long - check *arr queue or retry',
         'FileDetected': 'New file detected',
+        'ReverificationRequested': 'Reverification requested - checking the replacement again',
         'VerificationStarted': 'Verifying replacement file',
         'VerificationSuccess': 'New file verified - fixed!',
         'VerificationFailed': 'New file also has issues - trying again',
         'MaxRetriesReached': 'Maximum retries exhausted',
         'RetryScheduled': 'Retry scheduled',
         'DownloadTimeout': 'Download timed out',
+        'DownloadRejected': 'Download was corrupt - blocklisted, *arr is searching again',
         'CorruptionIgnored': 'Marked as ignored',
         'ImportBlocked': 'Import failed - check *arr Activity → Queue for errors',
         'ManuallyRemoved': 'Removed from queue - re-add in *arr or retry here',
         'DownloadIgnored': 'Ignored by user - unblock in *arr Activity → Queue',
+        'RemediationDeferred': 'Over the monthly data budget - deferred to next month',
+        'BudgetApprovalRequired': 'Over the monthly data budget - waiting for approval',
+        'LowConfidenceDetection': 'Detection not confident enough - retry to remediate, or ignore',
+        'QualityRegression': 'Replacement has a lower resolution than the original',
+        'AudioTrackMissing': 'Replacement lacks audio languages or channels of the original',
+        'DeletionPlanChanged': 'File changed in *arr before deletion - check it, then retry',
     };
     
     return descriptions[eventType] || eventType.replace(/([A-Z])/g, ' $1').trim();
@@ -396,6 +473,10 @@ export function getArrIcon(arrType: string): string {
 export function formatCronExpression(cron: string): string {
     if (!cron) return 'Invalid schedule';
 
+    // A CRON_TZ= prefix sets the timezone the times are read in
+    const tz = cron.trim().match(/^(?:CRON_)?TZ=(\S+)\s+(.+)$/);
+    if (tz) return `${formatCronExpression(tz[2])} (${tz[1]})`;
+
     const parts = cron.trim().split(/\s+/);
     if (parts.length < 5) return cron; // Return raw if not valid cron format
 
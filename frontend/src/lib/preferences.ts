@@ -0,0 +1,82 @@
+import { getPreferences, updatePreferences, type UIPreferences } from './api';
+import { TIMEZONE_STORAGE_KEY, getDateFormatPreset, setDateFormatPreset } from './dateFormat';
+
+// The server keeps the preferences; localStorage caches them so pages can
+// read them synchronously and the UI starts in the right state
+const PAGE_SIZE_KEY = 'healarr_page_size';
+const DEFAULT_FILTERS_KEY = 'healarr_default_filters';
+const THEME_KEY = 'healarr_theme';
+
+const DEFAULT_PAGE_SIZE = 50;
+
+export const getPageSize = (legacyKey?: string): number => {
+    const stored = localStorage.getItem(PAGE_SIZE_KEY) ?? (legacyKey ? localStorage.getItem(legacyKey) : null);
+    const size = stored ? parseInt(stored, 10) : NaN;
+    return size > 0 ? size : DEFAULT_PAGE_SIZE;
+};
+
+const getDefaultFilters = (): UIPreferences['default_filters'] => {
+    try {
+        return JSON.parse(localStorage.getItem(DEFAULT_FILTERS_KEY) ?? '{}');
+    } catch {
+        return {};
+    }
+};
+
+export const getDefaultFilter = (page: string, key: string): string | undefined => getDefaultFilters()[page]?.[key];
+
+const cache = (prefs: Partial<UIPreferences>) => {
+    if (prefs.page_size) localStorage.setItem(PAGE_SIZE_KEY, String(prefs.page_size));
+    if (prefs.timezone !== undefined) {
+        localStorage.setItem(TIMEZONE_STORAGE_KEY, prefs.timezone);
+        window.dispatchEvent(new CustomEvent('timezoneChange', { detail: prefs.timezone }));
+    }
+    if (prefs.default_filters) localStorage.setItem(DEFAULT_FILTERS_KEY, JSON.stringify(prefs.default_filters));
+    if (prefs.date_format && prefs.date_format !== getDateFormatPreset()) setDateFormatPreset(prefs.date_format);
+    if (prefs.theme && prefs.theme !== localStorage.getItem(THEME_KEY)) {
+        window.dispatchEvent(new CustomEvent('themeChange', { detail: prefs.theme }));
+    }
+};
+
+// savePreferences stores preferences on the server and applies them here.
+// Failures only cost the sync with other browsers, so they are not surfaced.
+export const savePreferences = async (update: Partial<UIPreferences>): Promise<void> => {
+    cache(update);
+    try {
+        await updatePreferences(update);
+    } catch (err) {
+        console.debug('Failed to save preferences', err);
+    }
+};
+
+// setDefaultFilter saves the filter a page opens with, or clears it.
+export const setDefaultFilter = (page: string, key: string, value: string | undefined): Promise<void> => {
+    const filters = getDefaultFilters();
+    const pageFilters = { ...filters[page] };
+    if (value === undefined) {
+        delete pageFilters[key];
+    } else {
+        pageFilters[key] = value;
+    }
+    return savePreferences({ default_filters: { ...filters, [page]: pageFilters } });
+};
+
+// syncPreferences loads the server's preferences after login. The first time,
+// this browser's choices are saved as the preferences instead.
+export const syncPreferences = async (): Promise<void> => {
+    try {
+        const { preferences, updated_at } = await getPreferences();
+        if (updated_at === null) {
+            const theme = localStorage.getItem(THEME_KEY);
+            await updatePreferences({
+                page_size: getPageSize('healarr_corruptions_limit'),
+                date_format: getDateFormatPreset(),
+                theme: theme === 'light' || theme === 'dark' ? theme : '',
+            });
+            return;
+        }
+        cache(preferences);
+    } catch (err) {
+        console.debug('Failed to load preferences', err);
+    }
+};
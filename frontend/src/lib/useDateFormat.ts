@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback } from 'react';
 import { 
   getDateFormatPreset, 
+  getTimezone,
   setDateFormatPreset as setPreset,
   formatTime,
   formatDate,
@@ -14,14 +15,22 @@ export type { DateFormatPreset } from './dateFormat';
 
 export const useDateFormat = () => {
   const [preset, setPresetState] = useState<DateFormatPreset>(getDateFormatPreset);
+  const [timezone, setTimezone] = useState<string>(getTimezone);
 
   useEffect(() => {
     const handleChange = (e: CustomEvent<DateFormatPreset>) => {
       setPresetState(e.detail);
     };
+    const handleTimezoneChange = (e: CustomEvent<string>) => {
+      setTimezone(e.detail);
+    };
     
     window.addEventListener('dateFormatChange', handleChange as EventListener);
-    return () => window.removeEventListener('dateFormatChange', handleChange as EventListener);
+    window.addEventListener('timezoneChange', handleTimezoneChange as EventListener);
+    return () => {
+      window.removeEventListener('dateFormatChange', handleChange as EventListener);
+      window.removeEventListener('timezoneChange', handleTimezoneChange as EventListener);
+    };
   }, []);
 
   const setDateFormatPreset = useCallback((newPreset: DateFormatPreset) => {
@@ -32,6 +41,8 @@ export const useDateFormat = () => {
   return {
     preset,
     setDateFormatPreset,
+    // Preferred timezone, empty for the browser's
+    timezone,
     // Time is always on top in table views
     formatTime,
     // Date is always below in table views (format respects preset)
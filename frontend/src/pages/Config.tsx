@@ -1,21 +1,22 @@
 import { useState, useEffect, useRef } from 'react';
 import { useLocation } from 'react-router-dom';
 import { motion, AnimatePresence } from 'framer-motion';
-import { Settings, ChevronDown, Pencil, Save, Copy, RefreshCw, Shield, Lock, Monitor, Globe, Database, Pause, Square, RotateCcw, Info, Wand2, Download, Upload, Play, PlayCircle, Wrench } from 'lucide-react';
+import { Settings, ChevronDown, Pencil, Save, Copy, RefreshCw, Shield, Lock, Monitor, Globe, Database, Pause, Square, RotateCcw, Info, Wand2, Download, Upload, Play, PlayCircle, Wrench, PauseCircle } from 'lucide-react';
 import { useDateFormat, type DateFormatPreset } from '../lib/useDateFormat';
+import { getPageSize, savePreferences } from '../lib/preferences';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import {
     getAPIKey, regenerateAPIKey, changePassword,
     getRuntimeConfig, updateSettings, restartServer, resetSetupWizard,
-    triggerScanAll, exportConfig, importConfig, downloadDatabaseBackup,
-    pauseAllScans, resumeAllScans, cancelAllScans,
+    triggerScanAll, exportConfig, importConfig, downloadDatabaseBackup, downloadRemediationExport,
+    pauseAllScans, resumeAllScans, cancelAllScans, pauseSystem,
     type ConfigExport
 } from '../lib/api';
 import clsx from 'clsx';
 import { useToast } from '../contexts/ToastContext';
 import ConfigWarningBanner from '../components/ConfigWarningBanner';
 import AboutSection from '../components/AboutSection';
-import { ArrServersSection, ScanPathsSection, SchedulesSection } from '../components/config';
+import { ArrServersSection, ScanPathsSection, PathGroupsSection, SchedulesSection, DeletedItemsSection, MaintenanceSection, BackupTargetsSection } from '../components/config';
 
 // Notifications Section - imported directly as it has its own complex structure
 import NotificationsSection from './config/NotificationsSection';
@@ -25,7 +26,23 @@ interface QuickActionsSectionProps {
     toast: ReturnType<typeof useToast>;
 }
 
+// Auto-resume choices for the automation pause, in minutes (0: until resumed)
+const PAUSE_DURATIONS = [
+    { minutes: 0, label: 'Until resumed' },
+    { minutes: 30, label: '30 minutes' },
+    { minutes: 60, label: '1 hour' },
+    { minutes: 120, label: '2 hours' },
+    { minutes: 240, label: '4 hours' },
+    { minutes: 480, label: '8 hours' },
+    { minutes: 1440, label: '24 hours' },
+];
+
 const QuickActionsSection = ({ toast }: QuickActionsSectionProps) => {
+    const queryClient = useQueryClient();
+    const [pauseReason, setPauseReason] = useState('');
+    const [pauseMinutes, setPauseMinutes] = useState(60);
+    const [pauseDetectionOnly, setPauseDetectionOnly] = useState(false);
+
     const scanAllMutation = useMutation({
         mutationFn: triggerScanAll,
         onSuccess: (data) => {
@@ -59,6 +76,24 @@ const QuickActionsSection = ({ toast }: QuickActionsSectionProps) => {
         }
     });
 
+    const pauseSystemMutation = useMutation({
+        mutationFn: () => pauseSystem({
+            reason: pauseReason.trim() || undefined,
+            detection_only: pauseDetectionOnly,
+            duration_minutes: pauseMinutes,
+        }),
+        onSuccess: () => {
+            toast.success('Automation paused');
+            setPauseReason('');
+            queryClient.invalidateQueries({ queryKey: ['systemPause'] });
+            queryClient.invalidateQueries({ queryKey: ['remediationQueue'] });
+        },
+        onError: (error: unknown) => {
+            const err = error as { response?: { data?: { error?: string } }; message?: string };
+            toast.error(`Failed to pause automation: ${err.response?.data?.error || err.message}`);
+        }
+    });
+
     const cancelAllMutation = useMutation({
         mutationFn: cancelAllScans,
         onSuccess: (data) => {
@@ -126,6 +161,54 @@ const QuickActionsSection = ({ toast }: QuickActionsSectionProps) => {
                         </button>
                     </div>
                 </div>
+
+                {/* Global pause for maintenance on the *arr stack or the storage array */}
+                <div className="flex items-center justify-between flex-wrap gap-4 mt-4 pt-4 border-t border-slate-200 dark:border-slate-800/50">
+                    <div className="flex items-center gap-3">
+                        <div className="p-2 rounded-lg bg-amber-500/10 border border-amber-500/20">
+                            <PauseCircle className="w-5 h-5 text-amber-400" />
+                        </div>
+                        <div>
+                            <h3 className="text-sm font-semibold text-slate-900 dark:text-white">Pause Automation</h3>
+                            <p className="text-xs text-slate-500">Hold remediation, verification and scheduled scans during maintenance</p>
+                        </div>
+                    </div>
+
+                    <div className="flex items-center gap-3 flex-wrap">
+                        <input
+                            type="text"
+                            value={pauseReason}
+                            onChange={(e) => setPauseReason(e.target.value)}
+                            placeholder="Reason (optional)"
+                            className="px-3 py-2 text-sm rounded-lg bg-slate-100 dark:bg-slate-800/50 border border-slate-200 dark:border-slate-700/50 text-slate-900 dark:text-white"
+                        />
+                        <select
+                            value={pauseMinutes}
+                            onChange={(e) => setPauseMinutes(Number(e.target.value))}
+                            className="px-3 py-2 text-sm rounded-lg bg-slate-100 dark:bg-slate-800/50 border border-slate-200 dark:border-slate-700/50 text-slate-900 dark:text-white cursor-pointer"
+                        >
+                            {PAUSE_DURATIONS.map(d => (
+                                <option key={d.minutes} value={d.minutes}>{d.label}</option>
+                            ))}
+                        </select>
+                        <label className="flex items-center gap-2 text-sm text-slate-600 dark:text-slate-400 cursor-pointer">
+                            <input
+                                type="checkbox"
+                                checked={pauseDetectionOnly}
+                                onChange={(e) => setPauseDetectionOnly(e.target.checked)}
+                            />
+                            Keep scanning
+                        </label>
+                        <button
+                            onClick={() => pauseSystemMutation.mutate()}
+                            disabled={pauseSystemMutation.isPending}
+                            className="flex items-center gap-2 px-4 py-2 bg-amber-500/10 hover:bg-amber-500/20 text-amber-400 rounded-lg transition-colors border border-amber-500/20 cursor-pointer disabled:opacity-50 disabled:cursor-not-allowed"
+                        >
+                            <PauseCircle className="w-4 h-4" />
+                            {pauseSystemMutation.isPending ? 'Pausing...' : 'Pause Automation'}
+                        </button>
+                    </div>
+                </div>
             </div>
         </motion.div>
     );
@@ -160,6 +243,16 @@ const DataManagementSection = ({ toast, queryClient }: DataManagementSectionProp
         }
     };
 
+    const handleExportRemediations = async () => {
+        try {
+            await downloadRemediationExport('csv');
+            toast.success('Remediation history exported successfully');
+        } catch (error: unknown) {
+            const err = error as { response?: { data?: { error?: string } }; message?: string };
+            toast.error(`Failed to export remediations: ${err.response?.data?.error || err.message}`);
+        }
+    };
+
     const handleImportClick = () => {
         fileInputRef.current?.click();
     };
@@ -246,6 +339,15 @@ const DataManagementSection = ({ toast, queryClient }: DataManagementSectionProp
                     {isDownloadingBackup ? 'Downloading...' : 'Download DB Backup'}
                 </button>
 
+                <button
+                    onClick={handleExportRemediations}
+                    title="Every file Healarr replaced, with quality before/after, dates and reason"
+                    className="flex items-center gap-2 px-4 py-2 bg-emerald-500/10 hover:bg-emerald-500/20 text-emerald-400 rounded-lg transition-colors border border-emerald-500/20 cursor-pointer"
+                >
+                    <Download className="w-4 h-4" />
+                    Export Remediations (CSV)
+                </button>
+
                 <input
                     type="file"
                     ref={fileInputRef}
@@ -745,7 +847,8 @@ const Config = () => {
     const queryClient = useQueryClient();
     const toast = useToast();
     const location = useLocation();
-    const { preset: dateFormatPreset, setDateFormatPreset } = useDateFormat();
+    const { preset: dateFormatPreset, setDateFormatPreset, timezone } = useDateFormat();
+    const [pageSize, setPageSize] = useState(() => getPageSize());
     const aboutSectionRef = useRef<HTMLDivElement>(null);
 
     // Collapsible state
@@ -802,9 +905,15 @@ const Config = () => {
             {/* Scan Paths Section */}
             <ScanPathsSection onScrollToDetectionTools={scrollToDetectionTools} />
 
+            {/* Path Groups Section */}
+            <PathGroupsSection />
+
             {/* Scheduled Scans Section */}
             <SchedulesSection />
 
+            {/* Recently Deleted Section (hidden while empty) */}
+            <DeletedItemsSection />
+
             {/* Notifications Section */}
             <NotificationsSection />
 
@@ -874,6 +983,7 @@ const Config = () => {
                                                         key={option.value}
                                                         onClick={() => {
                                                             setDateFormatPreset(option.value);
+                                                            savePreferences({ date_format: option.value });
                                                             toast.success(`Date format set to "${option.label}"`);
                                                         }}
                                                         className={clsx(
@@ -890,6 +1000,48 @@ const Config = () => {
                                                 ))}
                                             </div>
                                         </div>
+
+                                        <div className="bg-slate-100 dark:bg-slate-800/30 border border-slate-300 dark:border-slate-700/50 rounded-xl p-4 grid grid-cols-1 sm:grid-cols-2 gap-4">
+                                            <div>
+                                                <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">
+                                                    Timezone
+                                                </label>
+                                                <select
+                                                    value={timezone}
+                                                    onChange={(e) => {
+                                                        savePreferences({ timezone: e.target.value });
+                                                        toast.success(e.target.value ? `Timezone set to ${e.target.value}` : 'Using the browser\'s timezone');
+                                                    }}
+                                                    className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                                >
+                                                    <option value="">Browser ({Intl.DateTimeFormat().resolvedOptions().timeZone})</option>
+                                                    {Intl.supportedValuesOf('timeZone').map(tz => (
+                                                        <option key={tz} value={tz}>{tz}</option>
+                                                    ))}
+                                                </select>
+                                            </div>
+                                            <div>
+                                                <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">
+                                                    Rows Per Page
+                                                </label>
+                                                <select
+                                                    value={pageSize}
+                                                    onChange={(e) => {
+                                                        const size = parseInt(e.target.value, 10);
+                                                        setPageSize(size);
+                                                        savePreferences({ page_size: size });
+                                                    }}
+                                                    className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-blue-500"
+                                                >
+                                                    {[25, 50, 100, 250, 1000].map(size => (
+                                                        <option key={size} value={size}>{size}</option>
+                                                    ))}
+                                                </select>
+                                            </div>
+                                            <p className="sm:col-span-2 text-xs text-slate-600 dark:text-slate-400">
+                                                Display settings, the theme and default filters are saved on the server and follow you to every browser.
+                                            </p>
+                                        </div>
                                     </div>
 
                                     {/* Server Settings */}
@@ -925,6 +1077,12 @@ const Config = () => {
                                     {/* Data Management */}
                                     <DataManagementSection toast={toast} queryClient={queryClient} />
 
+                                    {/* Database Maintenance */}
+                                    <MaintenanceSection />
+
+                                    {/* Off-site Backups */}
+                                    <BackupTargetsSection />
+
                                     {/* Setup Wizard */}
                                     <SetupWizardResetSection toast={toast} />
                                 </div>
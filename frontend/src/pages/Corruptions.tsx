@@ -6,11 +6,13 @@ import DataGrid from '../components/ui/DataGrid';
 import RemediationJourney from '../components/RemediationJourney';
 import ConfirmDialog from '../components/ui/ConfirmDialog';
 import clsx from 'clsx';
-import { AlertTriangle, ArrowUpDown, Filter, RefreshCw, EyeOff, Trash2, X, AlertCircle, FolderOpen, Film, Tv } from 'lucide-react';
+import { AlertTriangle, ArrowUpDown, Bookmark, BookmarkCheck, Filter, RefreshCw, EyeOff, Trash2, X, AlertCircle, FolderOpen, Film, Tv, Shield } from 'lucide-react';
 import { formatCorruptionType, formatCorruptionState, formatBytes, formatDuration, getDownloadClientIcon, getArrIcon } from '../lib/formatters';
 import { useDateFormat } from '../lib/useDateFormat';
+import { getDefaultFilter, getPageSize, savePreferences, setDefaultFilter } from '../lib/preferences';
 import { useToast } from '../contexts/ToastContext';
 
+// Page size stored before it became a preference
 const LIMIT_STORAGE_KEY = 'healarr_corruptions_limit';
 
 const Corruptions = () => {
@@ -19,13 +21,11 @@ const Corruptions = () => {
     const [selectedIds, setSelectedIds] = useState<Set<string>>(new Set());
     const lastClickedIndex = useRef<number | null>(null);
     const [page, setPage] = useState(1);
-    const [limit, setLimit] = useState(() => {
-        const stored = localStorage.getItem(LIMIT_STORAGE_KEY);
-        return stored ? parseInt(stored, 10) : 50;
-    });
+    const [limit, setLimit] = useState(() => getPageSize(LIMIT_STORAGE_KEY));
     const [sortBy, setSortBy] = useState<string>('detected_at');
     const [sortOrder, setSortOrder] = useState<'asc' | 'desc'>('desc');
-    const [statusFilter, setStatusFilter] = useState<string>(() => searchParams.get('status') || 'action_required');
+    const [statusFilter, setStatusFilter] = useState<string>(() => searchParams.get('status') || getDefaultFilter('corruptions', 'status') || 'action_required');
+    const [defaultStatus, setDefaultStatus] = useState(() => getDefaultFilter('corruptions', 'status') ?? 'action_required');
     const pathIdFilter = searchParams.get('path_id') ? parseInt(searchParams.get('path_id')!, 10) : undefined;
     const [showDeleteConfirm, setShowDeleteConfirm] = useState(false);
     const [isDeleting, setIsDeleting] = useState(false);
@@ -33,11 +33,18 @@ const Corruptions = () => {
     const toast = useToast();
     const queryClient = useQueryClient();
 
-    // Handle limit changes with localStorage persistence
+    // Handle limit changes, saved as the page size preference
     const handleLimitChange = (newLimit: number) => {
         setLimit(newLimit);
         setPage(1);
-        localStorage.setItem(LIMIT_STORAGE_KEY, String(newLimit));
+        savePreferences({ page_size: newLimit });
+    };
+
+    // Open the page with the current status filter from now on
+    const saveDefaultStatus = () => {
+        setDefaultStatus(statusFilter);
+        setDefaultFilter('corruptions', 'status', statusFilter);
+        toast.success('Default filter saved');
     };
 
     // Clear path_id filter
@@ -203,6 +210,15 @@ const Corruptions = () => {
                         <option value="ignored">Ignored</option>
                         <option value="all">All</option>
                     </select>
+                    <button
+                        onClick={saveDefaultStatus}
+                        disabled={statusFilter === defaultStatus}
+                        className="p-1.5 mr-1 text-slate-400 hover:text-blue-400 rounded transition-colors cursor-pointer disabled:cursor-default disabled:text-blue-400"
+                        title={statusFilter === defaultStatus ? 'Default filter' : 'Open with this filter by default'}
+                        aria-label="Save as default filter"
+                    >
+                        {statusFilter === defaultStatus ? <BookmarkCheck className="w-4 h-4" /> : <Bookmark className="w-4 h-4" />}
+                    </button>
                 </div>
             </div>
 
@@ -395,6 +411,12 @@ const Corruptions = () => {
                                         <span className="text-xs text-slate-500 truncate" title={row.file_path}>
                                             {subtitle}
                                         </span>
+                                        {row.protected && (
+                                            <span className="mt-1 inline-flex w-fit items-center gap-1 text-xs text-blue-500 dark:text-blue-400" title="Protected: reported but never remediated automatically">
+                                                <Shield className="w-3 h-3" />
+                                                Protected
+                                            </span>
+                                        )}
                                         {row.last_error && (
                                             <div className="mt-1 flex items-start gap-1 text-xs text-red-400 bg-red-500/10 p-1 rounded border border-red-500/20">
                                                 <AlertTriangle className="w-3 h-3 mt-0.5 shrink-0" />
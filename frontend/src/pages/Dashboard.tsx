@@ -3,15 +3,17 @@ import { motion, AnimatePresence } from 'framer-motion';
 import { ShieldCheck, AlertOctagon, Loader2, X, Clock, AlertTriangle, EyeOff, CheckCircle2, FileSearch, TrendingUp, HandMetal, Play, ChevronDown, ScanSearch, PlayCircle, AlertCircle, ArrowRight, Music, Film } from 'lucide-react';
 import clsx from 'clsx';
 import { useQuery } from '@tanstack/react-query';
-import { getDashboardStats, getActiveScans, cancelScan, getScanPaths, triggerScan, triggerScanAll, getPathHealth, type ScanProgress, type ScanPath } from '../lib/api';
+import { getDashboardStats, getActiveScans, cancelScan, getScanPaths, triggerScan, triggerScanAll, getPathHealth, getRemediationQueue, type ScanProgress, type ScanPath } from '../lib/api';
 import type { PathHealth } from '../types/api';
 import { FolderOpen, FolderCheck, FolderX, FolderSearch, FolderMinus } from 'lucide-react';
 import ActivityChart from '../components/charts/ActivityChart';
 import TypeDistributionChart from '../components/charts/TypeDistributionChart';
+import CorruptionHeatmap from '../components/charts/CorruptionHeatmap';
 import { useWebSocket } from '../contexts/WebSocketProvider';
 import { useToast } from '../contexts/ToastContext';
 import { useNavigate } from 'react-router-dom';
 import ConfigWarningBanner from '../components/ConfigWarningBanner';
+import { useDateFormat } from '../lib/useDateFormat';
 
 const StatCard = ({ title, value, subtitle, icon: Icon, color, delay, onClick }: { title: string, value: string, subtitle?: string, icon: React.ElementType, color: string, delay: number, onClick?: () => void }) => (
     <motion.div
@@ -289,6 +291,72 @@ const ActiveScansTable = () => {
     );
 };
 
+// Remediation Queue Component - shows remediations held back by per-instance throttling
+const RemediationQueueSection = () => {
+    const { formatCompact } = useDateFormat();
+    const { data } = useQuery({
+        queryKey: ['remediationQueue'],
+        queryFn: getRemediationQueue,
+        refetchInterval: 30000,
+    });
+
+    const detectionOnly = data?.instances.some(inst => inst.detection_only) ?? false;
+    if (!data || (data.queue.length === 0 && !detectionOnly && !data.system_paused)) {
+        return null;
+    }
+
+    const limits = data.searches_per_hour > 0
+        ? `${data.max_concurrent} at a time, ${data.searches_per_hour} searches/hour per instance`
+        : `${data.max_concurrent} at a time per instance`;
+    const schedule = data.window ? `${limits}, between ${data.window}` : limits;
+
+    return (
+        <motion.div
+            initial={{ opacity: 0, y: 20 }}
+            animate={{ opacity: 1, y: 0 }}
+            transition={{ delay: 0.11 }}
+            className="rounded-2xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl p-6"
+        >
+            <div className="flex items-center justify-between mb-4">
+                <h2 className="text-lg font-semibold text-slate-900 dark:text-white">Remediation Queue</h2>
+                <span className="text-xs text-slate-500">{schedule}</span>
+            </div>
+            {data.system_paused && (
+                <p className="text-xs text-amber-500 mb-4">Automation is paused, queued remediations start once it is resumed</p>
+            )}
+            {data.window_opens_at && (
+                <p className="text-xs text-amber-500 mb-4">Outside the remediation window, queued remediations start at {formatCompact(data.window_opens_at)}</p>
+            )}
+            <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-3 mb-4">
+                {data.instances.filter(inst => inst.queued > 0 || inst.detection_only).map(inst => (
+                    <div key={inst.instance_id} className="p-4 rounded-xl border bg-slate-100 dark:bg-slate-800/30 border-slate-200 dark:border-slate-700/30">
+                        <p className="font-medium text-slate-900 dark:text-white">{inst.name || `Instance ${inst.instance_id}`}</p>
+                        <p className="text-xs text-slate-600 dark:text-slate-400">
+                            {inst.active} running · {inst.queued} queued · {inst.searches_in_window} searches this hour
+                        </p>
+                        {inst.detection_only && (
+                            <p className="text-xs text-red-500 mt-1">Instance unreachable - detection only, remediations resume when it recovers</p>
+                        )}
+                        {inst.next_slot_at && (
+                            <p className="text-xs text-amber-500 mt-1">Hourly limit reached, next search at {formatCompact(inst.next_slot_at)}</p>
+                        )}
+                    </div>
+                ))}
+            </div>
+            <ul className="divide-y divide-slate-200 dark:divide-slate-800/50 max-h-64 overflow-y-auto">
+                {data.queue.map(item => (
+                    <li key={item.corruption_id} className="py-2 flex items-center justify-between gap-4 text-sm">
+                        <span className="truncate text-slate-700 dark:text-slate-300" title={item.file_path}>
+                            #{item.position} {item.file_path.split('/').pop()}
+                        </span>
+                        <span className="shrink-0 text-xs text-slate-500">{item.instance_name || `Instance ${item.instance_id}`}</span>
+                    </li>
+                ))}
+            </ul>
+        </motion.div>
+    );
+};
+
 // Quick Scan Dropdown Component
 const QuickScanDropdown = () => {
     const [isOpen, setIsOpen] = useState(false);
@@ -637,6 +705,8 @@ const Dashboard = () => {
                 </div>
             </motion.div>
 
+            <RemediationQueueSection />
+
             {/* Media Type Breakdown - Only show if audio stats exist */}
             {stats?.audio_stats && (
                 <motion.div
@@ -803,6 +873,16 @@ const Dashboard = () => {
                     <TypeDistributionChart />
                 </motion.div>
             </div>
+
+            <motion.div
+                initial={{ opacity: 0, y: 20 }}
+                animate={{ opacity: 1, y: 0 }}
+                transition={{ delay: 0.6 }}
+                className="rounded-2xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl p-6"
+            >
+                <h2 className="text-xl font-semibold text-slate-900 dark:text-white mb-6">Corruption Heatmap</h2>
+                <CorruptionHeatmap />
+            </motion.div>
         </div>
     );
 };
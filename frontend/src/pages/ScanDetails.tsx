@@ -1,14 +1,15 @@
 import { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { useQuery, useQueryClient } from '@tanstack/react-query';
-import { ArrowLeft, FileCheck, FileX, Loader2, Filter, HardDrive, Clock, FolderOpen, AlertCircle, X, RefreshCw, ClockArrowDown, ExternalLink, Radio, SkipForward, ShieldAlert, HelpCircle } from 'lucide-react';
+import { ArrowLeft, FileCheck, FileX, Loader2, Filter, HardDrive, Clock, FolderOpen, AlertCircle, X, RefreshCw, ClockArrowDown, ExternalLink, Radio, SkipForward, ShieldAlert, HelpCircle, AlertTriangle } from 'lucide-react';
 import clsx from 'clsx';
 import { getScanDetails, getScanFiles, cancelScan, rescanPath, type ScanFile, type ScanProgress } from '../lib/api';
 import DataGrid from '../components/ui/DataGrid';
 import { useDateFormat } from '../lib/useDateFormat';
 import { useToast } from '../contexts/ToastContext';
 import { useWebSocket } from '../contexts/WebSocketProvider';
-import { formatBytes } from '../lib/formatters';
+import { formatBytes, formatDuration } from '../lib/formatters';
+import ScanComparison from '../components/ScanComparison';
 
 const ScanDetails = () => {
     const { id } = useParams();
@@ -144,13 +145,19 @@ const ScanDetails = () => {
                     </span>
                 );
             case 'corrupt':
-            case 'error':
                 return (
                     <span className="inline-flex items-center gap-1 px-2 py-0.5 rounded text-xs font-medium bg-red-500/20 text-red-400">
                         <FileX className="w-3 h-3" />
                         Corrupt
                     </span>
                 );
+            case 'error':
+                return (
+                    <span className="inline-flex items-center gap-1 px-2 py-0.5 rounded text-xs font-medium bg-rose-500/20 text-rose-400">
+                        <AlertTriangle className="w-3 h-3" />
+                        Error
+                    </span>
+                );
             case 'skipped':
                 return (
                     <span className="inline-flex items-center gap-1 px-2 py-0.5 rounded text-xs font-medium bg-amber-500/20 text-amber-400">
@@ -258,7 +265,7 @@ const ScanDetails = () => {
             </div>
 
             {/* Stats Cards - File Counts */}
-            <div className="grid grid-cols-2 md:grid-cols-5 gap-4">
+            <div className="grid grid-cols-2 md:grid-cols-3 lg:grid-cols-6 gap-4">
                 <div
                     onClick={() => { setStatusFilter('all'); setPage(1); }}
                     className={clsx(
@@ -363,6 +370,25 @@ const ScanDetails = () => {
                         </div>
                     </div>
                 </div>
+
+                <div
+                    onClick={() => { setStatusFilter('error'); setPage(1); }}
+                    className={clsx(
+                        "rounded-2xl border border-slate-200 dark:border-slate-800/50 bg-white/80 dark:bg-slate-900/40 backdrop-blur-xl p-5 cursor-pointer transition-all hover:scale-[1.02]",
+                        statusFilter === 'error' && "ring-2 ring-rose-500/50"
+                    )}
+                    title="Files whose health check could not complete (detector failed or timed out)"
+                >
+                    <div className="flex items-center gap-3">
+                        <div className="p-2.5 rounded-xl bg-rose-500/10 border border-rose-500/20">
+                            <AlertTriangle className="w-5 h-5 text-rose-400" />
+                        </div>
+                        <div>
+                            <p className="text-2xl font-bold text-slate-900 dark:text-white">{scanDetails.error_files ?? 0}</p>
+                            <p className="text-xs text-slate-600 dark:text-slate-400">Errors</p>
+                        </div>
+                    </div>
+                </div>
             </div>
 
             {/* Stats Cards - Time Info */}
@@ -434,6 +460,9 @@ const ScanDetails = () => {
                 </div>
             )}
 
+            {/* Changes since the previous scan of the path */}
+            {scanDetails.status === 'completed' && <ScanComparison scanId={scanId} />}
+
             {/* Files Table */}
             <div className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/50 overflow-hidden">
                 <div className="p-4 border-b border-slate-200 dark:border-slate-800 flex items-center justify-between">
@@ -454,6 +483,7 @@ const ScanDetails = () => {
                                 <option value="corrupt" className="bg-slate-100 dark:bg-slate-800">Corrupt Only</option>
                                 <option value="skipped" className="bg-slate-100 dark:bg-slate-800">Skipped Only</option>
                                 <option value="inaccessible" className="bg-slate-100 dark:bg-slate-800">Inaccessible Only</option>
+                                <option value="error" className="bg-slate-100 dark:bg-slate-800">Errors Only</option>
                             </select>
                         </div>
                     )}
@@ -519,6 +549,7 @@ const ScanDetails = () => {
                                                             <span className="font-medium">{row.corruption_type === 'RecentlyModified' ? 'Recently Modified' :
                                                                 row.corruption_type === 'SizeChanging' ? 'Size Changing' :
                                                                 row.corruption_type === 'AlreadyProcessing' ? 'Already Processing' :
+                                                                row.corruption_type === 'NoConsensus' ? 'No Consensus' :
                                                                 row.corruption_type}: </span>
                                                         )}
                                                         <span className="line-clamp-2">{row.error_details || 'File skipped - will be checked on next scan'}</span>
@@ -541,6 +572,25 @@ const ScanDetails = () => {
                                     ),
                                     mobileLabel: 'Path',
                                 },
+                                {
+                                    header: 'Check',
+                                    accessorKey: (row: ScanFile) => (
+                                        row.duration_ms === null ? (
+                                            <span className="text-slate-400">-</span>
+                                        ) : (
+                                            <div className="flex flex-col text-sm">
+                                                <span className="text-slate-600 dark:text-slate-400">
+                                                    {row.duration_ms < 1000 ? `${row.duration_ms}ms` : formatDuration(row.duration_ms / 1000)}
+                                                </span>
+                                                {row.detection_tool && (
+                                                    <span className="text-xs text-slate-500">{row.detection_tool}</span>
+                                                )}
+                                            </div>
+                                        )
+                                    ),
+                                    className: 'w-24',
+                                    mobileLabel: 'Check',
+                                },
                                 {
                                     header: 'Size',
                                     accessorKey: (row: ScanFile) => (
@@ -5,17 +5,16 @@ import clsx from 'clsx';
 import { useState } from 'react';
 import { ArrowUpDown, X, RefreshCw, Loader2 } from 'lucide-react';
 import { useDateFormat } from '../lib/useDateFormat';
+import { getPageSize, savePreferences } from '../lib/preferences';
 import { useToast } from '../contexts/ToastContext';
 import { useNavigate } from 'react-router-dom';
 
+// Page size stored before it became a preference
 const LIMIT_STORAGE_KEY = 'healarr_scans_limit';
 
 const Scans = () => {
     const [page, setPage] = useState(1);
-    const [limit, setLimit] = useState(() => {
-        const stored = localStorage.getItem(LIMIT_STORAGE_KEY);
-        return stored ? parseInt(stored, 10) : 50;
-    });
+    const [limit, setLimit] = useState(() => getPageSize(LIMIT_STORAGE_KEY));
     const [sortBy, setSortBy] = useState<string>('started_at');
     const [sortOrder, setSortOrder] = useState<'asc' | 'desc'>('desc');
     const [loadingAction, setLoadingAction] = useState<number | null>(null);
@@ -24,11 +23,11 @@ const Scans = () => {
     const toast = useToast();
     const queryClient = useQueryClient();
 
-    // Handle limit changes with localStorage persistence
+    // Handle limit changes, saved as the page size preference
     const handleLimitChange = (newLimit: number) => {
         setLimit(newLimit);
         setPage(1);
-        localStorage.setItem(LIMIT_STORAGE_KEY, String(newLimit));
+        savePreferences({ page_size: newLimit });
     };
 
     const { data, isLoading } = useQuery({
@@ -0,0 +1,137 @@
+import { useQuery } from '@tanstack/react-query';
+import { CheckCircle, AlertTriangle, XCircle, HelpCircle, Activity, FileCheck } from 'lucide-react';
+import clsx from 'clsx';
+import { getPublicDashboard, type PublicDashboard } from '../lib/api';
+import { formatDateTime } from '../lib/dateFormat';
+
+type HealthStatus = PublicDashboard['status'];
+
+const statusStyles: Record<HealthStatus, { label: string; className: string; icon: typeof CheckCircle }> = {
+    healthy: { label: 'Healthy', className: 'text-green-600 dark:text-green-400 bg-green-500/10 border-green-500/30', icon: CheckCircle },
+    warning: { label: 'Needs attention', className: 'text-amber-600 dark:text-amber-400 bg-amber-500/10 border-amber-500/30', icon: AlertTriangle },
+    critical: { label: 'Critical', className: 'text-red-600 dark:text-red-400 bg-red-500/10 border-red-500/30', icon: XCircle },
+    unknown: { label: 'Not scanned yet', className: 'text-slate-600 dark:text-slate-400 bg-slate-500/10 border-slate-500/30', icon: HelpCircle },
+    disabled: { label: 'Disabled', className: 'text-slate-500 bg-slate-500/10 border-slate-500/30', icon: HelpCircle },
+};
+
+const StatusBadge = ({ status }: { status: HealthStatus }) => {
+    const style = statusStyles[status] ?? statusStyles.unknown;
+    const Icon = style.icon;
+    return (
+        <span className={clsx('inline-flex items-center gap-1.5 px-2.5 py-1 rounded-full border text-xs font-medium', style.className)}>
+            <Icon className="w-3.5 h-3.5" />
+            {style.label}
+        </span>
+    );
+};
+
+// Read-only status page for wall displays. Served without login when HEALARR_PUBLIC_DASHBOARD is enabled.
+const Status = () => {
+    const { data, isLoading, error } = useQuery({
+        queryKey: ['publicDashboard'],
+        queryFn: getPublicDashboard,
+        refetchInterval: 30000,
+        retry: false,
+    });
+
+    if (isLoading) {
+        return (
+            <div className="min-h-screen bg-slate-50 dark:bg-slate-950 flex items-center justify-center">
+                <div className="w-8 h-8 border-2 border-green-500/30 border-t-green-500 rounded-full animate-spin" />
+            </div>
+        );
+    }
+
+    if (error || !data) {
+        return (
+            <div className="min-h-screen bg-slate-50 dark:bg-slate-950 flex items-center justify-center p-6">
+                <div className="max-w-md text-center space-y-2">
+                    <h1 className="text-xl font-semibold text-slate-900 dark:text-white">Status page unavailable</h1>
+                    <p className="text-sm text-slate-500 dark:text-slate-400">
+                        The public dashboard is disabled. Set <code>HEALARR_PUBLIC_DASHBOARD=true</code> to enable it.
+                    </p>
+                </div>
+            </div>
+        );
+    }
+
+    const counters = [
+        { label: 'Pending', value: data.corruptions.pending },
+        { label: 'In progress', value: data.corruptions.in_progress },
+        { label: 'Resolved', value: data.corruptions.resolved },
+        { label: 'Failed', value: data.corruptions.failed },
+        { label: 'Success rate', value: `${data.corruptions.success_rate}%` },
+    ];
+
+    return (
+        <div className="min-h-screen bg-slate-50 dark:bg-slate-950 p-6">
+            <div className="max-w-5xl mx-auto space-y-6">
+                <header className="flex flex-wrap items-center justify-between gap-4">
+                    <div className="flex items-center gap-3">
+                        <h1 className="text-2xl font-bold text-slate-900 dark:text-white">Healarr</h1>
+                        <StatusBadge status={data.status} />
+                    </div>
+                    <div className="flex items-center gap-4 text-xs text-slate-500 dark:text-slate-400">
+                        {data.active_scans > 0 && (
+                            <span className="inline-flex items-center gap-1.5">
+                                <Activity className="w-3.5 h-3.5 animate-pulse text-blue-500" />
+                                {data.active_scans} scan{data.active_scans === 1 ? '' : 's'} running
+                            </span>
+                        )}
+                        <span>Updated {formatDateTime(data.generated_at, 'time')}</span>
+                    </div>
+                </header>
+
+                <section className="grid grid-cols-2 md:grid-cols-5 gap-3">
+                    {counters.map((c) => (
+                        <div key={c.label} className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/60 p-4">
+                            <div className="text-xs text-slate-500 dark:text-slate-400">{c.label}</div>
+                            <div className="text-2xl font-semibold text-slate-900 dark:text-white">{c.value}</div>
+                        </div>
+                    ))}
+                </section>
+
+                <section className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/60">
+                    <h2 className="px-4 py-3 border-b border-slate-200 dark:border-slate-800 font-semibold text-slate-900 dark:text-white">Library health</h2>
+                    {data.libraries.length === 0 ? (
+                        <p className="p-4 text-sm text-slate-500">No libraries configured.</p>
+                    ) : (
+                        <ul className="divide-y divide-slate-200 dark:divide-slate-800">
+                            {data.libraries.map((lib, idx) => (
+                                <li key={`${lib.name}-${idx}`} className="flex items-center justify-between gap-4 px-4 py-3">
+                                    <div>
+                                        <div className="font-medium text-slate-900 dark:text-white">{lib.name}</div>
+                                        <div className="text-xs text-slate-500 dark:text-slate-400">
+                                            {lib.last_scan_time ? `Last scan ${formatDateTime(lib.last_scan_time, 'compact')}` : 'Never scanned'}
+                                            {lib.active_corruptions > 0 && ` · ${lib.active_corruptions} active`}
+                                        </div>
+                                    </div>
+                                    <StatusBadge status={lib.status} />
+                                </li>
+                            ))}
+                        </ul>
+                    )}
+                </section>
+
+                <section className="rounded-xl border border-slate-200 dark:border-slate-800 bg-white dark:bg-slate-900/60">
+                    <h2 className="px-4 py-3 border-b border-slate-200 dark:border-slate-800 font-semibold text-slate-900 dark:text-white">Recent remediations</h2>
+                    {data.recent_remediations.length === 0 ? (
+                        <p className="p-4 text-sm text-slate-500">No files have been replaced yet.</p>
+                    ) : (
+                        <ul className="divide-y divide-slate-200 dark:divide-slate-800">
+                            {data.recent_remediations.map((r, idx) => (
+                                <li key={`${r.file_name}-${idx}`} className="flex items-center gap-3 px-4 py-2.5">
+                                    <FileCheck className="w-4 h-4 text-green-500 shrink-0" />
+                                    <span className="text-sm text-slate-700 dark:text-slate-300 truncate flex-1" title={r.file_name}>{r.file_name}</span>
+                                    <span className="text-xs text-slate-500 dark:text-slate-400 shrink-0">{formatDateTime(r.completed_at, 'compact')}</span>
+                                </li>
+                            ))}
+                        </ul>
+                    )}
+                </section>
+            </div>
+        </div>
+    );
+};
+
+export default Status;
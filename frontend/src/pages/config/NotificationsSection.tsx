@@ -1,11 +1,11 @@
 import { useState } from 'react';
 import { motion, AnimatePresence } from 'framer-motion';
-import { Bell, Plus, Trash2, ChevronDown, Clock, Send, CheckCircle2, AlertCircle, History, Pencil, X } from 'lucide-react';
+import { Bell, Plus, Trash2, ChevronDown, Clock, Send, CheckCircle2, AlertCircle, History, Pencil, X, Languages } from 'lucide-react';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import {
     getNotifications, createNotification, updateNotification, deleteNotification,
-    testNotification, getNotificationEvents, getNotificationLog,
-    type NotificationConfig, type NotificationLogEntry
+    testNotification, getNotificationEvents, getNotificationLog, getPathGroups,
+    NOTIFICATION_LOCALES, type NotificationConfig, type NotificationLogEntry
 } from '../../lib/api';
 import { formatDistanceToNow } from '../../lib/formatters';
 import { PROVIDER_CONFIGS, getProviderLabel } from '../../lib/notificationProviders';
@@ -20,8 +20,10 @@ interface NotificationFormData {
     provider_type: string;
     config: Record<string, unknown>;
     events: string[];
+    group_ids: number[];
     enabled: boolean;
     throttle_seconds: number;
+    locale: string;
 }
 
 const defaultFormData: NotificationFormData = {
@@ -29,8 +31,10 @@ const defaultFormData: NotificationFormData = {
     provider_type: '',
     config: {},
     events: ['CorruptionDetected', 'ScanComplete'],
+    group_ids: [],
     enabled: true,
     throttle_seconds: 300,
+    locale: '',
 };
 
 const NotificationsSection = () => {
@@ -64,6 +68,11 @@ const NotificationsSection = () => {
         queryFn: getNotificationEvents,
     });
 
+    const { data: pathGroups = [] } = useQuery({
+        queryKey: ['pathGroups'],
+        queryFn: getPathGroups,
+    });
+
     const { data: logEntries, isLoading: isLogLoading } = useQuery({
         queryKey: ['notificationLog', viewingLogId],
         queryFn: () => getNotificationLog(viewingLogId!, 20),
@@ -140,8 +149,10 @@ const NotificationsSection = () => {
             provider_type: notification.provider_type,
             config: notification.config || {},
             events: notification.events || [],
+            group_ids: notification.group_ids || [],
             enabled: notification.enabled,
             throttle_seconds: notification.throttle_seconds,
+            locale: notification.locale || '',
         });
         setEditingId(notification.id!);
         setIsAddExpanded(true);
@@ -163,12 +174,14 @@ const NotificationsSection = () => {
         setTestResult(null);
         try {
             const result = await testNotification({
+                id: editingId ?? undefined,
                 name: formData.name || `${getProviderLabel(formData.provider_type)} Test`,
                 provider_type: formData.provider_type,
                 config: formData.config,
                 events: formData.events,
                 enabled: true,
                 throttle_seconds: 0,
+                locale: formData.locale,
             });
             setTestResult(result);
             if (result.success) {
@@ -197,8 +210,10 @@ const NotificationsSection = () => {
             provider_type: formData.provider_type,
             config: formData.config,
             events: formData.events,
+            group_ids: formData.group_ids,
             enabled: formData.enabled,
             throttle_seconds: formData.throttle_seconds,
+            locale: formData.locale,
         };
 
         if (editingId) {
@@ -317,6 +332,61 @@ const NotificationsSection = () => {
                                                     Minimum seconds between notifications (0 = no throttling)
                                                 </p>
                                             </div>
+                                            <div>
+                                                <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2 flex items-center gap-2">
+                                                    <Languages className="w-4 h-4" />
+                                                    Language
+                                                </label>
+                                                <select
+                                                    value={formData.locale}
+                                                    onChange={(e) => setFormData(prev => ({ ...prev, locale: e.target.value }))}
+                                                    className="w-full px-3 py-2 bg-white dark:bg-slate-900 border border-slate-300 dark:border-slate-700 rounded-lg text-slate-900 dark:text-white focus:ring-2 focus:ring-pink-500"
+                                                >
+                                                    <option value="">Server default (HEALARR_LOCALE)</option>
+                                                    {NOTIFICATION_LOCALES.map(locale => (
+                                                        <option key={locale.code} value={locale.code}>{locale.label}</option>
+                                                    ))}
+                                                </select>
+                                                <p className="text-xs text-slate-500 mt-1">
+                                                    Language of this channel's messages
+                                                </p>
+                                            </div>
+                                            {pathGroups.length > 0 && (
+                                                <div>
+                                                    <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">
+                                                        Path Groups
+                                                    </label>
+                                                    <div className="flex flex-wrap gap-2">
+                                                        {pathGroups.map(group => {
+                                                            const selected = formData.group_ids.includes(group.id);
+                                                            return (
+                                                                <button
+                                                                    key={group.id}
+                                                                    type="button"
+                                                                    onClick={() => setFormData(prev => ({
+                                                                        ...prev,
+                                                                        group_ids: selected
+                                                                            ? prev.group_ids.filter(id => id !== group.id)
+                                                                            : [...prev.group_ids, group.id]
+                                                                    }))}
+                                                                    className={clsx(
+                                                                        "px-3 py-1.5 rounded-lg text-xs font-medium border transition-colors cursor-pointer",
+                                                                        selected
+                                                                            ? "bg-pink-500/10 text-pink-500 border-pink-500/30"
+                                                                            : "bg-white dark:bg-slate-900 text-slate-600 dark:text-slate-400 border-slate-300 dark:border-slate-700"
+                                                                    )}
+                                                                    aria-pressed={selected}
+                                                                >
+                                                                    {group.name}
+                                                                </button>
+                                                            );
+                                                        })}
+                                                    </div>
+                                                    <p className="text-xs text-slate-500 mt-1">
+                                                        Only events of scan paths in these groups (none selected = all paths)
+                                                    </p>
+                                                </div>
+                                            )}
                                         </div>
                                     )}
 
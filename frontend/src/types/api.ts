@@ -19,7 +19,7 @@ export interface DashboardStats {
     ignored_corruptions: number;
     in_progress_corruptions: number;
     failed_corruptions: number;      // *Failed states
-    manual_intervention_corruptions: number; // ImportBlocked or ManuallyRemoved - requires user action in *arr
+    manual_intervention_corruptions: number; // ImportBlocked, ManuallyRemoved, BudgetApprovalRequired, LowConfidenceDetection, QualityRegression, AudioTrackMissing or DeletionPlanChanged - requires user action
     successful_remediations: number;
     active_scans: number;
     total_scans: number;
@@ -33,6 +33,14 @@ export interface DashboardStats {
     // Media type breakdown
     video_stats?: MediaTypeStats;    // Video file corruption stats
     audio_stats?: MediaTypeStats;    // Audio/music file corruption stats
+    sla?: SLAStatus;                 // Present when HEALARR_RESOLUTION_SLA is set
+}
+
+export interface SLAStatus {
+    sla_hours: number;
+    breached_unresolved: number;     // Unresolved corruptions currently past the SLA
+    breaches_total: number;          // SLABreached events raised so far
+    oldest_unresolved_hours: number;
 }
 
 export interface Corruption {
@@ -45,6 +53,8 @@ export interface Corruption {
     last_updated_at: string;
     corruption_type: string;
     path_id?: number;
+    protected?: boolean;                   // File or media item is protected from remediation
+    tags?: string[];                       // Triage tags, lower-case
 
     // Enriched data from event_data (optional - may not be present for older entries)
     file_size?: number;                    // Original corrupt file size
@@ -73,6 +83,7 @@ export interface Corruption {
     download_client?: string;              // "SABnzbd", "qBittorrent", etc.
     indexer?: string;                      // "NZBgeek", "1337x", etc.
     download_time_left?: string;           // Estimated time remaining
+    download_estimated_completion?: string; // RFC3339 time the client expects to finish
 }
 
 export interface Remediation {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/mescon/Healarr/internal/i18n"
 	"github.com/mescon/Healarr/internal/logger"
 )
 
@@ -17,16 +18,63 @@ const (
 	ErrMsgServiceUnavailable  = "Service unavailable"
 	ErrMsgInternalError       = "Internal server error"
 	ErrMsgScanNotFound        = "Scan not found"
-	ErrMsgNoIDsProvided       = "No IDs provided"
 	ErrMsgInvalidID           = "Invalid ID"
 )
 
+// Error codes of API error responses, stable across locales so clients can
+// tell errors apart without parsing the message.
+const (
+	ErrCodeDatabase           = "database_error"
+	ErrCodeAuthentication     = "authentication_error"
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeServiceUnavailable = "service_unavailable"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeNoIDs              = "no_ids_provided"
+	ErrCodeInvalidID          = "invalid_id"
+)
+
+// errorCodes are the codes of the standard error messages.
+var errorCodes = map[string]string{
+	ErrMsgDatabaseError:       ErrCodeDatabase,
+	ErrMsgAuthenticationError: ErrCodeAuthentication,
+	ErrMsgInvalidRequest:      ErrCodeInvalidRequest,
+	ErrMsgNotFound:            ErrCodeNotFound,
+	ErrMsgServiceUnavailable:  ErrCodeServiceUnavailable,
+	ErrMsgInternalError:       ErrCodeInternal,
+	ErrMsgScanNotFound:        ErrCodeNotFound,
+	ErrMsgInvalidID:           ErrCodeInvalidID,
+}
+
+// requestLocale returns the locale of the messages in a response: the lang
+// query parameter, else the first supported language of Accept-Language,
+// else HEALARR_LOCALE.
+func requestLocale(c *gin.Context) i18n.Printer {
+	if c.Request == nil {
+		return i18n.For("")
+	}
+	if lang := c.Query("lang"); lang != "" {
+		return i18n.For(lang)
+	}
+	return i18n.For(i18n.FromAcceptLanguage(c.GetHeader("Accept-Language")))
+}
+
+// respondError sends one of the standard error messages, translated, with
+// its code.
+func respondError(c *gin.Context, status int, msg string) {
+	code := errorCodes[msg]
+	if code == "" {
+		code = ErrCodeInternal
+	}
+	c.JSON(status, gin.H{"error": requestLocale(c).T(msg), "code": code})
+}
+
 // respondWithError sends a JSON error response and logs the actual error
 func respondWithError(c *gin.Context, status int, publicMsg string, err error) {
 	if err != nil {
 		logger.Debugf("%s: %v", publicMsg, err)
 	}
-	c.JSON(status, gin.H{"error": publicMsg})
+	respondError(c, status, publicMsg)
 }
 
 // respondDatabaseError handles database errors consistently
@@ -39,22 +87,28 @@ func respondAuthError(c *gin.Context, err error) {
 	respondWithError(c, http.StatusInternalServerError, ErrMsgAuthenticationError, err)
 }
 
-// respondBadRequest handles bad request errors, optionally exposing the error message
+// respondBadRequest sends a problem response for a bad request, optionally
+// exposing the error message as its detail.
 // Use exposeError=true only for validation errors safe to show users
 func respondBadRequest(c *gin.Context, err error, exposeError bool) {
 	if exposeError && err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondProblem(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	respondWithError(c, http.StatusBadRequest, ErrMsgInvalidRequest, err)
+	if err != nil {
+		logger.Debugf("%s: %v", ErrMsgInvalidRequest, err)
+	}
+	respondProblem(c, http.StatusBadRequest, "")
 }
 
 // respondNotFound handles not found errors
 func respondNotFound(c *gin.Context, resource string) {
-	c.JSON(http.StatusNotFound, gin.H{"error": resource + " not found"})
+	tr := requestLocale(c)
+	c.JSON(http.StatusNotFound, gin.H{"error": tr.Sprintf("%s not found", tr.T(resource)), "code": ErrCodeNotFound})
 }
 
 // respondServiceUnavailable handles service unavailable errors
 func respondServiceUnavailable(c *gin.Context, service string) {
-	c.JSON(http.StatusServiceUnavailable, gin.H{"error": service + " not available"})
+	tr := requestLocale(c)
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": tr.Sprintf("%s not available", tr.T(service)), "code": ErrCodeServiceUnavailable})
 }
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/i18n"
+)
+
+func TestErrorResponses_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/missing", func(c *gin.Context) { respondNotFound(c, "Scan path") })
+	r.GET("/unavailable", func(c *gin.Context) { respondServiceUnavailable(c, "Notification service") })
+	r.GET("/invalid", func(c *gin.Context) { respondError(c, http.StatusBadRequest, ErrMsgInvalidID) })
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		wantStatus     int
+		wantError      string
+		wantCode       string
+	}{
+		{"english by default", "/missing", "", http.StatusNotFound, "Scan path not found", ErrCodeNotFound},
+		{"accept-language", "/missing", "de-DE,de;q=0.9,en;q=0.8", http.StatusNotFound, "Scanpfad nicht gefunden", ErrCodeNotFound},
+		{"lang query wins", "/unavailable?lang=fr", "de", http.StatusServiceUnavailable, "Service de notification indisponible", ErrCodeServiceUnavailable},
+		{"unsupported language", "/invalid", "it", http.StatusBadRequest, "Invalid ID", ErrCodeInvalidID},
+		{"spanish", "/invalid?lang=es", "", http.StatusBadRequest, "ID no válido", ErrCodeInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantError, body["error"])
+			assert.Equal(t, tt.wantCode, body["code"])
+		})
+	}
+}
+
+func TestErrorResponses_DefaultLocale(t *testing.T) {
+	require.NoError(t, i18n.SetDefault(i18n.Spanish))
+	t.Cleanup(func() { _ = i18n.SetDefault(i18n.English) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/db", func(c *gin.Context) { respondDatabaseError(c, nil) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/db", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "Error de base de datos", "code": "database_error"}`, w.Body.String())
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/metrics"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// alertThresholds returns the thresholds of the recommended alerts, with the
+// configured time after which a scan is overdue.
+func alertThresholds() metrics.AlertThresholds {
+	t := metrics.DefaultAlertThresholds()
+	t.ScanOverdueAfter = config.Get().AlertScanOverdue
+	return t
+}
+
+// getAlerts evaluates the recommended Prometheus alerts for setups without
+// Prometheus: the remediation failure rate, open *arr circuit breakers and
+// overdue scheduled scans.
+// GET /api/alerts
+func (s *RESTServer) getAlerts(c *gin.Context) {
+	breakers, _ := s.arrClient.(services.CircuitBreakerSource)
+	alerts, err := services.EvaluateAlerts(c.Request.Context(), s.reader(), breakers, alertThresholds(), time.Now())
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	firing := 0
+	for _, alert := range alerts {
+		if alert.Firing {
+			firing++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"firing": firing, "alerts": alerts})
+}
+
+// getAlertRules returns the recommended Prometheus alerting rules as a rule
+// file, for the configured thresholds.
+// GET /api/alerts/rules
+func (s *RESTServer) getAlertRules(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", metrics.AlertRulesYAML(alertThresholds()))
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/metrics"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestAlerts(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	cfg := config.NewTestConfig()
+	cfg.AlertScanOverdue = 36 * time.Hour
+	config.SetForTesting(cfg)
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, enabled, created_at) VALUES (1, '/tv', '/tv', 1, datetime('now', '-2 days'));
+		INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled) VALUES (1, '0 3 * * *', 1);
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/alerts", s.getAlerts)
+	r.GET("/alerts/rules", s.getAlertRules)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/alerts", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Firing int                    `json:"firing"`
+		Alerts []services.AlertStatus `json:"alerts"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Firing)
+	require.Len(t, resp.Alerts, 3)
+	for _, a := range resp.Alerts {
+		assert.Equal(t, a.Alert == metrics.AlertScanOverdue, a.Firing, a.Alert)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/alerts/rules", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/yaml")
+	assert.Contains(t, w.Body.String(), "time() - healarr_last_scan_completed_timestamp_seconds > 129600")
+}
@@ -5,21 +5,27 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/services"
 )
 
 // errInvalidURLScheme is returned when a URL has an invalid scheme.
 var errInvalidURLScheme = errors.New("only http and https schemes are allowed")
 
-// formatInvalidURLError formats an error message for invalid URL responses.
-func formatInvalidURLError(err error) string {
-	return fmt.Sprintf("Invalid URL: %v", err)
+// invalidURLField is the field error of a url that failed validateArrURL,
+// in the language of the request.
+func invalidURLField(c *gin.Context, err error) FieldError {
+	return FieldError{Field: "url", Rule: "url", Message: requestLocale(c).Sprintf("Invalid URL: %v", err)}
 }
 
 // validateArrURL validates that a URL is safe to use for *arr API requests.
@@ -53,7 +59,13 @@ func validateArrURL(rawURL string) error {
 }
 
 func (s *RESTServer) getArrInstances(c *gin.Context) {
-	rows, err := s.db.Query("SELECT id, name, type, url, api_key, enabled FROM arr_instances")
+	// Usage today shows how close each instance is to its daily request budget
+	rows, err := s.db.Query(`
+		SELECT i.id, i.name, i.type, i.url, i.api_key, i.enabled, i.daily_request_budget,
+			COALESCE(u.requests, 0), COALESCE(u.deferred, 0)
+		FROM arr_instances i
+		LEFT JOIN arr_request_usage u ON u.instance_id = i.id AND u.day = ?
+		WHERE i.deleted_at IS NULL`, time.Now().UTC().Format("2006-01-02"))
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -65,23 +77,29 @@ func (s *RESTServer) getArrInstances(c *gin.Context) {
 		var id int
 		var name, arrType, url, apiKey string
 		var enabled bool
-		if err := rows.Scan(&id, &name, &arrType, &url, &apiKey, &enabled); err != nil {
+		var budget, requestsToday, deferredToday int
+		if err := rows.Scan(&id, &name, &arrType, &url, &apiKey, &enabled, &budget, &requestsToday, &deferredToday); err != nil {
 			logger.Warnf("Failed to scan arr_instances row: %v", err)
 			continue
 		}
-		// Decrypt API key for display
+		// The API key is masked; sending the mask back on update keeps it
 		decryptedKey, err := crypto.Decrypt(apiKey)
+		maskedKey := redact.Value(decryptedKey)
 		if err != nil {
 			logger.Errorf("Failed to decrypt API key for instance %d: %v", id, err)
-			decryptedKey = "[DECRYPTION_ERROR]"
+			maskedKey = "[DECRYPTION_ERROR]"
 		}
 		instances = append(instances, map[string]interface{}{
 			"id":      id,
 			"name":    name,
 			"type":    arrType,
 			"url":     url,
-			"api_key": decryptedKey,
+			"api_key": maskedKey,
 			"enabled": enabled,
+
+			"daily_request_budget": budget,
+			"requests_today":       requestsToday,
+			"deferred_today":       deferredToday,
 		})
 	}
 
@@ -119,12 +137,12 @@ func (s *RESTServer) generateInstanceName(arrType string) string {
 	baseType := arrType
 	if strings.HasPrefix(arrType, "whisparr") {
 		// Count all whisparr variants together
-		err := s.db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE type LIKE 'whisparr%'").Scan(&count)
+		err := s.db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE type LIKE 'whisparr%' AND deleted_at IS NULL").Scan(&count)
 		if err != nil {
 			count = 0
 		}
 	} else {
-		err := s.db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE type = ?", baseType).Scan(&count)
+		err := s.db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE type = ? AND deleted_at IS NULL", baseType).Scan(&count)
 		if err != nil {
 			count = 0
 		}
@@ -139,19 +157,20 @@ func (s *RESTServer) generateInstanceName(arrType string) string {
 func (s *RESTServer) createArrInstance(c *gin.Context) {
 	var req struct {
 		Name    string `json:"name"`
-		Type    string `json:"type"`
-		URL     string `json:"url"`
+		Type    string `json:"type" binding:"required,oneof=sonarr radarr whisparr-v2 whisparr-v3 lidarr"`
+		URL     string `json:"url" binding:"required"`
 		APIKey  string `json:"api_key"`
 		Enabled bool   `json:"enabled"`
+		// Requests per UTC day; 0 or absent is unlimited
+		DailyRequestBudget *int `json:"daily_request_budget" binding:"omitempty,min=0"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
 	// Security: Validate URL to prevent SSRF attacks
 	if err := validateArrURL(req.URL); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatInvalidURLError(err)})
+		respondFieldErrors(c, invalidURLField(c, err))
 		return
 	}
 
@@ -163,6 +182,7 @@ func (s *RESTServer) createArrInstance(c *gin.Context) {
 	}
 
 	// Encrypt API key before storage
+	redact.Register(req.APIKey)
 	encryptedKey, err := crypto.Encrypt(req.APIKey)
 	if err != nil {
 		logger.Errorf("Failed to encrypt API key: %v", err)
@@ -170,19 +190,32 @@ func (s *RESTServer) createArrInstance(c *gin.Context) {
 		return
 	}
 
-	_, err = s.db.Exec("INSERT INTO arr_instances (name, type, url, api_key, enabled) VALUES (?, ?, ?, ?, ?)",
-		instanceName, req.Type, req.URL, encryptedKey, req.Enabled)
+	budget := 0
+	if req.DailyRequestBudget != nil {
+		budget = *req.DailyRequestBudget
+	}
+	_, err = s.db.Exec("INSERT INTO arr_instances (name, type, url, api_key, enabled, daily_request_budget) VALUES (?, ?, ?, ?, ?, ?)",
+		instanceName, req.Type, req.URL, encryptedKey, req.Enabled, budget)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
+	s.syncArrTagsInBackground()
 	c.Status(http.StatusCreated)
 }
 
+// deleteArrInstance soft-deletes an *arr instance; it can be restored until it is purged.
 func (s *RESTServer) deleteArrInstance(c *gin.Context) {
-	id := c.Param("id")
-	_, err := s.db.Exec("DELETE FROM arr_instances WHERE id = ?", id)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	if err := s.deletedConfig().DeleteArrInstance(id); err != nil {
+		if errors.Is(err, services.ErrArrInstanceNotFound) {
+			respondNotFound(c, "Instance")
+			return
+		}
 		respondDatabaseError(c, err)
 		return
 	}
@@ -193,55 +226,84 @@ func (s *RESTServer) updateArrInstance(c *gin.Context) {
 	id := c.Param("id")
 	var req struct {
 		Name    string `json:"name"`
-		Type    string `json:"type"`
-		URL     string `json:"url"`
+		Type    string `json:"type" binding:"required,oneof=sonarr radarr whisparr-v2 whisparr-v3 lidarr"`
+		URL     string `json:"url" binding:"required"`
 		APIKey  string `json:"api_key"`
 		Enabled bool   `json:"enabled"`
+		// Requests per UTC day; 0 is unlimited, absent keeps the current budget
+		DailyRequestBudget *int `json:"daily_request_budget" binding:"omitempty,min=0"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
 	// Security: Validate URL to prevent SSRF attacks
 	if err := validateArrURL(req.URL); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": formatInvalidURLError(err)})
+		respondFieldErrors(c, invalidURLField(c, err))
 		return
 	}
 
-	// Encrypt API key before storage
-	encryptedKey, err := crypto.Encrypt(req.APIKey)
-	if err != nil {
-		logger.Errorf("Failed to encrypt API key: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt API key"})
-		return
+	// The masked key of getArrInstances keeps the stored key
+	var encryptedKey string
+	if req.APIKey == redact.Mask {
+		if err := s.db.QueryRow("SELECT api_key FROM arr_instances WHERE id = ?", id).Scan(&encryptedKey); err != nil {
+			respondNotFound(c, "Instance")
+			return
+		}
+	} else {
+		redact.Register(req.APIKey)
+		var err error
+		if encryptedKey, err = crypto.Encrypt(req.APIKey); err != nil {
+			logger.Errorf("Failed to encrypt API key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt API key"})
+			return
+		}
 	}
 
-	_, err = s.db.Exec("UPDATE arr_instances SET name = ?, type = ?, url = ?, api_key = ?, enabled = ? WHERE id = ?",
-		req.Name, req.Type, req.URL, encryptedKey, req.Enabled, id)
+	_, err := s.db.Exec(`UPDATE arr_instances SET name = ?, type = ?, url = ?, api_key = ?, enabled = ?,
+		daily_request_budget = COALESCE(?, daily_request_budget) WHERE id = ?`,
+		req.Name, req.Type, req.URL, encryptedKey, req.Enabled, req.DailyRequestBudget, id)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
+	s.syncArrTagsInBackground()
 	c.Status(http.StatusOK)
 }
 
+// storedArrAPIKey returns the decrypted API key of an instance.
+func (s *RESTServer) storedArrAPIKey(id int64) (string, error) {
+	var encrypted string
+	if err := s.db.QueryRow("SELECT api_key FROM arr_instances WHERE id = ?", id).Scan(&encrypted); err != nil {
+		return "", err
+	}
+	return crypto.Decrypt(encrypted)
+}
+
 func (s *RESTServer) testArrConnection(c *gin.Context) {
 	var req struct {
-		URL    string `json:"url"`
+		ID     int64  `json:"id"` // Instance whose stored key replaces a masked api_key
+		URL    string `json:"url" binding:"required"`
 		APIKey string `json:"api_key"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
+	if req.APIKey == redact.Mask {
+		key, err := s.storedArrAPIKey(req.ID)
+		if err != nil {
+			respondNotFound(c, "Instance")
+			return
+		}
+		req.APIKey = key
+	}
 
 	// Security: Validate URL to prevent SSRF attacks
 	if err := validateArrURL(req.URL); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   formatInvalidURLError(err),
-		})
+		p := fieldProblem(invalidURLField(c, err))
+		success := false
+		p.Success = &success
+		writeProblem(c, p)
 		return
 	}
 
@@ -374,3 +436,112 @@ func (s *RESTServer) getArrRootFolders(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// getArrTags returns the tags defined in a *arr instance and caches them for
+// binding scan paths by tag.
+// GET /api/config/arr/:id/tags
+func (s *RESTServer) getArrTags(c *gin.Context) {
+	instanceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instance ID"})
+		return
+	}
+
+	if s.arrClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Arr client not available"})
+		return
+	}
+
+	tags, err := s.arrClient.GetTags(instanceID)
+	if err != nil {
+		logger.Errorf("Failed to get tags for instance %d: %v", instanceID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get tags: %v", err)})
+		return
+	}
+	if err := services.StoreArrTags(s.db, instanceID, tags); err != nil {
+		logger.Warnf("Failed to cache tags of instance %d: %v", instanceID, err)
+	}
+
+	if tags == nil {
+		tags = []integration.ArrTag{}
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// syncArrTags refreshes the tags of all enabled *arr instances and re-binds
+// tag-bound scan paths to the instance now carrying their tag.
+// POST /api/config/arr/tags/sync
+func (s *RESTServer) syncArrTags(c *gin.Context) {
+	if s.arrClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Arr client not available"})
+		return
+	}
+
+	result, err := services.SyncArrTags(s.db, s.arrClient)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if result.Rebound > 0 {
+		s.reloadPathMappings("rebound")
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// syncArrTagsInBackground refreshes *arr tags after an instance changes, so a
+// re-created instance picks up the scan paths bound to its tags.
+func (s *RESTServer) syncArrTagsInBackground() {
+	if s.arrClient == nil {
+		return
+	}
+	go func() {
+		result, err := services.SyncArrTags(s.db, s.arrClient)
+		if err != nil {
+			logger.Warnf("Failed to sync *arr tags: %v", err)
+			return
+		}
+		if result.Rebound > 0 {
+			s.reloadPathMappings("rebound")
+		}
+	}()
+}
+
+// reloadPathMappings reloads path mappings after scan paths were re-bound or
+// remapped, and announces the change with action.
+func (s *RESTServer) reloadPathMappings(action string) {
+	s.publishScanPathsChanged(action)
+	if s.pathMapper == nil {
+		return
+	}
+	if err := s.pathMapper.Reload(); err != nil {
+		logger.Errorf(errMsgReloadPathMappings, err)
+	}
+}
+
+// publishScanPathsChanged announces that action, e.g. "created", changed the
+// scan paths with pathIDs, or many paths if none are given, so every service
+// holding path mappings reloads them. The API reloads its own mapper itself
+// to answer with the new mappings in place.
+func (s *RESTServer) publishScanPathsChanged(action string, pathIDs ...int64) {
+	if s.eventBus == nil {
+		return
+	}
+	aggregateID := "scan_paths"
+	if len(pathIDs) == 1 {
+		aggregateID = strconv.FormatInt(pathIDs[0], 10)
+	}
+	if pathIDs == nil {
+		pathIDs = []int64{}
+	}
+	if err := s.eventBus.Publish(domain.Event{
+		AggregateType: "scan_path",
+		AggregateID:   aggregateID,
+		EventType:     domain.ScanPathsChanged,
+		EventData: map[string]interface{}{
+			"action":   action,
+			"path_ids": pathIDs,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish ScanPathsChanged (%s): %v", action, err)
+	}
+}
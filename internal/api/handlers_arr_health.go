@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// Overall health of an *arr instance.
+const (
+	ArrHealthUnknown  = "unknown"
+	ArrHealthHealthy  = "healthy"
+	ArrHealthDegraded = "degraded"
+	ArrHealthDown     = "down"
+)
+
+const (
+	// arrHealthMaxHours bounds the ?hours window, matching the default retention.
+	arrHealthMaxHours = 90 * 24
+	// arrHealthDegradedUptime is the uptime below which an instance is degraded.
+	arrHealthDegradedUptime = 95.0
+)
+
+// arrBreakerState is the circuit breaker of an instance.
+type arrBreakerState struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenSince           *time.Time `json:"open_since,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	TotalRejected       int64      `json:"total_rejected"`
+}
+
+// arrInstanceHealth is the response of GET /arr/instances/:id/health.
+type arrInstanceHealth struct {
+	InstanceID int64           `json:"instance_id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	Hours      int             `json:"hours"`
+	Breaker    arrBreakerState `json:"breaker"`
+	*services.ArrHealthHistory
+}
+
+// getArrInstanceHealth returns an instance's uptime and response times over the
+// last ?hours (default 24, hourly periods up to 48 hours and daily beyond), the
+// latest health check and the current state of its circuit breaker.
+func (s *RESTServer) getArrInstanceHealth(c *gin.Context) {
+	var instanceID int64
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &instanceID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instance ID"})
+		return
+	}
+
+	hours := parseInt(c.DefaultQuery("hours", "24"), 24)
+	if hours < 1 {
+		hours = 1
+	}
+	if hours > arrHealthMaxHours {
+		hours = arrHealthMaxHours
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	health := arrInstanceHealth{InstanceID: instanceID, Hours: hours}
+	err := s.reader().QueryRowContext(ctx,
+		"SELECT name, type FROM arr_instances WHERE id = ? AND deleted_at IS NULL", instanceID,
+	).Scan(&health.Name, &health.Type)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondNotFound(c, "Instance")
+		return
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	health.ArrHealthHistory, err = services.LoadArrHealthHistory(ctx, s.reader(), instanceID, since, hours > 48)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	health.Breaker = s.arrBreakerState(instanceID)
+	health.Status = arrHealthStatus(health.ArrHealthHistory, health.Breaker)
+	c.JSON(http.StatusOK, health)
+}
+
+// arrBreakerState returns the circuit breaker of an instance. Instances
+// without one haven't been contacted yet, which a closed breaker describes.
+func (s *RESTServer) arrBreakerState(instanceID int64) arrBreakerState {
+	state := arrBreakerState{State: integration.CircuitClosed.String()}
+	source, ok := s.arrClient.(services.CircuitBreakerSource)
+	if !ok {
+		return state
+	}
+	st, ok := source.GetCircuitBreakerStats()[instanceID]
+	if !ok {
+		return state
+	}
+
+	state.State = st.State.String()
+	state.ConsecutiveFailures = st.ConsecutiveFailures
+	state.TotalRejected = st.TotalRejected
+	if !st.OpenSince.IsZero() {
+		t := st.OpenSince
+		state.OpenSince = &t
+	}
+	if !st.LastFailureTime.IsZero() {
+		t := st.LastFailureTime
+		state.LastFailure = &t
+	}
+	return state
+}
+
+// arrHealthStatus rates an instance: down while its breaker is open or its
+// latest check failed, degraded while the breaker probes or uptime is low.
+func arrHealthStatus(history *services.ArrHealthHistory, breaker arrBreakerState) string {
+	switch {
+	case breaker.State == integration.CircuitOpen.String():
+		return ArrHealthDown
+	case history.Latest == nil:
+		return ArrHealthUnknown
+	case !history.Latest.Reachable:
+		return ArrHealthDown
+	case breaker.State == integration.CircuitHalfOpen.String(),
+		history.UptimePercent != nil && *history.UptimePercent < arrHealthDegradedUptime:
+		return ArrHealthDegraded
+	default:
+		return ArrHealthHealthy
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetArrInstanceHealth(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	require.NoError(t, testutil.SeedArrInstance(sqlDB, 1, "Sonarr", "sonarr", "http://sonarr:8989", "key"))
+	require.NoError(t, testutil.SeedArrInstance(sqlDB, 2, "Radarr", "radarr", "http://radarr:7878", "key"))
+	_, err = sqlDB.Exec(`
+		INSERT INTO arr_health_samples (instance_id, checked_at, reachable, response_ms, error) VALUES
+			(1, datetime('now', '-3 hours'), 1, 40, NULL),
+			(1, datetime('now', '-2 hours'), 0, 0, 'unreachable: connection refused'),
+			(1, datetime('now', '-1 hours'), 1, 60, NULL),
+			(1, datetime('now', '-30 hours'), 1, 500, NULL)
+	`)
+	require.NoError(t, err)
+
+	openSince := time.Now().Add(-10 * time.Minute)
+	client := &breakerArrClient{
+		MockArrClient: &testutil.MockArrClient{},
+		stats: map[int64]integration.CircuitBreakerStats{
+			2: {State: integration.CircuitOpen, ConsecutiveFailures: 5, OpenSince: openSince},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: sqlDB, arrClient: client}
+	r.GET("/arr/instances/:id/health", s.getArrInstanceHealth)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("last 24 hours", func(t *testing.T) {
+		w := get("/arr/instances/1/health")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var health arrInstanceHealth
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+		assert.Equal(t, "Sonarr", health.Name)
+		assert.Equal(t, 24, health.Hours)
+		assert.Equal(t, 3, health.Samples)
+		require.NotNil(t, health.UptimePercent)
+		assert.InDelta(t, 66.67, *health.UptimePercent, 0.01)
+		assert.InDelta(t, 50, health.AvgResponseMs, 0.01)
+		assert.Equal(t, int64(60), health.P95ResponseMs)
+		assert.Len(t, health.Periods, 3)
+		require.NotNil(t, health.Latest)
+		assert.True(t, health.Latest.Reachable)
+		assert.Equal(t, "closed", health.Breaker.State)
+		assert.Equal(t, ArrHealthDegraded, health.Status)
+	})
+
+	t.Run("daily periods", func(t *testing.T) {
+		w := get("/arr/instances/1/health?hours=168")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var health arrInstanceHealth
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+		assert.Equal(t, 4, health.Samples)
+		assert.Equal(t, int64(500), health.P95ResponseMs)
+		for _, p := range health.Periods {
+			assert.Len(t, p.Period, 10, "periods are days")
+		}
+	})
+
+	t.Run("open breaker without samples", func(t *testing.T) {
+		w := get("/arr/instances/2/health")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var health arrInstanceHealth
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+		assert.Equal(t, 0, health.Samples)
+		assert.Nil(t, health.UptimePercent)
+		assert.Nil(t, health.Latest)
+		assert.Equal(t, "open", health.Breaker.State)
+		assert.Equal(t, 5, health.Breaker.ConsecutiveFailures)
+		require.NotNil(t, health.Breaker.OpenSince)
+		assert.Equal(t, ArrHealthDown, health.Status)
+	})
+
+	t.Run("unknown instance", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/arr/instances/99/health").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/arr/instances/abc/health").Code)
+	})
+}
+
+func TestArrHealthStatus(t *testing.T) {
+	uptime := func(v float64) *float64 { return &v }
+	closed := arrBreakerState{State: "closed"}
+
+	tests := []struct {
+		name    string
+		history services.ArrHealthHistory
+		breaker arrBreakerState
+		want    string
+	}{
+		{"never checked", services.ArrHealthHistory{}, closed, ArrHealthUnknown},
+		{"healthy", services.ArrHealthHistory{Latest: &services.ArrHealthSample{Reachable: true}, UptimePercent: uptime(100)}, closed, ArrHealthHealthy},
+		{"latest failed", services.ArrHealthHistory{Latest: &services.ArrHealthSample{}, UptimePercent: uptime(99)}, closed, ArrHealthDown},
+		{"low uptime", services.ArrHealthHistory{Latest: &services.ArrHealthSample{Reachable: true}, UptimePercent: uptime(90)}, closed, ArrHealthDegraded},
+		{"half-open", services.ArrHealthHistory{Latest: &services.ArrHealthSample{Reachable: true}, UptimePercent: uptime(100)}, arrBreakerState{State: "half-open"}, ArrHealthDegraded},
+		{"open", services.ArrHealthHistory{Latest: &services.ArrHealthSample{Reachable: true}}, arrBreakerState{State: "open"}, ArrHealthDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, arrHealthStatus(&tt.history, tt.breaker))
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,9 @@ import (
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 // mockArrClient is a mock implementation of integration.ArrClient for testing
@@ -33,6 +37,14 @@ func (m *mockArrClient) DeleteFile(_ int64, _ string) (map[string]interface{}, e
 	return nil, nil
 }
 
+func (m *mockArrClient) PlanDeletion(mediaID int64, _ string) (*integration.DeletionPlan, error) {
+	return &integration.DeletionPlan{MediaID: mediaID}, nil
+}
+
+func (m *mockArrClient) FindSearchMetadata(_ int64, _ string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
 func (m *mockArrClient) GetFilePath(_ int64, _ map[string]interface{}, _ string) (string, error) {
 	return "", nil
 }
@@ -45,6 +57,10 @@ func (m *mockArrClient) TriggerSearch(_ int64, _ string, _ []int64) error {
 	return nil
 }
 
+func (m *mockArrClient) RescanMedia(_ int64, _ string) error {
+	return nil
+}
+
 func (m *mockArrClient) GetAllInstances() ([]*integration.ArrInstanceInfo, error) {
 	return nil, nil
 }
@@ -64,6 +80,10 @@ func (m *mockArrClient) GetRootFolders(_ int64) ([]integration.RootFolder, error
 	return m.rootFolders, nil
 }
 
+func (m *mockArrClient) GetTags(_ int64) ([]integration.ArrTag, error) {
+	return nil, nil
+}
+
 func (m *mockArrClient) GetQueueForPath(_ string) ([]integration.QueueItemInfo, error) {
 	return nil, nil
 }
@@ -92,6 +112,22 @@ func (m *mockArrClient) GetMediaDetails(_ int64, _ string) (*integration.MediaDe
 	return nil, nil
 }
 
+func (m *mockArrClient) GetExpectedRuntime(_ int64, _ string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (m *mockArrClient) GetDelayProfile(_ int64, _ string) (*integration.DelayProfile, error) {
+	return nil, nil
+}
+
+func (m *mockArrClient) GetReleases(_ int64, _ string, _ []int64) ([]integration.Release, error) {
+	return nil, nil
+}
+
+func (m *mockArrClient) GrabRelease(_, _ string, _ int64) (*integration.Release, error) {
+	return nil, nil
+}
+
 // setupArrTestServer creates a test server with arr routes and authentication
 // Returns router, apiKey, and cleanup function that must be called to release resources
 func setupArrTestServer(t *testing.T, db *sql.DB) (*gin.Engine, string, func()) {
@@ -187,13 +223,13 @@ func TestGetArrInstances_WithData(t *testing.T) {
 	assert.Equal(t, "Test Sonarr", response[0]["name"])
 	assert.Equal(t, "sonarr", response[0]["type"])
 	assert.Equal(t, "http://localhost:8989", response[0]["url"])
-	assert.Equal(t, testAPIKey, response[0]["api_key"]) // Should be decrypted
+	assert.Equal(t, redact.Mask, response[0]["api_key"]) // Set, but never returned
 	assert.Equal(t, true, response[0]["enabled"])
 }
 
 func TestGetArrInstances_PlaintextAPIKey(t *testing.T) {
 	// When encryption is disabled (no HEALARR_ENCRYPTION_KEY), API keys are stored as plaintext
-	// and returned as-is for backwards compatibility
+	// and are masked the same way
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -217,8 +253,7 @@ func TestGetArrInstances_PlaintextAPIKey(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Len(t, response, 1)
-	// When encryption is disabled, plaintext values are returned as-is
-	assert.Equal(t, "plaintext-api-key", response[0]["api_key"])
+	assert.Equal(t, redact.Mask, response[0]["api_key"])
 }
 
 func TestGetArrInstances_Unauthorized(t *testing.T) {
@@ -403,6 +438,57 @@ func TestUpdateArrInstance_Success(t *testing.T) {
 	assert.False(t, enabled)
 }
 
+func TestUpdateArrInstance_DailyRequestBudget(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	router, apiKey, serverCleanup := setupArrTestServer(t, db)
+	defer serverCleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	_, err := db.Exec("INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', ?, 1)", encryptedKey)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO arr_request_usage (instance_id, day, requests, deferred) VALUES (1, ?, 42, 3)", time.Now().UTC().Format("2006-01-02"))
+	require.NoError(t, err)
+
+	put := func(body string) int {
+		req, _ := http.NewRequest("PUT", "/api/config/arr/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	budget := func() int {
+		var b int
+		require.NoError(t, db.QueryRow("SELECT daily_request_budget FROM arr_instances WHERE id = 1").Scan(&b))
+		return b
+	}
+
+	instance := `"name": "Sonarr", "type": "sonarr", "url": "http://sonarr:8989", "api_key": "api-key", "enabled": true`
+	assert.Equal(t, http.StatusOK, put(`{`+instance+`, "daily_request_budget": 500}`))
+	assert.Equal(t, 500, budget())
+
+	// Clients that don't know the budget keep it
+	assert.Equal(t, http.StatusOK, put(`{`+instance+`}`))
+	assert.Equal(t, 500, budget())
+
+	assert.Equal(t, http.StatusBadRequest, put(`{`+instance+`, "daily_request_budget": -1}`))
+	assert.Equal(t, 500, budget())
+
+	req, _ := http.NewRequest("GET", "/api/config/arr", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var instances []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &instances))
+	require.Len(t, instances, 1)
+	assert.Equal(t, float64(500), instances[0]["daily_request_budget"])
+	assert.Equal(t, float64(42), instances[0]["requests_today"])
+	assert.Equal(t, float64(3), instances[0]["deferred_today"])
+}
+
 func TestUpdateArrInstance_InvalidJSON(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -442,14 +528,21 @@ func TestDeleteArrInstance_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
-	// Verify deletion
-	var count int
-	err = db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE id = ?", id).Scan(&count)
+	// Verify the instance is kept as deleted
+	var deletedAt sql.NullString
+	err = db.QueryRow("SELECT deleted_at FROM arr_instances WHERE id = ?", id).Scan(&deletedAt)
 	assert.NoError(t, err)
-	assert.Equal(t, 0, count)
+	assert.True(t, deletedAt.Valid)
+
+	// Deleting it again finds nothing
+	req, _ = http.NewRequest("DELETE", "/api/config/arr/"+string(rune(id+'0')), nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestDeleteArrInstance_CascadeDeletesScanPaths(t *testing.T) {
+func TestDeleteArrInstance_KeepsScanPaths(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -481,9 +574,9 @@ func TestDeleteArrInstance_CascadeDeletesScanPaths(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
-	// Verify cascade delete of scan paths
+	// Scan paths stay assigned so restoring the instance reconnects them
 	db.QueryRow("SELECT COUNT(*) FROM scan_paths WHERE arr_instance_id = ?", instanceID).Scan(&scanPathCount)
-	assert.Equal(t, 0, scanPathCount)
+	assert.Equal(t, 1, scanPathCount)
 }
 
 func TestDeleteArrInstance_DBError(t *testing.T) {
@@ -1145,6 +1238,8 @@ func TestTestArrConnection_SSRFBlocked(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.Equal(t, false, response["success"])
 	assert.Contains(t, response["error"], "Invalid URL")
+	fields := response["errors"].([]interface{})
+	assert.Equal(t, "url", fields[0].(map[string]interface{})["field"])
 }
 
 func TestGenerateInstanceName_FirstInstance(t *testing.T) {
@@ -1292,3 +1387,106 @@ func TestGenerateInstanceName_SonarrV3(t *testing.T) {
 	name := s.generateInstanceName("sonarr-v3")
 	assert.Equal(t, "Sonarr", name)
 }
+
+func TestArrTags_BindScanPathsByTag(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES
+			(1, 'Radarr', 'radarr', 'http://radarr', 'key', 1),
+			(2, 'Radarr 4K', 'radarr', 'http://radarr4k', 'key', 1);
+	`)
+	require.NoError(t, err)
+
+	tagsByInstance := map[int64][]integration.ArrTag{
+		1: {{ID: 1, Label: "hd"}},
+		2: {{ID: 1, Label: "UHD"}},
+	}
+	arr := &testutil.MockArrClient{
+		GetAllInstancesFunc: func() ([]*integration.ArrInstanceInfo, error) {
+			var infos []*integration.ArrInstanceInfo
+			rows, err := db.Query("SELECT id, name FROM arr_instances WHERE enabled = 1 AND deleted_at IS NULL")
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var info integration.ArrInstanceInfo
+				if err := rows.Scan(&info.ID, &info.Name); err != nil {
+					return nil, err
+				}
+				infos = append(infos, &info)
+			}
+			return infos, rows.Err()
+		},
+		GetTagsFunc: func(instanceID int64) ([]integration.ArrTag, error) {
+			return tagsByInstance[instanceID], nil
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, arrClient: arr, pathMapper: &testutil.MockPathMapper{}}
+	r.GET("/config/arr/:id/tags", s.getArrTags)
+	r.POST("/config/arr/tags/sync", s.syncArrTags)
+	r.DELETE("/config/arr/:id", s.deleteArrInstance)
+	r.GET("/config/paths", s.getScanPaths)
+	r.POST("/config/paths", s.createScanPath)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	instanceOf := func(localPath string) sql.NullInt64 {
+		var id sql.NullInt64
+		require.NoError(t, db.QueryRow("SELECT arr_instance_id FROM scan_paths WHERE local_path = ?", localPath).Scan(&id))
+		return id
+	}
+
+	w := do("GET", "/config/arr/2/tags", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tags []integration.ArrTag
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+	assert.Equal(t, []integration.ArrTag{{ID: 1, Label: "UHD"}}, tags)
+	assert.Equal(t, http.StatusBadRequest, do("GET", "/config/arr/abc/tags", "").Code)
+
+	// The tag overrides arr_instance_id
+	w = do("POST", "/config/paths", `{"local_path":"/media/movies-4k","arr_instance_id":1,"arr_instance_tag":" uhd ","max_retries":3}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, int64(2), instanceOf("/media/movies-4k").Int64)
+
+	// Tags not cached yet are fetched before the path is rejected
+	w = do("POST", "/config/paths", `{"local_path":"/media/movies","arr_instance_tag":"hd","max_retries":3}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, int64(1), instanceOf("/media/movies").Int64)
+
+	w = do("POST", "/config/paths", `{"local_path":"/media/anime","arr_instance_tag":"anime","max_retries":3}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do("GET", "/config/paths", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 2)
+	assert.Equal(t, "uhd", paths[0]["arr_instance_tag"])
+
+	// Re-creating the 4K instance under a new ID re-binds the path on sync
+	assert.Equal(t, http.StatusNoContent, do("DELETE", "/config/arr/2", "").Code)
+	assert.False(t, instanceOf("/media/movies-4k").Valid)
+	_, err = db.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (5, 'Radarr 4K', 'radarr', 'http://radarr4k', 'key', 1)`)
+	require.NoError(t, err)
+	tagsByInstance[5] = []integration.ArrTag{{ID: 1, Label: "uhd"}}
+
+	w = do("POST", "/config/arr/tags/sync", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result services.ArrTagSyncResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Instances)
+	assert.Equal(t, 1, result.Rebound)
+	assert.Equal(t, int64(5), instanceOf("/media/movies-4k").Int64)
+}
@@ -17,7 +17,7 @@ func (s *RESTServer) handleAuthSetup(c *gin.Context) {
 	// Check if password already exists
 	var exists bool
 	if s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM settings WHERE key = 'password_hash')").Scan(&exists) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrMsgDatabaseError})
+		respondError(c, http.StatusInternalServerError, ErrMsgDatabaseError)
 		return
 	}
 
@@ -27,15 +27,9 @@ func (s *RESTServer) handleAuthSetup(c *gin.Context) {
 	}
 
 	var req struct {
-		Password string `json:"password"`
-	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		Password string `json:"password" binding:"min=8" label:"Password"`
 	}
-
-	if len(req.Password) < 8 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Password must be at least 8 characters"})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -80,8 +74,7 @@ func (s *RESTServer) handleLogin(c *gin.Context) {
 	var req struct {
 		Password string `json:"password"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -93,7 +86,7 @@ func (s *RESTServer) handleLogin(c *gin.Context) {
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrMsgDatabaseError})
+		respondError(c, http.StatusInternalServerError, ErrMsgDatabaseError)
 		return
 	}
 
@@ -131,7 +124,7 @@ func (s *RESTServer) handleAuthStatus(c *gin.Context) {
 
 	var count int
 	if s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM settings WHERE key = 'password_hash'").Scan(&count) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrMsgDatabaseError})
+		respondError(c, http.StatusInternalServerError, ErrMsgDatabaseError)
 		return
 	}
 
@@ -189,23 +182,17 @@ func (s *RESTServer) changePassword(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var req struct {
-		CurrentPassword string `json:"current_password"`
-		NewPassword     string `json:"new_password"`
+		CurrentPassword string `json:"current_password" binding:"required" label:"Current password"`
+		NewPassword     string `json:"new_password" binding:"min=8" label:"New password"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(req.NewPassword) < 8 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "New password must be at least 8 characters"})
+	if !bindBody(c, &req) {
 		return
 	}
 
 	// Verify current password
 	var hash string
 	if s.db.QueryRowContext(ctx, "SELECT value FROM settings WHERE key = 'password_hash'").Scan(&hash) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrMsgDatabaseError})
+		respondError(c, http.StatusInternalServerError, ErrMsgDatabaseError)
 		return
 	}
 
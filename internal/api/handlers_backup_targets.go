@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/backup"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// backupTargetRequest is the body for creating, updating or testing a backup target.
+type backupTargetRequest struct {
+	ID       int64               `json:"id"` // Testing: the saved target whose masked secrets to use
+	Name     string              `json:"name"`
+	Type     string              `json:"type"`
+	Config   backup.TargetConfig `json:"config"`
+	KeepLast int                 `json:"keep_last" binding:"min=0,max=1000"`
+	KeepDays int                 `json:"keep_days" binding:"min=0,max=3650"`
+	Enabled  *bool               `json:"enabled"`
+}
+
+// toTarget validates the name and the settings of the target type, and
+// converts the request to a BackupTarget.
+func (req *backupTargetRequest) toTarget() (*backup.BackupTarget, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := req.Config.Validate(req.Type); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return &backup.BackupTarget{
+		Name:      name,
+		Type:      req.Type,
+		Config:    req.Config,
+		Retention: backup.Retention{KeepLast: req.KeepLast, KeepDays: req.KeepDays},
+		Enabled:   enabled,
+	}, nil
+}
+
+// backupTargetID parses the :id parameter, responding with an error if it is invalid.
+func backupTargetID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return 0, false
+	}
+	return id, true
+}
+
+// getBackupTargets returns the backup targets and whether uploads are encrypted.
+func (s *RESTServer) getBackupTargets(c *gin.Context) {
+	targets, err := s.backups.GetAllTargets()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	for _, t := range targets {
+		t.Config = t.Config.Masked()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets":            targets,
+		"types":              backup.Types,
+		"encryption_enabled": s.backups.EncryptionEnabled(),
+	})
+}
+
+func (s *RESTServer) createBackupTarget(c *gin.Context) {
+	var req backupTargetRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	target, err := req.toTarget()
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+
+	id, err := s.backups.CreateTarget(target)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Backup target created"})
+}
+
+func (s *RESTServer) updateBackupTarget(c *gin.Context) {
+	id, ok := backupTargetID(c)
+	if !ok {
+		return
+	}
+
+	var req backupTargetRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	target, err := req.toTarget()
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+	target.ID = id
+	if !s.keepBackupTargetSecrets(c, target) {
+		return
+	}
+
+	if err := s.backups.UpdateTarget(target); err != nil {
+		if errors.Is(err, backup.ErrTargetNotFound) {
+			respondNotFound(c, "Backup target")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Backup target updated"})
+}
+
+// keepBackupTargetSecrets restores the stored secrets of the target that the
+// client sent back masked, responding with an error if it doesn't exist.
+func (s *RESTServer) keepBackupTargetSecrets(c *gin.Context, target *backup.BackupTarget) bool {
+	cfg := target.Config
+	if cfg.SecretAccessKey != redact.Mask && cfg.Password != redact.Mask && cfg.PrivateKey != redact.Mask {
+		return true
+	}
+	stored, err := s.backups.GetTarget(target.ID)
+	if err != nil {
+		respondNotFound(c, "Backup target")
+		return false
+	}
+	target.Config.KeepSecrets(stored.Config)
+	return true
+}
+
+func (s *RESTServer) deleteBackupTarget(c *gin.Context) {
+	id, ok := backupTargetID(c)
+	if !ok {
+		return
+	}
+
+	if err := s.backups.DeleteTarget(id); err != nil {
+		if errors.Is(err, backup.ErrTargetNotFound) {
+			respondNotFound(c, "Backup target")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Backup target deleted"})
+}
+
+// testBackupTarget checks that the settings in the body reach the target by
+// listing the backups stored on it. Nothing is uploaded.
+func (s *RESTServer) testBackupTarget(c *gin.Context) {
+	var req backupTargetRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	if req.Name == "" {
+		req.Name = "test"
+	}
+	target, err := req.toTarget()
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+	if req.ID > 0 {
+		target.ID = req.ID
+		if !s.keepBackupTargetSecrets(c, target) {
+			return
+		}
+	}
+
+	backups, err := s.backups.TestTarget(c.Request.Context(), target)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "backups": backups})
+}
+
+// uploadBackupNow creates a database backup and uploads it to the target in
+// the background. The result shows up in the target's last upload and error.
+func (s *RESTServer) uploadBackupNow(c *gin.Context) {
+	id, ok := backupTargetID(c)
+	if !ok {
+		return
+	}
+
+	target, err := s.backups.GetTarget(id)
+	if err != nil {
+		if errors.Is(err, backup.ErrTargetNotFound) {
+			respondNotFound(c, "Backup target")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	go func() {
+		if err := s.backups.UploadNow(context.Background(), target); err != nil {
+			logger.Errorf("Backup upload to %s failed: %v", target.Name, err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Backup upload started"})
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/mescon/Healarr/internal/auth"
+	"github.com/mescon/Healarr/internal/backup"
+	"github.com/mescon/Healarr/internal/crypto"
+)
+
+// setupBackupTargetsTestServer creates a test server with the backup target routes
+func setupBackupTargetsTestServer(t *testing.T) (*gin.Engine, string, func()) {
+	t.Helper()
+
+	db, dbCleanup := setupTestDB(t)
+	_, err := db.Exec(`
+		CREATE TABLE backup_targets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			config TEXT NOT NULL,
+			keep_last INTEGER NOT NULL DEFAULT 0,
+			keep_days INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN DEFAULT 1,
+			last_upload_at TIMESTAMP,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	backupDir := t.TempDir()
+	backups := backup.NewService(db, func() (string, error) {
+		path := filepath.Join(backupDir, "healarr_20240101_000000.db")
+		return path, os.WriteFile(path, []byte("backup"), 0600)
+	})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, backups: backups}
+
+	apiKey, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	encryptedKey, err := crypto.Encrypt(apiKey)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO settings (key, value) VALUES ('api_key', ?)", encryptedKey)
+	require.NoError(t, err)
+
+	protected := r.Group("/api")
+	protected.Use(s.authMiddleware())
+	{
+		protected.GET("/config/backup-targets", s.getBackupTargets)
+		protected.POST("/config/backup-targets", s.createBackupTarget)
+		protected.POST("/config/backup-targets/test", s.testBackupTarget)
+		protected.PUT(routeBackupTargetByID, s.updateBackupTarget)
+		protected.DELETE(routeBackupTargetByID, s.deleteBackupTarget)
+		protected.POST(routeBackupTargetByID+"/upload", s.uploadBackupNow)
+	}
+
+	return r, apiKey, dbCleanup
+}
+
+// newTestWebDAVShare serves an in-memory WebDAV share.
+func newTestWebDAVShare(t *testing.T) (*httptest.Server, webdav.FileSystem) {
+	t.Helper()
+	fs := webdav.NewMemFS()
+	server := httptest.NewServer(&webdav.Handler{FileSystem: fs, LockSystem: webdav.NewMemLS()})
+	t.Cleanup(server.Close)
+	return server, fs
+}
+
+func doBackupTargetRequest(t *testing.T, router *gin.Engine, apiKey, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// backupTargetsResponse is the body of GET /api/config/backup-targets.
+type backupTargetsResponse struct {
+	Targets           []backup.BackupTarget `json:"targets"`
+	EncryptionEnabled bool                  `json:"encryption_enabled"`
+}
+
+func getBackupTargetsForTest(t *testing.T, router *gin.Engine, apiKey string) backupTargetsResponse {
+	t.Helper()
+	w := doBackupTargetRequest(t, router, apiKey, "GET", "/api/config/backup-targets", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp backupTargetsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestCreateBackupTarget_Validation(t *testing.T) {
+	router, apiKey, cleanup := setupBackupTargetsTestServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"missing name", `{"type":"webdav","config":{"url":"https://nas/dav"}}`, "name is required"},
+		{"unknown type", `{"name":"a","type":"ftp","config":{}}`, "unknown target type: ftp (supported: s3, webdav, sftp, rclone)"},
+		{"s3 bucket", `{"name":"a","type":"s3","config":{"region":"eu-west-1","access_key_id":"k","secret_access_key":"s"}}`, "bucket is required"},
+		{"webdav scheme", `{"name":"a","type":"webdav","config":{"url":"nas/dav"}}`, "url must start with http:// or https://"},
+		{"sftp auth", `{"name":"a","type":"sftp","config":{"host":"nas","username":"healarr"}}`, "password or private_key is required"},
+		{"rclone remote", `{"name":"a","type":"rclone","config":{}}`, "remote is required"},
+		{"keep last", `{"name":"a","type":"rclone","config":{"remote":"b2:"},"keep_last":-1}`, "keep_last must be between 0 and 1000"},
+		{"keep days", `{"name":"a","type":"rclone","config":{"remote":"b2:"},"keep_days":5000}`, "keep_days must be between 0 and 3650"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doBackupTargetRequest(t, router, apiKey, "POST", "/api/config/backup-targets", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
+
+func TestBackupTargets_Lifecycle(t *testing.T) {
+	router, apiKey, cleanup := setupBackupTargetsTestServer(t)
+	defer cleanup()
+	share, fs := newTestWebDAVShare(t)
+
+	body := `{"name":"NAS","type":"webdav","config":{"url":"` + share.URL + `"},"keep_last":3}`
+
+	// Test the settings before saving them
+	w := doBackupTargetRequest(t, router, apiKey, "POST", "/api/config/backup-targets/test", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"success":true`)
+
+	w = doBackupTargetRequest(t, router, apiKey, "POST", "/api/config/backup-targets", body)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	resp := getBackupTargetsForTest(t, router, apiKey)
+	require.Len(t, resp.Targets, 1)
+	assert.False(t, resp.EncryptionEnabled)
+	target := resp.Targets[0]
+	assert.Equal(t, "NAS", target.Name)
+	assert.Equal(t, share.URL, target.Config.URL)
+	assert.Equal(t, 3, target.KeepLast)
+	assert.True(t, target.Enabled)
+
+	// Upload now runs in the background and records the result on the target
+	w = doBackupTargetRequest(t, router, apiKey, "POST", "/api/config/backup-targets/1/upload", "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Eventually(t, func() bool {
+		targets := getBackupTargetsForTest(t, router, apiKey).Targets
+		return targets[0].LastUploadAt != nil
+	}, 5*time.Second, 20*time.Millisecond)
+	_, err := fs.Stat(t.Context(), "/healarr_20240101_000000.db")
+	assert.NoError(t, err, "the backup should be on the share")
+
+	w = doBackupTargetRequest(t, router, apiKey, "PUT", "/api/config/backup-targets/1",
+		`{"name":"NAS2","type":"webdav","config":{"url":"`+share.URL+`"},"keep_days":30,"enabled":false}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	target = getBackupTargetsForTest(t, router, apiKey).Targets[0]
+	assert.Equal(t, "NAS2", target.Name)
+	assert.Equal(t, 30, target.KeepDays)
+	assert.False(t, target.Enabled)
+
+	w = doBackupTargetRequest(t, router, apiKey, "DELETE", "/api/config/backup-targets/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, getBackupTargetsForTest(t, router, apiKey).Targets)
+
+	for _, req := range []struct{ method, path, body string }{
+		{"PUT", "/api/config/backup-targets/1", body},
+		{"DELETE", "/api/config/backup-targets/1", ""},
+		{"POST", "/api/config/backup-targets/1/upload", ""},
+	} {
+		w = doBackupTargetRequest(t, router, apiKey, req.method, req.path, req.body)
+		assert.Equal(t, http.StatusNotFound, w.Code, "%s %s", req.method, req.path)
+	}
+	w = doBackupTargetRequest(t, router, apiKey, "DELETE", "/api/config/backup-targets/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTestBackupTarget_Failure(t *testing.T) {
+	router, apiKey, cleanup := setupBackupTargetsTestServer(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	w := doBackupTargetRequest(t, router, apiKey, "POST", "/api/config/backup-targets/test",
+		`{"type":"webdav","config":{"url":"`+server.URL+`"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, false, resp["success"])
+	assert.Contains(t, resp["error"], "403")
+}
@@ -14,8 +14,13 @@ import (
 
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/i18n"
 	"github.com/mescon/Healarr/internal/logger"
 	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/remote"
+	"github.com/mescon/Healarr/internal/services"
 )
 
 // Type alias for cleaner code
@@ -28,8 +33,7 @@ func (s *RESTServer) updateSettings(c *gin.Context) {
 	var req struct {
 		BasePath string `json:"base_path"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -79,9 +83,10 @@ func (s *RESTServer) restartServer(c *gin.Context) {
 	}()
 }
 
-// exportArrInstances exports arr instances from the database.
-func (s *RESTServer) exportArrInstances() []gin.H {
-	rows, err := s.db.Query("SELECT name, type, url, api_key, enabled FROM arr_instances")
+// exportArrInstances exports arr instances from the database, with their API
+// keys masked unless includeSecrets is set.
+func (s *RESTServer) exportArrInstances(includeSecrets bool) []gin.H {
+	rows, err := s.db.Query("SELECT name, type, url, api_key, enabled FROM arr_instances WHERE deleted_at IS NULL")
 	if err != nil {
 		logger.Debugf("Failed to query arr instances for export: %v", err)
 		return nil
@@ -100,6 +105,8 @@ func (s *RESTServer) exportArrInstances() []gin.H {
 		if err != nil {
 			logger.Errorf("Failed to decrypt API key for export: %v", err)
 			decryptedKey = "[DECRYPTION_ERROR]"
+		} else if !includeSecrets {
+			decryptedKey = redact.Value(decryptedKey)
 		}
 		instances = append(instances, gin.H{
 			"name": name, "type": arrType, "url": url, "api_key": decryptedKey, "enabled": enabled,
@@ -111,11 +118,17 @@ func (s *RESTServer) exportArrInstances() []gin.H {
 	return instances
 }
 
-// exportScanPaths exports scan paths from the database.
-func (s *RESTServer) exportScanPaths() []gin.H {
-	rows, err := s.db.Query(`SELECT local_path, arr_path, arr_instance_id, enabled, auto_remediate, dry_run,
-		detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours
-		FROM scan_paths`)
+// exportScanPaths exports scan paths from the database, with their remote
+// passwords masked unless includeSecrets is set.
+func (s *RESTServer) exportScanPaths(includeSecrets bool) []gin.H {
+	rows, err := s.db.Query(`SELECT local_path, arr_path, arr_instance_id, arr_instance_tag, enabled, auto_remediate, dry_run,
+		detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours,
+		detection_fallbacks, min_file_age_minutes, size_stability_seconds, quality_pin, priority,
+		research_interval_days, research_period_days, protocol_preference,
+		remote_url, remote_username, remote_password, remote_host_key_fingerprint, remote_requests_per_minute,
+		media_extensions, min_file_size_mb, duration_check, rollout_daily_limit, rollout_days, min_confidence,
+		symlink_policy
+		FROM scan_paths WHERE deleted_at IS NULL`)
 	if err != nil {
 		logger.Debugf("Failed to query scan paths for export: %v", err)
 		return nil
@@ -124,31 +137,65 @@ func (s *RESTServer) exportScanPaths() []gin.H {
 
 	var paths []gin.H
 	for rows.Next() {
-		var localPath, arrPath, detectionMethod, detectionMode string
+		var localPath, arrPath, arrInstanceTag, detectionMethod, detectionMode, qualityPin, protocolPreference string
+		var remoteURL, remoteUsername, remotePassword, remoteHostKeyFingerprint, mediaExtensions, durationCheck, symlinkPolicy string
 		var arrInstanceID sql.NullInt64
 		var enabled, autoRemediate, dryRun bool
-		var detectionArgs sql.NullString
-		var maxRetries int
-		var verificationTimeout sql.NullInt64
-		if err := rows.Scan(&localPath, &arrPath, &arrInstanceID, &enabled, &autoRemediate, &dryRun,
-			&detectionMethod, &detectionArgs, &detectionMode, &maxRetries, &verificationTimeout); err != nil {
+		var detectionArgs, detectionFallbacks sql.NullString
+		var maxRetries, priority, researchIntervalDays, researchPeriodDays, remoteRequestsPerMinute, minFileSizeMB int
+		var rolloutDailyLimit, rolloutDays, minConfidence int
+		var verificationTimeout, minFileAgeMinutes, sizeStabilitySeconds sql.NullInt64
+		if err := rows.Scan(&localPath, &arrPath, &arrInstanceID, &arrInstanceTag, &enabled, &autoRemediate, &dryRun,
+			&detectionMethod, &detectionArgs, &detectionMode, &maxRetries, &verificationTimeout,
+			&detectionFallbacks, &minFileAgeMinutes, &sizeStabilitySeconds, &qualityPin, &priority,
+			&researchIntervalDays, &researchPeriodDays, &protocolPreference,
+			&remoteURL, &remoteUsername, &remotePassword, &remoteHostKeyFingerprint, &remoteRequestsPerMinute,
+			&mediaExtensions, &minFileSizeMB, &durationCheck, &rolloutDailyLimit, &rolloutDays, &minConfidence,
+			&symlinkPolicy); err != nil {
 			logger.Errorf("Failed to scan path for export: %v", err)
 			continue
 		}
 		path := gin.H{
 			"local_path": localPath, "arr_path": arrPath, "enabled": enabled,
 			"auto_remediate": autoRemediate, "dry_run": dryRun, "detection_method": detectionMethod,
-			"detection_mode": detectionMode, "max_retries": maxRetries,
+			"detection_mode": detectionMode, "max_retries": maxRetries, "quality_pin": qualityPin,
+			"priority": priority, "research_interval_days": researchIntervalDays, "research_period_days": researchPeriodDays,
+			"protocol_preference": protocolPreference, "media_extensions": mediaExtensions, "min_file_size_mb": minFileSizeMB,
+			"duration_check": durationCheck, "rollout_daily_limit": rolloutDailyLimit, "rollout_days": rolloutDays,
+			"min_confidence": minConfidence, "symlink_policy": symlinkPolicy,
 		}
 		if arrInstanceID.Valid {
 			path["arr_instance_id"] = arrInstanceID.Int64
 		}
+		if arrInstanceTag != "" {
+			path["arr_instance_tag"] = arrInstanceTag
+		}
 		if detectionArgs.Valid && detectionArgs.String != "" {
 			path["detection_args"] = detectionArgs.String
 		}
 		if verificationTimeout.Valid {
 			path["verification_timeout_hours"] = verificationTimeout.Int64
 		}
+		if detectionFallbacks.Valid {
+			path["detection_fallbacks"] = detectionFallbacks.String
+		}
+		if minFileAgeMinutes.Valid {
+			path["min_file_age_minutes"] = minFileAgeMinutes.Int64
+		}
+		if sizeStabilitySeconds.Valid {
+			path["size_stability_seconds"] = sizeStabilitySeconds.Int64
+		}
+		if remoteURL != "" {
+			path["remote_url"] = remoteURL
+			path["remote_username"] = remoteUsername
+			password := decryptRemotePassword(remotePassword)
+			if !includeSecrets {
+				password = redact.Value(password)
+			}
+			path["remote_password"] = password
+			path["remote_host_key_fingerprint"] = remoteHostKeyFingerprint
+			path["remote_requests_per_minute"] = remoteRequestsPerMinute
+		}
 		paths = append(paths, path)
 	}
 	if err := rows.Err(); err != nil {
@@ -160,9 +207,10 @@ func (s *RESTServer) exportScanPaths() []gin.H {
 // exportSchedules exports scan schedules from the database.
 func (s *RESTServer) exportSchedules() []gin.H {
 	rows, err := s.db.Query(`
-		SELECT ss.cron_expression, ss.enabled, sp.local_path
+		SELECT ss.cron_expression, ss.enabled, sp.local_path, ss.timezone, ss.dst_gap, ss.dst_overlap
 		FROM scan_schedules ss
 		JOIN scan_paths sp ON ss.scan_path_id = sp.id
+		WHERE sp.deleted_at IS NULL
 	`)
 	if err != nil {
 		logger.Debugf("Failed to query schedules for export: %v", err)
@@ -172,14 +220,15 @@ func (s *RESTServer) exportSchedules() []gin.H {
 
 	var schedules []gin.H
 	for rows.Next() {
-		var cronExpr, localPath string
+		var cronExpr, localPath, timezone, dstGap, dstOverlap string
 		var enabled bool
-		if err := rows.Scan(&cronExpr, &enabled, &localPath); err != nil {
+		if err := rows.Scan(&cronExpr, &enabled, &localPath, &timezone, &dstGap, &dstOverlap); err != nil {
 			logger.Errorf("Failed to scan schedule for export: %v", err)
 			continue
 		}
 		schedules = append(schedules, gin.H{
 			"local_path": localPath, "cron_expression": cronExpr, "enabled": enabled,
+			"timezone": timezone, "dst_gap": dstGap, "dst_overlap": dstOverlap,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -188,8 +237,9 @@ func (s *RESTServer) exportSchedules() []gin.H {
 	return schedules
 }
 
-// exportNotifications exports notification configs.
-func (s *RESTServer) exportNotifications() []gin.H {
+// exportNotifications exports notification configs, with their secrets
+// masked unless includeSecrets is set.
+func (s *RESTServer) exportNotifications(includeSecrets bool) []gin.H {
 	if s.notifier == nil {
 		return nil
 	}
@@ -199,32 +249,38 @@ func (s *RESTServer) exportNotifications() []gin.H {
 	}
 	var notifConfigs []gin.H
 	for _, cfg := range configs {
+		if !includeSecrets {
+			cfg.Config = cfg.MaskedConfig()
+		}
 		notifConfigs = append(notifConfigs, gin.H{
 			"name": cfg.Name, "provider_type": cfg.ProviderType,
 			"config": cfg.Config, "events": cfg.Events,
 			"enabled": cfg.Enabled, "throttle_seconds": cfg.ThrottleSeconds,
+			"locale": cfg.Locale,
 		})
 	}
 	return notifConfigs
 }
 
-// exportConfig exports all configuration as JSON
+// exportConfig exports all configuration as JSON. Secrets are masked unless
+// include_secrets=true, which a backup to restore on another install needs.
 func (s *RESTServer) exportConfig(c *gin.Context) {
+	includeSecrets := c.Query("include_secrets") == "true"
 	export := gin.H{
 		"exported_at": time.Now().Format(time.RFC3339),
 		"version":     config.Version,
 	}
 
-	if instances := s.exportArrInstances(); instances != nil {
+	if instances := s.exportArrInstances(includeSecrets); instances != nil {
 		export["arr_instances"] = instances
 	}
-	if paths := s.exportScanPaths(); paths != nil {
+	if paths := s.exportScanPaths(includeSecrets); paths != nil {
 		export["scan_paths"] = paths
 	}
 	if schedules := s.exportSchedules(); schedules != nil {
 		export["schedules"] = schedules
 	}
-	if notifications := s.exportNotifications(); notifications != nil {
+	if notifications := s.exportNotifications(includeSecrets); notifications != nil {
 		export["notifications"] = notifications
 	}
 
@@ -248,23 +304,45 @@ type importArrInstance struct {
 }
 
 type importScanPath struct {
-	LocalPath                string `json:"local_path"`
-	ArrPath                  string `json:"arr_path"`
-	ArrInstanceID            *int   `json:"arr_instance_id"`
-	Enabled                  bool   `json:"enabled"`
-	AutoRemediate            bool   `json:"auto_remediate"`
-	DryRun                   bool   `json:"dry_run"`
-	DetectionMethod          string `json:"detection_method"`
-	DetectionArgs            string `json:"detection_args"`
-	DetectionMode            string `json:"detection_mode"`
-	MaxRetries               int    `json:"max_retries"`
-	VerificationTimeoutHours *int   `json:"verification_timeout_hours"`
+	LocalPath                string  `json:"local_path"`
+	ArrPath                  string  `json:"arr_path"`
+	ArrInstanceID            *int    `json:"arr_instance_id"`
+	ArrInstanceTag           string  `json:"arr_instance_tag"`
+	Enabled                  bool    `json:"enabled"`
+	AutoRemediate            bool    `json:"auto_remediate"`
+	DryRun                   bool    `json:"dry_run"`
+	DetectionMethod          string  `json:"detection_method"`
+	DetectionArgs            string  `json:"detection_args"`
+	DetectionMode            string  `json:"detection_mode"`
+	MaxRetries               int     `json:"max_retries"`
+	VerificationTimeoutHours *int    `json:"verification_timeout_hours"`
+	DetectionFallbacks       *string `json:"detection_fallbacks"`
+	MinFileAgeMinutes        *int    `json:"min_file_age_minutes"`
+	SizeStabilitySeconds     int     `json:"size_stability_seconds"`
+	QualityPin               string  `json:"quality_pin"`
+	Priority                 int     `json:"priority"`
+	ResearchIntervalDays     int     `json:"research_interval_days"`
+	ResearchPeriodDays       int     `json:"research_period_days"`
+	ProtocolPreference       string  `json:"protocol_preference"`
+	RemoteURL                string  `json:"remote_url"`
+	RemoteUsername           string  `json:"remote_username"`
+	RemotePassword           string  `json:"remote_password"`
+	RemoteHostKeyFingerprint string  `json:"remote_host_key_fingerprint"`
+	RemoteRequestsPerMinute  int     `json:"remote_requests_per_minute"`
+	MediaExtensions          string  `json:"media_extensions"`
+	MinFileSizeMB            int     `json:"min_file_size_mb"`
+	DurationCheck            string  `json:"duration_check"`
+	RolloutDailyLimit        int     `json:"rollout_daily_limit"`
+	RolloutDays              int     `json:"rollout_days"`
+	MinConfidence            int     `json:"min_confidence"`
+	SymlinkPolicy            string  `json:"symlink_policy"`
 }
 
 type importSchedule struct {
 	LocalPath      string `json:"local_path"`
 	CronExpression string `json:"cron_expression"`
 	Enabled        bool   `json:"enabled"`
+	domain.ScheduleTiming
 }
 
 type importNotification struct {
@@ -274,6 +352,7 @@ type importNotification struct {
 	Events          []string `json:"events"`
 	Enabled         bool     `json:"enabled"`
 	ThrottleSeconds int      `json:"throttle_seconds"`
+	Locale          string   `json:"locale"`
 }
 
 // importArrInstances imports arr instances and returns the count.
@@ -283,13 +362,18 @@ func (s *RESTServer) importArrInstances(instances []importArrInstance) int {
 	for _, inst := range instances {
 		// Check if an instance with the same URL already exists
 		var existingID int
-		err := s.db.QueryRow("SELECT id FROM arr_instances WHERE url = ?", inst.URL).Scan(&existingID)
+		err := s.db.QueryRow("SELECT id FROM arr_instances WHERE url = ? AND deleted_at IS NULL", inst.URL).Scan(&existingID)
 		if err == nil {
 			// Instance already exists, skip
 			logger.Debugf("Skipping duplicate arr instance with URL %s (existing ID: %d)", inst.URL, existingID)
 			continue
 		}
 
+		if inst.APIKey == redact.Mask {
+			logger.Warnf("Importing arr instance %s disabled: the export has its API key masked", inst.Name)
+			inst.APIKey, inst.Enabled = "", false
+		}
+		redact.Register(inst.APIKey)
 		encryptedKey, err := crypto.Encrypt(inst.APIKey)
 		if err != nil {
 			logger.Errorf("Failed to encrypt API key for import: %v", err)
@@ -320,6 +404,48 @@ func normalizeScanPathDefaults(path *importScanPath) {
 	if path.ArrPath == "" {
 		path.ArrPath = path.LocalPath
 	}
+	path.ArrInstanceTag = services.NormalizeArrTag(path.ArrInstanceTag)
+	if path.MinFileAgeMinutes == nil || *path.MinFileAgeMinutes < 0 || *path.MinFileAgeMinutes > maxMinFileAgeMinutes {
+		minutes := defaultMinFileAgeMinutes
+		path.MinFileAgeMinutes = &minutes
+	}
+	if path.SizeStabilitySeconds < 0 || path.SizeStabilitySeconds > maxSizeStabilitySeconds {
+		path.SizeStabilitySeconds = 0
+	}
+	if !services.ValidQualityPin(path.QualityPin) {
+		path.QualityPin = services.QualityPinOff
+	}
+	path.Priority = max(0, min(path.Priority, maxScanPathPriority))
+	path.ResearchIntervalDays = max(0, min(path.ResearchIntervalDays, maxResearchIntervalDays))
+	if path.ResearchPeriodDays <= 0 || path.ResearchPeriodDays > maxResearchPeriodDays {
+		path.ResearchPeriodDays = defaultResearchPeriodDays
+	}
+	if !services.ValidProtocolPreference(path.ProtocolPreference) {
+		path.ProtocolPreference = services.ProtocolAny
+	}
+	if !services.ValidDurationCheck(path.DurationCheck) {
+		path.DurationCheck = services.DurationCheckOff
+	}
+	if !services.ValidSymlinkPolicy(path.SymlinkPolicy) {
+		path.SymlinkPolicy = services.SymlinkSkip
+	}
+	if services.ValidateRollout(path.RolloutDailyLimit, path.RolloutDays) != nil {
+		path.RolloutDailyLimit, path.RolloutDays = 0, 0
+	}
+	if services.ValidateMinConfidence(path.MinConfidence) != nil {
+		path.MinConfidence = 0
+	}
+	if path.RemoteURL != "" && remote.ValidateURL(path.RemoteURL) != nil {
+		logger.Warnf("Importing scan path %s as a local path: invalid remote URL", path.LocalPath)
+		path.RemoteURL = ""
+	}
+	if path.RemoteURL == "" {
+		path.RemoteUsername, path.RemotePassword, path.RemoteHostKeyFingerprint = "", "", ""
+	}
+	path.RemoteRequestsPerMinute = remote.NormalizeRequestsPerMinute(path.RemoteRequestsPerMinute)
+	// Keep the valid extensions of a hand-edited export
+	path.MediaExtensions, _ = services.NormalizeMediaExtensions(path.MediaExtensions)
+	path.MinFileSizeMB = max(0, min(path.MinFileSizeMB, maxMinFileSizeMB))
 }
 
 // importScanPaths imports scan paths and returns count and path ID mapping.
@@ -332,7 +458,7 @@ func (s *RESTServer) importScanPaths(paths []importScanPath) (int, map[string]in
 
 		// Check if a scan path with the same local_path already exists
 		var existingID int64
-		err := s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ?", path.LocalPath).Scan(&existingID)
+		err := s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ? AND deleted_at IS NULL", path.LocalPath).Scan(&existingID)
 		if err == nil {
 			// Path already exists, add to mapping but don't count as imported
 			logger.Debugf("Skipping duplicate scan path %s (existing ID: %d)", path.LocalPath, existingID)
@@ -341,12 +467,42 @@ func (s *RESTServer) importScanPaths(paths []importScanPath) (int, map[string]in
 		}
 
 		normalizeScanPathDefaults(path)
+		if err := s.deletedConfig().PurgeDeletedScanPathAt(path.LocalPath); err != nil {
+			logger.Errorf("Failed to import scan path %s: %v", path.LocalPath, err)
+			continue
+		}
+		if path.ArrInstanceTag != "" {
+			// The exported instance ID belongs to another install; bind by tag where possible
+			path.ArrInstanceID = nil
+			if id, err := services.ResolveArrInstanceTag(s.db, path.ArrInstanceTag); err == nil {
+				instanceID := int(id)
+				path.ArrInstanceID = &instanceID
+			}
+		}
+
+		if path.RemotePassword == redact.Mask {
+			logger.Warnf("Importing scan path %s disabled: the export has its remote password masked", path.LocalPath)
+			path.RemotePassword, path.Enabled = "", false
+		}
+		remotePassword, err := crypto.Encrypt(path.RemotePassword)
+		if err != nil {
+			logger.Errorf("Failed to encrypt remote password for import: %v", err)
+			continue
+		}
 
 		result, err := s.db.Exec(`INSERT INTO scan_paths
-			(local_path, arr_path, arr_instance_id, enabled, auto_remediate, dry_run, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			path.LocalPath, path.ArrPath, path.ArrInstanceID, path.Enabled, path.AutoRemediate, path.DryRun,
-			path.DetectionMethod, path.DetectionArgs, path.DetectionMode, path.MaxRetries, path.VerificationTimeoutHours)
+			(local_path, arr_path, arr_instance_id, arr_instance_tag, enabled, auto_remediate, dry_run, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours, detection_fallbacks,
+			 min_file_age_minutes, size_stability_seconds, quality_pin, priority, research_interval_days, research_period_days, protocol_preference,
+			 remote_url, remote_username, remote_password, remote_host_key_fingerprint, remote_requests_per_minute, media_extensions, min_file_size_mb, duration_check,
+			 rollout_daily_limit, rollout_days, min_confidence, symlink_policy)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			path.LocalPath, path.ArrPath, path.ArrInstanceID, path.ArrInstanceTag, path.Enabled, path.AutoRemediate, path.DryRun,
+			path.DetectionMethod, path.DetectionArgs, path.DetectionMode, path.MaxRetries, path.VerificationTimeoutHours,
+			path.DetectionFallbacks, *path.MinFileAgeMinutes, path.SizeStabilitySeconds, path.QualityPin, path.Priority,
+			path.ResearchIntervalDays, path.ResearchPeriodDays, path.ProtocolPreference,
+			path.RemoteURL, path.RemoteUsername, remotePassword, path.RemoteHostKeyFingerprint, path.RemoteRequestsPerMinute,
+			path.MediaExtensions, path.MinFileSizeMB, path.DurationCheck, path.RolloutDailyLimit, path.RolloutDays,
+			path.MinConfidence, path.SymlinkPolicy)
 		if err == nil {
 			count++
 			if newID, idErr := result.LastInsertId(); idErr == nil {
@@ -368,7 +524,7 @@ func (s *RESTServer) importSchedules(schedules []importSchedule, pathIDs map[str
 	for _, sched := range schedules {
 		scanPathID, exists := pathIDs[sched.LocalPath]
 		if !exists {
-			row := s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ?", sched.LocalPath)
+			row := s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ? AND deleted_at IS NULL", sched.LocalPath)
 			if err := row.Scan(&scanPathID); err != nil {
 				logger.Errorf("Failed to find scan path for schedule (local_path=%s): %v", sched.LocalPath, err)
 				continue
@@ -384,8 +540,9 @@ func (s *RESTServer) importSchedules(schedules []importSchedule, pathIDs map[str
 			continue
 		}
 
-		_, err = s.db.Exec("INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled) VALUES (?, ?, ?)",
-			scanPathID, sched.CronExpression, sched.Enabled)
+		timing := sched.ScheduleTiming.WithDefaults()
+		_, err = s.db.Exec("INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled, timezone, dst_gap, dst_overlap) VALUES (?, ?, ?, ?, ?, ?)",
+			scanPathID, sched.CronExpression, sched.Enabled, timing.Timezone, timing.DSTGap, timing.DSTOverlap)
 		if err == nil {
 			count++
 		} else {
@@ -423,6 +580,17 @@ func (s *RESTServer) importNotifications(notifications []importNotification) int
 			continue
 		}
 
+		if redact.HasMask(configBytes) {
+			logger.Warnf("Importing notification %s disabled: the export has its secrets masked", notif.Name)
+			notif.Enabled = false
+		}
+
+		// An unsupported locale falls back to the default one
+		locale, ok := i18n.Normalize(notif.Locale)
+		if !ok {
+			locale = ""
+		}
+
 		cfg := &notifierConfig{
 			Name:            notif.Name,
 			ProviderType:    notif.ProviderType,
@@ -430,6 +598,7 @@ func (s *RESTServer) importNotifications(notifications []importNotification) int
 			Events:          notif.Events,
 			Enabled:         notif.Enabled,
 			ThrottleSeconds: notif.ThrottleSeconds,
+			Locale:          locale,
 		}
 
 		if _, err := s.notifier.CreateConfig(cfg); err == nil {
@@ -444,8 +613,7 @@ func (s *RESTServer) importNotifications(notifications []importNotification) int
 // importConfig imports configuration from JSON
 func (s *RESTServer) importConfig(c *gin.Context) {
 	var req importConfigRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -454,7 +622,19 @@ func (s *RESTServer) importConfig(c *gin.Context) {
 	schedCount := s.importSchedules(req.Schedules, pathIDs)
 	notifCount := s.importNotifications(req.Notifications)
 
+	// Tag-bound paths imported before their instance's tags were cached are bound once tags are fetched
+	for _, path := range req.ScanPaths {
+		if path.ArrInstanceTag != "" {
+			s.syncArrTagsInBackground()
+			break
+		}
+	}
+
 	// Reload path mappings and scheduler
+	s.remotePaths.Invalidate()
+	if pathCount > 0 {
+		s.publishScanPathsChanged("imported")
+	}
 	if s.pathMapper != nil {
 		if err := s.pathMapper.Reload(); err != nil {
 			logger.Errorf("Failed to reload path mappings after import: %v", err)
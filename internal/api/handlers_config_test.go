@@ -64,7 +64,8 @@ func setupConfigTestDB(t *testing.T) (*sql.DB, func()) {
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
 			enabled INTEGER DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_paths (
@@ -72,6 +73,24 @@ func setupConfigTestDB(t *testing.T) (*sql.DB, func()) {
 			local_path TEXT NOT NULL,
 			arr_path TEXT NOT NULL,
 			arr_instance_id INTEGER REFERENCES arr_instances(id) ON DELETE SET NULL,
+			arr_instance_tag TEXT NOT NULL DEFAULT '',
+			quality_pin TEXT NOT NULL DEFAULT 'off',
+			priority INTEGER NOT NULL DEFAULT 0,
+			research_interval_days INTEGER NOT NULL DEFAULT 0,
+			research_period_days INTEGER NOT NULL DEFAULT 90,
+			protocol_preference TEXT NOT NULL DEFAULT 'any',
+			remote_url TEXT NOT NULL DEFAULT '',
+			remote_username TEXT NOT NULL DEFAULT '',
+			remote_password TEXT NOT NULL DEFAULT '',
+			remote_host_key_fingerprint TEXT NOT NULL DEFAULT '',
+			remote_requests_per_minute INTEGER NOT NULL DEFAULT 60,
+			media_extensions TEXT NOT NULL DEFAULT '',
+			min_file_size_mb INTEGER NOT NULL DEFAULT 0,
+			duration_check TEXT NOT NULL DEFAULT 'off',
+			symlink_policy TEXT NOT NULL DEFAULT 'skip',
+			rollout_daily_limit INTEGER NOT NULL DEFAULT 0,
+			rollout_days INTEGER NOT NULL DEFAULT 0,
+			min_confidence INTEGER NOT NULL DEFAULT 0,
 			enabled INTEGER DEFAULT 1,
 			auto_remediate INTEGER DEFAULT 1,
 			dry_run INTEGER DEFAULT 0,
@@ -80,13 +99,20 @@ func setupConfigTestDB(t *testing.T) (*sql.DB, func()) {
 			detection_mode TEXT DEFAULT 'quick',
 			max_retries INTEGER DEFAULT 3,
 			verification_timeout_hours INTEGER DEFAULT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			detection_fallbacks TEXT DEFAULT NULL,
+			min_file_age_minutes INTEGER DEFAULT 2,
+			size_stability_seconds INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_schedules (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL REFERENCES scan_paths(id) ON DELETE CASCADE,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled INTEGER DEFAULT 1,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
@@ -97,8 +123,10 @@ func setupConfigTestDB(t *testing.T) (*sql.DB, func()) {
 			provider_type TEXT NOT NULL,
 			config TEXT NOT NULL,
 			events TEXT DEFAULT '[]',
+			group_ids TEXT NOT NULL DEFAULT '[]',
 			enabled INTEGER DEFAULT 1,
 			throttle_seconds INTEGER DEFAULT 0,
+			locale TEXT NOT NULL DEFAULT '',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// tagRequest names corruptions and the tags to attach to or detach from them.
+type tagRequest struct {
+	IDs  []string `json:"ids" binding:"min=1"`
+	Tags []string `json:"tags" binding:"min=1"`
+}
+
+// bindTagRequest parses a tagRequest, responding with 400 if it is incomplete.
+func bindTagRequest(c *gin.Context) (*tagRequest, bool) {
+	var req tagRequest
+	if !bindBody(c, &req) {
+		return nil, false
+	}
+	return &req, true
+}
+
+// getCorruptionTags lists the tags in use and how many corruptions carry each.
+// GET /api/corruptions/tags
+func (s *RESTServer) getCorruptionTags(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	tags, err := s.tags.ListTags(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// tagCorruptions attaches tags to the selected corruptions.
+// POST /api/corruptions/tags
+func (s *RESTServer) tagCorruptions(c *gin.Context) {
+	req, ok := bindTagRequest(c)
+	if !ok {
+		return
+	}
+	if err := s.tags.AddTags(req.IDs, req.Tags); err != nil {
+		if errors.Is(err, services.ErrInvalidTag) {
+			respondBadRequest(c, err, true)
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Tagged %d corruption(s)", len(req.IDs))})
+}
+
+// untagCorruptions detaches tags from the selected corruptions.
+// POST /api/corruptions/tags/remove
+func (s *RESTServer) untagCorruptions(c *gin.Context) {
+	req, ok := bindTagRequest(c)
+	if !ok {
+		return
+	}
+	if err := s.tags.RemoveTags(req.IDs, req.Tags); err != nil {
+		if errors.Is(err, services.ErrInvalidTag) {
+			respondBadRequest(c, err, true)
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Untagged %d corruption(s)", len(req.IDs))})
+}
+
+// getSavedFilters lists the saved corruption filters.
+// GET /api/corruptions/filters
+func (s *RESTServer) getSavedFilters(c *gin.Context) {
+	filters, err := s.tags.ListFilters()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, filters)
+}
+
+// bindSavedFilter parses a saved filter, responding with 400 if it names an
+// unknown status.
+func bindSavedFilter(c *gin.Context) (*services.SavedFilter, bool) {
+	var f services.SavedFilter
+	if !bindBody(c, &f) {
+		return nil, false
+	}
+	for _, status := range f.Statuses {
+		if _, ok := statusFilterClauses[status]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown status %q", status)})
+			return nil, false
+		}
+	}
+	return &f, true
+}
+
+// respondSavedFilterError maps saved filter errors to responses.
+func respondSavedFilterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSavedFilterNotFound):
+		respondNotFound(c, "Saved filter")
+	case errors.Is(err, services.ErrSavedFilterExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSavedFilter), errors.Is(err, services.ErrInvalidTag):
+		respondBadRequest(c, err, true)
+	default:
+		respondDatabaseError(c, err)
+	}
+}
+
+// createSavedFilter saves a named combination of statuses, tags and scan paths.
+// POST /api/corruptions/filters
+func (s *RESTServer) createSavedFilter(c *gin.Context) {
+	f, ok := bindSavedFilter(c)
+	if !ok {
+		return
+	}
+	id, err := s.tags.CreateFilter(f)
+	if err != nil {
+		respondSavedFilterError(c, err)
+		return
+	}
+	s.respondSavedFilter(c, id, http.StatusCreated)
+}
+
+// updateSavedFilter replaces the name and criteria of a saved filter.
+// PUT /api/corruptions/filters/:id
+func (s *RESTServer) updateSavedFilter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	f, ok := bindSavedFilter(c)
+	if !ok {
+		return
+	}
+	if err := s.tags.UpdateFilter(id, f); err != nil {
+		respondSavedFilterError(c, err)
+		return
+	}
+	s.respondSavedFilter(c, id, http.StatusOK)
+}
+
+// deleteSavedFilter removes a saved filter.
+// DELETE /api/corruptions/filters/:id
+func (s *RESTServer) deleteSavedFilter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	if err := s.tags.DeleteFilter(id); err != nil {
+		respondSavedFilterError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Saved filter deleted"})
+}
+
+// respondSavedFilter sends a saved filter as stored.
+func (s *RESTServer) respondSavedFilter(c *gin.Context, id int64, status int) {
+	f, err := s.tags.GetFilter(id)
+	if err != nil {
+		respondSavedFilterError(c, err)
+		return
+	}
+	c.JSON(status, f)
+}
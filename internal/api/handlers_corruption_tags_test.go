@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+func TestCorruptionTagsAndSavedFilters(t *testing.T) {
+	db, cleanup := setupCorruptionsTestDB(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	now := time.Now()
+	seedCorruptionEvent(t, db, "a", domain.CorruptionDetected, map[string]interface{}{"file_path": "/tv/a.mkv", "path_id": 1}, now)
+	seedCorruptionEvent(t, db, "b", domain.CorruptionDetected, map[string]interface{}{"file_path": "/tv/b.mkv", "path_id": 1}, now)
+	seedCorruptionEvent(t, db, "c", domain.CorruptionDetected, map[string]interface{}{"file_path": "/movies/c.mkv", "path_id": 2}, now)
+	seedCorruptionEvent(t, db, "c", domain.MaxRetriesReached, map[string]interface{}{}, now.Add(time.Minute))
+
+	server := createCorruptionsTestServer(t, db, eb)
+	defer server.scanner.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/corruptions", server.getCorruptions)
+	r.GET("/corruptions/tags", server.getCorruptionTags)
+	r.POST("/corruptions/tags", server.tagCorruptions)
+	r.POST("/corruptions/tags/remove", server.untagCorruptions)
+	r.POST("/corruptions/filters", server.createSavedFilter)
+	r.PUT("/corruptions/filters/:id", server.updateSavedFilter)
+	r.DELETE("/corruptions/filters/:id", server.deleteSavedFilter)
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		r.ServeHTTP(w, req)
+		return w
+	}
+	listIDs := func(t *testing.T, url string) []string {
+		t.Helper()
+		w := do("GET", url, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response struct {
+			Data []struct {
+				ID   string   `json:"id"`
+				Tags []string `json:"tags"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := make([]string, 0, len(response.Data))
+		for _, d := range response.Data {
+			ids = append(ids, d.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	require.Equal(t, http.StatusOK, do("POST", "/corruptions/tags", `{"ids": ["a", "c"], "tags": ["Disk-Failure-2024"]}`).Code)
+	require.Equal(t, http.StatusOK, do("POST", "/corruptions/tags", `{"ids": ["a"], "tags": ["ignore-next-scan"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/corruptions/tags", `{"ids": ["a"], "tags": ["a,b"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/corruptions/tags", `{"ids": ["a"]}`).Code)
+
+	t.Run("tag filter", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "c"}, listIDs(t, "/corruptions?tag=disk-failure-2024"))
+		assert.Equal(t, []string{"a"}, listIDs(t, "/corruptions?tag=disk-failure-2024,ignore-next-scan"))
+		assert.Equal(t, []string{"a", "c"}, listIDs(t, "/corruptions?path_id=1,2&tag=disk-failure-2024"))
+		assert.Equal(t, []string{"a", "b", "c"}, listIDs(t, "/corruptions?status=pending,orphaned"))
+	})
+
+	t.Run("tag counts", func(t *testing.T) {
+		w := do("GET", "/corruptions/tags", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var tags []services.TagCount
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+		assert.Equal(t, []services.TagCount{{Tag: "disk-failure-2024", Corruptions: 2}, {Tag: "ignore-next-scan", Corruptions: 1}}, tags)
+	})
+
+	t.Run("saved filter", func(t *testing.T) {
+		w := do("POST", "/corruptions/filters", `{"name": "Failing disk", "statuses": ["orphaned"], "tags": ["disk-failure-2024"]}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var saved services.SavedFilter
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &saved))
+
+		filterURL := fmt.Sprintf("/corruptions?filter=%d", saved.ID)
+		assert.Equal(t, []string{"c"}, listIDs(t, filterURL))
+		assert.Empty(t, listIDs(t, filterURL+"&path_id=1"))
+
+		assert.Equal(t, http.StatusConflict, do("POST", "/corruptions/filters", `{"name": "Failing disk"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do("POST", "/corruptions/filters", `{"name": "x", "statuses": ["bogus"]}`).Code)
+
+		w = do("PUT", fmt.Sprintf("/corruptions/filters/%d", saved.ID), `{"name": "Failing disk", "tags": ["disk-failure-2024"], "path_ids": [1]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"a"}, listIDs(t, filterURL))
+
+		require.Equal(t, http.StatusOK, do("DELETE", fmt.Sprintf("/corruptions/filters/%d", saved.ID), "").Code)
+		assert.Equal(t, http.StatusNotFound, do("GET", filterURL, "").Code)
+	})
+
+	t.Run("remove tag", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do("POST", "/corruptions/tags/remove", `{"ids": ["a"], "tags": ["disk-failure-2024"]}`).Code)
+		assert.Equal(t, []string{"c"}, listIDs(t, "/corruptions?tag=disk-failure-2024"))
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
 )
 
@@ -25,16 +26,16 @@ var statusFilterClauses = map[string]string{
 	// Granular technical filters (kept for API compatibility and detail views)
 	"active":              "current_state != 'VerificationSuccess' AND current_state != 'MaxRetriesReached' AND current_state != 'CorruptionIgnored'",
 	"pending":             "current_state = 'CorruptionDetected'",
-	"in_progress":         "(current_state LIKE '%Started' OR current_state LIKE '%Queued' OR current_state LIKE '%Progress' OR current_state = 'RemediationQueued')",
+	"in_progress":         "(current_state LIKE '%Started' OR current_state LIKE '%Queued' OR current_state LIKE '%Progress' OR current_state = 'DownloadRejected' OR current_state = 'RemediationQueued' OR current_state = 'RemediationDeferred' OR current_state = 'ScheduledResearch')",
 	"resolved":            "current_state = 'VerificationSuccess'",
 	"failed":              "current_state LIKE '%Failed'",
 	"orphaned":            "current_state = 'MaxRetriesReached'",
 	"ignored":             "current_state = 'CorruptionIgnored'",
-	"manual_intervention": "(current_state = 'ImportBlocked' OR current_state = 'ManuallyRemoved')",
+	"manual_intervention": "(current_state = 'ImportBlocked' OR current_state = 'ManuallyRemoved' OR current_state = 'BudgetApprovalRequired' OR current_state = 'LowConfidenceDetection' OR current_state = 'QualityRegression' OR current_state = 'AudioTrackMissing' OR current_state = 'DeletionPlanChanged')",
 
 	// User-friendly combined filters (for simplified UI)
-	"action_required": "(current_state = 'ImportBlocked' OR current_state = 'ManuallyRemoved' OR current_state = 'MaxRetriesReached')",
-	"working":         "(current_state = 'CorruptionDetected' OR current_state LIKE '%Started' OR current_state LIKE '%Queued' OR current_state LIKE '%Progress' OR current_state = 'RemediationQueued' OR (current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached'))",
+	"action_required": "(current_state = 'ImportBlocked' OR current_state = 'ManuallyRemoved' OR current_state = 'MaxRetriesReached' OR current_state = 'BudgetApprovalRequired' OR current_state = 'LowConfidenceDetection' OR current_state = 'QualityRegression' OR current_state = 'AudioTrackMissing' OR current_state = 'DeletionPlanChanged')",
+	"working":         "(current_state = 'CorruptionDetected' OR current_state LIKE '%Started' OR current_state LIKE '%Queued' OR current_state LIKE '%Progress' OR current_state = 'DownloadRejected' OR current_state = 'RemediationQueued' OR current_state = 'RemediationDeferred' OR current_state = 'ScheduledResearch' OR (current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached'))",
 }
 
 // extractJSONString extracts a string value from a map if it exists and is non-empty.
@@ -89,26 +90,34 @@ func (s *RESTServer) getCorruptions(c *gin.Context) {
 		},
 	}
 	p := ParsePagination(c, cfg)
-	statusFilter := c.DefaultQuery("status", "all")
-	pathIDFilter := c.Query("path_id")
 
 	// Build query
-	baseQuery := "FROM corruption_status"
+	baseQuery := "FROM corruption_summary"
 	whereClauses := []string{}
 	args := []interface{}{}
 
-	// Status filter - use map lookup instead of switch
-	if clause, ok := statusFilterClauses[statusFilter]; ok {
-		whereClauses = append(whereClauses, clause)
+	// A saved filter applies first; the query parameters narrow it further
+	if filterID := c.Query("filter"); filterID != "" {
+		id, err := strconv.ParseInt(filterID, 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+			return
+		}
+		saved, err := s.tags.GetFilter(id)
+		if err != nil {
+			respondSavedFilterError(c, err)
+			return
+		}
+		whereClauses, args = appendCorruptionFilter(whereClauses, args, saved.Statuses, saved.PathIDs, saved.Tags)
 	}
-
-	// Path ID filter (for filtering by scan path)
-	if pathIDFilter != "" {
-		if pathID, err := strconv.ParseInt(pathIDFilter, 10, 64); err == nil {
-			whereClauses = append(whereClauses, "path_id = ?")
-			args = append(args, pathID)
+	var pathIDs []int64
+	for _, v := range splitQueryList(c.Query("path_id")) {
+		if pathID, err := strconv.ParseInt(v, 10, 64); err == nil {
+			pathIDs = append(pathIDs, pathID)
 		}
 	}
+	whereClauses, args = appendCorruptionFilter(whereClauses, args,
+		splitQueryList(c.DefaultQuery("status", "all")), pathIDs, splitQueryList(strings.ToLower(c.Query("tag"))))
 
 	// Build WHERE clause
 	whereClause := ""
@@ -120,7 +129,7 @@ func (s *RESTServer) getCorruptions(c *gin.Context) {
 	// Security: whereClause contains only fixed strings with ? placeholders, user values are in args
 	var total int
 	countQuery := "SELECT COUNT(*) " + baseQuery + whereClause // NOSONAR - uses parameterized query with args
-	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := s.reader().QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
@@ -140,7 +149,7 @@ func (s *RESTServer) getCorruptions(c *gin.Context) {
 	query := fmt.Sprintf("SELECT corruption_id, current_state, retry_count, file_path, path_id, last_error, detected_at, last_updated_at, corruption_type %s%s %s LIMIT ? OFFSET ?", baseQuery, whereClause, orderByClause) // NOSONAR - parameterized query + validated ORDER BY
 	args = append(args, p.Limit, p.Offset)
 
-	rows, err := s.db.QueryContext(ctx, query, args...) // NOSONAR
+	rows, err := s.reader().QueryContext(ctx, query, args...) // NOSONAR
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -188,12 +197,87 @@ func (s *RESTServer) getCorruptions(c *gin.Context) {
 		return
 	}
 
+	// Flag corruptions of protected files and media, and add their tags
+	ids := make([]string, len(corruptions))
+	for i, corruption := range corruptions {
+		ids[i] = corruption["id"].(string)
+	}
+	protected := s.protectedCorruptions(ctx, ids)
+	tags := s.corruptionTags(ctx, ids)
+	for _, corruption := range corruptions {
+		id := corruption["id"].(string)
+		corruption["protected"] = protected[id]
+		if tags[id] != nil {
+			corruption["tags"] = tags[id]
+		} else {
+			corruption["tags"] = []string{}
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data":       corruptions,
 		"pagination": NewPaginationResponse(p, total),
 	})
 }
 
+// splitQueryList splits a comma-separated query parameter, dropping empty values.
+func splitQueryList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// appendCorruptionFilter adds the WHERE clauses of a corruption list filter: the
+// state matches any of statuses, the scan path is any of pathIDs, and all of
+// tags are attached. Unknown statuses and "all" don't restrict the list.
+func appendCorruptionFilter(whereClauses []string, args []interface{}, statuses []string, pathIDs []int64, tags []string) ([]string, []interface{}) {
+	var statusClauses []string
+	for _, status := range statuses {
+		if clause, ok := statusFilterClauses[status]; ok {
+			statusClauses = append(statusClauses, "("+clause+")")
+		}
+	}
+	if len(statusClauses) > 0 {
+		whereClauses = append(whereClauses, "("+strings.Join(statusClauses, " OR ")+")")
+	}
+
+	if len(pathIDs) > 0 {
+		whereClauses = append(whereClauses, "path_id IN ("+strings.TrimSuffix(strings.Repeat("?,", len(pathIDs)), ",")+")")
+		for _, id := range pathIDs {
+			args = append(args, id)
+		}
+	}
+
+	if len(tags) > 0 {
+		whereClauses = append(whereClauses, `corruption_id IN (
+			SELECT corruption_id FROM corruption_tags WHERE tag IN (`+strings.TrimSuffix(strings.Repeat("?,", len(tags)), ",")+`)
+			GROUP BY corruption_id HAVING COUNT(DISTINCT tag) = ?)`)
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		args = append(args, len(tags))
+	}
+	return whereClauses, args
+}
+
+// corruptionTags returns the tags of the given corruptions. Lookup failures
+// leave them untagged.
+func (s *RESTServer) corruptionTags(ctx context.Context, ids []string) map[string][]string {
+	if s.tags == nil {
+		return map[string][]string{}
+	}
+	tags, err := s.tags.TagsFor(ctx, ids)
+	if err != nil {
+		logger.Debugf("Failed to load corruption tags: %v", err)
+		return map[string][]string{}
+	}
+	return tags
+}
+
 // getEnrichedCorruptionData extracts enriched display data from event_data:
 // - file_size from CorruptionDetected
 // - media_title, media_type, arr_type from SearchCompleted
@@ -328,6 +412,9 @@ func (s *RESTServer) enrichFromDownloadProgress(ctx context.Context, corruptionI
 	if v, ok := extractJSONString(data, "time_left"); ok {
 		enriched["download_time_left"] = v
 	}
+	if v, ok := extractJSONString(data, "estimated_completion"); ok && v != "" {
+		enriched["download_estimated_completion"] = v
+	}
 }
 
 func (s *RESTServer) getRemediations(c *gin.Context) {
@@ -340,13 +427,13 @@ func (s *RESTServer) getRemediations(c *gin.Context) {
 
 	// Get total count
 	var total int
-	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM corruption_status WHERE current_state = ?", string(domain.VerificationSuccess)).Scan(&total); err != nil {
+	if err := s.reader().QueryRowContext(ctx, "SELECT COUNT(*) FROM corruption_summary WHERE current_state = ?", string(domain.VerificationSuccess)).Scan(&total); err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
 
 	// Get paginated data
-	rows, err := s.db.QueryContext(ctx, "SELECT corruption_id, file_path, last_updated_at FROM corruption_status WHERE current_state = ? ORDER BY last_updated_at DESC LIMIT ? OFFSET ?", string(domain.VerificationSuccess), p.Limit, p.Offset)
+	rows, err := s.reader().QueryContext(ctx, "SELECT corruption_id, file_path, last_updated_at FROM corruption_summary WHERE current_state = ? ORDER BY last_updated_at DESC LIMIT ? OFFSET ?", string(domain.VerificationSuccess), p.Limit, p.Offset)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -385,7 +472,7 @@ func (s *RESTServer) getCorruptionHistory(c *gin.Context) {
 	defer cancel()
 
 	id := c.Param("id")
-	rows, err := s.db.QueryContext(ctx, "SELECT event_type, event_data, created_at FROM events WHERE aggregate_id = ? ORDER BY created_at ASC", id)
+	rows, err := s.reader().QueryContext(ctx, "SELECT event_type, event_data, created_at FROM events WHERE aggregate_id = ? ORDER BY created_at ASC", id)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -423,6 +510,67 @@ func (s *RESTServer) getCorruptionHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, history)
 }
 
+// corruptionDiagnosticsEntry is one stored checker result for a corruption.
+type corruptionDiagnosticsEntry struct {
+	ID        int64  `json:"id"`
+	FilePath  string `json:"file_path"`
+	CreatedAt string `json:"created_at"`
+	integration.CheckDiagnostics
+}
+
+// getCorruptionDiagnostics returns the raw checker output (trimmed stdout/stderr,
+// exit code, duration) recorded when the corruption was detected.
+func (s *RESTServer) getCorruptionDiagnostics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	entries, err := s.loadCorruptionDiagnostics(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	if len(entries) == 0 {
+		respondNotFound(c, "Diagnostics")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corruption_id": id,
+		"diagnostics":   entries,
+	})
+}
+
+// loadCorruptionDiagnostics returns the stored checker results for a corruption,
+// skipping entries that cannot be decoded.
+func (s *RESTServer) loadCorruptionDiagnostics(ctx context.Context, id string) ([]corruptionDiagnosticsEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, file_path, data, created_at FROM corruption_diagnostics
+		WHERE corruption_id = ? ORDER BY id ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]corruptionDiagnosticsEntry, 0)
+	for rows.Next() {
+		var entry corruptionDiagnosticsEntry
+		var blob []byte
+		if err := rows.Scan(&entry.ID, &entry.FilePath, &blob, &entry.CreatedAt); err != nil {
+			continue
+		}
+		diag, err := integration.DecodeDiagnostics(blob)
+		if err != nil {
+			logger.Warnf("Skipping unreadable diagnostics %d for corruption %s: %v", entry.ID, id, err)
+			continue
+		}
+		entry.CheckDiagnostics = diag
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
 // retryCorruptions triggers a manual retry for selected corruptions
 func (s *RESTServer) retryCorruptions(c *gin.Context) {
 	// Create context with timeout to prevent blocking on DB locks
@@ -430,20 +578,22 @@ func (s *RESTServer) retryCorruptions(c *gin.Context) {
 	defer cancel()
 
 	var req struct {
-		IDs []string `json:"ids"`
+		IDs []string `json:"ids" binding:"min=1"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
-	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgNoIDsProvided})
-		return
-	}
+	// Protected files and media are never remediated, not even on request
+	protected := s.protectedCorruptions(ctx, req.IDs)
 
-	retried := 0
+	retried, skippedProtected := 0, 0
 	for _, id := range req.IDs {
+		if protected[id] {
+			skippedProtected++
+			continue
+		}
+
 		var filePath sql.NullString
 		var pathID sql.NullInt64
 		err := s.db.QueryRowContext(ctx, `
@@ -476,24 +626,38 @@ func (s *RESTServer) retryCorruptions(c *gin.Context) {
 		retried++
 	}
 
+	message := fmt.Sprintf("Retried %d corruption(s)", retried)
+	if skippedProtected > 0 {
+		message += fmt.Sprintf(", skipped %d protected", skippedProtected)
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Retried %d corruption(s)", retried),
-		"retried": retried,
+		"message":           message,
+		"retried":           retried,
+		"skipped_protected": skippedProtected,
 	})
 }
 
+// protectedCorruptions returns which of the given corruptions concern a
+// protected file or media item. Lookup failures leave them unflagged; the
+// remediator enforces protection on its own.
+func (s *RESTServer) protectedCorruptions(ctx context.Context, ids []string) map[string]bool {
+	if s.protection == nil {
+		return map[string]bool{}
+	}
+	protected, err := s.protection.ProtectedCorruptions(ctx, ids)
+	if err != nil {
+		logger.Debugf("Failed to look up protected corruptions: %v", err)
+		return map[string]bool{}
+	}
+	return protected
+}
+
 // ignoreCorruptions marks corruptions as ignored (excluded from stats)
 func (s *RESTServer) ignoreCorruptions(c *gin.Context) {
 	var req struct {
-		IDs []string `json:"ids"`
+		IDs []string `json:"ids" binding:"min=1"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgNoIDsProvided})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -524,15 +688,9 @@ func (s *RESTServer) deleteCorruptions(c *gin.Context) {
 	defer cancel()
 
 	var req struct {
-		IDs []string `json:"ids"`
+		IDs []string `json:"ids" binding:"min=1"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgNoIDsProvided})
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -551,6 +709,31 @@ func (s *RESTServer) deleteCorruptions(c *gin.Context) {
 		if rows > 0 {
 			deleted++
 		}
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM corruption_summary WHERE corruption_id = ?`, id); err != nil {
+			logger.Debugf("Failed to delete summary for corruption %s: %v", id, err)
+		}
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM corruption_diagnostics WHERE corruption_id = ?`, id); err != nil {
+			logger.Debugf("Failed to delete diagnostics for corruption %s: %v", id, err)
+		}
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM corruption_deletion_plans WHERE corruption_id = ?`, id); err != nil {
+			logger.Debugf("Failed to delete deletion plan for corruption %s: %v", id, err)
+		}
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM corruption_links WHERE corruption_id = ?`, id); err != nil {
+			logger.Debugf("Failed to delete links of corruption %s: %v", id, err)
+		}
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM corruption_mapping_overrides WHERE corruption_id = ?`, id); err != nil {
+			logger.Debugf("Failed to delete mapping override of corruption %s: %v", id, err)
+		}
+		if s.samples != nil {
+			if err := s.samples.Delete(ctx, id); err != nil {
+				logger.Debugf("Failed to delete sample of corruption %s: %v", id, err)
+			}
+		}
+		if s.tags != nil {
+			if err := s.tags.DeleteCorruption(id); err != nil {
+				logger.Debugf("Failed to delete tags for corruption %s: %v", id, err)
+			}
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
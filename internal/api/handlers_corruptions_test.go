@@ -17,7 +17,9 @@ import (
 
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 // setupCorruptionsTestDB creates a test database with schema for corruption tests
@@ -93,12 +95,60 @@ func setupCorruptionsTestDB(t *testing.T) (*sql.DB, func()) {
 		FROM events e
 		WHERE aggregate_type = 'corruption'
 		GROUP BY aggregate_id;
+
+		CREATE TABLE corruption_diagnostics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			corruption_id TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			data BLOB NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE corruption_tags (
+			corruption_id TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (corruption_id, tag)
+		);
+
+		CREATE TABLE saved_filters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			statuses TEXT NOT NULL DEFAULT '[]',
+			tags TEXT NOT NULL DEFAULT '[]',
+			path_ids TEXT NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE false_positives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			corruption_id TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			file_size INTEGER NOT NULL DEFAULT 0,
+			fingerprint TEXT NOT NULL DEFAULT '',
+			corruption_type TEXT NOT NULL DEFAULT '',
+			error_details TEXT NOT NULL DEFAULT '',
+			signature TEXT NOT NULL,
+			detection_tool TEXT NOT NULL DEFAULT '',
+			tool_version TEXT NOT NULL DEFAULT '',
+			checker_output BLOB,
+			note TEXT NOT NULL DEFAULT '',
+			suppressed_count INTEGER NOT NULL DEFAULT 0,
+			last_suppressed_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
 	`
 	if _, err := db.Exec(schema); err != nil {
 		db.Close()
 		os.RemoveAll(tmpDir)
 		t.Fatalf("Failed to create schema: %v", err)
 	}
+	if err := testutil.CreateCorruptionSummary(db); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create corruption summary: %v", err)
+	}
 
 	cleanup := func() {
 		db.Close()
@@ -142,6 +192,7 @@ func createCorruptionsTestServer(t *testing.T, db *sql.DB, eb *eventbus.EventBus
 		eventBus:   eb,
 		scanner:    scanner,
 		pathMapper: pm,
+		tags:       services.NewTagService(db),
 		metrics:    getGlobalMetricsService(eb),
 		hub:        NewWebSocketHub(eb),
 		startTime:  time.Now(),
@@ -922,6 +973,73 @@ func TestGetCorruptionHistory_EmptyHistory(t *testing.T) {
 	}
 }
 
+func TestGetCorruptionDiagnostics(t *testing.T) {
+	db, cleanup := setupCorruptionsTestDB(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	blob, err := integration.EncodeDiagnostics(integration.CheckDiagnostics{
+		ErrorType: integration.ErrorTypeCorruptHeader,
+		Message:   "ffprobe failed: moov atom not found",
+		Runs: []integration.ToolRun{
+			{Tool: "ffprobe", Args: []string{"/test/file.mkv"}, ExitCode: 1, DurationMs: 12, Stderr: "moov atom not found"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode diagnostics: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO corruption_diagnostics (corruption_id, file_path, data) VALUES (?, ?, ?)`,
+		"test-corruption", "/test/file.mkv", blob); err != nil {
+		t.Fatalf("Failed to seed diagnostics: %v", err)
+	}
+
+	server := createCorruptionsTestServer(t, db, eb)
+	defer server.scanner.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/corruptions/:id/diagnostics", server.getCorruptionDiagnostics)
+
+	req, _ := http.NewRequest("GET", "/corruptions/test-corruption/diagnostics", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		CorruptionID string `json:"corruption_id"`
+		Diagnostics  []struct {
+			FilePath  string                `json:"file_path"`
+			ErrorType string                `json:"error_type"`
+			Runs      []integration.ToolRun `json:"runs"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.CorruptionID != "test-corruption" || len(resp.Diagnostics) != 1 {
+		t.Fatalf("Unexpected response: %s", w.Body.String())
+	}
+	d := resp.Diagnostics[0]
+	if d.FilePath != "/test/file.mkv" || d.ErrorType != integration.ErrorTypeCorruptHeader {
+		t.Errorf("Unexpected diagnostics entry: %+v", d)
+	}
+	if len(d.Runs) != 1 || d.Runs[0].Stderr != "moov atom not found" || d.Runs[0].ExitCode != 1 {
+		t.Errorf("Unexpected runs: %+v", d.Runs)
+	}
+
+	req, _ = http.NewRequest("GET", "/corruptions/unknown/diagnostics", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown corruption, got %d", w.Code)
+	}
+}
+
 func TestGetCorruptionHistory_WithEvents(t *testing.T) {
 	db, cleanup := setupCorruptionsTestDB(t)
 	defer cleanup()
@@ -1403,8 +1521,8 @@ func TestGetCorruptions_DBError(t *testing.T) {
 	server := createCorruptionsTestServer(t, db, eb)
 	defer server.scanner.Shutdown()
 
-	// Drop the view to cause DB error
-	db.Exec("DROP VIEW corruption_status")
+	// Drop the summary table to cause DB error
+	db.Exec("DROP TABLE corruption_summary")
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -1433,8 +1551,8 @@ func TestGetRemediations_DBError(t *testing.T) {
 	server := createCorruptionsTestServer(t, db, eb)
 	defer server.scanner.Shutdown()
 
-	// Drop the view to cause DB error
-	db.Exec("DROP VIEW corruption_status")
+	// Drop the summary table to cause DB error
+	db.Exec("DROP TABLE corruption_summary")
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
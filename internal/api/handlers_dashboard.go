@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getDashboard returns the dashboard summary: corruptions per state, active
+// downloads, corruptions that need attention and the last scan of each path.
+// It is served from memory, kept up to date by the event bus, without queries.
+// GET /api/dashboard
+func (s *RESTServer) getDashboard(c *gin.Context) {
+	c.JSON(http.StatusOK, s.dashboard.Snapshot())
+}
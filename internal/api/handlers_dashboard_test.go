@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetDashboard_FollowsEvents(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	dashboard := services.NewDashboardSummary(sqlDB)
+	defer dashboard.Stop()
+	eb := eventbus.NewEventBus(sqlDB)
+	defer eb.Shutdown()
+	eb.AddProjector(db.ProjectCorruptionSummary)
+	eb.AddProjector(dashboard.Project)
+
+	require.NoError(t, eb.Publish(domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "c1",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/movie.mkv", "path_id": 1, "corruption_type": "CorruptHeader"},
+	}))
+	require.NoError(t, eb.Publish(domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "c2",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/other.mkv", "path_id": 1, "corruption_type": "CorruptHeader"},
+	}))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: sqlDB, eventBus: eb, dashboard: dashboard}
+	r.GET("/dashboard", s.getDashboard)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/dashboard", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var snap services.DashboardSnapshot
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snap))
+	assert.Equal(t, 2, snap.Corruptions)
+	assert.Equal(t, 2, snap.States["CorruptionDetected"])
+
+	// The in-memory summary matches the read model in the database
+	reloaded := services.NewDashboardSummary(sqlDB)
+	defer reloaded.Stop()
+	assert.Equal(t, reloaded.Snapshot().States, snap.States)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// deletedConfig returns the service that soft-deletes and restores scan paths
+// and *arr instances.
+func (s *RESTServer) deletedConfig() *services.DeletedConfigService {
+	return services.NewDeletedConfigService(s.db)
+}
+
+// reloadSchedules re-registers scan schedules after scan paths were deleted or restored.
+func (s *RESTServer) reloadSchedules() {
+	if s.scheduler == nil {
+		return
+	}
+	if err := s.scheduler.LoadSchedules(); err != nil {
+		logger.Errorf("Failed to reload schedules: %v", err)
+	}
+}
+
+// getDeletedConfig lists deleted scan paths and *arr instances that can still be restored.
+// GET /api/config/deleted
+func (s *RESTServer) getDeletedConfig(c *gin.Context) {
+	deleted, err := s.deletedConfig().ListDeleted(c.Request.Context(), config.Get().DeletedRetentionDays)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deleted)
+}
+
+// restoreScanPath undoes the deletion of a scan path, including its schedules.
+// POST /api/config/paths/:id/restore
+func (s *RESTServer) restoreScanPath(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	if err := s.deletedConfig().RestoreScanPath(id); err != nil {
+		if errors.Is(err, services.ErrScanPathNotFound) {
+			respondNotFound(c, "Deleted scan path")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	s.reloadSchedules()
+	s.syncArrTagsInBackground()
+	s.remotePaths.Invalidate()
+	s.publishScanPathsChanged("restored", id)
+	if err := s.pathMapper.Reload(); err != nil {
+		logger.Errorf(errMsgReloadPathMappings, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan path restored but path mapping update failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Scan path restored"})
+}
+
+// restoreArrInstance undoes the deletion of an *arr instance.
+// POST /api/config/arr/:id/restore
+func (s *RESTServer) restoreArrInstance(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	if err := s.deletedConfig().RestoreArrInstance(id); err != nil {
+		if errors.Is(err, services.ErrArrInstanceNotFound) {
+			respondNotFound(c, "Deleted instance")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	// Tag-bound scan paths were unbound on deletion
+	s.syncArrTagsInBackground()
+	c.JSON(http.StatusOK, gin.H{"message": "Instance restored"})
+}
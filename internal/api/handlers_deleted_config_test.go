@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestDeleteAndRestoreConfig(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Radarr', 'radarr', 'http://radarr', 'key');
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/movies', '/movies', 1);
+	`)
+	require.NoError(t, err)
+
+	reloads := 0
+	pm := &testutil.MockPathMapper{ReloadFunc: func() error { reloads++; return nil }}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, pathMapper: pm}
+	r.GET("/config/paths", s.getScanPaths)
+	r.DELETE("/config/paths/:id", s.deleteScanPath)
+	r.POST("/config/paths/:id/restore", s.restoreScanPath)
+	r.DELETE("/config/arr/:id", s.deleteArrInstance)
+	r.POST("/config/arr/:id/restore", s.restoreArrInstance)
+	r.GET("/config/deleted", s.getDeletedConfig)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	listDeleted := func(t *testing.T) services.DeletedConfig {
+		t.Helper()
+		w := do("GET", "/config/deleted")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var deleted services.DeletedConfig
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deleted))
+		return deleted
+	}
+
+	require.Equal(t, http.StatusNoContent, do("DELETE", "/config/paths/1").Code)
+	require.Equal(t, http.StatusNoContent, do("DELETE", "/config/arr/1").Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/config/paths/1").Code)
+	assert.Equal(t, http.StatusBadRequest, do("DELETE", "/config/paths/abc").Code)
+	assert.Equal(t, 1, reloads)
+
+	w := do("GET", "/config/paths")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "/media/movies")
+
+	deleted := listDeleted(t)
+	assert.Equal(t, 30, deleted.RetentionDays)
+	require.Len(t, deleted.ScanPaths, 1)
+	require.Len(t, deleted.ArrInstances, 1)
+	assert.Equal(t, "/media/movies", deleted.ScanPaths[0].LocalPath)
+	assert.NotEmpty(t, deleted.ScanPaths[0].PurgeAt)
+	assert.Equal(t, "Radarr", deleted.ArrInstances[0].Name)
+
+	require.Equal(t, http.StatusOK, do("POST", "/config/paths/1/restore").Code)
+	require.Equal(t, http.StatusOK, do("POST", "/config/arr/1/restore").Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/config/paths/1/restore").Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/config/arr/2/restore").Code)
+	assert.Equal(t, 2, reloads)
+
+	w = do("GET", "/config/paths")
+	assert.Contains(t, w.Body.String(), "/media/movies")
+	deleted = listDeleted(t)
+	assert.Empty(t, deleted.ScanPaths)
+	assert.Empty(t, deleted.ArrInstances)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// getDeletionPlan returns what the latest remediation of a corruption planned
+// to delete: the *arr file records and the files on disk.
+// GET /api/corruptions/:id/deletion-plan
+func (s *RESTServer) getDeletionPlan(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	exists, err := s.corruptionExists(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if !exists {
+		respondNotFound(c, "Corruption")
+		return
+	}
+
+	plan, err := services.LoadDeletionPlan(ctx, s.db, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if plan == nil {
+		respondNotFound(c, "Deletion plan")
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
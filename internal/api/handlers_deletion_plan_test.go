@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetDeletionPlan(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = testutil.SeedEvent(db, domain.Event{
+		AggregateID:   "c1",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/movies/a.mkv"},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/corruptions/:id/deletion-plan", s.getDeletionPlan)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/corruptions/missing/deletion-plan").Code)
+	// Not planned yet
+	assert.Equal(t, http.StatusNotFound, get("/corruptions/c1/deletion-plan").Code)
+
+	_, err = db.Exec(`INSERT INTO corruption_deletion_plans (corruption_id, plan, planned_at) VALUES ('c1', ?, datetime('now'))`,
+		`{"action": "delete_via_arr", "media_id": 5, "arr_files": [{"id": 9, "path": "/movies/a.mkv", "size": 100}], "disk_paths": ["/media/movies/a.mkv"], "dry_run": true}`)
+	require.NoError(t, err)
+
+	w := get("/corruptions/c1/deletion-plan")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var plan services.DeletionPlan
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	assert.Equal(t, services.DeletionActionArr, plan.Action)
+	assert.Equal(t, []string{"/media/movies/a.mkv"}, plan.DiskPaths)
+	assert.True(t, plan.DryRun)
+	require.Len(t, plan.ArrFiles, 1)
+	assert.Equal(t, int64(9), plan.ArrFiles[0].ID)
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// diagnosticsLogLines is how many of the most recent log lines go into a diagnostics bundle.
+const diagnosticsLogLines = 500
+
+// diagnosticsRecentErrors caps the failure events included in a diagnostics bundle.
+const diagnosticsRecentErrors = 50
+
+// diagnosticsErrorEvents are the event types reported as recent errors.
+var diagnosticsErrorEvents = []domain.EventType{
+	domain.DeletionFailed, domain.DeletionPlanChanged, domain.SearchFailed, domain.VerificationFailed, domain.DownloadFailed,
+	domain.ImportBlocked, domain.QualityRegression, domain.AudioTrackMissing, domain.MaxRetriesReached, domain.SearchExhausted, domain.ScanFailed,
+	domain.NotificationFailed, domain.StuckRemediation, domain.InstanceUnhealthy, domain.SLABreached, domain.MappingDrift,
+}
+
+// diagnosticsVersions identifies the build and runtime.
+type diagnosticsVersions struct {
+	Healarr       string `json:"healarr"`
+	Go            string `json:"go"`
+	OS            string `json:"os"`
+	Arch          string `json:"arch"`
+	Environment   string `json:"environment"`
+	SQLite        string `json:"sqlite,omitempty"`
+	SchemaVersion int    `json:"schema_version,omitempty"`
+	Uptime        string `json:"uptime"`
+}
+
+// diagnosticsCircuitBreaker is the circuit breaker state of one *arr instance.
+type diagnosticsCircuitBreaker struct {
+	InstanceID          int64      `json:"instance_id"`
+	InstanceName        string     `json:"instance_name,omitempty"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	OpenSince           *time.Time `json:"open_since,omitempty"`
+	TotalFailures       int64      `json:"total_failures"`
+	TotalSuccesses      int64      `json:"total_successes"`
+	TotalRejected       int64      `json:"total_rejected"`
+}
+
+// diagnosticsErrorEvent is a recent failure event.
+type diagnosticsErrorEvent struct {
+	AggregateID string                 `json:"aggregate_id"`
+	EventType   string                 `json:"event_type"`
+	EventData   map[string]interface{} `json:"event_data,omitempty"`
+	CreatedAt   string                 `json:"created_at"`
+}
+
+// handleSystemDiagnostics returns a zip with what maintainers usually ask for in
+// issue reports: versions, configuration, tool availability, database stats,
+// circuit breaker states, recent errors and the tail of the log. Secrets are redacted.
+func (s *RESTServer) handleSystemDiagnostics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	cfg := config.Get()
+	logLines, err := tailLogFile(filepath.Join(cfg.LogDir, "healarr.log"), diagnosticsLogLines)
+	if err != nil {
+		logger.Debugf("Failed to read log file for diagnostics: %v", err)
+	}
+
+	var dbStats interface{}
+	if stats, err := db.DatabaseStats(s.db); err != nil {
+		dbStats = gin.H{"error": err.Error()}
+	} else {
+		dbStats = stats
+	}
+
+	var tools interface{}
+	if s.toolChecker != nil {
+		tools = s.toolChecker.GetToolStatus()
+	}
+
+	generatedAt := time.Now().UTC()
+	files := []bundleFile{
+		{"manifest.json", gin.H{
+			"healarr_version": config.Version,
+			"generated_at":    generatedAt.Format(time.RFC3339),
+			"log_lines":       len(logLines),
+		}},
+		{"versions.json", s.collectDiagnosticsVersions(ctx)},
+		{"config.json", gin.H{
+			"system":   newSystemConfigInfo(cfg),
+			"warnings": config.GetWarnings(),
+		}},
+		{"tools.json", tools},
+		{"database.json", dbStats},
+		{"circuit_breakers.json", s.collectCircuitBreakerStates()},
+		{"recent_errors.json", gin.H{
+			"events": s.loadRecentErrorEvents(ctx),
+			"log":    filterLogLevel(logLines, "ERROR"),
+		}},
+		{"healarr.log.txt", []byte(strings.Join(logLines, "\n"))},
+	}
+
+	archive, err := buildRedactedZip(files, s.knownSecrets())
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to build diagnostics bundle", err)
+		return
+	}
+
+	logger.Infof("Created system diagnostics bundle")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=healarr_diagnostics_%s.zip", generatedAt.Format("20060102_150405")))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// collectDiagnosticsVersions reports the Healarr, Go, SQLite and schema versions.
+func (s *RESTServer) collectDiagnosticsVersions(ctx context.Context) diagnosticsVersions {
+	v := diagnosticsVersions{
+		Healarr:     config.Version,
+		Go:          runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Environment: "native",
+		Uptime:      formatUptime(time.Since(s.startTime)),
+	}
+	if isDockerEnvironment() {
+		v.Environment = "docker"
+	}
+	if err := s.db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&v.SQLite); err != nil {
+		logger.Debugf("Failed to query SQLite version: %v", err)
+	}
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&v.SchemaVersion); err != nil {
+		logger.Debugf("Failed to query schema version: %v", err)
+	}
+	return v
+}
+
+// collectCircuitBreakerStates returns the circuit breaker of every *arr instance
+// that has one, or nil if the arr client doesn't track them.
+func (s *RESTServer) collectCircuitBreakerStates() []diagnosticsCircuitBreaker {
+	source, ok := s.arrClient.(services.CircuitBreakerSource)
+	if !ok {
+		return nil
+	}
+
+	names := make(map[int64]string)
+	if instances, err := s.arrClient.GetAllInstances(); err == nil {
+		for _, inst := range instances {
+			if inst != nil {
+				names[inst.ID] = inst.Name
+			}
+		}
+	}
+
+	stats := source.GetCircuitBreakerStats()
+	states := make([]diagnosticsCircuitBreaker, 0, len(stats))
+	for id, st := range stats {
+		state := diagnosticsCircuitBreaker{
+			InstanceID:          id,
+			InstanceName:        names[id],
+			State:               st.State.String(),
+			ConsecutiveFailures: st.ConsecutiveFailures,
+			TotalFailures:       st.TotalFailures,
+			TotalSuccesses:      st.TotalSuccesses,
+			TotalRejected:       st.TotalRejected,
+		}
+		if !st.LastFailureTime.IsZero() {
+			t := st.LastFailureTime
+			state.LastFailure = &t
+		}
+		if !st.OpenSince.IsZero() {
+			t := st.OpenSince
+			state.OpenSince = &t
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].InstanceID < states[j].InstanceID })
+	return states
+}
+
+// loadRecentErrorEvents returns the most recent failure events, newest first.
+func (s *RESTServer) loadRecentErrorEvents(ctx context.Context) []diagnosticsErrorEvent {
+	placeholders := make([]string, len(diagnosticsErrorEvents))
+	args := make([]interface{}, 0, len(diagnosticsErrorEvents)+1)
+	for i, t := range diagnosticsErrorEvents {
+		placeholders[i] = "?"
+		args = append(args, string(t))
+	}
+	args = append(args, diagnosticsRecentErrors)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT aggregate_id, event_type, event_data, created_at
+		FROM events WHERE event_type IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY id DESC LIMIT ?`, args...)
+	if err != nil {
+		logger.Debugf("Failed to load recent errors for diagnostics: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	events := make([]diagnosticsErrorEvent, 0)
+	for rows.Next() {
+		var e diagnosticsErrorEvent
+		var data []byte
+		if err := rows.Scan(&e.AggregateID, &e.EventType, &data, &e.CreatedAt); err != nil {
+			continue
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &e.EventData); err != nil {
+				logger.Debugf("Failed to unmarshal event data for %s event: %v", e.EventType, err)
+			}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// tailLogFile returns the last n lines of a log file. A missing file yields no lines.
+func tailLogFile(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = append(lines[1:], scanner.Text())
+		} else {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// filterLogLevel returns the log lines written at the given level.
+func filterLogLevel(lines []string, level string) []string {
+	marker := "[" + level + "]"
+	filtered := make([]string, 0)
+	for _, line := range lines {
+		if strings.Contains(line, marker) {
+			filtered = append(filtered, line)
+		}
+	}
+	return filtered
+}
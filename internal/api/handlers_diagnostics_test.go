@@ -0,0 +1,132 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// breakerArrClient adds circuit breaker stats to the mock arr client.
+type breakerArrClient struct {
+	*testutil.MockArrClient
+	stats map[int64]integration.CircuitBreakerStats
+}
+
+func (b *breakerArrClient) GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats {
+	return b.stats
+}
+
+func TestHandleSystemDiagnostics(t *testing.T) {
+	const apiKey = "fedcba9876543210fedcba9876543210"
+
+	cfg := config.NewTestConfig()
+	cfg.LogDir = t.TempDir()
+	config.SetForTesting(cfg)
+
+	var log strings.Builder
+	for i := 0; i < diagnosticsLogLines+10; i++ {
+		log.WriteString("2026-01-01T00:00:00Z [INFO] line\n")
+	}
+	log.WriteString("2026-01-01T00:00:01Z [ERROR] GET http://radarr:7878/api/v3/movie?apikey=" + apiKey + " failed\n")
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.LogDir, "healarr.log"), []byte(log.String()), 0o644))
+
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+			('corruption', 'c1', 'CorruptionDetected', '{"file_path":"/media/a.mkv"}'),
+			('corruption', 'c1', 'SearchFailed', '{"error":"indexer down"}');
+	`)
+	require.NoError(t, err)
+
+	arr := &breakerArrClient{
+		MockArrClient: &testutil.MockArrClient{
+			GetAllInstancesFunc: func() ([]*integration.ArrInstanceInfo, error) {
+				return []*integration.ArrInstanceInfo{{ID: 2, Name: "Radarr", APIKey: apiKey}}, nil
+			},
+		},
+		stats: map[int64]integration.CircuitBreakerStats{
+			2: {State: integration.CircuitOpen, ConsecutiveFailures: 5, OpenSince: time.Now().Add(-time.Minute)},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, arrClient: arr, startTime: time.Now()}
+	r.GET("/system/diagnostics", s.handleSystemDiagnostics)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/system/diagnostics", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "healarr_diagnostics_")
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = data
+		assert.NotContains(t, string(data), apiKey, "%s leaks the API key", f.Name)
+	}
+	for _, name := range []string{"manifest.json", "versions.json", "config.json", "tools.json", "database.json", "circuit_breakers.json", "recent_errors.json", "healarr.log.txt"} {
+		assert.Contains(t, files, name)
+	}
+
+	assert.Len(t, strings.Split(string(files["healarr.log.txt"]), "\n"), diagnosticsLogLines)
+
+	var versions diagnosticsVersions
+	require.NoError(t, json.Unmarshal(files["versions.json"], &versions))
+	assert.Equal(t, config.Version, versions.Healarr)
+	assert.NotEmpty(t, versions.SQLite)
+
+	var breakers []diagnosticsCircuitBreaker
+	require.NoError(t, json.Unmarshal(files["circuit_breakers.json"], &breakers))
+	require.Len(t, breakers, 1)
+	assert.Equal(t, "Radarr", breakers[0].InstanceName)
+	assert.Equal(t, "open", breakers[0].State)
+	assert.NotNil(t, breakers[0].OpenSince)
+
+	var recent struct {
+		Events []diagnosticsErrorEvent `json:"events"`
+		Log    []string                `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(files["recent_errors.json"], &recent))
+	require.Len(t, recent.Events, 1)
+	assert.Equal(t, "SearchFailed", recent.Events[0].EventType)
+	require.Len(t, recent.Log, 1)
+	assert.Contains(t, recent.Log[0], "apikey="+redact.Mask)
+
+	var dbStats map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["database.json"], &dbStats))
+	assert.Contains(t, dbStats, "size_bytes")
+}
+
+func TestTailLogFile_Missing(t *testing.T) {
+	lines, err := tailLogFile(filepath.Join(t.TempDir(), "missing.log"), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func setupEventsTestServer(t *testing.T) *gin.Engine {
+	t.Helper()
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []struct {
+		aggregateType, aggregateID, eventType string
+	}{
+		{"corruption", "c1", "CorruptionDetected"},
+		{"scan", "s1", "ScanStarted"},
+		{"corruption", "c1", "RemediationQueued"},
+		{"corruption", "c2", "CorruptionDetected"},
+		{"scan", "s1", "ScanCompleted"},
+	}
+	for i, e := range events {
+		_, err := db.Exec(`
+			INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, event_version, created_at)
+			VALUES (?, ?, ?, ?, 1, ?)
+		`, e.aggregateType, e.aggregateID, e.eventType, `{"n":`+itoa(i+1)+`}`, base.Add(time.Duration(i)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/api/events", s.getEvents)
+	return r
+}
+
+func getEventFeed(t *testing.T, r *gin.Engine, query string) EventFeedPage {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/events?"+query, nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var page EventFeedPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page
+}
+
+func TestGetEvents_CursorPagination(t *testing.T) {
+	r := setupEventsTestServer(t)
+
+	page := getEventFeed(t, r, "limit=2")
+	require.Len(t, page.Events, 2)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, "CorruptionDetected", page.Events[0].EventType)
+	assert.JSONEq(t, `{"n":1}`, string(page.Events[0].Data))
+	assert.Equal(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), page.Events[0].CreatedAt)
+
+	var ids []int64
+	for _, e := range page.Events {
+		ids = append(ids, e.ID)
+	}
+	for page.HasMore {
+		page = getEventFeed(t, r, "limit=2&cursor="+page.NextCursor)
+		for _, e := range page.Events {
+			ids = append(ids, e.ID)
+		}
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	// Tailing past the end keeps the cursor
+	page = getEventFeed(t, r, "cursor=5")
+	assert.Empty(t, page.Events)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, "5", page.NextCursor)
+}
+
+func TestGetEvents_Filters(t *testing.T) {
+	r := setupEventsTestServer(t)
+
+	page := getEventFeed(t, r, "aggregate_type=corruption&aggregate_id=c1")
+	require.Len(t, page.Events, 2)
+	assert.Equal(t, "RemediationQueued", page.Events[1].EventType)
+
+	page = getEventFeed(t, r, "event_type=ScanStarted,ScanCompleted")
+	require.Len(t, page.Events, 2)
+	assert.Equal(t, int64(5), page.Events[1].ID)
+
+	page = getEventFeed(t, r, "since=2026-05-01T13:00:00Z&until=2026-05-01T15:00:00Z")
+	require.Len(t, page.Events, 2)
+	assert.Equal(t, int64(2), page.Events[0].ID)
+	assert.Equal(t, int64(3), page.Events[1].ID)
+
+	// Offsets are converted to UTC
+	page = getEventFeed(t, r, "since=2026-05-01T17:30:00%2B02:00")
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, int64(5), page.Events[0].ID)
+}
+
+func TestGetEvents_InvalidParameters(t *testing.T) {
+	r := setupEventsTestServer(t)
+	for _, query := range []string{"cursor=abc", "cursor=-1", "limit=0", "limit=5000", "since=yesterday"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/events?"+query, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,337 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Formats of the remediation export.
+const (
+	exportFormatCSV    = "csv"
+	exportFormatJSON   = "json"
+	exportFormatRadarr = "radarr" // StevenLu-style custom list of movies
+	exportFormatSonarr = "sonarr" // custom list of series
+)
+
+// RemediationRecord is one corruption Healarr acted on: the file it deleted or
+// searched a replacement for, and how that ended.
+type RemediationRecord struct {
+	CorruptionID  string `json:"corruption_id"`
+	Title         string `json:"title"` // e.g. "The Matrix (1999)" or "Colony S01E08"
+	MediaTitle    string `json:"media_title,omitempty"`
+	MediaType     string `json:"media_type,omitempty"`
+	ArrType       string `json:"arr_type,omitempty"`
+	InstanceName  string `json:"instance_name,omitempty"`
+	MediaID       int64  `json:"media_id,omitempty"`
+	TmdbID        int64  `json:"tmdb_id,omitempty"`
+	TvdbID        int64  `json:"tvdb_id,omitempty"`
+	ImdbID        string `json:"imdb_id,omitempty"`
+	FilePath      string `json:"file_path"`
+	Reason        string `json:"reason"` // corruption type
+	ErrorDetails  string `json:"error_details,omitempty"`
+	QualityBefore string `json:"quality_before,omitempty"`
+	QualityAfter  string `json:"quality_after,omitempty"`
+	ReleaseGroup  string `json:"release_group,omitempty"`
+	DetectedAt    string `json:"detected_at"`
+	DeletedAt     string `json:"deleted_at,omitempty"`
+	ResolvedAt    string `json:"resolved_at,omitempty"`
+	State         string `json:"state"`
+	Outcome       string `json:"outcome"`
+	RetryCount    int    `json:"retry_count"`
+}
+
+// remediationCSVHeader are the columns of the CSV export, in the order of
+// RemediationRecord.csvRow.
+var remediationCSVHeader = []string{
+	"corruption_id", "title", "media_title", "media_type", "arr_type", "instance_name", "media_id",
+	"tmdb_id", "tvdb_id", "imdb_id", "file_path", "reason", "error_details",
+	"quality_before", "quality_after", "release_group",
+	"detected_at", "deleted_at", "resolved_at", "state", "outcome", "retry_count",
+}
+
+func (r *RemediationRecord) csvRow() []string {
+	id := func(v int64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	return []string{
+		r.CorruptionID, r.Title, r.MediaTitle, r.MediaType, r.ArrType, r.InstanceName, id(r.MediaID),
+		id(r.TmdbID), id(r.TvdbID), r.ImdbID, r.FilePath, r.Reason, r.ErrorDetails,
+		r.QualityBefore, r.QualityAfter, r.ReleaseGroup,
+		r.DetectedAt, r.DeletedAt, r.ResolvedAt, r.State, r.Outcome, strconv.Itoa(r.RetryCount),
+	}
+}
+
+// exportRemediations exports the remediation history, so Healarr's actions can
+// be reconciled with the *arr history or an external ledger. As CSV or JSON it
+// lists every remediation; as a radarr or sonarr list it lists the remediated
+// movies or series, for an *arr custom import list.
+// GET /api/export/remediations?format=csv|json|radarr|sonarr&days=30&path_id=1&outcome=replaced
+func (s *RESTServer) exportRemediations(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", exportFormatCSV))
+	switch format {
+	case exportFormatCSV, exportFormatJSON, exportFormatRadarr, exportFormatSonarr:
+	default:
+		respondBadRequest(c, fmt.Errorf("format must be csv, json, radarr or sonarr"), true)
+		return
+	}
+	days := parseInt(c.DefaultQuery("days", "0"), 0)
+	var pathID int64
+	if v := c.Query("path_id"); v != "" {
+		id := parseInt(v, -1)
+		if id <= 0 {
+			respondBadRequest(c, fmt.Errorf("invalid path_id"), true)
+			return
+		}
+		pathID = int64(id)
+	}
+	outcome := c.Query("outcome")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	records, err := s.loadRemediations(ctx, pathID, days)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if outcome != "" {
+		filtered := records[:0]
+		for _, r := range records {
+			if r.Outcome == outcome {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+
+	switch format {
+	case exportFormatJSON:
+		c.JSON(http.StatusOK, records)
+	case exportFormatRadarr:
+		c.JSON(http.StatusOK, radarrImportList(records))
+	case exportFormatSonarr:
+		c.JSON(http.StatusOK, sonarrImportList(records))
+	default:
+		s.writeRemediationsCSV(c, records)
+	}
+}
+
+func (s *RESTServer) writeRemediationsCSV(c *gin.Context, records []RemediationRecord) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=healarr_remediations_%s.csv", time.Now().Format("20060102_150405")))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(remediationCSVHeader); err != nil {
+		logger.Errorf("Failed to write remediation export: %v", err)
+		return
+	}
+	for i := range records {
+		if err := w.Write(records[i].csvRow()); err != nil {
+			logger.Errorf("Failed to write remediation export: %v", err)
+			return
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.Errorf("Failed to write remediation export: %v", err)
+	}
+}
+
+// radarrListItem is a movie in the StevenLu custom list format Radarr imports.
+type radarrListItem struct {
+	Title  string `json:"title"`
+	ImdbID string `json:"imdb_id"`
+	TmdbID int64  `json:"tmdb_id,omitempty"`
+}
+
+// sonarrListItem is a series in the custom list format Sonarr imports.
+type sonarrListItem struct {
+	Title  string `json:"title"`
+	TvdbID int64  `json:"tvdbId"`
+}
+
+// radarrImportList lists each remediated movie with an IMDb ID once.
+func radarrImportList(records []RemediationRecord) []radarrListItem {
+	items := make([]radarrListItem, 0)
+	seen := make(map[string]bool)
+	for _, r := range records {
+		if r.MediaType != "movie" || r.ImdbID == "" || seen[r.ImdbID] {
+			continue
+		}
+		seen[r.ImdbID] = true
+		items = append(items, radarrListItem{Title: r.MediaTitle, ImdbID: r.ImdbID, TmdbID: r.TmdbID})
+	}
+	return items
+}
+
+// sonarrImportList lists each remediated series with a TVDB ID once.
+func sonarrImportList(records []RemediationRecord) []sonarrListItem {
+	items := make([]sonarrListItem, 0)
+	seen := make(map[int64]bool)
+	for _, r := range records {
+		if r.MediaType != "series" || r.TvdbID == 0 || seen[r.TvdbID] {
+			continue
+		}
+		seen[r.TvdbID] = true
+		items = append(items, sonarrListItem{Title: r.MediaTitle, TvdbID: r.TvdbID})
+	}
+	return items
+}
+
+// remediationEventData is what the export reads from a corruption's events.
+type remediationEventData struct {
+	ErrorDetails  string `json:"error_details"`
+	MediaID       int64  `json:"media_id"`
+	MediaTitle    string `json:"media_title"`
+	MediaYear     int    `json:"media_year"`
+	MediaType     string `json:"media_type"`
+	ArrType       string `json:"arr_type"`
+	InstanceName  string `json:"instance_name"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+	TmdbID        int64  `json:"tmdb_id"`
+	TvdbID        int64  `json:"tvdb_id"`
+	ImdbID        string `json:"imdb_id"`
+	Quality       string `json:"quality"`
+	ReleaseGroup  string `json:"release_group"`
+	Metadata      struct {
+		Quality string `json:"quality"`
+	} `json:"metadata"`
+}
+
+// loadRemediations loads the corruptions Healarr deleted or searched a
+// replacement for, of all scan paths or of pathID if set, oldest first.
+func (s *RESTServer) loadRemediations(ctx context.Context, pathID int64, days int) ([]RemediationRecord, error) {
+	where := `
+		WHERE cs.file_path IS NOT NULL
+		AND EXISTS (
+			SELECT 1 FROM events a
+			WHERE a.aggregate_id = cs.corruption_id
+			AND a.event_type IN ('DeletionCompleted', 'SearchStarted')
+		)`
+	var args []interface{}
+	if pathID > 0 {
+		where += ` AND cs.path_id = ?`
+		args = append(args, pathID)
+	}
+	if days > 0 {
+		where += ` AND substr(cs.detected_at, 1, 10) >= date('now', ?)`
+		args = append(args, fmt.Sprintf("-%d days", days))
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT cs.corruption_id, cs.file_path, COALESCE(cs.corruption_type, ''),
+			cs.current_state, COALESCE(cs.retry_count, 0), COALESCE(cs.detected_at, '')
+		FROM corruption_summary cs`+where+`
+		ORDER BY cs.detected_at ASC, cs.corruption_id ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]RemediationRecord, 0)
+	index := make(map[string]int)
+	for rows.Next() {
+		var r RemediationRecord
+		if err := rows.Scan(&r.CorruptionID, &r.FilePath, &r.Reason, &r.State, &r.RetryCount, &r.DetectedAt); err != nil {
+			logger.Debugf("Failed to scan remediation row: %v", err)
+			continue
+		}
+		r.Outcome = corruptionOutcome(r.State)
+		index[r.CorruptionID] = len(records)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	// Fill in the details recorded by the events of the same corruptions
+	events, err := s.reader().QueryContext(ctx, `
+		SELECT e.aggregate_id, e.event_type, e.event_data, COALESCE(e.created_at, '')
+		FROM events e
+		JOIN corruption_summary cs ON cs.corruption_id = e.aggregate_id`+where+`
+		AND e.event_type IN ('CorruptionDetected', 'DeletionCompleted', 'SearchStarted', 'SearchCompleted', 'VerificationSuccess')
+		ORDER BY e.id ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer events.Close()
+
+	details := make(map[string]*integration.MediaDetails)
+	for events.Next() {
+		var aggregateID, eventType, eventData, createdAt string
+		if err := events.Scan(&aggregateID, &eventType, &eventData, &createdAt); err != nil {
+			logger.Debugf("Failed to scan remediation event: %v", err)
+			continue
+		}
+		i, ok := index[aggregateID]
+		if !ok {
+			continue
+		}
+		var data remediationEventData
+		if err := json.Unmarshal([]byte(eventData), &data); err != nil {
+			logger.Debugf("Failed to decode %s event of %s: %v", eventType, aggregateID, err)
+			continue
+		}
+		r := &records[i]
+		switch domain.EventType(eventType) {
+		case domain.CorruptionDetected:
+			r.ErrorDetails = data.ErrorDetails
+		case domain.DeletionCompleted:
+			// The first deletion removed the original file
+			if r.DeletedAt == "" {
+				r.DeletedAt = createdAt
+				r.QualityBefore = data.Metadata.Quality
+			}
+		case domain.SearchStarted, domain.SearchCompleted:
+			if data.MediaID > 0 {
+				r.MediaID = data.MediaID
+			}
+			if data.MediaTitle != "" {
+				r.MediaTitle, r.MediaType = data.MediaTitle, data.MediaType
+				r.ArrType, r.InstanceName = data.ArrType, data.InstanceName
+				r.TmdbID, r.TvdbID, r.ImdbID = data.TmdbID, data.TvdbID, data.ImdbID
+				details[aggregateID] = &integration.MediaDetails{
+					Title:         data.MediaTitle,
+					Year:          data.MediaYear,
+					MediaType:     data.MediaType,
+					SeasonNumber:  data.SeasonNumber,
+					EpisodeNumber: data.EpisodeNumber,
+				}
+			}
+		case domain.VerificationSuccess:
+			r.ResolvedAt = createdAt
+			r.QualityAfter = data.Quality
+			r.ReleaseGroup = data.ReleaseGroup
+		}
+	}
+	if err := events.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, d := range details {
+		records[index[id]].Title = d.FormatDisplayTitle()
+	}
+	return records, nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestExportRemediations(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (1, '/media/movies', '/movies'), (2, '/media/tv', '/tv')`)
+	require.NoError(t, err)
+	// Events project onto corruption_summary through the test trigger
+	_, err = db.Exec(`
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at) VALUES
+			('corruption', 'movie', 'CorruptionDetected', '{"file_path": "/media/movies/The Matrix (1999)/matrix.mkv", "path_id": 1, "corruption_type": "CorruptHeader", "error_details": "moov atom not found"}', '2026-04-01 10:00:00'),
+			('corruption', 'movie', 'DeletionCompleted', '{"media_id": 42, "metadata": {"quality": "Bluray-1080p"}}', '2026-04-01 10:05:00'),
+			('corruption', 'movie', 'SearchStarted', '{"media_id": 42, "media_title": "The Matrix", "media_year": 1999, "media_type": "movie", "arr_type": "radarr", "instance_name": "Radarr", "tmdb_id": 603, "imdb_id": "tt0133093"}', '2026-04-01 10:06:00'),
+			('corruption', 'movie', 'VerificationSuccess', '{"quality": "Bluray-2160p", "release_group": "GROUP"}', '2026-04-01 12:00:00'),
+			('corruption', 'episode', 'CorruptionDetected', '{"file_path": "/media/tv/Colony/Season 1/e08.mkv", "path_id": 2, "corruption_type": "CorruptStream"}', '2026-04-02 10:00:00'),
+			('corruption', 'episode', 'DeletionCompleted', '{"media_id": 7, "metadata": {"quality": "HDTV-720p"}}', '2026-04-02 10:05:00'),
+			('corruption', 'episode', 'SearchStarted', '{"media_id": 7, "media_title": "Colony", "media_year": 2016, "media_type": "series", "arr_type": "sonarr", "instance_name": "Sonarr", "season_number": 1, "episode_number": 8, "tvdb_id": 284210}', '2026-04-02 10:06:00'),
+			('corruption', 'episode', 'SearchFailed', '{"error": "no releases"}', '2026-04-02 11:00:00'),
+			('corruption', 'episode', 'MaxRetriesReached', '{}', '2026-04-02 12:00:00'),
+			('corruption', 'untouched', 'CorruptionDetected', '{"file_path": "/media/movies/Other/other.mkv", "path_id": 1, "corruption_type": "CorruptHeader"}', '2026-04-03 10:00:00')
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/export/remediations", s.exportRemediations)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/export/remediations"+query, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("csv by default", func(t *testing.T) {
+		w := get("")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "healarr_remediations_")
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3, "header and the two remediated corruptions")
+		assert.Equal(t, remediationCSVHeader, rows[0])
+		row := map[string]string{}
+		for i, col := range rows[0] {
+			row[col] = rows[1][i]
+		}
+		assert.Equal(t, "The Matrix (1999)", row["title"])
+		assert.Equal(t, "CorruptHeader", row["reason"])
+		assert.Equal(t, "moov atom not found", row["error_details"])
+		assert.Equal(t, "Bluray-1080p", row["quality_before"])
+		assert.Equal(t, "Bluray-2160p", row["quality_after"])
+		assert.Equal(t, "2026-04-01 10:05:00", row["deleted_at"])
+		assert.Equal(t, "replaced", row["outcome"])
+		assert.Equal(t, "tt0133093", row["imdb_id"])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := get("?format=json")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var records []RemediationRecord
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+		require.Len(t, records, 2)
+		episode := records[1]
+		assert.Equal(t, "Colony S01E08", episode.Title)
+		assert.Equal(t, "HDTV-720p", episode.QualityBefore)
+		assert.Empty(t, episode.QualityAfter)
+		assert.Empty(t, episode.ResolvedAt)
+		assert.Equal(t, "failed", episode.Outcome)
+		assert.Equal(t, 1, episode.RetryCount)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		var records []RemediationRecord
+		w := get("?format=json&path_id=2")
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, "episode", records[0].CorruptionID)
+
+		w = get("?format=json&outcome=replaced")
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, "movie", records[0].CorruptionID)
+	})
+
+	t.Run("arr import lists", func(t *testing.T) {
+		w := get("?format=radarr")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"title": "The Matrix", "imdb_id": "tt0133093", "tmdb_id": 603}]`, w.Body.String())
+
+		w = get("?format=sonarr")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"title": "Colony", "tvdbId": 284210}]`, w.Body.String())
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		w := get("?format=xml")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "format")
+		assert.Equal(t, http.StatusBadRequest, get("?path_id=abc").Code)
+	})
+}
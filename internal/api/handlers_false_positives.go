@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+const (
+	defaultFalsePositiveLimit = 100
+	maxFalsePositiveLimit     = 1000
+	falsePositiveReportTopN   = 10
+)
+
+// markFalsePositives ignores the selected corruptions and records each finding
+// as a false positive, so the scanner suppresses identical findings on the
+// same file content.
+func (s *RESTServer) markFalsePositives(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	var req struct {
+		IDs  []string `json:"ids" binding:"min=1"`
+		Note string   `json:"note"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+
+	marked := 0
+	for _, id := range req.IDs {
+		fp, err := s.buildFalsePositive(ctx, id)
+		if err != nil {
+			logger.Errorf("Failed to load finding for corruption %s: %v", id, err)
+			continue
+		}
+		fp.Note = req.Note
+
+		if _, err := s.falsePositives.Record(fp); err != nil {
+			logger.Errorf("Failed to record false positive for corruption %s: %v", id, err)
+			continue
+		}
+
+		if err := s.eventBus.Publish(domain.Event{
+			AggregateID:   id,
+			AggregateType: "corruption",
+			EventType:     domain.CorruptionIgnored,
+			EventData:     map[string]interface{}{"reason": "Marked as false positive"},
+		}); err != nil {
+			logger.Errorf("Failed to publish CorruptionIgnored event for %s: %v", id, err)
+			continue
+		}
+		marked++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Marked %d corruption(s) as false positive", marked),
+		"marked":  marked,
+	})
+}
+
+// buildFalsePositive collects the finding, checker output, tool version and
+// file fingerprint of a corruption.
+func (s *RESTServer) buildFalsePositive(ctx context.Context, id string) (*services.FalsePositive, error) {
+	var filePath, corruptionType, errorDetails sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			json_extract(event_data, '$.file_path'),
+			json_extract(event_data, '$.corruption_type'),
+			json_extract(event_data, '$.error_details')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = 'CorruptionDetected'
+		ORDER BY id DESC
+		LIMIT 1
+	`, id).Scan(&filePath, &corruptionType, &errorDetails)
+	if err != nil {
+		return nil, err
+	}
+	if !filePath.Valid || filePath.String == "" {
+		return nil, errors.New("corruption has no file path")
+	}
+
+	fp := &services.FalsePositive{
+		CorruptionID:   id,
+		FilePath:       filePath.String,
+		CorruptionType: corruptionType.String,
+		ErrorDetails:   errorDetails.String,
+	}
+
+	// The latest checker output tells which tool produced the finding
+	var blob []byte
+	err = s.db.QueryRowContext(ctx, `
+		SELECT data FROM corruption_diagnostics WHERE corruption_id = ? ORDER BY id DESC LIMIT 1
+	`, id).Scan(&blob)
+	if err == nil {
+		fp.CheckerOutput = blob
+		if diag, err := integration.DecodeDiagnostics(blob); err == nil && len(diag.Runs) > 0 {
+			fp.DetectionTool = diag.Runs[len(diag.Runs)-1].Tool
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		logger.Debugf("Failed to load diagnostics for corruption %s: %v", id, err)
+	}
+
+	if fp.DetectionTool != "" && s.toolChecker != nil {
+		if status, ok := s.toolChecker.GetToolStatus()[strings.ToLower(fp.DetectionTool)]; ok {
+			fp.ToolVersion = status.Version
+		}
+	}
+
+	// A missing file can still be recorded; it just never suppresses anything
+	if fingerprint, size, err := services.FileFingerprint(fp.FilePath); err == nil {
+		fp.Fingerprint = fingerprint
+		fp.FileSize = size
+	} else {
+		logger.Debugf("Could not fingerprint %s: %v", fp.FilePath, err)
+	}
+
+	return fp, nil
+}
+
+// getFalsePositives lists recorded false positives, newest first.
+func (s *RESTServer) getFalsePositives(c *gin.Context) {
+	limit := defaultFalsePositiveLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxFalsePositiveLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxFalsePositiveLimit)})
+			return
+		}
+		limit = n
+	}
+
+	list, err := s.falsePositives.List(limit)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// getFalsePositiveReport returns aggregate false positive counts for tuning
+// detection heuristics.
+func (s *RESTServer) getFalsePositiveReport(c *gin.Context) {
+	report, err := s.falsePositives.Report(falsePositiveReportTopN)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// deleteFalsePositive removes a false positive so matching findings are
+// reported again.
+func (s *RESTServer) deleteFalsePositive(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	if err := s.falsePositives.Delete(id); err != nil {
+		if errors.Is(err, services.ErrFalsePositiveNotFound) {
+			respondNotFound(c, "False positive")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "False positive deleted"})
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+func TestFalsePositives_MarkListReportDelete(t *testing.T) {
+	db, cleanup := setupCorruptionsTestDB(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	filePath := filepath.Join(t.TempDir(), "movie.mkv")
+	if err := os.WriteFile(filePath, []byte("media content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	eventData, _ := json.Marshal(map[string]interface{}{
+		"file_path":       filePath,
+		"corruption_type": integration.ErrorTypeCorruptHeader,
+		"error_details":   "moov atom not found",
+	})
+	if _, err := db.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data)
+		VALUES ('corruption', 'fp-corruption', 'CorruptionDetected', ?)`, string(eventData)); err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+	blob, err := integration.EncodeDiagnostics(integration.CheckDiagnostics{
+		ErrorType: integration.ErrorTypeCorruptHeader,
+		Message:   "moov atom not found",
+		Runs:      []integration.ToolRun{{Tool: "ffprobe", ExitCode: 1, Stderr: "moov atom not found"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO corruption_diagnostics (corruption_id, file_path, data) VALUES (?, ?, ?)`,
+		"fp-corruption", filePath, blob); err != nil {
+		t.Fatalf("Failed to seed diagnostics: %v", err)
+	}
+
+	server := createCorruptionsTestServer(t, db, eb)
+	defer server.scanner.Shutdown()
+	server.falsePositives = services.NewFalsePositiveService(db)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/corruptions/false-positive", server.markFalsePositives)
+	r.GET("/false-positives", server.getFalsePositives)
+	r.GET("/false-positives/report", server.getFalsePositiveReport)
+	r.DELETE("/false-positives/:id", server.deleteFalsePositive)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("POST", "/corruptions/false-positive", `{"ids":[]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty ids, got %d", w.Code)
+	}
+
+	w := do("POST", "/corruptions/false-positive", `{"ids":["fp-corruption","missing"],"note":"plays fine"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var marked struct {
+		Marked int `json:"marked"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &marked); err != nil || marked.Marked != 1 {
+		t.Fatalf("Expected 1 marked, got %s", w.Body.String())
+	}
+
+	var ignored int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM events WHERE aggregate_id = 'fp-corruption' AND event_type = 'CorruptionIgnored'`).Scan(&ignored); err != nil || ignored != 1 {
+		t.Errorf("Expected a CorruptionIgnored event, got %d (%v)", ignored, err)
+	}
+
+	w = do("GET", "/false-positives", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var list []services.FalsePositive
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 {
+		t.Fatalf("Unexpected list %s (%v)", w.Body.String(), err)
+	}
+	fp := list[0]
+	if fp.FilePath != filePath || fp.DetectionTool != "ffprobe" || fp.Note != "plays fine" || fp.Fingerprint == "" || fp.FileSize != 13 {
+		t.Errorf("Unexpected false positive: %+v", fp)
+	}
+
+	var storedOutput []byte
+	if err := db.QueryRow(`SELECT checker_output FROM false_positives WHERE id = ?`, fp.ID).Scan(&storedOutput); err != nil || !bytes.Equal(storedOutput, blob) {
+		t.Errorf("Expected checker output to be stored (%v)", err)
+	}
+
+	if w := do("GET", "/false-positives?limit=0", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for bad limit, got %d", w.Code)
+	}
+
+	w = do("GET", "/false-positives/report", "")
+	var report services.FalsePositiveReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil || report.Total != 1 || len(report.ByCorruptionType) != 1 {
+		t.Errorf("Unexpected report %s (%v)", w.Body.String(), err)
+	}
+
+	if w := do("DELETE", "/false-positives/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid id, got %d", w.Code)
+	}
+	if w := do("DELETE", "/false-positives/1", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 on delete, got %d", w.Code)
+	}
+	if w := do("DELETE", "/false-positives/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 on second delete, got %d", w.Code)
+	}
+}
@@ -47,7 +47,7 @@ type arrHealthResult struct {
 func (s *RESTServer) checkArrInstancesHealth(ctx context.Context) arrHealthResult {
 	result := arrHealthResult{}
 
-	rows, err := s.db.QueryContext(ctx, "SELECT url, api_key FROM arr_instances WHERE enabled = 1")
+	rows, err := s.db.QueryContext(ctx, "SELECT url, api_key FROM arr_instances WHERE enabled = 1 AND deleted_at IS NULL")
 	if err != nil {
 		return result
 	}
@@ -169,7 +169,7 @@ func (s *RESTServer) handleHealth(c *gin.Context) {
 
 	// Get pending corruptions count
 	var pending int
-	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM corruption_status WHERE current_state = 'CorruptionDetected'").Scan(&pending); err != nil {
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM corruption_summary WHERE current_state = 'CorruptionDetected'").Scan(&pending); err != nil {
 		logger.Debugf("Failed to query pending corruptions: %v", err)
 	}
 
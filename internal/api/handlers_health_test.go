@@ -22,6 +22,7 @@ import (
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/metrics"
 	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 // Global metrics service to avoid Prometheus duplicate registration
@@ -80,7 +81,8 @@ func setupTestDBForHealth(t *testing.T) (*sql.DB, string, func()) {
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
 			enabled INTEGER DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_paths (
@@ -94,7 +96,8 @@ func setupTestDBForHealth(t *testing.T) (*sql.DB, string, func()) {
 			detection_method TEXT DEFAULT 'ffprobe',
 			detection_mode TEXT DEFAULT 'quick',
 			max_retries INTEGER DEFAULT 3,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scans (
@@ -146,6 +149,11 @@ func setupTestDBForHealth(t *testing.T) (*sql.DB, string, func()) {
 		os.RemoveAll(tmpDir)
 		t.Fatalf("Failed to create schema: %v", err)
 	}
+	if err := testutil.CreateCorruptionSummary(db); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create corruption summary: %v", err)
+	}
 
 	cleanup := func() {
 		db.Close()
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Directory depth of the corruption heatmap, counted from the scan path.
+const (
+	defaultHeatmapDepth = 2 // e.g. show and season, or movie folder
+	maxHeatmapDepth     = 10
+)
+
+// HeatmapDirectory is the number of corruptions found below a directory of
+// the library, counted from the directory's scan path.
+type HeatmapDirectory struct {
+	PathID         int64  `json:"path_id,omitempty"`
+	Directory      string `json:"directory"` // absolute
+	Name           string `json:"name"`      // relative to the scan path, "." for its own files
+	Depth          int    `json:"depth"`     // shallower than requested if files are higher up
+	Corruptions    int    `json:"corruptions"`
+	Unresolved     int    `json:"unresolved"`
+	Resolved       int    `json:"resolved"`
+	Files          int    `json:"files"` // distinct corrupted files
+	LastDetectedAt string `json:"last_detected_at"`
+}
+
+// CorruptionHeatmap is the corruption count of every library directory at a
+// depth, most corrupted first.
+type CorruptionHeatmap struct {
+	Depth       int                `json:"depth"`
+	Days        int                `json:"days,omitempty"` // 0: all time
+	Total       int                `json:"total"`
+	Directories []HeatmapDirectory `json:"directories"`
+}
+
+// getStatsHeatmap returns corruption counts aggregated per directory, so the
+// UI can show which parts of the library keep rotting. Query parameters:
+// depth (directory levels below the scan path, default 2), path_id (one scan
+// path) and days (corruptions detected in the last days, default all time).
+// Ignored corruptions aren't counted.
+// GET /api/stats/heatmap
+func (s *RESTServer) getStatsHeatmap(c *gin.Context) {
+	depth := parseInt(c.DefaultQuery("depth", "2"), defaultHeatmapDepth)
+	if depth < 1 || depth > maxHeatmapDepth {
+		respondBadRequest(c, fmt.Errorf("depth must be between 1 and %d", maxHeatmapDepth), true)
+		return
+	}
+	days := parseInt(c.DefaultQuery("days", "0"), 0)
+	var pathID int64
+	if v := c.Query("path_id"); v != "" {
+		id := parseInt(v, -1)
+		if id <= 0 {
+			respondBadRequest(c, fmt.Errorf("invalid path_id"), true)
+			return
+		}
+		pathID = int64(id)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	heatmap, err := s.loadCorruptionHeatmap(ctx, depth, pathID, days)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// loadCorruptionHeatmap aggregates the corruptions of all scan paths, or of
+// pathID if set, by their directory depth levels below the scan path.
+func (s *RESTServer) loadCorruptionHeatmap(ctx context.Context, depth int, pathID int64, days int) (*CorruptionHeatmap, error) {
+	query := `
+		SELECT cs.file_path, COALESCE(cs.path_id, 0), COALESCE(sp.local_path, ''),
+			cs.current_state, COALESCE(cs.detected_at, '')
+		FROM corruption_summary cs
+		LEFT JOIN scan_paths sp ON sp.id = cs.path_id
+		WHERE cs.current_state != 'CorruptionIgnored' AND cs.file_path IS NOT NULL`
+	var args []interface{}
+	if pathID > 0 {
+		query += ` AND cs.path_id = ?`
+		args = append(args, pathID)
+	}
+	if days > 0 {
+		query += ` AND substr(cs.detected_at, 1, 10) >= date('now', ?)`
+		args = append(args, fmt.Sprintf("-%d days", days))
+	}
+
+	rows, err := s.reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	heatmap := &CorruptionHeatmap{Depth: depth, Days: days, Directories: []HeatmapDirectory{}}
+	byDir := make(map[string]*HeatmapDirectory)
+	files := make(map[string]map[string]bool)
+	for rows.Next() {
+		var filePath, root, state, detectedAt string
+		var rowPathID int64
+		if err := rows.Scan(&filePath, &rowPathID, &root, &state, &detectedAt); err != nil {
+			return nil, err
+		}
+
+		dir, name, level := heatmapDirectory(filePath, root, depth)
+		entry, ok := byDir[dir]
+		if !ok {
+			entry = &HeatmapDirectory{PathID: rowPathID, Directory: dir, Name: name, Depth: level}
+			byDir[dir] = entry
+			files[dir] = make(map[string]bool)
+		}
+		entry.Corruptions++
+		if state == "VerificationSuccess" {
+			entry.Resolved++
+		} else {
+			entry.Unresolved++
+		}
+		files[dir][filePath] = true
+		if detectedAt > entry.LastDetectedAt {
+			entry.LastDetectedAt = detectedAt
+		}
+		heatmap.Total++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for dir, entry := range byDir {
+		entry.Files = len(files[dir])
+		heatmap.Directories = append(heatmap.Directories, *entry)
+	}
+	sort.Slice(heatmap.Directories, func(i, j int) bool {
+		a, b := heatmap.Directories[i], heatmap.Directories[j]
+		if a.Corruptions != b.Corruptions {
+			return a.Corruptions > b.Corruptions
+		}
+		return a.Directory < b.Directory
+	})
+	return heatmap, nil
+}
+
+// heatmapDirectory returns the directory a file is counted under: its
+// ancestor depth levels below root, or its own directory if that is higher
+// up. Files outside root, e.g. of a deleted scan path, count from /.
+func heatmapDirectory(filePath, root string, depth int) (dir, name string, level int) {
+	root = filepath.Clean(root)
+	rel, err := filepath.Rel(root, filepath.Dir(filePath))
+	if root == "." || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		root = string(filepath.Separator)
+		rel = strings.TrimPrefix(filepath.Dir(filePath), root)
+	}
+	if rel == "." || rel == "" {
+		return root, ".", 0
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	name = strings.Join(parts, "/")
+	return filepath.Join(root, filepath.Join(parts...)), name, len(parts)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetStatsHeatmap(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (1, '/media/tv', '/tv'), (2, '/media/movies', '/movies')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('a', '/media/tv/Show/Season 1/e1.mkv', 1, 'CorruptionDetected', '2026-04-01 10:00:00', '2026-04-01 10:00:00'),
+			('b', '/media/tv/Show/Season 1/e2.mkv', 1, 'VerificationSuccess', '2026-04-02 10:00:00', '2026-04-02 10:00:00'),
+			('c', '/media/tv/Show/Season 2/e1.mkv', 1, 'SearchStarted', '2026-04-03 10:00:00', '2026-04-03 10:00:00'),
+			('d', '/media/tv/stray.mkv', 1, 'CorruptionDetected', '2026-04-03 10:00:00', '2026-04-03 10:00:00'),
+			('e', '/media/movies/Film (2020)/film.mkv', 2, 'CorruptionDetected', '2026-04-04 10:00:00', '2026-04-04 10:00:00'),
+			('f', '/media/movies/Film (2020)/film.mkv', 2, 'CorruptionIgnored', '2026-04-04 11:00:00', '2026-04-04 11:00:00')
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/stats/heatmap", s.getStatsHeatmap)
+
+	get := func(query string) (*httptest.ResponseRecorder, CorruptionHeatmap) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/stats/heatmap"+query, nil)
+		r.ServeHTTP(w, req)
+		var heatmap CorruptionHeatmap
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &heatmap))
+		}
+		return w, heatmap
+	}
+
+	t.Run("seasons by default", func(t *testing.T) {
+		w, heatmap := get("")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 2, heatmap.Depth)
+		assert.Equal(t, 5, heatmap.Total, "ignored corruptions aren't counted")
+		require.Len(t, heatmap.Directories, 4)
+
+		top := heatmap.Directories[0]
+		assert.Equal(t, "/media/tv/Show/Season 1", top.Directory)
+		assert.Equal(t, "Show/Season 1", top.Name)
+		assert.Equal(t, 2, top.Corruptions)
+		assert.Equal(t, 1, top.Resolved)
+		assert.Equal(t, 1, top.Unresolved)
+		assert.Equal(t, 2, top.Files)
+
+		byName := map[string]HeatmapDirectory{}
+		for _, d := range heatmap.Directories {
+			byName[d.Directory] = d
+		}
+		assert.Equal(t, ".", byName["/media/tv"].Name, "files directly in the scan path")
+		assert.Equal(t, 1, byName["/media/movies/Film (2020)"].Depth)
+	})
+
+	t.Run("shows at depth 1 of one path", func(t *testing.T) {
+		w, heatmap := get("?depth=1&path_id=1")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, heatmap.Directories, 2)
+		assert.Equal(t, "Show", heatmap.Directories[0].Name)
+		assert.Equal(t, 3, heatmap.Directories[0].Corruptions)
+		assert.Equal(t, "2026-04-03 10:00:00", heatmap.Directories[0].LastDetectedAt)
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		w, _ := get("?depth=11")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w, _ = get("?depth=0")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHeatmapDirectory(t *testing.T) {
+	tests := []struct {
+		file, root string
+		depth      int
+		dir, name  string
+		level      int
+	}{
+		{"/media/tv/Show/Season 1/e1.mkv", "/media/tv", 1, "/media/tv/Show", "Show", 1},
+		{"/media/tv/Show/Season 1/e1.mkv", "/media/tv/", 3, "/media/tv/Show/Season 1", "Show/Season 1", 2},
+		{"/media/tv/e1.mkv", "/media/tv", 2, "/media/tv", ".", 0},
+		{"/other/Show/e1.mkv", "/media/tv", 1, "/other", "other", 1},
+		{"/other/Show/e1.mkv", "", 2, "/other/Show", "other/Show", 2},
+	}
+	for _, tt := range tests {
+		dir, name, level := heatmapDirectory(tt.file, tt.root, tt.depth)
+		assert.Equal(t, tt.dir, dir, tt.file)
+		assert.Equal(t, tt.name, name, tt.file)
+		assert.Equal(t, tt.level, level, tt.file)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// libraryMigrationTimeout bounds a library migration, which looks up the
+// media of every open corruption on the new instance.
+const libraryMigrationTimeout = 5 * time.Minute
+
+// migrateLibraryRequest moves scan paths of the instance in the URL to
+// ToInstanceID.
+type migrateLibraryRequest struct {
+	ToInstanceID int64   `json:"to_instance_id" binding:"required,gt=0"`
+	PathIDs      []int64 `json:"path_ids"` // empty: every scan path of the instance
+	DryRun       bool    `json:"dry_run"`
+}
+
+// migrateLibrary moves scan paths from one *arr instance to another of the
+// same type, e.g. after replacing Sonarr with a new install, and looks up
+// the media of their open corruptions on the new instance. The corruptions
+// keep their history and carry on remediating instead of running out of
+// retries against media IDs the new instance doesn't know.
+// POST /api/config/arr/:id/migrate
+func (s *RESTServer) migrateLibrary(c *gin.Context) {
+	fromID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	var req migrateLibraryRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	// A dry run doesn't look anything up
+	if !req.DryRun && (s.arrClient == nil || s.pathMapper == nil || s.eventBus == nil) {
+		respondServiceUnavailable(c, "Arr client")
+		return
+	}
+	// The scan paths are moved before the corruptions are looked up; a client
+	// that disconnects must not leave them half re-resolved
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), libraryMigrationTimeout)
+	defer cancel()
+
+	migration, err := services.MigrateLibrary(ctx, s.db, s.eventBus, s.pathMapper, s.arrClient, services.LibraryMigrationOptions{
+		FromInstanceID: fromID,
+		ToInstanceID:   req.ToInstanceID,
+		PathIDs:        req.PathIDs,
+		DryRun:         req.DryRun,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrArrInstanceNotFound):
+			respondNotFound(c, "Instance")
+		case errors.Is(err, services.ErrInvalidMigration):
+			respondBadRequest(c, err, true)
+		default:
+			respondDatabaseError(c, err)
+		}
+		return
+	}
+	if !req.DryRun {
+		s.reloadPathMappings("migrated")
+	}
+	c.JSON(http.StatusOK, migration)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateLibrary(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	_, err := db.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+		(1, 'Old Sonarr', 'sonarr', 'http://old:8989', 'key'),
+		(2, 'New Sonarr', 'sonarr', 'http://new:8989', 'key'),
+		(3, 'Radarr', 'radarr', 'http://radarr:7878', 'key')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/tv', '/tv', 1)`)
+	require.NoError(t, err)
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(t *testing.T, url, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		code, resp := post(t, "/api/config/arr/1/migrate", `{"to_instance_id": 2, "dry_run": true}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "New Sonarr", resp["to_instance"])
+		assert.Len(t, resp["paths"], 1)
+
+		var instanceID int64
+		require.NoError(t, db.QueryRow("SELECT arr_instance_id FROM scan_paths WHERE id = 1").Scan(&instanceID))
+		assert.Equal(t, int64(1), instanceID)
+	})
+
+	t.Run("other type", func(t *testing.T) {
+		code, resp := post(t, "/api/config/arr/1/migrate", `{"to_instance_id": 3, "dry_run": true}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, resp["error"], "Radarr is radarr")
+	})
+
+	t.Run("unknown instance", func(t *testing.T) {
+		code, _ := post(t, "/api/config/arr/9/migrate", `{"to_instance_id": 2, "dry_run": true}`)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		code, resp := post(t, "/api/config/arr/1/migrate", `{}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "to_instance_id is required", resp["error"])
+	})
+
+	t.Run("needs the arr client", func(t *testing.T) {
+		code, _ := post(t, "/api/config/arr/1/migrate", `{"to_instance_id": 2}`)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+	})
+}
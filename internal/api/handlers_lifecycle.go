@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+// getCorruptionLifecycle documents the corruption state machine that
+// published events are validated against. format=mermaid returns just the
+// diagram, e.g. for pasting into docs.
+// GET /api/corruptions/lifecycle
+func (s *RESTServer) getCorruptionLifecycle(c *gin.Context) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, gin.H{
+			"states":        domain.LifecycleStates(),
+			"initial":       domain.CorruptionDetected,
+			"terminal":      domain.TerminalStates(),
+			"transitions":   domain.LifecycleTransitions(),
+			"any_state":     domain.AnyStateEvents(),
+			"override_keys": domain.TransitionOverrideKeys(),
+			"diagram":       domain.LifecycleDiagram(),
+		})
+	case "mermaid":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(domain.LifecycleDiagram()))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or mermaid"})
+	}
+}
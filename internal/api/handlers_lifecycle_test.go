@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCorruptionLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r}
+	r.GET("/corruptions/lifecycle", s.getCorruptionLifecycle)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/corruptions/lifecycle")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var lifecycle struct {
+		Initial     string   `json:"initial"`
+		Terminal    []string `json:"terminal"`
+		AnyState    []string `json:"any_state"`
+		Transitions []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"transitions"`
+		Diagram string `json:"diagram"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lifecycle))
+	assert.Equal(t, "CorruptionDetected", lifecycle.Initial)
+	assert.Contains(t, lifecycle.Terminal, "VerificationSuccess")
+	assert.Contains(t, lifecycle.AnyState, "RetryScheduled")
+	assert.NotEmpty(t, lifecycle.Transitions)
+	assert.True(t, strings.HasPrefix(lifecycle.Diagram, "stateDiagram-v2"))
+
+	w = get("/corruptions/lifecycle?format=mermaid")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Equal(t, lifecycle.Diagram, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, get("/corruptions/lifecycle?format=svg").Code)
+}
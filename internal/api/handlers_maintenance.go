@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// summaryCheckTimeout bounds the corruption summary check and repair, which
+// read the whole events table.
+const summaryCheckTimeout = 2 * time.Minute
+
+// getMaintenanceStatus returns the maintenance schedule with the progress of
+// the current run and the outcome of the last one.
+func (s *RESTServer) getMaintenanceStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, s.maintenance.Status())
+}
+
+// triggerMaintenance starts a maintenance run in the background. With
+// ?skip_vacuum=true the incremental vacuum is left out.
+func (s *RESTServer) triggerMaintenance(c *gin.Context) {
+	run, err := s.maintenance.Trigger(services.MaintenanceOptions{
+		SkipVacuum: c.Query("skip_vacuum") == "true",
+	})
+	if errors.Is(err, services.ErrMaintenanceRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Maintenance is already running"})
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// abortMaintenance interrupts the running maintenance run, e.g. a long vacuum
+// during peak hours. The run ends once its current statement is interrupted.
+func (s *RESTServer) abortMaintenance(c *gin.Context) {
+	if err := s.maintenance.Abort(); errors.Is(err, services.ErrMaintenanceNotRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Maintenance is not running"})
+		return
+	}
+	c.JSON(http.StatusAccepted, s.maintenance.Status())
+}
+
+// checkCorruptionSummary compares corruption_summary, which the corruption
+// list and statistics read, with the summaries computed from the events.
+func (s *RESTServer) checkCorruptionSummary(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), summaryCheckTimeout)
+	defer cancel()
+
+	result, err := db.CheckCorruptionSummary(ctx, s.db)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"consistent": result.Consistent(), "result": result})
+}
+
+// repairCorruptionSummary rebuilds the corruption_summary rows that differ
+// from the events and returns what it found, or rebuilds every row with
+// ?full=true.
+func (s *RESTServer) repairCorruptionSummary(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), summaryCheckTimeout)
+	defer cancel()
+
+	if c.Query("full") == "true" {
+		rebuilt, err := db.RebuildCorruptionSummary(ctx, s.db)
+		if err != nil {
+			respondDatabaseError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"rebuilt": rebuilt})
+		return
+	}
+
+	result, err := db.RepairCorruptionSummary(ctx, s.db)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
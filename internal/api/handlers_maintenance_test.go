@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestMaintenanceEndpoints(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	maintenance := services.NewMaintenanceService(&db.Repository{DB: sqlDB}, db.UniformRetention(90), 30)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: sqlDB, maintenance: maintenance}
+	r.GET("/system/maintenance", s.getMaintenanceStatus)
+	r.POST("/system/maintenance", s.triggerMaintenance)
+	r.POST("/system/maintenance/abort", s.abortMaintenance)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusConflict, do("POST", "/system/maintenance/abort").Code)
+
+	w := do("POST", "/system/maintenance?skip_vacuum=true")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var run services.MaintenanceRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.Equal(t, services.MaintenanceTriggerManual, run.Trigger)
+	maintenance.Wait()
+
+	w = do("GET", "/system/maintenance")
+	require.Equal(t, http.StatusOK, w.Code)
+	var status services.MaintenanceStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.NotNil(t, status.LastRun)
+	assert.Nil(t, status.Current)
+	assert.Equal(t, services.MaintenanceCompleted, status.LastRun.Status)
+	for _, step := range status.LastRun.Steps {
+		if step.Name == db.MaintenanceStepVacuum {
+			assert.Equal(t, services.MaintenanceSkipped, step.Status)
+		}
+	}
+}
+
+func TestCorruptionSummaryEndpoints(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: sqlDB}
+	r.GET("/system/corruption-summary", s.checkCorruptionSummary)
+	r.POST("/system/corruption-summary/repair", s.repairCorruptionSummary)
+
+	do := func(method, path string) map[string]interface{} {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	_, err = testutil.SeedEvent(sqlDB, domain.Event{
+		AggregateID: "c1", AggregateType: "corruption", EventType: domain.CorruptionDetected,
+		EventData: map[string]interface{}{"file_path": "/media/a.mkv"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, do("GET", "/system/corruption-summary")["consistent"])
+
+	// A summary that fell behind its events
+	_, err = sqlDB.Exec(`UPDATE corruption_summary SET current_state = 'SearchStarted' WHERE corruption_id = 'c1'`)
+	require.NoError(t, err)
+	body := do("GET", "/system/corruption-summary")
+	assert.Equal(t, false, body["consistent"])
+	assert.Equal(t, []interface{}{"c1"}, body["result"].(map[string]interface{})["mismatched"])
+
+	assert.Equal(t, float64(1), do("POST", "/system/corruption-summary/repair")["repaired"])
+	assert.Equal(t, true, do("GET", "/system/corruption-summary")["consistent"])
+	assert.Equal(t, float64(1), do("POST", "/system/corruption-summary/repair?full=true")["rebuilt"])
+}
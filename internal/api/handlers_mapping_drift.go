@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// mappingDriftTimeout bounds a drift check, which asks every instance for its
+// root folders.
+const mappingDriftTimeout = 60 * time.Second
+
+// remapScanPathsRequest moves the *arr paths below From to To.
+type remapScanPathsRequest struct {
+	InstanceID int64  `json:"instance_id"` // 0: scan paths of every instance
+	From       string `json:"from"`
+	To         string `json:"to"`
+	DryRun     bool   `json:"dry_run"`
+}
+
+// getMappingDrift lists the scan paths whose *arr path is outside the root
+// folders of their instance, with the root folder they probably moved to.
+// GET /api/config/paths/drift
+func (s *RESTServer) getMappingDrift(c *gin.Context) {
+	if s.arrClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Arr client not available"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), mappingDriftTimeout)
+	defer cancel()
+
+	drifts, err := services.DetectMappingDrift(ctx, s.db, s.arrClient)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, drifts)
+}
+
+// remapScanPaths replaces an *arr path prefix in all scan paths below it, e.g.
+// after the library moved to another root folder in the *arr. The scan paths
+// keep their IDs and with them their corruption history.
+// POST /api/config/paths/remap
+func (s *RESTServer) remapScanPaths(c *gin.Context) {
+	var req remapScanPathsRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	remapped, err := services.RemapScanPaths(ctx, s.db, req.InstanceID, req.From, req.To, req.DryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRemap) {
+			respondBadRequest(c, err, true)
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	if !req.DryRun && len(remapped) > 0 {
+		logger.Infof("Remapped %d scan path(s) from %s to %s", len(remapped), req.From, req.To)
+		s.reloadPathMappings("remapped")
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": req.DryRun, "remapped": remapped})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMappingDrift_NoArrClient(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	req, _ := http.NewRequest("GET", "/api/config/paths/drift", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRemapScanPaths(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	_, err := db.Exec("INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'key')")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES
+		(1, '/media/tv', '/tv', 1), (2, '/media/tv/kids', '/tv/kids', 1), (3, '/media/tvshows', '/tvshows', 1)`)
+	require.NoError(t, err)
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(t *testing.T, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", "/api/config/paths/remap", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+	arrPath := func(id int) string {
+		var p string
+		require.NoError(t, db.QueryRow("SELECT arr_path FROM scan_paths WHERE id = ?", id).Scan(&p))
+		return p
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		code, resp := post(t, `{"instance_id": 1, "from": "/tv", "to": "/data/tv", "dry_run": true}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, resp["dry_run"])
+		assert.Len(t, resp["remapped"], 2)
+		assert.Equal(t, "/tv", arrPath(1))
+	})
+
+	t.Run("remap", func(t *testing.T) {
+		code, resp := post(t, `{"instance_id": 1, "from": "/tv", "to": "/data/tv"}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, resp["remapped"], 2)
+		assert.Equal(t, "/data/tv", arrPath(1))
+		assert.Equal(t, "/data/tv/kids", arrPath(2))
+		assert.Equal(t, "/tvshows", arrPath(3))
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		code, resp := post(t, `{"from": "tv", "to": "/data/tv"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, resp["error"], "absolute")
+	})
+}
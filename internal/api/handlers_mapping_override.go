@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// MappingStatus shows how a corruption's file maps to its *arr instance.
+type MappingStatus struct {
+	FilePath string `json:"file_path"`
+	// MappedArrPath is what the path mappings make of the file, empty when they can't map it
+	MappedArrPath string `json:"mapped_arr_path"`
+	MappingError  string `json:"mapping_error,omitempty"`
+	// Override is the operator's override, which remediation uses instead (nil if none)
+	Override *services.MappingOverride `json:"override"`
+}
+
+// corruptionFilePath returns the file path of a corruption, and false if
+// there is no such corruption.
+func (s *RESTServer) corruptionFilePath(ctx context.Context, id string) (string, bool, error) {
+	var filePath sql.NullString
+	err := s.db.QueryRowContext(ctx, `SELECT file_path FROM corruption_summary WHERE corruption_id = ?`, id).Scan(&filePath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return filePath.String, filePath.String != "", nil
+}
+
+// mappingOverrideTarget resolves the corruption of a mapping request to its
+// file path, responding with an error and returning false if it can't.
+func (s *RESTServer) mappingOverrideTarget(ctx context.Context, c *gin.Context) (string, bool) {
+	if s.mappingOverrides == nil {
+		respondServiceUnavailable(c, "Mapping overrides")
+		return "", false
+	}
+	filePath, ok, err := s.corruptionFilePath(ctx, c.Param("id"))
+	if err != nil {
+		respondDatabaseError(c, err)
+		return "", false
+	}
+	if !ok {
+		respondNotFound(c, "Corruption")
+		return "", false
+	}
+	return filePath, true
+}
+
+// respondMappingStatus sends the mapping of a corruption's file.
+func (s *RESTServer) respondMappingStatus(ctx context.Context, c *gin.Context, id, filePath string) {
+	status := MappingStatus{FilePath: filePath}
+	if mapped, err := s.mappingOverrides.MappedPath(filePath); err != nil {
+		status.MappingError = err.Error()
+	} else {
+		status.MappedArrPath = mapped
+	}
+	override, err := s.mappingOverrides.Get(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	status.Override = override
+	c.JSON(http.StatusOK, status)
+}
+
+// getMappingOverride returns how a corruption's file maps to its *arr
+// instance, and the override if one is set.
+// GET /api/corruptions/:id/mapping
+func (s *RESTServer) getMappingOverride(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	filePath, ok := s.mappingOverrideTarget(ctx, c)
+	if !ok {
+		return
+	}
+	s.respondMappingStatus(ctx, c, c.Param("id"), filePath)
+}
+
+// setMappingOverride sets the *arr path and/or media ID of a corruption's
+// file for when the path mappings get it wrong. Retry the corruption
+// (POST /api/corruptions/retry) to remediate it with the override.
+// PATCH /api/corruptions/:id/mapping
+func (s *RESTServer) setMappingOverride(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	var req struct {
+		ArrPath string `json:"arr_path"`
+		MediaID int64  `json:"media_id"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+	filePath, ok := s.mappingOverrideTarget(ctx, c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := s.mappingOverrides.Set(ctx, id, filePath, req.ArrPath, req.MediaID); err != nil {
+		if errors.Is(err, services.ErrEmptyMappingOverride) || errors.Is(err, services.ErrInvalidArrPath) || errors.Is(err, services.ErrUnmappedPath) {
+			respondBadRequest(c, err, true)
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondMappingStatus(ctx, c, id, filePath)
+}
+
+// clearMappingOverride removes a corruption's override, so the path mappings
+// apply to its file again.
+// DELETE /api/corruptions/:id/mapping
+func (s *RESTServer) clearMappingOverride(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	filePath, ok := s.mappingOverrideTarget(ctx, c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if err := s.mappingOverrides.Clear(id); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondMappingStatus(ctx, c, id, filePath)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestMappingOverrideEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (1, '/media/movies', '/movies')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state) VALUES ('c1', '/mnt/odd/a.mkv', 1, 'SearchFailed')`)
+	require.NoError(t, err)
+	mapper, err := integration.NewPathMapper(db)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, mappingOverrides: services.NewMappingOverrides(db, mapper)}
+	r.GET("/corruptions/:id/mapping", s.getMappingOverride)
+	r.PATCH("/corruptions/:id/mapping", s.setMappingOverride)
+	r.DELETE("/corruptions/:id/mapping", s.clearMappingOverride)
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, MappingStatus) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		r.ServeHTTP(w, req)
+		var status MappingStatus
+		_ = json.Unmarshal(w.Body.Bytes(), &status)
+		return w, status
+	}
+
+	w, status := do("GET", "/corruptions/c1/mapping", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, status.MappedArrPath)
+	assert.Contains(t, status.MappingError, "no mapping found")
+	assert.Nil(t, status.Override)
+
+	// The unmapped file needs an *arr path
+	w, _ = do("PATCH", "/corruptions/c1/mapping", `{"media_id": 12}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, status = do("PATCH", "/corruptions/c1/mapping", `{"arr_path": "/movies/A/a.mkv", "media_id": 12}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, status.Override)
+	assert.Equal(t, "/movies/A/a.mkv", status.Override.ArrPath)
+	assert.Equal(t, int64(12), status.Override.MediaID)
+
+	w, _ = do("PATCH", "/corruptions/missing/mapping", `{"arr_path": "/movies/b.mkv"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, status = do("DELETE", "/corruptions/c1/mapping", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, status.Override)
+
+	s.mappingOverrides = nil
+	w, _ = do("GET", "/corruptions/c1/mapping", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// MediaHistory is every corruption Healarr has recorded for one movie or series.
+type MediaHistory struct {
+	ArrInstanceID int64                    `json:"arr_instance_id"`
+	MediaID       int64                    `json:"media_id"`
+	MediaTitle    string                   `json:"media_title,omitempty"`
+	MediaYear     int                      `json:"media_year,omitempty"`
+	Summary       MediaHistorySummary      `json:"summary"`
+	Corruptions   []MediaHistoryCorruption `json:"corruptions"`
+}
+
+// MediaHistorySummary counts a media item's corruptions by outcome.
+type MediaHistorySummary struct {
+	TotalCorruptions int     `json:"total_corruptions"`
+	TimesReplaced    int     `json:"times_replaced"`
+	Failed           int     `json:"failed"`
+	ManualAction     int     `json:"manual_action"`
+	InProgress       int     `json:"in_progress"`
+	Ignored          int     `json:"ignored"`
+	FirstDetectedAt  *string `json:"first_detected_at,omitempty"`
+	LastDetectedAt   *string `json:"last_detected_at,omitempty"`
+}
+
+// MediaHistoryCorruption is one corruption of the media item and how it ended.
+type MediaHistoryCorruption struct {
+	CorruptionID   string `json:"corruption_id"`
+	FilePath       string `json:"file_path"`
+	CorruptionType string `json:"corruption_type,omitempty"`
+	State          string `json:"state"`
+	Outcome        string `json:"outcome"` // "replaced", "failed", "manual_action", "ignored" or "in_progress"
+	RetryCount     int    `json:"retry_count"`
+	DetectedAt     string `json:"detected_at"`
+	LastUpdatedAt  string `json:"last_updated_at"`
+}
+
+// corruptionOutcome groups a corruption's current state into its outcome.
+func corruptionOutcome(state string) string {
+	switch domain.EventType(state) {
+	case domain.VerificationSuccess:
+		return "replaced"
+	case domain.MaxRetriesReached, domain.SearchExhausted:
+		return "failed"
+	case domain.ImportBlocked, domain.ManuallyRemoved, domain.BudgetApprovalRequired, domain.LowConfidenceDetection, domain.QualityRegression, domain.AudioTrackMissing, domain.DeletionPlanChanged:
+		return "manual_action"
+	case domain.CorruptionIgnored:
+		return "ignored"
+	}
+	if strings.HasSuffix(state, "Failed") {
+		return "failed"
+	}
+	return "in_progress"
+}
+
+// getMediaHistory returns all corruptions and remediation outcomes of a movie or
+// series, so repeat offenders can be spotted.
+// GET /api/media/:arr_instance/:media_id/history
+func (s *RESTServer) getMediaHistory(c *gin.Context) {
+	instanceID, err := strconv.ParseInt(c.Param("arr_instance"), 10, 64)
+	if err != nil || instanceID <= 0 {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	mediaID, err := strconv.ParseInt(c.Param("media_id"), 10, 64)
+	if err != nil || mediaID <= 0 {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	// Corruptions are tied to media through the media_id of their search and
+	// deletion events, or of the lookup after a library migration, and to the
+	// instance through their scan path.
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT cs.corruption_id, cs.file_path, cs.corruption_type, cs.current_state,
+			cs.retry_count, cs.detected_at, cs.last_updated_at
+		FROM corruption_summary cs
+		JOIN scan_paths sp ON sp.id = cs.path_id
+		WHERE sp.arr_instance_id = ?
+		AND cs.corruption_id IN (
+			SELECT aggregate_id FROM events
+			WHERE aggregate_type = 'corruption'
+			AND json_extract(event_data, '$.media_id') = ?
+			UNION
+			SELECT json_extract(event_data, '$.corruption_id') FROM events
+			WHERE event_type = 'MediaReresolved'
+			AND json_extract(event_data, '$.media_id') = ?
+		)
+		ORDER BY cs.detected_at ASC
+	`, instanceID, mediaID, mediaID)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	defer rows.Close()
+
+	history := MediaHistory{
+		ArrInstanceID: instanceID,
+		MediaID:       mediaID,
+		Corruptions:   make([]MediaHistoryCorruption, 0),
+	}
+	for rows.Next() {
+		var mc MediaHistoryCorruption
+		var filePath, corruptionType sql.NullString
+		if err := rows.Scan(&mc.CorruptionID, &filePath, &corruptionType, &mc.State,
+			&mc.RetryCount, &mc.DetectedAt, &mc.LastUpdatedAt); err != nil {
+			logger.Debugf("Failed to scan media history row: %v", err)
+			continue
+		}
+		mc.FilePath = filePath.String
+		mc.CorruptionType = corruptionType.String
+		mc.Outcome = corruptionOutcome(mc.State)
+		history.Corruptions = append(history.Corruptions, mc)
+	}
+	if err := rows.Err(); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if len(history.Corruptions) == 0 {
+		respondNotFound(c, "Media history")
+		return
+	}
+
+	history.Summary = summarizeMediaHistory(history.Corruptions)
+	s.loadMediaTitle(ctx, &history)
+
+	c.JSON(http.StatusOK, history)
+}
+
+// summarizeMediaHistory counts corruptions by outcome. Corruptions are ordered by detection time.
+func summarizeMediaHistory(corruptions []MediaHistoryCorruption) MediaHistorySummary {
+	summary := MediaHistorySummary{TotalCorruptions: len(corruptions)}
+	for _, mc := range corruptions {
+		switch mc.Outcome {
+		case "replaced":
+			summary.TimesReplaced++
+		case "failed":
+			summary.Failed++
+		case "manual_action":
+			summary.ManualAction++
+		case "ignored":
+			summary.Ignored++
+		default:
+			summary.InProgress++
+		}
+	}
+	if len(corruptions) > 0 {
+		first := corruptions[0].DetectedAt
+		last := corruptions[len(corruptions)-1].DetectedAt
+		summary.FirstDetectedAt = &first
+		summary.LastDetectedAt = &last
+	}
+	return summary
+}
+
+// loadMediaTitle fills in the title and year recorded by the most recent search
+// for any of the media item's corruptions.
+func (s *RESTServer) loadMediaTitle(ctx context.Context, history *MediaHistory) {
+	placeholders := make([]string, len(history.Corruptions))
+	args := make([]interface{}, len(history.Corruptions))
+	for i, mc := range history.Corruptions {
+		placeholders[i] = "?"
+		args[i] = mc.CorruptionID
+	}
+
+	var title sql.NullString
+	var year sql.NullInt64
+	err := s.reader().QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.media_title'), json_extract(event_data, '$.media_year')
+		FROM events
+		WHERE aggregate_id IN (`+strings.Join(placeholders, ",")+`)
+		AND json_extract(event_data, '$.media_title') IS NOT NULL
+		ORDER BY id DESC LIMIT 1
+	`, args...).Scan(&title, &year)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Debugf("Failed to query media title: %v", err)
+		}
+		return
+	}
+	history.MediaTitle = title.String
+	history.MediaYear = int(year.Int64)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetMediaHistory(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/movies', '/movies', 4), (2, '/media/other', '/other', 5);
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at) VALUES
+			('corruption', 'm1', 'CorruptionDetected', '{"file_path":"/media/movies/a.mkv","path_id":1}', '2026-01-01 10:00:00'),
+			('corruption', 'm1', 'SearchCompleted', '{"file_path":"/media/movies/a.mkv","media_id":42,"media_title":"Old Title","media_year":1999}', '2026-01-01 10:01:00'),
+			('corruption', 'm1', 'VerificationSuccess', '{"file_path":"/media/movies/a.mkv"}', '2026-01-01 11:00:00'),
+			('corruption', 'm2', 'CorruptionDetected', '{"file_path":"/media/movies/b.mkv","path_id":1}', '2026-02-01 10:00:00'),
+			('corruption', 'm2', 'SearchCompleted', '{"file_path":"/media/movies/b.mkv","media_id":42,"media_title":"The Movie","media_year":2001}', '2026-02-01 10:01:00'),
+			('corruption', 'm2', 'MaxRetriesReached', '{"file_path":"/media/movies/b.mkv"}', '2026-02-02 10:00:00'),
+			('corruption', 'm3', 'CorruptionDetected', '{"file_path":"/media/movies/c.mkv","path_id":1}', '2026-03-01 10:00:00'),
+			('corruption', 'm3', 'DeletionCompleted', '{"media_id":42}', '2026-03-01 10:01:00'),
+			('corruption', 'x1', 'CorruptionDetected', '{"file_path":"/media/other/z.mkv","path_id":2}', '2026-01-01 10:00:00'),
+			('corruption', 'x1', 'SearchCompleted', '{"file_path":"/media/other/z.mkv","media_id":42}', '2026-01-01 10:01:00'),
+			('corruption', 'y1', 'CorruptionDetected', '{"file_path":"/media/movies/y.mkv","path_id":1}', '2026-01-01 10:00:00'),
+			('corruption', 'y1', 'SearchCompleted', '{"file_path":"/media/movies/y.mkv","media_id":7}', '2026-01-01 10:01:00');
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/media/:arr_instance/:media_id/history", s.getMediaHistory)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/media/4/42/history", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var history MediaHistory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	assert.Equal(t, "The Movie", history.MediaTitle)
+	assert.Equal(t, 2001, history.MediaYear)
+
+	// x1 belongs to another instance, y1 to another movie
+	require.Len(t, history.Corruptions, 3)
+	assert.Equal(t, "m1", history.Corruptions[0].CorruptionID)
+	assert.Equal(t, "replaced", history.Corruptions[0].Outcome)
+	assert.Equal(t, "failed", history.Corruptions[1].Outcome)
+	assert.Equal(t, "in_progress", history.Corruptions[2].Outcome)
+
+	assert.Equal(t, 3, history.Summary.TotalCorruptions)
+	assert.Equal(t, 1, history.Summary.TimesReplaced)
+	assert.Equal(t, 1, history.Summary.Failed)
+	assert.Equal(t, 1, history.Summary.InProgress)
+	require.NotNil(t, history.Summary.FirstDetectedAt)
+	assert.Contains(t, *history.Summary.FirstDetectedAt, "2026-01-01")
+}
+
+func TestGetMediaHistory_Errors(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/media/:arr_instance/:media_id/history", s.getMediaHistory)
+
+	for path, want := range map[string]int{
+		"/media/abc/1/history": http.StatusBadRequest,
+		"/media/1/0/history":   http.StatusBadRequest,
+		"/media/1/99/history":  http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, path)
+	}
+}
+
+func TestCorruptionOutcome(t *testing.T) {
+	tests := map[string]string{
+		"VerificationSuccess": "replaced",
+		"MaxRetriesReached":   "failed",
+		"SearchExhausted":     "failed",
+		"DeletionFailed":      "failed",
+		"ImportBlocked":       "manual_action",
+		"CorruptionIgnored":   "ignored",
+		"CorruptionDetected":  "in_progress",
+		"DownloadProgress":    "in_progress",
+	}
+	for state, want := range tests {
+		assert.Equal(t, want, corruptionOutcome(state), state)
+	}
+}
@@ -1,13 +1,17 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/mescon/Healarr/internal/i18n"
 	"github.com/mescon/Healarr/internal/logger"
 	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // requireNotifier checks if the notifier is available, returning false and sending error if not
@@ -29,6 +33,9 @@ func (s *RESTServer) getNotifications(c *gin.Context) {
 		respondDatabaseError(c, err)
 		return
 	}
+	for _, cfg := range configs {
+		cfg.Config = cfg.MaskedConfig()
+	}
 
 	c.JSON(http.StatusOK, configs)
 }
@@ -39,8 +46,7 @@ func (s *RESTServer) createNotification(c *gin.Context) {
 	}
 
 	var req notifier.NotificationConfig
-	if err := c.BindJSON(&req); err != nil {
-		respondBadRequest(c, err, false)
+	if !bindBody(c, &req) {
 		return
 	}
 
@@ -48,6 +54,9 @@ func (s *RESTServer) createNotification(c *gin.Context) {
 	if req.ThrottleSeconds <= 0 || req.ThrottleSeconds > 3600 {
 		req.ThrottleSeconds = 5
 	}
+	if !normalizeNotificationLocale(c, &req) {
+		return
+	}
 
 	id, err := s.notifier.CreateConfig(&req)
 	if err != nil {
@@ -66,16 +75,18 @@ func (s *RESTServer) updateNotification(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
 	var req notifier.NotificationConfig
-	if err := c.BindJSON(&req); err != nil {
-		respondBadRequest(c, err, false)
+	if !bindBody(c, &req) {
 		return
 	}
 	req.ID = id
+	if !normalizeNotificationLocale(c, &req) || !s.keepNotificationSecrets(c, &req) {
+		return
+	}
 
 	if err := s.notifier.UpdateConfig(&req); err != nil {
 		respondDatabaseError(c, err)
@@ -93,7 +104,7 @@ func (s *RESTServer) deleteNotification(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
@@ -111,8 +122,13 @@ func (s *RESTServer) testNotification(c *gin.Context) {
 	}
 
 	var req notifier.NotificationConfig
-	if err := c.BindJSON(&req); err != nil {
-		respondBadRequest(c, err, false)
+	if !bindBody(c, &req) {
+		return
+	}
+	if !normalizeNotificationLocale(c, &req) {
+		return
+	}
+	if req.ID > 0 && !s.keepNotificationSecrets(c, &req) {
 		return
 	}
 
@@ -144,7 +160,7 @@ func (s *RESTServer) getNotificationLog(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
@@ -171,7 +187,7 @@ func (s *RESTServer) getNotification(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
@@ -180,6 +196,35 @@ func (s *RESTServer) getNotification(c *gin.Context) {
 		respondNotFound(c, "Notification")
 		return
 	}
+	cfg.Config = cfg.MaskedConfig()
 
 	c.JSON(http.StatusOK, cfg)
 }
+
+// keepNotificationSecrets replaces the masked secrets of a submitted config
+// with the stored ones, responding with an error if it isn't stored.
+func (s *RESTServer) keepNotificationSecrets(c *gin.Context, req *notifier.NotificationConfig) bool {
+	if !redact.HasMask(req.Config) {
+		return true
+	}
+	stored, err := s.notifier.GetConfig(req.ID)
+	if err != nil {
+		respondNotFound(c, "Notification")
+		return false
+	}
+	req.KeepSecrets(stored)
+	return true
+}
+
+// normalizeNotificationLocale reduces the locale of a submitted config to a
+// supported one, responding with an error if there is none.
+func normalizeNotificationLocale(c *gin.Context, req *notifier.NotificationConfig) bool {
+	locale, ok := i18n.Normalize(req.Locale)
+	if !ok {
+		respondBadRequest(c, fmt.Errorf("unsupported locale %q, use one of: %s",
+			req.Locale, strings.Join(i18n.Locales(), ", ")), true)
+		return false
+	}
+	req.Locale = locale
+	return true
+}
@@ -32,8 +32,10 @@ func setupNotificationsTestDB(t *testing.T) (*sql.DB, func()) {
 			provider_type TEXT NOT NULL,
 			config TEXT NOT NULL,
 			events TEXT NOT NULL DEFAULT '[]',
+			group_ids TEXT NOT NULL DEFAULT '[]',
 			enabled INTEGER DEFAULT 1,
 			throttle_seconds INTEGER DEFAULT 5,
+			locale TEXT NOT NULL DEFAULT '',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
@@ -347,6 +349,41 @@ func TestCreateNotification_DefaultThrottle(t *testing.T) {
 	assert.Equal(t, 5, throttle)
 }
 
+func TestCreateNotification_Locale(t *testing.T) {
+	db, cleanup := setupNotificationsTestDB(t)
+	defer cleanup()
+
+	router, apiKey, serverCleanup := setupNotificationsTestServer(t, db, true)
+	defer serverCleanup()
+
+	create := func(name, locale string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(`{
+			"name": "` + name + `",
+			"provider_type": "slack",
+			"config": {"webhook_url": "https://hooks.slack.com/test"},
+			"events": [],
+			"enabled": true,
+			"locale": "` + locale + `"
+		}`)
+		req, _ := http.NewRequest("POST", "/api/config/notifications", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create("German", "de-DE")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var locale string
+	db.QueryRow("SELECT locale FROM notifications WHERE name = ?", "German").Scan(&locale)
+	assert.Equal(t, "de", locale)
+
+	w = create("Italian", "it")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "en, de, fr, es")
+}
+
 func TestCreateNotification_InvalidJSON(t *testing.T) {
 	db, cleanup := setupNotificationsTestDB(t)
 	defer cleanup()
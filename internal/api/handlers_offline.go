@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/web"
+)
+
+// Kinds of outbound destinations in an offline check.
+const (
+	OutboundArr          = "arr"
+	OutboundNotification = "notification"
+	OutboundWebhook      = "webhook"
+	OutboundMQTT         = "mqtt"
+	OutboundUpdateCheck  = "update_check"
+	OutboundTelemetry    = "telemetry"
+)
+
+// OfflineCheck reports whether Healarr works without internet access: the web
+// UI must not load anything from other hosts and only destinations the user
+// configured may be contacted.
+type OfflineCheck struct {
+	OfflineMode bool             `json:"offline_mode"`
+	Passed      bool             `json:"passed"`
+	WebAssets   OfflineWebAssets `json:"web_assets"`
+	Outbound    []OutboundTarget `json:"outbound"`
+}
+
+// OfflineWebAssets lists what the served web UI loads from other hosts.
+type OfflineWebAssets struct {
+	// Source is "embedded", "filesystem" or "none" (API-only mode)
+	Source             string                  `json:"source"`
+	ExternalReferences []web.ExternalReference `json:"external_references"`
+	Error              string                  `json:"error,omitempty"`
+}
+
+// OutboundTarget is a host Healarr may connect to. Configured targets are
+// expected in an air-gapped network; anything else fails the check while enabled.
+type OutboundTarget struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Host       string `json:"host,omitempty"`
+	Enabled    bool   `json:"enabled"`
+	Configured bool   `json:"configured"`
+}
+
+// handleOfflineCheck verifies the build and configuration for air-gapped use.
+func (s *RESTServer) handleOfflineCheck(c *gin.Context) {
+	cfg := config.Get()
+	check := OfflineCheck{
+		OfflineMode: cfg.OfflineMode,
+		WebAssets:   checkWebAssets(cfg.WebDir),
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	outbound, err := s.configuredOutbound(ctx, cfg)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	check.Outbound = append(outbound, OutboundTarget{
+		Kind:    OutboundUpdateCheck,
+		Name:    "GitHub update check",
+		Host:    hostOf(githubAPIURL),
+		Enabled: !cfg.OfflineMode,
+	})
+	if s.telemetry != nil {
+		if status := s.telemetry.Status(); status.Endpoint != "" {
+			check.Outbound = append(check.Outbound, OutboundTarget{
+				Kind:    OutboundTelemetry,
+				Name:    "Anonymous usage statistics",
+				Host:    hostOf(status.Endpoint),
+				Enabled: status.Enabled,
+			})
+		}
+	}
+
+	check.Passed = check.WebAssets.Error == "" && len(check.WebAssets.ExternalReferences) == 0
+	for _, target := range check.Outbound {
+		if target.Enabled && !target.Configured {
+			check.Passed = false
+		}
+	}
+	c.JSON(http.StatusOK, check)
+}
+
+// checkWebAssets scans the web UI that is being served, preferring embedded
+// assets over webDir as the router does.
+func checkWebAssets(webDir string) OfflineWebAssets {
+	assets := OfflineWebAssets{Source: "none", ExternalReferences: []web.ExternalReference{}}
+
+	var fsys fs.FS
+	if web.HasEmbeddedAssets() {
+		assets.Source = "embedded"
+		fsys = web.GetFS()
+	} else if _, err := os.Stat(filepath.Join(webDir, indexHTMLFile)); err == nil {
+		assets.Source = "filesystem"
+		fsys = os.DirFS(webDir)
+	} else {
+		return assets
+	}
+
+	refs, err := web.FindExternalReferences(fsys)
+	if err != nil {
+		assets.Error = err.Error()
+		return assets
+	}
+	assets.ExternalReferences = refs
+	return assets
+}
+
+// configuredOutbound lists the *arr instances, notification providers, webhooks
+// and MQTT broker Healarr talks to.
+func (s *RESTServer) configuredOutbound(ctx context.Context, cfg *config.Config) ([]OutboundTarget, error) {
+	targets := []OutboundTarget{}
+
+	queries := []struct {
+		kind  string
+		query string
+	}{
+		{OutboundArr, "SELECT name, url, enabled FROM arr_instances WHERE deleted_at IS NULL ORDER BY name"},
+		{OutboundNotification, "SELECT name, provider_type, enabled FROM notifications ORDER BY name"},
+		{OutboundWebhook, "SELECT name, url, enabled FROM outgoing_webhooks ORDER BY name"},
+	}
+	for _, q := range queries {
+		rows, err := s.db.QueryContext(ctx, q.query)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var name, target string
+			var enabled sql.NullBool
+			if err := rows.Scan(&name, &target, &enabled); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			t := OutboundTarget{Kind: q.kind, Name: name, Enabled: !enabled.Valid || enabled.Bool, Configured: true}
+			if q.kind == OutboundNotification {
+				// Provider URLs live in the (possibly encrypted) config; name the provider instead
+				t.Name = name + " (" + target + ")"
+			} else {
+				t.Host = hostOf(target)
+			}
+			targets = append(targets, t)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.MQTTBroker != "" {
+		targets = append(targets, OutboundTarget{
+			Kind:       OutboundMQTT,
+			Name:       "MQTT broker",
+			Host:       hostOf(cfg.MQTTBroker),
+			Enabled:    true,
+			Configured: true,
+		})
+	}
+	return targets, nil
+}
+
+// hostOf returns the host:port of rawURL, or rawURL itself if it doesn't parse.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host
+}
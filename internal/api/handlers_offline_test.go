@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestHandleOfflineCheck(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	_, err = sqlDB.Exec(`
+		CREATE TABLE notifications (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			provider_type TEXT NOT NULL,
+			config TEXT NOT NULL,
+			events TEXT NOT NULL,
+			group_ids TEXT NOT NULL DEFAULT '[]',
+			enabled BOOLEAN DEFAULT 1
+		);
+		CREATE TABLE outgoing_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT 1
+		);
+	`)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec("INSERT INTO arr_instances (name, type, url, api_key, enabled) VALUES (?, ?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://sonarr:8989", "key", true)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec("INSERT INTO notifications (name, provider_type, config, events) VALUES (?, ?, ?, ?)",
+		"Alerts", "discord", "{}", "[]")
+	require.NoError(t, err)
+
+	webDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, indexHTMLFile),
+		[]byte(`<html><head><script type="module" src="/assets/index.js"></script></head></html>`), 0o644))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: sqlDB}
+	r.GET("/system/offline-check", s.handleOfflineCheck)
+
+	check := func(t *testing.T) OfflineCheck {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/system/offline-check", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result OfflineCheck
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	t.Run("online", func(t *testing.T) {
+		cfg := config.NewTestConfig()
+		cfg.WebDir = webDir
+		config.SetForTesting(cfg)
+
+		result := check(t)
+		assert.False(t, result.Passed, "update check is an unconfigured destination")
+		assert.Equal(t, "filesystem", result.WebAssets.Source)
+		assert.Empty(t, result.WebAssets.ExternalReferences)
+
+		require.Len(t, result.Outbound, 3)
+		assert.Equal(t, OutboundTarget{Kind: OutboundArr, Name: "Sonarr", Host: "sonarr:8989", Enabled: true, Configured: true}, result.Outbound[0])
+		assert.Equal(t, OutboundTarget{Kind: OutboundNotification, Name: "Alerts (discord)", Enabled: true, Configured: true}, result.Outbound[1])
+		assert.Equal(t, OutboundUpdateCheck, result.Outbound[2].Kind)
+		assert.True(t, result.Outbound[2].Enabled)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		cfg := config.NewTestConfig()
+		cfg.WebDir = webDir
+		cfg.OfflineMode = true
+		config.SetForTesting(cfg)
+
+		result := check(t)
+		assert.True(t, result.OfflineMode)
+		assert.True(t, result.Passed)
+		assert.False(t, result.Outbound[len(result.Outbound)-1].Enabled)
+	})
+
+	t.Run("external assets", func(t *testing.T) {
+		cdnDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(cdnDir, indexHTMLFile),
+			[]byte(`<html><head><link rel="stylesheet" href="https://fonts.googleapis.com/css"></head></html>`), 0o644))
+
+		cfg := config.NewTestConfig()
+		cfg.WebDir = cdnDir
+		cfg.OfflineMode = true
+		config.SetForTesting(cfg)
+
+		result := check(t)
+		assert.False(t, result.Passed)
+		require.Len(t, result.WebAssets.ExternalReferences, 1)
+		assert.Equal(t, "https://fonts.googleapis.com/css", result.WebAssets.ExternalReferences[0].URL)
+	})
+}
+
+func TestHandleCheckUpdate_OfflineMode(t *testing.T) {
+	cfg := config.NewTestConfig()
+	cfg.OfflineMode = true
+	config.SetForTesting(cfg)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r}
+	r.GET("/updates/check", s.handleCheckUpdate)
+
+	req, _ := http.NewRequest("GET", "/updates/check", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp UpdateCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.ChecksDisabled)
+	assert.False(t, resp.UpdateAvailable)
+	assert.Equal(t, config.Version, resp.CurrentVersion)
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// outgoingWebhookRequest is the body for creating or updating an outgoing webhook.
+type outgoingWebhookRequest struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"` // Empty generates one on create; empty or masked keeps the existing one on update
+	Events      []string `json:"events"`
+	Enabled     *bool    `json:"enabled"`
+	MaxAttempts int      `json:"max_attempts"`
+}
+
+// requireOutbox checks if the webhook outbox is available, returning false and sending error if not
+func (s *RESTServer) requireOutbox(c *gin.Context) bool {
+	if s.outbox == nil {
+		respondServiceUnavailable(c, "Webhook outbox")
+		return false
+	}
+	return true
+}
+
+// toWebhook validates the request and converts it to an OutgoingWebhook.
+func (req *outgoingWebhookRequest) toWebhook() (*notifier.OutgoingWebhook, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := validateArrURL(req.URL); err != nil {
+		return nil, err
+	}
+	if len(req.Events) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	for _, e := range req.Events {
+		if !domain.IsValidEventType(domain.EventType(e)) {
+			return nil, fmt.Errorf("unknown event type: %s", e)
+		}
+	}
+
+	maxAttempts := req.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = notifier.DefaultWebhookMaxAttempts
+	}
+	if maxAttempts < 1 || maxAttempts > notifier.MaxWebhookMaxAttempts {
+		return nil, fmt.Errorf("max_attempts must be between 1 and %d", notifier.MaxWebhookMaxAttempts)
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return &notifier.OutgoingWebhook{
+		Name:        name,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Enabled:     enabled,
+		MaxAttempts: maxAttempts,
+	}, nil
+}
+
+func (s *RESTServer) getOutgoingWebhooks(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	webhooks, err := s.outbox.GetAllWebhooks()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	for _, w := range webhooks {
+		w.Secret = redact.Value(w.Secret)
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (s *RESTServer) getOutgoingWebhook(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	webhook, err := s.outbox.GetWebhook(id)
+	if err != nil {
+		respondNotFound(c, "Webhook")
+		return
+	}
+	webhook.Secret = redact.Value(webhook.Secret)
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (s *RESTServer) createOutgoingWebhook(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	var req outgoingWebhookRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	webhook, err := req.toWebhook()
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+
+	if webhook.Secret == "" || webhook.Secret == redact.Mask {
+		if webhook.Secret, err = notifier.GenerateWebhookSecret(); err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+			return
+		}
+	}
+
+	id, err := s.outbox.CreateWebhook(webhook)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id, "secret": webhook.Secret, "message": "Webhook created"})
+}
+
+func (s *RESTServer) updateOutgoingWebhook(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	var req outgoingWebhookRequest
+	if !bindBody(c, &req) {
+		return
+	}
+	webhook, err := req.toWebhook()
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+	webhook.ID = id
+
+	if webhook.Secret == "" || webhook.Secret == redact.Mask {
+		existing, err := s.outbox.GetWebhook(id)
+		if err != nil {
+			respondNotFound(c, "Webhook")
+			return
+		}
+		webhook.Secret = existing.Secret
+	}
+
+	if err := s.outbox.UpdateWebhook(webhook); err != nil {
+		if errors.Is(err, notifier.ErrWebhookNotFound) {
+			respondNotFound(c, "Webhook")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook updated"})
+}
+
+func (s *RESTServer) deleteOutgoingWebhook(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	if err := s.outbox.DeleteWebhook(id); err != nil {
+		if errors.Is(err, notifier.ErrWebhookNotFound) {
+			respondNotFound(c, "Webhook")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// getOutgoingWebhookDeliveries returns delivery status, optionally filtered by
+// webhook (route :id) and ?status=pending|delivered|failed.
+func (s *RESTServer) getOutgoingWebhookDeliveries(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	var webhookID int64
+	if idStr := c.Param("id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+			return
+		}
+		webhookID = id
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", notifier.DeliveryPending, notifier.DeliveryDelivered, notifier.DeliveryFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of: pending, delivered, failed"})
+		return
+	}
+
+	limit := parseInt(c.DefaultQuery("limit", "50"), 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	deliveries, err := s.outbox.GetDeliveries(webhookID, status, limit)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// retryOutgoingWebhookDelivery re-queues a delivery for immediate delivery.
+func (s *RESTServer) retryOutgoingWebhookDelivery(c *gin.Context) {
+	if !s.requireOutbox(c) {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	if err := s.outbox.RetryDelivery(id); err != nil {
+		if errors.Is(err, notifier.ErrWebhookNotFound) {
+			respondNotFound(c, "Delivery")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Delivery queued for retry"})
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/auth"
+	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// setupOutgoingWebhooksTestServer creates a test server with the outgoing webhook routes
+func setupOutgoingWebhooksTestServer(t *testing.T, withOutbox bool) (*gin.Engine, *sql.DB, string, func()) {
+	t.Helper()
+
+	db, dbCleanup := setupTestDB(t)
+	_, err := db.Exec(`
+		CREATE TABLE outgoing_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT 1,
+			max_attempts INTEGER DEFAULT 5,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE outgoing_webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			webhook_id INTEGER NOT NULL,
+			event_id INTEGER,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			response_code INTEGER,
+			last_error TEXT,
+			next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_attempt_at TIMESTAMP,
+			delivered_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	eb := eventbus.NewEventBus(db)
+	s := &RESTServer{router: r, db: db, eventBus: eb}
+	if withOutbox {
+		s.outbox = notifier.NewWebhookOutbox(db, eb)
+	}
+
+	apiKey, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	encryptedKey, err := crypto.Encrypt(apiKey)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO settings (key, value) VALUES ('api_key', ?)", encryptedKey)
+	require.NoError(t, err)
+
+	protected := r.Group("/api")
+	protected.Use(s.authMiddleware())
+	{
+		protected.GET("/webhooks/outgoing", s.getOutgoingWebhooks)
+		protected.POST("/webhooks/outgoing", s.createOutgoingWebhook)
+		protected.GET("/webhooks/outgoing/deliveries", s.getOutgoingWebhookDeliveries)
+		protected.POST("/webhooks/outgoing/deliveries/:id/retry", s.retryOutgoingWebhookDelivery)
+		protected.GET(routeOutgoingWebhookByID, s.getOutgoingWebhook)
+		protected.PUT(routeOutgoingWebhookByID, s.updateOutgoingWebhook)
+		protected.DELETE(routeOutgoingWebhookByID, s.deleteOutgoingWebhook)
+		protected.GET(routeOutgoingWebhookByID+"/deliveries", s.getOutgoingWebhookDeliveries)
+	}
+
+	cleanup := func() {
+		eb.Shutdown()
+		dbCleanup()
+	}
+	return r, db, apiKey, cleanup
+}
+
+func doOutgoingWebhookRequest(t *testing.T, router *gin.Engine, apiKey, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOutgoingWebhooks_ServiceUnavailable(t *testing.T) {
+	router, _, apiKey, cleanup := setupOutgoingWebhooksTestServer(t, false)
+	defer cleanup()
+
+	w := doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestCreateOutgoingWebhook_Validation(t *testing.T) {
+	router, _, apiKey, cleanup := setupOutgoingWebhooksTestServer(t, true)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"missing name", `{"url":"http://hook.local","events":["ScanStarted"]}`, "name is required"},
+		{"bad scheme", `{"name":"a","url":"ftp://hook.local","events":["ScanStarted"]}`, "URL must start with http:// or https://"},
+		{"no events", `{"name":"a","url":"http://hook.local","events":[]}`, "at least one event type is required"},
+		{"unknown event", `{"name":"a","url":"http://hook.local","events":["Nope"]}`, "unknown event type: Nope"},
+		{"max attempts", `{"name":"a","url":"http://hook.local","events":["ScanStarted"],"max_attempts":99}`, "max_attempts must be between 1 and 20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doOutgoingWebhookRequest(t, router, apiKey, "POST", "/api/webhooks/outgoing", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
+
+func TestOutgoingWebhooks_Lifecycle(t *testing.T) {
+	router, db, apiKey, cleanup := setupOutgoingWebhooksTestServer(t, true)
+	defer cleanup()
+
+	// Create without a secret: one is generated and returned
+	w := doOutgoingWebhookRequest(t, router, apiKey, "POST", "/api/webhooks/outgoing",
+		`{"name":"HA","url":"http://hook.local/in","events":["CorruptionDetected","VerificationSuccess"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		ID     int64  `json:"id"`
+		Secret string `json:"secret"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Len(t, created.Secret, 64)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var got notifier.OutgoingWebhook
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.True(t, got.Enabled)
+	assert.Equal(t, notifier.DefaultWebhookMaxAttempts, got.MaxAttempts)
+	assert.Equal(t, redact.Mask, got.Secret, "the secret is only shown on create")
+
+	storedSecret := func() string {
+		var encrypted string
+		require.NoError(t, db.QueryRow(`SELECT secret FROM outgoing_webhooks WHERE id = 1`).Scan(&encrypted))
+		secret, err := crypto.Decrypt(encrypted)
+		require.NoError(t, err)
+		return secret
+	}
+
+	// Update without a secret, or with the masked one, keeps the existing one
+	for _, secret := range []string{"", redact.Mask} {
+		w = doOutgoingWebhookRequest(t, router, apiKey, "PUT", "/api/webhooks/outgoing/1",
+			`{"name":"HA2","url":"http://hook.local/in","secret":"`+secret+`","events":["ScanCompleted"],"enabled":false,"max_attempts":3}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, created.Secret, storedSecret())
+	}
+	w = doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing/1", "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "HA2", got.Name)
+	assert.False(t, got.Enabled)
+
+	// Delivery status, all and per webhook
+	_, err := db.Exec(`INSERT INTO outgoing_webhook_deliveries (webhook_id, event_type, payload, status, attempts, last_error)
+		VALUES (1, 'ScanCompleted', '{"event_type":"ScanCompleted"}', 'failed', 3, 'connection refused')`)
+	require.NoError(t, err)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing/deliveries?status=failed", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var deliveries []notifier.WebhookDelivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deliveries))
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "connection refused", deliveries[0].LastError)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing/1/deliveries?status=pending", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deliveries))
+	assert.Empty(t, deliveries)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "GET", "/api/webhooks/outgoing/deliveries?status=bogus", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "POST", "/api/webhooks/outgoing/deliveries/1/retry", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var status string
+	require.NoError(t, db.QueryRow(`SELECT status FROM outgoing_webhook_deliveries WHERE id = 1`).Scan(&status))
+	assert.Equal(t, notifier.DeliveryPending, status)
+
+	w = doOutgoingWebhookRequest(t, router, apiKey, "POST", "/api/webhooks/outgoing/deliveries/99/retry", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Delete
+	w = doOutgoingWebhookRequest(t, router, apiKey, "DELETE", "/api/webhooks/outgoing/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doOutgoingWebhookRequest(t, router, apiKey, "DELETE", "/api/webhooks/outgoing/1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = doOutgoingWebhookRequest(t, router, apiKey, "PUT", "/api/webhooks/outgoing/1",
+		`{"name":"x","url":"http://hook.local","events":["ScanStarted"]}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// pathGroupStats are the corruption counts of a path group's scan paths.
+type pathGroupStats struct {
+	TotalCorruptions              int `json:"total_corruptions"`
+	PendingCorruptions            int `json:"pending_corruptions"`
+	InProgressCorruptions         int `json:"in_progress_corruptions"`
+	ResolvedCorruptions           int `json:"resolved_corruptions"`
+	OrphanedCorruptions           int `json:"orphaned_corruptions"`
+	FailedCorruptions             int `json:"failed_corruptions"`
+	ManualInterventionCorruptions int `json:"manual_intervention_corruptions"`
+	IgnoredCorruptions            int `json:"ignored_corruptions"`
+	SuccessRate                   int `json:"success_rate"`
+}
+
+func newPathGroupStats(cc corruptionCounts) pathGroupStats {
+	return pathGroupStats{
+		TotalCorruptions:              cc.total(),
+		PendingCorruptions:            cc.pending,
+		InProgressCorruptions:         cc.inProgress,
+		ResolvedCorruptions:           cc.resolved,
+		OrphanedCorruptions:           cc.orphaned,
+		FailedCorruptions:             cc.failed,
+		ManualInterventionCorruptions: cc.manualIntervention,
+		IgnoredCorruptions:            cc.ignored,
+		SuccessRate:                   cc.successRate(),
+	}
+}
+
+// pathGroupResponse is a path group with the corruption counts of its paths.
+type pathGroupResponse struct {
+	services.PathGroup
+	Stats pathGroupStats `json:"stats"`
+}
+
+// loadPathGroupStats returns the corruption counts of each path group.
+func (s *RESTServer) loadPathGroupStats(c *gin.Context) (map[int64]pathGroupStats, error) {
+	rows, err := s.reader().QueryContext(c.Request.Context(), `
+		SELECT p.group_id, `+corruptionCountColumns+`
+		FROM corruption_summary cs
+		JOIN scan_paths p ON p.id = cs.path_id
+		WHERE p.group_id IS NOT NULL
+		GROUP BY p.group_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[int64]pathGroupStats)
+	for rows.Next() {
+		var groupID int64
+		var cc corruptionCounts
+		if err := scanCorruptionCounts(rows, &cc, &groupID); err != nil {
+			return nil, err
+		}
+		stats[groupID] = newPathGroupStats(cc)
+	}
+	return stats, rows.Err()
+}
+
+// getPathGroups lists the scan path groups with their paths and stats.
+// GET /api/config/path-groups
+func (s *RESTServer) getPathGroups(c *gin.Context) {
+	groups, err := s.pathGroups.List()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	stats, err := s.loadPathGroupStats(c)
+	if err != nil {
+		// The groups are still useful without their stats
+		logger.Warnf("Failed to load path group stats: %v", err)
+	}
+	result := make([]pathGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, pathGroupResponse{PathGroup: group, Stats: stats[group.ID]})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// respondPathGroupError maps path group errors to responses.
+func respondPathGroupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPathGroupNotFound):
+		respondNotFound(c, "Path group")
+	case errors.Is(err, services.ErrPathGroupExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPathGroup), errors.Is(err, services.ErrPathGroupUnknownPath):
+		respondBadRequest(c, err, true)
+	default:
+		respondDatabaseError(c, err)
+	}
+}
+
+// respondPathGroup responds with a path group and its stats.
+func (s *RESTServer) respondPathGroup(c *gin.Context, id int64, status int) {
+	group, err := s.pathGroups.Get(id)
+	if err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	stats, err := s.loadPathGroupStats(c)
+	if err != nil {
+		logger.Warnf("Failed to load path group stats: %v", err)
+	}
+	c.JSON(status, pathGroupResponse{PathGroup: *group, Stats: stats[id]})
+}
+
+// pathGroupID parses the :id of a path group route, responding with 400 if invalid.
+func pathGroupID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return 0, false
+	}
+	return id, true
+}
+
+// createPathGroup creates a path group, moving the given scan paths into it.
+// POST /api/config/path-groups
+func (s *RESTServer) createPathGroup(c *gin.Context) {
+	var group services.PathGroup
+	if !bindBody(c, &group) {
+		return
+	}
+	id, err := s.pathGroups.Create(&group)
+	if err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	s.respondPathGroup(c, id, http.StatusCreated)
+}
+
+// updatePathGroup replaces the name, pause and scan paths of a path group.
+// PUT /api/config/path-groups/:id
+func (s *RESTServer) updatePathGroup(c *gin.Context) {
+	id, ok := pathGroupID(c)
+	if !ok {
+		return
+	}
+	var group services.PathGroup
+	if !bindBody(c, &group) {
+		return
+	}
+	if err := s.pathGroups.Update(id, &group); err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	s.respondPathGroup(c, id, http.StatusOK)
+}
+
+// deletePathGroup removes a path group; its scan paths stay, ungrouped.
+// DELETE /api/config/path-groups/:id
+func (s *RESTServer) deletePathGroup(c *gin.Context) {
+	id, ok := pathGroupID(c)
+	if !ok {
+		return
+	}
+	if err := s.pathGroups.Delete(id); err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Path group deleted"})
+}
+
+// pausePathGroup holds the remediation of corruptions in the group's scan
+// paths until resumed. Scans keep running.
+// POST /api/config/path-groups/:id/pause
+func (s *RESTServer) pausePathGroup(c *gin.Context) {
+	s.setPathGroupPaused(c, true)
+}
+
+// resumePathGroup lets held remediations of the group's scan paths continue.
+// POST /api/config/path-groups/:id/resume
+func (s *RESTServer) resumePathGroup(c *gin.Context) {
+	s.setPathGroupPaused(c, false)
+}
+
+func (s *RESTServer) setPathGroupPaused(c *gin.Context, paused bool) {
+	id, ok := pathGroupID(c)
+	if !ok {
+		return
+	}
+	if err := s.pathGroups.SetRemediationPaused(id, paused); err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	if paused {
+		logger.Infof("Remediation of path group %d paused", id)
+	} else {
+		logger.Infof("Remediation of path group %d resumed", id)
+	}
+	s.respondPathGroup(c, id, http.StatusOK)
+}
+
+// scanPathGroup scans all enabled scan paths of a group.
+// POST /api/config/path-groups/:id/scan
+func (s *RESTServer) scanPathGroup(c *gin.Context) {
+	id, ok := pathGroupID(c)
+	if !ok {
+		return
+	}
+	if _, err := s.pathGroups.Get(id); err != nil {
+		respondPathGroupError(c, err)
+		return
+	}
+	s.startPathScans(c, "group_id = ?", id)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func setupPathGroupsTestServer(t *testing.T) (*RESTServer, *gin.Engine) {
+	t.Helper()
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, enabled) VALUES
+			(1, '/media/movies', '/movies', 1),
+			(2, '/media/kids', '/kids', 1),
+			(3, '/media/kids-old', '/kids-old', 0)
+	`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('a', '/media/kids/a.mkv', 2, 'VerificationSuccess', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+			('b', '/media/kids/b.mkv', 2, 'CorruptionDetected', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+			('c', '/media/movies/c.mkv', 1, 'CorruptionDetected', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, pathGroups: services.NewPathGroupService(db), scanner: newScansMockScanner()}
+	r.GET("/api/config/path-groups", s.getPathGroups)
+	r.POST("/api/config/path-groups", s.createPathGroup)
+	r.PUT("/api/config/path-groups/:id", s.updatePathGroup)
+	r.DELETE("/api/config/path-groups/:id", s.deletePathGroup)
+	r.POST("/api/config/path-groups/:id/pause", s.pausePathGroup)
+	r.POST("/api/config/path-groups/:id/resume", s.resumePathGroup)
+	r.POST("/api/config/path-groups/:id/scan", s.scanPathGroup)
+	return s, r
+}
+
+func decodePathGroup(t *testing.T, body []byte) pathGroupResponse {
+	t.Helper()
+	var resp pathGroupResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	return resp
+}
+
+func TestPathGroups_CRUDAndStats(t *testing.T) {
+	_, r := setupPathGroupsTestServer(t)
+
+	w := doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups", map[string]interface{}{
+		"name": "Kids", "path_ids": []int64{2, 3},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	group := decodePathGroup(t, w.Body.Bytes())
+	assert.Equal(t, "Kids", group.Name)
+	assert.Equal(t, []int64{2, 3}, group.PathIDs)
+	assert.Equal(t, 2, group.Stats.TotalCorruptions)
+	assert.Equal(t, 1, group.Stats.ResolvedCorruptions)
+	assert.Equal(t, 1, group.Stats.PendingCorruptions)
+
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups", map[string]interface{}{"name": "kids"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups", map[string]interface{}{"name": ""})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doSetupWizardRequest(t, r, http.MethodPut, "/api/config/path-groups/1", map[string]interface{}{
+		"name": "Children", "path_ids": []int64{2},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []int64{2}, decodePathGroup(t, w.Body.Bytes()).PathIDs)
+
+	w = doSetupWizardRequest(t, r, http.MethodGet, "/api/config/path-groups", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var groups []pathGroupResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Children", groups[0].Name)
+
+	w = doSetupWizardRequest(t, r, http.MethodPut, "/api/config/path-groups/abc", map[string]interface{}{"name": "x"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doSetupWizardRequest(t, r, http.MethodDelete, "/api/config/path-groups/1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doSetupWizardRequest(t, r, http.MethodDelete, "/api/config/path-groups/1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPathGroups_PauseResumeAndScan(t *testing.T) {
+	_, r := setupPathGroupsTestServer(t)
+	w := doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups", map[string]interface{}{
+		"name": "Kids", "path_ids": []int64{2, 3},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups/1/pause", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, decodePathGroup(t, w.Body.Bytes()).RemediationPaused)
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups/1/resume", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, decodePathGroup(t, w.Body.Bytes()).RemediationPaused)
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups/9/pause", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Only the enabled path of the group is scanned
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups/1/scan", nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(1), resp["started"])
+
+	w = doSetupWizardRequest(t, r, http.MethodPost, "/api/config/path-groups/9/scan", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
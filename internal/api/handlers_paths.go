@@ -1,19 +1,28 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/remote"
+	"github.com/mescon/Healarr/internal/services"
 )
 
 const errMsgReloadPathMappings = "Failed to reload path mappings: %v"
@@ -53,9 +62,12 @@ func sanitizeBrowsePath(requestedPath string) (string, error) {
 
 // scanPathRequest is the common request structure for creating and updating scan paths.
 type scanPathRequest struct {
-	LocalPath                string   `json:"local_path"`
-	ArrPath                  string   `json:"arr_path"`
-	ArrInstanceID            *int     `json:"arr_instance_id"`
+	LocalPath     string `json:"local_path"`
+	ArrPath       string `json:"arr_path"`
+	ArrInstanceID *int   `json:"arr_instance_id"`
+	// ArrInstanceTag binds the path to whichever enabled instance carries this
+	// *arr tag, overriding arr_instance_id. Empty binds by ID only.
+	ArrInstanceTag           string   `json:"arr_instance_tag"`
 	Enabled                  bool     `json:"enabled"`
 	AutoRemediate            bool     `json:"auto_remediate"`
 	DetectionMethod          string   `json:"detection_method"`
@@ -63,12 +75,132 @@ type scanPathRequest struct {
 	DetectionMode            string   `json:"detection_mode"`
 	MaxRetries               int      `json:"max_retries"`
 	VerificationTimeoutHours *int     `json:"verification_timeout_hours"`
+	// DetectionFallbacks is the preferred order of detectors tried when the
+	// primary fails. Omitted/null keeps the built-in chain; [] disables fallbacks.
+	DetectionFallbacks *[]string `json:"detection_fallbacks"`
+	// MinFileAgeMinutes skips files modified more recently than this during
+	// path scans. Omitted/null uses the default; 0 disables the check.
+	MinFileAgeMinutes *int `json:"min_file_age_minutes"`
+	// SizeStabilitySeconds is the delay between the two size checks used to
+	// detect files still being copied. 0 uses the quick built-in check.
+	SizeStabilitySeconds int `json:"size_stability_seconds"`
+	// QualityPin requires replacements to match the original's resolution:
+	// off (default), flag or search.
+	QualityPin string `json:"quality_pin"`
+	// Priority orders scheduled scans that fire together: higher first.
+	Priority int `json:"priority"`
+	// ResearchIntervalDays searches again every so many days after no
+	// replacement was found; 0 turns it off. ResearchPeriodDays is how long
+	// to keep searching (default 90).
+	ResearchIntervalDays int `json:"research_interval_days"`
+	ResearchPeriodDays   int `json:"research_period_days"`
+	// ProtocolPreference limits replacements to one download protocol:
+	// any (default), usenet or torrent.
+	ProtocolPreference string `json:"protocol_preference"`
+	// RemoteURL scans the path over WebDAV (http/https) or SFTP (sftp)
+	// instead of the local filesystem; empty for local paths. local_path is
+	// then the path the *arr knows the files by.
+	RemoteURL      string `json:"remote_url"`
+	RemoteUsername string `json:"remote_username"`
+	RemotePassword string `json:"remote_password"`
+	// RemoteHostKeyFingerprint is the SHA256 fingerprint of the SFTP server's host key.
+	RemoteHostKeyFingerprint string `json:"remote_host_key_fingerprint"`
+	// RemoteRequestsPerMinute caps requests to the server (default 60).
+	RemoteRequestsPerMinute int `json:"remote_requests_per_minute"`
+	// MediaExtensions replaces the built-in media extensions scanned in the
+	// path, e.g. ".mkv,.mp4"; empty keeps them. MinFileSizeMB flags files
+	// that decode but are smaller as Undersized; 0 turns the check off.
+	MediaExtensions string `json:"media_extensions"`
+	MinFileSizeMB   int    `json:"min_file_size_mb"`
+	// DurationCheck compares a file's duration with the runtime the *arr
+	// expects: off (default), flag or remediate.
+	DurationCheck string `json:"duration_check"`
+	// SymlinkPolicy decides how scans treat symbolic links: skip (default),
+	// follow or verify_target_once.
+	SymlinkPolicy string `json:"symlink_policy"`
+	// RolloutDailyLimit caps the remediations started per day during the
+	// first RolloutDays days after the path was added; 0 turns it off.
+	RolloutDailyLimit int `json:"rollout_daily_limit"`
+	RolloutDays       int `json:"rollout_days"`
+	// MinConfidence is the detection confidence (0-100) a corruption needs to
+	// be remediated automatically; findings below it wait for the user. 0
+	// remediates every finding.
+	MinConfidence int `json:"min_confidence"`
+
+	// detectionFallbacksJSON is the validated value stored in the database.
+	detectionFallbacksJSON sql.NullString
+	// remotePasswordEncrypted is the remote password stored in the database.
+	remotePasswordEncrypted string
 }
 
-// prepareScanPathRequest validates and normalizes a scan path request.
-// It applies defaults and marshals detection_args to JSON.
-// Returns the JSON bytes for detection_args and any validation error.
+// File age filter limits for scan paths.
+const (
+	defaultMinFileAgeMinutes = 2
+	maxMinFileAgeMinutes     = 1440 // 1 day
+	maxSizeStabilitySeconds  = 300
+)
+
+// maxScanPathPriority is the highest scan path priority.
+const maxScanPathPriority = 100
+
+// maxMinFileSizeMB is the highest minimum file size of a scan path (1 TB).
+const maxMinFileSizeMB = 1 << 20
+
+// Bounds of the re-search schedule after SearchExhausted.
+const (
+	maxResearchIntervalDays   = 365
+	maxResearchPeriodDays     = 3650
+	defaultResearchPeriodDays = 90
+)
+
+// validDetectionMethods lists the detection methods accepted by the scan path API.
+var validDetectionMethods = map[string]bool{
+	string(integration.DetectionFFprobe):   true,
+	string(integration.DetectionMediaInfo): true,
+	string(integration.DetectionHandBrake): true,
+	string(integration.DetectionZeroByte):  true,
+}
+
+// buildDetectionFallbacks validates a requested fallback order and returns the
+// value to store. Each entry must be a known method, distinct, and different
+// from the primary method.
+func buildDetectionFallbacks(primary string, fallbacks *[]string) (sql.NullString, error) {
+	if fallbacks == nil {
+		return sql.NullString{}, nil
+	}
+	seen := map[string]bool{primary: true}
+	for _, m := range *fallbacks {
+		if !validDetectionMethods[m] {
+			return sql.NullString{}, fmt.Errorf("detection_fallbacks: unknown detection method %q", m)
+		}
+		if seen[m] {
+			return sql.NullString{}, fmt.Errorf("detection_fallbacks: %q is listed twice or is the primary method", m)
+		}
+		seen[m] = true
+	}
+	data, err := json.Marshal(*fallbacks)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// prepareScanPathRequest validates and normalizes a scan path request,
+// responding with 400 if it is invalid.
+// Returns the JSON bytes for detection_args and whether the request is valid.
 func prepareScanPathRequest(req *scanPathRequest, c *gin.Context) ([]byte, bool) {
+	detectionArgsJSON, err := normalizeScanPathRequest(req)
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return nil, false
+	}
+	return detectionArgsJSON, true
+}
+
+// normalizeScanPathRequest applies defaults to a scan path request and
+// marshals detection_args to JSON. The error is a validation message safe to
+// show users.
+func normalizeScanPathRequest(req *scanPathRequest) ([]byte, error) {
 	// Apply defaults
 	if req.DetectionMethod == "" {
 		req.DetectionMethod = "ffprobe"
@@ -76,6 +208,12 @@ func prepareScanPathRequest(req *scanPathRequest, c *gin.Context) ([]byte, bool)
 	if req.DetectionMode == "" {
 		req.DetectionMode = "quick"
 	}
+	switch req.DetectionMode {
+	case integration.ModeQuick, integration.ModeThorough, integration.ModeSampled:
+		// Valid
+	default:
+		return nil, errors.New("detection_mode must be one of: quick, thorough, sampled")
+	}
 	if req.MaxRetries <= 0 || req.MaxRetries > 100 {
 		req.MaxRetries = config.Get().DefaultMaxRetries
 	}
@@ -87,11 +225,66 @@ func prepareScanPathRequest(req *scanPathRequest, c *gin.Context) ([]byte, bool)
 	if req.VerificationTimeoutHours != nil {
 		hours := *req.VerificationTimeoutHours
 		if hours < 1 || hours > 8760 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "verification_timeout_hours must be between 1 and 8760"})
-			return nil, false
+			return nil, errors.New("verification_timeout_hours must be between 1 and 8760")
 		}
 	}
 
+	// Validate file age filter
+	if req.MinFileAgeMinutes == nil {
+		minutes := defaultMinFileAgeMinutes
+		req.MinFileAgeMinutes = &minutes
+	} else if *req.MinFileAgeMinutes < 0 || *req.MinFileAgeMinutes > maxMinFileAgeMinutes {
+		return nil, fmt.Errorf("min_file_age_minutes must be between 0 and %d", maxMinFileAgeMinutes)
+	}
+	if req.SizeStabilitySeconds < 0 || req.SizeStabilitySeconds > maxSizeStabilitySeconds {
+		return nil, fmt.Errorf("size_stability_seconds must be between 0 and %d", maxSizeStabilitySeconds)
+	}
+
+	if req.QualityPin == "" {
+		req.QualityPin = services.QualityPinOff
+	} else if !services.ValidQualityPin(req.QualityPin) {
+		return nil, services.ErrInvalidQualityPin
+	}
+	if req.Priority < 0 || req.Priority > maxScanPathPriority {
+		return nil, fmt.Errorf("priority must be between 0 and %d", maxScanPathPriority)
+	}
+	if err := normalizeResearchSchedule(req); err != nil {
+		return nil, err
+	}
+	if req.ProtocolPreference == "" {
+		req.ProtocolPreference = services.ProtocolAny
+	} else if !services.ValidProtocolPreference(req.ProtocolPreference) {
+		return nil, services.ErrInvalidProtocolPreference
+	}
+	if req.DurationCheck == "" {
+		req.DurationCheck = services.DurationCheckOff
+	} else if !services.ValidDurationCheck(req.DurationCheck) {
+		return nil, services.ErrInvalidDurationCheck
+	}
+	if req.SymlinkPolicy == "" {
+		req.SymlinkPolicy = services.SymlinkSkip
+	} else if !services.ValidSymlinkPolicy(req.SymlinkPolicy) {
+		return nil, services.ErrInvalidSymlinkPolicy
+	}
+	if err := services.ValidateRollout(req.RolloutDailyLimit, req.RolloutDays); err != nil {
+		return nil, err
+	}
+	if err := services.ValidateMinConfidence(req.MinConfidence); err != nil {
+		return nil, err
+	}
+	if err := normalizeRemoteScanPath(req); err != nil {
+		return nil, err
+	}
+	if err := normalizeMediaFilter(req); err != nil {
+		return nil, err
+	}
+
+	fallbacks, err := buildDetectionFallbacks(req.DetectionMethod, req.DetectionFallbacks)
+	if err != nil {
+		return nil, err
+	}
+	req.detectionFallbacksJSON = fallbacks
+
 	// Marshal detection args to JSON
 	var detectionArgsJSON []byte
 	if len(req.DetectionArgs) > 0 {
@@ -103,11 +296,147 @@ func prepareScanPathRequest(req *scanPathRequest, c *gin.Context) ([]byte, bool)
 		}
 	}
 
-	return detectionArgsJSON, true
+	return detectionArgsJSON, nil
+}
+
+// normalizeResearchSchedule validates the re-search schedule of a scan path,
+// defaulting the period to 90 days.
+func normalizeResearchSchedule(req *scanPathRequest) error {
+	if req.ResearchIntervalDays < 0 || req.ResearchIntervalDays > maxResearchIntervalDays {
+		return fmt.Errorf("research_interval_days must be between 0 and %d", maxResearchIntervalDays)
+	}
+	if req.ResearchPeriodDays == 0 {
+		req.ResearchPeriodDays = defaultResearchPeriodDays
+	}
+	if req.ResearchPeriodDays < 0 || req.ResearchPeriodDays > maxResearchPeriodDays {
+		return fmt.Errorf("research_period_days must be between 0 and %d", maxResearchPeriodDays)
+	}
+	if req.ResearchIntervalDays > req.ResearchPeriodDays {
+		return fmt.Errorf("research_interval_days must not be longer than research_period_days")
+	}
+	return nil
+}
+
+// normalizeMediaFilter validates the media extensions and minimum file size
+// of a scan path.
+func normalizeMediaFilter(req *scanPathRequest) error {
+	extensions, err := services.NormalizeMediaExtensions(req.MediaExtensions)
+	if err != nil {
+		return fmt.Errorf("media_extensions: %w", err)
+	}
+	req.MediaExtensions = extensions
+	if req.MinFileSizeMB < 0 || req.MinFileSizeMB > maxMinFileSizeMB {
+		return fmt.Errorf("min_file_size_mb must be between 0 and %d", maxMinFileSizeMB)
+	}
+	return nil
+}
+
+// normalizeRemoteScanPath validates the remote server of a scan path and
+// encrypts its password. Local paths get the remote fields cleared.
+func normalizeRemoteScanPath(req *scanPathRequest) error {
+	req.RemoteURL = strings.TrimSpace(req.RemoteURL)
+	if req.RemoteURL == "" {
+		req.RemoteUsername, req.RemotePassword, req.RemoteHostKeyFingerprint = "", "", ""
+		req.RemoteRequestsPerMinute = remote.DefaultRequestsPerMinute
+		req.remotePasswordEncrypted = ""
+		return nil
+	}
+
+	protocol, err := remote.ProtocolOf(req.RemoteURL)
+	if err != nil {
+		return fmt.Errorf("remote_url: %w", err)
+	}
+	req.RemoteHostKeyFingerprint = strings.TrimSpace(req.RemoteHostKeyFingerprint)
+	if protocol == remote.ProtocolSFTP && req.RemoteHostKeyFingerprint == "" {
+		return errors.New("remote_host_key_fingerprint is required for sftp:// URLs (SHA256 fingerprint from ssh-keygen -lf)")
+	}
+	if req.RemoteRequestsPerMinute == 0 {
+		req.RemoteRequestsPerMinute = remote.DefaultRequestsPerMinute
+	}
+	if req.RemoteRequestsPerMinute < 1 || req.RemoteRequestsPerMinute > remote.MaxRequestsPerMinute {
+		return fmt.Errorf("remote_requests_per_minute must be between 1 and %d", remote.MaxRequestsPerMinute)
+	}
+
+	encrypted, err := crypto.Encrypt(req.RemotePassword)
+	if err != nil {
+		logger.Errorf("Failed to encrypt remote password: %v", err)
+		return errors.New("failed to encrypt remote_password")
+	}
+	req.remotePasswordEncrypted = encrypted
+	return nil
+}
+
+// keepRemotePassword replaces a masked remote password, as getScanPaths
+// returns it, with the stored one.
+func (s *RESTServer) keepRemotePassword(req *scanPathRequest, id string) {
+	if req.RemotePassword != redact.Mask {
+		return
+	}
+	var encrypted string
+	if err := s.db.QueryRow("SELECT remote_password FROM scan_paths WHERE id = ?", id).Scan(&encrypted); err != nil {
+		logger.Debugf("Failed to load remote password of scan path %s: %v", id, err)
+	}
+	req.RemotePassword = decryptRemotePassword(encrypted)
+}
+
+// decryptRemotePassword returns the stored remote password.
+func decryptRemotePassword(encrypted string) string {
+	if encrypted == "" {
+		return ""
+	}
+	password, err := crypto.Decrypt(encrypted)
+	if err != nil {
+		logger.Errorf("Failed to decrypt remote password: %v", err)
+		return ""
+	}
+	return password
+}
+
+// resolveScanPathTag points a tag-bound scan path request at the instance
+// carrying its tag, responding with an error if it can't.
+func (s *RESTServer) resolveScanPathTag(req *scanPathRequest, c *gin.Context) bool {
+	err := s.bindScanPathTag(req)
+	if errors.Is(err, services.ErrArrTagNotFound) || errors.Is(err, services.ErrArrTagAmbiguous) {
+		respondBadRequest(c, err, true)
+		return false
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return false
+	}
+	return true
+}
+
+// bindScanPathTag sets arr_instance_id of a tag-bound scan path request to the
+// instance carrying its tag. If no instance has the tag in the cached tags,
+// they are refreshed from the *arr instances once before giving up.
+func (s *RESTServer) bindScanPathTag(req *scanPathRequest) error {
+	req.ArrInstanceTag = services.NormalizeArrTag(req.ArrInstanceTag)
+	if req.ArrInstanceTag == "" {
+		return nil
+	}
+
+	instanceID, err := services.ResolveArrInstanceTag(s.db, req.ArrInstanceTag)
+	if errors.Is(err, services.ErrArrTagNotFound) && s.arrClient != nil {
+		if _, syncErr := services.SyncArrTags(s.db, s.arrClient); syncErr != nil {
+			logger.Warnf("Failed to refresh *arr tags: %v", syncErr)
+		}
+		instanceID, err = services.ResolveArrInstanceTag(s.db, req.ArrInstanceTag)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrArrTagNotFound) || errors.Is(err, services.ErrArrTagAmbiguous) {
+			return fmt.Errorf("arr_instance_tag %q: %w", req.ArrInstanceTag, err)
+		}
+		return err
+	}
+
+	id := int(instanceID)
+	req.ArrInstanceID = &id
+	return nil
 }
 
 func (s *RESTServer) getScanPaths(c *gin.Context) {
-	rows, err := s.db.Query("SELECT id, local_path, arr_path, arr_instance_id, enabled, auto_remediate, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours FROM scan_paths")
+	rows, err := s.db.Query("SELECT id, local_path, arr_path, arr_instance_id, arr_instance_tag, enabled, auto_remediate, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours, detection_fallbacks, min_file_age_minutes, size_stability_seconds, quality_pin, priority, research_interval_days, research_period_days, protocol_preference, remote_url, remote_username, remote_password, remote_host_key_fingerprint, remote_requests_per_minute, media_extensions, min_file_size_mb, duration_check, symlink_policy, rollout_daily_limit, rollout_days, min_confidence, strftime('%Y-%m-%dT%H:%M:%SZ', created_at, '+' || rollout_days || ' days') FROM scan_paths WHERE deleted_at IS NULL")
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -117,33 +446,79 @@ func (s *RESTServer) getScanPaths(c *gin.Context) {
 	var paths []gin.H
 	for rows.Next() {
 		var id int
-		var localPath, arrPath string
+		var localPath, arrPath, arrInstanceTag, qualityPin, protocolPreference string
+		var remoteURL, remoteUsername, remotePassword, remoteHostKeyFingerprint, mediaExtensions, durationCheck, symlinkPolicy string
 		var arrInstanceID sql.NullInt64
 		var enabled, autoRemediate bool
 		var detectionMethod, detectionMode string
-		var detectionArgs sql.NullString
-		var maxRetries int
-		var verificationTimeoutHours sql.NullInt64
-		if rows.Scan(&id, &localPath, &arrPath, &arrInstanceID, &enabled, &autoRemediate, &detectionMethod, &detectionArgs, &detectionMode, &maxRetries, &verificationTimeoutHours) != nil {
+		var detectionArgs, detectionFallbacks sql.NullString
+		var maxRetries, priority, researchIntervalDays, researchPeriodDays, remoteRequestsPerMinute, minFileSizeMB int
+		var rolloutDailyLimit, rolloutDays, minConfidence int
+		var rolloutEndsAt sql.NullString
+		var verificationTimeoutHours, minFileAgeMinutes, sizeStabilitySeconds sql.NullInt64
+		if rows.Scan(&id, &localPath, &arrPath, &arrInstanceID, &arrInstanceTag, &enabled, &autoRemediate, &detectionMethod, &detectionArgs, &detectionMode, &maxRetries, &verificationTimeoutHours, &detectionFallbacks, &minFileAgeMinutes, &sizeStabilitySeconds, &qualityPin, &priority, &researchIntervalDays, &researchPeriodDays, &protocolPreference,
+			&remoteURL, &remoteUsername, &remotePassword, &remoteHostKeyFingerprint, &remoteRequestsPerMinute, &mediaExtensions, &minFileSizeMB, &durationCheck, &symlinkPolicy, &rolloutDailyLimit, &rolloutDays, &minConfidence, &rolloutEndsAt) != nil {
 			continue
 		}
 		path := gin.H{
-			"id":               id,
-			"local_path":       localPath,
-			"arr_path":         arrPath,
-			"arr_instance_id":  arrInstanceID.Int64,
-			"enabled":          enabled,
-			"auto_remediate":   autoRemediate,
-			"detection_method": detectionMethod,
-			"detection_args":   detectionArgs.String,
-			"detection_mode":   detectionMode,
-			"max_retries":      maxRetries,
+			"id":                          id,
+			"local_path":                  localPath,
+			"arr_path":                    arrPath,
+			"arr_instance_id":             arrInstanceID.Int64,
+			"arr_instance_tag":            arrInstanceTag,
+			"enabled":                     enabled,
+			"auto_remediate":              autoRemediate,
+			"detection_method":            detectionMethod,
+			"detection_args":              detectionArgs.String,
+			"detection_mode":              detectionMode,
+			"max_retries":                 maxRetries,
+			"quality_pin":                 qualityPin,
+			"priority":                    priority,
+			"research_interval_days":      researchIntervalDays,
+			"research_period_days":        researchPeriodDays,
+			"protocol_preference":         protocolPreference,
+			"remote_url":                  remoteURL,
+			"remote_username":             remoteUsername,
+			"remote_password":             redact.Value(decryptRemotePassword(remotePassword)),
+			"remote_host_key_fingerprint": remoteHostKeyFingerprint,
+			"remote_requests_per_minute":  remoteRequestsPerMinute,
+			"media_extensions":            mediaExtensions,
+			"min_file_size_mb":            minFileSizeMB,
+			"duration_check":              durationCheck,
+			"symlink_policy":              symlinkPolicy,
+			"rollout_daily_limit":         rolloutDailyLimit,
+			"rollout_days":                rolloutDays,
+			"min_confidence":              minConfidence,
+		}
+		if rolloutDailyLimit > 0 && rolloutDays > 0 && rolloutEndsAt.Valid {
+			path["rollout_ends_at"] = rolloutEndsAt.String
 		}
+		if minFileAgeMinutes.Valid {
+			path["min_file_age_minutes"] = minFileAgeMinutes.Int64
+		} else {
+			path["min_file_age_minutes"] = defaultMinFileAgeMinutes
+		}
+		path["size_stability_seconds"] = sizeStabilitySeconds.Int64
 		if verificationTimeoutHours.Valid {
 			path["verification_timeout_hours"] = verificationTimeoutHours.Int64
 		} else {
 			path["verification_timeout_hours"] = nil
 		}
+		path["detection_fallbacks"] = nil
+		var fallbacks []string
+		if detectionFallbacks.Valid && json.Unmarshal([]byte(detectionFallbacks.String), &fallbacks) == nil {
+			if fallbacks == nil {
+				fallbacks = []string{}
+			}
+			path["detection_fallbacks"] = fallbacks
+		}
+		if protocol, err := remote.ProtocolOf(remoteURL); err == nil {
+			// Remote paths don't run detection tools
+			path["capability"] = integration.ScanCapability{Scannable: true, MissingTools: []string{}}
+			path["remote_capabilities"] = remote.CapabilitiesFor(protocol)
+		} else {
+			path["capability"] = s.toolChecker.CapabilityFor(scanPathDetectionConfig(detectionMethod, detectionMode, detectionFallbacks))
+		}
 		paths = append(paths, path)
 	}
 	if rows.Err() != nil {
@@ -195,6 +570,17 @@ func (s *RESTServer) getDetectionPreview(c *gin.Context) {
 	// Mode descriptions
 	var modeDescription string
 	switch mode {
+	case "sampled":
+		switch method {
+		case "ffprobe":
+			modeDescription = "Decodes short segments at the start, middle, end and random points of the file. Much faster than a full decode on very large files while still catching most mid-file corruption."
+		case "mediainfo":
+			modeDescription = "Sampling only applies to ffprobe. Basic metadata extraction to verify container structure."
+		case "handbrake":
+			modeDescription = "Sampling only applies to ffprobe. Basic container scan to detect audio/video tracks."
+		case "zero_byte":
+			modeDescription = "Simple file size check - only detects completely empty files."
+		}
 	case "thorough":
 		switch method {
 		case "ffprobe":
@@ -230,25 +616,21 @@ func (s *RESTServer) getDetectionPreview(c *gin.Context) {
 
 func (s *RESTServer) createScanPath(c *gin.Context) {
 	var req scanPathRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
 	detectionArgsJSON, ok := prepareScanPathRequest(&req, c)
-	if !ok {
+	if !ok || !s.resolveScanPathTag(&req, c) {
 		return
 	}
-
-	_, err := s.db.Exec(`INSERT INTO scan_paths
-		(local_path, arr_path, arr_instance_id, enabled, auto_remediate, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		req.LocalPath, req.ArrPath, req.ArrInstanceID, req.Enabled, req.AutoRemediate,
-		req.DetectionMethod, detectionArgsJSON, req.DetectionMode, req.MaxRetries, req.VerificationTimeoutHours)
+	id, err := s.insertScanPath(&req, detectionArgsJSON)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
+	s.remotePaths.Invalidate()
+	s.publishScanPathsChanged("created", id)
 	if err := s.pathMapper.Reload(); err != nil {
 		logger.Errorf(errMsgReloadPathMappings, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan path created but path mapping update failed"})
@@ -257,13 +639,24 @@ func (s *RESTServer) createScanPath(c *gin.Context) {
 	c.Status(http.StatusCreated)
 }
 
+// deleteScanPath soft-deletes a scan path; it can be restored until it is purged.
 func (s *RESTServer) deleteScanPath(c *gin.Context) {
-	id := c.Param("id")
-	_, err := s.db.Exec("DELETE FROM scan_paths WHERE id = ?", id)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+	if err := s.deletedConfig().DeleteScanPath(id); err != nil {
+		if errors.Is(err, services.ErrScanPathNotFound) {
+			respondNotFound(c, "Scan path")
+			return
+		}
 		respondDatabaseError(c, err)
 		return
 	}
+	s.reloadSchedules()
+	s.remotePaths.Invalidate()
+	s.publishScanPathsChanged("deleted", id)
 	if err := s.pathMapper.Reload(); err != nil {
 		logger.Errorf(errMsgReloadPathMappings, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan path deleted but path mapping update failed"})
@@ -363,28 +756,46 @@ func (s *RESTServer) browseDirectory(c *gin.Context) {
 func (s *RESTServer) updateScanPath(c *gin.Context) {
 	id := c.Param("id")
 	var req scanPathRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
+	s.keepRemotePassword(&req, id)
 
 	detectionArgsJSON, ok := prepareScanPathRequest(&req, c)
-	if !ok {
+	if !ok || !s.resolveScanPathTag(&req, c) {
 		return
 	}
 
 	_, err := s.db.Exec(`UPDATE scan_paths SET
-		local_path = ?, arr_path = ?, arr_instance_id = ?, enabled = ?,
+		local_path = ?, arr_path = ?, arr_instance_id = ?, arr_instance_tag = ?, enabled = ?,
 		auto_remediate = ?, detection_method = ?, detection_args = ?,
-		detection_mode = ?, max_retries = ?, verification_timeout_hours = ?
+		detection_mode = ?, max_retries = ?, verification_timeout_hours = ?,
+		detection_fallbacks = ?, min_file_age_minutes = ?, size_stability_seconds = ?, quality_pin = ?, priority = ?,
+		research_interval_days = ?, research_period_days = ?, protocol_preference = ?,
+		remote_url = ?, remote_username = ?, remote_password = ?, remote_host_key_fingerprint = ?, remote_requests_per_minute = ?,
+		media_extensions = ?, min_file_size_mb = ?, duration_check = ?, rollout_daily_limit = ?, rollout_days = ?,
+		min_confidence = ?, symlink_policy = ?
 		WHERE id = ?`,
-		req.LocalPath, req.ArrPath, req.ArrInstanceID, req.Enabled,
+		req.LocalPath, req.ArrPath, req.ArrInstanceID, req.ArrInstanceTag, req.Enabled,
 		req.AutoRemediate, req.DetectionMethod, detectionArgsJSON,
-		req.DetectionMode, req.MaxRetries, req.VerificationTimeoutHours, id)
+		req.DetectionMode, req.MaxRetries, req.VerificationTimeoutHours,
+		req.detectionFallbacksJSON, *req.MinFileAgeMinutes, req.SizeStabilitySeconds, req.QualityPin, req.Priority,
+		req.ResearchIntervalDays, req.ResearchPeriodDays, req.ProtocolPreference,
+		req.RemoteURL, req.RemoteUsername, req.remotePasswordEncrypted, req.RemoteHostKeyFingerprint, req.RemoteRequestsPerMinute,
+		req.MediaExtensions, req.MinFileSizeMB, req.DurationCheck, req.RolloutDailyLimit, req.RolloutDays,
+		req.MinConfidence, req.SymlinkPolicy, id)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
+	s.remotePaths.Invalidate()
+	if pathID, err := strconv.ParseInt(id, 10, 64); err == nil {
+		s.publishScanPathsChanged("updated", pathID)
+	}
+	if s.remediator != nil {
+		// A raised or removed rollout limit lets deferred remediations go ahead
+		s.remediator.RecheckRollout()
+	}
 	if err := s.pathMapper.Reload(); err != nil {
 		logger.Errorf(errMsgReloadPathMappings, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan path updated but path mapping update failed"})
@@ -474,7 +885,7 @@ func (s *RESTServer) validateScanPath(c *gin.Context) {
 
 	// Get the path from database
 	var localPath string
-	err := s.db.QueryRow("SELECT local_path FROM scan_paths WHERE id = ?", id).Scan(&localPath)
+	err := s.db.QueryRow("SELECT local_path FROM scan_paths WHERE id = ? AND deleted_at IS NULL", id).Scan(&localPath)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Scan path not found"})
 		return
@@ -484,6 +895,17 @@ func (s *RESTServer) validateScanPath(c *gin.Context) {
 		return
 	}
 
+	pathID, _ := strconv.ParseInt(id, 10, 64)
+	remotePath, isRemote, err := remote.LoadPath(c.Request.Context(), s.db, pathID)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if isRemote {
+		c.JSON(http.StatusOK, validateRemotePath(c.Request.Context(), remotePath))
+		return
+	}
+
 	// Check if path exists and is accessible
 	info, err := os.Stat(localPath)
 	if err != nil {
@@ -524,3 +946,55 @@ func (s *RESTServer) validateScanPath(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// Limits of validating a remote scan path. Every listed directory is a
+// rate-limited request, so only the start of the library is counted.
+const (
+	maxRemoteValidationFiles = 100
+	remoteValidationTimeout  = 30 * time.Second
+)
+
+// errStopRemoteWalk ends a remote walk once enough files were counted.
+var errStopRemoteWalk = errors.New("enough files counted")
+
+// validateRemotePath connects to a remote scan path and counts the media files
+// at the start of its library.
+func validateRemotePath(ctx context.Context, p remote.Path) pathValidationResult {
+	ctx, cancel := context.WithTimeout(ctx, remoteValidationTimeout)
+	defer cancel()
+
+	result := pathValidationResult{SampleFiles: []string{}}
+	backend, err := remote.Open(ctx, p.Config)
+	if err != nil {
+		errMsg := "Remote server not accessible: " + err.Error()
+		result.Error = &errMsg
+		return result
+	}
+	defer backend.Close()
+
+	err = remote.Walk(ctx, backend, func(e remote.Entry) error {
+		if !validationMediaExtensions[strings.ToLower(path.Ext(e.Path))] {
+			return nil
+		}
+		result.FileCount++
+		if len(result.SampleFiles) < 5 {
+			result.SampleFiles = append(result.SampleFiles, e.Path)
+		}
+		if result.FileCount >= maxRemoteValidationFiles {
+			return errStopRemoteWalk
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		result.Accessible = true
+	case errors.Is(err, errStopRemoteWalk), errors.Is(err, context.DeadlineExceeded) && result.FileCount > 0:
+		result.Accessible = true
+		truncMsg := fmt.Sprintf("Count limited to the first %d files found on the remote server", result.FileCount)
+		result.Error = &truncMsg
+	default:
+		errMsg := "Remote server not accessible: " + err.Error()
+		result.Error = &errMsg
+	}
+	return result
+}
@@ -0,0 +1,437 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// maxBulkScanPaths bounds the rows of a single bulk import.
+const maxBulkScanPaths = 1000
+
+// Outcome of a bulk import row.
+const (
+	BulkPathCreated = "created"
+	BulkPathValid   = "valid" // dry run: the row would be created
+	BulkPathFailed  = "failed"
+)
+
+// bulkScanPathRow is a row of a bulk import: a scan path request whose
+// instance may be given by name instead of ID.
+type bulkScanPathRow struct {
+	scanPathRequest
+	// Instance is the name of the *arr instance (case-insensitive)
+	Instance string `json:"instance"`
+}
+
+// bulkScanPathResult reports what happened to one row. Row is 1-based and
+// doesn't count the CSV header.
+type bulkScanPathResult struct {
+	Row       int      `json:"row"`
+	LocalPath string   `json:"local_path"`
+	Status    string   `json:"status"`
+	ID        int64    `json:"id,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// bulkScanPathResponse is the response of POST /config/paths/bulk.
+type bulkScanPathResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Results []bulkScanPathResult `json:"results"`
+}
+
+// bulkScanPathColumns sets a row field from a CSV column.
+var bulkScanPathColumns = map[string]func(row *bulkScanPathRow, value string) error{
+	"local_path":       func(row *bulkScanPathRow, v string) error { row.LocalPath = v; return nil },
+	"arr_path":         func(row *bulkScanPathRow, v string) error { row.ArrPath = v; return nil },
+	"instance":         func(row *bulkScanPathRow, v string) error { row.Instance = v; return nil },
+	"arr_instance_tag": func(row *bulkScanPathRow, v string) error { row.ArrInstanceTag = v; return nil },
+	"detection_method": func(row *bulkScanPathRow, v string) error { row.DetectionMethod = v; return nil },
+	"detection_mode":   func(row *bulkScanPathRow, v string) error { row.DetectionMode = v; return nil },
+	"enabled":          func(row *bulkScanPathRow, v string) error { return parseBulkBool(v, "enabled", &row.Enabled) },
+	"auto_remediate": func(row *bulkScanPathRow, v string) error {
+		return parseBulkBool(v, "auto_remediate", &row.AutoRemediate)
+	},
+	"max_retries": func(row *bulkScanPathRow, v string) error {
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("max_retries: %q is not a number", v)
+		}
+		row.MaxRetries = n
+		return nil
+	},
+	"priority": func(row *bulkScanPathRow, v string) error {
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("priority: %q is not a number", v)
+		}
+		row.Priority = n
+		return nil
+	},
+	"research_interval_days": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "research_interval_days", &row.ResearchIntervalDays)
+	},
+	"research_period_days": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "research_period_days", &row.ResearchPeriodDays)
+	},
+	"protocol_preference":         func(row *bulkScanPathRow, v string) error { row.ProtocolPreference = v; return nil },
+	"remote_url":                  func(row *bulkScanPathRow, v string) error { row.RemoteURL = v; return nil },
+	"remote_username":             func(row *bulkScanPathRow, v string) error { row.RemoteUsername = v; return nil },
+	"remote_password":             func(row *bulkScanPathRow, v string) error { row.RemotePassword = v; return nil },
+	"remote_host_key_fingerprint": func(row *bulkScanPathRow, v string) error { row.RemoteHostKeyFingerprint = v; return nil },
+	"remote_requests_per_minute": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "remote_requests_per_minute", &row.RemoteRequestsPerMinute)
+	},
+	"media_extensions": func(row *bulkScanPathRow, v string) error { row.MediaExtensions = v; return nil },
+	"min_file_size_mb": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "min_file_size_mb", &row.MinFileSizeMB)
+	},
+	"duration_check": func(row *bulkScanPathRow, v string) error { row.DurationCheck = v; return nil },
+	"symlink_policy": func(row *bulkScanPathRow, v string) error { row.SymlinkPolicy = v; return nil },
+	"rollout_daily_limit": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "rollout_daily_limit", &row.RolloutDailyLimit)
+	},
+	"rollout_days": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "rollout_days", &row.RolloutDays)
+	},
+	"min_confidence": func(row *bulkScanPathRow, v string) error {
+		return parseBulkInt(v, "min_confidence", &row.MinConfidence)
+	},
+}
+
+// parseBulkBool parses a CSV boolean, leaving the default for empty cells.
+func parseBulkBool(value, column string, dst *bool) error {
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not true or false", column, value)
+	}
+	*dst = b
+	return nil
+}
+
+// parseBulkInt parses a CSV number, leaving the default for empty cells.
+func parseBulkInt(value, column string, dst *int) error {
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", column, value)
+	}
+	*dst = n
+	return nil
+}
+
+// bulkCreateScanPaths creates scan paths from a JSON array or, with
+// Content-Type text/csv, a CSV with a header row. Each row is validated and
+// created on its own, so one bad row doesn't stop the rest; ?dry_run=true only
+// validates. Rows are enabled unless they say otherwise.
+func (s *RESTServer) bulkCreateScanPaths(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBadRequest(c, err, false)
+		return
+	}
+
+	var rows []bulkScanPathRow
+	var rowErrs []error
+	if c.ContentType() == "text/csv" {
+		rows, rowErrs, err = parseBulkScanPathsCSV(body)
+	} else {
+		rows, rowErrs, err = parseBulkScanPathsJSON(body)
+	}
+	if err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+	// The body is the list of rows, so these have no field to name
+	if len(rows) == 0 {
+		respondProblem(c, http.StatusBadRequest, requestLocale(c).T("No scan paths provided"))
+		return
+	}
+	if len(rows) > maxBulkScanPaths {
+		respondProblem(c, http.StatusBadRequest, requestLocale(c).Sprintf("At most %d scan paths can be imported at once", maxBulkScanPaths))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	instances, err := s.bulkInstanceNames(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	existing, err := s.configuredLocalPaths(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	resp := bulkScanPathResponse{DryRun: c.Query("dry_run") == "true", Results: make([]bulkScanPathResult, 0, len(rows))}
+	for i := range rows {
+		row := &rows[i]
+		result := bulkScanPathResult{Row: i + 1, LocalPath: row.LocalPath, Status: BulkPathFailed}
+
+		var args []byte
+		err := rowErrs[i]
+		if err == nil {
+			args, err = s.validateBulkScanPath(row, instances, existing)
+			result.LocalPath = row.LocalPath
+		}
+		if err != nil {
+			result.Error = err.Error()
+			resp.Failed++
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+		if info, statErr := os.Stat(row.LocalPath); row.RemoteURL == "" && (statErr != nil || !info.IsDir()) {
+			result.Warnings = append(result.Warnings, "local path is not an accessible directory")
+		}
+		existing[row.LocalPath] = true
+
+		if resp.DryRun {
+			result.Status = BulkPathValid
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+		id, err := s.insertScanPath(&row.scanPathRequest, args)
+		if err != nil {
+			logger.Errorf("Bulk import of scan path %s failed: %v", row.LocalPath, err)
+			result.Error = ErrMsgDatabaseError
+			resp.Failed++
+		} else {
+			result.Status = BulkPathCreated
+			result.ID = id
+			resp.Created++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if resp.Created > 0 {
+		s.remotePaths.Invalidate()
+		s.publishScanPathsChanged("imported")
+		if err := s.pathMapper.Reload(); err != nil {
+			logger.Errorf(errMsgReloadPathMappings, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan paths created but path mapping update failed"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// validateBulkScanPath checks and normalizes one row against the instances by
+// lowercased name and the local paths already configured.
+func (s *RESTServer) validateBulkScanPath(row *bulkScanPathRow, instances map[string]int, existing map[string]bool) ([]byte, error) {
+	if row.LocalPath == "" {
+		return nil, errors.New("local_path is required")
+	}
+	row.LocalPath = filepath.Clean(row.LocalPath)
+	if !filepath.IsAbs(row.LocalPath) {
+		return nil, errors.New("local_path must be absolute")
+	}
+	if existing[row.LocalPath] {
+		return nil, errors.New("local_path is already configured")
+	}
+	if row.DetectionMethod != "" && !validDetectionMethods[row.DetectionMethod] {
+		return nil, fmt.Errorf("unknown detection_method %q", row.DetectionMethod)
+	}
+
+	if row.Instance != "" {
+		id, ok := instances[strings.ToLower(strings.TrimSpace(row.Instance))]
+		if !ok {
+			return nil, fmt.Errorf("no *arr instance named %q", row.Instance)
+		}
+		row.ArrInstanceID = &id
+	} else if row.ArrInstanceID != nil && !containsInstanceID(instances, *row.ArrInstanceID) {
+		return nil, fmt.Errorf("no *arr instance with ID %d", *row.ArrInstanceID)
+	}
+
+	args, err := normalizeScanPathRequest(&row.scanPathRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.bindScanPathTag(&row.scanPathRequest); err != nil {
+		if errors.Is(err, services.ErrArrTagNotFound) || errors.Is(err, services.ErrArrTagAmbiguous) {
+			return nil, err
+		}
+		logger.Errorf("Failed to resolve *arr tag of scan path %s: %v", row.LocalPath, err)
+		return nil, errors.New(ErrMsgDatabaseError)
+	}
+	return args, nil
+}
+
+// insertScanPath stores a validated scan path, replacing a deleted path at the
+// same location rather than restoring it.
+func (s *RESTServer) insertScanPath(req *scanPathRequest, detectionArgsJSON []byte) (int64, error) {
+	if err := s.deletedConfig().PurgeDeletedScanPathAt(req.LocalPath); err != nil {
+		return 0, err
+	}
+	res, err := s.db.Exec(`INSERT INTO scan_paths
+		(local_path, arr_path, arr_instance_id, arr_instance_tag, enabled, auto_remediate, detection_method, detection_args, detection_mode, max_retries, verification_timeout_hours, detection_fallbacks,
+		 min_file_age_minutes, size_stability_seconds, quality_pin, priority, research_interval_days, research_period_days, protocol_preference,
+		 remote_url, remote_username, remote_password, remote_host_key_fingerprint, remote_requests_per_minute, media_extensions, min_file_size_mb, duration_check,
+		 rollout_daily_limit, rollout_days, min_confidence, symlink_policy)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		req.LocalPath, req.ArrPath, req.ArrInstanceID, req.ArrInstanceTag, req.Enabled, req.AutoRemediate,
+		req.DetectionMethod, detectionArgsJSON, req.DetectionMode, req.MaxRetries, req.VerificationTimeoutHours,
+		req.detectionFallbacksJSON, *req.MinFileAgeMinutes, req.SizeStabilitySeconds, req.QualityPin, req.Priority,
+		req.ResearchIntervalDays, req.ResearchPeriodDays, req.ProtocolPreference,
+		req.RemoteURL, req.RemoteUsername, req.remotePasswordEncrypted, req.RemoteHostKeyFingerprint, req.RemoteRequestsPerMinute,
+		req.MediaExtensions, req.MinFileSizeMB, req.DurationCheck, req.RolloutDailyLimit, req.RolloutDays,
+		req.MinConfidence, req.SymlinkPolicy)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// bulkInstanceNames maps the lowercased names of the *arr instances to their IDs.
+func (s *RESTServer) bulkInstanceNames(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, name FROM arr_instances WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	instances := map[string]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		instances[strings.ToLower(strings.TrimSpace(name))] = id
+	}
+	return instances, rows.Err()
+}
+
+// configuredLocalPaths returns the local paths of the scan paths in use.
+func (s *RESTServer) configuredLocalPaths(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT local_path FROM scan_paths WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paths := map[string]bool{}
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths[filepath.Clean(path)] = true
+	}
+	return paths, rows.Err()
+}
+
+func containsInstanceID(instances map[string]int, id int) bool {
+	for _, v := range instances {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBulkScanPathsJSON decodes a JSON array of rows. A row that doesn't
+// decode gets an error of its own instead of failing the import.
+func parseBulkScanPathsJSON(body []byte) ([]bulkScanPathRow, []error, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, nil, errors.New("body must be a JSON array of scan paths")
+	}
+
+	rows := make([]bulkScanPathRow, len(raw))
+	rowErrs := make([]error, len(raw))
+	for i, r := range raw {
+		rows[i].Enabled = true
+		if err := json.Unmarshal(r, &rows[i]); err != nil {
+			rowErrs[i] = fmt.Errorf("invalid row: %v", err)
+		}
+	}
+	return rows, rowErrs, nil
+}
+
+// parseBulkScanPathsCSV reads a CSV whose header names the columns, in any
+// order, from bulkScanPathColumns.
+func parseBulkScanPathsCSV(body []byte) ([]bulkScanPathRow, []error, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	setters := make([]func(*bulkScanPathRow, string) error, len(header))
+	hasLocalPath := false
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		set, ok := bulkScanPathColumns[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		setters[i] = set
+		hasLocalPath = hasLocalPath || name == "local_path"
+	}
+	if !hasLocalPath {
+		return nil, nil, errors.New("CSV needs a local_path column")
+	}
+
+	var rows []bulkScanPathRow
+	var rowErrs []error
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		row := bulkScanPathRow{scanPathRequest: scanPathRequest{Enabled: true}}
+		var rowErr error
+		if len(record) != len(header) {
+			rowErr = fmt.Errorf("expected %d columns, got %d", len(header), len(record))
+		}
+		for i := 0; i < len(record) && i < len(setters) && rowErr == nil; i++ {
+			rowErr = setters[i](&row, strings.TrimSpace(record[i]))
+		}
+		rows = append(rows, row)
+		rowErrs = append(rowErrs, rowErr)
+	}
+	return rows, rowErrs, nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCreateScanPaths(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	_, err := db.Exec("INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'key')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO scan_paths (local_path, arr_path, arr_instance_id) VALUES ('/media/existing', '/existing', 1)")
+	require.NoError(t, err)
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(t *testing.T, query, contentType, body string) (int, bulkScanPathResponse) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", "/api/config/paths/bulk"+query, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp bulkScanPathResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+	countPaths := func() int {
+		var n int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM scan_paths").Scan(&n))
+		return n
+	}
+
+	t.Run("dry run validates without creating", func(t *testing.T) {
+		code, resp := post(t, "?dry_run=true", "application/json", `[
+			{"local_path": "/media/tv", "arr_path": "/tv", "instance": "sonarr"},
+			{"local_path": "/media/existing/", "instance": "Sonarr"}
+		]`)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, resp.DryRun)
+		assert.Equal(t, 0, resp.Created)
+		assert.Equal(t, 1, resp.Failed)
+		assert.Equal(t, BulkPathValid, resp.Results[0].Status)
+		assert.Equal(t, "local_path is already configured", resp.Results[1].Error)
+		assert.Equal(t, 1, countPaths())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		code, resp := post(t, "", "application/json", `[
+			{"local_path": "/media/tv", "arr_path": "/tv", "instance": "Sonarr", "auto_remediate": true},
+			{"local_path": "/media/tv", "instance": "Sonarr"},
+			{"local_path": "/media/anime", "instance": "Radarr"},
+			{"local_path": "media/relative"},
+			{"local_path": "/media/kids", "detection_method": "magic"},
+			{"local_path": "/media/docs", "enabled": "yes"}
+		]`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 5, resp.Failed)
+		require.Len(t, resp.Results, 6)
+
+		assert.Equal(t, BulkPathCreated, resp.Results[0].Status)
+		assert.NotZero(t, resp.Results[0].ID)
+		assert.Contains(t, resp.Results[0].Warnings, "local path is not an accessible directory")
+		assert.Equal(t, "local_path is already configured", resp.Results[1].Error, "duplicates within the import")
+		assert.Equal(t, `no *arr instance named "Radarr"`, resp.Results[2].Error)
+		assert.Equal(t, "local_path must be absolute", resp.Results[3].Error)
+		assert.Equal(t, `unknown detection_method "magic"`, resp.Results[4].Error)
+		assert.Contains(t, resp.Results[5].Error, "invalid row")
+
+		var arrPath string
+		var instanceID int
+		var enabled, autoRemediate bool
+		require.NoError(t, db.QueryRow("SELECT arr_path, arr_instance_id, enabled, auto_remediate FROM scan_paths WHERE local_path = '/media/tv'").
+			Scan(&arrPath, &instanceID, &enabled, &autoRemediate))
+		assert.Equal(t, "/tv", arrPath)
+		assert.Equal(t, 1, instanceID)
+		assert.True(t, enabled, "rows are enabled by default")
+		assert.True(t, autoRemediate)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		code, resp := post(t, "", "text/csv", "local_path,arr_path,instance,enabled,detection_mode\n"+
+			"/media/movies,/movies,Sonarr,false,thorough\n"+
+			"/media/music,,Sonarr,maybe,\n"+
+			"/media/books,/books\n")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 2, resp.Failed)
+		assert.Equal(t, `enabled: "maybe" is not true or false`, resp.Results[1].Error)
+		assert.Equal(t, "expected 5 columns, got 2", resp.Results[2].Error)
+
+		var enabled bool
+		var mode string
+		require.NoError(t, db.QueryRow("SELECT enabled, detection_mode FROM scan_paths WHERE local_path = '/media/movies'").Scan(&enabled, &mode))
+		assert.False(t, enabled)
+		assert.Equal(t, "thorough", mode)
+	})
+
+	t.Run("invalid bodies", func(t *testing.T) {
+		code, _ := post(t, "", "application/json", `{"local_path": "/media/tv"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = post(t, "", "application/json", `[]`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = post(t, "", "text/csv", "path,instance\n/media/tv,Sonarr\n")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,7 @@ import (
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/redact"
 	"github.com/mescon/Healarr/internal/testutil"
 )
 
@@ -42,6 +44,9 @@ func setupPathsTestDB(t *testing.T) (*sql.DB, func()) {
 		ALTER TABLE scan_paths ADD COLUMN detection_mode TEXT DEFAULT 'quick';
 		ALTER TABLE scan_paths ADD COLUMN max_retries INTEGER DEFAULT 3;
 		ALTER TABLE scan_paths ADD COLUMN verification_timeout_hours INTEGER;
+		ALTER TABLE scan_paths ADD COLUMN detection_fallbacks TEXT;
+		ALTER TABLE scan_paths ADD COLUMN min_file_age_minutes INTEGER DEFAULT 2;
+		ALTER TABLE scan_paths ADD COLUMN size_stability_seconds INTEGER DEFAULT 0;
 	`
 	_, err := db.Exec(schema)
 	require.NoError(t, err)
@@ -86,6 +91,10 @@ func setupPathsTestServer(t *testing.T, db *sql.DB) (*gin.Engine, string, func()
 	{
 		protected.GET("/config/paths", s.getScanPaths)
 		protected.POST("/config/paths", s.createScanPath)
+		protected.POST("/config/paths/bulk", s.bulkCreateScanPaths)
+		protected.GET("/config/paths/drift", s.getMappingDrift)
+		protected.POST("/config/paths/remap", s.remapScanPaths)
+		protected.POST("/config/arr/:id/migrate", s.migrateLibrary)
 		protected.PUT("/config/paths/:id", s.updateScanPath)
 		protected.DELETE("/config/paths/:id", s.deleteScanPath)
 		protected.GET("/config/paths/:id/validate", s.validateScanPath)
@@ -371,6 +380,522 @@ func TestCreateScanPath_InvalidVerificationTimeout(t *testing.T) {
 	}
 }
 
+func TestCreateScanPath_InvalidDetectionMode(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	body := bytes.NewBufferString(`{
+		"local_path": "/media/bogus",
+		"enabled": true,
+		"detection_mode": "bogus"
+	}`)
+
+	req, _ := http.NewRequest("POST", "/api/config/paths", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "detection_mode must be one of")
+}
+
+func TestCreateScanPath_Priority(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path string, priority int) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true, "priority": %d}`,
+			path, arrID, priority))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, priority := range []int{-1, 101} {
+		w := post(fmt.Sprintf("/media/invalid-%d", priority), priority)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "priority must be between 0 and 100")
+	}
+
+	w := post("/media/movies", 80)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	req, _ := http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 1)
+	assert.Equal(t, float64(80), paths[0]["priority"])
+}
+
+func TestCreateScanPath_ResearchSchedule(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path string, interval, period int) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true, "research_interval_days": %d, "research_period_days": %d}`,
+			path, arrID, interval, period))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	invalid := []struct {
+		interval, period int
+		message          string
+	}{
+		{-1, 90, "research_interval_days must be between 0 and 365"},
+		{366, 3650, "research_interval_days must be between 0 and 365"},
+		{7, 3651, "research_period_days must be between 0 and 3650"},
+		{30, 14, "research_interval_days must not be longer than research_period_days"},
+	}
+	for i, tt := range invalid {
+		w := post(fmt.Sprintf("/media/invalid-%d", i), tt.interval, tt.period)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), tt.message)
+	}
+
+	w := post("/media/movies", 7, 0)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	req, _ := http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 1)
+	assert.Equal(t, float64(7), paths[0]["research_interval_days"])
+	assert.Equal(t, float64(90), paths[0]["research_period_days"], "the period defaults to 90 days")
+}
+
+func TestCreateScanPath_ProtocolPreference(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://localhost:8989", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, preference string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true, "protocol_preference": %q}`,
+			path, arrID, preference))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/invalid", "ftp")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "protocol_preference must be one of")
+
+	require.Equal(t, http.StatusCreated, post("/media/tv", "usenet").Code)
+	require.Equal(t, http.StatusCreated, post("/media/movies", "").Code)
+
+	req, _ := http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 2)
+	preferences := map[string]interface{}{}
+	for _, p := range paths {
+		preferences[p["local_path"].(string)] = p["protocol_preference"]
+	}
+	assert.Equal(t, "usenet", preferences["/media/tv"])
+	assert.Equal(t, "any", preferences["/media/movies"])
+}
+
+func TestCreateScanPath_Remote(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://localhost:8989", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, remote string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true, %s}`, path, arrID, remote))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		remote  string
+		message string
+	}{
+		{`"remote_url": "ftp://seedbox/tv"`, "remote_url"},
+		{`"remote_url": "sftp://seedbox/tv"`, "remote_host_key_fingerprint is required"},
+		{`"remote_url": "https://seedbox/dav", "remote_requests_per_minute": 601`, "remote_requests_per_minute must be between 1 and 600"},
+	}
+	for _, tt := range tests {
+		w := post("/seedbox/invalid", tt.remote)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.remote)
+		assert.Contains(t, w.Body.String(), tt.message)
+	}
+
+	w := post("/seedbox/tv", `"remote_url": "https://seedbox/dav/tv", "remote_username": "me", "remote_password": "secret"`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Equal(t, http.StatusCreated, post("/media/movies", `"remote_username": "ignored"`).Code)
+
+	var stored string
+	require.NoError(t, db.QueryRow("SELECT remote_password FROM scan_paths WHERE local_path = '/seedbox/tv'").Scan(&stored))
+	password, err := crypto.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", password)
+
+	req, _ := http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 2)
+	byPath := map[string]map[string]interface{}{}
+	for _, p := range paths {
+		byPath[p["local_path"].(string)] = p
+	}
+	remotePath := byPath["/seedbox/tv"]
+	assert.Equal(t, redact.Mask, remotePath["remote_password"])
+	assert.Equal(t, float64(60), remotePath["remote_requests_per_minute"])
+	capabilities, ok := remotePath["remote_capabilities"].(map[string]interface{})
+	require.True(t, ok, "remote paths report their capabilities")
+	assert.Equal(t, "webdav", capabilities["protocol"])
+	assert.Equal(t, false, capabilities["stream_decode"])
+	assert.Equal(t, true, remotePath["capability"].(map[string]interface{})["scannable"])
+
+	assert.Nil(t, byPath["/media/movies"]["remote_capabilities"])
+	assert.Equal(t, "", byPath["/media/movies"]["remote_username"], "local paths drop remote settings")
+}
+
+func TestCreateScanPath_MediaFilter(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, filter string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true, %s}`, path, arrID, filter))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/bad", `"media_extensions": "mkv, .m*"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "media_extensions")
+	w = post("/media/bad", `"min_file_size_mb": -1`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "min_file_size_mb must be between 0")
+
+	w = post("/media/movies", `"media_extensions": "MKV mp4 .mkv", "min_file_size_mb": 200`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var extensions string
+	var minSize int
+	require.NoError(t, db.QueryRow("SELECT media_extensions, min_file_size_mb FROM scan_paths WHERE local_path = '/media/movies'").Scan(&extensions, &minSize))
+	assert.Equal(t, ".mkv,.mp4", extensions)
+	assert.Equal(t, 200, minSize)
+}
+
+func TestCreateScanPath_DurationCheck(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, extra string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true%s}`, path, arrID, extra))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/bad", `, "duration_check": "always"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "duration_check must be one of")
+
+	require.Equal(t, http.StatusCreated, post("/media/movies", `, "duration_check": "flag"`).Code)
+	require.Equal(t, http.StatusCreated, post("/media/tv", "").Code)
+
+	checks := map[string]string{}
+	rows, err := db.Query("SELECT local_path, duration_check FROM scan_paths")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var path, check string
+		require.NoError(t, rows.Scan(&path, &check))
+		checks[path] = check
+	}
+	assert.Equal(t, "flag", checks["/media/movies"])
+	assert.Equal(t, "off", checks["/media/tv"])
+}
+
+func TestCreateScanPath_SymlinkPolicy(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, extra string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true%s}`, path, arrID, extra))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/bad", `, "symlink_policy": "resolve"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "symlink_policy must be one of")
+
+	require.Equal(t, http.StatusCreated, post("/media/movies", `, "symlink_policy": "verify_target_once"`).Code)
+	require.Equal(t, http.StatusCreated, post("/media/tv", "").Code)
+
+	policies := map[string]string{}
+	rows, err := db.Query("SELECT local_path, symlink_policy FROM scan_paths")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var path, policy string
+		require.NoError(t, rows.Scan(&path, &policy))
+		policies[path] = policy
+	}
+	assert.Equal(t, "verify_target_once", policies["/media/movies"])
+	assert.Equal(t, "skip", policies["/media/tv"])
+}
+
+func TestCreateScanPath_StagedRollout(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://localhost:8989", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, extra string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true%s}`, path, arrID, extra))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/bad", `, "rollout_daily_limit": -1, "rollout_days": 7`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must not be negative")
+
+	require.Equal(t, http.StatusCreated, post("/media/tv", `, "rollout_daily_limit": 25, "rollout_days": 7`).Code)
+	require.Equal(t, http.StatusCreated, post("/media/movies", "").Code)
+
+	req, _ := http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var paths []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paths))
+	require.Len(t, paths, 2)
+	for _, p := range paths {
+		switch p["local_path"] {
+		case "/media/tv":
+			assert.Equal(t, float64(25), p["rollout_daily_limit"])
+			assert.Equal(t, float64(7), p["rollout_days"])
+			endsAt, err := time.Parse(time.RFC3339, p["rollout_ends_at"].(string))
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), endsAt, time.Hour)
+		case "/media/movies":
+			assert.Equal(t, float64(0), p["rollout_daily_limit"])
+			assert.NotContains(t, p, "rollout_ends_at")
+		}
+	}
+}
+
+func TestCreateScanPath_MinConfidence(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://localhost:8989", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(path, extra string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true%s}`, path, arrID, extra))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/media/bad", `, "min_confidence": 101`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "between 0 and 100")
+
+	require.Equal(t, http.StatusCreated, post("/media/tv", `, "min_confidence": 70`).Code)
+	require.Equal(t, http.StatusCreated, post("/media/movies", "").Code)
+
+	confidences := map[string]int{}
+	rows, err := db.Query("SELECT local_path, min_confidence FROM scan_paths")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var path string
+		var minConfidence int
+		require.NoError(t, rows.Scan(&path, &minConfidence))
+		confidences[path] = minConfidence
+	}
+	assert.Equal(t, map[string]int{"/media/tv": 70, "/media/movies": 0}, confidences)
+}
+
+func TestCreateScanPath_DetectionFallbacks(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Sonarr", "sonarr", "http://localhost:8989", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(localPath, fallbacks string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{
+			"local_path": %q,
+			"arr_instance_id": %d,
+			"enabled": true,
+			"detection_method": "mediainfo",
+			"detection_fallbacks": %s
+		}`, localPath, arrID, fallbacks))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("stores preferred order", func(t *testing.T) {
+		w := post("/media/ordered", `["handbrake", "ffprobe"]`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var stored sql.NullString
+		db.QueryRow("SELECT detection_fallbacks FROM scan_paths WHERE local_path = ?", "/media/ordered").Scan(&stored)
+		assert.Equal(t, `["handbrake","ffprobe"]`, stored.String)
+	})
+
+	t.Run("null keeps built-in chain", func(t *testing.T) {
+		w := post("/media/default", `null`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var stored sql.NullString
+		db.QueryRow("SELECT detection_fallbacks FROM scan_paths WHERE local_path = ?", "/media/default").Scan(&stored)
+		assert.False(t, stored.Valid)
+	})
+
+	t.Run("rejects unknown method", func(t *testing.T) {
+		w := post("/media/unknown", `["vlc"]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown detection method")
+	})
+
+	t.Run("rejects primary method in fallbacks", func(t *testing.T) {
+		w := post("/media/dupe", `["mediainfo"]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestCreateScanPath_MaxRetriesDefaults(t *testing.T) {
 	// Ensure config is initialized for this test
 	config.SetForTesting(&config.Config{
@@ -647,10 +1172,16 @@ func TestDeleteScanPath_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
-	// Verify deletion
-	var count int
-	db.QueryRow("SELECT COUNT(*) FROM scan_paths WHERE id = ?", id).Scan(&count)
-	assert.Equal(t, 0, count)
+	// Verify the path is kept as deleted and no longer listed
+	var deletedAt sql.NullString
+	db.QueryRow("SELECT deleted_at FROM scan_paths WHERE id = ?", id).Scan(&deletedAt)
+	assert.True(t, deletedAt.Valid)
+
+	req, _ = http.NewRequest("GET", "/api/config/paths", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.NotContains(t, w.Body.String(), "/to/delete")
 }
 
 func TestDeleteScanPath_DBError(t *testing.T) {
@@ -873,6 +1404,27 @@ func TestGetDetectionPreview_FFprobe_Thorough(t *testing.T) {
 	assert.Contains(t, response["mode_description"], "Decodes the entire file")
 }
 
+func TestGetDetectionPreview_FFprobe_Sampled(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	req, _ := http.NewRequest("GET", "/api/config/detection-preview?method=ffprobe&mode=sampled", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "sampled", response["mode"])
+	assert.Contains(t, response["command"], "-ss <offset>")
+	assert.Contains(t, response["mode_description"], "short segments")
+}
+
 func TestGetDetectionPreview_MediaInfo(t *testing.T) {
 	db, cleanup := setupPathsTestDB(t)
 	defer cleanup()
@@ -1775,3 +2327,95 @@ func TestRelPathOrName(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateScanPath_FileAgeFilter(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	result, _ := db.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, ?, ?, ?)",
+		"Radarr", "radarr", "http://localhost:7878", encryptedKey)
+	arrID, _ := result.LastInsertId()
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	post := func(localPath, extra string) *httptest.ResponseRecorder {
+		body := bytes.NewBufferString(fmt.Sprintf(`{"local_path": %q, "arr_instance_id": %d, "enabled": true%s}`, localPath, arrID, extra))
+		req, _ := http.NewRequest("POST", "/api/config/paths", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("stores configured values", func(t *testing.T) {
+		w := post("/media/rsync", `, "min_file_age_minutes": 30, "size_stability_seconds": 15`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var minutes, seconds int
+		db.QueryRow("SELECT min_file_age_minutes, size_stability_seconds FROM scan_paths WHERE local_path = ?", "/media/rsync").Scan(&minutes, &seconds)
+		assert.Equal(t, 30, minutes)
+		assert.Equal(t, 15, seconds)
+	})
+
+	t.Run("omitted uses default and zero disables", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, post("/media/default", "").Code)
+		require.Equal(t, http.StatusCreated, post("/media/disabled", `, "min_file_age_minutes": 0`).Code)
+
+		var minutes int
+		db.QueryRow("SELECT min_file_age_minutes FROM scan_paths WHERE local_path = ?", "/media/default").Scan(&minutes)
+		assert.Equal(t, defaultMinFileAgeMinutes, minutes)
+		db.QueryRow("SELECT min_file_age_minutes FROM scan_paths WHERE local_path = ?", "/media/disabled").Scan(&minutes)
+		assert.Equal(t, 0, minutes)
+	})
+
+	t.Run("rejects out of range values", func(t *testing.T) {
+		w := post("/media/bad", `, "min_file_age_minutes": -1`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "min_file_age_minutes must be between 0 and 1440")
+
+		w = post("/media/bad", `, "size_stability_seconds": 301`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "size_stability_seconds must be between 0 and 300")
+	})
+}
+
+func TestScanPathChanges_PublishScanPathsChanged(t *testing.T) {
+	db, cleanup := setupPathsTestDB(t)
+	defer cleanup()
+
+	_, err := db.Exec("INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://localhost:8989', 'key')")
+	require.NoError(t, err)
+
+	router, apiKey, serverCleanup := setupPathsTestServer(t, db)
+	defer serverCleanup()
+
+	send := func(method, url, body string) int {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	require.Equal(t, http.StatusCreated, send("POST", "/api/config/paths", `{"local_path": "/media/tv", "arr_path": "/tv", "arr_instance_id": 1}`))
+	var id int64
+	require.NoError(t, db.QueryRow("SELECT id FROM scan_paths WHERE local_path = '/media/tv'").Scan(&id))
+	require.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/api/config/paths/%d", id), ""))
+
+	rows, err := db.Query("SELECT aggregate_id, event_data FROM events WHERE event_type = 'ScanPathsChanged' ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	var actions []string
+	for rows.Next() {
+		var aggregateID, data string
+		require.NoError(t, rows.Scan(&aggregateID, &data))
+		assert.Equal(t, fmt.Sprintf("%d", id), aggregateID)
+		var eventData map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(data), &eventData))
+		actions = append(actions, eventData["action"].(string))
+	}
+	assert.Equal(t, []string{"created", "deleted"}, actions)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// pauseRequest is the body of POST /api/system/pause. All fields are optional.
+type pauseRequest struct {
+	Reason string `json:"reason"`
+	// DetectionOnly keeps scheduled scans running, so new corruptions are
+	// still detected but not remediated
+	DetectionOnly bool `json:"detection_only"`
+	// DurationMinutes resumes automation by itself after that long (0: until resumed)
+	DurationMinutes int `json:"duration_minutes"`
+}
+
+// getSystemPause returns the global automation pause.
+// GET /api/system/pause
+func (s *RESTServer) getSystemPause(c *gin.Context) {
+	c.JSON(http.StatusOK, s.systemPause.Status())
+}
+
+// pauseSystem pauses remediation, verification polling and scheduled scans,
+// e.g. during maintenance on the *arr stack or the storage array. Pausing
+// while paused replaces the reason and auto-resume timer.
+// POST /api/system/pause
+func (s *RESTServer) pauseSystem(c *gin.Context) {
+	var req pauseRequest
+	if c.Request.ContentLength != 0 {
+		if !bindBody(c, &req) {
+			return
+		}
+	}
+
+	status, err := s.systemPause.Pause(req.Reason, req.DetectionOnly, time.Duration(req.DurationMinutes)*time.Minute)
+	if errors.Is(err, services.ErrInvalidPauseDuration) {
+		respondBadRequest(c, err, true)
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// resumeSystem lifts the global automation pause.
+// POST /api/system/resume
+func (s *RESTServer) resumeSystem(c *gin.Context) {
+	status, err := s.systemPause.Resume()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
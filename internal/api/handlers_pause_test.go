@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestSystemPauseEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	pause := services.NewSystemPause(db, eb)
+	defer pause.Stop()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb, systemPause: pause}
+	r.GET("/system/pause", s.getSystemPause)
+	r.POST("/system/pause", s.pauseSystem)
+	r.POST("/system/resume", s.resumeSystem)
+	r.GET("/system/status", s.handleSystemStatus)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/system/pause", `{"reason": "Storage maintenance", "detection_only": true, "duration_minutes": 90}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var status services.SystemPauseStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.True(t, status.Paused)
+	assert.True(t, status.DetectionOnly)
+	assert.Equal(t, "Storage maintenance", status.Reason)
+	require.NotNil(t, status.ResumesAt)
+	assert.True(t, pause.Paused())
+
+	// The pause is recorded as an event
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM events WHERE event_type = 'AutomationPaused'`).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// ... and reported by the system status
+	w = do("GET", "/system/status", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var sysStatus SystemStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sysStatus))
+	assert.True(t, sysStatus.Pause.Paused)
+	require.Len(t, sysStatus.Warnings, 1)
+	assert.Contains(t, sysStatus.Warnings[0], "Storage maintenance")
+
+	w = do("POST", "/system/resume", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, pause.Paused())
+
+	// A body is optional
+	assert.Equal(t, http.StatusOK, do("POST", "/system/pause", "").Code)
+	assert.True(t, pause.Paused())
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/system/pause", `{"duration_minutes": -5}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/system/pause", `{"reason": `).Code)
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Limits for the stored UI preferences.
+const (
+	minPreferencePageSize     = 10
+	maxPreferencePageSize     = 1000
+	maxPreferenceFilterPages  = 20
+	maxPreferenceFiltersPage  = 20
+	maxPreferenceFilterLength = 200
+)
+
+// uiPreferences are the dashboard preferences kept server-side so the UI
+// behaves the same in every browser. Healarr has a single user, so there is
+// one set, shared by the web UI and API clients. Empty fields leave the choice
+// to the browser.
+type uiPreferences struct {
+	PageSize   int    `json:"page_size"`   // rows per page in lists, 0 = not set
+	Timezone   string `json:"timezone"`    // IANA name, e.g. Europe/Berlin; empty = the browser's
+	DateFormat string `json:"date_format"` // time-first, date-first or iso
+	Theme      string `json:"theme"`       // dark or light
+	// DefaultFilters are the filters a page opens with, by page, e.g.
+	// {"corruptions": {"status": "action_required"}}
+	DefaultFilters map[string]map[string]string `json:"default_filters"`
+}
+
+// preferencesUpdate is the body of PUT /api/preferences. Fields left out keep
+// their value; default_filters replaces all default filters.
+type preferencesUpdate struct {
+	PageSize       *int                         `json:"page_size"`
+	Timezone       *string                      `json:"timezone"`
+	DateFormat     *string                      `json:"date_format"`
+	Theme          *string                      `json:"theme"`
+	DefaultFilters map[string]map[string]string `json:"default_filters"`
+}
+
+var preferenceDateFormats = map[string]bool{"": true, "time-first": true, "date-first": true, "iso": true}
+
+var preferenceThemes = map[string]bool{"": true, "dark": true, "light": true}
+
+// apply merges the update into p.
+func (u *preferencesUpdate) apply(p *uiPreferences) {
+	if u.PageSize != nil {
+		p.PageSize = *u.PageSize
+	}
+	if u.Timezone != nil {
+		p.Timezone = *u.Timezone
+	}
+	if u.DateFormat != nil {
+		p.DateFormat = *u.DateFormat
+	}
+	if u.Theme != nil {
+		p.Theme = *u.Theme
+	}
+	if u.DefaultFilters != nil {
+		p.DefaultFilters = u.DefaultFilters
+	}
+}
+
+// validate checks the preferences, returning an error safe to show users.
+func (p *uiPreferences) validate() error {
+	if p.PageSize != 0 && (p.PageSize < minPreferencePageSize || p.PageSize > maxPreferencePageSize) {
+		return fmt.Errorf("page_size must be between %d and %d", minPreferencePageSize, maxPreferencePageSize)
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "Local" {
+			return fmt.Errorf("unknown timezone: %s", p.Timezone)
+		}
+	}
+	if !preferenceDateFormats[p.DateFormat] {
+		return errors.New("date_format must be time-first, date-first or iso")
+	}
+	if !preferenceThemes[p.Theme] {
+		return errors.New("theme must be dark or light")
+	}
+	if len(p.DefaultFilters) > maxPreferenceFilterPages {
+		return fmt.Errorf("default_filters may cover at most %d pages", maxPreferenceFilterPages)
+	}
+	for page, filters := range p.DefaultFilters {
+		if page == "" || len(page) > maxPreferenceFilterLength {
+			return errors.New("default_filters page names must be 1-200 characters")
+		}
+		if len(filters) > maxPreferenceFiltersPage {
+			return fmt.Errorf("default_filters may hold at most %d filters per page", maxPreferenceFiltersPage)
+		}
+		for key, value := range filters {
+			if key == "" || len(key) > maxPreferenceFilterLength || len(value) > maxPreferenceFilterLength {
+				return errors.New("default_filters keys must be 1-200 characters and values at most 200")
+			}
+		}
+	}
+	return nil
+}
+
+// loadPreferences reads the stored preferences. updatedAt is empty when none
+// were saved yet.
+func (s *RESTServer) loadPreferences() (prefs uiPreferences, updatedAt string, err error) {
+	var value string
+	err = s.db.QueryRow("SELECT value, updated_at FROM settings WHERE key = 'ui_preferences'").Scan(&value, &updatedAt)
+	if err == sql.ErrNoRows {
+		return prefs, "", nil
+	}
+	if err != nil {
+		return prefs, "", err
+	}
+	if err := json.Unmarshal([]byte(value), &prefs); err != nil {
+		// Start over rather than lock the user out of saving new preferences
+		logger.Warnf("Ignoring unreadable UI preferences: %v", err)
+		return uiPreferences{}, "", nil
+	}
+	return prefs, updatedAt, nil
+}
+
+// preferencesResponse adds when the preferences were last saved.
+func preferencesResponse(prefs uiPreferences, updatedAt string) gin.H {
+	if prefs.DefaultFilters == nil {
+		prefs.DefaultFilters = map[string]map[string]string{}
+	}
+	var saved interface{}
+	if updatedAt != "" {
+		saved = updatedAt
+	}
+	return gin.H{"preferences": prefs, "updated_at": saved}
+}
+
+// getPreferences returns the stored UI preferences.
+func (s *RESTServer) getPreferences(c *gin.Context) {
+	prefs, updatedAt, err := s.loadPreferences()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preferencesResponse(prefs, updatedAt))
+}
+
+// updatePreferences merges the fields in the body into the stored preferences.
+func (s *RESTServer) updatePreferences(c *gin.Context) {
+	var req preferencesUpdate
+	if !bindBody(c, &req) {
+		return
+	}
+
+	prefs, _, err := s.loadPreferences()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	req.apply(&prefs)
+	if err := prefs.validate(); err != nil {
+		respondBadRequest(c, err, true)
+		return
+	}
+
+	value, err := json.Marshal(prefs)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrMsgInternalError)
+		return
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO settings (key, value, updated_at) VALUES ('ui_preferences', ?, datetime('now'))
+		ON CONFLICT(key) DO UPDATE SET value = ?, updated_at = datetime('now')
+	`, string(value), string(value))
+	if err != nil {
+		logger.Errorf("Failed to save UI preferences: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save preferences"})
+		return
+	}
+
+	s.getPreferences(c)
+}
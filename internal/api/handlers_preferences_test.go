@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPreferencesTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	db, cleanup := setupTestDB(t)
+	t.Cleanup(cleanup)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/preferences", s.getPreferences)
+	r.PUT("/preferences", s.updatePreferences)
+	return r
+}
+
+// preferencesBody is the response of GET and PUT /preferences.
+type preferencesBody struct {
+	Preferences uiPreferences `json:"preferences"`
+	UpdatedAt   *string       `json:"updated_at"`
+}
+
+func doPreferencesRequest(t *testing.T, r *gin.Engine, method, body string) (*httptest.ResponseRecorder, preferencesBody) {
+	t.Helper()
+	req, _ := http.NewRequest(method, "/preferences", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp preferencesBody
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w, resp
+}
+
+func TestPreferences_SaveAndMerge(t *testing.T) {
+	r := setupPreferencesTestRouter(t)
+
+	// Nothing saved yet
+	w, resp := doPreferencesRequest(t, r, "GET", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, resp.UpdatedAt)
+	assert.Equal(t, uiPreferences{DefaultFilters: map[string]map[string]string{}}, resp.Preferences)
+
+	w, _ = doPreferencesRequest(t, r, "PUT", `{
+		"page_size": 100, "timezone": "Europe/Berlin", "date_format": "iso", "theme": "light",
+		"default_filters": {"corruptions": {"status": "action_required"}}
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Fields left out keep their value, default_filters is replaced
+	w, resp = doPreferencesRequest(t, r, "PUT", `{"theme": "dark", "default_filters": {"scans": {"status": "completed"}}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotNil(t, resp.UpdatedAt)
+	assert.Equal(t, uiPreferences{
+		PageSize:       100,
+		Timezone:       "Europe/Berlin",
+		DateFormat:     "iso",
+		Theme:          "dark",
+		DefaultFilters: map[string]map[string]string{"scans": {"status": "completed"}},
+	}, resp.Preferences)
+
+	_, resp = doPreferencesRequest(t, r, "GET", "")
+	assert.Equal(t, "dark", resp.Preferences.Theme)
+	assert.Equal(t, 100, resp.Preferences.PageSize)
+}
+
+func TestPreferences_Validation(t *testing.T) {
+	r := setupPreferencesTestRouter(t)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"page size", `{"page_size": 5}`, "page_size must be between 10 and 1000"},
+		{"timezone", `{"timezone": "Mars/Olympus"}`, "unknown timezone: Mars/Olympus"},
+		{"local timezone", `{"timezone": "Local"}`, "unknown timezone: Local"},
+		{"date format", `{"date_format": "us"}`, "date_format must be time-first, date-first or iso"},
+		{"theme", `{"theme": "blue"}`, "theme must be dark or light"},
+		{"filter key", `{"default_filters": {"corruptions": {"": "x"}}}`, "default_filters keys must be 1-200 characters and values at most 200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := doPreferencesRequest(t, r, "PUT", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+
+	// Rejected updates leave the stored preferences alone
+	_, resp := doPreferencesRequest(t, r, "GET", "")
+	assert.Nil(t, resp.UpdatedAt)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// getProtectedItems lists protected files and media.
+// GET /api/protected
+func (s *RESTServer) getProtectedItems(c *gin.Context) {
+	items, err := s.protection.List()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// protectItem protects a file, or a movie or series of an *arr instance, from
+// automatic remediation.
+// POST /api/protected
+func (s *RESTServer) protectItem(c *gin.Context) {
+	var req struct {
+		FilePath      string `json:"file_path"`
+		ArrInstanceID int64  `json:"arr_instance_id"`
+		MediaID       int64  `json:"media_id"`
+		Note          string `json:"note"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+
+	item := services.ProtectedItem{
+		FilePath:      req.FilePath,
+		ArrInstanceID: req.ArrInstanceID,
+		MediaID:       req.MediaID,
+		Note:          req.Note,
+	}
+	id, err := s.protection.Protect(&item)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidProtectedItem) {
+			respondFieldErrors(c, FieldError{
+				Field:   "file_path",
+				Rule:    "required_without",
+				Param:   "media_id",
+				Message: requestLocale(c).Sprintf("%s is required when %s is not set", "file_path", "arr_instance_id/media_id"),
+			})
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item protected", "id": id})
+}
+
+// deleteProtectedItem removes a protection so the item can be remediated again.
+// DELETE /api/protected/:id
+func (s *RESTServer) deleteProtectedItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	if err := s.protection.Delete(id); err != nil {
+		if errors.Is(err, services.ErrProtectedItemNotFound) {
+			respondNotFound(c, "Protected item")
+			return
+		}
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Protection removed"})
+}
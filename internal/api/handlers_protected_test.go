@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestProtectedItems_Handlers(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/movies', '/movies', 2);
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+			('corruption', 'home-video', 'CorruptionDetected', '{"file_path":"/media/movies/Home/wedding.mkv","path_id":1}'),
+			('corruption', 'rare', 'CorruptionDetected', '{"file_path":"/media/movies/Rare/rare.mkv","path_id":1}'),
+			('corruption', 'rare', 'DeletionStarted', '{"media_id":77}'),
+			('corruption', 'normal', 'CorruptionDetected', '{"file_path":"/media/movies/Normal/normal.mkv","path_id":1}');
+	`)
+	require.NoError(t, err)
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb, protection: services.NewProtectionService(db)}
+	r.GET("/protected", s.getProtectedItems)
+	r.POST("/protected", s.protectItem)
+	r.DELETE("/protected/:id", s.deleteProtectedItem)
+	r.GET("/corruptions", s.getCorruptions)
+	r.POST("/corruptions/retry", s.retryCorruptions)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/protected", `{"note":"nothing to protect"}`).Code)
+	w := do("POST", "/protected", `{"arr_instance_id":2}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "file_path", problem.Errors[0].Field)
+
+	w = do("POST", "/protected", `{"file_path":"/media/movies/Home/wedding.mkv","note":"irreplaceable"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = do("POST", "/protected", `{"arr_instance_id":2,"media_id":77}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = do("GET", "/protected", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var items []services.ProtectedItem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	assert.Len(t, items, 2)
+
+	// Corruption list flags protected files and media
+	w = do("GET", "/corruptions", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 3)
+	for _, corruption := range list.Data {
+		assert.Equal(t, corruption["id"] != "normal", corruption["protected"], "corruption %v", corruption["id"])
+	}
+
+	// Retrying skips protected corruptions
+	w = do("POST", "/corruptions/retry", `{"ids":["home-video","rare","normal"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var retried struct {
+		Retried          int `json:"retried"`
+		SkippedProtected int `json:"skipped_protected"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &retried))
+	assert.Equal(t, 1, retried.Retried)
+	assert.Equal(t, 2, retried.SkippedProtected)
+
+	var retryEvents int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM events WHERE event_type = ?", string(domain.RetryScheduled)).Scan(&retryEvents))
+	assert.Equal(t, 1, retryEvents)
+
+	assert.Equal(t, http.StatusBadRequest, do("DELETE", "/protected/abc", "").Code)
+	assert.Equal(t, http.StatusOK, do("DELETE", "/protected/"+strconv.FormatInt(created.ID, 10), "").Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/protected/"+strconv.FormatInt(created.ID, 10), "").Code)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// publicRecentRemediations is how many resolved corruptions the public dashboard lists.
+const publicRecentRemediations = 10
+
+// PublicDashboard is the read-only status served without authentication.
+// It carries aggregate counts and file names only: no full paths, IDs or configuration.
+type PublicDashboard struct {
+	Status             string                    `json:"status"` // worst status of the enabled libraries
+	Corruptions        PublicCorruptionSummary   `json:"corruptions"`
+	Libraries          []PublicLibraryHealth     `json:"libraries"`
+	RecentRemediations []PublicRecentRemediation `json:"recent_remediations"`
+	ActiveScans        int                       `json:"active_scans"`
+	GeneratedAt        time.Time                 `json:"generated_at"`
+}
+
+// PublicCorruptionSummary counts corruptions by outcome.
+type PublicCorruptionSummary struct {
+	Total       int `json:"total"`
+	Pending     int `json:"pending"`
+	InProgress  int `json:"in_progress"`
+	Resolved    int `json:"resolved"`
+	Failed      int `json:"failed"` // failed, orphaned and needing manual intervention
+	SuccessRate int `json:"success_rate"`
+}
+
+// PublicLibraryHealth is the health of one scan path, named by its last path element.
+type PublicLibraryHealth struct {
+	Name              string  `json:"name"`
+	Status            string  `json:"status"`
+	ActiveCorruptions int     `json:"active_corruptions"`
+	LastScanTime      *string `json:"last_scan_time,omitempty"`
+}
+
+// PublicRecentRemediation is a successfully replaced file.
+type PublicRecentRemediation struct {
+	FileName    string `json:"file_name"`
+	CompletedAt string `json:"completed_at"`
+}
+
+// pathHealthSeverity orders path health statuses from best to worst.
+var pathHealthSeverity = map[string]int{"disabled": 0, "healthy": 1, "unknown": 2, "warning": 3, "critical": 4}
+
+// getPublicDashboard returns the read-only status page data.
+// GET /api/public/dashboard (only routed when HEALARR_PUBLIC_DASHBOARD is enabled)
+func (s *RESTServer) getPublicDashboard(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	counts, err := s.loadCorruptionCounts(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	paths, err := s.collectPathHealth(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	dashboard := PublicDashboard{
+		Status: "healthy",
+		Corruptions: PublicCorruptionSummary{
+			Total:       counts.total(),
+			Pending:     counts.pending,
+			InProgress:  counts.inProgress,
+			Resolved:    counts.resolved,
+			Failed:      counts.failed + counts.orphaned + counts.manualIntervention,
+			SuccessRate: counts.successRate(),
+		},
+		Libraries:          make([]PublicLibraryHealth, 0, len(paths)),
+		RecentRemediations: s.loadPublicRecentRemediations(ctx),
+		GeneratedAt:        time.Now().UTC(),
+	}
+	for _, p := range paths {
+		dashboard.Libraries = append(dashboard.Libraries, PublicLibraryHealth{
+			Name:              filepath.Base(p.LocalPath),
+			Status:            p.Status,
+			ActiveCorruptions: p.ActiveCorruptions,
+			LastScanTime:      p.LastScanTime,
+		})
+		if pathHealthSeverity[p.Status] > pathHealthSeverity[dashboard.Status] {
+			dashboard.Status = p.Status
+		}
+	}
+	if s.scanner != nil {
+		dashboard.ActiveScans = len(s.scanner.GetActiveScans())
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// loadPublicRecentRemediations returns the most recently resolved files, newest first.
+func (s *RESTServer) loadPublicRecentRemediations(ctx context.Context) []PublicRecentRemediation {
+	remediations := make([]PublicRecentRemediation, 0)
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT file_path, last_updated_at FROM corruption_summary WHERE current_state = ? ORDER BY last_updated_at DESC LIMIT ?",
+		string(domain.VerificationSuccess), publicRecentRemediations)
+	if err != nil {
+		logger.Debugf("Failed to query recent remediations for public dashboard: %v", err)
+		return remediations
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var filePath, completedAt string
+		if rows.Scan(&filePath, &completedAt) != nil {
+			continue
+		}
+		remediations = append(remediations, PublicRecentRemediation{
+			FileName:    filepath.Base(filePath),
+			CompletedAt: completedAt,
+		})
+	}
+	return remediations
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetPublicDashboard(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, enabled) VALUES (1, '/media/movies', '/movies', 1), (2, '/media/tv', '/tv', 1);
+		INSERT INTO scans (path, path_id, status, completed_at) VALUES ('/media/movies', 1, 'completed', '2026-01-01 10:00:00'), ('/media/tv', 2, 'completed', '2026-01-01 11:00:00');
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+			('corruption', 'c1', 'CorruptionDetected', '{"file_path":"/media/movies/Movie (2020)/movie.mkv","path_id":1}'),
+			('corruption', 'c1', 'VerificationSuccess', '{"file_path":"/media/movies/Movie (2020)/movie.mkv","path_id":1}'),
+			('corruption', 'c2', 'CorruptionDetected', '{"file_path":"/media/tv/Show/S01E01.mkv","path_id":2}');
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/public/dashboard", s.getPublicDashboard)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/public/dashboard", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "/media/", "full paths must not be exposed")
+
+	var dashboard PublicDashboard
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dashboard))
+	assert.Equal(t, "warning", dashboard.Status)
+	assert.Equal(t, 2, dashboard.Corruptions.Total)
+	assert.Equal(t, 1, dashboard.Corruptions.Resolved)
+	assert.Equal(t, 1, dashboard.Corruptions.Pending)
+
+	require.Len(t, dashboard.Libraries, 2)
+	assert.Equal(t, "movies", dashboard.Libraries[0].Name)
+	assert.Equal(t, "healthy", dashboard.Libraries[0].Status)
+	assert.Equal(t, "tv", dashboard.Libraries[1].Name)
+	assert.Equal(t, 1, dashboard.Libraries[1].ActiveCorruptions)
+
+	require.Len(t, dashboard.RecentRemediations, 1)
+	assert.Equal(t, "movie.mkv", dashboard.RecentRemediations[0].FileName)
+}
+
+func TestPublicDashboard_Routing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	for _, enabled := range []bool{false, true} {
+		cfg := config.NewTestConfig()
+		cfg.PublicDashboard = enabled
+		config.SetForTesting(cfg)
+
+		s := NewRESTServer(ServerDeps{DB: db, EventBus: eb, Metrics: getGlobalMetricsService(eb)})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/public/dashboard", nil)
+		s.router.ServeHTTP(w, req)
+		if enabled {
+			assert.Equal(t, http.StatusOK, w.Code, "public dashboard should not require auth")
+		} else {
+			assert.Equal(t, http.StatusNotFound, w.Code, "public dashboard should be off by default")
+		}
+
+		// Everything else stays protected
+		for _, route := range []struct{ method, path string }{
+			{"GET", "/api/corruptions"},
+			{"GET", "/api/stats/dashboard"},
+			{"GET", "/api/config/arr"},
+			{"POST", "/api/scans"},
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(route.method, route.path, nil)
+			s.router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s (public dashboard %v)", route.method, route.path, enabled)
+		}
+	}
+}
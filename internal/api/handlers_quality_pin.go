@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// corruptionExists reports whether a corruption with this ID was ever detected.
+func (s *RESTServer) corruptionExists(ctx context.Context, id string) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM events WHERE aggregate_id = ? AND event_type = 'CorruptionDetected'
+	`, id).Scan(&count)
+	return count > 0, err
+}
+
+// respondQualityPin sends the quality pin that now applies to a corruption.
+func (s *RESTServer) respondQualityPin(ctx context.Context, c *gin.Context, id string) {
+	pin, err := services.LoadQualityPin(ctx, s.db, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pin)
+}
+
+// getQualityPin returns the quality pin of a corruption and whether it comes
+// from the corruption itself or its scan path.
+// GET /api/corruptions/:id/quality-pin
+func (s *RESTServer) getQualityPin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	exists, err := s.corruptionExists(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if !exists {
+		respondNotFound(c, "Corruption")
+		return
+	}
+	s.respondQualityPin(ctx, c, id)
+}
+
+// setQualityPin overrides the quality pin of a corruption.
+// PUT /api/corruptions/:id/quality-pin
+func (s *RESTServer) setQualityPin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+	if !services.ValidQualityPin(req.Mode) {
+		respondBadRequest(c, services.ErrInvalidQualityPin, true)
+		return
+	}
+
+	id := c.Param("id")
+	exists, err := s.corruptionExists(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if !exists {
+		respondNotFound(c, "Corruption")
+		return
+	}
+
+	if err := services.SetQualityPin(s.db, id, req.Mode); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondQualityPin(ctx, c, id)
+}
+
+// clearQualityPin removes a corruption's quality pin override, so its scan
+// path's setting applies again.
+// DELETE /api/corruptions/:id/quality-pin
+func (s *RESTServer) clearQualityPin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	if err := services.ClearQualityPin(s.db, id); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondQualityPin(ctx, c, id)
+}
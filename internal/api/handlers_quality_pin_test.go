@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestQualityPinEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, quality_pin) VALUES (1, '/media/movies', '/movies', 'flag')`)
+	require.NoError(t, err)
+	_, err = testutil.SeedEvent(db, domain.Event{
+		AggregateID:   "c1",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/movies/a.mkv", "path_id": 1},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/corruptions/:id/quality-pin", s.getQualityPin)
+	r.PUT("/corruptions/:id/quality-pin", s.setQualityPin)
+	r.DELETE("/corruptions/:id/quality-pin", s.clearQualityPin)
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, services.QualityPin) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		r.ServeHTTP(w, req)
+		var pin services.QualityPin
+		_ = json.Unmarshal(w.Body.Bytes(), &pin)
+		return w, pin
+	}
+
+	w, pin := do("GET", "/corruptions/c1/quality-pin", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.QualityPin{Mode: "flag", Source: "path"}, pin)
+
+	w, pin = do("PUT", "/corruptions/c1/quality-pin", `{"mode": "search"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.QualityPin{Mode: "search", Source: "corruption"}, pin)
+
+	w, _ = do("PUT", "/corruptions/c1/quality-pin", `{"mode": "strict"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = do("PUT", "/corruptions/missing/quality-pin", `{"mode": "flag"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, pin = do("DELETE", "/corruptions/c1/quality-pin", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.QualityPin{Mode: "flag", Source: "path"}, pin)
+}
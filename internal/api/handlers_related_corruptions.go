@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+// RelatedCorruption is a corruption that shares a media item, season or
+// download with another one.
+type RelatedCorruption struct {
+	CorruptionID  string   `json:"corruption_id"`
+	FilePath      string   `json:"file_path"`
+	State         string   `json:"state"`
+	Active        bool     `json:"active"`    // still being remediated
+	Relations     []string `json:"relations"` // media, season and/or download
+	DetectedAt    string   `json:"detected_at"`
+	LastUpdatedAt string   `json:"last_updated_at"`
+}
+
+// RelatedCorruptions lists the corruptions related to one, with how many
+// share each relation.
+type RelatedCorruptions struct {
+	CorruptionID string              `json:"corruption_id"`
+	Related      []RelatedCorruption `json:"related"`
+	// Counts are the related corruptions per relation, and Active those of
+	// them still being remediated, e.g. for "3 other episodes from this
+	// season are also being replaced".
+	Counts map[string]int `json:"counts"`
+	Active map[string]int `json:"active"`
+}
+
+// getRelatedCorruptions returns the corruptions of the same movie or series,
+// the same season, or replaced by the same download (e.g. a season pack).
+// GET /api/corruptions/:id/related
+func (s *RESTServer) getRelatedCorruptions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	exists, err := s.corruptionExists(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if !exists {
+		respondNotFound(c, "Corruption")
+		return
+	}
+	related, err := s.loadRelatedCorruptions(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, related)
+}
+
+// loadRelatedCorruptions loads the corruptions sharing a link with id.
+func (s *RESTServer) loadRelatedCorruptions(ctx context.Context, id string) (*RelatedCorruptions, error) {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT other.corruption_id, GROUP_CONCAT(DISTINCT other.relation),
+			COALESCE(cs.file_path, ''), cs.current_state, cs.detected_at, cs.last_updated_at
+		FROM corruption_links l
+		JOIN corruption_links other ON other.relation = l.relation AND other.link_key = l.link_key
+			AND other.corruption_id != l.corruption_id
+		JOIN corruption_summary cs ON cs.corruption_id = other.corruption_id
+		WHERE l.corruption_id = ?
+		GROUP BY other.corruption_id
+		ORDER BY cs.file_path
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terminal := make(map[string]bool)
+	for _, t := range domain.TerminalStates() {
+		terminal[string(t)] = true
+	}
+	result := &RelatedCorruptions{
+		CorruptionID: id,
+		Related:      []RelatedCorruption{},
+		Counts:       map[string]int{db.RelationMedia: 0, db.RelationSeason: 0, db.RelationDownload: 0},
+		Active:       map[string]int{db.RelationMedia: 0, db.RelationSeason: 0, db.RelationDownload: 0},
+	}
+	for rows.Next() {
+		var r RelatedCorruption
+		var relations string
+		if err := rows.Scan(&r.CorruptionID, &relations, &r.FilePath, &r.State, &r.DetectedAt, &r.LastUpdatedAt); err != nil {
+			return nil, err
+		}
+		r.Relations = strings.Split(relations, ",")
+		r.Active = !terminal[r.State]
+		for _, relation := range r.Relations {
+			result.Counts[relation]++
+			if r.Active {
+				result.Active[relation]++
+			}
+		}
+		result.Related = append(result.Related, r)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetRelatedCorruptions(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+			('corruption', 'e1', 'CorruptionDetected', '{"file_path": "/tv/Show/S01E01.mkv", "path_id": 1}'),
+			('corruption', 'e2', 'CorruptionDetected', '{"file_path": "/tv/Show/S01E02.mkv", "path_id": 1}'),
+			('corruption', 'e3', 'CorruptionDetected', '{"file_path": "/tv/Show/S01E03.mkv", "path_id": 1}'),
+			('corruption', 'm1', 'CorruptionDetected', '{"file_path": "/tv/Show/S02E01.mkv", "path_id": 1}'),
+			('corruption', 'lone', 'CorruptionDetected', '{"file_path": "/movies/x.mkv", "path_id": 2}'),
+			('corruption', 'e1', 'SearchCompleted', '{}'),
+			('corruption', 'e2', 'DownloadProgress', '{}'),
+			('corruption', 'e3', 'VerificationSuccess', '{}'),
+			('corruption', 'm1', 'SearchCompleted', '{}');
+		INSERT INTO corruption_links (corruption_id, relation, link_key) VALUES
+			('e1', 'media', '1:7'), ('e1', 'season', '1:7:1'), ('e1', 'download', 'pack'),
+			('e2', 'media', '1:7'), ('e2', 'season', '1:7:1'), ('e2', 'download', 'pack'),
+			('e3', 'media', '1:7'), ('e3', 'season', '1:7:1'),
+			('m1', 'media', '1:7');
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/api/corruptions/:id/related", s.getRelatedCorruptions)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/corruptions/"+id+"/related", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("e1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp RelatedCorruptions
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Related, 3)
+	assert.Equal(t, "e2", resp.Related[0].CorruptionID)
+	assert.ElementsMatch(t, []string{"media", "season", "download"}, resp.Related[0].Relations)
+	assert.True(t, resp.Related[0].Active)
+	assert.Equal(t, "e3", resp.Related[1].CorruptionID)
+	assert.False(t, resp.Related[1].Active)
+	assert.ElementsMatch(t, []string{"media"}, resp.Related[2].Relations)
+	assert.Equal(t, map[string]int{"media": 3, "season": 2, "download": 1}, resp.Counts)
+	assert.Equal(t, map[string]int{"media": 2, "season": 1, "download": 1}, resp.Active)
+
+	w = get("lone")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Related)
+
+	assert.Equal(t, http.StatusNotFound, get("missing").Code)
+}
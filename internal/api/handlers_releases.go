@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// getCorruptionReleases runs an interactive search for a corruption's media
+// item and returns the releases the *arr instance's indexers offer.
+// GET /api/corruptions/:id/releases
+func (s *RESTServer) getCorruptionReleases(c *gin.Context) {
+	if s.remediator == nil {
+		respondServiceUnavailable(c, "Remediator")
+		return
+	}
+
+	// Interactive searches query every indexer, so they aren't bound by dbTimeout
+	id := c.Param("id")
+	releases, err := s.remediator.SearchReleases(c.Request.Context(), id)
+	if errors.Is(err, services.ErrCorruptionNotFound) {
+		respondNotFound(c, "Corruption")
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to search releases for corruption %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to search releases: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, releases)
+}
+
+// grabCorruptionRelease makes the *arr instance download a release returned by
+// getCorruptionReleases as the corruption's replacement.
+// POST /api/corruptions/:id/releases/grab
+func (s *RESTServer) grabCorruptionRelease(c *gin.Context) {
+	if s.remediator == nil {
+		respondServiceUnavailable(c, "Remediator")
+		return
+	}
+
+	var req struct {
+		GUID      string `json:"guid" binding:"required"`
+		IndexerID int64  `json:"indexer_id" binding:"gt=0"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+
+	id := c.Param("id")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	protected := s.protectedCorruptions(ctx, []string{id})
+	cancel()
+	if protected[id] {
+		c.JSON(http.StatusConflict, gin.H{"error": "Corruption concerns a protected file"})
+		return
+	}
+
+	release, err := s.remediator.GrabRelease(c.Request.Context(), id, req.GUID, req.IndexerID)
+	if errors.Is(err, services.ErrCorruptionNotFound) {
+		respondNotFound(c, "Corruption")
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to grab release for corruption %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to grab release: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, release)
+}
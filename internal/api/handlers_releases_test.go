@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestCorruptionReleasesEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = testutil.SeedEvent(db, domain.Event{
+		AggregateID:   "movie",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/movies/a.mkv", "path_id": 1},
+	})
+	require.NoError(t, err)
+
+	arr := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 7, nil },
+		GetReleasesFunc: func(mediaID int64, arrPath string, episodeIDs []int64) ([]integration.Release, error) {
+			return []integration.Release{{GUID: "a", IndexerID: 1, Title: "Movie.2024.1080p"}}, nil
+		},
+		GrabReleaseFunc: func(arrPath, guid string, indexerID int64) (*integration.Release, error) {
+			return &integration.Release{GUID: guid, IndexerID: indexerID, Title: "Movie.2024.1080p"}, nil
+		},
+	}
+	bus := testutil.NewMockEventBus()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, remediator: services.NewRemediatorService(bus, arr, &testutil.MockPathMapper{}, db)}
+	r.GET("/corruptions/:id/releases", s.getCorruptionReleases)
+	r.POST("/corruptions/:id/releases/grab", s.grabCorruptionRelease)
+
+	t.Run("list releases", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/corruptions/movie/releases", nil)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var releases []integration.Release
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &releases))
+		assert.Len(t, releases, 1)
+	})
+
+	t.Run("unknown corruption", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/corruptions/unknown/releases", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("grab requires a release", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/corruptions/movie/releases/grab", bytes.NewBufferString(`{"guid": "a"}`))
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("grab", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/corruptions/movie/releases/grab", bytes.NewBufferString(`{"guid": "a", "indexer_id": 1}`))
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, arr.CallCount("GrabRelease"))
+		assert.Len(t, bus.GetEvents(domain.SearchCompleted), 1)
+	})
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// remediationQueueInstance is an instance's throttle state with its display name.
+type remediationQueueInstance struct {
+	services.InstanceThrottleStatus
+	Name string `json:"name"`
+}
+
+// remediationQueueEntry is a queued remediation with its instance's display name.
+type remediationQueueEntry struct {
+	services.QueuedRemediation
+	InstanceName string `json:"instance_name"`
+}
+
+// getRemediationQueue returns the per-instance remediation limits, what is
+// running against each instance and the remediations waiting for a slot.
+func (s *RESTServer) getRemediationQueue(c *gin.Context) {
+	if s.remediator == nil {
+		respondServiceUnavailable(c, "Remediator")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	names := make(map[int64]string)
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM arr_instances`)
+	if err != nil {
+		logger.Debugf("Failed to load instance names for remediation queue: %v", err)
+	} else {
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			var name string
+			if rows.Scan(&id, &name) == nil {
+				names[id] = name
+			}
+		}
+	}
+
+	status := s.remediator.QueueStatus()
+	instances := make([]remediationQueueInstance, 0, len(status.Instances))
+	for _, inst := range status.Instances {
+		instances = append(instances, remediationQueueInstance{InstanceThrottleStatus: inst, Name: names[inst.InstanceID]})
+	}
+	queue := make([]remediationQueueEntry, 0, len(status.Queue))
+	for _, q := range status.Queue {
+		queue = append(queue, remediationQueueEntry{QueuedRemediation: q, InstanceName: names[q.InstanceID]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"max_concurrent":    status.MaxConcurrent,
+		"searches_per_hour": status.SearchesPerHour,
+		"budget_paused":     status.BudgetPaused,
+		"instances":         instances,
+		"queue":             queue,
+	})
+}
+
+// getRemediationBudget returns this month's remediation data usage against the
+// monthly budget, what happens to remediations over it, and how many are
+// deferred to next month or waiting for approval.
+// GET /api/remediation/budget
+func (s *RESTServer) getRemediationBudget(c *gin.Context) {
+	if s.remediator == nil {
+		respondServiceUnavailable(c, "Remediator")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	status, err := services.LoadDataBudgetStatus(ctx, s.reader(), time.Now(), s.remediator.DataBudget())
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	var deferred, awaitingApproval int
+	err = s.reader().QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN current_state = 'RemediationDeferred' THEN 1 END),
+			COUNT(CASE WHEN current_state = 'BudgetApprovalRequired' THEN 1 END)
+		FROM corruption_summary
+		WHERE current_state IN ('RemediationDeferred', 'BudgetApprovalRequired')
+	`).Scan(&deferred, &awaitingApproval)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":           status.BudgetBytes > 0,
+		"action":            s.remediator.DataBudgetAction(),
+		"paused":            s.remediator.QueueStatus().BudgetPaused,
+		"deferred_month":    s.remediator.DeferredMonth(),
+		"deferred":          deferred,
+		"awaiting_approval": awaitingApproval,
+		"current_month":     status,
+	})
+}
+
+// approveBudgetRemediations lets remediations that wait for approval over the
+// monthly data budget go ahead anyway.
+// POST /api/remediation/budget/approve
+func (s *RESTServer) approveBudgetRemediations(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	var req struct {
+		IDs []string `json:"ids" binding:"min=1"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+
+	approved, skipped := 0, 0
+	for _, id := range req.IDs {
+		var filePath sql.NullString
+		var pathID sql.NullInt64
+		err := s.db.QueryRowContext(ctx, `
+			SELECT file_path, path_id FROM corruption_summary
+			WHERE corruption_id = ? AND current_state = 'BudgetApprovalRequired'
+		`, id).Scan(&filePath, &pathID)
+		if err != nil || !filePath.Valid || filePath.String == "" {
+			skipped++
+			continue
+		}
+
+		if err := s.eventBus.Publish(domain.Event{
+			AggregateID:   id,
+			AggregateType: "corruption",
+			EventType:     domain.RetryScheduled,
+			EventData: map[string]interface{}{
+				"file_path":       filePath.String,
+				"path_id":         pathID.Int64,
+				"auto_remediate":  true,
+				"manual_retry":    true,
+				"budget_approved": true,
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish RetryScheduled event for %s: %v", id, err)
+			skipped++
+			continue
+		}
+		approved++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  fmt.Sprintf("Approved %d remediation(s), skipped %d not waiting for approval", approved, skipped),
+		"approved": approved,
+		"skipped":  skipped,
+	})
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestGetRemediationQueue_Unavailable(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/remediation/queue", s.getRemediationQueue)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/remediation/queue", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetRemediationQueue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	_, err := db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'key');
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/tv', '/tv', 1);
+	`)
+	require.NoError(t, err)
+
+	unblock := make(chan struct{})
+	arr := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(string) (int64, error) { return 1, nil },
+		DeleteFileFunc: func(int64, string) (map[string]interface{}, error) {
+			<-unblock
+			return nil, nil
+		},
+		TriggerSearchFunc: func(int64, string, []int64) error { return nil },
+	}
+	bus := testutil.NewMockEventBus()
+	remediator := services.NewRemediatorService(bus, arr, &testutil.MockPathMapper{}, db)
+	remediator.SetThrottle(services.RemediationThrottleConfig{MaxConcurrent: 1, SearchesPerHour: 20})
+	remediator.Start()
+	defer remediator.Stop()
+	defer close(unblock)
+
+	for _, file := range []string{"/media/tv/a.mkv", "/media/tv/b.mkv"} {
+		require.NoError(t, bus.Publish(testutil.NewCorruptionEventWithType(file, integration.ErrorTypeCorruptHeader,
+			testutil.WithAutoRemediate(true), testutil.WithPathID(1))))
+	}
+	require.Eventually(t, func() bool { return len(remediator.QueueStatus().Queue) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, remediator: remediator}
+	r.GET("/remediation/queue", s.getRemediationQueue)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/remediation/queue", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		MaxConcurrent   int `json:"max_concurrent"`
+		SearchesPerHour int `json:"searches_per_hour"`
+		Instances       []struct {
+			InstanceID int64  `json:"instance_id"`
+			Name       string `json:"name"`
+			Active     int    `json:"active"`
+			Queued     int    `json:"queued"`
+		} `json:"instances"`
+		Queue []struct {
+			FilePath     string `json:"file_path"`
+			InstanceName string `json:"instance_name"`
+			Position     int    `json:"position"`
+		} `json:"queue"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.MaxConcurrent)
+	assert.Equal(t, 20, resp.SearchesPerHour)
+	require.Len(t, resp.Instances, 1)
+	assert.Equal(t, "Sonarr", resp.Instances[0].Name)
+	assert.Equal(t, 1, resp.Instances[0].Active)
+	assert.Equal(t, 1, resp.Instances[0].Queued)
+	require.Len(t, resp.Queue, 1)
+	assert.Equal(t, "Sonarr", resp.Queue[0].InstanceName)
+	assert.Equal(t, 1, resp.Queue[0].Position)
+
+	// Both remediations were announced as queued
+	assert.Equal(t, 2, bus.EventCount(domain.RemediationQueued))
+}
+
+func TestRemediationBudget(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now().UTC()
+	_, err = db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('held', '/media/held.mkv', 1, 'BudgetApprovalRequired', ?, ?),
+			('later', '/media/later.mkv', 1, 'RemediationDeferred', ?, ?)
+	`, now, now, now, now)
+	require.NoError(t, err)
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+	remediator := services.NewRemediatorService(testutil.NewMockEventBus(), &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	remediator.SetDataBudget(1000)
+	remediator.SetDataBudgetAction(config.BudgetActionApprove)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb, remediator: remediator}
+	r.GET("/remediation/budget", s.getRemediationBudget)
+	r.POST("/remediation/budget/approve", s.approveBudgetRemediations)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/remediation/budget", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status struct {
+		Enabled          bool                      `json:"enabled"`
+		Action           string                    `json:"action"`
+		Deferred         int                       `json:"deferred"`
+		AwaitingApproval int                       `json:"awaiting_approval"`
+		CurrentMonth     services.DataBudgetStatus `json:"current_month"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.True(t, status.Enabled)
+	assert.Equal(t, config.BudgetActionApprove, status.Action)
+	assert.Equal(t, 1, status.Deferred)
+	assert.Equal(t, 1, status.AwaitingApproval)
+	assert.Equal(t, int64(1000), status.CurrentMonth.BudgetBytes)
+
+	// Only remediations waiting for approval can be approved
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/remediation/budget/approve", strings.NewReader(`{"ids": ["held", "later"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Approved int `json:"approved"`
+		Skipped  int `json:"skipped"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Approved)
+	assert.Equal(t, 1, resp.Skipped)
+
+	var approved bool
+	require.NoError(t, db.QueryRow(`
+		SELECT json_extract(event_data, '$.budget_approved') FROM events
+		WHERE aggregate_id = 'held' AND event_type = 'RetryScheduled'
+	`).Scan(&approved))
+	assert.True(t, approved)
+}
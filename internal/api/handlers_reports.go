@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// reportListLimit caps how many reports GET /api/reports returns.
+const reportListLimit = 52
+
+// getReports lists stored reports, newest first.
+func (s *RESTServer) getReports(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	reports, err := s.reports.List(ctx, reportListLimit)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// generateReport compiles a report for the last week and delivers it to notification channels.
+func (s *RESTServer) generateReport(c *gin.Context) {
+	report, err := s.reports.GenerateAndDeliver(c.Request.Context())
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// getReport returns a stored report as JSON, or as a Markdown or HTML download when format is set.
+func (s *RESTServer) getReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	report, err := s.reports.Get(ctx, id)
+	if errors.Is(err, services.ErrReportNotFound) {
+		respondNotFound(c, "Report")
+		return
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("healarr_report_%s", report.PeriodEnd.Format("2006-01-02"))
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, report)
+	case "markdown", "md":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.md", filename))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(report.Markdown()))
+	case "html":
+		page, err := report.HTML()
+		if err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.html", filename))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, markdown or html"})
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// getResolutionAudit returns the resolution durability over the audit window
+// and the latest audit since startup.
+// GET /api/corruptions/audit
+func (s *RESTServer) getResolutionAudit(c *gin.Context) {
+	if s.resolutionAudit == nil {
+		respondServiceUnavailable(c, "Resolution audit")
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	durability, err := s.resolutionAudit.Durability(ctx)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"schedule":   config.Get().ResolutionAuditSchedule,
+		"durability": durability,
+		"last_run":   s.resolutionAudit.LastRun(),
+	})
+}
+
+// runResolutionAudit starts an audit of recently resolved corruptions now.
+// The reverifications run in the background, a batch at a time.
+// POST /api/corruptions/audit
+func (s *RESTServer) runResolutionAudit(c *gin.Context) {
+	if s.resolutionAudit == nil {
+		respondServiceUnavailable(c, "Resolution audit")
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	run, err := s.resolutionAudit.Run(ctx)
+	if errors.Is(err, services.ErrAuditRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestResolutionAuditEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, testutil.SeedEvents(db, []domain.Event{
+		{AggregateID: "a", AggregateType: "corruption", EventType: domain.VerificationSuccess,
+			EventData: map[string]interface{}{"reverification_of": 1, "audit": true}},
+		{AggregateID: "b", AggregateType: "corruption", EventType: domain.VerificationSuccess,
+			EventData: map[string]interface{}{"reverification_of": 2, "audit": true}},
+		{AggregateID: "c", AggregateType: "corruption", EventType: domain.VerificationFailed,
+			EventData: map[string]interface{}{"reverification_of": 3, "audit": true}},
+		// A manual reverification isn't part of the audit
+		{AggregateID: "d", AggregateType: "corruption", EventType: domain.VerificationFailed,
+			EventData: map[string]interface{}{"reverification_of": 4}},
+	}))
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+	audit := services.NewResolutionAuditService(db, eb, 30, 20)
+	defer audit.Stop()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, resolutionAudit: audit}
+	r.GET("/corruptions/audit", s.getResolutionAudit)
+	r.POST("/corruptions/audit", s.runResolutionAudit)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/corruptions/audit", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Durability services.ResolutionDurability `json:"durability"`
+		LastRun    *services.ResolutionAuditRun  `json:"last_run"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Durability.Audited)
+	require.NotNil(t, resp.Durability.Ratio)
+	assert.InDelta(t, 2.0/3, *resp.Durability.Ratio, 0.001)
+	assert.Nil(t, resp.LastRun)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/corruptions/audit", nil))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var run services.ResolutionAuditRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.Equal(t, 0, run.Candidates)
+
+	s.resolutionAudit = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/corruptions/audit", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
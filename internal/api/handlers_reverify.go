@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// reverifyCorruption checks the replacement of a resolved corruption again,
+// e.g. when the user suspects it is bad as well. The corruption is re-opened
+// rather than detected anew: the verifier publishes the outcome on it, tied to
+// the VerificationSuccess it repeats, and a failed check is retried like any
+// other failed verification.
+// POST /api/corruptions/:id/reverify
+func (s *RESTServer) reverifyCorruption(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	r, err := services.RequestReverification(ctx, s.db, s.eventBus, id, false)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		respondNotFound(c, "Corruption")
+		return
+	case errors.Is(err, services.ErrNotResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only resolved corruptions can be reverified"})
+		return
+	case errors.Is(err, services.ErrNoReplacementFile):
+		c.JSON(http.StatusConflict, gin.H{"error": "Corruption has no replacement file to verify"})
+		return
+	case err != nil:
+		logger.Errorf("Failed to request reverification of %s: %v", id, err)
+		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":           "Reverification started",
+		"corruption_id":     id,
+		"reverification_of": r.ReverificationOf,
+		"file_paths":        r.FilePaths,
+	})
+}
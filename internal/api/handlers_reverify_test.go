@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestReverifyCorruption(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now().UTC()
+	_, err = db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('fixed', '/media/tv/show.mkv', 1, 'VerificationSuccess', ?, ?),
+			('synced', '/media/movies/film.mkv', 1, 'VerificationSuccess', ?, ?),
+			('open', '/media/movies/other.mkv', 1, 'SearchStarted', ?, ?)
+	`, now, now, now, now, now, now)
+	require.NoError(t, err)
+
+	require.NoError(t, testutil.SeedEvents(db, []domain.Event{
+		{AggregateID: "fixed", AggregateType: "corruption", EventType: domain.CorruptionDetected,
+			EventData: map[string]interface{}{"file_path": "/media/tv/show.mkv"}},
+		{AggregateID: "fixed", AggregateType: "corruption", EventType: domain.FileDetected,
+			EventData: map[string]interface{}{"file_path": "/media/tv/e01.mkv", "file_paths": []string{"/media/tv/e01.mkv", "/media/tv/e02.mkv"}}},
+		{AggregateID: "fixed", AggregateType: "corruption", EventType: domain.VerificationStarted},
+	}))
+	verificationID, err := testutil.SeedEvent(db, domain.Event{
+		AggregateID: "fixed", AggregateType: "corruption", EventType: domain.VerificationSuccess,
+		EventData: map[string]interface{}{"verified_count": 2},
+	})
+	require.NoError(t, err)
+	// Resolved by the *arr sync, which records no replacement path
+	_, err = testutil.SeedEvent(db, domain.Event{
+		AggregateID: "synced", AggregateType: "corruption", EventType: domain.VerificationSuccess,
+		EventData: map[string]interface{}{"recovery_action": "arr_sync"},
+	})
+	require.NoError(t, err)
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb}
+	r.POST("/corruptions/:id/reverify", s.reverifyCorruption)
+
+	post := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/corruptions/"+id+"/reverify", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("fixed")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp struct {
+		ReverificationOf int64    `json:"reverification_of"`
+		FilePaths        []string `json:"file_paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, verificationID, resp.ReverificationOf)
+	assert.Equal(t, []string{"/media/tv/e01.mkv", "/media/tv/e02.mkv"}, resp.FilePaths)
+
+	// The request is recorded on the same corruption, tied to its verification
+	var count int
+	var reverificationOf int64
+	require.NoError(t, db.QueryRow(`
+		SELECT COUNT(*), json_extract(event_data, '$.reverification_of') FROM events
+		WHERE aggregate_id = 'fixed' AND event_type = 'ReverificationRequested'
+	`).Scan(&count, &reverificationOf))
+	assert.Equal(t, 1, count)
+	assert.Equal(t, verificationID, reverificationOf)
+
+	// Without a recorded replacement, the original path is checked
+	w = post("synced")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"/media/movies/film.mkv"}, resp.FilePaths)
+
+	assert.Equal(t, http.StatusConflict, post("open").Code)
+	assert.Equal(t, http.StatusNotFound, post("missing").Code)
+}
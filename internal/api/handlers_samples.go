@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// sampleExtractTimeout bounds an on-demand extraction, which may run ffmpeg.
+const sampleExtractTimeout = 2 * time.Minute
+
+// getCorruptionSample returns the sample extracted from a corrupt file.
+// GET /api/corruptions/:id/sample
+func (s *RESTServer) getCorruptionSample(c *gin.Context) {
+	sample, ok := s.loadCorruptionSample(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, sample)
+}
+
+// downloadCorruptionSample sends the sample extracted from a corrupt file.
+// GET /api/corruptions/:id/sample/download
+func (s *RESTServer) downloadCorruptionSample(c *gin.Context) {
+	sample, ok := s.loadCorruptionSample(c)
+	if !ok {
+		return
+	}
+	c.FileAttachment(sample.SamplePath, sample.FileName)
+}
+
+// extractCorruptionSample copies a few MB around the first error of a corrupt
+// file to the sample directory, replacing an earlier sample. The file must
+// still exist, so this works until remediation replaces it.
+// POST /api/corruptions/:id/sample
+func (s *RESTServer) extractCorruptionSample(c *gin.Context) {
+	if s.samples == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Sample extraction not available"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	var filePath sql.NullString
+	err := s.db.QueryRowContext(ctx, `SELECT file_path FROM corruption_summary WHERE corruption_id = ?`, id).Scan(&filePath)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && filePath.String == "" {
+		respondNotFound(c, "Corruption")
+		return
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	// The last check of the file tells where its first error is
+	var diag integration.CheckDiagnostics
+	entries, err := s.loadCorruptionDiagnostics(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.Deletion == nil {
+			diag = entry.CheckDiagnostics
+		}
+	}
+
+	extractCtx, cancelExtract := context.WithTimeout(c.Request.Context(), sampleExtractTimeout)
+	defer cancelExtract()
+	sample, err := s.samples.Extract(extractCtx, id, filePath.String, diag)
+	if err != nil {
+		logger.Warnf("Failed to extract a sample of %s: %v", filePath.String, err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to extract a sample - the file may have been replaced already"})
+		return
+	}
+	c.JSON(http.StatusOK, sample)
+}
+
+// loadCorruptionSample looks up the sample of the :id corruption, responding
+// with an error if there is none.
+func (s *RESTServer) loadCorruptionSample(c *gin.Context) (*services.CorruptionSample, bool) {
+	if s.samples == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Sample extraction not available"})
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	sample, err := s.samples.Sample(ctx, c.Param("id"))
+	if errors.Is(err, services.ErrSampleNotFound) {
+		respondNotFound(c, "Sample")
+		return nil, false
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return nil, false
+	}
+	return sample, true
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestCorruptionSample(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "show.mkv")
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	require.NoError(t, os.WriteFile(file, data, 0o644))
+
+	now := time.Now().UTC()
+	_, err = db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('c1', ?, 1, 'CorruptionDetected', ?, ?),
+			('replaced', ?, 1, 'VerificationSuccess', ?, ?)
+	`, file, now, now, filepath.Join(dir, "gone.mkv"), now, now)
+	require.NoError(t, err)
+	blob, err := integration.EncodeDiagnostics(integration.CheckDiagnostics{
+		Message: "Read error at pos. 2048",
+		Runs:    []integration.ToolRun{},
+	})
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO corruption_diagnostics (corruption_id, file_path, data) VALUES ('c1', ?, ?)`, file, blob)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, samples: services.NewSampleExtractor(db, services.SampleConfig{
+		Dir:           filepath.Join(dir, "samples"),
+		MaxBytes:      1024,
+		DirMaxBytes:   4096,
+		RetentionDays: 7,
+	})}
+	r.GET("/corruptions/:id/sample", s.getCorruptionSample)
+	r.POST("/corruptions/:id/sample", s.extractCorruptionSample)
+	r.GET("/corruptions/:id/sample/download", s.downloadCorruptionSample)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, do("GET", "/corruptions/c1/sample").Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/corruptions/unknown/sample").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, do("POST", "/corruptions/replaced/sample").Code)
+
+	w := do("POST", "/corruptions/c1/sample")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var sample services.CorruptionSample
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sample))
+	assert.Equal(t, services.SampleMethodBytes, sample.Method)
+	require.NotNil(t, sample.OffsetBytes)
+	assert.Equal(t, int64(1792), *sample.OffsetBytes)
+	assert.Equal(t, int64(1024), sample.SizeBytes)
+
+	w = do("GET", "/corruptions/c1/sample")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"file_name":"show.sample.mkv"`)
+
+	w = do("GET", "/corruptions/c1/sample/download")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "show.sample.mkv")
+	assert.Equal(t, data[1792:2816], w.Body.Bytes())
+}
+
+func TestCorruptionSample_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r}
+	r.GET("/corruptions/:id/sample", s.getCorruptionSample)
+	r.POST("/corruptions/:id/sample", s.extractCorruptionSample)
+
+	for _, method := range []string{"GET", "POST"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/corruptions/c1/sample", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, method)
+	}
+}
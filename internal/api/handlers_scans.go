@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,18 +15,26 @@ func (s *RESTServer) triggerScan(c *gin.Context) {
 	var req struct {
 		PathID int64 `json:"path_id"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
 	// Look up path
 	var localPath string
-	if s.db.QueryRow("SELECT local_path FROM scan_paths WHERE id = ?", req.PathID).Scan(&localPath) != nil {
+	if s.db.QueryRow("SELECT local_path FROM scan_paths WHERE id = ? AND deleted_at IS NULL", req.PathID).Scan(&localPath) != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Path not found"})
 		return
 	}
 
+	// Refuse up front rather than starting a scan the scanner will skip
+	if capability, ok := s.scanPathCapability(req.PathID); ok && !capability.Scannable {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         fmt.Sprintf("Path can't be scanned: missing detection tools (%s)", strings.Join(capability.MissingTools, ", ")),
+			"missing_tools": capability.MissingTools,
+		})
+		return
+	}
+
 	// Check if scan is already in progress
 	if s.scanner.IsPathBeingScanned(localPath) {
 		c.JSON(http.StatusConflict, gin.H{"error": "Scan already in progress for this path"})
@@ -61,7 +70,7 @@ func (s *RESTServer) getScans(c *gin.Context) {
 
 	// Get total count
 	var total int
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM scans").Scan(&total); err != nil {
+	if err := s.reader().QueryRow("SELECT COUNT(*) FROM scans").Scan(&total); err != nil {
 		logger.Errorf("Failed to query scans count: %v", err)
 		respondDatabaseError(c, err)
 		return
@@ -79,7 +88,7 @@ func (s *RESTServer) getScans(c *gin.Context) {
 	orderByClause := SafeOrderByClause(p.SortBy, p.SortOrder, allowedSortColumns, "started_at", "desc")
 	// Security: orderByClause is validated against allowlist by SafeOrderByClause
 	query := fmt.Sprintf("SELECT id, path, status, files_scanned, corruptions_found, started_at, completed_at FROM scans %s LIMIT ? OFFSET ?", orderByClause) // NOSONAR - validated ORDER BY
-	rows, err := s.db.Query(query, p.Limit, p.Offset)                                                                                                         // NOSONAR
+	rows, err := s.reader().Query(query, p.Limit, p.Offset)                                                                                                   // NOSONAR
 	if err != nil {
 		logger.Errorf("Failed to query scans: %v", err)
 		respondDatabaseError(c, err)
@@ -129,7 +138,7 @@ func (s *RESTServer) getActiveScans(c *gin.Context) {
 func (s *RESTServer) cancelScan(c *gin.Context) {
 	scanID := c.Param("scan_id")
 	if s.scanner.CancelScan(scanID) != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": ErrMsgScanNotFound})
+		respondError(c, http.StatusNotFound, ErrMsgScanNotFound)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Scan cancelled"})
@@ -200,7 +209,7 @@ func (s *RESTServer) rescanPath(c *gin.Context) {
 	var status string
 	err := s.db.QueryRow("SELECT path, status FROM scans WHERE id = ?", scanID).Scan(&path, &status)
 	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": ErrMsgScanNotFound})
+		respondError(c, http.StatusNotFound, ErrMsgScanNotFound)
 		return
 	}
 	if err != nil {
@@ -216,7 +225,7 @@ func (s *RESTServer) rescanPath(c *gin.Context) {
 
 	// Find the scan_path that matches this path (to get the path_id)
 	var pathID int64
-	err = s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ? AND enabled = 1", path).Scan(&pathID)
+	err = s.db.QueryRow("SELECT id FROM scan_paths WHERE local_path = ? AND enabled = 1 AND deleted_at IS NULL", path).Scan(&pathID)
 	if err == sql.ErrNoRows {
 		// Path might not be in scan_paths (e.g., webhook scan) - scan directly
 		go func() {
@@ -258,17 +267,18 @@ func (s *RESTServer) getScanDetails(c *gin.Context) {
 		CorruptFiles      int    `json:"corrupt_files"`
 		SkippedFiles      int    `json:"skipped_files"`
 		InaccessibleFiles int    `json:"inaccessible_files"`
+		ErrorFiles        int    `json:"error_files"`
 	}
 
 	var completedAt sql.NullString
 	var pathID sql.NullInt64
-	err := s.db.QueryRow(`
+	err := s.reader().QueryRow(`
 		SELECT id, path, path_id, status, files_scanned, corruptions_found, started_at, completed_at
 		FROM scans WHERE id = ?
 	`, scanID).Scan(&scan.ID, &scan.Path, &pathID, &scan.Status, &scan.FilesScanned, &scan.CorruptionsFound, &scan.StartedAt, &completedAt)
 
 	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": ErrMsgScanNotFound})
+		respondError(c, http.StatusNotFound, ErrMsgScanNotFound)
 		return
 	}
 	if err != nil {
@@ -282,7 +292,7 @@ func (s *RESTServer) getScanDetails(c *gin.Context) {
 	}
 
 	// Get file counts from scan_files table using single GROUP BY query (performance optimization)
-	rows, err := s.db.Query("SELECT status, COUNT(*) FROM scan_files WHERE scan_id = ? GROUP BY status", scanID)
+	rows, err := s.reader().Query("SELECT status, COUNT(*) FROM scan_files WHERE scan_id = ? GROUP BY status", scanID)
 	if err != nil {
 		logger.Debugf("Failed to query file counts: %v", err)
 	} else {
@@ -300,6 +310,8 @@ func (s *RESTServer) getScanDetails(c *gin.Context) {
 					scan.SkippedFiles = count
 				case "inaccessible":
 					scan.InaccessibleFiles = count
+				case "error":
+					scan.ErrorFiles = count
 				}
 			}
 		}
@@ -308,18 +320,28 @@ func (s *RESTServer) getScanDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, scan)
 }
 
+// scanFileStatuses are the outcomes a file can have in a scan.
+var scanFileStatuses = map[string]bool{
+	"healthy": true, "corrupt": true, "error": true, "inaccessible": true, "skipped": true,
+}
+
+// getScanFiles lists the per-file results of a scan. Results can be filtered by
+// status (one or a comma-separated list), detection tool and a file path
+// substring.
 func (s *RESTServer) getScanFiles(c *gin.Context) {
 	scanID := c.Param("scan_id")
-	statusFilter := c.DefaultQuery("status", "all") // 'all', 'healthy', 'corrupt'
+	statusFilter := c.DefaultQuery("status", "all") // 'all', or e.g. 'error,inaccessible'
+	toolFilter := c.Query("tool")
+	search := c.Query("search")
 
 	// Parse pagination (no sorting - fixed order by status DESC, file_path ASC)
 	p := ParsePagination(c, DefaultPaginationConfig())
 
 	// Verify scan exists
 	var scanExists int
-	err := s.db.QueryRow("SELECT id FROM scans WHERE id = ?", scanID).Scan(&scanExists)
+	err := s.reader().QueryRow("SELECT id FROM scans WHERE id = ?", scanID).Scan(&scanExists)
 	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": ErrMsgScanNotFound})
+		respondError(c, http.StatusNotFound, ErrMsgScanNotFound)
 		return
 	}
 
@@ -328,15 +350,30 @@ func (s *RESTServer) getScanFiles(c *gin.Context) {
 	args := []interface{}{scanID}
 
 	if statusFilter != "all" {
-		whereClause += " AND status = ?"
-		args = append(args, statusFilter)
+		statuses := strings.Split(statusFilter, ",")
+		for _, status := range statuses {
+			if !scanFileStatuses[status] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status filter: %s", status)})
+				return
+			}
+			args = append(args, status)
+		}
+		whereClause += " AND status IN (?" + strings.Repeat(", ?", len(statuses)-1) + ")"
+	}
+	if toolFilter != "" {
+		whereClause += " AND detection_tool = ?"
+		args = append(args, toolFilter)
+	}
+	if search != "" {
+		whereClause += " AND instr(file_path, ?) > 0"
+		args = append(args, search)
 	}
 
 	// Get total count
 	// Security: whereClause contains only fixed strings with ? placeholders, user values are in args
 	var total int
 	countQuery := "SELECT COUNT(*) FROM scan_files " + whereClause // NOSONAR - parameterized query
-	err = s.db.QueryRow(countQuery, args...).Scan(&total)
+	err = s.reader().QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -345,14 +382,14 @@ func (s *RESTServer) getScanFiles(c *gin.Context) {
 	// Get paginated data
 	// Security: whereClause uses ? placeholders, ORDER BY is fixed/hardcoded
 	query := fmt.Sprintf(`
-		SELECT id, file_path, status, corruption_type, error_details, file_size, scanned_at
+		SELECT id, file_path, status, corruption_type, error_details, file_size, scanned_at, duration_ms, detection_tool
 		FROM scan_files %s
 		ORDER BY status DESC, file_path ASC
 		LIMIT ? OFFSET ?
 	`, whereClause) // NOSONAR - parameterized query with fixed ORDER BY
 	args = append(args, p.Limit, p.Offset)
 
-	rows, err := s.db.Query(query, args...) // NOSONAR
+	rows, err := s.reader().Query(query, args...) // NOSONAR
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -363,14 +400,14 @@ func (s *RESTServer) getScanFiles(c *gin.Context) {
 	for rows.Next() {
 		var id int
 		var filePath, status, scannedAt string
-		var corruptionType, errorDetails sql.NullString
-		var fileSize sql.NullInt64
+		var corruptionType, errorDetails, detectionTool sql.NullString
+		var fileSize, durationMs sql.NullInt64
 
-		if rows.Scan(&id, &filePath, &status, &corruptionType, &errorDetails, &fileSize, &scannedAt) != nil {
+		if rows.Scan(&id, &filePath, &status, &corruptionType, &errorDetails, &fileSize, &scannedAt, &durationMs, &detectionTool) != nil {
 			continue
 		}
 
-		files = append(files, map[string]interface{}{
+		file := map[string]interface{}{
 			"id":              id,
 			"file_path":       filePath,
 			"status":          status,
@@ -378,7 +415,13 @@ func (s *RESTServer) getScanFiles(c *gin.Context) {
 			"error_details":   errorDetails.String,
 			"file_size":       fileSize.Int64,
 			"scanned_at":      scannedAt,
-		})
+			"duration_ms":     nil,
+			"detection_tool":  detectionTool.String,
+		}
+		if durationMs.Valid {
+			file["duration_ms"] = durationMs.Int64
+		}
+		files = append(files, file)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -395,7 +438,17 @@ func (s *RESTServer) getScanFiles(c *gin.Context) {
 
 // triggerScanAll triggers scans for all enabled paths
 func (s *RESTServer) triggerScanAll(c *gin.Context) {
-	rows, err := s.db.Query("SELECT id, local_path FROM scan_paths WHERE enabled = 1")
+	s.startPathScans(c, "")
+}
+
+// startPathScans starts scans of the enabled scan paths matching the extra
+// condition (none if empty), skipping paths already being scanned.
+func (s *RESTServer) startPathScans(c *gin.Context, condition string, args ...interface{}) {
+	query := "SELECT id, local_path FROM scan_paths WHERE enabled = 1 AND deleted_at IS NULL"
+	if condition != "" {
+		query += " AND " + condition
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
@@ -408,7 +461,7 @@ func (s *RESTServer) triggerScanAll(c *gin.Context) {
 		var pathID int64
 		var localPath string
 		if err := rows.Scan(&pathID, &localPath); err != nil {
-			logger.Errorf("Failed to scan row in triggerScanAll: %v", err)
+			logger.Errorf("Failed to scan row in startPathScans: %v", err)
 			continue
 		}
 
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scanCompareScan identifies one side of a scan comparison.
+type scanCompareScan struct {
+	ID           int    `json:"id"`
+	Path         string `json:"path"`
+	StartedAt    string `json:"started_at"`
+	CompletedAt  string `json:"completed_at"`
+	FilesScanned int    `json:"files_scanned"`
+
+	pathID sql.NullInt64
+	status string
+}
+
+// scanCompareFile is a file whose result differs, or stayed corrupt, between
+// two scans. Status and CorruptionType are from scan b (scan a for removed
+// files), the previous values from scan a.
+type scanCompareFile struct {
+	FilePath               string `json:"file_path"`
+	Status                 string `json:"status"`
+	CorruptionType         string `json:"corruption_type,omitempty"`
+	PreviousStatus         string `json:"previous_status,omitempty"` // empty for files new in scan b
+	PreviousCorruptionType string `json:"previous_corruption_type,omitempty"`
+}
+
+// scanFileResult is a file's outcome in one scan.
+type scanFileResult struct {
+	status         string
+	corruptionType string
+}
+
+// scanComparison is the response of GET /api/scans/compare.
+type scanComparison struct {
+	A              scanCompareScan   `json:"a"`
+	B              scanCompareScan   `json:"b"`
+	NewlyCorrupted []scanCompareFile `json:"newly_corrupted"`
+	NewlyHealthy   []scanCompareFile `json:"newly_healthy"`
+	StillCorrupted []scanCompareFile `json:"still_corrupted"`
+	Removed        []scanCompareFile `json:"removed"`
+}
+
+// compareScans compares the per-file results of two completed scans of the
+// same path: files corrupt in b but not in a, corrupt in a and healthy in b,
+// corrupt in both, and in a but no longer in b. Without a, b is compared with
+// the previous completed scan of its path.
+func (s *RESTServer) compareScans(c *gin.Context) {
+	bID, err := strconv.Atoi(c.Query("b"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "b must be a scan ID"})
+		return
+	}
+	b, ok := s.loadComparedScan(c, bID)
+	if !ok {
+		return
+	}
+
+	var aID int
+	if c.Query("a") == "" {
+		aID, err = s.previousCompletedScan(b)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Scan %d has no previous completed scan to compare with", bID)})
+			return
+		}
+		if err != nil {
+			respondDatabaseError(c, err)
+			return
+		}
+	} else if aID, err = strconv.Atoi(c.Query("a")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a must be a scan ID"})
+		return
+	}
+	if aID == bID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a and b must be different scans"})
+		return
+	}
+	a, ok := s.loadComparedScan(c, aID)
+	if !ok {
+		return
+	}
+
+	if !sameScanPath(a, b) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Scans are of different paths"})
+		return
+	}
+
+	aFiles, ok := s.loadScanFileResults(c, a)
+	if !ok {
+		return
+	}
+	bFiles, ok := s.loadScanFileResults(c, b)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, diffScanResults(a, b, aFiles, bFiles))
+}
+
+// loadComparedScan loads a scan, responding with an error if it does not
+// exist or has not completed.
+func (s *RESTServer) loadComparedScan(c *gin.Context, id int) (scanCompareScan, bool) {
+	scan := scanCompareScan{ID: id}
+	var completedAt sql.NullString
+	err := s.reader().QueryRow(`
+		SELECT path, path_id, status, files_scanned, started_at, completed_at
+		FROM scans WHERE id = ?
+	`, id).Scan(&scan.Path, &scan.pathID, &scan.status, &scan.FilesScanned, &scan.StartedAt, &completedAt)
+	if err == sql.ErrNoRows {
+		respondError(c, http.StatusNotFound, ErrMsgScanNotFound)
+		return scan, false
+	}
+	if err != nil {
+		respondDatabaseError(c, err)
+		return scan, false
+	}
+	scan.CompletedAt = completedAt.String
+
+	// A cancelled or interrupted scan didn't reach every file, which would
+	// show the rest as removed
+	if scan.status != "completed" {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Scan %d is %s; only completed scans can be compared", id, scan.status)})
+		return scan, false
+	}
+	return scan, true
+}
+
+// previousCompletedScan returns the ID of the last completed scan of b's path
+// that started before b.
+func (s *RESTServer) previousCompletedScan(b scanCompareScan) (int, error) {
+	column, value := "path", interface{}(b.Path)
+	if b.pathID.Valid {
+		column, value = "path_id", b.pathID.Int64
+	}
+	var id int
+	// Security: column is one of two fixed names
+	err := s.reader().QueryRow(fmt.Sprintf(`
+		SELECT id FROM scans
+		WHERE %s = ? AND status = 'completed' AND id != ? AND started_at <= ?
+		ORDER BY started_at DESC, id DESC LIMIT 1
+	`, column), value, b.ID, b.StartedAt).Scan(&id) // NOSONAR - fixed column name
+	return id, err
+}
+
+// sameScanPath reports whether two scans are of the same scan path. Scans of
+// a single file or a folder without a scan path are matched by path.
+func sameScanPath(a, b scanCompareScan) bool {
+	if a.pathID.Valid && b.pathID.Valid {
+		return a.pathID.Int64 == b.pathID.Int64
+	}
+	return a.Path == b.Path
+}
+
+// loadScanFileResults returns the per-file results of a scan, responding with
+// an error if they were pruned.
+func (s *RESTServer) loadScanFileResults(c *gin.Context, scan scanCompareScan) (map[string]scanFileResult, bool) {
+	rows, err := s.reader().Query(`
+		SELECT file_path, status, COALESCE(corruption_type, '') FROM scan_files WHERE scan_id = ?
+	`, scan.ID)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return nil, false
+	}
+	defer rows.Close()
+
+	results := make(map[string]scanFileResult)
+	for rows.Next() {
+		var path string
+		var result scanFileResult
+		if err := rows.Scan(&path, &result.status, &result.corruptionType); err != nil {
+			respondDatabaseError(c, err)
+			return nil, false
+		}
+		results[path] = result
+	}
+	if err := rows.Err(); err != nil {
+		respondDatabaseError(c, err)
+		return nil, false
+	}
+
+	if len(results) == 0 && scan.FilesScanned > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Per-file results of scan %d are no longer kept (see HEALARR_SCAN_RESULTS_PER_PATH)", scan.ID)})
+		return nil, false
+	}
+	return results, true
+}
+
+// diffScanResults sorts the files of two scans into the comparison lists.
+// Files that are healthy in both scans, or neither healthy nor corrupt in b,
+// are left out.
+func diffScanResults(a, b scanCompareScan, aFiles, bFiles map[string]scanFileResult) scanComparison {
+	cmp := scanComparison{
+		A:              a,
+		B:              b,
+		NewlyCorrupted: []scanCompareFile{},
+		NewlyHealthy:   []scanCompareFile{},
+		StillCorrupted: []scanCompareFile{},
+		Removed:        []scanCompareFile{},
+	}
+
+	for path, after := range bFiles {
+		before, existed := aFiles[path]
+		file := scanCompareFile{
+			FilePath:               path,
+			Status:                 after.status,
+			CorruptionType:         after.corruptionType,
+			PreviousStatus:         before.status,
+			PreviousCorruptionType: before.corruptionType,
+		}
+		switch {
+		case after.status == "corrupt" && existed && before.status == "corrupt":
+			cmp.StillCorrupted = append(cmp.StillCorrupted, file)
+		case after.status == "corrupt":
+			cmp.NewlyCorrupted = append(cmp.NewlyCorrupted, file)
+		case after.status == "healthy" && existed && before.status == "corrupt":
+			cmp.NewlyHealthy = append(cmp.NewlyHealthy, file)
+		}
+	}
+	for path, before := range aFiles {
+		if _, ok := bFiles[path]; !ok {
+			cmp.Removed = append(cmp.Removed, scanCompareFile{
+				FilePath:       path,
+				Status:         before.status,
+				CorruptionType: before.corruptionType,
+			})
+		}
+	}
+
+	for _, files := range [][]scanCompareFile{cmp.NewlyCorrupted, cmp.NewlyHealthy, cmp.StillCorrupted, cmp.Removed} {
+		sort.Slice(files, func(i, j int) bool { return files[i].FilePath < files[j].FilePath })
+	}
+	return cmp
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupScanCompareTest creates two completed scans of path 1 and returns a
+// router serving GET /scans/compare.
+func setupScanCompareTest(t *testing.T) (*gin.Engine, *sql.DB) {
+	t.Helper()
+
+	db, cleanup := setupScansTestDB(t)
+	t.Cleanup(cleanup)
+
+	_, err := db.Exec(`
+		INSERT INTO scans (id, path_id, path, status, started_at, files_scanned) VALUES
+			(1, 1, '/media/tv', 'completed', '2024-01-01 00:00:00', 5),
+			(2, 1, '/media/tv', 'completed', '2024-01-08 00:00:00', 4),
+			(3, 2, '/media/movies', 'completed', '2024-01-09 00:00:00', 1),
+			(4, 1, '/media/tv', 'running', '2024-01-10 00:00:00', 2),
+			(5, 1, '/media/tv', 'completed', '2023-12-01 00:00:00', 3);
+		INSERT INTO scan_files (scan_id, file_path, status, corruption_type) VALUES
+			(1, '/media/tv/fixed.mkv', 'corrupt', 'TruncatedFile'),
+			(1, '/media/tv/still.mkv', 'corrupt', 'CorruptHeader'),
+			(1, '/media/tv/broke.mkv', 'healthy', NULL),
+			(1, '/media/tv/fine.mkv', 'healthy', NULL),
+			(1, '/media/tv/deleted.mkv', 'corrupt', 'TruncatedFile'),
+			(2, '/media/tv/fixed.mkv', 'healthy', NULL),
+			(2, '/media/tv/still.mkv', 'corrupt', 'TruncatedFile'),
+			(2, '/media/tv/broke.mkv', 'corrupt', 'StreamError'),
+			(2, '/media/tv/fine.mkv', 'healthy', NULL),
+			(2, '/media/tv/new.mkv', 'corrupt', 'CorruptHeader'),
+			(3, '/media/movies/a.mkv', 'healthy', NULL);
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/scans/compare", s.compareScans)
+	return r, db
+}
+
+func TestCompareScans_Success(t *testing.T) {
+	r, _ := setupScanCompareTest(t)
+
+	for _, query := range []string{"a=1&b=2", "b=2"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/scans/compare?"+query, nil)
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var resp scanComparison
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, 1, resp.A.ID, "scan 1 is the previous completed scan of the path")
+			assert.Equal(t, 2, resp.B.ID)
+
+			assert.Equal(t, []scanCompareFile{
+				{FilePath: "/media/tv/broke.mkv", Status: "corrupt", CorruptionType: "StreamError", PreviousStatus: "healthy"},
+				{FilePath: "/media/tv/new.mkv", Status: "corrupt", CorruptionType: "CorruptHeader"},
+			}, resp.NewlyCorrupted)
+			assert.Equal(t, []scanCompareFile{
+				{FilePath: "/media/tv/fixed.mkv", Status: "healthy", PreviousStatus: "corrupt", PreviousCorruptionType: "TruncatedFile"},
+			}, resp.NewlyHealthy)
+			assert.Equal(t, []scanCompareFile{
+				{FilePath: "/media/tv/still.mkv", Status: "corrupt", CorruptionType: "TruncatedFile", PreviousStatus: "corrupt", PreviousCorruptionType: "CorruptHeader"},
+			}, resp.StillCorrupted)
+			assert.Equal(t, []scanCompareFile{
+				{FilePath: "/media/tv/deleted.mkv", Status: "corrupt", CorruptionType: "TruncatedFile"},
+			}, resp.Removed)
+		})
+	}
+}
+
+func TestCompareScans_Errors(t *testing.T) {
+	r, _ := setupScanCompareTest(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantErr  string
+	}{
+		{"missing b", "a=1", http.StatusBadRequest, "b must be a scan ID"},
+		{"invalid a", "a=x&b=2", http.StatusBadRequest, "a must be a scan ID"},
+		{"same scan", "a=2&b=2", http.StatusBadRequest, "a and b must be different scans"},
+		{"unknown scan", "a=1&b=99", http.StatusNotFound, ErrMsgScanNotFound},
+		{"different paths", "a=1&b=3", http.StatusBadRequest, "Scans are of different paths"},
+		{"running scan", "a=1&b=4", http.StatusConflict, "Scan 4 is running; only completed scans can be compared"},
+		{"pruned results", "a=5&b=2", http.StatusConflict, "Per-file results of scan 5 are no longer kept (see HEALARR_SCAN_RESULTS_PER_PATH)"},
+		{"no previous scan", "b=3", http.StatusNotFound, "Scan 3 has no previous completed scan to compare with"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/scans/compare?"+tt.query, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp["error"])
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScanSummary is the compact record of a finished scan, kept after the scan
+// record itself is pruned.
+type ScanSummary struct {
+	ScanID           int64  `json:"scan_id"`
+	Path             string `json:"path"`
+	PathID           *int64 `json:"path_id"`
+	Status           string `json:"status"`
+	FilesScanned     int    `json:"files_scanned"`
+	TotalFiles       int    `json:"total_files"`
+	CorruptionsFound int    `json:"corruptions_found"`
+	StartedAt        string `json:"started_at"`
+	CompletedAt      string `json:"completed_at,omitempty"`
+	// Detailed is true while the scan record, and with it its file results, still exists
+	Detailed bool `json:"detailed"`
+}
+
+// getScanHistory returns the summaries of finished scans, newest first. They
+// follow their own retention, so they reach further back than GET /api/scans.
+// Query parameters: path_id (one scan path) and the usual pagination.
+// GET /api/scans/history
+func (s *RESTServer) getScanHistory(c *gin.Context) {
+	p := ParsePagination(c, PaginationConfig{DefaultLimit: 50, MaxLimit: 500})
+	var pathID int64
+	if v := c.Query("path_id"); v != "" {
+		id := parseInt(v, -1)
+		if id <= 0 {
+			respondBadRequest(c, fmt.Errorf("invalid path_id"), true)
+			return
+		}
+		pathID = int64(id)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	where, args := "", []interface{}{}
+	if pathID > 0 {
+		where, args = "WHERE ss.path_id = ?", append(args, pathID)
+	}
+
+	var total int
+	if err := s.reader().QueryRowContext(ctx, "SELECT COUNT(*) FROM scan_summaries ss "+where, args...).Scan(&total); err != nil { // NOSONAR - fixed WHERE clause
+		respondDatabaseError(c, err)
+		return
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT ss.scan_id, ss.path, ss.path_id, ss.status, ss.files_scanned, ss.total_files, ss.corruptions_found,
+			COALESCE(ss.started_at, ''), COALESCE(ss.completed_at, ''), EXISTS (SELECT 1 FROM scans s WHERE s.id = ss.scan_id)
+		FROM scan_summaries ss `+where+`
+		ORDER BY ss.started_at DESC, ss.scan_id DESC
+		LIMIT ? OFFSET ?
+	`, append(args, p.Limit, p.Offset)...) // NOSONAR - fixed WHERE clause
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	defer rows.Close()
+
+	summaries := make([]ScanSummary, 0)
+	for rows.Next() {
+		var summary ScanSummary
+		var id sql.NullInt64
+		if err := rows.Scan(&summary.ScanID, &summary.Path, &id, &summary.Status, &summary.FilesScanned, &summary.TotalFiles,
+			&summary.CorruptionsFound, &summary.StartedAt, &summary.CompletedAt, &summary.Detailed); err != nil {
+			respondDatabaseError(c, err)
+			return
+		}
+		if id.Valid {
+			summary.PathID = &id.Int64
+		}
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       summaries,
+		"pagination": NewPaginationResponse(p, total),
+	})
+}
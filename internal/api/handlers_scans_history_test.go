@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetScanHistory(t *testing.T) {
+	db, cleanup := setupScansTestDB(t)
+	defer cleanup()
+
+	// Scan 1 was pruned and only its summary is left
+	_, err := db.Exec(`
+		INSERT INTO scans (id, path_id, path, status, started_at, completed_at) VALUES
+			(2, 1, '/media/tv', 'completed', '2024-01-08 00:00:00', '2024-01-08 01:00:00');
+		INSERT INTO scan_summaries (scan_id, path, path_id, status, files_scanned, total_files, corruptions_found, started_at, completed_at) VALUES
+			(1, '/media/tv', 1, 'completed', 5, 5, 2, '2023-01-01 00:00:00', '2023-01-01 01:00:00'),
+			(2, '/media/tv', 1, 'completed', 4, 4, 0, '2024-01-08 00:00:00', '2024-01-08 01:00:00'),
+			(3, '/media/movies', 2, 'error', 0, 0, 0, '2024-01-09 00:00:00', NULL);
+	`)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/scans/history", s.getScanHistory)
+
+	get := func(url string) (int, []ScanSummary) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		r.ServeHTTP(w, req)
+		var resp struct {
+			Data []ScanSummary `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	code, summaries := get("/scans/history")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, summaries, 3)
+	assert.Equal(t, int64(3), summaries[0].ScanID)
+	assert.Empty(t, summaries[0].CompletedAt)
+
+	code, summaries = get("/scans/history?path_id=1")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, summaries, 2)
+	assert.True(t, summaries[0].Detailed)
+	assert.False(t, summaries[1].Detailed)
+	assert.Equal(t, 2, summaries[1].CorruptionsFound)
+
+	code, _ = get("/scans/history?path_id=x")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
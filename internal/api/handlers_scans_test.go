@@ -69,7 +69,8 @@ func setupScansTestDB(t *testing.T) (*sql.DB, func()) {
 			local_path TEXT NOT NULL,
 			arr_path TEXT,
 			enabled BOOLEAN DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_files (
@@ -80,7 +81,21 @@ func setupScansTestDB(t *testing.T) (*sql.DB, func()) {
 			corruption_type TEXT,
 			error_details TEXT,
 			file_size INTEGER,
-			scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			duration_ms INTEGER,
+			detection_tool TEXT
+		);
+
+		CREATE TABLE scan_summaries (
+			scan_id INTEGER PRIMARY KEY,
+			path TEXT NOT NULL,
+			path_id INTEGER,
+			status TEXT NOT NULL,
+			files_scanned INTEGER NOT NULL DEFAULT 0,
+			total_files INTEGER NOT NULL DEFAULT 0,
+			corruptions_found INTEGER NOT NULL DEFAULT 0,
+			started_at TIMESTAMP,
+			completed_at TIMESTAMP
 		);
 	`
 	if _, err := db.Exec(schema); err != nil {
@@ -950,6 +965,73 @@ func TestGetScanFiles_StatusFilter(t *testing.T) {
 	}
 }
 
+func TestGetScanFiles_DrillDownFilters(t *testing.T) {
+	db, cleanup := setupScansTestDB(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	_, err := db.Exec(`INSERT INTO scans (path, status, started_at) VALUES ('/media', 'completed', datetime('now'))`)
+	if err != nil {
+		t.Fatalf("Failed to insert scan: %v", err)
+	}
+	_, err = db.Exec(`
+		INSERT INTO scan_files (scan_id, file_path, status, corruption_type, duration_ms, detection_tool) VALUES
+			(1, '/media/Movies/Heat.mkv', 'healthy', NULL, 120, 'ffprobe'),
+			(1, '/media/Movies/Alien.mkv', 'error', 'ToolFailure', 30000, 'ffprobe'),
+			(1, '/media/TV/Show.S01E01.mkv', 'inaccessible', 'MountLost', 5, NULL),
+			(1, '/media/TV/Show.S01E02.mkv', 'healthy', NULL, 80, 'mediainfo')
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert scan files: %v", err)
+	}
+
+	server := createScansTestServer(t, db, eb)
+	defer server.scanner.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/scans/:scan_id/files", server.getScanFiles)
+
+	get := func(query string) (int, []interface{}) {
+		req, _ := http.NewRequest("GET", "/scans/1/files?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response struct {
+			Data []interface{} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Data
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"status=error,inaccessible", 2},
+		{"tool=mediainfo", 1},
+		{"search=/TV/", 2},
+		{"status=healthy&search=Movies", 1},
+	}
+	for _, tt := range tests {
+		code, data := get(tt.query)
+		if code != http.StatusOK || len(data) != tt.want {
+			t.Errorf("%s: expected %d files, got %d (status %d)", tt.query, tt.want, len(data), code)
+		}
+	}
+
+	_, data := get("status=error")
+	file := data[0].(map[string]interface{})
+	if file["duration_ms"] != float64(30000) || file["detection_tool"] != "ffprobe" {
+		t.Errorf("Expected duration and tool in result, got %v", file)
+	}
+
+	if code, _ := get("status=bogus"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown status, got %d", code)
+	}
+}
+
 func TestTriggerScanAll_NoPaths(t *testing.T) {
 	db, cleanup := setupScansTestDB(t)
 	defer cleanup()
@@ -1,17 +1,26 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/services"
 )
 
+// getSchedules lists the schedules with their next run, as an instant so the
+// UI can show it in the user's timezone.
 func (s *RESTServer) getSchedules(c *gin.Context) {
 	rows, err := s.db.Query(`
-		SELECT s.id, s.scan_path_id, p.local_path, s.cron_expression, s.enabled
+		SELECT s.id, s.scan_path_id, p.local_path, s.cron_expression, s.enabled,
+			s.timezone, s.dst_gap, s.dst_overlap
 		FROM scan_schedules s
 		JOIN scan_paths p ON s.scan_path_id = p.id
+		WHERE p.deleted_at IS NULL
 	`)
 	if err != nil {
 		respondDatabaseError(c, err)
@@ -19,20 +28,34 @@ func (s *RESTServer) getSchedules(c *gin.Context) {
 	}
 	defer rows.Close()
 
+	now := time.Now()
 	schedules := make([]gin.H, 0)
 	for rows.Next() {
 		var id, scanPathID int
 		var localPath, cronExpr string
 		var enabled bool
-		if rows.Scan(&id, &scanPathID, &localPath, &cronExpr, &enabled) != nil {
+		var timing domain.ScheduleTiming
+		if rows.Scan(&id, &scanPathID, &localPath, &cronExpr, &enabled, &timing.Timezone, &timing.DSTGap, &timing.DSTOverlap) != nil {
 			continue
 		}
+		var nextRun interface{}
+		if enabled {
+			if schedule, err := services.ParseSchedule(cronExpr, timing); err == nil {
+				if next := schedule.Next(now); !next.IsZero() {
+					nextRun = next.UTC()
+				}
+			}
+		}
 		schedules = append(schedules, gin.H{
 			"id":              id,
 			"scan_path_id":    scanPathID,
 			"local_path":      localPath,
 			"cron_expression": cronExpr,
 			"enabled":         enabled,
+			"timezone":        timing.Timezone,
+			"dst_gap":         timing.DSTGap,
+			"dst_overlap":     timing.DSTOverlap,
+			"next_run_at":     nextRun,
 		})
 	}
 	if rows.Err() != nil {
@@ -46,13 +69,17 @@ func (s *RESTServer) addSchedule(c *gin.Context) {
 	var req struct {
 		ScanPathID     int    `json:"scan_path_id"`
 		CronExpression string `json:"cron_expression"`
+		domain.ScheduleTiming
+	}
+	if !bindBody(c, &req) {
+		return
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := req.ScheduleTiming.Validate(); err != nil {
+		respondBadRequest(c, err, true)
 		return
 	}
 
-	id, err := s.scheduler.AddSchedule(req.ScanPathID, req.CronExpression)
+	id, err := s.scheduler.AddSchedule(req.ScanPathID, req.CronExpression, req.ScheduleTiming)
 	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
 		return
@@ -65,7 +92,7 @@ func (s *RESTServer) deleteSchedule(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
@@ -80,19 +107,50 @@ func (s *RESTServer) deleteSchedule(c *gin.Context) {
 func (s *RESTServer) updateSchedule(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrMsgInvalidID})
+		respondError(c, http.StatusBadRequest, ErrMsgInvalidID)
 		return
 	}
 
 	var req struct {
-		CronExpression string `json:"cron_expression"`
-		Enabled        *bool  `json:"enabled"` // Pointer to distinguish between false and missing
+		CronExpression string  `json:"cron_expression"`
+		Enabled        *bool   `json:"enabled"` // Pointer to distinguish between false and missing
+		Timezone       *string `json:"timezone"`
+		DSTGap         *string `json:"dst_gap"`
+		DSTOverlap     *string `json:"dst_overlap"`
 	}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
+	// Timing fields left out keep their value
+	var timing *domain.ScheduleTiming
+	if req.Timezone != nil || req.DSTGap != nil || req.DSTOverlap != nil {
+		timing = &domain.ScheduleTiming{}
+		err := s.db.QueryRow("SELECT timezone, dst_gap, dst_overlap FROM scan_schedules WHERE id = ?", id).
+			Scan(&timing.Timezone, &timing.DSTGap, &timing.DSTOverlap)
+		if err == sql.ErrNoRows {
+			respondNotFound(c, "Schedule")
+			return
+		}
+		if err != nil {
+			respondDatabaseError(c, err)
+			return
+		}
+		if req.Timezone != nil {
+			timing.Timezone = *req.Timezone
+		}
+		if req.DSTGap != nil {
+			timing.DSTGap = *req.DSTGap
+		}
+		if req.DSTOverlap != nil {
+			timing.DSTOverlap = *req.DSTOverlap
+		}
+		if err := timing.Validate(); err != nil {
+			respondBadRequest(c, err, true)
+			return
+		}
+	}
+
 	// If enabled is missing, default to true (or maybe we should require it?)
 	// Actually, for an update, we might want to keep existing if nil.
 	// But for simplicity, let's assume the frontend sends everything or we fetch-modify-save.
@@ -106,7 +164,7 @@ func (s *RESTServer) updateSchedule(c *gin.Context) {
 		enabled = *req.Enabled
 	}
 
-	if err := s.scheduler.UpdateSchedule(id, req.CronExpression, enabled); err != nil {
+	if err := s.scheduler.UpdateSchedule(id, req.CronExpression, enabled, timing); err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
 		return
 	}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/mescon/Healarr/internal/auth"
 	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/testutil"
 )
@@ -32,6 +33,9 @@ func setupSchedulesTestDB(t *testing.T) (*sql.DB, func()) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL REFERENCES scan_paths(id) ON DELETE CASCADE,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled INTEGER DEFAULT 1,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
@@ -160,6 +164,10 @@ func TestGetSchedules_WithData(t *testing.T) {
 	assert.Equal(t, "/media/tv", response[0]["local_path"])
 	assert.Equal(t, "0 0 * * *", response[0]["cron_expression"])
 	assert.Equal(t, true, response[0]["enabled"])
+	assert.Equal(t, "", response[0]["timezone"])
+	assert.Equal(t, "run", response[0]["dst_gap"])
+	assert.Equal(t, "once", response[0]["dst_overlap"])
+	assert.NotNil(t, response[0]["next_run_at"])
 }
 
 // =============================================================================
@@ -174,7 +182,7 @@ func TestAddSchedule_Success(t *testing.T) {
 	_, pathID, _ := createTestPathWithSchedule(t, db, false)
 
 	mockScheduler := &testutil.MockSchedulerService{
-		AddScheduleFunc: func(scanPathID int, cronExpr string) (int64, error) {
+		AddScheduleFunc: func(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error) {
 			return 1, nil
 		},
 	}
@@ -208,7 +216,7 @@ func TestAddSchedule_ServiceError(t *testing.T) {
 	defer cleanup()
 
 	mockScheduler := &testutil.MockSchedulerService{
-		AddScheduleFunc: func(scanPathID int, cronExpr string) (int64, error) {
+		AddScheduleFunc: func(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error) {
 			return 0, errors.New("invalid cron expression")
 		},
 	}
@@ -261,7 +269,7 @@ func TestUpdateSchedule_Success(t *testing.T) {
 	defer cleanup()
 
 	mockScheduler := &testutil.MockSchedulerService{
-		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool) error {
+		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
 			return nil
 		},
 	}
@@ -289,13 +297,53 @@ func TestUpdateSchedule_Success(t *testing.T) {
 	assert.Equal(t, 1, mockScheduler.CallCount("UpdateSchedule"))
 }
 
+func TestUpdateSchedule_Timing(t *testing.T) {
+	db, cleanup := setupSchedulesTestDB(t)
+	defer cleanup()
+	_, _, scheduleID := createTestPathWithSchedule(t, db, true)
+
+	var captured *domain.ScheduleTiming
+	mockScheduler := &testutil.MockSchedulerService{
+		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
+			captured = timing
+			return nil
+		},
+	}
+	router, apiKey, serverCleanup := setupSchedulesTestServer(t, db, mockScheduler)
+	defer serverCleanup()
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("/api/config/schedules/%d", scheduleID), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Fields left out keep their stored value
+	w := update(`{"timezone": "Europe/Berlin", "dst_overlap": "twice"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, captured)
+	assert.Equal(t, domain.ScheduleTiming{Timezone: "Europe/Berlin", DSTGap: "run", DSTOverlap: "twice"}, *captured)
+
+	// Without timing fields the timing is left alone
+	w = update(`{"enabled": false}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, captured)
+
+	w = update(`{"timezone": "Mars/Olympus"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown timezone: Mars/Olympus")
+}
+
 func TestUpdateSchedule_DefaultEnabled(t *testing.T) {
 	db, cleanup := setupSchedulesTestDB(t)
 	defer cleanup()
 
 	var capturedEnabled bool
 	mockScheduler := &testutil.MockSchedulerService{
-		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool) error {
+		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
 			capturedEnabled = enabled
 			return nil
 		},
@@ -365,7 +413,7 @@ func TestUpdateSchedule_ServiceError(t *testing.T) {
 	defer cleanup()
 
 	mockScheduler := &testutil.MockSchedulerService{
-		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool) error {
+		UpdateScheduleFunc: func(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
 			return errors.New("schedule not found")
 		},
 	}
@@ -49,15 +49,24 @@ type SetupStatus struct {
 // handleSetupStatus returns the current setup status for the onboarding wizard
 // This endpoint is public (no auth required) to allow first-time setup
 func (s *RESTServer) handleSetupStatus(c *gin.Context) {
+	status, err := s.loadSetupStatus()
+	if err != nil {
+		logger.Errorf("Failed to check setup status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// loadSetupStatus checks what has been set up so far.
+func (s *RESTServer) loadSetupStatus() (SetupStatus, error) {
 	status := SetupStatus{}
 
 	// Check for password
 	var passwordExists int
 	err := s.db.QueryRow(sqlCountPasswordHash).Scan(&passwordExists)
 	if err != nil && err != sql.ErrNoRows {
-		logger.Errorf("Failed to check password status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
-		return
+		return status, fmt.Errorf("password: %w", err)
 	}
 	status.HasPassword = passwordExists > 0
 
@@ -65,29 +74,23 @@ func (s *RESTServer) handleSetupStatus(c *gin.Context) {
 	var apiKeyExists int
 	err = s.db.QueryRow(sqlCountAPIKey).Scan(&apiKeyExists)
 	if err != nil && err != sql.ErrNoRows {
-		logger.Errorf("Failed to check API key status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
-		return
+		return status, fmt.Errorf("API key: %w", err)
 	}
 	status.HasAPIKey = apiKeyExists > 0
 
 	// Check for configured instances
 	var instanceCount int
-	err = s.db.QueryRow("SELECT COUNT(*) FROM arr_instances").Scan(&instanceCount)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM arr_instances WHERE deleted_at IS NULL").Scan(&instanceCount)
 	if err != nil && err != sql.ErrNoRows {
-		logger.Errorf("Failed to check instances: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
-		return
+		return status, fmt.Errorf("instances: %w", err)
 	}
 	status.HasInstances = instanceCount > 0
 
 	// Check for configured scan paths
 	var pathCount int
-	err = s.db.QueryRow("SELECT COUNT(*) FROM scan_paths").Scan(&pathCount)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM scan_paths WHERE deleted_at IS NULL").Scan(&pathCount)
 	if err != nil && err != sql.ErrNoRows {
-		logger.Errorf("Failed to check scan paths: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
-		return
+		return status, fmt.Errorf("scan paths: %w", err)
 	}
 	status.HasScanPaths = pathCount > 0
 
@@ -95,16 +98,14 @@ func (s *RESTServer) handleSetupStatus(c *gin.Context) {
 	var dismissed sql.NullString
 	err = s.db.QueryRow("SELECT value FROM settings WHERE key = 'onboarding_dismissed'").Scan(&dismissed)
 	if err != nil && err != sql.ErrNoRows {
-		logger.Errorf("Failed to check onboarding dismissed status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errMsgDatabaseError})
-		return
+		return status, fmt.Errorf("onboarding dismissed: %w", err)
 	}
 	status.OnboardingDismissed = dismissed.Valid && dismissed.String == "true"
 
 	// User needs setup if they have no password (first-time setup)
 	status.NeedsSetup = !status.HasPassword
 
-	c.JSON(http.StatusOK, status)
+	return status, nil
 }
 
 // handleSetupDismiss allows power users to skip the onboarding wizard
@@ -138,6 +139,10 @@ func (s *RESTServer) handleSetupReset(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset setup wizard"})
 		return
 	}
+	// Start over rather than resume where the wizard was last left
+	if _, err := s.db.Exec("DELETE FROM settings WHERE key = ?", setupWizardSetting); err != nil {
+		logger.Warnf("Failed to clear setup wizard progress: %v", err)
+	}
 
 	logger.Infof("Setup wizard reset by user - will show on next page load")
 	c.JSON(http.StatusOK, gin.H{"message": "Setup wizard will appear on next page load"})
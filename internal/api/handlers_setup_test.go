@@ -56,14 +56,16 @@ func setupSetupTestDB(t *testing.T) (*sql.DB, string, func()) {
 			type TEXT NOT NULL,
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
-			enabled INTEGER DEFAULT 1
+			enabled INTEGER DEFAULT 1,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_paths (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			local_path TEXT NOT NULL,
 			arr_path TEXT,
-			enabled INTEGER DEFAULT 1
+			enabled INTEGER DEFAULT 1,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE events (
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// setupWizardSetting is the settings key the setup wizard's progress is
+// stored under, so the wizard resumes where it was left when the browser is
+// closed.
+const setupWizardSetting = "setup_wizard"
+
+// Setup wizard steps, in order.
+var setupWizardSteps = []string{"welcome", "password", "arr", "path", "schedule", "notifications", "complete"}
+
+// SetupWizardState is the progress of the setup wizard. It holds the IDs of
+// what the wizard created rather than form contents, so no secret is stored.
+type SetupWizardState struct {
+	Step        string `json:"step"`
+	InstanceID  int64  `json:"instance_id,omitempty"`
+	PathID      int64  `json:"path_id,omitempty"`
+	ScheduleID  int64  `json:"schedule_id,omitempty"`
+	ScanStarted bool   `json:"scan_started,omitempty"`
+}
+
+// setupWizardResponse is the stored progress, what is already set up, and the
+// step to resume at.
+type setupWizardResponse struct {
+	State    SetupWizardState `json:"state"`
+	Status   SetupStatus      `json:"status"`
+	NextStep string           `json:"next_step"`
+}
+
+// SetupRootFolder is a root folder of an *arr instance with the local path
+// suggested for its scan path.
+type SetupRootFolder struct {
+	Path           string `json:"path"`
+	FreeSpace      int64  `json:"free_space"`
+	SuggestedLocal string `json:"suggested_local_path"` // empty if no local directory matches
+	LocalExists    bool   `json:"local_exists"`
+	ScanPathID     int64  `json:"scan_path_id,omitempty"` // set if a scan path already covers it
+}
+
+func setupStepIndex(step string) int {
+	for i, s := range setupWizardSteps {
+		if s == step {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextSetupStep returns the step to resume at: the stored step, unless an
+// earlier step it depends on isn't done (e.g. the instance was deleted).
+func nextSetupStep(state SetupWizardState, status SetupStatus) string {
+	required := ""
+	switch {
+	case !status.HasPassword:
+		required = "password"
+	case !status.HasInstances:
+		required = "arr"
+	case !status.HasScanPaths:
+		required = "path"
+	}
+	if state.Step == "" {
+		if required == "" {
+			return "schedule"
+		}
+		return required
+	}
+	if required != "" && setupStepIndex(required) < setupStepIndex(state.Step) {
+		return required
+	}
+	return state.Step
+}
+
+// loadSetupWizardState returns the stored wizard progress, empty if none.
+func (s *RESTServer) loadSetupWizardState() (SetupWizardState, error) {
+	var state SetupWizardState
+	var value string
+	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", setupWizardSetting).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		logger.Warnf("Ignoring invalid setup wizard state: %v", err)
+		return SetupWizardState{}, nil
+	}
+	return state, nil
+}
+
+func (s *RESTServer) saveSetupWizardState(state SetupWizardState) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, datetime('now'))
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, setupWizardSetting, string(value))
+	return err
+}
+
+// respondSetupWizard responds with the wizard progress and the step to resume at.
+func (s *RESTServer) respondSetupWizard(c *gin.Context, state SetupWizardState) {
+	status, err := s.loadSetupStatus()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, setupWizardResponse{State: state, Status: status, NextStep: nextSetupStep(state, status)})
+}
+
+// getSetupWizard returns the stored wizard progress and the step to resume at.
+// GET /api/setup/wizard
+func (s *RESTServer) getSetupWizard(c *gin.Context) {
+	state, err := s.loadSetupWizardState()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondSetupWizard(c, state)
+}
+
+// updateSetupWizard stores the wizard progress.
+// PUT /api/setup/wizard
+func (s *RESTServer) updateSetupWizard(c *gin.Context) {
+	var state SetupWizardState
+	if !bindBody(c, &state) {
+		return
+	}
+	if setupStepIndex(state.Step) < 0 {
+		respondFieldErrors(c, FieldError{
+			Field:   "step",
+			Rule:    "oneof",
+			Param:   strings.Join(setupWizardSteps, " "),
+			Message: requestLocale(c).Sprintf("%s must be one of: %s", "step", strings.Join(setupWizardSteps, ", ")),
+		})
+		return
+	}
+	if err := s.saveSetupWizardState(state); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondSetupWizard(c, state)
+}
+
+// clearSetupWizard forgets the wizard progress, e.g. when the wizard is finished.
+// DELETE /api/setup/wizard
+func (s *RESTServer) clearSetupWizard(c *gin.Context) {
+	if _, err := s.db.Exec("DELETE FROM settings WHERE key = ?", setupWizardSetting); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Setup wizard progress cleared"})
+}
+
+// getSetupRootFolders returns the root folders of an *arr instance, each with
+// a suggested local path: the same path if it exists here, as it does when
+// Healarr and the *arr instance mount the library at the same place.
+// GET /api/setup/root-folders?instance_id=
+func (s *RESTServer) getSetupRootFolders(c *gin.Context) {
+	instanceID, err := strconv.ParseInt(c.Query("instance_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid instance ID"})
+		return
+	}
+	if s.arrClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Arr client not available"})
+		return
+	}
+
+	folders, err := s.arrClient.GetRootFolders(instanceID)
+	if err != nil {
+		logger.Errorf("Failed to get root folders for instance %d: %v", instanceID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to get root folders: %v", err)})
+		return
+	}
+
+	configured, err := s.scanPathsByArrPath()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	result := make([]SetupRootFolder, 0, len(folders))
+	for _, folder := range folders {
+		path := strings.TrimRight(folder.Path, "/")
+		if path == "" {
+			path = folder.Path
+		}
+		rf := SetupRootFolder{Path: path, FreeSpace: folder.FreeSpace, ScanPathID: configured[path]}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			rf.SuggestedLocal = path
+			rf.LocalExists = true
+		}
+		result = append(result, rf)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// scanPathsByArrPath maps the *arr path of each scan path to its ID.
+func (s *RESTServer) scanPathsByArrPath() (map[string]int64, error) {
+	rows, err := s.db.Query("SELECT id, arr_path FROM scan_paths WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	paths := make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var arrPath string
+		if err := rows.Scan(&id, &arrPath); err != nil {
+			return nil, err
+		}
+		paths[strings.TrimRight(arrPath, "/")] = id
+	}
+	return paths, rows.Err()
+}
+
+// setupFirstScan schedules the regular scans of the path the wizard created
+// and optionally starts the first scan right away.
+// POST /api/setup/first-scan
+func (s *RESTServer) setupFirstScan(c *gin.Context) {
+	var req struct {
+		PathID         int64  `json:"path_id"`
+		CronExpression string `json:"cron_expression"` // empty: no schedule
+		Timezone       string `json:"timezone"`
+		ScanNow        bool   `json:"scan_now"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+
+	var localPath string
+	if s.db.QueryRow("SELECT local_path FROM scan_paths WHERE id = ? AND deleted_at IS NULL", req.PathID).Scan(&localPath) != nil {
+		respondNotFound(c, "Path")
+		return
+	}
+
+	state, err := s.loadSetupWizardState()
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	state.PathID = req.PathID
+
+	if req.CronExpression != "" {
+		if s.scheduler == nil {
+			respondServiceUnavailable(c, "Scheduler")
+			return
+		}
+		timing := domain.ScheduleTiming{Timezone: req.Timezone}
+		if err := timing.Validate(); err != nil {
+			respondBadRequest(c, err, true)
+			return
+		}
+		id, err := s.scheduler.AddSchedule(int(req.PathID), req.CronExpression, timing)
+		if err != nil {
+			respondBadRequest(c, err, true)
+			return
+		}
+		state.ScheduleID = id
+	}
+
+	if req.ScanNow {
+		if s.scanner == nil {
+			respondServiceUnavailable(c, "Scanner")
+			return
+		}
+		if capability, ok := s.scanPathCapability(req.PathID); ok && !capability.Scannable {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":         fmt.Sprintf("Path can't be scanned: missing detection tools (%s)", strings.Join(capability.MissingTools, ", ")),
+				"missing_tools": capability.MissingTools,
+			})
+			return
+		}
+		if !s.scanner.IsPathBeingScanned(localPath) {
+			go func() {
+				if err := s.scanner.ScanPath(req.PathID, localPath); err != nil {
+					logger.Errorf("Scan failed for path %d (%s): %v", req.PathID, localPath, err)
+				}
+			}()
+		}
+		state.ScanStarted = true
+	}
+
+	if setupStepIndex(state.Step) < setupStepIndex("notifications") {
+		state.Step = "notifications"
+	}
+	if err := s.saveSetupWizardState(state); err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	s.respondSetupWizard(c, state)
+}
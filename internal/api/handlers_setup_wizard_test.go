@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func setupWizardTestServer(t *testing.T) (*RESTServer, *gin.Engine) {
+	t.Helper()
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db}
+	r.GET("/api/setup/wizard", s.getSetupWizard)
+	r.PUT("/api/setup/wizard", s.updateSetupWizard)
+	r.DELETE("/api/setup/wizard", s.clearSetupWizard)
+	r.GET("/api/setup/root-folders", s.getSetupRootFolders)
+	r.POST("/api/setup/first-scan", s.setupFirstScan)
+	return s, r
+}
+
+func doSetupWizardRequest(t *testing.T, r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeSetupWizard(t *testing.T, w *httptest.ResponseRecorder) setupWizardResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp setupWizardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestNextSetupStep(t *testing.T) {
+	done := SetupStatus{HasPassword: true, HasInstances: true, HasScanPaths: true}
+	tests := []struct {
+		name   string
+		step   string
+		status SetupStatus
+		want   string
+	}{
+		{"fresh install", "", SetupStatus{}, "password"},
+		{"no progress but configured", "", done, "schedule"},
+		{"resumes stored step", "notifications", done, "notifications"},
+		{"instance deleted since", "path", SetupStatus{HasPassword: true}, "arr"},
+		{"earlier step kept", "welcome", SetupStatus{}, "welcome"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextSetupStep(SetupWizardState{Step: tt.step}, tt.status))
+		})
+	}
+}
+
+func TestSetupWizard_PersistsProgress(t *testing.T) {
+	_, r := setupWizardTestServer(t)
+
+	resp := decodeSetupWizard(t, doSetupWizardRequest(t, r, "GET", "/api/setup/wizard", nil))
+	assert.Empty(t, resp.State.Step)
+	assert.Equal(t, "password", resp.NextStep)
+
+	w := doSetupWizardRequest(t, r, "PUT", "/api/setup/wizard", SetupWizardState{Step: "bogus"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	resp = decodeSetupWizard(t, doSetupWizardRequest(t, r, "PUT", "/api/setup/wizard", SetupWizardState{Step: "welcome"}))
+	assert.Equal(t, "welcome", resp.NextStep)
+
+	// A new page load resumes where the wizard was left
+	resp = decodeSetupWizard(t, doSetupWizardRequest(t, r, "GET", "/api/setup/wizard", nil))
+	assert.Equal(t, "welcome", resp.State.Step)
+
+	w = doSetupWizardRequest(t, r, "DELETE", "/api/setup/wizard", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = decodeSetupWizard(t, doSetupWizardRequest(t, r, "GET", "/api/setup/wizard", nil))
+	assert.Empty(t, resp.State.Step)
+}
+
+func TestSetupWizard_RootFolders(t *testing.T) {
+	s, r := setupWizardTestServer(t)
+	local := t.TempDir()
+	s.arrClient = &testutil.MockArrClient{
+		GetRootFoldersFunc: func(instanceID int64) ([]integration.RootFolder, error) {
+			if instanceID != 1 {
+				return nil, errors.New("instance not found")
+			}
+			return []integration.RootFolder{
+				{ID: 1, Path: local + "/", FreeSpace: 100},
+				{ID: 2, Path: "/does/not/exist"},
+			}, nil
+		},
+	}
+	_, err := s.db.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'key')`)
+	require.NoError(t, err)
+	_, err = s.db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (7, '/mnt/tv', '/does/not/exist', 1)`)
+	require.NoError(t, err)
+
+	w := doSetupWizardRequest(t, r, "GET", "/api/setup/root-folders?instance_id=1", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var folders []SetupRootFolder
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &folders))
+	require.Len(t, folders, 2)
+
+	assert.Equal(t, local, folders[0].Path)
+	assert.Equal(t, local, folders[0].SuggestedLocal)
+	assert.True(t, folders[0].LocalExists)
+	assert.Zero(t, folders[0].ScanPathID)
+
+	assert.Empty(t, folders[1].SuggestedLocal)
+	assert.False(t, folders[1].LocalExists)
+	assert.Equal(t, int64(7), folders[1].ScanPathID)
+
+	w = doSetupWizardRequest(t, r, "GET", "/api/setup/root-folders?instance_id=2", nil)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	w = doSetupWizardRequest(t, r, "GET", "/api/setup/root-folders?instance_id=x", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetupWizard_FirstScan(t *testing.T) {
+	s, r := setupWizardTestServer(t)
+	scheduler := &testutil.MockSchedulerService{
+		AddScheduleFunc: func(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error) {
+			return 42, nil
+		},
+	}
+	s.scheduler = scheduler
+	s.scanner = newScansMockScanner()
+	_, err := s.db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (3, '/mnt/movies', '/movies')`)
+	require.NoError(t, err)
+
+	w := doSetupWizardRequest(t, r, "POST", "/api/setup/first-scan", gin.H{"path_id": 99, "cron_expression": "0 3 * * *"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = doSetupWizardRequest(t, r, "POST", "/api/setup/first-scan", gin.H{"path_id": 3, "cron_expression": "0 3 * * *", "timezone": "Nowhere/Else"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	resp := decodeSetupWizard(t, doSetupWizardRequest(t, r, "POST", "/api/setup/first-scan",
+		gin.H{"path_id": 3, "cron_expression": "0 3 * * *", "scan_now": true}))
+	assert.Equal(t, int64(3), resp.State.PathID)
+	assert.Equal(t, int64(42), resp.State.ScheduleID)
+	assert.True(t, resp.State.ScanStarted)
+	assert.Equal(t, "notifications", resp.State.Step)
+	assert.Equal(t, 1, scheduler.CallCount("AddSchedule"))
+
+	// The result is part of the stored progress
+	resp = decodeSetupWizard(t, doSetupWizardRequest(t, r, "GET", "/api/setup/wizard", nil))
+	assert.Equal(t, int64(42), resp.State.ScheduleID)
+}
@@ -1,14 +1,66 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
 )
 
+// corruptionCounts is the number of corruptions in each group of states.
+type corruptionCounts struct {
+	resolved, orphaned, inProgress, manualIntervention, pending, failed, ignored int
+}
+
+// total excludes ignored corruptions - they're not part of active remediation.
+func (cc corruptionCounts) total() int {
+	return cc.pending + cc.resolved + cc.orphaned + cc.manualIntervention + cc.inProgress + cc.failed
+}
+
+// successRate is the percentage of finished remediations that succeeded.
+func (cc corruptionCounts) successRate() int {
+	attempts := cc.resolved + cc.orphaned
+	if attempts > 0 {
+		return (cc.resolved * 100) / attempts
+	}
+	if cc.inProgress > 0 {
+		return 0
+	}
+	return 100
+}
+
+// corruptionCountColumns selects the corruptionCounts of corruption_summary
+// rows, in the order of scanCorruptionCounts.
+const corruptionCountColumns = `
+			COUNT(DISTINCT CASE WHEN current_state = 'VerificationSuccess' THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state = 'MaxRetriesReached' THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state IN ('SearchStarted', 'SearchQueued', 'RemediationQueued', 'RemediationDeferred', 'ScheduledResearch',
+				'DownloadStarted', 'DownloadProgress', 'DownloadRejected', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
+				THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state IN ('ImportBlocked', 'ManuallyRemoved', 'BudgetApprovalRequired', 'LowConfidenceDetection', 'QualityRegression', 'AudioTrackMissing', 'DeletionPlanChanged') THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionDetected' THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached' THEN corruption_id END),
+			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionIgnored' THEN corruption_id END)`
+
+// scanCorruptionCounts reads the columns of corruptionCountColumns into cc,
+// and any columns selected before them into dest.
+func scanCorruptionCounts(row interface{ Scan(...interface{}) error }, cc *corruptionCounts, dest ...interface{}) error {
+	return row.Scan(append(dest, &cc.resolved, &cc.orphaned, &cc.inProgress, &cc.manualIntervention, &cc.pending, &cc.failed, &cc.ignored)...)
+}
+
+// loadCorruptionCounts counts corruptions by current state.
+func (s *RESTServer) loadCorruptionCounts(ctx context.Context) (corruptionCounts, error) {
+	var cc corruptionCounts
+	row := s.reader().QueryRowContext(ctx, `SELECT `+corruptionCountColumns+` FROM corruption_summary`)
+	err := scanCorruptionCounts(row, &cc)
+	return cc, err
+}
+
 func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	// MediaTypeStats holds corruption statistics for a specific media type
 	type MediaTypeStats struct {
@@ -23,25 +75,26 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	}
 
 	var stats struct {
-		TotalCorruptions              int      `json:"total_corruptions"`
-		PendingCorruptions            int      `json:"pending_corruptions"` // Just CorruptionDetected state
-		ResolvedCorruptions           int      `json:"resolved_corruptions"`
-		OrphanedCorruptions           int      `json:"orphaned_corruptions"`
-		IgnoredCorruptions            int      `json:"ignored_corruptions"`
-		InProgressCorruptions         int      `json:"in_progress_corruptions"`
-		FailedCorruptions             int      `json:"failed_corruptions"`              // *Failed states (not MaxRetriesReached)
-		ManualInterventionCorruptions int      `json:"manual_intervention_corruptions"` // ImportBlocked or ManuallyRemoved
-		SuccessfulRemediations        int      `json:"successful_remediations"`
-		ActiveScans                   int      `json:"active_scans"`
-		TotalScans                    int      `json:"total_scans"`
-		FilesScannedToday             int      `json:"files_scanned_today"`
-		FilesScannedWeek              int      `json:"files_scanned_week"`
-		CorruptionsToday              int      `json:"corruptions_today"`
-		SuccessRate                   int      `json:"success_rate"`
-		LastScanTime                  *string  `json:"last_scan_time,omitempty"` // Timestamp of most recent completed scan
-		LastScanPath                  *string  `json:"last_scan_path,omitempty"` // Path that was scanned
-		LastScanID                    *int     `json:"last_scan_id,omitempty"`   // ID for linking to scan details
-		Warnings                      []string `json:"warnings,omitempty"`       // Query failures (partial results returned)
+		TotalCorruptions              int                 `json:"total_corruptions"`
+		PendingCorruptions            int                 `json:"pending_corruptions"` // Just CorruptionDetected state
+		ResolvedCorruptions           int                 `json:"resolved_corruptions"`
+		OrphanedCorruptions           int                 `json:"orphaned_corruptions"`
+		IgnoredCorruptions            int                 `json:"ignored_corruptions"`
+		InProgressCorruptions         int                 `json:"in_progress_corruptions"`
+		FailedCorruptions             int                 `json:"failed_corruptions"`              // *Failed states (not MaxRetriesReached)
+		ManualInterventionCorruptions int                 `json:"manual_intervention_corruptions"` // ImportBlocked, ManuallyRemoved, BudgetApprovalRequired, LowConfidenceDetection, QualityRegression, AudioTrackMissing or DeletionPlanChanged
+		SuccessfulRemediations        int                 `json:"successful_remediations"`
+		ActiveScans                   int                 `json:"active_scans"`
+		TotalScans                    int                 `json:"total_scans"`
+		FilesScannedToday             int                 `json:"files_scanned_today"`
+		FilesScannedWeek              int                 `json:"files_scanned_week"`
+		CorruptionsToday              int                 `json:"corruptions_today"`
+		SuccessRate                   int                 `json:"success_rate"`
+		LastScanTime                  *string             `json:"last_scan_time,omitempty"` // Timestamp of most recent completed scan
+		LastScanPath                  *string             `json:"last_scan_path,omitempty"` // Path that was scanned
+		LastScanID                    *int                `json:"last_scan_id,omitempty"`   // ID for linking to scan details
+		Warnings                      []string            `json:"warnings,omitempty"`       // Query failures (partial results returned)
+		SLA                           *services.SLAStatus `json:"sla,omitempty"`            // Set when a resolution SLA is configured
 		// Media type breakdown
 		VideoStats *MediaTypeStats `json:"video_stats,omitempty"`
 		AudioStats *MediaTypeStats `json:"audio_stats,omitempty"`
@@ -50,37 +103,25 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	var warnings []string
 
 	// Query 1: All corruption stats in a single query (was 5 separate queries)
-	var resolved, orphaned, inProgress, manualIntervention, pending, failed, ignored int
-	if err := s.db.QueryRow(`
-		SELECT
-			COUNT(DISTINCT CASE WHEN current_state = 'VerificationSuccess' THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state = 'MaxRetriesReached' THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state IN ('SearchStarted', 'SearchQueued', 'RemediationQueued',
-				'DownloadStarted', 'DownloadProgress', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
-				THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state IN ('ImportBlocked', 'ManuallyRemoved') THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionDetected' THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached' THEN corruption_id END),
-			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionIgnored' THEN corruption_id END)
-		FROM corruption_status
-	`).Scan(&resolved, &orphaned, &inProgress, &manualIntervention, &pending, &failed, &ignored); err != nil {
+	counts, err := s.loadCorruptionCounts(c.Request.Context())
+	if err != nil {
 		warnings = append(warnings, "failed to query corruption stats")
 		logger.Debugf("Failed to query corruption stats: %v", err)
 	}
+	resolved, orphaned, inProgress := counts.resolved, counts.orphaned, counts.inProgress
 
 	stats.ResolvedCorruptions = resolved
 	stats.OrphanedCorruptions = orphaned
 	stats.InProgressCorruptions = inProgress
-	stats.ManualInterventionCorruptions = manualIntervention
-	stats.PendingCorruptions = pending
-	stats.FailedCorruptions = failed
-	stats.IgnoredCorruptions = ignored
+	stats.ManualInterventionCorruptions = counts.manualIntervention
+	stats.PendingCorruptions = counts.pending
+	stats.FailedCorruptions = counts.failed
+	stats.IgnoredCorruptions = counts.ignored
 	stats.SuccessfulRemediations = resolved
-	// Total excludes ignored - they're not part of active remediation
-	stats.TotalCorruptions = pending + resolved + orphaned + manualIntervention + inProgress + failed
+	stats.TotalCorruptions = counts.total()
 
 	// Query 2: All scan stats in a single query (was 4 separate queries)
-	if err := s.db.QueryRow(`
+	if err := s.reader().QueryRow(`
 		SELECT
 			COUNT(CASE WHEN status = 'running' THEN 1 END),
 			COUNT(*),
@@ -93,12 +134,12 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	}
 
 	// Query 3: Corruptions detected today (needs events table)
-	if err := s.db.QueryRow(`
+	if err := s.reader().QueryRow(`
 		SELECT COUNT(*) FROM events e
 		WHERE e.event_type = 'CorruptionDetected'
 		AND substr(e.created_at, 1, 10) = date('now')
 		AND NOT EXISTS (
-			SELECT 1 FROM corruption_status cs
+			SELECT 1 FROM corruption_summary cs
 			WHERE cs.corruption_id = e.aggregate_id
 			AND cs.current_state = 'CorruptionIgnored'
 		)
@@ -110,7 +151,7 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	// Query 4: Last completed scan info
 	var lastScanID sql.NullInt64
 	var lastScanTime, lastScanPath sql.NullString
-	if err := s.db.QueryRow(`
+	if err := s.reader().QueryRow(`
 		SELECT id, completed_at, path
 		FROM scans
 		WHERE status = 'completed' AND completed_at IS NOT NULL
@@ -131,28 +172,31 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 		stats.LastScanPath = &lastScanPath.String
 	}
 
-	// Calculate success rate
-	totalAttempts := resolved + orphaned
-	if totalAttempts > 0 {
-		stats.SuccessRate = (resolved * 100) / totalAttempts
-	} else if inProgress > 0 {
-		stats.SuccessRate = 0
-	} else {
-		stats.SuccessRate = 100
+	// Query 5: Resolution SLA breaches
+	if s.healthMonitor != nil {
+		if sla, err := s.healthMonitor.GetSLAStatus(c.Request.Context()); err != nil {
+			warnings = append(warnings, "failed to query SLA status")
+			logger.Debugf("Failed to query SLA status: %v", err)
+		} else {
+			stats.SLA = sla
+		}
 	}
 
-	// Query 5: Media type breakdown (video vs audio)
+	// Calculate success rate
+	stats.SuccessRate = counts.successRate()
+
+	// Query 6: Media type breakdown (video vs audio)
 	// This uses the corruption_summary table which has the media_type column
 	videoStats := &MediaTypeStats{}
 	audioStats := &MediaTypeStats{}
 
 	// Try to get video stats
-	if err := s.db.QueryRow(`
+	if err := s.reader().QueryRow(`
 		SELECT
 			COUNT(DISTINCT CASE WHEN current_state = 'VerificationSuccess' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state = 'MaxRetriesReached' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state IN ('SearchStarted', 'SearchQueued', 'RemediationQueued',
-				'DownloadStarted', 'DownloadProgress', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
+				'DownloadStarted', 'DownloadProgress', 'DownloadRejected', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
 				THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionDetected' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached' THEN corruption_id END)
@@ -174,12 +218,12 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 	}
 
 	// Try to get audio stats
-	if err := s.db.QueryRow(`
+	if err := s.reader().QueryRow(`
 		SELECT
 			COUNT(DISTINCT CASE WHEN current_state = 'VerificationSuccess' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state = 'MaxRetriesReached' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state IN ('SearchStarted', 'SearchQueued', 'RemediationQueued',
-				'DownloadStarted', 'DownloadProgress', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
+				'DownloadStarted', 'DownloadProgress', 'DownloadRejected', 'SearchCompleted', 'DeletionCompleted', 'FileDetected')
 				THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state = 'CorruptionDetected' THEN corruption_id END),
 			COUNT(DISTINCT CASE WHEN current_state LIKE '%Failed' AND current_state != 'MaxRetriesReached' THEN corruption_id END)
@@ -210,7 +254,7 @@ func (s *RESTServer) getDashboardStats(c *gin.Context) {
 func (s *RESTServer) getStatsHistory(c *gin.Context) {
 	// Group by date for the last 30 days
 	// Use substr to extract YYYY-MM-DD from Go's time.Time format
-	rows, err := s.db.Query(`
+	rows, err := s.reader().Query(`
 		SELECT substr(created_at, 1, 10) as date, COUNT(*) as count
 		FROM events
 		WHERE event_type = 'CorruptionDetected'
@@ -243,9 +287,66 @@ func (s *RESTServer) getStatsHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// getStatsBandwidth returns the data remediation deleted and downloaded per day
+// (group=day, last `days` days, default 30) or per month (group=month, last 12
+// months), plus this month's usage against the monthly data budget.
+func (s *RESTServer) getStatsBandwidth(c *gin.Context) {
+	group := c.DefaultQuery("group", "day")
+	if group != "day" && group != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group must be 'day' or 'month'"})
+		return
+	}
+
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, -parseInt(c.DefaultQuery("days", "30"), 30))
+	if group == "month" {
+		since = time.Date(now.Year(), now.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	usage, err := services.LoadBandwidthUsage(ctx, s.reader(), group == "month", since)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	var budgetBytes int64
+	if s.remediator != nil {
+		budgetBytes = s.remediator.DataBudget()
+	}
+	budget, err := services.LoadDataBudgetStatus(ctx, s.reader(), now, budgetBytes)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	var deletedBytes, downloadedBytes int64
+	var deletions, replacements int
+	for _, u := range usage {
+		deletedBytes += u.DeletedBytes
+		downloadedBytes += u.DownloadedBytes
+		deletions += u.Deletions
+		replacements += u.Replacements
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group":   group,
+		"periods": usage,
+		"total": gin.H{
+			"deleted_bytes":    deletedBytes,
+			"downloaded_bytes": downloadedBytes,
+			"deletions":        deletions,
+			"replacements":     replacements,
+		},
+		"current_month": budget,
+	})
+}
+
 func (s *RESTServer) getStatsTypes(c *gin.Context) {
 	// Group by corruption type
-	rows, err := s.db.Query(`
+	rows, err := s.reader().Query(`
 		SELECT json_extract(event_data, '$.corruption_type') as type, COUNT(*) as count
 		FROM events
 		WHERE event_type = 'CorruptionDetected'
@@ -298,15 +399,24 @@ type PathHealth struct {
 // getPathHealth returns health status for each configured scan path.
 // GET /api/stats/path-health
 func (s *RESTServer) getPathHealth(c *gin.Context) {
-	// Get all configured scan paths
-	pathRows, err := s.db.Query(`SELECT id, local_path, enabled FROM scan_paths ORDER BY local_path`)
+	paths, err := s.collectPathHealth(c.Request.Context())
 	if err != nil {
 		respondDatabaseError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, paths)
+}
+
+// collectPathHealth returns the last scan, corruption counts and health status of each scan path.
+func (s *RESTServer) collectPathHealth(ctx context.Context) ([]PathHealth, error) {
+	// Get all configured scan paths
+	pathRows, err := s.reader().QueryContext(ctx, `SELECT id, local_path, enabled FROM scan_paths WHERE deleted_at IS NULL ORDER BY local_path`)
+	if err != nil {
+		return nil, err
+	}
 	defer pathRows.Close()
 
-	var paths []PathHealth
+	paths := make([]PathHealth, 0)
 	for pathRows.Next() {
 		var p PathHealth
 		if pathRows.Scan(&p.PathID, &p.LocalPath, &p.Enabled) != nil {
@@ -315,13 +425,7 @@ func (s *RESTServer) getPathHealth(c *gin.Context) {
 		paths = append(paths, p)
 	}
 	if err := pathRows.Err(); err != nil {
-		respondDatabaseError(c, err)
-		return
-	}
-
-	if len(paths) == 0 {
-		c.JSON(http.StatusOK, []PathHealth{})
-		return
+		return nil, err
 	}
 
 	// For each path, get last scan and corruption stats
@@ -331,7 +435,7 @@ func (s *RESTServer) getPathHealth(c *gin.Context) {
 		// Get last completed scan for this path
 		var lastScanID sql.NullInt64
 		var lastScanTime sql.NullString
-		err := s.db.QueryRow(`
+		err := s.reader().QueryRowContext(ctx, `
 			SELECT id, completed_at
 			FROM scans
 			WHERE path_id = ? AND status = 'completed' AND completed_at IS NOT NULL
@@ -350,12 +454,12 @@ func (s *RESTServer) getPathHealth(c *gin.Context) {
 
 		// Get corruption counts for this path
 		var active, total, resolved int
-		err = s.db.QueryRow(`
+		err = s.reader().QueryRowContext(ctx, `
 			SELECT
 				COUNT(DISTINCT CASE WHEN current_state NOT IN ('VerificationSuccess', 'MaxRetriesReached', 'CorruptionIgnored') THEN corruption_id END),
 				COUNT(DISTINCT corruption_id),
 				COUNT(DISTINCT CASE WHEN current_state = 'VerificationSuccess' THEN corruption_id END)
-			FROM corruption_status
+			FROM corruption_summary
 			WHERE path_id = ?
 		`, pathID).Scan(&active, &total, &resolved)
 		if err == nil {
@@ -368,7 +472,7 @@ func (s *RESTServer) getPathHealth(c *gin.Context) {
 		paths[i].Status = determinePathHealthStatus(paths[i])
 	}
 
-	c.JSON(http.StatusOK, paths)
+	return paths, nil
 }
 
 // determinePathHealthStatus calculates the health status based on corruption counts and scan recency.
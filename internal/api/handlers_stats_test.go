@@ -17,6 +17,7 @@ import (
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 // mockHealthChecker and mockPathMapper are defined in handlers_health_test.go
@@ -72,7 +73,8 @@ func setupStatsTestDB(t *testing.T) (*sql.DB, func()) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			local_path TEXT NOT NULL UNIQUE,
 			arr_path TEXT,
-			enabled BOOLEAN DEFAULT 1
+			enabled BOOLEAN DEFAULT 1,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE VIEW corruption_status AS
@@ -134,6 +136,11 @@ func setupStatsTestDB(t *testing.T) (*sql.DB, func()) {
 		os.RemoveAll(tmpDir)
 		t.Fatalf("Failed to create schema: %v", err)
 	}
+	if err := testutil.CreateCorruptionSummary(db); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create corruption summary: %v", err)
+	}
 
 	cleanup := func() {
 		db.Close()
@@ -953,10 +960,10 @@ func TestGetDashboardStats_DBError_WithWarnings(t *testing.T) {
 	server := createStatsTestServer(t, db, eb)
 	defer server.scanner.Shutdown()
 
-	// Drop the corruption_status view to force errors in the corruption stats query
-	_, err := db.Exec("DROP VIEW corruption_status")
+	// Drop the corruption_summary table to force errors in the corruption stats query
+	_, err := db.Exec("DROP TABLE corruption_summary")
 	if err != nil {
-		t.Fatalf("Failed to drop view: %v", err)
+		t.Fatalf("Failed to drop table: %v", err)
 	}
 
 	gin.SetMode(gin.TestMode)
@@ -1308,3 +1315,115 @@ func TestGetPathHealth_WithScansAndCorruptions(t *testing.T) {
 		t.Error("Expected /healthy to have last_scan_id")
 	}
 }
+
+func TestGetDashboardStats_ResolutionSLA(t *testing.T) {
+	db, cleanup := setupStatsTestDB(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	now := time.Now().UTC()
+	for id, age := range map[string]time.Duration{"sla-overdue": 72 * time.Hour, "sla-fresh": time.Hour} {
+		if _, err := db.Exec(`INSERT INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at)
+			VALUES (?, ?, 'SearchStarted', ?, ?)`, id, "/test/"+id+".mkv", now.Add(-age), now); err != nil {
+			t.Fatalf("Failed to seed corruption: %v", err)
+		}
+	}
+
+	server := createStatsTestServer(t, db, eb)
+	defer server.scanner.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/stats/dashboard", server.getDashboardStats)
+
+	get := func() map[string]interface{} {
+		req, _ := http.NewRequest("GET", "/stats/dashboard", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var stats map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return stats
+	}
+
+	// No health monitor, no SLA section
+	if _, ok := get()["sla"]; ok {
+		t.Error("Expected no sla section without a health monitor")
+	}
+
+	server.healthMonitor = services.NewHealthMonitorService(db, eb, nil, 24*time.Hour)
+	server.healthMonitor.SetResolutionSLA(48 * time.Hour)
+
+	sla, ok := get()["sla"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected sla section in dashboard stats")
+	}
+	if sla["sla_hours"].(float64) != 48 {
+		t.Errorf("sla_hours = %v, want 48", sla["sla_hours"])
+	}
+	if sla["breached_unresolved"].(float64) != 1 {
+		t.Errorf("breached_unresolved = %v, want 1", sla["breached_unresolved"])
+	}
+	if sla["breaches_total"].(float64) != 0 {
+		t.Errorf("breaches_total = %v, want 0", sla["breaches_total"])
+	}
+}
+
+func TestGetStatsBandwidth(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().UTC()
+	seedStatsEvent(t, db, "c1", domain.DeletionCompleted, map[string]interface{}{"media_id": 1, "file_size": 4000}, now)
+	seedStatsEvent(t, db, "c1", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 5000}, now)
+	seedStatsEvent(t, db, "old", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 9000}, now.AddDate(0, 0, -90))
+
+	remediator := services.NewRemediatorService(testutil.NewMockEventBus(), &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	remediator.SetDataBudget(20000)
+	server := &RESTServer{db: db, remediator: remediator}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/stats/bandwidth", server.getStatsBandwidth)
+
+	req, _ := http.NewRequest("GET", "/stats/bandwidth", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Periods []services.BandwidthUsage `json:"periods"`
+		Total   struct {
+			DeletedBytes    int64 `json:"deleted_bytes"`
+			DownloadedBytes int64 `json:"downloaded_bytes"`
+		} `json:"total"`
+		CurrentMonth services.DataBudgetStatus `json:"current_month"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(resp.Periods) != 1 || resp.Periods[0].Period != now.Format("2006-01-02") {
+		t.Errorf("Expected only today within the default 30 days, got %+v", resp.Periods)
+	}
+	if resp.Total.DeletedBytes != 4000 || resp.Total.DownloadedBytes != 5000 {
+		t.Errorf("Unexpected totals: %+v", resp.Total)
+	}
+	if resp.CurrentMonth.BudgetBytes != 20000 || resp.CurrentMonth.DownloadedBytes != 5000 || resp.CurrentMonth.Exceeded {
+		t.Errorf("Unexpected current month: %+v", resp.CurrentMonth)
+	}
+
+	req, _ = http.NewRequest("GET", "/stats/bandwidth?group=week", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown group, got %d", w.Code)
+	}
+}
@@ -0,0 +1,319 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// supportBundleHistoryLimit caps the *arr history entries included in a support bundle.
+const supportBundleHistoryLimit = 25
+
+// bundleFile is one file in a support or diagnostics archive. Data is written
+// as is when it is a []byte and as indented JSON otherwise.
+type bundleFile struct {
+	name string
+	data interface{}
+}
+
+// supportBundleEvent is one event in a support bundle's event stream.
+type supportBundleEvent struct {
+	ID            int64                  `json:"id"`
+	AggregateType string                 `json:"aggregate_type"`
+	EventType     string                 `json:"event_type"`
+	EventData     map[string]interface{} `json:"event_data"`
+	EventVersion  int                    `json:"event_version"`
+	CreatedAt     string                 `json:"created_at"`
+}
+
+// supportBundleInstance is an *arr instance without its API key.
+type supportBundleInstance struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// supportBundleArrState is what the *arr instance currently reports for the media.
+// Lookups that fail are recorded in Errors rather than failing the bundle.
+type supportBundleArrState struct {
+	Instance     *supportBundleInstance        `json:"instance,omitempty"`
+	ArrPath      string                        `json:"arr_path,omitempty"`
+	MediaID      int64                         `json:"media_id,omitempty"`
+	MediaDetails *integration.MediaDetails     `json:"media_details,omitempty"`
+	Queue        []integration.QueueItemInfo   `json:"queue,omitempty"`
+	History      []integration.HistoryItemInfo `json:"history,omitempty"`
+	Errors       map[string]string             `json:"errors,omitempty"`
+}
+
+// createCorruptionSupportBundle builds a zip with everything needed to debug one
+// corruption: its event stream, checker diagnostics, what the *arr instance
+// reports for the media and the relevant configuration. API keys are redacted.
+func (s *RESTServer) createCorruptionSupportBundle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), dbTimeout)
+	defer cancel()
+
+	id := c.Param("id")
+	events, err := s.loadSupportBundleEvents(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if len(events) == 0 {
+		respondNotFound(c, "Corruption")
+		return
+	}
+
+	diagnostics, err := s.loadCorruptionDiagnostics(ctx, id)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+
+	filePath, pathID, mediaID := corruptionContextFromEvents(events)
+	scanPath := s.loadScanPathExcerpt(ctx, pathID)
+	var instanceID int64
+	if v, ok := scanPath["arr_instance_id"].(int64); ok {
+		instanceID = v
+	}
+
+	files := []bundleFile{
+		{"manifest.json", gin.H{
+			"corruption_id":   id,
+			"file_path":       filePath,
+			"healarr_version": config.Version,
+			"generated_at":    time.Now().UTC().Format(time.RFC3339),
+		}},
+		{"events.json", events},
+		{"diagnostics.json", diagnostics},
+		{"config.json", gin.H{
+			"system":    newSystemConfigInfo(config.Get()),
+			"scan_path": scanPath,
+		}},
+		{"arr.json", s.collectSupportBundleArrState(filePath, instanceID, mediaID)},
+	}
+
+	archive, err := buildRedactedZip(files, s.knownSecrets())
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to build support bundle", err)
+		return
+	}
+
+	logger.Infof("Created support bundle for corruption %s (%d events)", id, len(events))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=healarr_support_%s.zip", id))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// loadSupportBundleEvents returns the full event stream of a corruption.
+func (s *RESTServer) loadSupportBundleEvents(ctx context.Context, id string) ([]supportBundleEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, aggregate_type, event_type, event_data, event_version, created_at
+		FROM events WHERE aggregate_id = ? ORDER BY id ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]supportBundleEvent, 0)
+	for rows.Next() {
+		var e supportBundleEvent
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.AggregateType, &e.EventType, &data, &e.EventVersion, &e.CreatedAt); err != nil {
+			continue
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &e.EventData); err != nil {
+				logger.Debugf("Failed to unmarshal event data for event %d: %v", e.ID, err)
+			}
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// corruptionContextFromEvents returns the file path, scan path ID and the most
+// recent *arr media ID recorded in a corruption's events.
+func corruptionContextFromEvents(events []supportBundleEvent) (filePath string, pathID, mediaID int64) {
+	for _, e := range events {
+		if v, ok := extractJSONString(e.EventData, "file_path"); ok && filePath == "" {
+			filePath = v
+		}
+		if v, ok := extractJSONInt64(e.EventData, "path_id"); ok && pathID == 0 {
+			pathID = v
+		}
+		if v, ok := extractJSONInt64(e.EventData, "media_id"); ok && v > 0 {
+			mediaID = v
+		}
+	}
+	return filePath, pathID, mediaID
+}
+
+// loadScanPathExcerpt returns the scan path configuration as column/value pairs,
+// or nil if the path is unknown.
+func (s *RESTServer) loadScanPathExcerpt(ctx context.Context, pathID int64) map[string]interface{} {
+	if pathID == 0 {
+		return nil
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT * FROM scan_paths WHERE id = ?`, pathID)
+	if err != nil {
+		logger.Debugf("Failed to load scan path %d for support bundle: %v", pathID, err)
+		return nil
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil || !rows.Next() {
+		return nil
+	}
+	values := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil
+	}
+
+	excerpt := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		if b, ok := values[i].([]byte); ok {
+			excerpt[col] = string(b)
+		} else {
+			excerpt[col] = values[i]
+		}
+	}
+	return excerpt
+}
+
+// collectSupportBundleArrState asks the *arr instance what it knows about the media.
+func (s *RESTServer) collectSupportBundleArrState(filePath string, instanceID, mediaID int64) supportBundleArrState {
+	state := supportBundleArrState{Errors: make(map[string]string)}
+	if s.arrClient == nil {
+		state.Errors["arr"] = "arr client not available"
+		return state
+	}
+
+	if instanceID > 0 {
+		if inst, err := s.arrClient.GetInstanceByID(instanceID); err != nil {
+			state.Errors["instance"] = err.Error()
+		} else if inst != nil {
+			state.Instance = &supportBundleInstance{ID: inst.ID, Name: inst.Name, Type: inst.Type, URL: sanitizeURL(inst.URL)}
+		}
+	}
+
+	if s.pathMapper != nil && filePath != "" {
+		if arrPath, err := s.pathMapper.ToArrPath(filePath); err != nil {
+			state.Errors["path_mapping"] = err.Error()
+		} else {
+			state.ArrPath = arrPath
+		}
+	}
+	if state.ArrPath == "" {
+		return state
+	}
+
+	state.MediaID = mediaID
+	if state.MediaID == 0 {
+		id, err := s.arrClient.FindMediaByPath(state.ArrPath)
+		if err != nil {
+			state.Errors["media_lookup"] = err.Error()
+			return state
+		}
+		state.MediaID = id
+	}
+
+	if details, err := s.arrClient.GetMediaDetails(state.MediaID, state.ArrPath); err != nil {
+		state.Errors["media_details"] = err.Error()
+	} else {
+		state.MediaDetails = details
+	}
+	if queue, err := s.arrClient.FindQueueItemsByMediaIDForPath(state.ArrPath, state.MediaID); err != nil {
+		state.Errors["queue"] = err.Error()
+	} else {
+		state.Queue = queue
+	}
+	if history, err := s.arrClient.GetRecentHistoryForMediaByPath(state.ArrPath, state.MediaID, supportBundleHistoryLimit); err != nil {
+		state.Errors["history"] = err.Error()
+	} else {
+		state.History = history
+	}
+	return state
+}
+
+// buildRedactedZip writes the files into a zip archive, redacting secrets from each.
+func buildRedactedZip(files []bundleFile, secrets []string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		data, ok := f.data.([]byte)
+		if !ok {
+			var err error
+			if data, err = json.MarshalIndent(f.data, "", "  "); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", f.name, err)
+			}
+		}
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(redactSecrets(data, secrets)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// knownSecrets returns the API keys of all configured *arr instances.
+func (s *RESTServer) knownSecrets() []string {
+	if s.arrClient == nil {
+		return nil
+	}
+	instances, err := s.arrClient.GetAllInstances()
+	if err != nil {
+		logger.Debugf("Failed to load instances for support bundle redaction: %v", err)
+		return nil
+	}
+	secrets := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		if inst != nil && inst.APIKey != "" {
+			secrets = append(secrets, inst.APIKey)
+		}
+	}
+	return secrets
+}
+
+// redactSecrets masks known secrets and credentials redact.Text recognizes.
+func redactSecrets(data []byte, secrets []string) []byte {
+	out := string(data)
+	for _, secret := range secrets {
+		out = strings.ReplaceAll(out, secret, redact.Mask)
+	}
+	return []byte(redact.Text(out))
+}
+
+// sanitizeURL drops credentials and query parameters from a URL.
+func sanitizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redact.Mask
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"bufio"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,7 +14,10 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/db"
 	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
 )
 
 // SystemInfo contains runtime environment information
@@ -37,10 +42,12 @@ type SystemConfigInfo struct {
 	BasePath             string  `json:"base_path"`
 	BasePathSource       string  `json:"base_path_source"`
 	LogLevel             string  `json:"log_level"`
+	Locale               string  `json:"locale"`
 	DataDir              string  `json:"data_dir"`
 	DatabasePath         string  `json:"database_path"`
 	LogDir               string  `json:"log_dir"`
 	DryRunMode           bool    `json:"dry_run_mode"`
+	OfflineMode          bool    `json:"offline_mode"`
 	RetentionDays        int     `json:"retention_days"`
 	DefaultMaxRetries    int     `json:"default_max_retries"`
 	VerificationTimeout  string  `json:"verification_timeout"`
@@ -66,6 +73,29 @@ type SystemLinks struct {
 	Discussions string `json:"discussions"`
 }
 
+// newSystemConfigInfo extracts the non-secret configuration shown in system info
+// and included in support bundles.
+func newSystemConfigInfo(cfg *config.Config) SystemConfigInfo {
+	return SystemConfigInfo{
+		Port:                 cfg.Port,
+		BasePath:             cfg.BasePath,
+		BasePathSource:       cfg.BasePathSource,
+		LogLevel:             cfg.LogLevel,
+		Locale:               cfg.Locale,
+		DataDir:              cfg.DataDir,
+		DatabasePath:         cfg.DatabasePath,
+		LogDir:               cfg.LogDir,
+		DryRunMode:           cfg.DryRunMode,
+		OfflineMode:          cfg.OfflineMode,
+		RetentionDays:        cfg.RetentionDays,
+		DefaultMaxRetries:    cfg.DefaultMaxRetries,
+		VerificationTimeout:  cfg.VerificationTimeout.String(),
+		VerificationInterval: cfg.VerificationInterval.String(),
+		ArrRateLimitRPS:      cfg.ArrRateLimitRPS,
+		ArrRateLimitBurst:    cfg.ArrRateLimitBurst,
+	}
+}
+
 // handleSystemInfo returns runtime environment information
 func (s *RESTServer) handleSystemInfo(c *gin.Context) {
 	cfg := config.Get()
@@ -99,23 +129,8 @@ func (s *RESTServer) handleSystemInfo(c *gin.Context) {
 		Uptime:      uptimeStr,
 		UptimeSecs:  int64(uptime.Seconds()),
 		StartedAt:   s.startTime,
-		Config: SystemConfigInfo{
-			Port:                 cfg.Port,
-			BasePath:             cfg.BasePath,
-			BasePathSource:       cfg.BasePathSource,
-			LogLevel:             cfg.LogLevel,
-			DataDir:              cfg.DataDir,
-			DatabasePath:         cfg.DatabasePath,
-			LogDir:               cfg.LogDir,
-			DryRunMode:           cfg.DryRunMode,
-			RetentionDays:        cfg.RetentionDays,
-			DefaultMaxRetries:    cfg.DefaultMaxRetries,
-			VerificationTimeout:  cfg.VerificationTimeout.String(),
-			VerificationInterval: cfg.VerificationInterval.String(),
-			ArrRateLimitRPS:      cfg.ArrRateLimitRPS,
-			ArrRateLimitBurst:    cfg.ArrRateLimitBurst,
-		},
-		Tools: s.toolChecker.GetToolStatus(),
+		Config:      newSystemConfigInfo(cfg),
+		Tools:       s.toolChecker.GetToolStatus(),
 		Links: SystemLinks{
 			GitHub:      "https://github.com/mescon/Healarr",
 			Issues:      "https://github.com/mescon/Healarr/issues",
@@ -133,6 +148,171 @@ func (s *RESTServer) handleSystemInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, info)
 }
 
+// SystemStatus reports the outcome of startup checks that need the user's attention.
+type SystemStatus struct {
+	Version          string                     `json:"version"`
+	StartedAt        time.Time                  `json:"started_at"`
+	ArrKeyEncryption *db.ArrKeyEncryptionReport `json:"arr_key_encryption"`
+	// UnscannablePaths lists enabled scan paths whose detection tools are missing
+	UnscannablePaths []ScanPathCapability `json:"unscannable_paths"`
+	// Pause is the global automation pause (see POST /api/system/pause)
+	Pause services.SystemPauseStatus `json:"pause"`
+	// StartupWarnings counts the warnings of the last startup (see GET /api/system/startup-report)
+	StartupWarnings int      `json:"startup_warnings"`
+	Warnings        []string `json:"warnings"`
+}
+
+// ScanPathCapability is the detection tool availability of one scan path.
+type ScanPathCapability struct {
+	PathID    int64  `json:"path_id"`
+	LocalPath string `json:"local_path"`
+	integration.ScanCapability
+}
+
+// scanPathDetectionConfig builds the detection config whose tools a scan path
+// needs. Like the scanner, NULL or invalid fallbacks use the built-in chain.
+func scanPathDetectionConfig(method, mode string, fallbacksJSON sql.NullString) integration.DetectionConfig {
+	cfg := integration.DetectionConfig{
+		Method: integration.DetectionMethod(method),
+		Mode:   mode,
+	}
+	if !fallbacksJSON.Valid || fallbacksJSON.String == "" ||
+		json.Unmarshal([]byte(fallbacksJSON.String), &cfg.Fallbacks) != nil {
+		cfg.Fallbacks = integration.DefaultFallbacksFor(cfg.Method)
+	}
+	return cfg
+}
+
+// scanPathCapability checks a single scan path against the installed detection
+// tools. ok is false when no tool checker is configured or the lookup fails.
+func (s *RESTServer) scanPathCapability(pathID int64) (integration.ScanCapability, bool) {
+	if s.toolChecker == nil {
+		return integration.ScanCapability{}, false
+	}
+	var method, mode string
+	var fallbacks sql.NullString
+	if err := s.db.QueryRow("SELECT detection_method, detection_mode, detection_fallbacks FROM scan_paths WHERE id = ? AND deleted_at IS NULL", pathID).
+		Scan(&method, &mode, &fallbacks); err != nil {
+		return integration.ScanCapability{}, false
+	}
+	return s.toolChecker.CapabilityFor(scanPathDetectionConfig(method, mode, fallbacks)), true
+}
+
+// scanPathCapabilities checks every enabled scan path against the installed
+// detection tools and returns those that can't run their primary method.
+func (s *RESTServer) scanPathCapabilities() ([]ScanPathCapability, error) {
+	rows, err := s.db.Query("SELECT id, local_path, detection_method, detection_mode, detection_fallbacks FROM scan_paths WHERE enabled = 1 AND deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []ScanPathCapability
+	for rows.Next() {
+		var p ScanPathCapability
+		var method, mode string
+		var fallbacks sql.NullString
+		if err := rows.Scan(&p.PathID, &p.LocalPath, &method, &mode, &fallbacks); err != nil {
+			return nil, err
+		}
+		p.ScanCapability = s.toolChecker.CapabilityFor(scanPathDetectionConfig(method, mode, fallbacks))
+		if !p.Scannable || p.Degraded {
+			result = append(result, p)
+		}
+	}
+	return result, rows.Err()
+}
+
+// handleSystemStatus returns the results of startup checks, such as the
+// encryption of *arr API keys.
+func (s *RESTServer) handleSystemStatus(c *gin.Context) {
+	status := SystemStatus{
+		Version:          config.Version,
+		StartedAt:        s.startTime,
+		ArrKeyEncryption: s.arrKeyEncryption,
+		UnscannablePaths: []ScanPathCapability{},
+		Pause:            s.systemPause.Status(),
+		Warnings:         []string{},
+	}
+	if status.Pause.Paused {
+		warning := "Automation is paused: remediation, verification polling and scheduled scans are on hold"
+		if status.Pause.DetectionOnly {
+			warning = "Automation is paused: remediation and verification polling are on hold, scheduled scans continue"
+		}
+		if status.Pause.Reason != "" {
+			warning += " (" + status.Pause.Reason + ")"
+		}
+		status.Warnings = append(status.Warnings, warning)
+	}
+	if report := s.arrKeyEncryption; report != nil {
+		if len(report.Undecryptable) > 0 {
+			status.Warnings = append(status.Warnings, fmt.Sprintf(
+				"API keys of %s can't be decrypted: check HEALARR_ENCRYPTION_KEY or re-enter the keys",
+				strings.Join(report.Undecryptable, ", ")))
+		}
+		if len(report.Plaintext) > 0 {
+			status.Warnings = append(status.Warnings, fmt.Sprintf(
+				"API keys of %s are stored unencrypted: set HEALARR_ENCRYPTION_KEY to encrypt them",
+				strings.Join(report.Plaintext, ", ")))
+		}
+	}
+	if s.db != nil {
+		startup, err := services.LoadStartupReport(s.db)
+		if err != nil {
+			logger.Errorf("Failed to load startup report: %v", err)
+		} else if startup != nil && len(startup.Warnings) > 0 {
+			status.StartupWarnings = len(startup.Warnings)
+			status.Warnings = append(status.Warnings, fmt.Sprintf(
+				"Healarr started with %d warning(s): see the startup report", len(startup.Warnings)))
+		}
+	}
+	if s.toolChecker != nil && s.db != nil {
+		paths, err := s.scanPathCapabilities()
+		if err != nil {
+			logger.Errorf("Failed to check scan path capabilities: %v", err)
+		}
+		for _, p := range paths {
+			if !p.Scannable {
+				status.UnscannablePaths = append(status.UnscannablePaths, p)
+				status.Warnings = append(status.Warnings, fmt.Sprintf(
+					"Scan path %s can't be scanned, scans are skipped: install %s",
+					p.LocalPath, strings.Join(p.MissingTools, ", ")))
+			} else {
+				status.Warnings = append(status.Warnings, fmt.Sprintf(
+					"Scan path %s is scanned with a fallback detector: %s is missing",
+					p.LocalPath, strings.Join(p.MissingTools, ", ")))
+			}
+		}
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// handleStartupReport returns the report of the last startup: versions, the
+// configuration in effect, the result of initializing each component, and
+// the warnings and errors logged while starting.
+func (s *RESTServer) handleStartupReport(c *gin.Context) {
+	report, err := services.LoadStartupReport(s.db)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if report == nil {
+		respondNotFound(c, "Startup report")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// handleSystemTools returns availability, path and version of each detection tool.
+// Results are cached from startup; pass ?refresh=true to re-check the binaries,
+// e.g. after installing a tool into /config/tools.
+func (s *RESTServer) handleSystemTools(c *gin.Context) {
+	if c.Query("refresh") == "true" {
+		s.toolChecker.RefreshTools()
+	}
+	c.JSON(http.StatusOK, gin.H{"tools": s.toolChecker.GetToolStatus()})
+}
+
 // isDockerEnvironment checks if we're running inside a Docker container
 func isDockerEnvironment() bool {
 	// Check for .dockerenv file
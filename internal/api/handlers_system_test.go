@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,7 +13,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/db"
 	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 func TestHandleSystemInfo(t *testing.T) {
@@ -93,6 +98,181 @@ func TestHandleSystemInfo(t *testing.T) {
 	assert.True(t, ffprobe.Required, "ffprobe should be marked as required")
 }
 
+func TestHandleSystemTools(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Tool checker that has never been populated - refresh must probe the binaries
+	s := &RESTServer{
+		router:      gin.New(),
+		toolChecker: integration.NewToolCheckerWithPaths("/nonexistent/ffprobe", "ffmpeg", "mediainfo", "HandBrakeCLI"),
+	}
+	s.router.GET("/api/system/tools", s.handleSystemTools)
+
+	req, _ := http.NewRequest("GET", "/api/system/tools", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var cached struct {
+		Tools map[string]*integration.ToolStatus `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cached))
+	assert.Empty(t, cached.Tools, "without refresh the cached (empty) status is returned")
+
+	req, _ = http.NewRequest("GET", "/api/system/tools?refresh=true", nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var refreshed struct {
+		Tools map[string]*integration.ToolStatus `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	for _, name := range []string{"ffprobe", "ffmpeg", "mediainfo", "handbrake"} {
+		assert.Contains(t, refreshed.Tools, name)
+	}
+	assert.False(t, refreshed.Tools["ffprobe"].Available, "ffprobe at a nonexistent path must be unavailable")
+}
+
+func TestHandleSystemStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &RESTServer{
+		router:    gin.New(),
+		startTime: time.Now(),
+		arrKeyEncryption: &db.ArrKeyEncryptionReport{
+			Instances:     3,
+			Migrated:      []string{},
+			Plaintext:     []string{"Sonarr"},
+			Undecryptable: []string{"Radarr"},
+		},
+	}
+	s.router.GET("/api/system/status", s.handleSystemStatus)
+
+	req, _ := http.NewRequest("GET", "/api/system/status", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status SystemStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, config.Version, status.Version)
+	require.NotNil(t, status.ArrKeyEncryption)
+	assert.Equal(t, []string{"Radarr"}, status.ArrKeyEncryption.Undecryptable)
+	require.Len(t, status.Warnings, 2)
+	assert.Contains(t, status.Warnings[0], "Radarr")
+	assert.Contains(t, status.Warnings[1], "Sonarr")
+
+	// Without a report (check didn't run) there is nothing to warn about
+	s.arrKeyEncryption = nil
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Empty(t, status.Warnings)
+}
+
+func TestHandleSystemStatus_UnscannablePaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer testDB.Close()
+
+	// ffprobe with its mediainfo fallback, and mediainfo with no fallbacks
+	require.NoError(t, testutil.SeedScanPath(testDB, 1, "/media/movies", "/movies", false, false))
+	require.NoError(t, testutil.SeedScanPath(testDB, 2, "/media/tv", "/tv", false, false))
+	_, err = testDB.Exec(`UPDATE scan_paths SET detection_method = 'mediainfo', detection_fallbacks = '[]' WHERE id = 2`)
+	require.NoError(t, err)
+
+	// No detection tool is installed, so neither path can be scanned
+	tools := integration.NewToolCheckerWithPaths("/nonexistent/ffprobe", "/nonexistent/ffmpeg", "/nonexistent/mediainfo", "/nonexistent/HandBrakeCLI")
+	tools.CheckAllTools()
+
+	s := &RESTServer{
+		router:      gin.New(),
+		db:          testDB,
+		startTime:   time.Now(),
+		toolChecker: tools,
+	}
+	s.router.GET("/api/system/status", s.handleSystemStatus)
+	s.router.POST("/api/scans", s.triggerScan)
+
+	req, _ := http.NewRequest("GET", "/api/system/status", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status SystemStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.Len(t, status.UnscannablePaths, 2)
+	assert.Equal(t, int64(1), status.UnscannablePaths[0].PathID)
+	assert.ElementsMatch(t, []string{"ffprobe", "mediainfo"}, status.UnscannablePaths[0].MissingTools)
+	assert.Equal(t, "/media/tv", status.UnscannablePaths[1].LocalPath)
+	require.Len(t, status.Warnings, 2)
+	assert.Contains(t, status.Warnings[0], "/media/movies")
+
+	// Manual scans of an unscannable path are refused
+	req, _ = http.NewRequest("POST", "/api/scans", strings.NewReader(`{"path_id": 2}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "mediainfo")
+
+	// A zero-byte check needs no tools at all
+	_, err = testDB.Exec(`UPDATE scan_paths SET detection_method = 'zero_byte', detection_fallbacks = NULL`)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/system/status", nil)
+	s.router.ServeHTTP(w, req)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Empty(t, status.UnscannablePaths)
+	assert.Empty(t, status.Warnings)
+}
+
+func TestHandleStartupReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testDB, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer testDB.Close()
+
+	s := &RESTServer{router: gin.New(), db: testDB, startTime: time.Now()}
+	s.router.GET("/api/system/startup-report", s.handleStartupReport)
+	s.router.GET("/api/system/status", s.handleSystemStatus)
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Nothing stored before the first startup completes
+	assert.Equal(t, http.StatusNotFound, get("/api/system/startup-report").Code)
+
+	report := services.NewStartupReport("v1.2.3")
+	report.Setting("Port", "%s", "3090")
+	report.Component("Event Bus", "")
+	logger.Warnf("Failed to open database read pool")
+	require.NoError(t, report.Complete(testDB))
+
+	w := get("/api/system/startup-report")
+	require.Equal(t, http.StatusOK, w.Code)
+	var got services.StartupReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "v1.2.3", got.Version)
+	assert.Equal(t, []services.StartupSetting{{Name: "Port", Value: "3090"}}, got.Config)
+	assert.Equal(t, []string{"Failed to open database read pool"}, got.Warnings)
+
+	// The status points to the report
+	var status SystemStatus
+	require.NoError(t, json.Unmarshal(get("/api/system/status").Body.Bytes(), &status))
+	assert.Equal(t, 1, status.StartupWarnings)
+	require.Len(t, status.Warnings, 1)
+	assert.Contains(t, status.Warnings[0], "startup report")
+}
+
 func TestHandleSystemInfo_UptimeFormatting(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/services"
+)
+
+// telemetryRequest is the body of PUT /api/system/telemetry.
+type telemetryRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// getTelemetry returns whether anonymous usage statistics are sent.
+// GET /api/system/telemetry
+func (s *RESTServer) getTelemetry(c *gin.Context) {
+	c.JSON(http.StatusOK, s.telemetry.Status())
+}
+
+// previewTelemetry returns exactly the report that would be sent now, whether
+// the user opted in or not.
+// GET /api/system/telemetry/preview
+func (s *RESTServer) previewTelemetry(c *gin.Context) {
+	report, err := s.telemetry.Preview(c.Request.Context())
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// setTelemetry opts in to or out of anonymous usage statistics. Opting in is
+// refused while HEALARR_TELEMETRY_DISABLED or offline mode is set.
+// PUT /api/system/telemetry
+func (s *RESTServer) setTelemetry(c *gin.Context) {
+	var req telemetryRequest
+	if !bindBody(c, &req) {
+		return
+	}
+
+	status, err := s.telemetry.SetEnabled(*req.Enabled)
+	if errors.Is(err, services.ErrTelemetryDisabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrMsgInternalError, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/services"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestTelemetryEndpoints(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func(cfg services.TelemetryConfig) *gin.Engine {
+		r := gin.New()
+		s := &RESTServer{router: r, db: db, telemetry: services.NewTelemetryService(db, cfg, nil)}
+		r.GET("/system/telemetry", s.getTelemetry)
+		r.PUT("/system/telemetry", s.setTelemetry)
+		r.GET("/system/telemetry/preview", s.previewTelemetry)
+		return r
+	}
+	do := func(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	r := newRouter(services.TelemetryConfig{Version: "v1.0.0"})
+
+	// Off until the user opts in
+	w := do(r, "GET", "/system/telemetry", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var status services.TelemetryStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.False(t, status.Enabled)
+
+	w = do(r, "GET", "/system/telemetry/preview", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report services.TelemetryReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "v1.0.0", report.Version)
+	assert.Equal(t, "0", report.LibraryFiles)
+
+	w = do(r, "PUT", "/system/telemetry", `{"enabled": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.True(t, status.Enabled)
+
+	w = do(r, "PUT", "/system/telemetry", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The hard off switch refuses opting in
+	off := newRouter(services.TelemetryConfig{Disabled: true})
+	w = do(off, "PUT", "/system/telemetry", `{"enabled": true}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = do(off, "PUT", "/system/telemetry", `{"enabled": false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/mescon/Healarr/internal/auth"
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
 )
 
 // setupTestDB creates a temporary database with schema for testing
@@ -69,7 +70,19 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
 			enabled INTEGER DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			tags TEXT NOT NULL DEFAULT '[]',
+			daily_request_budget INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
+		);
+
+		CREATE TABLE arr_request_usage (
+			instance_id INTEGER NOT NULL,
+			day TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 0,
+			deferred INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (instance_id, day)
 		);
 
 		CREATE TABLE scan_paths (
@@ -77,8 +90,36 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 			local_path TEXT NOT NULL,
 			arr_path TEXT NOT NULL,
 			arr_instance_id INTEGER NOT NULL REFERENCES arr_instances(id) ON DELETE CASCADE,
+			arr_instance_tag TEXT NOT NULL DEFAULT '',
+			quality_pin TEXT NOT NULL DEFAULT 'off',
+			priority INTEGER NOT NULL DEFAULT 0,
+			research_interval_days INTEGER NOT NULL DEFAULT 0,
+			research_period_days INTEGER NOT NULL DEFAULT 90,
+			protocol_preference TEXT NOT NULL DEFAULT 'any',
+			remote_url TEXT NOT NULL DEFAULT '',
+			remote_username TEXT NOT NULL DEFAULT '',
+			remote_password TEXT NOT NULL DEFAULT '',
+			remote_host_key_fingerprint TEXT NOT NULL DEFAULT '',
+			remote_requests_per_minute INTEGER NOT NULL DEFAULT 60,
+			media_extensions TEXT NOT NULL DEFAULT '',
+			min_file_size_mb INTEGER NOT NULL DEFAULT 0,
+			duration_check TEXT NOT NULL DEFAULT 'off',
+			symlink_policy TEXT NOT NULL DEFAULT 'skip',
+			rollout_daily_limit INTEGER NOT NULL DEFAULT 0,
+			rollout_days INTEGER NOT NULL DEFAULT 0,
+			min_confidence INTEGER NOT NULL DEFAULT 0,
 			enabled INTEGER DEFAULT 1,
 			auto_remediate INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
+		);
+
+		CREATE TABLE protected_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			file_path TEXT,
+			arr_instance_id INTEGER,
+			media_id INTEGER,
+			note TEXT NOT NULL DEFAULT '',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
 
@@ -90,6 +131,11 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 		os.RemoveAll(tmpDir)
 		t.Fatalf("Failed to create schema: %v", err)
 	}
+	if err := testutil.CreateCorruptionSummary(db); err != nil {
+		db.Close()
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to create corruption summary: %v", err)
+	}
 
 	cleanup := func() {
 		db.Close()
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// getTestMode returns the forced failures, fake *arr setting and synthetic files.
+// GET /api/test-mode
+func (s *RESTServer) getTestMode(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"stages": integration.FaultStages,
+		"state":  s.faults.State(),
+	})
+}
+
+// resetTestMode clears all forced failures and synthetic files.
+// POST /api/test-mode/reset
+func (s *RESTServer) resetTestMode(c *gin.Context) {
+	s.faults.Reset()
+	c.JSON(http.StatusOK, s.faults.State())
+}
+
+// setTestModeFakeArr turns fake *arr responses for synthetic files on or off.
+// PUT /api/test-mode/fake-arr
+func (s *RESTServer) setTestModeFakeArr(c *gin.Context) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+	s.faults.SetFakeArr(req.Enabled)
+	c.JSON(http.StatusOK, s.faults.State())
+}
+
+// setTestModeFailure forces a pipeline stage to fail, for the next count calls
+// or until cleared when count is 0.
+// PUT /api/test-mode/failures/:stage
+func (s *RESTServer) setTestModeFailure(c *gin.Context) {
+	var req struct {
+		Message string `json:"message"`
+		Count   int    `json:"count"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+	if err := s.faults.SetFailure(c.Param("stage"), req.Message, req.Count); err != nil {
+		if errors.Is(err, integration.ErrUnknownStage) {
+			respondBadRequest(c, err, true)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	logger.Warnf("Test mode: forcing %s stage failures", c.Param("stage"))
+	c.JSON(http.StatusOK, s.faults.State())
+}
+
+// clearTestModeFailure stops forcing a pipeline stage to fail.
+// DELETE /api/test-mode/failures/:stage
+func (s *RESTServer) clearTestModeFailure(c *gin.Context) {
+	s.faults.ClearFailure(c.Param("stage"))
+	c.JSON(http.StatusOK, s.faults.State())
+}
+
+// injectTestCorruption publishes a synthetic CorruptionDetected event for an
+// existing file inside a scan path, which then runs through the normal
+// remediation pipeline. With fake *arr responses the file is never deleted, and
+// its "replacement" passes verification unless that stage is forced to fail.
+// POST /api/test-mode/corruptions
+func (s *RESTServer) injectTestCorruption(c *gin.Context) {
+	var req struct {
+		FilePath       string `json:"file_path" binding:"required"`
+		CorruptionType string `json:"corruption_type"`
+		ErrorDetails   string `json:"error_details"`
+		AutoRemediate  *bool  `json:"auto_remediate"`
+		DryRun         *bool  `json:"dry_run"`
+	}
+	if !bindBody(c, &req) {
+		return
+	}
+	info, err := os.Stat(req.FilePath)
+	if err != nil || info.IsDir() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file_path must be an existing file (it is only used as a placeholder)"})
+		return
+	}
+
+	arrPath, err := s.pathMapper.ToArrPath(req.FilePath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file_path is not inside a configured scan path"})
+		return
+	}
+	pathID, autoRemediate, dryRun, err := s.findScanPathForFile(req.FilePath)
+	if err != nil {
+		respondDatabaseError(c, err)
+		return
+	}
+	if pathID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file_path is not inside a configured scan path"})
+		return
+	}
+	if req.AutoRemediate != nil {
+		autoRemediate = *req.AutoRemediate
+	}
+	if req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+	if req.CorruptionType == "" {
+		req.CorruptionType = integration.ErrorTypeCorruptStream
+	}
+	if req.ErrorDetails == "" {
+		req.ErrorDetails = "Synthetic corruption injected by test mode"
+	}
+
+	file := s.faults.AddSyntheticFile(req.FilePath, arrPath)
+	corruptionID := uuid.New().String()
+	if err := s.eventBus.PublishWithRetry(domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   corruptionID,
+		EventType:     domain.CorruptionDetected,
+		EventData: map[string]interface{}{
+			"file_path":       req.FilePath,
+			"file_size":       info.Size(),
+			"path_id":         pathID,
+			"corruption_type": req.CorruptionType,
+			"error_details":   req.ErrorDetails,
+			"source":          "test_mode",
+			"auto_remediate":  autoRemediate,
+			"dry_run":         dryRun,
+		},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish event"})
+		logger.Errorf("Test mode: failed to publish synthetic corruption for %s: %v", req.FilePath, err)
+		return
+	}
+
+	logger.Infof("Test mode: injected synthetic corruption %s for %s", corruptionID, req.FilePath)
+	c.JSON(http.StatusCreated, gin.H{
+		"corruption_id": corruptionID,
+		"media_id":      file.MediaID,
+		"arr_path":      arrPath,
+	})
+}
+
+// findScanPathForFile returns the ID and remediation settings of the most
+// specific scan path containing filePath, or ID 0 if there is none.
+func (s *RESTServer) findScanPathForFile(filePath string) (id int64, autoRemediate, dryRun bool, err error) {
+	rows, err := s.db.Query("SELECT id, local_path, auto_remediate, dry_run FROM scan_paths WHERE deleted_at IS NULL")
+	if err != nil {
+		return 0, false, false, err
+	}
+	defer rows.Close()
+
+	bestLen := 0
+	for rows.Next() {
+		var pathID int64
+		var localPath string
+		var auto, dry bool
+		if err := rows.Scan(&pathID, &localPath, &auto, &dry); err != nil {
+			return 0, false, false, err
+		}
+		root := strings.TrimSuffix(localPath, "/")
+		if filePath != root && !strings.HasPrefix(filePath, root+"/") {
+			continue
+		}
+		if len(root) >= bestLen {
+			bestLen = len(root)
+			id, autoRemediate, dryRun = pathID, auto, dry
+		}
+	}
+	return id, autoRemediate, dryRun, rows.Err()
+}
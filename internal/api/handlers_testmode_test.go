@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestTestMode_Handlers(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mediaDir := t.TempDir()
+	filePath := filepath.Join(mediaDir, "Movie (2020)", "Movie (2020).mkv")
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0750))
+	require.NoError(t, os.WriteFile(filePath, []byte("placeholder"), 0600))
+
+	_, err = db.Exec("INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, auto_remediate) VALUES (1, ?, '/movies', 1, 1)", mediaDir)
+	require.NoError(t, err)
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	faults := integration.NewFaultInjector()
+	pathMapper := &testutil.MockPathMapper{
+		ToArrPathFunc: func(localPath string) (string, error) {
+			return "/movies" + localPath[len(mediaDir):], nil
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb, pathMapper: pathMapper, faults: faults}
+	r.GET("/test-mode", s.getTestMode)
+	r.POST("/test-mode/reset", s.resetTestMode)
+	r.PUT("/test-mode/fake-arr", s.setTestModeFakeArr)
+	r.PUT("/test-mode/failures/:stage", s.setTestModeFailure)
+	r.DELETE("/test-mode/failures/:stage", s.clearTestModeFailure)
+	r.POST("/test-mode/corruptions", s.injectTestCorruption)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Forced failures
+	assert.Equal(t, http.StatusBadRequest, do("PUT", "/test-mode/failures/transcode", `{}`).Code)
+	w := do("PUT", "/test-mode/failures/delete", `{"message":"disk full","count":1}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "disk full", faults.State().Failures[integration.StageDelete].Message)
+	require.Equal(t, http.StatusOK, do("DELETE", "/test-mode/failures/delete", "").Code)
+	assert.Empty(t, faults.State().Failures)
+
+	require.Equal(t, http.StatusOK, do("PUT", "/test-mode/fake-arr", `{"enabled":false}`).Code)
+	assert.False(t, faults.State().FakeArr)
+
+	// Synthetic corruptions
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/test-mode/corruptions", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/test-mode/corruptions", `{"file_path":"/nonexistent/file.mkv"}`).Code)
+	outside := filepath.Join(t.TempDir(), "outside.mkv")
+	require.NoError(t, os.WriteFile(outside, []byte("placeholder"), 0600))
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/test-mode/corruptions", `{"file_path":"`+outside+`"}`).Code)
+
+	w = do("POST", "/test-mode/corruptions", `{"file_path":"`+filePath+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp struct {
+		CorruptionID string `json:"corruption_id"`
+		MediaID      int64  `json:"media_id"`
+		ArrPath      string `json:"arr_path"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "/movies/Movie (2020)/Movie (2020).mkv", resp.ArrPath)
+
+	events, err := testutil.GetEventsByAggregate(db, resp.CorruptionID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.CorruptionDetected, events[0].EventType)
+	assert.Equal(t, "test_mode", events[0].EventData["source"])
+	assert.Equal(t, true, events[0].EventData["auto_remediate"])
+	assert.Equal(t, float64(1), events[0].EventData["path_id"])
+
+	w = do("GET", "/test-mode", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var state struct {
+		State integration.FaultInjectorState `json:"state"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &state))
+	require.Len(t, state.State.SyntheticFiles, 1)
+	assert.Equal(t, resp.MediaID, state.State.SyntheticFiles[0].MediaID)
+
+	require.Equal(t, http.StatusOK, do("POST", "/test-mode/reset", "").Code)
+	assert.Empty(t, faults.State().SyntheticFiles)
+}
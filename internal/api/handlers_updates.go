@@ -37,6 +37,8 @@ type UpdateCheckResponse struct {
 	DownloadURLs       map[string]string  `json:"download_urls"`
 	DockerPullCmd      string             `json:"docker_pull_cmd"`
 	UpdateInstructions UpdateInstructions `json:"update_instructions"`
+	// ChecksDisabled is set in offline mode, where GitHub is never contacted
+	ChecksDisabled bool `json:"checks_disabled,omitempty"`
 }
 
 // UpdateInstructions provides platform-specific upgrade guidance
@@ -56,6 +58,16 @@ const (
 func (s *RESTServer) handleCheckUpdate(c *gin.Context) {
 	currentVersion := config.Version
 
+	if config.Get().OfflineMode {
+		c.JSON(http.StatusOK, UpdateCheckResponse{
+			CurrentVersion:  currentVersion,
+			LatestVersion:   currentVersion,
+			UpdateAvailable: false,
+			ChecksDisabled:  true,
+		})
+		return
+	}
+
 	// Fetch latest release from GitHub
 	client := &http.Client{Timeout: 10 * time.Second}
 	req, err := http.NewRequest("GET", githubAPIURL, nil)
@@ -74,7 +74,7 @@ func (s *RESTServer) handleWebhook(c *gin.Context) {
 
 	// Verify instance exists and is enabled
 	var enabled bool
-	err = s.db.QueryRow("SELECT enabled FROM arr_instances WHERE id = ?", instanceID).Scan(&enabled)
+	err = s.db.QueryRow("SELECT enabled FROM arr_instances WHERE id = ? AND deleted_at IS NULL", instanceID).Scan(&enabled)
 	if err != nil {
 		logger.Errorf("Webhook rejected: Instance %d not found", instanceID)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Instance not found"})
@@ -91,8 +91,7 @@ func (s *RESTServer) handleWebhook(c *gin.Context) {
 	}
 
 	var req WebhookRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindBody(c, &req) {
 		return
 	}
 
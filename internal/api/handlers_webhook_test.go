@@ -83,7 +83,8 @@ func setupWebhookTestDB(t *testing.T) (*sql.DB, func()) {
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
 			enabled INTEGER DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 
 		CREATE TABLE scan_paths (
@@ -92,7 +93,8 @@ func setupWebhookTestDB(t *testing.T) (*sql.DB, func()) {
 			arr_path TEXT NOT NULL,
 			arr_instance_id INTEGER REFERENCES arr_instances(id),
 			enabled INTEGER DEFAULT 1,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 	`
 	_, err = db.Exec(schema)
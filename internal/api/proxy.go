@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// socketPeerAddr stands in for the peer address of connections over the Unix
+// domain socket, which have none. Only processes on this host can connect
+// to the socket, so they count as a local proxy.
+const socketPeerAddr = "127.0.0.1:0"
+
+// proxyTrust decides whether a request came through a trusted reverse proxy,
+// whose X-Forwarded-* headers are then honored.
+type proxyTrust struct {
+	nets []*net.IPNet
+}
+
+// newProxyTrust parses the trusted proxy IPs and CIDRs, skipping invalid
+// entries, which config.ValidateAndWarn reports. With a listen socket the
+// stand-in address of its peers is trusted too. It returns the entries to
+// pass to gin, which resolves client IPs from X-Forwarded-For.
+func newProxyTrust(entries []string, socket bool) (proxyTrust, []string) {
+	var p proxyTrust
+	var valid []string
+	if socket {
+		entries = append(append([]string(nil), entries...), "127.0.0.1/32")
+	}
+	for _, entry := range entries {
+		if !config.ValidProxyEntry(entry) {
+			logger.Warnf("Ignoring invalid trusted proxy %q", entry)
+			continue
+		}
+		cidr := entry
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		p.nets = append(p.nets, ipNet)
+		valid = append(valid, entry)
+	}
+	return p, valid
+}
+
+// trusts reports whether the direct peer of r is a trusted proxy.
+func (p proxyTrust) trusts(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range p.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedHeader returns the first value of a X-Forwarded-* header if the
+// request came through a trusted proxy.
+func (s *RESTServer) forwardedHeader(c *gin.Context, name string) string {
+	if !s.proxies.trusts(c.Request) {
+		return ""
+	}
+	value, _, _ := strings.Cut(c.GetHeader(name), ",")
+	return strings.TrimSpace(value)
+}
+
+// externalURL returns the absolute URL of path as the client sees it: the
+// scheme and host come from X-Forwarded-Proto and X-Forwarded-Host when a
+// trusted proxy sets them.
+func (s *RESTServer) externalURL(c *gin.Context, path string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := strings.ToLower(s.forwardedHeader(c, "X-Forwarded-Proto")); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwarded := s.forwardedHeader(c, "X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	if host == "" {
+		return path
+	}
+	return scheme + "://" + host + path
+}
+
+// redirectToBasePath redirects to the base path with an absolute URL, so
+// proxies that don't rewrite Location headers send the client to the right place.
+func (s *RESTServer) redirectToBasePath(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, s.externalURL(c, basePath))
+	}
+}
+
+// socketPeer gives requests over the Unix domain socket the stand-in peer
+// address, so client IPs resolve from the proxy's X-Forwarded-For.
+func socketPeer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = socketPeerAddr
+		next.ServeHTTP(w, r)
+	})
+}
+
+// listenSocket listens on the Unix domain socket at path, replacing a stale
+// socket left by an earlier run. The socket is readable and writable by the
+// owner and group, e.g. a proxy in the same group.
+func listenSocket(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		_ = os.Remove(path)
+	}
+	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0660); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProxyTestServer returns a server trusting proxies, with a base path
+// redirect and a route echoing the client IP.
+func newProxyTestServer(t *testing.T, proxies []string, socket bool) *RESTServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	trust, entries := newProxyTrust(proxies, socket)
+	require.NoError(t, r.SetTrustedProxies(entries))
+	s := &RESTServer{router: r, proxies: trust}
+	r.GET("/", s.redirectToBasePath("/healarr/"))
+	r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+	return s
+}
+
+func TestProxyTrust(t *testing.T) {
+	s := newProxyTestServer(t, []string{"10.0.0.0/8", "192.168.1.5", "not-an-ip"}, false)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantIP     string
+		wantURL    string
+	}{
+		{"trusted CIDR", "10.1.2.3:1234", "203.0.113.7", "https://healarr.example.com/healarr/"},
+		{"trusted IP", "192.168.1.5:1234", "203.0.113.7", "https://healarr.example.com/healarr/"},
+		{"untrusted peer", "192.168.1.6:1234", "192.168.1.6", "http://internal:3090/healarr/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("X-Forwarded-For", "203.0.113.7")
+			header.Set("X-Forwarded-Proto", "https")
+			header.Set("X-Forwarded-Host", "healarr.example.com, other.example.com")
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "http://internal:3090/ip", nil)
+			req.RemoteAddr, req.Header = tt.remoteAddr, header.Clone()
+			s.router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantIP, w.Body.String())
+
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest("GET", "http://internal:3090/", nil)
+			req.RemoteAddr, req.Header = tt.remoteAddr, header.Clone()
+			s.router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusMovedPermanently, w.Code)
+			assert.Equal(t, tt.wantURL, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestStartSocket(t *testing.T) {
+	s := newProxyTestServer(t, nil, true)
+	path := filepath.Join(t.TempDir(), "healarr.sock")
+	go func() { _ = s.StartSocket(path) }()
+	defer func() { _ = s.Shutdown(context.Background()) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		req, _ := http.NewRequest("GET", "http://healarr/ip", nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		var err error
+		resp, err = client.Do(req)
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	defer resp.Body.Close()
+
+	// The proxy on the other end of the socket is trusted
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "203.0.113.7", string(body))
+
+	// A second server can't take over a socket in use
+	_, err := listenSocket(path)
+	assert.Error(t, err)
+}
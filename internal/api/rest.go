@@ -18,13 +18,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/mescon/Healarr/internal/backup"
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/db"
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
 	"github.com/mescon/Healarr/internal/metrics"
 	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/remote"
 	"github.com/mescon/Healarr/internal/services"
 	"github.com/mescon/Healarr/internal/web"
 )
@@ -33,30 +36,103 @@ import (
 type RESTServer struct {
 	router         *gin.Engine
 	httpServer     *http.Server
+	proxies        proxyTrust // reverse proxies whose X-Forwarded-* headers are honored
 	db             *sql.DB
+	readDB         *sql.DB // query-only pool for heavy GET endpoints; nil falls back to db
 	eventBus       *eventbus.EventBus
 	scanner        services.Scanner
 	pathMapper     integration.PathMapper
 	arrClient      integration.ArrClient
 	scheduler      services.Scheduler
 	notifier       *notifier.Notifier
+	outbox         *notifier.WebhookOutbox
 	healthNotifier HealthNotifier // Interface for health notifications (enables testing)
 	metrics        *metrics.MetricsService
 	hub            *WebSocketHub
 	startTime      time.Time
 	toolChecker    *integration.ToolChecker
+	falsePositives *services.FalsePositiveService
+	protection     *services.ProtectionService
+	tags           *services.TagService
+	pathGroups     *services.PathGroupService
+	remediator     *services.RemediatorService
+	healthMonitor  *services.HealthMonitorService
+	reports        *services.ReportService
+	// maintenance runs database maintenance (nil disables the /api/system/maintenance endpoints)
+	maintenance *services.MaintenanceService
+	// backups uploads database backups (nil disables the /api/config/backup-targets endpoints)
+	backups *backup.Service
+	// arrKeyEncryption is the startup *arr API key encryption check (nil if it didn't run)
+	arrKeyEncryption *db.ArrKeyEncryptionReport
+	// faults is the test mode failure injector (nil unless test mode is enabled)
+	faults *integration.FaultInjector
+	// remotePaths caches the remote scan paths; invalidated when scan paths change
+	remotePaths *remote.Registry
+	// systemPause pauses remediation, verification polling and scheduled scans (optional)
+	systemPause *services.SystemPause
+	// dashboard is the in-memory dashboard summary (nil disables /api/dashboard)
+	dashboard *services.DashboardSummary
+	// telemetry sends the opt-in usage statistics (nil disables /api/system/telemetry)
+	telemetry *services.TelemetryService
+	// samples extracts corrupted-file samples (nil disables /api/corruptions/:id/sample)
+	samples *services.SampleExtractor
+	// mappingOverrides stores per-corruption mapping overrides (nil disables /api/corruptions/:id/mapping)
+	mappingOverrides *services.MappingOverrides
+	// resolutionAudit re-checks resolved corruptions (nil disables /api/corruptions/audit)
+	resolutionAudit *services.ResolutionAuditService
+}
+
+// reader returns the pool for read-heavy handlers: the query-only pool when one
+// is configured, the main pool otherwise.
+func (s *RESTServer) reader() *sql.DB {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
 }
 
 // ServerDeps contains all dependencies required for the REST server
 type ServerDeps struct {
-	DB         *sql.DB
-	EventBus   *eventbus.EventBus
-	Scanner    services.Scanner
-	PathMapper integration.PathMapper
-	ArrClient  integration.ArrClient
-	Scheduler  services.Scheduler
-	Notifier   *notifier.Notifier
-	Metrics    *metrics.MetricsService
+	DB            *sql.DB
+	ReadDB        *sql.DB // optional query-only pool so scans writing events don't stall the UI
+	EventBus      *eventbus.EventBus
+	Scanner       services.Scanner
+	PathMapper    integration.PathMapper
+	ArrClient     integration.ArrClient
+	Scheduler     services.Scheduler
+	Notifier      *notifier.Notifier
+	Outbox        *notifier.WebhookOutbox
+	Metrics       *metrics.MetricsService
+	Remediator    *services.RemediatorService
+	HealthMonitor *services.HealthMonitorService
+	// Reports runs the report schedule; a new one is created when nil
+	Reports *services.ReportService
+	// PathGroups is shared with the remediator, which holds paused groups; a new one is created when nil
+	PathGroups *services.PathGroupService
+	// Maintenance runs database maintenance; the maintenance endpoints are disabled when nil
+	Maintenance *services.MaintenanceService
+	// Backups uploads database backups to off-site targets; the backup target endpoints are disabled when nil
+	Backups *backup.Service
+	// ArrKeyEncryption is the startup *arr API key encryption check, reported by /api/system/status
+	ArrKeyEncryption *db.ArrKeyEncryptionReport
+	// FaultInjector enables the /api/test-mode endpoints when set
+	FaultInjector *integration.FaultInjector
+	// ToolChecker is shared with the scanner; a new one is created when nil
+	ToolChecker *integration.ToolChecker
+	// RemotePaths is the remote scan path registry shared with the services (optional)
+	RemotePaths *remote.Registry
+	// SystemPause is the global automation pause; the pause endpoints are disabled when nil
+	SystemPause *services.SystemPause
+	// Dashboard is the event-projected dashboard summary; /api/dashboard is disabled when nil
+	Dashboard *services.DashboardSummary
+	// Telemetry sends the opt-in usage statistics; the telemetry endpoints are disabled when nil
+	Telemetry *services.TelemetryService
+	// Samples extracts corrupted-file samples; the sample endpoints are disabled when nil
+	Samples *services.SampleExtractor
+	// MappingOverrides applies per-corruption mapping overrides; the mapping endpoints are disabled when nil
+	MappingOverrides *services.MappingOverrides
+	// ResolutionAudit re-checks resolved corruptions; the audit endpoints are disabled when nil
+	ResolutionAudit *services.ResolutionAuditService
 }
 
 // NewRESTServer creates a new REST server with the provided dependencies.
@@ -67,18 +143,11 @@ func NewRESTServer(deps ServerDeps) *RESTServer {
 
 	// Configure trusted proxies for accurate client IP detection (used by rate limiters).
 	// Without this, X-Forwarded-For can be spoofed to bypass rate limiting.
-	if trustedProxies := os.Getenv("HEALARR_TRUSTED_PROXIES"); trustedProxies != "" {
-		proxies := strings.Split(trustedProxies, ",")
-		for i := range proxies {
-			proxies[i] = strings.TrimSpace(proxies[i])
-		}
-		if err := r.SetTrustedProxies(proxies); err != nil {
-			logger.Warnf("Failed to set trusted proxies: %v", err)
-		}
-	} else {
-		// Default: trust no proxies — use direct remote address.
-		// Set HEALARR_TRUSTED_PROXIES if running behind a reverse proxy.
-		_ = r.SetTrustedProxies(nil)
+	// Default: trust no proxies — use direct remote address.
+	proxyCfg := config.Get()
+	proxies, trustedProxies := newProxyTrust(proxyCfg.TrustedProxies, proxyCfg.ListenSocket != "")
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		logger.Warnf("Failed to set trusted proxies: %v", err)
 	}
 
 	// Request ID middleware for correlation/tracing
@@ -153,31 +222,66 @@ func NewRESTServer(deps ServerDeps) *RESTServer {
 	})
 
 	// Initialize tool checker with custom binary paths from config
-	cfg := config.Get()
-	toolChecker := integration.NewToolCheckerWithPaths(
-		cfg.FFprobePath,
-		cfg.FFmpegPath,
-		cfg.MediaInfoPath,
-		cfg.HandBrakePath,
-	)
-	toolChecker.CheckAllTools()
+	toolChecker := deps.ToolChecker
+	if toolChecker == nil {
+		cfg := config.Get()
+		toolChecker = integration.NewToolCheckerWithPaths(
+			cfg.FFprobePath,
+			cfg.FFmpegPath,
+			cfg.MediaInfoPath,
+			cfg.HandBrakePath,
+		)
+		toolChecker.CheckAllTools()
+	}
+
+	reports := deps.Reports
+	if reports == nil {
+		reports = services.NewReportService(deps.DB, deps.EventBus)
+	}
+	pathGroups := deps.PathGroups
+	if pathGroups == nil {
+		pathGroups = services.NewPathGroupService(deps.DB)
+	}
 
 	s := &RESTServer{
 		router:         r,
+		proxies:        proxies,
 		db:             deps.DB,
+		readDB:         deps.ReadDB,
 		eventBus:       deps.EventBus,
 		scanner:        deps.Scanner,
 		pathMapper:     deps.PathMapper,
 		arrClient:      deps.ArrClient,
 		scheduler:      deps.Scheduler,
 		notifier:       deps.Notifier,
+		outbox:         deps.Outbox,
 		healthNotifier: deps.Notifier, // Uses same notifier via interface for testability
 		metrics:        deps.Metrics,
 		hub:            NewWebSocketHub(deps.EventBus),
 		startTime:      time.Now(),
 		toolChecker:    toolChecker,
+		falsePositives: services.NewFalsePositiveService(deps.DB),
+		protection:     services.NewProtectionService(deps.DB),
+		tags:           services.NewTagService(deps.DB),
+		pathGroups:     pathGroups,
+		remediator:     deps.Remediator,
+		healthMonitor:  deps.HealthMonitor,
+		reports:        reports,
+		maintenance:    deps.Maintenance,
+		backups:        deps.Backups,
+
+		arrKeyEncryption: deps.ArrKeyEncryption,
+		faults:           deps.FaultInjector,
+		remotePaths:      deps.RemotePaths,
+		systemPause:      deps.SystemPause,
+		dashboard:        deps.Dashboard,
+		telemetry:        deps.Telemetry,
+		samples:          deps.Samples,
+		mappingOverrides: deps.MappingOverrides,
+		resolutionAudit:  deps.ResolutionAudit,
 	}
 
+	s.hub.startLiveMetrics(newLiveMetrics(s.scanner, s.corruptionStates), liveMetricsInterval)
 	s.setupRoutes()
 
 	return s
@@ -189,6 +293,12 @@ const indexHTMLFile = "index.html"
 // routeNotificationByID is the route path for notification operations by ID
 const routeNotificationByID = "/config/notifications/:id"
 
+// routeOutgoingWebhookByID is the route path for outgoing webhook operations by ID
+const routeOutgoingWebhookByID = "/webhooks/outgoing/:id"
+
+// routeBackupTargetByID is the route path for backup target operations by ID
+const routeBackupTargetByID = "/config/backup-targets/:id"
+
 // mustSub returns a sub-filesystem or panics. Used for embedded assets.
 func mustSub(fsys fs.FS, dir string) fs.FS {
 	sub, err := fs.Sub(fsys, dir)
@@ -220,6 +330,7 @@ func (s *RESTServer) handleRuntimeConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"base_path":        basePath,
 		"base_path_source": source,
+		"public_dashboard": cfg.PublicDashboard,
 	})
 }
 
@@ -275,7 +386,7 @@ func (s *RESTServer) setupEmbeddedAssets(base *gin.RouterGroup, basePath string)
 		if basePath == "/" || strings.HasPrefix(c.Request.URL.Path, basePath) {
 			indexHandler(c)
 		} else {
-			c.Redirect(http.StatusMovedPermanently, basePath)
+			s.redirectToBasePath(basePath)(c)
 		}
 	})
 }
@@ -302,7 +413,7 @@ func (s *RESTServer) setupFilesystemAssets(base *gin.RouterGroup, basePath, webD
 		if basePath == "/" || strings.HasPrefix(c.Request.URL.Path, basePath) {
 			indexHandler(c)
 		} else {
-			c.Redirect(http.StatusMovedPermanently, basePath)
+			s.redirectToBasePath(basePath)(c)
 		}
 	})
 }
@@ -335,9 +446,7 @@ func (s *RESTServer) setupRoutes() {
 	} else {
 		base = s.router.Group(basePath)
 		// Redirect root to base path
-		s.router.GET("/", func(c *gin.Context) {
-			c.Redirect(http.StatusMovedPermanently, basePath)
-		})
+		s.router.GET("/", s.redirectToBasePath(basePath))
 	}
 
 	api := base.Group("/api")
@@ -351,6 +460,11 @@ func (s *RESTServer) setupRoutes() {
 		// System info endpoint (no authentication required - useful for debugging)
 		api.GET("/system/info", s.handleSystemInfo)
 
+		// Read-only status page data, opt-in via HEALARR_PUBLIC_DASHBOARD (no authentication)
+		if cfg.PublicDashboard {
+			api.GET("/public/dashboard", APILimiter.Middleware(), s.getPublicDashboard)
+		}
+
 		// Public auth endpoints with rate limiting
 		api.POST("/auth/setup", SetupLimiter.Middleware(), s.handleAuthSetup)
 		api.POST("/auth/login", LoginLimiter.Middleware(), s.handleLogin)
@@ -372,6 +486,10 @@ func (s *RESTServer) setupRoutes() {
 			// Prometheus metrics endpoint (authenticated — use Bearer token or X-API-Key for scraping)
 			protected.GET("/metrics", gin.WrapH(s.metrics.Handler()))
 
+			// Recommended alerts: evaluated here, or as Prometheus rules
+			protected.GET("/alerts", s.getAlerts)
+			protected.GET("/alerts/rules", s.getAlertRules)
+
 			// Auth management
 			protected.GET("/auth/key", s.getAPIKey)
 			protected.POST("/auth/regenerate", s.regenerateAPIKey)
@@ -382,20 +500,52 @@ func (s *RESTServer) setupRoutes() {
 			protected.POST("/config/restart", s.restartServer)
 			protected.POST("/setup/reset", s.handleSetupReset)
 
+			// Setup wizard - progress and guided first scan
+			protected.GET("/setup/wizard", s.getSetupWizard)
+			protected.PUT("/setup/wizard", s.updateSetupWizard)
+			protected.DELETE("/setup/wizard", s.clearSetupWizard)
+			protected.GET("/setup/root-folders", s.getSetupRootFolders)
+			protected.POST("/setup/first-scan", s.setupFirstScan)
+
+			// UI preferences
+			protected.GET("/preferences", s.getPreferences)
+			protected.PUT("/preferences", s.updatePreferences)
+
 			// Config
 			protected.GET("/config/arr", s.getArrInstances)
 			protected.POST("/config/arr", s.createArrInstance)
 			protected.POST("/config/arr/test", s.testArrConnection)
 			protected.PUT("/config/arr/:id", s.updateArrInstance)
 			protected.DELETE("/config/arr/:id", s.deleteArrInstance)
+			protected.POST("/config/arr/:id/restore", s.restoreArrInstance)
+			protected.POST("/config/arr/:id/migrate", s.migrateLibrary)
 			protected.GET("/config/arr/:id/rootfolders", s.getArrRootFolders)
+			protected.GET("/config/arr/:id/tags", s.getArrTags)
+			protected.POST("/config/arr/tags/sync", s.syncArrTags)
+
+			// *arr instance uptime, response times and circuit breaker state
+			protected.GET("/arr/instances/:id/health", s.getArrInstanceHealth)
 			protected.GET("/config/paths", s.getScanPaths)
 			protected.POST("/config/paths", s.createScanPath)
+			protected.POST("/config/paths/bulk", s.bulkCreateScanPaths)
+			protected.GET("/config/paths/drift", s.getMappingDrift)
+			protected.POST("/config/paths/remap", s.remapScanPaths)
 			protected.PUT("/config/paths/:id", s.updateScanPath)
 			protected.DELETE("/config/paths/:id", s.deleteScanPath)
+			protected.POST("/config/paths/:id/restore", s.restoreScanPath)
+			protected.GET("/config/deleted", s.getDeletedConfig)
 			protected.GET("/config/paths/:id/validate", s.validateScanPath)
 			protected.GET("/config/browse", s.browseDirectory)
 
+			// Scan path groups - group-level scans, remediation pause and stats
+			protected.GET("/config/path-groups", s.getPathGroups)
+			protected.POST("/config/path-groups", s.createPathGroup)
+			protected.PUT("/config/path-groups/:id", s.updatePathGroup)
+			protected.DELETE("/config/path-groups/:id", s.deletePathGroup)
+			protected.POST("/config/path-groups/:id/pause", s.pausePathGroup)
+			protected.POST("/config/path-groups/:id/resume", s.resumePathGroup)
+			protected.POST("/config/path-groups/:id/scan", s.scanPathGroup)
+
 			// Notifications
 			protected.GET("/config/notifications", s.getNotifications)
 			protected.POST("/config/notifications", s.createNotification)
@@ -406,20 +556,49 @@ func (s *RESTServer) setupRoutes() {
 			protected.GET(routeNotificationByID+"/log", s.getNotificationLog)
 			protected.GET(routeNotificationByID, s.getNotification)
 
+			// Outgoing webhooks (event forwarding to external automation)
+			protected.GET("/webhooks/outgoing", s.getOutgoingWebhooks)
+			protected.POST("/webhooks/outgoing", s.createOutgoingWebhook)
+			protected.GET("/webhooks/outgoing/deliveries", s.getOutgoingWebhookDeliveries)
+			protected.POST("/webhooks/outgoing/deliveries/:id/retry", s.retryOutgoingWebhookDelivery)
+			protected.GET(routeOutgoingWebhookByID, s.getOutgoingWebhook)
+			protected.PUT(routeOutgoingWebhookByID, s.updateOutgoingWebhook)
+			protected.DELETE(routeOutgoingWebhookByID, s.deleteOutgoingWebhook)
+			protected.GET(routeOutgoingWebhookByID+"/deliveries", s.getOutgoingWebhookDeliveries)
+
 			// Config export/import
 			protected.GET("/config/export", s.exportConfig)
+			protected.GET("/export/remediations", s.exportRemediations)
 			protected.POST("/config/import", s.importConfig)
 			protected.GET("/config/backup", s.downloadDatabaseBackup)
 			protected.POST("/config/restore", s.handleDatabaseRestore)
 
+			// Off-site backup targets: uploads after every database backup, with retention per target
+			if s.backups != nil {
+				protected.GET("/config/backup-targets", s.getBackupTargets)
+				protected.POST("/config/backup-targets", s.createBackupTarget)
+				protected.POST("/config/backup-targets/test", s.testBackupTarget)
+				protected.PUT(routeBackupTargetByID, s.updateBackupTarget)
+				protected.DELETE(routeBackupTargetByID, s.deleteBackupTarget)
+				protected.POST(routeBackupTargetByID+"/upload", s.uploadBackupNow)
+			}
+
 			// Detection preview - shows what command will be run
 			protected.GET("/config/detection-preview", s.getDetectionPreview)
 
 			// Stats & Data
 			protected.GET("/stats/dashboard", s.getDashboardStats)
+			if s.dashboard != nil {
+				protected.GET("/dashboard", s.getDashboard)
+			}
 			protected.GET("/stats/history", s.getStatsHistory)
 			protected.GET("/stats/types", s.getStatsTypes)
 			protected.GET("/stats/path-health", s.getPathHealth)
+			protected.GET("/stats/heatmap", s.getStatsHeatmap)
+			protected.GET("/stats/bandwidth", s.getStatsBandwidth)
+
+			// Event store feed for audit tools, paged with a cursor
+			protected.GET("/events", s.getEvents)
 			protected.GET("/corruptions", s.getCorruptions)
 			protected.GET("/config/schedules", s.getSchedules)
 			protected.POST("/config/schedules", s.addSchedule)
@@ -427,13 +606,55 @@ func (s *RESTServer) setupRoutes() {
 			protected.DELETE("/config/schedules/:id", s.deleteSchedule)
 
 			protected.GET("/corruptions/:id/history", s.getCorruptionHistory)
+			protected.GET("/corruptions/:id/diagnostics", s.getCorruptionDiagnostics)
+			protected.GET("/corruptions/:id/related", s.getRelatedCorruptions)
+			protected.POST("/corruptions/:id/support-bundle", s.createCorruptionSupportBundle)
+			protected.GET("/corruptions/:id/sample", s.getCorruptionSample)
+			protected.POST("/corruptions/:id/sample", s.extractCorruptionSample)
+			protected.GET("/corruptions/:id/sample/download", s.downloadCorruptionSample)
+			protected.GET("/corruptions/:id/deletion-plan", s.getDeletionPlan)
+			protected.GET("/corruptions/:id/quality-pin", s.getQualityPin)
+			protected.PUT("/corruptions/:id/quality-pin", s.setQualityPin)
+			protected.DELETE("/corruptions/:id/quality-pin", s.clearQualityPin)
+			protected.GET("/corruptions/:id/mapping", s.getMappingOverride)
+			protected.PATCH("/corruptions/:id/mapping", s.setMappingOverride)
+			protected.DELETE("/corruptions/:id/mapping", s.clearMappingOverride)
+			protected.GET("/corruptions/:id/releases", s.getCorruptionReleases)
+			protected.POST("/corruptions/:id/releases/grab", s.grabCorruptionRelease)
+			protected.POST("/corruptions/:id/reverify", s.reverifyCorruption)
+			protected.GET("/corruptions/lifecycle", s.getCorruptionLifecycle)
+			protected.GET("/corruptions/audit", s.getResolutionAudit)
+			protected.POST("/corruptions/audit", s.runResolutionAudit)
 			// Corruption bulk actions
+			protected.GET("/corruptions/tags", s.getCorruptionTags)
+			protected.POST("/corruptions/tags", s.tagCorruptions)
+			protected.POST("/corruptions/tags/remove", s.untagCorruptions)
+			protected.GET("/corruptions/filters", s.getSavedFilters)
+			protected.POST("/corruptions/filters", s.createSavedFilter)
+			protected.PUT("/corruptions/filters/:id", s.updateSavedFilter)
+			protected.DELETE("/corruptions/filters/:id", s.deleteSavedFilter)
 			protected.POST("/corruptions/retry", s.retryCorruptions)
 			protected.POST("/corruptions/ignore", s.ignoreCorruptions)
 			protected.POST("/corruptions/delete", s.deleteCorruptions)
+			protected.GET("/remediation/queue", s.getRemediationQueue)
+			protected.GET("/remediation/budget", s.getRemediationBudget)
+			protected.POST("/remediation/budget/approve", s.approveBudgetRemediations)
+			protected.POST("/corruptions/false-positive", s.markFalsePositives)
+			protected.GET("/false-positives", s.getFalsePositives)
+			protected.GET("/false-positives/report", s.getFalsePositiveReport)
+			protected.DELETE("/false-positives/:id", s.deleteFalsePositive)
+			protected.GET("/protected", s.getProtectedItems)
+			protected.POST("/protected", s.protectItem)
+			protected.DELETE("/protected/:id", s.deleteProtectedItem)
 			protected.GET("/remediations", s.getRemediations)
+			protected.GET("/reports", s.getReports)
+			protected.POST("/reports", s.generateReport)
+			protected.GET("/reports/:id", s.getReport)
+			protected.GET("/media/:arr_instance/:media_id/history", s.getMediaHistory)
 			protected.GET("/scans", s.getScans)
 			protected.GET("/scans/active", s.getActiveScans)
+			protected.GET("/scans/compare", s.compareScans)
+			protected.GET("/scans/history", s.getScanHistory) // Summaries outlive the scan records
 			// Specific routes MUST come before :scan_id parameter routes
 			protected.POST("/scans/all", s.triggerScanAll) // Scan all enabled paths
 			protected.POST("/scans/pause-all", s.pauseAllScans)
@@ -458,6 +679,56 @@ func (s *RESTServer) setupRoutes() {
 
 			// Updates - check for new versions
 			protected.GET("/updates/check", s.handleCheckUpdate)
+
+			// Detection tool availability (?refresh=true re-probes the binaries)
+			protected.GET("/system/tools", s.handleSystemTools)
+
+			// Results of startup checks (e.g. *arr API key encryption)
+			protected.GET("/system/status", s.handleSystemStatus)
+
+			// Versions, config, component results and warnings of the last startup
+			protected.GET("/system/startup-report", s.handleStartupReport)
+
+			// Diagnostics bundle for issue reports (secrets redacted)
+			protected.GET("/system/diagnostics", s.handleSystemDiagnostics)
+
+			// Air-gap verification: external loads in the web UI and every outbound destination
+			protected.GET("/system/offline-check", s.handleOfflineCheck)
+
+			// Consistency of the corruption summary with the events, and its repair
+			protected.GET("/system/corruption-summary", s.checkCorruptionSummary)
+			protected.POST("/system/corruption-summary/repair", s.repairCorruptionSummary)
+
+			// Database maintenance: progress of the current and last run, manual trigger and abort
+			if s.maintenance != nil {
+				protected.GET("/system/maintenance", s.getMaintenanceStatus)
+				protected.POST("/system/maintenance", s.triggerMaintenance)
+				protected.POST("/system/maintenance/abort", s.abortMaintenance)
+			}
+
+			// Global pause of remediation, verification polling and scheduled scans
+			if s.systemPause != nil {
+				protected.GET("/system/pause", s.getSystemPause)
+				protected.POST("/system/pause", s.pauseSystem)
+				protected.POST("/system/resume", s.resumeSystem)
+			}
+
+			// Opt-in anonymous usage statistics and a preview of what they contain
+			if s.telemetry != nil {
+				protected.GET("/system/telemetry", s.getTelemetry)
+				protected.PUT("/system/telemetry", s.setTelemetry)
+				protected.GET("/system/telemetry/preview", s.previewTelemetry)
+			}
+
+			// Failure injection for end-to-end pipeline testing (test mode only)
+			if s.faults != nil {
+				protected.GET("/test-mode", s.getTestMode)
+				protected.POST("/test-mode/reset", s.resetTestMode)
+				protected.PUT("/test-mode/fake-arr", s.setTestModeFakeArr)
+				protected.PUT("/test-mode/failures/:stage", s.setTestModeFailure)
+				protected.DELETE("/test-mode/failures/:stage", s.clearTestModeFailure)
+				protected.POST("/test-mode/corruptions", s.injectTestCorruption)
+			}
 		}
 	}
 
@@ -482,6 +753,18 @@ func (s *RESTServer) Start(addr string) error {
 	return s.httpServer.ListenAndServe()
 }
 
+// StartSocket begins serving HTTP requests on the Unix domain socket at path.
+func (s *RESTServer) StartSocket(path string) error {
+	listener, err := listenSocket(path)
+	if err != nil {
+		return err
+	}
+	s.httpServer = &http.Server{
+		Handler: socketPeer(s.router),
+	}
+	return s.httpServer.Serve(listener)
+}
+
 // Shutdown gracefully shuts down the HTTP server
 func (s *RESTServer) Shutdown(ctx context.Context) error {
 	if s.httpServer == nil {
@@ -253,7 +253,7 @@ func TestNewRESTServer(t *testing.T) {
 
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS settings (key TEXT PRIMARY KEY, value TEXT);
-		CREATE TABLE IF NOT EXISTS arr_instances (id INTEGER PRIMARY KEY, name TEXT, url TEXT, api_key TEXT, enabled INTEGER DEFAULT 1);
+		CREATE TABLE IF NOT EXISTS arr_instances (id INTEGER PRIMARY KEY, name TEXT, url TEXT, api_key TEXT, enabled INTEGER DEFAULT 1, deleted_at TIMESTAMP);
 	`)
 	require.NoError(t, err)
 
@@ -302,6 +302,22 @@ func TestNewRESTServer(t *testing.T) {
 // handleRuntimeConfig tests
 // =============================================================================
 
+func TestRESTServer_Reader(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	readDB, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer readDB.Close()
+
+	// Without a read pool, reads go to the main pool
+	s := &RESTServer{db: db}
+	assert.Same(t, db, s.reader())
+
+	s.readDB = readDB
+	assert.Same(t, readDB, s.reader())
+}
+
 func TestHandleRuntimeConfig(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
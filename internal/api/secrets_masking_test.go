@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/backup"
+	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/notifier"
+	"github.com/mescon/Healarr/internal/redact"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// TestSecretsMasking seeds every kind of secret Healarr stores and checks that
+// none of them appears in the payloads of the endpoints that return config.
+func TestSecretsMasking(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TABLE notifications (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			provider_type TEXT NOT NULL,
+			config TEXT NOT NULL,
+			events TEXT NOT NULL DEFAULT '[]',
+			group_ids TEXT NOT NULL DEFAULT '[]',
+			enabled INTEGER DEFAULT 1,
+			throttle_seconds INTEGER DEFAULT 5,
+			locale TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE outgoing_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT 1,
+			max_attempts INTEGER DEFAULT 5,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE backup_targets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			config TEXT NOT NULL,
+			keep_last INTEGER NOT NULL DEFAULT 0,
+			keep_days INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN DEFAULT 1,
+			last_upload_at TIMESTAMP,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`)
+	require.NoError(t, err)
+
+	secrets := map[string]string{
+		"arr api key":          "arr-api-key-0123456789",
+		"remote password":      "remote-password-hunter2",
+		"smtp password":        "smtp-password-hunter2",
+		"telegram bot token":   "123456:telegram-bot-token",
+		"discord webhook url":  "https://discord.com/api/webhooks/1/discord-token",
+		"outgoing webhook":     "outgoing-webhook-secret",
+		"s3 secret access key": "s3-secret-access-key",
+	}
+
+	encryptedKey, err := crypto.Encrypt(secrets["arr api key"])
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', ?)`, encryptedKey)
+	require.NoError(t, err)
+	encryptedPassword, err := crypto.Encrypt(secrets["remote password"])
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, remote_url, remote_username, remote_password)
+		VALUES (1, '/seedbox/tv', '/tv', 1, 'https://seedbox.example.com/tv', 'user', ?)`, encryptedPassword)
+	require.NoError(t, err)
+
+	eb := eventbus.NewEventBus(db)
+	n := notifier.NewNotifier(db, eb)
+	outbox := notifier.NewWebhookOutbox(db, eb)
+	backups := backup.NewService(db, func() (string, error) {
+		path := filepath.Join(t.TempDir(), "healarr.db")
+		return path, os.WriteFile(path, []byte("backup"), 0600)
+	})
+
+	for _, cfg := range []*notifier.NotificationConfig{
+		{Name: "Mail", ProviderType: notifier.ProviderEmail, Config: json.RawMessage(`{"host": "smtp.example.com", "port": 587, "username": "healarr", "password": "` + secrets["smtp password"] + `"}`)},
+		{Name: "Telegram", ProviderType: notifier.ProviderTelegram, Config: json.RawMessage(`{"bot_token": "` + secrets["telegram bot token"] + `", "chat_id": "42"}`)},
+		{Name: "Discord", ProviderType: notifier.ProviderDiscord, Config: json.RawMessage(`{"webhook_url": "` + secrets["discord webhook url"] + `"}`)},
+	} {
+		cfg.Events = []string{"CorruptionDetected"}
+		cfg.Enabled = true
+		_, err := n.CreateConfig(cfg)
+		require.NoError(t, err)
+	}
+	_, err = outbox.CreateWebhook(&notifier.OutgoingWebhook{Name: "HA", URL: "http://hook.local/in", Secret: secrets["outgoing webhook"],
+		Events: []string{"CorruptionDetected"}, Enabled: true, MaxAttempts: 5})
+	require.NoError(t, err)
+	_, err = backups.CreateTarget(&backup.BackupTarget{Name: "S3", Type: backup.TypeS3, Enabled: true,
+		Config: backup.TargetConfig{Bucket: "healarr", AccessKeyID: "AKIA", SecretAccessKey: secrets["s3 secret access key"]}})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	s := &RESTServer{router: r, db: db, eventBus: eb, notifier: n, outbox: outbox, backups: backups}
+	r.GET("/config/arr", s.getArrInstances)
+	r.GET("/config/paths", s.getScanPaths)
+	r.GET("/config/notifications", s.getNotifications)
+	r.GET("/config/notifications/:id", s.getNotification)
+	r.GET("/webhooks/outgoing", s.getOutgoingWebhooks)
+	r.GET("/webhooks/outgoing/:id", s.getOutgoingWebhook)
+	r.GET("/config/backup-targets", s.getBackupTargets)
+	r.GET("/config/export", s.exportConfig)
+
+	for _, path := range []string{
+		"/config/arr",
+		"/config/paths",
+		"/config/notifications",
+		"/config/notifications/1",
+		"/config/notifications/2",
+		"/config/notifications/3",
+		"/webhooks/outgoing",
+		"/webhooks/outgoing/1",
+		"/config/backup-targets",
+		"/config/export",
+	} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			body := w.Body.String()
+			for name, secret := range secrets {
+				assert.NotContains(t, body, secret, "response leaks the %s", name)
+			}
+			// The JSON escape of "/" must not hide a leaked URL either
+			assert.NotContains(t, body, strings.ReplaceAll(secrets["discord webhook url"], "/", `\/`))
+			assert.Contains(t, body, redact.Mask, "set secrets show as masked")
+		})
+	}
+
+	t.Run("export with secrets", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/config/export?include_secrets=true", nil)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), secrets["arr api key"])
+		assert.Contains(t, w.Body.String(), secrets["smtp password"])
+	})
+}
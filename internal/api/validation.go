@@ -0,0 +1,312 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mescon/Healarr/internal/i18n"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response. Error and Code repeat the
+// detail and the error code in the fields of the other API errors, and Success
+// the flag of endpoints that answered with one, so clients reading those keep
+// working.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Error    string       `json:"error"`
+	Code     string       `json:"code"`
+	Success  *bool        `json:"success,omitempty"`
+}
+
+// FieldError is why one field of a request body is invalid. Message is in
+// the language of the request.
+type FieldError struct {
+	Field   string `json:"field"`           // JSON path, e.g. "filters.media_type"
+	Rule    string `json:"rule"`            // the failed rule, e.g. "required" or "max"
+	Param   string `json:"param,omitempty"` // the rule's parameter, e.g. "100"
+	Message string `json:"message"`
+}
+
+func init() {
+	// Report fields by their JSON names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindBody decodes the JSON body of a POST, PUT or PATCH request into obj
+// and checks its binding tags. An invalid body gets a problem response with
+// the failing fields, and false is returned.
+func bindBody(c *gin.Context, obj interface{}) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+	logger.Debugf("Invalid request body for %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	respondValidationError(c, err, reflect.TypeOf(obj))
+	return false
+}
+
+// respondValidationError sends a problem response for an invalid request
+// body of type body: a decoding error, or the binding tags that failed.
+func respondValidationError(c *gin.Context, err error, body reflect.Type) {
+	tr := requestLocale(c)
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, newFieldError(tr, body, fe))
+		}
+		respondFieldErrors(c, fields...)
+	case errors.As(err, &typeErr):
+		respondFieldErrors(c, FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: tr.Sprintf("%s must be %s, not %s", typeErr.Field, tr.T(jsonTypeName(typeErr.Type)), typeErr.Value),
+		})
+	case errors.As(err, &syntaxErr):
+		respondProblem(c, http.StatusBadRequest, tr.Sprintf("malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.Is(err, io.EOF):
+		respondProblem(c, http.StatusBadRequest, tr.T("request body is required"))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		respondProblem(c, http.StatusBadRequest, tr.T("malformed JSON: unexpected end of input"))
+	default:
+		respondProblem(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+// respondFieldErrors sends a problem response for invalid fields, checked by
+// a handler or found by bindBody.
+func respondFieldErrors(c *gin.Context, fields ...FieldError) {
+	writeProblem(c, fieldProblem(fields...))
+}
+
+// fieldProblem is the problem of invalid fields. The detail joins the field
+// messages.
+func fieldProblem(fields ...FieldError) Problem {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	p := Problem{
+		Status: http.StatusBadRequest,
+		Detail: strings.Join(messages, "; "),
+		Errors: fields,
+	}
+	// Bulk endpoints answered an empty ids list with no_ids_provided before
+	// request validation; clients checking the code keep working.
+	if len(fields) == 1 && fields[0].Field == "ids" && (fields[0].Rule == "min" || fields[0].Rule == "required") {
+		p.Code = ErrCodeNoIDs
+	}
+	return p
+}
+
+// respondProblem sends a problem response without field errors.
+func respondProblem(c *gin.Context, status int, detail string) {
+	writeProblem(c, Problem{Status: status, Detail: detail})
+}
+
+// writeProblem fills in the standard members of p and sends it.
+func writeProblem(c *gin.Context, p Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = requestLocale(c).T(ErrMsgInvalidRequest)
+		if p.Status != http.StatusBadRequest {
+			p.Title = http.StatusText(p.Status)
+		}
+	}
+	if c.Request != nil && c.Request.URL != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	p.Error = p.Detail
+	if p.Error == "" {
+		p.Error = p.Title
+	}
+	if p.Code == "" {
+		p.Code = ErrCodeInvalidRequest
+	}
+	c.Render(p.Status, problemRender{p})
+}
+
+// problemRender writes a Problem as application/problem+json.
+type problemRender struct {
+	problem Problem
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.problem)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", problemContentType)
+}
+
+// newFieldError describes a failed binding tag of a field of body. The
+// message names the field by its label tag, e.g. label:"New password", and
+// by its JSON path when it has none.
+func newFieldError(tr i18n.Printer, body reflect.Type, fe validator.FieldError) FieldError {
+	for body != nil && body.Kind() == reflect.Pointer {
+		body = body.Elem()
+	}
+	field := fieldPath(body, fe.Namespace())
+	sf, _ := structField(body, fieldPath(body, fe.StructNamespace()))
+	name := field
+	if label := sf.Tag.Get("label"); label != "" {
+		name = tr.T(label)
+	}
+	return FieldError{Field: field, Rule: fe.Tag(), Param: fe.Param(), Message: ruleMessage(tr, name, fe, sf)}
+}
+
+// fieldPath turns a validator namespace such as "Request.filters[0].name"
+// into the path of the field in body, "filters[0].name". The namespace of a
+// field of an anonymous struct has no type name to strip.
+func fieldPath(body reflect.Type, namespace string) string {
+	if body != nil && body.Name() != "" {
+		return strings.TrimPrefix(namespace, body.Name()+".")
+	}
+	return namespace
+}
+
+// structField finds the field of t at a Go field path such as
+// "Filters[0].Name".
+func structField(t reflect.Type, path string) (reflect.StructField, bool) {
+	if t == nil {
+		return reflect.StructField{}, false
+	}
+	var sf reflect.StructField
+	for _, name := range strings.Split(path, ".") {
+		name, _, _ = strings.Cut(name, "[")
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+		var ok bool
+		if sf, ok = t.FieldByName(name); !ok {
+			return reflect.StructField{}, false
+		}
+		t = sf.Type
+	}
+	return sf, true
+}
+
+// bounds returns the lower and upper bound a binding tag sets with min or
+// gte and max or lte, empty when it sets none.
+func bounds(tag string) (lower, upper string) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "min", "gte":
+			lower = param
+		case "max", "lte":
+			upper = param
+		}
+	}
+	return lower, upper
+}
+
+// ruleMessage says what a failed binding tag of sf requires of the field.
+func ruleMessage(tr i18n.Printer, field string, fe validator.FieldError, sf reflect.StructField) string {
+	param := fe.Param()
+	sized := fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	str := fe.Kind() == reflect.String
+	// A number with both bounds is described by its range
+	if lower, upper := bounds(sf.Tag.Get("binding")); !sized && lower != "" && upper != "" {
+		switch fe.Tag() {
+		case "min", "gte", "max", "lte":
+			return tr.Sprintf("%s must be between %s and %s", field, lower, upper)
+		}
+	}
+	switch fe.Tag() {
+	case "required":
+		return tr.Sprintf("%s is required", field)
+	case "required_without":
+		return tr.Sprintf("%s is required when %s is not set", field, param)
+	case "min", "gte":
+		switch {
+		case sized && param == "1":
+			return tr.Sprintf("%s must not be empty", field)
+		case str:
+			return tr.Sprintf("%s must be at least %s characters", field, param)
+		case sized:
+			return tr.Sprintf("%s must have at least %s items", field, param)
+		}
+		return tr.Sprintf("%s must be %s or greater", field, param)
+	case "max", "lte":
+		switch {
+		case str:
+			return tr.Sprintf("%s must be at most %s characters", field, param)
+		case sized:
+			return tr.Sprintf("%s must have at most %s items", field, param)
+		}
+		return tr.Sprintf("%s must be %s or less", field, param)
+	case "gt":
+		return tr.Sprintf("%s must be greater than %s", field, param)
+	case "lt":
+		return tr.Sprintf("%s must be less than %s", field, param)
+	case "oneof":
+		return tr.Sprintf("%s must be one of: %s", field, strings.Join(strings.Fields(param), ", "))
+	case "url", "http_url":
+		return tr.Sprintf("%s must be a valid URL", field)
+	case "startswith":
+		return tr.Sprintf("%s must start with %q", field, param)
+	default:
+		return tr.Sprintf("%s failed the %s check", field, fe.Tag())
+	}
+}
+
+// jsonTypeName names a Go type as the JSON type a client has to send.
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
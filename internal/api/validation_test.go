@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validationTestRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Count   int      `json:"count" binding:"min=0,max=10"`
+	Mode    string   `json:"mode" binding:"omitempty,oneof=fast slow"`
+	IDs     []string `json:"ids" binding:"min=1"`
+	Filters struct {
+		MediaType string `json:"media_type" binding:"required"`
+	} `json:"filters"`
+}
+
+func doValidationRequest(t *testing.T, body string) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	return doValidationRequestTo(t, "/api/things", body)
+}
+
+func doValidationRequestTo(t *testing.T, target, body string) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/things", func(c *gin.Context) {
+		var req validationTestRequest
+		if !bindBody(c, &req) {
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+
+	req := httptest.NewRequest("POST", target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var problem Problem
+	if w.Code != http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	}
+	return w, problem
+}
+
+func TestBindBody_Valid(t *testing.T) {
+	w, _ := doValidationRequest(t, `{"name":"a","count":3,"ids":["x"],"filters":{"media_type":"movie"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBindBody_FieldErrors(t *testing.T) {
+	w, problem := doValidationRequest(t, `{"count":11,"mode":"medium","ids":[]}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/api/things", problem.Instance)
+	assert.Equal(t, ErrCodeInvalidRequest, problem.Code)
+	assert.Equal(t, problem.Detail, problem.Error)
+
+	assert.Equal(t, []FieldError{
+		{Field: "name", Rule: "required", Message: "name is required"},
+		{Field: "count", Rule: "max", Param: "10", Message: "count must be between 0 and 10"},
+		{Field: "mode", Rule: "oneof", Param: "fast slow", Message: "mode must be one of: fast, slow"},
+		{Field: "ids", Rule: "min", Param: "1", Message: "ids must not be empty"},
+		{Field: "filters.media_type", Rule: "required", Message: "filters.media_type is required"},
+	}, problem.Errors)
+	assert.Equal(t, "name is required; count must be between 0 and 10; mode must be one of: fast, slow; "+
+		"ids must not be empty; filters.media_type is required", problem.Detail)
+}
+
+func TestBindBody_EmptyIDsKeepCode(t *testing.T) {
+	w, problem := doValidationRequest(t, `{"name":"a","ids":[],"filters":{"media_type":"movie"}}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrCodeNoIDs, problem.Code)
+	assert.Equal(t, "ids must not be empty", problem.Detail)
+}
+
+func TestBindBody_Localized(t *testing.T) {
+	w, problem := doValidationRequestTo(t, "/api/things?lang=de", `{"count":"three"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Ungültige Anfrage", problem.Title)
+	assert.Equal(t, "count muss eine ganze Zahl sein, nicht string", problem.Detail)
+
+	_, problem = doValidationRequestTo(t, "/api/things?lang=fr", `{"count":11,"ids":["x"],"filters":{"media_type":"movie"}}`)
+	assert.Equal(t, []FieldError{
+		{Field: "name", Rule: "required", Message: "name est obligatoire"},
+		{Field: "count", Rule: "max", Param: "10", Message: "count doit être compris entre 0 et 10"},
+	}, problem.Errors)
+	assert.Equal(t, "name est obligatoire; count doit être compris entre 0 et 10", problem.Detail)
+}
+
+func TestBindBody_Labels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/things", func(c *gin.Context) {
+		var req struct {
+			NewPassword string `json:"new_password" binding:"min=8" label:"New password"`
+			Filters     struct {
+				MediaType string `json:"media_type" binding:"required"`
+			} `json:"filters"`
+		}
+		if !bindBody(c, &req) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for lang, want := range map[string]string{
+		"en": "New password must be at least 8 characters",
+		"de": "Neues Passwort muss mindestens 8 Zeichen lang sein",
+	} {
+		req := httptest.NewRequest("POST", "/api/things?lang="+lang, strings.NewReader(`{"new_password":"short"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		require.Len(t, problem.Errors, 2)
+		// The field keeps its JSON path; the message uses the label
+		assert.Equal(t, "new_password", problem.Errors[0].Field)
+		assert.Equal(t, want, problem.Errors[0].Message)
+		assert.Equal(t, "filters.media_type", problem.Errors[1].Field)
+	}
+}
+
+func TestBindBody_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantDetail string
+		wantField  *FieldError
+	}{
+		{"empty body", ``, "request body is required", nil},
+		{"malformed", `{"name":`, "malformed JSON: unexpected end of input", nil},
+		{"syntax", `{"name" "a"}`, "malformed JSON at offset 9", nil},
+		{"wrong type", `{"name":"a","count":"three"}`, "count must be an integer, not string",
+			&FieldError{Field: "count", Rule: "type", Param: "int", Message: "count must be an integer, not string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, problem := doValidationRequest(t, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantDetail, problem.Detail)
+			if tt.wantField == nil {
+				assert.Empty(t, problem.Errors)
+			} else {
+				assert.Equal(t, []FieldError{*tt.wantField}, problem.Errors)
+			}
+		})
+	}
+}
+
+func TestRespondFieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/api/config/arr/1", nil)
+
+	respondFieldErrors(c, FieldError{Field: "url", Rule: "url", Message: "Invalid URL"})
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Invalid request", problem.Title)
+	assert.Equal(t, "Invalid URL", problem.Detail)
+	assert.Equal(t, "/api/config/arr/1", problem.Instance)
+	assert.Len(t, problem.Errors, 1)
+}
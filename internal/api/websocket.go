@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"os"
@@ -67,6 +68,10 @@ type WebSocketHub struct {
 	logCh      chan logger.LogEntry
 	mu         sync.Mutex
 	eventBus   *eventbus.EventBus
+	// topics holds the clients subscribed to each topic
+	topics map[string]map[*websocket.Conn]bool
+	// liveMetrics samples the metrics topic (nil until startLiveMetrics)
+	liveMetrics *liveMetrics
 }
 
 // NewWebSocketHub creates a new WebSocketHub and subscribes to relevant events.
@@ -78,6 +83,7 @@ func NewWebSocketHub(eventBus *eventbus.EventBus) *WebSocketHub {
 		shutdown:   make(chan struct{}),
 		clients:    make(map[*websocket.Conn]bool),
 		eventBus:   eventBus,
+		topics:     make(map[string]map[*websocket.Conn]bool),
 	}
 
 	// Subscribe to all events that affect UI state
@@ -86,6 +92,7 @@ func NewWebSocketHub(eventBus *eventbus.EventBus) *WebSocketHub {
 		domain.ScanStarted,
 		domain.ScanCompleted,
 		domain.ScanFailed,
+		domain.ScanSkipped,
 		domain.ScanProgress,
 		// Corruption lifecycle events
 		domain.CorruptionDetected,
@@ -94,23 +101,40 @@ func NewWebSocketHub(eventBus *eventbus.EventBus) *WebSocketHub {
 		domain.DeletionStarted,
 		domain.DeletionCompleted,
 		domain.DeletionFailed,
+		domain.DeletionPlanChanged,
 		domain.SearchStarted,
 		domain.SearchCompleted,
 		domain.SearchFailed,
 		domain.SearchExhausted,
+		domain.ScheduledResearch,
 		domain.FileDetected,
 		domain.VerificationStarted,
 		domain.VerificationSuccess,
 		domain.VerificationFailed,
+		domain.QualityRegression,
+		domain.AudioTrackMissing,
+		domain.ReverificationRequested,
 		domain.DownloadTimeout,
 		domain.DownloadProgress,
 		domain.DownloadFailed,
+		domain.DownloadRejected,
 		domain.ImportBlocked,
 		domain.ManuallyRemoved,
 		domain.DownloadIgnored,
 		domain.RetryScheduled,
 		domain.MaxRetriesReached,
 		domain.StuckRemediation,
+		domain.SLABreached,
+		// Detection-only mode events
+		domain.RemediationPaused,
+		domain.RemediationResumed,
+		domain.RemediationBudgetExceeded,
+		domain.RemediationBudgetRestored,
+		domain.AutomationPaused,
+		domain.AutomationResumed,
+		domain.RemediationDeferred,
+		domain.BudgetApprovalRequired,
+		domain.LowConfidenceDetection,
 		// Notification events
 		domain.NotificationSent,
 		domain.NotificationFailed,
@@ -178,6 +202,7 @@ func (h *WebSocketHub) closeAllClients() {
 		}
 		delete(h.clients, client)
 	}
+	clear(h.topics)
 }
 
 // registerClient adds a new client to the hub.
@@ -192,6 +217,9 @@ func (h *WebSocketHub) registerClient(client *websocket.Conn) {
 func (h *WebSocketHub) unregisterClient(client *websocket.Conn) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	for _, subscribers := range h.topics {
+		delete(subscribers, client)
+	}
 	if _, ok := h.clients[client]; ok {
 		delete(h.clients, client)
 		if err := client.Close(); err != nil {
@@ -212,6 +240,9 @@ func (h *WebSocketHub) broadcastMessage(message interface{}) {
 				logger.Debugf("WebSocket close error during broadcast: %v", closeErr)
 			}
 			delete(h.clients, client)
+			for _, subscribers := range h.topics {
+				delete(subscribers, client)
+			}
 		}
 	}
 }
@@ -288,13 +319,16 @@ func (h *WebSocketHub) HandleConnection(c *gin.Context) {
 
 	for {
 		// ReadMessage blocks until a message is received or an error occurs.
-		// We don't necessarily care about the content of the message here,
-		// as the pong handler updates the read deadline.
-		// This loop primarily keeps the connection open and allows the pong handler to work.
-		_, _, err := ws.ReadMessage()
+		// The pong handler updates the read deadline; the only messages
+		// clients send are topic subscriptions.
+		_, data, err := ws.ReadMessage()
 		if err != nil {
 			break
 		}
+		var msg clientMessage
+		if json.Unmarshal(data, &msg) == nil {
+			h.handleClientMessage(ws, msg)
+		}
 	}
 }
 
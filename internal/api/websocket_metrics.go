@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+const (
+	// topicMetrics is the WebSocket topic of the live metrics. Clients
+	// subscribe with {"type": "subscribe", "topic": "metrics"}.
+	topicMetrics = "metrics"
+	// liveMetricsInterval is how often the live metrics are pushed.
+	liveMetricsInterval = 5 * time.Second
+)
+
+// activeRemediationStates are the states of a corruption being remediated:
+// its file deleted, a replacement searched for, downloaded or verified.
+var activeRemediationStates = []domain.EventType{
+	domain.DeletionStarted, domain.DeletionCompleted,
+	domain.SearchStarted, domain.SearchCompleted,
+	domain.DownloadProgress, domain.DownloadRejected,
+	domain.FileDetected, domain.VerificationStarted, domain.ReverificationRequested,
+}
+
+// queuedRemediationStates are the states of a corruption waiting to be remediated.
+var queuedRemediationStates = []domain.EventType{domain.RemediationQueued, domain.RemediationDeferred}
+
+// liveMetrics samples the values of the dashboard's real-time graphs. Each
+// push carries only the values that changed since the previous one; a client
+// gets all of them when it subscribes.
+type liveMetrics struct {
+	scanner services.Scanner
+	// states counts the corruptions per current state
+	states func(ctx context.Context) (map[string]int, error)
+
+	mu        sync.Mutex
+	filesDone map[string]int // files done per active scan at the previous sample
+	sampledAt time.Time
+	last      map[string]interface{} // values of the previous push
+}
+
+func newLiveMetrics(scanner services.Scanner, states func(ctx context.Context) (map[string]int, error)) *liveMetrics {
+	return &liveMetrics{scanner: scanner, states: states, filesDone: make(map[string]int)}
+}
+
+// sample takes a sample and returns the values that changed since the
+// previous one. The timestamp is always included.
+func (l *liveMetrics) sample(now time.Time) map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.collect(now)
+	delta := map[string]interface{}{"timestamp": now.UnixMilli()}
+	for key, value := range current {
+		if l.last == nil || l.last[key] != value {
+			delta[key] = value
+		}
+	}
+	l.last = current
+	return delta
+}
+
+// full returns all values of the latest sample, taking one if there is none yet.
+func (l *liveMetrics) full(now time.Time) map[string]interface{} {
+	l.mu.Lock()
+	if l.last == nil {
+		l.last = l.collect(now)
+	}
+	values := map[string]interface{}{"timestamp": now.UnixMilli(), "full": true}
+	for key, value := range l.last {
+		values[key] = value
+	}
+	l.mu.Unlock()
+	return values
+}
+
+// reset forgets the previous sample, so throughput isn't averaged over the
+// time no client was subscribed.
+func (l *liveMetrics) reset() {
+	l.mu.Lock()
+	l.last = nil
+	l.sampledAt = time.Time{}
+	clear(l.filesDone)
+	l.mu.Unlock()
+}
+
+// collect reads the current values. Scan throughput is the files the active
+// scans finished since the previous sample, per second.
+func (l *liveMetrics) collect(now time.Time) map[string]interface{} {
+	var scans []services.ScanProgressSnapshot
+	if l.scanner != nil {
+		scans = l.scanner.GetActiveScans()
+	}
+	filesDone := make(map[string]int, len(scans))
+	scanned := 0
+	for _, scan := range scans {
+		filesDone[scan.ID] = scan.FilesDone
+		scanned += max(scan.FilesDone-l.filesDone[scan.ID], 0)
+	}
+	throughput := 0.0
+	if elapsed := now.Sub(l.sampledAt).Seconds(); !l.sampledAt.IsZero() && elapsed > 0 {
+		throughput = math.Round(float64(scanned)/elapsed*10) / 10
+	}
+	l.filesDone = filesDone
+	l.sampledAt = now
+
+	values := map[string]interface{}{
+		"active_scans":    len(scans),
+		"scan_throughput": throughput,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	states, err := l.states(ctx)
+	if err != nil {
+		// Leave the remediation values out rather than report them as zero
+		logger.Debugf("Live metrics: failed to count corruptions: %v", err)
+		return values
+	}
+	values["active_remediations"] = countStates(states, activeRemediationStates)
+	values["queue_depth"] = countStates(states, queuedRemediationStates)
+	return values
+}
+
+// countStates sums the corruptions in the given states.
+func countStates(counts map[string]int, states []domain.EventType) int {
+	n := 0
+	for _, state := range states {
+		n += counts[string(state)]
+	}
+	return n
+}
+
+// corruptionStates counts the corruptions per current state, from the
+// in-memory dashboard summary when there is one.
+func (s *RESTServer) corruptionStates(ctx context.Context) (map[string]int, error) {
+	if s.dashboard != nil {
+		return s.dashboard.Snapshot().States, nil
+	}
+	rows, err := s.reader().QueryContext(ctx, `SELECT current_state, COUNT(*) FROM corruption_summary GROUP BY current_state`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var state string
+		var n int
+		if err := rows.Scan(&state, &n); err != nil {
+			return nil, err
+		}
+		counts[state] = n
+	}
+	return counts, rows.Err()
+}
+
+// startLiveMetrics pushes the live metrics to the subscribed clients every
+// interval. Nothing is sampled while no client is subscribed.
+func (h *WebSocketHub) startLiveMetrics(metrics *liveMetrics, interval time.Duration) {
+	h.mu.Lock()
+	h.liveMetrics = metrics
+	h.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.shutdown:
+				return
+			case now := <-ticker.C:
+				if h.subscriberCount(topicMetrics) == 0 {
+					metrics.reset()
+					continue
+				}
+				h.publish(topicMetrics, map[string]interface{}{
+					"type": topicMetrics,
+					"data": metrics.sample(now),
+				})
+			}
+		}
+	}()
+}
+
+// subscriberCount returns the number of clients subscribed to topic.
+func (h *WebSocketHub) subscriberCount(topic string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.topics[topic])
+}
+
+// publish sends a message to the clients subscribed to topic.
+func (h *WebSocketHub) publish(topic string, message interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.topics[topic] {
+		if err := client.WriteJSON(message); err != nil {
+			logger.Debugf("WebSocket error on topic %s: %v", topic, err)
+			delete(h.topics[topic], client)
+		}
+	}
+}
+
+// clientMessage is a message from a WebSocket client.
+type clientMessage struct {
+	Type  string `json:"type"` // subscribe or unsubscribe
+	Topic string `json:"topic"`
+}
+
+// handleClientMessage subscribes or unsubscribes a client to a topic. A new
+// metrics subscriber gets all current values right away.
+func (h *WebSocketHub) handleClientMessage(client *websocket.Conn, msg clientMessage) {
+	if msg.Topic != topicMetrics {
+		return
+	}
+	if msg.Type == "unsubscribe" {
+		h.mu.Lock()
+		delete(h.topics[msg.Topic], client)
+		h.mu.Unlock()
+		return
+	}
+	if msg.Type != "subscribe" {
+		return
+	}
+
+	h.mu.Lock()
+	metrics := h.liveMetrics
+	h.mu.Unlock()
+	if metrics == nil {
+		return
+	}
+	values := metrics.full(time.Now())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.topics[msg.Topic] == nil {
+		h.topics[msg.Topic] = make(map[*websocket.Conn]bool)
+	}
+	h.topics[msg.Topic][client] = true
+	if err := client.WriteJSON(map[string]interface{}{"type": topicMetrics, "data": values}); err != nil {
+		logger.Debugf("Failed to send live metrics: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/services"
+)
+
+func TestLiveMetrics_SampleDeltas(t *testing.T) {
+	scanner := newScansMockScanner()
+	scanner.activeScans = []services.ScanProgressSnapshot{{ID: "scan-1", FilesDone: 100}}
+	states := map[string]int{"RemediationQueued": 3, "SearchStarted": 1, "DownloadProgress": 2, "VerificationSuccess": 40}
+	metrics := newLiveMetrics(scanner, func(context.Context) (map[string]int, error) { return states, nil })
+
+	start := time.Unix(1700000000, 0)
+	first := metrics.sample(start)
+	for key, want := range map[string]interface{}{"active_scans": 1, "scan_throughput": 0.0, "active_remediations": 3, "queue_depth": 3} {
+		if first[key] != want {
+			t.Errorf("first sample %s = %v, want %v", key, first[key], want)
+		}
+	}
+
+	// 50 more files in 5s; the remediations didn't change
+	scanner.activeScans = []services.ScanProgressSnapshot{{ID: "scan-1", FilesDone: 150}}
+	delta := metrics.sample(start.Add(5 * time.Second))
+	if delta["scan_throughput"] != 10.0 {
+		t.Errorf("scan_throughput = %v, want 10", delta["scan_throughput"])
+	}
+	for _, key := range []string{"active_scans", "active_remediations", "queue_depth"} {
+		if _, ok := delta[key]; ok {
+			t.Errorf("delta contains unchanged %s", key)
+		}
+	}
+	if delta["timestamp"] != start.Add(5*time.Second).UnixMilli() {
+		t.Errorf("timestamp = %v", delta["timestamp"])
+	}
+
+	full := metrics.full(start.Add(6 * time.Second))
+	if full["full"] != true || full["queue_depth"] != 3 || full["scan_throughput"] != 10.0 {
+		t.Errorf("full() = %v, want all latest values", full)
+	}
+
+	// After a reset throughput starts over instead of averaging over the gap
+	metrics.reset()
+	scanner.activeScans = []services.ScanProgressSnapshot{{ID: "scan-1", FilesDone: 500}}
+	if got := metrics.sample(start.Add(time.Hour)); got["scan_throughput"] != 0.0 {
+		t.Errorf("scan_throughput after reset = %v, want 0", got["scan_throughput"])
+	}
+}
+
+func TestWebSocketHub_LiveMetricsTopic(t *testing.T) {
+	db, cleanup := setupTestDBForWebSocket(t)
+	defer cleanup()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	hub := NewWebSocketHub(eb)
+	defer hub.Shutdown()
+	scanner := newScansMockScanner()
+	scanner.activeScans = []services.ScanProgressSnapshot{{ID: "scan-1", FilesDone: 10}}
+	states := func(context.Context) (map[string]int, error) {
+		return map[string]int{"RemediationDeferred": 2}, nil
+	}
+	hub.startLiveMetrics(newLiveMetrics(scanner, states), 20*time.Millisecond)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/ws", hub.HandleConnection)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer ws.Close()
+
+	// Unsubscribed clients get no metrics. A timed out read breaks the
+	// connection, so the subscription uses a new one.
+	readUntil := func(msgType string, timeout time.Duration) map[string]interface{} {
+		ws.SetReadDeadline(time.Now().Add(timeout))
+		for {
+			var msg map[string]interface{}
+			if err := ws.ReadJSON(&msg); err != nil {
+				return nil
+			}
+			if msg["type"] == msgType {
+				return msg
+			}
+		}
+	}
+	if msg := readUntil(topicMetrics, 100*time.Millisecond); msg != nil {
+		t.Fatalf("Expected no metrics before subscribing, got %v", msg)
+	}
+
+	ws, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to reconnect: %v", err)
+	}
+	defer ws.Close()
+	if err := ws.WriteJSON(map[string]string{"type": "subscribe", "topic": topicMetrics}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	msg := readUntil(topicMetrics, time.Second)
+	if msg == nil {
+		t.Fatal("Expected the current metrics after subscribing")
+	}
+	data := msg["data"].(map[string]interface{})
+	if data["full"] != true || data["queue_depth"] != float64(2) || data["active_scans"] != float64(1) {
+		t.Errorf("subscription metrics = %v, want all values", data)
+	}
+
+	msg = readUntil(topicMetrics, time.Second)
+	if msg == nil {
+		t.Fatal("Expected periodic metrics")
+	}
+	data = msg["data"].(map[string]interface{})
+	if _, ok := data["timestamp"]; !ok || data["full"] != nil {
+		t.Errorf("periodic metrics = %v, want a delta with a timestamp", data)
+	}
+	if _, ok := data["queue_depth"]; ok {
+		t.Errorf("periodic metrics = %v, unchanged queue_depth should be left out", data)
+	}
+
+	if err := ws.WriteJSON(map[string]string{"type": "unsubscribe", "topic": topicMetrics}); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := hub.subscriberCount(topicMetrics); n != 0 {
+		t.Errorf("subscriberCount() = %d after unsubscribing, want 0", n)
+	}
+}
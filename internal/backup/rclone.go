@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rcloneBinary is the rclone executable, looked up in PATH.
+var rcloneBinary = "rclone"
+
+// rcloneTarget stores backups on any rclone remote by running the rclone
+// binary, for providers the built-in targets don't cover.
+type rcloneTarget struct {
+	cfg TargetConfig
+}
+
+func newRcloneTarget(cfg TargetConfig) *rcloneTarget {
+	return &rcloneTarget{cfg: cfg}
+}
+
+// remotePath returns "remote:path/name" for a backup file.
+func (t *rcloneTarget) remotePath(name string) string {
+	remote := t.cfg.Remote
+	if !strings.Contains(remote, ":") {
+		remote += ":"
+	}
+	p := joinPath(t.cfg.Path, name)
+	if p == "" || strings.HasSuffix(remote, ":") {
+		return remote + p
+	}
+	return strings.TrimRight(remote, "/") + "/" + p
+}
+
+// run runs an rclone command with stdin and returns its output. The error
+// includes rclone's stderr.
+func (t *rcloneTarget) run(ctx context.Context, stdin io.Reader, command string, args ...string) ([]byte, error) {
+	args = append([]string{command}, args...)
+	if t.cfg.ConfigFile != "" {
+		args = append(args, "--config", t.cfg.ConfigFile)
+	}
+	cmd := exec.CommandContext(ctx, rcloneBinary, args...) // #nosec G204 -- arguments come from the administrator's target settings
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rclone %s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Upload implements Target.
+func (t *rcloneTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	_, err := t.run(ctx, r, "rcat", "--size", strconv.FormatInt(size, 10), t.remotePath(name))
+	return err
+}
+
+// List implements Target.
+func (t *rcloneTarget) List(ctx context.Context) ([]RemoteFile, error) {
+	// One "name;size;time" line per file
+	out, err := t.run(ctx, nil, "lsf", "--files-only", "--format", "pst", "--separator", ";", "--time-format", "RFC3339", t.remotePath(""))
+	if err != nil {
+		return nil, err
+	}
+	var files []RemoteFile
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, ";")
+		if len(fields) != 3 {
+			continue
+		}
+		file := RemoteFile{Name: fields[0]}
+		file.Size, _ = strconv.ParseInt(fields[1], 10, 64)
+		file.ModTime, _ = time.Parse(time.RFC3339, fields[2])
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Delete implements Target.
+func (t *rcloneTarget) Delete(ctx context.Context, name string) error {
+	_, err := t.run(ctx, nil, "deletefile", t.remotePath(name))
+	return err
+}
@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRcloneTarget_RemotePath(t *testing.T) {
+	tests := []struct {
+		remote, path, want string
+	}{
+		{"gdrive", "", "gdrive:a.db"},
+		{"gdrive:", "healarr", "gdrive:healarr/a.db"},
+		{"b2:bucket", "healarr/", "b2:bucket/healarr/a.db"},
+		{"b2:bucket/", "", "b2:bucket/a.db"},
+	}
+	for _, tt := range tests {
+		target := newRcloneTarget(TargetConfig{Remote: tt.remote, Path: tt.path})
+		if got := target.remotePath("a.db"); got != tt.want {
+			t.Errorf("remotePath with remote %q and path %q = %s, want %s", tt.remote, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRcloneTarget_Commands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as rclone")
+	}
+
+	// A fake rclone that logs its arguments and stdin and lists two files
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	script := `#!/bin/sh
+echo "$@" >> ` + log + `
+case "$1" in
+  rcat) cat >> ` + log + `; echo >> ` + log + ` ;;
+  lsf) printf 'healarr_20240101_000000.db.enc;120;2024-01-01T00:00:05Z\nnotes.txt;3;2024-01-02T00:00:00Z\n' ;;
+  deletefile) [ "$2" = "remote:missing" ] && { echo "object not found" >&2; exit 3; } ;;
+esac
+`
+	oldBinary := rcloneBinary
+	rcloneBinary = filepath.Join(dir, "rclone")
+	defer func() { rcloneBinary = oldBinary }()
+	if err := os.WriteFile(rcloneBinary, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	target := newRcloneTarget(TargetConfig{Remote: "remote:", ConfigFile: "/config/rclone.conf"})
+	ctx := context.Background()
+
+	if err := target.Upload(ctx, "healarr_20240101_000000.db.enc", strings.NewReader("backup"), 6); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	files, err := target.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "healarr_20240101_000000.db.enc" || files[0].Size != 120 || files[0].ModTime.Second() != 5 {
+		t.Errorf("List() = %+v", files)
+	}
+	if err := target.Delete(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "object not found") {
+		t.Errorf("Delete error = %v, want rclone's stderr", err)
+	}
+
+	calls, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"rcat --size 6 remote:healarr_20240101_000000.db.enc --config /config/rclone.conf\nbackup\n",
+		"lsf --files-only --format pst --separator ; --time-format RFC3339 remote: --config /config/rclone.conf\n",
+	} {
+		if !strings.Contains(string(calls), want) {
+			t.Errorf("rclone calls:\n%s\nwant them to include:\n%s", calls, want)
+		}
+	}
+}
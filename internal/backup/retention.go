@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// backupNamePattern matches the backup files Healarr creates, plain or
+// encrypted. Other files on a target are never deleted.
+var backupNamePattern = regexp.MustCompile(`^healarr_(\d{8}_\d{6})\.db(\.enc)?$`)
+
+// Retention decides which backups a target keeps. A backup is kept if it is
+// one of the KeepLast newest or younger than KeepDays days. Zero disables a
+// rule; with both zero every backup is kept. The newest backup is always kept.
+type Retention struct {
+	KeepLast int `json:"keep_last"`
+	KeepDays int `json:"keep_days"`
+}
+
+// backupTime returns the creation time encoded in a backup name.
+func backupTime(name string) (time.Time, bool) {
+	m := backupNamePattern.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation("20060102_150405", m[1], time.Local)
+	return t, err == nil
+}
+
+// Expired returns the backups among files that the policy no longer keeps.
+func (r Retention) Expired(files []RemoteFile, now time.Time) []string {
+	if r.KeepLast <= 0 && r.KeepDays <= 0 {
+		return nil
+	}
+
+	type backup struct {
+		name    string
+		created time.Time
+	}
+	var backups []backup
+	for _, f := range files {
+		if created, ok := backupTime(f.Name); ok {
+			backups = append(backups, backup{f.Name, created})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].created.After(backups[j].created) })
+
+	cutoff := now.AddDate(0, 0, -r.KeepDays)
+	var expired []string
+	for i, b := range backups {
+		if i == 0 || (r.KeepLast > 0 && i < r.KeepLast) || (r.KeepDays > 0 && b.created.After(cutoff)) {
+			continue
+		}
+		expired = append(expired, b.name)
+	}
+	return expired
+}
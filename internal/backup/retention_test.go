@@ -0,0 +1,44 @@
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetention_Expired(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	files := []RemoteFile{
+		{Name: "healarr_20240310_060000.db.enc"},
+		{Name: "healarr_20240309_060000.db.enc"},
+		{Name: "healarr_20240308_060000.db"},
+		{Name: "healarr_20240301_060000.db.enc"},
+		{Name: "healarr_20240201_060000.db.enc"},
+		{Name: "notes.txt"},
+		{Name: "healarr_latest.db"},
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      []string
+	}{
+		{"no policy keeps everything", Retention{}, nil},
+		{"keep last", Retention{KeepLast: 3}, []string{"healarr_20240301_060000.db.enc", "healarr_20240201_060000.db.enc"}},
+		{"keep days", Retention{KeepDays: 5}, []string{"healarr_20240301_060000.db.enc", "healarr_20240201_060000.db.enc"}},
+		{"either rule keeps a backup", Retention{KeepLast: 4, KeepDays: 1}, []string{"healarr_20240201_060000.db.enc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.retention.Expired(files, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The newest backup is kept even when every backup is older than keep_days
+	old := []RemoteFile{{Name: "healarr_20230101_000000.db"}, {Name: "healarr_20230102_000000.db"}}
+	if got := (Retention{KeepDays: 7}).Expired(old, now); !reflect.DeepEqual(got, []string{"healarr_20230101_000000.db"}) {
+		t.Errorf("Expired() = %v, want only the older backup", got)
+	}
+}
@@ -0,0 +1,269 @@
+package backup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 request signing (AWS Signature Version 4).
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3EmptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // SHA-256 of ""
+	s3DefaultRegion   = "us-east-1"
+	maxErrorBody      = 512 // bytes of a failed response kept in the error
+)
+
+// s3Target stores backups in an S3-compatible bucket (AWS, MinIO, Backblaze
+// B2, Wasabi, Cloudflare R2, ...). Requests are signed with the standard
+// library so no SDK is needed.
+type s3Target struct {
+	cfg    TargetConfig
+	base   *url.URL // bucket URL
+	region string
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3Target(cfg TargetConfig) *s3Target {
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "s3." + region + ".amazonaws.com"
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	base, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		base = &url.URL{Scheme: "https", Host: endpoint}
+	}
+	if cfg.PathStyle {
+		base.Path += "/" + cfg.Bucket
+	} else {
+		base.Host = cfg.Bucket + "." + base.Host
+	}
+	return &s3Target{cfg: cfg, base: base, region: region, client: &http.Client{}, now: time.Now}
+}
+
+// objectKey returns the key of a backup file.
+func (t *s3Target) objectKey(name string) string {
+	return joinPath(t.cfg.Path, name)
+}
+
+// newRequest builds a signed request for a key (empty for the bucket) and query.
+func (t *s3Target) newRequest(ctx context.Context, method, key string, query url.Values, body io.Reader, payloadHash string) (*http.Request, error) {
+	u := *t.base
+	if key != "" {
+		u.Path += "/" + key
+		u.RawPath = t.base.EscapedPath() + "/" + s3EscapePath(key)
+	}
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	t.sign(req, payloadHash)
+	return req, nil
+}
+
+// sign adds the Signature Version 4 Authorization header. Every header set
+// on the request is signed, along with the host.
+func (t *s3Target) sign(req *http.Request, payloadHash string) {
+	now := t.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + t.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+t.cfg.SecretAccessKey), day)
+	for _, part := range []string{t.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, t.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// do sends a request and returns an error for non-2xx responses.
+func (t *s3Target) do(req *http.Request) (*http.Response, error) {
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("S3 %s %s returned %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Upload implements Target.
+func (t *s3Target) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := t.newRequest(ctx, http.MethodPut, t.objectKey(name), nil, r, s3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := t.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// s3ListResult is the ListObjectsV2 response.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List implements Target.
+func (t *s3Target) List(ctx context.Context) ([]RemoteFile, error) {
+	prefix := t.objectKey("")
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+
+	var files []RemoteFile
+	for {
+		req, err := t.newRequest(ctx, http.MethodGet, "", query, nil, s3EmptyPayload)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.do(req)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse S3 listing: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			files = append(files, RemoteFile{
+				Name:    strings.TrimPrefix(object.Key, prefix),
+				Size:    object.Size,
+				ModTime: object.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return files, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// Delete implements Target.
+func (t *s3Target) Delete(ctx context.Context, name string) error {
+	req, err := t.newRequest(ctx, http.MethodDelete, t.objectKey(name), nil, nil, s3EmptyPayload)
+	if err != nil {
+		return err
+	}
+	resp, err := t.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// s3CanonicalQuery encodes a query sorted by key, as signing requires.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3EscapePath escapes every segment of an object key.
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// s3Escape percent-encodes everything but the RFC 3986 unreserved characters.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
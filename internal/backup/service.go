@@ -0,0 +1,371 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// Timeouts for database queries and for one upload including retention.
+const (
+	queryTimeout  = 10 * time.Second
+	uploadTimeout = 2 * time.Hour
+	testTimeout   = time.Minute
+)
+
+// EncryptedSuffix is appended to the names of encrypted backups.
+const EncryptedSuffix = ".enc"
+
+// targetColumns is the SQL column list for backup target queries.
+const targetColumns = `id, name, type, config, keep_last, keep_days, enabled, last_upload_at, COALESCE(last_error, ''), created_at, updated_at`
+
+// ErrTargetNotFound is returned when a backup target does not exist.
+var ErrTargetNotFound = errors.New("backup target not found")
+
+// BackupTarget is a configured upload target with its retention policy and
+// the result of its last upload.
+type BackupTarget struct {
+	ID     int64        `json:"id"`
+	Name   string       `json:"name"`
+	Type   string       `json:"type"`
+	Config TargetConfig `json:"config"`
+	Retention
+	Enabled      bool    `json:"enabled"`
+	LastUploadAt *string `json:"last_upload_at,omitempty"`
+	LastError    string  `json:"last_error,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+// Service uploads database backups to the configured targets. Backups are
+// encrypted before they leave the host when an encryption key is set.
+type Service struct {
+	db         *sql.DB
+	backup     func() (string, error)
+	passphrase string
+	newTarget  func(targetType string, cfg TargetConfig) (Target, error)
+	now        func() time.Time
+	mu         sync.Mutex // one upload per target at a time
+	uploading  map[int64]bool
+}
+
+// NewService creates a backup upload service. backup creates a local
+// database backup and returns its path; it is used by UploadNow.
+func NewService(db *sql.DB, backup func() (string, error)) *Service {
+	return &Service{
+		db:        db,
+		backup:    backup,
+		newTarget: NewTarget,
+		now:       time.Now,
+		uploading: make(map[int64]bool),
+	}
+}
+
+// SetEncryptionKey sets the passphrase uploaded backups are encrypted with.
+// Empty uploads them unencrypted.
+func (s *Service) SetEncryptionKey(passphrase string) {
+	s.passphrase = passphrase
+}
+
+// EncryptionEnabled reports whether uploads are encrypted.
+func (s *Service) EncryptionEnabled() bool {
+	return s.passphrase != ""
+}
+
+func scanTargetRow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*BackupTarget, error) {
+	var t BackupTarget
+	var config string
+	var lastUploadAt sql.NullString
+	if err := scanner.Scan(&t.ID, &t.Name, &t.Type, &config, &t.KeepLast, &t.KeepDays, &t.Enabled,
+		&lastUploadAt, &t.LastError, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if lastUploadAt.Valid {
+		t.LastUploadAt = &lastUploadAt.String
+	}
+
+	decrypted, err := crypto.Decrypt(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt config for backup target %d: %w", t.ID, err)
+	}
+	redact.RegisterJSON([]byte(decrypted))
+	if err := json.Unmarshal([]byte(decrypted), &t.Config); err != nil {
+		return nil, fmt.Errorf("invalid config for backup target %d: %w", t.ID, err)
+	}
+	return &t, nil
+}
+
+// encryptConfig serializes a target's settings, encrypted at rest.
+func encryptConfig(cfg TargetConfig) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := crypto.Encrypt(string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt config: %w", err)
+	}
+	return encrypted, nil
+}
+
+// GetAllTargets returns every backup target, enabled or not.
+func (s *Service) GetAllTargets() ([]*BackupTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+targetColumns+` FROM backup_targets ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make([]*BackupTarget, 0)
+	for rows.Next() {
+		t, err := scanTargetRow(rows)
+		if err != nil {
+			logger.Errorf("Failed to scan backup target row: %v", err)
+			continue
+		}
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backup targets: %w", err)
+	}
+	return targets, nil
+}
+
+// GetTarget returns a single backup target.
+func (s *Service) GetTarget(id int64) (*BackupTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	t, err := scanTargetRow(s.db.QueryRowContext(ctx, `SELECT `+targetColumns+` FROM backup_targets WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTargetNotFound
+	}
+	return t, err
+}
+
+// CreateTarget stores a new backup target. Its settings are encrypted at rest.
+func (s *Service) CreateTarget(t *BackupTarget) (int64, error) {
+	config, err := encryptConfig(t.Config)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO backup_targets (name, type, config, keep_last, keep_days, enabled)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, t.Name, t.Type, config, t.KeepLast, t.KeepDays, t.Enabled)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateTarget updates an existing backup target.
+func (s *Service) UpdateTarget(t *BackupTarget) error {
+	config, err := encryptConfig(t.Config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE backup_targets
+		SET name = ?, type = ?, config = ?, keep_last = ?, keep_days = ?, enabled = ?, updated_at = datetime('now')
+		WHERE id = ?
+	`, t.Name, t.Type, config, t.KeepLast, t.KeepDays, t.Enabled, t.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+// DeleteTarget removes a backup target. Backups already uploaded stay on it.
+func (s *Service) DeleteTarget(id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM backup_targets WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+// TestTarget checks that a target is reachable by listing its backups.
+func (s *Service) TestTarget(ctx context.Context, t *BackupTarget) ([]RemoteFile, error) {
+	target, err := s.newTarget(t.Type, t.Config)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+
+	files, err := target.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]RemoteFile, 0, len(files))
+	for _, f := range files {
+		if _, ok := backupTime(f.Name); ok {
+			backups = append(backups, f)
+		}
+	}
+	return backups, nil
+}
+
+// UploadAll uploads a local backup to every enabled target. Failures are
+// logged and recorded on the target; they don't stop the other uploads.
+func (s *Service) UploadAll(ctx context.Context, backupPath string) {
+	targets, err := s.GetAllTargets()
+	if err != nil {
+		logger.Errorf("Failed to load backup targets: %v", err)
+		return
+	}
+	for _, t := range targets {
+		if !t.Enabled {
+			continue
+		}
+		if err := s.Upload(ctx, t, backupPath); err != nil {
+			logger.Errorf("Backup upload to %s (%s) failed: %v", t.Name, t.Type, err)
+		}
+	}
+}
+
+// UploadNow creates a fresh local backup and uploads it to one target.
+func (s *Service) UploadNow(ctx context.Context, t *BackupTarget) error {
+	backupPath, err := s.backup()
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	return s.Upload(ctx, t, backupPath)
+}
+
+// Upload uploads a local backup to a target, then deletes the backups its
+// retention policy no longer keeps. The result is recorded on the target.
+func (s *Service) Upload(ctx context.Context, t *BackupTarget, backupPath string) error {
+	s.mu.Lock()
+	if s.uploading[t.ID] {
+		s.mu.Unlock()
+		return fmt.Errorf("an upload to %s is already running", t.Name)
+	}
+	s.uploading[t.ID] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.uploading, t.ID)
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	name, err := s.upload(ctx, t, backupPath)
+	s.recordResult(t.ID, name != "", err)
+	if err == nil {
+		logger.Infof("✓ Backup uploaded to %s (%s): %s", t.Name, t.Type, name)
+	}
+	return err
+}
+
+func (s *Service) upload(ctx context.Context, t *BackupTarget, backupPath string) (string, error) {
+	target, err := s.newTarget(t.Type, t.Config)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(backupPath) // #nosec G304 -- path of a backup Healarr just created
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(backupPath)
+	var body io.Reader = f
+	size := info.Size()
+	if s.passphrase != "" {
+		name += EncryptedSuffix
+		size = crypto.EncryptedStreamSize(size)
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		go func() {
+			pw.CloseWithError(crypto.EncryptStream(pw, f, s.passphrase))
+		}()
+		body = pr
+	}
+
+	if err := target.Upload(ctx, name, body, size); err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	if err := s.applyRetention(ctx, target, t.Retention); err != nil {
+		return name, fmt.Errorf("backup uploaded but retention failed: %w", err)
+	}
+	return name, nil
+}
+
+// applyRetention deletes the backups on a target its retention policy no longer keeps.
+func (s *Service) applyRetention(ctx context.Context, target Target, retention Retention) error {
+	if retention.KeepLast <= 0 && retention.KeepDays <= 0 {
+		return nil
+	}
+	files, err := target.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range retention.Expired(files, s.now()) {
+		if err := target.Delete(ctx, name); err != nil {
+			return err
+		}
+		logger.Debugf("Deleted expired backup %s", name)
+	}
+	return nil
+}
+
+// recordResult stores the outcome of an upload on the target. An upload
+// whose retention failed still counts as the last upload.
+func (s *Service) recordResult(id int64, uploaded bool, uploadErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	lastError := ""
+	if uploadErr != nil {
+		lastError = uploadErr.Error()
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE backup_targets
+		SET last_upload_at = CASE WHEN ? THEN datetime('now') ELSE last_upload_at END, last_error = ?
+		WHERE id = ?
+	`, uploaded, lastError, id); err != nil {
+		logger.Errorf("Failed to record backup upload result for target %d: %v", id, err)
+	}
+}
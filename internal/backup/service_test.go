@@ -0,0 +1,207 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // Register CGo SQLite driver for database/sql
+
+	"github.com/mescon/Healarr/internal/crypto"
+)
+
+// memTarget keeps uploaded files in memory.
+type memTarget struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	fail  error
+}
+
+func (m *memTarget) Upload(_ context.Context, name string, r io.Reader, size int64) error {
+	if m.fail != nil {
+		return m.fail
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = data
+	return nil
+}
+
+func (m *memTarget) List(context.Context) ([]RemoteFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var files []RemoteFile
+	for name, data := range m.files {
+		files = append(files, RemoteFile{Name: name, Size: int64(len(data))})
+	}
+	return files, nil
+}
+
+func (m *memTarget) Delete(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memTarget) names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var names []string
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newTestService returns a service whose targets all upload to mem.
+func newTestService(t *testing.T) (*Service, *memTarget) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`
+		CREATE TABLE backup_targets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			config TEXT NOT NULL,
+			keep_last INTEGER NOT NULL DEFAULT 0,
+			keep_days INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN DEFAULT 1,
+			last_upload_at TIMESTAMP,
+			last_error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	mem := &memTarget{files: map[string][]byte{
+		"healarr_20240101_000000.db.enc": []byte("old"),
+		"healarr_20240102_000000.db.enc": []byte("old"),
+		"README":                         []byte("not a backup"),
+	}}
+	dir := t.TempDir()
+	s := NewService(db, func() (string, error) {
+		path := filepath.Join(dir, "healarr_20240105_000000.db")
+		return path, os.WriteFile(path, []byte("fresh backup"), 0600)
+	})
+	s.newTarget = func(string, TargetConfig) (Target, error) { return mem, nil }
+	return s, mem
+}
+
+func TestService_TargetCRUD(t *testing.T) {
+	s, _ := newTestService(t)
+
+	target := &BackupTarget{Name: "NAS", Type: TypeWebDAV, Config: TargetConfig{URL: "https://nas/dav", Password: "secret"},
+		Retention: Retention{KeepLast: 7}, Enabled: true}
+	id, err := s.CreateTarget(target)
+	if err != nil {
+		t.Fatalf("CreateTarget failed: %v", err)
+	}
+
+	got, err := s.GetTarget(id)
+	if err != nil {
+		t.Fatalf("GetTarget failed: %v", err)
+	}
+	if got.Name != "NAS" || got.Config.Password != "secret" || got.KeepLast != 7 || !got.Enabled || got.LastUploadAt != nil {
+		t.Errorf("GetTarget() = %+v", got)
+	}
+
+	got.Enabled = false
+	if err := s.UpdateTarget(got); err != nil {
+		t.Fatalf("UpdateTarget failed: %v", err)
+	}
+	targets, err := s.GetAllTargets()
+	if err != nil || len(targets) != 1 || targets[0].Enabled {
+		t.Fatalf("GetAllTargets() = %+v, %v", targets, err)
+	}
+
+	if err := s.DeleteTarget(id); err != nil {
+		t.Fatalf("DeleteTarget failed: %v", err)
+	}
+	if _, err := s.GetTarget(id); !errors.Is(err, ErrTargetNotFound) {
+		t.Errorf("GetTarget() after delete error = %v, want ErrTargetNotFound", err)
+	}
+	if err := s.DeleteTarget(id); !errors.Is(err, ErrTargetNotFound) {
+		t.Errorf("DeleteTarget() of a missing target error = %v, want ErrTargetNotFound", err)
+	}
+}
+
+func TestService_UploadNow(t *testing.T) {
+	s, mem := newTestService(t)
+	s.SetEncryptionKey("passphrase")
+
+	id, err := s.CreateTarget(&BackupTarget{Name: "S3", Type: TypeS3, Retention: Retention{KeepLast: 2}, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateTarget failed: %v", err)
+	}
+	target, _ := s.GetTarget(id)
+
+	if err := s.UploadNow(context.Background(), target); err != nil {
+		t.Fatalf("UploadNow failed: %v", err)
+	}
+
+	// The oldest backup is past keep_last; other files are left alone
+	want := []string{"README", "healarr_20240102_000000.db.enc", "healarr_20240105_000000.db.enc"}
+	if got := mem.names(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Files on the target = %v, want %v", got, want)
+	}
+
+	var decrypted bytes.Buffer
+	if err := crypto.DecryptStream(&decrypted, bytes.NewReader(mem.files["healarr_20240105_000000.db.enc"]), "passphrase"); err != nil {
+		t.Fatalf("DecryptStream failed: %v", err)
+	}
+	if decrypted.String() != "fresh backup" {
+		t.Errorf("Decrypted backup = %q", decrypted.String())
+	}
+
+	if target, _ = s.GetTarget(id); target.LastUploadAt == nil || target.LastError != "" {
+		t.Errorf("After a successful upload LastUploadAt=%v LastError=%q", target.LastUploadAt, target.LastError)
+	}
+}
+
+func TestService_UploadAllRecordsFailures(t *testing.T) {
+	s, mem := newTestService(t)
+	mem.fail = errors.New("disk full")
+
+	enabled, _ := s.CreateTarget(&BackupTarget{Name: "enabled", Type: TypeS3, Enabled: true})
+	disabled, _ := s.CreateTarget(&BackupTarget{Name: "disabled", Type: TypeS3, Enabled: false})
+	path, _ := s.backup()
+
+	s.UploadAll(context.Background(), path)
+
+	if target, _ := s.GetTarget(enabled); target.LastUploadAt != nil || target.LastError != "upload failed: disk full" {
+		t.Errorf("Enabled target LastUploadAt=%v LastError=%q", target.LastUploadAt, target.LastError)
+	}
+	if target, _ := s.GetTarget(disabled); target.LastError != "" {
+		t.Errorf("Disabled target was uploaded to: %q", target.LastError)
+	}
+
+	// Unencrypted without a key
+	mem.fail = nil
+	s.UploadAll(context.Background(), path)
+	if _, ok := mem.files["healarr_20240105_000000.db"]; !ok {
+		t.Errorf("Files on the target = %v, want the plain backup", mem.names())
+	}
+}
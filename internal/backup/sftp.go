@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/sftp"
+
+	"github.com/mescon/Healarr/internal/sftpclient"
+)
+
+// sftpTarget stores backups on an SSH server through SFTP. The host key is
+// pinned by its SHA-256 fingerprint.
+type sftpTarget struct {
+	cfg TargetConfig
+}
+
+func newSFTPTarget(cfg TargetConfig) *sftpTarget {
+	return &sftpTarget{cfg: cfg}
+}
+
+// remotePath returns the path of a backup file. A relative Path is relative
+// to the user's home directory.
+func (t *sftpTarget) remotePath(name string) string {
+	dir := strings.TrimRight(t.cfg.Path, "/")
+	if dir == "" && strings.HasPrefix(t.cfg.Path, "/") {
+		return "/" + name
+	}
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// connect opens an SFTP session. The connection is closed when ctx is done.
+func (t *sftpTarget) connect(ctx context.Context) (*sftpclient.Client, func(), error) {
+	client, err := sftpclient.Dial(ctx, sftpclient.Config{
+		Addr:               t.cfg.Host,
+		Username:           t.cfg.Username,
+		Password:           t.cfg.Password,
+		PrivateKey:         t.cfg.PrivateKey,
+		HostKeyFingerprint: t.cfg.HostKeyFingerprint,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	return client, func() {
+		stop()
+		client.Close()
+	}, nil
+}
+
+// Upload implements Target.
+func (t *sftpTarget) Upload(ctx context.Context, name string, r io.Reader, _ int64) error {
+	client, done, err := t.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return sftpUpload(client.Client, t.remotePath(name), r)
+}
+
+// List implements Target.
+func (t *sftpTarget) List(ctx context.Context) ([]RemoteFile, error) {
+	client, done, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	dir := t.remotePath("")
+	if dir == "" {
+		dir = "."
+	}
+	return sftpReadDir(client.Client, dir)
+}
+
+// Delete implements Target.
+func (t *sftpTarget) Delete(ctx context.Context, name string) error {
+	client, done, err := t.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	p := t.remotePath(name)
+	if err := client.Remove(p); err != nil {
+		return fmt.Errorf("remove %s: %w", p, err)
+	}
+	return nil
+}
+
+// sftpUpload writes r to path, replacing any existing file.
+func sftpUpload(client *sftp.Client, path string, r io.Reader) error {
+	f, err := client.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// sftpReadDir lists the regular files in a directory.
+func sftpReadDir(client *sftp.Client, dir string) ([]RemoteFile, error) {
+	infos, err := client.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("readdir %s: %w", dir, err)
+	}
+	var files []RemoteFile
+	for _, info := range infos {
+		if info.Mode().IsRegular() {
+			files = append(files, RemoteFile{Name: info.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		}
+	}
+	return files, nil
+}
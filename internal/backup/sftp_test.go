@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/pkg/sftp"
+)
+
+// newTestSFTPClient connects to an in-process SFTP server keeping files in memory.
+func newTestSFTPClient(t *testing.T) *sftp.Client {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	server := sftp.NewRequestServer(serverConn, sftp.InMemHandler())
+	go server.Serve()
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	if err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return client
+}
+
+func TestSFTPUploadListRemove(t *testing.T) {
+	client := newTestSFTPClient(t)
+	if err := client.Mkdir("/backups"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Mkdir("/backups/old"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("healarr!"), 100<<10)
+	if err := sftpUpload(client, "/backups/healarr_20240101_000000.db", bytes.NewReader(data)); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	// Uploading again replaces the file rather than appending to it
+	if err := sftpUpload(client, "/backups/healarr_20240101_000000.db", bytes.NewReader(data)); err != nil {
+		t.Fatalf("second upload failed: %v", err)
+	}
+
+	files, err := sftpReadDir(client, "/backups")
+	if err != nil {
+		t.Fatalf("readDir failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "healarr_20240101_000000.db" || files[0].Size != int64(len(data)) {
+		t.Fatalf("readDir() = %+v, want only the uploaded file", files)
+	}
+
+	if err := client.Remove("/backups/healarr_20240101_000000.db"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := client.Remove("/backups/missing.db"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("remove of a missing file error = %v", err)
+	}
+}
+
+func TestSFTPTarget_Paths(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"", "a.db"},
+		{"/", "/a.db"},
+		{"backups/", "backups/a.db"},
+		{"/srv/backups", "/srv/backups/a.db"},
+	}
+	for _, tt := range tests {
+		if got := newSFTPTarget(TargetConfig{Path: tt.path}).remotePath("a.db"); got != tt.want {
+			t.Errorf("remotePath with Path %q = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package backup uploads database backups to off-site targets and applies
+// each target's retention policy to the backups stored there.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// Target types.
+const (
+	TypeS3     = "s3"
+	TypeWebDAV = "webdav"
+	TypeSFTP   = "sftp"
+	TypeRclone = "rclone"
+)
+
+// Types lists the supported target types.
+var Types = []string{TypeS3, TypeWebDAV, TypeSFTP, TypeRclone}
+
+// Target is a place backups are uploaded to. Names are plain file names;
+// the target places them under its configured path.
+type Target interface {
+	// Upload stores size bytes read from r as name, replacing an existing file.
+	Upload(ctx context.Context, name string, r io.Reader, size int64) error
+	// List returns the files stored under the target's path.
+	List(ctx context.Context) ([]RemoteFile, error)
+	// Delete removes a file.
+	Delete(ctx context.Context, name string) error
+}
+
+// RemoteFile is a file stored on a target.
+type RemoteFile struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// TargetConfig holds the settings of every target type; each type uses the
+// fields documented for it.
+type TargetConfig struct {
+	// Path is the directory (S3: key prefix) backups are stored under.
+	Path string `json:"path,omitempty"`
+
+	// S3-compatible storage. Endpoint defaults to AWS for the region.
+	Endpoint        string `json:"endpoint,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Bucket          string `json:"bucket,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	PathStyle       bool   `json:"path_style,omitempty"` // https://endpoint/bucket/key instead of https://bucket.endpoint/key
+
+	// WebDAV: URL of the collection. Username and Password are also used by SFTP.
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// SFTP: Host is host[:port]. The host key must match HostKeyFingerprint
+	// (SHA256:... as printed by ssh-keygen -lf).
+	Host               string `json:"host,omitempty"`
+	PrivateKey         string `json:"private_key,omitempty"` // PEM, used instead of Password when set
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"`
+
+	// rclone: Remote is a configured remote ("name:" or "name:bucket").
+	Remote     string `json:"remote,omitempty"`
+	ConfigFile string `json:"config_file,omitempty"` // rclone.conf, defaults to rclone's own
+}
+
+// Masked returns the settings with the password, secret access key and
+// private key masked, as the API returns them.
+func (c TargetConfig) Masked() TargetConfig {
+	c.SecretAccessKey = redact.Value(c.SecretAccessKey)
+	c.Password = redact.Value(c.Password)
+	c.PrivateKey = redact.Value(c.PrivateKey)
+	return c
+}
+
+// KeepSecrets restores the stored secrets that were sent back masked.
+func (c *TargetConfig) KeepSecrets(stored TargetConfig) {
+	c.SecretAccessKey = redact.Keep(c.SecretAccessKey, stored.SecretAccessKey)
+	c.Password = redact.Keep(c.Password, stored.Password)
+	c.PrivateKey = redact.Keep(c.PrivateKey, stored.PrivateKey)
+}
+
+// NewTarget returns the target for a type and its settings.
+func NewTarget(targetType string, cfg TargetConfig) (Target, error) {
+	if err := cfg.Validate(targetType); err != nil {
+		return nil, err
+	}
+	switch targetType {
+	case TypeS3:
+		return newS3Target(cfg), nil
+	case TypeWebDAV:
+		return newWebDAVTarget(cfg), nil
+	case TypeSFTP:
+		return newSFTPTarget(cfg), nil
+	default:
+		return newRcloneTarget(cfg), nil
+	}
+}
+
+// Validate checks that the settings required by the target type are set.
+func (c TargetConfig) Validate(targetType string) error {
+	var required map[string]string
+	switch targetType {
+	case TypeS3:
+		required = map[string]string{"bucket": c.Bucket, "access_key_id": c.AccessKeyID, "secret_access_key": c.SecretAccessKey}
+		if c.Endpoint == "" && c.Region == "" {
+			return errors.New("endpoint or region is required")
+		}
+	case TypeWebDAV:
+		required = map[string]string{"url": c.URL}
+		if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+			return errors.New("url must start with http:// or https://")
+		}
+	case TypeSFTP:
+		required = map[string]string{"host": c.Host, "username": c.Username}
+		if c.Password == "" && c.PrivateKey == "" {
+			return errors.New("password or private_key is required")
+		}
+	case TypeRclone:
+		required = map[string]string{"remote": c.Remote}
+	default:
+		return fmt.Errorf("unknown target type: %s (supported: %s)", targetType, strings.Join(Types, ", "))
+	}
+	for _, field := range []string{"bucket", "access_key_id", "secret_access_key", "url", "host", "username", "remote"} {
+		if value, ok := required[field]; ok && strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s is required", field)
+		}
+	}
+	return nil
+}
+
+// joinPath joins the target's path and a file name with slashes.
+func joinPath(dir, name string) string {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
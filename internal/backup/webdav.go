@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// webdavPropfind asks for the properties List needs.
+const webdavPropfind = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/></d:prop></d:propfind>`
+
+// webdavTarget stores backups in a WebDAV collection (Nextcloud, ownCloud,
+// Synology, Apache mod_dav, rclone serve webdav, ...).
+type webdavTarget struct {
+	cfg    TargetConfig
+	base   string // collection URL with a trailing slash
+	client *http.Client
+}
+
+func newWebDAVTarget(cfg TargetConfig) *webdavTarget {
+	base := strings.TrimRight(cfg.URL, "/") + "/"
+	if dir := strings.Trim(cfg.Path, "/"); dir != "" {
+		base += escapeURLPath(dir) + "/"
+	}
+	return &webdavTarget{cfg: cfg, base: base, client: &http.Client{}}
+}
+
+// do sends a request to a file (or the collection for an empty name) and
+// returns an error for non-2xx responses.
+func (t *webdavTarget) do(ctx context.Context, method, name string, body io.Reader, size int64, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.base+url.PathEscape(name), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if t.cfg.Username != "" || t.cfg.Password != "" {
+		req.SetBasicAuth(t.cfg.Username, t.cfg.Password)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("WebDAV %s %s returned %s: %s", method, req.URL.Path, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return resp, nil
+}
+
+// Upload implements Target.
+func (t *webdavTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	resp, err := t.do(ctx, http.MethodPut, name, r, size, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// webdavMultistatus is a PROPFIND response.
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength int64  `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List implements Target.
+func (t *webdavTarget) List(ctx context.Context) ([]RemoteFile, error) {
+	resp, err := t.do(ctx, "PROPFIND", "", strings.NewReader(webdavPropfind), int64(len(webdavPropfind)),
+		map[string]string{"Depth": "1", "Content-Type": "application/xml"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse WebDAV listing: %w", err)
+	}
+
+	var files []RemoteFile
+	for _, r := range result.Responses {
+		for _, propstat := range r.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") || propstat.Prop.ResourceType.Collection != nil {
+				continue // the collection itself and subdirectories
+			}
+			href, err := url.PathUnescape(r.Href)
+			if err != nil {
+				href = r.Href
+			}
+			file := RemoteFile{Name: path.Base(href), Size: propstat.Prop.ContentLength}
+			if modTime, err := http.ParseTime(propstat.Prop.LastModified); err == nil {
+				file.ModTime = modTime
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// Delete implements Target.
+func (t *webdavTarget) Delete(ctx context.Context, name string) error {
+	resp, err := t.do(ctx, http.MethodDelete, name, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// escapeURLPath escapes every segment of a slash-separated path.
+func escapeURLPath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
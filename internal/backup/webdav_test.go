@@ -0,0 +1,69 @@
+package backup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+// newWebDAVServer serves an in-memory WebDAV share with a backups collection.
+func newWebDAVServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	fs := webdav.NewMemFS()
+	if err := fs.Mkdir(context.Background(), "/backups", os.ModeDir|0755); err != nil {
+		t.Fatal(err)
+	}
+	handler := &webdav.Handler{FileSystem: fs, LockSystem: webdav.NewMemLS()}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "healarr" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebDAVTarget_UploadListDelete(t *testing.T) {
+	server := newWebDAVServer(t)
+	target := newWebDAVTarget(TargetConfig{URL: server.URL + "/", Path: "backups", Username: "healarr", Password: "secret"})
+	ctx := context.Background()
+
+	for _, name := range []string{"healarr_20240101_000000.db.enc", "healarr_20240102_000000.db.enc"} {
+		if err := target.Upload(ctx, name, strings.NewReader("backup"), 6); err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+	}
+
+	files, err := target.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("List() = %+v, want 2 files", files)
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name, "healarr_2024010") || f.Size != 6 || f.ModTime.IsZero() {
+			t.Errorf("Unexpected file %+v", f)
+		}
+	}
+
+	if err := target.Delete(ctx, "healarr_20240101_000000.db.enc"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if files, _ := target.List(ctx); len(files) != 1 || files[0].Name != "healarr_20240102_000000.db.enc" {
+		t.Errorf("List() after delete = %+v", files)
+	}
+
+	target.cfg.Password = "wrong"
+	if _, err := target.List(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("List with a bad password error = %v, want 401", err)
+	}
+}
@@ -3,11 +3,15 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/mescon/Healarr/internal/i18n"
 )
 
 // Version is set at build time via -ldflags
@@ -35,6 +39,16 @@ type Config struct {
 	// Port is the HTTP server listen port (default: 3090)
 	Port string
 
+	// ListenSocket is the path of a Unix domain socket to listen on instead
+	// of Port, for reverse proxies on the same host. Connections over the
+	// socket are trusted like a proxy in TrustedProxies.
+	ListenSocket string
+
+	// TrustedProxies lists the IPs and CIDRs of reverse proxies whose
+	// X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host headers are
+	// honored. Empty (default) trusts no proxy and uses the direct peer.
+	TrustedProxies []string
+
 	// BasePath is the URL base path for reverse proxy setups (default: "/")
 	// Example: "/healarr" if hosting at domain.com/healarr/
 	BasePath string
@@ -45,6 +59,11 @@ type Config struct {
 	// LogLevel controls logging verbosity: "debug", "info", "error" (default: "info")
 	LogLevel string
 
+	// Locale is the language of notifications and API errors: "en", "de", "fr"
+	// or "es" (default: "en"). Notification channels and API clients can
+	// choose their own.
+	Locale string
+
 	// VerificationTimeout is the maximum time to wait for *arr to replace a corrupt file (default: 72h)
 	VerificationTimeout time.Duration
 
@@ -82,6 +101,56 @@ type Config struct {
 	// Set to 0 to disable automatic pruning
 	RetentionDays int
 
+	// ResolvedRetentionDays, FailedRetentionDays and ScanEventRetentionDays
+	// override RetentionDays for the events of resolved or ignored
+	// corruptions, corruptions that reached max retries, and scans. -1
+	// (default) uses RetentionDays, 0 keeps them forever.
+	ResolvedRetentionDays  int
+	FailedRetentionDays    int
+	ScanEventRetentionDays int
+
+	// ScanRetentionDays overrides RetentionDays for scan records with their
+	// file lists and per-file results. -1 (default) uses RetentionDays, 0
+	// keeps them forever.
+	ScanRetentionDays int
+
+	// ScanSummaryRetentionDays is how many days the compact summaries of
+	// finished scans are kept after their scan records are pruned (default:
+	// 0, forever).
+	ScanSummaryRetentionDays int
+
+	// DeletedRetentionDays is how many days deleted scan paths and *arr instances
+	// can be restored before they are purged (default: 30). Set to 0 to keep them
+	// until restored.
+	DeletedRetentionDays int
+
+	// ScanResultsPerPath is how many of each scan path's most recent scans
+	// keep their per-file results (default: 10). Older scans keep their
+	// summary only. Set to 0 to keep all file results.
+	ScanResultsPerPath int
+
+	// ScanFileQueueMemory is how many file paths a path scan keeps in memory
+	// (default: 10000). Longer file lists are spilled to the database while
+	// the directory is walked. Set to 0 to keep every file list in memory.
+	ScanFileQueueMemory int
+
+	// ScanCheckpointFiles and ScanCheckpointInterval are how often a path scan
+	// saves its position for resuming after a restart or crash: every N files
+	// (default: 10) and after the first file finished M after the previous
+	// save (default: 30s). 0 disables either; with both 0 every 10 files.
+	ScanCheckpointFiles    int
+	ScanCheckpointInterval time.Duration
+
+	// ScheduledScanConcurrency is how many scheduled scans may run at once; the
+	// rest wait and start in order of their scan path's priority. Set to 0 for
+	// no limit (default: 0)
+	ScheduledScanConcurrency int
+
+	// ScheduledScanPreemption pauses the lowest-priority running scheduled scan
+	// when a higher-priority schedule fires and no slot is free, resuming it
+	// once a slot frees up (default: false)
+	ScheduledScanPreemption bool
+
 	// DataDir is the directory for persistent data (database, logs, backups, pid file)
 	// Default: /config in Docker, ./config locally
 	DataDir string
@@ -89,6 +158,10 @@ type Config struct {
 	// DatabasePath is the SQLite database file path (default: <DataDir>/healarr.db)
 	DatabasePath string
 
+	// BackupEncryptionKey is the passphrase database backups are encrypted with
+	// before they are uploaded to backup targets. Empty uploads them unencrypted.
+	BackupEncryptionKey string
+
 	// LogDir is the directory for log files (default: <DataDir>/logs)
 	LogDir string
 
@@ -110,10 +183,291 @@ type Config struct {
 
 	// HandBrakePath is the path to HandBrakeCLI binary (default: "HandBrakeCLI")
 	HandBrakePath string
+
+	// SampleSegments is the number of segments decoded per file in "sampled"
+	// detection mode (default: 10, minimum 3 for start/middle/end)
+	SampleSegments int
+
+	// SampleDuration is the length of each decoded segment in "sampled" mode (default: 30s)
+	SampleDuration time.Duration
+
+	// TSMaxContinuityErrors, TSMaxDecodeErrors and TSMaxTimestampGap are how much
+	// damage an MPEG-TS/M2TS recording may show before it counts as corrupt
+	// (defaults: 100, 50 and 10s). Live-TV recordings routinely have a few
+	// continuity errors from weak reception that don't affect playback.
+	TSMaxContinuityErrors int
+	TSMaxDecodeErrors     int
+	TSMaxTimestampGap     time.Duration
+
+	// ToolMaxRSSMB kills a detection tool whose resident memory exceeds this many MB (default: 4096)
+	// Set to 0 to disable the memory limit. Only enforced on Linux.
+	ToolMaxRSSMB int
+
+	// ToolRetries is how many times a hung or killed detection tool is retried (default: 1)
+	ToolRetries int
+
+	// ToolFailureThreshold is the number of consecutive hangs/kills after which a
+	// detection tool is disabled and scans fall back to another checker (default: 3)
+	ToolFailureThreshold int
+
+	// ToolDisableDuration is how long a disabled detection tool is skipped (default: 15m)
+	ToolDisableDuration time.Duration
+
+	// MQTTBroker is the MQTT broker URL, e.g. tcp://mosquitto:1883 or ssl://broker:8883
+	// MQTT publishing is disabled when empty (default: "")
+	MQTTBroker string
+
+	// MQTTUsername and MQTTPassword authenticate to the broker (default: "")
+	MQTTUsername string
+	MQTTPassword string
+
+	// MQTTClientID is the MQTT client ID, also used as the Home Assistant node ID (default: "healarr")
+	MQTTClientID string
+
+	// MQTTTopicPrefix is prepended to all published topics (default: "healarr")
+	MQTTTopicPrefix string
+
+	// MQTTQoS is the QoS level (0-2) for published messages (default: 0)
+	MQTTQoS int
+
+	// MQTTEvents is a comma-separated list of event types published to <prefix>/events/<type>
+	// (default: corruption, verification, scan and instance health events)
+	MQTTEvents string
+
+	// MQTTCAFile is an optional CA bundle used to verify TLS brokers (default: "")
+	MQTTCAFile string
+
+	// MQTTTLSInsecure skips TLS certificate verification for self-signed brokers (default: false)
+	MQTTTLSInsecure bool
+
+	// MQTTDiscovery publishes Home Assistant MQTT discovery configs (default: true)
+	MQTTDiscovery bool
+
+	// MQTTDiscoveryPrefix is the Home Assistant discovery topic prefix (default: "homeassistant")
+	MQTTDiscoveryPrefix string
+
+	// SuppressFalsePositives skips findings identical to ones a user marked as false positive (default: true)
+	SuppressFalsePositives bool
+
+	// VerifyAudioTracks flags healthy replacements that lack audio languages or
+	// channels the original file had, instead of resolving them (default: false)
+	VerifyAudioTracks bool
+
+	// DownloadPrevalidation checks a replacement in the download client's completed
+	// folder before the *arr imports it, and blocklists corrupt downloads so the
+	// *arr grabs another release right away (default: false)
+	DownloadPrevalidation bool
+
+	// DownloadPathMappings translates the *arr's view of download folders to
+	// Healarr's, as comma-separated "arr path=local path" pairs (default: "")
+	DownloadPathMappings string
+
+	// RemediationMaxConcurrent is the number of remediations that may run at once per *arr instance (default: 5)
+	RemediationMaxConcurrent int
+
+	// RemediationSearchesPerHour caps searches started per *arr instance per hour; the rest
+	// wait in the remediation queue. Set to 0 for no limit (default: 0)
+	RemediationSearchesPerHour int
+
+	// RemediationWindow is a daily HH:MM-HH:MM window in which remediations start;
+	// corruptions found outside it wait in the remediation queue. Empty allows
+	// remediation at any time (default: "")
+	RemediationWindow string
+
+	// RemediationMonthlyBudgetGB is how many GB (10^9 bytes) remediation may download per
+	// month before new remediations wait for the next month. Set to 0 for no limit (default: 0)
+	RemediationMonthlyBudgetGB float64
+
+	// RemediationBudgetAction is what happens to a remediation whose estimated replacement
+	// would exceed the monthly budget: "defer" holds it in the queue until next month,
+	// "approve" stops it until a user approves it (default: "defer")
+	RemediationBudgetAction string
+
+	// ResolutionSLA is how long a corruption may stay unresolved before an SLABreached
+	// event is raised. Set to 0 to disable SLA tracking (default: 0)
+	ResolutionSLA time.Duration
+
+	// AlertScanOverdue is how long a scheduled scan path may go without a finished
+	// scan before the scan overdue alert fires (default: 168h, minimum: 1h)
+	AlertScanOverdue time.Duration
+
+	// ArrHealthInterval is how often each *arr instance's system/status endpoint is polled
+	// for the uptime and latency history (default: 5m)
+	ArrHealthInterval time.Duration
+
+	// ReportSchedule is a cron expression for generating the weekly summary report, which is
+	// sent to the notification channels subscribed to ReportGenerated. Empty disables
+	// scheduled reports; they can still be generated from the API (default: "")
+	ReportSchedule string
+
+	// ResolutionAuditSchedule is a cron expression for re-checking the replacements of
+	// recently resolved corruptions. Empty disables scheduled audits; they can still
+	// be run from the API (default: "")
+	ResolutionAuditSchedule string
+
+	// ResolutionAuditDays is how far back resolved corruptions are audited (default: 30)
+	ResolutionAuditDays int
+
+	// ResolutionAuditSample is how many of them an audit re-checks, picked at random.
+	// Set to 0 to re-check all of them (default: 20)
+	ResolutionAuditSample int
+
+	// MaintenanceSchedule is a cron expression for database maintenance (pruning,
+	// incremental vacuum, analysis). "off" disables scheduled maintenance; it can
+	// still be run from the API (default: "0 3 * * *")
+	MaintenanceSchedule string
+
+	// MaintenancePeakHours is a daily HH:MM-HH:MM window in which the incremental
+	// vacuum doesn't start, and is interrupted if it runs into it. Empty allows
+	// it at any time (default: "")
+	MaintenancePeakHours string
+
+	// DetectionOnlyAfter is how long an *arr instance may be unreachable before its paths
+	// switch to detection-only and remediations are queued until it recovers.
+	// Set to 0 to disable (default: 15m)
+	DetectionOnlyAfter time.Duration
+
+	// DBReadConnections is the size of the query-only connection pool used by the
+	// dashboard and other read-heavy endpoints. Set to 0 to share the main pool (default: 4)
+	DBReadConnections int
+
+	// DBReadBusyTimeout is how long a read waits on a database lock before failing (default: 5s)
+	DBReadBusyTimeout time.Duration
+
+	// DBJournalMode is the SQLite journal mode: wal, delete, truncate or persist (default: wal)
+	DBJournalMode string
+
+	// DBSynchronous is the SQLite synchronous mode: full, normal or off (default: full)
+	DBSynchronous string
+
+	// DBCacheSizeMB is the SQLite page cache per connection (default: 8)
+	DBCacheSizeMB int
+
+	// DBMmapSizeMB is how much of the database is memory-mapped per connection (default: 0, off)
+	DBMmapSizeMB int
+
+	// DBBusyTimeout is how long a write waits on a database lock before failing (default: 30s)
+	DBBusyTimeout time.Duration
+
+	// EventBatchSize is how many scan progress events are stored per transaction
+	// during scans. A crash loses at most this many. 0 or 1 stores each event on
+	// its own (default: 50)
+	EventBatchSize int
+
+	// EventBatchInterval is how long a batched event may wait before its batch is
+	// stored (default: 1s)
+	EventBatchInterval time.Duration
+
+	// StrictEventValidation rejects published events whose payload does not match the
+	// schema of their type instead of logging them. Meant for development (default: false)
+	StrictEventValidation bool
+
+	// PublicDashboard serves a read-only status page at /status, and its data at
+	// /api/public/dashboard, without authentication (default: false)
+	PublicDashboard bool
+
+	// UntrackedFileAction is what remediation does with a corrupt file that exists on disk
+	// but isn't tracked by its *arr instance: "none" fails the remediation, "delete" removes
+	// the file and "quarantine" moves it to QuarantineDir. Both then rescan the media
+	// item and search for a replacement (default: "none")
+	UntrackedFileAction string
+
+	// QuarantineDir receives untracked files when UntrackedFileAction is "quarantine"
+	// (default: <DataDir>/quarantine)
+	QuarantineDir string
+
+	// SampleExtraction copies a few MB around the first error of every file found
+	// corrupt to SampleDir, to share when reporting a detection problem. Samples of
+	// single corruptions can be extracted on demand either way (default: false)
+	SampleExtraction bool
+
+	// SampleDir receives corrupted-file samples (default: <DataDir>/samples)
+	SampleDir string
+
+	// SampleMaxMB caps the size of one sample (default: 8)
+	SampleMaxMB int
+
+	// SampleDirMaxMB caps the total size of SampleDir; the oldest samples are
+	// removed beyond it (default: 512)
+	SampleDirMaxMB int
+
+	// SampleRetentionDays is how many days samples are kept (default: 14)
+	SampleRetentionDays int
+
+	// TestMode enables the /api/test-mode endpoints, which inject synthetic corruptions,
+	// fake *arr responses and forced pipeline failures. For development and CI only
+	// (default: false)
+	TestMode bool
+
+	// HDRMetadataPolicy is what a scan does with a file that decodes cleanly but has
+	// missing or inconsistent HDR10/Dolby Vision metadata: "flag" records it as an
+	// HDRMetadata corruption without remediating it, "remediate" treats it like any
+	// other corruption and "off" skips the check (default: "flag")
+	HDRMetadataPolicy string
+
+	// DetectionConsensus has the other detection tools re-check a file a scan found
+	// corrupt before the corruption is reported: "any" reports it anyway, "majority"
+	// when more tools find it corrupt than healthy, "all" when none finds it healthy.
+	// Disagreements are recorded either way. "off" skips the re-check (default: "off")
+	DetectionConsensus string
+
+	// OfflineMode is for air-gapped installs: it turns off calls to hosts other than
+	// the configured *arr instances, notification providers, webhooks and MQTT broker,
+	// such as the GitHub update check (default: false)
+	OfflineMode bool
+
+	// TelemetryDisabled is the hard off switch of the anonymous usage statistics: they
+	// are never sent and can't be turned on in the UI. Implied by OfflineMode (default: false)
+	TelemetryDisabled bool
+
+	// TelemetryURL is where anonymous usage statistics are posted once the user opted
+	// in. Nothing is sent while it is empty (default: "")
+	TelemetryURL string
+
+	// TelemetryInterval is the time between anonymous usage statistics reports
+	// (default: 168h)
+	TelemetryInterval time.Duration
+
+	// ArrRecordDir turns on recording of *arr API traffic for debugging: sanitized
+	// requests and responses are written there as test fixtures, one file per
+	// endpoint. Empty turns it off (default: "")
+	ArrRecordDir string
 }
 
-// Global singleton
-var cfg *Config
+// Untracked file actions accepted by HEALARR_UNTRACKED_FILE_ACTION.
+const (
+	UntrackedFileNone       = "none"
+	UntrackedFileDelete     = "delete"
+	UntrackedFileQuarantine = "quarantine"
+)
+
+// HDR metadata policies accepted by HEALARR_HDR_METADATA_POLICY.
+const (
+	HDRMetadataOff       = "off"
+	HDRMetadataFlag      = "flag"
+	HDRMetadataRemediate = "remediate"
+)
+
+// Consensus policies accepted by HEALARR_DETECTION_CONSENSUS.
+const (
+	ConsensusOff      = "off"
+	ConsensusAny      = "any"
+	ConsensusMajority = "majority"
+	ConsensusAll      = "all"
+)
+
+// Remediations over the monthly data budget are either deferred or wait for approval.
+const (
+	BudgetActionDefer   = "defer"
+	BudgetActionApprove = "approve"
+)
+
+// defaultMQTTEvents is the event list published to MQTT when HEALARR_MQTT_EVENTS is unset.
+const defaultMQTTEvents = "CorruptionDetected,VerificationSuccess,MaxRetriesReached,SearchExhausted,ScanCompleted,ScanFailed,InstanceUnhealthy,InstanceHealthy"
+
+// current is the configuration returned by Get. Reload swaps it while other
+// goroutines read it, so it is only accessed atomically.
+var current atomic.Pointer[Config]
 
 // resolveBasePath resolves and normalizes the base path from environment.
 // Returns the normalized path and its source ("environment" or "default").
@@ -240,6 +594,17 @@ func createRequiredDirs(dataDir string) string {
 // Load reads configuration from environment variables with sensible defaults.
 // Should be called once at application startup.
 func Load() *Config {
+	if err := loadEnvFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	c := build()
+	current.Store(c)
+	return c
+}
+
+// build reads the configuration from the environment and the env file without
+// installing it.
+func build() *Config {
 	basePath, basePathSource := resolveBasePath()
 	dataDir := resolveDataDir()
 	webDir := resolveWebDir()
@@ -251,11 +616,14 @@ func Load() *Config {
 		dbPath = filepath.Join(dataDir, "healarr.db")
 	}
 
-	cfg = &Config{
+	cfg := &Config{
 		Port:                 getEnvOrDefault("HEALARR_PORT", "3090"),
+		ListenSocket:         getEnvOrDefault("HEALARR_LISTEN_SOCKET", ""),
+		TrustedProxies:       splitList(os.Getenv("HEALARR_TRUSTED_PROXIES")),
 		BasePath:             basePath,
 		BasePathSource:       basePathSource,
 		LogLevel:             strings.ToLower(getEnvOrDefault("HEALARR_LOG_LEVEL", "info")),
+		Locale:               getEnvOrDefault("HEALARR_LOCALE", i18n.English),
 		VerificationTimeout:  getEnvDurationOrDefault("HEALARR_VERIFICATION_TIMEOUT", 72*time.Hour),
 		VerificationInterval: getEnvDurationOrDefault("HEALARR_VERIFICATION_INTERVAL", 30*time.Second),
 		StaleThreshold:       getEnvDurationOrDefault("HEALARR_STALE_THRESHOLD", 24*time.Hour),
@@ -264,15 +632,234 @@ func Load() *Config {
 		ArrRateLimitRPS:        getEnvFloatOrDefault("HEALARR_ARR_RATE_LIMIT_RPS", 5.0),
 		ArrRateLimitBurst:      getEnvIntOrDefault("HEALARR_ARR_RATE_LIMIT_BURST", 10),
 		AllowWholeSeriesSearch: getEnvBoolOrDefault("HEALARR_ALLOW_WHOLE_SERIES_SEARCH", false),
-		RetentionDays:        getEnvIntOrDefault("HEALARR_RETENTION_DAYS", 90),
+		RetentionDays:          getEnvIntOrDefault("HEALARR_RETENTION_DAYS", 90),
+		ResolvedRetentionDays:  getEnvIntOrDefault("HEALARR_RETENTION_RESOLVED_DAYS", -1),
+		FailedRetentionDays:    getEnvIntOrDefault("HEALARR_RETENTION_FAILED_DAYS", -1),
+		ScanEventRetentionDays: getEnvIntOrDefault("HEALARR_RETENTION_SCAN_EVENTS_DAYS", -1),
+		ScanRetentionDays:      getEnvIntOrDefault("HEALARR_RETENTION_SCANS_DAYS", -1),
+		ScanSummaryRetentionDays: getEnvIntOrDefault("HEALARR_RETENTION_SCAN_SUMMARIES_DAYS", 0),
+		DeletedRetentionDays: getEnvIntOrDefault("HEALARR_DELETED_RETENTION_DAYS", 30),
+		ScanResultsPerPath:   getEnvIntOrDefault("HEALARR_SCAN_RESULTS_PER_PATH", 10),
+		ScanFileQueueMemory:  getEnvIntOrDefault("HEALARR_SCAN_FILE_QUEUE_MEMORY", 10000),
+		ScanCheckpointFiles:    getEnvIntOrDefault("HEALARR_SCAN_CHECKPOINT_FILES", 10),
+		ScanCheckpointInterval: getEnvDurationOrDefault("HEALARR_SCAN_CHECKPOINT_INTERVAL", 30*time.Second),
+		ScheduledScanConcurrency: getEnvIntOrDefault("HEALARR_SCHEDULED_SCAN_CONCURRENCY", 0),
+		ScheduledScanPreemption:  getEnvBoolOrDefault("HEALARR_SCHEDULED_SCAN_PREEMPTION", false),
 		DataDir:              dataDir,
 		DatabasePath:         dbPath,
+		BackupEncryptionKey:  getEnvOrDefault("HEALARR_BACKUP_ENCRYPTION_KEY", ""),
 		LogDir:               logDir,
 		WebDir:               webDir,
 		FFprobePath:          getEnvOrDefault("HEALARR_FFPROBE_PATH", "ffprobe"),
 		FFmpegPath:           getEnvOrDefault("HEALARR_FFMPEG_PATH", "ffmpeg"),
 		MediaInfoPath:        getEnvOrDefault("HEALARR_MEDIAINFO_PATH", "mediainfo"),
 		HandBrakePath:        getEnvOrDefault("HEALARR_HANDBRAKE_PATH", "HandBrakeCLI"),
+		SampleSegments:       getEnvIntOrDefault("HEALARR_SAMPLE_SEGMENTS", 10),
+		SampleDuration:       getEnvDurationOrDefault("HEALARR_SAMPLE_DURATION", 30*time.Second),
+		TSMaxContinuityErrors: getEnvIntOrDefault("HEALARR_TS_MAX_CONTINUITY_ERRORS", 100),
+		TSMaxDecodeErrors:     getEnvIntOrDefault("HEALARR_TS_MAX_DECODE_ERRORS", 50),
+		TSMaxTimestampGap:     getEnvDurationOrDefault("HEALARR_TS_MAX_TIMESTAMP_GAP", 10*time.Second),
+		ToolMaxRSSMB:         getEnvIntOrDefault("HEALARR_TOOL_MAX_RSS_MB", 4096),
+		ToolRetries:          getEnvIntOrDefault("HEALARR_TOOL_RETRIES", 1),
+		ToolFailureThreshold: getEnvIntOrDefault("HEALARR_TOOL_FAILURE_THRESHOLD", 3),
+		ToolDisableDuration:  getEnvDurationOrDefault("HEALARR_TOOL_DISABLE_DURATION", 15*time.Minute),
+		MQTTBroker:           getEnvOrDefault("HEALARR_MQTT_BROKER", ""),
+		MQTTUsername:         getEnvOrDefault("HEALARR_MQTT_USERNAME", ""),
+		MQTTPassword:         getEnvOrDefault("HEALARR_MQTT_PASSWORD", ""),
+		MQTTClientID:         getEnvOrDefault("HEALARR_MQTT_CLIENT_ID", "healarr"),
+		MQTTTopicPrefix:      getEnvOrDefault("HEALARR_MQTT_TOPIC_PREFIX", "healarr"),
+		MQTTQoS:              getEnvIntOrDefault("HEALARR_MQTT_QOS", 0),
+		MQTTEvents:           getEnvOrDefault("HEALARR_MQTT_EVENTS", defaultMQTTEvents),
+		MQTTCAFile:           getEnvOrDefault("HEALARR_MQTT_CA_FILE", ""),
+		MQTTTLSInsecure:      getEnvBoolOrDefault("HEALARR_MQTT_TLS_INSECURE", false),
+		MQTTDiscovery:        getEnvBoolOrDefault("HEALARR_MQTT_DISCOVERY", true),
+		MQTTDiscoveryPrefix:  getEnvOrDefault("HEALARR_MQTT_DISCOVERY_PREFIX", "homeassistant"),
+		SuppressFalsePositives: getEnvBoolOrDefault("HEALARR_SUPPRESS_FALSE_POSITIVES", true),
+		VerifyAudioTracks:      getEnvBoolOrDefault("HEALARR_VERIFY_AUDIO_TRACKS", false),
+		DownloadPrevalidation:  getEnvBoolOrDefault("HEALARR_DOWNLOAD_PREVALIDATION", false),
+		DownloadPathMappings:   strings.TrimSpace(getEnvOrDefault("HEALARR_DOWNLOAD_PATH_MAPPINGS", "")),
+		RemediationMaxConcurrent:   getEnvIntOrDefault("HEALARR_REMEDIATION_MAX_CONCURRENT", 5),
+		RemediationSearchesPerHour: getEnvIntOrDefault("HEALARR_REMEDIATION_SEARCHES_PER_HOUR", 0),
+		RemediationWindow:          strings.TrimSpace(getEnvOrDefault("HEALARR_REMEDIATION_WINDOW", "")),
+		RemediationMonthlyBudgetGB: getEnvFloatOrDefault("HEALARR_REMEDIATION_MONTHLY_BUDGET_GB", 0),
+		RemediationBudgetAction:    strings.ToLower(getEnvOrDefault("HEALARR_REMEDIATION_BUDGET_ACTION", BudgetActionDefer)),
+		ResolutionSLA:              getEnvDurationOrDefault("HEALARR_RESOLUTION_SLA", 0),
+		AlertScanOverdue:           getEnvDurationOrDefault("HEALARR_ALERT_SCAN_OVERDUE", 7*24*time.Hour),
+		ArrHealthInterval:          getEnvDurationOrDefault("HEALARR_ARR_HEALTH_INTERVAL", 5*time.Minute),
+		ReportSchedule:             strings.TrimSpace(getEnvOrDefault("HEALARR_REPORT_SCHEDULE", "")),
+		ResolutionAuditSchedule:    strings.TrimSpace(getEnvOrDefault("HEALARR_RESOLUTION_AUDIT_SCHEDULE", "")),
+		ResolutionAuditDays:        getEnvIntOrDefault("HEALARR_RESOLUTION_AUDIT_DAYS", 30),
+		ResolutionAuditSample:      getEnvIntOrDefault("HEALARR_RESOLUTION_AUDIT_SAMPLE", 20),
+		MaintenanceSchedule:        strings.TrimSpace(getEnvOrDefault("HEALARR_MAINTENANCE_SCHEDULE", "0 3 * * *")),
+		MaintenancePeakHours:       strings.TrimSpace(getEnvOrDefault("HEALARR_MAINTENANCE_PEAK_HOURS", "")),
+		DetectionOnlyAfter:         getEnvDurationOrDefault("HEALARR_DETECTION_ONLY_AFTER", 15*time.Minute),
+		DBReadConnections:          getEnvIntOrDefault("HEALARR_DB_READ_CONNECTIONS", 4),
+		DBReadBusyTimeout:          getEnvDurationOrDefault("HEALARR_DB_READ_BUSY_TIMEOUT", 5*time.Second),
+		DBJournalMode:              strings.ToLower(getEnvOrDefault("HEALARR_DB_JOURNAL_MODE", "wal")),
+		DBSynchronous:              strings.ToLower(getEnvOrDefault("HEALARR_DB_SYNCHRONOUS", "full")),
+		DBCacheSizeMB:              getEnvIntOrDefault("HEALARR_DB_CACHE_SIZE_MB", 8),
+		DBMmapSizeMB:               getEnvIntOrDefault("HEALARR_DB_MMAP_SIZE_MB", 0),
+		DBBusyTimeout:              getEnvDurationOrDefault("HEALARR_DB_BUSY_TIMEOUT", 30*time.Second),
+		EventBatchSize:             getEnvIntOrDefault("HEALARR_EVENT_BATCH_SIZE", 50),
+		EventBatchInterval:         getEnvDurationOrDefault("HEALARR_EVENT_BATCH_INTERVAL", time.Second),
+		StrictEventValidation:      getEnvBoolOrDefault("HEALARR_STRICT_EVENT_VALIDATION", false),
+		PublicDashboard:            getEnvBoolOrDefault("HEALARR_PUBLIC_DASHBOARD", false),
+		UntrackedFileAction:        strings.ToLower(getEnvOrDefault("HEALARR_UNTRACKED_FILE_ACTION", UntrackedFileNone)),
+		HDRMetadataPolicy:          strings.ToLower(getEnvOrDefault("HEALARR_HDR_METADATA_POLICY", HDRMetadataFlag)),
+		DetectionConsensus:         strings.ToLower(getEnvOrDefault("HEALARR_DETECTION_CONSENSUS", ConsensusOff)),
+		QuarantineDir:              getEnvOrDefault("HEALARR_QUARANTINE_DIR", filepath.Join(dataDir, "quarantine")),
+		SampleExtraction:           getEnvBoolOrDefault("HEALARR_SAMPLE_EXTRACTION", false),
+		SampleDir:                  getEnvOrDefault("HEALARR_SAMPLE_DIR", filepath.Join(dataDir, "samples")),
+		SampleMaxMB:                getEnvIntOrDefault("HEALARR_SAMPLE_MAX_MB", 8),
+		SampleDirMaxMB:             getEnvIntOrDefault("HEALARR_SAMPLE_DIR_MAX_MB", 512),
+		SampleRetentionDays:        getEnvIntOrDefault("HEALARR_SAMPLE_RETENTION_DAYS", 14),
+		TestMode:                   getEnvBoolOrDefault("HEALARR_TEST_MODE", false),
+		OfflineMode:                getEnvBoolOrDefault("HEALARR_OFFLINE_MODE", false),
+		TelemetryDisabled:          getEnvBoolOrDefault("HEALARR_TELEMETRY_DISABLED", false),
+		TelemetryURL:               getEnvOrDefault("HEALARR_TELEMETRY_URL", ""),
+		TelemetryInterval:          getEnvDurationOrDefault("HEALARR_TELEMETRY_INTERVAL", 7*24*time.Hour),
+		ArrRecordDir:               getEnvOrDefault("HEALARR_ARR_RECORD_DIR", ""),
+	}
+
+	// At least one remediation per instance must be able to run
+	if cfg.RemediationMaxConcurrent < 1 {
+		cfg.RemediationMaxConcurrent = 1
+	}
+	if cfg.RemediationSearchesPerHour < 0 {
+		cfg.RemediationSearchesPerHour = 0
+	}
+	if cfg.RemediationMonthlyBudgetGB < 0 {
+		cfg.RemediationMonthlyBudgetGB = 0
+	}
+	if cfg.ResolutionSLA < 0 {
+		cfg.ResolutionSLA = 0
+	}
+	if cfg.AlertScanOverdue < time.Hour {
+		cfg.AlertScanOverdue = time.Hour
+	}
+	if cfg.ArrHealthInterval < time.Minute {
+		cfg.ArrHealthInterval = time.Minute
+	}
+	if cfg.ResolutionAuditDays < 1 {
+		cfg.ResolutionAuditDays = 30
+	}
+	if cfg.ResolutionAuditSample < 0 {
+		cfg.ResolutionAuditSample = 0
+	}
+	if cfg.TSMaxContinuityErrors < 0 {
+		cfg.TSMaxContinuityErrors = 0
+	}
+	if cfg.TSMaxDecodeErrors < 0 {
+		cfg.TSMaxDecodeErrors = 0
+	}
+	if cfg.TSMaxTimestampGap <= 0 {
+		cfg.TSMaxTimestampGap = 10 * time.Second
+	}
+	if cfg.DetectionOnlyAfter < 0 {
+		cfg.DetectionOnlyAfter = 0
+	}
+	if cfg.ScheduledScanConcurrency < 0 {
+		cfg.ScheduledScanConcurrency = 0
+	}
+	if cfg.DBReadConnections < 0 {
+		cfg.DBReadConnections = 0
+	}
+	if cfg.DBReadBusyTimeout <= 0 {
+		cfg.DBReadBusyTimeout = 5 * time.Second
+	}
+	if cfg.DBCacheSizeMB <= 0 {
+		cfg.DBCacheSizeMB = 8
+	}
+	if cfg.DBMmapSizeMB < 0 {
+		cfg.DBMmapSizeMB = 0
+	}
+	if cfg.DBBusyTimeout <= 0 {
+		cfg.DBBusyTimeout = 30 * time.Second
+	}
+	if cfg.EventBatchSize < 0 {
+		cfg.EventBatchSize = 0
+	}
+	// Reports are aggregates; more than one a day tells nobody anything new
+	if cfg.TelemetryInterval < 24*time.Hour {
+		cfg.TelemetryInterval = 24 * time.Hour
+	}
+	if cfg.ScanFileQueueMemory < 0 {
+		cfg.ScanFileQueueMemory = 0
+	}
+	if cfg.ScanCheckpointFiles < 0 {
+		cfg.ScanCheckpointFiles = 0
+	}
+	if cfg.ScanCheckpointInterval < 0 {
+		cfg.ScanCheckpointInterval = 0
+	}
+	if cfg.ScanCheckpointFiles == 0 && cfg.ScanCheckpointInterval == 0 {
+		cfg.ScanCheckpointFiles = 10
+	}
+	if cfg.EventBatchInterval <= 0 {
+		cfg.EventBatchInterval = time.Second
+	}
+	// A sample has to fit one error and its surroundings, but is meant for sharing
+	if cfg.SampleMaxMB < 1 {
+		cfg.SampleMaxMB = 1
+	}
+	if cfg.SampleMaxMB > 64 {
+		cfg.SampleMaxMB = 64
+	}
+	if cfg.SampleDirMaxMB < cfg.SampleMaxMB {
+		cfg.SampleDirMaxMB = cfg.SampleMaxMB
+	}
+	if cfg.SampleRetentionDays < 1 {
+		cfg.SampleRetentionDays = 1
+	}
+	if strings.EqualFold(cfg.MaintenanceSchedule, "off") {
+		cfg.MaintenanceSchedule = ""
+	}
+
+	switch cfg.UntrackedFileAction {
+	case UntrackedFileNone, UntrackedFileDelete, UntrackedFileQuarantine:
+		// Valid
+	default:
+		cfg.UntrackedFileAction = UntrackedFileNone
+	}
+
+	switch cfg.RemediationBudgetAction {
+	case BudgetActionDefer, BudgetActionApprove:
+		// Valid
+	default:
+		cfg.RemediationBudgetAction = BudgetActionDefer
+	}
+
+	switch cfg.HDRMetadataPolicy {
+	case HDRMetadataOff, HDRMetadataFlag, HDRMetadataRemediate:
+		// Valid
+	default:
+		cfg.HDRMetadataPolicy = HDRMetadataFlag
+	}
+
+	switch cfg.DetectionConsensus {
+	case ConsensusOff, ConsensusAny, ConsensusMajority, ConsensusAll:
+		// Valid
+	default:
+		cfg.DetectionConsensus = ConsensusOff
+	}
+
+	switch cfg.DBJournalMode {
+	case "wal", "delete", "truncate", "persist":
+		// Valid
+	default:
+		cfg.DBJournalMode = "wal"
+	}
+
+	switch cfg.DBSynchronous {
+	case "full", "normal", "off":
+		// Valid
+	default:
+		cfg.DBSynchronous = "full"
+	}
+
+	// Clamp MQTT QoS to the valid range
+	if cfg.MQTTQoS < 0 || cfg.MQTTQoS > 2 {
+		cfg.MQTTQoS = 0
 	}
 
 	// Validate log level
@@ -283,12 +870,19 @@ func Load() *Config {
 		cfg.LogLevel = "info" // Fall back to info for invalid values
 	}
 
+	if locale, ok := i18n.Normalize(cfg.Locale); ok && locale != "" {
+		cfg.Locale = locale
+	} else {
+		cfg.Locale = i18n.English
+	}
+
 	return cfg
 }
 
 // LoadBasePathFromDB loads the base path from the database if not set via environment.
 // Should be called after database is initialized.
 func LoadBasePathFromDB(db *sql.DB) {
+	cfg := current.Load()
 	if cfg == nil {
 		return
 	}
@@ -318,16 +912,17 @@ func LoadBasePathFromDB(db *sql.DB) {
 
 // Get returns the current configuration. Panics if Load() hasn't been called.
 func Get() *Config {
-	if cfg == nil {
+	c := current.Load()
+	if c == nil {
 		panic("config.Load() must be called before config.Get()")
 	}
-	return cfg
+	return c
 }
 
 // SetForTesting allows tests to set the global config without calling Load().
 // This should ONLY be used in test code.
 func SetForTesting(c *Config) {
-	cfg = c
+	current.Store(c)
 }
 
 // NewTestConfig returns a minimal Config suitable for unit tests.
@@ -337,6 +932,7 @@ func NewTestConfig() *Config {
 		BasePath:             "/",
 		BasePathSource:       "test",
 		LogLevel:             "debug",
+		Locale:               "en",
 		VerificationTimeout:  72 * time.Hour,
 		VerificationInterval: 30 * time.Second,
 		StaleThreshold:       24 * time.Hour,
@@ -344,21 +940,91 @@ func NewTestConfig() *Config {
 		DryRunMode:           false,
 		ArrRateLimitRPS:      5,
 		ArrRateLimitBurst:    10,
-		RetentionDays:        90,
+		RetentionDays:          90,
+		ResolvedRetentionDays:  -1,
+		FailedRetentionDays:    -1,
+		ScanEventRetentionDays: -1,
+		ScanRetentionDays:      -1,
+		DeletedRetentionDays:   30,
+		ScanResultsPerPath:   10,
+		ScanFileQueueMemory:  10000,
+		ScanCheckpointFiles:    10,
+		ScanCheckpointInterval: 30 * time.Second,
+		ScheduledScanConcurrency: 0,
+		ScheduledScanPreemption:  false,
 		DataDir:              "/tmp/healarr-test",
 		DatabasePath:         "/tmp/healarr-test/healarr.db",
+		BackupEncryptionKey:  "",
 		LogDir:               "/tmp/healarr-test/logs",
 		WebDir:               "",
 		FFprobePath:          "ffprobe",
 		FFmpegPath:           "ffmpeg",
 		MediaInfoPath:        "mediainfo",
 		HandBrakePath:        "HandBrakeCLI",
+		SampleSegments:       10,
+		SampleDuration:       30 * time.Second,
+		TSMaxContinuityErrors: 100,
+		TSMaxDecodeErrors:     50,
+		TSMaxTimestampGap:     10 * time.Second,
+		ToolMaxRSSMB:         4096,
+		ToolRetries:          1,
+		ToolFailureThreshold: 3,
+		ToolDisableDuration:  15 * time.Minute,
+		MQTTClientID:         "healarr",
+		MQTTTopicPrefix:      "healarr",
+		MQTTEvents:           defaultMQTTEvents,
+		MQTTDiscovery:        true,
+		MQTTDiscoveryPrefix:  "homeassistant",
+		SuppressFalsePositives: true,
+		VerifyAudioTracks:      false,
+		DownloadPrevalidation:  false,
+		DownloadPathMappings:   "",
+		RemediationMaxConcurrent:   5,
+		RemediationSearchesPerHour: 0,
+		RemediationWindow:          "",
+		RemediationMonthlyBudgetGB: 0,
+		RemediationBudgetAction:    BudgetActionDefer,
+		ResolutionSLA:              0,
+		AlertScanOverdue:           7 * 24 * time.Hour,
+		ArrHealthInterval:          5 * time.Minute,
+		ReportSchedule:             "",
+		ResolutionAuditSchedule:    "",
+		ResolutionAuditDays:        30,
+		ResolutionAuditSample:      20,
+		MaintenanceSchedule:        "0 3 * * *",
+		MaintenancePeakHours:       "",
+		DetectionOnlyAfter:         15 * time.Minute,
+		DBReadConnections:          4,
+		DBReadBusyTimeout:          5 * time.Second,
+		DBJournalMode:              "wal",
+		DBSynchronous:              "full",
+		DBCacheSizeMB:              8,
+		DBMmapSizeMB:               0,
+		DBBusyTimeout:              30 * time.Second,
+		EventBatchSize:             0,
+		EventBatchInterval:         time.Second,
+		StrictEventValidation:      true,
+		PublicDashboard:            false,
+		UntrackedFileAction:        UntrackedFileNone,
+		HDRMetadataPolicy:          HDRMetadataFlag,
+		DetectionConsensus:         ConsensusOff,
+		QuarantineDir:              "/tmp/healarr-test/quarantine",
+		SampleExtraction:           false,
+		SampleDir:                  "/tmp/healarr-test/samples",
+		SampleMaxMB:                8,
+		SampleDirMaxMB:             512,
+		SampleRetentionDays:        14,
+		TestMode:                   false,
+		OfflineMode:                false,
+		TelemetryDisabled:          true,
+		TelemetryURL:               "",
+		TelemetryInterval:          7 * 24 * time.Hour,
 	}
 }
 
 // getEnvOrDefault returns the environment variable value or the default if not set.
 func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
@@ -366,7 +1032,7 @@ func getEnvOrDefault(key, defaultValue string) string {
 
 // getEnvIntOrDefault returns the environment variable as an int or the default if not set/invalid.
 func getEnvIntOrDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if i, err := strconv.Atoi(value); err == nil {
 			return i
 		}
@@ -377,7 +1043,7 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 // getEnvDurationOrDefault returns the environment variable as a duration or the default if not set/invalid.
 // Accepts Go duration strings like "30s", "5m", "72h".
 func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if d, err := time.ParseDuration(value); err == nil {
 			return d
 		}
@@ -388,7 +1054,7 @@ func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Durati
 // getEnvBoolOrDefault returns the environment variable as a bool or the default if not set.
 // Accepts "true", "1", "yes" as true values (case-insensitive).
 func getEnvBoolOrDefault(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		lower := strings.ToLower(value)
 		return lower == "true" || lower == "1" || lower == "yes"
 	}
@@ -397,7 +1063,7 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 
 // getEnvFloatOrDefault returns the environment variable as a float64 or the default if not set/invalid.
 func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if f, err := strconv.ParseFloat(value, 64); err == nil {
 			return f
 		}
@@ -415,6 +1081,8 @@ type FlagOverrides struct {
 	StaleThreshold       *time.Duration
 	DefaultMaxRetries    *int
 	DryRunMode           *bool
+	TestMode             *bool
+	OfflineMode          *bool
 	ArrRateLimitRPS      *float64
 	ArrRateLimitBurst    *int
 	RetentionDays        *int
@@ -452,7 +1120,7 @@ func applyFloatFlag(target, flag *float64) {
 }
 
 // applyBasePathFlag applies a base path flag with normalization.
-func applyBasePathFlag(flags FlagOverrides) {
+func applyBasePathFlag(cfg *Config, flags FlagOverrides) {
 	if flags.BasePath == nil || *flags.BasePath == "" {
 		return
 	}
@@ -471,12 +1139,18 @@ func applyBasePathFlag(flags FlagOverrides) {
 // Should be called after Load() and after flag parsing.
 // Only non-nil values with non-default flag values will override.
 func ApplyFlags(flags FlagOverrides) {
+	cfg := current.Load()
 	if cfg == nil {
 		return
 	}
+	appliedFlags = flags
+	applyFlags(cfg, flags)
+}
 
+// applyFlags applies flag overrides to cfg.
+func applyFlags(cfg *Config, flags FlagOverrides) {
 	applyStringFlag(&cfg.Port, flags.Port)
-	applyBasePathFlag(flags)
+	applyBasePathFlag(cfg, flags)
 	if flags.LogLevel != nil && *flags.LogLevel != "" {
 		cfg.LogLevel = strings.ToLower(*flags.LogLevel)
 	}
@@ -487,6 +1161,12 @@ func ApplyFlags(flags FlagOverrides) {
 	if flags.DryRunMode != nil {
 		cfg.DryRunMode = *flags.DryRunMode
 	}
+	if flags.TestMode != nil && *flags.TestMode {
+		cfg.TestMode = true
+	}
+	if flags.OfflineMode != nil && *flags.OfflineMode {
+		cfg.OfflineMode = true
+	}
 	applyFloatFlag(&cfg.ArrRateLimitRPS, flags.ArrRateLimitRPS)
 	applyIntFlag(&cfg.ArrRateLimitBurst, flags.ArrRateLimitBurst)
 	if flags.RetentionDays != nil {
@@ -555,12 +1235,54 @@ func validateDataDir(dataDir string, inDocker bool) *ConfigWarning {
 	return nil
 }
 
+// validateTrustedProxies reports the trusted proxies that are neither an IP
+// nor a CIDR; they are ignored.
+func validateTrustedProxies(proxies []string) *ConfigWarning {
+	var invalid []string
+	for _, proxy := range proxies {
+		if !ValidProxyEntry(proxy) {
+			invalid = append(invalid, proxy)
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return &ConfigWarning{
+		Type:        "invalid_proxy",
+		Field:       "trusted_proxies",
+		Message:     "Trusted proxies must be IP addresses or CIDRs - these entries are ignored",
+		Current:     strings.Join(invalid, ","),
+		Recommended: "172.16.0.0/12",
+	}
+}
+
+// ValidProxyEntry reports whether entry is an IP address or a CIDR.
+func ValidProxyEntry(entry string) bool {
+	if _, _, err := net.ParseCIDR(entry); err == nil {
+		return true
+	}
+	return net.ParseIP(entry) != nil
+}
+
+// splitList splits a comma-separated list, trimming spaces and dropping
+// empty entries.
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // ValidateAndWarn checks the configuration for potential issues and logs warnings.
 // This should be called after Load() and ApplyFlags(), typically from main.go.
 // Returns the list of warnings for use by the health API.
 func ValidateAndWarn() []ConfigWarning {
 	configWarnings = nil // Reset warnings
 
+	cfg := current.Load()
 	if cfg == nil {
 		return nil
 	}
@@ -577,6 +1299,11 @@ func ValidateAndWarn() []ConfigWarning {
 		configWarnings = append(configWarnings, *warning)
 	}
 
+	// Check trusted proxies for entries that are neither IPs nor CIDRs
+	if warning := validateTrustedProxies(cfg.TrustedProxies); warning != nil {
+		configWarnings = append(configWarnings, *warning)
+	}
+
 	// Log warnings prominently
 	if len(configWarnings) > 0 {
 		fmt.Fprintln(os.Stderr, "")
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -285,8 +286,8 @@ func TestNewTestConfig(t *testing.T) {
 
 func TestSetForTesting(t *testing.T) {
 	// Save original
-	original := cfg
-	defer func() { cfg = original }()
+	original := current.Load()
+	defer func() { current.Store(original) }()
 
 	testCfg := &Config{Port: "9999"}
 	SetForTesting(testCfg)
@@ -303,9 +304,9 @@ func TestSetForTesting(t *testing.T) {
 
 func TestGet_PanicsWhenNotLoaded(t *testing.T) {
 	// Save and clear global config
-	original := cfg
-	cfg = nil
-	defer func() { cfg = original }()
+	original := current.Load()
+	current.Store(nil)
+	defer func() { current.Store(original) }()
 
 	defer func() {
 		if recover() == nil {
@@ -318,9 +319,9 @@ func TestGet_PanicsWhenNotLoaded(t *testing.T) {
 
 func TestGet_ReturnsConfig(t *testing.T) {
 	testCfg := &Config{Port: "7777"}
-	original := cfg
-	cfg = testCfg
-	defer func() { cfg = original }()
+	original := current.Load()
+	current.Store(testCfg)
+	defer func() { current.Store(original) }()
 
 	got := Get()
 	if got != testCfg {
@@ -479,6 +480,43 @@ func TestLoad_InvalidLogLevel(t *testing.T) {
 	}
 }
 
+func TestLoad_Locale(t *testing.T) {
+	tests := map[string]string{"": "en", "de-DE": "de", "FR": "fr", "es": "es", "it": "en"}
+	for value, want := range tests {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("HEALARR_DATA_DIR", t.TempDir())
+			t.Setenv("HEALARR_LOCALE", value)
+
+			if c := Load(); c.Locale != want {
+				t.Errorf("HEALARR_LOCALE=%q: Locale = %s, want %s", value, c.Locale, want)
+			}
+		})
+	}
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	t.Setenv("HEALARR_DATA_DIR", t.TempDir())
+	t.Setenv("HEALARR_TRUSTED_PROXIES", " 10.0.0.1, 172.16.0.0/12,,traefik ")
+	t.Setenv("HEALARR_LISTEN_SOCKET", "/run/healarr/healarr.sock")
+
+	c := Load()
+	want := []string{"10.0.0.1", "172.16.0.0/12", "traefik"}
+	if strings.Join(c.TrustedProxies, ",") != strings.Join(want, ",") {
+		t.Errorf("TrustedProxies = %v, want %v", c.TrustedProxies, want)
+	}
+	if c.ListenSocket != "/run/healarr/healarr.sock" {
+		t.Errorf("ListenSocket = %q", c.ListenSocket)
+	}
+
+	warning := validateTrustedProxies(c.TrustedProxies)
+	if warning == nil || warning.Current != "traefik" {
+		t.Errorf("Expected a warning about the host name, got %+v", warning)
+	}
+	if validateTrustedProxies(want[:2]) != nil {
+		t.Error("Expected no warning for IPs and CIDRs")
+	}
+}
+
 func TestLoad_ValidLogLevels(t *testing.T) {
 	for _, level := range []string{"debug", "info", "error"} {
 		t.Run(level, func(t *testing.T) {
@@ -501,9 +539,9 @@ func TestLoad_ValidLogLevels(t *testing.T) {
 func TestLoadBasePathFromDB_NotLoaded(t *testing.T) {
 	t.Helper() // Mark as helper to use t parameter
 	// Save and clear global config
-	original := cfg
-	cfg = nil
-	defer func() { cfg = original }()
+	original := current.Load()
+	current.Store(nil)
+	defer func() { current.Store(original) }()
 
 	// Should not panic
 	LoadBasePathFromDB(nil)
@@ -596,9 +634,9 @@ func TestLoadBasePathFromDB_NormalizesPath(t *testing.T) {
 
 func TestApplyFlags_NilConfig(t *testing.T) {
 	t.Helper() // Mark as helper to use t parameter
-	original := cfg
-	cfg = nil
-	defer func() { cfg = original }()
+	original := current.Load()
+	current.Store(nil)
+	defer func() { current.Store(original) }()
 
 	// Should not panic
 	ApplyFlags(FlagOverrides{})
@@ -607,7 +645,7 @@ func TestApplyFlags_NilConfig(t *testing.T) {
 func TestApplyFlags_AllFlags(t *testing.T) {
 	c := NewTestConfig()
 	SetForTesting(c)
-	defer func() { cfg = nil }()
+	defer func() { current.Store(nil) }()
 
 	port := "9999"
 	basePath := "/flagged"
@@ -616,6 +654,8 @@ func TestApplyFlags_AllFlags(t *testing.T) {
 	interval := 1 * time.Minute
 	retries := 10
 	dryRun := true
+	testMode := true
+	offline := true
 	rps := 20.0
 	burst := 50
 	retention := 7
@@ -631,6 +671,8 @@ func TestApplyFlags_AllFlags(t *testing.T) {
 		VerificationInterval: &interval,
 		DefaultMaxRetries:    &retries,
 		DryRunMode:           &dryRun,
+		TestMode:             &testMode,
+		OfflineMode:          &offline,
 		ArrRateLimitRPS:      &rps,
 		ArrRateLimitBurst:    &burst,
 		RetentionDays:        &retention,
@@ -663,6 +705,12 @@ func TestApplyFlags_AllFlags(t *testing.T) {
 	if c.DryRunMode != true {
 		t.Error("DryRunMode should be true")
 	}
+	if !c.TestMode {
+		t.Error("TestMode should be true")
+	}
+	if !c.OfflineMode {
+		t.Error("OfflineMode should be true")
+	}
 	if c.ArrRateLimitRPS != 20.0 {
 		t.Errorf("ArrRateLimitRPS = %v, want 20.0", c.ArrRateLimitRPS)
 	}
@@ -687,7 +735,7 @@ func TestApplyFlags_EmptyStringsNotApplied(t *testing.T) {
 	c := NewTestConfig()
 	c.Port = "original"
 	SetForTesting(c)
-	defer func() { cfg = nil }()
+	defer func() { current.Store(nil) }()
 
 	empty := ""
 	ApplyFlags(FlagOverrides{
@@ -704,7 +752,7 @@ func TestApplyFlags_ZeroValuesNotApplied(t *testing.T) {
 	c.VerificationTimeout = 72 * time.Hour
 	c.DefaultMaxRetries = 5
 	SetForTesting(c)
-	defer func() { cfg = nil }()
+	defer func() { current.Store(nil) }()
 
 	zero := 0
 	zeroDuration := time.Duration(0)
@@ -724,7 +772,7 @@ func TestApplyFlags_ZeroValuesNotApplied(t *testing.T) {
 func TestApplyFlags_BasePathNormalization(t *testing.T) {
 	c := NewTestConfig()
 	SetForTesting(c)
-	defer func() { cfg = nil }()
+	defer func() { current.Store(nil) }()
 
 	path := "no-slash/"
 	ApplyFlags(FlagOverrides{
@@ -753,6 +801,91 @@ func TestLoad_CreatesDataDirectory(t *testing.T) {
 	}
 }
 
+func TestLoad_UntrackedFileAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HEALARR_DATA_DIR", tmpDir)
+	t.Setenv("HEALARR_BASE_PATH", "")
+
+	t.Setenv("HEALARR_UNTRACKED_FILE_ACTION", "")
+	c := Load()
+	if c.UntrackedFileAction != UntrackedFileNone {
+		t.Errorf("UntrackedFileAction = %q, want %q", c.UntrackedFileAction, UntrackedFileNone)
+	}
+	if c.QuarantineDir != filepath.Join(tmpDir, "quarantine") {
+		t.Errorf("QuarantineDir = %q, want <DataDir>/quarantine", c.QuarantineDir)
+	}
+
+	t.Setenv("HEALARR_UNTRACKED_FILE_ACTION", "Quarantine")
+	if c := Load(); c.UntrackedFileAction != UntrackedFileQuarantine {
+		t.Errorf("UntrackedFileAction = %q, want %q", c.UntrackedFileAction, UntrackedFileQuarantine)
+	}
+
+	t.Setenv("HEALARR_UNTRACKED_FILE_ACTION", "shred")
+	if c := Load(); c.UntrackedFileAction != UntrackedFileNone {
+		t.Errorf("Invalid action should fall back to %q, got %q", UntrackedFileNone, c.UntrackedFileAction)
+	}
+}
+
+func TestLoad_RemediationBudgetAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HEALARR_DATA_DIR", tmpDir)
+	t.Setenv("HEALARR_BASE_PATH", "")
+
+	t.Setenv("HEALARR_REMEDIATION_BUDGET_ACTION", "")
+	if c := Load(); c.RemediationBudgetAction != BudgetActionDefer {
+		t.Errorf("RemediationBudgetAction = %q, want %q", c.RemediationBudgetAction, BudgetActionDefer)
+	}
+
+	t.Setenv("HEALARR_REMEDIATION_BUDGET_ACTION", "Approve")
+	if c := Load(); c.RemediationBudgetAction != BudgetActionApprove {
+		t.Errorf("RemediationBudgetAction = %q, want %q", c.RemediationBudgetAction, BudgetActionApprove)
+	}
+
+	t.Setenv("HEALARR_REMEDIATION_BUDGET_ACTION", "drop")
+	if c := Load(); c.RemediationBudgetAction != BudgetActionDefer {
+		t.Errorf("Invalid action should fall back to %q, got %q", BudgetActionDefer, c.RemediationBudgetAction)
+	}
+}
+
+func TestLoad_DetectionConsensus(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HEALARR_DATA_DIR", tmpDir)
+	t.Setenv("HEALARR_BASE_PATH", "")
+
+	t.Setenv("HEALARR_DETECTION_CONSENSUS", "")
+	if c := Load(); c.DetectionConsensus != ConsensusOff {
+		t.Errorf("DetectionConsensus = %q, want %q", c.DetectionConsensus, ConsensusOff)
+	}
+
+	t.Setenv("HEALARR_DETECTION_CONSENSUS", "Majority")
+	if c := Load(); c.DetectionConsensus != ConsensusMajority {
+		t.Errorf("DetectionConsensus = %q, want %q", c.DetectionConsensus, ConsensusMajority)
+	}
+
+	t.Setenv("HEALARR_DETECTION_CONSENSUS", "most")
+	if c := Load(); c.DetectionConsensus != ConsensusOff {
+		t.Errorf("Invalid policy should fall back to %q, got %q", ConsensusOff, c.DetectionConsensus)
+	}
+}
+
+func TestLoad_ScanCheckpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HEALARR_DATA_DIR", tmpDir)
+	t.Setenv("HEALARR_BASE_PATH", "")
+
+	t.Setenv("HEALARR_SCAN_CHECKPOINT_FILES", "0")
+	t.Setenv("HEALARR_SCAN_CHECKPOINT_INTERVAL", "1m")
+	if c := Load(); c.ScanCheckpointFiles != 0 || c.ScanCheckpointInterval != time.Minute {
+		t.Errorf("Checkpoint = %d files, %v, want 0 files, 1m", c.ScanCheckpointFiles, c.ScanCheckpointInterval)
+	}
+
+	// Disabling both triggers falls back to every 10 files
+	t.Setenv("HEALARR_SCAN_CHECKPOINT_INTERVAL", "0s")
+	if c := Load(); c.ScanCheckpointFiles != 10 || c.ScanCheckpointInterval != 0 {
+		t.Errorf("Checkpoint = %d files, %v, want 10 files, 0s", c.ScanCheckpointFiles, c.ScanCheckpointInterval)
+	}
+}
+
 func TestLoad_CreatesLogDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Setenv("HEALARR_DATA_DIR", tmpDir)
@@ -956,7 +1089,7 @@ func TestGetWarnings(t *testing.T) {
 
 func TestValidateAndWarn_NilConfig(t *testing.T) {
 	// Set config to nil
-	cfg = nil
+	current.Store(nil)
 
 	warnings := ValidateAndWarn()
 
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// envFileVar names the optional env file. Its variables are read on Load and
+// again on Reload; variables set in the process environment take precedence.
+const envFileVar = "HEALARR_ENV_FILE"
+
+// envFile holds the variables read from the env file.
+var envFile map[string]string
+
+// reloadMu serializes reloads, which read and rewrite envFile and the
+// current configuration.
+var reloadMu sync.Mutex
+
+// appliedFlags are the flag overrides passed to ApplyFlags, re-applied on
+// Reload so flags keep taking precedence over the env file.
+var appliedFlags FlagOverrides
+
+// reloadableFields are the Config fields Reload applies to the running
+// configuration. Changes to other fields are reported but need a restart.
+var reloadableFields = map[string]bool{
+	"LogLevel":                   true,
+	"ArrRateLimitRPS":            true,
+	"ArrRateLimitBurst":          true,
+	"RemediationMaxConcurrent":   true,
+	"RemediationSearchesPerHour": true,
+	"RemediationWindow":          true,
+	"ReportSchedule":             true,
+	"MQTTBroker":                 true,
+	"MQTTUsername":               true,
+	"MQTTPassword":               true,
+	"MQTTClientID":               true,
+	"MQTTTopicPrefix":            true,
+	"MQTTQoS":                    true,
+	"MQTTEvents":                 true,
+	"MQTTCAFile":                 true,
+	"MQTTTLSInsecure":            true,
+	"MQTTDiscovery":              true,
+	"MQTTDiscoveryPrefix":        true,
+}
+
+// secretFields are not logged in a Change.
+var secretFields = map[string]bool{
+	"MQTTPassword":        true,
+	"BackupEncryptionKey": true,
+}
+
+// Change is a Config field that differs after a reload. Old and New are
+// masked for secrets.
+type Change struct {
+	Field   string
+	Old     string
+	New     string
+	Applied bool // false if the change needs a restart
+}
+
+// String formats the change as key=value pairs for the log.
+func (c Change) String() string {
+	return fmt.Sprintf("field=%s old=%q new=%q applied=%t", c.Field, c.Old, c.New, c.Applied)
+}
+
+// lookupEnv returns a variable from the process environment or the env file.
+func lookupEnv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return envFile[key]
+}
+
+// loadEnvFile reads the env file named by HEALARR_ENV_FILE, if any. The
+// previously read variables are kept if it can't be read.
+func loadEnvFile() error {
+	path := os.Getenv(envFileVar)
+	if path == "" {
+		envFile = nil
+		return nil
+	}
+	vars, err := readEnvFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s: %w", envFileVar, path, err)
+	}
+	envFile = vars
+	return nil
+}
+
+// readEnvFile parses KEY=VALUE lines. Blank lines, # comments and an
+// "export " prefix are ignored and values may be quoted.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is set by the administrator
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	return vars, scanner.Err()
+}
+
+// Reload re-reads the env file and the environment and applies the changes to
+// reloadable fields. It returns every field that changed; the caller
+// re-initializes the components affected by the applied ones. On error the
+// current configuration is kept.
+func Reload() ([]Change, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	cfg := current.Load()
+	if cfg == nil {
+		return nil, errors.New("config.Load() must be called before config.Reload()")
+	}
+	if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	loaded := build()
+	applyFlags(loaded, appliedFlags)
+
+	next := *cfg
+	changes := diff(cfg, loaded)
+	for i, change := range changes {
+		if !reloadableFields[change.Field] {
+			continue
+		}
+		field := reflect.ValueOf(&next).Elem().FieldByName(change.Field)
+		field.Set(reflect.ValueOf(loaded).Elem().FieldByName(change.Field))
+		changes[i].Applied = true
+	}
+	// Readers that already called Get keep the previous values
+	current.Store(&next)
+	return changes, nil
+}
+
+// diff returns the fields that differ between two configurations. The base
+// path may come from the database and is only compared when both came from
+// the environment or flags.
+func diff(before, after *Config) []Change {
+	var changes []Change
+	b, a := reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem()
+	for i := 0; i < b.NumField(); i++ {
+		name := b.Type().Field(i).Name
+		if name == "BasePath" || name == "BasePathSource" {
+			if before.BasePathSource == "database" {
+				continue
+			}
+		}
+		oldValue, newValue := fmt.Sprint(b.Field(i).Interface()), fmt.Sprint(a.Field(i).Interface())
+		if oldValue == newValue {
+			continue
+		}
+		if secretFields[name] {
+			oldValue, newValue = redact.Value(oldValue), redact.Value(newValue)
+		}
+		changes = append(changes, Change{Field: name, Old: oldValue, New: newValue})
+	}
+	return changes
+}
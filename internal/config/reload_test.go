@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+func writeEnvFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "healarr.env")
+	writeEnvFile(t, path, `
+# Log settings
+HEALARR_LOG_LEVEL=debug
+export HEALARR_PORT = 4000
+HEALARR_MQTT_PASSWORD="p#ss=word"
+HEALARR_MQTT_USERNAME='healarr'
+`)
+
+	vars, err := readEnvFile(path)
+	if err != nil {
+		t.Fatalf("readEnvFile failed: %v", err)
+	}
+	want := map[string]string{
+		"HEALARR_LOG_LEVEL":     "debug",
+		"HEALARR_PORT":          "4000",
+		"HEALARR_MQTT_PASSWORD": "p#ss=word",
+		"HEALARR_MQTT_USERNAME": "healarr",
+	}
+	if len(vars) != len(want) {
+		t.Fatalf("readEnvFile() = %v, want %v", vars, want)
+	}
+	for key, value := range want {
+		if vars[key] != value {
+			t.Errorf("%s = %q, want %q", key, vars[key], value)
+		}
+	}
+
+	writeEnvFile(t, path, "HEALARR_LOG_LEVEL=debug\nnot a variable\n")
+	if _, err := readEnvFile(path); err == nil {
+		t.Error("Expected an error for a line without '='")
+	}
+}
+
+func TestReload(t *testing.T) {
+	defer SetForTesting(nil)
+	defer func() { envFile, appliedFlags = nil, FlagOverrides{} }()
+
+	for _, v := range []string{"HEALARR_PORT", "HEALARR_LOG_LEVEL", "HEALARR_ARR_RATE_LIMIT_RPS", "HEALARR_MQTT_PASSWORD"} {
+		t.Setenv(v, "")
+	}
+	t.Setenv("HEALARR_DATA_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "healarr.env")
+	t.Setenv(envFileVar, path)
+
+	writeEnvFile(t, path, "HEALARR_LOG_LEVEL=info\nHEALARR_PORT=3090\n")
+	Load()
+	ApplyFlags(FlagOverrides{})
+	if got := Get().LogLevel; got != "info" {
+		t.Fatalf("LogLevel = %s, want info", got)
+	}
+
+	writeEnvFile(t, path, "HEALARR_LOG_LEVEL=debug\nHEALARR_PORT=4000\nHEALARR_MQTT_PASSWORD=secret\n")
+	changes, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	got := make(map[string]Change)
+	for _, change := range changes {
+		got[change.Field] = change
+	}
+	if len(got) != 3 {
+		t.Fatalf("Reload() changes = %v, want LogLevel, Port and MQTTPassword", changes)
+	}
+	if c := got["LogLevel"]; !c.Applied || c.Old != "info" || c.New != "debug" {
+		t.Errorf("LogLevel change = %+v", c)
+	}
+	if c := got["Port"]; c.Applied {
+		t.Errorf("Port change should need a restart: %+v", c)
+	}
+	if c := got["MQTTPassword"]; !c.Applied || c.New != redact.Mask {
+		t.Errorf("MQTTPassword change = %+v, want applied and masked", c)
+	}
+	if cfg := Get(); cfg.LogLevel != "debug" || cfg.Port != "3090" || cfg.MQTTPassword != "secret" {
+		t.Errorf("After reload LogLevel=%s Port=%s MQTTPassword=%s, want debug, 3090 and secret", cfg.LogLevel, cfg.Port, cfg.MQTTPassword)
+	}
+
+	// The process environment and flags take precedence over the env file
+	t.Setenv("HEALARR_ARR_RATE_LIMIT_RPS", "2")
+	writeEnvFile(t, path, "HEALARR_LOG_LEVEL=error\nHEALARR_ARR_RATE_LIMIT_RPS=9\n")
+	level := "debug"
+	appliedFlags.LogLevel = &level
+	if _, err := Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if cfg := Get(); cfg.LogLevel != "debug" || cfg.ArrRateLimitRPS != 2 {
+		t.Errorf("After reload LogLevel=%s ArrRateLimitRPS=%v, want debug and 2", cfg.LogLevel, cfg.ArrRateLimitRPS)
+	}
+
+	// A broken env file keeps the configuration
+	writeEnvFile(t, path, "broken\n")
+	if _, err := Reload(); err == nil {
+		t.Error("Expected Reload to fail for an invalid env file")
+	}
+	if got := Get().LogLevel; got != "debug" {
+		t.Errorf("LogLevel = %s after a failed reload, want debug", got)
+	}
+}
+
+func TestReload_ConcurrentGet(t *testing.T) {
+	defer SetForTesting(nil)
+	defer func() { envFile, appliedFlags = nil, FlagOverrides{} }()
+
+	t.Setenv("HEALARR_DATA_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "healarr.env")
+	t.Setenv(envFileVar, path)
+	writeEnvFile(t, path, "HEALARR_LOG_LEVEL=info\n")
+	Load()
+
+	// Run with -race: readers must never see the configuration being swapped
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_ = Get().LogLevel
+		}
+	}()
+	for _, level := range []string{"debug", "warn", "info"} {
+		writeEnvFile(t, path, "HEALARR_LOG_LEVEL="+level+"\n")
+		if _, err := Reload(); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	}
+	<-done
+	if got := Get().LogLevel; got != "info" {
+		t.Errorf("LogLevel = %q after the last reload, want info", got)
+	}
+}
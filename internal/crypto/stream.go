@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted streams, used for database backups, start with streamMagic and a
+// random salt. The AES-256 key is derived from a passphrase and the salt with
+// scrypt, so every stream has its own key. The data follows in chunks sealed
+// with AES-GCM; a chunk's nonce is its index plus a flag marking the last
+// chunk, so reordered, dropped or truncated chunks fail to decrypt.
+const (
+	streamMagic     = "HLRBAK\x00\x01"
+	streamChunkSize = 64 << 10
+	streamSaltSize  = 16
+	streamHeaderLen = len(streamMagic) + streamSaltSize
+)
+
+// ErrNotEncryptedStream is returned when decrypting data that doesn't start
+// with the encrypted stream header.
+var ErrNotEncryptedStream = errors.New("not an encrypted Healarr backup")
+
+// EncryptedStreamSize returns the size of an encrypted stream of n bytes.
+func EncryptedStreamSize(n int64) int64 {
+	chunks := n/streamChunkSize + 1 // the last chunk may be empty
+	return int64(streamHeaderLen) + n + chunks*16
+}
+
+// streamCipher derives the stream's key from the passphrase and salt.
+func streamCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, ErrNoEncryptionKey
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// streamNonce returns the nonce of the chunk with the given index.
+func streamNonce(size int, index uint64, last bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:size-1], index)
+	if last {
+		nonce[size-1] = 1
+	}
+	return nonce
+}
+
+// EncryptStream encrypts src to dst with a key derived from passphrase.
+func EncryptStream(dst io.Writer, src io.Reader, passphrase string) error {
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	aead, err := streamCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := dst.Write(append([]byte(streamMagic), salt...)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamChunkSize)
+	sealed := make([]byte, 0, streamChunkSize+aead.Overhead())
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(src, buf)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return err
+		}
+		sealed = aead.Seal(sealed[:0], streamNonce(aead.NonceSize(), index, last), buf[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// DecryptStream decrypts a stream written by EncryptStream to dst. It fails
+// without writing the rest if a chunk was modified or the stream is truncated.
+func DecryptStream(dst io.Writer, src io.Reader, passphrase string) error {
+	header := make([]byte, streamHeaderLen)
+	if _, err := io.ReadFull(src, header); err != nil || string(header[:len(streamMagic)]) != streamMagic {
+		return ErrNotEncryptedStream
+	}
+	aead, err := streamCipher(passphrase, header[len(streamMagic):])
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamChunkSize+aead.Overhead())
+	plain := make([]byte, 0, streamChunkSize)
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(src, buf)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return err
+		}
+		plain, err = aead.Open(plain[:0], streamNonce(aead.NonceSize(), index, last), buf[:n], nil)
+		if err != nil {
+			return fmt.Errorf("%w: wrong passphrase or damaged backup at chunk %d", ErrDecryptFailed, index)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// =============================================================================
+// Encrypted stream tests
+// =============================================================================
+
+func TestEncryptStream_Roundtrip(t *testing.T) {
+	for _, size := range []int{0, 1, streamChunkSize - 1, streamChunkSize, 3*streamChunkSize + 17} {
+		plain := bytes.Repeat([]byte("healarr"), size/7+1)[:size]
+
+		var encrypted bytes.Buffer
+		if err := EncryptStream(&encrypted, bytes.NewReader(plain), "passphrase"); err != nil {
+			t.Fatalf("size %d: EncryptStream failed: %v", size, err)
+		}
+		if got, want := int64(encrypted.Len()), EncryptedStreamSize(int64(size)); got != want {
+			t.Errorf("size %d: encrypted size = %d, EncryptedStreamSize = %d", size, got, want)
+		}
+
+		var decrypted bytes.Buffer
+		if err := DecryptStream(&decrypted, bytes.NewReader(encrypted.Bytes()), "passphrase"); err != nil {
+			t.Fatalf("size %d: DecryptStream failed: %v", size, err)
+		}
+		if !bytes.Equal(decrypted.Bytes(), plain) {
+			t.Errorf("size %d: decrypted data differs from the original", size)
+		}
+	}
+}
+
+func TestDecryptStream_Rejects(t *testing.T) {
+	plain := bytes.Repeat([]byte{1}, 2*streamChunkSize+5)
+	var encrypted bytes.Buffer
+	if err := EncryptStream(&encrypted, bytes.NewReader(plain), "passphrase"); err != nil {
+		t.Fatalf("EncryptStream failed: %v", err)
+	}
+	data := encrypted.Bytes()
+
+	tampered := append([]byte(nil), data...)
+	tampered[streamHeaderLen+10] ^= 1
+	// Truncated at a chunk boundary, so every remaining chunk is intact
+	truncated := data[:streamHeaderLen+2*(streamChunkSize+16)]
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		want       error
+	}{
+		{"wrong passphrase", data, "other", ErrDecryptFailed},
+		{"tampered", tampered, "passphrase", ErrDecryptFailed},
+		{"truncated", truncated, "passphrase", ErrDecryptFailed},
+		{"not encrypted", plain, "passphrase", ErrNotEncryptedStream},
+		{"no passphrase", data, "", ErrNoEncryptionKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DecryptStream(&bytes.Buffer{}, bytes.NewReader(tt.data), tt.passphrase)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("DecryptStream() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+// Relations between corruptions recorded in corruption_links.
+const (
+	RelationMedia    = "media"    // same movie or series
+	RelationSeason   = "season"   // same season of a series
+	RelationDownload = "download" // replaced by the same download, e.g. a season pack
+)
+
+// ProjectCorruptionLinks records the media item, season and download a
+// corruption event names in corruption_links; corruptions with the same link
+// are related. It must run after ProjectCorruptionSummary, whose path_id
+// scopes the *arr media IDs.
+func ProjectCorruptionLinks(tx *sql.Tx, event domain.Event) error {
+	if event.AggregateType != "corruption" {
+		return nil
+	}
+	links := make(map[string]string, 3)
+	if downloadID := event.GetStringOr("download_id", ""); downloadID != "" {
+		links[RelationDownload] = downloadID
+	}
+	if mediaID := event.GetInt64Or("media_id", 0); mediaID > 0 {
+		var pathID sql.NullInt64
+		err := tx.QueryRow(`SELECT path_id FROM corruption_summary WHERE corruption_id = ?`, event.AggregateID).Scan(&pathID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look up the path of %s: %w", event.AggregateID, err)
+		}
+		if pathID.Valid {
+			links[RelationMedia] = fmt.Sprintf("%d:%d", pathID.Int64, mediaID)
+			if season := event.GetInt64Or("season_number", 0); season > 0 {
+				links[RelationSeason] = fmt.Sprintf("%d:%d:%d", pathID.Int64, mediaID, season)
+			}
+		}
+	}
+	for relation, key := range links {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO corruption_links (corruption_id, relation, link_key, created_at) VALUES (?, ?, ?, ?)
+		`, event.AggregateID, relation, key, event.CreatedAt); err != nil {
+			return fmt.Errorf("failed to link %s: %w", event.AggregateID, err)
+		}
+	}
+	return nil
+}
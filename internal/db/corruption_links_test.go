@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+func TestProjectCorruptionLinks(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, e := range []domain.Event{
+		{AggregateID: "a", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/tv/s02e01.mkv", "path_id": 1}},
+		{AggregateID: "b", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/tv/s02e02.mkv", "path_id": 1}},
+		{AggregateID: "c", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/tv/s03e01.mkv", "path_id": 1}},
+		{AggregateID: "d", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/other/x.mkv", "path_id": 2}},
+		{AggregateID: "a", EventType: domain.SearchCompleted, EventData: map[string]interface{}{"media_id": int64(7), "season_number": 2}},
+		{AggregateID: "b", EventType: domain.SearchCompleted, EventData: map[string]interface{}{"media_id": float64(7), "season_number": 2}},
+		{AggregateID: "c", EventType: domain.SearchCompleted, EventData: map[string]interface{}{"media_id": 7, "season_number": 3}},
+		// Media IDs are per *arr instance: the same ID on another path isn't related
+		{AggregateID: "d", EventType: domain.SearchCompleted, EventData: map[string]interface{}{"media_id": 7}},
+		{AggregateID: "a", EventType: domain.DownloadProgress, EventData: map[string]interface{}{"download_id": "pack"}},
+		{AggregateID: "c", EventType: domain.DownloadProgress, EventData: map[string]interface{}{"download_id": "pack"}},
+		{AggregateID: "c", EventType: domain.DownloadProgress, EventData: map[string]interface{}{"download_id": "pack"}},
+	} {
+		e.AggregateType = "corruption"
+		e.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		data, err := json.Marshal(e.EventData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := TxWithRetry(repo.DB, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, event_version, created_at)
+				VALUES (?, ?, ?, ?, 1, ?)`, e.AggregateType, e.AggregateID, e.EventType, data, e.CreatedAt); err != nil {
+				return err
+			}
+			if err := ProjectCorruptionSummary(tx, e); err != nil {
+				return err
+			}
+			return ProjectCorruptionLinks(tx, e)
+		}); err != nil {
+			t.Fatalf("Failed to store %s: %v", e.EventType, err)
+		}
+	}
+
+	rows, err := repo.DB.Query(`SELECT corruption_id, relation, link_key FROM corruption_links ORDER BY corruption_id, relation`)
+	if err != nil {
+		t.Fatalf("Failed to read links: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var id, relation, key string
+		if err := rows.Scan(&id, &relation, &key); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, id+" "+relation+" "+key)
+	}
+	want := []string{
+		"a download pack", "a media 1:7", "a season 1:7:2",
+		"b media 1:7", "b season 1:7:2",
+		"c download pack", "c media 1:7", "c season 1:7:3",
+		"d media 2:7",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected links %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Link %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
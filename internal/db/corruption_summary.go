@@ -0,0 +1,282 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// corruptionSummaryColumns are the columns of corruption_summary, which the
+// corruption_status view computes from the events in the same order.
+const corruptionSummaryColumns = `corruption_id, current_state, retry_count, file_path, path_id,
+	last_error, corruption_type, media_type, detected_at, last_updated_at`
+
+// corruptionStatusView derives each corruption's summary from its events. It
+// is too slow to read from with a large events table; corruption_summary is
+// the read model and the view is only used to rebuild and check it.
+const corruptionStatusView = `
+	CREATE VIEW corruption_status AS
+	SELECT
+		aggregate_id as corruption_id,
+		(SELECT event_type FROM events e2
+		 WHERE e2.aggregate_id = e.aggregate_id
+		 ORDER BY id DESC LIMIT 1) as current_state,
+		(SELECT COUNT(*) FROM events e3
+		 WHERE e3.aggregate_id = e.aggregate_id
+		 AND e3.event_type LIKE '%Failed') as retry_count,
+		(SELECT json_extract(event_data, '$.file_path') FROM events e4
+		 WHERE e4.aggregate_id = e.aggregate_id
+		 AND e4.event_type = 'CorruptionDetected'
+		 LIMIT 1) as file_path,
+		(SELECT json_extract(event_data, '$.path_id') FROM events e7
+		 WHERE e7.aggregate_id = e.aggregate_id
+		 AND e7.event_type = 'CorruptionDetected'
+		 LIMIT 1) as path_id,
+		(SELECT json_extract(event_data, '$.error') FROM events e5
+		 WHERE e5.aggregate_id = e.aggregate_id
+		 ORDER BY id DESC LIMIT 1) as last_error,
+		(SELECT json_extract(event_data, '$.corruption_type') FROM events e6
+		 WHERE e6.aggregate_id = e.aggregate_id
+		 AND e6.event_type = 'CorruptionDetected'
+		 LIMIT 1) as corruption_type,
+		COALESCE((SELECT json_extract(event_data, '$.media_type') FROM events e8
+		 WHERE e8.aggregate_id = e.aggregate_id
+		 AND e8.event_type = 'CorruptionDetected'
+		 LIMIT 1), 'video') as media_type,
+		MIN(created_at) as detected_at,
+		MAX(created_at) as last_updated_at
+	FROM events e
+	WHERE aggregate_type = 'corruption'
+	GROUP BY aggregate_id
+`
+
+// projectCorruptionSummarySQL applies one event to its corruption's summary
+// row. Details of the corruption come from its CorruptionDetected event;
+// every later event moves the state on and *Failed events count as retries.
+const projectCorruptionSummarySQL = `
+	INSERT INTO corruption_summary (` + corruptionSummaryColumns + `)
+	VALUES (
+		?1, ?2, ?3,
+		CASE WHEN ?2 = 'CorruptionDetected' THEN json_extract(?4, '$.file_path') END,
+		CASE WHEN ?2 = 'CorruptionDetected' THEN json_extract(?4, '$.path_id') END,
+		json_extract(?4, '$.error'),
+		CASE WHEN ?2 = 'CorruptionDetected' THEN json_extract(?4, '$.corruption_type') END,
+		COALESCE(CASE WHEN ?2 = 'CorruptionDetected' THEN json_extract(?4, '$.media_type') END, 'video'),
+		?5, ?5
+	)
+	ON CONFLICT(corruption_id) DO UPDATE SET
+		current_state = excluded.current_state,
+		retry_count = corruption_summary.retry_count + excluded.retry_count,
+		file_path = COALESCE(excluded.file_path, corruption_summary.file_path),
+		path_id = COALESCE(excluded.path_id, corruption_summary.path_id),
+		last_error = excluded.last_error,
+		corruption_type = COALESCE(excluded.corruption_type, corruption_summary.corruption_type),
+		media_type = CASE WHEN excluded.current_state = 'CorruptionDetected'
+			THEN excluded.media_type ELSE corruption_summary.media_type END,
+		last_updated_at = excluded.last_updated_at
+`
+
+// ProjectCorruptionSummary updates corruption_summary with a stored event. The
+// event bus runs it in the transaction that stores the event, so the summary
+// never lags behind the events; events of other aggregates are ignored.
+func ProjectCorruptionSummary(tx *sql.Tx, event domain.Event) error {
+	return projectCorruptionSummary(tx, event, nil)
+}
+
+// CorruptionSummaryProjector is ProjectCorruptionSummary with the projection
+// statement prepared once on database instead of for every event. It is
+// prepared up front because projections run inside a transaction, where
+// preparing would need a second connection.
+func CorruptionSummaryProjector(database *sql.DB) func(tx *sql.Tx, event domain.Event) error {
+	stmt, err := database.Prepare(projectCorruptionSummarySQL)
+	if err != nil {
+		logger.Warnf("Failed to prepare corruption summary projection, using plain statements: %v", err)
+		stmt = nil
+	}
+	return func(tx *sql.Tx, event domain.Event) error {
+		return projectCorruptionSummary(tx, event, stmt)
+	}
+}
+
+// projectCorruptionSummary applies an event with the prepared projection
+// statement, or the plain one if stmt is nil.
+func projectCorruptionSummary(tx *sql.Tx, event domain.Event, stmt *sql.Stmt) error {
+	if event.AggregateType != "corruption" {
+		return nil
+	}
+	data, err := json.Marshal(event.EventData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	retries := 0
+	if strings.HasSuffix(string(event.EventType), "Failed") {
+		retries = 1
+	}
+	args := []interface{}{event.AggregateID, string(event.EventType), retries, string(data), event.CreatedAt}
+	if stmt != nil {
+		_, err = tx.Stmt(stmt).Exec(args...)
+	} else {
+		_, err = tx.Exec(projectCorruptionSummarySQL, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to project %s for %s: %w", event.EventType, event.AggregateID, err)
+	}
+	return nil
+}
+
+// SummaryConsistency is the result of comparing corruption_summary with the
+// summaries the corruption_status view computes from the events.
+type SummaryConsistency struct {
+	Corruptions int `json:"corruptions"` // corruptions with events
+	// Missing have events but no summary row, Orphaned a summary row but no
+	// events, and Mismatched a row whose state, retry count, path or type differ.
+	Missing    []string `json:"missing"`
+	Orphaned   []string `json:"orphaned"`
+	Mismatched []string `json:"mismatched"`
+	Repaired   int      `json:"repaired"`
+}
+
+// Consistent reports whether the summary matched the events.
+func (c *SummaryConsistency) Consistent() bool {
+	return len(c.Missing) == 0 && len(c.Orphaned) == 0 && len(c.Mismatched) == 0
+}
+
+// drifted returns the IDs of all corruptions whose summary is wrong.
+func (c *SummaryConsistency) drifted() []string {
+	ids := make([]string, 0, len(c.Missing)+len(c.Orphaned)+len(c.Mismatched))
+	ids = append(ids, c.Missing...)
+	ids = append(ids, c.Orphaned...)
+	return append(ids, c.Mismatched...)
+}
+
+// CheckCorruptionSummary compares every corruption_summary row with the
+// corruption_status view. It reads the whole events table and is meant for
+// maintenance, not request paths.
+func CheckCorruptionSummary(ctx context.Context, conn *sql.DB) (*SummaryConsistency, error) {
+	result := &SummaryConsistency{Missing: []string{}, Orphaned: []string{}, Mismatched: []string{}}
+
+	if err := conn.QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT aggregate_id) FROM events WHERE aggregate_type = 'corruption'`,
+	).Scan(&result.Corruptions); err != nil {
+		return nil, fmt.Errorf("failed to count corruptions: %w", err)
+	}
+
+	checks := []struct {
+		ids   *[]string
+		query string
+	}{
+		{&result.Missing, `
+			SELECT v.corruption_id FROM corruption_status v
+			LEFT JOIN corruption_summary s ON s.corruption_id = v.corruption_id
+			WHERE s.corruption_id IS NULL`},
+		{&result.Orphaned, `
+			SELECT s.corruption_id FROM corruption_summary s
+			WHERE NOT EXISTS (
+				SELECT 1 FROM events e WHERE e.aggregate_id = s.corruption_id AND e.aggregate_type = 'corruption'
+			)`},
+		{&result.Mismatched, `
+			SELECT s.corruption_id FROM corruption_summary s
+			JOIN corruption_status v ON v.corruption_id = s.corruption_id
+			WHERE s.current_state IS NOT v.current_state
+			OR s.retry_count IS NOT v.retry_count
+			OR s.file_path IS NOT v.file_path
+			OR s.path_id IS NOT v.path_id
+			OR s.corruption_type IS NOT v.corruption_type
+			OR s.media_type IS NOT v.media_type`},
+	}
+	for _, check := range checks {
+		rows, err := conn.QueryContext(ctx, check.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check corruption summary: %w", err)
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			*check.ids = append(*check.ids, id)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// RebuildCorruptionSummary recomputes the summary rows of the given
+// corruptions from their events, or of all corruptions when ids is empty.
+// It returns the number of rows written.
+func RebuildCorruptionSummary(ctx context.Context, conn *sql.DB, ids ...string) (int64, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var written int64
+	rebuild := func(where string, args ...interface{}) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM corruption_summary`+where, args...); err != nil { // NOSONAR - only placeholders are concatenated
+			return fmt.Errorf("failed to clear corruption summary: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, `INSERT INTO corruption_summary (`+corruptionSummaryColumns+`)
+			SELECT `+corruptionSummaryColumns+` FROM corruption_status`+where, args...) // NOSONAR - only placeholders are concatenated
+		if err != nil {
+			return fmt.Errorf("failed to rebuild corruption summary: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		written += n
+		return nil
+	}
+
+	if len(ids) == 0 {
+		if err := rebuild(""); err != nil {
+			return 0, err
+		}
+	}
+	for start := 0; start < len(ids); start += pruneBatchSize {
+		batch := ids[start:min(start+pruneBatchSize, len(ids))]
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		if err := rebuild(` WHERE corruption_id IN (`+placeholders(len(batch))+`)`, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// RepairCorruptionSummary checks corruption_summary against the events and
+// rebuilds the rows that drifted, e.g. after pruning removed the older events
+// of an open corruption, events were written outside the event bus or a
+// projection failed.
+func RepairCorruptionSummary(ctx context.Context, conn *sql.DB) (*SummaryConsistency, error) {
+	result, err := CheckCorruptionSummary(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if result.Consistent() {
+		return result, nil
+	}
+
+	logger.Infof("Corruption summary differs from the events: %d missing, %d orphaned, %d mismatched - rebuilding them",
+		len(result.Missing), len(result.Orphaned), len(result.Mismatched))
+	drifted := result.drifted()
+	if _, err := RebuildCorruptionSummary(ctx, conn, drifted...); err != nil {
+		return result, err
+	}
+	result.Repaired = len(drifted)
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+// storeEvent stores an event and projects it like the event bus does.
+func storeEvent(t *testing.T, conn *sql.DB, event domain.Event) {
+	t.Helper()
+	data, err := json.Marshal(event.EventData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := TxWithRetry(conn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, event_version, created_at)
+			VALUES (?, ?, ?, ?, 1, ?)`, event.AggregateType, event.AggregateID, event.EventType, data, event.CreatedAt); err != nil {
+			return err
+		}
+		return ProjectCorruptionSummary(tx, event)
+	}); err != nil {
+		t.Fatalf("Failed to store %s: %v", event.EventType, err)
+	}
+}
+
+func TestCorruptionSummary_ProjectionMatchesEvents(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, e := range []domain.Event{
+		{AggregateID: "a", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{
+			"file_path": "/media/a.flac", "path_id": 2, "corruption_type": "Truncated", "media_type": "audio"}},
+		{AggregateID: "a", EventType: domain.DeletionFailed, EventData: map[string]interface{}{"error": "timeout"}},
+		{AggregateID: "a", EventType: domain.DeletionStarted, EventData: map[string]interface{}{}},
+		{AggregateID: "a", EventType: domain.DeletionFailed, EventData: map[string]interface{}{"error": "refused"}},
+		{AggregateID: "b", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/media/b.mkv", "path_id": 1}},
+		{AggregateID: "b", EventType: domain.VerificationSuccess, EventData: map[string]interface{}{}},
+		{AggregateID: "scan-1", AggregateType: "scan", EventType: domain.ScanStarted, EventData: map[string]interface{}{}},
+	} {
+		if e.AggregateType == "" {
+			e.AggregateType = "corruption"
+		}
+		e.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		storeEvent(t, repo.DB, e)
+	}
+
+	var state, filePath, mediaType, lastError string
+	var retries, pathID int
+	if err := repo.DB.QueryRow(`SELECT current_state, retry_count, file_path, path_id, media_type, last_error
+		FROM corruption_summary WHERE corruption_id = 'a'`).Scan(&state, &retries, &filePath, &pathID, &mediaType, &lastError); err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	if state != "DeletionFailed" || retries != 2 || filePath != "/media/a.flac" || pathID != 2 || mediaType != "audio" || lastError != "refused" {
+		t.Errorf("Unexpected summary: %s %d %s %d %s %s", state, retries, filePath, pathID, mediaType, lastError)
+	}
+
+	result, err := CheckCorruptionSummary(ctx, repo.DB)
+	if err != nil {
+		t.Fatalf("CheckCorruptionSummary failed: %v", err)
+	}
+	if !result.Consistent() || result.Corruptions != 2 {
+		t.Errorf("Expected 2 consistent corruptions, got %+v", result)
+	}
+}
+
+func TestCorruptionSummaryProjector_ReusesPreparedStatement(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	project := CorruptionSummaryProjector(repo.DB)
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, e := range []domain.Event{
+		{AggregateID: "a", EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/media/a.mkv", "path_id": 1}},
+		{AggregateID: "a", EventType: domain.DeletionFailed, EventData: map[string]interface{}{"error": "timeout"}},
+	} {
+		e.AggregateType = "corruption"
+		e.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		if err := TxWithRetry(repo.DB, func(tx *sql.Tx) error { return project(tx, e) }); err != nil {
+			t.Fatalf("Failed to project %s: %v", e.EventType, err)
+		}
+	}
+
+	var state, filePath string
+	var retries int
+	if err := repo.DB.QueryRow(`SELECT current_state, retry_count, file_path FROM corruption_summary WHERE corruption_id = 'a'`).
+		Scan(&state, &retries, &filePath); err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	if state != "DeletionFailed" || retries != 1 || filePath != "/media/a.mkv" {
+		t.Errorf("Unexpected summary: %s %d %s", state, retries, filePath)
+	}
+}
+
+func TestCorruptionSummary_RepairDrift(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for _, id := range []string{"ok", "stale", "lost"} {
+		storeEvent(t, repo.DB, domain.Event{AggregateID: id, AggregateType: "corruption", EventType: domain.CorruptionDetected,
+			EventData: map[string]interface{}{"file_path": "/media/" + id + ".mkv"}, CreatedAt: now})
+	}
+	// Events written outside the event bus and a summary row without events
+	if _, err := repo.DB.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data)
+		VALUES ('corruption', 'stale', 'SearchFailed', '{}')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.DB.Exec(`DELETE FROM corruption_summary WHERE corruption_id = 'lost'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.DB.Exec(`INSERT INTO corruption_summary (corruption_id, current_state) VALUES ('ghost', 'CorruptionDetected')`); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := CheckCorruptionSummary(ctx, repo.DB)
+	if err != nil {
+		t.Fatalf("CheckCorruptionSummary failed: %v", err)
+	}
+	if result.Consistent() || len(result.Missing) != 1 || result.Missing[0] != "lost" ||
+		len(result.Orphaned) != 1 || result.Orphaned[0] != "ghost" ||
+		len(result.Mismatched) != 1 || result.Mismatched[0] != "stale" {
+		t.Fatalf("Unexpected check result: %+v", result)
+	}
+
+	result, err = RepairCorruptionSummary(ctx, repo.DB)
+	if err != nil {
+		t.Fatalf("RepairCorruptionSummary failed: %v", err)
+	}
+	if result.Repaired != 3 {
+		t.Errorf("Expected 3 repaired rows, got %d", result.Repaired)
+	}
+	if result, err = CheckCorruptionSummary(ctx, repo.DB); err != nil || !result.Consistent() {
+		t.Errorf("Expected a consistent summary after repair, got %+v (%v)", result, err)
+	}
+
+	var state string
+	var retries int
+	if err := repo.DB.QueryRow(`SELECT current_state, retry_count FROM corruption_summary WHERE corruption_id = 'stale'`).Scan(&state, &retries); err != nil {
+		t.Fatal(err)
+	}
+	if state != "SearchFailed" || retries != 1 {
+		t.Errorf("Expected repaired SearchFailed with 1 retry, got %s with %d", state, retries)
+	}
+}
+
+func TestRebuildCorruptionSummary(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := repo.DB.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+		('corruption', 'a', 'CorruptionDetected', '{"file_path": "/media/a.mkv"}'),
+		('corruption', 'b', 'CorruptionDetected', '{"file_path": "/media/b.mkv"}'),
+		('corruption', 'b', 'CorruptionIgnored', '{}')`); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := RebuildCorruptionSummary(context.Background(), repo.DB, "b")
+	if err != nil || n != 1 {
+		t.Fatalf("RebuildCorruptionSummary(b) = %d, %v; want 1 row", n, err)
+	}
+	n, err = RebuildCorruptionSummary(context.Background(), repo.DB)
+	if err != nil || n != 2 {
+		t.Fatalf("RebuildCorruptionSummary() = %d, %v; want 2 rows", n, err)
+	}
+
+	// b is ignored and left out of the dashboard
+	var active int
+	if err := repo.DB.QueryRow(`SELECT active_corruptions FROM dashboard_stats`).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	if active != 1 {
+		t.Errorf("Expected 1 active corruption in dashboard_stats, got %d", active)
+	}
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyObserver is told how long a database operation took, including the
+// time spent retrying, and how often it was retried on SQLITE_BUSY.
+type LatencyObserver func(operation string, d time.Duration, retries int)
+
+var latencyObserver atomic.Pointer[LatencyObserver]
+
+// SetLatencyObserver registers a process-wide observer of database latency,
+// e.g. to export metrics. ExecWithRetry ("exec"), TxWithRetry ("transaction"),
+// QueryWithRetry ("query") and the named variants report to it. Pass nil to
+// remove it.
+func SetLatencyObserver(fn LatencyObserver) {
+	if fn == nil {
+		latencyObserver.Store(nil)
+		return
+	}
+	latencyObserver.Store(&fn)
+}
+
+// ObserveLatency reports an operation that started at start to the latency
+// observer, for hot queries that don't go through the retry helpers.
+func ObserveLatency(operation string, start time.Time) {
+	observeLatency(operation, start, 0)
+}
+
+func observeLatency(operation string, start time.Time, retries int) {
+	if fn := latencyObserver.Load(); fn != nil {
+		(*fn)(operation, time.Since(start), retries)
+	}
+}
+
+// StmtCache prepares statements on first use and reuses them afterwards, so
+// queries that run for every event or every scanned file aren't parsed and
+// planned by SQLite each time. It is safe for concurrent use.
+type StmtCache struct {
+	db    *sql.DB
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+var errNoStmtCache = errors.New("no database to prepare statements on")
+
+// NewStmtCache creates a statement cache for db.
+func NewStmtCache(db *sql.DB) *StmtCache {
+	return &StmtCache{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// Get returns the prepared statement for query, preparing it on first use.
+// A statement that failed to prepare is prepared again on the next call.
+// A nil cache returns an error, so callers can fall back to the plain query.
+func (c *StmtCache) Get(query string) (*sql.Stmt, error) {
+	if c == nil || c.db == nil {
+		return nil, errNoStmtCache
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), retryQueryTimeout)
+	defer cancel()
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+// Close closes all prepared statements.
+func (c *StmtCache) Close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query, stmt := range c.stmts {
+		_ = stmt.Close()
+		delete(c.stmts, query)
+	}
+}
@@ -0,0 +1,6 @@
+-- Migration 007: Per-path detector fallback order
+-- JSON array of detection methods tried, in order, when the primary detector
+-- fails with a recoverable error. NULL keeps the built-in fallback chain,
+-- an empty array disables fallbacks for the path.
+
+ALTER TABLE scan_paths ADD COLUMN detection_fallbacks TEXT DEFAULT NULL;
@@ -0,0 +1,36 @@
+-- Migration 008: Outgoing webhook outbox
+-- Webhook targets subscribe to domain event types. Matching events are written
+-- to the delivery outbox first and delivered by a background worker, so a
+-- target that is down does not lose events and retries survive restarts.
+
+CREATE TABLE outgoing_webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL DEFAULT '',
+    events TEXT NOT NULL,
+    enabled BOOLEAN DEFAULT 1,
+    max_attempts INTEGER DEFAULT 5,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE outgoing_webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event_id INTEGER,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES outgoing_webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_outgoing_deliveries_due ON outgoing_webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_outgoing_deliveries_webhook ON outgoing_webhook_deliveries(webhook_id, created_at);
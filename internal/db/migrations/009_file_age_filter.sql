@@ -0,0 +1,8 @@
+-- Migration 009: Per-path file age and size stability filter
+-- Files modified less than min_file_age_minutes ago are skipped during path
+-- scans so partial copies and in-progress imports are not flagged as corrupt.
+-- 0 disables the age check. size_stability_seconds is the delay between the
+-- two size checks; 0 keeps the quick built-in check.
+
+ALTER TABLE scan_paths ADD COLUMN min_file_age_minutes INTEGER DEFAULT 2;
+ALTER TABLE scan_paths ADD COLUMN size_stability_seconds INTEGER DEFAULT 0;
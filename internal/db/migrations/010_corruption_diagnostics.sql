@@ -0,0 +1,15 @@
+-- Migration 010: Checker output for failed health checks
+-- Stores the raw output of the detection tools (trimmed stdout/stderr, exit
+-- code, duration) for each CorruptionDetected event as gzip-compressed JSON,
+-- keyed by the corruption's aggregate ID.
+
+CREATE TABLE IF NOT EXISTS corruption_diagnostics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    corruption_id TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    data BLOB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_corruption_diagnostics_corruption_id ON corruption_diagnostics(corruption_id);
+CREATE INDEX IF NOT EXISTS idx_corruption_diagnostics_created_at ON corruption_diagnostics(created_at);
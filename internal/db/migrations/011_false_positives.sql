@@ -0,0 +1,25 @@
+-- Migration 011: False positive feedback
+-- Findings a user marked as false positive, with the checker output, tool
+-- version and a content fingerprint of the file. The scanner suppresses new
+-- findings with the same fingerprint, corruption type and signature.
+
+CREATE TABLE IF NOT EXISTS false_positives (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    corruption_id TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    file_size INTEGER NOT NULL DEFAULT 0,
+    fingerprint TEXT NOT NULL DEFAULT '',
+    corruption_type TEXT NOT NULL DEFAULT '',
+    error_details TEXT NOT NULL DEFAULT '',
+    signature TEXT NOT NULL,
+    detection_tool TEXT NOT NULL DEFAULT '',
+    tool_version TEXT NOT NULL DEFAULT '',
+    checker_output BLOB,
+    note TEXT NOT NULL DEFAULT '',
+    suppressed_count INTEGER NOT NULL DEFAULT 0,
+    last_suppressed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_false_positives_match ON false_positives(signature, corruption_type, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_false_positives_corruption_id ON false_positives(corruption_id);
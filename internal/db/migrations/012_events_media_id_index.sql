@@ -0,0 +1,7 @@
+-- Migration 012: Expression index for media_id lookups
+-- The per-media remediation history finds every corruption of a movie or
+-- series by the *arr media ID recorded in its search and deletion events.
+
+CREATE INDEX IF NOT EXISTS idx_events_media_id
+    ON events(json_extract(event_data, '$.media_id'))
+    WHERE json_extract(event_data, '$.media_id') IS NOT NULL;
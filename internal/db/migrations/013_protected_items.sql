@@ -0,0 +1,19 @@
+-- Migration 013: Protected files and media
+-- Files or whole movies/series that must never be remediated automatically,
+-- e.g. irreplaceable home media or rare releases. They are still scanned and
+-- reported; the remediator refuses to delete or replace them.
+
+CREATE TABLE IF NOT EXISTS protected_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_path TEXT,
+    arr_instance_id INTEGER,
+    media_id INTEGER,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (file_path IS NOT NULL OR (arr_instance_id IS NOT NULL AND media_id IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_protected_items_file_path
+    ON protected_items(file_path) WHERE file_path IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_protected_items_media
+    ON protected_items(arr_instance_id, media_id) WHERE file_path IS NULL;
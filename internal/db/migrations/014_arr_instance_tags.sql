@@ -0,0 +1,8 @@
+-- Migration 014: Bind scan paths to *arr instances by tag
+-- arr_instances.tags caches the tag labels defined in each instance (JSON
+-- array), refreshed from the *arr API. A scan path with arr_instance_tag set
+-- is re-bound to whichever enabled instance carries that tag, so paths keep
+-- routing to the right instance when instances are re-created with new IDs.
+
+ALTER TABLE arr_instances ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE scan_paths ADD COLUMN arr_instance_tag TEXT NOT NULL DEFAULT '';
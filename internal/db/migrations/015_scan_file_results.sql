@@ -0,0 +1,9 @@
+-- Migration 015: Per-file scan result details
+-- duration_ms is how long the health check of the file took and
+-- detection_tool the tool whose verdict was recorded (a fallback when the
+-- primary failed). Both are NULL for files skipped before any check ran.
+-- Files whose check could not complete (tool failure, timeout) are recorded
+-- with status 'error'; 'inaccessible' remains for mount and permission issues.
+
+ALTER TABLE scan_files ADD COLUMN duration_ms INTEGER;
+ALTER TABLE scan_files ADD COLUMN detection_tool TEXT;
@@ -0,0 +1,14 @@
+-- Migration 016: Weekly summary reports
+-- Each row is a generated report over [period_start, period_end). The figures
+-- are stored as JSON so a report can be rendered as Markdown or HTML later,
+-- unchanged by events that arrive or are pruned after it was generated.
+
+CREATE TABLE IF NOT EXISTS reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    data TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_period_end ON reports(period_end);
@@ -0,0 +1,15 @@
+-- Migration 017: Quality pinning for replacement searches
+-- scan_paths.quality_pin requires replacements to match the resolution of the
+-- file they replace: 'off' (default), 'flag' (a lower resolution is reported
+-- as a QualityRegression instead of resolving the corruption) or 'search'
+-- (the regression also counts as a failed verification, so the search is
+-- retried). corruption_quality_pins overrides the path setting for single
+-- corruptions.
+
+ALTER TABLE scan_paths ADD COLUMN quality_pin TEXT NOT NULL DEFAULT 'off';
+
+CREATE TABLE IF NOT EXISTS corruption_quality_pins (
+    corruption_id TEXT PRIMARY KEY,
+    mode TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
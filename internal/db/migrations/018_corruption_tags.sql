@@ -0,0 +1,23 @@
+-- Migration 018: Corruption tags and saved filters
+-- corruption_tags attaches free-form labels to corruptions for triage, e.g.
+-- "disk-failure-2024". saved_filters stores named combinations of statuses,
+-- tags and scan paths for the corruption list; list columns hold JSON arrays.
+
+CREATE TABLE IF NOT EXISTS corruption_tags (
+    corruption_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (corruption_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_corruption_tags_tag ON corruption_tags(tag);
+
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    statuses TEXT NOT NULL DEFAULT '[]',
+    tags TEXT NOT NULL DEFAULT '[]',
+    path_ids TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- Migration 019: Soft-delete for scan paths and *arr instances
+-- Deleting a scan path or instance sets deleted_at instead of removing the
+-- row, so scans and corruptions that reference it stay intact and the
+-- deletion can be undone. Rows are purged once deleted_at is older than
+-- HEALARR_DELETED_RETENTION_DAYS.
+
+ALTER TABLE scan_paths ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE arr_instances ADD COLUMN deleted_at TIMESTAMP DEFAULT NULL;
@@ -0,0 +1,16 @@
+-- Migration 020: *arr instance health history
+-- The health monitor records one sample per instance and check: whether its
+-- system/status endpoint answered and how long that took. Uptime and latency
+-- trends are computed from these samples, which follow HEALARR_RETENTION_DAYS.
+
+CREATE TABLE arr_health_samples (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    instance_id INTEGER NOT NULL,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reachable BOOLEAN NOT NULL,
+    response_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    FOREIGN KEY (instance_id) REFERENCES arr_instances(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_arr_health_samples_instance ON arr_health_samples(instance_id, checked_at);
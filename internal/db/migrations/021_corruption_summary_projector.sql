@@ -0,0 +1,7 @@
+-- Migration 021: Maintain corruption_summary from the event bus
+-- The trigger recounted each corruption's events on every insert. The event
+-- bus now projects each event onto corruption_summary in the transaction that
+-- stores it, and maintenance checks the table against the corruption_status
+-- view, which computes the same summaries from the events.
+
+DROP TRIGGER IF EXISTS trg_update_corruption_summary;
@@ -0,0 +1,6 @@
+-- Migration 022: Scan path priority
+-- When scheduled scans collide and HEALARR_SCHEDULED_SCAN_CONCURRENCY limits
+-- how many run at once, waiting scans start in order of their scan path's
+-- priority, highest first. 0 is the default priority.
+
+ALTER TABLE scan_paths ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- Migration 023: Off-site backup targets
+-- After every database backup, Healarr uploads a copy to each enabled target
+-- (S3-compatible storage, WebDAV, SFTP or an rclone remote) and deletes the
+-- uploaded backups its retention policy no longer keeps. config holds the
+-- type-specific settings as JSON, encrypted when HEALARR_ENCRYPTION_KEY is set.
+
+CREATE TABLE backup_targets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    config TEXT NOT NULL,
+    keep_last INTEGER NOT NULL DEFAULT 0,
+    keep_days INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN DEFAULT 1,
+    last_upload_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
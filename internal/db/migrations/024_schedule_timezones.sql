@@ -0,0 +1,10 @@
+-- Migration 024: Schedule timezones and DST policies
+-- A schedule's cron expression is read in its own timezone (empty for the
+-- scheduler's, from HEALARR_TZ or TZ). dst_gap says whether a time skipped
+-- when clocks go forward runs right after the jump ('run') or not at all
+-- ('skip'); dst_overlap whether a time repeated when clocks go back runs
+-- 'once' or 'twice'.
+
+ALTER TABLE scan_schedules ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_schedules ADD COLUMN dst_gap TEXT NOT NULL DEFAULT 'run';
+ALTER TABLE scan_schedules ADD COLUMN dst_overlap TEXT NOT NULL DEFAULT 'once';
@@ -0,0 +1,12 @@
+-- Migration 025: Deletion plans
+-- Before deleting a corrupt file, remediation records which *arr file records
+-- and which files on disk it is about to delete. Right before deleting it
+-- plans again, and stops with DeletionPlanChanged if the *arr instance's files
+-- changed in between. Dry runs record their plan too. One plan per
+-- corruption, the latest.
+
+CREATE TABLE IF NOT EXISTS corruption_deletion_plans (
+    corruption_id TEXT PRIMARY KEY,
+    plan TEXT NOT NULL,
+    planned_at TIMESTAMP NOT NULL
+);
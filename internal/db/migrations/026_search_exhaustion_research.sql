@@ -0,0 +1,9 @@
+-- Migration 026: Scheduled re-search after SearchExhausted
+-- When no replacement can be found, a scan path can keep searching on a long
+-- interval instead of giving up: every research_interval_days days for
+-- research_period_days days after the search was first exhausted. Each
+-- scheduled search is tracked as a ScheduledResearch event. An interval of 0
+-- (the default) turns it off.
+
+ALTER TABLE scan_paths ADD COLUMN research_interval_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scan_paths ADD COLUMN research_period_days INTEGER NOT NULL DEFAULT 90;
@@ -0,0 +1,7 @@
+-- Migration 027: Download protocol preference
+-- A scan path can require replacements to come from one protocol, e.g. only
+-- usenet to keep torrents' seeding ratio out of remediation. Grabs of the
+-- other protocol are removed from the *arr queue and blocklisted, so the *arr
+-- searches for another release. 'any' (the default) accepts both.
+
+ALTER TABLE scan_paths ADD COLUMN protocol_preference TEXT NOT NULL DEFAULT 'any';
@@ -0,0 +1,12 @@
+-- Migration 028: Remote scan paths
+-- A scan path can point at a WebDAV or SFTP server (e.g. a seedbox) instead of
+-- a local mount. local_path stays the path the *arr knows the files by; the
+-- remote URL is the same directory on the server. Checks are limited to file
+-- headers, and requests are rate limited per server. The password is stored
+-- encrypted like *arr API keys. An empty remote_url means a local path.
+
+ALTER TABLE scan_paths ADD COLUMN remote_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_paths ADD COLUMN remote_username TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_paths ADD COLUMN remote_password TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_paths ADD COLUMN remote_host_key_fingerprint TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_paths ADD COLUMN remote_requests_per_minute INTEGER NOT NULL DEFAULT 60;
@@ -0,0 +1,9 @@
+-- Migration 029: Per-path media extensions and minimum file size
+-- media_extensions replaces the built-in list of media extensions for a scan
+-- path (comma-separated, e.g. '.mkv,.mp4'); empty keeps the built-in list.
+-- min_file_size_mb flags files that decode but are too small to be what they
+-- claim, e.g. a 1080p movie under 200 MB, as Undersized corruptions. 0
+-- disables the check.
+
+ALTER TABLE scan_paths ADD COLUMN media_extensions TEXT NOT NULL DEFAULT '';
+ALTER TABLE scan_paths ADD COLUMN min_file_size_mb INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Migration 030: Runtime duration check
+-- duration_check compares the duration of a scanned video with the runtime its
+-- *arr instance expects, so a 20-minute file for a 2-hour movie is reported as
+-- a DurationMismatch corruption. 'flag' records it without remediating,
+-- 'remediate' follows the path's auto_remediate setting, 'off' (the default)
+-- skips the check.
+
+ALTER TABLE scan_paths ADD COLUMN duration_check TEXT NOT NULL DEFAULT 'off';
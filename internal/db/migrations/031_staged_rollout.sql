@@ -0,0 +1,8 @@
+-- Migration 031: Staged rollout of new scan paths
+-- A newly added path can hold a large backlog of corrupt files. For the first
+-- rollout_days days after the path was added, at most rollout_daily_limit
+-- remediations start per day; the rest are deferred to the next day. A limit
+-- of 0 (the default) disables the staged rollout.
+
+ALTER TABLE scan_paths ADD COLUMN rollout_daily_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scan_paths ADD COLUMN rollout_days INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- Migration 032: Indexes for the hottest query patterns
+-- query_plan_test.go checks that these queries keep using an index.
+
+-- Events of a type in a time window: reports, stats and replay. Replaces
+-- idx_event_type, which is a prefix of it.
+CREATE INDEX IF NOT EXISTS idx_events_type_created_at ON events(event_type, created_at);
+DROP INDEX IF EXISTS idx_event_type;
+
+-- Corruptions in a state, newest first: resolved list, public status page
+CREATE INDEX IF NOT EXISTS idx_corruption_summary_state_updated
+    ON corruption_summary(current_state, last_updated_at);
+
+-- Scan history of a file and of a path
+CREATE INDEX IF NOT EXISTS idx_scan_files_file_path ON scan_files(file_path);
+CREATE INDEX IF NOT EXISTS idx_scans_path ON scans(path);
+
+-- idx_scan_files_scan_status (scan_id, status) serves every lookup by scan_id,
+-- so the single-column index only slows down writes during scans
+DROP INDEX IF EXISTS idx_scan_files_scan_id;
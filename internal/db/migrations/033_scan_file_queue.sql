@@ -0,0 +1,14 @@
+-- Migration 033: Spill-to-disk queue of the files of a path scan
+-- A scan keeps the list of its files in memory up to a limit. Beyond it the
+-- list is written here and read back page by page, so the scanner's memory use
+-- doesn't grow with the library. Rows are deleted once the scan finishes;
+-- interrupted scans keep theirs to resume from. Scans whose list fit in memory
+-- keep storing it in scans.file_list instead.
+
+CREATE TABLE IF NOT EXISTS scan_file_queue (
+    scan_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    file_path TEXT NOT NULL,
+    PRIMARY KEY (scan_id, position),
+    FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE
+) WITHOUT ROWID;
@@ -0,0 +1,6 @@
+-- Migration 034: Minimum detection confidence per scan path
+-- Corruptions are scored from 0 to 100 by how sure the detection is. Findings
+-- below min_confidence are not remediated automatically but wait for the user
+-- to confirm them. 0 (the default) remediates every finding.
+
+ALTER TABLE scan_paths ADD COLUMN min_confidence INTEGER NOT NULL DEFAULT 0;
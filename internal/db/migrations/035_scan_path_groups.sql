@@ -0,0 +1,20 @@
+-- Migration 035: Scan path groups
+-- Groups such as "Movies", "TV" or "Kids" collect scan paths for actions on
+-- all of them at once: scanning the group, pausing its remediation and group
+-- stats. A scan path belongs to at most one group. notifications.group_ids
+-- limits a notification to events of paths in those groups (a JSON array;
+-- empty receives events of every path).
+
+CREATE TABLE IF NOT EXISTS scan_path_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    remediation_paused BOOLEAN NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE scan_paths ADD COLUMN group_id INTEGER REFERENCES scan_path_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_scan_paths_group_id ON scan_paths(group_id);
+
+ALTER TABLE notifications ADD COLUMN group_ids TEXT NOT NULL DEFAULT '[]';
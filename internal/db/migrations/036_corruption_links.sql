@@ -0,0 +1,44 @@
+-- Migration 036: Related corruptions
+-- Corruptions of the same media item (movie or series), the same season, or
+-- replaced by the same download (e.g. a season pack) share a link key, so the
+-- corruption detail can list the related ones. Keys are scoped to the scan
+-- path because *arr media IDs are only unique per instance:
+--   media    <path_id>:<media_id>
+--   season   <path_id>:<media_id>:<season_number>
+--   download <download_id>
+
+CREATE TABLE IF NOT EXISTS corruption_links (
+    corruption_id TEXT NOT NULL,
+    relation TEXT NOT NULL,
+    link_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (corruption_id, relation, link_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_corruption_links_key ON corruption_links(relation, link_key);
+
+-- Link the corruptions already in the event store
+INSERT OR IGNORE INTO corruption_links (corruption_id, relation, link_key, created_at)
+SELECT e.aggregate_id, 'media', cs.path_id || ':' || json_extract(e.event_data, '$.media_id'), MIN(e.created_at)
+FROM events e
+JOIN corruption_summary cs ON cs.corruption_id = e.aggregate_id
+WHERE e.aggregate_type = 'corruption' AND cs.path_id IS NOT NULL
+    AND json_extract(e.event_data, '$.media_id') > 0
+GROUP BY 1, 3;
+
+INSERT OR IGNORE INTO corruption_links (corruption_id, relation, link_key, created_at)
+SELECT e.aggregate_id, 'season',
+    cs.path_id || ':' || json_extract(e.event_data, '$.media_id') || ':' || json_extract(e.event_data, '$.season_number'),
+    MIN(e.created_at)
+FROM events e
+JOIN corruption_summary cs ON cs.corruption_id = e.aggregate_id
+WHERE e.aggregate_type = 'corruption' AND cs.path_id IS NOT NULL
+    AND json_extract(e.event_data, '$.media_id') > 0
+    AND json_extract(e.event_data, '$.season_number') > 0
+GROUP BY 1, 3;
+
+INSERT OR IGNORE INTO corruption_links (corruption_id, relation, link_key, created_at)
+SELECT aggregate_id, 'download', json_extract(event_data, '$.download_id'), MIN(created_at)
+FROM events
+WHERE aggregate_type = 'corruption' AND COALESCE(json_extract(event_data, '$.download_id'), '') != ''
+GROUP BY 1, 3;
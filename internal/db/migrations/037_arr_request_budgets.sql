@@ -0,0 +1,17 @@
+-- Migration 037: Daily *arr API request budgets
+-- Some private indexers limit API use per day, which the *arr passes on.
+-- daily_request_budget caps the requests Healarr sends an instance per UTC
+-- day; 0 (the default) is unlimited. From 90% of the budget non-urgent
+-- requests, like listing fallbacks and history polls, wait for the next day.
+
+ALTER TABLE arr_instances ADD COLUMN daily_request_budget INTEGER NOT NULL DEFAULT 0;
+
+-- Requests sent to and deferred for each instance per UTC day
+CREATE TABLE IF NOT EXISTS arr_request_usage (
+    instance_id INTEGER NOT NULL REFERENCES arr_instances(id) ON DELETE CASCADE,
+    day TEXT NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    deferred INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (instance_id, day)
+);
@@ -0,0 +1,8 @@
+-- Migration 038: Symlink policy
+-- symlink_policy decides how scans treat symbolic links below a scan path:
+-- 'skip' (the default, and the behavior so far) ignores them, 'follow' scans
+-- linked files and directories wherever they point, 'verify_target_once'
+-- checks linked files inside the scan path once through their target. Files
+-- and directories reached twice, e.g. through a link loop, are scanned once.
+
+ALTER TABLE scan_paths ADD COLUMN symlink_policy TEXT NOT NULL DEFAULT 'skip';
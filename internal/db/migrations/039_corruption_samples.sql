@@ -0,0 +1,17 @@
+-- Migration 039: Corrupted-file samples
+-- A sample is a few MB of a corrupt file around its first error, copied to the
+-- sample directory so it can be shared when reporting a detection problem.
+-- The files are capped in size and age; the rows go with them.
+
+CREATE TABLE IF NOT EXISTS corruption_samples (
+    corruption_id TEXT PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    sample_path TEXT NOT NULL,
+    method TEXT NOT NULL,              -- 'ffmpeg' (remuxed clip) or 'bytes' (raw byte range)
+    offset_seconds REAL,               -- where the clip starts, for 'ffmpeg'
+    offset_bytes INTEGER,              -- where the byte range starts, for 'bytes'
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_corruption_samples_created ON corruption_samples(created_at);
@@ -0,0 +1,5 @@
+-- Migration 040: Notification locale
+-- locale is the language a notification channel's messages are written in:
+-- 'en', 'de', 'fr' or 'es'. Empty means HEALARR_LOCALE.
+
+ALTER TABLE notifications ADD COLUMN locale TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,45 @@
+-- Migration 041: Compact scan summaries
+-- Scan records carry their file list and per-file results, so they are pruned
+-- with the scan history. A summary of each finished scan is kept separately,
+-- forever by default, so scan trends survive the pruning of scans and events.
+-- Triggers keep the summary in step with the scan, including corruptions
+-- counted after the scan finished.
+
+CREATE TABLE IF NOT EXISTS scan_summaries (
+    scan_id INTEGER PRIMARY KEY,
+    path TEXT NOT NULL,
+    path_id INTEGER,
+    status TEXT NOT NULL,
+    files_scanned INTEGER NOT NULL DEFAULT 0,
+    total_files INTEGER NOT NULL DEFAULT 0,
+    corruptions_found INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scan_summaries_path_id ON scan_summaries(path_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_scan_summaries_started_at ON scan_summaries(started_at);
+
+DROP TRIGGER IF EXISTS trg_scan_summary_insert;
+CREATE TRIGGER trg_scan_summary_insert
+AFTER INSERT ON scans
+WHEN NEW.status IN ('completed', 'cancelled', 'error', 'aborted')
+BEGIN
+    INSERT OR REPLACE INTO scan_summaries (scan_id, path, path_id, status, files_scanned, total_files, corruptions_found, started_at, completed_at)
+    VALUES (NEW.id, NEW.path, NEW.path_id, NEW.status, COALESCE(NEW.files_scanned, 0), COALESCE(NEW.total_files, 0), COALESCE(NEW.corruptions_found, 0), NEW.started_at, NEW.completed_at);
+END;
+
+DROP TRIGGER IF EXISTS trg_scan_summary_update;
+CREATE TRIGGER trg_scan_summary_update
+AFTER UPDATE OF status, path_id, files_scanned, total_files, corruptions_found, completed_at ON scans
+WHEN NEW.status IN ('completed', 'cancelled', 'error', 'aborted')
+BEGIN
+    INSERT OR REPLACE INTO scan_summaries (scan_id, path, path_id, status, files_scanned, total_files, corruptions_found, started_at, completed_at)
+    VALUES (NEW.id, NEW.path, NEW.path_id, NEW.status, COALESCE(NEW.files_scanned, 0), COALESCE(NEW.total_files, 0), COALESCE(NEW.corruptions_found, 0), NEW.started_at, NEW.completed_at);
+END;
+
+-- Backfill the scans finished before this migration
+INSERT OR IGNORE INTO scan_summaries (scan_id, path, path_id, status, files_scanned, total_files, corruptions_found, started_at, completed_at)
+SELECT id, path, path_id, status, COALESCE(files_scanned, 0), COALESCE(total_files, 0), COALESCE(corruptions_found, 0), started_at, completed_at
+FROM scans
+WHERE status IN ('completed', 'cancelled', 'error', 'aborted');
@@ -0,0 +1,17 @@
+-- Migration 042: Per-corruption path mapping overrides
+-- When the path mappings get a single file wrong (an odd mount layout), the
+-- operator can supply the path the *arr instance knows the file by, and the
+-- ID of its movie or series. Remediation then maps the file's local path to
+-- that *arr path and skips the media lookup. The override follows the file:
+-- later corruptions of the same file use it too.
+
+CREATE TABLE IF NOT EXISTS corruption_mapping_overrides (
+    corruption_id TEXT PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    arr_path TEXT NOT NULL,              -- the mapped path when only the media ID was given
+    media_id INTEGER NOT NULL DEFAULT 0, -- 0 looks the media up by path
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_corruption_mapping_overrides_file ON corruption_mapping_overrides(file_path);
+CREATE INDEX IF NOT EXISTS idx_corruption_mapping_overrides_arr ON corruption_mapping_overrides(arr_path);
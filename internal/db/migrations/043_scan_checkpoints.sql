@@ -0,0 +1,15 @@
+-- Migration 043: Scan checkpoints
+-- checkpoint_file_id is the last scan_files row of a scan when its position
+-- was last saved. Results recorded after it belong to files the scan finished
+-- after the checkpoint, which a scan resumed after a crash skips instead of
+-- checking again.
+
+ALTER TABLE scans ADD COLUMN checkpoint_file_id INTEGER;
+
+-- Scans left running by a crash are resumed from now on. Those of earlier
+-- versions, possibly long gone, are marked failed instead of all resuming at
+-- once on the first start after the upgrade.
+DELETE FROM scan_file_queue WHERE scan_id IN (SELECT id FROM scans WHERE status = 'running');
+UPDATE scans
+SET status = 'error', error_message = 'Interrupted before upgrading', completed_at = CURRENT_TIMESTAMP
+WHERE status = 'running';
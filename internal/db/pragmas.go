@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// SQLiteOptions are the tunable SQLite pragmas of a Repository.
+type SQLiteOptions struct {
+	// JournalMode is WAL, DELETE, TRUNCATE or PERSIST. Only WAL lets reads run
+	// alongside the writer; the others are meant for file systems without
+	// shared memory support, such as some network shares.
+	JournalMode string
+	// Synchronous is FULL, NORMAL or OFF. NORMAL is safe in WAL mode, but the
+	// last transactions may be lost on power failure.
+	Synchronous string
+	// CacheSizeMB is the page cache of each connection.
+	CacheSizeMB int
+	// MmapSizeMB is how much of the database file is memory-mapped per
+	// connection (0 = no memory mapping).
+	MmapSizeMB int
+	// BusyTimeout is how long a write waits on a lock before failing with SQLITE_BUSY.
+	BusyTimeout time.Duration
+}
+
+// DefaultSQLiteOptions returns the pragmas used unless configured otherwise:
+// durability first, since the events table is the source of truth.
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		JournalMode: "WAL",
+		Synchronous: "FULL",
+		CacheSizeMB: 8,
+		MmapSizeMB:  0,
+		BusyTimeout: 30 * time.Second,
+	}
+}
+
+// normalize upper-cases the modes and replaces invalid values with the
+// defaults, logging each replacement.
+func (o SQLiteOptions) normalize() SQLiteOptions {
+	def := DefaultSQLiteOptions()
+
+	o.JournalMode = strings.ToUpper(strings.TrimSpace(o.JournalMode))
+	switch o.JournalMode {
+	case "WAL", "DELETE", "TRUNCATE", "PERSIST":
+	default:
+		if o.JournalMode != "" {
+			logger.Warnf("Invalid SQLite journal mode %q, using %s", o.JournalMode, def.JournalMode)
+		}
+		o.JournalMode = def.JournalMode
+	}
+
+	o.Synchronous = strings.ToUpper(strings.TrimSpace(o.Synchronous))
+	switch o.Synchronous {
+	case "FULL", "NORMAL", "OFF":
+	default:
+		if o.Synchronous != "" {
+			logger.Warnf("Invalid SQLite synchronous mode %q, using %s", o.Synchronous, def.Synchronous)
+		}
+		o.Synchronous = def.Synchronous
+	}
+
+	if o.CacheSizeMB <= 0 {
+		o.CacheSizeMB = def.CacheSizeMB
+	}
+	if o.MmapSizeMB < 0 {
+		o.MmapSizeMB = 0
+	}
+	if o.BusyTimeout <= 0 {
+		o.BusyTimeout = def.BusyTimeout
+	}
+	return o
+}
+
+// connectionPragmas are the pragmas that only apply to the connection they
+// are set on. They go into the DSN so every connection of a pool gets them,
+// not just the one that happens to run a PRAGMA statement.
+func (o SQLiteOptions) connectionPragmas(busyTimeout time.Duration) []string {
+	return []string{
+		fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+		"synchronous(" + o.Synchronous + ")",
+		// Negative cache sizes are in KiB
+		fmt.Sprintf("cache_size(%d)", -o.CacheSizeMB*1024),
+		fmt.Sprintf("mmap_size(%d)", int64(o.MmapSizeMB)<<20),
+		// Store temp tables in memory for performance
+		"temp_store(MEMORY)",
+	}
+}
+
+// sqliteDSN builds the DSN of a database file with per-connection pragmas.
+func sqliteDSN(path string, pragmas ...string) string {
+	if len(pragmas) == 0 {
+		return path
+	}
+	params := make([]string, len(pragmas))
+	for i, p := range pragmas {
+		params[i] = "_pragma=" + p
+	}
+	return path + "?" + strings.Join(params, "&")
+}
+
+// configureSQLite sets the pragmas that are stored in the database file: the
+// journal mode and auto-vacuum.
+func configureSQLite(db *sql.DB, opts SQLiteOptions) error {
+	// Critical: the journal mode decides whether readers block the writer
+	// Note: foreign_keys is enabled AFTER migrations to allow table recreation
+	if _, err := db.Exec("PRAGMA journal_mode=" + opts.JournalMode); err != nil {
+		return fmt.Errorf("failed to set critical pragma journal_mode=%s: %w", opts.JournalMode, err)
+	}
+	if opts.JournalMode != "WAL" {
+		logger.Warnf("SQLite journal mode is %s: reads and writes will block each other", opts.JournalMode)
+	}
+
+	// Non-critical pragmas - log failures but continue
+	optionalPragmas := []string{
+		// Auto-vacuum in incremental mode - reclaims space automatically
+		"PRAGMA auto_vacuum=INCREMENTAL",
+	}
+	for _, pragma := range optionalPragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			// Log but don't fail - some pragmas may not be supported
+			logger.Debugf("Failed to set optional pragma %s: %v", pragma, err)
+		}
+	}
+
+	return nil
+}
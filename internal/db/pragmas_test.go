@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewRepositoryWithOptions_AppliesPragmasToEveryConnection(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	repo, err := NewRepositoryWithOptions(dbPath, SQLiteOptions{
+		JournalMode: "wal",
+		Synchronous: "normal",
+		CacheSizeMB: 16,
+		MmapSizeMB:  64,
+		BusyTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewRepositoryWithOptions failed: %v", err)
+	}
+	defer repo.Close()
+
+	// Hold two connections at once so the pool can't hand out the same one twice
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		conn, err := repo.DB.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get connection: %v", err)
+		}
+		defer conn.Close()
+
+		var synchronous, cacheSize, mmapSize, busyTimeout int64
+		for _, q := range []struct {
+			pragma string
+			dest   *int64
+		}{
+			{"synchronous", &synchronous},
+			{"cache_size", &cacheSize},
+			{"mmap_size", &mmapSize},
+			{"busy_timeout", &busyTimeout},
+		} {
+			if err := conn.QueryRowContext(ctx, "PRAGMA "+q.pragma).Scan(q.dest); err != nil {
+				t.Fatalf("Failed to read %s: %v", q.pragma, err)
+			}
+		}
+		if synchronous != 1 || cacheSize != -16*1024 || mmapSize != 64<<20 || busyTimeout != 10000 {
+			t.Errorf("Connection %d: synchronous=%d cache_size=%d mmap_size=%d busy_timeout=%d",
+				i, synchronous, cacheSize, mmapSize, busyTimeout)
+		}
+	}
+
+	stats, err := repo.GetDatabaseStats()
+	if err != nil {
+		t.Fatalf("GetDatabaseStats failed: %v", err)
+	}
+	if stats["synchronous"] != "normal" || stats["cache_size_bytes"] != int64(16<<20) || stats["mmap_size_bytes"] != int64(64<<20) {
+		t.Errorf("Unexpected pragma stats: %v", stats)
+	}
+}
+
+func TestSQLiteOptions_NormalizeFallsBackToDefaults(t *testing.T) {
+	got := SQLiteOptions{JournalMode: "memory", Synchronous: "sometimes", CacheSizeMB: -1, MmapSizeMB: -5}.normalize()
+	want := DefaultSQLiteOptions()
+	if got != want {
+		t.Errorf("normalize() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+// hotQueries are the queries that run for every event, every scanned file or
+// every page load. Each must be answered through the index it names instead
+// of a full table scan, so a schema change that loses the index fails here
+// rather than slowing down large installations.
+var hotQueries = []struct {
+	name  string
+	query string
+	args  []interface{}
+	index string
+}{
+	{
+		name:  "current state of a corruption",
+		query: `SELECT event_type FROM events WHERE aggregate_id = ? AND event_type IN ('CorruptionDetected', 'DeletionStarted') ORDER BY id DESC LIMIT 1`,
+		args:  []interface{}{"c1"},
+		index: "idx_events_aggregate_event",
+	},
+	{
+		name:  "event of a type for a corruption",
+		query: `SELECT event_data FROM events WHERE aggregate_id = ? AND event_type = ? ORDER BY id DESC LIMIT 1`,
+		args:  []interface{}{"c1", "VerificationSuccess"},
+		index: "idx_events_aggregate_event",
+	},
+	{
+		name:  "history of a corruption",
+		query: `SELECT event_type, event_data FROM events WHERE aggregate_id = ? ORDER BY id ASC`,
+		args:  []interface{}{"c1"},
+		index: "idx_events_aggregate_event",
+	},
+	{
+		name:  "events of a type in a time window",
+		query: `SELECT aggregate_id FROM events WHERE event_type = 'CorruptionDetected' AND created_at >= ? AND created_at < ?`,
+		args:  []interface{}{"2026-01-01", "2026-02-01"},
+		index: "idx_events_type_created_at",
+	},
+	{
+		name: "active corruption of a file",
+		query: `SELECT COUNT(*) FROM events e1
+			WHERE e1.event_type = 'CorruptionDetected' AND json_extract(e1.event_data, '$.file_path') = ?`,
+		args:  []interface{}{"/media/a.mkv"},
+		index: "idx_events_type_file_path",
+	},
+	{
+		name:  "corruption summary by ID",
+		query: `SELECT current_state, file_path FROM corruption_summary WHERE corruption_id = ?`,
+		args:  []interface{}{"c1"},
+		index: "sqlite_autoindex_corruption_summary_1",
+	},
+	{
+		name:  "corruptions in a state, newest first",
+		query: `SELECT corruption_id, file_path FROM corruption_summary WHERE current_state = ? ORDER BY last_updated_at DESC LIMIT ?`,
+		args:  []interface{}{"VerificationSuccess", 50},
+		index: "idx_corruption_summary_state_updated",
+	},
+	{
+		name:  "corruptions of a path",
+		query: `SELECT COUNT(*) FROM corruption_summary WHERE path_id = ?`,
+		args:  []interface{}{1},
+		index: "idx_corruption_summary_path_id",
+	},
+	{
+		name:  "results of a scan",
+		query: `SELECT file_path, status FROM scan_files WHERE scan_id = ?`,
+		args:  []interface{}{1},
+		index: "idx_scan_files_scan_status",
+	},
+	{
+		name:  "result counts of a scan",
+		query: `SELECT status, COUNT(*) FROM scan_files WHERE scan_id = ? GROUP BY status`,
+		args:  []interface{}{1},
+		index: "idx_scan_files_scan_status",
+	},
+	{
+		name:  "scan history of a file",
+		query: `SELECT scan_id, status FROM scan_files WHERE file_path = ?`,
+		args:  []interface{}{"/media/a.mkv"},
+		index: "idx_scan_files_file_path",
+	},
+	{
+		name:  "scans of a path",
+		query: `SELECT id, status FROM scans WHERE path = ?`,
+		args:  []interface{}{"/media"},
+		index: "idx_scans_path",
+	},
+	{
+		name:  "running scans",
+		query: `SELECT id FROM scans WHERE status = 'running'`,
+		index: "idx_scans_status",
+	},
+}
+
+func TestQueryPlans_HotQueriesUseIndexes(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, hq := range hotQueries {
+		t.Run(hq.name, func(t *testing.T) {
+			rows, err := repo.DB.Query("EXPLAIN QUERY PLAN "+hq.query, hq.args...)
+			if err != nil {
+				t.Fatalf("EXPLAIN QUERY PLAN failed: %v", err)
+			}
+			defer rows.Close()
+
+			var steps []string
+			for rows.Next() {
+				var id, parent, notUsed int
+				var detail string
+				if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+					t.Fatalf("Failed to read query plan: %v", err)
+				}
+				steps = append(steps, detail)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("Failed to read query plan: %v", err)
+			}
+
+			plan := strings.Join(steps, "\n")
+			if !strings.Contains(plan, "INDEX "+hq.index) {
+				t.Errorf("Expected the plan to use %s, got:\n%s", hq.index, plan)
+			}
+			for _, step := range steps {
+				// "SCAN t" without an index reads every row
+				if strings.HasPrefix(step, "SCAN ") && !strings.Contains(step, " USING ") {
+					t.Errorf("Expected no full table scan, got %q in:\n%s", step, plan)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Startup problems Diagnose can identify.
+const (
+	ProblemPermissions = "permissions"
+	ProblemLocked      = "locked"
+	ProblemOrphanedWAL = "orphaned_wal"
+	ProblemCorrupt     = "corrupt"
+)
+
+// sqliteHeader starts every SQLite database file.
+const sqliteHeader = "SQLite format 3\x00"
+
+// Finding is one cause Diagnose found for a database that can't be opened.
+type Finding struct {
+	Problem string
+	Detail  string
+	// Hint tells the user what to do about it
+	Hint string
+}
+
+// String formats the finding for the log.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Problem, f.Detail, f.Hint)
+}
+
+// Diagnosis is the result of Diagnose.
+type Diagnosis struct {
+	Path     string
+	Findings []Finding
+}
+
+// Healthy reports whether Diagnose found no problem.
+func (d Diagnosis) Healthy() bool {
+	return len(d.Findings) == 0
+}
+
+// Has reports whether Diagnose found problem.
+func (d Diagnosis) Has(problem string) bool {
+	for _, f := range d.Findings {
+		if f.Problem == problem {
+			return true
+		}
+	}
+	return false
+}
+
+// add records a finding.
+func (d *Diagnosis) add(problem, hint, format string, args ...interface{}) {
+	d.Findings = append(d.Findings, Finding{Problem: problem, Detail: fmt.Sprintf(format, args...), Hint: hint})
+}
+
+// Diagnose looks for the usual reasons the database at dbPath fails to open:
+// files the process may not write, a lock held by another process, a
+// write-ahead log left behind by a crash, and a damaged database file. It
+// repairs nothing, but like any connection it lets SQLite fold a leftover
+// write-ahead log into the database.
+func Diagnose(dbPath string) Diagnosis {
+	d := Diagnosis{Path: dbPath}
+
+	dir := filepath.Dir(dbPath)
+	if info, err := os.Stat(dir); err != nil {
+		if os.IsPermission(err) {
+			d.add(ProblemPermissions, "give the Healarr user access to the directory", "cannot access %s: %v", dir, err)
+		}
+		// A missing directory is created on startup
+		return d
+	} else if !info.IsDir() {
+		d.add(ProblemPermissions, "point HEALARR_DATABASE_PATH at a file in a directory", "%s is not a directory", dir)
+		return d
+	}
+	if err := checkDirWritable(dir); err != nil {
+		d.add(ProblemPermissions, "make the directory writable for the Healarr user (check the PUID/PGID or file owner)", "cannot create files in %s: %v", dir, err)
+	}
+
+	walPath, shmPath := dbPath+"-wal", dbPath+"-shm"
+	dbExists := fileExists(dbPath)
+	for _, path := range []string{dbPath, walPath, shmPath} {
+		if !fileExists(path) {
+			continue
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0) // #nosec G304 -- database files from the config
+		if err != nil {
+			if os.IsPermission(err) {
+				d.add(ProblemPermissions, "change the owner of the file to the Healarr user", "cannot write %s: %v", path, err)
+			}
+			continue
+		}
+		f.Close()
+	}
+	if d.Has(ProblemPermissions) {
+		return d
+	}
+
+	if !dbExists {
+		if fileSize(walPath) > 0 {
+			d.add(ProblemOrphanedWAL, "the write-ahead log belongs to a database that is gone; restore a backup or move the -wal file away", "%s exists without its database", walPath)
+		}
+		return d
+	}
+
+	if size := fileSize(dbPath); size > 0 {
+		if err := checkSQLiteHeader(dbPath); err != nil {
+			d.add(ProblemCorrupt, "restore the latest backup (start with --recover)", "%v", err)
+			return d
+		}
+	}
+
+	orphanedWAL := fileSize(walPath) > 0 && !fileExists(shmPath)
+
+	conn, err := sql.Open("sqlite", sqliteDSN(dbPath, "busy_timeout(0)", "locking_mode(EXCLUSIVE)"))
+	if err != nil {
+		d.add(ProblemCorrupt, "restore the latest backup (start with --recover)", "cannot open %s: %v", dbPath, err)
+		return d
+	}
+	defer conn.Close()
+	conn.SetMaxOpenConns(1)
+
+	if locked, err := probeLock(conn); locked {
+		d.add(ProblemLocked, "stop the other Healarr instance or process using the database", "%s is locked by another process", dbPath)
+		return d
+	} else if err != nil {
+		d.add(ProblemCorrupt, "restore the latest backup (start with --recover)", "cannot read %s: %v", dbPath, err)
+		return d
+	}
+
+	if orphanedWAL {
+		d.add(ProblemOrphanedWAL, "checkpoint it into the database (start with --recover)", "%s was left behind by an unclean shutdown", walPath)
+	}
+
+	var result string
+	if err := conn.QueryRow("PRAGMA quick_check").Scan(&result); err != nil {
+		d.add(ProblemCorrupt, "restore the latest backup (start with --recover)", "integrity check failed: %v", err)
+	} else if result != "ok" {
+		d.add(ProblemCorrupt, "restore the latest backup (start with --recover)", "integrity check failed: %s", result)
+	}
+	return d
+}
+
+// probeLock reports whether another connection has the database open. In
+// exclusive locking mode a write transaction needs every other connection,
+// including idle ones in WAL mode, to have let go of the file.
+func probeLock(conn *sql.DB) (bool, error) {
+	if _, err := conn.Exec("BEGIN IMMEDIATE"); err != nil {
+		return isLockedError(err), err
+	}
+	_, err := conn.Exec("ROLLBACK")
+	return false, err
+}
+
+// isLockedError reports whether err is SQLite's SQLITE_BUSY.
+func isLockedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "database is locked")
+}
+
+// RecoveryStep is one action Recover took.
+type RecoveryStep struct {
+	Action string
+	Detail string
+}
+
+// Recover attempts the safe recovery steps for the problems of diagnosis:
+// checkpointing an orphaned write-ahead log, and restoring the newest
+// verified backup from the backups directory next to the database when the
+// database is damaged. The damaged files are moved aside rather than deleted.
+// It refuses to touch a database another process holds, or files it can't
+// write. Every step is logged and returned.
+func Recover(diagnosis Diagnosis) ([]RecoveryStep, error) {
+	var steps []RecoveryStep
+	step := func(action, format string, args ...interface{}) {
+		s := RecoveryStep{Action: action, Detail: fmt.Sprintf(format, args...)}
+		logger.Infof("Database recovery: %s: %s", s.Action, s.Detail)
+		steps = append(steps, s)
+	}
+
+	switch {
+	case diagnosis.Has(ProblemPermissions):
+		return steps, errors.New("file permissions must be fixed by hand")
+	case diagnosis.Has(ProblemLocked):
+		return steps, errors.New("the database is in use by another process")
+	}
+
+	dbPath := diagnosis.Path
+	corrupt := diagnosis.Has(ProblemCorrupt)
+	// A log without its database can't be replayed into anything
+	walWithoutDB := diagnosis.Has(ProblemOrphanedWAL) && !fileExists(dbPath)
+	if diagnosis.Has(ProblemOrphanedWAL) && !walWithoutDB && !corrupt {
+		if err := checkpointWAL(dbPath); err != nil {
+			step("checkpoint", "failed to checkpoint the write-ahead log: %v", err)
+			corrupt = true
+		} else {
+			step("checkpoint", "write-ahead log of %s checkpointed into the database", filepath.Base(dbPath))
+			if err := verifyBackupIntegrity(dbPath); err != nil {
+				step("verify", "database still damaged after the checkpoint: %v", err)
+				corrupt = true
+			}
+		}
+	}
+	if !corrupt && !walWithoutDB {
+		return steps, nil
+	}
+
+	backupPath, err := latestVerifiedBackup(filepath.Join(filepath.Dir(dbPath), "backups"))
+	if err != nil {
+		return steps, err
+	}
+	step("select backup", "restoring %s", backupPath)
+
+	suffix := ".corrupt-" + time.Now().Format("20060102_150405")
+	for _, path := range []string{dbPath, dbPath + "-wal", dbPath + "-shm"} {
+		if !fileExists(path) {
+			continue
+		}
+		if err := os.Rename(path, path+suffix); err != nil {
+			return steps, fmt.Errorf("failed to move %s aside: %w", path, err)
+		}
+		step("move aside", "%s moved to %s", filepath.Base(path), filepath.Base(path+suffix))
+	}
+	if err := copyFile(backupPath, dbPath); err != nil {
+		return steps, fmt.Errorf("failed to restore %s: %w", backupPath, err)
+	}
+	step("restore", "%s restored from %s; changes made since the backup are lost", filepath.Base(dbPath), filepath.Base(backupPath))
+	return steps, nil
+}
+
+// checkpointWAL replays the write-ahead log of the database at dbPath into
+// the database file and truncates it.
+func checkpointWAL(dbPath string) error {
+	conn, err := sql.Open("sqlite", sqliteDSN(dbPath, "busy_timeout(5000)"))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var busy, logFrames, checkpointed int
+	if err := conn.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return err
+	}
+	if busy != 0 {
+		return errors.New("checkpoint blocked by another connection")
+	}
+	return nil
+}
+
+// latestVerifiedBackup returns the newest backup in backupDir that passes an
+// integrity check. Uploads staged for a restore are not considered.
+func latestVerifiedBackup(backupDir string) (string, error) {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return "", fmt.Errorf("no backups to restore: %w", err)
+	}
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	var candidates []candidate
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".db") || strings.HasPrefix(name, "restore_temp_") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{path: filepath.Join(backupDir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.After(candidates[j].modTime)
+	})
+	for _, c := range candidates {
+		if err := verifyBackupIntegrity(c.path); err != nil {
+			logger.Warnf("Database recovery: skipping backup %s: %v", filepath.Base(c.path), err)
+			continue
+		}
+		return c.path, nil
+	}
+	return "", fmt.Errorf("no intact backup in %s", backupDir)
+}
+
+// checkSQLiteHeader returns an error if the file at path isn't an SQLite database.
+func checkSQLiteHeader(path string) error {
+	f, err := os.Open(path) // #nosec G304 -- database file from the config
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(f, header); err != nil || string(header) != sqliteHeader {
+		return fmt.Errorf("%s is not an SQLite database or its header is damaged", path)
+	}
+	return nil
+}
+
+// checkDirWritable creates and removes a file in dir.
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".healarr-write-check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// copyFile copies src to a new file dst, syncing it to disk.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) // #nosec G304 -- backup from the backups directory
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec G304 -- database path from the config
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fileSize returns the size of the file at path, or 0 if it doesn't exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
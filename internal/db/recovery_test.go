@@ -0,0 +1,131 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newRecoveryTestDB creates a database with one backup and returns its path.
+func newRecoveryTestDB(t *testing.T) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "healarr.db")
+	repo, err := NewRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if _, err := repo.DB.Exec(`INSERT INTO settings (key, value) VALUES ('recovery_test', 'kept')`); err != nil {
+		t.Fatalf("Failed to insert setting: %v", err)
+	}
+	if _, err := repo.Backup(dbPath); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+	repo.Close()
+	return dbPath
+}
+
+func TestDiagnose_Healthy(t *testing.T) {
+	dbPath := newRecoveryTestDB(t)
+	if d := Diagnose(dbPath); !d.Healthy() {
+		t.Errorf("Expected no findings, got %v", d.Findings)
+	}
+	// A database that doesn't exist yet is created on startup
+	if d := Diagnose(filepath.Join(t.TempDir(), "new.db")); !d.Healthy() {
+		t.Errorf("Expected no findings for a new database, got %v", d.Findings)
+	}
+}
+
+func TestDiagnose_Locked(t *testing.T) {
+	dbPath := newRecoveryTestDB(t)
+	repo, err := NewRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	defer repo.Close()
+
+	d := Diagnose(dbPath)
+	if !d.Has(ProblemLocked) {
+		t.Fatalf("Expected the open database to be reported locked, got %v", d.Findings)
+	}
+	if _, err := Recover(d); err == nil {
+		t.Error("Expected Recover to refuse a database in use")
+	}
+}
+
+func TestDiagnose_OrphanedWAL(t *testing.T) {
+	dbPath := newRecoveryTestDB(t)
+	if err := os.WriteFile(dbPath+"-wal", []byte("stale"), 0600); err != nil {
+		t.Fatalf("Failed to write WAL: %v", err)
+	}
+	os.Remove(dbPath + "-shm")
+
+	if d := Diagnose(dbPath); !d.Has(ProblemOrphanedWAL) {
+		t.Errorf("Expected an orphaned write-ahead log, got %v", d.Findings)
+	}
+
+	// Without its database the log is moved aside and the backup restored
+	if err := os.Remove(dbPath); err != nil {
+		t.Fatalf("Failed to remove database: %v", err)
+	}
+	if err := os.WriteFile(dbPath+"-wal", []byte("stale"), 0600); err != nil {
+		t.Fatalf("Failed to write WAL: %v", err)
+	}
+	d := Diagnose(dbPath)
+	if !d.Has(ProblemOrphanedWAL) {
+		t.Fatalf("Expected a write-ahead log without its database, got %v", d.Findings)
+	}
+	if _, err := Recover(d); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if fileExists(dbPath+"-wal") || !fileExists(dbPath) {
+		t.Error("Expected the log to be moved aside and the database restored")
+	}
+}
+
+func TestRecover_RestoresBackup(t *testing.T) {
+	dbPath := newRecoveryTestDB(t)
+	if err := os.WriteFile(dbPath, []byte("this is not a database, just garbage bytes"), 0600); err != nil {
+		t.Fatalf("Failed to damage database: %v", err)
+	}
+
+	d := Diagnose(dbPath)
+	if !d.Has(ProblemCorrupt) {
+		t.Fatalf("Expected the damaged database to be reported corrupt, got %v", d.Findings)
+	}
+	steps, err := Recover(d)
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if len(steps) == 0 || steps[len(steps)-1].Action != "restore" {
+		t.Errorf("Expected the last step to restore the backup, got %v", steps)
+	}
+
+	// The damaged file is kept next to the restored database
+	matches, _ := filepath.Glob(dbPath + ".corrupt-*")
+	if len(matches) != 1 {
+		t.Errorf("Expected the damaged database to be moved aside, got %v", matches)
+	}
+	repo, err := NewRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Restored database fails to open: %v", err)
+	}
+	defer repo.Close()
+	var value string
+	if err := repo.DB.QueryRow(`SELECT value FROM settings WHERE key = 'recovery_test'`).Scan(&value); err != nil || value != "kept" {
+		t.Errorf("Expected the restored database to hold the backed up data, got %q (%v)", value, err)
+	}
+}
+
+func TestRecover_NoBackup(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "healarr.db")
+	if err := os.WriteFile(dbPath, []byte("garbage"), 0600); err != nil {
+		t.Fatalf("Failed to write database: %v", err)
+	}
+	if _, err := Recover(Diagnose(dbPath)); err == nil {
+		t.Error("Expected Recover to fail without a backup")
+	}
+	// Nothing is moved when there's no backup to restore
+	if !fileExists(dbPath) {
+		t.Error("Expected the database to stay in place")
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite" // Register pure-Go SQLite driver for database/sql
@@ -28,17 +30,36 @@ var migrationsFS embed.FS
 // Repository provides database access methods for the application.
 type Repository struct {
 	DB *sql.DB
+	// ReadDB is an optional query-only pool for read-heavy callers (see OpenReadPool).
+	// Nil until opened.
+	ReadDB *sql.DB
+	// ArrKeyEncryption is the result of the startup check of *arr API keys.
+	// Nil if the check could not run.
+	ArrKeyEncryption *ArrKeyEncryptionReport
+	path             string
+	options          SQLiteOptions
+
+	pruneMu       sync.RWMutex
+	pruneObserver func(category string, count int64)
 }
 
 // NewRepository creates a new Repository with the database at the given path.
 func NewRepository(dbPath string) (*Repository, error) {
+	return NewRepositoryWithOptions(dbPath, DefaultSQLiteOptions())
+}
+
+// NewRepositoryWithOptions creates a new Repository with the database at the
+// given path and the given SQLite tuning. Invalid options fall back to the defaults.
+func NewRepositoryWithOptions(dbPath string, opts SQLiteOptions) (*Repository, error) {
+	opts = opts.normalize()
+
 	// Ensure directory exists with restricted permissions (owner only)
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath, opts.connectionPragmas(opts.BusyTimeout)...))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -56,11 +77,11 @@ func NewRepository(dbPath string) (*Repository, error) {
 	}
 
 	// Configure SQLite for reliability and performance
-	if err := configureSQLite(db); err != nil {
+	if err := configureSQLite(db, opts); err != nil {
 		return nil, fmt.Errorf("failed to configure database: %w", err)
 	}
 
-	repo := &Repository{DB: db}
+	repo := &Repository{DB: db, path: dbPath, options: opts}
 	if err := repo.runMigrations(); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -83,6 +104,14 @@ func NewRepository(dbPath string) (*Repository, error) {
 		// Non-fatal - continue with startup
 	}
 
+	// Encrypt plain-text *arr API keys and flag keys that can't be decrypted
+	report, err := repo.migrateArrAPIKeyEncryption()
+	if err != nil {
+		logger.Errorf("Warning: failed to migrate *arr API key encryption: %v", err)
+		// Non-fatal - continue with startup
+	}
+	repo.ArrKeyEncryption = report
+
 	// Run integrity check on startup
 	if err := repo.checkIntegrity(); err != nil {
 		logger.Errorf("Warning: database integrity check failed: %v", err)
@@ -92,46 +121,59 @@ func NewRepository(dbPath string) (*Repository, error) {
 	return repo, nil
 }
 
-// configureSQLite sets optimal SQLite pragmas for reliability and performance
-func configureSQLite(db *sql.DB) error {
-	// Critical pragmas that must succeed for proper database operation
-	// Note: foreign_keys is enabled AFTER migrations to allow table recreation
-	criticalPragmas := []string{
-		// WAL mode for better concurrency and crash recovery
-		"PRAGMA journal_mode=WAL",
-		// Busy timeout of 30 seconds to handle concurrent access during heavy scans
-		"PRAGMA busy_timeout=30000",
+// OpenReadPool opens a second pool of query-only connections to the database.
+// In WAL mode readers never wait for the writer, but they do wait for a free
+// connection: during a big scan the main pool is busy writing events and
+// dashboard queries queue behind them. A separate pool keeps reads responsive.
+// busyTimeout bounds how long a read waits on a lock (e.g. during a checkpoint).
+func (r *Repository) OpenReadPool(maxConns int, busyTimeout time.Duration) error {
+	if maxConns <= 0 || r.path == "" {
+		return nil
 	}
 
-	for _, pragma := range criticalPragmas {
-		if _, err := db.Exec(pragma); err != nil {
-			return fmt.Errorf("failed to set critical pragma %s: %w", pragma, err)
-		}
+	opts := r.options
+	if opts == (SQLiteOptions{}) {
+		opts = DefaultSQLiteOptions()
 	}
-
-	// Non-critical pragmas - log failures but continue
-	optionalPragmas := []string{
-		// Synchronous FULL ensures durability even on power loss during checkpoint
-		// Slightly slower than NORMAL but prevents corruption on unexpected shutdown
-		"PRAGMA synchronous=FULL",
-		// Auto-vacuum in incremental mode - reclaims space automatically
-		"PRAGMA auto_vacuum=INCREMENTAL",
-		// Store temp tables in memory for performance
-		"PRAGMA temp_store=MEMORY",
-		// Increase cache size (negative = KB, so -8000 = 8MB)
-		"PRAGMA cache_size=-8000",
+	pragmas := append(opts.connectionPragmas(busyTimeout), "query_only(1)")
+	readDB, err := sql.Open("sqlite", sqliteDSN(r.path, pragmas...))
+	if err != nil {
+		return fmt.Errorf("failed to open read pool: %w", err)
 	}
 
-	for _, pragma := range optionalPragmas {
-		if _, err := db.Exec(pragma); err != nil {
-			// Log but don't fail - some pragmas may not be supported
-			logger.Debugf("Failed to set optional pragma %s: %v", pragma, err)
-		}
+	readDB.SetMaxOpenConns(maxConns)
+	readDB.SetMaxIdleConns(maxConns)
+	readDB.SetConnMaxLifetime(0)
+	readDB.SetConnMaxIdleTime(5 * time.Minute)
+
+	if err := readDB.Ping(); err != nil {
+		readDB.Close()
+		return fmt.Errorf("failed to ping read pool: %w", err)
 	}
 
+	r.ReadDB = readDB
 	return nil
 }
 
+// Reader returns the query-only pool if one is open, otherwise the main pool.
+func (r *Repository) Reader() *sql.DB {
+	if r.ReadDB != nil {
+		return r.ReadDB
+	}
+	return r.DB
+}
+
+// closeReadPool closes the query-only pool, if any.
+func (r *Repository) closeReadPool() {
+	if r.ReadDB == nil {
+		return
+	}
+	if err := r.ReadDB.Close(); err != nil {
+		logger.Debugf("Failed to close read pool: %v", err)
+	}
+	r.ReadDB = nil
+}
+
 // checkIntegrity runs a quick integrity check on the database
 func (r *Repository) checkIntegrity() error {
 	var result string
@@ -148,6 +190,7 @@ func (r *Repository) checkIntegrity() error {
 
 // Close closes the database connection.
 func (r *Repository) Close() error {
+	r.closeReadPool()
 	return r.DB.Close()
 }
 
@@ -159,6 +202,9 @@ func (r *Repository) Close() error {
 func (r *Repository) GracefulClose() error {
 	logger.Infof("Database: initiating graceful shutdown...")
 
+	// Readers would hold the WAL open and keep the checkpoint from truncating it
+	r.closeReadPool()
+
 	// Run final checkpoint to merge WAL into main database
 	if _, err := r.DB.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
 		logger.Warnf("Shutdown WAL checkpoint failed: %v", err)
@@ -211,33 +257,16 @@ func (r *Repository) StartPeriodicCheckpoint(interval time.Duration) func() {
 	}
 }
 
-// recreateViews drops and recreates database views to ensure they match the latest schema.
-// This is necessary because SQLite views are not automatically updated when the schema changes.
-// Note: corruption_status VIEW is now a thin wrapper over corruption_summary TABLE for backwards compatibility.
-// createViewsWithSummaryTable creates optimized views using corruption_summary table
+// createViewsWithSummaryTable creates the views for databases with the
+// corruption_summary table: corruption_status computes the summaries from the
+// events to rebuild and check the table, dashboard_stats reads the table.
 func (r *Repository) createViewsWithSummaryTable() error {
-	// corruption_status VIEW is a thin wrapper for backwards compatibility
-	_, err := r.DB.Exec(`
-		CREATE VIEW corruption_status AS
-		SELECT
-			corruption_id,
-			current_state,
-			retry_count,
-			file_path,
-			path_id,
-			last_error,
-			corruption_type,
-			media_type,
-			detected_at,
-			last_updated_at
-		FROM corruption_summary
-	`)
-	if err != nil {
+	if _, err := r.DB.Exec(corruptionStatusView); err != nil {
 		return fmt.Errorf("failed to create corruption_status view: %w", err)
 	}
 
 	// dashboard_stats uses corruption_summary directly for maximum performance
-	_, err = r.DB.Exec(`
+	_, err := r.DB.Exec(`
 		CREATE VIEW dashboard_stats AS
 		SELECT
 			COUNT(CASE
@@ -257,6 +286,7 @@ func (r *Repository) createViewsWithSummaryTable() error {
 				WHEN (current_state LIKE '%Started'
 				OR current_state LIKE '%Queued'
 				OR current_state LIKE '%Progress'
+				OR current_state = 'DownloadRejected'
 				OR current_state = 'SearchCompleted'
 				OR current_state = 'DeletionCompleted'
 				OR current_state = 'FileDetected')
@@ -333,6 +363,7 @@ func (r *Repository) createViewsLegacy() error {
 				WHEN (current_state LIKE '%Started'
 				OR current_state LIKE '%Queued'
 				OR current_state LIKE '%Progress'
+				OR current_state = 'DownloadRejected'
 				OR current_state = 'SearchCompleted'
 				OR current_state = 'DeletionCompleted'
 				OR current_state = 'FileDetected')
@@ -352,6 +383,8 @@ func (r *Repository) createViewsLegacy() error {
 	return nil
 }
 
+// recreateViews drops and recreates database views to ensure they match the latest schema.
+// This is necessary because SQLite views are not automatically updated when the schema changes.
 func (r *Repository) recreateViews() error {
 	// Drop existing views
 	// Security: view names are hardcoded in this slice, not from user input
@@ -385,13 +418,15 @@ func (r *Repository) recreateViews() error {
 
 // pruneOperation represents a data pruning operation with query and logging format.
 type pruneOperation struct {
-	name   string
-	query  string
-	args   []interface{}
-	format string
+	name     string
+	category string
+	query    string
+	args     []interface{}
+	format   string
 }
 
-// executePruneOperation executes a pruning query and logs the result.
+// executePruneOperation executes a pruning query, logs the result and reports
+// it to the prune observer.
 func (r *Repository) executePruneOperation(op pruneOperation) {
 	result, err := r.DB.Exec(op.query, op.args...)
 	if err != nil {
@@ -400,20 +435,66 @@ func (r *Repository) executePruneOperation(op pruneOperation) {
 	}
 	if deleted, _ := result.RowsAffected(); deleted > 0 {
 		logger.Infof(op.format, deleted)
+		r.reportPruned(op.category, deleted)
 	}
 }
 
 // executeMaintenanceCommand executes a maintenance SQL command and logs the result.
-func (r *Repository) executeMaintenanceCommand(name, sql string, warnOnError bool) {
-	if _, err := r.DB.Exec(sql); err != nil {
+// Errors are returned when warnOnError is set; otherwise the command is
+// optional and its failure only logged.
+func (r *Repository) executeMaintenanceCommand(ctx context.Context, name, sql string, warnOnError bool) error {
+	if _, err := r.DB.ExecContext(ctx, sql); err != nil {
 		if warnOnError {
 			logger.Errorf("Failed to run %s: %v", name, err)
-		} else {
-			logger.Debugf("%s failed (might not be applicable): %v", name, err)
+			return fmt.Errorf("%s: %w", name, err)
 		}
-		return
+		logger.Debugf("%s failed (might not be applicable): %v", name, err)
+		return nil
 	}
 	logger.Debugf("%s completed", name)
+	return nil
+}
+
+// Maintenance step names, in the order MaintenanceSteps returns them.
+const (
+	MaintenanceStepPrune      = "prune"
+	MaintenanceStepSummary    = "summary"
+	MaintenanceStepVacuum     = "vacuum"
+	MaintenanceStepAnalyze    = "analyze"
+	MaintenanceStepCheckpoint = "checkpoint"
+)
+
+// MaintenanceStep is one stage of database maintenance. Cancelling the
+// context passed to Run interrupts the statement it is executing.
+type MaintenanceStep struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// MaintenanceSteps returns the stages of database maintenance, pruning
+// history according to policy.
+func (r *Repository) MaintenanceSteps(policy RetentionPolicy) []MaintenanceStep {
+	command := func(name, sql string, warnOnError bool) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			return r.executeMaintenanceCommand(ctx, name, sql, warnOnError)
+		}
+	}
+	return []MaintenanceStep{
+		{MaintenanceStepPrune, func(context.Context) error {
+			r.pruneHistory(policy)
+			return nil
+		}},
+		{MaintenanceStepSummary, func(ctx context.Context) error {
+			if _, err := RepairCorruptionSummary(ctx, r.DB); err != nil {
+				logger.Errorf("Failed to repair corruption summary: %v", err)
+				return fmt.Errorf("corruption summary repair: %w", err)
+			}
+			return nil
+		}},
+		{MaintenanceStepVacuum, command("incremental vacuum", "PRAGMA incremental_vacuum", true)},
+		{MaintenanceStepAnalyze, command("database analysis", "ANALYZE", true)},
+		{MaintenanceStepCheckpoint, command("WAL checkpoint", "PRAGMA wal_checkpoint(TRUNCATE)", false)},
+	}
 }
 
 // RunMaintenance performs database maintenance tasks:
@@ -422,46 +503,17 @@ func (r *Repository) executeMaintenanceCommand(name, sql string, warnOnError boo
 // - Optimize indexes
 // Call this periodically (e.g., daily or weekly)
 func (r *Repository) RunMaintenance(retentionDays int) error {
-	logger.Infof("Starting database maintenance...")
+	return r.RunMaintenanceWithPolicy(UniformRetention(retentionDays))
+}
 
-	if retentionDays > 0 {
-		cutoff := time.Now().AddDate(0, 0, -retentionDays).Format(time.RFC3339)
-		pruneOps := []pruneOperation{
-			{
-				name:   "prune old events",
-				query:  "DELETE FROM events WHERE created_at < ?",
-				args:   []interface{}{cutoff},
-				format: "Pruned %d old events",
-			},
-			{
-				name:   "prune old scans",
-				query:  "DELETE FROM scans WHERE status IN ('completed', 'cancelled', 'error') AND completed_at < ?",
-				args:   []interface{}{cutoff},
-				format: "Pruned %d old scan records",
-			},
-			{
-				name:   "prune orphaned scan_files",
-				query:  "DELETE FROM scan_files WHERE scan_id NOT IN (SELECT id FROM scans)",
-				args:   nil,
-				format: "Pruned %d orphaned scan_files records",
-			},
-		}
-		for _, op := range pruneOps {
-			r.executePruneOperation(op)
-		}
-	}
+// RunMaintenanceWithPolicy performs the same tasks as RunMaintenance, pruning
+// each category of history according to its own retention in policy. A
+// failing step is logged and the remaining steps still run.
+func (r *Repository) RunMaintenanceWithPolicy(policy RetentionPolicy) error {
+	logger.Infof("Starting database maintenance...")
 
-	maintenanceOps := []struct {
-		name        string
-		sql         string
-		warnOnError bool
-	}{
-		{"incremental vacuum", "PRAGMA incremental_vacuum", true},
-		{"database analysis", "ANALYZE", true},
-		{"WAL checkpoint", "PRAGMA wal_checkpoint(TRUNCATE)", false},
-	}
-	for _, op := range maintenanceOps {
-		r.executeMaintenanceCommand(op.name, op.sql, op.warnOnError)
+	for _, step := range r.MaintenanceSteps(policy) {
+		_ = step.Run(context.Background())
 	}
 
 	logger.Infof("✓ Database maintenance completed")
@@ -470,14 +522,19 @@ func (r *Repository) RunMaintenance(retentionDays int) error {
 
 // GetDatabaseStats returns statistics about the database
 func (r *Repository) GetDatabaseStats() (map[string]interface{}, error) {
+	return DatabaseStats(r.DB)
+}
+
+// DatabaseStats returns size, page, row count and pragma statistics for an open database.
+func DatabaseStats(conn *sql.DB) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Get page count and page size
 	var pageCount, pageSize int64
-	if err := r.DB.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+	if err := conn.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
 		return nil, fmt.Errorf("failed to get page_count: %w", err)
 	}
-	if err := r.DB.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+	if err := conn.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
 		return nil, fmt.Errorf("failed to get page_size: %w", err)
 	}
 	stats["size_bytes"] = pageCount * pageSize
@@ -486,7 +543,7 @@ func (r *Repository) GetDatabaseStats() (map[string]interface{}, error) {
 
 	// Get freelist count (unused pages)
 	var freelistCount int64
-	if err := r.DB.QueryRow("PRAGMA freelist_count").Scan(&freelistCount); err != nil {
+	if err := conn.QueryRow("PRAGMA freelist_count").Scan(&freelistCount); err != nil {
 		return nil, fmt.Errorf("failed to get freelist_count: %w", err)
 	}
 	stats["freelist_pages"] = freelistCount
@@ -499,7 +556,7 @@ func (r *Repository) GetDatabaseStats() (map[string]interface{}, error) {
 	for _, table := range tables {
 		var count int64
 		// Table might not exist yet, so we don't fail on error here
-		if err := r.DB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count); err == nil { // NOSONAR - table name from hardcoded slice
+		if err := conn.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count); err == nil { // NOSONAR - table name from hardcoded slice
 			tableCounts[table] = count
 		}
 	}
@@ -507,19 +564,40 @@ func (r *Repository) GetDatabaseStats() (map[string]interface{}, error) {
 
 	// Get journal mode
 	var journalMode string
-	if err := r.DB.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil {
+	if err := conn.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil {
 		return nil, fmt.Errorf("failed to get journal_mode: %w", err)
 	}
 	stats["journal_mode"] = journalMode
 
 	// Get auto_vacuum setting
 	var autoVacuum int
-	if err := r.DB.QueryRow("PRAGMA auto_vacuum").Scan(&autoVacuum); err != nil {
+	if err := conn.QueryRow("PRAGMA auto_vacuum").Scan(&autoVacuum); err != nil {
 		return nil, fmt.Errorf("failed to get auto_vacuum: %w", err)
 	}
 	autoVacuumModes := map[int]string{0: "none", 1: "full", 2: "incremental"}
 	stats["auto_vacuum"] = autoVacuumModes[autoVacuum]
 
+	// Per-connection tuning (see SQLiteOptions); informational, so errors are ignored
+	var synchronous int
+	if err := conn.QueryRow("PRAGMA synchronous").Scan(&synchronous); err == nil {
+		stats["synchronous"] = map[int]string{0: "off", 1: "normal", 2: "full", 3: "extra"}[synchronous]
+	}
+	var cacheSize, mmapSize, busyTimeout int64
+	if err := conn.QueryRow("PRAGMA cache_size").Scan(&cacheSize); err == nil {
+		// Negative cache sizes are in KiB, positive ones in pages
+		if cacheSize < 0 {
+			stats["cache_size_bytes"] = -cacheSize * 1024
+		} else {
+			stats["cache_size_bytes"] = cacheSize * pageSize
+		}
+	}
+	if err := conn.QueryRow("PRAGMA mmap_size").Scan(&mmapSize); err == nil {
+		stats["mmap_size_bytes"] = mmapSize
+	}
+	if err := conn.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout); err == nil {
+		stats["busy_timeout_ms"] = busyTimeout
+	}
+
 	return stats, nil
 }
 
@@ -793,3 +871,86 @@ func (r *Repository) migrateAPIKeyEncryption() error {
 	logger.Infof("✓ API key encrypted successfully")
 	return nil
 }
+
+// ArrKeyEncryptionReport describes the state of the *arr instance API keys
+// after the startup encryption check.
+type ArrKeyEncryptionReport struct {
+	EncryptionEnabled bool      `json:"encryption_enabled"`
+	Instances         int       `json:"instances"`
+	Migrated          []string  `json:"migrated"`      // instances whose plain-text key was encrypted
+	Plaintext         []string  `json:"plaintext"`     // instances still storing a plain-text key
+	Undecryptable     []string  `json:"undecryptable"` // instances whose key can't be decrypted with the configured key
+	CheckedAt         time.Time `json:"checked_at"`
+}
+
+// migrateArrAPIKeyEncryption encrypts plain-text API keys in arr_instances, e.g.
+// from older versions, manual database edits or an encryption key set after
+// instances were added. It also reports keys that can't be decrypted, which
+// would otherwise only surface as *arr lookups silently skipping the instance.
+func (r *Repository) migrateArrAPIKeyEncryption() (*ArrKeyEncryptionReport, error) {
+	report := &ArrKeyEncryptionReport{
+		EncryptionEnabled: crypto.EncryptionEnabled(),
+		Migrated:          []string{},
+		Plaintext:         []string{},
+		Undecryptable:     []string{},
+		CheckedAt:         time.Now().UTC(),
+	}
+
+	rows, err := r.DB.Query("SELECT id, name, api_key FROM arr_instances ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query *arr instances: %w", err)
+	}
+	type instanceKey struct {
+		id     int64
+		name   string
+		apiKey string
+	}
+	var instances []instanceKey
+	for rows.Next() {
+		var inst instanceKey
+		if err := rows.Scan(&inst.id, &inst.name, &inst.apiKey); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan *arr instance: %w", err)
+		}
+		instances = append(instances, inst)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating *arr instances: %w", err)
+	}
+	report.Instances = len(instances)
+
+	for _, inst := range instances {
+		if crypto.IsEncrypted(inst.apiKey) {
+			if _, err := crypto.Decrypt(inst.apiKey); err != nil {
+				logger.Errorf("API key of *arr instance %q can't be decrypted (%v): check HEALARR_ENCRYPTION_KEY or re-enter the key", inst.name, err)
+				report.Undecryptable = append(report.Undecryptable, inst.name)
+			}
+			continue
+		}
+		if inst.apiKey == "" {
+			continue
+		}
+		if !report.EncryptionEnabled {
+			report.Plaintext = append(report.Plaintext, inst.name)
+			continue
+		}
+
+		encryptedKey, err := crypto.Encrypt(inst.apiKey)
+		if err != nil {
+			return report, fmt.Errorf("failed to encrypt API key of instance %d: %w", inst.id, err)
+		}
+		if _, err := ExecWithRetry(r.DB, "UPDATE arr_instances SET api_key = ? WHERE id = ?", encryptedKey, inst.id); err != nil {
+			return report, fmt.Errorf("failed to store encrypted API key of instance %d: %w", inst.id, err)
+		}
+		report.Migrated = append(report.Migrated, inst.name)
+	}
+
+	if len(report.Migrated) > 0 {
+		logger.Infof("✓ Encrypted plain-text API keys of %d *arr instance(s): %s", len(report.Migrated), strings.Join(report.Migrated, ", "))
+	}
+	if len(report.Plaintext) > 0 {
+		logger.Infof("*arr API key encryption: skipped for %d instance(s) (no encryption key configured)", len(report.Plaintext))
+	}
+	return report, nil
+}
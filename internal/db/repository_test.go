@@ -1,9 +1,11 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -155,11 +157,11 @@ func TestRepository_IndexesCreated(t *testing.T) {
 
 	expectedIndexes := []string{
 		"idx_aggregate",
-		"idx_event_type",
+		"idx_events_type_created_at",
 		"idx_created_at",
 		"idx_events_aggregate_event",
 		"idx_scans_status",
-		"idx_scan_files_scan_id",
+		"idx_scan_files_scan_status",
 		"idx_scan_files_status",
 	}
 
@@ -1213,6 +1215,113 @@ func TestRepository_MigrateAPIKeyEncryption_NoAPIKey(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// migrateArrAPIKeyEncryption Tests
+// =============================================================================
+
+// insertArrInstances stores *arr instances with the given raw API keys.
+func insertArrInstances(t *testing.T, repo *Repository, keys map[string]string) {
+	t.Helper()
+	for name, key := range keys {
+		if _, err := repo.DB.Exec("INSERT INTO arr_instances (name, type, url, api_key) VALUES (?, 'sonarr', 'http://sonarr:8989', ?)",
+			name, key); err != nil {
+			t.Fatalf("Failed to insert instance %s: %v", name, err)
+		}
+	}
+}
+
+func TestRepository_MigrateArrAPIKeyEncryption_NoEncryptionKey(t *testing.T) {
+	if crypto.EncryptionEnabled() {
+		t.Skip("HEALARR_ENCRYPTION_KEY is set")
+	}
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertArrInstances(t, repo, map[string]string{
+		"Sonarr":   "plain-key",
+		"Radarr":   crypto.EncryptedPrefix + "c29tZXRoaW5n",
+		"Whisparr": "",
+	})
+
+	report, err := repo.migrateArrAPIKeyEncryption()
+	if err != nil {
+		t.Fatalf("migrateArrAPIKeyEncryption failed: %v", err)
+	}
+	if report.EncryptionEnabled || report.Instances != 3 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if len(report.Migrated) != 0 {
+		t.Errorf("Expected nothing migrated without an encryption key, got %v", report.Migrated)
+	}
+	if len(report.Plaintext) != 1 || report.Plaintext[0] != "Sonarr" {
+		t.Errorf("Expected Sonarr reported as plain-text, got %v", report.Plaintext)
+	}
+	if len(report.Undecryptable) != 1 || report.Undecryptable[0] != "Radarr" {
+		t.Errorf("Expected Radarr reported as undecryptable, got %v", report.Undecryptable)
+	}
+
+	var stored string
+	if err := repo.DB.QueryRow("SELECT api_key FROM arr_instances WHERE name = 'Sonarr'").Scan(&stored); err != nil {
+		t.Fatalf("Failed to query key: %v", err)
+	}
+	if stored != "plain-key" {
+		t.Errorf("Expected key left unchanged, got %q", stored)
+	}
+}
+
+// TestRepository_MigrateArrAPIKeyEncryption_WithEncryptionKey re-runs itself with
+// an encryption key, since the key manager reads it once per process.
+func TestRepository_MigrateArrAPIKeyEncryption_WithEncryptionKey(t *testing.T) {
+	if !crypto.EncryptionEnabled() {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRepository_MigrateArrAPIKeyEncryption_WithEncryptionKey$")
+		cmd.Env = append(os.Environ(), "HEALARR_ENCRYPTION_KEY=arr-key-migration-test")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Subprocess failed: %v\nOutput: %s", err, output)
+		}
+		return
+	}
+
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	encrypted, err := crypto.Encrypt("already-encrypted")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	insertArrInstances(t, repo, map[string]string{
+		"Sonarr": "plain-key",
+		"Radarr": encrypted,
+	})
+
+	report, err := repo.migrateArrAPIKeyEncryption()
+	if err != nil {
+		t.Fatalf("migrateArrAPIKeyEncryption failed: %v", err)
+	}
+	if !report.EncryptionEnabled || len(report.Plaintext) != 0 || len(report.Undecryptable) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if len(report.Migrated) != 1 || report.Migrated[0] != "Sonarr" {
+		t.Errorf("Expected Sonarr migrated, got %v", report.Migrated)
+	}
+
+	var stored string
+	if err := repo.DB.QueryRow("SELECT api_key FROM arr_instances WHERE name = 'Sonarr'").Scan(&stored); err != nil {
+		t.Fatalf("Failed to query key: %v", err)
+	}
+	if !crypto.IsEncrypted(stored) {
+		t.Fatalf("Expected key encrypted in place, got %q", stored)
+	}
+	if decrypted, err := crypto.Decrypt(stored); err != nil || decrypted != "plain-key" {
+		t.Errorf("Expected key to decrypt to the original, got %q (%v)", decrypted, err)
+	}
+
+	// A second run has nothing left to do
+	report, err = repo.migrateArrAPIKeyEncryption()
+	if err != nil || len(report.Migrated) != 0 {
+		t.Errorf("Expected no migration on second run, got %v (%v)", report.Migrated, err)
+	}
+}
+
 // =============================================================================
 // checkIntegrity Error Tests
 // =============================================================================
@@ -1942,14 +2051,15 @@ func TestConfigureSQLite_AllPragmas(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	dbPath := filepath.Join(tmpDir, "test.db")
-	db, err := sql.Open("sqlite", dbPath)
+	opts := DefaultSQLiteOptions()
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath, opts.connectionPragmas(opts.BusyTimeout)...))
 	if err != nil {
 		t.Fatalf("Failed to open database: %v", err)
 	}
 	defer db.Close()
 
 	// Configure SQLite
-	err = configureSQLite(db)
+	err = configureSQLite(db, opts)
 	if err != nil {
 		t.Errorf("configureSQLite failed: %v", err)
 	}
@@ -2075,6 +2185,10 @@ func TestRepository_RecreateViews_WithCorruptionData(t *testing.T) {
 	if err != nil {
 		t.Fatalf("recreateViews failed: %v", err)
 	}
+	// The events were inserted outside the event bus
+	if _, err := RebuildCorruptionSummary(context.Background(), repo.DB); err != nil {
+		t.Fatalf("RebuildCorruptionSummary failed: %v", err)
+	}
 
 	// Query corruption_status view - should have entries
 	var count int
@@ -2879,7 +2993,7 @@ func TestConfigureSQLite_AfterClose(t *testing.T) {
 	db.Close()
 
 	// configureSQLite should return an error for critical pragma failures on a closed database
-	err = configureSQLite(db)
+	err = configureSQLite(db, DefaultSQLiteOptions())
 	if err == nil {
 		t.Error("configureSQLite should return error for closed database")
 	}
@@ -3168,6 +3282,10 @@ func TestRepository_RecreateViews_WithExistingData(t *testing.T) {
 	if err != nil {
 		t.Errorf("recreateViews failed: %v", err)
 	}
+	// The event was inserted outside the event bus
+	if _, err := RebuildCorruptionSummary(context.Background(), repo.DB); err != nil {
+		t.Fatalf("RebuildCorruptionSummary failed: %v", err)
+	}
 
 	// Query the view to ensure it works with real data
 	var activeCount int
@@ -3581,18 +3699,27 @@ func TestRepository_ExecuteMaintenanceCommand_Success(t *testing.T) {
 	repo, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// executeMaintenanceCommand logs success, we just verify it doesn't panic
-	repo.executeMaintenanceCommand("test operation", "SELECT 1", true)
-	repo.executeMaintenanceCommand("test operation with warn=false", "SELECT 1", false)
+	ctx := context.Background()
+	if err := repo.executeMaintenanceCommand(ctx, "test operation", "SELECT 1", true); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := repo.executeMaintenanceCommand(ctx, "test operation with warn=false", "SELECT 1", false); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 }
 
 func TestRepository_ExecuteMaintenanceCommand_Error(t *testing.T) {
 	repo, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// Invalid SQL should log error but not panic
-	repo.executeMaintenanceCommand("invalid operation", "INVALID SQL SYNTAX", true)
-	repo.executeMaintenanceCommand("invalid operation with warn=false", "INVALID SQL SYNTAX", false)
+	// Invalid SQL is only reported for commands that warn on error
+	ctx := context.Background()
+	if err := repo.executeMaintenanceCommand(ctx, "invalid operation", "INVALID SQL SYNTAX", true); err == nil {
+		t.Error("Expected an error for invalid SQL")
+	}
+	if err := repo.executeMaintenanceCommand(ctx, "invalid operation with warn=false", "INVALID SQL SYNTAX", false); err != nil {
+		t.Errorf("Expected optional command errors to be ignored, got %v", err)
+	}
 }
 
 func TestRepository_ExecutePruneOperation_Success(t *testing.T) {
@@ -4210,3 +4337,52 @@ func TestRepository_GetDatabaseStats_TableCounts(t *testing.T) {
 		t.Error("Missing table_counts in stats")
 	}
 }
+
+func TestRepository_OpenReadPool(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if repo.Reader() != repo.DB {
+		t.Error("Reader should fall back to the main pool before the read pool is opened")
+	}
+
+	if err := repo.OpenReadPool(2, time.Second); err != nil {
+		t.Fatalf("OpenReadPool failed: %v", err)
+	}
+	if repo.ReadDB == nil || repo.Reader() != repo.ReadDB {
+		t.Fatal("Reader should return the read pool once opened")
+	}
+
+	// Writes through the main pool are visible to readers
+	if _, err := repo.DB.Exec(`INSERT INTO settings (key, value) VALUES ('read_pool_test', 'ok')`); err != nil {
+		t.Fatalf("Failed to write setting: %v", err)
+	}
+	var value string
+	if err := repo.Reader().QueryRow(`SELECT value FROM settings WHERE key = 'read_pool_test'`).Scan(&value); err != nil || value != "ok" {
+		t.Errorf("Expected committed row from read pool, got %q (%v)", value, err)
+	}
+
+	// The read pool refuses writes
+	if _, err := repo.ReadDB.Exec(`INSERT INTO settings (key, value) VALUES ('read_pool_write', 'no')`); err == nil {
+		t.Error("Expected write through the read pool to fail")
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if repo.ReadDB != nil {
+		t.Error("Close should release the read pool")
+	}
+}
+
+func TestRepository_OpenReadPool_Disabled(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := repo.OpenReadPool(0, time.Second); err != nil {
+		t.Fatalf("OpenReadPool with no connections should be a no-op: %v", err)
+	}
+	if repo.ReadDB != nil {
+		t.Error("Read pool should stay closed when disabled")
+	}
+}
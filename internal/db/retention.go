@@ -0,0 +1,242 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Prune categories reported to the prune observer.
+const (
+	PruneResolved    = "resolved"
+	PruneFailed      = "failed"
+	PruneScanEvents  = "scan_events"
+	PruneOtherEvents = "other_events"
+	PruneScans       = "scans"
+	PruneScanSummary = "scan_summaries"
+	PruneDiagnostics = "diagnostics"
+	PruneScanFiles   = "scan_files"
+	PruneArrHealth   = "arr_health"
+)
+
+// pruneBatchSize bounds the number of corruptions deleted per statement.
+const pruneBatchSize = 500
+
+// closedCorruptionStates lists the final event types that put a corruption
+// under the resolved or failed retention instead of the default one.
+const closedCorruptionStates = `'VerificationSuccess', 'CorruptionIgnored', 'MaxRetriesReached'`
+
+// RetentionPolicy sets how many days each kind of history is kept; 0 keeps it
+// forever. Resolved and failed corruptions are pruned as a whole once their
+// last event is older than their retention, so they never lose their
+// detection event while their later events are kept.
+type RetentionPolicy struct {
+	// Days applies to all events and history not covered below.
+	Days int
+	// ResolvedDays applies to corruptions that were resolved or ignored.
+	ResolvedDays int
+	// FailedDays applies to corruptions that ran out of retries.
+	FailedDays int
+	// ScanEventDays applies to scan events such as ScanStarted and ScanCompleted.
+	ScanEventDays int
+	// ScanDays applies to scan records with their file lists and per-file results.
+	ScanDays int
+	// ScanSummaryDays applies to the compact summaries of finished scans,
+	// which outlive their scan records.
+	ScanSummaryDays int
+}
+
+// UniformRetention returns a policy that keeps all history for days. Scan
+// summaries are kept forever.
+func UniformRetention(days int) RetentionPolicy {
+	return RetentionPolicy{Days: days, ResolvedDays: days, FailedDays: days, ScanEventDays: days, ScanDays: days}
+}
+
+// SetPruneObserver registers a function that is told how many rows each
+// maintenance run pruned per category, e.g. to export metrics.
+func (r *Repository) SetPruneObserver(fn func(category string, count int64)) {
+	r.pruneMu.Lock()
+	defer r.pruneMu.Unlock()
+	r.pruneObserver = fn
+}
+
+// reportPruned passes the rows pruned in a category to the prune observer.
+func (r *Repository) reportPruned(category string, count int64) {
+	r.pruneMu.RLock()
+	fn := r.pruneObserver
+	r.pruneMu.RUnlock()
+	if fn != nil && count > 0 {
+		fn(category, count)
+	}
+}
+
+// retentionCutoff returns the timestamp before which data older than days is pruned.
+func retentionCutoff(days int) string {
+	return time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
+}
+
+// pruneHistory prunes events and scan history according to policy.
+func (r *Repository) pruneHistory(policy RetentionPolicy) {
+	corruptionOps := []struct {
+		category string
+		days     int
+		states   []string
+	}{
+		{PruneResolved, policy.ResolvedDays, []string{"VerificationSuccess", "CorruptionIgnored"}},
+		{PruneFailed, policy.FailedDays, []string{"MaxRetriesReached"}},
+	}
+	for _, op := range corruptionOps {
+		if op.days <= 0 {
+			continue
+		}
+		deleted, err := r.pruneCorruptions(op.days, op.states...)
+		if err != nil {
+			logger.Errorf("Failed to prune %s corruptions: %v", op.category, err)
+		}
+		if deleted > 0 {
+			logger.Infof("Pruned %d events of %s corruptions", deleted, op.category)
+			r.reportPruned(op.category, deleted)
+		}
+	}
+
+	var pruneOps []pruneOperation
+	if policy.ScanEventDays > 0 {
+		pruneOps = append(pruneOps, pruneOperation{
+			name:     "prune old scan events",
+			category: PruneScanEvents,
+			query:    "DELETE FROM events WHERE aggregate_type = 'scan' AND created_at < ?",
+			args:     []interface{}{retentionCutoff(policy.ScanEventDays)},
+			format:   "Pruned %d old scan events",
+		})
+	}
+	if policy.Days > 0 {
+		cutoff := retentionCutoff(policy.Days)
+		pruneOps = append(pruneOps,
+			pruneOperation{
+				name:     "prune old events",
+				category: PruneOtherEvents,
+				// Resolved and failed corruptions follow their own retention
+				query: `DELETE FROM events WHERE created_at < ? AND aggregate_type != 'scan'
+					AND aggregate_id NOT IN (
+						SELECT aggregate_id FROM (
+							SELECT aggregate_id, event_type, MAX(id)
+							FROM events WHERE aggregate_type = 'corruption'
+							GROUP BY aggregate_id
+						)
+						WHERE event_type IN (` + closedCorruptionStates + `)
+					)`,
+				args:   []interface{}{cutoff},
+				format: "Pruned %d old events",
+			},
+			pruneOperation{
+				name:     "prune old corruption diagnostics",
+				category: PruneDiagnostics,
+				query:    "DELETE FROM corruption_diagnostics WHERE created_at < datetime(?)",
+				args:     []interface{}{cutoff},
+				format:   "Pruned %d old corruption diagnostics",
+			},
+			pruneOperation{
+				name:     "prune old *arr health samples",
+				category: PruneArrHealth,
+				query:    "DELETE FROM arr_health_samples WHERE checked_at < datetime(?)",
+				args:     []interface{}{cutoff},
+				format:   "Pruned %d old *arr health samples",
+			},
+		)
+	}
+	if policy.ScanDays > 0 {
+		pruneOps = append(pruneOps,
+			pruneOperation{
+				name:     "prune old scans",
+				category: PruneScans,
+				query:    "DELETE FROM scans WHERE status IN ('completed', 'cancelled', 'error') AND completed_at < ?",
+				args:     []interface{}{retentionCutoff(policy.ScanDays)},
+				format:   "Pruned %d old scan records",
+			},
+			pruneOperation{
+				name:     "prune orphaned scan_files",
+				category: PruneScanFiles,
+				query:    "DELETE FROM scan_files WHERE scan_id NOT IN (SELECT id FROM scans)",
+				args:     nil,
+				format:   "Pruned %d orphaned scan_files records",
+			},
+		)
+	}
+	if policy.ScanSummaryDays > 0 {
+		pruneOps = append(pruneOps, pruneOperation{
+			name:     "prune old scan summaries",
+			category: PruneScanSummary,
+			query:    "DELETE FROM scan_summaries WHERE datetime(COALESCE(completed_at, started_at)) < datetime(?)",
+			args:     []interface{}{retentionCutoff(policy.ScanSummaryDays)},
+			format:   "Pruned %d old scan summaries",
+		})
+	}
+	for _, op := range pruneOps {
+		r.executePruneOperation(op)
+	}
+}
+
+// pruneCorruptions deletes the events, summaries, tags and quality pins of
+// corruptions whose last event is one of states and older than days, and
+// returns the number of events deleted.
+func (r *Repository) pruneCorruptions(days int, states ...string) (int64, error) {
+	args := make([]interface{}, 0, len(states)+1)
+	for _, state := range states {
+		args = append(args, state)
+	}
+	args = append(args, retentionCutoff(days))
+
+	rows, err := r.DB.Query(`
+		SELECT aggregate_id FROM (
+			SELECT aggregate_id, event_type, MAX(id), created_at
+			FROM events WHERE aggregate_type = 'corruption'
+			GROUP BY aggregate_id
+		)
+		WHERE event_type IN (`+placeholders(len(states))+`) AND datetime(created_at) < datetime(?)
+	`, args...) // NOSONAR - only placeholders are concatenated
+	if err != nil {
+		return 0, err
+	}
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for start := 0; start < len(ids); start += pruneBatchSize {
+		batch := ids[start:min(start+pruneBatchSize, len(ids))]
+		in := placeholders(len(batch))
+
+		result, err := ExecWithRetry(r.DB, `DELETE FROM events WHERE aggregate_id IN (`+in+`)`, batch...) // NOSONAR - only placeholders are concatenated
+		if err != nil {
+			return deleted, err
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+
+		// Rows derived from the events go with them; older databases may lack some tables
+		for _, table := range []string{"corruption_summary", "corruption_tags", "corruption_quality_pins", "corruption_deletion_plans", "corruption_links", "corruption_mapping_overrides"} {
+			query := fmt.Sprintf("DELETE FROM %s WHERE corruption_id IN (%s)", table, in)
+			if _, err := ExecWithRetry(r.DB, query, batch...); err != nil { // NOSONAR - table name from hardcoded slice
+				logger.Debugf("Failed to prune %s: %v", table, err)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// placeholders returns n comma-separated SQL placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
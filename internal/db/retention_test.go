@@ -0,0 +1,217 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepository_RunMaintenanceWithPolicy(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	daysAgo := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
+	}
+	events := []struct {
+		aggregateType, aggregateID, eventType string
+		age                                   int
+	}{
+		{"corruption", "resolved-old", "CorruptionDetected", 200},
+		{"corruption", "resolved-old", "VerificationSuccess", 100},
+		{"corruption", "resolved-recent", "CorruptionDetected", 200},
+		{"corruption", "resolved-recent", "VerificationSuccess", 10},
+		{"corruption", "failed-old", "CorruptionDetected", 120},
+		{"corruption", "failed-old", "MaxRetriesReached", 100},
+		{"corruption", "open-old", "CorruptionDetected", 100},
+		{"scan", "scan-old", "ScanStarted", 20},
+		{"scan", "scan-recent", "ScanStarted", 5},
+	}
+	for _, e := range events {
+		if _, err := repo.DB.Exec(`
+			INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at)
+			VALUES (?, ?, ?, '{}', ?)
+		`, e.aggregateType, e.aggregateID, e.eventType, daysAgo(e.age)); err != nil {
+			t.Fatalf("Failed to insert event: %v", err)
+		}
+	}
+	if _, err := repo.DB.Exec(`INSERT INTO corruption_tags (corruption_id, tag) VALUES ('resolved-old', 'disk')`); err != nil {
+		t.Fatalf("Failed to insert tag: %v", err)
+	}
+
+	if _, err := repo.DB.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'key');
+		INSERT INTO arr_health_samples (instance_id, checked_at, reachable, response_ms) VALUES
+			(1, datetime('now', '-40 days'), 1, 30),
+			(1, datetime('now', '-5 days'), 0, 0);
+	`); err != nil {
+		t.Fatalf("Failed to insert health samples: %v", err)
+	}
+
+	pruned := make(map[string]int64)
+	repo.SetPruneObserver(func(category string, count int64) {
+		pruned[category] += count
+	})
+
+	err := repo.RunMaintenanceWithPolicy(RetentionPolicy{
+		Days:          30,
+		ResolvedDays:  90,
+		FailedDays:    365,
+		ScanEventDays: 14,
+	})
+	if err != nil {
+		t.Fatalf("RunMaintenanceWithPolicy failed: %v", err)
+	}
+
+	count := func(query string, args ...interface{}) int {
+		t.Helper()
+		var n int
+		if err := repo.DB.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("Failed to count: %v", err)
+		}
+		return n
+	}
+	wantEvents := map[string]int{
+		"resolved-old":    0,
+		"resolved-recent": 2, // the detection outlives the default retention
+		"failed-old":      2,
+		"open-old":        0,
+		"scan-old":        0,
+		"scan-recent":     1,
+	}
+	for id, want := range wantEvents {
+		if got := count(`SELECT COUNT(*) FROM events WHERE aggregate_id = ?`, id); got != want {
+			t.Errorf("Expected %d events of %s, got %d", want, id, got)
+		}
+	}
+	if got := count(`SELECT COUNT(*) FROM corruption_summary WHERE corruption_id = 'resolved-old'`); got != 0 {
+		t.Errorf("Expected the summary of the pruned corruption to be removed, got %d", got)
+	}
+	if got := count(`SELECT COUNT(*) FROM corruption_tags WHERE corruption_id = 'resolved-old'`); got != 0 {
+		t.Errorf("Expected the tags of the pruned corruption to be removed, got %d", got)
+	}
+
+	wantPruned := map[string]int64{PruneResolved: 2, PruneOtherEvents: 1, PruneScanEvents: 1, PruneArrHealth: 1}
+	for category, want := range wantPruned {
+		if pruned[category] != want {
+			t.Errorf("Expected %d rows pruned in %s, got %d", want, category, pruned[category])
+		}
+	}
+	if _, ok := pruned[PruneFailed]; ok {
+		t.Errorf("Expected no failed corruptions pruned, got %d", pruned[PruneFailed])
+	}
+}
+
+func TestRepository_RunMaintenanceWithPolicy_KeepForever(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	old := time.Now().AddDate(0, 0, -400).Format(time.RFC3339)
+	for _, eventType := range []string{"CorruptionDetected", "MaxRetriesReached"} {
+		if _, err := repo.DB.Exec(`
+			INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at)
+			VALUES ('corruption', 'failed', ?, '{}', ?)
+		`, eventType, old); err != nil {
+			t.Fatalf("Failed to insert event: %v", err)
+		}
+	}
+
+	// Failed corruptions are kept forever while everything else expires
+	policy := UniformRetention(30)
+	policy.FailedDays = 0
+	if err := repo.RunMaintenanceWithPolicy(policy); err != nil {
+		t.Fatalf("RunMaintenanceWithPolicy failed: %v", err)
+	}
+
+	var n int
+	if err := repo.DB.QueryRow(`SELECT COUNT(*) FROM events WHERE aggregate_id = 'failed'`).Scan(&n); err != nil {
+		t.Fatalf("Failed to count events: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected the failed corruption to be kept, got %d events", n)
+	}
+}
+
+func TestRepository_RunMaintenanceWithPolicy_ScanSummaries(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if _, err := repo.DB.Exec(`
+		INSERT INTO scans (id, path, status, files_scanned, total_files, started_at, completed_at) VALUES
+			(1, '/tv', 'completed', 10, 10, datetime('now', '-400 days'), datetime('now', '-400 days')),
+			(2, '/tv', 'completed', 12, 12, datetime('now', '-40 days'), datetime('now', '-40 days')),
+			(3, '/tv', 'running', 3, 12, datetime('now'), NULL);
+		INSERT INTO scan_files (scan_id, file_path, status) VALUES (1, '/tv/a.mkv', 'healthy'), (2, '/tv/a.mkv', 'healthy');
+		UPDATE scans SET corruptions_found = 2 WHERE id = 2;
+	`); err != nil {
+		t.Fatalf("Failed to insert scans: %v", err)
+	}
+
+	// Finished scans are summarized as they finish, and follow later updates
+	var summaries, corruptions int
+	if err := repo.DB.QueryRow(`SELECT COUNT(*), COALESCE(SUM(corruptions_found), 0) FROM scan_summaries`).Scan(&summaries, &corruptions); err != nil {
+		t.Fatalf("Failed to count summaries: %v", err)
+	}
+	if summaries != 2 || corruptions != 2 {
+		t.Fatalf("Expected 2 summaries with 2 corruptions, got %d with %d", summaries, corruptions)
+	}
+
+	// Scan records expire after 30 days while their summaries are kept forever
+	policy := UniformRetention(90)
+	policy.ScanDays = 30
+	if err := repo.RunMaintenanceWithPolicy(policy); err != nil {
+		t.Fatalf("RunMaintenanceWithPolicy failed: %v", err)
+	}
+	count := func(query string) int {
+		t.Helper()
+		var n int
+		if err := repo.DB.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("Failed to count: %v", err)
+		}
+		return n
+	}
+	if got := count(`SELECT COUNT(*) FROM scans`); got != 1 {
+		t.Errorf("Expected only the running scan to be kept, got %d scans", got)
+	}
+	if got := count(`SELECT COUNT(*) FROM scan_files`); got != 0 {
+		t.Errorf("Expected the file results of pruned scans to be removed, got %d", got)
+	}
+	if got := count(`SELECT COUNT(*) FROM scan_summaries`); got != 2 {
+		t.Errorf("Expected the summaries to outlive their scans, got %d", got)
+	}
+
+	policy.ScanSummaryDays = 365
+	if err := repo.RunMaintenanceWithPolicy(policy); err != nil {
+		t.Fatalf("RunMaintenanceWithPolicy failed: %v", err)
+	}
+	if got := count(`SELECT COUNT(*) FROM scan_summaries WHERE scan_id = 2`); got != 1 || count(`SELECT COUNT(*) FROM scan_summaries`) != 1 {
+		t.Errorf("Expected only the summary older than a year to be pruned")
+	}
+}
+
+func TestRepository_ScanSummaryBackfill(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Scans recorded before migration 041 get a summary when it runs
+	if _, err := repo.DB.Exec(`
+		INSERT INTO scans (id, path, path_id, status, files_scanned, total_files, corruptions_found, started_at, completed_at) VALUES
+			(1, '/tv', NULL, 'completed', 10, 10, 1, datetime('now', '-2 days'), datetime('now', '-2 days')),
+			(2, '/tv', NULL, 'interrupted', 4, 10, 0, datetime('now', '-1 days'), NULL);
+		DELETE FROM scan_summaries;
+		DELETE FROM schema_migrations WHERE version = 41;
+	`); err != nil {
+		t.Fatalf("Failed to prepare database: %v", err)
+	}
+	if err := repo.applyMigration("041_scan_summaries.sql", 41); err != nil {
+		t.Fatalf("Failed to apply migration: %v", err)
+	}
+
+	var scanID, filesScanned, corruptions int
+	var status string
+	if err := repo.DB.QueryRow(`SELECT scan_id, status, files_scanned, corruptions_found FROM scan_summaries`).Scan(&scanID, &status, &filesScanned, &corruptions); err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	if scanID != 1 || status != "completed" || filesScanned != 10 || corruptions != 1 {
+		t.Errorf("Unexpected summary: scan %d, %s, %d files, %d corruptions", scanID, status, filesScanned, corruptions)
+	}
+}
@@ -17,14 +17,30 @@ const retryQueryTimeout = 15 * time.Second
 // This function works with any *sql.DB and is useful for high-concurrency scenarios
 // where multiple goroutines may be writing to the database simultaneously.
 func ExecWithRetry(db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	return execWithRetry("exec", func(ctx context.Context) (sql.Result, error) {
+		return db.ExecContext(ctx, query, args...)
+	})
+}
+
+// ExecStmtWithRetry is ExecWithRetry for a prepared statement (see StmtCache).
+// operation names the statement in the latency metrics.
+func ExecStmtWithRetry(stmt *sql.Stmt, operation string, args ...interface{}) (sql.Result, error) {
+	return execWithRetry(operation, func(ctx context.Context) (sql.Result, error) {
+		return stmt.ExecContext(ctx, args...)
+	})
+}
+
+func execWithRetry(operation string, exec func(ctx context.Context) (sql.Result, error)) (sql.Result, error) {
 	var result sql.Result
 	var err error
+	start := time.Now()
 
 	for attempt := 0; attempt < MaxRetries; attempt++ {
 		ctx, cancel := context.WithTimeout(context.Background(), retryQueryTimeout)
-		result, err = db.ExecContext(ctx, query, args...)
+		result, err = exec(ctx)
 		cancel()
 		if err == nil {
+			observeLatency(operation, start, attempt)
 			return result, nil
 		}
 
@@ -32,6 +48,7 @@ func ExecWithRetry(db *sql.DB, query string, args ...interface{}) (sql.Result, e
 		errStr := err.Error()
 		if !strings.Contains(errStr, "SQLITE_BUSY") && !strings.Contains(errStr, "database is locked") && !strings.Contains(errStr, "context deadline exceeded") {
 			// Not a busy/timeout error, don't retry
+			observeLatency(operation, start, attempt)
 			return nil, err
 		}
 
@@ -43,9 +60,63 @@ func ExecWithRetry(db *sql.DB, query string, args ...interface{}) (sql.Result, e
 		}
 	}
 
+	observeLatency(operation, start, MaxRetries-1)
 	return nil, fmt.Errorf("database busy after %d retries: %w", MaxRetries, err)
 }
 
+// TxWithRetry runs fn in a transaction and commits it, retrying the whole
+// transaction on SQLITE_BUSY errors like ExecWithRetry. fn may run more than
+// once and must not have side effects outside the transaction.
+func TxWithRetry(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	return NamedTxWithRetry(db, "transaction", fn)
+}
+
+// NamedTxWithRetry is TxWithRetry for a hot transaction that gets its own
+// operation name in the latency metrics.
+func NamedTxWithRetry(db *sql.DB, operation string, fn func(tx *sql.Tx) error) error {
+	var err error
+	start := time.Now()
+
+	for attempt := 0; attempt < MaxRetries; attempt++ {
+		err = runTx(db, fn)
+		if err == nil {
+			observeLatency(operation, start, attempt)
+			return nil
+		}
+
+		errStr := err.Error()
+		if !strings.Contains(errStr, "SQLITE_BUSY") && !strings.Contains(errStr, "database is locked") && !strings.Contains(errStr, "context deadline exceeded") {
+			observeLatency(operation, start, attempt)
+			return err
+		}
+
+		delay := RetryDelay * time.Duration(1<<attempt)
+		if attempt < MaxRetries-1 {
+			logger.Debugf("Database busy, retrying transaction in %v (attempt %d/%d)", delay, attempt+1, MaxRetries)
+			time.Sleep(delay)
+		}
+	}
+
+	observeLatency(operation, start, MaxRetries-1)
+	return fmt.Errorf("database busy after %d retries: %w", MaxRetries, err)
+}
+
+// runTx runs fn in a transaction with the per-attempt timeout of TxWithRetry.
+func runTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), retryQueryTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // QueryWithRetry executes a query with retry logic for SQLITE_BUSY errors.
 // Note: Unlike ExecWithRetry, this function does not use a context timeout because
 // the returned *sql.Rows continues to use the context for iteration (Next/Scan).
@@ -53,16 +124,19 @@ func ExecWithRetry(db *sql.DB, query string, args ...interface{}) (sql.Result, e
 func QueryWithRetry(db *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
 	var rows *sql.Rows
 	var err error
+	start := time.Now()
 
 	for attempt := 0; attempt < MaxRetries; attempt++ {
 		rows, err = db.Query(query, args...)
 		if err == nil {
+			observeLatency("query", start, attempt)
 			return rows, nil
 		}
 
 		// Check if error is SQLITE_BUSY (database is locked)
 		errStr := err.Error()
 		if !strings.Contains(errStr, "SQLITE_BUSY") && !strings.Contains(errStr, "database is locked") {
+			observeLatency("query", start, attempt)
 			return nil, err
 		}
 
@@ -73,5 +147,6 @@ func QueryWithRetry(db *sql.DB, query string, args ...interface{}) (*sql.Rows, e
 		}
 	}
 
+	observeLatency("query", start, MaxRetries-1)
 	return nil, fmt.Errorf("database busy after %d retries: %w", MaxRetries, err)
 }
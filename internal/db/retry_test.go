@@ -54,7 +54,8 @@ func newTestDBForRetry() (*sql.DB, error) {
 			detection_args TEXT,
 			detection_mode TEXT NOT NULL DEFAULT 'quick',
 			max_retries INTEGER DEFAULT 3,
-			verification_timeout_hours INTEGER DEFAULT NULL
+			verification_timeout_hours INTEGER DEFAULT NULL,
+			deleted_at TIMESTAMP DEFAULT NULL
 		)
 	`)
 	if err != nil {
@@ -13,6 +13,7 @@ const (
 	DeletionStarted      EventType = "DeletionStarted"
 	DeletionCompleted    EventType = "DeletionCompleted"
 	DeletionFailed       EventType = "DeletionFailed"
+	DeletionPlanChanged  EventType = "DeletionPlanChanged" // *arr files changed between planning and deleting - requires manual intervention
 	SearchStarted        EventType = "SearchStarted"
 	SearchCompleted      EventType = "SearchCompleted"
 	SearchFailed         EventType = "SearchFailed"
@@ -20,19 +21,24 @@ const (
 	VerificationStarted  EventType = "VerificationStarted"
 	VerificationSuccess  EventType = "VerificationSuccess"
 	VerificationFailed   EventType = "VerificationFailed"
+	QualityRegression    EventType = "QualityRegression" // Healthy replacement below the pinned quality of the original
+	AudioTrackMissing    EventType = "AudioTrackMissing" // Healthy replacement lacks audio languages or channels of the original
 	DownloadTimeout      EventType = "DownloadTimeout"
 	DownloadProgress     EventType = "DownloadProgress"
 	DownloadFailed       EventType = "DownloadFailed"
-	ImportBlocked        EventType = "ImportBlocked"   // *arr import blocked - requires manual intervention
-	ManuallyRemoved      EventType = "ManuallyRemoved" // Item manually removed from *arr queue
-	DownloadIgnored      EventType = "DownloadIgnored" // *arr marked download as ignored by user
+	DownloadRejected     EventType = "DownloadRejected" // Completed download failed the pre-import check and was blocklisted
+	ImportBlocked        EventType = "ImportBlocked"    // *arr import blocked - requires manual intervention
+	ManuallyRemoved      EventType = "ManuallyRemoved"  // Item manually removed from *arr queue
+	DownloadIgnored      EventType = "DownloadIgnored"  // *arr marked download as ignored by user
 	RetryScheduled       EventType = "RetryScheduled"
 	MaxRetriesReached    EventType = "MaxRetriesReached"
-	SearchExhausted      EventType = "SearchExhausted" // No replacement found - arr search returned 0 results or item vanished
+	SearchExhausted      EventType = "SearchExhausted"   // No replacement found - arr search returned 0 results or item vanished
+	ScheduledResearch    EventType = "ScheduledResearch" // No replacement found yet - the path's re-search schedule searches again later
 	ScanStarted          EventType = "ScanStarted"
 	ScanCompleted        EventType = "ScanCompleted"
 	ScanFailed           EventType = "ScanFailed"
 	ScanProgress         EventType = "ScanProgress"
+	ScanSkipped          EventType = "ScanSkipped" // Path can't be scanned, e.g. its detection tools are missing
 	NotificationSent     EventType = "NotificationSent"
 	NotificationFailed   EventType = "NotificationFailed"
 	CorruptionIgnored    EventType = "CorruptionIgnored"
@@ -42,8 +48,80 @@ const (
 	StuckRemediation  EventType = "StuckRemediation"
 	InstanceUnhealthy EventType = "InstanceUnhealthy"
 	InstanceHealthy   EventType = "InstanceHealthy"
+	SLABreached       EventType = "SLABreached" // Corruption unresolved past the configured resolution SLA
+
+	// Scan path *arr path outside the instance's root folders, e.g. after a library move in the *arr
+	MappingDrift EventType = "MappingDrift"
+
+	// Scan paths created, updated, deleted or re-bound; holders of path mappings reload them
+	ScanPathsChanged EventType = "ScanPathsChanged"
+
+	// Daily *arr API request budgets
+	ArrRequestBudgetWarning EventType = "ArrRequestBudgetWarning" // Instance near or at its daily budget; non-urgent requests wait for the next day
+
+	// Detection-only mode during *arr instance outages
+	RemediationPaused  EventType = "RemediationPaused"  // Instance down too long; its paths only detect
+	RemediationResumed EventType = "RemediationResumed" // Instance recovered; queued remediations continue
+
+	// Monthly remediation data budget
+	RemediationBudgetExceeded EventType = "RemediationBudgetExceeded" // Budget used up; remediations are queued
+	RemediationBudgetRestored EventType = "RemediationBudgetRestored" // Budget available again; queued remediations continue
+	RemediationDeferred       EventType = "RemediationDeferred"       // Over the data budget, a new path's daily limit or a paused path group
+	BudgetApprovalRequired    EventType = "BudgetApprovalRequired"    // Replacement would exceed the budget; needs user approval
+
+	// Global pause of all automation, e.g. during maintenance of the *arr stack
+	AutomationPaused  EventType = "AutomationPaused"  // Remediation, verification polling and scheduled scans are on hold
+	AutomationResumed EventType = "AutomationResumed" // Automation runs again, by hand or when the pause timer expired
+
+	// Resolved corruptions checked again on request
+	ReverificationRequested EventType = "ReverificationRequested" // Replacement of a resolved corruption is verified again
+
+	// Detection confidence below the scan path's minimum
+	LowConfidenceDetection EventType = "LowConfidenceDetection" // Not remediated until the user confirms the finding
+
+	// Scheduled reports
+	ReportGenerated EventType = "ReportGenerated" // Weekly summary report compiled
+
+	// Verification deadlines extended by the delay profile of the *arr instance
+	VerificationDeadlineExtended EventType = "VerificationDeadlineExtended"
+
+	// Library migrated to another *arr instance
+	MediaReresolved EventType = "MediaReresolved" // Media ID of an open corruption looked up again on the new instance
 )
 
+// AllEventTypes returns every domain event type, in declaration order.
+func AllEventTypes() []EventType {
+	return []EventType{
+		CorruptionDetected, RemediationQueued, DeletionStarted, DeletionCompleted, DeletionFailed, DeletionPlanChanged,
+		SearchStarted, SearchCompleted, SearchFailed, FileDetected,
+		VerificationStarted, VerificationSuccess, VerificationFailed, QualityRegression, AudioTrackMissing,
+		DownloadTimeout, DownloadProgress, DownloadFailed, DownloadRejected, ImportBlocked, ManuallyRemoved, DownloadIgnored,
+		RetryScheduled, MaxRetriesReached, SearchExhausted, ScheduledResearch,
+		ScanStarted, ScanCompleted, ScanFailed, ScanProgress, ScanSkipped,
+		NotificationSent, NotificationFailed, CorruptionIgnored, SystemHealthDegraded,
+		StuckRemediation, InstanceUnhealthy, InstanceHealthy, SLABreached,
+		MappingDrift, ScanPathsChanged, ArrRequestBudgetWarning,
+		RemediationPaused, RemediationResumed,
+		RemediationBudgetExceeded, RemediationBudgetRestored, RemediationDeferred, BudgetApprovalRequired,
+		AutomationPaused, AutomationResumed,
+		ReverificationRequested,
+		LowConfidenceDetection,
+		ReportGenerated,
+		VerificationDeadlineExtended,
+		MediaReresolved,
+	}
+}
+
+// IsValidEventType reports whether t is a known domain event type.
+func IsValidEventType(t EventType) bool {
+	for _, known := range AllEventTypes() {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
 // Event represents a domain event in the event-sourced architecture.
 // Events are immutable records of state changes, stored in the events table.
 type Event struct {
@@ -186,6 +264,9 @@ type CorruptionEventData struct {
 	AutoRemediate  bool   `json:"auto_remediate"`
 	DryRun         bool   `json:"dry_run"`
 	BatchThrottled bool   `json:"batch_throttled,omitempty"`
+	Confidence     int    `json:"confidence,omitempty"`     // 0-100; 0 for events from before confidence scoring
+	MinConfidence  int    `json:"min_confidence,omitempty"` // Minimum of the scan path for automatic remediation
+	LowConfidence  bool   `json:"low_confidence,omitempty"` // Confidence below MinConfidence; waits for the user
 }
 
 // ParseCorruptionEventData extracts typed corruption data from an event.
@@ -204,6 +285,9 @@ func (e *Event) ParseCorruptionEventData() (CorruptionEventData, bool) {
 		AutoRemediate:  e.GetBoolOr("auto_remediate", false),
 		DryRun:         e.GetBoolOr("dry_run", false),
 		BatchThrottled: e.GetBoolOr("batch_throttled", false),
+		Confidence:     int(e.GetInt64Or("confidence", 0)),
+		MinConfidence:  int(e.GetInt64Or("min_confidence", 0)),
+		LowConfidence:  e.GetBoolOr("low_confidence", false),
 	}, true
 }
 
@@ -671,3 +671,22 @@ func TestEvent_ParseRetryEventData_MissingFilePath(t *testing.T) {
 		t.Error("ParseRetryEventData() should return false when file_path is missing")
 	}
 }
+
+// TestAllEventTypes tests that every event type is listed exactly once.
+func TestAllEventTypes(t *testing.T) {
+	seen := make(map[EventType]bool)
+	for _, et := range AllEventTypes() {
+		if seen[et] {
+			t.Errorf("duplicate event type %s", et)
+		}
+		seen[et] = true
+	}
+	for _, et := range []EventType{CorruptionDetected, ScanProgress, InstanceHealthy} {
+		if !IsValidEventType(et) {
+			t.Errorf("IsValidEventType(%s) = false, want true", et)
+		}
+	}
+	if IsValidEventType("NotAnEvent") {
+		t.Error("IsValidEventType(NotAnEvent) = true, want false")
+	}
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidTransition is returned when a corruption event does not follow
+// from the state the corruption is in, e.g. VerificationSuccess after SearchExhausted.
+var ErrInvalidTransition = errors.New("invalid lifecycle transition")
+
+// TransitionError describes an event that doesn't follow from the current state.
+type TransitionError struct {
+	From EventType
+	To   EventType
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s cannot follow %s", ErrInvalidTransition, e.To, e.From)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// CorruptionAggregate is the aggregate type of the corruption lifecycle.
+const CorruptionAggregate = "corruption"
+
+// Groups of states used in the transition table below.
+var (
+	// downloadingStates are the states while the *arr grabs and downloads a replacement.
+	downloadingStates = []EventType{SearchCompleted, DownloadProgress, DownloadRejected, ImportBlocked}
+
+	// unresolvedStates are all states a remediation can get stuck in.
+	unresolvedStates = []EventType{
+		CorruptionDetected, RemediationQueued, RemediationDeferred, BudgetApprovalRequired, LowConfidenceDetection,
+		DeletionStarted, DeletionCompleted, DeletionFailed, DeletionPlanChanged,
+		SearchStarted, SearchCompleted, SearchFailed, SearchExhausted,
+		DownloadProgress, DownloadRejected, DownloadTimeout, DownloadFailed, ImportBlocked, ManuallyRemoved, DownloadIgnored,
+		FileDetected, ReverificationRequested, VerificationStarted, VerificationFailed, QualityRegression, AudioTrackMissing,
+		ScheduledResearch, RetryScheduled, StuckRemediation, CorruptionIgnored,
+	}
+
+	// failedStates are the failures the monitor retries until the retry limit.
+	failedStates = []EventType{DeletionFailed, SearchFailed, VerificationFailed, DownloadTimeout, DownloadFailed, StuckRemediation}
+)
+
+// lifecycleTransitions maps each state of a corruption to the states it may
+// follow. CorruptionDetected starts the lifecycle and follows no state.
+var lifecycleTransitions = map[EventType][]EventType{
+	CorruptionDetected:      {},
+	RemediationQueued:       {CorruptionDetected, RetryScheduled, RemediationQueued},
+	RemediationDeferred:     {RemediationQueued},
+	BudgetApprovalRequired:  {RemediationQueued},
+	LowConfidenceDetection:  {RemediationQueued},
+	DeletionPlanChanged:     {RemediationQueued, RemediationDeferred},
+	DeletionStarted:         {RemediationQueued, RemediationDeferred},
+	DeletionFailed:          {CorruptionDetected, RetryScheduled, RemediationQueued, RemediationDeferred, DeletionStarted},
+	DeletionCompleted:       {DeletionStarted},
+	SearchStarted:           {DeletionCompleted, RetryScheduled},
+	SearchFailed:            {SearchStarted, DeletionCompleted, RetryScheduled},
+	SearchCompleted:         {SearchStarted},
+	DownloadProgress:        downloadingStates,
+	DownloadRejected:        downloadingStates,
+	ImportBlocked:           downloadingStates,
+	DownloadTimeout:         append([]EventType{FileDetected}, downloadingStates...),
+	DownloadFailed:          downloadingStates,
+	ManuallyRemoved:         downloadingStates,
+	DownloadIgnored:         downloadingStates,
+	FileDetected:            downloadingStates,
+	VerificationStarted:     {FileDetected, ReverificationRequested},
+	QualityRegression:       {VerificationStarted},
+	AudioTrackMissing:       {VerificationStarted},
+	VerificationSuccess:     {VerificationStarted},
+	ReverificationRequested: {VerificationSuccess},
+	VerificationFailed:      {VerificationStarted, QualityRegression},
+	MaxRetriesReached:       failedStates,
+	StuckRemediation:        unresolvedStates,
+	SearchExhausted:         unresolvedStates,
+	ScheduledResearch:       {SearchExhausted},
+}
+
+// anyStateEvents may follow every state: the user or the monitor can retry or
+// ignore a corruption at any time.
+var anyStateEvents = map[EventType]bool{
+	RetryScheduled:    true,
+	CorruptionIgnored: true,
+}
+
+// terminalStates end a corruption's lifecycle unless it is retried.
+var terminalStates = []EventType{VerificationSuccess, MaxRetriesReached, SearchExhausted, CorruptionIgnored}
+
+// overrideKeys mark events that reconcile a corruption with reality, e.g. the
+// *arr sync finding the replacement, or act on the user's request. They may
+// follow any state.
+var overrideKeys = []string{"recovery_action", "manual_grab", "manual_retry", "budget_approved"}
+
+// IsLifecycleEvent reports whether t changes the state of a corruption.
+// Notifications and health events on a corruption don't.
+func IsLifecycleEvent(t EventType) bool {
+	_, ok := lifecycleTransitions[t]
+	return ok || anyStateEvents[t]
+}
+
+// LifecycleStates returns the event types that change the state of a
+// corruption, sorted by name.
+func LifecycleStates() []EventType {
+	states := make([]EventType, 0, len(lifecycleTransitions)+len(anyStateEvents))
+	for t := range lifecycleTransitions {
+		states = append(states, t)
+	}
+	for t := range anyStateEvents {
+		if _, ok := lifecycleTransitions[t]; !ok {
+			states = append(states, t)
+		}
+	}
+	sortEventTypes(states)
+	return states
+}
+
+// ValidateTransition checks that event may follow from, the latest lifecycle
+// event of its corruption. from is empty when the corruption has no history,
+// e.g. because it was deleted while a remediation was still running; such
+// events aren't checked, except that CorruptionDetected must come first.
+func ValidateTransition(from EventType, event Event) error {
+	to := event.EventType
+	if event.AggregateType != CorruptionAggregate || !IsLifecycleEvent(to) {
+		return nil
+	}
+	if to == CorruptionDetected {
+		if from != "" {
+			return &TransitionError{From: from, To: to}
+		}
+		return nil
+	}
+	if from == "" || !IsLifecycleEvent(from) || anyStateEvents[to] {
+		return nil
+	}
+	for _, key := range overrideKeys {
+		if _, ok := event.EventData[key]; ok {
+			return nil
+		}
+	}
+	for _, allowed := range lifecycleTransitions[to] {
+		if allowed == from {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to}
+}
+
+// Transition is an allowed step in the corruption lifecycle.
+type Transition struct {
+	From EventType `json:"from"`
+	To   EventType `json:"to"`
+}
+
+// LifecycleTransitions returns the allowed transitions, sorted by source and
+// target. Events that may follow any state are listed by AnyStateEvents.
+func LifecycleTransitions() []Transition {
+	var transitions []Transition
+	for to, froms := range lifecycleTransitions {
+		for _, from := range froms {
+			transitions = append(transitions, Transition{From: from, To: to})
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if transitions[i].From != transitions[j].From {
+			return transitions[i].From < transitions[j].From
+		}
+		return transitions[i].To < transitions[j].To
+	})
+	return transitions
+}
+
+// AnyStateEvents returns the events that may follow every state, sorted by name.
+func AnyStateEvents() []EventType {
+	events := make([]EventType, 0, len(anyStateEvents))
+	for t := range anyStateEvents {
+		events = append(events, t)
+	}
+	sortEventTypes(events)
+	return events
+}
+
+// TerminalStates returns the states that end a corruption's lifecycle.
+func TerminalStates() []EventType {
+	return append([]EventType(nil), terminalStates...)
+}
+
+// TransitionOverrideKeys returns the event_data keys that let an event follow
+// any state.
+func TransitionOverrideKeys() []string {
+	return append([]string(nil), overrideKeys...)
+}
+
+// LifecycleDiagram renders the corruption lifecycle as a Mermaid state diagram.
+func LifecycleDiagram() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", CorruptionDetected)
+	for _, t := range LifecycleTransitions() {
+		fmt.Fprintf(&b, "    %s --> %s\n", t.From, t.To)
+	}
+	b.WriteString("    state \"Any state\" as AnyState\n")
+	for _, t := range AnyStateEvents() {
+		fmt.Fprintf(&b, "    AnyState --> %s\n", t)
+	}
+	for _, t := range terminalStates {
+		fmt.Fprintf(&b, "    %s --> [*]\n", t)
+	}
+	return b.String()
+}
+
+func sortEventTypes(types []EventType) {
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+}
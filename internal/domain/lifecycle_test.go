@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	corruption := func(eventType EventType, data map[string]interface{}) Event {
+		return Event{AggregateType: CorruptionAggregate, AggregateID: "c1", EventType: eventType, EventData: data}
+	}
+
+	tests := []struct {
+		name    string
+		from    EventType
+		event   Event
+		wantErr bool
+	}{
+		{"detection starts the lifecycle", "", corruption(CorruptionDetected, nil), false},
+		{"second detection", RemediationQueued, corruption(CorruptionDetected, nil), true},
+		{"queued after detection", CorruptionDetected, corruption(RemediationQueued, nil), false},
+		{"deletion before queueing", CorruptionDetected, corruption(DeletionStarted, nil), true},
+		{"search after deletion", DeletionCompleted, corruption(SearchStarted, nil), false},
+		{"download progress while downloading", DownloadProgress, corruption(DownloadProgress, nil), false},
+		{"import blocked then imported", ImportBlocked, corruption(FileDetected, nil), false},
+		{"success after verification", VerificationStarted, corruption(VerificationSuccess, nil), false},
+		{"success after exhausted search", SearchExhausted, corruption(VerificationSuccess, nil), true},
+		{"success without verification", SearchCompleted, corruption(VerificationSuccess, nil), true},
+		{"quality regression fails the verification", QualityRegression, corruption(VerificationFailed, nil), false},
+		{"retry from a terminal state", MaxRetriesReached, corruption(RetryScheduled, nil), false},
+		{"ignore from any state", DownloadProgress, corruption(CorruptionIgnored, nil), false},
+		{"give up after a failure", SearchFailed, corruption(MaxRetriesReached, nil), false},
+		{"give up while downloading", DownloadProgress, corruption(MaxRetriesReached, nil), true},
+		{"arr sync resolves an exhausted search", SearchExhausted,
+			corruption(VerificationSuccess, map[string]interface{}{"recovery_action": "arr_sync"}), false},
+		{"manual grab", MaxRetriesReached, corruption(SearchCompleted, map[string]interface{}{"manual_grab": true}), false},
+		{"unknown history", "", corruption(VerificationSuccess, nil), false},
+		{"notifications don't change the state", SearchExhausted, corruption(NotificationSent, nil), false},
+		{"other aggregates aren't checked", SearchExhausted,
+			Event{AggregateType: "scan", EventType: VerificationSuccess}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTransition(%s, %s) = %v, wantErr %v", tt.from, tt.event.EventType, err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("Expected ErrInvalidTransition, got %v", err)
+			}
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) || transitionErr.From != tt.from || transitionErr.To != tt.event.EventType {
+				t.Errorf("Expected the states in the error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLifecycle_EveryStateIsKnown(t *testing.T) {
+	for _, state := range LifecycleStates() {
+		if !IsValidEventType(state) {
+			t.Errorf("Unknown event type %s in the lifecycle", state)
+		}
+	}
+	for _, tr := range LifecycleTransitions() {
+		if !IsLifecycleEvent(tr.From) {
+			t.Errorf("Transition %s -> %s starts from a state outside the lifecycle", tr.From, tr.To)
+		}
+	}
+	for _, state := range TerminalStates() {
+		if !IsLifecycleEvent(state) {
+			t.Errorf("Terminal state %s is outside the lifecycle", state)
+		}
+	}
+}
+
+func TestLifecycleDiagram(t *testing.T) {
+	diagram := LifecycleDiagram()
+	for _, want := range []string{
+		"stateDiagram-v2\n",
+		"[*] --> CorruptionDetected\n",
+		"DeletionCompleted --> SearchStarted\n",
+		"AnyState --> RetryScheduled\n",
+		"VerificationSuccess --> [*]\n",
+	} {
+		if !strings.Contains(diagram, want) {
+			t.Errorf("Expected %q in the diagram:\n%s", want, diagram)
+		}
+	}
+	if diagram != LifecycleDiagram() {
+		t.Error("Expected the diagram to be stable")
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policies for runs whose time falls into a daylight saving time change.
+const (
+	// DSTGapRun runs a time skipped when clocks go forward right after the
+	// jump, e.g. 02:30 runs at 03:30.
+	DSTGapRun = "run"
+	// DSTGapSkip leaves out a time skipped when clocks go forward.
+	DSTGapSkip = "skip"
+	// DSTOverlapOnce runs a time repeated when clocks go back the first time
+	// only.
+	DSTOverlapOnce = "once"
+	// DSTOverlapTwice runs a time repeated when clocks go back both times.
+	DSTOverlapTwice = "twice"
+)
+
+// ScheduleTiming says in which timezone a cron expression is read and what
+// happens to runs that fall into a daylight saving time change.
+type ScheduleTiming struct {
+	Timezone   string `json:"timezone"`    // IANA name, e.g. Europe/Berlin; empty for the scheduler's timezone
+	DSTGap     string `json:"dst_gap"`     // DSTGapRun (default) or DSTGapSkip
+	DSTOverlap string `json:"dst_overlap"` // DSTOverlapOnce (default) or DSTOverlapTwice
+}
+
+// WithDefaults fills in the default policies.
+func (t ScheduleTiming) WithDefaults() ScheduleTiming {
+	if t.DSTGap == "" {
+		t.DSTGap = DSTGapRun
+	}
+	if t.DSTOverlap == "" {
+		t.DSTOverlap = DSTOverlapOnce
+	}
+	return t
+}
+
+// Validate checks the timezone and policies, returning an error safe to show users.
+func (t ScheduleTiming) Validate() error {
+	if t.Timezone != "" {
+		if _, err := time.LoadLocation(t.Timezone); err != nil || t.Timezone == "Local" {
+			return fmt.Errorf("unknown timezone: %s", t.Timezone)
+		}
+	}
+	if t.DSTGap != "" && t.DSTGap != DSTGapRun && t.DSTGap != DSTGapSkip {
+		return fmt.Errorf("dst_gap must be %s or %s", DSTGapRun, DSTGapSkip)
+	}
+	if t.DSTOverlap != "" && t.DSTOverlap != DSTOverlapOnce && t.DSTOverlap != DSTOverlapTwice {
+		return fmt.Errorf("dst_overlap must be %s or %s", DSTOverlapOnce, DSTOverlapTwice)
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidEventData is returned when event_data does not match the schema of its event type.
+var ErrInvalidEventData = errors.New("invalid event data")
+
+// FieldType is the JSON type an event_data field must have.
+type FieldType string
+
+const (
+	FieldString  FieldType = "string"
+	FieldInteger FieldType = "integer" // Go integer types only; float64 means the value went through JSON
+	FieldNumber  FieldType = "number"
+	FieldBoolean FieldType = "boolean"
+	FieldObject  FieldType = "object"
+	FieldArray   FieldType = "array"
+)
+
+// FieldSchema describes one event_data field.
+type FieldSchema struct {
+	Type     FieldType `json:"type"`
+	Required bool      `json:"required,omitempty"`
+}
+
+// EventSchema maps event_data keys to their schema. Keys not listed are
+// allowed and not checked, so publishers can add context freely.
+type EventSchema map[string]FieldSchema
+
+// Shared field definitions, so the same key has the same type in every event.
+var (
+	filePathField        = FieldSchema{Type: FieldString}
+	filePathRequired     = FieldSchema{Type: FieldString, Required: true}
+	pathIDField          = FieldSchema{Type: FieldInteger}
+	mediaIDField         = FieldSchema{Type: FieldInteger}
+	mediaIDRequired      = FieldSchema{Type: FieldInteger, Required: true}
+	episodeIDsField      = FieldSchema{Type: FieldArray}
+	metadataField        = FieldSchema{Type: FieldObject}
+	errorField           = FieldSchema{Type: FieldString}
+	autoRemediateField   = FieldSchema{Type: FieldBoolean}
+	confidenceField      = FieldSchema{Type: FieldInteger}
+	instanceIDRequired   = FieldSchema{Type: FieldInteger, Required: true}
+	instanceNameField    = FieldSchema{Type: FieldString}
+	instanceNameRequired = FieldSchema{Type: FieldString, Required: true}
+)
+
+// eventSchemas holds the event_data schema of each event type that downstream
+// handlers read fields from. Event types without an entry are not validated.
+var eventSchemas = map[EventType]EventSchema{
+	CorruptionDetected: {
+		"file_path":         filePathRequired,
+		"corruption_type":   {Type: FieldString, Required: true},
+		"path_id":           pathIDField,
+		"file_size":         {Type: FieldInteger},
+		"error_details":     {Type: FieldString},
+		"source":            {Type: FieldString},
+		"auto_remediate":    autoRemediateField,
+		"dry_run":           {Type: FieldBoolean},
+		"batch_throttled":   {Type: FieldBoolean},
+		"confidence":        confidenceField,
+		"min_confidence":    confidenceField,
+		"low_confidence":    {Type: FieldBoolean},
+		"detector_verdicts": {Type: FieldArray},
+	},
+	RemediationQueued: {
+		"media_id": mediaIDField,
+		"dry_run":  {Type: FieldBoolean},
+	},
+	DeletionStarted: {
+		"file_path":     filePathField,
+		"arr_path":      {Type: FieldString},
+		"media_id":      mediaIDField,
+		"deletion_plan": {Type: FieldObject},
+	},
+	DeletionCompleted: {
+		"media_id":  mediaIDRequired,
+		"metadata":  metadataField,
+		"file_size": {Type: FieldInteger},
+	},
+	DeletionFailed: {
+		"file_path": filePathField,
+		"error":     errorField,
+		"cause":     {Type: FieldString},
+		"retryable": {Type: FieldBoolean},
+		"forensics": {Type: FieldObject},
+	},
+	DeletionPlanChanged: {
+		"file_path": filePathField,
+		"planned":   {Type: FieldObject, Required: true},
+		"current":   {Type: FieldObject, Required: true},
+		"reason":    {Type: FieldString, Required: true},
+	},
+	SearchStarted: {
+		"file_path":   filePathField,
+		"media_id":    mediaIDField,
+		"path_id":     pathIDField,
+		"episode_ids": episodeIDsField,
+	},
+	SearchCompleted: {
+		"file_path":     filePathRequired,
+		"media_id":      mediaIDRequired,
+		"path_id":       pathIDField,
+		"metadata":      metadataField,
+		"episode_ids":   episodeIDsField,
+		"is_retry":      {Type: FieldBoolean},
+		"manual_grab":   {Type: FieldBoolean},
+		"release_title": {Type: FieldString},
+	},
+	SearchFailed: {
+		"file_path": filePathField,
+		"path_id":   pathIDField,
+		"error":     errorField,
+	},
+	FileDetected: {
+		"file_path":  filePathRequired,
+		"file_paths": {Type: FieldArray},
+		"file_count": {Type: FieldInteger},
+	},
+	VerificationSuccess: {
+		"file_path":         filePathField,
+		"path_id":           pathIDField,
+		"new_file_size":     {Type: FieldInteger},
+		"rescan_triggered":  {Type: FieldBoolean},
+		"rescan_error":      {Type: FieldString},
+		"reverification_of": {Type: FieldInteger},
+		"audit":             {Type: FieldBoolean},
+	},
+	VerificationFailed: {
+		"error":             errorField,
+		"failed_paths":      {Type: FieldArray},
+		"reverification_of": {Type: FieldInteger},
+		"audit":             {Type: FieldBoolean},
+	},
+	QualityRegression: {
+		"original_quality":    {Type: FieldString},
+		"original_resolution": {Type: FieldInteger, Required: true},
+		"new_quality":         {Type: FieldString, Required: true},
+		"new_resolution":      {Type: FieldInteger, Required: true},
+		"file_path":           filePathField,
+		"keep_searching":      {Type: FieldBoolean},
+		"reason":              {Type: FieldString},
+	},
+	AudioTrackMissing: {
+		"file_path":          filePathField,
+		"missing_languages":  {Type: FieldArray},
+		"original_languages": {Type: FieldArray},
+		"new_languages":      {Type: FieldArray},
+		"original_channels":  {Type: FieldInteger},
+		"new_channels":       {Type: FieldInteger},
+		"reason":             {Type: FieldString, Required: true},
+	},
+	ReverificationRequested: {
+		"file_path":         filePathRequired,
+		"file_paths":        {Type: FieldArray},
+		"reverification_of": {Type: FieldInteger, Required: true},
+		"audit":             {Type: FieldBoolean},
+	},
+	DownloadProgress: {
+		"progress":        {Type: FieldNumber},
+		"status":          {Type: FieldString},
+		"eta_seconds":     {Type: FieldInteger},
+		"indexer":         {Type: FieldString},
+		"download_client": {Type: FieldString},
+	},
+	DownloadRejected: {
+		"file_path":    filePathField,
+		"error":        errorField,
+		"title":        {Type: FieldString},
+		"download_id":  {Type: FieldString},
+		"failed_paths": {Type: FieldArray},
+		"reason":       {Type: FieldString},
+		"protocol":     {Type: FieldString},
+	},
+	RetryScheduled: {
+		"file_path":          filePathRequired,
+		"path_id":            pathIDField,
+		"auto_remediate":     autoRemediateField,
+		"budget_approved":    {Type: FieldBoolean},
+		"scheduled_research": {Type: FieldBoolean},
+		"research_attempt":   {Type: FieldInteger},
+	},
+	MaxRetriesReached: {
+		"file_path":   filePathField,
+		"path_id":     pathIDField,
+		"retry_count": {Type: FieldInteger},
+		"max_retries": {Type: FieldInteger},
+	},
+	SearchExhausted: {
+		"file_path": filePathField,
+		"path_id":   pathIDField,
+		"media_id":  mediaIDField,
+		"reason":    {Type: FieldString},
+	},
+	ScheduledResearch: {
+		"file_path":      filePathRequired,
+		"path_id":        pathIDField,
+		"attempt":        {Type: FieldInteger, Required: true},
+		"next_search_at": {Type: FieldString, Required: true},
+		"started_at":     {Type: FieldString, Required: true},
+		"until":          {Type: FieldString, Required: true},
+		"reason":         {Type: FieldString},
+	},
+	ScanSkipped: {
+		"path":          {Type: FieldString, Required: true},
+		"path_id":       pathIDField,
+		"reason":        {Type: FieldString},
+		"missing_tools": {Type: FieldArray},
+	},
+	ScanProgress: {
+		"total_files": {Type: FieldInteger},
+		"files_done":  {Type: FieldInteger},
+		"scan_db_id":  {Type: FieldInteger},
+	},
+	StuckRemediation: {
+		"file_path":       filePathRequired,
+		"threshold_hours": {Type: FieldNumber},
+	},
+	InstanceUnhealthy: {
+		"instance_name": instanceNameRequired,
+		"error":         errorField,
+	},
+	InstanceHealthy: {
+		"instance_name": instanceNameField,
+	},
+	MappingDrift: {
+		"path_id":            pathIDField,
+		"path":               {Type: FieldString, Required: true},
+		"arr_path":           {Type: FieldString, Required: true},
+		"instance_name":      instanceNameField,
+		"root_folders":       {Type: FieldArray},
+		"suggested_arr_path": {Type: FieldString},
+		"reason":             {Type: FieldString},
+	},
+	ScanPathsChanged: {
+		"action":   {Type: FieldString, Required: true},
+		"path_ids": {Type: FieldArray},
+	},
+	ArrRequestBudgetWarning: {
+		"instance_id":   {Type: FieldInteger, Required: true},
+		"instance_name": instanceNameRequired,
+		"day":           {Type: FieldString, Required: true},
+		"used":          {Type: FieldInteger, Required: true},
+		"limit":         {Type: FieldInteger, Required: true},
+		"exhausted":     {Type: FieldBoolean},
+		"reason":        {Type: FieldString},
+	},
+	SLABreached: {
+		"corruption_id": {Type: FieldString, Required: true},
+		"file_path":     filePathRequired,
+		"elapsed_hours": {Type: FieldNumber},
+		"sla_hours":     {Type: FieldNumber},
+	},
+	RemediationPaused: {
+		"instance_id":    instanceIDRequired,
+		"instance_name":  instanceNameRequired,
+		"affected_paths": {Type: FieldArray},
+		"queued":         {Type: FieldInteger},
+	},
+	RemediationResumed: {
+		"instance_id":    instanceIDRequired,
+		"instance_name":  instanceNameRequired,
+		"affected_paths": {Type: FieldArray},
+		"queued":         {Type: FieldInteger},
+	},
+	AutomationPaused: {
+		"reason":         {Type: FieldString},
+		"detection_only": {Type: FieldBoolean, Required: true},
+		"resumes_at":     {Type: FieldString},
+	},
+	AutomationResumed: {
+		"reason":       {Type: FieldString},
+		"auto_resumed": {Type: FieldBoolean},
+	},
+	RemediationBudgetExceeded: dataBudgetSchema,
+	RemediationBudgetRestored: dataBudgetSchema,
+	RemediationDeferred:       deferredSchema,
+	BudgetApprovalRequired:    overBudgetSchema,
+	LowConfidenceDetection: {
+		"file_path":       filePathRequired,
+		"path_id":         pathIDField,
+		"corruption_type": {Type: FieldString},
+		"confidence":      {Type: FieldInteger, Required: true},
+		"min_confidence":  {Type: FieldInteger, Required: true},
+		"reason":          {Type: FieldString},
+	},
+	ReportGenerated: {
+		"report_id":    {Type: FieldInteger, Required: true},
+		"period_start": {Type: FieldString, Required: true},
+		"period_end":   {Type: FieldString, Required: true},
+		"markdown":     {Type: FieldString, Required: true},
+	},
+	VerificationDeadlineExtended: {
+		"corruption_id":      {Type: FieldString, Required: true},
+		"instance_name":      instanceNameField,
+		"delay_profile_id":   {Type: FieldInteger},
+		"protocol":           {Type: FieldString},
+		"delay_minutes":      {Type: FieldNumber, Required: true},
+		"base_timeout_hours": {Type: FieldNumber},
+		"timeout_hours":      {Type: FieldNumber, Required: true},
+	},
+	MediaReresolved: {
+		"corruption_id":    {Type: FieldString, Required: true},
+		"file_path":        filePathRequired,
+		"path_id":          pathIDField,
+		"arr_path":         {Type: FieldString, Required: true},
+		"from_instance_id": instanceIDRequired,
+		"to_instance_id":   instanceIDRequired,
+		"instance_name":    instanceNameField,
+		"old_media_id":     {Type: FieldInteger},
+		"media_id":         {Type: FieldInteger, Required: true},
+		"metadata":         metadataField,
+	},
+}
+
+// dataBudgetSchema is shared by the monthly data budget events.
+var dataBudgetSchema = EventSchema{
+	"month":        {Type: FieldString, Required: true},
+	"used_bytes":   {Type: FieldInteger, Required: true},
+	"budget_bytes": {Type: FieldInteger, Required: true},
+	"queued":       {Type: FieldInteger},
+	"reason":       {Type: FieldString},
+}
+
+// overBudgetSchema is the schema of BudgetApprovalRequired, for a corruption
+// whose replacement would exceed the monthly data budget.
+var overBudgetSchema = EventSchema{
+	"file_path":       filePathRequired,
+	"path_id":         pathIDField,
+	"month":           {Type: FieldString, Required: true},
+	"projected_bytes": {Type: FieldInteger, Required: true},
+	"used_bytes":      {Type: FieldInteger, Required: true},
+	"budget_bytes":    {Type: FieldInteger, Required: true},
+	"deferred_until":  {Type: FieldString},
+	"reason":          {Type: FieldString},
+}
+
+// deferredSchema is the schema of RemediationDeferred. A remediation is
+// deferred either over the monthly data budget, with the budget fields of
+// overBudgetSchema, by the staged rollout of a new scan path, or while the
+// remediation of the path's group is paused.
+var deferredSchema = EventSchema{
+	"file_path":           filePathRequired,
+	"path_id":             pathIDField,
+	"month":               {Type: FieldString},
+	"projected_bytes":     {Type: FieldInteger},
+	"used_bytes":          {Type: FieldInteger},
+	"budget_bytes":        {Type: FieldInteger},
+	"rollout_daily_limit": {Type: FieldInteger},
+	"deferred_until":      {Type: FieldString},
+	"group_id":            {Type: FieldInteger},
+	"reason":              {Type: FieldString},
+}
+
+// SchemaFor returns the event_data schema for an event type, if it has one.
+func SchemaFor(t EventType) (EventSchema, bool) {
+	s, ok := eventSchemas[t]
+	return s, ok
+}
+
+// ValidateEventData checks event_data against the schema of its event type.
+// All problems are reported together, e.g.
+// "invalid event data for SearchCompleted: media_id is required; path_id must be integer, got float64".
+func ValidateEventData(t EventType, data map[string]interface{}) error {
+	schema, ok := eventSchemas[t]
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		field := schema[key]
+		v, present := data[key]
+		if !present || v == nil {
+			if field.Required {
+				problems = append(problems, key+" is required")
+			}
+			continue
+		}
+		if !matchesType(v, field.Type) {
+			problems = append(problems, fmt.Sprintf("%s must be %s, got %T", key, field.Type, v))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w for %s: %s", ErrInvalidEventData, t, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Validate checks the event's data against the schema of its type.
+func (e *Event) Validate() error {
+	return ValidateEventData(e.EventType, e.EventData)
+}
+
+// matchesType reports whether v has the Go kind that marshals to the given JSON type.
+func matchesType(v interface{}, t FieldType) bool {
+	kind := reflect.TypeOf(v).Kind()
+	switch t {
+	case FieldString:
+		return kind == reflect.String
+	case FieldInteger:
+		return isIntegerKind(kind)
+	case FieldNumber:
+		return isIntegerKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+	case FieldBoolean:
+		return kind == reflect.Bool
+	case FieldObject:
+		return kind == reflect.Map || kind == reflect.Struct ||
+			(kind == reflect.Ptr && reflect.TypeOf(v).Elem().Kind() == reflect.Struct)
+	case FieldArray:
+		return kind == reflect.Slice || kind == reflect.Array
+	}
+	return false
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
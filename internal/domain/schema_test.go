@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateEventData(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType EventType
+		data      map[string]interface{}
+		wantErr   []string
+	}{
+		{
+			name:      "valid corruption",
+			eventType: CorruptionDetected,
+			data: map[string]interface{}{
+				"file_path":       "/media/movie.mkv",
+				"corruption_type": "CorruptHeader",
+				"path_id":         int64(1),
+				"file_size":       int64(1024),
+				"auto_remediate":  true,
+			},
+		},
+		{
+			name:      "missing required fields",
+			eventType: CorruptionDetected,
+			data:      map[string]interface{}{"path_id": int64(1)},
+			wantErr:   []string{"corruption_type is required", "file_path is required"},
+		},
+		{
+			name:      "nil required field",
+			eventType: RetryScheduled,
+			data:      map[string]interface{}{"file_path": nil},
+			wantErr:   []string{"file_path is required"},
+		},
+		{
+			name:      "float path_id",
+			eventType: RetryScheduled,
+			data:      map[string]interface{}{"file_path": "/media/a.mkv", "path_id": float64(2)},
+			wantErr:   []string{"path_id must be integer, got float64"},
+		},
+		{
+			name:      "wrong string type",
+			eventType: SearchCompleted,
+			data:      map[string]interface{}{"file_path": 12, "media_id": int64(3)},
+			wantErr:   []string{"file_path must be string, got int"},
+		},
+		{
+			name:      "number accepts int and float",
+			eventType: SLABreached,
+			data: map[string]interface{}{
+				"corruption_id": "abc",
+				"file_path":     "/media/a.mkv",
+				"elapsed_hours": 49.5,
+				"sla_hours":     48,
+			},
+		},
+		{
+			name:      "arrays and objects",
+			eventType: SearchCompleted,
+			data: map[string]interface{}{
+				"file_path":   "/media/a.mkv",
+				"media_id":    int64(3),
+				"episode_ids": []int64{1, 2},
+				"metadata":    map[string]interface{}{"title": "x"},
+			},
+		},
+		{
+			name:      "array must be a slice",
+			eventType: FileDetected,
+			data:      map[string]interface{}{"file_path": "/media/a.mkv", "file_paths": "/media/a.mkv"},
+			wantErr:   []string{"file_paths must be array, got string"},
+		},
+		{
+			name:      "unknown fields are allowed",
+			eventType: RetryScheduled,
+			data:      map[string]interface{}{"file_path": "/media/a.mkv", "reason": "manual"},
+		},
+		{
+			name:      "event type without schema",
+			eventType: NotificationSent,
+			data:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEventData(tt.eventType, tt.data)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidEventData) {
+				t.Fatalf("Expected ErrInvalidEventData, got %v", err)
+			}
+			if !strings.Contains(err.Error(), string(tt.eventType)) {
+				t.Errorf("Error should name the event type: %v", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Error %q should contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEventSchemas_KnownTypes(t *testing.T) {
+	for eventType := range eventSchemas {
+		if !IsValidEventType(eventType) {
+			t.Errorf("Schema registered for unknown event type %s", eventType)
+		}
+	}
+	if _, ok := SchemaFor(CorruptionDetected); !ok {
+		t.Error("Expected a schema for CorruptionDetected")
+	}
+}
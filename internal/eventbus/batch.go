@@ -0,0 +1,118 @@
+package eventbus
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// eventBatch collects events of the batched types until there are size of
+// them or interval has passed since the first, then stores them together.
+type eventBatch struct {
+	size     int
+	interval time.Duration
+	types    map[domain.EventType]bool
+
+	mu      sync.Mutex // guards pending and timer
+	pending []pendingEvent
+	timer   *time.Timer
+
+	// flushMu serializes flushes, so events are stored in the order they were published
+	flushMu sync.Mutex
+}
+
+// pendingEvent is a validated event waiting to be stored with its batch.
+type pendingEvent struct {
+	event domain.Event
+	data  []byte
+}
+
+// EnableBatching stores events of the given types in transactions of up to
+// size events, flushed at the latest interval after the first one was
+// published, instead of one transaction per event. On a database on spinning
+// disks every transaction costs an fsync, and a large scan publishes an event
+// for every file.
+//
+// Publish returns as soon as a batched event is queued. Subscribers receive it
+// once its batch is stored, and an event of any other type flushes the queue
+// before it is stored, so events are stored and delivered in publish order.
+// The price is crash safety: a crash loses the queued events, at most size
+// events or interval worth. A failed batch is logged and dropped.
+//
+// A size below 2 disables batching. Must be called before events are published.
+func (eb *EventBus) EnableBatching(size int, interval time.Duration, types ...domain.EventType) {
+	if size < 2 || interval <= 0 || len(types) == 0 {
+		eb.batch = nil
+		return
+	}
+	batched := make(map[domain.EventType]bool, len(types))
+	for _, t := range types {
+		batched[t] = true
+	}
+	eb.batch = &eventBatch{size: size, interval: interval, types: batched}
+}
+
+// enqueue adds a validated event to the batch, flushing it once it is full.
+func (eb *EventBus) enqueue(event domain.Event, data []byte) {
+	b := eb.batch
+	b.mu.Lock()
+	b.pending = append(b.pending, pendingEvent{event: event, data: data})
+	full := len(b.pending) >= b.size
+	if !full && b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, eb.Flush)
+	}
+	b.mu.Unlock()
+
+	if full {
+		eb.Flush()
+	}
+}
+
+// Flush stores the queued batch in one transaction and delivers its events to
+// subscribers. It is called automatically; call it to make queued events
+// visible in the database right away.
+func (eb *EventBus) Flush() {
+	b := eb.batch
+	if b == nil {
+		return
+	}
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	eb.mu.RLock()
+	projectors := eb.projectors
+	eb.mu.RUnlock()
+
+	insert, _ := eb.stmts.Get(insertEventSQL)
+	if err := db.NamedTxWithRetry(eb.db, "event_batch_insert", func(tx *sql.Tx) error {
+		for i := range pending {
+			if err := eb.persistAndProject(tx, insert, &pending[i].event, pending[i].data, projectors); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("EventBus: failed to store a batch of %d events, dropping them: %v", len(pending), err)
+		return
+	}
+
+	for _, p := range pending {
+		eb.dispatch(p.event)
+	}
+}
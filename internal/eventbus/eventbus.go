@@ -3,6 +3,7 @@ package eventbus
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -35,24 +36,146 @@ var _ Publisher = (*EventBus)(nil)
 // Events are persisted to the database before being dispatched to subscribers.
 type EventBus struct {
 	db          *sql.DB
+	stmts       *db.StmtCache // statements run for every event
 	subscribers map[domain.EventType][]chan domain.Event
 	mu          sync.RWMutex
 	stopChan    chan struct{}
 	wg          sync.WaitGroup
+
+	// Payload and lifecycle validation (see SetStrictValidation and OnInvalidEvent)
+	strictValidation bool
+	onInvalid        []func(domain.EventType, error)
+
+	// Read models kept up to date with each stored event (see AddProjector)
+	projectors []Projector
+
+	// Events stored in batches (see EnableBatching); nil stores each event on its own
+	batch *eventBatch
 }
 
+// Projector applies a stored event to a read model table within the
+// transaction that stores the event. The event carries its ID and creation time.
+type Projector func(tx *sql.Tx, event domain.Event) error
+
 // NewEventBus creates a new EventBus with the given database connection.
-func NewEventBus(db *sql.DB) *EventBus {
+func NewEventBus(database *sql.DB) *EventBus {
 	return &EventBus{
-		db:          db,
+		db:          database,
+		stmts:       db.NewStmtCache(database),
 		subscribers: make(map[domain.EventType][]chan domain.Event),
 		stopChan:    make(chan struct{}),
 	}
 }
 
+// SetStrictValidation controls what happens to events whose payload does not
+// match the schema of their type, or corruption events that don't follow from
+// the corruption's state. In strict mode (development and tests) Publish
+// rejects them; otherwise they are logged, counted and published anyway so a
+// schema mistake cannot stop remediation in production.
+// Must be called before events are published.
+func (eb *EventBus) SetStrictValidation(strict bool) {
+	eb.strictValidation = strict
+}
+
+// OnInvalidEvent registers a callback for events that fail payload or lifecycle
+// validation, e.g. to count them in metrics. Must be called before events are published.
+func (eb *EventBus) OnInvalidEvent(fn func(domain.EventType, error)) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.onInvalid = append(eb.onInvalid, fn)
+}
+
+// AddProjector registers a read model projection. Each event is stored and
+// projected in one transaction, before subscribers receive it, so reads that
+// follow Publish see the event. A failing projection is logged and the event
+// stored anyway; the read model is repaired from the events later.
+// Must be called before events are published.
+func (eb *EventBus) AddProjector(p Projector) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.projectors = append(eb.projectors, p)
+}
+
+// validate checks the event payload against its schema and corruption events
+// against the lifecycle, and reports failures. It returns an error only in
+// strict mode.
+func (eb *EventBus) validate(event domain.Event) error {
+	var failures []error
+	if err := event.Validate(); err != nil {
+		failures = append(failures, err)
+	}
+	if err := eb.validateTransition(event); err != nil {
+		failures = append(failures, err)
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	eb.mu.RLock()
+	hooks := eb.onInvalid
+	eb.mu.RUnlock()
+	for _, err := range failures {
+		for _, fn := range hooks {
+			fn(event.EventType, err)
+		}
+	}
+
+	err := errors.Join(failures...)
+	if eb.strictValidation {
+		return err
+	}
+	logger.Errorf("EventBus: %v (aggregate %s) - publishing anyway", err, event.AggregateID)
+	return nil
+}
+
+// validateTransition checks that a corruption event follows from the state
+// the corruption is in.
+func (eb *EventBus) validateTransition(event domain.Event) error {
+	if eb.db == nil || event.AggregateType != domain.CorruptionAggregate || !domain.IsLifecycleEvent(event.EventType) {
+		return nil
+	}
+	var from string
+	var row *sql.Row
+	start := time.Now()
+	if stmt, err := eb.stmts.Get(currentStateSQL); err == nil {
+		row = stmt.QueryRow(event.AggregateID)
+	} else {
+		row = eb.db.QueryRow(currentStateSQL, event.AggregateID)
+	}
+	err := row.Scan(&from)
+	db.ObserveLatency("event_state_lookup", start)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		// Can't tell the current state; the event itself is fine
+		logger.Debugf("EventBus: failed to load state of %s: %v", event.AggregateID, err)
+		return nil
+	}
+	return domain.ValidateTransition(domain.EventType(from), event)
+}
+
+// lifecycleStatesSQL lists the event types that change a corruption's state.
+var lifecycleStatesSQL = func() string {
+	states := domain.LifecycleStates()
+	quoted := make([]string, len(states))
+	for i, t := range states {
+		quoted[i] = "'" + string(t) + "'"
+	}
+	return strings.Join(quoted, ", ")
+}()
+
+// currentStateSQL loads the latest lifecycle state of a corruption.
+var currentStateSQL = `
+	SELECT event_type FROM events
+	WHERE aggregate_id = ? AND event_type IN (` + lifecycleStatesSQL + `)
+	ORDER BY id DESC LIMIT 1
+`
+
 func (eb *EventBus) Publish(event domain.Event) error {
 	logger.Debugf("EventBus: Publishing event %s (ID: %d, AggregateID: %s)", event.EventType, event.ID, event.AggregateID)
 
+	if err := eb.validate(event); err != nil {
+		return err
+	}
+
 	// 1. Store event in database (source of truth)
 	eventDataJSON, err := json.Marshal(event.EventData)
 	if err != nil {
@@ -67,22 +190,50 @@ func (eb *EventBus) Publish(event domain.Event) error {
 		event.EventVersion = 1
 	}
 
-	res, err := db.ExecWithRetry(eb.db, `
-        INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, event_version, created_at, user_id)
-        VALUES (?, ?, ?, ?, ?, ?, ?)
-    `, event.AggregateType, event.AggregateID, event.EventType, eventDataJSON, event.EventVersion, event.CreatedAt, event.UserID)
-
-	if err != nil {
-		return fmt.Errorf("failed to persist event: %w", err)
+	if eb.batch != nil && eb.batch.types[event.EventType] {
+		eb.enqueue(event, eventDataJSON)
+		return nil
 	}
+	// Events queued for a batch are older and must be stored first
+	eb.Flush()
 
-	// Get the ID of the inserted event
-	id, err := res.LastInsertId()
-	if err == nil {
-		event.ID = id
+	eb.mu.RLock()
+	projectors := eb.projectors
+	eb.mu.RUnlock()
+
+	// Prepared once and reused; nil falls back to the plain statement
+	insert, _ := eb.stmts.Get(insertEventSQL)
+
+	if len(projectors) == 0 {
+		args := []interface{}{event.AggregateType, event.AggregateID, event.EventType, eventDataJSON, event.EventVersion, event.CreatedAt, event.UserID}
+		var res sql.Result
+		if insert != nil {
+			res, err = db.ExecStmtWithRetry(insert, "event_insert", args...)
+		} else {
+			res, err = db.ExecWithRetry(eb.db, insertEventSQL, args...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to persist event: %w", err)
+		}
+
+		// Get the ID of the inserted event
+		id, err := res.LastInsertId()
+		if err == nil {
+			event.ID = id
+		}
+	} else if err := db.NamedTxWithRetry(eb.db, "event_insert", func(tx *sql.Tx) error {
+		return eb.persistAndProject(tx, insert, &event, eventDataJSON, projectors)
+	}); err != nil {
+		return fmt.Errorf("failed to persist event: %w", err)
 	}
 
 	// 2. Publish to in-memory subscribers
+	eb.dispatch(event)
+	return nil
+}
+
+// dispatch sends a stored event to its subscribers without blocking.
+func (eb *EventBus) dispatch(event domain.Event) {
 	eb.mu.RLock()
 	defer eb.mu.RUnlock()
 
@@ -101,7 +252,38 @@ func (eb *EventBus) Publish(event domain.Event) error {
 			}
 		}
 	}
+}
+
+// insertEventSQL stores an event.
+const insertEventSQL = `
+	INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, event_version, created_at, user_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+// persistAndProject stores an event and applies it to the read models in tx.
+// insert is the prepared insertEventSQL, or nil to run the plain statement.
+// It must be prepared before tx starts: preparing needs a connection of its own.
+func (eb *EventBus) persistAndProject(tx *sql.Tx, insert *sql.Stmt, event *domain.Event, eventDataJSON []byte, projectors []Projector) error {
+	args := []interface{}{event.AggregateType, event.AggregateID, event.EventType, eventDataJSON, event.EventVersion, event.CreatedAt, event.UserID}
+	var res sql.Result
+	var err error
+	if insert != nil {
+		res, err = tx.Stmt(insert).Exec(args...)
+	} else {
+		res, err = tx.Exec(insertEventSQL, args...)
+	}
+	if err != nil {
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		event.ID = id
+	}
 
+	for _, project := range projectors {
+		if err := project(tx, *event); err != nil {
+			logger.Errorf("EventBus: projection of %s (aggregate %s) failed: %v", event.EventType, event.AggregateID, err)
+		}
+	}
 	return nil
 }
 
@@ -118,8 +300,9 @@ func (eb *EventBus) PublishWithRetry(event domain.Event) error {
 			return nil
 		}
 
-		// Don't retry marshal errors (data validation issue, not transient)
-		if strings.Contains(lastErr.Error(), "marshal") {
+		// Don't retry marshal, schema or lifecycle errors (data validation issue, not transient)
+		if strings.Contains(lastErr.Error(), "marshal") || errors.Is(lastErr, domain.ErrInvalidEventData) ||
+			errors.Is(lastErr, domain.ErrInvalidTransition) {
 			return lastErr
 		}
 
@@ -183,6 +366,7 @@ func (eb *EventBus) RepublishToSubscribers(event domain.Event) error {
 
 // Shutdown stops all subscriber goroutines and waits for them to finish
 func (eb *EventBus) Shutdown() {
+	eb.Flush()
 	close(eb.stopChan)
 	eb.wg.Wait()
 	logger.Infof("EventBus shutdown complete")
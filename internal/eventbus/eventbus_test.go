@@ -3,12 +3,14 @@ package eventbus
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	_ "modernc.org/sqlite" // Register pure-Go SQLite driver for database/sql
 
+	"github.com/mescon/Healarr/internal/db"
 	"github.com/mescon/Healarr/internal/domain"
 )
 
@@ -960,3 +962,366 @@ func TestRepublishToSubscribers_NoSubscribers(t *testing.T) {
 		t.Errorf("RepublishToSubscribers should not error with no subscribers: %v", err)
 	}
 }
+
+// TestEventBus_Validation_Lenient tests that malformed events are reported but still published.
+func TestEventBus_Validation_Lenient(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	eb := NewEventBus(db)
+	defer eb.Shutdown()
+
+	var reported []domain.EventType
+	eb.OnInvalidEvent(func(eventType domain.EventType, err error) {
+		reported = append(reported, eventType)
+	})
+
+	err := eb.Publish(domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "lenient-validation-test",
+		EventType:     domain.RetryScheduled,
+		EventData:     map[string]interface{}{"file_path": "/media/a.mkv", "path_id": float64(3)},
+	})
+	if err != nil {
+		t.Fatalf("Publish should not fail outside strict mode: %v", err)
+	}
+
+	if len(reported) != 1 || reported[0] != domain.RetryScheduled {
+		t.Errorf("Expected one invalid RetryScheduled report, got %v", reported)
+	}
+	if events := getEventsByAggregate(t, db, "lenient-validation-test"); len(events) != 1 {
+		t.Errorf("Expected event to be persisted, got %d", len(events))
+	}
+}
+
+// TestEventBus_Validation_Strict tests that strict mode rejects malformed events before persisting them.
+func TestEventBus_Validation_Strict(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	eb := NewEventBus(db)
+	eb.SetStrictValidation(true)
+	defer eb.Shutdown()
+
+	event := domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "strict-validation-test",
+		EventType:     domain.SearchCompleted,
+		EventData:     map[string]interface{}{"media_id": int64(42)},
+	}
+
+	start := time.Now()
+	err := eb.PublishWithRetry(event)
+	if !errors.Is(err, domain.ErrInvalidEventData) {
+		t.Fatalf("Expected ErrInvalidEventData, got %v", err)
+	}
+	if !containsString(err.Error(), "file_path is required") {
+		t.Errorf("Expected error to name the missing field, got: %v", err)
+	}
+	// Schema errors are not transient and must not be retried
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Schema error took too long (%v), should not have retried", elapsed)
+	}
+	if events := getEventsByAggregate(t, db, "strict-validation-test"); len(events) != 0 {
+		t.Errorf("Rejected event should not be persisted, got %d", len(events))
+	}
+
+	// A well-formed event still goes through
+	event.EventData["file_path"] = "/media/a.mkv"
+	if err := eb.Publish(event); err != nil {
+		t.Errorf("Valid event should publish in strict mode: %v", err)
+	}
+}
+
+// TestEventBus_Transition_Strict tests that strict mode rejects corruption
+// events that don't follow from the corruption's state.
+func TestEventBus_Transition_Strict(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	eb := NewEventBus(db)
+	eb.SetStrictValidation(true)
+	defer eb.Shutdown()
+
+	publish := func(eventType domain.EventType, data map[string]interface{}) error {
+		return eb.PublishWithRetry(domain.Event{
+			AggregateType: "corruption",
+			AggregateID:   "strict-transition-test",
+			EventType:     eventType,
+			EventData:     data,
+		})
+	}
+
+	if err := publish(domain.SearchExhausted, map[string]interface{}{"file_path": "/media/a.mkv"}); err != nil {
+		t.Fatalf("Events without history should publish: %v", err)
+	}
+	// Notifications don't change the state
+	if err := publish(domain.NotificationSent, nil); err != nil {
+		t.Fatalf("Notification should publish: %v", err)
+	}
+
+	start := time.Now()
+	err := publish(domain.VerificationSuccess, map[string]interface{}{"file_path": "/media/a.mkv"})
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+	}
+	if !containsString(err.Error(), "VerificationSuccess cannot follow SearchExhausted") {
+		t.Errorf("Expected error to name both states, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Transition error took too long (%v), should not have retried", elapsed)
+	}
+	if events := getEventsByAggregate(t, db, "strict-transition-test"); len(events) != 2 {
+		t.Errorf("Rejected event should not be persisted, got %d events", len(events))
+	}
+
+	// Reconciling with the *arr may resolve it anyway
+	if err := publish(domain.VerificationSuccess, map[string]interface{}{"file_path": "/media/a.mkv", "recovery_action": "arr_sync"}); err != nil {
+		t.Errorf("Override should publish in strict mode: %v", err)
+	}
+}
+
+// TestEventBus_Transition_Lenient tests that out-of-order events are reported but still published.
+func TestEventBus_Transition_Lenient(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	eb := NewEventBus(db)
+	defer eb.Shutdown()
+
+	var reported []error
+	eb.OnInvalidEvent(func(eventType domain.EventType, err error) {
+		reported = append(reported, err)
+	})
+
+	for _, eventType := range []domain.EventType{domain.CorruptionDetected, domain.DeletionCompleted} {
+		if err := eb.Publish(domain.Event{
+			AggregateType: "corruption",
+			AggregateID:   "lenient-transition-test",
+			EventType:     eventType,
+			EventData:     map[string]interface{}{"file_path": "/media/a.mkv", "corruption_type": "CorruptHeader", "media_id": int64(1)},
+		}); err != nil {
+			t.Fatalf("Publish should not fail outside strict mode: %v", err)
+		}
+	}
+
+	var transitionErr *domain.TransitionError
+	if len(reported) != 1 || !errors.As(reported[0], &transitionErr) ||
+		transitionErr.From != domain.CorruptionDetected || transitionErr.To != domain.DeletionCompleted {
+		t.Errorf("Expected one DeletionCompleted after CorruptionDetected report, got %v", reported)
+	}
+	if events := getEventsByAggregate(t, db, "lenient-transition-test"); len(events) != 2 {
+		t.Errorf("Expected both events to be persisted, got %d", len(events))
+	}
+}
+
+// TestEventBus_Projector tests that projectors see each stored event within its
+// transaction and that a failing projection doesn't lose the event.
+func TestEventBus_Projector(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE projected (event_id INTEGER, event_type TEXT)`); err != nil {
+		t.Fatalf("Failed to create projected table: %v", err)
+	}
+
+	eb := NewEventBus(db)
+	defer eb.Shutdown()
+	eb.AddProjector(func(tx *sql.Tx, e domain.Event) error {
+		_, err := tx.Exec(`INSERT INTO projected (event_id, event_type) VALUES (?, ?)`, e.ID, e.EventType)
+		return err
+	})
+	eb.AddProjector(func(tx *sql.Tx, e domain.Event) error {
+		return errors.New("projection failed")
+	})
+
+	// Subscribers receive events after they are projected
+	projected := make(chan int, 1)
+	eb.Subscribe(domain.CorruptionDetected, func(e domain.Event) {
+		var n int
+		_ = db.QueryRow(`SELECT COUNT(*) FROM projected WHERE event_id = ?`, e.ID).Scan(&n)
+		projected <- n
+	})
+
+	if err := eb.Publish(domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "projector-test",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/a.mkv"},
+	}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	select {
+	case n := <-projected:
+		if n != 1 {
+			t.Errorf("Expected the event to be projected before delivery, found %d rows", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscriber not called")
+	}
+	if events := getEventsByAggregate(t, db, "projector-test"); len(events) != 1 {
+		t.Errorf("Expected the event to be stored despite the failing projection, got %d", len(events))
+	}
+}
+
+func TestEventBus_ReportsLatency(t *testing.T) {
+	sqlDB := newTestDB(t)
+	defer sqlDB.Close()
+
+	var mu sync.Mutex
+	ops := make(map[string]int)
+	db.SetLatencyObserver(func(operation string, _ time.Duration, _ int) {
+		mu.Lock()
+		ops[operation]++
+		mu.Unlock()
+	})
+	t.Cleanup(func() { db.SetLatencyObserver(nil) })
+
+	eb := NewEventBus(sqlDB)
+	defer eb.Shutdown()
+
+	// The prepared insert is reused for every event
+	for i := 0; i < 3; i++ {
+		if err := eb.Publish(domain.Event{
+			AggregateType: "corruption",
+			AggregateID:   "latency-test",
+			EventType:     domain.CorruptionDetected,
+			EventData:     map[string]interface{}{"file_path": "/media/a.mkv"},
+		}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if ops["event_insert"] != 3 || ops["event_state_lookup"] != 3 {
+		t.Errorf("Expected 3 inserts and state lookups to be reported, got %v", ops)
+	}
+	if events := getEventsByAggregate(t, sqlDB, "latency-test"); len(events) != 3 {
+		t.Errorf("Expected 3 stored events, got %d", len(events))
+	}
+}
+
+func countEvents(t *testing.T, sqlDB *sql.DB, eventType domain.EventType) int {
+	t.Helper()
+	var n int
+	if err := sqlDB.QueryRow(`SELECT COUNT(*) FROM events WHERE event_type = ?`, eventType).Scan(&n); err != nil {
+		t.Fatalf("Failed to count events: %v", err)
+	}
+	return n
+}
+
+func TestEventBus_Batching_FlushesWhenFull(t *testing.T) {
+	sqlDB := newTestDB(t)
+	defer sqlDB.Close()
+
+	eb := NewEventBus(sqlDB)
+	defer eb.Shutdown()
+	eb.EnableBatching(3, time.Hour, domain.ScanProgress)
+
+	received := make(chan domain.Event, 10)
+	eb.Subscribe(domain.ScanProgress, func(e domain.Event) { received <- e })
+
+	publish := func(i int) {
+		if err := eb.Publish(domain.Event{
+			AggregateType: "scan",
+			AggregateID:   "scan-1",
+			EventType:     domain.ScanProgress,
+			EventData:     map[string]interface{}{"files_done": i},
+		}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	publish(1)
+	publish(2)
+	if n := countEvents(t, sqlDB, domain.ScanProgress); n != 0 {
+		t.Fatalf("Expected queued events not to be stored yet, found %d", n)
+	}
+	select {
+	case e := <-received:
+		t.Fatalf("Expected no delivery before the batch is stored, got %+v", e)
+	default:
+	}
+
+	publish(3)
+	if n := countEvents(t, sqlDB, domain.ScanProgress); n != 3 {
+		t.Fatalf("Expected the full batch to be stored, found %d", n)
+	}
+	for i := 1; i <= 3; i++ {
+		select {
+		case e := <-received:
+			if e.ID == 0 || e.EventData["files_done"] != i {
+				t.Errorf("Expected event %d with its ID in publish order, got %+v", i, e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Event %d not delivered", i)
+		}
+	}
+}
+
+func TestEventBus_Batching_FlushesAfterInterval(t *testing.T) {
+	sqlDB := newTestDB(t)
+	defer sqlDB.Close()
+
+	eb := NewEventBus(sqlDB)
+	defer eb.Shutdown()
+	eb.EnableBatching(100, 20*time.Millisecond, domain.ScanProgress)
+
+	if err := eb.Publish(domain.Event{
+		AggregateType: "scan",
+		AggregateID:   "scan-1",
+		EventType:     domain.ScanProgress,
+		EventData:     map[string]interface{}{},
+	}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for countEvents(t, sqlDB, domain.ScanProgress) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := countEvents(t, sqlDB, domain.ScanProgress); n != 1 {
+		t.Errorf("Expected the event to be stored after the flush interval, found %d", n)
+	}
+}
+
+func TestEventBus_Batching_KeepsPublishOrder(t *testing.T) {
+	sqlDB := newTestDB(t)
+	defer sqlDB.Close()
+
+	eb := NewEventBus(sqlDB)
+	eb.EnableBatching(100, time.Hour, domain.ScanProgress)
+
+	for _, eventType := range []domain.EventType{domain.ScanProgress, domain.ScanProgress, domain.ScanCompleted, domain.ScanProgress} {
+		if err := eb.Publish(domain.Event{
+			AggregateType: "scan",
+			AggregateID:   "scan-1",
+			EventType:     eventType,
+			EventData:     map[string]interface{}{},
+		}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	// The unbatched event flushed the two before it; Shutdown flushes the rest
+	if n := countEvents(t, sqlDB, domain.ScanProgress); n != 2 {
+		t.Errorf("Expected 2 progress events stored before ScanCompleted, found %d", n)
+	}
+	eb.Shutdown()
+
+	events := getEventsByAggregate(t, sqlDB, "scan-1")
+	var order []domain.EventType
+	for _, e := range events {
+		order = append(order, e.EventType)
+	}
+	want := []domain.EventType{domain.ScanProgress, domain.ScanProgress, domain.ScanCompleted, domain.ScanProgress}
+	if len(order) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, order)
+		}
+	}
+}
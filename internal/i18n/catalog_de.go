@@ -0,0 +1,195 @@
+package i18n
+
+// catalogDE holds the German translations.
+var catalogDE = map[string]string{
+	// API errors
+	"Database error":        "Datenbankfehler",
+	"Authentication error":  "Authentifizierungsfehler",
+	"Invalid request":       "Ungültige Anfrage",
+	"Not found":             "Nicht gefunden",
+	"Service unavailable":   "Dienst nicht verfügbar",
+	"Internal server error": "Interner Serverfehler",
+	"Scan not found":        "Scan nicht gefunden",
+	"Invalid ID":            "Ungültige ID",
+	"%s not found":          "%s nicht gefunden",
+	"%s not available":      "%s nicht verfügbar",
+
+	// Request validation
+	"Invalid URL: %v":                               "Ungültige URL: %v",
+	"%s is required":                                "%s ist erforderlich",
+	"%s is required when %s is not set":             "%s ist erforderlich, wenn %s nicht gesetzt ist",
+	"%s must not be empty":                          "%s darf nicht leer sein",
+	"%s must be at least %s characters":             "%s muss mindestens %s Zeichen lang sein",
+	"%s must have at least %s items":                "%s muss mindestens %s Einträge haben",
+	"%s must be %s or greater":                      "%s muss %s oder größer sein",
+	"%s must be at most %s characters":              "%s darf höchstens %s Zeichen lang sein",
+	"%s must have at most %s items":                 "%s darf höchstens %s Einträge haben",
+	"%s must be %s or less":                         "%s muss %s oder kleiner sein",
+	"%s must be greater than %s":                    "%s muss größer als %s sein",
+	"%s must be less than %s":                       "%s muss kleiner als %s sein",
+	"%s must be between %s and %s":                  "%s muss zwischen %s und %s liegen",
+	"%s must be one of: %s":                         "%s muss einer dieser Werte sein: %s",
+	"%s must be a valid URL":                        "%s muss eine gültige URL sein",
+	"%s must start with %q":                         "%s muss mit %q beginnen",
+	"%s failed the %s check":                        "%s hat die Prüfung %s nicht bestanden",
+	"%s must be %s, not %s":                         "%s muss %s sein, nicht %s",
+	"a boolean":                                     "ein Wahrheitswert",
+	"an integer":                                    "eine ganze Zahl",
+	"a number":                                      "eine Zahl",
+	"a string":                                      "eine Zeichenkette",
+	"an array":                                      "ein Array",
+	"an object":                                     "ein Objekt",
+	"malformed JSON at offset %d":                   "Fehlerhaftes JSON an Position %d",
+	"request body is required":                      "Anfragetext fehlt",
+	"malformed JSON: unexpected end of input":       "Fehlerhaftes JSON: unerwartetes Ende der Eingabe",
+	"Password":                                      "Passwort",
+	"New password":                                  "Neues Passwort",
+	"Current password":                              "Aktuelles Passwort",
+	"No scan paths provided":                        "Keine Scanpfade angegeben",
+	"At most %d scan paths can be imported at once": "Es können höchstens %d Scanpfade auf einmal importiert werden",
+
+	// API resources
+	"Arr client":           "*arr-Client",
+	"Backup target":        "Sicherungsziel",
+	"Corruption":           "Beschädigung",
+	"Database":             "Datenbank",
+	"Deleted instance":     "Gelöschte Instanz",
+	"Deleted scan path":    "Gelöschter Scanpfad",
+	"Deletion plan":        "Löschplan",
+	"Delivery":             "Zustellung",
+	"Diagnostics":          "Diagnosedaten",
+	"False positive":       "Fehlalarm",
+	"Instance":             "Instanz",
+	"Mapping overrides":    "Manuelle Pfadzuordnungen",
+	"Media history":        "Medienverlauf",
+	"Notification":         "Benachrichtigung",
+	"Notification service": "Benachrichtigungsdienst",
+	"Path":                 "Pfad",
+	"Path group":           "Pfadgruppe",
+	"Protected item":       "Geschützter Eintrag",
+	"Remediator":           "Behebungsdienst",
+	"Report":               "Bericht",
+	"Resolution audit":     "Prüfung behobener Beschädigungen",
+	"Sample":               "Dateiausschnitt",
+	"Saved filter":         "Gespeicherter Filter",
+	"Scan path":            "Scanpfad",
+	"Scanner":              "Scanner",
+	"Schedule":             "Zeitplan",
+	"Scheduler":            "Zeitplaner",
+	"Startup report":       "Startbericht",
+	"User":                 "Benutzer",
+	"Webhook":              "Webhook",
+	"Webhook outbox":       "Webhook-Warteschlange",
+
+	// Notification messages
+	"🔍 Scan started: %s":                               "🔍 Scan gestartet: %s",
+	"✅ Scan complete: %s\n📊 %d/%d healthy, %d corrupt": "✅ Scan abgeschlossen: %s\n📊 %d/%d intakt, %d beschädigt",
+	"❌ Scan failed: %s\n⚠️ %s":                         "❌ Scan fehlgeschlagen: %s\n⚠️ %s",
+	"⏭️ Scan skipped: %s":                              "⏭️ Scan übersprungen: %s",
+	"🔴 Corruption detected: %s":                        "🔴 Beschädigung erkannt: %s",
+	"\n📋 Type: %s":                                     "\n📋 Typ: %s",
+	"\n📋 Reason: %s":                                   "\n📋 Grund: %s",
+	"🔧 Remediation queued: %s":                         "🔧 Behebung eingereiht: %s",
+	"🗑️ Deletion started: %s":                          "🗑️ Löschung gestartet: %s",
+	"✅ File deleted for re-download: %s":               "✅ Datei zum erneuten Herunterladen gelöscht: %s",
+	"❌ Deletion failed: %s\n⚠️ %s":                     "❌ Löschung fehlgeschlagen: %s\n⚠️ %s",
+	"🔎 Search triggered in *arr: %s":                   "🔎 Suche in *arr ausgelöst: %s",
+	"✅ Search completed: %s":                           "✅ Suche abgeschlossen: %s",
+	"❌ Search failed: %s\n⚠️ %s":                       "❌ Suche fehlgeschlagen: %s\n⚠️ %s",
+	"🔬 Verification started: %s":                       "🔬 Prüfung gestartet: %s",
+	"✅ File verified healthy: %s":                      "✅ Datei als intakt bestätigt: %s",
+	"❌ Verification failed: %s\n⚠️ %s":                 "❌ Prüfung fehlgeschlagen: %s\n⚠️ %s",
+	"⏰ Download timeout: %s":                           "⏰ Download-Zeitüberschreitung: %s",
+	"🚫 Import blocked in *arr: %s\n⚠️ %s\n👉 Manual intervention required in Sonarr/Radarr":       "🚫 Import in *arr blockiert: %s\n⚠️ %s\n👉 Manuelles Eingreifen in Sonarr/Radarr erforderlich",
+	"📶 Approval required: %s\n⚠️ %s\n👉 Approve the remediation in Healarr to download it anyway": "📶 Freigabe erforderlich: %s\n⚠️ %s\n👉 Gib die Behebung in Healarr frei, um trotzdem herunterzuladen",
+	"🤔 Unconfirmed corruption: %s\n⚠️ %s\n👉 Retry in Healarr to remediate it, or ignore it":      "🤔 Unbestätigte Beschädigung: %s\n⚠️ %s\n👉 In Healarr erneut versuchen, um sie zu beheben, oder ignorieren",
+	"📉 Quality regression: %s\n⚠️ %s":                                                            "📉 Qualitätsverlust: %s\n⚠️ %s",
+	"🔇 Replacement missing audio track: %s\n⚠️ %s":                                               "🔇 Ersatz ohne Tonspur: %s\n⚠️ %s",
+	"✋ Deletion stopped: %s\n⚠️ %s\n👉 Check the file in *arr, then retry in Healarr":             "✋ Löschung angehalten: %s\n⚠️ %s\n👉 Prüfe die Datei in *arr und versuche es dann in Healarr erneut",
+	"📶 Remediation deferred: %s\n⚠️ %s":                                                          "📶 Behebung zurückgestellt: %s\n⚠️ %s",
+	"\n👉 Goes ahead on %s": "\n👉 Wird am %s fortgesetzt",
+	"🗑️ Download manually removed: %s\n👉 Item was removed from *arr queue without being imported":      "🗑️ Download manuell entfernt: %s\n👉 Der Eintrag wurde ohne Import aus der *arr-Warteschlange entfernt",
+	"⏸️ Download ignored by user: %s\n👉 User marked download as ignored in *arr - remediation stopped": "⏸️ Download vom Benutzer ignoriert: %s\n👉 Der Download wurde in *arr als ignoriert markiert - Behebung beendet",
+	"🔄 Retry scheduled (%d/%d): %s":                                                        "🔄 Neuer Versuch geplant (%d/%d): %s",
+	"⚠️ Max retries exhausted (%d): %s":                                                    "⚠️ Maximale Versuche aufgebraucht (%d): %s",
+	"🔍 No replacement found: %s":                                                           "🔍 Kein Ersatz gefunden: %s",
+	"\n📊 Attempts: %d":                                                                     "\n📊 Versuche: %d",
+	"\n👉 Check your indexers or manually search in Sonarr/Radarr":                          "\n👉 Prüfe deine Indexer oder suche manuell in Sonarr/Radarr",
+	"🗓️ No replacement yet: %s":                                                            "🗓️ Noch kein Ersatz: %s",
+	"\n🔍 Searching again on %s":                                                            "\n🔍 Erneute Suche am %s",
+	"❌ Download failed: %s":                                                                "❌ Download fehlgeschlagen: %s",
+	"🚫 Corrupt download blocklisted before import: %s":                                     "🚫 Beschädigter Download vor dem Import gesperrt: %s",
+	"🚫 Download blocklisted: %s":                                                           "🚫 Download gesperrt: %s",
+	"\n🔍 *arr is searching for another release":                                            "\n🔍 *arr sucht nach einem anderen Release",
+	"⚠️ System health degraded":                                                            "⚠️ Systemzustand beeinträchtigt",
+	"🔴 Arr instance unreachable":                                                           "🔴 Arr-Instanz nicht erreichbar",
+	"🟢 Arr instance recovered":                                                             "🟢 Arr-Instanz wieder erreichbar",
+	"⌛ Resolution SLA breached":                                                            "⌛ Lösungs-SLA überschritten",
+	"\n⏱️ Unresolved for %.1fh (SLA: %.1fh)":                                               "\n⏱️ Seit %.1f h ungelöst (SLA: %.1f h)",
+	"⏸️ Remediation paused, scanning continues in detection-only mode":                     "⏸️ Behebung pausiert, Scans laufen im Nur-Erkennungsmodus weiter",
+	"\n👉 New corruptions are queued until the instance recovers":                           "\n👉 Neue Beschädigungen warten, bis die Instanz wieder erreichbar ist",
+	"▶️ Remediation resumed":                                                               "▶️ Behebung fortgesetzt",
+	"📉 Daily *arr API request budget nearly used up":                                       "📉 Tägliches *arr-API-Anfragebudget fast aufgebraucht",
+	"\n👉 Non-urgent requests wait until tomorrow; remediation continues":                   "\n👉 Nicht dringende Anfragen warten bis morgen; die Behebung läuft weiter",
+	"🧭 Scan path no longer matches the *arr root folders":                                  "🧭 Scanpfad passt nicht mehr zu den *arr-Stammordnern",
+	"\n👉 Remap the path in Healarr's config so its files match *arr media again":           "\n👉 Ordne den Pfad in der Healarr-Konfiguration neu zu, damit seine Dateien wieder zu den *arr-Medien passen",
+	"📶 Monthly remediation data budget used up":                                            "📶 Monatliches Datenbudget für Behebungen aufgebraucht",
+	"\n👉 New corruptions are queued until next month":                                      "\n👉 Neue Beschädigungen warten bis zum nächsten Monat",
+	"📶 Remediation data budget available again":                                            "📶 Datenbudget für Behebungen wieder verfügbar",
+	"⏸️ Automation paused":                                                                 "⏸️ Automatisierung pausiert",
+	"\n👉 Remediation and verification are on hold until automation is resumed":             "\n👉 Behebung und Prüfung ruhen, bis die Automatisierung fortgesetzt wird",
+	"▶️ Automation resumed":                                                                "▶️ Automatisierung fortgesetzt",
+	"⏰ Stuck remediation detected":                                                         "⏰ Hängende Behebung erkannt",
+	"\n👉 Remediation has shown no progress - manual check recommended":                     "\n👉 Die Behebung kommt nicht voran - manuelle Prüfung empfohlen",
+	"🙈 Corruption ignored: %s":                                                             "🙈 Beschädigung ignoriert: %s",
+	"📢 Event: %s":                                                                          "📢 Ereignis: %s",
+	"🧪 Healarr Test Notification\n✅ Your notification configuration is working correctly!": "🧪 Healarr-Testbenachrichtigung\n✅ Deine Benachrichtigungskonfiguration funktioniert!",
+
+	// Notification titles
+	"🔴 Corruption Detected":                       "🔴 Beschädigung erkannt",
+	"🔍 Scan Started":                              "🔍 Scan gestartet",
+	"✅ Scan Complete":                             "✅ Scan abgeschlossen",
+	"❌ Scan Failed":                               "❌ Scan fehlgeschlagen",
+	"⏭️ Scan Skipped":                             "⏭️ Scan übersprungen",
+	"🔧 Remediation Queued":                        "🔧 Behebung eingereiht",
+	"🗑️ Deletion Started":                         "🗑️ Löschung gestartet",
+	"✅ File Deleted":                              "✅ Datei gelöscht",
+	"❌ Deletion Failed":                           "❌ Löschung fehlgeschlagen",
+	"🔎 Search Triggered":                          "🔎 Suche ausgelöst",
+	"✅ Search Complete":                           "✅ Suche abgeschlossen",
+	"❌ Search Failed":                             "❌ Suche fehlgeschlagen",
+	"🔬 Verification Started":                      "🔬 Prüfung gestartet",
+	"✅ Verification Success":                      "✅ Prüfung erfolgreich",
+	"❌ Verification Failed":                       "❌ Prüfung fehlgeschlagen",
+	"⏰ Download Timeout":                          "⏰ Download-Zeitüberschreitung",
+	"🚫 Import Blocked - Manual Action Required":   "🚫 Import blockiert - Eingreifen erforderlich",
+	"🗑️ Download Manually Removed":                "🗑️ Download manuell entfernt",
+	"⏸️ Download Ignored by User":                 "⏸️ Download vom Benutzer ignoriert",
+	"🔄 Retry Scheduled":                           "🔄 Neuer Versuch geplant",
+	"⚠️ Max Retries Reached":                      "⚠️ Maximale Versuche erreicht",
+	"🔍 No Replacement Found":                      "🔍 Kein Ersatz gefunden",
+	"🗓️ Searching Again Later":                    "🗓️ Spätere erneute Suche",
+	"❌ Download Failed":                           "❌ Download fehlgeschlagen",
+	"🚫 Corrupt Download Blocklisted":              "🚫 Beschädigter Download gesperrt",
+	"⚠️ System Health Degraded":                   "⚠️ Systemzustand beeinträchtigt",
+	"🔴 Arr Instance Unreachable":                  "🔴 Arr-Instanz nicht erreichbar",
+	"🟢 Arr Instance Recovered":                    "🟢 Arr-Instanz wieder erreichbar",
+	"📉 Arr Request Budget Low":                    "📉 Arr-Anfragebudget knapp",
+	"🧭 Path Mapping Drift":                        "🧭 Pfadzuordnung abgewichen",
+	"⏰ Stuck Remediation Detected":                "⏰ Hängende Behebung erkannt",
+	"⌛ Resolution SLA Breached":                   "⌛ Lösungs-SLA überschritten",
+	"⏸️ Remediation Paused - Detection Only":      "⏸️ Behebung pausiert - nur Erkennung",
+	"▶️ Remediation Resumed":                      "▶️ Behebung fortgesetzt",
+	"📶 Data Budget Exceeded":                      "📶 Datenbudget überschritten",
+	"📶 Data Budget Available":                     "📶 Datenbudget verfügbar",
+	"📶 Remediation Deferred":                      "📶 Behebung zurückgestellt",
+	"⏸️ Automation Paused":                        "⏸️ Automatisierung pausiert",
+	"▶️ Automation Resumed":                       "▶️ Automatisierung fortgesetzt",
+	"📶 Budget Approval Required":                  "📶 Budgetfreigabe erforderlich",
+	"🤔 Low Confidence Detection":                  "🤔 Unsichere Erkennung",
+	"📉 Quality Regression":                        "📉 Qualitätsverlust",
+	"🔇 Audio Track Missing":                       "🔇 Tonspur fehlt",
+	"✋ Deletion Stopped - Manual Action Required": "✋ Löschung angehalten - Eingreifen erforderlich",
+	"📊 Weekly Report":                             "📊 Wochenbericht",
+	"🙈 Corruption Ignored by User":                "🙈 Beschädigung vom Benutzer ignoriert",
+}
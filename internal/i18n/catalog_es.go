@@ -0,0 +1,195 @@
+package i18n
+
+// catalogES holds the Spanish translations.
+var catalogES = map[string]string{
+	// API errors
+	"Database error":        "Error de base de datos",
+	"Authentication error":  "Error de autenticación",
+	"Invalid request":       "Solicitud no válida",
+	"Not found":             "No encontrado",
+	"Service unavailable":   "Servicio no disponible",
+	"Internal server error": "Error interno del servidor",
+	"Scan not found":        "Escaneo no encontrado",
+	"Invalid ID":            "ID no válido",
+	"%s not found":          "No encontrado: %s",
+	"%s not available":      "%s no disponible",
+
+	// Request validation
+	"Invalid URL: %v":                               "URL no válida: %v",
+	"%s is required":                                "%s es obligatorio",
+	"%s is required when %s is not set":             "%s es obligatorio si %s no está definido",
+	"%s must not be empty":                          "%s no debe estar vacío",
+	"%s must be at least %s characters":             "%s debe tener al menos %s caracteres",
+	"%s must have at least %s items":                "%s debe tener al menos %s elementos",
+	"%s must be %s or greater":                      "%s debe ser %s o mayor",
+	"%s must be at most %s characters":              "%s debe tener como máximo %s caracteres",
+	"%s must have at most %s items":                 "%s debe tener como máximo %s elementos",
+	"%s must be %s or less":                         "%s debe ser %s o menor",
+	"%s must be greater than %s":                    "%s debe ser mayor que %s",
+	"%s must be less than %s":                       "%s debe ser menor que %s",
+	"%s must be between %s and %s":                  "%s debe estar entre %s y %s",
+	"%s must be one of: %s":                         "%s debe ser uno de: %s",
+	"%s must be a valid URL":                        "%s debe ser una URL válida",
+	"%s must start with %q":                         "%s debe empezar por %q",
+	"%s failed the %s check":                        "%s no superó la comprobación %s",
+	"%s must be %s, not %s":                         "%s debe ser %s, no %s",
+	"a boolean":                                     "un booleano",
+	"an integer":                                    "un entero",
+	"a number":                                      "un número",
+	"a string":                                      "una cadena",
+	"an array":                                      "un array",
+	"an object":                                     "un objeto",
+	"malformed JSON at offset %d":                   "JSON mal formado en la posición %d",
+	"request body is required":                      "el cuerpo de la solicitud es obligatorio",
+	"malformed JSON: unexpected end of input":       "JSON mal formado: fin inesperado de los datos",
+	"Password":                                      "Contraseña",
+	"New password":                                  "Nueva contraseña",
+	"Current password":                              "Contraseña actual",
+	"No scan paths provided":                        "No se indicó ninguna ruta de escaneo",
+	"At most %d scan paths can be imported at once": "Se pueden importar como máximo %d rutas de escaneo a la vez",
+
+	// API resources
+	"Arr client":           "Cliente *arr",
+	"Backup target":        "Destino de copia de seguridad",
+	"Corruption":           "Corrupción",
+	"Database":             "Base de datos",
+	"Deleted instance":     "Instancia eliminada",
+	"Deleted scan path":    "Ruta de escaneo eliminada",
+	"Deletion plan":        "Plan de eliminación",
+	"Delivery":             "Entrega",
+	"Diagnostics":          "Diagnóstico",
+	"False positive":       "Falso positivo",
+	"Instance":             "Instancia",
+	"Mapping overrides":    "Asignaciones de ruta manuales",
+	"Media history":        "Historial del medio",
+	"Notification":         "Notificación",
+	"Notification service": "Servicio de notificaciones",
+	"Path":                 "Ruta",
+	"Path group":           "Grupo de rutas",
+	"Protected item":       "Elemento protegido",
+	"Remediator":           "Servicio de remediación",
+	"Report":               "Informe",
+	"Resolution audit":     "Auditoría de corrupciones resueltas",
+	"Sample":               "Muestra",
+	"Saved filter":         "Filtro guardado",
+	"Scan path":            "Ruta de escaneo",
+	"Scanner":              "Escáner",
+	"Schedule":             "Programación",
+	"Scheduler":            "Programador",
+	"Startup report":       "Informe de inicio",
+	"User":                 "Usuario",
+	"Webhook":              "Webhook",
+	"Webhook outbox":       "Cola de webhooks",
+
+	// Notification messages
+	"🔍 Scan started: %s":                               "🔍 Escaneo iniciado: %s",
+	"✅ Scan complete: %s\n📊 %d/%d healthy, %d corrupt": "✅ Escaneo completado: %s\n📊 %d/%d sanos, %d corruptos",
+	"❌ Scan failed: %s\n⚠️ %s":                         "❌ Escaneo fallido: %s\n⚠️ %s",
+	"⏭️ Scan skipped: %s":                              "⏭️ Escaneo omitido: %s",
+	"🔴 Corruption detected: %s":                        "🔴 Corrupción detectada: %s",
+	"\n📋 Type: %s":                                     "\n📋 Tipo: %s",
+	"\n📋 Reason: %s":                                   "\n📋 Motivo: %s",
+	"🔧 Remediation queued: %s":                         "🔧 Remediación en cola: %s",
+	"🗑️ Deletion started: %s":                          "🗑️ Eliminación iniciada: %s",
+	"✅ File deleted for re-download: %s":               "✅ Archivo eliminado para volver a descargarlo: %s",
+	"❌ Deletion failed: %s\n⚠️ %s":                     "❌ Eliminación fallida: %s\n⚠️ %s",
+	"🔎 Search triggered in *arr: %s":                   "🔎 Búsqueda iniciada en *arr: %s",
+	"✅ Search completed: %s":                           "✅ Búsqueda completada: %s",
+	"❌ Search failed: %s\n⚠️ %s":                       "❌ Búsqueda fallida: %s\n⚠️ %s",
+	"🔬 Verification started: %s":                       "🔬 Verificación iniciada: %s",
+	"✅ File verified healthy: %s":                      "✅ Archivo verificado como sano: %s",
+	"❌ Verification failed: %s\n⚠️ %s":                 "❌ Verificación fallida: %s\n⚠️ %s",
+	"⏰ Download timeout: %s":                           "⏰ Tiempo de descarga agotado: %s",
+	"🚫 Import blocked in *arr: %s\n⚠️ %s\n👉 Manual intervention required in Sonarr/Radarr":       "🚫 Importación bloqueada en *arr: %s\n⚠️ %s\n👉 Se requiere intervención manual en Sonarr/Radarr",
+	"📶 Approval required: %s\n⚠️ %s\n👉 Approve the remediation in Healarr to download it anyway": "📶 Aprobación requerida: %s\n⚠️ %s\n👉 Aprueba la remediación en Healarr para descargarlo de todos modos",
+	"🤔 Unconfirmed corruption: %s\n⚠️ %s\n👉 Retry in Healarr to remediate it, or ignore it":      "🤔 Corrupción no confirmada: %s\n⚠️ %s\n👉 Reinténtalo en Healarr para remediarla, o ignórala",
+	"📉 Quality regression: %s\n⚠️ %s":                                                            "📉 Pérdida de calidad: %s\n⚠️ %s",
+	"🔇 Replacement missing audio track: %s\n⚠️ %s":                                               "🔇 Falta una pista de audio en el reemplazo: %s\n⚠️ %s",
+	"✋ Deletion stopped: %s\n⚠️ %s\n👉 Check the file in *arr, then retry in Healarr":             "✋ Eliminación detenida: %s\n⚠️ %s\n👉 Revisa el archivo en *arr y vuelve a intentarlo en Healarr",
+	"📶 Remediation deferred: %s\n⚠️ %s":                                                          "📶 Remediación aplazada: %s\n⚠️ %s",
+	"\n👉 Goes ahead on %s": "\n👉 Continuará el %s",
+	"🗑️ Download manually removed: %s\n👉 Item was removed from *arr queue without being imported":      "🗑️ Descarga eliminada manualmente: %s\n👉 El elemento se quitó de la cola de *arr sin importarse",
+	"⏸️ Download ignored by user: %s\n👉 User marked download as ignored in *arr - remediation stopped": "⏸️ Descarga ignorada por el usuario: %s\n👉 La descarga se marcó como ignorada en *arr - remediación detenida",
+	"🔄 Retry scheduled (%d/%d): %s":                                                        "🔄 Reintento programado (%d/%d): %s",
+	"⚠️ Max retries exhausted (%d): %s":                                                    "⚠️ Reintentos máximos agotados (%d): %s",
+	"🔍 No replacement found: %s":                                                           "🔍 No se encontró reemplazo: %s",
+	"\n📊 Attempts: %d":                                                                     "\n📊 Intentos: %d",
+	"\n👉 Check your indexers or manually search in Sonarr/Radarr":                          "\n👉 Revisa tus indexadores o busca manualmente en Sonarr/Radarr",
+	"🗓️ No replacement yet: %s":                                                            "🗓️ Aún sin reemplazo: %s",
+	"\n🔍 Searching again on %s":                                                            "\n🔍 Se buscará de nuevo el %s",
+	"❌ Download failed: %s":                                                                "❌ Descarga fallida: %s",
+	"🚫 Corrupt download blocklisted before import: %s":                                     "🚫 Descarga corrupta bloqueada antes de importarse: %s",
+	"🚫 Download blocklisted: %s":                                                           "🚫 Descarga bloqueada: %s",
+	"\n🔍 *arr is searching for another release":                                            "\n🔍 *arr está buscando otra versión",
+	"⚠️ System health degraded":                                                            "⚠️ Estado del sistema degradado",
+	"🔴 Arr instance unreachable":                                                           "🔴 Instancia Arr inaccesible",
+	"🟢 Arr instance recovered":                                                             "🟢 Instancia Arr recuperada",
+	"⌛ Resolution SLA breached":                                                            "⌛ SLA de resolución incumplido",
+	"\n⏱️ Unresolved for %.1fh (SLA: %.1fh)":                                               "\n⏱️ Sin resolver desde hace %.1f h (SLA: %.1f h)",
+	"⏸️ Remediation paused, scanning continues in detection-only mode":                     "⏸️ Remediación en pausa, el escaneo continúa en modo solo detección",
+	"\n👉 New corruptions are queued until the instance recovers":                           "\n👉 Las nuevas corrupciones esperan hasta que la instancia se recupere",
+	"▶️ Remediation resumed":                                                               "▶️ Remediación reanudada",
+	"📉 Daily *arr API request budget nearly used up":                                       "📉 Presupuesto diario de solicitudes a la API de *arr casi agotado",
+	"\n👉 Non-urgent requests wait until tomorrow; remediation continues":                   "\n👉 Las solicitudes no urgentes esperan a mañana; la remediación continúa",
+	"🧭 Scan path no longer matches the *arr root folders":                                  "🧭 La ruta de escaneo ya no coincide con las carpetas raíz de *arr",
+	"\n👉 Remap the path in Healarr's config so its files match *arr media again":           "\n👉 Reasigna la ruta en la configuración de Healarr para que sus archivos vuelvan a coincidir con los medios de *arr",
+	"📶 Monthly remediation data budget used up":                                            "📶 Presupuesto mensual de datos de remediación agotado",
+	"\n👉 New corruptions are queued until next month":                                      "\n👉 Las nuevas corrupciones esperan hasta el próximo mes",
+	"📶 Remediation data budget available again":                                            "📶 Presupuesto de datos de remediación disponible de nuevo",
+	"⏸️ Automation paused":                                                                 "⏸️ Automatización en pausa",
+	"\n👉 Remediation and verification are on hold until automation is resumed":             "\n👉 La remediación y la verificación quedan en espera hasta que se reanude la automatización",
+	"▶️ Automation resumed":                                                                "▶️ Automatización reanudada",
+	"⏰ Stuck remediation detected":                                                         "⏰ Remediación atascada detectada",
+	"\n👉 Remediation has shown no progress - manual check recommended":                     "\n👉 La remediación no avanza - se recomienda una revisión manual",
+	"🙈 Corruption ignored: %s":                                                             "🙈 Corrupción ignorada: %s",
+	"📢 Event: %s":                                                                          "📢 Evento: %s",
+	"🧪 Healarr Test Notification\n✅ Your notification configuration is working correctly!": "🧪 Notificación de prueba de Healarr\n✅ ¡Tu configuración de notificaciones funciona correctamente!",
+
+	// Notification titles
+	"🔴 Corruption Detected":                       "🔴 Corrupción detectada",
+	"🔍 Scan Started":                              "🔍 Escaneo iniciado",
+	"✅ Scan Complete":                             "✅ Escaneo completado",
+	"❌ Scan Failed":                               "❌ Escaneo fallido",
+	"⏭️ Scan Skipped":                             "⏭️ Escaneo omitido",
+	"🔧 Remediation Queued":                        "🔧 Remediación en cola",
+	"🗑️ Deletion Started":                         "🗑️ Eliminación iniciada",
+	"✅ File Deleted":                              "✅ Archivo eliminado",
+	"❌ Deletion Failed":                           "❌ Eliminación fallida",
+	"🔎 Search Triggered":                          "🔎 Búsqueda iniciada",
+	"✅ Search Complete":                           "✅ Búsqueda completada",
+	"❌ Search Failed":                             "❌ Búsqueda fallida",
+	"🔬 Verification Started":                      "🔬 Verificación iniciada",
+	"✅ Verification Success":                      "✅ Verificación correcta",
+	"❌ Verification Failed":                       "❌ Verificación fallida",
+	"⏰ Download Timeout":                          "⏰ Tiempo de descarga agotado",
+	"🚫 Import Blocked - Manual Action Required":   "🚫 Importación bloqueada - se requiere acción manual",
+	"🗑️ Download Manually Removed":                "🗑️ Descarga eliminada manualmente",
+	"⏸️ Download Ignored by User":                 "⏸️ Descarga ignorada por el usuario",
+	"🔄 Retry Scheduled":                           "🔄 Reintento programado",
+	"⚠️ Max Retries Reached":                      "⚠️ Reintentos máximos alcanzados",
+	"🔍 No Replacement Found":                      "🔍 No se encontró reemplazo",
+	"🗓️ Searching Again Later":                    "🗓️ Se buscará de nuevo más tarde",
+	"❌ Download Failed":                           "❌ Descarga fallida",
+	"🚫 Corrupt Download Blocklisted":              "🚫 Descarga corrupta bloqueada",
+	"⚠️ System Health Degraded":                   "⚠️ Estado del sistema degradado",
+	"🔴 Arr Instance Unreachable":                  "🔴 Instancia Arr inaccesible",
+	"🟢 Arr Instance Recovered":                    "🟢 Instancia Arr recuperada",
+	"📉 Arr Request Budget Low":                    "📉 Presupuesto de solicitudes Arr bajo",
+	"🧭 Path Mapping Drift":                        "🧭 Desviación del mapeo de rutas",
+	"⏰ Stuck Remediation Detected":                "⏰ Remediación atascada detectada",
+	"⌛ Resolution SLA Breached":                   "⌛ SLA de resolución incumplido",
+	"⏸️ Remediation Paused - Detection Only":      "⏸️ Remediación en pausa - solo detección",
+	"▶️ Remediation Resumed":                      "▶️ Remediación reanudada",
+	"📶 Data Budget Exceeded":                      "📶 Presupuesto de datos superado",
+	"📶 Data Budget Available":                     "📶 Presupuesto de datos disponible",
+	"📶 Remediation Deferred":                      "📶 Remediación aplazada",
+	"⏸️ Automation Paused":                        "⏸️ Automatización en pausa",
+	"▶️ Automation Resumed":                       "▶️ Automatización reanudada",
+	"📶 Budget Approval Required":                  "📶 Se requiere aprobación del presupuesto",
+	"🤔 Low Confidence Detection":                  "🤔 Detección de baja confianza",
+	"📉 Quality Regression":                        "📉 Pérdida de calidad",
+	"🔇 Audio Track Missing":                       "🔇 Falta pista de audio",
+	"✋ Deletion Stopped - Manual Action Required": "✋ Eliminación detenida - se requiere acción manual",
+	"📊 Weekly Report":                             "📊 Informe semanal",
+	"🙈 Corruption Ignored by User":                "🙈 Corrupción ignorada por el usuario",
+}
@@ -0,0 +1,195 @@
+package i18n
+
+// catalogFR holds the French translations.
+var catalogFR = map[string]string{
+	// API errors
+	"Database error":        "Erreur de base de données",
+	"Authentication error":  "Erreur d'authentification",
+	"Invalid request":       "Requête invalide",
+	"Not found":             "Introuvable",
+	"Service unavailable":   "Service indisponible",
+	"Internal server error": "Erreur interne du serveur",
+	"Scan not found":        "Analyse introuvable",
+	"Invalid ID":            "Identifiant invalide",
+	"%s not found":          "%s introuvable",
+	"%s not available":      "%s indisponible",
+
+	// Request validation
+	"Invalid URL: %v":                               "URL invalide : %v",
+	"%s is required":                                "%s est obligatoire",
+	"%s is required when %s is not set":             "%s est obligatoire si %s n'est pas défini",
+	"%s must not be empty":                          "%s ne doit pas être vide",
+	"%s must be at least %s characters":             "%s doit contenir au moins %s caractères",
+	"%s must have at least %s items":                "%s doit contenir au moins %s éléments",
+	"%s must be %s or greater":                      "%s doit être supérieur ou égal à %s",
+	"%s must be at most %s characters":              "%s doit contenir au plus %s caractères",
+	"%s must have at most %s items":                 "%s doit contenir au plus %s éléments",
+	"%s must be %s or less":                         "%s doit être inférieur ou égal à %s",
+	"%s must be greater than %s":                    "%s doit être supérieur à %s",
+	"%s must be less than %s":                       "%s doit être inférieur à %s",
+	"%s must be between %s and %s":                  "%s doit être compris entre %s et %s",
+	"%s must be one of: %s":                         "%s doit être l'une de ces valeurs : %s",
+	"%s must be a valid URL":                        "%s doit être une URL valide",
+	"%s must start with %q":                         "%s doit commencer par %q",
+	"%s failed the %s check":                        "%s n'a pas passé la vérification %s",
+	"%s must be %s, not %s":                         "%s doit être %s, pas %s",
+	"a boolean":                                     "un booléen",
+	"an integer":                                    "un entier",
+	"a number":                                      "un nombre",
+	"a string":                                      "une chaîne",
+	"an array":                                      "un tableau",
+	"an object":                                     "un objet",
+	"malformed JSON at offset %d":                   "JSON mal formé à la position %d",
+	"request body is required":                      "le corps de la requête est obligatoire",
+	"malformed JSON: unexpected end of input":       "JSON mal formé : fin inattendue des données",
+	"Password":                                      "Mot de passe",
+	"New password":                                  "Nouveau mot de passe",
+	"Current password":                              "Mot de passe actuel",
+	"No scan paths provided":                        "Aucun chemin d'analyse fourni",
+	"At most %d scan paths can be imported at once": "Au plus %d chemins d'analyse peuvent être importés à la fois",
+
+	// API resources
+	"Arr client":           "Client *arr",
+	"Backup target":        "Cible de sauvegarde",
+	"Corruption":           "Corruption",
+	"Database":             "Base de données",
+	"Deleted instance":     "Instance supprimée",
+	"Deleted scan path":    "Chemin d'analyse supprimé",
+	"Deletion plan":        "Plan de suppression",
+	"Delivery":             "Livraison",
+	"Diagnostics":          "Diagnostics",
+	"False positive":       "Faux positif",
+	"Instance":             "Instance",
+	"Mapping overrides":    "Correspondances de chemin manuelles",
+	"Media history":        "Historique du média",
+	"Notification":         "Notification",
+	"Notification service": "Service de notification",
+	"Path":                 "Chemin",
+	"Path group":           "Groupe de chemins",
+	"Protected item":       "Élément protégé",
+	"Remediator":           "Service de remédiation",
+	"Report":               "Rapport",
+	"Resolution audit":     "Audit des corruptions résolues",
+	"Sample":               "Échantillon",
+	"Saved filter":         "Filtre enregistré",
+	"Scan path":            "Chemin d'analyse",
+	"Scanner":              "Scanner",
+	"Schedule":             "Planification",
+	"Scheduler":            "Planificateur",
+	"Startup report":       "Rapport de démarrage",
+	"User":                 "Utilisateur",
+	"Webhook":              "Webhook",
+	"Webhook outbox":       "File d'attente des webhooks",
+
+	// Notification messages
+	"🔍 Scan started: %s":                               "🔍 Analyse démarrée : %s",
+	"✅ Scan complete: %s\n📊 %d/%d healthy, %d corrupt": "✅ Analyse terminée : %s\n📊 %d/%d sains, %d corrompus",
+	"❌ Scan failed: %s\n⚠️ %s":                         "❌ Échec de l'analyse : %s\n⚠️ %s",
+	"⏭️ Scan skipped: %s":                              "⏭️ Analyse ignorée : %s",
+	"🔴 Corruption detected: %s":                        "🔴 Corruption détectée : %s",
+	"\n📋 Type: %s":                                     "\n📋 Type : %s",
+	"\n📋 Reason: %s":                                   "\n📋 Raison : %s",
+	"🔧 Remediation queued: %s":                         "🔧 Remédiation en file d'attente : %s",
+	"🗑️ Deletion started: %s":                          "🗑️ Suppression démarrée : %s",
+	"✅ File deleted for re-download: %s":               "✅ Fichier supprimé pour nouveau téléchargement : %s",
+	"❌ Deletion failed: %s\n⚠️ %s":                     "❌ Échec de la suppression : %s\n⚠️ %s",
+	"🔎 Search triggered in *arr: %s":                   "🔎 Recherche lancée dans *arr : %s",
+	"✅ Search completed: %s":                           "✅ Recherche terminée : %s",
+	"❌ Search failed: %s\n⚠️ %s":                       "❌ Échec de la recherche : %s\n⚠️ %s",
+	"🔬 Verification started: %s":                       "🔬 Vérification démarrée : %s",
+	"✅ File verified healthy: %s":                      "✅ Fichier vérifié sain : %s",
+	"❌ Verification failed: %s\n⚠️ %s":                 "❌ Échec de la vérification : %s\n⚠️ %s",
+	"⏰ Download timeout: %s":                           "⏰ Délai de téléchargement dépassé : %s",
+	"🚫 Import blocked in *arr: %s\n⚠️ %s\n👉 Manual intervention required in Sonarr/Radarr":       "🚫 Import bloqué dans *arr : %s\n⚠️ %s\n👉 Intervention manuelle requise dans Sonarr/Radarr",
+	"📶 Approval required: %s\n⚠️ %s\n👉 Approve the remediation in Healarr to download it anyway": "📶 Approbation requise : %s\n⚠️ %s\n👉 Approuvez la remédiation dans Healarr pour télécharger quand même",
+	"🤔 Unconfirmed corruption: %s\n⚠️ %s\n👉 Retry in Healarr to remediate it, or ignore it":      "🤔 Corruption non confirmée : %s\n⚠️ %s\n👉 Relancez dans Healarr pour y remédier, ou ignorez-la",
+	"📉 Quality regression: %s\n⚠️ %s":                                                            "📉 Baisse de qualité : %s\n⚠️ %s",
+	"🔇 Replacement missing audio track: %s\n⚠️ %s":                                               "🔇 Piste audio manquante dans le remplacement : %s\n⚠️ %s",
+	"✋ Deletion stopped: %s\n⚠️ %s\n👉 Check the file in *arr, then retry in Healarr":             "✋ Suppression arrêtée : %s\n⚠️ %s\n👉 Vérifiez le fichier dans *arr, puis relancez dans Healarr",
+	"📶 Remediation deferred: %s\n⚠️ %s":                                                          "📶 Remédiation reportée : %s\n⚠️ %s",
+	"\n👉 Goes ahead on %s": "\n👉 Reprise le %s",
+	"🗑️ Download manually removed: %s\n👉 Item was removed from *arr queue without being imported":      "🗑️ Téléchargement retiré manuellement : %s\n👉 L'élément a été retiré de la file *arr sans être importé",
+	"⏸️ Download ignored by user: %s\n👉 User marked download as ignored in *arr - remediation stopped": "⏸️ Téléchargement ignoré par l'utilisateur : %s\n👉 Le téléchargement a été marqué comme ignoré dans *arr - remédiation arrêtée",
+	"🔄 Retry scheduled (%d/%d): %s":                                                        "🔄 Nouvelle tentative planifiée (%d/%d) : %s",
+	"⚠️ Max retries exhausted (%d): %s":                                                    "⚠️ Nombre maximal de tentatives atteint (%d) : %s",
+	"🔍 No replacement found: %s":                                                           "🔍 Aucun remplacement trouvé : %s",
+	"\n📊 Attempts: %d":                                                                     "\n📊 Tentatives : %d",
+	"\n👉 Check your indexers or manually search in Sonarr/Radarr":                          "\n👉 Vérifiez vos indexeurs ou lancez une recherche manuelle dans Sonarr/Radarr",
+	"🗓️ No replacement yet: %s":                                                            "🗓️ Pas encore de remplacement : %s",
+	"\n🔍 Searching again on %s":                                                            "\n🔍 Nouvelle recherche le %s",
+	"❌ Download failed: %s":                                                                "❌ Échec du téléchargement : %s",
+	"🚫 Corrupt download blocklisted before import: %s":                                     "🚫 Téléchargement corrompu mis en liste noire avant l'import : %s",
+	"🚫 Download blocklisted: %s":                                                           "🚫 Téléchargement mis en liste noire : %s",
+	"\n🔍 *arr is searching for another release":                                            "\n🔍 *arr recherche une autre version",
+	"⚠️ System health degraded":                                                            "⚠️ État du système dégradé",
+	"🔴 Arr instance unreachable":                                                           "🔴 Instance Arr injoignable",
+	"🟢 Arr instance recovered":                                                             "🟢 Instance Arr rétablie",
+	"⌛ Resolution SLA breached":                                                            "⌛ SLA de résolution dépassé",
+	"\n⏱️ Unresolved for %.1fh (SLA: %.1fh)":                                               "\n⏱️ Non résolu depuis %.1f h (SLA : %.1f h)",
+	"⏸️ Remediation paused, scanning continues in detection-only mode":                     "⏸️ Remédiation en pause, l'analyse continue en mode détection seule",
+	"\n👉 New corruptions are queued until the instance recovers":                           "\n👉 Les nouvelles corruptions attendent le rétablissement de l'instance",
+	"▶️ Remediation resumed":                                                               "▶️ Remédiation reprise",
+	"📉 Daily *arr API request budget nearly used up":                                       "📉 Budget quotidien de requêtes API *arr presque épuisé",
+	"\n👉 Non-urgent requests wait until tomorrow; remediation continues":                   "\n👉 Les requêtes non urgentes attendent demain ; la remédiation continue",
+	"🧭 Scan path no longer matches the *arr root folders":                                  "🧭 Le chemin d'analyse ne correspond plus aux dossiers racine *arr",
+	"\n👉 Remap the path in Healarr's config so its files match *arr media again":           "\n👉 Remappez le chemin dans la configuration de Healarr pour que ses fichiers correspondent de nouveau aux médias *arr",
+	"📶 Monthly remediation data budget used up":                                            "📶 Budget mensuel de données de remédiation épuisé",
+	"\n👉 New corruptions are queued until next month":                                      "\n👉 Les nouvelles corruptions attendent le mois prochain",
+	"📶 Remediation data budget available again":                                            "📶 Budget de données de remédiation de nouveau disponible",
+	"⏸️ Automation paused":                                                                 "⏸️ Automatisation en pause",
+	"\n👉 Remediation and verification are on hold until automation is resumed":             "\n👉 La remédiation et la vérification sont suspendues jusqu'à la reprise de l'automatisation",
+	"▶️ Automation resumed":                                                                "▶️ Automatisation reprise",
+	"⏰ Stuck remediation detected":                                                         "⏰ Remédiation bloquée détectée",
+	"\n👉 Remediation has shown no progress - manual check recommended":                     "\n👉 La remédiation n'avance plus - vérification manuelle recommandée",
+	"🙈 Corruption ignored: %s":                                                             "🙈 Corruption ignorée : %s",
+	"📢 Event: %s":                                                                          "📢 Événement : %s",
+	"🧪 Healarr Test Notification\n✅ Your notification configuration is working correctly!": "🧪 Notification de test Healarr\n✅ Votre configuration de notification fonctionne correctement !",
+
+	// Notification titles
+	"🔴 Corruption Detected":                       "🔴 Corruption détectée",
+	"🔍 Scan Started":                              "🔍 Analyse démarrée",
+	"✅ Scan Complete":                             "✅ Analyse terminée",
+	"❌ Scan Failed":                               "❌ Échec de l'analyse",
+	"⏭️ Scan Skipped":                             "⏭️ Analyse ignorée",
+	"🔧 Remediation Queued":                        "🔧 Remédiation en file d'attente",
+	"🗑️ Deletion Started":                         "🗑️ Suppression démarrée",
+	"✅ File Deleted":                              "✅ Fichier supprimé",
+	"❌ Deletion Failed":                           "❌ Échec de la suppression",
+	"🔎 Search Triggered":                          "🔎 Recherche lancée",
+	"✅ Search Complete":                           "✅ Recherche terminée",
+	"❌ Search Failed":                             "❌ Échec de la recherche",
+	"🔬 Verification Started":                      "🔬 Vérification démarrée",
+	"✅ Verification Success":                      "✅ Vérification réussie",
+	"❌ Verification Failed":                       "❌ Échec de la vérification",
+	"⏰ Download Timeout":                          "⏰ Délai de téléchargement dépassé",
+	"🚫 Import Blocked - Manual Action Required":   "🚫 Import bloqué - action manuelle requise",
+	"🗑️ Download Manually Removed":                "🗑️ Téléchargement retiré manuellement",
+	"⏸️ Download Ignored by User":                 "⏸️ Téléchargement ignoré par l'utilisateur",
+	"🔄 Retry Scheduled":                           "🔄 Nouvelle tentative planifiée",
+	"⚠️ Max Retries Reached":                      "⚠️ Nombre maximal de tentatives atteint",
+	"🔍 No Replacement Found":                      "🔍 Aucun remplacement trouvé",
+	"🗓️ Searching Again Later":                    "🗓️ Nouvelle recherche plus tard",
+	"❌ Download Failed":                           "❌ Échec du téléchargement",
+	"🚫 Corrupt Download Blocklisted":              "🚫 Téléchargement corrompu mis en liste noire",
+	"⚠️ System Health Degraded":                   "⚠️ État du système dégradé",
+	"🔴 Arr Instance Unreachable":                  "🔴 Instance Arr injoignable",
+	"🟢 Arr Instance Recovered":                    "🟢 Instance Arr rétablie",
+	"📉 Arr Request Budget Low":                    "📉 Budget de requêtes Arr faible",
+	"🧭 Path Mapping Drift":                        "🧭 Dérive du mappage de chemins",
+	"⏰ Stuck Remediation Detected":                "⏰ Remédiation bloquée détectée",
+	"⌛ Resolution SLA Breached":                   "⌛ SLA de résolution dépassé",
+	"⏸️ Remediation Paused - Detection Only":      "⏸️ Remédiation en pause - détection seule",
+	"▶️ Remediation Resumed":                      "▶️ Remédiation reprise",
+	"📶 Data Budget Exceeded":                      "📶 Budget de données dépassé",
+	"📶 Data Budget Available":                     "📶 Budget de données disponible",
+	"📶 Remediation Deferred":                      "📶 Remédiation reportée",
+	"⏸️ Automation Paused":                        "⏸️ Automatisation en pause",
+	"▶️ Automation Resumed":                       "▶️ Automatisation reprise",
+	"📶 Budget Approval Required":                  "📶 Approbation du budget requise",
+	"🤔 Low Confidence Detection":                  "🤔 Détection peu fiable",
+	"📉 Quality Regression":                        "📉 Baisse de qualité",
+	"🔇 Audio Track Missing":                       "🔇 Piste audio manquante",
+	"✋ Deletion Stopped - Manual Action Required": "✋ Suppression arrêtée - action manuelle requise",
+	"📊 Weekly Report":                             "📊 Rapport hebdomadaire",
+	"🙈 Corruption Ignored by User":                "🙈 Corruption ignorée par l'utilisateur",
+}
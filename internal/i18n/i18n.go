@@ -0,0 +1,138 @@
+// Package i18n translates the user-facing strings of notifications and API
+// errors. Messages are looked up by their English text, so code keeps reading
+// like English and a string without a translation is sent in English.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Supported locales.
+const (
+	English = "en"
+	German  = "de"
+	French  = "fr"
+	Spanish = "es"
+)
+
+// ErrUnsupportedLocale is returned for a locale without a catalog.
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// catalogs maps the English text of a message to its translation, per
+// locale. English has no catalog.
+var catalogs = map[string]map[string]string{
+	German:  catalogDE,
+	French:  catalogFR,
+	Spanish: catalogES,
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLocale = English
+)
+
+// Locales returns the supported locales, English first.
+func Locales() []string {
+	return []string{English, German, French, Spanish}
+}
+
+// Normalize returns the supported locale of a language tag, e.g. "de" for
+// "de-AT" or "DE_de". An empty tag is valid and means the default locale.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" || tag == English {
+		return tag, true
+	}
+	_, ok := catalogs[tag]
+	return tag, ok
+}
+
+// SetDefault sets the locale used where none is chosen, e.g. for notification
+// channels without their own.
+func SetDefault(locale string) error {
+	l, ok := Normalize(locale)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+	if l == "" {
+		l = English
+	}
+	defaultMu.Lock()
+	defaultLocale = l
+	defaultMu.Unlock()
+	return nil
+}
+
+// Default returns the default locale.
+func Default() string {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLocale
+}
+
+// Printer translates messages into one locale.
+type Printer struct {
+	locale  string
+	catalog map[string]string
+}
+
+// For returns the printer of locale, or of the default locale if locale is
+// empty or unsupported.
+func For(locale string) Printer {
+	l, ok := Normalize(locale)
+	if !ok || l == "" {
+		l = Default()
+	}
+	return Printer{locale: l, catalog: catalogs[l]}
+}
+
+// Locale returns the locale p translates into.
+func (p Printer) Locale() string {
+	if p.locale == "" {
+		return English
+	}
+	return p.locale
+}
+
+// T returns the translation of msg, or msg if it has none.
+func (p Printer) T(msg string) string {
+	if t, ok := p.catalog[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format with args.
+func (p Printer) Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(p.T(format), args...)
+}
+
+// FromAcceptLanguage returns the supported locale an Accept-Language header
+// prefers most, or "" if it names none.
+func FromAcceptLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		locale, ok := Normalize(tag)
+		if !ok || locale == "" || q <= bestQ {
+			continue
+		}
+		best, bestQ = locale, q
+	}
+	return best
+}
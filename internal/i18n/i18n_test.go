@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func TestCatalogs_Complete(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key := range catalogDE {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: missing translation of %q", locale, key)
+			}
+		}
+		if len(catalog) != len(catalogDE) {
+			t.Errorf("%s: %d translations, want %d", locale, len(catalog), len(catalogDE))
+		}
+		for key, msg := range catalog {
+			want := verbPattern.FindAllString(key, -1)
+			got := verbPattern.FindAllString(msg, -1)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", locale, msg, got, want)
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"", "", true},
+		{"en", "en", true},
+		{"de-AT", "de", true},
+		{" FR_ca ", "fr", true},
+		{"es", "es", true},
+		{"it", "it", false},
+	}
+	for _, tt := range tests {
+		got, ok := Normalize(tt.tag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"de-DE,de;q=0.9,en;q=0.8", "de"},
+		{"it-IT,it;q=0.9,fr;q=0.5,es;q=0.7", "es"},
+		{"en-US", "en"},
+		{"it, ja;q=0.5", ""},
+		{"fr;q=bad, es;q=0.1", "es"},
+	}
+	for _, tt := range tests {
+		if got := FromAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	var zero Printer
+	if got := zero.Sprintf("%s not found", "Scan path"); got != "Scan path not found" {
+		t.Errorf("zero printer: %q", got)
+	}
+
+	de := For("de")
+	if de.Locale() != German {
+		t.Errorf("Locale() = %q", de.Locale())
+	}
+	if got := de.Sprintf("%s not found", de.T("Scan path")); got != "Scanpfad nicht gefunden" {
+		t.Errorf("German: %q", got)
+	}
+	if got := de.T("Untranslated message"); got != "Untranslated message" {
+		t.Errorf("untranslated: %q", got)
+	}
+
+	if got := For("it").Locale(); got != English {
+		t.Errorf("unsupported locale printer: %q", got)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { _ = SetDefault(English) })
+
+	if err := SetDefault("xx"); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+	if err := SetDefault("fr-FR"); err != nil {
+		t.Fatal(err)
+	}
+	if Default() != French {
+		t.Errorf("Default() = %q", Default())
+	}
+	if got := For("").Locale(); got != French {
+		t.Errorf("For(\"\") = %q, want the default", got)
+	}
+	if got := For("es").Locale(); got != Spanish {
+		t.Errorf("For(\"es\") = %q", got)
+	}
+}
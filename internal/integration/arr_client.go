@@ -11,6 +11,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,6 +21,7 @@ import (
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // Arr instance type constants
@@ -81,25 +84,71 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	}
 }
 
+// SetLimits changes the rate and burst size. Tokens beyond the new burst are dropped.
+func (r *RateLimiter) SetLimits(rps float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refillRate = rps
+	r.maxTokens = float64(burst)
+	if r.tokens > r.maxTokens {
+		r.tokens = r.maxTokens
+	}
+}
+
 // HTTPArrClient implements ArrClient for communicating with Sonarr/Radarr APIs.
 type HTTPArrClient struct {
 	db              *sql.DB
 	httpClient      *http.Client
 	rateLimiter     *RateLimiter
 	circuitBreakers *CircuitBreakerRegistry
+	budgets         *RequestBudgets
 }
 
 // NewArrClient creates an HTTPArrClient with rate limiting and circuit breaker support.
 func NewArrClient(db *sql.DB) *HTTPArrClient {
 	cfg := config.Get()
-	return &HTTPArrClient{
+	c := &HTTPArrClient{
 		db: db,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 		rateLimiter:     NewRateLimiter(cfg.ArrRateLimitRPS, cfg.ArrRateLimitBurst),
 		circuitBreakers: NewCircuitBreakerRegistry(DefaultCircuitBreakerConfig()),
+		budgets:         NewRequestBudgets(db),
+	}
+	c.registerAPIKeys()
+	return c
+}
+
+// registerAPIKeys registers the API keys of the stored instances for
+// redaction. Keys of instances added or changed later are registered by the
+// API when they are saved.
+func (c *HTTPArrClient) registerAPIKeys() {
+	if c.db == nil {
+		return
+	}
+	rows, err := c.db.Query("SELECT id, api_key FROM arr_instances WHERE deleted_at IS NULL")
+	if err != nil {
+		logger.Errorf("Failed to load API keys for redaction: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var id int64
+		var encryptedKey string
+		if rows.Scan(&id, &encryptedKey) != nil {
+			continue
+		}
+		decryptedKey, err := crypto.Decrypt(encryptedKey)
+		if err != nil {
+			logger.Errorf("Failed to decrypt API key for instance %d: %v", id, err)
+			continue
+		}
+		keys = append(keys, decryptedKey)
 	}
+	redact.Register(keys...)
 }
 
 // GetCircuitBreakerStats returns statistics for all circuit breakers.
@@ -118,6 +167,35 @@ func (c *HTTPArrClient) ResetAllCircuitBreakers() {
 	c.circuitBreakers.ResetAll()
 }
 
+// SetRateLimit changes the rate limit of requests to the *arr instances.
+func (c *HTTPArrClient) SetRateLimit(rps float64, burst int) {
+	c.rateLimiter.SetLimits(rps, burst)
+}
+
+// RequestBudgets returns the daily request budgets of the *arr instances.
+func (c *HTTPArrClient) RequestBudgets() *RequestBudgets {
+	return c.budgets
+}
+
+// SetTransport replaces the transport of requests to the *arr instances, e.g.
+// with a ReplayTransport in tests. Call before the client is used.
+func (c *HTTPArrClient) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// EnableRecording records sanitized *arr API requests and responses as
+// fixtures in dir, for tests with a ReplayTransport. For debugging only: it
+// slows requests down and writes response data to disk. Call before the
+// client is used.
+func (c *HTTPArrClient) EnableRecording(dir string) error {
+	rt, err := NewRecordingTransport(dir, c.httpClient.Transport)
+	if err != nil {
+		return err
+	}
+	c.httpClient.Transport = rt
+	return nil
+}
+
 // ArrInstance represents a configured Sonarr or Radarr instance.
 type ArrInstance struct {
 	ID     int64
@@ -138,6 +216,25 @@ type MediaItem struct {
 type ParseResult struct {
 	Movie  *MediaItem `json:"movie"`  // For Radarr
 	Series *MediaItem `json:"series"` // For Sonarr
+
+	// What the *arr read from the path, also when it matched nothing in the library
+	ParsedMovieInfo   *ParsedMovieInfo   `json:"parsedMovieInfo"`   // For Radarr
+	ParsedEpisodeInfo *ParsedEpisodeInfo `json:"parsedEpisodeInfo"` // For Sonarr
+	Episodes          []Episode          `json:"episodes"`          // For Sonarr, the episodes the path names
+}
+
+// ParsedMovieInfo is the title and year Radarr parsed from a path.
+type ParsedMovieInfo struct {
+	MovieTitles []string `json:"movieTitles"`
+	Year        int      `json:"year"`
+}
+
+// ParsedEpisodeInfo is the series title and year Sonarr parsed from a path.
+type ParsedEpisodeInfo struct {
+	SeriesTitle     string `json:"seriesTitle"`
+	SeriesTitleInfo struct {
+		Year int `json:"year"`
+	} `json:"seriesTitleInfo"`
 }
 
 // MovieFile represents a movie file in Radarr
@@ -238,7 +335,7 @@ func normalizedPathLength(rootPath string) int {
 }
 
 func (c *HTTPArrClient) getInstanceForPath(arrPath string) (*ArrInstance, error) {
-	rows, err := c.db.Query("SELECT i.id, i.name, i.type, i.url, i.api_key, sp.arr_path FROM arr_instances i JOIN scan_paths sp ON sp.arr_instance_id = i.id WHERE i.enabled = 1")
+	rows, err := c.db.Query("SELECT i.id, i.name, i.type, i.url, i.api_key, sp.arr_path FROM arr_instances i JOIN scan_paths sp ON sp.arr_instance_id = i.id WHERE i.enabled = 1 AND i.deleted_at IS NULL AND sp.deleted_at IS NULL")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query instances: %w", err)
 	}
@@ -321,6 +418,16 @@ func (c *HTTPArrClient) doRequest(instance *ArrInstance, method, endpoint string
 	return c.doRequestWithRetry(instance, method, endpoint, bodyData, 3)
 }
 
+// doDeferrableRequest is doRequest for non-urgent requests, e.g. listing
+// fallbacks and history polls. It fails with ErrRequestDeferred while the
+// instance's daily request budget is nearly used up.
+func (c *HTTPArrClient) doDeferrableRequest(instance *ArrInstance, method, endpoint string, bodyData interface{}) (*http.Response, error) {
+	if err := c.budgets.Defer(instance); err != nil {
+		return nil, err
+	}
+	return c.doRequest(instance, method, endpoint, bodyData)
+}
+
 // retryAction represents the action to take after a retry attempt
 type retryAction int
 
@@ -363,17 +470,20 @@ func handleServerError(resp *http.Response, cb *CircuitBreaker, instance *ArrIns
 		logger.Warnf("*arr API %s returned %d after %d attempts - recording circuit breaker failure", instance.Name, resp.StatusCode, maxRetries)
 	}
 
-	// Drain and close body to allow connection reuse
-	_, _ = io.Copy(io.Discard, resp.Body)
-	_ = resp.Body.Close()
-
 	if !isLastAttempt {
+		// Drain and close body to allow connection reuse
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
 		logger.Infof("*arr API returned %d, retrying (%d/%d)...", resp.StatusCode, attempt+1, maxRetries)
 		time.Sleep(time.Duration(attempt+1) * 2 * time.Second)
 		return retryActionContinue, nil
 	}
 
-	return retryActionReturn, fmt.Errorf("*arr API returned %d after %d attempts", resp.StatusCode, maxRetries)
+	// Keep the start of the body, it says what went wrong
+	respErr := newArrResponseError(fmt.Sprintf("*arr API returned %d after %d attempts", resp.StatusCode, maxRetries), resp.StatusCode, resp.Body)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return retryActionReturn, respErr
 }
 
 // doRequestWithRetry performs an HTTP request with automatic retry for transient errors.
@@ -417,6 +527,7 @@ func (c *HTTPArrClient) executeAttempt(instance *ArrInstance, method, endpoint s
 	if err != nil {
 		return nil, err, true
 	}
+	c.budgets.Record(instance)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -455,8 +566,9 @@ func (c *HTTPArrClient) handleRequestSuccess(resp *http.Response, cb *CircuitBre
 	return resp, nil, true
 }
 
-// tryParseMedia attempts to find media ID using the parse API endpoint
-func (c *HTTPArrClient) tryParseMedia(instance *ArrInstance, path string) (int64, bool) {
+// tryParseMedia attempts to find media ID using the parse API endpoint. The
+// parse result is returned even without a match, for findMediaByLookup.
+func (c *HTTPArrClient) tryParseMedia(instance *ArrInstance, path string) (int64, *ParseResult, bool) {
 	logger.Debugf("Parsing path with %s: %s", instance.Type, path)
 	encodedPath := url.QueryEscape(path)
 	endpoint := fmt.Sprintf("/api/v3/parse?path=%s", encodedPath)
@@ -467,25 +579,25 @@ func (c *HTTPArrClient) tryParseMedia(instance *ArrInstance, path string) (int64
 			_, _ = io.Copy(io.Discard, resp.Body)
 			_ = resp.Body.Close()
 		}
-		return 0, false
+		return 0, nil, false
 	}
 	defer resp.Body.Close()
 
 	var result ParseResult
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		logger.Debugf("Failed to decode parse response from %s: %v", instance.Type, err)
-		return 0, false
+		return 0, nil, false
 	}
 
 	if isMovieType(instance) && result.Movie != nil {
 		logger.Infof("Found movie via parse: %s (ID: %d)", result.Movie.Title, result.Movie.ID)
-		return result.Movie.ID, true
+		return result.Movie.ID, &result, true
 	}
 	if isSeriesType(instance) && result.Series != nil {
 		logger.Infof("Found series via parse: %s (ID: %d)", result.Series.Title, result.Series.ID)
-		return result.Series.ID, true
+		return result.Series.ID, &result, true
 	}
-	return 0, false
+	return 0, &result, false
 }
 
 // matchMediaItem checks if a media item matches the given file path
@@ -506,9 +618,10 @@ func matchMediaItem(item MediaItem, path, fileDirBase, showDirBase string) bool
 	return strings.HasPrefix(normalizedFilePath, normalizedMediaPath+"/")
 }
 
-// findMediaByListing lists all media and finds a match by path
+// findMediaByListing lists all media and finds a match by path. On large
+// libraries this is a big response, so it is the last resort.
 func (c *HTTPArrClient) findMediaByListing(instance *ArrInstance, path string) (int64, error) {
-	logger.Infof("Parse failed, falling back to listing all media for %s", instance.Type)
+	logger.Infof("Parse and lookup failed, falling back to listing all media for %s", instance.Type)
 
 	var listEndpoint string
 	if isMovieType(instance) {
@@ -517,7 +630,7 @@ func (c *HTTPArrClient) findMediaByListing(instance *ArrInstance, path string) (
 		listEndpoint = "/api/v3/series"
 	}
 
-	resp, err := c.doRequest(instance, "GET", listEndpoint, nil)
+	resp, err := c.doDeferrableRequest(instance, "GET", listEndpoint, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -532,6 +645,16 @@ func (c *HTTPArrClient) findMediaByListing(instance *ArrInstance, path string) (
 		return 0, err
 	}
 
+	if item, ok := findMediaItemForPath(items, path); ok {
+		logger.Infof("Matched media: %s (ID: %d)", item.Title, item.ID)
+		return item.ID, nil
+	}
+
+	return 0, fmt.Errorf("media not found for path: %s", path)
+}
+
+// findMediaItemForPath returns the media item whose folder holds path.
+func findMediaItemForPath(items []MediaItem, path string) (MediaItem, bool) {
 	// Precompute path components for matching
 	fileDir := filepath.Dir(path)
 	fileDirBase := filepath.Base(fileDir)
@@ -540,12 +663,10 @@ func (c *HTTPArrClient) findMediaByListing(instance *ArrInstance, path string) (
 
 	for _, item := range items {
 		if matchMediaItem(item, path, fileDirBase, showDirBase) {
-			logger.Infof("Matched media: %s (ID: %d)", item.Title, item.ID)
-			return item.ID, nil
+			return item, true
 		}
 	}
-
-	return 0, fmt.Errorf("media not found for path: %s", path)
+	return MediaItem{}, false
 }
 
 func (c *HTTPArrClient) FindMediaByPath(path string) (int64, error) {
@@ -555,10 +676,19 @@ func (c *HTTPArrClient) FindMediaByPath(path string) (int64, error) {
 	}
 
 	// Try parse API first
-	if mediaID, found := c.tryParseMedia(instance, path); found {
+	mediaID, parsed, found := c.tryParseMedia(instance, path)
+	if found {
 		return mediaID, nil
 	}
 
+	// Then search for the parsed title, much smaller than a full listing. A
+	// failed parse request means the *arr has trouble; don't add to it.
+	if parsed != nil {
+		if mediaID, found = c.findMediaByLookup(instance, path, parsed); found {
+			return mediaID, nil
+		}
+	}
+
 	// Fallback to listing all media
 	return c.findMediaByListing(instance, path)
 }
@@ -588,8 +718,28 @@ func getAPIVersion(instance *ArrInstance) string {
 
 // genericFile represents a file from the arr API with minimal fields
 type genericFile struct {
-	ID   int64  `json:"id"`
-	Path string `json:"path"`
+	ID        int64          `json:"id"`
+	Path      string         `json:"path"`
+	Size      int64          `json:"size"`
+	Quality   fileQuality    `json:"quality"`
+	MediaInfo *fileMediaInfo `json:"mediaInfo"`
+}
+
+// fileMediaInfo is the part of a file's media info the *arr instance read on
+// import that replacements are compared against. AudioLanguages is a
+// "/"-separated list, AudioChannels a layout such as 5.1.
+type fileMediaInfo struct {
+	AudioLanguages string  `json:"audioLanguages"`
+	AudioChannels  float64 `json:"audioChannels"`
+}
+
+// fileQuality is the quality the *arr instance assigned to a file.
+// Resolution is 0 for audio files and qualities without one.
+type fileQuality struct {
+	Quality struct {
+		Name       string `json:"name"`
+		Resolution int    `json:"resolution"`
+	} `json:"quality"`
 }
 
 // getFilesForMedia fetches all files associated with a media item
@@ -620,15 +770,15 @@ func (c *HTTPArrClient) getFilesForMedia(instance *ArrInstance, mediaID int64) (
 	return files, nil
 }
 
-// findFileIDByBasename finds a file ID by matching the basename of the path
-func findFileIDByBasename(files []genericFile, path string) int64 {
+// findFileByBasename finds a file by matching the basename of the path
+func findFileByBasename(files []genericFile, path string) *genericFile {
 	targetBase := filepath.Base(path)
-	for _, f := range files {
-		if filepath.Base(f.Path) == targetBase {
-			return f.ID
+	for i := range files {
+		if filepath.Base(files[i].Path) == targetBase {
+			return &files[i]
 		}
 	}
-	return 0
+	return nil
 }
 
 // collectEpisodeMetadata fetches episode IDs for a given file ID in Sonarr/Whisparr
@@ -703,24 +853,32 @@ func (c *HTTPArrClient) deleteFileByID(instance *ArrInstance, fileID int64) erro
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete file: %s", resp.Status)
+		return newArrResponseError(fmt.Sprintf("failed to delete file: %s", resp.Status), resp.StatusCode, resp.Body)
 	}
 	return nil
 }
 
+// FileNotInArrError is returned by DeleteFile when the *arr instance doesn't
+// track a file that still exists on disk, e.g. an unmatched or extra file.
+// Metadata holds what a search for the media's missing files needs.
+type FileNotInArrError struct {
+	ArrType  string
+	Path     string
+	Metadata map[string]interface{}
+}
+
+func (e *FileNotInArrError) Error() string {
+	return fmt.Sprintf("file not found in %s but exists on disk: %s", e.ArrType, e.Path)
+}
+
 // handleFileNotInArr handles the case where a file is not found in the arr instance
 func (c *HTTPArrClient) handleFileNotInArr(instance *ArrInstance, mediaID int64, path string) (map[string]interface{}, error) {
 	// Check if file exists on disk
-	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
-		return nil, fmt.Errorf("file not found in %s but exists on disk: %s", instance.Type, path)
-	}
-
-	// File is gone from both arr and disk - treat as already deleted
-	logger.Infof("File already deleted (not in %s and not on disk): %s", instance.Type, path)
+	_, statErr := os.Stat(path)
+	onDisk := !os.IsNotExist(statErr)
 
 	metadata := map[string]interface{}{
-		"deleted_path":    path,
-		"already_deleted": true,
+		"deleted_path": path,
 	}
 
 	if isSeriesType(instance) {
@@ -735,6 +893,13 @@ func (c *HTTPArrClient) handleFileNotInArr(instance *ArrInstance, mediaID int64,
 		metadata["movie_id"] = mediaID
 	}
 
+	if onDisk {
+		return nil, &FileNotInArrError{ArrType: instance.Type, Path: path, Metadata: metadata}
+	}
+
+	// File is gone from both arr and disk - treat as already deleted
+	logger.Infof("File already deleted (not in %s and not on disk): %s", instance.Type, path)
+	metadata["already_deleted"] = true
 	return metadata, nil
 }
 
@@ -773,14 +938,29 @@ func (c *HTTPArrClient) DeleteFile(mediaID int64, path string) (map[string]inter
 		return nil, err
 	}
 
-	// Find file ID by basename
-	fileID := findFileIDByBasename(files, path)
-	if fileID == 0 {
+	// Find file by basename
+	file := findFileByBasename(files, path)
+	if file == nil || file.ID == 0 {
 		return c.handleFileNotInArr(instance, mediaID, path)
 	}
+	fileID := file.ID
 
-	// Build metadata before deletion
+	// Build metadata before deletion, including the quality and audio replacements are compared against
 	metadata := c.buildDeleteMetadata(instance, mediaID, fileID, path)
+	if file.Quality.Quality.Name != "" {
+		metadata["quality"] = file.Quality.Quality.Name
+	}
+	if file.Quality.Quality.Resolution > 0 {
+		metadata["quality_resolution"] = file.Quality.Quality.Resolution
+	}
+	if info := file.MediaInfo; info != nil {
+		if languages := ParseArrLanguages(info.AudioLanguages); len(languages) > 0 {
+			metadata["audio_languages"] = languages
+		}
+		if info.AudioChannels > 0 {
+			metadata["audio_channels"] = ArrChannelCount(info.AudioChannels)
+		}
+	}
 
 	// Delete the file
 	logger.Infof("Deleting file ID %d from %s", fileID, instance.Type)
@@ -792,6 +972,86 @@ func (c *HTTPArrClient) DeleteFile(mediaID int64, path string) (map[string]inter
 	return metadata, nil
 }
 
+// PlanDeletion implements ArrClient interface - looks up the file record
+// DeleteFile would delete, the same way, without deleting it.
+func (c *HTTPArrClient) PlanDeletion(mediaID int64, path string) (*DeletionPlan, error) {
+	instance, err := c.getInstanceForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := c.getFilesForMedia(instance, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DeletionPlan{InstanceID: instance.ID, MediaID: mediaID, Files: []ArrFileRecord{}}
+	if file := findFileByBasename(files, path); file != nil && file.ID != 0 {
+		plan.Files = append(plan.Files, ArrFileRecord{ID: file.ID, Path: file.Path, Size: file.Size})
+	}
+	return plan, nil
+}
+
+// FindSearchMetadata implements ArrClient interface - looks up the metadata
+// DeleteFile would record for the file at path, for a search on an instance
+// whose IDs differ from those recorded on deletion, e.g. after a library
+// moved to another instance. A file the instance no longer has is matched
+// to the episodes its name refers to.
+func (c *HTTPArrClient) FindSearchMetadata(mediaID int64, path string) (map[string]interface{}, error) {
+	instance, err := c.getInstanceForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := c.getFilesForMedia(instance, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if file := findFileByBasename(files, path); file != nil && file.ID != 0 {
+		return c.buildDeleteMetadata(instance, mediaID, file.ID, path), nil
+	}
+
+	metadata := map[string]interface{}{
+		"deleted_path": path,
+	}
+	switch {
+	case isSeriesType(instance):
+		episodeIDs, err := c.findEpisodesForPath(instance, mediaID, path)
+		if err != nil {
+			return nil, err
+		}
+		metadata["episode_ids"] = episodeIDs
+	case isAudioType(instance):
+		metadata["artist_id"] = mediaID
+	default:
+		metadata["movie_id"] = mediaID
+	}
+	return metadata, nil
+}
+
+// findEpisodesForPath returns the episodes of a series a path names, as
+// parsed by the instance, or else its season's missing episodes.
+func (c *HTTPArrClient) findEpisodesForPath(instance *ArrInstance, seriesID int64, path string) ([]int64, error) {
+	if _, parsed, found := c.tryParseMedia(instance, path); found && parsed.Series.ID == seriesID {
+		var episodeIDs []int64
+		for _, ep := range parsed.Episodes {
+			episodeIDs = append(episodeIDs, ep.ID)
+		}
+		if len(episodeIDs) > 0 {
+			return episodeIDs, nil
+		}
+	}
+
+	episodeIDs, err := c.findMissingEpisodesForPath(instance, seriesID, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(episodeIDs) == 0 {
+		return nil, fmt.Errorf("no episodes of series %d found for %s", seriesID, path)
+	}
+	return episodeIDs, nil
+}
+
 // extractSeasonFromPath tries to determine the season number from a path.
 // Returns -1 if no season could be determined.
 func extractSeasonFromPath(path string) int {
@@ -1133,9 +1393,203 @@ func (c *HTTPArrClient) TriggerSearch(mediaID int64, path string, episodeIDs []i
 	return nil
 }
 
+// buildRescanPayload creates the command that makes an instance rescan the
+// folder of a movie, series or artist from disk.
+func buildRescanPayload(instance *ArrInstance, mediaID int64) map[string]interface{} {
+	switch {
+	case isMovieType(instance):
+		return map[string]interface{}{"name": "RescanMovie", "movieId": int(mediaID)}
+	case isAudioType(instance):
+		return map[string]interface{}{"name": "RefreshArtist", "artistId": int(mediaID)}
+	default:
+		return map[string]interface{}{"name": "RescanSeries", "seriesId": int(mediaID)}
+	}
+}
+
+// RescanMedia makes the instance owning path rescan the folder of a media item,
+// so it notices files that were added or removed outside of it.
+func (c *HTTPArrClient) RescanMedia(mediaID int64, path string) error {
+	instance, err := c.getInstanceForPath(path)
+	if err != nil {
+		return err
+	}
+
+	payload := buildRescanPayload(instance, mediaID)
+	logger.Infof("Triggering %s for media ID %d on %s", payload["name"], mediaID, instance.Type)
+
+	resp, err := c.doRequest(instance, "POST", getAPIVersion(instance)+"/command", payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to trigger rescan: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// releaseResource is a release as returned by the *arr release endpoint.
+type releaseResource struct {
+	GUID              string            `json:"guid"`
+	IndexerID         int64             `json:"indexerId"`
+	Indexer           string            `json:"indexer"`
+	Title             string            `json:"title"`
+	Quality           fileQuality       `json:"quality"`
+	Size              int64             `json:"size"`
+	AgeHours          float64           `json:"ageHours"`
+	Protocol          string            `json:"protocol"`
+	Seeders           *int              `json:"seeders"`
+	Leechers          *int              `json:"leechers"`
+	CustomFormatScore int               `json:"customFormatScore"`
+	Approved          bool              `json:"approved"`
+	DownloadAllowed   bool              `json:"downloadAllowed"`
+	Rejections        []json.RawMessage `json:"rejections"`
+}
+
+// toRelease converts a release resource. Rejections are plain strings in most
+// *arr versions and objects with a reason in some; both are kept as text.
+func (r releaseResource) toRelease() Release {
+	release := Release{
+		GUID:              r.GUID,
+		IndexerID:         r.IndexerID,
+		Indexer:           r.Indexer,
+		Title:             r.Title,
+		Quality:           r.Quality.Quality.Name,
+		Size:              r.Size,
+		AgeHours:          r.AgeHours,
+		Protocol:          r.Protocol,
+		Seeders:           r.Seeders,
+		Leechers:          r.Leechers,
+		CustomFormatScore: r.CustomFormatScore,
+		Approved:          r.Approved,
+		DownloadAllowed:   r.DownloadAllowed,
+		Rejections:        make([]string, 0, len(r.Rejections)),
+	}
+	for _, raw := range r.Rejections {
+		var text string
+		if json.Unmarshal(raw, &text) != nil {
+			var obj struct {
+				Reason string `json:"reason"`
+			}
+			if json.Unmarshal(raw, &obj) != nil || obj.Reason == "" {
+				continue
+			}
+			text = obj.Reason
+		}
+		release.Rejections = append(release.Rejections, text)
+	}
+	return release
+}
+
+// releaseQueries returns the release endpoint queries that cover a media item:
+// one per episode (or album for Lidarr), otherwise the whole movie, season or artist.
+func releaseQueries(instance *ArrInstance, mediaID int64, arrPath string, episodeIDs []int64) ([]string, error) {
+	switch {
+	case isMovieType(instance):
+		return []string{fmt.Sprintf("movieId=%d", mediaID)}, nil
+	case isAudioType(instance):
+		if len(episodeIDs) == 0 {
+			return []string{fmt.Sprintf("artistId=%d", mediaID)}, nil
+		}
+		queries := make([]string, 0, len(episodeIDs))
+		for _, id := range episodeIDs {
+			queries = append(queries, fmt.Sprintf("albumId=%d", id))
+		}
+		return queries, nil
+	default:
+		if len(episodeIDs) == 0 {
+			season := extractSeasonFromPath(arrPath)
+			if season < 0 {
+				return nil, fmt.Errorf("no episode IDs or season for series %d", mediaID)
+			}
+			return []string{fmt.Sprintf("seriesId=%d&seasonNumber=%d", mediaID, season)}, nil
+		}
+		queries := make([]string, 0, len(episodeIDs))
+		for _, id := range episodeIDs {
+			queries = append(queries, fmt.Sprintf("episodeId=%d", id))
+		}
+		return queries, nil
+	}
+}
+
+// GetReleases runs an interactive search on the instance owning arrPath and
+// returns the releases its indexers offer for the media item, without grabbing
+// any. For several episodes or albums the results are merged.
+func (c *HTTPArrClient) GetReleases(mediaID int64, arrPath string, episodeIDs []int64) ([]Release, error) {
+	instance, err := c.getInstanceForPath(arrPath)
+	if err != nil {
+		return nil, err
+	}
+	queries, err := releaseQueries(instance, mediaID, arrPath, episodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []Release{}
+	seen := make(map[string]bool)
+	for _, query := range queries {
+		resp, err := c.doRequest(instance, "GET", getAPIVersion(instance)+"/release?"+query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search releases: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to search releases: %s", resp.Status)
+		}
+		var resources []releaseResource
+		err = json.NewDecoder(resp.Body).Decode(&resources)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode releases: %w", err)
+		}
+		for _, r := range resources {
+			key := fmt.Sprintf("%d/%s", r.IndexerID, r.GUID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			releases = append(releases, r.toRelease())
+		}
+	}
+	return releases, nil
+}
+
+// GrabRelease makes the instance owning arrPath download a release found by
+// an earlier GetReleases call. The instance only knows releases from its recent
+// searches, so guid and indexerID must come from one.
+func (c *HTTPArrClient) GrabRelease(arrPath, guid string, indexerID int64) (*Release, error) {
+	instance, err := c.getInstanceForPath(arrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Grabbing release %s from indexer %d on %s", guid, indexerID, instance.Type)
+	payload := map[string]interface{}{"guid": guid, "indexerId": indexerID}
+	resp, err := c.doRequest(instance, "POST", getAPIVersion(instance)+"/release", payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grab release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to grab release: %s", resp.Status)
+	}
+
+	var resource releaseResource
+	if err := json.NewDecoder(resp.Body).Decode(&resource); err != nil {
+		// The grab went through; only the echoed release couldn't be read
+		logger.Debugf("Failed to decode grabbed release %s: %v", guid, err)
+		return &Release{GUID: guid, IndexerID: indexerID}, nil
+	}
+	release := resource.toRelease()
+	return &release, nil
+}
+
 // getAllInstancesInternal returns all enabled *arr instances (internal use)
 func (c *HTTPArrClient) getAllInstancesInternal() ([]*ArrInstance, error) {
-	rows, err := c.db.Query("SELECT id, name, type, url, api_key FROM arr_instances WHERE enabled = 1")
+	rows, err := c.db.Query("SELECT id, name, type, url, api_key FROM arr_instances WHERE enabled = 1 AND deleted_at IS NULL")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query instances: %w", err)
 	}
@@ -1168,7 +1622,7 @@ func (c *HTTPArrClient) getAllInstancesInternal() ([]*ArrInstance, error) {
 func (c *HTTPArrClient) getInstanceByIDInternal(id int64) (*ArrInstance, error) {
 	var i ArrInstance
 	var encryptedKey string
-	err := c.db.QueryRow("SELECT id, name, type, url, api_key FROM arr_instances WHERE id = ?", id).
+	err := c.db.QueryRow("SELECT id, name, type, url, api_key FROM arr_instances WHERE id = ? AND deleted_at IS NULL", id).
 		Scan(&i.ID, &i.Name, &i.Type, &i.URL, &encryptedKey)
 	if err != nil {
 		return nil, err
@@ -1258,7 +1712,7 @@ func (c *HTTPArrClient) GetHistory(instance *ArrInstance, page, pageSize int, ev
 		endpoint += "&eventType=" + eventType
 	}
 
-	resp, err := c.doRequest(instance, "GET", endpoint, nil)
+	resp, err := c.doDeferrableRequest(instance, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1284,7 +1738,7 @@ func (c *HTTPArrClient) GetRecentHistoryForMedia(instance *ArrInstance, mediaID
 		endpoint = fmt.Sprintf("/api/v3/history/series?seriesId=%d&eventType=grabbed", mediaID)
 	}
 
-	resp, err := c.doRequest(instance, "GET", endpoint, nil)
+	resp, err := c.doDeferrableRequest(instance, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1501,6 +1955,31 @@ func (c *HTTPArrClient) GetRootFolders(instanceID int64) ([]RootFolder, error) {
 	return folders, nil
 }
 
+// GetTags returns the tags defined in a *arr instance.
+func (c *HTTPArrClient) GetTags(instanceID int64) ([]ArrTag, error) {
+	instance, err := c.getInstanceByIDInternal(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	resp, err := c.doRequest(instance, "GET", getAPIVersion(instance)+"/tag", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get tags: %s", resp.Status)
+	}
+
+	var tags []ArrTag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	return tags, nil
+}
+
 // GetQueueForPath implements ArrClient interface - gets queue for a path's instance
 func (c *HTTPArrClient) GetQueueForPath(arrPath string) ([]QueueItemInfo, error) {
 	instance, err := c.getInstanceForPath(arrPath)
@@ -1538,6 +2017,7 @@ func (c *HTTPArrClient) GetQueueForPath(arrPath string) ([]QueueItemInfo, error)
 			Protocol:              item.Protocol,
 			DownloadClient:        item.DownloadClient,
 			Indexer:               item.Indexer,
+			OutputPath:            item.OutputPath,
 			Size:                  item.Size,
 			SizeLeft:              item.SizeLeft,
 			Progress:              progress,
@@ -1589,6 +2069,7 @@ func (c *HTTPArrClient) FindQueueItemsByMediaIDForPath(arrPath string, mediaID i
 			Protocol:              item.Protocol,
 			DownloadClient:        item.DownloadClient,
 			Indexer:               item.Indexer,
+			OutputPath:            item.OutputPath,
 			Size:                  item.Size,
 			SizeLeft:              item.SizeLeft,
 			Progress:              progress,
@@ -1699,6 +2180,159 @@ func (c *HTTPArrClient) GetMediaDetails(mediaID int64, arrPath string) (*MediaDe
 	}
 }
 
+// GetExpectedRuntime implements ArrClient interface - movies report their
+// runtime, episode files the sum of the runtimes of the episodes in them. Sonarr
+// versions without episode runtimes fall back to the series' runtime.
+func (c *HTTPArrClient) GetExpectedRuntime(mediaID int64, arrPath string) (time.Duration, error) {
+	instance, err := c.getInstanceForPath(arrPath)
+	if err != nil {
+		return 0, err
+	}
+
+	switch instance.Type {
+	case ArrTypeRadarr, ArrTypeWhisparrV3:
+		var movie struct {
+			Runtime int `json:"runtime"`
+		}
+		if err := c.getJSON(instance, fmt.Sprintf("/api/v3/movie/%d", mediaID), &movie); err != nil {
+			return 0, err
+		}
+		return time.Duration(movie.Runtime) * time.Minute, nil
+	case ArrTypeSonarr, ArrTypeWhisparrV2:
+		return c.getEpisodeFileRuntime(instance, mediaID, arrPath)
+	default:
+		return 0, nil
+	}
+}
+
+// getEpisodeFileRuntime sums the runtimes of the episodes in a series' file.
+func (c *HTTPArrClient) getEpisodeFileRuntime(instance *ArrInstance, seriesID int64, arrPath string) (time.Duration, error) {
+	files, err := c.getFilesForMedia(instance, seriesID)
+	if err != nil {
+		return 0, err
+	}
+	file := findFileByBasename(files, arrPath)
+	if file == nil {
+		return 0, nil
+	}
+
+	var episodes []struct {
+		EpisodeFileID int64 `json:"episodeFileId"`
+		Runtime       int   `json:"runtime"`
+	}
+	if err := c.getJSON(instance, fmt.Sprintf("/api/v3/episode?seriesId=%d", seriesID), &episodes); err != nil {
+		return 0, err
+	}
+	var count, minutes int
+	for _, ep := range episodes {
+		if ep.EpisodeFileID == file.ID {
+			count++
+			minutes += ep.Runtime
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	if minutes == 0 {
+		var series struct {
+			Runtime int `json:"runtime"`
+		}
+		if err := c.getJSON(instance, fmt.Sprintf("/api/v3/series/%d", seriesID), &series); err != nil {
+			return 0, err
+		}
+		minutes = series.Runtime * count
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// arrDelayProfile is a delay profile as the *arr API returns it.
+type arrDelayProfile struct {
+	ID                int64   `json:"id"`
+	EnableUsenet      bool    `json:"enableUsenet"`
+	EnableTorrent     bool    `json:"enableTorrent"`
+	PreferredProtocol string  `json:"preferredProtocol"`
+	UsenetDelay       int     `json:"usenetDelay"`  // minutes
+	TorrentDelay      int     `json:"torrentDelay"` // minutes
+	Order             int     `json:"order"`
+	Tags              []int64 `json:"tags"`
+}
+
+// GetDelayProfile returns the delay profile that applies to a media item:
+// like the instance, the first profile by order that shares a tag with the
+// movie, series or artist, or else the profile without tags.
+func (c *HTTPArrClient) GetDelayProfile(mediaID int64, arrPath string) (*DelayProfile, error) {
+	instance, err := c.getInstanceForPath(arrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var mediaEndpoint string
+	switch instance.Type {
+	case ArrTypeRadarr, ArrTypeWhisparrV3:
+		mediaEndpoint = fmt.Sprintf("/api/v3/movie/%d", mediaID)
+	case ArrTypeSonarr, ArrTypeWhisparrV2:
+		mediaEndpoint = fmt.Sprintf("/api/v3/series/%d", mediaID)
+	case ArrTypeLidarr:
+		mediaEndpoint = fmt.Sprintf("/api/v1/artist/%d", mediaID)
+	default:
+		return nil, nil
+	}
+
+	var profiles []arrDelayProfile
+	if err := c.getJSON(instance, getAPIVersion(instance)+"/delayprofile", &profiles); err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	var media struct {
+		Tags []int64 `json:"tags"`
+	}
+	if err := c.getJSON(instance, mediaEndpoint, &media); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].Order < profiles[j].Order })
+	for _, p := range profiles {
+		if len(p.Tags) > 0 && !sharesTag(p.Tags, media.Tags) {
+			continue
+		}
+		return &DelayProfile{
+			ID:                p.ID,
+			InstanceName:      instance.Name,
+			PreferredProtocol: p.PreferredProtocol,
+			EnableUsenet:      p.EnableUsenet,
+			EnableTorrent:     p.EnableTorrent,
+			UsenetDelay:       time.Duration(p.UsenetDelay) * time.Minute,
+			TorrentDelay:      time.Duration(p.TorrentDelay) * time.Minute,
+		}, nil
+	}
+	return nil, nil
+}
+
+// sharesTag reports whether a and b have a tag in common.
+func sharesTag(a, b []int64) bool {
+	for _, x := range a {
+		if slices.Contains(b, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// getJSON decodes the response of a GET request to an instance.
+func (c *HTTPArrClient) getJSON(instance *ArrInstance, endpoint string, v interface{}) error {
+	resp, err := c.doRequest(instance, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
 // getMovieDetails fetches movie title and year from Radarr/Whisparr
 func (c *HTTPArrClient) getMovieDetails(instance *ArrInstance, movieID int64) (*MediaDetails, error) {
 	endpoint := fmt.Sprintf("/api/v3/movie/%d", movieID)
@@ -1715,8 +2349,10 @@ func (c *HTTPArrClient) getMovieDetails(instance *ArrInstance, movieID int64) (*
 	}
 
 	var movie struct {
-		Title string `json:"title"`
-		Year  int    `json:"year"`
+		Title  string `json:"title"`
+		Year   int    `json:"year"`
+		TmdbID int64  `json:"tmdbId"`
+		ImdbID string `json:"imdbId"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&movie); err != nil {
 		logger.Debugf("Failed to decode movie details for ID %d: %v", movieID, err)
@@ -1729,6 +2365,8 @@ func (c *HTTPArrClient) getMovieDetails(instance *ArrInstance, movieID int64) (*
 		MediaType:    "movie",
 		ArrType:      instance.Type,
 		InstanceName: instance.Name,
+		TmdbID:       movie.TmdbID,
+		ImdbID:       movie.ImdbID,
 	}, nil
 }
 
@@ -1749,8 +2387,10 @@ func (c *HTTPArrClient) getSeriesDetails(instance *ArrInstance, seriesID int64)
 	}
 
 	var series struct {
-		Title string `json:"title"`
-		Year  int    `json:"year"`
+		Title  string `json:"title"`
+		Year   int    `json:"year"`
+		TvdbID int64  `json:"tvdbId"`
+		ImdbID string `json:"imdbId"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
 		logger.Debugf("Failed to decode series details for ID %d: %v", seriesID, err)
@@ -1763,6 +2403,8 @@ func (c *HTTPArrClient) getSeriesDetails(instance *ArrInstance, seriesID int64)
 		MediaType:    "series",
 		ArrType:      instance.Type,
 		InstanceName: instance.Name,
+		TvdbID:       series.TvdbID,
+		ImdbID:       series.ImdbID,
 	}, nil
 }
 
@@ -17,6 +17,7 @@ import (
 
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // testDB is a helper for creating test databases without import cycles
@@ -44,7 +45,17 @@ func newTestDB(t *testing.T) *testDB {
 			type TEXT NOT NULL,
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
-			enabled INTEGER DEFAULT 1
+			enabled INTEGER DEFAULT 1,
+			daily_request_budget INTEGER NOT NULL DEFAULT 0,
+			deleted_at TIMESTAMP DEFAULT NULL
+		);
+		CREATE TABLE IF NOT EXISTS arr_request_usage (
+			instance_id INTEGER NOT NULL,
+			day TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 0,
+			deferred INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (instance_id, day)
 		);
 		CREATE TABLE IF NOT EXISTS scan_paths (
 			id INTEGER PRIMARY KEY,
@@ -54,6 +65,7 @@ func newTestDB(t *testing.T) *testDB {
 			auto_remediate INTEGER DEFAULT 0,
 			is_4k INTEGER DEFAULT 0,
 			verification_timeout_hours INTEGER,
+			deleted_at TIMESTAMP DEFAULT NULL,
 			FOREIGN KEY (arr_instance_id) REFERENCES arr_instances(id)
 		);
 	`
@@ -125,6 +137,23 @@ func TestRateLimiter_Wait_Throttle(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_SetLimits(t *testing.T) {
+	rl := NewRateLimiter(0.1, 10)
+	rl.SetLimits(1000.0, 1)
+	if rl.maxTokens != 1 || rl.tokens != 1 {
+		t.Fatalf("Expected burst of 1, got %v tokens of %v", rl.tokens, rl.maxTokens)
+	}
+
+	// Without the new rate the second call would wait 10s
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+}
+
 // =============================================================================
 // isRetryableError tests
 // =============================================================================
@@ -175,6 +204,26 @@ func setupTestClient(t *testing.T) (*HTTPArrClient, *testDB) {
 	return client, db
 }
 
+func TestNewArrClient_RegistersStoredAPIKeys(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+	db := newTestDB(t)
+	defer db.Close()
+
+	encryptedKey, err := crypto.Encrypt("stored-sonarr-api-key-0123456789")
+	if err != nil {
+		t.Fatalf("Failed to encrypt API key: %v", err)
+	}
+	if _, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled)
+		VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr:8989', ?, 0)`, encryptedKey); err != nil {
+		t.Fatalf("Failed to insert instance: %v", err)
+	}
+
+	NewArrClient(db.DB)
+	if got := redact.Text("key stored-sonarr-api-key-0123456789 rejected"); strings.Contains(got, "stored-sonarr-api-key") {
+		t.Errorf("Expected the stored key to be redacted, got %q", got)
+	}
+}
+
 func TestHTTPArrClient_FindMediaByPath_Radarr(t *testing.T) {
 	client, db := setupTestClient(t)
 	defer db.Close()
@@ -274,6 +323,53 @@ func TestHTTPArrClient_FindMediaByPath_Sonarr(t *testing.T) {
 	}
 }
 
+func TestHTTPArrClient_FindSearchMetadata(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/episodefile":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 7, "path": "/tv/Test Show/Season 01/S01E01.mkv"}})
+		case "/api/v3/episode":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 100, "episodeFileId": 7},
+				{"id": 101, "seasonNumber": 1, "monitored": true},
+			})
+		case "/api/v3/parse":
+			json.NewEncoder(w).Encode(ParseResult{
+				Series:   &MediaItem{ID: 456, Title: "Test Show"},
+				Episodes: []Episode{{ID: 102}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	encryptedKey, _ := crypto.Encrypt("sonarr-key")
+	db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (1, 'Test Sonarr', 'sonarr', ?, ?, 1)`, server.URL, encryptedKey)
+	db.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, auto_remediate, is_4k) VALUES (1, '/local/tv', '/tv', 1, 0, 0)`)
+
+	// A file the instance has: the episodes it belongs to
+	metadata, err := client.FindSearchMetadata(456, "/tv/Test Show/Season 01/S01E01.mkv")
+	if err != nil {
+		t.Fatalf("FindSearchMetadata failed: %v", err)
+	}
+	if ids, _ := metadata["episode_ids"].([]int64); len(ids) != 1 || ids[0] != 100 {
+		t.Errorf("Expected episode 100 of the file, got %v", metadata)
+	}
+
+	// A deleted file: the episodes its name refers to
+	metadata, err = client.FindSearchMetadata(456, "/tv/Test Show/Season 01/S01E03.mkv")
+	if err != nil {
+		t.Fatalf("FindSearchMetadata failed: %v", err)
+	}
+	if ids, _ := metadata["episode_ids"].([]int64); len(ids) != 1 || ids[0] != 102 {
+		t.Errorf("Expected parsed episode 102, got %v", metadata)
+	}
+}
+
 func TestHTTPArrClient_FindMediaByPath_Fallback(t *testing.T) {
 	client, db := setupTestClient(t)
 	defer db.Close()
@@ -1013,6 +1109,100 @@ func TestHTTPArrClient_DeleteFile_Radarr(t *testing.T) {
 	}
 }
 
+func TestHTTPArrClient_DeleteFile_RecordsQualityAndAudio(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v3/moviefile" && r.Method == "GET":
+			w.Write([]byte(`[{"id": 10, "path": "/movies/Test Movie (2024)/movie.mkv",
+				"quality": {"quality": {"id": 7, "name": "Bluray-1080p", "source": "bluray", "resolution": 1080}},
+				"mediaInfo": {"audioChannels": 5.1, "audioLanguages": "English/Japanese"}}]`))
+		case r.URL.Path == "/api/v3/moviefile/10" && r.Method == "DELETE":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	encryptedKey, _ := crypto.Encrypt("key")
+	db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (1, 'Radarr', 'radarr', ?, ?, 1)`, server.URL, encryptedKey)
+	db.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, auto_remediate, is_4k) VALUES (1, '/local/movies', '/movies', 1, 0, 0)`)
+
+	metadata, err := client.DeleteFile(123, "/movies/Test Movie (2024)/movie.mkv")
+	if err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if metadata["quality"] != "Bluray-1080p" || metadata["quality_resolution"] != 1080 {
+		t.Errorf("Expected the deleted file's quality in metadata, got %v", metadata)
+	}
+	languages, _ := metadata["audio_languages"].([]string)
+	if len(languages) != 2 || languages[0] != "eng" || languages[1] != "jpn" || metadata["audio_channels"] != 6 {
+		t.Errorf("Expected the deleted file's audio in metadata, got %v", metadata)
+	}
+}
+
+func TestHTTPArrClient_Releases(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	var grabbed map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v3/release" && r.Method == "GET":
+			// Both episodes return the season pack
+			w.Write([]byte(`[
+				{"guid": "pack", "indexerId": 2, "indexer": "NZBgeek", "title": "Show.S01.1080p", "size": 5000,
+				 "protocol": "usenet", "approved": true, "downloadAllowed": true, "rejections": [],
+				 "quality": {"quality": {"name": "WEBDL-1080p", "resolution": 1080}}},
+				{"guid": "ep` + r.URL.Query().Get("episodeId") + `", "indexerId": 3, "title": "Show.S01E01.720p",
+				 "protocol": "torrent", "seeders": 4, "downloadAllowed": true,
+				 "rejections": ["Not an upgrade", {"reason": "Blocklisted"}],
+				 "quality": {"quality": {"name": "HDTV-720p", "resolution": 720}}}
+			]`))
+		case r.URL.Path == "/api/v3/release" && r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&grabbed)
+			w.Write([]byte(`{"guid": "pack", "indexerId": 2, "title": "Show.S01.1080p"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	encryptedKey, _ := crypto.Encrypt("key")
+	db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (1, 'Sonarr', 'sonarr', ?, ?, 1)`, server.URL, encryptedKey)
+	db.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, auto_remediate, is_4k) VALUES (1, '/local/tv', '/tv', 1, 0, 0)`)
+
+	releases, err := client.GetReleases(5, "/tv/Show/Season 01/Show.S01E01.mkv", []int64{11, 12})
+	if err != nil {
+		t.Fatalf("GetReleases failed: %v", err)
+	}
+	if len(releases) != 3 {
+		t.Fatalf("Expected the season pack once plus one release per episode, got %+v", releases)
+	}
+	if releases[0].Quality != "WEBDL-1080p" || !releases[0].Approved || releases[0].Seeders != nil {
+		t.Errorf("Unexpected usenet release: %+v", releases[0])
+	}
+	if r := releases[1]; r.GUID != "ep11" || r.Seeders == nil || *r.Seeders != 4 ||
+		len(r.Rejections) != 2 || r.Rejections[1] != "Blocklisted" {
+		t.Errorf("Unexpected torrent release: %+v", r)
+	}
+
+	release, err := client.GrabRelease("/tv/Show/Season 01/Show.S01E01.mkv", "pack", 2)
+	if err != nil {
+		t.Fatalf("GrabRelease failed: %v", err)
+	}
+	if release.Title != "Show.S01.1080p" || grabbed["guid"] != "pack" || grabbed["indexerId"] != float64(2) {
+		t.Errorf("Unexpected grab: release %+v, payload %v", release, grabbed)
+	}
+
+	if _, err := client.GetReleases(5, "/tv/Show/Specials/x.mkv", nil); err == nil {
+		t.Error("Expected an error for a series without episode IDs or season")
+	}
+}
+
 func TestHTTPArrClient_DeleteFile_NotFoundInArr(t *testing.T) {
 	client, db := setupTestClient(t)
 	defer db.Close()
@@ -1896,6 +2086,7 @@ func TestHTTPArrClient_DeleteFile_ServerError(t *testing.T) {
 		// Return error for delete
 		if r.Method == "DELETE" {
 			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "Access to the path '/movies/Test Movie/movie.mkv' is denied."}`))
 			return
 		}
 		w.WriteHeader(http.StatusNotFound)
@@ -1908,7 +2099,14 @@ func TestHTTPArrClient_DeleteFile_ServerError(t *testing.T) {
 
 	_, err := client.DeleteFile(123, "/movies/Test Movie/movie.mkv")
 	if err == nil {
-		t.Error("Expected error when delete fails")
+		t.Fatal("Expected error when delete fails")
+	}
+	var respErr *ArrResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("Expected an ArrResponseError, got %T: %v", err, err)
+	}
+	if respErr.StatusCode != http.StatusInternalServerError || !strings.Contains(respErr.Body, "is denied") {
+		t.Errorf("Expected the status and response body, got %d %q", respErr.StatusCode, respErr.Body)
 	}
 }
 
@@ -5663,6 +5861,45 @@ func TestHTTPArrClient_GetRootFolders_InstanceNotFound(t *testing.T) {
 	}
 }
 
+func TestHTTPArrClient_GetTags(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/tag":
+			json.NewEncoder(w).Encode([]ArrTag{{ID: 1, Label: "uhd"}, {ID: 2, Label: "anime"}})
+		case "/api/v1/tag":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+		(1, 'Radarr', 'radarr', ?, 'test-key'),
+		(2, 'Lidarr', 'lidarr', ?, 'test-key')`, server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to insert test instances: %v", err)
+	}
+
+	tags, err := client.GetTags(1)
+	if err != nil {
+		t.Fatalf("GetTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0].Label != "uhd" || tags[1].ID != 2 {
+		t.Errorf("GetTags() = %+v", tags)
+	}
+
+	if _, err := client.GetTags(2); err == nil {
+		t.Error("GetTags() expected error for API error response")
+	}
+	if _, err := client.GetTags(999); err == nil {
+		t.Error("GetTags() expected error for non-existent instance")
+	}
+}
+
 func TestHTTPArrClient_GetRootFolders_APIError(t *testing.T) {
 	client, db := setupTestClient(t)
 	defer db.Close()
@@ -5788,6 +6025,106 @@ func TestHTTPArrClient_GetMediaDetails_Sonarr(t *testing.T) {
 	}
 }
 
+func TestHTTPArrClient_GetExpectedRuntime(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	// Episode 10 and 11 share file 5 (a double episode); this Sonarr only knows the series runtime
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/movie/123":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 123, "runtime": 136})
+		case "/api/v3/episodefile":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 5, "path": "/tv/Show/Season 01/Show - S01E01E02.mkv"}})
+		case "/api/v3/episode":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 10, "episodeFileId": 5}, {"id": 11, "episodeFileId": 5}, {"id": 12, "episodeFileId": 0},
+			})
+		case "/api/v3/series/456":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 456, "runtime": 22})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'TestRadarr', 'radarr', '` + server.URL + `', 'test-key')`,
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (2, 'TestSonarr', 'sonarr', '` + server.URL + `', 'test-key')`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/local/movies', '/movies', 1)`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (2, '/local/tv', '/tv', 2)`,
+	} {
+		if _, err := db.DB.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+
+	runtime, err := client.GetExpectedRuntime(123, "/movies/The Matrix (1999)/movie.mkv")
+	if err != nil || runtime != 136*time.Minute {
+		t.Errorf("GetExpectedRuntime(movie) = %v, %v; want 2h16m", runtime, err)
+	}
+	runtime, err = client.GetExpectedRuntime(456, "/tv/Show/Season 01/Show - S01E01E02.mkv")
+	if err != nil || runtime != 44*time.Minute {
+		t.Errorf("GetExpectedRuntime(double episode) = %v, %v; want 44m", runtime, err)
+	}
+	runtime, err = client.GetExpectedRuntime(456, "/tv/Show/Season 01/unknown.mkv")
+	if err != nil || runtime != 0 {
+		t.Errorf("GetExpectedRuntime(unknown file) = %v, %v; want 0", runtime, err)
+	}
+}
+
+func TestHTTPArrClient_GetDelayProfile(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	// Movie 123 is tagged anime (tag 2), movie 124 has no tags
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/delayprofile":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "enableUsenet": true, "enableTorrent": true, "preferredProtocol": "usenet", "usenetDelay": 0, "torrentDelay": 60, "order": 2147483647, "tags": []int{}},
+				{"id": 2, "enableUsenet": false, "enableTorrent": true, "preferredProtocol": "torrent", "usenetDelay": 0, "torrentDelay": 720, "order": 1, "tags": []int{2}},
+			})
+		case "/api/v3/movie/123":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 123, "tags": []int{1, 2}})
+		case "/api/v3/movie/124":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 124, "tags": []int{}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'TestRadarr', 'radarr', '` + server.URL + `', 'test-key')`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/local/movies', '/movies', 1)`,
+	} {
+		if _, err := db.DB.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+
+	profile, err := client.GetDelayProfile(123, "/movies/Akira (1988)/movie.mkv")
+	if err != nil || profile == nil {
+		t.Fatalf("GetDelayProfile(tagged) = %v, %v", profile, err)
+	}
+	if profile.ID != 2 || profile.InstanceName != "TestRadarr" || profile.TorrentDelay != 12*time.Hour || profile.EnableUsenet {
+		t.Errorf("GetDelayProfile(tagged) = %+v, want the tagged profile with a 12h torrent delay", profile)
+	}
+
+	profile, err = client.GetDelayProfile(124, "/movies/The Matrix (1999)/movie.mkv")
+	if err != nil || profile == nil {
+		t.Fatalf("GetDelayProfile(untagged) = %v, %v", profile, err)
+	}
+	if profile.ID != 1 || profile.Delay("") != time.Hour {
+		t.Errorf("GetDelayProfile(untagged) = %+v, want the default profile with a 1h delay", profile)
+	}
+
+	if _, err := client.GetDelayProfile(999, "/movies/Unknown/movie.mkv"); err == nil {
+		t.Error("GetDelayProfile(unknown movie) expected an error")
+	}
+}
+
 func TestHTTPArrClient_GetMediaDetails_NotFound(t *testing.T) {
 	client, db := setupTestClient(t)
 	defer db.Close()
@@ -5999,6 +6336,59 @@ func TestHTTPArrClient_HandleFileNotInArr_FileExistsOnDisk(t *testing.T) {
 	if !strings.Contains(err.Error(), "file not found in radarr but exists on disk") {
 		t.Errorf("Expected error message about file existing on disk, got: %v", err)
 	}
+	var notInArr *FileNotInArrError
+	if !errors.As(err, &notInArr) {
+		t.Fatalf("Expected *FileNotInArrError, got %T", err)
+	}
+	if notInArr.Metadata["movie_id"] != int64(123) {
+		t.Errorf("Expected search metadata for movie 123, got %v", notInArr.Metadata)
+	}
+}
+
+func TestHTTPArrClient_RescanMedia(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	var commands []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || (r.URL.Path != "/api/v3/command" && r.URL.Path != "/api/v1/command") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var cmd map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&cmd)
+		commands = append(commands, cmd)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	_, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+		(1, 'Radarr', 'radarr', ?, 'key'), (2, 'Sonarr', 'sonarr', ?, 'key')`, server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to insert test instances: %v", err)
+	}
+	_, err = db.DB.Exec(`INSERT INTO scan_paths (local_path, arr_path, arr_instance_id) VALUES
+		('/media/movies', '/movies', 1), ('/media/tv', '/tv', 2)`)
+	if err != nil {
+		t.Fatalf("Failed to insert test scan paths: %v", err)
+	}
+
+	if err := client.RescanMedia(10, "/movies/Movie/movie.mkv"); err != nil {
+		t.Fatalf("RescanMedia() movie error = %v", err)
+	}
+	if err := client.RescanMedia(20, "/tv/Show/Season 01/ep.mkv"); err != nil {
+		t.Fatalf("RescanMedia() series error = %v", err)
+	}
+
+	if len(commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(commands))
+	}
+	if commands[0]["name"] != "RescanMovie" || commands[0]["movieId"] != float64(10) {
+		t.Errorf("Unexpected movie rescan command: %v", commands[0])
+	}
+	if commands[1]["name"] != "RescanSeries" || commands[1]["seriesId"] != float64(20) {
+		t.Errorf("Unexpected series rescan command: %v", commands[1])
+	}
 }
 
 func TestHTTPArrClient_HandleFileNotInArr_MovieAlreadyDeleted(t *testing.T) {
@@ -6089,8 +6479,10 @@ func TestHTTPArrClient_GetMovieDetails_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/api/v3/movie/123" {
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"title": "The Matrix",
-				"year":  1999,
+				"title":  "The Matrix",
+				"year":   1999,
+				"tmdbId": 603,
+				"imdbId": "tt0133093",
 			})
 			return
 		}
@@ -6125,6 +6517,9 @@ func TestHTTPArrClient_GetMovieDetails_Success(t *testing.T) {
 	if details.MediaType != "movie" {
 		t.Errorf("Expected MediaType 'movie', got %q", details.MediaType)
 	}
+	if details.TmdbID != 603 || details.ImdbID != "tt0133093" {
+		t.Errorf("Expected TMDb 603 and IMDb tt0133093, got %d and %q", details.TmdbID, details.ImdbID)
+	}
 }
 
 func TestHTTPArrClient_GetMovieDetails_NotFound(t *testing.T) {
@@ -6194,8 +6589,9 @@ func TestHTTPArrClient_GetSeriesDetails_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/api/v3/series/456" {
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"title": "Breaking Bad",
-				"year":  2008,
+				"title":  "Breaking Bad",
+				"year":   2008,
+				"tvdbId": 81189,
 			})
 			return
 		}
@@ -6230,6 +6626,9 @@ func TestHTTPArrClient_GetSeriesDetails_Success(t *testing.T) {
 	if details.MediaType != "series" {
 		t.Errorf("Expected MediaType 'series', got %q", details.MediaType)
 	}
+	if details.TvdbID != 81189 {
+		t.Errorf("Expected TVDB 81189, got %d", details.TvdbID)
+	}
 }
 
 func TestHTTPArrClient_GetSeriesDetails_NotFound(t *testing.T) {
@@ -0,0 +1,290 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// ErrNoFixture is returned by a ReplayTransport for a request that wasn't
+// recorded.
+var ErrNoFixture = errors.New("no recorded *arr response for request")
+
+// ArrExchange is one recorded *arr API request and its response. Only the
+// path and query of the URL are kept, so fixtures don't depend on the host
+// they were recorded from. Bodies that aren't JSON are kept as text.
+type ArrExchange struct {
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	Query        string          `json:"query,omitempty"`
+	RequestJSON  json.RawMessage `json:"request_json,omitempty"`
+	RequestText  string          `json:"request_text,omitempty"`
+	Status       int             `json:"status"`
+	ContentType  string          `json:"content_type,omitempty"`
+	ResponseJSON json.RawMessage `json:"response_json,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+}
+
+// requestKey identifies the request of an exchange, without its body.
+func (e *ArrExchange) requestKey() string {
+	return e.Method + " " + e.Path + "?" + e.Query
+}
+
+// requestBody returns the request body as it was recorded.
+func (e *ArrExchange) requestBody() string {
+	if len(e.RequestJSON) > 0 {
+		return string(e.RequestJSON)
+	}
+	return e.RequestText
+}
+
+// setBody stores body as JSON if it is, otherwise as text.
+func setBody(body []byte, asJSON *json.RawMessage, asText *string) {
+	if len(body) == 0 {
+		return
+	}
+	if sanitized, ok := sanitizeJSON(body); ok {
+		*asJSON = sanitized
+		return
+	}
+	*asText = string(body)
+}
+
+// sanitizeJSON returns body, indented, with the secrets *arr responses contain
+// in e.g. download client and indexer settings masked. ok is false if body
+// isn't JSON.
+func sanitizeJSON(body []byte) (json.RawMessage, bool) {
+	if !json.Valid(body) {
+		return nil, false
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, redact.JSON(body), "", "  "); err != nil {
+		return nil, false
+	}
+	return out.Bytes(), true
+}
+
+// sanitizeQuery returns the query without the API key, sorted by key.
+func sanitizeQuery(query url.Values) string {
+	clean := url.Values{}
+	for k, vals := range query {
+		if redact.IsSecretField(k) {
+			continue
+		}
+		clean[k] = vals
+	}
+	return clean.Encode()
+}
+
+// fixtureFile is the file the exchanges of an endpoint are recorded in, e.g.
+// GET_api_v3_system_status.json.
+func fixtureFile(dir, method, path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.Trim(path, "/"))
+	return filepath.Join(dir, method+"_"+name+".json")
+}
+
+// readFixture reads the exchanges recorded in a fixture file. A missing file
+// has none.
+func readFixture(file string) ([]ArrExchange, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var exchanges []ArrExchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", file, err)
+	}
+	return exchanges, nil
+}
+
+// RecordingTransport records the *arr API requests it forwards, and their
+// responses, as fixtures for a ReplayTransport. It keeps the latest exchange
+// of every distinct request, one file per endpoint. The API key header,
+// sensitive query parameters and sensitive JSON fields are left out.
+type RecordingTransport struct {
+	dir  string
+	base http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecordingTransport creates a RecordingTransport that forwards requests to
+// base (http.DefaultTransport if nil) and writes fixtures to dir.
+func NewRecordingTransport(dir string, base http.RoundTripper) (*RecordingTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	return &RecordingTransport{dir: dir, base: base}, nil
+}
+
+// RoundTrip forwards the request and records the exchange. Failing to record
+// it is logged, not returned.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	exchange := ArrExchange{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		Query:       sanitizeQuery(req.URL.Query()),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	setBody(reqBody, &exchange.RequestJSON, &exchange.RequestText)
+	setBody(respBody, &exchange.ResponseJSON, &exchange.ResponseText)
+	if err := t.record(exchange); err != nil {
+		logger.Warnf("Failed to record *arr API %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// record writes an exchange to the fixture of its endpoint, replacing an
+// earlier recording of the same request.
+func (t *RecordingTransport) record(exchange ArrExchange) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file := fixtureFile(t.dir, exchange.Method, exchange.Path)
+	exchanges, err := readFixture(file)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range exchanges {
+		if exchanges[i].requestKey() == exchange.requestKey() && exchanges[i].requestBody() == exchange.requestBody() {
+			exchanges[i] = exchange
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.SliceStable(exchanges, func(i, j int) bool {
+		return exchanges[i].requestKey() < exchanges[j].requestKey()
+	})
+
+	data, err := json.MarshalIndent(exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o640)
+}
+
+// ReplayTransport answers *arr API requests with the responses a
+// RecordingTransport recorded, so tests can run against real traffic of an
+// *arr version without a running instance. Requests match on method, path and
+// query; of several recordings with different bodies, the one with the same
+// body wins.
+type ReplayTransport struct {
+	exchanges map[string][]ArrExchange
+}
+
+// NewReplayTransport loads the fixtures recorded in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	t := &ReplayTransport{exchanges: make(map[string][]ArrExchange)}
+	for _, file := range files {
+		exchanges, err := readFixture(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range exchanges {
+			t.exchanges[e.requestKey()] = append(t.exchanges[e.requestKey()], e)
+		}
+	}
+	return t, nil
+}
+
+// RoundTrip returns the recorded response of the request, or ErrNoFixture.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		var rec ArrExchange
+		setBody(data, &rec.RequestJSON, &rec.RequestText)
+		body = rec.requestBody()
+	}
+
+	key := (&ArrExchange{Method: req.Method, Path: req.URL.Path, Query: sanitizeQuery(req.URL.Query())}).requestKey()
+	candidates := t.exchanges[key]
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoFixture, key)
+	}
+	match := candidates[0]
+	for _, e := range candidates {
+		if e.requestBody() == body {
+			match = e
+			break
+		}
+	}
+
+	respBody := []byte(match.ResponseText)
+	if len(match.ResponseJSON) > 0 {
+		respBody = match.ResponseJSON
+	}
+	header := http.Header{}
+	if match.ContentType != "" {
+		header.Set("Content-Type", match.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Status, http.StatusText(match.Status)),
+		StatusCode:    match.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
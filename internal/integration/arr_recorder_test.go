@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+func TestArrRecording_RecordAndReplay(t *testing.T) {
+	fixtures := t.TempDir()
+
+	// Record real traffic
+	client, db := setupTestClient(t)
+	defer db.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/system/status":
+			w.Write([]byte(`{"version": "5.2.6", "apiKey": "server-side-key", "settings": [{"name": "password", "value": "hunter2"}]}`))
+		case "/api/v3/parse":
+			json.NewEncoder(w).Encode(ParseResult{Movie: &MediaItem{ID: 42, Title: "Test Movie", Path: "/movies/Test Movie (2024)"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Radarr', 'radarr', ?, 'secret-api-key')`, server.URL); err != nil {
+		t.Fatalf("Failed to insert instance: %v", err)
+	}
+	if _, err := db.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/local/movies', '/movies', 1)`); err != nil {
+		t.Fatalf("Failed to insert scan path: %v", err)
+	}
+
+	if err := client.EnableRecording(fixtures); err != nil {
+		t.Fatalf("EnableRecording() error = %v", err)
+	}
+	if err := client.CheckInstanceHealth(1); err != nil {
+		t.Fatalf("CheckInstanceHealth() error = %v", err)
+	}
+	if err := client.CheckInstanceHealth(1); err != nil {
+		t.Fatalf("CheckInstanceHealth() error = %v", err)
+	}
+	if id, err := client.FindMediaByPath("/movies/Test Movie (2024)/movie.mkv"); err != nil || id != 42 {
+		t.Fatalf("FindMediaByPath() = %d, %v", id, err)
+	}
+
+	status, err := readFixture(filepath.Join(fixtures, "GET_api_v3_system_status.json"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if len(status) != 1 {
+		t.Fatalf("Expected a repeated request to be recorded once, got %d", len(status))
+	}
+	if status[0].Status != http.StatusOK || status[0].ContentType != "application/json" {
+		t.Errorf("Unexpected exchange: %+v", status[0])
+	}
+	files, _ := filepath.Glob(filepath.Join(fixtures, "*.json"))
+	if len(files) != 2 {
+		t.Errorf("Expected one fixture per endpoint, got %v", files)
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		for _, secret := range []string{"secret-api-key", "server-side-key", "hunter2", server.URL} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s contains %q", filepath.Base(file), secret)
+			}
+		}
+	}
+
+	// Replay it without the server
+	replay, err := NewReplayTransport(fixtures)
+	if err != nil {
+		t.Fatalf("NewReplayTransport() error = %v", err)
+	}
+	replayClient, replayDB := setupTestClient(t)
+	defer replayDB.Close()
+	replayClient.SetTransport(replay)
+	if _, err := replayDB.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Radarr', 'radarr', 'http://radarr.invalid', 'other-key')`); err != nil {
+		t.Fatalf("Failed to insert instance: %v", err)
+	}
+	if _, err := replayDB.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/local/movies', '/movies', 1)`); err != nil {
+		t.Fatalf("Failed to insert scan path: %v", err)
+	}
+
+	if err := replayClient.CheckInstanceHealth(1); err != nil {
+		t.Errorf("Replayed CheckInstanceHealth() error = %v", err)
+	}
+	if id, err := replayClient.FindMediaByPath("/movies/Test Movie (2024)/movie.mkv"); err != nil || id != 42 {
+		t.Errorf("Replayed FindMediaByPath() = %d, %v", id, err)
+	}
+
+	req, _ := http.NewRequest("GET", "http://radarr.invalid/api/v3/queue", nil)
+	if _, err := replay.RoundTrip(req); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("Expected ErrNoFixture for an unrecorded request, got %v", err)
+	}
+}
+
+func TestSanitizeQuery(t *testing.T) {
+	query := url.Values{"apikey": {"secret"}, "path": {"/movies/a.mkv"}, "page": {"2"}}
+	if got, want := sanitizeQuery(query), "page=2&path=%2Fmovies%2Fa.mkv"; got != want {
+		t.Errorf("sanitizeQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactSensitive(t *testing.T) {
+	body := []byte(`{"name": "SABnzbd", "api_key": "k", "fields": [{"name": "apiKey", "value": "k"}, {"name": "host", "value": "localhost"}], "nested": {"Password": "p"}}`)
+	sanitized, ok := sanitizeJSON(body)
+	if !ok {
+		t.Fatal("Expected JSON body")
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(sanitized, &got); err != nil {
+		t.Fatal(err)
+	}
+	fields := got["fields"].([]interface{})
+	if got["api_key"] != redact.Mask || fields[0].(map[string]interface{})["value"] != redact.Mask ||
+		got["nested"].(map[string]interface{})["Password"] != redact.Mask {
+		t.Errorf("Secrets not redacted: %s", sanitized)
+	}
+	if got["name"] != "SABnzbd" || fields[1].(map[string]interface{})["value"] != "localhost" {
+		t.Errorf("Other values should be kept: %s", sanitized)
+	}
+	if _, ok := sanitizeJSON([]byte("not json")); ok {
+		t.Error("Expected text body not to be treated as JSON")
+	}
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrAudioProbeUnsupported is returned by health checkers that can't list audio tracks.
+var ErrAudioProbeUnsupported = errors.New("audio track probing not supported")
+
+// AudioTrack is an audio stream of a media file.
+type AudioTrack struct {
+	// Language is an ISO 639-2 code, empty if the track isn't tagged
+	Language string `json:"language,omitempty"`
+	Channels int    `json:"channels"`
+}
+
+// languageCodes maps ISO 639-1 codes, ISO 639-2/B codes and English names to
+// the ISO 639-2/T code ffprobe and the *arr instances usually report. Codes
+// that aren't listed are compared as they are.
+var languageCodes = map[string]string{
+	"en": "eng", "english": "eng",
+	"de": "deu", "ger": "deu", "german": "deu",
+	"fr": "fra", "fre": "fra", "french": "fra",
+	"es": "spa", "spanish": "spa",
+	"it": "ita", "italian": "ita",
+	"pt": "por", "portuguese": "por",
+	"nl": "nld", "dut": "nld", "dutch": "nld", "flemish": "nld",
+	"sv": "swe", "swedish": "swe",
+	"da": "dan", "danish": "dan",
+	"no": "nor", "nb": "nor", "nob": "nor", "norwegian": "nor",
+	"fi": "fin", "finnish": "fin",
+	"is": "isl", "ice": "isl", "icelandic": "isl",
+	"pl": "pol", "polish": "pol",
+	"cs": "ces", "cze": "ces", "czech": "ces",
+	"sk": "slk", "slo": "slk", "slovak": "slk",
+	"hu": "hun", "hungarian": "hun",
+	"ro": "ron", "rum": "ron", "romanian": "ron",
+	"bg": "bul", "bulgarian": "bul",
+	"hr": "hrv", "croatian": "hrv",
+	"sr": "srp", "serbian": "srp",
+	"sl": "slv", "slovenian": "slv",
+	"el": "ell", "gre": "ell", "greek": "ell",
+	"tr": "tur", "turkish": "tur",
+	"ru": "rus", "russian": "rus",
+	"uk": "ukr", "ukrainian": "ukr",
+	"lt": "lit", "lithuanian": "lit",
+	"lv": "lav", "latvian": "lav",
+	"et": "est", "estonian": "est",
+	"he": "heb", "hebrew": "heb",
+	"ar": "ara", "arabic": "ara",
+	"fa": "fas", "per": "fas", "persian": "fas",
+	"hi": "hin", "hindi": "hin",
+	"ta": "tam", "tamil": "tam",
+	"te": "tel", "telugu": "tel",
+	"th": "tha", "thai": "tha",
+	"vi": "vie", "vietnamese": "vie",
+	"id": "ind", "indonesian": "ind",
+	"ms": "msa", "may": "msa", "malay": "msa",
+	"zh": "zho", "chi": "zho", "chinese": "zho", "mandarin": "zho", "cantonese": "zho",
+	"ja": "jpn", "japanese": "jpn",
+	"ko": "kor", "korean": "kor",
+}
+
+// NormalizeLanguage returns the ISO 639-2/T code of a language code or name,
+// or "" for untagged and undetermined languages.
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	switch language {
+	case "", "und", "unknown", "mis", "mul", "zxx":
+		return ""
+	}
+	if code, ok := languageCodes[language]; ok {
+		return code
+	}
+	return language
+}
+
+// ParseArrLanguages parses the audioLanguages of an *arr file's media info,
+// e.g. "English/Japanese" or "eng / jpn", into normalized codes without duplicates.
+func ParseArrLanguages(languages string) []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, part := range strings.FieldsFunc(languages, func(r rune) bool { return r == '/' || r == ',' }) {
+		code := NormalizeLanguage(part)
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ArrChannelCount converts the audioChannels of an *arr file's media info,
+// written as a layout such as 5.1, into a channel count.
+func ArrChannelCount(channels float64) int {
+	whole := math.Floor(channels)
+	return int(whole) + int(math.Round((channels-whole)*10))
+}
+
+// ProbeAudioTracks lists the audio tracks of a file with ffprobe.
+func (hc *CmdHealthChecker) ProbeAudioTracks(path string) ([]AudioTrack, error) {
+	if err := validateMediaPath(path); err != nil {
+		return nil, fmt.Errorf("invalid media path: %w", err)
+	}
+
+	output, err := hc.runTool("ffprobe", hc.FFprobePath, []string{
+		"-v", "error", "-select_streams", "a",
+		"-show_entries", "stream=channels:stream_tags=language", "-of", "json", path,
+	}, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Streams []struct {
+			Channels int `json:"channels"`
+			Tags     struct {
+				Language string `json:"language"`
+			} `json:"tags"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	tracks := make([]AudioTrack, 0, len(result.Streams))
+	for _, s := range result.Streams {
+		tracks = append(tracks, AudioTrack{Language: NormalizeLanguage(s.Tags.Language), Channels: s.Channels})
+	}
+	return tracks, nil
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	for in, want := range map[string]string{
+		"eng":      "eng",
+		"en":       "eng",
+		"English":  "eng",
+		" GER ":    "deu",
+		"fre":      "fra",
+		"Japanese": "jpn",
+		"und":      "",
+		"":         "",
+		"kli":      "kli",
+	} {
+		if got := NormalizeLanguage(in); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseArrLanguages(t *testing.T) {
+	got := ParseArrLanguages("English / Japanese/english, Unknown")
+	if len(got) != 2 || got[0] != "eng" || got[1] != "jpn" {
+		t.Errorf("ParseArrLanguages = %v, want [eng jpn]", got)
+	}
+	if got := ParseArrLanguages(""); len(got) != 0 {
+		t.Errorf("ParseArrLanguages(\"\") = %v, want none", got)
+	}
+}
+
+func TestArrChannelCount(t *testing.T) {
+	for in, want := range map[float64]int{2: 2, 5.1: 6, 7.1: 8, 1: 1, 2.1: 3} {
+		if got := ArrChannelCount(in); got != want {
+			t.Errorf("ArrChannelCount(%v) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestProbeAudioTracks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires shell scripts as fake detectors")
+	}
+
+	dir := t.TempDir()
+	media := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(media, []byte("media"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ffprobe := filepath.Join(dir, "ffprobe")
+	script := `#!/bin/sh
+echo '{"streams": [{"channels": 6, "tags": {"language": "eng"}}, {"channels": 2, "tags": {"language": "ja"}}, {"channels": 2}]}'
+`
+	if err := os.WriteFile(ffprobe, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tracks, err := NewHealthCheckerWithPaths(ffprobe, "ffmpeg", "mediainfo", "HandBrakeCLI").ProbeAudioTracks(media)
+	if err != nil {
+		t.Fatalf("ProbeAudioTracks failed: %v", err)
+	}
+	want := []AudioTrack{{Language: "eng", Channels: 6}, {Language: "jpn", Channels: 2}, {Channels: 2}}
+	if len(tracks) != len(want) {
+		t.Fatalf("Expected %d tracks, got %+v", len(want), tracks)
+	}
+	for i := range want {
+		if tracks[i] != want[i] {
+			t.Errorf("Track %d = %+v, want %+v", i, tracks[i], want[i])
+		}
+	}
+}
@@ -63,6 +63,7 @@ type CircuitBreaker struct {
 	successes       int       // consecutive successes (for half-open state)
 	lastFailureTime time.Time // when the last failure occurred
 	lastStateChange time.Time // when the state last changed
+	openedAt        time.Time // when the circuit last opened from closed; zero while closed
 	totalFailures   int64     // total failures (for stats)
 	totalSuccesses  int64     // total successes (for stats)
 	totalRejected   int64     // requests rejected due to open circuit
@@ -134,6 +135,7 @@ func (cb *CircuitBreaker) RecordSuccess() {
 		if cb.successes >= cb.config.SuccessThreshold {
 			cb.state = CircuitClosed
 			cb.lastStateChange = time.Now()
+			cb.openedAt = time.Time{}
 			cb.failures = 0
 			cb.successes = 0
 		}
@@ -161,6 +163,7 @@ func (cb *CircuitBreaker) RecordFailure() {
 		if cb.failures >= cb.config.FailureThreshold {
 			cb.state = CircuitOpen
 			cb.lastStateChange = time.Now()
+			cb.openedAt = cb.lastStateChange
 		}
 
 	case CircuitHalfOpen:
@@ -189,6 +192,7 @@ func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
 		ConsecutiveFailures: cb.failures,
 		LastFailureTime:     cb.lastFailureTime,
 		LastStateChange:     cb.lastStateChange,
+		OpenSince:           cb.openedAt,
 		TotalFailures:       cb.totalFailures,
 		TotalSuccesses:      cb.totalSuccesses,
 		TotalRejected:       cb.totalRejected,
@@ -203,6 +207,7 @@ func (cb *CircuitBreaker) Reset() {
 	cb.failures = 0
 	cb.successes = 0
 	cb.lastStateChange = time.Now()
+	cb.openedAt = time.Time{}
 }
 
 // CircuitBreakerStats holds statistics for monitoring.
@@ -211,6 +216,7 @@ type CircuitBreakerStats struct {
 	ConsecutiveFailures int
 	LastFailureTime     time.Time
 	LastStateChange     time.Time
+	OpenSince           time.Time // start of the current outage (open or half-open); zero while closed
 	TotalFailures       int64
 	TotalSuccesses      int64
 	TotalRejected       int64
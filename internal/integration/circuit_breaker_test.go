@@ -239,6 +239,39 @@ func TestCircuitBreaker_ReopensOnFailureInHalfOpen(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_OpenSinceSpansWholeOutage(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		ResetTimeout:     50 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+
+	if !cb.Stats().OpenSince.IsZero() {
+		t.Fatal("OpenSince should be zero while closed")
+	}
+
+	cb.RecordFailure()
+	openedAt := cb.Stats().OpenSince
+	if openedAt.IsZero() {
+		t.Fatal("OpenSince should be set once the circuit opens")
+	}
+
+	// A failed probe reopens the circuit but the outage started earlier
+	time.Sleep(60 * time.Millisecond)
+	cb.Allow()
+	cb.RecordFailure()
+	if got := cb.Stats().OpenSince; !got.Equal(openedAt) {
+		t.Errorf("OpenSince = %v, want %v", got, openedAt)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	cb.Allow()
+	cb.RecordSuccess()
+	if cb.State() != CircuitClosed || !cb.Stats().OpenSince.IsZero() {
+		t.Errorf("Expected closed circuit with zero OpenSince, got %v / %v", cb.State(), cb.Stats().OpenSince)
+	}
+}
+
 func TestCircuitBreaker_Reset(t *testing.T) {
 	cb := NewCircuitBreaker(CircuitBreakerConfig{
 		FailureThreshold: 2,
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// baseConfidence is how sure a corruption finding of each type is on its own,
+// in percent. CorruptHeader is also what unrecognized detector failures are
+// classified as, so it starts lower than the stream errors a decode reports.
+var baseConfidence = map[string]int{
+	ErrorTypeZeroByte:         100,
+	ErrorTypeUndersized:       100,
+	ErrorTypeCorruptStream:    80,
+	ErrorTypeInvalidFormat:    75,
+	ErrorTypeDurationMismatch: 70,
+	ErrorTypeCorruptHeader:    60,
+	ErrorTypeBlackVideo:       60,
+	ErrorTypeFrozenVideo:      60,
+	ErrorTypeHDRMetadata:      60,
+	ErrorTypeSilentAudio:      50,
+}
+
+// Adjustments of the confidence score, in percentage points.
+const (
+	confidencePerError      = 5  // every error beyond the first the tool reported
+	confidenceMaxErrorBonus = 15 // cap of the error count bonus
+	confidenceSingleError   = -5 // a single error line, e.g. one bad packet
+	confidencePerAgreement  = 10 // every other detector that also found corruption
+	confidencePerDisagree   = 30 // every other detector that found the file healthy
+)
+
+// sampledErrorsRe extracts the decode error count from a sampled check.
+var sampledErrorsRe = regexp.MustCompile(`\((\d+) total`)
+
+// ConfidenceScore returns how sure the finding is that the file is corrupt,
+// from 0 to 100. Checks that didn't score it get the base confidence of their
+// type.
+func (e *HealthCheckError) ConfidenceScore() int {
+	if e.Confidence > 0 {
+		return e.Confidence
+	}
+	return ScoreConfidence(e.Type, 0, 0, 0)
+}
+
+// ScoreConfidence scores a corruption finding of errType: the base confidence
+// of the type, raised by the number of errors the tool reported and by other
+// detectors that agree, lowered by a lone error and by detectors that found
+// the file healthy. errorCount 0 means unknown. Corruption types score at
+// least 1, since 0 means unscored; other types score 0.
+func ScoreConfidence(errType string, errorCount, agreed, disagreed int) int {
+	score, ok := baseConfidence[errType]
+	if !ok {
+		return 0
+	}
+	if score < 100 {
+		switch {
+		case errorCount == 1:
+			score += confidenceSingleError
+		case errorCount > 1:
+			score += min((errorCount-1)*confidencePerError, confidenceMaxErrorBonus)
+		}
+	}
+	score += agreed*confidencePerAgreement - disagreed*confidencePerDisagree
+	return max(1, min(100, score))
+}
+
+// countReportedErrors returns how many errors a detector reported in its
+// message: the decode error total of a sampled check, otherwise the error
+// lines of the tool output.
+func countReportedErrors(message string) int {
+	if m := sampledErrorsRe.FindStringSubmatch(message); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	count := 0
+	for _, line := range strings.Split(message, "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// confirmationDetectors returns the detectors that cross-check a corruption
+// found by method: the other detectors of the chain, or the default fallbacks
+// of method if the chain has none.
+func confirmationDetectors(method DetectionMethod, config DetectionConfig) []DetectionMethod {
+	candidates := append([]DetectionMethod{config.Method}, config.Fallbacks...)
+	if len(candidates) == 1 {
+		candidates = DefaultFallbacksFor(method)
+	}
+	var others []DetectionMethod
+	seen := map[DetectionMethod]bool{method: true, DetectionZeroByte: true}
+	for _, m := range candidates {
+		if !seen[m] {
+			seen[m] = true
+			others = append(others, m)
+		}
+	}
+	return others
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScoreConfidence(t *testing.T) {
+	tests := []struct {
+		name                          string
+		errType                       string
+		errorCount, agreed, disagreed int
+		want                          int
+	}{
+		{"zero byte is certain", ErrorTypeZeroByte, 0, 0, 0, 100},
+		{"zero byte ignores error count", ErrorTypeZeroByte, 1, 0, 0, 100},
+		{"unknown error count", ErrorTypeCorruptStream, 0, 0, 0, 80},
+		{"single error", ErrorTypeCorruptStream, 1, 0, 0, 75},
+		{"several errors", ErrorTypeCorruptStream, 3, 0, 0, 90},
+		{"error bonus is capped", ErrorTypeCorruptHeader, 50, 0, 0, 75},
+		{"agreeing detector", ErrorTypeCorruptHeader, 0, 1, 0, 70},
+		{"disagreeing detector", ErrorTypeCorruptHeader, 1, 0, 1, 25},
+		{"never above 100", ErrorTypeCorruptStream, 10, 2, 0, 100},
+		{"never below 1", ErrorTypeSilentAudio, 1, 0, 2, 1},
+		{"not a corruption", ErrorTypeTimeout, 5, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScoreConfidence(tt.errType, tt.errorCount, tt.agreed, tt.disagreed); got != tt.want {
+				t.Errorf("ScoreConfidence() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthCheckError_ConfidenceScore(t *testing.T) {
+	scored := &HealthCheckError{Type: ErrorTypeCorruptStream, Confidence: 42}
+	if got := scored.ConfidenceScore(); got != 42 {
+		t.Errorf("ConfidenceScore() = %d, want the scored 42", got)
+	}
+	unscored := &HealthCheckError{Type: ErrorTypeInvalidFormat}
+	if got := unscored.ConfidenceScore(); got != 75 {
+		t.Errorf("ConfidenceScore() = %d, want the base confidence 75", got)
+	}
+}
+
+func TestCountReportedErrors(t *testing.T) {
+	for message, want := range map[string]int{
+		"":                         0,
+		"Invalid data found":       1,
+		"error one\n\nerror two\n": 2,
+		"ffmpeg failed: 2 of 8 sampled segments had decode errors (12 total, first invalid NAL)": 12,
+	} {
+		if got := countReportedErrors(message); got != want {
+			t.Errorf("countReportedErrors(%q) = %d, want %d", message, got, want)
+		}
+	}
+}
+
+func TestConfirmationDetectors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method DetectionMethod
+		config DetectionConfig
+		want   []DetectionMethod
+	}{
+		{"default fallbacks", DetectionFFprobe, DetectionConfig{Method: DetectionFFprobe}, []DetectionMethod{DetectionMediaInfo}},
+		{"configured chain", DetectionFFprobe, DetectionConfig{Method: DetectionFFprobe, Fallbacks: []DetectionMethod{DetectionHandBrake, DetectionMediaInfo}}, []DetectionMethod{DetectionHandBrake, DetectionMediaInfo}},
+		{"found by a fallback", DetectionMediaInfo, DetectionConfig{Method: DetectionFFprobe, Fallbacks: []DetectionMethod{DetectionMediaInfo}}, []DetectionMethod{DetectionFFprobe}},
+		{"zero byte and duplicates skipped", DetectionFFprobe, DetectionConfig{Method: DetectionFFprobe, Fallbacks: []DetectionMethod{DetectionZeroByte, DetectionMediaInfo, DetectionMediaInfo}}, []DetectionMethod{DetectionMediaInfo}},
+		{"nothing to confirm with", DetectionZeroByte, DetectionConfig{Method: DetectionZeroByte}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confirmationDetectors(tt.method, tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("confirmationDetectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConsensusPolicy is how many detectors must find a file corrupt before the
+// corruption is reported. The empty policy turns consensus checking off.
+type ConsensusPolicy string
+
+// Consensus policies.
+const (
+	ConsensusAny      ConsensusPolicy = "any"      // one detector is enough; disagreements are only recorded
+	ConsensusMajority ConsensusPolicy = "majority" // more detectors find the file corrupt than healthy
+	ConsensusAll      ConsensusPolicy = "all"      // no detector finds the file healthy
+)
+
+// Verdicts of a detector that cross-checked a corrupt file.
+const (
+	VerdictCorrupt     = "corrupt"
+	VerdictHealthy     = "healthy"
+	VerdictUnavailable = "unavailable" // the detector couldn't check the file, e.g. a missing binary
+)
+
+// DetectorVerdict is what one detector found when checking a corrupt file.
+type DetectorVerdict struct {
+	Method  DetectionMethod `json:"method"`
+	Verdict string          `json:"verdict"`
+	Type    string          `json:"type,omitempty"` // error type, unless healthy
+}
+
+// Reached reports whether the verdicts meet the policy. Detectors that
+// couldn't check the file don't count, so a finding no other detector could
+// check stands.
+func (p ConsensusPolicy) Reached(verdicts []DetectorVerdict) bool {
+	var corrupt, healthy int
+	for _, v := range verdicts {
+		switch v.Verdict {
+		case VerdictCorrupt:
+			corrupt++
+		case VerdictHealthy:
+			healthy++
+		}
+	}
+	switch p {
+	case ConsensusMajority:
+		return corrupt > healthy
+	case ConsensusAll:
+		return healthy == 0
+	default:
+		return corrupt > 0
+	}
+}
+
+// crossCheck scores the confidence of a corruption found by method. With
+// config.Confirm or a consensus policy, the other detectors check the file
+// too: their verdicts are recorded on herr and count as agreement or
+// disagreement.
+func (hc *CmdHealthChecker) crossCheck(path string, method DetectionMethod, herr *HealthCheckError, config DetectionConfig, mode string) {
+	var agreed, disagreed int
+	if config.Confirm || config.Consensus != "" {
+		herr.Verdicts = []DetectorVerdict{{Method: method, Verdict: VerdictCorrupt, Type: herr.Type}}
+		for _, other := range confirmationDetectors(method, config) {
+			verdict := DetectorVerdict{Method: other, Verdict: VerdictHealthy}
+			ok, oerr := hc.runSingleDetector(path, other, nil, mode)
+			switch {
+			case ok:
+				disagreed++
+			case oerr != nil && oerr.IsTrueCorruption():
+				agreed++
+				verdict.Verdict, verdict.Type = VerdictCorrupt, oerr.Type
+			default:
+				verdict.Verdict, verdict.Type = VerdictUnavailable, errTypeOrUnknown(oerr)
+			}
+			herr.Verdicts = append(herr.Verdicts, verdict)
+		}
+	}
+	herr.Confidence = ScoreConfidence(herr.Type, countReportedErrors(herr.Message), agreed, disagreed)
+}
+
+// noConsensus turns a corruption the detectors didn't agree on as policy
+// requires into a NoConsensus finding, which isn't remediated.
+func noConsensus(herr *HealthCheckError, policy ConsensusPolicy) *HealthCheckError {
+	var healthy []string
+	for _, v := range herr.Verdicts {
+		if v.Verdict == VerdictHealthy {
+			healthy = append(healthy, string(v.Method))
+		}
+	}
+	return &HealthCheckError{
+		Type: ErrorTypeNoConsensus,
+		Message: fmt.Sprintf("%s found by %s, but %s found the file healthy (consensus: %s): %s",
+			herr.Type, herr.Verdicts[0].Method, strings.Join(healthy, ", "), policy, herr.Message),
+		Confidence: herr.Confidence,
+		Verdicts:   herr.Verdicts,
+	}
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConsensusPolicy_Reached(t *testing.T) {
+	corrupt := DetectorVerdict{Method: DetectionFFprobe, Verdict: VerdictCorrupt}
+	healthy := DetectorVerdict{Method: DetectionMediaInfo, Verdict: VerdictHealthy}
+	unavailable := DetectorVerdict{Method: DetectionHandBrake, Verdict: VerdictUnavailable}
+	agree := DetectorVerdict{Method: DetectionHandBrake, Verdict: VerdictCorrupt}
+
+	tests := []struct {
+		name     string
+		verdicts []DetectorVerdict
+		want     map[ConsensusPolicy]bool
+	}{
+		{"no other detector", []DetectorVerdict{corrupt}, map[ConsensusPolicy]bool{ConsensusAny: true, ConsensusMajority: true, ConsensusAll: true}},
+		{"others unavailable", []DetectorVerdict{corrupt, unavailable}, map[ConsensusPolicy]bool{ConsensusAny: true, ConsensusMajority: true, ConsensusAll: true}},
+		{"tie", []DetectorVerdict{corrupt, healthy}, map[ConsensusPolicy]bool{ConsensusAny: true, ConsensusMajority: false, ConsensusAll: false}},
+		{"majority", []DetectorVerdict{corrupt, healthy, agree}, map[ConsensusPolicy]bool{ConsensusAny: true, ConsensusMajority: true, ConsensusAll: false}},
+		{"unanimous", []DetectorVerdict{corrupt, agree}, map[ConsensusPolicy]bool{ConsensusAny: true, ConsensusMajority: true, ConsensusAll: true}},
+	}
+	for _, tt := range tests {
+		for policy, want := range tt.want {
+			if got := policy.Reached(tt.verdicts); got != want {
+				t.Errorf("%s: %s.Reached() = %v, want %v", tt.name, policy, got, want)
+			}
+		}
+	}
+}
+
+func TestCheckWithConfig_Consensus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires shell scripts as fake detectors")
+	}
+
+	// ffprobe rejects the file, MediaInfo reads it fine
+	dir := t.TempDir()
+	ffprobe := filepath.Join(dir, "ffprobe")
+	if err := os.WriteFile(ffprobe, []byte("#!/bin/sh\necho 'Invalid data found when processing input' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mediainfo := filepath.Join(dir, "mediainfo")
+	script := "#!/bin/sh\necho '{\"media\":{\"track\":[{\"@type\":\"General\"},{\"@type\":\"Video\"}]}}'\n"
+	if err := os.WriteFile(mediainfo, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	media := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(media, []byte("not a video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hc := NewHealthCheckerWithPaths(ffprobe, ffprobe, mediainfo, filepath.Join(dir, "missing"))
+
+	check := func(policy ConsensusPolicy) *HealthCheckError {
+		t.Helper()
+		healthy, herr := hc.CheckWithConfig(media, DetectionConfig{Method: DetectionFFprobe, Mode: ModeQuick, Consensus: policy})
+		if healthy || herr == nil {
+			t.Fatalf("Expected a finding with consensus %q", policy)
+		}
+		return herr
+	}
+
+	herr := check("")
+	if herr.Type != ErrorTypeCorruptHeader || len(herr.Verdicts) != 0 {
+		t.Errorf("Without consensus the file should only be checked once: %+v", herr)
+	}
+
+	herr = check(ConsensusAny)
+	if herr.Type != ErrorTypeCorruptHeader {
+		t.Errorf("Expected the corruption to stand under %q, got %s", ConsensusAny, herr.Type)
+	}
+	want := []DetectorVerdict{
+		{Method: DetectionFFprobe, Verdict: VerdictCorrupt, Type: ErrorTypeCorruptHeader},
+		{Method: DetectionMediaInfo, Verdict: VerdictHealthy},
+	}
+	if len(herr.Verdicts) != 2 || herr.Verdicts[0] != want[0] || herr.Verdicts[1] != want[1] {
+		t.Errorf("Verdicts = %+v, want %+v", herr.Verdicts, want)
+	}
+
+	herr = check(ConsensusMajority)
+	if herr.Type != ErrorTypeNoConsensus || herr.IsTrueCorruption() || herr.IsRecoverable() {
+		t.Fatalf("Expected the corruption to be overruled, got %+v", herr)
+	}
+	if !strings.Contains(herr.Message, "CorruptHeader found by ffprobe, but mediainfo found the file healthy") {
+		t.Errorf("Unexpected message: %s", herr.Message)
+	}
+	if len(herr.Diagnostics) != 2 {
+		t.Errorf("Expected the runs of both detectors to be recorded, got %d", len(herr.Diagnostics))
+	}
+}
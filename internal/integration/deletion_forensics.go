@@ -0,0 +1,220 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxArrResponseBody bounds how much of a failed *arr response body is kept.
+const maxArrResponseBody = 4 * 1024
+
+// Causes of a failed deletion, worked out by InspectDeletionFailure.
+const (
+	DeletionCausePermissionDenied = "permission_denied" // Healarr or the *arr may not delete the file
+	DeletionCauseReadOnly         = "read_only"         // The file system is mounted read-only
+	DeletionCauseFileLocked       = "file_locked"       // Another process has the file open
+	DeletionCauseFileMissing      = "file_missing"      // The file is already gone
+	DeletionCauseMountLost        = "mount_lost"        // The file's folder isn't reachable
+	DeletionCauseArrError         = "arr_error"         // The *arr instance answered with an error
+	DeletionCauseArrUnreachable   = "arr_unreachable"   // The *arr instance didn't answer
+	DeletionCauseNotInArr         = "not_in_arr"        // The *arr doesn't track the file
+	DeletionCauseUnknown          = "unknown"
+)
+
+// ArrResponseError is an unsuccessful *arr API response. Body holds the start
+// of the response, where the *arr explains what went wrong.
+type ArrResponseError struct {
+	Message    string
+	StatusCode int
+	Body       string
+}
+
+func (e *ArrResponseError) Error() string {
+	return e.Message
+}
+
+// newArrResponseError reads the start of body into an ArrResponseError.
+func newArrResponseError(message string, statusCode int, body io.Reader) *ArrResponseError {
+	data, _ := io.ReadAll(io.LimitReader(body, maxArrResponseBody))
+	return &ArrResponseError{Message: message, StatusCode: statusCode, Body: strings.TrimSpace(string(data))}
+}
+
+// FileState is what stat() reported for a file.
+type FileState struct {
+	Exists  bool      `json:"exists"`
+	Size    int64     `json:"size,omitempty"`
+	Mode    string    `json:"mode,omitempty"`
+	ModTime time.Time `json:"mod_time,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// MountState is whether the folder holding a file was usable.
+type MountState struct {
+	Dir        string `json:"dir"`
+	Accessible bool   `json:"accessible"`
+	Writable   bool   `json:"writable"`
+	ReadOnly   bool   `json:"read_only,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DeletionForensics describes why a deletion failed: the *arr's answer and
+// the state of the file and its folder right after the failure.
+type DeletionForensics struct {
+	Cause       string     `json:"cause"`
+	Retryable   bool       `json:"retryable"`
+	Error       string     `json:"error"`
+	ArrStatus   int        `json:"arr_status,omitempty"`
+	ArrResponse string     `json:"arr_response,omitempty"`
+	File        FileState  `json:"file"`
+	Mount       MountState `json:"mount"`
+	CapturedAt  time.Time  `json:"captured_at"`
+}
+
+// InspectDeletionFailure captures the state behind a failed deletion of
+// localPath and classifies the failure, so permission problems can be told
+// apart from *arr errors and locked files.
+func InspectDeletionFailure(err error, localPath string) *DeletionForensics {
+	f := &DeletionForensics{
+		File:       statFile(localPath),
+		Mount:      inspectMount(filepath.Dir(localPath)),
+		CapturedAt: time.Now().UTC(),
+	}
+	if err != nil {
+		f.Error = err.Error()
+	}
+
+	var arrErr *ArrResponseError
+	if errors.As(err, &arrErr) {
+		f.ArrStatus = arrErr.StatusCode
+		f.ArrResponse = arrErr.Body
+	}
+
+	f.Cause = classifyDeletionError(err, arrErr)
+	// Without a better explanation, the folder's state tells what happened
+	if f.Cause == DeletionCauseUnknown || f.Cause == DeletionCauseArrError {
+		switch {
+		case !f.Mount.Accessible:
+			f.Cause = DeletionCauseMountLost
+		case f.Mount.ReadOnly:
+			f.Cause = DeletionCauseReadOnly
+		}
+	}
+
+	switch f.Cause {
+	case DeletionCausePermissionDenied, DeletionCauseReadOnly, DeletionCauseNotInArr:
+		// Retrying gives the same result until someone fixes the setup
+		f.Retryable = false
+	case DeletionCauseArrError:
+		f.Retryable = arrErr == nil || arrErr.StatusCode >= 500 || arrErr.StatusCode == 429
+	default:
+		f.Retryable = true
+	}
+	return f
+}
+
+// classifyDeletionError works out the cause of a failed deletion from the
+// error itself.
+func classifyDeletionError(err error, arrErr *ArrResponseError) string {
+	var notInArr *FileNotInArrError
+	switch {
+	case err == nil:
+		return DeletionCauseUnknown
+	case errors.As(err, &notInArr):
+		return DeletionCauseNotInArr
+	case errors.Is(err, ErrCircuitOpen):
+		return DeletionCauseArrUnreachable
+	}
+
+	// The *arr reports file system errors in the response body
+	if arrErr != nil {
+		if cause := classifyDeletionMessage(arrErr.Body); cause != "" {
+			return cause
+		}
+		return DeletionCauseArrError
+	}
+
+	if cause := classifyDeletionErrno(err); cause != "" {
+		return cause
+	}
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return DeletionCausePermissionDenied
+	case errors.Is(err, fs.ErrNotExist):
+		return DeletionCauseFileMissing
+	}
+	if errType, _ := classifySyscallError(err); errType == ErrorTypeMountLost {
+		return DeletionCauseMountLost
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || isRetryableError(err) {
+		return DeletionCauseArrUnreachable
+	}
+	if cause := classifyDeletionMessage(err.Error()); cause != "" {
+		return cause
+	}
+	return DeletionCauseUnknown
+}
+
+// classifyDeletionMessage recognizes the file system errors Sonarr, Radarr and
+// friends put into their error messages, on Linux and Windows hosts.
+func classifyDeletionMessage(msg string) string {
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "being used by another process"),
+		strings.Contains(msg, "resource busy"),
+		strings.Contains(msg, "text file busy"),
+		strings.Contains(msg, "sharing violation"):
+		return DeletionCauseFileLocked
+	case strings.Contains(msg, "read-only file system"):
+		return DeletionCauseReadOnly
+	case strings.Contains(msg, "permission denied"),
+		strings.Contains(msg, "access to the path") && strings.Contains(msg, "denied"),
+		strings.Contains(msg, "unauthorizedaccessexception"):
+		return DeletionCausePermissionDenied
+	case strings.Contains(msg, "could not find file"),
+		strings.Contains(msg, "no such file or directory"):
+		return DeletionCauseFileMissing
+	}
+	return ""
+}
+
+// statFile reports what stat() says about path.
+func statFile(path string) FileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileState{Error: err.Error()}
+	}
+	return FileState{
+		Exists:  true,
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime().UTC(),
+	}
+}
+
+// inspectMount checks whether dir can be listed and written to.
+func inspectMount(dir string) MountState {
+	state := MountState{Dir: dir}
+	info, err := os.Stat(dir)
+	if err == nil && !info.IsDir() {
+		err = fmt.Errorf("not a directory")
+	}
+	if err == nil {
+		_, err = os.ReadDir(dir)
+	}
+	if err != nil {
+		state.Error = err.Error()
+		return state
+	}
+	state.Accessible = true
+	state.Writable, state.ReadOnly = dirWritable(dir, info)
+	return state
+}
@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspectDeletionFailure(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(file, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, removeErr := os.Stat(filepath.Join(dir, "gone.mkv"))
+
+	tests := []struct {
+		name          string
+		err           error
+		path          string
+		wantCause     string
+		wantRetryable bool
+		wantStatus    int
+	}{
+		{
+			name:          "arr reports permission denied",
+			err:           &ArrResponseError{Message: "*arr API returned 500 after 3 attempts", StatusCode: 500, Body: `{"message": "Access to the path '/movies/movie.mkv' is denied."}`},
+			path:          file,
+			wantCause:     DeletionCausePermissionDenied,
+			wantRetryable: false,
+			wantStatus:    500,
+		},
+		{
+			name:          "arr reports a locked file",
+			err:           &ArrResponseError{Message: "*arr API returned 500 after 3 attempts", StatusCode: 500, Body: "The process cannot access the file because it is being used by another process."},
+			path:          file,
+			wantCause:     DeletionCauseFileLocked,
+			wantRetryable: true,
+			wantStatus:    500,
+		},
+		{
+			name:          "arr server error",
+			err:           &ArrResponseError{Message: "*arr API returned 500 after 3 attempts", StatusCode: 500, Body: "NullReferenceException"},
+			path:          file,
+			wantCause:     DeletionCauseArrError,
+			wantRetryable: true,
+			wantStatus:    500,
+		},
+		{
+			name:          "arr rejects the request",
+			err:           fmt.Errorf("wrapped: %w", &ArrResponseError{Message: "failed to delete file: 400 Bad Request", StatusCode: 400}),
+			path:          file,
+			wantCause:     DeletionCauseArrError,
+			wantRetryable: false,
+			wantStatus:    400,
+		},
+		{
+			name:          "arr unreachable",
+			err:           fmt.Errorf("*arr API unavailable after 3 attempts: %w", &url.Error{Op: "Delete", URL: "http://radarr", Err: errors.New("connection refused")}),
+			path:          file,
+			wantCause:     DeletionCauseArrUnreachable,
+			wantRetryable: true,
+		},
+		{
+			name:          "circuit breaker open",
+			err:           fmt.Errorf("%w: Radarr is unhealthy", ErrCircuitOpen),
+			path:          file,
+			wantCause:     DeletionCauseArrUnreachable,
+			wantRetryable: true,
+		},
+		{
+			name:          "not tracked by the arr",
+			err:           &FileNotInArrError{ArrType: "radarr", Path: file},
+			path:          file,
+			wantCause:     DeletionCauseNotInArr,
+			wantRetryable: false,
+		},
+		{
+			name:          "file already gone",
+			err:           removeErr,
+			path:          filepath.Join(dir, "gone.mkv"),
+			wantCause:     DeletionCauseFileMissing,
+			wantRetryable: true,
+		},
+		{
+			name:          "folder unreachable",
+			err:           errors.New("something went wrong"),
+			path:          filepath.Join(dir, "missing", "movie.mkv"),
+			wantCause:     DeletionCauseMountLost,
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := InspectDeletionFailure(tt.err, tt.path)
+			if f.Cause != tt.wantCause {
+				t.Errorf("Cause = %q, want %q", f.Cause, tt.wantCause)
+			}
+			if f.Retryable != tt.wantRetryable {
+				t.Errorf("Retryable = %v, want %v", f.Retryable, tt.wantRetryable)
+			}
+			if f.ArrStatus != tt.wantStatus {
+				t.Errorf("ArrStatus = %d, want %d", f.ArrStatus, tt.wantStatus)
+			}
+			if f.Error != tt.err.Error() {
+				t.Errorf("Error = %q, want %q", f.Error, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestInspectDeletionFailure_CapturesFileAndMount(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "episode.mkv")
+	if err := os.WriteFile(file, []byte("corrupt data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := InspectDeletionFailure(&ArrResponseError{StatusCode: 500, Body: "boom"}, file)
+	if !f.File.Exists || f.File.Size != 12 || f.File.Mode == "" || f.File.ModTime.IsZero() {
+		t.Errorf("Expected the file's stat() results, got %+v", f.File)
+	}
+	if f.Mount.Dir != dir || !f.Mount.Accessible || !f.Mount.Writable || f.Mount.ReadOnly {
+		t.Errorf("Expected a usable folder, got %+v", f.Mount)
+	}
+	if f.ArrResponse != "boom" {
+		t.Errorf("Expected the response body, got %q", f.ArrResponse)
+	}
+
+	missing := InspectDeletionFailure(errors.New("boom"), filepath.Join(dir, "nope", "episode.mkv"))
+	if missing.File.Exists || missing.File.Error == "" || missing.Mount.Accessible || missing.Mount.Error == "" {
+		t.Errorf("Expected stat() errors to be kept, got %+v / %+v", missing.File, missing.Mount)
+	}
+}
+
+func TestClassifyDeletionMessage(t *testing.T) {
+	tests := map[string]string{
+		"System.IO.IOException: Device or resource busy":                    DeletionCauseFileLocked,
+		"System.IO.IOException: Read-only file system : '/tv/show.mkv'":     DeletionCauseReadOnly,
+		"System.UnauthorizedAccessException: Access to the path is denied.": DeletionCausePermissionDenied,
+		"remove /tv/show.mkv: permission denied":                            DeletionCausePermissionDenied,
+		"Could not find file '/tv/show.mkv'.":                               DeletionCauseFileMissing,
+		"Object reference not set to an instance of an object.":             "",
+	}
+	for msg, want := range tests {
+		if got := classifyDeletionMessage(msg); got != want {
+			t.Errorf("classifyDeletionMessage(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
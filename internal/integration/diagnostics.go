@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDiagnosticOutput bounds how much of a tool's stdout/stderr is kept per run.
+// Longer output keeps its beginning and end, which is where tools report errors.
+const maxDiagnosticOutput = 16 * 1024
+
+// maxDiagnosticBlobSize bounds the decompressed size accepted by DecodeDiagnostics.
+const maxDiagnosticBlobSize = 4 << 20
+
+// ToolRun is the captured output of a single detection tool invocation.
+type ToolRun struct {
+	Tool       string   `json:"tool"`
+	Args       []string `json:"args"`
+	ExitCode   int      `json:"exit_code"` // -1 when the tool did not exit normally
+	DurationMs int64    `json:"duration_ms"`
+	Stdout     string   `json:"stdout,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// CheckDiagnostics describes why a health check failed: the classified error
+// and the raw output of every tool run during the check. Entries recorded for
+// a failed deletion carry its forensics instead of tool runs.
+type CheckDiagnostics struct {
+	ErrorType string             `json:"error_type"`
+	Message   string             `json:"message"`
+	Runs      []ToolRun          `json:"runs"`
+	Deletion  *DeletionForensics `json:"deletion,omitempty"`
+}
+
+// NewCheckDiagnostics builds the diagnostics for a failed check.
+func NewCheckDiagnostics(herr *HealthCheckError) CheckDiagnostics {
+	d := CheckDiagnostics{Runs: []ToolRun{}}
+	if herr != nil {
+		d.ErrorType = herr.Type
+		d.Message = herr.Message
+		if herr.Diagnostics != nil {
+			d.Runs = herr.Diagnostics
+		}
+	}
+	return d
+}
+
+// EncodeDiagnostics serializes diagnostics as gzip-compressed JSON.
+func EncodeDiagnostics(d CheckDiagnostics) ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeDiagnostics reverses EncodeDiagnostics.
+func DecodeDiagnostics(blob []byte) (CheckDiagnostics, error) {
+	var d CheckDiagnostics
+	zr, err := gzip.NewReader(bytes.NewReader(blob))
+	if err != nil {
+		return d, fmt.Errorf("invalid diagnostics blob: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(io.LimitReader(zr, maxDiagnosticBlobSize+1))
+	if err != nil {
+		return d, fmt.Errorf("invalid diagnostics blob: %w", err)
+	}
+	if len(data) > maxDiagnosticBlobSize {
+		return d, errors.New("diagnostics blob too large")
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return d, fmt.Errorf("invalid diagnostics blob: %w", err)
+	}
+	return d, nil
+}
+
+// toolRunRecorder collects the tool runs made during one health check.
+type toolRunRecorder struct {
+	mu   sync.Mutex
+	runs []ToolRun
+}
+
+func (r *toolRunRecorder) record(tool string, args []string, stdout, stderr []byte, err error, elapsed time.Duration) {
+	run := ToolRun{
+		Tool:       tool,
+		Args:       append([]string(nil), args...),
+		DurationMs: elapsed.Milliseconds(),
+		Stdout:     trimDiagnosticOutput(stdout),
+		Stderr:     trimDiagnosticOutput(stderr),
+	}
+	if err != nil {
+		run.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			run.ExitCode = exitErr.ExitCode()
+		}
+		run.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.runs = append(r.runs, run)
+	r.mu.Unlock()
+}
+
+func (r *toolRunRecorder) snapshot() []ToolRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.runs) == 0 {
+		return nil
+	}
+	return append([]ToolRun(nil), r.runs...)
+}
+
+// trimDiagnosticOutput trims whitespace and, if the output is still too long,
+// keeps its head and tail.
+func trimDiagnosticOutput(b []byte) string {
+	s := strings.TrimSpace(string(b))
+	if len(s) <= maxDiagnosticOutput {
+		return s
+	}
+	half := maxDiagnosticOutput / 2
+	omitted := len(s) - 2*half
+	return fmt.Sprintf("%s\n... [%d bytes omitted] ...\n%s", s[:half], omitted, s[len(s)-half:])
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeDiagnostics(t *testing.T) {
+	in := CheckDiagnostics{
+		ErrorType: ErrorTypeCorruptHeader,
+		Message:   "ffprobe failed: moov atom not found",
+		Runs: []ToolRun{{
+			Tool: "ffprobe", Args: []string{"-v", "error", "/media/a.mkv"},
+			ExitCode: 1, DurationMs: 42, Stderr: "moov atom not found",
+		}},
+	}
+
+	blob, err := EncodeDiagnostics(in)
+	if err != nil {
+		t.Fatalf("EncodeDiagnostics failed: %v", err)
+	}
+	out, err := DecodeDiagnostics(blob)
+	if err != nil {
+		t.Fatalf("DecodeDiagnostics failed: %v", err)
+	}
+	if out.ErrorType != in.ErrorType || len(out.Runs) != 1 || out.Runs[0].Stderr != "moov atom not found" || out.Runs[0].ExitCode != 1 {
+		t.Errorf("Round trip mismatch: %+v", out)
+	}
+
+	if _, err := DecodeDiagnostics([]byte("not gzip")); err == nil {
+		t.Error("Expected error for invalid blob")
+	}
+}
+
+func TestTrimDiagnosticOutput(t *testing.T) {
+	if got := trimDiagnosticOutput([]byte("  error line\n")); got != "error line" {
+		t.Errorf("trimDiagnosticOutput = %q", got)
+	}
+
+	long := "HEAD" + strings.Repeat("x", 2*maxDiagnosticOutput) + "TAIL"
+	got := trimDiagnosticOutput([]byte(long))
+	if len(got) > maxDiagnosticOutput+64 {
+		t.Errorf("Output not trimmed: %d bytes", len(got))
+	}
+	if !strings.HasPrefix(got, "HEAD") || !strings.HasSuffix(got, "TAIL") || !strings.Contains(got, "bytes omitted") {
+		t.Errorf("Expected head and tail to be kept, got %q...", got[:32])
+	}
+}
+
+func TestCheckWithConfig_RecordsDiagnostics(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a shell script as a fake detector")
+	}
+
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "ffprobe")
+	script := "#!/bin/sh\necho 'moov atom not found' >&2\nexit 1\n"
+	if err := os.WriteFile(tool, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	media := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(media, []byte("not a video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hc := NewHealthCheckerWithPaths(tool, tool, tool, tool)
+	healthy, herr := hc.CheckWithConfig(media, DetectionConfig{Method: DetectionFFprobe, Mode: ModeQuick})
+	if healthy || herr == nil {
+		t.Fatal("Expected check to fail")
+	}
+	if len(herr.Diagnostics) != 1 {
+		t.Fatalf("Expected one recorded run, got %+v", herr.Diagnostics)
+	}
+	run := herr.Diagnostics[0]
+	if run.Tool != "ffprobe" || run.ExitCode != 1 || run.Stderr != "moov atom not found" {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+	if run.Args[len(run.Args)-1] != media {
+		t.Errorf("Expected args to end with the media path, got %v", run.Args)
+	}
+	if hc.recorder != nil {
+		t.Error("Recording must not leak into the shared checker")
+	}
+
+	d := NewCheckDiagnostics(herr)
+	if d.ErrorType != herr.Type || len(d.Runs) != 1 {
+		t.Errorf("NewCheckDiagnostics = %+v", d)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"syscall"
 )
 
+// accessWriteOK is W_OK for access(2).
+const accessWriteOK = 0x2
+
 // classifySyscallError checks for Unix-specific syscall errors and returns
 // the appropriate error type string, or empty string if not a known syscall error.
 func classifySyscallError(err error) (errorType string, message string) {
@@ -36,3 +39,30 @@ func classifySyscallError(err error) (errorType string, message string) {
 
 	return "", ""
 }
+
+// classifyDeletionErrno returns the deletion cause of Unix errors a removal
+// can fail with, or empty string if err isn't one of them.
+func classifyDeletionErrno(err error) string {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return ""
+	}
+	switch errno {
+	case syscall.EACCES, syscall.EPERM:
+		return DeletionCausePermissionDenied
+	case syscall.EROFS:
+		return DeletionCauseReadOnly
+	case syscall.EBUSY, syscall.ETXTBSY:
+		return DeletionCauseFileLocked
+	case syscall.ENOENT:
+		return DeletionCauseFileMissing
+	}
+	return ""
+}
+
+// dirWritable reports whether files in dir can be created and removed, and
+// whether dir is on a read-only file system.
+func dirWritable(dir string, _ fs.FileInfo) (writable, readOnly bool) {
+	err := syscall.Access(dir, accessWriteOK)
+	return err == nil, errors.Is(err, syscall.EROFS)
+}
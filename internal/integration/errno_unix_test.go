@@ -3,7 +3,9 @@
 package integration
 
 import (
+	"errors"
 	"io/fs"
+	"os"
 	"syscall"
 	"testing"
 )
@@ -151,3 +153,22 @@ func TestClassifySyscallError(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyDeletionErrno(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&fs.PathError{Op: "remove", Path: "/tv/a.mkv", Err: syscall.EACCES}, DeletionCausePermissionDenied},
+		{&fs.PathError{Op: "remove", Path: "/tv/a.mkv", Err: syscall.EROFS}, DeletionCauseReadOnly},
+		{&fs.PathError{Op: "remove", Path: "/tv/a.mkv", Err: syscall.EBUSY}, DeletionCauseFileLocked},
+		{&os.LinkError{Op: "rename", Old: "/tv/a.mkv", New: "/q/a.mkv", Err: syscall.ETXTBSY}, DeletionCauseFileLocked},
+		{&fs.PathError{Op: "remove", Path: "/tv/a.mkv", Err: syscall.EIO}, ""},
+		{errors.New("not an errno"), ""},
+	}
+	for _, tt := range tests {
+		if got := classifyDeletionErrno(tt.err); got != tt.want {
+			t.Errorf("classifyDeletionErrno(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -21,6 +21,9 @@ const (
 	ERROR_SEM_TIMEOUT           syscall.Errno = 121
 	ERROR_UNEXP_NET_ERR         syscall.Errno = 59
 	ERROR_REM_NOT_LIST          syscall.Errno = 51
+	ERROR_WRITE_PROTECT         syscall.Errno = 19
+	ERROR_SHARING_VIOLATION     syscall.Errno = 32
+	ERROR_LOCK_VIOLATION        syscall.Errno = 33
 )
 
 // classifySyscallError checks for Windows-specific syscall errors and returns
@@ -51,3 +54,29 @@ func classifySyscallError(err error) (errorType string, message string) {
 
 	return "", ""
 }
+
+// classifyDeletionErrno returns the deletion cause of Windows errors a removal
+// can fail with, or empty string if err isn't one of them.
+func classifyDeletionErrno(err error) string {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return ""
+	}
+	switch errno {
+	case ERROR_ACCESS_DENIED, ERROR_NETWORK_ACCESS_DENIED:
+		return DeletionCausePermissionDenied
+	case ERROR_WRITE_PROTECT:
+		return DeletionCauseReadOnly
+	case ERROR_SHARING_VIOLATION, ERROR_LOCK_VIOLATION:
+		return DeletionCauseFileLocked
+	case ERROR_PATH_NOT_FOUND:
+		return DeletionCauseFileMissing
+	}
+	return ""
+}
+
+// dirWritable reports whether files in dir can be created and removed. Windows
+// has no read-only file systems, only read-only folders.
+func dirWritable(_ string, info fs.FileInfo) (writable, readOnly bool) {
+	return info.Mode().Perm()&0o200 != 0, false
+}
@@ -0,0 +1,417 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Pipeline stages that can be forced to fail in test mode.
+const (
+	StageDetect = "detect" // health checks during scans
+	StageLookup = "lookup" // finding the media item in the *arr instance
+	StageDelete = "delete" // deleting the corrupt file via the *arr instance
+	StageSearch = "search" // triggering the replacement search
+	StageVerify = "verify" // health checks of the replacement file
+)
+
+// FaultStages lists the stages accepted by FaultInjector.SetFailure.
+var FaultStages = []string{StageDetect, StageLookup, StageDelete, StageSearch, StageVerify}
+
+// fakeMediaIDBase keeps fake media IDs well clear of real *arr IDs.
+const fakeMediaIDBase int64 = 1 << 40
+
+// ErrUnknownStage is returned when a failure is set for a stage that doesn't exist.
+var ErrUnknownStage = errors.New("unknown pipeline stage")
+
+// ForcedFailure is a failure injected into a pipeline stage.
+type ForcedFailure struct {
+	Message string `json:"message"`
+	// Remaining is how many more calls fail; 0 means every call until cleared.
+	Remaining int `json:"remaining"`
+}
+
+// SyntheticFile is a file registered through test mode. Its *arr calls get fake
+// responses (when enabled) and its replacement always passes verification
+// unless the verify stage is forced to fail.
+type SyntheticFile struct {
+	LocalPath string `json:"local_path"`
+	ArrPath   string `json:"arr_path"`
+	MediaID   int64  `json:"media_id"`
+}
+
+// FaultInjectorState is a snapshot of the test mode configuration.
+type FaultInjectorState struct {
+	FakeArr        bool                     `json:"fake_arr"`
+	Failures       map[string]ForcedFailure `json:"failures"`
+	SyntheticFiles []SyntheticFile          `json:"synthetic_files"`
+}
+
+// FaultInjector drives test mode: it forces failures at pipeline stages and
+// answers *arr calls for synthetic files, so the detect→delete→search→verify
+// flow can be exercised without touching real media. A nil FaultInjector is
+// valid and injects nothing.
+type FaultInjector struct {
+	mu        sync.Mutex
+	fakeArr   bool
+	failures  map[string]*ForcedFailure
+	byArrPath map[string]*SyntheticFile
+	byLocal   map[string]*SyntheticFile
+	byMediaID map[int64]*SyntheticFile
+	nextID    int64
+}
+
+// NewFaultInjector creates a FaultInjector with fake *arr responses enabled and
+// no forced failures.
+func NewFaultInjector() *FaultInjector {
+	f := &FaultInjector{fakeArr: true}
+	f.reset()
+	return f
+}
+
+func (f *FaultInjector) reset() {
+	f.failures = make(map[string]*ForcedFailure)
+	f.byArrPath = make(map[string]*SyntheticFile)
+	f.byLocal = make(map[string]*SyntheticFile)
+	f.byMediaID = make(map[int64]*SyntheticFile)
+	f.nextID = fakeMediaIDBase
+}
+
+// Reset clears all forced failures and synthetic files and re-enables fake *arr responses.
+func (f *FaultInjector) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fakeArr = true
+	f.reset()
+}
+
+// SetFakeArr sets whether *arr calls for synthetic files get fake responses.
+// When disabled they go to the configured instance, e.g. a mock *arr server.
+func (f *FaultInjector) SetFakeArr(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fakeArr = enabled
+}
+
+// SetFailure makes the next count calls of a stage fail with message, or every
+// call until cleared when count is 0.
+func (f *FaultInjector) SetFailure(stage, message string, count int) error {
+	if !isFaultStage(stage) {
+		return fmt.Errorf("%w: %s", ErrUnknownStage, stage)
+	}
+	if message == "" {
+		message = "injected " + stage + " failure"
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[stage] = &ForcedFailure{Message: message, Remaining: max(count, 0)}
+	return nil
+}
+
+// ClearFailure removes the forced failure of a stage.
+func (f *FaultInjector) ClearFailure(stage string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.failures, stage)
+}
+
+// AddSyntheticFile registers a synthetic file and returns it with its fake media ID.
+// Registering the same file again returns the existing entry.
+func (f *FaultInjector) AddSyntheticFile(localPath, arrPath string) SyntheticFile {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if file, ok := f.byLocal[localPath]; ok {
+		return *file
+	}
+	f.nextID++
+	file := &SyntheticFile{LocalPath: localPath, ArrPath: arrPath, MediaID: f.nextID}
+	f.byLocal[localPath] = file
+	f.byArrPath[arrPath] = file
+	f.byMediaID[file.MediaID] = file
+	return *file
+}
+
+// State returns a snapshot of the current configuration.
+func (f *FaultInjector) State() FaultInjectorState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := FaultInjectorState{
+		FakeArr:        f.fakeArr,
+		Failures:       make(map[string]ForcedFailure, len(f.failures)),
+		SyntheticFiles: make([]SyntheticFile, 0, len(f.byLocal)),
+	}
+	for stage, failure := range f.failures {
+		state.Failures[stage] = *failure
+	}
+	for _, file := range f.byLocal {
+		state.SyntheticFiles = append(state.SyntheticFiles, *file)
+	}
+	sort.Slice(state.SyntheticFiles, func(i, j int) bool {
+		return state.SyntheticFiles[i].MediaID < state.SyntheticFiles[j].MediaID
+	})
+	return state
+}
+
+// failure returns the forced failure for a stage, if any, and counts it down.
+func (f *FaultInjector) failure(stage string) error {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	failure, ok := f.failures[stage]
+	if !ok {
+		return nil
+	}
+	if failure.Remaining > 0 {
+		failure.Remaining--
+		if failure.Remaining == 0 {
+			delete(f.failures, stage)
+		}
+	}
+	return errors.New(failure.Message)
+}
+
+// fakeByPath returns the synthetic file for an *arr path when fake responses are on.
+func (f *FaultInjector) fakeByPath(arrPath string) *SyntheticFile {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.fakeArr {
+		return nil
+	}
+	return f.byArrPath[arrPath]
+}
+
+// fakeByMediaID returns the synthetic file for a fake media ID when fake responses are on.
+func (f *FaultInjector) fakeByMediaID(mediaID int64) *SyntheticFile {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.fakeArr {
+		return nil
+	}
+	return f.byMediaID[mediaID]
+}
+
+// isSynthetic reports whether a local path belongs to a synthetic file.
+func (f *FaultInjector) isSynthetic(localPath string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.byLocal[localPath]
+	return ok
+}
+
+func isFaultStage(stage string) bool {
+	for _, s := range FaultStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// WrapArrClient returns an ArrClient that applies the injector's forced
+// failures and fake responses. A nil injector returns client unchanged.
+func (f *FaultInjector) WrapArrClient(client ArrClient) ArrClient {
+	if f == nil {
+		return client
+	}
+	return &faultInjectingArrClient{ArrClient: client, faults: f}
+}
+
+// WrapHealthChecker returns a HealthChecker for the given stage (StageDetect or
+// StageVerify) that applies forced failures. Synthetic files pass the verify
+// stage without running a detector. A nil injector returns checker unchanged.
+func (f *FaultInjector) WrapHealthChecker(checker HealthChecker, stage string) HealthChecker {
+	if f == nil {
+		return checker
+	}
+	return &faultInjectingHealthChecker{HealthChecker: checker, faults: f, stage: stage}
+}
+
+// faultInjectingArrClient wraps an ArrClient for test mode. Methods that aren't
+// part of the remediation pipeline go straight to the wrapped client.
+type faultInjectingArrClient struct {
+	ArrClient
+	faults *FaultInjector
+}
+
+func (c *faultInjectingArrClient) FindMediaByPath(path string) (int64, error) {
+	if err := c.faults.failure(StageLookup); err != nil {
+		return 0, err
+	}
+	if file := c.faults.fakeByPath(path); file != nil {
+		return file.MediaID, nil
+	}
+	return c.ArrClient.FindMediaByPath(path)
+}
+
+func (c *faultInjectingArrClient) DeleteFile(mediaID int64, path string) (map[string]interface{}, error) {
+	if err := c.faults.failure(StageDelete); err != nil {
+		return nil, err
+	}
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		return map[string]interface{}{"deleted_path": file.ArrPath}, nil
+	}
+	return c.ArrClient.DeleteFile(mediaID, path)
+}
+
+func (c *faultInjectingArrClient) PlanDeletion(mediaID int64, path string) (*DeletionPlan, error) {
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		return &DeletionPlan{MediaID: mediaID, Files: []ArrFileRecord{{ID: mediaID, Path: file.ArrPath}}}, nil
+	}
+	return c.ArrClient.PlanDeletion(mediaID, path)
+}
+
+func (c *faultInjectingArrClient) FindSearchMetadata(mediaID int64, path string) (map[string]interface{}, error) {
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		return map[string]interface{}{"deleted_path": file.ArrPath}, nil
+	}
+	return c.ArrClient.FindSearchMetadata(mediaID, path)
+}
+
+func (c *faultInjectingArrClient) GetFilePath(mediaID int64, metadata map[string]interface{}, referencePath string) (string, error) {
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		return file.ArrPath, nil
+	}
+	return c.ArrClient.GetFilePath(mediaID, metadata, referencePath)
+}
+
+func (c *faultInjectingArrClient) GetAllFilePaths(mediaID int64, metadata map[string]interface{}, referencePath string) ([]string, error) {
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		return []string{file.ArrPath}, nil
+	}
+	return c.ArrClient.GetAllFilePaths(mediaID, metadata, referencePath)
+}
+
+func (c *faultInjectingArrClient) TriggerSearch(mediaID int64, path string, episodeIDs []int64) error {
+	if err := c.faults.failure(StageSearch); err != nil {
+		return err
+	}
+	if c.faults.fakeByMediaID(mediaID) != nil {
+		return nil
+	}
+	return c.ArrClient.TriggerSearch(mediaID, path, episodeIDs)
+}
+
+func (c *faultInjectingArrClient) RescanMedia(mediaID int64, path string) error {
+	if c.faults.fakeByMediaID(mediaID) != nil {
+		return nil
+	}
+	return c.ArrClient.RescanMedia(mediaID, path)
+}
+
+func (c *faultInjectingArrClient) FindQueueItemsByMediaIDForPath(arrPath string, mediaID int64) ([]QueueItemInfo, error) {
+	if c.faults.fakeByMediaID(mediaID) != nil {
+		return nil, nil
+	}
+	return c.ArrClient.FindQueueItemsByMediaIDForPath(arrPath, mediaID)
+}
+
+func (c *faultInjectingArrClient) GetRecentHistoryForMediaByPath(arrPath string, mediaID int64, limit int) ([]HistoryItemInfo, error) {
+	if file := c.faults.fakeByMediaID(mediaID); file != nil {
+		// The replacement is "imported" as soon as it is searched for
+		return []HistoryItemInfo{{
+			EventType:    "downloadFolderImported",
+			Date:         time.Now().UTC().Format(time.RFC3339),
+			SourceTitle:  "Healarr test mode",
+			ImportedPath: file.ArrPath,
+		}}, nil
+	}
+	return c.ArrClient.GetRecentHistoryForMediaByPath(arrPath, mediaID, limit)
+}
+
+func (c *faultInjectingArrClient) GetMediaDetails(mediaID int64, arrPath string) (*MediaDetails, error) {
+	if c.faults.fakeByMediaID(mediaID) != nil {
+		return nil, nil
+	}
+	return c.ArrClient.GetMediaDetails(mediaID, arrPath)
+}
+
+func (c *faultInjectingArrClient) GetReleases(mediaID int64, arrPath string, episodeIDs []int64) ([]Release, error) {
+	if c.faults.fakeByMediaID(mediaID) != nil {
+		return []Release{}, nil
+	}
+	return c.ArrClient.GetReleases(mediaID, arrPath, episodeIDs)
+}
+
+// GetCircuitBreakerStats passes through the wrapped client's circuit breaker
+// stats, so detection-only mode and diagnostics keep working in test mode.
+func (c *faultInjectingArrClient) GetCircuitBreakerStats() map[int64]CircuitBreakerStats {
+	if source, ok := c.ArrClient.(interface {
+		GetCircuitBreakerStats() map[int64]CircuitBreakerStats
+	}); ok {
+		return source.GetCircuitBreakerStats()
+	}
+	return nil
+}
+
+// faultInjectingHealthChecker wraps a HealthChecker for one pipeline stage.
+type faultInjectingHealthChecker struct {
+	HealthChecker
+	faults *FaultInjector
+	stage  string
+}
+
+// inject returns the forced result for path, if there is one.
+func (h *faultInjectingHealthChecker) inject(path string) (bool, *HealthCheckError, bool) {
+	if err := h.faults.failure(h.stage); err != nil {
+		errType := ErrorTypeCorruptStream
+		if h.stage == StageDetect {
+			// A failing detector, not a corrupt file: must never trigger remediation
+			errType = ErrorTypeToolFailure
+		}
+		return false, &HealthCheckError{Type: errType, Message: err.Error()}, true
+	}
+	if h.stage == StageVerify && h.faults.isSynthetic(path) {
+		return true, nil, true
+	}
+	return false, nil, false
+}
+
+func (h *faultInjectingHealthChecker) Check(path, mode string) (bool, *HealthCheckError) {
+	if healthy, herr, ok := h.inject(path); ok {
+		return healthy, herr
+	}
+	return h.HealthChecker.Check(path, mode)
+}
+
+func (h *faultInjectingHealthChecker) CheckWithConfig(path string, config DetectionConfig) (bool, *HealthCheckError) {
+	if healthy, herr, ok := h.inject(path); ok {
+		return healthy, herr
+	}
+	return h.HealthChecker.CheckWithConfig(path, config)
+}
+
+func (h *faultInjectingHealthChecker) ValidateHDRMetadata(path string) (bool, *HealthCheckError) {
+	if validator, ok := h.HealthChecker.(HDRValidator); ok {
+		return validator.ValidateHDRMetadata(path)
+	}
+	return true, nil
+}
+
+func (h *faultInjectingHealthChecker) ProbeAudioTracks(path string) ([]AudioTrack, error) {
+	if prober, ok := h.HealthChecker.(AudioProber); ok {
+		return prober.ProbeAudioTracks(path)
+	}
+	return nil, ErrAudioProbeUnsupported
+}
+
+func (h *faultInjectingHealthChecker) ProbeDuration(path string) (time.Duration, error) {
+	if prober, ok := h.HealthChecker.(DurationProber); ok {
+		return prober.ProbeDuration(path)
+	}
+	return 0, errors.New("duration probing not supported")
+}
+
+func (h *faultInjectingHealthChecker) CheckWithConfigDetector(path string, config DetectionConfig) (bool, DetectionMethod, *HealthCheckError) {
+	if healthy, herr, ok := h.inject(path); ok {
+		return healthy, config.Method, herr
+	}
+	if reporter, ok := h.HealthChecker.(DetectorReporter); ok {
+		return reporter.CheckWithConfigDetector(path, config)
+	}
+	healthy, herr := h.HealthChecker.CheckWithConfig(path, config)
+	return healthy, config.Method, herr
+}
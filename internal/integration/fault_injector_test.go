@@ -0,0 +1,177 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubArrClient answers the pipeline calls used by the fault injector tests.
+// Other methods panic through the nil embedded interface.
+type stubArrClient struct {
+	ArrClient
+	deleted  []string
+	searched []int64
+}
+
+func (s *stubArrClient) FindMediaByPath(path string) (int64, error) { return 7, nil }
+
+func (s *stubArrClient) DeleteFile(mediaID int64, path string) (map[string]interface{}, error) {
+	s.deleted = append(s.deleted, path)
+	return map[string]interface{}{}, nil
+}
+
+func (s *stubArrClient) TriggerSearch(mediaID int64, path string, episodeIDs []int64) error {
+	s.searched = append(s.searched, mediaID)
+	return nil
+}
+
+// stubHealthChecker reports every file healthy and counts checks.
+type stubHealthChecker struct {
+	HealthChecker
+	checks int
+}
+
+func (s *stubHealthChecker) Check(path, mode string) (bool, *HealthCheckError) {
+	s.checks++
+	return true, nil
+}
+
+func TestFaultInjector_NilIsNoop(t *testing.T) {
+	var faults *FaultInjector
+	client := &stubArrClient{}
+	checker := &stubHealthChecker{}
+
+	if faults.WrapArrClient(client) != ArrClient(client) {
+		t.Error("nil injector should return the client unchanged")
+	}
+	if faults.WrapHealthChecker(checker, StageVerify) != HealthChecker(checker) {
+		t.Error("nil injector should return the checker unchanged")
+	}
+}
+
+func TestFaultInjector_ForcedFailures(t *testing.T) {
+	faults := NewFaultInjector()
+	stub := &stubArrClient{}
+	client := faults.WrapArrClient(stub)
+
+	if err := faults.SetFailure("transcode", "boom", 0); !errors.Is(err, ErrUnknownStage) {
+		t.Errorf("Expected ErrUnknownStage, got %v", err)
+	}
+
+	// Fails the next two calls only
+	if err := faults.SetFailure(StageDelete, "disk full", 2); err != nil {
+		t.Fatalf("SetFailure failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.DeleteFile(7, "/movies/a.mkv"); err == nil || err.Error() != "disk full" {
+			t.Errorf("Call %d: expected forced failure, got %v", i+1, err)
+		}
+	}
+	if _, err := client.DeleteFile(7, "/movies/a.mkv"); err != nil {
+		t.Errorf("Expected failure to be used up, got %v", err)
+	}
+	if len(stub.deleted) != 1 {
+		t.Errorf("Expected 1 real delete, got %d", len(stub.deleted))
+	}
+
+	// Fails until cleared, with a default message
+	if err := faults.SetFailure(StageSearch, "", 0); err != nil {
+		t.Fatalf("SetFailure failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := client.TriggerSearch(7, "/movies/a.mkv", nil); err == nil {
+			t.Errorf("Call %d: expected forced failure", i+1)
+		}
+	}
+	if state := faults.State(); state.Failures[StageSearch].Message != "injected search failure" {
+		t.Errorf("Unexpected failures: %v", state.Failures)
+	}
+	faults.ClearFailure(StageSearch)
+	if err := client.TriggerSearch(7, "/movies/a.mkv", nil); err != nil {
+		t.Errorf("Expected search to pass after clearing, got %v", err)
+	}
+}
+
+func TestFaultInjector_FakeArrResponses(t *testing.T) {
+	faults := NewFaultInjector()
+	stub := &stubArrClient{}
+	client := faults.WrapArrClient(stub)
+
+	file := faults.AddSyntheticFile("/media/movies/test.mkv", "/movies/test.mkv")
+	if again := faults.AddSyntheticFile("/media/movies/test.mkv", "/movies/test.mkv"); again != file {
+		t.Errorf("Registering twice should return the same file, got %v and %v", file, again)
+	}
+
+	mediaID, err := client.FindMediaByPath(file.ArrPath)
+	if err != nil || mediaID != file.MediaID {
+		t.Fatalf("FindMediaByPath = %d, %v; want fake ID %d", mediaID, err, file.MediaID)
+	}
+	if _, err := client.DeleteFile(mediaID, file.ArrPath); err != nil {
+		t.Errorf("Fake DeleteFile failed: %v", err)
+	}
+	if err := client.TriggerSearch(mediaID, file.ArrPath, nil); err != nil {
+		t.Errorf("Fake TriggerSearch failed: %v", err)
+	}
+	if len(stub.deleted) != 0 || len(stub.searched) != 0 {
+		t.Errorf("Synthetic file must not reach the real client: deleted=%v searched=%v", stub.deleted, stub.searched)
+	}
+
+	history, _ := client.GetRecentHistoryForMediaByPath(file.ArrPath, mediaID, 20)
+	if len(history) != 1 || history[0].EventType != "downloadFolderImported" {
+		t.Errorf("Expected a fake import event, got %v", history)
+	}
+	paths, _ := client.GetAllFilePaths(mediaID, nil, file.LocalPath)
+	if len(paths) != 1 || paths[0] != file.ArrPath {
+		t.Errorf("GetAllFilePaths = %v, want [%s]", paths, file.ArrPath)
+	}
+
+	// Real files still go to the wrapped client
+	if id, _ := client.FindMediaByPath("/movies/real.mkv"); id != 7 {
+		t.Errorf("Real file lookup = %d, want 7", id)
+	}
+
+	// With fake responses off, synthetic files go to the configured instance
+	faults.SetFakeArr(false)
+	if id, _ := client.FindMediaByPath(file.ArrPath); id != 7 {
+		t.Errorf("Lookup with fake responses off = %d, want 7", id)
+	}
+
+	faults.Reset()
+	if state := faults.State(); !state.FakeArr || len(state.SyntheticFiles) != 0 {
+		t.Errorf("Reset should clear synthetic files and enable fake responses, got %+v", state)
+	}
+}
+
+func TestFaultInjector_HealthChecks(t *testing.T) {
+	faults := NewFaultInjector()
+	stub := &stubHealthChecker{}
+	detect := faults.WrapHealthChecker(stub, StageDetect)
+	verify := faults.WrapHealthChecker(stub, StageVerify)
+	faults.AddSyntheticFile("/media/movies/test.mkv", "/movies/test.mkv")
+
+	// Synthetic replacements pass verification without running a detector
+	if healthy, _ := verify.Check("/media/movies/test.mkv", "thorough"); !healthy || stub.checks != 0 {
+		t.Errorf("Synthetic file: healthy=%v checks=%d, want healthy without a check", healthy, stub.checks)
+	}
+
+	if err := faults.SetFailure(StageVerify, "still corrupt", 1); err != nil {
+		t.Fatalf("SetFailure failed: %v", err)
+	}
+	healthy, herr := verify.Check("/media/movies/test.mkv", "thorough")
+	if healthy || herr == nil || herr.Type != ErrorTypeCorruptStream {
+		t.Errorf("Forced verify failure: healthy=%v err=%v", healthy, herr)
+	}
+
+	// A forced detect failure is a tool failure, never a corruption
+	if err := faults.SetFailure(StageDetect, "", 1); err != nil {
+		t.Fatalf("SetFailure failed: %v", err)
+	}
+	healthy, herr = detect.CheckWithConfig("/media/movies/real.mkv", DetectionConfig{})
+	if healthy || herr == nil || herr.Type != ErrorTypeToolFailure || !herr.IsRecoverable() {
+		t.Errorf("Forced detect failure: healthy=%v err=%v", healthy, herr)
+	}
+
+	if healthy, _ := detect.Check("/media/movies/real.mkv", "quick"); !healthy || stub.checks != 1 {
+		t.Errorf("Expected pass-through check, healthy=%v checks=%d", healthy, stub.checks)
+	}
+}
@@ -0,0 +1,232 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Transfer characteristics as ffprobe names them.
+const (
+	transferPQ  = "smpte2084"    // HDR10 and Dolby Vision
+	transferHLG = "arib-std-b67" // Hybrid log-gamma
+)
+
+// Side data types carrying HDR metadata in ffprobe's stream output.
+const (
+	sideDataDoVi             = "DOVI configuration record"
+	sideDataMasteringDisplay = "Mastering display metadata"
+)
+
+// hdrStream is a video stream as ffprobe -show_streams describes it, limited
+// to what HDR validation needs.
+type hdrStream struct {
+	PixFmt         string `json:"pix_fmt"`
+	ColorTransfer  string `json:"color_transfer"`
+	ColorPrimaries string `json:"color_primaries"`
+	Disposition    struct {
+		AttachedPic int `json:"attached_pic"`
+	} `json:"disposition"`
+	SideData []hdrSideData `json:"side_data_list"`
+}
+
+// hdrSideData holds the fields of the side data types HDR validation reads.
+type hdrSideData struct {
+	Type string `json:"side_data_type"`
+
+	// DOVI configuration record; flags are nil if ffprobe left them out
+	DVProfile  int  `json:"dv_profile"`
+	RPUPresent *int `json:"rpu_present_flag"`
+	ELPresent  *int `json:"el_present_flag"`
+	BLPresent  *int `json:"bl_present_flag"`
+	BLCompatID int  `json:"dv_bl_signal_compatibility_id"`
+
+	// Mastering display metadata, as rationals such as "10000000/10000"
+	MinLuminance string `json:"min_luminance"`
+	MaxLuminance string `json:"max_luminance"`
+}
+
+// pixFmtDepthRe finds the bit depth in pixel formats such as yuv420p10le.
+var pixFmtDepthRe = regexp.MustCompile(`p(\d{2})(le|be)?$`)
+
+// pixFmtBitDepth returns the bit depth of a pixel format, or 0 if unknown.
+func pixFmtBitDepth(pixFmt string) int {
+	switch {
+	case pixFmt == "":
+		return 0
+	case pixFmt == "p010le" || pixFmt == "p010be":
+		return 10
+	}
+	if m := pixFmtDepthRe.FindStringSubmatch(pixFmt); m != nil {
+		depth, _ := strconv.Atoi(m[1])
+		return depth
+	}
+	if strings.HasPrefix(pixFmt, "yuv") || strings.HasPrefix(pixFmt, "nv12") {
+		return 8
+	}
+	return 0
+}
+
+// parseRational parses an ffprobe rational such as "50/10000".
+func parseRational(s string) (float64, bool) {
+	num, den, found := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	if !found {
+		return n, true
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0, false
+	}
+	return n / d, true
+}
+
+// knownTransfer reports whether ffprobe identified the transfer characteristics.
+func knownTransfer(transfer string) bool {
+	return transfer != "" && transfer != "unknown" && transfer != "unspecified"
+}
+
+// evaluateHDRMetadata checks the HDR10 and Dolby Vision metadata of a file's
+// video streams. These problems leave the streams decodable, but clients that
+// trust the metadata show washed-out or purple-green pictures or refuse to
+// play the file.
+func evaluateHDRMetadata(streams []hdrStream) *HealthCheckError {
+	var video []hdrStream
+	for _, s := range streams {
+		if s.Disposition.AttachedPic == 0 {
+			video = append(video, s)
+		}
+	}
+
+	for _, s := range video {
+		for _, sd := range s.SideData {
+			var problem string
+			switch sd.Type {
+			case sideDataDoVi:
+				problem = checkDolbyVision(s, sd, len(video))
+			case sideDataMasteringDisplay:
+				problem = checkMasteringDisplay(sd)
+			}
+			if problem != "" {
+				return &HealthCheckError{Type: ErrorTypeHDRMetadata, Message: problem}
+			}
+		}
+
+		if s.ColorTransfer != transferPQ {
+			continue
+		}
+		if depth := pixFmtBitDepth(s.PixFmt); depth > 0 && depth < 10 {
+			return &HealthCheckError{
+				Type:    ErrorTypeHDRMetadata,
+				Message: fmt.Sprintf("PQ (HDR10) transfer on %d-bit video (%s)", depth, s.PixFmt),
+			}
+		}
+		if s.ColorPrimaries == "bt709" {
+			return &HealthCheckError{
+				Type:    ErrorTypeHDRMetadata,
+				Message: "PQ (HDR10) transfer with BT.709 primaries instead of BT.2020",
+			}
+		}
+	}
+	return nil
+}
+
+// checkDolbyVision validates a Dolby Vision configuration record against the
+// stream carrying it. videoStreams is the number of video streams in the file:
+// a dual-track file keeps its base layer in another stream.
+func checkDolbyVision(s hdrStream, dv hdrSideData, videoStreams int) string {
+	if isUnset(dv.BLPresent) && videoStreams == 1 {
+		return fmt.Sprintf("Dolby Vision profile %d without a base layer", dv.DVProfile)
+	}
+	if isUnset(dv.RPUPresent) {
+		return fmt.Sprintf("Dolby Vision profile %d without RPU (dynamic metadata)", dv.DVProfile)
+	}
+	if dv.DVProfile == 7 && isUnset(dv.ELPresent) && videoStreams == 1 {
+		return "Dolby Vision profile 7 without an enhancement layer"
+	}
+
+	if dv.DVProfile != 8 || !knownTransfer(s.ColorTransfer) {
+		return ""
+	}
+	// Profile 8 base layers must be playable on their own, in the format the
+	// compatibility ID promises
+	var want string
+	switch dv.BLCompatID {
+	case 1:
+		want = transferPQ
+	case 4:
+		want = transferHLG
+	case 2:
+		if s.ColorTransfer == transferPQ || s.ColorTransfer == transferHLG {
+			return fmt.Sprintf("Dolby Vision profile 8.2 base layer should be SDR but is %s", s.ColorTransfer)
+		}
+		return ""
+	default:
+		return ""
+	}
+	if s.ColorTransfer != want {
+		return fmt.Sprintf("Dolby Vision profile 8.%d base layer should be %s but is %s", dv.BLCompatID, want, s.ColorTransfer)
+	}
+	return ""
+}
+
+// isUnset reports whether a Dolby Vision flag is present and 0.
+func isUnset(flag *int) bool {
+	return flag != nil && *flag == 0
+}
+
+// checkMasteringDisplay validates SMPTE ST 2086 mastering display luminance.
+func checkMasteringDisplay(sd hdrSideData) string {
+	maxLum, okMax := parseRational(sd.MaxLuminance)
+	minLum, okMin := parseRational(sd.MinLuminance)
+	if !okMax || !okMin {
+		return ""
+	}
+	if maxLum <= 0 || minLum >= maxLum {
+		return fmt.Sprintf("invalid mastering display luminance (min %g, max %g cd/m²)", minLum, maxLum)
+	}
+	return ""
+}
+
+// ValidateHDRMetadata checks the HDR10 and Dolby Vision metadata of a file that
+// passed its health check. Files whose metadata can't be read pass, since the
+// structural check already vouched for them.
+func (hc *CmdHealthChecker) ValidateHDRMetadata(path string) (bool, *HealthCheckError) {
+	if err := validateMediaPath(path); err != nil {
+		return false, &HealthCheckError{
+			Type:    ErrorTypeInvalidConfig,
+			Message: fmt.Sprintf("invalid media path: %v", err),
+		}
+	}
+
+	rc := hc.withRecorder()
+	output, err := rc.runTool("ffprobe", rc.FFprobePath, []string{
+		"-v", "error", "-select_streams", "v", argShowStreams, "-of", "json", path,
+	}, 30*time.Second)
+	if err != nil {
+		logger.Debugf("HDR metadata check skipped (probe failed): %s: %v", path, err)
+		return true, nil
+	}
+
+	var result struct {
+		Streams []hdrStream `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		logger.Debugf("HDR metadata check skipped (bad ffprobe output): %s: %v", path, err)
+		return true, nil
+	}
+
+	if herr := evaluateHDRMetadata(result.Streams); herr != nil {
+		herr.Diagnostics = rc.recorder.snapshot()
+		return false, herr
+	}
+	return true, nil
+}
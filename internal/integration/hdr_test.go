@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPixFmtBitDepth(t *testing.T) {
+	for pixFmt, want := range map[string]int{
+		"yuv420p":     8,
+		"yuv420p10le": 10,
+		"yuv422p12be": 12,
+		"p010le":      10,
+		"nv12":        8,
+		"gbrp10le":    10,
+		"rgb24":       0,
+		"":            0,
+	} {
+		if got := pixFmtBitDepth(pixFmt); got != want {
+			t.Errorf("pixFmtBitDepth(%q) = %d, want %d", pixFmt, got, want)
+		}
+	}
+}
+
+func TestEvaluateHDRMetadata(t *testing.T) {
+	one, zero := 1, 0
+	dovi := func(profile, compat int, rpu, el, bl *int) hdrSideData {
+		return hdrSideData{Type: sideDataDoVi, DVProfile: profile, BLCompatID: compat, RPUPresent: rpu, ELPresent: el, BLPresent: bl}
+	}
+	hdr10 := hdrStream{PixFmt: "yuv420p10le", ColorTransfer: transferPQ, ColorPrimaries: "bt2020"}
+	with := func(s hdrStream, sd ...hdrSideData) hdrStream {
+		s.SideData = sd
+		return s
+	}
+	cover := hdrStream{PixFmt: "yuvj420p"}
+	cover.Disposition.AttachedPic = 1
+
+	tests := []struct {
+		name    string
+		streams []hdrStream
+		want    string // substring of the problem, empty if valid
+	}{
+		{"SDR", []hdrStream{{PixFmt: "yuv420p", ColorTransfer: "bt709"}}, ""},
+		{"HDR10", []hdrStream{hdr10}, ""},
+		{"HDR10 with cover art", []hdrStream{hdr10, cover}, ""},
+		{"profile 8.1", []hdrStream{with(hdr10, dovi(8, 1, &one, &zero, &one))}, ""},
+		{"profile 8.4 on HLG", []hdrStream{with(hdrStream{PixFmt: "yuv420p10le", ColorTransfer: transferHLG}, dovi(8, 4, &one, &zero, &one))}, ""},
+		{"profile 5 without flags", []hdrStream{with(hdr10, dovi(5, 0, nil, nil, nil))}, ""},
+		{"profile 7 dual track", []hdrStream{with(hdr10, dovi(7, 6, &one, &one, &zero)), hdr10}, ""},
+		{"missing base layer", []hdrStream{with(hdr10, dovi(8, 1, &one, &zero, &zero))}, "without a base layer"},
+		{"missing RPU", []hdrStream{with(hdr10, dovi(8, 1, &zero, &zero, &one))}, "without RPU"},
+		{"profile 7 without enhancement layer", []hdrStream{with(hdr10, dovi(7, 6, &one, &zero, &one))}, "without an enhancement layer"},
+		{"profile 8.1 on HLG", []hdrStream{with(hdrStream{PixFmt: "yuv420p10le", ColorTransfer: transferHLG}, dovi(8, 1, &one, &zero, &one))}, "should be smpte2084"},
+		{"profile 8.2 on PQ", []hdrStream{with(hdr10, dovi(8, 2, &one, &zero, &one))}, "should be SDR"},
+		{"inverted mastering luminance", []hdrStream{with(hdr10, hdrSideData{Type: sideDataMasteringDisplay, MinLuminance: "10000000/10000", MaxLuminance: "50/10000"})}, "mastering display"},
+		{"8-bit PQ", []hdrStream{{PixFmt: "yuv420p", ColorTransfer: transferPQ, ColorPrimaries: "bt2020"}}, "8-bit"},
+		{"PQ with BT.709 primaries", []hdrStream{{PixFmt: "yuv420p10le", ColorTransfer: transferPQ, ColorPrimaries: "bt709"}}, "BT.709"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			herr := evaluateHDRMetadata(tt.streams)
+			switch {
+			case tt.want == "" && herr != nil:
+				t.Errorf("Expected valid metadata, got %q", herr.Message)
+			case tt.want != "" && herr == nil:
+				t.Errorf("Expected problem %q, got none", tt.want)
+			case tt.want != "" && (herr.Type != ErrorTypeHDRMetadata || !strings.Contains(herr.Message, tt.want)):
+				t.Errorf("Expected %s containing %q, got %s %q", ErrorTypeHDRMetadata, tt.want, herr.Type, herr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateHDRMetadata(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires shell scripts as fake detectors")
+	}
+
+	dir := t.TempDir()
+	media := filepath.Join(dir, "movie.mkv")
+	if err := os.WriteFile(media, []byte("media"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakeProbe := func(t *testing.T, output string) *CmdHealthChecker {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "output.json"), []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+		ffprobe := filepath.Join(dir, "ffprobe")
+		script := "#!/bin/sh\ncat " + filepath.Join(dir, "output.json") + "\n"
+		if err := os.WriteFile(ffprobe, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return NewHealthCheckerWithPaths(ffprobe, "ffmpeg", "mediainfo", "HandBrakeCLI")
+	}
+
+	t.Run("missing RPU is reported with diagnostics", func(t *testing.T) {
+		output, _ := json.Marshal(map[string]interface{}{"streams": []map[string]interface{}{{
+			"pix_fmt":        "yuv420p10le",
+			"color_transfer": transferPQ,
+			"side_data_list": []map[string]interface{}{{
+				"side_data_type":   sideDataDoVi,
+				"dv_profile":       8,
+				"rpu_present_flag": 0,
+				"bl_present_flag":  1,
+			}},
+		}}})
+		healthy, herr := fakeProbe(t, string(output)).ValidateHDRMetadata(media)
+		if healthy || herr == nil || herr.Type != ErrorTypeHDRMetadata {
+			t.Fatalf("Expected HDRMetadata, got healthy=%v err=%+v", healthy, herr)
+		}
+		if len(herr.Diagnostics) != 1 || herr.Diagnostics[0].Tool != "ffprobe" {
+			t.Errorf("Expected the ffprobe run in diagnostics, got %+v", herr.Diagnostics)
+		}
+	})
+
+	t.Run("unreadable output passes", func(t *testing.T) {
+		if healthy, herr := fakeProbe(t, "not json").ValidateHDRMetadata(media); !healthy {
+			t.Errorf("Expected healthy, got %+v", herr)
+		}
+	})
+}
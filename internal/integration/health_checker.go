@@ -1,13 +1,16 @@
 package integration
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -79,6 +82,19 @@ const (
 	ModeQuick = "quick"
 	// ModeThorough performs full stream decoding (slow, decodes every frame).
 	ModeThorough = "thorough"
+	// ModeSampled decodes a handful of short segments spread across the file
+	// instead of every frame. Intended for very large remuxes where a full
+	// decode is impractical.
+	ModeSampled = "sampled"
+)
+
+// Sampled mode defaults
+const (
+	DefaultSampleSegments = 10               // Segments decoded per file (start, middle, end + random)
+	DefaultSampleDuration = 30 * time.Second // Length of each decoded segment
+	minSampleSegments     = 3                // Start, middle and end are always sampled
+	sampleSegmentTimeout  = 2 * time.Minute  // Per-segment decode timeout floor
+	sampleErrorTolerance  = 2                // Isolated decode errors tolerated across all segments
 )
 
 // Content analysis constants
@@ -112,6 +128,13 @@ type DetectionConfig struct {
 	// missing or the subprocess crashes. A detector that reports actual
 	// corruption is authoritative and is not overridden by a fallback.
 	Fallbacks []DetectionMethod
+	// Confirm cross-checks a corruption with the other detectors of the chain,
+	// or the default fallbacks, and scores the confidence by whether they
+	// agree. Costs another run of each per corrupt file.
+	Confirm bool
+	// Consensus cross-checks a corruption like Confirm and only reports it if
+	// the detectors agree as the policy requires. Empty turns it off.
+	Consensus ConsensusPolicy
 }
 
 // DefaultFallbacksFor returns the built-in fallback chain for the given
@@ -137,15 +160,35 @@ type CmdHealthChecker struct {
 	FFmpegPath    string
 	MediaInfoPath string
 	HandBrakePath string
+
+	// SampleSegments and SampleDuration control ModeSampled. Zero values
+	// fall back to DefaultSampleSegments and DefaultSampleDuration.
+	SampleSegments int
+	SampleDuration time.Duration
+
+	// TransportStream is how much damage a thorough check tolerates in
+	// MPEG-TS/M2TS files. Nil uses DefaultTransportStreamLimits.
+	TransportStream *TransportStreamLimits
+
+	// Supervisor enforces wall-clock and memory limits on the tools and
+	// disables ones that keep misbehaving. Nil uses DefaultToolLimits.
+	Supervisor *ToolSupervisor
+
+	// recorder captures tool output for the check in progress. It is only set
+	// on the per-check copy made by withRecorder.
+	recorder *toolRunRecorder
 }
 
 // NewHealthChecker creates a health checker with default binary paths (uses PATH lookup).
 func NewHealthChecker() *CmdHealthChecker {
 	return &CmdHealthChecker{
-		FFprobePath:   "ffprobe",
-		FFmpegPath:    "ffmpeg",
-		MediaInfoPath: "mediainfo",
-		HandBrakePath: "HandBrakeCLI",
+		FFprobePath:    "ffprobe",
+		FFmpegPath:     "ffmpeg",
+		MediaInfoPath:  "mediainfo",
+		HandBrakePath:  "HandBrakeCLI",
+		SampleSegments: DefaultSampleSegments,
+		SampleDuration: DefaultSampleDuration,
+		Supervisor:     NewToolSupervisor(DefaultToolLimits()),
 	}
 }
 
@@ -153,10 +196,13 @@ func NewHealthChecker() *CmdHealthChecker {
 // This allows using non-standard binary locations (e.g., /config/tools/ffprobe).
 func NewHealthCheckerWithPaths(ffprobePath, ffmpegPath, mediainfoPath, handbrakePath string) *CmdHealthChecker {
 	return &CmdHealthChecker{
-		FFprobePath:   ffprobePath,
-		FFmpegPath:    ffmpegPath,
-		MediaInfoPath: mediainfoPath,
-		HandBrakePath: handbrakePath,
+		FFprobePath:    ffprobePath,
+		FFmpegPath:     ffmpegPath,
+		MediaInfoPath:  mediainfoPath,
+		HandBrakePath:  handbrakePath,
+		SampleSegments: DefaultSampleSegments,
+		SampleDuration: DefaultSampleDuration,
+		Supervisor:     NewToolSupervisor(DefaultToolLimits()),
 	}
 }
 
@@ -175,9 +221,36 @@ func (hc *CmdHealthChecker) Check(path, mode string) (bool, *HealthCheckError) {
 // fails with a recoverable error (missing binary, subprocess crash, etc.). A
 // detector that reports true corruption is authoritative and stops the chain.
 func (hc *CmdHealthChecker) CheckWithConfig(path string, config DetectionConfig) (bool, *HealthCheckError) {
+	ok, _, herr := hc.CheckWithConfigDetector(path, config)
+	return ok, herr
+}
+
+// CheckWithConfigDetector is CheckWithConfig that also returns the detection
+// method whose verdict was used: the primary, or the fallback that ran after
+// it failed. The method is empty when no detector ran, e.g. for an
+// inaccessible file.
+func (hc *CmdHealthChecker) CheckWithConfigDetector(path string, config DetectionConfig) (bool, DetectionMethod, *HealthCheckError) {
+	rc := hc.withRecorder()
+	ok, method, herr := rc.checkWithConfig(path, config)
+	if herr != nil {
+		herr.Diagnostics = rc.recorder.snapshot()
+	}
+	return ok, method, herr
+}
+
+// withRecorder returns a copy of the checker that records the output of every
+// tool it runs, so concurrent checks don't mix their diagnostics.
+func (hc *CmdHealthChecker) withRecorder() *CmdHealthChecker {
+	hc.supervisor()
+	rc := *hc
+	rc.recorder = &toolRunRecorder{}
+	return &rc
+}
+
+func (hc *CmdHealthChecker) checkWithConfig(path string, config DetectionConfig) (bool, DetectionMethod, *HealthCheckError) {
 	// 0. Validate path to prevent command injection before any subprocess execution
 	if err := validateMediaPath(path); err != nil {
-		return false, &HealthCheckError{
+		return false, "", &HealthCheckError{
 			Type:    ErrorTypeInvalidConfig,
 			Message: fmt.Sprintf("invalid media path: %v", err),
 		}
@@ -185,12 +258,13 @@ func (hc *CmdHealthChecker) CheckWithConfig(path string, config DetectionConfig)
 
 	// 1. Zero byte check (if requested)
 	if config.Method == DetectionZeroByte {
-		return hc.checkZeroByte(path)
+		ok, herr := hc.checkZeroByte(path)
+		return ok, DetectionZeroByte, herr
 	}
 
 	// 2. Pre-flight accessibility check (distinguishes mount/access issues from corruption)
 	if err := hc.checkAccessibility(path); err != nil {
-		return false, err
+		return false, "", err
 	}
 
 	// Default to ModeQuick if mode not specified
@@ -202,17 +276,24 @@ func (hc *CmdHealthChecker) CheckWithConfig(path string, config DetectionConfig)
 	// 3. Run primary detector, with optional fallback chain on recoverable errors
 	chain := append([]DetectionMethod{config.Method}, config.Fallbacks...)
 	var lastErr *HealthCheckError
+	var lastMethod DetectionMethod
 	for i, method := range chain {
 		ok, herr := hc.runSingleDetector(path, method, config.Args, mode)
 		if ok {
-			return true, nil
+			return true, method, nil
 		}
 		// A detector that saw real corruption wins. Don't let a weaker
 		// fallback mask it.
 		if herr != nil && herr.IsTrueCorruption() {
-			return false, herr
+			hc.crossCheck(path, method, herr, config, mode)
+			if config.Consensus != "" && !config.Consensus.Reached(herr.Verdicts) {
+				logger.Infof("detectors disagree on %s: %s found %s, consensus %s not reached",
+					path, method, herr.Type, config.Consensus)
+				return false, method, noConsensus(herr, config.Consensus)
+			}
+			return false, method, herr
 		}
-		lastErr = herr
+		lastErr, lastMethod = herr, method
 		if i+1 < len(chain) {
 			logger.Warnf("detector %s failed for %s (%s): %s — trying fallback %s",
 				method, path, errTypeOrUnknown(herr), errMessageOrEmpty(herr), chain[i+1])
@@ -221,7 +302,7 @@ func (hc *CmdHealthChecker) CheckWithConfig(path string, config DetectionConfig)
 	if lastErr == nil {
 		lastErr = &HealthCheckError{Type: ErrorTypeInvalidConfig, Message: "unknown detection method"}
 	}
-	return false, lastErr
+	return false, lastMethod, lastErr
 }
 
 // runSingleDetector executes one detector method and returns a normalized result.
@@ -393,6 +474,25 @@ func (hc *CmdHealthChecker) classifyOSError(err error, path string, isParent boo
 func (hc *CmdHealthChecker) classifyDetectorError(err error, _ string) *HealthCheckError {
 	errStr := err.Error()
 
+	// The supervisor killed the tool or refused to run it. Timeouts keep their
+	// own type; everything else means the tool misbehaved, not the file.
+	var killed *ToolKilledError
+	if errors.As(err, &killed) && !killed.Timeout || errors.Is(err, ErrToolDisabled) {
+		return &HealthCheckError{
+			Type:    ErrorTypeToolFailure,
+			Message: errStr,
+		}
+	}
+
+	// A transport stream over its damage limits has stream-level corruption
+	var tsErr *TransportStreamError
+	if errors.As(err, &tsErr) {
+		return &HealthCheckError{
+			Type:    ErrorTypeCorruptStream,
+			Message: errStr,
+		}
+	}
+
 	// Check for path-related errors (file disappeared, wrong path, symlink issues)
 	if strings.Contains(errStr, "No such file or directory") ||
 		strings.Contains(errStr, "does not exist") ||
@@ -458,6 +558,14 @@ func (hc *CmdHealthChecker) runFFprobeWithArgs(path string, customArgs []string,
 	// Mode determines the type of check:
 	// - "quick": Only check container headers and stream info (fast, ~1-2 seconds) using ffprobe
 	// - "thorough": Decode entire file to detect stream corruption (slow, can take minutes) using ffmpeg
+	// - "sampled": Decode short segments spread across the file using ffmpeg (see runFFmpegSampled)
+
+	if mode == ModeSampled {
+		return hc.runFFmpegSampled(path, customArgs)
+	}
+	if mode == ModeThorough && IsTransportStream(path) {
+		return hc.runTransportStreamCheck(path, customArgs)
+	}
 
 	var args []string
 	var cmdPath string
@@ -495,51 +603,72 @@ func (hc *CmdHealthChecker) runFFprobeWithArgs(path string, customArgs []string,
 		}
 	}
 
-	cmd := exec.Command(cmdPath, args...)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
 	// Thorough mode needs much longer timeout since it decodes entire file
 	timeout := 30 * time.Second
 	if mode == ModeThorough {
 		timeout = 10 * time.Minute // Large files can take a while to fully decode
 	}
 
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case <-time.After(timeout):
-		if cmd.Process != nil {
-			// Kill the process - errors expected if process already exited
-			if killErr := cmd.Process.Kill(); killErr != nil {
-				logger.Debugf("Process kill returned: %v (may be already exited)", killErr)
-			}
-			// Wait to reap the zombie process - error expected since we killed it
-			if waitErr := cmd.Wait(); waitErr != nil {
-				logger.Debugf("Process wait after kill: %v", waitErr)
-			}
+	_, stderr, err := hc.runSupervised(cmdName, cmdPath, args, timeout)
+	if err != nil {
+		if isBinaryMissingError(err) {
+			logger.Warnf("Detector %s not found at %q — check HEALARR_%s_PATH or install the tool in the container", cmdName, cmdPath, strings.ToUpper(cmdName))
+			return fmt.Errorf("%s binary not found: %w", cmdName, err)
 		}
-		return fmt.Errorf("%s timed out after %v", cmdName, timeout)
-	case err := <-done:
-		if err != nil {
-			if isBinaryMissingError(err) {
-				logger.Warnf("Detector %s not found at %q — check HEALARR_%s_PATH or install the tool in the container", cmdName, cmdPath, strings.ToUpper(cmdName))
-				return fmt.Errorf("%s binary not found: %w", cmdName, err)
-			}
-			stderrText := strings.TrimSpace(stderr.String())
-			if stderrText == "" {
-				return fmt.Errorf("%s failed: %w", cmdName, err)
-			}
-			return fmt.Errorf("%s failed: %s", cmdName, stderrText)
+		if isSupervisorError(err) {
+			return err
+		}
+		stderrText := strings.TrimSpace(string(stderr))
+		if stderrText == "" {
+			return fmt.Errorf("%s failed: %w", cmdName, err)
 		}
+		return fmt.Errorf("%s failed: %s", cmdName, stderrText)
 	}
 
 	return nil
 }
 
+// supervisor returns the tool supervisor, creating a default one for checkers
+// built without a constructor.
+func (hc *CmdHealthChecker) supervisor() *ToolSupervisor {
+	if hc.Supervisor == nil {
+		hc.Supervisor = NewToolSupervisor(DefaultToolLimits())
+	}
+	return hc.Supervisor
+}
+
+// runSupervised runs a tool under the supervisor and records its output when
+// the checker is capturing diagnostics.
+func (hc *CmdHealthChecker) runSupervised(toolName, cmdPath string, args []string, timeout time.Duration) ([]byte, []byte, error) {
+	start := time.Now()
+	stdout, stderr, err := hc.supervisor().Run(toolName, cmdPath, args, timeout)
+	if hc.recorder != nil {
+		hc.recorder.record(toolName, args, stdout, stderr, err, time.Since(start))
+	}
+	return stdout, stderr, err
+}
+
+// runTool runs a tool under supervision and returns its stdout. A non-zero
+// exit is folded into an error carrying the tool's stderr.
+func (hc *CmdHealthChecker) runTool(toolName, cmdPath string, args []string, timeout time.Duration) ([]byte, error) {
+	stdout, stderr, err := hc.runSupervised(toolName, cmdPath, args, timeout)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s failed: %s", toolName, stderr)
+	}
+	return stdout, nil
+}
+
+// isSupervisorError reports whether err came from the supervisor killing or
+// refusing to run a tool, rather than from the tool itself.
+func isSupervisorError(err error) bool {
+	var killed *ToolKilledError
+	return errors.As(err, &killed) || errors.Is(err, ErrToolDisabled)
+}
+
 // isBinaryMissingError returns true when exec.Cmd failed because the binary
 // could not be located or executed (ENOENT on the executable itself).
 func isBinaryMissingError(err error) bool {
@@ -551,6 +680,180 @@ func isBinaryMissingError(err error) bool {
 		strings.Contains(msg, "no such file or directory") && strings.Contains(msg, "fork/exec")
 }
 
+// sampleSettings returns the effective segment count and segment duration for ModeSampled.
+func (hc *CmdHealthChecker) sampleSettings() (int, time.Duration) {
+	segments := hc.SampleSegments
+	if segments <= 0 {
+		segments = DefaultSampleSegments
+	}
+	if segments < minSampleSegments {
+		segments = minSampleSegments
+	}
+	segDur := hc.SampleDuration
+	if segDur <= 0 {
+		segDur = DefaultSampleDuration
+	}
+	return segments, segDur
+}
+
+// sampleSegmentTimeout returns the decode timeout for a single sampled segment.
+// Decoding normally runs far faster than real time, so four times the segment
+// length is generous; the floor covers slow seeks on network mounts.
+func (hc *CmdHealthChecker) sampleSegmentTimeout() time.Duration {
+	_, segDur := hc.sampleSettings()
+	if t := 4 * segDur; t > sampleSegmentTimeout {
+		return t
+	}
+	return sampleSegmentTimeout
+}
+
+// sampleOffsets picks the start offsets (in seconds) of the segments to decode.
+// The start, middle and end are always sampled so the header, the bulk of the
+// file and the tail (where truncated downloads show up) are covered. Remaining
+// segments are placed at a random point inside evenly sized buckets, so repeat
+// scans of the same file look at different regions while still spreading out.
+// Returns nil when the file is too short for sampling to save anything over a
+// full decode.
+func sampleOffsets(duration float64, segments int, segDur float64, rnd func() float64) []float64 {
+	if segments < minSampleSegments {
+		segments = minSampleSegments
+	}
+	if duration <= float64(segments)*segDur {
+		return nil
+	}
+
+	last := duration - segDur
+	offsets := []float64{0, last / 2, last}
+
+	extra := segments - minSampleSegments
+	if extra > 0 {
+		bucket := last / float64(extra)
+		for i := 0; i < extra; i++ {
+			offsets = append(offsets, float64(i)*bucket+rnd()*bucket)
+		}
+		sort.Float64s(offsets)
+	}
+	return offsets
+}
+
+// formatSeconds renders a second count the way ffmpeg's -ss/-t expect it,
+// rounded to the millisecond.
+func formatSeconds(secs float64) string {
+	return strconv.FormatFloat(math.Round(secs*1000)/1000, 'f', -1, 64)
+}
+
+// sampleResult holds the outcome of decoding one sampled segment.
+type sampleResult struct {
+	Offset       float64
+	DecodeErrors int    // Lines ffmpeg logged at error level
+	FirstError   string // First error line, for the corruption message
+	Failed       bool   // ffmpeg exited non-zero
+}
+
+// evaluateSampleResults classifies a sampled check from the aggregate decode
+// errors. A segment ffmpeg couldn't decode at all flags the file outright.
+// Otherwise a couple of isolated errors are tolerated, since a damaged packet
+// right at a seek point is common in files that play fine; anything beyond
+// sampleErrorTolerance is treated as stream corruption.
+func evaluateSampleResults(results []sampleResult) error {
+	var totalErrors, badSegments int
+	var failed bool
+	var firstErr string
+	for _, r := range results {
+		totalErrors += r.DecodeErrors
+		if r.Failed || r.DecodeErrors > 0 {
+			badSegments++
+			if firstErr == "" {
+				firstErr = fmt.Sprintf("at %ss: %s", formatSeconds(r.Offset), r.FirstError)
+			}
+		}
+		if r.Failed {
+			failed = true
+		}
+	}
+
+	if !failed && totalErrors <= sampleErrorTolerance {
+		return nil
+	}
+	return fmt.Errorf("ffmpeg failed: %d of %d sampled segments had decode errors (%d total, first %s)",
+		badSegments, len(results), totalErrors, firstErr)
+}
+
+// runFFmpegSampled decodes short segments spread across the file instead of the
+// whole stream. On 60-80GB remuxes this is roughly an order of magnitude faster
+// than a thorough check while still catching mid-file and tail damage. Falls
+// back to a full decode when the duration can't be probed or the file is too
+// short for sampling to help.
+func (hc *CmdHealthChecker) runFFmpegSampled(path string, customArgs []string) error {
+	segments, segDur := hc.sampleSettings()
+
+	info, err := hc.getMediaProbeInfo(path)
+	if err != nil {
+		logger.Warnf("Sampled check could not probe duration of %s (%v) — falling back to full decode", path, err)
+		return hc.runFFprobeWithArgs(path, customArgs, ModeThorough)
+	}
+
+	offsets := sampleOffsets(info.Duration, segments, segDur.Seconds(), rand.Float64)
+	if offsets == nil {
+		return hc.runFFprobeWithArgs(path, customArgs, ModeThorough)
+	}
+
+	results := make([]sampleResult, 0, len(offsets))
+	for _, offset := range offsets {
+		r, err := hc.runSampleSegment(path, customArgs, offset, segDur)
+		if err != nil {
+			return err
+		}
+		results = append(results, r)
+	}
+
+	logger.Debugf("Sampled check decoded %d x %v segments of %s (%.0fs)", len(results), segDur, path, info.Duration)
+	return evaluateSampleResults(results)
+}
+
+// runSampleSegment decodes a single segment with ffmpeg and counts the errors it
+// reports. Errors that prevent the check from running at all (missing binary,
+// timeout, memory limit) are returned as an error rather than a result so they
+// stay recoverable.
+func (hc *CmdHealthChecker) runSampleSegment(path string, customArgs []string, offset float64, segDur time.Duration) (sampleResult, error) {
+	// -ss before -i seeks on the demuxer, so we don't decode everything up to the offset
+	args := []string{"-v", "error", "-ss", formatSeconds(offset), "-t", formatSeconds(segDur.Seconds())}
+	args = append(args, customArgs...)
+	args = append(args, "-i", path, "-f", "null", "-")
+
+	_, stderr, err := hc.runSupervised("ffmpeg", hc.FFmpegPath, args, hc.sampleSegmentTimeout())
+	if err != nil {
+		if isBinaryMissingError(err) {
+			logger.Warnf("Detector ffmpeg not found at %q — check HEALARR_FFMPEG_PATH or install the tool in the container", hc.FFmpegPath)
+			return sampleResult{}, fmt.Errorf("ffmpeg binary not found: %w", err)
+		}
+		if isSupervisorError(err) {
+			return sampleResult{}, fmt.Errorf("segment at %ss: %w", formatSeconds(offset), err)
+		}
+	}
+
+	result := sampleResult{Offset: offset, Failed: err != nil}
+	isTS := IsTransportStream(path)
+	for _, line := range strings.Split(string(stderr), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		// Packet loss in recordings is tolerated here as in a thorough check
+		if isTS && isBenignTSLine(line) {
+			continue
+		}
+		result.DecodeErrors++
+		if result.FirstError == "" {
+			result.FirstError = line
+		}
+	}
+	if result.Failed && result.FirstError == "" {
+		result.FirstError = err.Error()
+	}
+	return result, nil
+}
+
 func (hc *CmdHealthChecker) runHandBrakeWithArgs(path string, customArgs []string, mode string) error {
 	// Mode determines the type of check:
 	// - "quick": Basic scan of container structure
@@ -585,36 +888,16 @@ func (hc *CmdHealthChecker) runHandBrakeWithArgs(path string, customArgs []strin
 		}
 	}
 
-	cmd := exec.Command(hc.HandBrakePath, args...)
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case <-time.After(timeout):
-		if cmd.Process != nil {
-			if killErr := cmd.Process.Kill(); killErr != nil {
-				logger.Debugf("HandBrake process kill returned: %v", killErr)
-			}
-			if waitErr := cmd.Wait(); waitErr != nil {
-				logger.Debugf("HandBrake process wait after kill: %v", waitErr)
-			}
-		}
-		return fmt.Errorf("HandBrake scan timed out after %v", timeout)
-	case err := <-done:
-		if err != nil {
-			return fmt.Errorf("HandBrake failed: %s", stderr.String())
+	stdout, stderr, err := hc.runSupervised("HandBrake", hc.HandBrakePath, args, timeout)
+	if err != nil {
+		if isSupervisorError(err) {
+			return err
 		}
+		return fmt.Errorf("HandBrake failed: %s", stderr)
 	}
 
 	// HandBrake returns exit code 0 even for failures, so check output for error indicators
-	combinedOutput := stdout.String() + stderr.String()
+	combinedOutput := string(stdout) + string(stderr)
 	if strings.Contains(combinedOutput, "No title found") ||
 		strings.Contains(combinedOutput, "unrecognized file type") ||
 		strings.Contains(combinedOutput, "open ") && strings.Contains(combinedOutput, " failed") {
@@ -643,33 +926,17 @@ func buildMediaInfoArgs(mode string, customArgs []string, path string) ([]string
 }
 
 // runCommandWithTimeout executes a command with a timeout, returning stdout or an error.
+// Unlike runTool it applies no memory limit and no retries.
 func runCommandWithTimeout(cmd *exec.Cmd, timeout time.Duration, toolName string) ([]byte, error) {
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Start command in main goroutine to avoid race on cmd.Process
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("%s failed to start: %s", toolName, err)
-	}
-
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Wait()
-	}()
-
-	select {
-	case <-time.After(timeout):
-		_ = cmd.Process.Kill()
-		// Wait for goroutine to complete before returning
-		<-done
-		return nil, fmt.Errorf("%s timed out after %v", toolName, timeout)
-	case err := <-done:
-		if err != nil {
-			return nil, fmt.Errorf("%s failed: %s", toolName, stderr.String())
+	stdout, stderr, err := superviseProcess(cmd, toolName, timeout, 0)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
 		}
+		return nil, fmt.Errorf("%s failed: %s", toolName, stderr)
 	}
-	return stdout.Bytes(), nil
+	return stdout, nil
 }
 
 // validateMediaInfoOutput parses MediaInfo JSON and verifies it contains valid media tracks.
@@ -760,9 +1027,8 @@ func hasField(m map[string]interface{}, key string) bool {
 
 func (hc *CmdHealthChecker) runMediaInfo(path string, customArgs []string, mode string) error {
 	args, timeout := buildMediaInfoArgs(mode, customArgs, path)
-	cmd := exec.Command(hc.MediaInfoPath, args...)
 
-	output, err := runCommandWithTimeout(cmd, timeout, "mediainfo")
+	output, err := hc.runTool("mediainfo", hc.MediaInfoPath, args, timeout)
 	if err != nil {
 		return err
 	}
@@ -773,11 +1039,18 @@ func (hc *CmdHealthChecker) runMediaInfo(path string, customArgs []string, mode
 // buildFFprobePreview builds the command preview for ffprobe/ffmpeg detection.
 func (hc *CmdHealthChecker) buildFFprobePreview(mode string, customArgs []string, filePath string) string {
 	var args []string
-	if mode == ModeThorough {
+	switch mode {
+	case ModeThorough:
 		args = []string{hc.FFmpegPath, "-v", "error", argXError}
 		args = append(args, customArgs...)
 		args = append(args, "-i", filePath, "-f", "null", "-")
-	} else {
+	case ModeSampled:
+		segments, segDur := hc.sampleSettings()
+		args = []string{hc.FFmpegPath, "-v", "error", "-ss", "<offset>", "-t", formatSeconds(segDur.Seconds())}
+		args = append(args, customArgs...)
+		args = append(args, "-i", filePath, "-f", "null", "-")
+		return fmt.Sprintf("%s (repeated for %d segments)", strings.Join(args, " "), segments)
+	default:
 		args = []string{hc.FFprobePath, "-v", "error", argShowFormat, argShowStreams}
 		args = append(args, customArgs...)
 		args = append(args, filePath)
@@ -846,6 +1119,10 @@ func (hc *CmdHealthChecker) GetTimeoutDescription(method DetectionMethod, mode s
 		if mode == ModeThorough {
 			return "10 minutes (ffmpeg decodes entire file)"
 		}
+		if mode == ModeSampled {
+			segments, _ := hc.sampleSettings()
+			return fmt.Sprintf("%d segments x %v (ffmpeg decodes sampled segments)", segments, hc.sampleSegmentTimeout())
+		}
 		return "30 seconds (ffprobe header check)"
 	case DetectionMediaInfo:
 		if mode == ModeThorough {
@@ -918,11 +1195,11 @@ type mediaProbeInfo struct {
 
 // getMediaProbeInfo uses ffprobe to get file duration and stream types in a single call.
 func (hc *CmdHealthChecker) getMediaProbeInfo(path string) (*mediaProbeInfo, error) {
-	cmd := exec.Command(hc.FFprobePath, "-v", "error",
+	args := []string{"-v", "error",
 		"-show_entries", "format=duration:stream=codec_type",
-		"-of", "json", path)
+		"-of", "json", path}
 
-	output, err := runCommandWithTimeout(cmd, 30*time.Second, "ffprobe")
+	output, err := hc.runTool("ffprobe", hc.FFprobePath, args, 30*time.Second)
 	if err != nil {
 		return nil, err
 	}
@@ -961,10 +1238,31 @@ func (hc *CmdHealthChecker) getMediaProbeInfo(path string) (*mediaProbeInfo, err
 	return info, nil
 }
 
+// ProbeDuration returns how long a file plays according to its container.
+func (hc *CmdHealthChecker) ProbeDuration(path string) (time.Duration, error) {
+	if err := validateMediaPath(path); err != nil {
+		return 0, fmt.Errorf("invalid media path: %w", err)
+	}
+	info, err := hc.getMediaProbeInfo(path)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(info.Duration * float64(time.Second)), nil
+}
+
 // AnalyzeContent checks for content-level issues (black video, frozen video, silent audio)
 // in files that have already passed structural health checks.
 // Only meaningful in thorough mode — call after CheckWithConfig passes.
 func (hc *CmdHealthChecker) AnalyzeContent(path string) (bool, *HealthCheckError) {
+	rc := hc.withRecorder()
+	ok, herr := rc.analyzeContent(path)
+	if herr != nil {
+		herr.Diagnostics = rc.recorder.snapshot()
+	}
+	return ok, herr
+}
+
+func (hc *CmdHealthChecker) analyzeContent(path string) (bool, *HealthCheckError) {
 	if err := validateMediaPath(path); err != nil {
 		return false, &HealthCheckError{
 			Type:    ErrorTypeInvalidConfig,
@@ -1006,35 +1304,18 @@ func (hc *CmdHealthChecker) AnalyzeContent(path string) (bool, *HealthCheckError
 	ffmpegArgs = append(ffmpegArgs, "-f", "null", "-")
 
 	// Run ffmpeg with detection filters
-	cmd := exec.Command(hc.FFmpegPath, ffmpegArgs...)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	timeout := 10 * time.Minute
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case <-time.After(timeout):
-		if cmd.Process != nil {
-			if killErr := cmd.Process.Kill(); killErr != nil {
-				logger.Debugf("Content analysis kill returned: %v", killErr)
-			}
-			<-done
-		}
-		logger.Warnf("Content analysis timed out after %v: %s", timeout, path)
-		return true, nil
-	case err := <-done:
-		if err != nil {
+	_, stderr, err := hc.runSupervised("ffmpeg", hc.FFmpegPath, ffmpegArgs, 10*time.Minute)
+	if err != nil {
+		if isSupervisorError(err) {
+			logger.Warnf("Content analysis skipped (%v): %s", err, path)
+		} else {
 			logger.Warnf("Content analysis ffmpeg error (treating as healthy): %s: %v", path, err)
-			return true, nil
 		}
+		return true, nil
 	}
 
 	// Parse results and evaluate against threshold
-	output := stderr.String()
+	output := string(stderr)
 	return evaluateContentAnalysis(contentAnalysisResult{
 		BlackDuration:   parseDurations(blackDurationRe, output),
 		FreezeDuration:  parseDurations(freezeDurationRe, output),
@@ -55,6 +55,8 @@ func TestHealthCheckError_IsTrueCorruption(t *testing.T) {
 		{ErrorTypeBlackVideo, true},
 		{ErrorTypeFrozenVideo, true},
 		{ErrorTypeSilentAudio, true},
+		{ErrorTypeUndersized, true},
+		{ErrorTypeDurationMismatch, true},
 		{ErrorTypeAccessDenied, false},
 		{ErrorTypePathNotFound, false},
 		{ErrorTypeMountLost, false},
@@ -567,6 +569,7 @@ func TestGetCommandPreview(t *testing.T) {
 		{DetectionZeroByte, "quick", "stat <file> (checks if file size == 0)"},
 		{DetectionFFprobe, "quick", "ffprobe"},
 		{DetectionFFprobe, "thorough", "ffmpeg"},
+		{DetectionFFprobe, "sampled", "-ss <offset> -t 30"},
 		{DetectionMediaInfo, "quick", "mediainfo"},
 		{DetectionHandBrake, "quick", "HandBrakeCLI"},
 		{"unknown", "quick", "unknown detection method"},
@@ -603,6 +606,7 @@ func TestGetTimeoutDescription(t *testing.T) {
 		{DetectionZeroByte, "quick", "instant (file metadata only)"},
 		{DetectionFFprobe, "quick", "30 seconds"},
 		{DetectionFFprobe, "thorough", "10 minutes"},
+		{DetectionFFprobe, "sampled", "10 segments"},
 		{DetectionMediaInfo, "quick", "30 seconds"},
 		{DetectionMediaInfo, "thorough", "2 minutes"},
 		{DetectionHandBrake, "quick", "2 minutes"},
@@ -1568,3 +1572,161 @@ func TestParseDurations_SilenceDetect(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// Sampled mode tests
+// =============================================================================
+
+func TestSampleSettings(t *testing.T) {
+	hc := &CmdHealthChecker{}
+	segments, segDur := hc.sampleSettings()
+	if segments != DefaultSampleSegments || segDur != DefaultSampleDuration {
+		t.Errorf("zero values = (%d, %v), want defaults (%d, %v)", segments, segDur, DefaultSampleSegments, DefaultSampleDuration)
+	}
+
+	hc.SampleSegments = 1
+	if segments, _ := hc.sampleSettings(); segments != minSampleSegments {
+		t.Errorf("segments below minimum = %d, want %d", segments, minSampleSegments)
+	}
+
+	hc.SampleDuration = 5 * time.Minute
+	if got := hc.sampleSegmentTimeout(); got != 20*time.Minute {
+		t.Errorf("sampleSegmentTimeout() = %v, want 20m for 5m segments", got)
+	}
+	hc.SampleDuration = 10 * time.Second
+	if got := hc.sampleSegmentTimeout(); got != sampleSegmentTimeout {
+		t.Errorf("sampleSegmentTimeout() = %v, want floor %v", got, sampleSegmentTimeout)
+	}
+}
+
+func TestSampleOffsets(t *testing.T) {
+	t.Run("always covers start, middle and end", func(t *testing.T) {
+		offsets := sampleOffsets(7230, 3, 30, func() float64 { return 0.5 })
+		want := []float64{0, 3600, 7200}
+		if len(offsets) != len(want) {
+			t.Fatalf("len(offsets) = %d, want %d", len(offsets), len(want))
+		}
+		for i := range want {
+			if offsets[i] != want[i] {
+				t.Errorf("offsets[%d] = %v, want %v", i, offsets[i], want[i])
+			}
+		}
+	})
+
+	t.Run("random samples stay inside their buckets", func(t *testing.T) {
+		for _, r := range []float64{0, 0.5, 0.999} {
+			offsets := sampleOffsets(7230, 10, 30, func() float64 { return r })
+			if len(offsets) != 10 {
+				t.Fatalf("len(offsets) = %d, want 10", len(offsets))
+			}
+			for i, off := range offsets {
+				if off < 0 || off > 7200 {
+					t.Errorf("offset %v out of range [0, 7200]", off)
+				}
+				if i > 0 && off < offsets[i-1] {
+					t.Errorf("offsets not sorted: %v", offsets)
+				}
+			}
+		}
+	})
+
+	t.Run("short file falls back to full decode", func(t *testing.T) {
+		if offsets := sampleOffsets(300, 10, 30, func() float64 { return 0.5 }); offsets != nil {
+			t.Errorf("expected nil offsets for short file, got %v", offsets)
+		}
+	})
+}
+
+func TestEvaluateSampleResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []sampleResult
+		wantErr bool
+	}{
+		{"all clean", []sampleResult{{Offset: 0}, {Offset: 3600}, {Offset: 7200}}, false},
+		{"isolated errors tolerated", []sampleResult{{Offset: 0, DecodeErrors: 1, FirstError: "err"}, {Offset: 3600, DecodeErrors: 1, FirstError: "err"}, {Offset: 7200}}, false},
+		{"too many errors", []sampleResult{{Offset: 0}, {Offset: 3600, DecodeErrors: 3, FirstError: "Invalid NAL unit size"}, {Offset: 7200}}, true},
+		{"segment failed to decode", []sampleResult{{Offset: 0}, {Offset: 3600}, {Offset: 7200, Failed: true, FirstError: "End of file"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluateSampleResults(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluateSampleResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := evaluateSampleResults([]sampleResult{{Offset: 3600.5, DecodeErrors: 4, FirstError: "Invalid NAL unit size"}})
+	if err == nil || !strings.Contains(err.Error(), "at 3600.5s: Invalid NAL unit size") {
+		t.Errorf("expected error to reference first failing segment, got %v", err)
+	}
+	// Sampled corruption must classify as corruption, not a recoverable error
+	if herr := NewHealthChecker().classifyDetectorError(err, ""); !herr.IsTrueCorruption() {
+		t.Errorf("sampled decode failure classified as %s, want corruption", herr.Type)
+	}
+}
+
+func TestCmdHealthChecker_CheckWithConfig_FFprobe_SampledMode(t *testing.T) {
+	hc := NewHealthCheckerWithPaths("/nonexistent/ffprobe", "/nonexistent/ffmpeg", "mediainfo", "HandBrakeCLI")
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.mkv")
+	if err := os.WriteFile(testFile, []byte("not a real video"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Probe fails, so sampled mode falls back to a full decode, which also
+	// can't run. Either way a missing binary must stay recoverable.
+	healthy, checkErr := hc.CheckWithConfig(testFile, DetectionConfig{
+		Method: DetectionFFprobe,
+		Mode:   ModeSampled,
+	})
+	if healthy {
+		t.Error("Expected unhealthy when ffmpeg is missing")
+	}
+	if checkErr == nil || !checkErr.IsRecoverable() {
+		t.Errorf("Expected recoverable error for missing binary, got %+v", checkErr)
+	}
+}
+
+func TestCmdHealthChecker_CheckWithConfigDetector(t *testing.T) {
+	tmpDir := t.TempDir()
+	mediaFile := filepath.Join(tmpDir, "movie.mkv")
+	if err := os.WriteFile(mediaFile, []byte("not really a video"), 0644); err != nil {
+		t.Fatalf("Failed to create media file: %v", err)
+	}
+	hc := NewHealthCheckerWithPaths("/nonexistent/ffprobe", "/nonexistent/ffmpeg", "/nonexistent/mediainfo", "/nonexistent/HandBrakeCLI")
+
+	t.Run("zero byte check reports itself", func(t *testing.T) {
+		healthy, method, _ := hc.CheckWithConfigDetector(mediaFile, DetectionConfig{Method: DetectionZeroByte})
+		if !healthy || method != DetectionZeroByte {
+			t.Errorf("Expected healthy zero_byte result, got healthy=%v method=%q", healthy, method)
+		}
+	})
+
+	t.Run("reports the last fallback that ran", func(t *testing.T) {
+		_, method, checkErr := hc.CheckWithConfigDetector(mediaFile, DetectionConfig{
+			Method:    DetectionFFprobe,
+			Mode:      ModeQuick,
+			Fallbacks: []DetectionMethod{DetectionMediaInfo},
+		})
+		if checkErr == nil {
+			t.Fatal("Expected an error with no detection tools installed")
+		}
+		if method != DetectionMediaInfo {
+			t.Errorf("Expected method %q, got %q", DetectionMediaInfo, method)
+		}
+	})
+
+	t.Run("no method when the file is inaccessible", func(t *testing.T) {
+		_, method, checkErr := hc.CheckWithConfigDetector(filepath.Join(tmpDir, "missing.mkv"), DetectionConfig{Method: DetectionFFprobe})
+		if checkErr == nil || checkErr.Type != ErrorTypePathNotFound {
+			t.Fatalf("Expected PathNotFound, got %v", checkErr)
+		}
+		if method != "" {
+			t.Errorf("Expected no method, got %q", method)
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package integration
 
+import "time"
+
 // ArrInstanceInfo represents a configured *arr instance.
 type ArrInstanceInfo struct {
 	ID     int64
@@ -18,16 +20,84 @@ type RootFolder struct {
 	TotalSpace int64  `json:"totalSpace"`
 }
 
+// ArrTag is a tag defined in an *arr instance.
+type ArrTag struct {
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// DeletionPlan is what DeleteFile would delete for a file: the *arr file
+// records matching it. Files is empty when the instance doesn't track the file.
+type DeletionPlan struct {
+	InstanceID int64           `json:"instance_id"`
+	MediaID    int64           `json:"media_id"`
+	Files      []ArrFileRecord `json:"files"`
+}
+
+// ArrFileRecord is a movie, episode or track file as the *arr instance tracks it.
+type ArrFileRecord struct {
+	ID   int64  `json:"id"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// DelayProfile is how long an *arr instance waits after a release appears
+// before it grabs it, giving better releases a chance to show up.
+type DelayProfile struct {
+	ID                int64         `json:"id"`
+	InstanceName      string        `json:"instance_name"`
+	PreferredProtocol string        `json:"preferred_protocol"` // usenet or torrent
+	EnableUsenet      bool          `json:"enable_usenet"`
+	EnableTorrent     bool          `json:"enable_torrent"`
+	UsenetDelay       time.Duration `json:"usenet_delay"`
+	TorrentDelay      time.Duration `json:"torrent_delay"`
+}
+
+// Delay returns how long the instance may wait before grabbing a release of
+// protocol, or of either enabled protocol for any other value.
+func (p *DelayProfile) Delay(protocol string) time.Duration {
+	if p == nil {
+		return 0
+	}
+	switch protocol {
+	case "usenet":
+		if p.EnableUsenet {
+			return p.UsenetDelay
+		}
+		return 0
+	case "torrent":
+		if p.EnableTorrent {
+			return p.TorrentDelay
+		}
+		return 0
+	}
+	var delay time.Duration
+	if p.EnableUsenet {
+		delay = p.UsenetDelay
+	}
+	if p.EnableTorrent {
+		delay = max(delay, p.TorrentDelay)
+	}
+	return delay
+}
+
 // ArrClient defines the interface for interacting with Sonarr/Radarr
 type ArrClient interface {
 	// Media operations
 	FindMediaByPath(path string) (int64, error)
 	DeleteFile(mediaID int64, path string) (map[string]interface{}, error)
+	// PlanDeletion returns the file records DeleteFile would delete, without deleting.
+	PlanDeletion(mediaID int64, path string) (*DeletionPlan, error)
+	// FindSearchMetadata returns what a search for the file at path needs,
+	// in the form DeleteFile records it, e.g. the episode IDs of a series file.
+	FindSearchMetadata(mediaID int64, path string) (map[string]interface{}, error)
 	GetFilePath(mediaID int64, metadata map[string]interface{}, referencePath string) (string, error)
 	// GetAllFilePaths returns all unique file paths for the tracked episodes/movie.
 	// For multi-episode files replaced with individual files, this returns multiple paths.
 	GetAllFilePaths(mediaID int64, metadata map[string]interface{}, referencePath string) ([]string, error)
 	TriggerSearch(mediaID int64, path string, episodeIDs []int64) error
+	// RescanMedia makes the instance rescan a media item's folder from disk.
+	RescanMedia(mediaID int64, path string) error
 
 	// Instance management
 	GetAllInstances() ([]*ArrInstanceInfo, error)
@@ -37,6 +107,9 @@ type ArrClient interface {
 	// Root folders - library paths configured in *arr instances
 	GetRootFolders(instanceID int64) ([]RootFolder, error)
 
+	// Tags defined in *arr instances - used to bind scan paths to instances by tag
+	GetTags(instanceID int64) ([]ArrTag, error)
+
 	// Queue monitoring - track active downloads
 	GetQueueForPath(arrPath string) ([]QueueItemInfo, error)
 	FindQueueItemsByMediaIDForPath(arrPath string, mediaID int64) ([]QueueItemInfo, error)
@@ -52,6 +125,18 @@ type ArrClient interface {
 	// Media details - fetch friendly titles for display
 	// Returns nil (not error) if media not found, to allow graceful degradation
 	GetMediaDetails(mediaID int64, arrPath string) (*MediaDetails, error)
+
+	// GetExpectedRuntime returns how long the movie or episodes in a file run
+	// according to the instance, 0 when it doesn't know.
+	GetExpectedRuntime(mediaID int64, arrPath string) (time.Duration, error)
+
+	// GetDelayProfile returns the delay profile that applies to a media item,
+	// nil when the instance has none.
+	GetDelayProfile(mediaID int64, arrPath string) (*DelayProfile, error)
+
+	// Interactive search - list the releases the indexers offer and grab one
+	GetReleases(mediaID int64, arrPath string, episodeIDs []int64) ([]Release, error)
+	GrabRelease(arrPath, guid string, indexerID int64) (*Release, error)
 }
 
 // QueueItemInfo represents a download queue item (simplified for interface)
@@ -67,6 +152,7 @@ type QueueItemInfo struct {
 	Protocol              string   // usenet, torrent
 	DownloadClient        string
 	Indexer               string // Source indexer (NZBgeek, 1337x, etc.)
+	OutputPath            string // Where the download client put the download, as the *arr sees it
 	Size                  int64
 	SizeLeft              int64
 	Progress              float64 // calculated: (size - sizeleft) / size * 100
@@ -96,6 +182,24 @@ type HistoryItemInfo struct {
 	DownloadClient string // e.g., "SABnzbd", "qBittorrent"
 }
 
+// Release is a release found by an interactive search of an *arr instance's indexers.
+type Release struct {
+	GUID              string   `json:"guid"`
+	IndexerID         int64    `json:"indexer_id"`
+	Indexer           string   `json:"indexer"`
+	Title             string   `json:"title"`
+	Quality           string   `json:"quality"` // e.g., "Bluray-1080p"
+	Size              int64    `json:"size"`
+	AgeHours          float64  `json:"age_hours"`
+	Protocol          string   `json:"protocol"` // "usenet" or "torrent"
+	Seeders           *int     `json:"seeders,omitempty"`
+	Leechers          *int     `json:"leechers,omitempty"`
+	CustomFormatScore int      `json:"custom_format_score"`
+	Approved          bool     `json:"approved"`         // Meets the instance's quality profile
+	DownloadAllowed   bool     `json:"download_allowed"` // Can be grabbed, even if not approved
+	Rejections        []string `json:"rejections"`       // Why the instance wouldn't grab it automatically
+}
+
 // MediaDetails contains friendly display information about a movie or TV episode.
 // Used to show "Colony S01E08" instead of raw file paths.
 type MediaDetails struct {
@@ -107,6 +211,9 @@ type MediaDetails struct {
 	EpisodeTitle  string // For TV only (empty for movies)
 	ArrType       string // "sonarr", "radarr", "whisparr"
 	InstanceName  string // e.g., "Radarr", "Radarr4K"
+	TmdbID        int64  // For movies only
+	TvdbID        int64  // For series only
+	ImdbID        string // e.g., "tt0133093", if known to the *arr
 }
 
 // FormatDisplayTitle returns a user-friendly title like "Colony S01E08" or "The Matrix (1999)"
@@ -156,6 +263,30 @@ type HealthChecker interface {
 	AnalyzeContent(path string) (bool, *HealthCheckError)
 }
 
+// DetectorReporter is implemented by health checkers that can report which
+// detection method in the fallback chain produced a result.
+type DetectorReporter interface {
+	CheckWithConfigDetector(path string, config DetectionConfig) (bool, DetectionMethod, *HealthCheckError)
+}
+
+// HDRValidator is implemented by health checkers that can validate the HDR10
+// and Dolby Vision metadata of a file that decodes cleanly.
+type HDRValidator interface {
+	ValidateHDRMetadata(path string) (bool, *HealthCheckError)
+}
+
+// AudioProber is implemented by health checkers that can list the audio
+// tracks of a file, used to compare a replacement with the original.
+type AudioProber interface {
+	ProbeAudioTracks(path string) ([]AudioTrack, error)
+}
+
+// DurationProber is implemented by health checkers that can read how long a
+// file plays, used to compare it with the runtime the *arr expects.
+type DurationProber interface {
+	ProbeDuration(path string) (time.Duration, error)
+}
+
 // PathMapper defines the interface for translating paths
 type PathMapper interface {
 	ToArrPath(localPath string) (string, error)
@@ -172,9 +303,12 @@ const (
 	ErrorTypeInvalidFormat = "InvalidFormat" // Not a valid media file
 
 	// Content analysis types - structurally valid but content is corrupt
-	ErrorTypeBlackVideo  = "BlackVideo"  // Video is entirely/mostly black
-	ErrorTypeFrozenVideo = "FrozenVideo" // Video is frozen on a single frame
-	ErrorTypeSilentAudio = "SilentAudio" // Audio is completely silent
+	ErrorTypeBlackVideo       = "BlackVideo"       // Video is entirely/mostly black
+	ErrorTypeFrozenVideo      = "FrozenVideo"      // Video is frozen on a single frame
+	ErrorTypeSilentAudio      = "SilentAudio"      // Audio is completely silent
+	ErrorTypeHDRMetadata      = "HDRMetadata"      // HDR10/Dolby Vision metadata missing or inconsistent
+	ErrorTypeUndersized       = "Undersized"       // File is smaller than its scan path's minimum size
+	ErrorTypeDurationMismatch = "DurationMismatch" // File runs much shorter or longer than the *arr expects
 
 	// Accessibility types - transient/infrastructure issues (should NOT trigger remediation)
 	ErrorTypeAccessDenied  = "AccessDenied"  // Permission error
@@ -183,12 +317,25 @@ const (
 	ErrorTypeIOError       = "IOError"       // Generic I/O error (network, disk)
 	ErrorTypeTimeout       = "Timeout"       // Operation timed out
 	ErrorTypeInvalidConfig = "InvalidConfig" // Bad detection configuration
+	ErrorTypeToolFailure   = "ToolFailure"   // Detector hung, exceeded limits, or is disabled
+
+	// Consensus types - a finding the detectors didn't agree on (should NOT trigger remediation)
+	ErrorTypeNoConsensus = "NoConsensus" // Other detectors overruled the finding under the consensus policy
 )
 
 // HealthCheckError contains details about why a file is unhealthy
 type HealthCheckError struct {
 	Type    string
 	Message string
+	// Diagnostics holds the raw output of the tools run during the check.
+	Diagnostics []ToolRun
+	// Confidence is how sure a corruption finding is, from 0 to 100; 0 when
+	// the check didn't score it (see ConfidenceScore).
+	Confidence int
+	// Verdicts are the results of every detector that checked a corrupt
+	// file, the one that found it first. Empty unless the finding was
+	// cross-checked.
+	Verdicts []DetectorVerdict
 }
 
 // IsRecoverable returns true if this error type represents a potentially
@@ -197,7 +344,7 @@ type HealthCheckError struct {
 func (e *HealthCheckError) IsRecoverable() bool {
 	switch e.Type {
 	case ErrorTypeAccessDenied, ErrorTypePathNotFound, ErrorTypeMountLost,
-		ErrorTypeIOError, ErrorTypeTimeout, ErrorTypeInvalidConfig:
+		ErrorTypeIOError, ErrorTypeTimeout, ErrorTypeInvalidConfig, ErrorTypeToolFailure:
 		return true
 	default:
 		return false
@@ -209,7 +356,8 @@ func (e *HealthCheckError) IsRecoverable() bool {
 func (e *HealthCheckError) IsTrueCorruption() bool {
 	switch e.Type {
 	case ErrorTypeZeroByte, ErrorTypeCorruptHeader, ErrorTypeCorruptStream, ErrorTypeInvalidFormat,
-		ErrorTypeBlackVideo, ErrorTypeFrozenVideo, ErrorTypeSilentAudio:
+		ErrorTypeBlackVideo, ErrorTypeFrozenVideo, ErrorTypeSilentAudio, ErrorTypeHDRMetadata,
+		ErrorTypeUndersized, ErrorTypeDurationMismatch:
 		return true
 	default:
 		return false
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+var (
+	// seasonFolderRe matches season folders below a series folder
+	seasonFolderRe = regexp.MustCompile(`(?i)^(season\s*\d+|specials|s\d+)$`)
+	// folderYearRe splits "Title (2024)" into title and year
+	folderYearRe = regexp.MustCompile(`^(.*?)\s*\((\d{4})\)`)
+)
+
+// findMediaByLookup searches the *arr for the title and year parsed from the
+// path, then matches the results that are in the library by path. It finds
+// media the parse API couldn't match without downloading the whole library.
+func (c *HTTPArrClient) findMediaByLookup(instance *ArrInstance, path string, parsed *ParseResult) (int64, bool) {
+	var endpoint string
+	switch {
+	case isMovieType(instance):
+		endpoint = "/api/v3/movie/lookup"
+	case isSeriesType(instance):
+		endpoint = "/api/v3/series/lookup"
+	default:
+		return 0, false
+	}
+	term := mediaLookupTerm(instance, path, parsed)
+	if term == "" {
+		return 0, false
+	}
+	logger.Debugf("Looking up %q with %s for %s", term, instance.Type, path)
+
+	resp, err := c.doRequest(instance, "GET", endpoint+"?term="+url.QueryEscape(term), nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		return 0, false
+	}
+	defer resp.Body.Close()
+
+	var results []MediaItem
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		logger.Debugf("Failed to decode lookup response from %s: %v", instance.Type, err)
+		return 0, false
+	}
+
+	// Results not in the library have no ID or path
+	inLibrary := results[:0]
+	for _, item := range results {
+		if item.ID > 0 && item.Path != "" {
+			inLibrary = append(inLibrary, item)
+		}
+	}
+	if item, ok := findMediaItemForPath(inLibrary, path); ok {
+		logger.Infof("Found media via lookup: %s (ID: %d)", item.Title, item.ID)
+		return item.ID, true
+	}
+	return 0, false
+}
+
+// mediaLookupTerm returns the search term for a lookup: the title and year
+// the parse API read from the path, or else those of the media folder.
+func mediaLookupTerm(instance *ArrInstance, path string, parsed *ParseResult) string {
+	var title string
+	var year int
+	if parsed != nil {
+		if info := parsed.ParsedMovieInfo; isMovieType(instance) && info != nil && len(info.MovieTitles) > 0 {
+			title, year = info.MovieTitles[0], info.Year
+		}
+		if info := parsed.ParsedEpisodeInfo; isSeriesType(instance) && info != nil {
+			title, year = info.SeriesTitle, info.SeriesTitleInfo.Year
+		}
+	}
+	if strings.TrimSpace(title) == "" {
+		title, year = mediaFolderTitle(path)
+	}
+	title = strings.TrimSpace(title)
+	if title == "" || year <= 0 {
+		return title
+	}
+	return fmt.Sprintf("%s %d", title, year)
+}
+
+// mediaFolderTitle returns the title and year of the movie or series folder
+// holding path, e.g. "Show" and 2020 for /tv/Show (2020)/Season 01/x.mkv.
+func mediaFolderTitle(path string) (string, int) {
+	folder := filepath.Dir(path)
+	if seasonFolderRe.MatchString(filepath.Base(folder)) {
+		folder = filepath.Dir(folder)
+	}
+	name := filepath.Base(folder)
+	if name == "." || name == string(filepath.Separator) {
+		return "", 0
+	}
+	if m := folderYearRe.FindStringSubmatch(name); m != nil {
+		year, _ := strconv.Atoi(m[2])
+		return m[1], year
+	}
+	return name, 0
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/crypto"
+)
+
+func TestHTTPArrClient_FindMediaByPath_Lookup(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	var term string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/parse":
+			// Parsed, but not matched to the library
+			w.Write([]byte(`{"parsedMovieInfo": {"movieTitles": ["Target Movie"], "year": 2024}}`))
+		case "/api/v3/movie/lookup":
+			term = r.URL.Query().Get("term")
+			json.NewEncoder(w).Encode([]MediaItem{
+				{ID: 0, Title: "Target Movie"}, // not in the library
+				{ID: 7, Title: "Target Movie", Path: "/movies/Target Movie (2024)"},
+			})
+		case "/api/v3/movie":
+			t.Error("Expected no full listing when the lookup finds the movie")
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	encryptedKey, _ := crypto.Encrypt("api-key")
+	db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES (1, 'Radarr', 'radarr', ?, ?, 1)`, server.URL, encryptedKey)
+	db.DB.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, auto_remediate, is_4k) VALUES (1, '/local/movies', '/movies', 1, 0, 0)`)
+
+	mediaID, err := client.FindMediaByPath("/movies/Target Movie (2024)/movie.mkv")
+	if err != nil {
+		t.Fatalf("FindMediaByPath lookup failed: %v", err)
+	}
+	if mediaID != 7 {
+		t.Errorf("Expected mediaID=7, got %d", mediaID)
+	}
+	if term != "Target Movie 2024" {
+		t.Errorf("Expected lookup term %q, got %q", "Target Movie 2024", term)
+	}
+}
+
+func TestMediaLookupTerm(t *testing.T) {
+	sonarr := &ArrInstance{Type: ArrTypeSonarr}
+	radarr := &ArrInstance{Type: ArrTypeRadarr}
+	parsedSeries := &ParseResult{ParsedEpisodeInfo: &ParsedEpisodeInfo{SeriesTitle: "The Show"}}
+	parsedSeries.ParsedEpisodeInfo.SeriesTitleInfo.Year = 2019
+
+	tests := []struct {
+		name     string
+		instance *ArrInstance
+		path     string
+		parsed   *ParseResult
+		want     string
+	}{
+		{"parsed series", sonarr, "/tv/x/Season 01/e.mkv", parsedSeries, "The Show 2019"},
+		{"series folder below season", sonarr, "/tv/The Show (2019)/Season 01/e.mkv", nil, "The Show 2019"},
+		{"series folder without season", sonarr, "/tv/The Show/e.mkv", &ParseResult{}, "The Show"},
+		{"specials", sonarr, "/tv/The Show/Specials/e.mkv", nil, "The Show"},
+		{"movie folder", radarr, "/movies/A Movie (1999) {tmdb-1}/m.mkv", nil, "A Movie 1999"},
+		{"parsed series ignored for movies", radarr, "/movies/A Movie/m.mkv", parsedSeries, "A Movie"},
+		{"lidarr", &ArrInstance{Type: ArrTypeLidarr}, "/music/Artist/a.flac", nil, "Artist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mediaLookupTerm(tt.instance, tt.path, tt.parsed); got != tt.want {
+				t.Errorf("mediaLookupTerm() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
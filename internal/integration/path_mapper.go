@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // SQLPathMapper translates between local filesystem paths and *arr paths.
+// Lookups read an immutable snapshot of the mappings without locking; Reload
+// builds a new one and swaps it in, so scans never wait for a reload.
 type SQLPathMapper struct {
 	db       *sql.DB
-	mappings []PathMapping
-	mu       sync.RWMutex
+	mappings atomic.Pointer[[]PathMapping]
+	reloadMu sync.Mutex // serializes reloads so an older snapshot never wins
 }
 
 // PathMapping defines a mapping between a local path and its *arr equivalent.
@@ -31,17 +34,18 @@ func NewPathMapper(db *sql.DB) (*SQLPathMapper, error) {
 	return pm, nil
 }
 
+// Reload reads the enabled scan paths and swaps in their mappings.
 func (pm *SQLPathMapper) Reload() error {
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
+	pm.reloadMu.Lock()
+	defer pm.reloadMu.Unlock()
 
-	rows, err := pm.db.Query("SELECT local_path, arr_path FROM scan_paths WHERE enabled = 1")
+	rows, err := pm.db.Query("SELECT local_path, arr_path FROM scan_paths WHERE enabled = 1 AND deleted_at IS NULL")
 	if err != nil {
 		return fmt.Errorf("failed to query scan_paths: %w", err)
 	}
 	defer rows.Close()
 
-	var mappings []PathMapping
+	mappings := []PathMapping{}
 	for rows.Next() {
 		var m PathMapping
 		if err := rows.Scan(&m.LocalPath, &m.ArrPath); err != nil {
@@ -58,19 +62,18 @@ func (pm *SQLPathMapper) Reload() error {
 		return fmt.Errorf("error iterating path mappings: %w", err)
 	}
 
-	pm.mappings = mappings
+	pm.mappings.Store(&mappings)
 	return nil
 }
 
 func (pm *SQLPathMapper) ToArrPath(localPath string) (string, error) {
-	pm.mu.RLock()
-	defer pm.mu.RUnlock()
+	mappings := pm.snapshot()
 
 	var bestMatch *PathMapping
 	var longestPrefixLen int
 
-	for i := range pm.mappings {
-		m := &pm.mappings[i]
+	for i := range mappings {
+		m := &mappings[i]
 		// Check if localPath starts with m.LocalPath AND is followed by / or end of string
 		// This prevents /mnt/media/TV from matching /mnt/media/TV2
 		if strings.HasPrefix(localPath, m.LocalPath) {
@@ -94,14 +97,13 @@ func (pm *SQLPathMapper) ToArrPath(localPath string) (string, error) {
 }
 
 func (pm *SQLPathMapper) ToLocalPath(arrPath string) (string, error) {
-	pm.mu.RLock()
-	defer pm.mu.RUnlock()
+	mappings := pm.snapshot()
 
 	var bestMatch *PathMapping
 	var longestPrefixLen int
 
-	for i := range pm.mappings {
-		m := &pm.mappings[i]
+	for i := range mappings {
+		m := &mappings[i]
 		// Check if arrPath starts with m.ArrPath AND is followed by / or end of string
 		// This prevents /data/movies from matching /data/movies-archive
 		if strings.HasPrefix(arrPath, m.ArrPath) {
@@ -123,3 +125,11 @@ func (pm *SQLPathMapper) ToLocalPath(arrPath string) (string, error) {
 	relPath := strings.TrimPrefix(arrPath, bestMatch.ArrPath)
 	return bestMatch.LocalPath + relPath, nil
 }
+
+// snapshot returns the current mappings. They are never modified in place.
+func (pm *SQLPathMapper) snapshot() []PathMapping {
+	if m := pm.mappings.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
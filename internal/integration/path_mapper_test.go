@@ -28,7 +28,8 @@ func newTestDBForPathMapper() (*sql.DB, error) {
 			auto_remediate INTEGER NOT NULL DEFAULT 0,
 			dry_run INTEGER NOT NULL DEFAULT 0,
 			enabled INTEGER NOT NULL DEFAULT 1,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		)
 	`)
 	if err != nil {
@@ -89,8 +90,8 @@ func TestNewPathMapper_WithMappings(t *testing.T) {
 		t.Fatalf("NewPathMapper() error = %v", err)
 	}
 
-	if len(pm.mappings) != 2 {
-		t.Errorf("Expected 2 mappings, got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 2 {
+		t.Errorf("Expected 2 mappings, got %d", len(pm.snapshot()))
 	}
 }
 
@@ -110,8 +111,8 @@ func TestPathMapper_Reload(t *testing.T) {
 		t.Fatalf("NewPathMapper() error = %v", err)
 	}
 
-	if len(pm.mappings) != 0 {
-		t.Errorf("Expected 0 mappings initially, got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 0 {
+		t.Errorf("Expected 0 mappings initially, got %d", len(pm.snapshot()))
 	}
 
 	// Add a mapping
@@ -123,8 +124,8 @@ func TestPathMapper_Reload(t *testing.T) {
 		t.Errorf("Reload() error = %v", err)
 	}
 
-	if len(pm.mappings) != 1 {
-		t.Errorf("Expected 1 mapping after reload, got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 1 {
+		t.Errorf("Expected 1 mapping after reload, got %d", len(pm.snapshot()))
 	}
 }
 
@@ -150,8 +151,8 @@ func TestPathMapper_Reload_DisabledPaths(t *testing.T) {
 	}
 
 	// Should only have the enabled path
-	if len(pm.mappings) != 1 {
-		t.Errorf("Expected 1 mapping (disabled excluded), got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 1 {
+		t.Errorf("Expected 1 mapping (disabled excluded), got %d", len(pm.snapshot()))
 	}
 }
 
@@ -173,16 +174,16 @@ func TestPathMapper_Reload_TrimsSlashes(t *testing.T) {
 		t.Fatalf("NewPathMapper() error = %v", err)
 	}
 
-	if len(pm.mappings) != 1 {
-		t.Fatalf("Expected 1 mapping, got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 1 {
+		t.Fatalf("Expected 1 mapping, got %d", len(pm.snapshot()))
 	}
 
 	// Trailing slashes should be removed
-	if pm.mappings[0].LocalPath != "/mnt/media/tv" {
-		t.Errorf("LocalPath = %s, want /mnt/media/tv (without trailing slash)", pm.mappings[0].LocalPath)
+	if pm.snapshot()[0].LocalPath != "/mnt/media/tv" {
+		t.Errorf("LocalPath = %s, want /mnt/media/tv (without trailing slash)", pm.snapshot()[0].LocalPath)
 	}
-	if pm.mappings[0].ArrPath != "/data/tv" {
-		t.Errorf("ArrPath = %s, want /data/tv (without trailing slash)", pm.mappings[0].ArrPath)
+	if pm.snapshot()[0].ArrPath != "/data/tv" {
+		t.Errorf("ArrPath = %s, want /data/tv (without trailing slash)", pm.snapshot()[0].ArrPath)
 	}
 }
 
@@ -550,8 +551,8 @@ func TestPathMapper_EmptyMappings(t *testing.T) {
 		t.Fatalf("NewPathMapper() error = %v", err)
 	}
 
-	if len(pm.mappings) != 0 {
-		t.Errorf("Expected 0 mappings with empty db, got %d", len(pm.mappings))
+	if len(pm.snapshot()) != 0 {
+		t.Errorf("Expected 0 mappings with empty db, got %d", len(pm.snapshot()))
 	}
 
 	_, err = pm.ToArrPath("/any/path")
@@ -564,3 +565,35 @@ func TestPathMapper_EmptyMappings(t *testing.T) {
 		t.Error("ToLocalPath should error with no mappings")
 	}
 }
+
+func TestPathMapper_ReloadSwapsSnapshot(t *testing.T) {
+	db, err := newTestDBForPathMapper()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if err := seedScanPath(db, 1, "/mnt/media/tv", "/tv", false, false); err != nil {
+		t.Fatal(err)
+	}
+	pm, err := NewPathMapper(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := pm.snapshot()
+
+	if _, err := db.Exec("UPDATE scan_paths SET arr_path = '/data/tv'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lookup in progress keeps the mappings it started with
+	if before[0].ArrPath != "/tv" {
+		t.Errorf("old snapshot changed to %s", before[0].ArrPath)
+	}
+	if got, _ := pm.ToArrPath("/mnt/media/tv/Show/ep.mkv"); got != "/data/tv/Show/ep.mkv" {
+		t.Errorf("ToArrPath() = %s, want /data/tv/Show/ep.mkv", got)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package integration
+
+import "errors"
+
+// rssSupported reports whether processRSS can read memory usage on this platform.
+const rssSupported = false
+
+// processRSS is not implemented outside Linux; memory limits are not enforced there.
+func processRSS(_ int) (uint64, error) {
+	return 0, errors.New("process RSS not available on this platform")
+}
@@ -0,0 +1,26 @@
+package integration
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// qualityResolutionPattern matches the resolution in *arr quality names such as "WEBDL-1080p".
+var qualityResolutionPattern = regexp.MustCompile(`(\d{3,4})p\b`)
+
+// QualityResolution returns the vertical resolution encoded in an *arr quality
+// name ("Bluray-1080p" → 1080), or 0 when the name doesn't carry one.
+func QualityResolution(name string) int {
+	if m := qualityResolutionPattern.FindStringSubmatch(strings.ToLower(name)); m != nil {
+		resolution, _ := strconv.Atoi(m[1])
+		return resolution
+	}
+	switch strings.ToLower(name) {
+	case "sdtv", "dvd", "dvd-r":
+		return 480
+	case "raw-hd":
+		return 1080
+	}
+	return 0
+}
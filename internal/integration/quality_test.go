@@ -0,0 +1,27 @@
+package integration
+
+import "testing"
+
+func TestQualityResolution(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"Bluray-1080p", 1080},
+		{"WEBDL-2160p", 2160},
+		{"HDTV-720p", 720},
+		{"Remux-2160p", 2160},
+		{"Bluray-480p", 480},
+		{"SDTV", 480},
+		{"DVD", 480},
+		{"Raw-HD", 1080},
+		{"FLAC", 0},
+		{"Unknown", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := QualityResolution(tt.name); got != tt.want {
+			t.Errorf("QualityResolution(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
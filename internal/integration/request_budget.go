@@ -0,0 +1,220 @@
+package integration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrRequestDeferred is returned for a non-urgent *arr request, e.g. a
+// listing fallback or a history poll, when the instance's daily request
+// budget is nearly used up. Such requests go through again the next day.
+var ErrRequestDeferred = errors.New("non-urgent *arr request deferred: daily request budget nearly used up")
+
+const (
+	// requestBudgetDay is the format of the UTC day requests are counted for.
+	requestBudgetDay = "2006-01-02"
+	// requestBudgetRefresh is how long a loaded daily_request_budget is used
+	// before it is read again, so changed budgets apply without a restart.
+	requestBudgetRefresh = time.Minute
+	// requestUsageRetentionDays is how many days of usage are kept.
+	requestUsageRetentionDays = 30
+)
+
+// RequestBudgetWarning reports an instance nearing or reaching its daily
+// request budget.
+type RequestBudgetWarning struct {
+	InstanceID   int64
+	InstanceName string
+	Day          string // UTC day, e.g. 2026-10-17
+	Used         int
+	Limit        int
+	Exhausted    bool // the budget is used up, not just nearly
+}
+
+// RequestBudgetUsage is an instance's *arr API usage on one day.
+type RequestBudgetUsage struct {
+	Day      string `json:"day"`
+	Used     int    `json:"used"`
+	Deferred int    `json:"deferred"`
+	Limit    int    `json:"limit"` // 0 is unlimited
+}
+
+// requestBudget is the usage of one instance on the current day.
+type requestBudget struct {
+	RequestBudgetUsage
+	loadedAt  time.Time
+	warned    bool
+	exhausted bool
+}
+
+// deferAt is the usage from which non-urgent requests are deferred: 90% of
+// the limit, leaving the rest for remediation.
+func (b *requestBudget) deferAt() int {
+	return b.Limit - b.Limit/10
+}
+
+// RequestBudgets counts the requests to each *arr instance per UTC day in
+// arr_request_usage. Instances with a daily_request_budget defer non-urgent
+// requests once 90% of it is used; urgent requests, e.g. searches and
+// deletions, still go through so remediations don't stall halfway. Usage
+// starts from zero the next day.
+type RequestBudgets struct {
+	db        *sql.DB
+	mu        sync.Mutex
+	budgets   map[int64]*requestBudget
+	onWarning func(RequestBudgetWarning)
+	now       func() time.Time
+}
+
+// NewRequestBudgets creates the request budgets of the instances in db.
+func NewRequestBudgets(db *sql.DB) *RequestBudgets {
+	return &RequestBudgets{
+		db:      db,
+		budgets: make(map[int64]*requestBudget),
+		now:     time.Now,
+	}
+}
+
+// SetWarningHandler sets the function called once per day when an instance
+// reaches the deferral threshold, and once more when its budget is used up.
+func (rb *RequestBudgets) SetWarningHandler(fn func(RequestBudgetWarning)) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.onWarning = fn
+}
+
+// Usage returns an instance's usage today.
+func (rb *RequestBudgets) Usage(instanceID int64) RequestBudgetUsage {
+	if rb == nil {
+		return RequestBudgetUsage{}
+	}
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	return rb.budget(instanceID).RequestBudgetUsage
+}
+
+// Defer returns ErrRequestDeferred if a non-urgent request to instance has
+// to wait for the next day.
+func (rb *RequestBudgets) Defer(instance *ArrInstance) error {
+	if rb == nil {
+		return nil
+	}
+	rb.mu.Lock()
+	b := rb.budget(instance.ID)
+	if b.Limit <= 0 || b.Used < b.deferAt() {
+		rb.mu.Unlock()
+		return nil
+	}
+	b.Deferred++
+	usage := b.RequestBudgetUsage
+	rb.mu.Unlock()
+
+	rb.persist(instance.ID, usage.Day, 0, 1)
+	logger.Debugf("Deferred non-urgent request to %s: %d of %d daily requests used", instance.Name, usage.Used, usage.Limit)
+	return fmt.Errorf("%w (%s: %d of %d requests used today)", ErrRequestDeferred, instance.Name, usage.Used, usage.Limit)
+}
+
+// Record counts a request sent to instance.
+func (rb *RequestBudgets) Record(instance *ArrInstance) {
+	if rb == nil {
+		return
+	}
+	rb.mu.Lock()
+	b := rb.budget(instance.ID)
+	b.Used++
+	var warning *RequestBudgetWarning
+	if b.Limit > 0 && (!b.warned && b.Used >= b.deferAt() || !b.exhausted && b.Used >= b.Limit) {
+		b.warned = true
+		b.exhausted = b.Used >= b.Limit
+		warning = &RequestBudgetWarning{
+			InstanceID:   instance.ID,
+			InstanceName: instance.Name,
+			Day:          b.Day,
+			Used:         b.Used,
+			Limit:        b.Limit,
+			Exhausted:    b.exhausted,
+		}
+	}
+	day, onWarning := b.Day, rb.onWarning
+	rb.mu.Unlock()
+
+	rb.persist(instance.ID, day, 1, 0)
+	if warning != nil {
+		logger.Warnf("*arr request budget of %s: %d of %d requests used today - non-urgent requests are deferred until tomorrow",
+			instance.Name, warning.Used, warning.Limit)
+		if onWarning != nil {
+			onWarning(*warning)
+		}
+	}
+}
+
+// budget returns the usage of an instance today, loading it at the start of
+// a day and refreshing its limit. The caller holds rb.mu.
+func (rb *RequestBudgets) budget(instanceID int64) *requestBudget {
+	now := rb.now()
+	day := now.UTC().Format(requestBudgetDay)
+	b := rb.budgets[instanceID]
+	if b == nil || b.Day != day {
+		b = &requestBudget{RequestBudgetUsage: RequestBudgetUsage{Day: day}}
+		rb.loadUsage(instanceID, b)
+		rb.budgets[instanceID] = b
+	} else if now.Sub(b.loadedAt) < requestBudgetRefresh {
+		return b
+	}
+	b.loadedAt = now
+	b.Limit = rb.loadLimit(instanceID)
+	// Usage already past the thresholds, e.g. before a restart, was warned about
+	b.warned = b.Limit > 0 && b.Used >= b.deferAt()
+	b.exhausted = b.Limit > 0 && b.Used >= b.Limit
+	return b
+}
+
+// loadUsage reads the usage persisted for b.Day, e.g. before a restart.
+func (rb *RequestBudgets) loadUsage(instanceID int64, b *requestBudget) {
+	if rb.db == nil {
+		return
+	}
+	err := rb.db.QueryRow(`SELECT requests, deferred FROM arr_request_usage WHERE instance_id = ? AND day = ?`,
+		instanceID, b.Day).Scan(&b.Used, &b.Deferred)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Debugf("Failed to load *arr request usage of instance %d: %v", instanceID, err)
+	}
+	cutoff := rb.now().UTC().AddDate(0, 0, -requestUsageRetentionDays).Format(requestBudgetDay)
+	if _, err := rb.db.Exec(`DELETE FROM arr_request_usage WHERE day < ?`, cutoff); err != nil {
+		logger.Debugf("Failed to prune *arr request usage: %v", err)
+	}
+}
+
+// loadLimit reads the daily request budget of an instance; 0 is unlimited.
+func (rb *RequestBudgets) loadLimit(instanceID int64) int {
+	if rb.db == nil {
+		return 0
+	}
+	var limit int
+	if err := rb.db.QueryRow(`SELECT daily_request_budget FROM arr_instances WHERE id = ?`, instanceID).Scan(&limit); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Debugf("Failed to load the request budget of instance %d: %v", instanceID, err)
+		}
+		return 0
+	}
+	return limit
+}
+
+// persist adds a request or deferral to the stored usage of a day.
+func (rb *RequestBudgets) persist(instanceID int64, day string, requests, deferred int) {
+	if rb.db == nil {
+		return
+	}
+	if _, err := rb.db.Exec(`
+		INSERT INTO arr_request_usage (instance_id, day, requests, deferred) VALUES (?, ?, ?, ?)
+		ON CONFLICT(instance_id, day) DO UPDATE SET requests = requests + excluded.requests,
+			deferred = deferred + excluded.deferred, updated_at = CURRENT_TIMESTAMP
+	`, instanceID, day, requests, deferred); err != nil {
+		logger.Debugf("Failed to store *arr request usage of instance %d: %v", instanceID, err)
+	}
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/crypto"
+)
+
+func TestRequestBudgets(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+
+	if _, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, daily_request_budget)
+		VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr', 'key', 10)`); err != nil {
+		t.Fatalf("Failed to insert instance: %v", err)
+	}
+	instance := &ArrInstance{ID: 1, Name: "Sonarr"}
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	budgets := NewRequestBudgets(db.DB)
+	budgets.now = func() time.Time { return now }
+	var warnings []RequestBudgetWarning
+	budgets.SetWarningHandler(func(w RequestBudgetWarning) { warnings = append(warnings, w) })
+
+	for i := 0; i < 8; i++ {
+		budgets.Record(instance)
+	}
+	if err := budgets.Defer(instance); err != nil {
+		t.Fatalf("Expected no deferral at 8 of 10 requests, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warning at 8 of 10 requests, got %v", warnings)
+	}
+
+	budgets.Record(instance)
+	if err := budgets.Defer(instance); !errors.Is(err, ErrRequestDeferred) {
+		t.Fatalf("Expected ErrRequestDeferred at 9 of 10 requests, got %v", err)
+	}
+	budgets.Record(instance)
+	budgets.Record(instance)
+	if len(warnings) != 2 || warnings[0].Used != 9 || warnings[0].Exhausted || warnings[1].Used != 10 || !warnings[1].Exhausted {
+		t.Fatalf("Expected a warning at 9 and one at 10 requests, got %+v", warnings)
+	}
+
+	var requests, deferred int
+	if err := db.DB.QueryRow(`SELECT requests, deferred FROM arr_request_usage WHERE instance_id = 1 AND day = '2026-10-17'`).
+		Scan(&requests, &deferred); err != nil {
+		t.Fatalf("Failed to read usage: %v", err)
+	}
+	if requests != 11 || deferred != 1 {
+		t.Errorf("Expected 11 requests and 1 deferral stored, got %d and %d", requests, deferred)
+	}
+
+	// After a restart the stored usage still counts, without warning again
+	restarted := NewRequestBudgets(db.DB)
+	restarted.now = budgets.now
+	restarted.SetWarningHandler(func(w RequestBudgetWarning) { warnings = append(warnings, w) })
+	restarted.Record(instance)
+	if usage := restarted.Usage(1); usage.Used != 12 || usage.Deferred != 1 || usage.Limit != 10 {
+		t.Errorf("Expected the stored usage after a restart, got %+v", usage)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("Expected no new warning after a restart, got %+v", warnings[2:])
+	}
+
+	// The next day starts from zero
+	now = now.Add(24 * time.Hour)
+	if err := restarted.Defer(instance); err != nil {
+		t.Errorf("Expected no deferral the next day, got %v", err)
+	}
+	if usage := restarted.Usage(1); usage.Day != "2026-10-18" || usage.Used != 0 {
+		t.Errorf("Expected no usage the next day, got %+v", usage)
+	}
+}
+
+func TestHTTPArrClient_GetHistory_DeferredOverBudget(t *testing.T) {
+	client, db := setupTestClient(t)
+	defer db.Close()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"records": []}`))
+	}))
+	defer server.Close()
+
+	encryptedKey, err := crypto.Encrypt("test-api-key")
+	if err != nil {
+		t.Fatalf("Failed to encrypt API key: %v", err)
+	}
+	if _, err := db.DB.Exec(`INSERT INTO arr_instances (id, name, type, url, api_key, daily_request_budget) VALUES (1, 'Sonarr', 'sonarr', ?, ?, 100)`,
+		server.URL, encryptedKey); err != nil {
+		t.Fatalf("Failed to insert instance: %v", err)
+	}
+	instance := &ArrInstance{ID: 1, Name: "Sonarr", Type: ArrTypeSonarr, URL: server.URL, APIKey: "test-api-key"}
+
+	if _, err := client.GetHistory(instance, 1, 10, ""); err != nil {
+		t.Fatalf("Expected history under the budget, got %v", err)
+	}
+	if _, err := db.DB.Exec(`UPDATE arr_request_usage SET requests = 95 WHERE instance_id = 1`); err != nil {
+		t.Fatalf("Failed to raise usage: %v", err)
+	}
+
+	// Drop the cached usage, as at the start of a day, to load the raised count
+	client.RequestBudgets().mu.Lock()
+	delete(client.RequestBudgets().budgets, 1)
+	client.RequestBudgets().mu.Unlock()
+
+	if _, err := client.GetHistory(instance, 1, 10, ""); !errors.Is(err, ErrRequestDeferred) {
+		t.Fatalf("Expected ErrRequestDeferred at 95 of 100 requests, got %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected the deferred request not to reach the *arr, got %d requests", hits.Load())
+	}
+}
@@ -266,6 +266,71 @@ func (tc *ToolChecker) checkHandBrake() *ToolStatus {
 	return status
 }
 
+// ToolsForMethod returns the tools a detection method needs in the given mode,
+// keyed like GetToolStatus. Zero-byte checks need no tools.
+func ToolsForMethod(method DetectionMethod, mode string) []string {
+	switch method {
+	case DetectionFFprobe:
+		if mode == ModeThorough || mode == ModeSampled {
+			return []string{"ffmpeg"}
+		}
+		return []string{"ffprobe"}
+	case DetectionMediaInfo:
+		return []string{"mediainfo"}
+	case DetectionHandBrake:
+		return []string{"handbrake"}
+	default:
+		return nil
+	}
+}
+
+// ScanCapability reports whether the tools a scan path's detection profile
+// needs are installed.
+type ScanCapability struct {
+	// Scannable is false when neither the primary method nor any fallback can run.
+	Scannable bool `json:"scannable"`
+	// Degraded is true when the primary method can't run but a fallback can.
+	Degraded     bool     `json:"degraded"`
+	MissingTools []string `json:"missing_tools"`
+}
+
+// CapabilityFor checks a detection config against the cached tool status.
+// Tools that were never checked count as available, so a nil or unchecked
+// ToolChecker never blocks a scan.
+func (tc *ToolChecker) CapabilityFor(cfg DetectionConfig) ScanCapability {
+	result := ScanCapability{Scannable: true, MissingTools: []string{}}
+	if tc == nil {
+		return result
+	}
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	usable := false
+	for i, method := range append([]DetectionMethod{cfg.Method}, cfg.Fallbacks...) {
+		ok := true
+		for _, tool := range ToolsForMethod(method, cfg.Mode) {
+			if status, checked := tc.tools[tool]; checked && !status.Available {
+				ok = false
+				if !seen[tool] {
+					seen[tool] = true
+					result.MissingTools = append(result.MissingTools, tool)
+				}
+			}
+		}
+		if ok {
+			usable = true
+		} else if i == 0 {
+			result.Degraded = true
+		}
+	}
+	result.Scannable = usable
+	if !usable {
+		result.Degraded = false
+	}
+	return result
+}
+
 // RefreshTools re-checks all tools and updates the cache
 func (tc *ToolChecker) RefreshTools() map[string]*ToolStatus {
 	return tc.CheckAllTools()
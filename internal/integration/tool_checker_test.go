@@ -3,6 +3,7 @@ package integration
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -498,3 +499,48 @@ func TestToolChecker_WithCustomAbsolutePaths(t *testing.T) {
 		t.Error("Expected handbrake to NOT be available")
 	}
 }
+
+// =============================================================================
+// CapabilityFor tests
+// =============================================================================
+
+func TestToolChecker_CapabilityFor(t *testing.T) {
+	tc := NewToolChecker()
+	tc.tools = map[string]*ToolStatus{
+		"ffprobe":   {Name: "ffprobe", Available: false},
+		"ffmpeg":    {Name: "ffmpeg", Available: true},
+		"mediainfo": {Name: "mediainfo", Available: false},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       DetectionConfig
+		scannable bool
+		degraded  bool
+		missing   []string
+	}{
+		{"thorough ffprobe uses ffmpeg", DetectionConfig{Method: DetectionFFprobe, Mode: ModeThorough}, true, false, []string{}},
+		{"sampled ffprobe uses ffmpeg", DetectionConfig{Method: DetectionFFprobe, Mode: ModeSampled}, true, false, []string{}},
+		{"quick ffprobe without fallbacks", DetectionConfig{Method: DetectionFFprobe, Mode: ModeQuick}, false, false, []string{"ffprobe"}},
+		{"no usable fallback", DetectionConfig{Method: DetectionFFprobe, Mode: ModeQuick, Fallbacks: []DetectionMethod{DetectionMediaInfo}}, false, false, []string{"ffprobe", "mediainfo"}},
+		{"zero byte fallback", DetectionConfig{Method: DetectionMediaInfo, Fallbacks: []DetectionMethod{DetectionZeroByte}}, true, true, []string{"mediainfo"}},
+		{"unchecked tool counts as available", DetectionConfig{Method: DetectionHandBrake}, true, false, []string{}},
+		{"zero byte needs nothing", DetectionConfig{Method: DetectionZeroByte}, true, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tc.CapabilityFor(tt.cfg)
+			if got.Scannable != tt.scannable || got.Degraded != tt.degraded {
+				t.Errorf("CapabilityFor() = %+v, want scannable=%v degraded=%v", got, tt.scannable, tt.degraded)
+			}
+			if strings.Join(got.MissingTools, ",") != strings.Join(tt.missing, ",") {
+				t.Errorf("MissingTools = %v, want %v", got.MissingTools, tt.missing)
+			}
+		})
+	}
+
+	var nilChecker *ToolChecker
+	if got := nilChecker.CapabilityFor(DetectionConfig{Method: DetectionFFprobe}); !got.Scannable {
+		t.Error("A nil ToolChecker should never block scans")
+	}
+}
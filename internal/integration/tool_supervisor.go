@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// rssPollInterval is how often a supervised tool's resident memory is sampled.
+const rssPollInterval = 500 * time.Millisecond
+
+// ErrToolDisabled is returned when a tool has been disabled after repeated
+// hangs or limit violations. It is recoverable so the detector fallback chain
+// moves on to the next checker.
+var ErrToolDisabled = errors.New("disabled after repeated failures")
+
+// ToolKilledError is returned when the supervisor had to kill a tool, either
+// because it ran past its wall-clock limit or exceeded the memory limit.
+type ToolKilledError struct {
+	Tool    string
+	Reason  string
+	Timeout bool // true when killed for exceeding the wall-clock limit
+}
+
+func (e *ToolKilledError) Error() string {
+	return fmt.Sprintf("%s %s", e.Tool, e.Reason)
+}
+
+// ToolLimits bounds what a single external tool invocation may consume and
+// how the supervisor reacts when a tool misbehaves.
+type ToolLimits struct {
+	// MaxRSSBytes kills the tool once its resident memory exceeds this value.
+	// Zero disables the check. Only enforced where RSS can be read (Linux).
+	MaxRSSBytes uint64
+	// Retries is how many times a killed invocation is retried before giving up.
+	Retries int
+	// FailureThreshold is the number of consecutive kills that disable a tool.
+	FailureThreshold int
+	// DisableFor is how long a disabled tool is skipped before it is tried again.
+	DisableFor time.Duration
+}
+
+// DefaultToolLimits returns the limits used when none are configured.
+func DefaultToolLimits() ToolLimits {
+	return ToolLimits{
+		MaxRSSBytes:      4096 << 20,
+		Retries:          1,
+		FailureThreshold: 3,
+		DisableFor:       15 * time.Minute,
+	}
+}
+
+// ToolSupervisor runs external checker binaries with hard wall-clock and memory
+// limits. A tool that keeps hanging or blowing its memory limit is disabled for
+// a while (via a per-tool CircuitBreaker) so scans fall back to another checker
+// instead of stalling on it.
+type ToolSupervisor struct {
+	limits   ToolLimits
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewToolSupervisor creates a supervisor with the given limits.
+func NewToolSupervisor(limits ToolLimits) *ToolSupervisor {
+	if limits.Retries < 0 {
+		limits.Retries = 0
+	}
+	return &ToolSupervisor{
+		limits:   limits,
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// breaker returns the circuit breaker tracking failures for a tool.
+func (s *ToolSupervisor) breaker(tool string) *CircuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cb, ok := s.breakers[tool]
+	if !ok {
+		cb = NewCircuitBreaker(CircuitBreakerConfig{
+			FailureThreshold: s.limits.FailureThreshold,
+			ResetTimeout:     s.limits.DisableFor,
+			SuccessThreshold: 1,
+		})
+		s.breakers[tool] = cb
+	}
+	return cb
+}
+
+// Run executes a tool under supervision and returns its stdout and stderr.
+// A non-zero exit is returned as the plain *exec.ExitError (the tool ran and
+// reported a problem with the file). Hangs and memory blowups are killed and
+// retried up to Retries times, and count towards disabling the tool.
+func (s *ToolSupervisor) Run(tool, cmdPath string, args []string, timeout time.Duration) ([]byte, []byte, error) {
+	cb := s.breaker(tool)
+	if !cb.Allow() {
+		return nil, nil, fmt.Errorf("%s %w", tool, ErrToolDisabled)
+	}
+
+	for attempt := 0; ; attempt++ {
+		stdout, stderr, err := superviseProcess(exec.Command(cmdPath, args...), tool, timeout, s.limits.MaxRSSBytes)
+
+		var killed *ToolKilledError
+		if !errors.As(err, &killed) {
+			// Missing binaries are handled by the caller's fallback chain and
+			// say nothing about whether the tool itself is well-behaved.
+			if !isBinaryMissingError(err) {
+				cb.RecordSuccess()
+			}
+			return stdout, stderr, err
+		}
+
+		cb.RecordFailure()
+		if cb.State() == CircuitOpen {
+			logger.Errorf("Detector %s disabled for %v after %d consecutive failures (last: %s) — falling back to other detectors",
+				tool, s.limits.DisableFor, cb.Stats().ConsecutiveFailures, killed.Reason)
+			return stdout, stderr, err
+		}
+		if attempt >= s.limits.Retries {
+			return stdout, stderr, err
+		}
+		logger.Warnf("Detector %s %s — retrying (%d/%d)", tool, killed.Reason, attempt+1, s.limits.Retries)
+	}
+}
+
+// Stats returns failure statistics for every tool the supervisor has run.
+func (s *ToolSupervisor) Stats() map[string]CircuitBreakerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]CircuitBreakerStats, len(s.breakers))
+	for tool, cb := range s.breakers {
+		stats[tool] = cb.Stats()
+	}
+	return stats
+}
+
+// IsDisabled reports whether a tool is currently disabled.
+func (s *ToolSupervisor) IsDisabled(tool string) bool {
+	s.mu.Lock()
+	cb, ok := s.breakers[tool]
+	s.mu.Unlock()
+	return ok && cb.State() == CircuitOpen
+}
+
+// superviseProcess runs a single invocation of cmd, killing it when it exceeds
+// the timeout or (if maxRSS > 0) its resident memory limit.
+func superviseProcess(cmd *exec.Cmd, tool string, timeout time.Duration, maxRSS uint64) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Start command in main goroutine to avoid race on cmd.Process
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("%s failed to start: %w", tool, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var rssTick <-chan time.Time
+	if maxRSS > 0 && rssSupported {
+		ticker := time.NewTicker(rssPollInterval)
+		defer ticker.Stop()
+		rssTick = ticker.C
+	}
+
+	kill := func() {
+		if killErr := cmd.Process.Kill(); killErr != nil {
+			logger.Debugf("%s process kill returned: %v (may be already exited)", tool, killErr)
+		}
+		// Wait for the goroutine so the process is reaped before returning
+		<-done
+	}
+
+	for {
+		select {
+		case err := <-done:
+			return stdout.Bytes(), stderr.Bytes(), err
+		case <-timer.C:
+			kill()
+			return nil, stderr.Bytes(), &ToolKilledError{
+				Tool:    tool,
+				Reason:  fmt.Sprintf("timed out after %v", timeout),
+				Timeout: true,
+			}
+		case <-rssTick:
+			rss, err := processRSS(cmd.Process.Pid)
+			if err != nil || rss <= maxRSS {
+				continue
+			}
+			kill()
+			return nil, stderr.Bytes(), &ToolKilledError{
+				Tool:   tool,
+				Reason: fmt.Sprintf("killed: exceeded memory limit (%d MB RSS, limit %d MB)", rss>>20, maxRSS>>20),
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolSupervisor_Run_Success(t *testing.T) {
+	s := NewToolSupervisor(DefaultToolLimits())
+
+	stdout, _, err := s.Run("echo", "echo", []string{"hello"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(string(stdout), "hello") {
+		t.Errorf("Expected stdout to contain 'hello', got %q", stdout)
+	}
+}
+
+func TestToolSupervisor_Run_NonZeroExitIsNotAToolFailure(t *testing.T) {
+	s := NewToolSupervisor(ToolLimits{FailureThreshold: 1, DisableFor: time.Minute})
+
+	// A tool reporting a bad file must never disable the tool
+	for i := 0; i < 3; i++ {
+		_, stderr, err := s.Run("sh", "sh", []string{"-c", "echo broken >&2; exit 1"}, 5*time.Second)
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("Expected *exec.ExitError, got %v", err)
+		}
+		if !strings.Contains(string(stderr), "broken") {
+			t.Errorf("Expected stderr to be returned, got %q", stderr)
+		}
+	}
+	if s.IsDisabled("sh") {
+		t.Error("Tool should not be disabled by non-zero exits")
+	}
+}
+
+func TestToolSupervisor_Run_TimeoutRetriesThenFails(t *testing.T) {
+	s := NewToolSupervisor(ToolLimits{Retries: 1, FailureThreshold: 5, DisableFor: time.Minute})
+
+	start := time.Now()
+	_, _, err := s.Run("sleep", "sleep", []string{"10"}, 100*time.Millisecond)
+	elapsed := time.Since(start)
+
+	var killed *ToolKilledError
+	if !errors.As(err, &killed) || !killed.Timeout {
+		t.Fatalf("Expected timeout ToolKilledError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout message, got %q", err.Error())
+	}
+	if elapsed < 200*time.Millisecond {
+		t.Errorf("Expected one retry (>= 200ms total), took %v", elapsed)
+	}
+	if got := s.Stats()["sleep"].ConsecutiveFailures; got != 2 {
+		t.Errorf("ConsecutiveFailures = %d, want 2", got)
+	}
+}
+
+func TestToolSupervisor_DisablesAfterThreshold(t *testing.T) {
+	s := NewToolSupervisor(ToolLimits{Retries: 0, FailureThreshold: 2, DisableFor: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		_, _, _ = s.Run("sleep", "sleep", []string{"10"}, 50*time.Millisecond)
+	}
+	if !s.IsDisabled("sleep") {
+		t.Fatal("Expected tool to be disabled after reaching the failure threshold")
+	}
+
+	start := time.Now()
+	_, _, err := s.Run("sleep", "sleep", []string{"10"}, 5*time.Second)
+	if !errors.Is(err, ErrToolDisabled) {
+		t.Fatalf("Expected ErrToolDisabled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Disabled tool should be rejected without running")
+	}
+
+	herr := NewHealthChecker().classifyDetectorError(err, "")
+	if herr.Type != ErrorTypeToolFailure || !herr.IsRecoverable() {
+		t.Errorf("Disabled tool classified as %s, want recoverable %s", herr.Type, ErrorTypeToolFailure)
+	}
+}
+
+func TestToolSupervisor_SuccessResetsFailures(t *testing.T) {
+	s := NewToolSupervisor(ToolLimits{Retries: 0, FailureThreshold: 2, DisableFor: time.Hour})
+
+	_, _, _ = s.Run("sh", "sh", []string{"-c", "sleep 10"}, 50*time.Millisecond)
+	if _, _, err := s.Run("sh", "sh", []string{"-c", "true"}, 5*time.Second); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	_, _, _ = s.Run("sh", "sh", []string{"-c", "sleep 10"}, 50*time.Millisecond)
+
+	if s.IsDisabled("sh") {
+		t.Error("A clean run between failures should reset the consecutive failure count")
+	}
+}
+
+func TestToolSupervisor_MissingBinary(t *testing.T) {
+	s := NewToolSupervisor(ToolLimits{FailureThreshold: 1, DisableFor: time.Hour})
+
+	_, _, err := s.Run("missing", "nonexistent-command-xyz-123", nil, 5*time.Second)
+	if err == nil || !isBinaryMissingError(err) {
+		t.Fatalf("Expected binary missing error, got %v", err)
+	}
+	if s.IsDisabled("missing") {
+		t.Error("Missing binary should not count as a tool failure")
+	}
+}
+
+func TestToolSupervisor_MemoryLimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("RSS limits are only enforced on Linux")
+	}
+
+	// Any real process uses more than 1 byte of resident memory
+	s := NewToolSupervisor(ToolLimits{MaxRSSBytes: 1, FailureThreshold: 5, DisableFor: time.Minute})
+
+	start := time.Now()
+	_, _, err := s.Run("sleep", "sleep", []string{"10"}, 30*time.Second)
+	var killed *ToolKilledError
+	if !errors.As(err, &killed) || killed.Timeout {
+		t.Fatalf("Expected memory-limit ToolKilledError, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected the process to be killed well before the timeout")
+	}
+
+	herr := NewHealthChecker().classifyDetectorError(err, "")
+	if herr.Type != ErrorTypeToolFailure {
+		t.Errorf("Memory kill classified as %s, want %s", herr.Type, ErrorTypeToolFailure)
+	}
+}
+
+func TestProcessRSS(t *testing.T) {
+	if !rssSupported {
+		if _, err := processRSS(os.Getpid()); err == nil {
+			t.Error("Expected error on unsupported platform")
+		}
+		return
+	}
+
+	rss, err := processRSS(os.Getpid())
+	if err != nil {
+		t.Fatalf("processRSS failed: %v", err)
+	}
+	if rss == 0 {
+		t.Error("Expected non-zero RSS for the test process")
+	}
+
+	if _, err := processRSS(-1); err == nil {
+		t.Error("Expected error for invalid pid")
+	}
+}
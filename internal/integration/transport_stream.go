@@ -0,0 +1,229 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// transportStreamExts are the extensions of MPEG transport stream files:
+// DVB/ATSC recordings (.ts, .tp, .trp) and Blu-ray/AVCHD streams (.m2ts, .mts).
+var transportStreamExts = map[string]bool{
+	".ts":   true,
+	".tp":   true,
+	".trp":  true,
+	".m2ts": true,
+	".mts":  true,
+}
+
+// IsTransportStream reports whether path is an MPEG-TS/M2TS file by its extension.
+func IsTransportStream(path string) bool {
+	return transportStreamExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// TransportStreamLimits is how much damage a transport stream may show before
+// it counts as corrupt. Recordings of live TV routinely lose a few packets to
+// weak reception, which players skip over, so a full decode of a TS file
+// tolerates these instead of stopping at the first error.
+type TransportStreamLimits struct {
+	// MaxContinuityErrors is the number of continuity counter errors (lost or
+	// duplicated packets) tolerated across the file.
+	MaxContinuityErrors int
+	// MaxDecodeErrors is the number of other errors ffmpeg may report while decoding.
+	MaxDecodeErrors int
+	// MaxTimestampGap is the largest jump in packet timestamps tolerated
+	// within a stream, forwards or backwards. Larger gaps come from PCR
+	// discontinuities such as a recording that lost signal for a while.
+	MaxTimestampGap time.Duration
+}
+
+// DefaultTransportStreamLimits returns the limits used when none are configured.
+func DefaultTransportStreamLimits() TransportStreamLimits {
+	return TransportStreamLimits{
+		MaxContinuityErrors: 100,
+		MaxDecodeErrors:     50,
+		MaxTimestampGap:     10 * time.Second,
+	}
+}
+
+// TransportStreamReport sums up the problems found in a transport stream.
+type TransportStreamReport struct {
+	ContinuityErrors int
+	DecodeErrors     int
+	// TimestampGaps counts the gaps larger than the limit; MaxTimestampGap is
+	// the largest gap seen, in seconds.
+	TimestampGaps   int
+	MaxTimestampGap float64
+	FirstError      string
+}
+
+// TransportStreamError is returned when a transport stream exceeds its limits.
+type TransportStreamError struct {
+	Report TransportStreamReport
+	Limits TransportStreamLimits
+}
+
+func (e *TransportStreamError) Error() string {
+	r, l := e.Report, e.Limits
+	var reasons []string
+	if r.ContinuityErrors > l.MaxContinuityErrors {
+		reasons = append(reasons, fmt.Sprintf("%d continuity errors (limit %d)", r.ContinuityErrors, l.MaxContinuityErrors))
+	}
+	if r.DecodeErrors > l.MaxDecodeErrors {
+		reasons = append(reasons, fmt.Sprintf("%d decode errors (limit %d)", r.DecodeErrors, l.MaxDecodeErrors))
+	}
+	if r.TimestampGaps > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d timestamp gaps, largest %ss (limit %s)",
+			r.TimestampGaps, formatSeconds(r.MaxTimestampGap), l.MaxTimestampGap))
+	}
+	msg := "transport stream damaged: " + strings.Join(reasons, ", ")
+	if r.FirstError != "" {
+		msg += "; first error: " + r.FirstError
+	}
+	return msg
+}
+
+// Exceeds reports whether the report is over any of the limits.
+func (r TransportStreamReport) Exceeds(limits TransportStreamLimits) bool {
+	return r.ContinuityErrors > limits.MaxContinuityErrors ||
+		r.DecodeErrors > limits.MaxDecodeErrors ||
+		r.TimestampGaps > 0
+}
+
+var (
+	continuityErrorRe = regexp.MustCompile(`Continuity check failed for pid \d+`)
+	// ffmpeg's "level" log flag prefixes each line with its level
+	ffmpegLogLevelRe = regexp.MustCompile(`\[(panic|fatal|error|warning)\]`)
+)
+
+// isBenignTSLine reports whether an ffmpeg log line describes packet loss the
+// transport stream demuxer recovers from rather than damage to the content.
+func isBenignTSLine(line string) bool {
+	return continuityErrorRe.MatchString(line) ||
+		strings.Contains(line, "Packet corrupt") ||
+		strings.Contains(line, "PES packet size mismatch")
+}
+
+// parseTransportStreamLog counts the continuity and decode errors in the
+// stderr of an ffmpeg run with -v level+warning. Warnings other than
+// continuity errors, such as timestamp discontinuities, aren't counted since
+// the timestamp check measures those.
+func parseTransportStreamLog(stderr []byte) TransportStreamReport {
+	var report TransportStreamReport
+	scanner := bufio.NewScanner(bytes.NewReader(stderr))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if continuityErrorRe.MatchString(line) {
+			report.ContinuityErrors++
+			continue
+		}
+		level := ffmpegLogLevelRe.FindStringSubmatch(line)
+		if level == nil || level[1] == "warning" || isBenignTSLine(line) {
+			continue
+		}
+		report.DecodeErrors++
+		if report.FirstError == "" {
+			report.FirstError = line
+		}
+	}
+	return report
+}
+
+// measureTimestampGaps finds the jumps in packet timestamps larger than
+// maxGap within each stream. Its input is ffprobe's
+// -show_entries packet=stream_index,dts_time -of csv=p=0 output.
+func measureTimestampGaps(packets []byte, maxGap time.Duration) (gaps int, largest float64) {
+	last := map[string]float64{}
+	scanner := bufio.NewScanner(bytes.NewReader(packets))
+	for scanner.Scan() {
+		stream, dts, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ",")
+		if !ok {
+			continue
+		}
+		dts, _, _ = strings.Cut(dts, ",")
+		t, err := strconv.ParseFloat(dts, 64)
+		if err != nil {
+			continue // N/A for packets without a timestamp
+		}
+		if prev, seen := last[stream]; seen {
+			gap := math.Abs(t - prev)
+			if gap > largest {
+				largest = gap
+			}
+			if gap > maxGap.Seconds() {
+				gaps++
+			}
+		}
+		last[stream] = t
+	}
+	return gaps, largest
+}
+
+// transportStreamLimits returns the configured limits or the defaults.
+func (hc *CmdHealthChecker) transportStreamLimits() TransportStreamLimits {
+	if hc.TransportStream == nil {
+		return DefaultTransportStreamLimits()
+	}
+	return *hc.TransportStream
+}
+
+// runTransportStreamCheck is the thorough check of a transport stream. The
+// file is fully decoded like any other, but without -xerror: continuity and
+// decode errors are counted against the limits instead. A second, demux-only
+// pass measures the timestamp gaps that PCR discontinuities leave behind.
+func (hc *CmdHealthChecker) runTransportStreamCheck(path string, customArgs []string) error {
+	limits := hc.transportStreamLimits()
+
+	args := []string{"-v", "level+warning"}
+	args = append(args, customArgs...)
+	args = append(args, "-i", path, "-f", "null", "-")
+	_, stderr, err := hc.runSupervised("ffmpeg", hc.FFmpegPath, args, 10*time.Minute)
+	if err != nil {
+		if isBinaryMissingError(err) {
+			logger.Warnf("Detector ffmpeg not found at %q — check HEALARR_FFMPEG_PATH or install the tool in the container", hc.FFmpegPath)
+			return fmt.Errorf("ffmpeg binary not found: %w", err)
+		}
+		if isSupervisorError(err) {
+			return err
+		}
+		// ffmpeg only gives up on a transport stream it can't demux at all
+		stderrText := strings.TrimSpace(string(stderr))
+		if stderrText == "" {
+			return fmt.Errorf("ffmpeg failed: %w", err)
+		}
+		return fmt.Errorf("ffmpeg failed: %s", stderrText)
+	}
+	report := parseTransportStreamLog(stderr)
+
+	packets, _, err := hc.runSupervised("ffprobe", hc.FFprobePath, []string{
+		"-v", "error", "-show_entries", "packet=stream_index,dts_time", "-of", "csv=p=0", path,
+	}, 10*time.Minute)
+	if err != nil {
+		if isSupervisorError(err) {
+			return err
+		}
+		// The decode already vouched for the content; don't fail on the timestamp pass
+		logger.Warnf("Could not measure timestamp gaps of %s: %v", path, err)
+	} else {
+		report.TimestampGaps, report.MaxTimestampGap = measureTimestampGaps(packets, limits.MaxTimestampGap)
+	}
+
+	logger.Debugf("Transport stream check of %s: %d continuity errors, %d decode errors, largest timestamp gap %ss",
+		path, report.ContinuityErrors, report.DecodeErrors, formatSeconds(report.MaxTimestampGap))
+	if report.Exceeds(limits) {
+		return &TransportStreamError{Report: report, Limits: limits}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsTransportStream(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/recordings/news.ts":   true,
+		"/recordings/NEWS.TS":   true,
+		"/bluray/00001.m2ts":    true,
+		"/camera/clip.MTS":      true,
+		"/recordings/show.tp":   true,
+		"/movies/movie.mkv":     false,
+		"/movies/movie.mp4":     false,
+		"/subtitles/movie.tsv":  false,
+		"/recordings/ts/show.x": false,
+	} {
+		if got := IsTransportStream(path); got != want {
+			t.Errorf("IsTransportStream(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestParseTransportStreamLog(t *testing.T) {
+	stderr := strings.Join([]string{
+		"[mpegts @ 0x55d0] [warning] Continuity check failed for pid 256 expected 3 got 5",
+		"[mpegts @ 0x55d0] [error] Continuity check failed for pid 257 expected 9 got 1",
+		"[mpegts @ 0x55d0] [warning] PES packet size mismatch",
+		"[h264 @ 0x55d1] [error] Packet corrupt (stream = 0, dts = 1234)",
+		"[h264 @ 0x55d1] [error] concealing 120 DC, 120 AC, 120 MV errors in P frame",
+		"[mp2 @ 0x55d2] [warning] timestamp discontinuity for stream #0:1",
+		"[h264 @ 0x55d1] [error] Invalid NAL unit size (1234 > 567).",
+		"",
+	}, "\n")
+
+	report := parseTransportStreamLog([]byte(stderr))
+	if report.ContinuityErrors != 2 {
+		t.Errorf("ContinuityErrors = %d, want 2", report.ContinuityErrors)
+	}
+	if report.DecodeErrors != 2 {
+		t.Errorf("DecodeErrors = %d, want 2", report.DecodeErrors)
+	}
+	if !strings.Contains(report.FirstError, "concealing") {
+		t.Errorf("FirstError = %q", report.FirstError)
+	}
+}
+
+func TestMeasureTimestampGaps(t *testing.T) {
+	packets := strings.Join([]string{
+		"0,1.000000",
+		"1,1.010000",
+		"0,1.040000",
+		"1,N/A",
+		"0,31.040000", // 30s signal loss
+		"1,1.050000",
+		"0,31.080000,",
+		"1,1.090000",
+		"0,2.000000", // timestamps reset
+	}, "\n")
+
+	gaps, largest := measureTimestampGaps([]byte(packets), 10*time.Second)
+	if gaps != 2 {
+		t.Errorf("gaps = %d, want 2", gaps)
+	}
+	if largest < 29.9 || largest > 30.1 {
+		t.Errorf("largest = %v, want about 30", largest)
+	}
+
+	if gaps, _ := measureTimestampGaps([]byte(packets), time.Minute); gaps != 0 {
+		t.Errorf("gaps with a 1m limit = %d, want 0", gaps)
+	}
+}
+
+func TestTransportStreamReport_Exceeds(t *testing.T) {
+	limits := TransportStreamLimits{MaxContinuityErrors: 10, MaxDecodeErrors: 5, MaxTimestampGap: 10 * time.Second}
+
+	tests := []struct {
+		name   string
+		report TransportStreamReport
+		want   bool
+	}{
+		{"clean", TransportStreamReport{}, false},
+		{"benign glitches", TransportStreamReport{ContinuityErrors: 10, DecodeErrors: 5, MaxTimestampGap: 2}, false},
+		{"continuity errors", TransportStreamReport{ContinuityErrors: 11}, true},
+		{"decode errors", TransportStreamReport{DecodeErrors: 6}, true},
+		{"timestamp gap", TransportStreamReport{TimestampGaps: 1, MaxTimestampGap: 45}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Exceeds(limits); got != tt.want {
+				t.Errorf("Exceeds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	err := &TransportStreamError{Report: TransportStreamReport{ContinuityErrors: 11, TimestampGaps: 1, MaxTimestampGap: 45}, Limits: limits}
+	want := "transport stream damaged: 11 continuity errors (limit 10), 1 timestamp gaps, largest 45s (limit 10s)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if herr := NewHealthChecker().classifyDetectorError(err, ""); herr.Type != ErrorTypeCorruptStream {
+		t.Errorf("classified as %s, want %s", herr.Type, ErrorTypeCorruptStream)
+	}
+}
+
+func TestCheckWithConfig_TransportStream(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires shell scripts as fake detectors")
+	}
+
+	dir := t.TempDir()
+	writeTool := func(name, script string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	ffmpeg := writeTool("ffmpeg", `for i in 1 2 3; do echo "[mpegts @ 0x1] [warning] Continuity check failed for pid 256 expected 1 got $i" >&2; done
+echo "[h264 @ 0x2] [error] concealing 10 DC, 10 AC, 10 MV errors in I frame" >&2
+`)
+	ffprobe := writeTool("ffprobe", `printf '0,1.0\n0,1.04\n0,1.08\n'`)
+
+	for _, name := range []string{"recording.ts", "movie.mkv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("media"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hc := NewHealthCheckerWithPaths(ffprobe, ffmpeg, "mediainfo", "HandBrakeCLI")
+	config := DetectionConfig{Method: DetectionFFprobe, Mode: ModeThorough}
+
+	t.Run("glitches within limits", func(t *testing.T) {
+		healthy, herr := hc.CheckWithConfig(filepath.Join(dir, "recording.ts"), config)
+		if !healthy {
+			t.Errorf("Expected healthy recording, got %+v", herr)
+		}
+	})
+
+	t.Run("strict limits", func(t *testing.T) {
+		strict := *hc
+		strict.TransportStream = &TransportStreamLimits{MaxContinuityErrors: 2, MaxDecodeErrors: 5, MaxTimestampGap: time.Second}
+		healthy, herr := strict.CheckWithConfig(filepath.Join(dir, "recording.ts"), config)
+		if healthy || herr == nil || herr.Type != ErrorTypeCorruptStream {
+			t.Fatalf("Expected CorruptStream, got healthy=%v err=%+v", healthy, herr)
+		}
+		if !strings.Contains(herr.Message, "3 continuity errors (limit 2)") {
+			t.Errorf("Unexpected message %q", herr.Message)
+		}
+	})
+
+	t.Run("only transport streams tolerate errors", func(t *testing.T) {
+		for name, wantArg := range map[string]string{"recording.ts": "level+warning", "movie.mkv": argXError} {
+			rc := hc.withRecorder()
+			if ok, _, herr := rc.checkWithConfig(filepath.Join(dir, name), config); !ok {
+				t.Fatalf("%s: expected healthy, got %+v", name, herr)
+			}
+			runs := rc.recorder.snapshot()
+			if len(runs) == 0 || runs[0].Tool != "ffmpeg" || !strings.Contains(strings.Join(runs[0].Args, " "), wantArg) {
+				t.Errorf("%s: expected ffmpeg with %s, got %+v", name, wantArg, runs)
+			}
+		}
+	})
+}
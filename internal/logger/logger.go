@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // LogLevel represents the severity level of a log message.
@@ -143,14 +145,15 @@ func broadcast(entry LogEntry) {
 	}
 }
 
-// Log writes a formatted message at the specified level to stdout, file, and subscribers.
+// Log writes a formatted message at the specified level to stdout, file, and
+// subscribers. Secrets in the message are masked.
 func Log(level LogLevel, format string, v ...interface{}) {
 	// Filter messages below minimum level
 	if levelPriority(level) < levelPriority(minLevel) {
 		return
 	}
 
-	msg := fmt.Sprintf(format, v...)
+	msg := redact.Text(fmt.Sprintf(format, v...))
 	timestamp := time.Now().Format(time.RFC3339)
 
 	// Print to stdout and file (via log.SetOutput in init)
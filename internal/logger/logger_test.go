@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // =============================================================================
@@ -596,3 +598,30 @@ func TestLog_WritesToFile(t *testing.T) {
 		t.Error("Log file should contain the logged message")
 	}
 }
+
+func TestLog_MasksSecrets(t *testing.T) {
+	originalListeners := listeners
+	listeners = make([]chan LogEntry, 0)
+	originalLevel := minLevel
+	defer func() {
+		listeners = originalListeners
+		minLevel = originalLevel
+	}()
+	minLevel = Debug
+
+	redact.Register("registered-logger-secret")
+	ch := Subscribe()
+	Errorf("GET http://sonarr:8989/api/v3/series?apikey=%s failed for %s", "abc123", "registered-logger-secret")
+
+	select {
+	case got := <-ch:
+		if strings.Contains(got.Message, "abc123") || strings.Contains(got.Message, "registered-logger-secret") {
+			t.Errorf("Log line leaks a secret: %s", got.Message)
+		}
+		if !strings.Contains(got.Message, redact.Mask) {
+			t.Errorf("Expected masked secrets in %q", got.Message)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Log line was not broadcast")
+	}
+}
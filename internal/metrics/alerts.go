@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alert names, shared by the Prometheus rules and GET /api/alerts.
+const (
+	AlertRemediationFailureRate = "HealarrRemediationFailureRate"
+	AlertArrCircuitBreakerOpen  = "HealarrArrCircuitBreakerOpen"
+	AlertScanOverdue            = "HealarrScanOverdue"
+)
+
+// AlertThresholds are the conditions the alerts fire on.
+type AlertThresholds struct {
+	// FailureRate is the share of finished remediations that reached max
+	// retries within FailureRateWindow, once at least FailureRateMin finished.
+	FailureRate       float64
+	FailureRateWindow time.Duration
+	FailureRateMin    int
+
+	// BreakerOpenFor is how long an *arr circuit breaker stays open
+	BreakerOpenFor time.Duration
+
+	// ScanOverdueAfter is how long a scheduled scan path goes without a
+	// finished scan
+	ScanOverdueAfter time.Duration
+}
+
+// DefaultAlertThresholds returns the thresholds of the shipped rules.
+func DefaultAlertThresholds() AlertThresholds {
+	return AlertThresholds{
+		FailureRate:       0.25,
+		FailureRateWindow: 24 * time.Hour,
+		FailureRateMin:    4,
+		BreakerOpenFor:    10 * time.Minute,
+		ScanOverdueAfter:  7 * 24 * time.Hour,
+	}
+}
+
+// AlertRule is a Prometheus alerting rule.
+type AlertRule struct {
+	Alert       string
+	Expr        string
+	For         time.Duration
+	Severity    string
+	Summary     string
+	Description string
+}
+
+// AlertRules returns the recommended alerting rules for thresholds.
+func AlertRules(t AlertThresholds) []AlertRule {
+	window := promDuration(t.FailureRateWindow)
+	finished := fmt.Sprintf("sum(increase(%s[%s]))", MetricRemediationsTotal, window)
+	return []AlertRule{
+		{
+			Alert: AlertRemediationFailureRate,
+			Expr: fmt.Sprintf(`sum(increase(%s{outcome="max_retries"}[%s])) / %s > %s and %s >= %d`,
+				MetricRemediationsTotal, window, finished, strconv.FormatFloat(t.FailureRate, 'f', -1, 64), finished, t.FailureRateMin),
+			Severity:    "warning",
+			Summary:     "Many remediations are failing",
+			Description: fmt.Sprintf("{{ $value | humanizePercentage }} of the remediations finished in the last %s reached max retries.", window),
+		},
+		{
+			Alert:       AlertArrCircuitBreakerOpen,
+			Expr:        MetricArrCircuitBreakerOpen + " == 1",
+			For:         t.BreakerOpenFor,
+			Severity:    "critical",
+			Summary:     "An *arr instance is unreachable",
+			Description: "The circuit breaker of *arr instance {{ $labels.instance_id }} has been open for more than " + promDuration(t.BreakerOpenFor) + ".",
+		},
+		{
+			Alert:       AlertScanOverdue,
+			Expr:        fmt.Sprintf("time() - %s > %d", MetricLastScanCompleted, int64(t.ScanOverdueAfter.Seconds())),
+			Severity:    "warning",
+			Summary:     "A scheduled scan is overdue",
+			Description: "Scan path {{ $labels.path_id }} has not finished a scan in more than " + promDuration(t.ScanOverdueAfter) + ".",
+		},
+	}
+}
+
+// AlertRulesYAML renders the rules for thresholds as a Prometheus rule file.
+func AlertRulesYAML(t AlertThresholds) []byte {
+	var b strings.Builder
+	b.WriteString("# Recommended Prometheus alerting rules for Healarr.\n")
+	b.WriteString("# Generated by Healarr - GET /api/alerts/rules returns them for the\n")
+	b.WriteString("# configured thresholds.\n")
+	b.WriteString("groups:\n  - name: healarr\n    rules:\n")
+	for _, r := range AlertRules(t) {
+		fmt.Fprintf(&b, "      - alert: %s\n", r.Alert)
+		fmt.Fprintf(&b, "        expr: %s\n", strconv.Quote(r.Expr))
+		if r.For > 0 {
+			fmt.Fprintf(&b, "        for: %s\n", promDuration(r.For))
+		}
+		fmt.Fprintf(&b, "        labels:\n          severity: %s\n", r.Severity)
+		fmt.Fprintf(&b, "        annotations:\n          summary: %s\n          description: %s\n",
+			strconv.Quote(r.Summary), strconv.Quote(r.Description))
+	}
+	return []byte(b.String())
+}
+
+// promDuration formats d in the largest whole Prometheus unit, e.g. "7d".
+func promDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", int64(d.Seconds()))
+	}
+}
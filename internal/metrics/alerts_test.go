@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The shipped rule file is generated from the default thresholds; regenerate
+// it with AlertRulesYAML(DefaultAlertThresholds()) when the rules change.
+func TestAlertRulesYAML_MatchesShippedFile(t *testing.T) {
+	shipped, err := os.ReadFile("../../docs/prometheus/healarr-alerts.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := AlertRulesYAML(DefaultAlertThresholds()); !bytes.Equal(got, shipped) {
+		t.Errorf("docs/prometheus/healarr-alerts.yml is out of date, want:\n%s", got)
+	}
+}
+
+func TestAlertRules(t *testing.T) {
+	th := DefaultAlertThresholds()
+	th.ScanOverdueAfter = 36 * time.Hour
+
+	rules := AlertRules(th)
+	if len(rules) != 3 {
+		t.Fatalf("got %d rules, want 3", len(rules))
+	}
+	want := map[string]string{
+		AlertRemediationFailureRate: MetricRemediationsTotal,
+		AlertArrCircuitBreakerOpen:  MetricArrCircuitBreakerOpen,
+		AlertScanOverdue:            MetricLastScanCompleted,
+	}
+	for _, r := range rules {
+		if !strings.Contains(r.Expr, want[r.Alert]) {
+			t.Errorf("%s: expr %q doesn't use %s", r.Alert, r.Expr, want[r.Alert])
+		}
+	}
+	if !strings.Contains(rules[2].Expr, "> 129600") || !strings.Contains(rules[2].Description, "36h") {
+		t.Errorf("scan overdue rule ignores its threshold: %+v", rules[2])
+	}
+	if yaml := string(AlertRulesYAML(th)); !strings.Contains(yaml, "        for: 10m\n") {
+		t.Errorf("rule file lacks the breaker's for clause:\n%s", yaml)
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		7 * 24 * time.Hour: "7d",
+		36 * time.Hour:     "36h",
+		10 * time.Minute:   "10m",
+		90 * time.Second:   "90s",
+	}
+	for d, want := range tests {
+		if got := promDuration(d); got != want {
+			t.Errorf("promDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -1,17 +1,41 @@
 package metrics
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
 )
 
+// Names of the metrics the alert rules are built from.
+const (
+	MetricRemediationsTotal     = "healarr_remediations_total"
+	MetricArrCircuitBreakerOpen = "healarr_arr_circuit_breaker_open"
+	MetricLastScanCompleted     = "healarr_last_scan_completed_timestamp_seconds"
+)
+
+// CircuitBreakerSource reports the circuit breaker state of each *arr instance.
+type CircuitBreakerSource interface {
+	GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats
+}
+
+// LastScansFunc returns when each scheduled scan path last finished a scan.
+type LastScansFunc func(ctx context.Context) (map[int64]time.Time, error)
+
+// DurabilityFunc returns how many audited replacements of resolved
+// corruptions were still healthy and how many failed, over the audit window.
+type DurabilityFunc func(ctx context.Context) (healthy, failed int, err error)
+
 // MetricsService exposes Prometheus metrics for Healarr
 type MetricsService struct {
 	eventBus *eventbus.EventBus
@@ -22,17 +46,33 @@ type MetricsService struct {
 	verificationsTotal  *prometheus.CounterVec
 	scansTotal          *prometheus.CounterVec
 	notificationsTotal  *prometheus.CounterVec
+	slaBreachesTotal    prometheus.Counter
+	invalidEventsTotal  *prometheus.CounterVec
+	invalidTransitions  *prometheus.CounterVec
+	retentionPruned     *prometheus.CounterVec
+	dbBusyRetries       *prometheus.CounterVec
 
 	// Gauges
 	activeRemediations  prometheus.Gauge
 	queuedRemediations  prometheus.Gauge
 	stuckRemediations   prometheus.Gauge
 	unhealthyInstances  prometheus.Gauge
+	detectionOnly       prometheus.Gauge
 	currentScanProgress prometheus.Gauge
 
+	// Gauges refreshed from their sources on every scrape
+	arrBreakerOpen    *prometheus.GaugeVec
+	lastScanCompleted *prometheus.GaugeVec
+	durabilityRatio   *prometheus.GaugeVec
+	resolutionAudits  *prometheus.GaugeVec
+	breakers          CircuitBreakerSource
+	lastScans         LastScansFunc
+	durability        DurabilityFunc
+
 	// Histograms
 	remediationDuration *prometheus.HistogramVec
 	scanDuration        prometheus.Histogram
+	dbQueryDuration     *prometheus.HistogramVec
 
 	// Internal tracking
 	mu                     sync.Mutex
@@ -40,6 +80,7 @@ type MetricsService struct {
 	queuedRemediationCount int
 	stuckRemediationCount  int
 	unhealthyInstanceCount int
+	detectionOnlyCount     int
 }
 
 // NewMetricsService creates and registers Prometheus metrics
@@ -57,7 +98,7 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 
 		remediationsTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
-				Name: "healarr_remediations_total",
+				Name: MetricRemediationsTotal,
 				Help: "Total number of remediations by outcome",
 			},
 			[]string{"outcome"}, // success, failed, max_retries
@@ -87,6 +128,37 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 			[]string{"outcome"}, // sent, failed
 		),
 
+		slaBreachesTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "healarr_sla_breaches_total",
+				Help: "Total number of corruptions left unresolved past the resolution SLA",
+			},
+		),
+
+		invalidEventsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_invalid_events_total",
+				Help: "Total number of published events whose payload failed schema validation",
+			},
+			[]string{"event_type"},
+		),
+
+		invalidTransitions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_invalid_transitions_total",
+				Help: "Total number of published corruption events that did not follow from the corruption's state",
+			},
+			[]string{"event_type", "from"},
+		),
+
+		retentionPruned: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_retention_pruned_total",
+				Help: "Total number of rows pruned by database maintenance by retention category",
+			},
+			[]string{"category"}, // resolved, failed, scan_events, other_events, scans, diagnostics, scan_files
+		),
+
 		activeRemediations: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "healarr_active_remediations",
@@ -115,6 +187,13 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 			},
 		),
 
+		detectionOnly: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "healarr_detection_only_instances",
+				Help: "Number of *arr instances whose remediation is paused by an outage",
+			},
+		),
+
 		currentScanProgress: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "healarr_scan_progress_percent",
@@ -122,6 +201,38 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 			},
 		),
 
+		arrBreakerOpen: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricArrCircuitBreakerOpen,
+				Help: "1 while the circuit breaker of an *arr instance is open or half-open, else 0",
+			},
+			[]string{"instance_id"},
+		),
+
+		lastScanCompleted: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLastScanCompleted,
+				Help: "Unix time a scheduled scan path last finished a scan, or was added if it never did",
+			},
+			[]string{"path_id"},
+		),
+
+		durabilityRatio: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "healarr_resolution_durability_ratio",
+				Help: "Share of audited replacements of resolved corruptions that were still healthy, over the audit window",
+			},
+			nil, // absent until an audit finished
+		),
+
+		resolutionAudits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "healarr_resolution_audits",
+				Help: "Audited replacements of resolved corruptions by outcome, over the audit window",
+			},
+			[]string{"outcome"}, // healthy, failed
+		),
+
 		remediationDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "healarr_remediation_duration_seconds",
@@ -138,6 +249,23 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 				Buckets: prometheus.ExponentialBuckets(1, 2, 12), // 1s to ~1hour
 			},
 		),
+
+		dbQueryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "healarr_db_query_duration_seconds",
+				Help:    "Duration of database operations in seconds, including retries on SQLITE_BUSY",
+				Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16), // 0.5ms to ~16s
+			},
+			[]string{"operation"},
+		),
+
+		dbBusyRetries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_db_busy_retries_total",
+				Help: "Total number of database operations retried because the database was locked",
+			},
+			[]string{"operation"},
+		),
 	}
 
 	// Register all metrics
@@ -147,13 +275,24 @@ func NewMetricsService(eb *eventbus.EventBus) *MetricsService {
 		m.verificationsTotal,
 		m.scansTotal,
 		m.notificationsTotal,
+		m.slaBreachesTotal,
+		m.invalidEventsTotal,
+		m.invalidTransitions,
+		m.retentionPruned,
 		m.activeRemediations,
 		m.queuedRemediations,
 		m.stuckRemediations,
 		m.unhealthyInstances,
+		m.detectionOnly,
 		m.currentScanProgress,
+		m.arrBreakerOpen,
+		m.lastScanCompleted,
+		m.durabilityRatio,
+		m.resolutionAudits,
 		m.remediationDuration,
 		m.scanDuration,
+		m.dbQueryDuration,
+		m.dbBusyRetries,
 	)
 
 	return m
@@ -177,13 +316,87 @@ func (m *MetricsService) Start() {
 	m.eventBus.Subscribe(domain.StuckRemediation, m.handleStuckRemediation)
 	m.eventBus.Subscribe(domain.InstanceUnhealthy, m.handleInstanceUnhealthy)
 	m.eventBus.Subscribe(domain.InstanceHealthy, m.handleInstanceHealthy)
+	m.eventBus.Subscribe(domain.SLABreached, m.handleSLABreached)
+	m.eventBus.Subscribe(domain.RemediationPaused, m.handleRemediationPaused)
+	m.eventBus.Subscribe(domain.RemediationResumed, m.handleRemediationResumed)
+	m.eventBus.OnInvalidEvent(m.handleInvalidEvent)
 
 	logger.Infof("Metrics service started")
 }
 
 // Handler returns the Prometheus HTTP handler for /metrics endpoint
 func (m *MetricsService) Handler() http.Handler {
-	return promhttp.Handler()
+	h := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.refreshSourcedGauges(r.Context())
+		h.ServeHTTP(w, r)
+	})
+}
+
+// SetCircuitBreakerSource sets where the circuit breaker gauge is read from.
+func (m *MetricsService) SetCircuitBreakerSource(source CircuitBreakerSource) {
+	m.mu.Lock()
+	m.breakers = source
+	m.mu.Unlock()
+}
+
+// SetLastScansSource sets where the last scan gauge is read from.
+func (m *MetricsService) SetLastScansSource(fn LastScansFunc) {
+	m.mu.Lock()
+	m.lastScans = fn
+	m.mu.Unlock()
+}
+
+// SetDurabilitySource sets where the resolution durability gauges are read from.
+func (m *MetricsService) SetDurabilitySource(fn DurabilityFunc) {
+	m.mu.Lock()
+	m.durability = fn
+	m.mu.Unlock()
+}
+
+// refreshSourcedGauges reads the gauges the alert rules use from their
+// sources, so a scrape sees the current state.
+func (m *MetricsService) refreshSourcedGauges(ctx context.Context) {
+	m.mu.Lock()
+	breakers, lastScans, durability := m.breakers, m.lastScans, m.durability
+	m.mu.Unlock()
+
+	if breakers != nil {
+		m.arrBreakerOpen.Reset()
+		for id, stats := range breakers.GetCircuitBreakerStats() {
+			open := 0.0
+			if stats.State != integration.CircuitClosed {
+				open = 1
+			}
+			m.arrBreakerOpen.WithLabelValues(strconv.FormatInt(id, 10)).Set(open)
+		}
+	}
+
+	if lastScans != nil {
+		scans, err := lastScans(ctx)
+		if err != nil {
+			logger.Debugf("Metrics: failed to load last scans: %v", err)
+		} else {
+			m.lastScanCompleted.Reset()
+			for id, at := range scans {
+				m.lastScanCompleted.WithLabelValues(strconv.FormatInt(id, 10)).Set(float64(at.Unix()))
+			}
+		}
+	}
+
+	if durability != nil {
+		healthy, failed, err := durability(ctx)
+		if err != nil {
+			logger.Debugf("Metrics: failed to load resolution durability: %v", err)
+			return
+		}
+		m.resolutionAudits.WithLabelValues("healthy").Set(float64(healthy))
+		m.resolutionAudits.WithLabelValues("failed").Set(float64(failed))
+		m.durabilityRatio.Reset()
+		if healthy+failed > 0 {
+			m.durabilityRatio.WithLabelValues().Set(float64(healthy) / float64(healthy+failed))
+		}
+	}
 }
 
 // Event handlers
@@ -219,8 +432,15 @@ func (m *MetricsService) handleDeletionStarted(_ domain.Event) {
 	m.mu.Unlock()
 }
 
-func (m *MetricsService) handleVerificationSuccess(_ domain.Event) {
-	m.verificationsTotal.WithLabelValues("success").Inc()
+func (m *MetricsService) handleVerificationSuccess(event domain.Event) {
+	// Audits are counted by the resolution durability gauges
+	if !event.GetBoolOr("audit", false) {
+		m.verificationsTotal.WithLabelValues("success").Inc()
+	}
+	// A passing reverification repeats a remediation that was already counted
+	if _, ok := event.GetInt64("reverification_of"); ok {
+		return
+	}
 	m.remediationsTotal.WithLabelValues("success").Inc()
 
 	m.mu.Lock()
@@ -298,6 +518,50 @@ func (m *MetricsService) handleInstanceHealthy(_ domain.Event) {
 	m.mu.Unlock()
 }
 
+func (m *MetricsService) handleSLABreached(_ domain.Event) {
+	m.slaBreachesTotal.Inc()
+}
+
+func (m *MetricsService) handleInvalidEvent(eventType domain.EventType, err error) {
+	var transitionErr *domain.TransitionError
+	if errors.As(err, &transitionErr) {
+		m.invalidTransitions.WithLabelValues(string(eventType), string(transitionErr.From)).Inc()
+		return
+	}
+	m.invalidEventsTotal.WithLabelValues(string(eventType)).Inc()
+}
+
+func (m *MetricsService) handleRemediationPaused(_ domain.Event) {
+	m.mu.Lock()
+	m.detectionOnlyCount++
+	m.detectionOnly.Set(float64(m.detectionOnlyCount))
+	m.mu.Unlock()
+}
+
+func (m *MetricsService) handleRemediationResumed(_ domain.Event) {
+	m.mu.Lock()
+	if m.detectionOnlyCount > 0 {
+		m.detectionOnlyCount--
+		m.detectionOnly.Set(float64(m.detectionOnlyCount))
+	}
+	m.mu.Unlock()
+}
+
+// RecordPruned counts rows pruned by database maintenance in a retention
+// category. Register it with Repository.SetPruneObserver.
+func (m *MetricsService) RecordPruned(category string, count int64) {
+	m.retentionPruned.WithLabelValues(category).Add(float64(count))
+}
+
+// RecordDBLatency records how long a database operation took and how often it
+// was retried. Register it with db.SetLatencyObserver.
+func (m *MetricsService) RecordDBLatency(operation string, d time.Duration, retries int) {
+	m.dbQueryDuration.WithLabelValues(operation).Observe(d.Seconds())
+	if retries > 0 {
+		m.dbBusyRetries.WithLabelValues(operation).Add(float64(retries))
+	}
+}
+
 // ResetStuckCount resets the stuck remediation counter (called after health check clears)
 func (m *MetricsService) ResetStuckCount() {
 	m.mu.Lock()
@@ -1,16 +1,20 @@
 package metrics
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
 
 	_ "modernc.org/sqlite" // Register pure-Go SQLite driver for database/sql
 )
@@ -102,6 +106,30 @@ func createTestMetrics(t *testing.T, eb *eventbus.EventBus) (*MetricsService, *p
 			[]string{"outcome"},
 		),
 
+		invalidEventsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_invalid_events_total",
+				Help: "Total number of published events whose payload failed schema validation",
+			},
+			[]string{"event_type"},
+		),
+
+		invalidTransitions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_invalid_transitions_total",
+				Help: "Total number of published corruption events that did not follow from the corruption's state",
+			},
+			[]string{"event_type", "from"},
+		),
+
+		retentionPruned: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_retention_pruned_total",
+				Help: "Total number of rows pruned by database maintenance by retention category",
+			},
+			[]string{"category"},
+		),
+
 		activeRemediations: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "healarr_active_remediations",
@@ -137,6 +165,22 @@ func createTestMetrics(t *testing.T, eb *eventbus.EventBus) (*MetricsService, *p
 			},
 		),
 
+		arrBreakerOpen: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricArrCircuitBreakerOpen,
+				Help: "1 while the circuit breaker of an *arr instance is open or half-open, else 0",
+			},
+			[]string{"instance_id"},
+		),
+
+		lastScanCompleted: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLastScanCompleted,
+				Help: "Unix time a scheduled scan path last finished a scan, or was added if it never did",
+			},
+			[]string{"path_id"},
+		),
+
 		remediationDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "healarr_remediation_duration_seconds",
@@ -153,6 +197,23 @@ func createTestMetrics(t *testing.T, eb *eventbus.EventBus) (*MetricsService, *p
 				Buckets: prometheus.ExponentialBuckets(1, 2, 12),
 			},
 		),
+
+		dbQueryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "healarr_db_query_duration_seconds",
+				Help:    "Duration of database operations in seconds, including retries on SQLITE_BUSY",
+				Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16),
+			},
+			[]string{"operation"},
+		),
+
+		dbBusyRetries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "healarr_db_busy_retries_total",
+				Help: "Total number of database operations retried because the database was locked",
+			},
+			[]string{"operation"},
+		),
 	}
 
 	// Register all metrics with custom registry
@@ -162,13 +223,20 @@ func createTestMetrics(t *testing.T, eb *eventbus.EventBus) (*MetricsService, *p
 		m.verificationsTotal,
 		m.scansTotal,
 		m.notificationsTotal,
+		m.invalidEventsTotal,
+		m.invalidTransitions,
+		m.retentionPruned,
 		m.activeRemediations,
 		m.queuedRemediations,
 		m.stuckRemediations,
 		m.unhealthyInstances,
 		m.currentScanProgress,
+		m.arrBreakerOpen,
+		m.lastScanCompleted,
 		m.remediationDuration,
 		m.scanDuration,
+		m.dbQueryDuration,
+		m.dbBusyRetries,
 	)
 
 	return m, reg
@@ -250,6 +318,47 @@ func TestMetricsService_Handler_ReturnsMetrics(t *testing.T) {
 	}
 }
 
+// fakeBreakers reports fixed circuit breaker states.
+type fakeBreakers map[int64]integration.CircuitBreakerStats
+
+func (f fakeBreakers) GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats {
+	return f
+}
+
+func TestMetricsService_Handler_RefreshesSourcedGauges(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+
+	m.SetCircuitBreakerSource(fakeBreakers{
+		1: {State: integration.CircuitOpen},
+		2: {State: integration.CircuitClosed},
+		3: {State: integration.CircuitHalfOpen},
+	})
+	lastScan := time.Unix(1700000000, 0)
+	m.SetLastScansSource(func(ctx context.Context) (map[int64]time.Time, error) {
+		return map[int64]time.Time{7: lastScan}, nil
+	})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	for id, want := range map[string]float64{"1": 1, "2": 0, "3": 1} {
+		if got := testutil.ToFloat64(m.arrBreakerOpen.WithLabelValues(id)); got != want {
+			t.Errorf("breaker %s open = %v, want %v", id, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(m.lastScanCompleted.WithLabelValues("7")); got != 1700000000 {
+		t.Errorf("last scan = %v, want 1700000000", got)
+	}
+
+	// A breaker that's gone drops its series
+	m.SetCircuitBreakerSource(fakeBreakers{})
+	m.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics", nil))
+	if n := testutil.CollectAndCount(m.arrBreakerOpen); n != 0 {
+		t.Errorf("breaker series = %d, want 0", n)
+	}
+}
+
 // =============================================================================
 // Event handler tests
 // =============================================================================
@@ -342,6 +451,38 @@ func TestHandleVerificationSuccess(t *testing.T) {
 	}
 }
 
+func TestHandleVerificationSuccess_Reverification(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+
+	m.activeRemediationCount = 1
+	m.handleVerificationSuccess(domain.Event{EventType: domain.VerificationSuccess, EventData: map[string]interface{}{"reverification_of": int64(42)}})
+
+	if got := testutil.ToFloat64(m.verificationsTotal.WithLabelValues("success")); got != 1 {
+		t.Errorf("verifications_total{success} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.remediationsTotal.WithLabelValues("success")); got != 0 {
+		t.Errorf("remediations_total{success} = %v, want 0 for a repeated verification", got)
+	}
+	if m.activeRemediationCount != 1 {
+		t.Errorf("activeRemediationCount = %d, want 1", m.activeRemediationCount)
+	}
+}
+
+func TestHandleVerificationSuccess_Audit(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+
+	m.handleVerificationSuccess(domain.Event{EventType: domain.VerificationSuccess, EventData: map[string]interface{}{"reverification_of": int64(42), "audit": true}})
+
+	if got := testutil.ToFloat64(m.verificationsTotal.WithLabelValues("success")); got != 0 {
+		t.Errorf("verifications_total{success} = %v, want 0 for a passed audit", got)
+	}
+	if got := testutil.ToFloat64(m.remediationsTotal.WithLabelValues("success")); got != 0 {
+		t.Errorf("remediations_total{success} = %v, want 0 for a passed audit", got)
+	}
+}
+
 func TestHandleVerificationFailed(t *testing.T) {
 	eb := newTestEventBus(t)
 	m, _ := createTestMetrics(t, eb)
@@ -556,6 +697,81 @@ func TestMetricsService_Start(t *testing.T) {
 	// (In a real scenario, we'd use synchronization)
 }
 
+func TestMetricsService_CountsInvalidEvents(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+	m.Start()
+
+	// Lenient mode: the event is still published, but counted
+	err := eb.Publish(domain.Event{
+		AggregateID: "invalid-metrics",
+		EventType:   domain.CorruptionDetected,
+		EventData:   map[string]interface{}{"corruption_type": "test"},
+	})
+	if err != nil {
+		t.Fatalf("Publish should not fail outside strict mode: %v", err)
+	}
+
+	if got := testutil.ToFloat64(m.invalidEventsTotal.WithLabelValues("CorruptionDetected")); got != 1 {
+		t.Errorf("Expected 1 invalid CorruptionDetected event, got %v", got)
+	}
+}
+
+func TestMetricsService_CountsInvalidTransitions(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+	m.Start()
+
+	for _, eventType := range []domain.EventType{domain.SearchExhausted, domain.VerificationStarted} {
+		if err := eb.Publish(domain.Event{
+			AggregateID:   "transition-metrics",
+			AggregateType: "corruption",
+			EventType:     eventType,
+			EventData:     map[string]interface{}{"file_path": "/media/a.mkv"},
+		}); err != nil {
+			t.Fatalf("Publish should not fail outside strict mode: %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(m.invalidTransitions.WithLabelValues("VerificationStarted", "SearchExhausted")); got != 1 {
+		t.Errorf("Expected 1 invalid transition, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.invalidEventsTotal.WithLabelValues("VerificationStarted")); got != 0 {
+		t.Errorf("Expected transitions not to count as invalid payloads, got %v", got)
+	}
+}
+
+func TestMetricsService_RecordPruned(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+
+	m.RecordPruned("resolved", 3)
+	m.RecordPruned("resolved", 2)
+	m.RecordPruned("scan_events", 7)
+
+	if got := testutil.ToFloat64(m.retentionPruned.WithLabelValues("resolved")); got != 5 {
+		t.Errorf("Expected 5 pruned resolved rows, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.retentionPruned.WithLabelValues("scan_events")); got != 7 {
+		t.Errorf("Expected 7 pruned scan events, got %v", got)
+	}
+}
+
+func TestMetricsService_RecordDBLatency(t *testing.T) {
+	eb := newTestEventBus(t)
+	m, _ := createTestMetrics(t, eb)
+
+	m.RecordDBLatency("event_insert", 2*time.Millisecond, 0)
+	m.RecordDBLatency("event_insert", 300*time.Millisecond, 2)
+
+	if got := testutil.CollectAndCount(m.dbQueryDuration); got != 1 {
+		t.Errorf("Expected one latency series, got %d", got)
+	}
+	if got := testutil.ToFloat64(m.dbBusyRetries.WithLabelValues("event_insert")); got != 2 {
+		t.Errorf("Expected 2 busy retries, got %v", got)
+	}
+}
+
 // =============================================================================
 // Full lifecycle tests
 // =============================================================================
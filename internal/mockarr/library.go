@@ -0,0 +1,220 @@
+package mockarr
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Instance types the mock server can emulate.
+const (
+	TypeRadarr = "radarr"
+	TypeSonarr = "sonarr"
+)
+
+// videoExtensions are the files picked up as library media.
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".m4v": true,
+	".mov": true, ".ts": true, ".wmv": true, ".webm": true,
+}
+
+var (
+	// "Movie Title (2020)"
+	titleYearPattern = regexp.MustCompile(`^(.*?)\s*\((\d{4})\)$`)
+	// "S01E02" anywhere in a file name
+	episodePattern = regexp.MustCompile(`(?i)s(\d{1,2})e(\d{1,3})`)
+	// "Season 1" folder
+	seasonPattern = regexp.MustCompile(`(?i)^season\s*(\d{1,2})$`)
+)
+
+// mediaFile is a movie or episode file known to the mock instance.
+type mediaFile struct {
+	ID      int64
+	MediaID int64 // movie or series ID
+	Path    string
+	Size    int64
+}
+
+type movie struct {
+	ID          int64
+	Title       string
+	Year        int
+	Path        string // movie folder
+	FileID      int64  // 0 when the movie has no file
+	DeletedPath string // path of the last deleted file, restored by a download
+}
+
+type series struct {
+	ID    int64
+	Title string
+	Path  string // series folder
+}
+
+type episode struct {
+	ID          int64
+	SeriesID    int64
+	Season      int
+	Number      int
+	FileID      int64
+	DeletedPath string
+}
+
+// library is the media an instance manages, built from a directory on disk.
+type library struct {
+	movies   map[int64]*movie
+	series   map[int64]*series
+	episodes map[int64]*episode
+	files    map[int64]*mediaFile
+	nextID   int64
+}
+
+func newLibrary() *library {
+	return &library{
+		movies:   make(map[int64]*movie),
+		series:   make(map[int64]*series),
+		episodes: make(map[int64]*episode),
+		files:    make(map[int64]*mediaFile),
+	}
+}
+
+func (l *library) id() int64 {
+	l.nextID++
+	return l.nextID
+}
+
+// loadLibrary walks dir and builds a Radarr library (one movie per folder) or
+// a Sonarr library (one series per top-level folder, one episode per file).
+func loadLibrary(instanceType, dir string) (*library, error) {
+	lib := newLibrary()
+	if dir == "" {
+		return lib, nil
+	}
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && videoExtensions[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library %s: %w", dir, err)
+	}
+	sort.Strings(paths) // stable IDs across runs
+
+	for _, path := range paths {
+		var size int64
+		if info, err := os.Stat(path); err == nil {
+			size = info.Size()
+		}
+		switch instanceType {
+		case TypeRadarr:
+			lib.addMovieFile(path, size)
+		case TypeSonarr:
+			lib.addEpisodeFile(dir, path, size)
+		default:
+			return nil, fmt.Errorf("unsupported instance type: %s", instanceType)
+		}
+	}
+	return lib, nil
+}
+
+func (l *library) addMovieFile(path string, size int64) {
+	folder := filepath.Dir(path)
+	for _, m := range l.movies {
+		if m.Path == folder {
+			return // one file per movie, like Radarr
+		}
+	}
+	title, year := parseTitleYear(filepath.Base(folder))
+	m := &movie{ID: l.id(), Title: title, Year: year, Path: folder}
+	f := &mediaFile{ID: l.id(), MediaID: m.ID, Path: path, Size: size}
+	m.FileID = f.ID
+	l.movies[m.ID] = m
+	l.files[f.ID] = f
+}
+
+func (l *library) addEpisodeFile(root, path string, size int64) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) < 2 {
+		return // files directly in the root don't belong to a series
+	}
+	seriesPath := filepath.Join(root, parts[0])
+
+	var s *series
+	for _, existing := range l.series {
+		if existing.Path == seriesPath {
+			s = existing
+			break
+		}
+	}
+	if s == nil {
+		title, _ := parseTitleYear(parts[0])
+		s = &series{ID: l.id(), Title: title, Path: seriesPath}
+		l.series[s.ID] = s
+	}
+
+	season, number := parseEpisode(parts[1:len(parts)-1], filepath.Base(path))
+	if number == 0 {
+		// No SxxEyy in the name: number episodes in file order
+		for _, ep := range l.episodes {
+			if ep.SeriesID == s.ID && ep.Season == season && ep.Number > number {
+				number = ep.Number
+			}
+		}
+		number++
+	}
+
+	f := &mediaFile{ID: l.id(), MediaID: s.ID, Path: path, Size: size}
+	ep := &episode{ID: l.id(), SeriesID: s.ID, Season: season, Number: number, FileID: f.ID}
+	l.files[f.ID] = f
+	l.episodes[ep.ID] = ep
+}
+
+// parseTitleYear splits "Title (2020)" into its title and year.
+func parseTitleYear(name string) (string, int) {
+	if m := titleYearPattern.FindStringSubmatch(name); m != nil {
+		year, _ := strconv.Atoi(m[2])
+		return m[1], year
+	}
+	return name, 0
+}
+
+// parseEpisode returns the season and episode number of a file, from an SxxEyy
+// tag or a "Season N" folder. The episode number is 0 when unknown.
+func parseEpisode(folders []string, name string) (int, int) {
+	if m := episodePattern.FindStringSubmatch(name); m != nil {
+		season, _ := strconv.Atoi(m[1])
+		number, _ := strconv.Atoi(m[2])
+		return season, number
+	}
+	for _, folder := range folders {
+		if m := seasonPattern.FindStringSubmatch(folder); m != nil {
+			season, _ := strconv.Atoi(m[1])
+			return season, 0
+		}
+	}
+	return 1, 0
+}
+
+// sortedIDs returns the keys of a map in ascending order.
+func sortedIDs[T any](m map[int64]T) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
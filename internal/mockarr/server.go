@@ -0,0 +1,867 @@
+// Package mockarr emulates the parts of the Radarr and Sonarr v3 APIs that
+// Healarr uses, backed by an in-memory library built from a directory. It lets
+// new users try the full remediation pipeline without a real *arr instance,
+// and gives integration tests a deterministic server to talk to.
+package mockarr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mockFileSize is the size reported for simulated downloads.
+const mockFileSize = 1 << 30
+
+// Config controls how the mock instance behaves.
+type Config struct {
+	Type    string // TypeRadarr or TypeSonarr
+	APIKey  string // required in X-Api-Key when set
+	Library string // directory the media library is built from
+
+	Latency   time.Duration // added to every request
+	ErrorRate float64       // fraction of requests (0-1) answered with HTTP 500
+
+	// DownloadTime is how long a searched release sits in the queue before it
+	// is imported. Zero imports it on the next request.
+	DownloadTime time.Duration
+
+	// ReplacementFile, when set, makes the mock touch the disk: deleting a file
+	// removes it, and an import copies ReplacementFile into its place. Without
+	// it the library on disk is never modified.
+	ReplacementFile string
+
+	Seed int64 // seeds the error injection; 0 uses the current time
+}
+
+// download is a release in the simulated download queue.
+type download struct {
+	ID         int64
+	DownloadID string
+	Title      string
+	MediaID    int64   // movie or series ID
+	EpisodeIDs []int64 // Sonarr only
+	Added      time.Time
+}
+
+type historyEntry struct {
+	ID         int64
+	EventType  string
+	Date       time.Time
+	DownloadID string
+	Title      string
+	MediaID    int64
+	EpisodeID  int64
+	Data       map[string]string
+}
+
+// Server is an http.Handler emulating a Radarr or Sonarr instance.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+	now func() time.Time
+
+	mu        sync.Mutex
+	lib       *library
+	queue     []*download
+	history   []*historyEntry
+	nextQueue int64
+	nextEvent int64
+	rng       *rand.Rand
+}
+
+// New builds a mock instance from cfg.
+func New(cfg Config) (*Server, error) {
+	if cfg.Type != TypeRadarr && cfg.Type != TypeSonarr {
+		return nil, fmt.Errorf("unsupported instance type %q (want %s or %s)", cfg.Type, TypeRadarr, TypeSonarr)
+	}
+	if cfg.ErrorRate < 0 || cfg.ErrorRate > 1 {
+		return nil, fmt.Errorf("error rate must be between 0 and 1, got %v", cfg.ErrorRate)
+	}
+	if cfg.ReplacementFile != "" {
+		if _, err := os.Stat(cfg.ReplacementFile); err != nil {
+			return nil, fmt.Errorf("replacement file: %w", err)
+		}
+	}
+	lib, err := loadLibrary(cfg.Type, cfg.Library)
+	if err != nil {
+		return nil, err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s := &Server{
+		cfg: cfg,
+		mux: http.NewServeMux(),
+		now: time.Now,
+		lib: lib,
+		rng: rand.New(rand.NewSource(seed)), // #nosec G404 -- error injection, not security
+	}
+	s.routes()
+	return s, nil
+}
+
+// Counts returns the number of media items and files in the library.
+func (s *Server) Counts() (media, files int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.Type == TypeRadarr {
+		return len(s.lib.movies), len(s.lib.files)
+	}
+	return len(s.lib.series), len(s.lib.files)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v3/system/status", s.handleSystemStatus)
+	s.mux.HandleFunc("GET /api/v3/rootfolder", s.handleRootFolders)
+	s.mux.HandleFunc("GET /api/v3/tag", s.handleTags)
+	s.mux.HandleFunc("GET /api/v3/parse", s.handleParse)
+	s.mux.HandleFunc("POST /api/v3/command", s.handleCommand)
+	s.mux.HandleFunc("GET /api/v3/queue", s.handleQueue)
+	s.mux.HandleFunc("DELETE /api/v3/queue/{id}", s.handleQueueDelete)
+	s.mux.HandleFunc("GET /api/v3/history", s.handleHistory)
+
+	if s.cfg.Type == TypeRadarr {
+		s.mux.HandleFunc("GET /api/v3/movie", s.handleMovies)
+		s.mux.HandleFunc("GET /api/v3/movie/{id}", s.handleMovie)
+		s.mux.HandleFunc("GET /api/v3/moviefile", s.handleMovieFiles)
+		s.mux.HandleFunc("GET /api/v3/moviefile/{id}", s.handleFile)
+		s.mux.HandleFunc("DELETE /api/v3/moviefile/{id}", s.handleFileDelete)
+		s.mux.HandleFunc("GET /api/v3/history/movie", s.handleMediaHistory("movieId"))
+		return
+	}
+	s.mux.HandleFunc("GET /api/v3/series", s.handleSeriesList)
+	s.mux.HandleFunc("GET /api/v3/series/{id}", s.handleSeries)
+	s.mux.HandleFunc("GET /api/v3/episode", s.handleEpisodes)
+	s.mux.HandleFunc("GET /api/v3/episode/{id}", s.handleEpisode)
+	s.mux.HandleFunc("GET /api/v3/episodefile", s.handleEpisodeFiles)
+	s.mux.HandleFunc("GET /api/v3/episodefile/{id}", s.handleFile)
+	s.mux.HandleFunc("DELETE /api/v3/episodefile/{id}", s.handleFileDelete)
+	s.mux.HandleFunc("GET /api/v3/history/series", s.handleMediaHistory("seriesId"))
+}
+
+// ServeHTTP checks the API key, applies the configured latency and error
+// rate, and moves finished downloads out of the queue before routing.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.APIKey != "" {
+		key := r.Header.Get("X-Api-Key")
+		if key == "" {
+			key = r.URL.Query().Get("apikey")
+		}
+		if key != s.cfg.APIKey {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+			return
+		}
+	}
+
+	if s.cfg.Latency > 0 {
+		time.Sleep(s.cfg.Latency)
+	}
+
+	s.mu.Lock()
+	fail := s.cfg.ErrorRate > 0 && s.rng.Float64() < s.cfg.ErrorRate
+	if !fail {
+		s.advanceQueue()
+	}
+	s.mu.Unlock()
+
+	if fail {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "Injected mock failure"})
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// =============================================================================
+// System
+// =============================================================================
+
+func (s *Server) handleSystemStatus(w http.ResponseWriter, r *http.Request) {
+	appName := "Radarr"
+	if s.cfg.Type == TypeSonarr {
+		appName = "Sonarr"
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"appName":      appName,
+		"instanceName": appName + " (mock)",
+		"version":      "3.0.0.0-mock",
+	})
+}
+
+func (s *Server) handleRootFolders(w http.ResponseWriter, r *http.Request) {
+	folders := []map[string]interface{}{}
+	if s.cfg.Library != "" {
+		folders = append(folders, map[string]interface{}{
+			"id":         1,
+			"path":       s.cfg.Library,
+			"accessible": true,
+			"freeSpace":  int64(500) << 30,
+			"totalSpace": int64(1) << 40,
+		})
+	}
+	writeJSON(w, http.StatusOK, folders)
+}
+
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []interface{}{})
+}
+
+// =============================================================================
+// Library
+// =============================================================================
+
+// handleParse resolves a file path to the movie or series containing it.
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := map[string]interface{}{"title": filepath.Base(path)}
+	if s.cfg.Type == TypeRadarr {
+		for _, id := range sortedIDs(s.lib.movies) {
+			if m := s.lib.movies[id]; isInside(m.Path, path) {
+				result["movie"] = s.movieJSON(m)
+				break
+			}
+		}
+	} else {
+		for _, id := range sortedIDs(s.lib.series) {
+			if sr := s.lib.series[id]; isInside(sr.Path, path) {
+				result["series"] = seriesJSON(sr)
+				break
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleMovies(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	movies := []map[string]interface{}{}
+	for _, id := range sortedIDs(s.lib.movies) {
+		movies = append(movies, s.movieJSON(s.lib.movies[id]))
+	}
+	writeJSON(w, http.StatusOK, movies)
+}
+
+func (s *Server) handleMovie(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.lib.movies[pathID(r)]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.movieJSON(m))
+}
+
+func (s *Server) handleMovieFiles(w http.ResponseWriter, r *http.Request) {
+	s.writeFilesFor(w, queryID(r, "movieId"))
+}
+
+func (s *Server) handleSeriesList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []map[string]interface{}{}
+	for _, id := range sortedIDs(s.lib.series) {
+		list = append(list, seriesJSON(s.lib.series[id]))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleSeries(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sr, ok := s.lib.series[pathID(r)]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, seriesJSON(sr))
+}
+
+func (s *Server) handleEpisodes(w http.ResponseWriter, r *http.Request) {
+	seriesID := queryID(r, "seriesId")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	episodes := []map[string]interface{}{}
+	for _, id := range sortedIDs(s.lib.episodes) {
+		if ep := s.lib.episodes[id]; ep.SeriesID == seriesID {
+			episodes = append(episodes, s.episodeJSON(ep))
+		}
+	}
+	writeJSON(w, http.StatusOK, episodes)
+}
+
+func (s *Server) handleEpisode(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ep, ok := s.lib.episodes[pathID(r)]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.episodeJSON(ep))
+}
+
+func (s *Server) handleEpisodeFiles(w http.ResponseWriter, r *http.Request) {
+	s.writeFilesFor(w, queryID(r, "seriesId"))
+}
+
+func (s *Server) writeFilesFor(w http.ResponseWriter, mediaID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := []map[string]interface{}{}
+	for _, id := range sortedIDs(s.lib.files) {
+		if f := s.lib.files[id]; f.MediaID == mediaID {
+			files = append(files, fileJSON(f))
+		}
+	}
+	writeJSON(w, http.StatusOK, files)
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.lib.files[pathID(r)]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, fileJSON(f))
+}
+
+// handleFileDelete forgets a movie or episode file, remembering its path so a
+// later download can put the replacement back in the same place.
+func (s *Server) handleFileDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.lib.files[pathID(r)]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	if s.cfg.ReplacementFile != "" {
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"message": err.Error()})
+			return
+		}
+	}
+	delete(s.lib.files, f.ID)
+
+	data := map[string]string{"reason": "Manual"}
+	if s.cfg.Type == TypeRadarr {
+		if m, ok := s.lib.movies[f.MediaID]; ok {
+			m.FileID = 0
+			m.DeletedPath = f.Path
+		}
+		s.addHistory(&historyEntry{EventType: "movieFileDeleted", Title: filepath.Base(f.Path), MediaID: f.MediaID, Data: data})
+	} else {
+		for _, id := range sortedIDs(s.lib.episodes) {
+			if ep := s.lib.episodes[id]; ep.FileID == f.ID {
+				ep.FileID = 0
+				ep.DeletedPath = f.Path
+				s.addHistory(&historyEntry{EventType: "episodeFileDeleted", Title: filepath.Base(f.Path), MediaID: f.MediaID, EpisodeID: ep.ID, Data: data})
+			}
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// =============================================================================
+// Commands
+// =============================================================================
+
+type commandRequest struct {
+	Name       string  `json:"name"`
+	MovieIDs   []int64 `json:"movieIds"`
+	MovieID    int64   `json:"movieId"`
+	SeriesID   int64   `json:"seriesId"`
+	EpisodeIDs []int64 `json:"episodeIds"`
+}
+
+// handleCommand accepts the search, rescan and refresh commands Healarr sends.
+// Searches queue a simulated download for every item that is missing a file.
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	var cmd commandRequest
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "invalid command body"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch cmd.Name {
+	case "MoviesSearch":
+		for _, id := range cmd.MovieIDs {
+			if m, ok := s.lib.movies[id]; ok && m.FileID == 0 {
+				s.grab(fmt.Sprintf("%s.%d.1080p.WEB-DL-MOCK", dotted(m.Title), m.Year), m.ID, nil)
+			}
+		}
+	case "EpisodeSearch":
+		s.searchEpisodes(cmd.EpisodeIDs)
+	case "MissingEpisodeSearch":
+		var ids []int64
+		for _, id := range sortedIDs(s.lib.episodes) {
+			if s.lib.episodes[id].SeriesID == cmd.SeriesID {
+				ids = append(ids, id)
+			}
+		}
+		s.searchEpisodes(ids)
+	case "RescanMovie", "RescanSeries", "RefreshMonitoredDownloads":
+		// Nothing to do: the library only changes through this API
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "unknown command: " + cmd.Name})
+		return
+	}
+
+	s.nextEvent++
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":     s.nextEvent,
+		"name":   cmd.Name,
+		"status": "queued",
+	})
+}
+
+// searchEpisodes queues one download per series for the given episodes that
+// are missing a file.
+func (s *Server) searchEpisodes(episodeIDs []int64) {
+	bySeries := make(map[int64][]int64)
+	for _, id := range episodeIDs {
+		if ep, ok := s.lib.episodes[id]; ok && ep.FileID == 0 {
+			bySeries[ep.SeriesID] = append(bySeries[ep.SeriesID], ep.ID)
+		}
+	}
+	for _, seriesID := range sortedIDs(bySeries) {
+		ids := bySeries[seriesID]
+		first := s.lib.episodes[ids[0]]
+		title := fmt.Sprintf("%s.S%02dE%02d.1080p.WEB-DL-MOCK", dotted(s.lib.series[seriesID].Title), first.Season, first.Number)
+		s.grab(title, seriesID, ids)
+	}
+}
+
+// grab adds a download to the queue and records it in the history.
+func (s *Server) grab(title string, mediaID int64, episodeIDs []int64) {
+	for _, d := range s.queue {
+		if d.MediaID == mediaID && sameIDs(d.EpisodeIDs, episodeIDs) {
+			return // already downloading
+		}
+	}
+	s.nextQueue++
+	d := &download{
+		ID:         s.nextQueue,
+		DownloadID: fmt.Sprintf("MOCK%012X", s.nextQueue),
+		Title:      title,
+		MediaID:    mediaID,
+		EpisodeIDs: episodeIDs,
+		Added:      s.now(),
+	}
+	s.queue = append(s.queue, d)
+	s.addDownloadHistory("grabbed", d, map[string]string{
+		"indexer":        "Mock Indexer",
+		"downloadClient": "Mock Client",
+		"releaseGroup":   "MOCK",
+	})
+}
+
+// advanceQueue imports every download that has been in the queue for at least
+// DownloadTime. Callers must hold s.mu.
+func (s *Server) advanceQueue() {
+	now := s.now()
+	remaining := s.queue[:0]
+	for _, d := range s.queue {
+		if now.Sub(d.Added) < s.cfg.DownloadTime {
+			remaining = append(remaining, d)
+			continue
+		}
+		s.importDownload(d)
+	}
+	s.queue = remaining
+}
+
+// importDownload puts a new file back where the deleted one was and records a
+// downloadFolderImported event, which Healarr uses to find the replacement.
+func (s *Server) importDownload(d *download) {
+	if s.cfg.Type == TypeRadarr {
+		m, ok := s.lib.movies[d.MediaID]
+		if !ok || m.FileID != 0 {
+			return
+		}
+		path := m.DeletedPath
+		if path == "" {
+			path = filepath.Join(m.Path, d.Title+".mkv")
+		}
+		m.FileID = s.importFile(d, path)
+		m.DeletedPath = ""
+		return
+	}
+
+	// Episodes that shared a file before deletion share the replacement too
+	imported := make(map[string]int64)
+	for _, id := range d.EpisodeIDs {
+		ep, ok := s.lib.episodes[id]
+		if !ok || ep.FileID != 0 {
+			continue
+		}
+		path := ep.DeletedPath
+		if path == "" {
+			sr := s.lib.series[ep.SeriesID]
+			path = filepath.Join(sr.Path, fmt.Sprintf("Season %02d", ep.Season), fmt.Sprintf("%s.S%02dE%02d.mkv", dotted(sr.Title), ep.Season, ep.Number))
+		}
+		fileID, ok := imported[path]
+		if !ok {
+			fileID = s.importFile(d, path)
+			imported[path] = fileID
+		}
+		ep.FileID = fileID
+		ep.DeletedPath = ""
+	}
+}
+
+// importFile adds a file record for path, copies the replacement into place
+// when configured, and returns the new file ID.
+func (s *Server) importFile(d *download, path string) int64 {
+	size := int64(mockFileSize)
+	if s.cfg.ReplacementFile != "" {
+		if n, err := copyFile(s.cfg.ReplacementFile, path); err == nil {
+			size = n
+		}
+	}
+	f := &mediaFile{ID: s.lib.id(), MediaID: d.MediaID, Path: path, Size: size}
+	s.lib.files[f.ID] = f
+	s.addDownloadHistory("downloadFolderImported", d, map[string]string{
+		"importedPath":   path,
+		"droppedPath":    filepath.Join("/downloads/complete", d.Title, filepath.Base(path)),
+		"quality":        "WEBDL-1080p",
+		"releaseGroup":   "MOCK",
+		"downloadClient": "Mock Client",
+	})
+	return f.ID
+}
+
+// =============================================================================
+// Queue
+// =============================================================================
+
+func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	records := make([]map[string]interface{}, 0, len(s.queue))
+	for _, d := range s.queue {
+		records = append(records, s.queueJSON(d, now))
+	}
+	page, pageSize := pagination(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"page":         page,
+		"pageSize":     pageSize,
+		"totalRecords": len(records),
+		"records":      paginate(records, page, pageSize),
+	})
+}
+
+func (s *Server) handleQueueDelete(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.queue {
+		if d.ID != id {
+			continue
+		}
+		s.queue = append(s.queue[:i], s.queue[i+1:]...)
+		s.addDownloadHistory("downloadFailed", d, map[string]string{"message": "Removed from queue"})
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeNotFound(w)
+}
+
+func (s *Server) queueJSON(d *download, now time.Time) map[string]interface{} {
+	left := int64(0)
+	remaining := s.cfg.DownloadTime - now.Sub(d.Added)
+	if remaining > 0 && s.cfg.DownloadTime > 0 {
+		left = int64(float64(mockFileSize) * float64(remaining) / float64(s.cfg.DownloadTime))
+	} else {
+		remaining = 0
+	}
+
+	item := map[string]interface{}{
+		"id":                      d.ID,
+		"downloadId":              d.DownloadID,
+		"title":                   d.Title,
+		"status":                  "downloading",
+		"trackedDownloadState":    "downloading",
+		"trackedDownloadStatus":   "ok",
+		"statusMessages":          []interface{}{},
+		"protocol":                "usenet",
+		"downloadClient":          "Mock Client",
+		"indexer":                 "Mock Indexer",
+		"outputPath":              filepath.Join("/downloads/incomplete", d.Title),
+		"size":                    mockFileSize,
+		"sizeleft":                left,
+		"timeleft":                formatTimeLeft(remaining),
+		"estimatedCompletionTime": d.Added.Add(s.cfg.DownloadTime).UTC().Format(time.RFC3339),
+		"added":                   d.Added.UTC().Format(time.RFC3339),
+	}
+	if s.cfg.Type == TypeRadarr {
+		item["movieId"] = d.MediaID
+	} else {
+		item["seriesId"] = d.MediaID
+		if len(d.EpisodeIDs) > 0 {
+			item["episodeId"] = d.EpisodeIDs[0]
+		}
+	}
+	return item
+}
+
+// =============================================================================
+// History
+// =============================================================================
+
+func (s *Server) addHistory(e *historyEntry) {
+	s.nextEvent++
+	e.ID = s.nextEvent
+	e.Date = s.now()
+	s.history = append(s.history, e)
+}
+
+// addDownloadHistory records a download event, once per episode for Sonarr.
+func (s *Server) addDownloadHistory(eventType string, d *download, data map[string]string) {
+	if len(d.EpisodeIDs) == 0 {
+		s.addHistory(&historyEntry{EventType: eventType, DownloadID: d.DownloadID, Title: d.Title, MediaID: d.MediaID, Data: data})
+		return
+	}
+	for _, id := range d.EpisodeIDs {
+		s.addHistory(&historyEntry{EventType: eventType, DownloadID: d.DownloadID, Title: d.Title, MediaID: d.MediaID, EpisodeID: id, Data: data})
+	}
+}
+
+// recentHistory returns history entries newest first, filtered by event type
+// and media ID when those are non-empty.
+func (s *Server) recentHistory(eventType string, mediaID int64) []map[string]interface{} {
+	records := []map[string]interface{}{}
+	for i := len(s.history) - 1; i >= 0; i-- {
+		e := s.history[i]
+		if eventType != "" && e.EventType != eventType {
+			continue
+		}
+		if mediaID != 0 && e.MediaID != mediaID {
+			continue
+		}
+		records = append(records, s.historyJSON(e))
+	}
+	return records
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := s.recentHistory(r.URL.Query().Get("eventType"), 0)
+	page, pageSize := pagination(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"page":         page,
+		"pageSize":     pageSize,
+		"totalRecords": len(records),
+		"records":      paginate(records, page, pageSize),
+	})
+}
+
+// handleMediaHistory serves /history/movie and /history/series, which return
+// a plain array rather than a page.
+func (s *Server) handleMediaHistory(idParam string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mediaID := queryID(r, idParam)
+		if mediaID == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": idParam + " is required"})
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.recentHistory(r.URL.Query().Get("eventType"), mediaID))
+	}
+}
+
+func (s *Server) historyJSON(e *historyEntry) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":          e.ID,
+		"eventType":   e.EventType,
+		"date":        e.Date.UTC().Format(time.RFC3339),
+		"downloadId":  e.DownloadID,
+		"sourceTitle": e.Title,
+		"data":        e.Data,
+	}
+	if s.cfg.Type == TypeRadarr {
+		item["movieId"] = e.MediaID
+	} else {
+		item["seriesId"] = e.MediaID
+		item["episodeId"] = e.EpisodeID
+	}
+	return item
+}
+
+// =============================================================================
+// JSON shapes
+// =============================================================================
+
+func (s *Server) movieJSON(m *movie) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":        m.ID,
+		"title":     m.Title,
+		"year":      m.Year,
+		"path":      m.Path,
+		"monitored": true,
+		"hasFile":   m.FileID != 0,
+	}
+	if f, ok := s.lib.files[m.FileID]; ok {
+		item["movieFile"] = fileJSON(f)
+	}
+	return item
+}
+
+func seriesJSON(sr *series) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        sr.ID,
+		"title":     sr.Title,
+		"path":      sr.Path,
+		"monitored": true,
+	}
+}
+
+func (s *Server) episodeJSON(ep *episode) map[string]interface{} {
+	sr := s.lib.series[ep.SeriesID]
+	return map[string]interface{}{
+		"id":            ep.ID,
+		"seriesId":      ep.SeriesID,
+		"seasonNumber":  ep.Season,
+		"episodeNumber": ep.Number,
+		"title":         fmt.Sprintf("Episode %d", ep.Number),
+		"hasFile":       ep.FileID != 0,
+		"episodeFileId": ep.FileID,
+		"monitored":     true,
+		"series":        map[string]interface{}{"title": sr.Title},
+	}
+}
+
+func fileJSON(f *mediaFile) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           f.ID,
+		"path":         f.Path,
+		"relativePath": filepath.Base(f.Path),
+		"size":         f.Size,
+	}
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "NotFound"})
+}
+
+func pathID(r *http.Request) int64 {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	return id
+}
+
+func queryID(r *http.Request, name string) int64 {
+	id, _ := strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+	return id
+}
+
+func pagination(r *http.Request) (page, pageSize int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ = strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+func paginate(records []map[string]interface{}, page, pageSize int) []map[string]interface{} {
+	start := (page - 1) * pageSize
+	if start >= len(records) {
+		return []map[string]interface{}{}
+	}
+	end := start + pageSize
+	if end > len(records) {
+		end = len(records)
+	}
+	return records[start:end]
+}
+
+// isInside reports whether path is root or below it.
+func isInside(root, path string) bool {
+	root = strings.TrimRight(root, "/")
+	return path == root || strings.HasPrefix(path, root+"/")
+}
+
+// dotted turns a title into a scene-style release name.
+func dotted(title string) string {
+	return strings.Join(strings.Fields(title), ".")
+}
+
+func formatTimeLeft(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]int64(nil), a...)
+	b = append([]int64(nil), b...)
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+	sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src) // #nosec G304 -- path given by the operator
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return 0, err
+	}
+	out, err := os.Create(dst) // #nosec G304 -- path inside the mock library
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
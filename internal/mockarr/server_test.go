@@ -0,0 +1,210 @@
+package mockarr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// writeLibrary creates empty media files below a temporary directory.
+func writeLibrary(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte("original"), 0600))
+	}
+	return dir
+}
+
+// newArrClient points a real HTTPArrClient at the mock through a test database.
+func newArrClient(t *testing.T, instanceType, url, library string) *integration.HTTPArrClient {
+	t.Helper()
+	db, err := testutil.NewTestDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, testutil.SeedArrInstance(db, 1, "Mock", instanceType, url, "secret"))
+	require.NoError(t, testutil.SeedScanPath(db, 1, library, library, true, false))
+	_, err = db.Exec("UPDATE scan_paths SET arr_instance_id = 1 WHERE id = 1")
+	require.NoError(t, err)
+	return integration.NewArrClient(db)
+}
+
+func TestLoadLibrary(t *testing.T) {
+	dir := writeLibrary(t,
+		"Movies/The Matrix (1999)/The Matrix (1999).mkv",
+		"Movies/The Matrix (1999)/sample.mkv",
+		"Movies/Heat (1995)/Heat.mp4",
+		"Movies/Heat (1995)/Heat.nfo",
+	)
+	lib, err := loadLibrary(TypeRadarr, filepath.Join(dir, "Movies"))
+	require.NoError(t, err)
+	require.Len(t, lib.movies, 2)
+	assert.Len(t, lib.files, 2, "one file per movie, extras ignored")
+
+	titles := map[string]int{}
+	for _, m := range lib.movies {
+		titles[m.Title] = m.Year
+	}
+	assert.Equal(t, map[string]int{"The Matrix": 1999, "Heat": 1995}, titles)
+
+	dir = writeLibrary(t,
+		"Show/Season 01/Show.S01E01.mkv",
+		"Show/Season 01/Show.S01E02.mkv",
+		"Show/Season 02/episode-a.mkv",
+		"Show/Season 02/episode-b.mkv",
+		"stray.mkv",
+	)
+	lib, err = loadLibrary(TypeSonarr, dir)
+	require.NoError(t, err)
+	require.Len(t, lib.series, 1)
+	require.Len(t, lib.episodes, 4)
+
+	numbers := map[[2]int]bool{}
+	for _, ep := range lib.episodes {
+		numbers[[2]int{ep.Season, ep.Number}] = true
+	}
+	assert.Equal(t, map[[2]int]bool{{1, 1}: true, {1, 2}: true, {2, 1}: true, {2, 2}: true}, numbers)
+
+	_, err = loadLibrary("lidarr", dir)
+	assert.Error(t, err)
+}
+
+func TestServer_Middleware(t *testing.T) {
+	s, err := New(Config{Type: TypeRadarr, APIKey: "secret", ErrorRate: 1, Seed: 1})
+	require.NoError(t, err)
+
+	do := func(key string) int {
+		req := httptest.NewRequest("GET", "/api/v3/system/status", nil)
+		if key != "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, do(""))
+	assert.Equal(t, http.StatusUnauthorized, do("wrong"))
+	assert.Equal(t, http.StatusInternalServerError, do("secret"))
+
+	s.cfg.ErrorRate = 0
+	assert.Equal(t, http.StatusOK, do("secret"))
+
+	_, err = New(Config{Type: "lidarr"})
+	assert.Error(t, err)
+	_, err = New(Config{Type: TypeRadarr, ErrorRate: 2})
+	assert.Error(t, err)
+}
+
+func TestServer_RadarrRemediation(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+	library := writeLibrary(t, "Heat (1995)/Heat (1995).mkv")
+	filePath := filepath.Join(library, "Heat (1995)", "Heat (1995).mkv")
+
+	replacement := filepath.Join(t.TempDir(), "replacement.mkv")
+	require.NoError(t, os.WriteFile(replacement, []byte("replacement"), 0600))
+
+	s, err := New(Config{Type: TypeRadarr, APIKey: "secret", Library: library, DownloadTime: time.Minute, ReplacementFile: replacement})
+	require.NoError(t, err)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	client := newArrClient(t, "radarr", srv.URL, library)
+
+	mediaID, err := client.FindMediaByPath(filePath)
+	require.NoError(t, err)
+	assert.NotZero(t, mediaID)
+
+	_, err = client.DeleteFile(mediaID, filePath)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filePath)
+
+	require.NoError(t, client.TriggerSearch(mediaID, filePath, nil))
+
+	// Still downloading: one queue item, halfway through
+	now = now.Add(30 * time.Second)
+	queue, err := client.FindQueueItemsByMediaIDForPath(filePath, mediaID)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.InDelta(t, 50, queue[0].Progress, 1)
+
+	// Finished: imported to the old path with the replacement contents
+	now = now.Add(time.Minute)
+	queue, err = client.FindQueueItemsByMediaIDForPath(filePath, mediaID)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	history, err := client.GetRecentHistoryForMediaByPath(filePath, mediaID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "grabbed", history[0].EventType)
+	assert.Equal(t, "Mock Indexer", history[0].Indexer)
+
+	newPath, err := client.GetFilePath(mediaID, nil, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, filePath, newPath)
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "replacement", string(content))
+
+	require.NoError(t, client.RescanMedia(mediaID, filePath))
+}
+
+func TestServer_SonarrRemediation(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+	library := writeLibrary(t, "Show/Season 01/Show.S01E01.mkv", "Show/Season 01/Show.S01E02.mkv")
+	filePath := filepath.Join(library, "Show", "Season 01", "Show.S01E02.mkv")
+
+	s, err := New(Config{Type: TypeSonarr, APIKey: "secret", Library: library})
+	require.NoError(t, err)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	client := newArrClient(t, "sonarr", srv.URL, library)
+
+	mediaID, err := client.FindMediaByPath(filePath)
+	require.NoError(t, err)
+
+	metadata, err := client.DeleteFile(mediaID, filePath)
+	require.NoError(t, err)
+	episodeIDs, ok := metadata["episode_ids"].([]int64)
+	require.True(t, ok, "metadata: %v", metadata)
+	require.Len(t, episodeIDs, 1)
+	assert.FileExists(t, filePath, "without a replacement file the disk is untouched")
+
+	require.NoError(t, client.TriggerSearch(mediaID, filePath, episodeIDs))
+
+	// No download time: imported on the next request
+	paths, err := client.GetAllFilePaths(mediaID, metadata, filePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filePath}, paths)
+
+	// The import is in the instance-wide history
+	req := httptest.NewRequest("GET", "/api/v3/history?eventType=downloadFolderImported", nil)
+	req.Header.Set("X-Api-Key", "secret")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	var page struct {
+		TotalRecords int                       `json:"totalRecords"`
+		Records      []integration.HistoryItem `json:"records"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Equal(t, 1, page.TotalRecords)
+	assert.Equal(t, episodeIDs[0], page.Records[0].EpisodeID)
+	assert.Equal(t, filePath, page.Records[0].Data["importedPath"])
+}
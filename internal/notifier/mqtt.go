@@ -0,0 +1,442 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// MQTT timing.
+const (
+	mqttConnectTimeout  = 10 * time.Second
+	mqttPublishTimeout  = 10 * time.Second
+	mqttStateInterval   = 1 * time.Minute
+	mqttStatusOnline    = "online"
+	mqttStatusOffline   = "offline"
+	mqttDefaultClientID = "healarr"
+)
+
+// mqttStateEvents are the event types that change the published sensor state.
+var mqttStateEvents = []domain.EventType{
+	domain.CorruptionDetected, domain.RemediationQueued, domain.DeletionCompleted, domain.SearchCompleted,
+	domain.VerificationSuccess, domain.VerificationFailed, domain.MaxRetriesReached, domain.SearchExhausted,
+	domain.CorruptionIgnored, domain.ScanStarted, domain.ScanCompleted, domain.ScanFailed,
+}
+
+// mqttInProgressStates mirrors the "in progress" bucket of the dashboard stats.
+const mqttInProgressStates = `'SearchStarted', 'SearchQueued', 'RemediationQueued', 'DownloadStarted',
+	'DownloadProgress', 'DownloadRejected', 'SearchCompleted', 'DeletionCompleted', 'FileDetected'`
+
+// MQTTConfig configures the MQTT publisher.
+type MQTTConfig struct {
+	Broker             string // e.g. tcp://mosquitto:1883 or ssl://broker:8883
+	Username           string
+	Password           string
+	ClientID           string
+	TopicPrefix        string
+	QoS                byte
+	Events             []string // Domain event types forwarded to <prefix>/events/<type>
+	CAFile             string   // Optional CA bundle for TLS brokers
+	InsecureSkipVerify bool
+	Discovery          bool   // Publish Home Assistant MQTT discovery configs
+	DiscoveryPrefix    string // Home Assistant discovery prefix (default: homeassistant)
+	SoftwareVersion    string // Reported in the Home Assistant device info
+}
+
+// ParseEventList splits a comma-separated list of event types, returning the
+// known types and any unknown names separately.
+func ParseEventList(csv string) (valid, unknown []string) {
+	for _, name := range strings.Split(csv, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if domain.IsValidEventType(domain.EventType(name)) {
+			valid = append(valid, name)
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	return valid, unknown
+}
+
+// mqttClient is the subset of the MQTT client used by the publisher.
+type mqttClient interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+	Disconnect()
+}
+
+// pahoClient adapts a paho client to mqttClient with bounded publish waits.
+type pahoClient struct {
+	client mqtt.Client
+}
+
+func (p *pahoClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	token := p.client.Publish(topic, qos, retained, payload)
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		return fmt.Errorf("publish to %s timed out", topic)
+	}
+	return token.Error()
+}
+
+func (p *pahoClient) Disconnect() {
+	p.client.Disconnect(250)
+}
+
+// MQTTState is the retained sensor state published to <prefix>/state.
+type MQTTState struct {
+	CorruptionsPending    int    `json:"corruptions_pending"`
+	CorruptionsInProgress int    `json:"corruptions_in_progress"`
+	LastScanStatus        string `json:"last_scan_status"`
+	LastScanPath          string `json:"last_scan_path,omitempty"`
+	LastScanFiles         int    `json:"last_scan_files"`
+	LastScanCorruptions   int    `json:"last_scan_corruptions"`
+	LastScanAt            string `json:"last_scan_at,omitempty"`
+}
+
+// MQTTPublisher publishes selected domain events and a library health state
+// to an MQTT broker, and announces Home Assistant sensors via MQTT discovery.
+type MQTTPublisher struct {
+	db       *sql.DB
+	eb       *eventbus.EventBus
+	cfg      MQTTConfig
+	client   mqttClient
+	events   map[string]bool
+	stopChan chan struct{}
+	syncChan chan struct{} // (Re)connected: publish availability, discovery and state
+	refresh  chan struct{} // Sensor state may have changed
+	wg       sync.WaitGroup
+}
+
+// NewMQTTPublisher creates a publisher. Call Start to connect.
+func NewMQTTPublisher(db *sql.DB, eb *eventbus.EventBus, cfg MQTTConfig) *MQTTPublisher {
+	if cfg.ClientID == "" {
+		cfg.ClientID = mqttDefaultClientID
+	}
+	cfg.TopicPrefix = strings.Trim(cfg.TopicPrefix, "/")
+	if cfg.TopicPrefix == "" {
+		cfg.TopicPrefix = "healarr"
+	}
+	if cfg.DiscoveryPrefix == "" {
+		cfg.DiscoveryPrefix = "homeassistant"
+	}
+	if cfg.QoS > 2 {
+		cfg.QoS = 2
+	}
+
+	events := make(map[string]bool, len(cfg.Events))
+	for _, e := range cfg.Events {
+		events[e] = true
+	}
+
+	return &MQTTPublisher{
+		db:       db,
+		eb:       eb,
+		cfg:      cfg,
+		events:   events,
+		stopChan: make(chan struct{}),
+		syncChan: make(chan struct{}, 1),
+		refresh:  make(chan struct{}, 1),
+	}
+}
+
+// tlsConfig builds the TLS settings for ssl:// and wss:// brokers.
+func (p *MQTTPublisher) tlsConfig() (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: p.cfg.InsecureSkipVerify, // #nosec G402 -- opt-in for self-signed brokers
+	}
+	if p.cfg.CAFile != "" {
+		pem, err := os.ReadFile(p.cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in MQTT CA file")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return tlsCfg, nil
+}
+
+// Start connects to the broker and begins publishing. Connection failures are
+// retried in the background; Start only fails on invalid configuration.
+func (p *MQTTPublisher) Start() error {
+	if p.client == nil {
+		tlsCfg, err := p.tlsConfig()
+		if err != nil {
+			return err
+		}
+
+		opts := mqtt.NewClientOptions().
+			AddBroker(p.cfg.Broker).
+			SetClientID(p.cfg.ClientID).
+			SetUsername(p.cfg.Username).
+			SetPassword(p.cfg.Password).
+			SetTLSConfig(tlsCfg).
+			SetAutoReconnect(true).
+			SetConnectRetry(true).
+			SetConnectTimeout(mqttConnectTimeout).
+			SetWill(p.statusTopic(), mqttStatusOffline, p.cfg.QoS, true).
+			SetOnConnectHandler(func(mqtt.Client) {
+				logger.Infof("MQTT connected to %s", p.cfg.Broker)
+				p.signal(p.syncChan)
+			}).
+			SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+				logger.Warnf("MQTT connection lost: %v (reconnecting)", err)
+			})
+
+		client := mqtt.NewClient(opts)
+		if token := client.Connect(); !token.WaitTimeout(mqttConnectTimeout) {
+			logger.Warnf("MQTT broker %s not reachable yet, retrying in the background", p.cfg.Broker)
+		} else if err := token.Error(); err != nil {
+			logger.Warnf("MQTT connect to %s failed: %v (retrying in the background)", p.cfg.Broker, err)
+		}
+		p.client = &pahoClient{client: client}
+	}
+
+	subscribed := make(map[domain.EventType]bool)
+	for e := range p.events {
+		subscribed[domain.EventType(e)] = true
+	}
+	for _, e := range mqttStateEvents {
+		subscribed[e] = true
+	}
+	for eventType := range subscribed {
+		p.eb.Subscribe(eventType, p.handleEvent)
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.worker()
+	}()
+	return nil
+}
+
+// Stop marks Healarr offline and disconnects from the broker.
+func (p *MQTTPublisher) Stop() {
+	close(p.stopChan)
+	p.wg.Wait()
+	if err := p.client.Publish(p.statusTopic(), p.cfg.QoS, true, []byte(mqttStatusOffline)); err != nil {
+		logger.Debugf("Failed to publish MQTT offline status: %v", err)
+	}
+	p.client.Disconnect()
+}
+
+func (p *MQTTPublisher) signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (p *MQTTPublisher) statusTopic() string { return p.cfg.TopicPrefix + "/status" }
+func (p *MQTTPublisher) stateTopic() string  { return p.cfg.TopicPrefix + "/state" }
+
+func (p *MQTTPublisher) eventTopic(eventType string) string {
+	return p.cfg.TopicPrefix + "/events/" + eventType
+}
+
+func (p *MQTTPublisher) worker() {
+	ticker := time.NewTicker(mqttStateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-p.syncChan:
+			p.publishSync()
+		case <-p.refresh:
+			p.publishState()
+		case <-ticker.C:
+			p.publishState()
+		}
+	}
+}
+
+// publishSync announces availability, the discovery configs and current state.
+func (p *MQTTPublisher) publishSync() {
+	if err := p.client.Publish(p.statusTopic(), p.cfg.QoS, true, []byte(mqttStatusOnline)); err != nil {
+		logger.Warnf("Failed to publish MQTT status: %v", err)
+	}
+	if p.cfg.Discovery {
+		p.publishDiscovery()
+	}
+	p.publishState()
+}
+
+// handleEvent forwards a subscribed event and schedules a state refresh.
+func (p *MQTTPublisher) handleEvent(ev domain.Event) {
+	select {
+	case <-p.stopChan:
+		return // Stopped, e.g. replaced after a config reload
+	default:
+	}
+
+	eventType := string(ev.EventType)
+	if p.events[eventType] {
+		data := ev.EventData
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		occurredAt := ev.CreatedAt
+		if occurredAt.IsZero() {
+			occurredAt = time.Now().UTC()
+		}
+		payload, err := json.Marshal(WebhookPayload{
+			EventID:       ev.ID,
+			EventType:     eventType,
+			AggregateType: ev.AggregateType,
+			AggregateID:   ev.AggregateID,
+			Data:          data,
+			OccurredAt:    occurredAt,
+		})
+		if err != nil {
+			logger.Errorf("Failed to marshal MQTT payload for %s: %v", eventType, err)
+		} else if err := p.client.Publish(p.eventTopic(eventType), p.cfg.QoS, false, payload); err != nil {
+			logger.Debugf("Failed to publish %s to MQTT: %v", eventType, err)
+		}
+	}
+
+	for _, e := range mqttStateEvents {
+		if e == ev.EventType {
+			p.signal(p.refresh)
+			break
+		}
+	}
+}
+
+// loadState reads the current sensor values from the database.
+func (p *MQTTPublisher) loadState() (*MQTTState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	state := &MQTTState{LastScanStatus: "none"}
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN current_state = 'CorruptionDetected' THEN 1 END),
+			COUNT(CASE WHEN current_state IN (`+mqttInProgressStates+`) THEN 1 END)
+		FROM corruption_summary
+	`).Scan(&state.CorruptionsPending, &state.CorruptionsInProgress); err != nil {
+		return nil, fmt.Errorf("failed to count corruptions: %w", err)
+	}
+
+	var path, startedAt string
+	var completedAt sql.NullString
+	err := p.db.QueryRowContext(ctx, `
+		SELECT status, path, files_scanned, corruptions_found, started_at, completed_at
+		FROM scans ORDER BY id DESC LIMIT 1
+	`).Scan(&state.LastScanStatus, &path, &state.LastScanFiles, &state.LastScanCorruptions, &startedAt, &completedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("failed to load last scan: %w", err)
+	default:
+		state.LastScanPath = path
+		state.LastScanAt = startedAt
+		if completedAt.Valid {
+			state.LastScanAt = completedAt.String
+		}
+	}
+	return state, nil
+}
+
+func (p *MQTTPublisher) publishState() {
+	state, err := p.loadState()
+	if err != nil {
+		logger.Errorf("Failed to load MQTT state: %v", err)
+		return
+	}
+	payload, err := json.Marshal(state)
+	if err != nil {
+		logger.Errorf("Failed to marshal MQTT state: %v", err)
+		return
+	}
+	if err := p.client.Publish(p.stateTopic(), p.cfg.QoS, true, payload); err != nil {
+		logger.Debugf("Failed to publish MQTT state: %v", err)
+	}
+}
+
+// haSensor describes a Home Assistant sensor backed by the state topic.
+type haSensor struct {
+	object string
+	name   string
+	field  string
+	icon   string
+	unit   string
+}
+
+var haSensors = []haSensor{
+	{"corruptions_pending", "Corruptions pending", "corruptions_pending", "mdi:file-alert", "files"},
+	{"corruptions_in_progress", "Corruptions in progress", "corruptions_in_progress", "mdi:progress-wrench", "files"},
+	{"last_scan_status", "Last scan status", "last_scan_status", "mdi:magnify-scan", ""},
+	{"last_scan_corruptions", "Last scan corruptions", "last_scan_corruptions", "mdi:alert-circle", "files"},
+}
+
+// invalidNodeChars matches characters Home Assistant does not allow in node/object IDs.
+var invalidNodeChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// discoveryConfigs returns the Home Assistant discovery topic and payload for each sensor.
+func (p *MQTTPublisher) discoveryConfigs() map[string][]byte {
+	nodeID := invalidNodeChars.ReplaceAllString(p.cfg.ClientID, "_")
+	device := map[string]interface{}{
+		"identifiers":  []string{nodeID},
+		"name":         "Healarr",
+		"manufacturer": "Healarr",
+		"model":        "Media library health",
+		"sw_version":   p.cfg.SoftwareVersion,
+	}
+
+	configs := make(map[string][]byte, len(haSensors))
+	for _, s := range haSensors {
+		cfg := map[string]interface{}{
+			"name":               s.name,
+			"unique_id":          nodeID + "_" + s.object,
+			"state_topic":        p.stateTopic(),
+			"value_template":     "{{ value_json." + s.field + " }}",
+			"availability_topic": p.statusTopic(),
+			"icon":               s.icon,
+			"device":             device,
+		}
+		if s.unit != "" {
+			cfg["unit_of_measurement"] = s.unit
+			cfg["state_class"] = "measurement"
+		}
+		if s.object == "last_scan_status" {
+			cfg["json_attributes_topic"] = p.stateTopic()
+		}
+		payload, err := json.Marshal(cfg)
+		if err != nil {
+			continue
+		}
+		topic := fmt.Sprintf("%s/sensor/%s/%s/config", p.cfg.DiscoveryPrefix, nodeID, s.object)
+		configs[topic] = payload
+	}
+	return configs
+}
+
+func (p *MQTTPublisher) publishDiscovery() {
+	for topic, payload := range p.discoveryConfigs() {
+		if err := p.client.Publish(topic, p.cfg.QoS, true, payload); err != nil {
+			logger.Warnf("Failed to publish MQTT discovery config %s: %v", topic, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package notifier
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+)
+
+type mqttMessage struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+// fakeMQTTClient records published messages.
+type fakeMQTTClient struct {
+	mu           sync.Mutex
+	messages     []mqttMessage
+	disconnected bool
+}
+
+func (f *fakeMQTTClient) Publish(topic string, _ byte, retained bool, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, mqttMessage{topic: topic, retained: retained, payload: payload})
+	return nil
+}
+
+func (f *fakeMQTTClient) Disconnect() {
+	f.mu.Lock()
+	f.disconnected = true
+	f.mu.Unlock()
+}
+
+func (f *fakeMQTTClient) find(topic string) (mqttMessage, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.messages) - 1; i >= 0; i-- {
+		if f.messages[i].topic == topic {
+			return f.messages[i], true
+		}
+	}
+	return mqttMessage{}, false
+}
+
+// newMQTTTestDB returns a notifier test DB with the tables read for sensor state.
+func newMQTTTestDB(t *testing.T) *testDB {
+	t.Helper()
+
+	tdb := newTestDB(t)
+	schema := `
+		CREATE TABLE corruption_summary (
+			corruption_id TEXT PRIMARY KEY,
+			current_state TEXT NOT NULL
+		);
+		CREATE TABLE scans (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			path TEXT NOT NULL,
+			status TEXT NOT NULL,
+			files_scanned INTEGER DEFAULT 0,
+			corruptions_found INTEGER DEFAULT 0,
+			started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			completed_at TIMESTAMP
+		);
+	`
+	if _, err := tdb.DB.Exec(schema); err != nil {
+		t.Fatalf("Failed to create MQTT schema: %v", err)
+	}
+	return tdb
+}
+
+func TestParseEventList(t *testing.T) {
+	valid, unknown := ParseEventList(" CorruptionDetected, ,ScanCompleted,Bogus")
+	if len(valid) != 2 || valid[0] != "CorruptionDetected" || valid[1] != "ScanCompleted" {
+		t.Errorf("valid = %v", valid)
+	}
+	if len(unknown) != 1 || unknown[0] != "Bogus" {
+		t.Errorf("unknown = %v", unknown)
+	}
+}
+
+func TestMQTTPublisher_Defaults(t *testing.T) {
+	p := NewMQTTPublisher(nil, nil, MQTTConfig{TopicPrefix: "/home/healarr/", QoS: 7})
+	if p.cfg.ClientID != "healarr" || p.cfg.DiscoveryPrefix != "homeassistant" || p.cfg.QoS != 2 {
+		t.Errorf("Unexpected defaults: %+v", p.cfg)
+	}
+	if p.stateTopic() != "home/healarr/state" || p.eventTopic("ScanCompleted") != "home/healarr/events/ScanCompleted" {
+		t.Errorf("Unexpected topics: %s, %s", p.stateTopic(), p.eventTopic("ScanCompleted"))
+	}
+}
+
+func TestMQTTPublisher_SyncPublishesDiscoveryAndState(t *testing.T) {
+	tdb := newMQTTTestDB(t)
+	defer tdb.Close()
+
+	if _, err := tdb.DB.Exec(`
+		INSERT INTO corruption_summary VALUES ('a', 'CorruptionDetected'), ('b', 'CorruptionDetected'),
+			('c', 'SearchCompleted'), ('d', 'VerificationSuccess');
+		INSERT INTO scans (path, status, files_scanned, corruptions_found, completed_at)
+			VALUES ('/media/tv', 'completed', 120, 2, '2026-01-02 03:04:05');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeMQTTClient{}
+	p := NewMQTTPublisher(tdb.DB, nil, MQTTConfig{ClientID: "healarr.main", Discovery: true, SoftwareVersion: "1.2.3"})
+	p.client = fake
+
+	p.publishSync()
+
+	status, ok := fake.find("healarr/status")
+	if !ok || string(status.payload) != "online" || !status.retained {
+		t.Errorf("Expected retained online status, got %+v", status)
+	}
+
+	stateMsg, ok := fake.find("healarr/state")
+	if !ok || !stateMsg.retained {
+		t.Fatal("Expected retained state message")
+	}
+	var state MQTTState
+	if err := json.Unmarshal(stateMsg.payload, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.CorruptionsPending != 2 || state.CorruptionsInProgress != 1 {
+		t.Errorf("Unexpected corruption counts: %+v", state)
+	}
+	if state.LastScanStatus != "completed" || state.LastScanFiles != 120 || !strings.HasPrefix(state.LastScanAt, "2026-01-02") {
+		t.Errorf("Unexpected last scan: %+v", state)
+	}
+
+	// Node ID is sanitised for Home Assistant
+	discovery, ok := fake.find("homeassistant/sensor/healarr_main/corruptions_pending/config")
+	if !ok || !discovery.retained {
+		t.Fatal("Expected retained discovery config for corruptions_pending")
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(discovery.payload, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["state_topic"] != "healarr/state" || cfg["availability_topic"] != "healarr/status" {
+		t.Errorf("Unexpected discovery topics: %v", cfg)
+	}
+	if cfg["value_template"] != "{{ value_json.corruptions_pending }}" || cfg["unique_id"] != "healarr_main_corruptions_pending" {
+		t.Errorf("Unexpected discovery config: %v", cfg)
+	}
+	if _, ok := fake.find("homeassistant/sensor/healarr_main/last_scan_status/config"); !ok {
+		t.Error("Expected discovery config for last_scan_status")
+	}
+}
+
+func TestMQTTPublisher_NoDiscoveryWhenDisabled(t *testing.T) {
+	tdb := newMQTTTestDB(t)
+	defer tdb.Close()
+
+	fake := &fakeMQTTClient{}
+	p := NewMQTTPublisher(tdb.DB, nil, MQTTConfig{Discovery: false})
+	p.client = fake
+	p.publishSync()
+
+	for _, m := range fake.messages {
+		if strings.HasPrefix(m.topic, "homeassistant/") {
+			t.Errorf("Unexpected discovery message on %s", m.topic)
+		}
+	}
+	stateMsg, _ := fake.find("healarr/state")
+	var state MQTTState
+	if err := json.Unmarshal(stateMsg.payload, &state); err != nil || state.LastScanStatus != "none" {
+		t.Errorf("Expected last_scan_status none with no scans, got %+v (%v)", state, err)
+	}
+}
+
+func TestMQTTPublisher_HandleEvent(t *testing.T) {
+	tdb := newMQTTTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+
+	fake := &fakeMQTTClient{}
+	p := NewMQTTPublisher(tdb.DB, eb, MQTTConfig{TopicPrefix: "hl", Events: []string{"CorruptionDetected"}})
+	p.client = fake
+
+	p.handleEvent(domain.Event{
+		ID: 7, EventType: domain.CorruptionDetected, AggregateID: "agg",
+		EventData: map[string]interface{}{"file_path": "/media/a.mkv"},
+	})
+	msg, ok := fake.find("hl/events/CorruptionDetected")
+	if !ok || msg.retained {
+		t.Fatalf("Expected non-retained event message, got %+v", msg)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(msg.payload, &payload); err != nil || payload.EventID != 7 || payload.Data["file_path"] != "/media/a.mkv" {
+		t.Errorf("Unexpected payload %s (%v)", msg.payload, err)
+	}
+	select {
+	case <-p.refresh:
+	default:
+		t.Error("Expected a state refresh after CorruptionDetected")
+	}
+
+	// State-only events trigger a refresh but are not forwarded
+	p.handleEvent(domain.Event{EventType: domain.ScanCompleted})
+	if _, ok := fake.find("hl/events/ScanCompleted"); ok {
+		t.Error("ScanCompleted was not selected and should not be published")
+	}
+	select {
+	case <-p.refresh:
+	default:
+		t.Error("Expected a state refresh after ScanCompleted")
+	}
+}
+
+func TestMQTTPublisher_StartStop(t *testing.T) {
+	tdb := newMQTTTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+
+	fake := &fakeMQTTClient{}
+	p := NewMQTTPublisher(tdb.DB, eb, MQTTConfig{Events: []string{"ScanStarted"}})
+	p.client = fake
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	p.Stop()
+
+	status, ok := fake.find("healarr/status")
+	if !ok || string(status.payload) != "offline" {
+		t.Errorf("Expected offline status on stop, got %+v", status)
+	}
+	if !fake.disconnected {
+		t.Error("Expected client to be disconnected")
+	}
+}
+
+func TestMQTTPublisher_BadCAFile(t *testing.T) {
+	p := NewMQTTPublisher(nil, nil, MQTTConfig{Broker: "ssl://localhost:8883", CAFile: "/nonexistent/ca.pem"})
+	if err := p.Start(); err == nil || !strings.Contains(err.Error(), "CA file") {
+		t.Errorf("Expected CA file error, got %v", err)
+	}
+}
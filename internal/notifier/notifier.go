@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,7 +18,9 @@ import (
 	"github.com/mescon/Healarr/internal/crypto"
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/i18n"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
 )
 
 // notifierQueryTimeout is the maximum time for database queries in notifier.
@@ -33,7 +36,7 @@ const (
 )
 
 // notificationColumns is the SQL column list for notification queries.
-const notificationColumns = `id, name, provider_type, config, events, enabled, throttle_seconds, created_at, updated_at`
+const notificationColumns = `id, name, provider_type, config, events, group_ids, enabled, throttle_seconds, locale, created_at, updated_at`
 
 // Provider types
 const (
@@ -60,6 +63,13 @@ const (
 	ProviderCustom     = "custom"
 )
 
+// extraSecretFields are config fields holding credentials whose names
+// redact doesn't recognize as secrets.
+var extraSecretFields = map[string][]string{
+	ProviderCustom:  {"url"},            // shoutrrr URLs embed tokens and passwords
+	ProviderGeneric: {"custom_headers"}, // e.g. Authorization headers
+}
+
 // NotificationConfig represents a notification provider configuration
 type NotificationConfig struct {
 	ID              int64           `json:"id"`
@@ -67,12 +77,26 @@ type NotificationConfig struct {
 	ProviderType    string          `json:"provider_type"`
 	Config          json.RawMessage `json:"config"`
 	Events          []string        `json:"events"`
+	GroupIDs        []int64         `json:"group_ids"` // only events of scan paths in these groups; empty: every path
 	Enabled         bool            `json:"enabled"`
 	ThrottleSeconds int             `json:"throttle_seconds"`
+	Locale          string          `json:"locale"` // language of the messages; empty: HEALARR_LOCALE
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
 }
 
+// MaskedConfig returns the provider config with its secrets masked, for API
+// responses.
+func (c *NotificationConfig) MaskedConfig() json.RawMessage {
+	return redact.JSON(c.Config, extraSecretFields[c.ProviderType]...)
+}
+
+// KeepSecrets restores the stored secrets of a config whose secrets were sent
+// back masked.
+func (c *NotificationConfig) KeepSecrets(stored *NotificationConfig) {
+	c.Config = redact.KeepJSON(c.Config, stored.Config)
+}
+
 // DiscordConfig holds Discord webhook notification settings.
 type DiscordConfig struct {
 	WebhookURL string `json:"webhook_url"`
@@ -240,6 +264,7 @@ func GetEventGroups() []EventGroup {
 				{string(domain.ScanStarted), "Scan Started", "When a scan begins on a configured media path"},
 				{string(domain.ScanCompleted), "Scan Completed", "When a scan finishes with results"},
 				{string(domain.ScanFailed), "Scan Failed", "When a scan encounters an error and cannot continue"},
+				{string(domain.ScanSkipped), "Scan Skipped", "When a scan can't run because its detection tools are missing"},
 			},
 		},
 		{
@@ -268,6 +293,7 @@ func GetEventGroups() []EventGroup {
 				{string(domain.VerificationFailed), "Replacement Corrupt", "When the new download is also corrupt"},
 				{string(domain.DownloadTimeout), "Download Timeout", "When the replacement download takes too long"},
 				{string(domain.DownloadFailed), "Download Failed", "When the download fails (no seeders, tracker issues)"},
+				{string(domain.DownloadRejected), "Download Rejected", "When a download is corrupt or uses a protocol the path doesn't accept, and gets blocklisted before import"},
 			},
 		},
 		{
@@ -277,12 +303,18 @@ func GetEventGroups() []EventGroup {
 				{string(domain.ManuallyRemoved), "Manually Removed", "When user removes item from *arr queue"},
 				{string(domain.DownloadIgnored), "Download Ignored", "When download was skipped or ignored by *arr"},
 				{string(domain.SearchExhausted), "No Replacement Found", "When indexers have no candidates after retries"},
+				{string(domain.BudgetApprovalRequired), "Budget Approval Required", "When a replacement would exceed the monthly data budget and needs approval"},
+				{string(domain.LowConfidenceDetection), "Low Confidence Detection", "When a corruption is below the path's minimum detection confidence and needs confirmation"},
+				{string(domain.QualityRegression), "Quality Regression", "When a replacement has a lower resolution than the pinned original"},
+				{string(domain.AudioTrackMissing), "Audio Track Missing", "When a replacement lacks audio languages or channels the original had"},
+				{string(domain.DeletionPlanChanged), "Deletion Stopped", "When the *arr files changed between planning and deleting a corrupt file"},
 			},
 		},
 		{
 			Name: "Retry Events",
 			Events: []EventInfo{
 				{string(domain.RetryScheduled), "Retry Scheduled", "When a manual retry is triggered for an item"},
+				{string(domain.ScheduledResearch), "Searching Again Later", "When no replacement was found and the path's re-search schedule searches again later"},
 				{string(domain.MaxRetriesReached), "Max Retries", "When remediation has failed too many times"},
 			},
 		},
@@ -292,13 +324,29 @@ func GetEventGroups() []EventGroup {
 				{string(domain.CorruptionIgnored), "Corruption Ignored", "When a user ignores a detected corruption"},
 			},
 		},
+		{
+			Name: "Reports",
+			Events: []EventInfo{
+				{string(domain.ReportGenerated), "Weekly Report", "A summary of new, resolved and failed corruptions, sent on the report schedule"},
+			},
+		},
 		{
 			Name: "System Events",
 			Events: []EventInfo{
 				{string(domain.SystemHealthDegraded), "System Health Degraded", "When system health checks detect issues"},
 				{string(domain.InstanceUnhealthy), "Arr Instance Unhealthy", "When an *arr instance becomes unreachable"},
 				{string(domain.InstanceHealthy), "Arr Instance Healthy", "When an *arr instance recovers"},
+				{string(domain.MappingDrift), "Path Mapping Drift", "When a scan path's *arr path is no longer inside a root folder of its instance"},
+				{string(domain.ArrRequestBudgetWarning), "Arr Request Budget", "When an *arr instance nears or uses up its daily API request budget"},
 				{string(domain.StuckRemediation), "Stuck Remediation", "When a remediation has been stuck for too long"},
+				{string(domain.SLABreached), "Resolution SLA Breached", "When a corruption stays unresolved past the configured SLA"},
+				{string(domain.RemediationPaused), "Detection-Only Mode", "When an *arr outage pauses remediation and queues new items"},
+				{string(domain.RemediationResumed), "Remediation Resumed", "When an *arr instance recovers and queued items continue"},
+				{string(domain.RemediationBudgetExceeded), "Data Budget Exceeded", "When the monthly remediation data budget is used up and new items are queued"},
+				{string(domain.RemediationBudgetRestored), "Data Budget Available", "When a new month restores the data budget and queued items continue"},
+				{string(domain.RemediationDeferred), "Remediation Deferred", "When a replacement would exceed the monthly data budget, or a new path's daily rollout limit, and has to wait"},
+				{string(domain.AutomationPaused), "Automation Paused", "When all automation is paused, e.g. for maintenance"},
+				{string(domain.AutomationResumed), "Automation Resumed", "When paused automation is resumed by hand or by its timer"},
 			},
 		},
 	}
@@ -409,8 +457,8 @@ func (n *Notifier) scanNotificationRow(scanner interface {
 	Scan(dest ...interface{}) error
 }) (*NotificationConfig, error) {
 	var cfg NotificationConfig
-	var configJSON, eventsJSON string
-	if err := scanner.Scan(&cfg.ID, &cfg.Name, &cfg.ProviderType, &configJSON, &eventsJSON, &cfg.Enabled, &cfg.ThrottleSeconds, &cfg.CreatedAt, &cfg.UpdatedAt); err != nil {
+	var configJSON, eventsJSON, groupIDsJSON string
+	if err := scanner.Scan(&cfg.ID, &cfg.Name, &cfg.ProviderType, &configJSON, &eventsJSON, &groupIDsJSON, &cfg.Enabled, &cfg.ThrottleSeconds, &cfg.Locale, &cfg.CreatedAt, &cfg.UpdatedAt); err != nil {
 		return nil, err
 	}
 
@@ -421,9 +469,13 @@ func (n *Notifier) scanNotificationRow(scanner interface {
 	if err := json.Unmarshal([]byte(decrypted), &cfg.Config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config for notification %d: %w", cfg.ID, err)
 	}
+	redact.RegisterJSON(cfg.Config, extraSecretFields[cfg.ProviderType]...)
 	if json.Unmarshal([]byte(eventsJSON), &cfg.Events) != nil {
 		cfg.Events = []string{}
 	}
+	if json.Unmarshal([]byte(groupIDsJSON), &cfg.GroupIDs) != nil || cfg.GroupIDs == nil {
+		cfg.GroupIDs = []int64{}
+	}
 	return &cfg, nil
 }
 
@@ -469,11 +521,21 @@ func (n *Notifier) getAllEvents() []string {
 }
 
 func (n *Notifier) handleEvent(eventType string, data map[string]interface{}) {
+	// A passing reverification repeats a repair that was already announced,
+	// and an audit is only heard of when a replacement fails it
+	if _, ok := data["reverification_of"]; ok && eventType == string(domain.VerificationSuccess) {
+		return
+	}
+	if data["audit"] == true && eventType == string(domain.ReverificationRequested) {
+		return
+	}
+
 	n.mu.RLock()
 	defer n.mu.RUnlock()
 
+	group := n.eventGroup(data)
 	for _, cfg := range n.configs {
-		if !n.shouldNotify(cfg, eventType) {
+		if !n.shouldNotify(cfg, eventType) || !group.matches(cfg.GroupIDs) {
 			continue
 		}
 		// Check throttle
@@ -495,6 +557,71 @@ func (n *Notifier) shouldNotify(cfg *NotificationConfig, eventType string) bool
 	return false
 }
 
+// eventPathGroup is the scan path group of an event, looked up on first use
+// since most configurations aren't limited to groups.
+type eventPathGroup func() (groupID int64, ok bool)
+
+// matches reports whether an event is for a configuration limited to
+// groupIDs. Events not tied to a scan path, e.g. system health, go to every
+// configuration; events of a path outside the groups don't.
+func (group eventPathGroup) matches(groupIDs []int64) bool {
+	if len(groupIDs) == 0 {
+		return true
+	}
+	groupID, ok := group()
+	if !ok {
+		return true
+	}
+	for _, id := range groupIDs {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// eventGroup returns the lookup of the scan path group of an event: by its
+// path_id, or for corruption events without one by the corruption's path.
+// ok is false for events not tied to a scan path; a path without a group
+// has group 0.
+func (n *Notifier) eventGroup(data map[string]interface{}) eventPathGroup {
+	var groupID int64
+	var ok, looked bool
+	return func() (int64, bool) {
+		if looked {
+			return groupID, ok
+		}
+		looked = true
+		if n.db == nil {
+			return 0, false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+		defer cancel()
+
+		event := domain.Event{EventData: data}
+		var group sql.NullInt64
+		var err error
+		if pathID, found := event.GetInt64("path_id"); found && pathID > 0 {
+			err = n.db.QueryRowContext(ctx, `SELECT group_id FROM scan_paths WHERE id = ?`, pathID).Scan(&group)
+		} else if aggregateID := n.extractAggregateID(data); aggregateID != "" {
+			err = n.db.QueryRowContext(ctx, `
+				SELECT p.group_id FROM corruption_summary cs JOIN scan_paths p ON p.id = cs.path_id
+				WHERE cs.corruption_id = ?
+			`, aggregateID).Scan(&group)
+		} else {
+			return 0, false
+		}
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				logger.Debugf("Failed to look up the path group of a notification event: %v", err)
+			}
+			return 0, false
+		}
+		groupID, ok = group.Int64, true
+		return groupID, ok
+	}
+}
+
 func (n *Notifier) canSend(configID int64, throttleSeconds int) bool {
 	n.mu.RLock()
 	lastSent, exists := n.lastSent[configID]
@@ -524,7 +651,7 @@ func (n *Notifier) sendNotification(cfg *NotificationConfig, eventType string, d
 		}
 
 		// Format message
-		message = n.formatMessage(eventType, data)
+		message = n.formatMessage(eventType, data, cfg.Locale)
 
 		// Send via shoutrrr
 		err = shoutrrr.Send(shoutrrrURL, message)
@@ -680,6 +807,13 @@ type messageContext struct {
 	ErrorMsg       string
 	Reason         string
 	Attempts       int
+	ElapsedHours   float64
+	SLAHours       float64
+	Report         string // Markdown body of a ReportGenerated event
+	NextSearchAt   string // When a ScheduledResearch searches again (RFC 3339)
+	DeferredUntil  string // When a RemediationDeferred goes ahead (RFC 3339)
+
+	tr i18n.Printer // translates the message; the zero value is English
 }
 
 // extractMessageContext extracts common fields from event data
@@ -702,8 +836,13 @@ func extractMessageContext(data map[string]interface{}) messageContext {
 	ctx.RetryCount = extractInt(data, "retry_count")
 	ctx.MaxRetries = extractInt(data, "max_retries")
 	ctx.Attempts = extractInt(data, "attempts")
+	ctx.ElapsedHours, _ = data["elapsed_hours"].(float64)
+	ctx.SLAHours, _ = data["sla_hours"].(float64)
 	ctx.ErrorMsg, _ = data["error"].(string)
 	ctx.Reason, _ = data["reason"].(string)
+	ctx.Report, _ = data["markdown"].(string)
+	ctx.NextSearchAt, _ = data["next_search_at"].(string)
+	ctx.DeferredUntil, _ = data["deferred_until"].(string)
 
 	return ctx
 }
@@ -724,194 +863,351 @@ type messageFormatter func(ctx messageContext) string
 
 // messageFormatters maps event types to their message formatters
 var messageFormatters = map[string]messageFormatter{
-	string(domain.ScanStarted):          fmtScanStarted,
-	string(domain.ScanCompleted):        fmtScanCompleted,
-	string(domain.ScanFailed):           fmtScanFailed,
-	string(domain.CorruptionDetected):   fmtCorruptionDetected,
-	string(domain.RemediationQueued):    fmtRemediationQueued,
-	string(domain.DeletionStarted):      fmtDeletionStarted,
-	string(domain.DeletionCompleted):    fmtDeletionCompleted,
-	string(domain.DeletionFailed):       fmtDeletionFailed,
-	string(domain.SearchStarted):        fmtSearchStarted,
-	string(domain.SearchCompleted):      fmtSearchCompleted,
-	string(domain.SearchFailed):         fmtSearchFailed,
-	string(domain.VerificationStarted):  fmtVerificationStarted,
-	string(domain.VerificationSuccess):  fmtVerificationSuccess,
-	string(domain.VerificationFailed):   fmtVerificationFailed,
-	string(domain.DownloadTimeout):      fmtDownloadTimeout,
-	string(domain.ImportBlocked):        fmtImportBlocked,
-	string(domain.ManuallyRemoved):      fmtManuallyRemoved,
-	string(domain.DownloadIgnored):      fmtDownloadIgnored,
-	string(domain.RetryScheduled):       fmtRetryScheduled,
-	string(domain.MaxRetriesReached):    fmtMaxRetriesReached,
-	string(domain.SearchExhausted):      fmtSearchExhausted,
-	string(domain.DownloadFailed):       fmtDownloadFailed,
-	string(domain.SystemHealthDegraded): fmtSystemHealthDegraded,
-	string(domain.InstanceUnhealthy):    fmtInstanceUnhealthy,
-	string(domain.InstanceHealthy):      fmtInstanceHealthy,
-	string(domain.StuckRemediation):     fmtStuckRemediation,
-	string(domain.CorruptionIgnored):    fmtCorruptionIgnored,
+	string(domain.ScanStarted):               fmtScanStarted,
+	string(domain.ScanCompleted):             fmtScanCompleted,
+	string(domain.ScanFailed):                fmtScanFailed,
+	string(domain.ScanSkipped):               fmtScanSkipped,
+	string(domain.CorruptionDetected):        fmtCorruptionDetected,
+	string(domain.RemediationQueued):         fmtRemediationQueued,
+	string(domain.DeletionStarted):           fmtDeletionStarted,
+	string(domain.DeletionCompleted):         fmtDeletionCompleted,
+	string(domain.DeletionFailed):            fmtDeletionFailed,
+	string(domain.SearchStarted):             fmtSearchStarted,
+	string(domain.SearchCompleted):           fmtSearchCompleted,
+	string(domain.SearchFailed):              fmtSearchFailed,
+	string(domain.VerificationStarted):       fmtVerificationStarted,
+	string(domain.VerificationSuccess):       fmtVerificationSuccess,
+	string(domain.VerificationFailed):        fmtVerificationFailed,
+	string(domain.DownloadTimeout):           fmtDownloadTimeout,
+	string(domain.ImportBlocked):             fmtImportBlocked,
+	string(domain.ManuallyRemoved):           fmtManuallyRemoved,
+	string(domain.DownloadIgnored):           fmtDownloadIgnored,
+	string(domain.RetryScheduled):            fmtRetryScheduled,
+	string(domain.MaxRetriesReached):         fmtMaxRetriesReached,
+	string(domain.SearchExhausted):           fmtSearchExhausted,
+	string(domain.ScheduledResearch):         fmtScheduledResearch,
+	string(domain.DownloadFailed):            fmtDownloadFailed,
+	string(domain.DownloadRejected):          fmtDownloadRejected,
+	string(domain.SystemHealthDegraded):      fmtSystemHealthDegraded,
+	string(domain.InstanceUnhealthy):         fmtInstanceUnhealthy,
+	string(domain.InstanceHealthy):           fmtInstanceHealthy,
+	string(domain.ArrRequestBudgetWarning):   fmtArrRequestBudgetWarning,
+	string(domain.MappingDrift):              fmtMappingDrift,
+	string(domain.StuckRemediation):          fmtStuckRemediation,
+	string(domain.SLABreached):               fmtSLABreached,
+	string(domain.RemediationPaused):         fmtRemediationPaused,
+	string(domain.RemediationResumed):        fmtRemediationResumed,
+	string(domain.RemediationBudgetExceeded): fmtRemediationBudgetExceeded,
+	string(domain.RemediationBudgetRestored): fmtRemediationBudgetRestored,
+	string(domain.RemediationDeferred):       fmtRemediationDeferred,
+	string(domain.AutomationPaused):          fmtAutomationPaused,
+	string(domain.AutomationResumed):         fmtAutomationResumed,
+	string(domain.BudgetApprovalRequired):    fmtBudgetApprovalRequired,
+	string(domain.LowConfidenceDetection):    fmtLowConfidenceDetection,
+	string(domain.QualityRegression):         fmtQualityRegression,
+	string(domain.AudioTrackMissing):         fmtAudioTrackMissing,
+	string(domain.DeletionPlanChanged):       fmtDeletionPlanChanged,
+	string(domain.ReportGenerated):           fmtReportGenerated,
+	string(domain.CorruptionIgnored):         fmtCorruptionIgnored,
 }
 
 func fmtScanStarted(ctx messageContext) string {
-	return fmt.Sprintf("🔍 Scan started: %s", ctx.ScanPath)
+	return ctx.tr.Sprintf("🔍 Scan started: %s", ctx.ScanPath)
 }
 
 func fmtScanCompleted(ctx messageContext) string {
-	return fmt.Sprintf("✅ Scan complete: %s\n📊 %d/%d healthy, %d corrupt", ctx.ScanPath, ctx.Healthy, ctx.Total, ctx.Corrupt)
+	return ctx.tr.Sprintf("✅ Scan complete: %s\n📊 %d/%d healthy, %d corrupt", ctx.ScanPath, ctx.Healthy, ctx.Total, ctx.Corrupt)
 }
 
 func fmtScanFailed(ctx messageContext) string {
-	return fmt.Sprintf("❌ Scan failed: %s\n⚠️ %s", ctx.ScanPath, ctx.ErrorMsg)
+	return ctx.tr.Sprintf("❌ Scan failed: %s\n⚠️ %s", ctx.ScanPath, ctx.ErrorMsg)
+}
+
+func fmtScanSkipped(ctx messageContext) string {
+	msg := ctx.tr.Sprintf("⏭️ Scan skipped: %s", ctx.ScanPath)
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg
 }
 
 func fmtCorruptionDetected(ctx messageContext) string {
-	msg := fmt.Sprintf("🔴 Corruption detected: %s", ctx.FileName)
+	msg := ctx.tr.Sprintf("🔴 Corruption detected: %s", ctx.FileName)
 	if ctx.CorruptionType != "" {
-		msg += fmt.Sprintf("\n📋 Type: %s", ctx.CorruptionType)
+		msg += ctx.tr.Sprintf("\n📋 Type: %s", ctx.CorruptionType)
 	}
 	return msg
 }
 
 func fmtRemediationQueued(ctx messageContext) string {
-	return fmt.Sprintf("🔧 Remediation queued: %s", ctx.FileName)
+	return ctx.tr.Sprintf("🔧 Remediation queued: %s", ctx.FileName)
 }
 
 func fmtDeletionStarted(ctx messageContext) string {
-	return fmt.Sprintf("🗑️ Deletion started: %s", ctx.FileName)
+	return ctx.tr.Sprintf("🗑️ Deletion started: %s", ctx.FileName)
 }
 
 func fmtDeletionCompleted(ctx messageContext) string {
-	return fmt.Sprintf("✅ File deleted for re-download: %s", ctx.FileName)
+	return ctx.tr.Sprintf("✅ File deleted for re-download: %s", ctx.FileName)
 }
 
 func fmtDeletionFailed(ctx messageContext) string {
-	return fmt.Sprintf("❌ Deletion failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
+	return ctx.tr.Sprintf("❌ Deletion failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
 }
 
 func fmtSearchStarted(ctx messageContext) string {
-	return fmt.Sprintf("🔎 Search triggered in *arr: %s", ctx.FileName)
+	return ctx.tr.Sprintf("🔎 Search triggered in *arr: %s", ctx.FileName)
 }
 
 func fmtSearchCompleted(ctx messageContext) string {
-	return fmt.Sprintf("✅ Search completed: %s", ctx.FileName)
+	return ctx.tr.Sprintf("✅ Search completed: %s", ctx.FileName)
 }
 
 func fmtSearchFailed(ctx messageContext) string {
-	return fmt.Sprintf("❌ Search failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
+	return ctx.tr.Sprintf("❌ Search failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
 }
 
 func fmtVerificationStarted(ctx messageContext) string {
-	return fmt.Sprintf("🔬 Verification started: %s", ctx.FileName)
+	return ctx.tr.Sprintf("🔬 Verification started: %s", ctx.FileName)
 }
 
 func fmtVerificationSuccess(ctx messageContext) string {
-	return fmt.Sprintf("✅ File verified healthy: %s", ctx.FileName)
+	return ctx.tr.Sprintf("✅ File verified healthy: %s", ctx.FileName)
 }
 
 func fmtVerificationFailed(ctx messageContext) string {
-	return fmt.Sprintf("❌ Verification failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
+	return ctx.tr.Sprintf("❌ Verification failed: %s\n⚠️ %s", ctx.FileName, ctx.ErrorMsg)
 }
 
 func fmtDownloadTimeout(ctx messageContext) string {
-	return fmt.Sprintf("⏰ Download timeout: %s", ctx.FileName)
+	return ctx.tr.Sprintf("⏰ Download timeout: %s", ctx.FileName)
 }
 
 func fmtImportBlocked(ctx messageContext) string {
-	return fmt.Sprintf("🚫 Import blocked in *arr: %s\n⚠️ %s\n👉 Manual intervention required in Sonarr/Radarr", ctx.FileName, ctx.ErrorMsg)
+	return ctx.tr.Sprintf("🚫 Import blocked in *arr: %s\n⚠️ %s\n👉 Manual intervention required in Sonarr/Radarr", ctx.FileName, ctx.ErrorMsg)
+}
+
+func fmtBudgetApprovalRequired(ctx messageContext) string {
+	return ctx.tr.Sprintf("📶 Approval required: %s\n⚠️ %s\n👉 Approve the remediation in Healarr to download it anyway", ctx.FileName, ctx.Reason)
+}
+
+func fmtLowConfidenceDetection(ctx messageContext) string {
+	return ctx.tr.Sprintf("🤔 Unconfirmed corruption: %s\n⚠️ %s\n👉 Retry in Healarr to remediate it, or ignore it", ctx.FileName, ctx.Reason)
+}
+
+func fmtQualityRegression(ctx messageContext) string {
+	return ctx.tr.Sprintf("📉 Quality regression: %s\n⚠️ %s", ctx.FileName, ctx.Reason)
+}
+
+func fmtAudioTrackMissing(ctx messageContext) string {
+	return ctx.tr.Sprintf("🔇 Replacement missing audio track: %s\n⚠️ %s", ctx.FileName, ctx.Reason)
+}
+
+func fmtDeletionPlanChanged(ctx messageContext) string {
+	return ctx.tr.Sprintf("✋ Deletion stopped: %s\n⚠️ %s\n👉 Check the file in *arr, then retry in Healarr", ctx.FileName, ctx.Reason)
+}
+
+func fmtRemediationDeferred(ctx messageContext) string {
+	msg := ctx.tr.Sprintf("📶 Remediation deferred: %s\n⚠️ %s", ctx.FileName, ctx.Reason)
+	if until, err := time.Parse(time.RFC3339, ctx.DeferredUntil); err == nil {
+		msg += ctx.tr.Sprintf("\n👉 Goes ahead on %s", until.Format("2006-01-02"))
+	}
+	return msg
 }
 
 func fmtManuallyRemoved(ctx messageContext) string {
-	return fmt.Sprintf("🗑️ Download manually removed: %s\n👉 Item was removed from *arr queue without being imported", ctx.FileName)
+	return ctx.tr.Sprintf("🗑️ Download manually removed: %s\n👉 Item was removed from *arr queue without being imported", ctx.FileName)
 }
 
 func fmtDownloadIgnored(ctx messageContext) string {
-	return fmt.Sprintf("⏸️ Download ignored by user: %s\n👉 User marked download as ignored in *arr - remediation stopped", ctx.FileName)
+	return ctx.tr.Sprintf("⏸️ Download ignored by user: %s\n👉 User marked download as ignored in *arr - remediation stopped", ctx.FileName)
 }
 
 func fmtRetryScheduled(ctx messageContext) string {
-	return fmt.Sprintf("🔄 Retry scheduled (%d/%d): %s", ctx.RetryCount, ctx.MaxRetries, ctx.FileName)
+	return ctx.tr.Sprintf("🔄 Retry scheduled (%d/%d): %s", ctx.RetryCount, ctx.MaxRetries, ctx.FileName)
 }
 
 func fmtMaxRetriesReached(ctx messageContext) string {
-	return fmt.Sprintf("⚠️ Max retries exhausted (%d): %s", ctx.MaxRetries, ctx.FileName)
+	return ctx.tr.Sprintf("⚠️ Max retries exhausted (%d): %s", ctx.MaxRetries, ctx.FileName)
 }
 
 func fmtSearchExhausted(ctx messageContext) string {
-	msg := fmt.Sprintf("🔍 No replacement found: %s", ctx.FileName)
+	msg := ctx.tr.Sprintf("🔍 No replacement found: %s", ctx.FileName)
 	if ctx.Attempts > 0 {
-		msg += fmt.Sprintf("\n📊 Attempts: %d", ctx.Attempts)
+		msg += ctx.tr.Sprintf("\n📊 Attempts: %d", ctx.Attempts)
 	}
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtReason, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtReason, ctx.Reason)
+	}
+	msg += ctx.tr.T("\n👉 Check your indexers or manually search in Sonarr/Radarr")
+	return msg
+}
+
+func fmtScheduledResearch(ctx messageContext) string {
+	msg := ctx.tr.Sprintf("🗓️ No replacement yet: %s", ctx.FileName)
+	if next, err := time.Parse(time.RFC3339, ctx.NextSearchAt); err == nil {
+		msg += ctx.tr.Sprintf("\n🔍 Searching again on %s", next.Format("2006-01-02"))
 	}
-	msg += "\n👉 Check your indexers or manually search in Sonarr/Radarr"
 	return msg
 }
 
 func fmtDownloadFailed(ctx messageContext) string {
-	msg := fmt.Sprintf("❌ Download failed: %s", ctx.FileName)
+	msg := ctx.tr.Sprintf("❌ Download failed: %s", ctx.FileName)
 	if ctx.ErrorMsg != "" {
-		msg += fmt.Sprintf("\n⚠️ %s", ctx.ErrorMsg)
+		msg += ctx.tr.Sprintf("\n⚠️ %s", ctx.ErrorMsg)
 	}
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtReason, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtReason, ctx.Reason)
+	}
+	return msg
+}
+
+func fmtDownloadRejected(ctx messageContext) string {
+	msg := ctx.tr.Sprintf("🚫 Corrupt download blocklisted before import: %s", ctx.FileName)
+	if ctx.Reason == "protocol" {
+		msg = ctx.tr.Sprintf("🚫 Download blocklisted: %s", ctx.FileName)
+	}
+	if ctx.ErrorMsg != "" {
+		msg += ctx.tr.Sprintf("\n⚠️ %s", ctx.ErrorMsg)
 	}
+	msg += ctx.tr.T("\n🔍 *arr is searching for another release")
 	return msg
 }
 
 func fmtSystemHealthDegraded(ctx messageContext) string {
-	msg := "⚠️ System health degraded"
+	msg := ctx.tr.T("⚠️ System health degraded")
 	if ctx.ErrorMsg != "" {
-		msg += fmt.Sprintf(msgFmtDetail, ctx.ErrorMsg)
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.ErrorMsg)
 	}
 	return msg
 }
 
 func fmtInstanceUnhealthy(ctx messageContext) string {
-	msg := "🔴 Arr instance unreachable"
+	msg := ctx.tr.T("🔴 Arr instance unreachable")
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtDetail, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
 	}
 	if ctx.ErrorMsg != "" {
-		msg += fmt.Sprintf("\n⚠️ %s", ctx.ErrorMsg)
+		msg += ctx.tr.Sprintf("\n⚠️ %s", ctx.ErrorMsg)
 	}
 	return msg
 }
 
 func fmtInstanceHealthy(ctx messageContext) string {
-	msg := "🟢 Arr instance recovered"
+	msg := ctx.tr.T("🟢 Arr instance recovered")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg
+}
+
+func fmtSLABreached(ctx messageContext) string {
+	msg := ctx.tr.T("⌛ Resolution SLA breached")
+	if ctx.FilePath != "" {
+		msg += ctx.tr.Sprintf(": %s", ctx.FileName)
+	}
+	if ctx.SLAHours > 0 {
+		msg += ctx.tr.Sprintf("\n⏱️ Unresolved for %.1fh (SLA: %.1fh)", ctx.ElapsedHours, ctx.SLAHours)
+	}
+	return msg
+}
+
+func fmtRemediationPaused(ctx messageContext) string {
+	msg := ctx.tr.T("⏸️ Remediation paused, scanning continues in detection-only mode")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg + ctx.tr.T("\n👉 New corruptions are queued until the instance recovers")
+}
+
+func fmtRemediationResumed(ctx messageContext) string {
+	msg := ctx.tr.T("▶️ Remediation resumed")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg
+}
+
+func fmtArrRequestBudgetWarning(ctx messageContext) string {
+	msg := ctx.tr.T("📉 Daily *arr API request budget nearly used up")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg + ctx.tr.T("\n👉 Non-urgent requests wait until tomorrow; remediation continues")
+}
+
+func fmtMappingDrift(ctx messageContext) string {
+	msg := ctx.tr.T("🧭 Scan path no longer matches the *arr root folders")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg + ctx.tr.T("\n👉 Remap the path in Healarr's config so its files match *arr media again")
+}
+
+func fmtRemediationBudgetExceeded(ctx messageContext) string {
+	msg := ctx.tr.T("📶 Monthly remediation data budget used up")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg + ctx.tr.T("\n👉 New corruptions are queued until next month")
+}
+
+func fmtRemediationBudgetRestored(ctx messageContext) string {
+	msg := ctx.tr.T("📶 Remediation data budget available again")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg
+}
+
+func fmtAutomationPaused(ctx messageContext) string {
+	msg := ctx.tr.T("⏸️ Automation paused")
+	if ctx.Reason != "" {
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
+	}
+	return msg + ctx.tr.T("\n👉 Remediation and verification are on hold until automation is resumed")
+}
+
+func fmtAutomationResumed(ctx messageContext) string {
+	msg := ctx.tr.T("▶️ Automation resumed")
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtDetail, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
 	}
 	return msg
 }
 
+func fmtReportGenerated(ctx messageContext) string {
+	return ctx.Report
+}
+
 func fmtStuckRemediation(ctx messageContext) string {
-	msg := "⏰ Stuck remediation detected"
+	msg := ctx.tr.T("⏰ Stuck remediation detected")
 	if ctx.FilePath != "" {
-		msg += fmt.Sprintf(": %s", ctx.FileName)
+		msg += ctx.tr.Sprintf(": %s", ctx.FileName)
 	}
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtDetail, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtDetail, ctx.Reason)
 	}
-	return msg + "\n👉 Remediation has shown no progress - manual check recommended"
+	return msg + ctx.tr.T("\n👉 Remediation has shown no progress - manual check recommended")
 }
 
 func fmtCorruptionIgnored(ctx messageContext) string {
-	msg := fmt.Sprintf("🙈 Corruption ignored: %s", ctx.FileName)
+	msg := ctx.tr.Sprintf("🙈 Corruption ignored: %s", ctx.FileName)
 	if ctx.Reason != "" {
-		msg += fmt.Sprintf(msgFmtReason, ctx.Reason)
+		msg += ctx.tr.Sprintf(msgFmtReason, ctx.Reason)
 	}
 	return msg
 }
 
-func (n *Notifier) formatMessage(eventType string, data map[string]interface{}) string {
+// formatMessage formats the message of an event in locale; an empty locale
+// is the default locale.
+func (n *Notifier) formatMessage(eventType string, data map[string]interface{}, locale string) string {
 	ctx := extractMessageContext(data)
+	ctx.tr = i18n.For(locale)
 	if formatter, ok := messageFormatters[eventType]; ok {
 		return formatter(ctx)
 	}
-	return fmt.Sprintf("📢 Event: %s", eventType)
+	return ctx.tr.Sprintf("📢 Event: %s", eventType)
 }
 
 // GenericWebhookPayload is the rich JSON payload sent to generic webhooks
@@ -1010,8 +1306,8 @@ func (n *Notifier) sendGenericWebhook(cfg *NotificationConfig, eventType string,
 	}
 
 	payload := GenericWebhookPayload{
-		Title:     n.formatTitle(eventType, getFileName(data)),
-		Message:   n.formatMessage(eventType, data),
+		Title:     n.formatTitle(eventType, getFileName(data), cfg.Locale),
+		Message:   n.formatMessage(eventType, data, cfg.Locale),
 		Event:     eventType,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Source:    "healarr",
@@ -1062,45 +1358,65 @@ func (n *Notifier) sendGenericWebhook(cfg *NotificationConfig, eventType string,
 // formatTitle creates a short title for the event
 // eventTitles maps event types to short titles
 var eventTitles = map[string]string{
-	string(domain.ScanStarted):          "🔍 Scan Started",
-	string(domain.ScanCompleted):        "✅ Scan Complete",
-	string(domain.ScanFailed):           "❌ Scan Failed",
-	string(domain.RemediationQueued):    "🔧 Remediation Queued",
-	string(domain.DeletionStarted):      "🗑️ Deletion Started",
-	string(domain.DeletionCompleted):    "✅ File Deleted",
-	string(domain.DeletionFailed):       "❌ Deletion Failed",
-	string(domain.SearchStarted):        "🔎 Search Triggered",
-	string(domain.SearchCompleted):      "✅ Search Complete",
-	string(domain.SearchFailed):         "❌ Search Failed",
-	string(domain.VerificationStarted):  "🔬 Verification Started",
-	string(domain.VerificationSuccess):  "✅ Verification Success",
-	string(domain.VerificationFailed):   "❌ Verification Failed",
-	string(domain.DownloadTimeout):      "⏰ Download Timeout",
-	string(domain.ImportBlocked):        "🚫 Import Blocked - Manual Action Required",
-	string(domain.ManuallyRemoved):      "🗑️ Download Manually Removed",
-	string(domain.DownloadIgnored):      "⏸️ Download Ignored by User",
-	string(domain.RetryScheduled):       "🔄 Retry Scheduled",
-	string(domain.MaxRetriesReached):    "⚠️ Max Retries Reached",
-	string(domain.SearchExhausted):      "🔍 No Replacement Found",
-	string(domain.DownloadFailed):       "❌ Download Failed",
-	string(domain.SystemHealthDegraded): "⚠️ System Health Degraded",
-	string(domain.InstanceUnhealthy):    "🔴 Arr Instance Unreachable",
-	string(domain.InstanceHealthy):      "🟢 Arr Instance Recovered",
-	string(domain.StuckRemediation):     "⏰ Stuck Remediation Detected",
-	string(domain.CorruptionIgnored):    "🙈 Corruption Ignored by User",
-}
-
-func (n *Notifier) formatTitle(eventType, fileName string) string {
+	string(domain.ScanStarted):               "🔍 Scan Started",
+	string(domain.ScanCompleted):             "✅ Scan Complete",
+	string(domain.ScanFailed):                "❌ Scan Failed",
+	string(domain.ScanSkipped):               "⏭️ Scan Skipped",
+	string(domain.RemediationQueued):         "🔧 Remediation Queued",
+	string(domain.DeletionStarted):           "🗑️ Deletion Started",
+	string(domain.DeletionCompleted):         "✅ File Deleted",
+	string(domain.DeletionFailed):            "❌ Deletion Failed",
+	string(domain.SearchStarted):             "🔎 Search Triggered",
+	string(domain.SearchCompleted):           "✅ Search Complete",
+	string(domain.SearchFailed):              "❌ Search Failed",
+	string(domain.VerificationStarted):       "🔬 Verification Started",
+	string(domain.VerificationSuccess):       "✅ Verification Success",
+	string(domain.VerificationFailed):        "❌ Verification Failed",
+	string(domain.DownloadTimeout):           "⏰ Download Timeout",
+	string(domain.ImportBlocked):             "🚫 Import Blocked - Manual Action Required",
+	string(domain.ManuallyRemoved):           "🗑️ Download Manually Removed",
+	string(domain.DownloadIgnored):           "⏸️ Download Ignored by User",
+	string(domain.RetryScheduled):            "🔄 Retry Scheduled",
+	string(domain.MaxRetriesReached):         "⚠️ Max Retries Reached",
+	string(domain.SearchExhausted):           "🔍 No Replacement Found",
+	string(domain.ScheduledResearch):         "🗓️ Searching Again Later",
+	string(domain.DownloadFailed):            "❌ Download Failed",
+	string(domain.DownloadRejected):          "🚫 Corrupt Download Blocklisted",
+	string(domain.SystemHealthDegraded):      "⚠️ System Health Degraded",
+	string(domain.InstanceUnhealthy):         "🔴 Arr Instance Unreachable",
+	string(domain.InstanceHealthy):           "🟢 Arr Instance Recovered",
+	string(domain.ArrRequestBudgetWarning):   "📉 Arr Request Budget Low",
+	string(domain.MappingDrift):              "🧭 Path Mapping Drift",
+	string(domain.StuckRemediation):          "⏰ Stuck Remediation Detected",
+	string(domain.SLABreached):               "⌛ Resolution SLA Breached",
+	string(domain.RemediationPaused):         "⏸️ Remediation Paused - Detection Only",
+	string(domain.RemediationResumed):        "▶️ Remediation Resumed",
+	string(domain.RemediationBudgetExceeded): "📶 Data Budget Exceeded",
+	string(domain.RemediationBudgetRestored): "📶 Data Budget Available",
+	string(domain.RemediationDeferred):       "📶 Remediation Deferred",
+	string(domain.AutomationPaused):          "⏸️ Automation Paused",
+	string(domain.AutomationResumed):         "▶️ Automation Resumed",
+	string(domain.BudgetApprovalRequired):    "📶 Budget Approval Required",
+	string(domain.LowConfidenceDetection):    "🤔 Low Confidence Detection",
+	string(domain.QualityRegression):         "📉 Quality Regression",
+	string(domain.AudioTrackMissing):         "🔇 Audio Track Missing",
+	string(domain.DeletionPlanChanged):       "✋ Deletion Stopped - Manual Action Required",
+	string(domain.ReportGenerated):           "📊 Weekly Report",
+	string(domain.CorruptionIgnored):         "🙈 Corruption Ignored by User",
+}
+
+func (n *Notifier) formatTitle(eventType, fileName, locale string) string {
+	tr := i18n.For(locale)
 	// Special case: CorruptionDetected includes filename
 	if eventType == string(domain.CorruptionDetected) {
 		if fileName != "" {
-			return fmt.Sprintf("🔴 Corruption detected: %s", fileName)
+			return tr.Sprintf("🔴 Corruption detected: %s", fileName)
 		}
-		return "🔴 Corruption Detected"
+		return tr.T("🔴 Corruption Detected")
 	}
 
 	if title, ok := eventTitles[eventType]; ok {
-		return title
+		return tr.T(title)
 	}
 	return fmt.Sprintf("📢 %s", eventType)
 }
@@ -1157,7 +1473,7 @@ func (n *Notifier) SendTestNotification(cfg *NotificationConfig) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	message := "🧪 Healarr Test Notification\n✅ Your notification configuration is working correctly!"
+	message := i18n.For(cfg.Locale).T("🧪 Healarr Test Notification\n✅ Your notification configuration is working correctly!")
 
 	if err := shoutrrr.Send(shoutrrrURL, message); err != nil {
 		return fmt.Errorf("failed to send: %w", err)
@@ -1207,7 +1523,7 @@ func (n *Notifier) GetConfig(id int64) (*NotificationConfig, error) {
 
 // CreateConfig creates a new notification configuration
 func (n *Notifier) CreateConfig(cfg *NotificationConfig) (int64, error) {
-	eventsJSON, err := json.Marshal(cfg.Events)
+	eventsJSON, groupIDsJSON, err := encodeNotificationLists(cfg)
 	if err != nil {
 		return 0, err
 	}
@@ -1222,9 +1538,9 @@ func (n *Notifier) CreateConfig(cfg *NotificationConfig) (int64, error) {
 	defer cancel()
 
 	result, err := n.db.ExecContext(ctx, `
-		INSERT INTO notifications (name, provider_type, config, events, enabled, throttle_seconds)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, cfg.Name, cfg.ProviderType, encryptedConfig, string(eventsJSON), cfg.Enabled, cfg.ThrottleSeconds)
+		INSERT INTO notifications (name, provider_type, config, events, group_ids, enabled, throttle_seconds, locale)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, cfg.Name, cfg.ProviderType, encryptedConfig, eventsJSON, groupIDsJSON, cfg.Enabled, cfg.ThrottleSeconds, cfg.Locale)
 	if err != nil {
 		return 0, err
 	}
@@ -1240,7 +1556,7 @@ func (n *Notifier) CreateConfig(cfg *NotificationConfig) (int64, error) {
 
 // UpdateConfig updates an existing notification configuration
 func (n *Notifier) UpdateConfig(cfg *NotificationConfig) error {
-	eventsJSON, err := json.Marshal(cfg.Events)
+	eventsJSON, groupIDsJSON, err := encodeNotificationLists(cfg)
 	if err != nil {
 		return err
 	}
@@ -1256,9 +1572,9 @@ func (n *Notifier) UpdateConfig(cfg *NotificationConfig) error {
 
 	_, err = n.db.ExecContext(ctx, `
 		UPDATE notifications
-		SET name = ?, provider_type = ?, config = ?, events = ?, enabled = ?, throttle_seconds = ?, updated_at = datetime('now')
+		SET name = ?, provider_type = ?, config = ?, events = ?, group_ids = ?, enabled = ?, throttle_seconds = ?, locale = ?, updated_at = datetime('now')
 		WHERE id = ?
-	`, cfg.Name, cfg.ProviderType, encryptedConfig, string(eventsJSON), cfg.Enabled, cfg.ThrottleSeconds, cfg.ID)
+	`, cfg.Name, cfg.ProviderType, encryptedConfig, eventsJSON, groupIDsJSON, cfg.Enabled, cfg.ThrottleSeconds, cfg.Locale, cfg.ID)
 	if err != nil {
 		return err
 	}
@@ -1267,6 +1583,23 @@ func (n *Notifier) UpdateConfig(cfg *NotificationConfig) error {
 	return nil
 }
 
+// encodeNotificationLists returns the events and path groups of a
+// configuration as JSON.
+func encodeNotificationLists(cfg *NotificationConfig) (events, groupIDs string, err error) {
+	if cfg.GroupIDs == nil {
+		cfg.GroupIDs = []int64{}
+	}
+	eventsJSON, err := json.Marshal(cfg.Events)
+	if err != nil {
+		return "", "", err
+	}
+	groupIDsJSON, err := json.Marshal(cfg.GroupIDs)
+	if err != nil {
+		return "", "", err
+	}
+	return string(eventsJSON), string(groupIDsJSON), nil
+}
+
 // DeleteConfig deletes a notification configuration
 func (n *Notifier) DeleteConfig(id int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
@@ -46,8 +46,10 @@ func newTestDB(t *testing.T) *testDB {
 			provider_type TEXT NOT NULL,
 			config TEXT NOT NULL,
 			events TEXT NOT NULL,
+			group_ids TEXT NOT NULL DEFAULT '[]',
 			enabled INTEGER DEFAULT 1,
 			throttle_seconds INTEGER DEFAULT 0,
+			locale TEXT NOT NULL DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
@@ -1158,7 +1160,7 @@ func TestNotifier_FormatMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.eventType, func(t *testing.T) {
-			msg := n.formatMessage(tt.eventType, tt.data)
+			msg := n.formatMessage(tt.eventType, tt.data, "")
 			for _, s := range tt.contains {
 				if !strings.Contains(msg, s) {
 					t.Errorf("formatMessage() = %q, should contain %q", msg, s)
@@ -1207,7 +1209,7 @@ func TestNotifier_FormatTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.eventType, func(t *testing.T) {
-			title := n.formatTitle(tt.eventType, tt.fileName)
+			title := n.formatTitle(tt.eventType, tt.fileName, "")
 			if !strings.Contains(title, tt.contains) {
 				t.Errorf("formatTitle() = %q, should contain %q", title, tt.contains)
 			}
@@ -1215,6 +1217,38 @@ func TestNotifier_FormatTitle(t *testing.T) {
 	}
 }
 
+func TestNotifier_FormatLocalized(t *testing.T) {
+	tdb := newTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+
+	n := NewNotifier(tdb.DB, eb)
+	data := map[string]interface{}{"file_path": "/media/movie.mkv", "corruption_type": "decode_error"}
+
+	tests := []struct {
+		locale string
+		title  string
+		msg    string
+	}{
+		{"", "🔧 Remediation Queued", "🔴 Corruption detected: movie.mkv"},
+		{"de", "🔧 Behebung eingereiht", "🔴 Beschädigung erkannt: movie.mkv\n📋 Typ: decode_error"},
+		{"fr", "🔧 Remédiation en file d'attente", "🔴 Corruption détectée : movie.mkv\n📋 Type : decode_error"},
+		{"es", "🔧 Remediación en cola", "🔴 Corrupción detectada: movie.mkv\n📋 Tipo: decode_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := n.formatTitle(string(domain.RemediationQueued), "", tt.locale); got != tt.title {
+				t.Errorf("formatTitle() = %q, want %q", got, tt.title)
+			}
+			if got := n.formatMessage(string(domain.CorruptionDetected), data, tt.locale); !strings.HasPrefix(got, tt.msg) {
+				t.Errorf("formatMessage() = %q, should start with %q", got, tt.msg)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // Provider label tests
 // =============================================================================
@@ -1432,6 +1466,47 @@ func TestNotifier_ShouldNotify(t *testing.T) {
 	}
 }
 
+func TestNotifier_EventGroupMatches(t *testing.T) {
+	tdb := newTestDB(t)
+	defer tdb.Close()
+
+	if _, err := tdb.DB.Exec(`
+		CREATE TABLE scan_paths (id INTEGER PRIMARY KEY, group_id INTEGER);
+		CREATE TABLE corruption_summary (corruption_id TEXT PRIMARY KEY, path_id INTEGER);
+		INSERT INTO scan_paths (id, group_id) VALUES (1, 10), (2, NULL);
+		INSERT INTO corruption_summary (corruption_id, path_id) VALUES ('c1', 1), ('c2', 2);
+	`); err != nil {
+		t.Fatalf("Failed to create scan paths: %v", err)
+	}
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+	n := NewNotifier(tdb.DB, eb)
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		groupIDs []int64
+		want     bool
+	}{
+		{"no group limit", map[string]interface{}{"path_id": 2}, nil, true},
+		{"path in group", map[string]interface{}{"path_id": 1}, []int64{10}, true},
+		{"path outside group", map[string]interface{}{"path_id": 1}, []int64{11}, false},
+		{"path without group", map[string]interface{}{"path_id": 2}, []int64{10}, false},
+		{"corruption in group", map[string]interface{}{"aggregate_id": "c1"}, []int64{10}, true},
+		{"corruption outside group", map[string]interface{}{"aggregate_id": "c2"}, []int64{10}, false},
+		{"not tied to a path", map[string]interface{}{"message": "disk full"}, []int64{10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.eventGroup(tt.data).matches(tt.groupIDs); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // CRUD operation tests
 // =============================================================================
@@ -1624,8 +1699,10 @@ func newTestDBWithFullLogSchema(t *testing.T) *testDB {
 			provider_type TEXT NOT NULL,
 			config TEXT NOT NULL,
 			events TEXT NOT NULL,
+			group_ids TEXT NOT NULL DEFAULT '[]',
 			enabled INTEGER DEFAULT 1,
 			throttle_seconds INTEGER DEFAULT 0,
+			locale TEXT NOT NULL DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
@@ -2038,6 +2115,78 @@ func TestNotifier_HandleEvent_Throttled(t *testing.T) {
 	}
 }
 
+func TestNotifier_HandleEvent_Reverification(t *testing.T) {
+	tdb := newTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+
+	n := NewNotifier(tdb.DB, eb)
+
+	_, err := tdb.DB.Exec(`
+		INSERT INTO notifications (id, name, provider_type, config, events, enabled, throttle_seconds)
+		VALUES (1, 'Test', 'discord', '{"webhook_url":"https://discord.com/api/webhooks/123/token"}', '["VerificationSuccess"]', 1, 0)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert config: %v", err)
+	}
+	if err := n.loadConfigs(); err != nil {
+		t.Fatalf("loadConfigs failed: %v", err)
+	}
+
+	// A passing reverification repeats a repair that was already announced
+	n.handleEvent(string(domain.VerificationSuccess), map[string]interface{}{
+		"file_path":         "/test/path.mkv",
+		"reverification_of": int64(42),
+	})
+
+	var count int
+	err = tdb.DB.QueryRow("SELECT COUNT(*) FROM notification_log").Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to count logs: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 log entries for a reverification, got %d", count)
+	}
+}
+
+func TestNotifier_HandleEvent_AuditRequest(t *testing.T) {
+	tdb := newTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+
+	n := NewNotifier(tdb.DB, eb)
+
+	_, err := tdb.DB.Exec(`
+		INSERT INTO notifications (id, name, provider_type, config, events, enabled, throttle_seconds)
+		VALUES (1, 'Test', 'discord', '{"webhook_url":"https://discord.com/api/webhooks/123/token"}', '["ReverificationRequested"]', 1, 0)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert config: %v", err)
+	}
+	if err := n.loadConfigs(); err != nil {
+		t.Fatalf("loadConfigs failed: %v", err)
+	}
+
+	n.handleEvent(string(domain.ReverificationRequested), map[string]interface{}{
+		"file_path":         "/test/path.mkv",
+		"reverification_of": int64(42),
+		"audit":             true,
+	})
+
+	var count int
+	err = tdb.DB.QueryRow("SELECT COUNT(*) FROM notification_log").Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to count logs: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 log entries for an audit, got %d", count)
+	}
+}
+
 func TestNotifier_HandleEvent_DisabledConfig(t *testing.T) {
 	tdb := newTestDB(t)
 	defer tdb.Close()
@@ -0,0 +1,724 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// Outbox timing and sizing.
+const (
+	outboxPollInterval    = 5 * time.Second
+	outboxBatchSize       = 50
+	outboxRequestTimeout  = 15 * time.Second
+	outboxBaseBackoff     = 30 * time.Second
+	outboxMaxBackoff      = 1 * time.Hour
+	outboxMaxResponseBody = 512 // bytes of a failed response kept as the delivery error
+)
+
+// Webhook target limits.
+const (
+	DefaultWebhookMaxAttempts = 5
+	MaxWebhookMaxAttempts     = 20
+)
+
+// Delivery statuses stored in outgoing_webhook_deliveries.status
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Headers sent with every outgoing webhook delivery.
+const (
+	HeaderWebhookSignature = "X-Healarr-Signature"
+	HeaderWebhookTimestamp = "X-Healarr-Timestamp"
+	HeaderWebhookEvent     = "X-Healarr-Event"
+	HeaderWebhookDelivery  = "X-Healarr-Delivery"
+)
+
+// outgoingWebhookColumns is the SQL column list for outgoing webhook queries.
+const outgoingWebhookColumns = `id, name, url, secret, events, enabled, max_attempts, created_at, updated_at`
+
+// deliveryColumns is the SQL column list for delivery queries.
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, response_code, last_error,
+	next_attempt_at, last_attempt_at, delivered_at, created_at`
+
+// ErrWebhookNotFound is returned when an outgoing webhook or delivery does not exist.
+var ErrWebhookNotFound = errors.New("outgoing webhook not found")
+
+// OutgoingWebhook is a user-registered target that receives domain events.
+type OutgoingWebhook struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	MaxAttempts int      `json:"max_attempts"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// subscribes reports whether the webhook wants events of the given type.
+func (w *OutgoingWebhook) subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one queued or attempted delivery of an event to a webhook.
+type WebhookDelivery struct {
+	ID            int64           `json:"id"`
+	WebhookID     int64           `json:"webhook_id"`
+	EventID       *int64          `json:"event_id,omitempty"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"response_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt *string         `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *string         `json:"last_attempt_at,omitempty"`
+	DeliveredAt   *string         `json:"delivered_at,omitempty"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// WebhookPayload is the JSON body POSTed to outgoing webhooks.
+type WebhookPayload struct {
+	EventID       int64                  `json:"event_id,omitempty"`
+	EventType     string                 `json:"event_type"`
+	AggregateType string                 `json:"aggregate_type,omitempty"`
+	AggregateID   string                 `json:"aggregate_id,omitempty"`
+	Data          map[string]interface{} `json:"data"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. Receivers recompute it to verify that a delivery
+// came from Healarr and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateWebhookSecret returns a random secret for signing deliveries.
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// WebhookOutbox forwards domain events to user-registered webhook targets.
+// Matching events are first written to the outgoing_webhook_deliveries table
+// and then delivered by a background worker, which retries failed deliveries
+// with exponential backoff until the target's max_attempts is reached.
+type WebhookOutbox struct {
+	db         *sql.DB
+	eb         *eventbus.EventBus
+	client     *http.Client
+	targets    []*OutgoingWebhook // Enabled targets only
+	mu         sync.RWMutex
+	stopChan   chan struct{}
+	wakeChan   chan struct{}
+	reloadChan chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewWebhookOutbox creates a new outgoing webhook outbox.
+func NewWebhookOutbox(db *sql.DB, eb *eventbus.EventBus) *WebhookOutbox {
+	return &WebhookOutbox{
+		db:         db,
+		eb:         eb,
+		client:     &http.Client{Timeout: outboxRequestTimeout},
+		stopChan:   make(chan struct{}),
+		wakeChan:   make(chan struct{}, 1),
+		reloadChan: make(chan struct{}, 1),
+	}
+}
+
+// Start loads the enabled targets, subscribes to every domain event type and
+// starts the delivery worker.
+func (o *WebhookOutbox) Start() error {
+	if err := o.loadTargets(); err != nil {
+		return fmt.Errorf("failed to load outgoing webhooks: %w", err)
+	}
+
+	for _, eventType := range domain.AllEventTypes() {
+		o.eb.Subscribe(eventType, o.enqueue)
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.worker()
+	}()
+
+	o.mu.RLock()
+	count := len(o.targets)
+	o.mu.RUnlock()
+	logger.Infof("Webhook outbox started with %d active targets", count)
+	return nil
+}
+
+// Stop stops the delivery worker. Pending deliveries stay in the outbox and
+// are picked up again on the next start.
+func (o *WebhookOutbox) Stop() {
+	close(o.stopChan)
+	o.wg.Wait()
+}
+
+// ReloadTargets triggers a reload of the enabled webhook targets.
+func (o *WebhookOutbox) ReloadTargets() {
+	select {
+	case o.reloadChan <- struct{}{}:
+	default:
+		// Already a reload pending
+	}
+}
+
+// wake asks the worker to process due deliveries now rather than at the next poll.
+func (o *WebhookOutbox) wake() {
+	select {
+	case o.wakeChan <- struct{}{}:
+	default:
+	}
+}
+
+func (o *WebhookOutbox) worker() {
+	pollTicker := time.NewTicker(outboxPollInterval)
+	defer pollTicker.Stop()
+	cleanupTicker := time.NewTicker(1 * time.Hour)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-o.stopChan:
+			return
+		case <-o.reloadChan:
+			if err := o.loadTargets(); err != nil {
+				logger.Errorf("Failed to reload outgoing webhooks: %v", err)
+			}
+		case <-o.wakeChan:
+			o.processDue()
+		case <-pollTicker.C:
+			o.processDue()
+		case <-cleanupTicker.C:
+			o.cleanupOldDeliveries()
+		}
+	}
+}
+
+func (o *WebhookOutbox) loadTargets() error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT `+outgoingWebhookColumns+` FROM outgoing_webhooks WHERE enabled = 1`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	targets := make([]*OutgoingWebhook, 0)
+	for rows.Next() {
+		w, err := scanOutgoingWebhookRow(rows)
+		if err != nil {
+			logger.Errorf("Failed to scan outgoing webhook row: %v", err)
+			continue
+		}
+		targets = append(targets, w)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating outgoing webhooks: %w", err)
+	}
+
+	o.mu.Lock()
+	o.targets = targets
+	o.mu.Unlock()
+	return nil
+}
+
+// enqueue writes a delivery row for every enabled target subscribed to the event.
+func (o *WebhookOutbox) enqueue(ev domain.Event) {
+	o.mu.RLock()
+	var matched []*OutgoingWebhook
+	for _, w := range o.targets {
+		if w.subscribes(string(ev.EventType)) {
+			matched = append(matched, w)
+		}
+	}
+	o.mu.RUnlock()
+
+	if len(matched) == 0 {
+		return
+	}
+
+	data := ev.EventData
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	occurredAt := ev.CreatedAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(WebhookPayload{
+		EventID:       ev.ID,
+		EventType:     string(ev.EventType),
+		AggregateType: ev.AggregateType,
+		AggregateID:   ev.AggregateID,
+		Data:          data,
+		OccurredAt:    occurredAt,
+	})
+	if err != nil {
+		logger.Errorf("Failed to marshal webhook payload for %s: %v", ev.EventType, err)
+		return
+	}
+
+	var eventID interface{}
+	if ev.ID > 0 {
+		eventID = ev.ID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	for _, w := range matched {
+		if _, err := o.db.ExecContext(ctx, `
+			INSERT INTO outgoing_webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+			VALUES (?, ?, ?, ?, ?, datetime('now'))
+		`, w.ID, eventID, string(ev.EventType), string(payload), DeliveryPending); err != nil {
+			logger.Errorf("Failed to queue webhook delivery for %s to webhook %d: %v", ev.EventType, w.ID, err)
+		}
+	}
+	o.wake()
+}
+
+// dueDelivery is a pending delivery joined with its target.
+type dueDelivery struct {
+	id          int64
+	eventType   string
+	payload     []byte
+	attempts    int
+	url         string
+	secret      string
+	maxAttempts int
+}
+
+// processDue attempts every pending delivery whose next attempt is due.
+func (o *WebhookOutbox) processDue() {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx, `
+		SELECT d.id, d.event_type, d.payload, d.attempts, w.url, w.secret, w.max_attempts
+		FROM outgoing_webhook_deliveries d
+		JOIN outgoing_webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= datetime('now') AND w.enabled = 1
+		ORDER BY d.id
+		LIMIT ?
+	`, DeliveryPending, outboxBatchSize)
+	if err != nil {
+		logger.Errorf("Failed to query due webhook deliveries: %v", err)
+		return
+	}
+
+	var due []dueDelivery
+	for rows.Next() {
+		var d dueDelivery
+		var payload string
+		if err := rows.Scan(&d.id, &d.eventType, &payload, &d.attempts, &d.url, &d.secret, &d.maxAttempts); err != nil {
+			logger.Errorf("Failed to scan webhook delivery: %v", err)
+			continue
+		}
+		d.payload = []byte(payload)
+		due = append(due, d)
+	}
+	iterErr := rows.Err()
+	rows.Close()
+	if iterErr != nil {
+		logger.Errorf("Error iterating webhook deliveries: %v", iterErr)
+	}
+
+	for _, d := range due {
+		select {
+		case <-o.stopChan:
+			return
+		default:
+		}
+		secret, err := crypto.Decrypt(d.secret)
+		if err != nil {
+			o.recordAttempt(d, 0, fmt.Errorf("failed to decrypt webhook secret: %w", err))
+			continue
+		}
+		redact.Register(secret)
+		code, err := o.deliver(d.url, secret, d.id, d.eventType, d.payload)
+		o.recordAttempt(d, code, err)
+	}
+}
+
+// deliver POSTs a signed payload and returns the response status code.
+func (o *WebhookOutbox) deliver(url, secret string, deliveryID int64, eventType string, body []byte) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Healarr/1.0")
+	req.Header.Set(HeaderWebhookEvent, eventType)
+	req.Header.Set(HeaderWebhookDelivery, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	if secret != "" {
+		req.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhookPayload(secret, timestamp, body))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, outboxMaxResponseBody))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// deliveryBackoff returns the delay before the next attempt after the given
+// number of failed attempts: 30s, 1m, 2m, ... capped at one hour.
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// recordAttempt stores the outcome of a delivery attempt and schedules a retry
+// or marks the delivery as permanently failed.
+func (o *WebhookOutbox) recordAttempt(d dueDelivery, code int, deliverErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	var responseCode interface{}
+	if code > 0 {
+		responseCode = code
+	}
+	attempts := d.attempts + 1
+
+	var err error
+	switch {
+	case deliverErr == nil:
+		_, err = o.db.ExecContext(ctx, `
+			UPDATE outgoing_webhook_deliveries
+			SET status = ?, attempts = ?, response_code = ?, last_error = NULL,
+				last_attempt_at = datetime('now'), delivered_at = datetime('now'), next_attempt_at = NULL
+			WHERE id = ?
+		`, DeliveryDelivered, attempts, responseCode, d.id)
+	case attempts >= d.maxAttempts:
+		logger.Warnf("Webhook delivery %d (%s) to %s failed permanently after %d attempts: %v",
+			d.id, d.eventType, d.url, attempts, deliverErr)
+		_, err = o.db.ExecContext(ctx, `
+			UPDATE outgoing_webhook_deliveries
+			SET status = ?, attempts = ?, response_code = ?, last_error = ?,
+				last_attempt_at = datetime('now'), next_attempt_at = NULL
+			WHERE id = ?
+		`, DeliveryFailed, attempts, responseCode, deliverErr.Error(), d.id)
+	default:
+		backoff := deliveryBackoff(attempts)
+		logger.Debugf("Webhook delivery %d (%s) failed (attempt %d/%d), retrying in %v: %v",
+			d.id, d.eventType, attempts, d.maxAttempts, backoff, deliverErr)
+		_, err = o.db.ExecContext(ctx, `
+			UPDATE outgoing_webhook_deliveries
+			SET attempts = ?, response_code = ?, last_error = ?,
+				last_attempt_at = datetime('now'), next_attempt_at = datetime('now', ?)
+			WHERE id = ?
+		`, attempts, responseCode, deliverErr.Error(), fmt.Sprintf("+%d seconds", int(backoff.Seconds())), d.id)
+	}
+	if err != nil {
+		logger.Errorf("Failed to record webhook delivery %d: %v", d.id, err)
+	}
+}
+
+func (o *WebhookOutbox) cleanupOldDeliveries() {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	// Keep finished deliveries for 7 days; pending ones are never removed here
+	result, err := o.db.ExecContext(ctx, `
+		DELETE FROM outgoing_webhook_deliveries
+		WHERE status != ? AND created_at < datetime('now', '-7 days')
+	`, DeliveryPending)
+	if err != nil {
+		logger.Errorf("Failed to cleanup webhook deliveries: %v", err)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		logger.Infof("Cleaned up %d old webhook delivery entries", rows)
+	}
+}
+
+// scanOutgoingWebhookRow scans an outgoing webhook and decrypts its secret.
+func scanOutgoingWebhookRow(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*OutgoingWebhook, error) {
+	var w OutgoingWebhook
+	var secret, eventsJSON string
+	if err := scanner.Scan(&w.ID, &w.Name, &w.URL, &secret, &eventsJSON, &w.Enabled, &w.MaxAttempts, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	decrypted, err := crypto.Decrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret for outgoing webhook %d: %w", w.ID, err)
+	}
+	w.Secret = decrypted
+	redact.Register(decrypted)
+	if json.Unmarshal([]byte(eventsJSON), &w.Events) != nil {
+		w.Events = []string{}
+	}
+	return &w, nil
+}
+
+// GetAllWebhooks returns every outgoing webhook, enabled or not.
+func (o *WebhookOutbox) GetAllWebhooks() ([]*OutgoingWebhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT `+outgoingWebhookColumns+` FROM outgoing_webhooks ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*OutgoingWebhook, 0)
+	for rows.Next() {
+		w, err := scanOutgoingWebhookRow(rows)
+		if err != nil {
+			logger.Errorf("Failed to scan outgoing webhook row: %v", err)
+			continue
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outgoing webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a single outgoing webhook.
+func (o *WebhookOutbox) GetWebhook(id int64) (*OutgoingWebhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	w, err := scanOutgoingWebhookRow(o.db.QueryRowContext(ctx,
+		`SELECT `+outgoingWebhookColumns+` FROM outgoing_webhooks WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	return w, err
+}
+
+// CreateWebhook stores a new outgoing webhook. The secret is encrypted at rest.
+func (o *WebhookOutbox) CreateWebhook(w *OutgoingWebhook) (int64, error) {
+	eventsJSON, err := json.Marshal(w.Events)
+	if err != nil {
+		return 0, err
+	}
+	encryptedSecret, err := crypto.Encrypt(w.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	result, err := o.db.ExecContext(ctx, `
+		INSERT INTO outgoing_webhooks (name, url, secret, events, enabled, max_attempts)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, w.Name, w.URL, encryptedSecret, string(eventsJSON), w.Enabled, w.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	o.ReloadTargets()
+	return id, nil
+}
+
+// UpdateWebhook updates an existing outgoing webhook.
+func (o *WebhookOutbox) UpdateWebhook(w *OutgoingWebhook) error {
+	eventsJSON, err := json.Marshal(w.Events)
+	if err != nil {
+		return err
+	}
+	encryptedSecret, err := crypto.Encrypt(w.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	result, err := o.db.ExecContext(ctx, `
+		UPDATE outgoing_webhooks
+		SET name = ?, url = ?, secret = ?, events = ?, enabled = ?, max_attempts = ?, updated_at = datetime('now')
+		WHERE id = ?
+	`, w.Name, w.URL, encryptedSecret, string(eventsJSON), w.Enabled, w.MaxAttempts, w.ID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrWebhookNotFound
+	}
+
+	o.ReloadTargets()
+	return nil
+}
+
+// DeleteWebhook removes an outgoing webhook and its delivery history.
+func (o *WebhookOutbox) DeleteWebhook(id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	result, err := o.db.ExecContext(ctx, `DELETE FROM outgoing_webhooks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrWebhookNotFound
+	}
+
+	if _, delErr := o.db.ExecContext(ctx, `DELETE FROM outgoing_webhook_deliveries WHERE webhook_id = ?`, id); delErr != nil {
+		logger.Warnf("Failed to cleanup webhook deliveries for id=%d: %v", id, delErr)
+	}
+
+	o.ReloadTargets()
+	return nil
+}
+
+// GetDeliveries returns recent deliveries, newest first. A webhookID of 0
+// returns deliveries for all webhooks; an empty status returns every status.
+func (o *WebhookOutbox) GetDeliveries(webhookID int64, status string, limit int) ([]WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + deliveryColumns + ` FROM outgoing_webhook_deliveries WHERE 1=1`
+	args := []interface{}{}
+	if webhookID > 0 {
+		query += ` AND webhook_id = ?`
+		args = append(args, webhookID)
+	}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := o.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]WebhookDelivery, 0)
+	for rows.Next() {
+		var d WebhookDelivery
+		var eventID, responseCode sql.NullInt64
+		var payload string
+		var lastError, nextAttempt, lastAttempt, deliveredAt sql.NullString
+		if err := rows.Scan(&d.ID, &d.WebhookID, &eventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&responseCode, &lastError, &nextAttempt, &lastAttempt, &deliveredAt, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		if eventID.Valid {
+			d.EventID = &eventID.Int64
+		}
+		if responseCode.Valid {
+			code := int(responseCode.Int64)
+			d.ResponseCode = &code
+		}
+		d.Payload = json.RawMessage(payload)
+		d.LastError = lastError.String
+		d.NextAttemptAt = nullStringPtr(nextAttempt)
+		d.LastAttemptAt = nullStringPtr(lastAttempt)
+		d.DeliveredAt = nullStringPtr(deliveredAt)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery re-queues a delivery for immediate delivery, resetting its
+// attempt count. Useful after fixing a target that exhausted its retries.
+func (o *WebhookOutbox) RetryDelivery(id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierQueryTimeout)
+	defer cancel()
+
+	result, err := o.db.ExecContext(ctx, `
+		UPDATE outgoing_webhook_deliveries
+		SET status = ?, attempts = 0, next_attempt_at = datetime('now'), delivered_at = NULL
+		WHERE id = ?
+	`, DeliveryPending, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrWebhookNotFound
+	}
+
+	o.wake()
+	return nil
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
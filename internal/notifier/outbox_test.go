@@ -0,0 +1,269 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+)
+
+// newOutboxTestDB returns a notifier test DB with the outgoing webhook tables.
+func newOutboxTestDB(t *testing.T) *testDB {
+	t.Helper()
+
+	tdb := newTestDB(t)
+	schema := `
+		CREATE TABLE outgoing_webhooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT 1,
+			max_attempts INTEGER DEFAULT 5,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE outgoing_webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			webhook_id INTEGER NOT NULL,
+			event_id INTEGER,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			response_code INTEGER,
+			last_error TEXT,
+			next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_attempt_at TIMESTAMP,
+			delivered_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`
+	if _, err := tdb.DB.Exec(schema); err != nil {
+		t.Fatalf("Failed to create outbox schema: %v", err)
+	}
+	return tdb
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	sig := SignWebhookPayload("secret", "1700000000", []byte(`{"a":1}`))
+	if len(sig) != 64 {
+		t.Fatalf("Expected 64 hex chars, got %d", len(sig))
+	}
+	if sig != SignWebhookPayload("secret", "1700000000", []byte(`{"a":1}`)) {
+		t.Error("Signature should be deterministic")
+	}
+	if sig == SignWebhookPayload("other", "1700000000", []byte(`{"a":1}`)) {
+		t.Error("Signature should depend on the secret")
+	}
+	if sig == SignWebhookPayload("secret", "1700000001", []byte(`{"a":1}`)) {
+		t.Error("Signature should depend on the timestamp")
+	}
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := deliveryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("deliveryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookOutbox_EnqueueAndDeliver(t *testing.T) {
+	tdb := newOutboxTestDB(t)
+	defer tdb.Close()
+
+	var gotBody []byte
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+	o := NewWebhookOutbox(tdb.DB, eb)
+
+	id, err := o.CreateWebhook(&OutgoingWebhook{
+		Name: "automation", URL: server.URL, Secret: "s3cret",
+		Events: []string{string(domain.CorruptionDetected)}, Enabled: true, MaxAttempts: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if err := o.loadTargets(); err != nil {
+		t.Fatalf("loadTargets failed: %v", err)
+	}
+
+	// Unsubscribed event types are not queued
+	o.enqueue(domain.Event{EventType: domain.ScanStarted})
+	o.enqueue(domain.Event{
+		ID: 42, EventType: domain.CorruptionDetected, AggregateType: "corruption", AggregateID: "agg-1",
+		EventData: map[string]interface{}{"file_path": "/media/movie.mkv"},
+	})
+
+	deliveries, err := o.GetDeliveries(id, "", 10)
+	if err != nil {
+		t.Fatalf("GetDeliveries failed: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != DeliveryPending {
+		t.Fatalf("Expected one pending delivery, got %+v", deliveries)
+	}
+
+	o.processDue()
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("Invalid payload %q: %v", gotBody, err)
+	}
+	if payload.EventID != 42 || payload.AggregateID != "agg-1" || payload.Data["file_path"] != "/media/movie.mkv" {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if gotHeaders.Get(HeaderWebhookEvent) != string(domain.CorruptionDetected) {
+		t.Errorf("Event header = %q", gotHeaders.Get(HeaderWebhookEvent))
+	}
+	wantSig := "sha256=" + SignWebhookPayload("s3cret", gotHeaders.Get(HeaderWebhookTimestamp), gotBody)
+	if gotHeaders.Get(HeaderWebhookSignature) != wantSig {
+		t.Errorf("Signature header = %q, want %q", gotHeaders.Get(HeaderWebhookSignature), wantSig)
+	}
+
+	deliveries, _ = o.GetDeliveries(id, "", 10)
+	d := deliveries[0]
+	if d.Status != DeliveryDelivered || d.Attempts != 1 || d.ResponseCode == nil || *d.ResponseCode != http.StatusNoContent {
+		t.Errorf("Unexpected delivery after success: %+v", d)
+	}
+	if d.DeliveredAt == nil || d.NextAttemptAt != nil {
+		t.Errorf("Expected delivered_at set and next_attempt_at cleared: %+v", d)
+	}
+}
+
+func TestWebhookOutbox_RetriesThenFails(t *testing.T) {
+	tdb := newOutboxTestDB(t)
+	defer tdb.Close()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+	o := NewWebhookOutbox(tdb.DB, eb)
+
+	id, err := o.CreateWebhook(&OutgoingWebhook{
+		Name: "flaky", URL: server.URL, Events: []string{string(domain.ScanCompleted)}, Enabled: true, MaxAttempts: 2,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if err := o.loadTargets(); err != nil {
+		t.Fatalf("loadTargets failed: %v", err)
+	}
+	o.enqueue(domain.Event{EventType: domain.ScanCompleted})
+
+	o.processDue()
+	deliveries, _ := o.GetDeliveries(id, DeliveryPending, 10)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 {
+		t.Fatalf("Expected pending delivery with 1 attempt, got %+v", deliveries)
+	}
+	if deliveries[0].LastError == "" || deliveries[0].NextAttemptAt == nil {
+		t.Errorf("Expected last_error and a scheduled retry: %+v", deliveries[0])
+	}
+
+	// Not due yet: backoff must be respected
+	o.processDue()
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("Expected retry to wait for backoff, got %d calls", got)
+	}
+
+	if _, err := tdb.DB.Exec(`UPDATE outgoing_webhook_deliveries SET next_attempt_at = datetime('now', '-1 seconds')`); err != nil {
+		t.Fatal(err)
+	}
+	o.processDue()
+
+	failed, _ := o.GetDeliveries(id, DeliveryFailed, 10)
+	if len(failed) != 1 || failed[0].Attempts != 2 {
+		t.Fatalf("Expected delivery to fail after max attempts, got %+v", failed)
+	}
+
+	// Manual retry re-queues it
+	if err := o.RetryDelivery(failed[0].ID); err != nil {
+		t.Fatalf("RetryDelivery failed: %v", err)
+	}
+	pending, _ := o.GetDeliveries(id, DeliveryPending, 10)
+	if len(pending) != 1 || pending[0].Attempts != 0 {
+		t.Errorf("Expected re-queued delivery with attempts reset, got %+v", pending)
+	}
+	if err := o.RetryDelivery(9999); err != ErrWebhookNotFound {
+		t.Errorf("RetryDelivery(missing) = %v, want ErrWebhookNotFound", err)
+	}
+}
+
+func TestWebhookOutbox_CRUD(t *testing.T) {
+	tdb := newOutboxTestDB(t)
+	defer tdb.Close()
+
+	eb := eventbus.NewEventBus(tdb.DB)
+	defer eb.Shutdown()
+	o := NewWebhookOutbox(tdb.DB, eb)
+
+	id, err := o.CreateWebhook(&OutgoingWebhook{
+		Name: "a", URL: "http://example.invalid", Secret: "x", Events: []string{"ScanStarted"}, Enabled: true, MaxAttempts: 5,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	w, err := o.GetWebhook(id)
+	if err != nil || w.Secret != "x" || len(w.Events) != 1 {
+		t.Fatalf("GetWebhook = %+v, %v", w, err)
+	}
+
+	w.Name = "renamed"
+	w.Enabled = false
+	if err := o.UpdateWebhook(w); err != nil {
+		t.Fatalf("UpdateWebhook failed: %v", err)
+	}
+	if err := o.loadTargets(); err != nil {
+		t.Fatal(err)
+	}
+	if len(o.targets) != 0 {
+		t.Error("Disabled webhooks should not be loaded as active targets")
+	}
+
+	all, err := o.GetAllWebhooks()
+	if err != nil || len(all) != 1 || all[0].Name != "renamed" {
+		t.Fatalf("GetAllWebhooks = %+v, %v", all, err)
+	}
+
+	if err := o.DeleteWebhook(id); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	if _, err := o.GetWebhook(id); err != ErrWebhookNotFound {
+		t.Errorf("GetWebhook after delete = %v, want ErrWebhookNotFound", err)
+	}
+	if err := o.DeleteWebhook(id); err != ErrWebhookNotFound {
+		t.Errorf("DeleteWebhook(missing) = %v, want ErrWebhookNotFound", err)
+	}
+}
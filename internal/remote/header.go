@@ -0,0 +1,259 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/integration"
+)
+
+// headerSize is how much of a file is read for the header check.
+const headerSize = 64 << 10
+
+// mp4Extensions are checked by walking their boxes instead of reading a header.
+var mp4Extensions = map[string]bool{".mp4": true, ".m4v": true, ".m4a": true, ".m4b": true, ".mov": true}
+
+// mp4FirstBoxes are the boxes an MP4 file can start with.
+var mp4FirstBoxes = map[string]bool{"ftyp": true, "styp": true, "moov": true, "mdat": true, "free": true, "skip": true, "wide": true, "pdin": true}
+
+// tsSyncPackets is how many transport stream packets must start with a sync byte.
+const tsSyncPackets = 8
+
+// maxMP4Boxes bounds the top-level boxes walked in an MP4 file. Each box
+// costs one range request.
+const maxMP4Boxes = 64
+
+// CheckFile checks the header and, for containers that record their length,
+// the top-level structure of a remote file. Truncated files are the most
+// common corruption that shows this way. It returns the same errors as a
+// local HealthChecker, so corruption and unreachable servers are told apart.
+func CheckFile(ctx context.Context, b Backend, e Entry) (bool, *integration.HealthCheckError) {
+	if e.Size == 0 {
+		return false, &integration.HealthCheckError{Type: integration.ErrorTypeZeroByte, Message: "File is empty (0 bytes)"}
+	}
+	ext := strings.ToLower(path.Ext(e.Path))
+	if mp4Extensions[ext] {
+		return checkMP4(ctx, b, e)
+	}
+
+	header := make([]byte, min(e.Size, headerSize))
+	n, err := b.ReadAt(ctx, e.Path, header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, AccessError(err)
+	}
+	header = header[:n]
+	if int64(n) < int64(len(header)) {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "Server returned %d of %d header bytes", n, len(header))
+	}
+
+	switch ext {
+	case ".mkv", ".mka", ".webm", ".mk3d":
+		return checkMatroska(header, e.Size)
+	case ".avi":
+		return checkRIFF(header, e.Size, "AVI ")
+	case ".wav":
+		return checkRIFF(header, e.Size, "WAVE")
+	case ".flac":
+		return checkMagic(header, "fLaC")
+	case ".ogg", ".oga", ".opus":
+		return checkMagic(header, "OggS")
+	case ".mp3":
+		return checkMP3(header)
+	}
+	if integration.IsTransportStream(e.Path) {
+		return checkTransportStream(header)
+	}
+	// Other formats have no header check; they pass
+	return true, nil
+}
+
+// AccessError classifies a failed request like an unreachable local file.
+func AccessError(err error) *integration.HealthCheckError {
+	errType := integration.ErrorTypeIOError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		errType = integration.ErrorTypePathNotFound
+	case errors.Is(err, ErrAccessDenied), errors.Is(err, ErrUnknownHostKey):
+		errType = integration.ErrorTypeAccessDenied
+	case errors.Is(err, context.DeadlineExceeded):
+		errType = integration.ErrorTypeTimeout
+	case errors.Is(err, ErrRangeUnsupported):
+		errType = integration.ErrorTypeInvalidConfig
+	}
+	return &integration.HealthCheckError{Type: errType, Message: err.Error()}
+}
+
+func corrupt(errType, format string, args ...interface{}) *integration.HealthCheckError {
+	return &integration.HealthCheckError{Type: errType, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkMagic(header []byte, magic string) (bool, *integration.HealthCheckError) {
+	if !bytes.HasPrefix(header, []byte(magic)) {
+		return false, corrupt(integration.ErrorTypeInvalidFormat, "Missing %q signature", magic)
+	}
+	return true, nil
+}
+
+// checkMatroska checks the EBML signature and that the Segment fits in the file.
+func checkMatroska(header []byte, size int64) (bool, *integration.HealthCheckError) {
+	if !bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+		return false, corrupt(integration.ErrorTypeInvalidFormat, "Missing EBML signature")
+	}
+	// Skip the EBML header element
+	pos := 4
+	ebmlSize, n, ok := readVint(header[pos:])
+	if !ok || ebmlSize > uint64(len(header)) {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "Invalid EBML header size")
+	}
+	pos += n + int(ebmlSize)
+	if pos+4 > len(header) {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "EBML header is larger than the file header")
+	}
+	if !bytes.Equal(header[pos:pos+4], []byte{0x18, 0x53, 0x80, 0x67}) {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "No Segment after the EBML header")
+	}
+	pos += 4
+	segmentSize, n, ok := readVint(header[pos:])
+	if !ok {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "Invalid Segment size")
+	}
+	pos += n
+	// All ones means unknown size, e.g. a live recording
+	if segmentSize != unknownVint(n) && int64(pos)+int64(segmentSize) > size {
+		return false, corrupt(integration.ErrorTypeCorruptStream,
+			"File is truncated: Segment needs %d bytes, file has %d", int64(pos)+int64(segmentSize), size)
+	}
+	return true, nil
+}
+
+// readVint decodes an EBML variable-size integer and returns its length.
+func readVint(b []byte) (uint64, int, bool) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, false
+	}
+	length := 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if len(b) < length {
+		return 0, 0, false
+	}
+	value := uint64(b[0] & (0xFF >> length))
+	for _, c := range b[1:length] {
+		value = value<<8 | uint64(c)
+	}
+	return value, length, true
+}
+
+// unknownVint is the "unknown size" value of an EBML vint of the given length.
+func unknownVint(length int) uint64 {
+	return 1<<(7*length) - 1
+}
+
+// checkMP4 walks the top-level boxes of an ISO BMFF file: every box must fit
+// in the file and a moov box must be present.
+func checkMP4(ctx context.Context, b Backend, e Entry) (bool, *integration.HealthCheckError) {
+	var off int64
+	sawMoov := false
+	header := make([]byte, 16)
+	for i := 0; off < e.Size; i++ {
+		if i == maxMP4Boxes {
+			// Fragmented files have many boxes; the start was fine
+			return true, nil
+		}
+		n, err := b.ReadAt(ctx, e.Path, header[:min(16, e.Size-off)], off)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, AccessError(err)
+		}
+		if n < 8 {
+			return false, corrupt(integration.ErrorTypeCorruptStream, "File is truncated inside a box header at byte %d", off)
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header))
+		boxType := string(header[4:8])
+		switch boxSize {
+		case 0:
+			boxSize = e.Size - off
+		case 1:
+			if n < 16 {
+				return false, corrupt(integration.ErrorTypeCorruptStream, "File is truncated inside a box header at byte %d", off)
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:]))
+		}
+		if i == 0 && !mp4FirstBoxes[boxType] {
+			return false, corrupt(integration.ErrorTypeInvalidFormat, "Not an MP4 file: invalid first box %q", boxType)
+		}
+		if boxSize < 8 || !printableBoxType(boxType) {
+			if i == 0 {
+				return false, corrupt(integration.ErrorTypeInvalidFormat, "Not an MP4 file: invalid first box")
+			}
+			return false, corrupt(integration.ErrorTypeCorruptHeader, "Invalid box %q of %d bytes at byte %d", boxType, boxSize, off)
+		}
+		if boxSize > e.Size-off {
+			return false, corrupt(integration.ErrorTypeCorruptStream,
+				"File is truncated: box %q needs %d bytes, file has %d", boxType, off+boxSize, e.Size)
+		}
+		if boxType == "moov" {
+			sawMoov = true
+		}
+		off += boxSize
+	}
+	if !sawMoov {
+		return false, corrupt(integration.ErrorTypeCorruptHeader, "No moov box: the file can't be played")
+	}
+	return true, nil
+}
+
+func printableBoxType(t string) bool {
+	for i := 0; i < len(t); i++ {
+		if t[i] < 0x20 || t[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRIFF checks a RIFF signature and form type and that the first RIFF
+// chunk fits in the file.
+func checkRIFF(header []byte, size int64, form string) (bool, *integration.HealthCheckError) {
+	if len(header) < 12 || string(header[:4]) != "RIFF" || string(header[8:12]) != form {
+		return false, corrupt(integration.ErrorTypeInvalidFormat, "Missing RIFF %q signature", form)
+	}
+	chunkSize := int64(binary.LittleEndian.Uint32(header[4:8])) + 8
+	if chunkSize > size {
+		return false, corrupt(integration.ErrorTypeCorruptStream, "File is truncated: RIFF chunk needs %d bytes, file has %d", chunkSize, size)
+	}
+	return true, nil
+}
+
+// checkMP3 accepts an ID3 tag or an MPEG audio frame sync at the start.
+func checkMP3(header []byte) (bool, *integration.HealthCheckError) {
+	if bytes.HasPrefix(header, []byte("ID3")) || (len(header) > 1 && header[0] == 0xFF && header[1]&0xE0 == 0xE0) {
+		return true, nil
+	}
+	return false, corrupt(integration.ErrorTypeInvalidFormat, "Missing ID3 tag or MPEG frame sync")
+}
+
+// checkTransportStream looks for the 0x47 sync byte at the first packets of
+// the header, in 188-byte TS or 192-byte M2TS packets.
+func checkTransportStream(header []byte) (bool, *integration.HealthCheckError) {
+	for _, layout := range []struct{ size, offset int }{{188, 0}, {192, 4}} {
+		packets := min((len(header)-layout.offset)/layout.size, tsSyncPackets)
+		if packets == 0 {
+			continue
+		}
+		synced := true
+		for i := 0; i < packets && synced; i++ {
+			synced = header[i*layout.size+layout.offset] == 0x47
+		}
+		if synced {
+			return true, nil
+		}
+	}
+	return false, corrupt(integration.ErrorTypeCorruptHeader, "No MPEG-TS packet sync in the first %d packets", tsSyncPackets)
+}
@@ -0,0 +1,163 @@
+package remote
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+)
+
+// memBackend serves files from memory and counts reads.
+type memBackend struct {
+	files map[string][]byte
+	reads int
+}
+
+func (m *memBackend) List(context.Context, string) ([]Entry, error) { return nil, nil }
+
+func (m *memBackend) Stat(_ context.Context, name string) (Entry, error) {
+	data, ok := m.files[name]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return Entry{Path: name, Size: int64(len(data))}, nil
+}
+
+func (m *memBackend) ReadAt(_ context.Context, name string, p []byte, off int64) (int, error) {
+	m.reads++
+	data, ok := m.files[name]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if off >= int64(len(data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *memBackend) Close() error { return nil }
+
+// matroska builds an EBML header followed by a Segment of segmentSize bytes,
+// of which only present bytes are in the file.
+func matroska(segmentSize uint32, present int) []byte {
+	data := []byte{0x1A, 0x45, 0xDF, 0xA3, 0x84, 'w', 'e', 'b', 'm'}
+	data = append(data, 0x18, 0x53, 0x80, 0x67, 0x08)
+	data = binary.BigEndian.AppendUint64(data[:len(data)-1], uint64(segmentSize)|0x01<<56)
+	return append(data, make([]byte, present)...)
+}
+
+func box(boxType string, size int) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(size))
+	b = append(b, boxType...)
+	return append(b, make([]byte, size-8)...)
+}
+
+func riff(form string, chunkSize uint32, present int) []byte {
+	data := []byte("RIFF")
+	data = binary.LittleEndian.AppendUint32(data, chunkSize)
+	data = append(data, form...)
+	return append(data, make([]byte, present)...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func TestCheckFile(t *testing.T) {
+	mp4 := concat(box("ftyp", 24), box("moov", 100), box("mdat", 1000))
+	tests := []struct {
+		name     string
+		data     []byte
+		healthy  bool
+		errType  string
+		contains string
+	}{
+		{"movie.mkv", matroska(100, 100), true, "", ""},
+		{"movie.mkv", matroska(1000, 100), false, integration.ErrorTypeCorruptStream, "truncated"},
+		{"movie.mkv", []byte("not a matroska file"), false, integration.ErrorTypeInvalidFormat, "EBML"},
+		{"movie.mp4", mp4, true, "", ""},
+		{"movie.mp4", mp4[:len(mp4)-10], false, integration.ErrorTypeCorruptStream, `box "mdat"`},
+		{"movie.mp4", concat(box("ftyp", 24), box("mdat", 1000)), false, integration.ErrorTypeCorruptHeader, "moov"},
+		{"movie.mp4", []byte("garbage garbage garbage"), false, integration.ErrorTypeInvalidFormat, "first box"},
+		{"movie.avi", riff("AVI ", 1004, 1000), true, "", ""},
+		{"movie.avi", riff("AVI ", 5000, 1000), false, integration.ErrorTypeCorruptStream, "RIFF"},
+		{"track.flac", []byte("fLaC rest"), true, "", ""},
+		{"track.mp3", []byte("ID3\x04\x00"), true, "", ""},
+		{"track.mp3", []byte("\x00\x00\x00\x00"), false, integration.ErrorTypeInvalidFormat, ""},
+		{"empty.mkv", []byte{}, false, integration.ErrorTypeZeroByte, ""},
+		{"movie.wmv", []byte("no header check for wmv"), true, "", ""},
+	}
+	for _, tt := range tests {
+		b := &memBackend{files: map[string][]byte{tt.name: tt.data}}
+		e, _ := b.Stat(context.Background(), tt.name)
+		healthy, healthErr := CheckFile(context.Background(), b, e)
+		if healthy != tt.healthy {
+			t.Errorf("%s (%d bytes): expected healthy=%v, got %v (%v)", tt.name, len(tt.data), tt.healthy, healthy, healthErr)
+			continue
+		}
+		if tt.healthy {
+			continue
+		}
+		if healthErr == nil || healthErr.Type != tt.errType || !strings.Contains(healthErr.Message, tt.contains) {
+			t.Errorf("%s (%d bytes): expected %s containing %q, got %+v", tt.name, len(tt.data), tt.errType, tt.contains, healthErr)
+		}
+	}
+}
+
+func TestCheckFile_TransportStream(t *testing.T) {
+	packets := make([]byte, 188*10)
+	for i := 0; i < 10; i++ {
+		packets[i*188] = 0x47
+	}
+	b := &memBackend{files: map[string][]byte{"rec.ts": packets}}
+	if healthy, healthErr := CheckFile(context.Background(), b, Entry{Path: "rec.ts", Size: int64(len(packets))}); !healthy {
+		t.Errorf("Expected synced packets to pass, got %v", healthErr)
+	}
+
+	packets[188*3] = 0
+	if healthy, _ := CheckFile(context.Background(), b, Entry{Path: "rec.ts", Size: int64(len(packets))}); healthy {
+		t.Error("Expected a lost sync byte to fail")
+	}
+}
+
+func TestCheckFile_ReadsOnlyHeaders(t *testing.T) {
+	b := &memBackend{files: map[string][]byte{"movie.mkv": matroska(1<<20, 1<<20)}}
+	e, _ := b.Stat(context.Background(), "movie.mkv")
+	if healthy, healthErr := CheckFile(context.Background(), b, e); !healthy {
+		t.Fatalf("Expected a healthy file, got %v", healthErr)
+	}
+	if b.reads != 1 {
+		t.Errorf("Expected a single header read, got %d", b.reads)
+	}
+}
+
+func TestAccessError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrNotFound, integration.ErrorTypePathNotFound},
+		{ErrAccessDenied, integration.ErrorTypeAccessDenied},
+		{ErrUnknownHostKey, integration.ErrorTypeAccessDenied},
+		{context.DeadlineExceeded, integration.ErrorTypeTimeout},
+		{ErrRangeUnsupported, integration.ErrorTypeInvalidConfig},
+		{errors.New("connection reset"), integration.ErrorTypeIOError},
+	}
+	for _, tt := range tests {
+		if got := AccessError(tt.err); got.Type != tt.want || !got.IsRecoverable() {
+			t.Errorf("AccessError(%v) = %s (recoverable %v), want recoverable %s", tt.err, got.Type, got.IsRecoverable(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package remote
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/crypto"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/redact"
+)
+
+// DetectionHeader is the detection method reported for remote header checks.
+const DetectionHeader integration.DetectionMethod = "remote_header"
+
+// registryTTL is how long the registry keeps the scan paths before reloading them.
+const registryTTL = time.Minute
+
+// Path is a scan path whose files are on a remote server. The local path of
+// a remote file is LocalPath joined with its name below the remote root, so
+// path mappings, corruptions and *arr lookups work as for local paths.
+type Path struct {
+	ID        int64
+	LocalPath string
+	Config    Config
+}
+
+// Rel returns the name below the remote root of the file at localPath.
+func (p Path) Rel(localPath string) (string, bool) {
+	if localPath == p.LocalPath {
+		return "", true
+	}
+	root := strings.TrimSuffix(p.LocalPath, "/") + "/"
+	if !strings.HasPrefix(localPath, root) {
+		return "", false
+	}
+	return strings.TrimPrefix(localPath, root), true
+}
+
+// Local returns the local path of the remote file name.
+func (p Path) Local(name string) string {
+	return path.Join(p.LocalPath, name)
+}
+
+const remotePathColumns = `id, local_path, remote_url, remote_username, remote_password, remote_host_key_fingerprint, remote_requests_per_minute`
+
+// LoadPath loads the remote configuration of a scan path. ok is false when
+// the path is local.
+func LoadPath(ctx context.Context, db *sql.DB, pathID int64) (Path, bool, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+remotePathColumns+` FROM scan_paths WHERE id = ? AND remote_url != ''`, pathID)
+	p, err := scanPath(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Path{}, false, nil
+	}
+	if err != nil {
+		return Path{}, false, err
+	}
+	return p, true, nil
+}
+
+func scanPath(row interface{ Scan(...any) error }) (Path, error) {
+	var p Path
+	var password string
+	if err := row.Scan(&p.ID, &p.LocalPath, &p.Config.URL, &p.Config.Username, &password,
+		&p.Config.HostKeyFingerprint, &p.Config.RequestsPerMinute); err != nil {
+		return Path{}, err
+	}
+	if password != "" {
+		decrypted, err := crypto.Decrypt(password)
+		if err != nil {
+			return Path{}, fmt.Errorf("failed to decrypt password of remote path %d: %w", p.ID, err)
+		}
+		p.Config.Password = decrypted
+		redact.Register(decrypted)
+	}
+	return p, nil
+}
+
+// Registry finds the remote scan path a local path belongs to. It reloads
+// the scan paths at most once a minute.
+type Registry struct {
+	db *sql.DB
+
+	mu       sync.Mutex
+	paths    []Path
+	loadedAt time.Time
+}
+
+// NewRegistry returns a registry of the remote scan paths in db.
+func NewRegistry(db *sql.DB) *Registry {
+	return &Registry{db: db}
+}
+
+// Lookup returns the remote scan path localPath is in and its name below the
+// remote root. ok is false for local files.
+func (r *Registry) Lookup(localPath string) (Path, string, bool) {
+	if r == nil {
+		return Path{}, "", false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.loadedAt) > registryTTL {
+		r.reload()
+	}
+
+	var best Path
+	var bestRel string
+	found := false
+	for _, p := range r.paths {
+		if rel, ok := p.Rel(localPath); ok && len(p.LocalPath) > len(best.LocalPath) {
+			best, bestRel, found = p, rel, true
+		}
+	}
+	return best, bestRel, found
+}
+
+// Invalidate makes the next lookup reload the scan paths.
+func (r *Registry) Invalidate() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+}
+
+// reload loads the remote scan paths. Must be called with r.mu held.
+func (r *Registry) reload() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `SELECT `+remotePathColumns+` FROM scan_paths
+		WHERE remote_url != '' AND enabled = 1 AND deleted_at IS NULL`)
+	if err != nil {
+		logger.Debugf("Failed to load remote scan paths: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	var paths []Path
+	for rows.Next() {
+		p, err := scanPath(rows)
+		if err != nil {
+			logger.Warnf("Skipping remote scan path: %v", err)
+			continue
+		}
+		paths = append(paths, p)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Debugf("Error iterating remote scan paths: %v", err)
+		return
+	}
+	r.paths = paths
+	r.loadedAt = time.Now()
+}
+
+// Stat returns the remote entry of localPath. ok is false for local files,
+// which the caller stats itself.
+func (r *Registry) Stat(localPath string) (Entry, bool, error) {
+	p, rel, ok := r.Lookup(localPath)
+	if !ok {
+		return Entry{}, false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*requestTimeout)
+	defer cancel()
+	b, err := Open(ctx, p.Config)
+	if err != nil {
+		return Entry{}, true, err
+	}
+	defer b.Close()
+	e, err := b.Stat(ctx, rel)
+	return e, true, err
+}
+
+// Checker is a HealthChecker that checks files of remote scan paths over
+// their backend and hands local files to the wrapped checker.
+type Checker struct {
+	local integration.HealthChecker
+	paths *Registry
+}
+
+// NewChecker wraps local so that files of remote scan paths get a header check.
+func NewChecker(local integration.HealthChecker, paths *Registry) *Checker {
+	return &Checker{local: local, paths: paths}
+}
+
+// checkRemote runs the header check if path is remote. handled is false for local files.
+func (c *Checker) checkRemote(localPath string) (healthy bool, healthErr *integration.HealthCheckError, handled bool) {
+	p, rel, ok := c.paths.Lookup(localPath)
+	if !ok {
+		return false, nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 4*requestTimeout)
+	defer cancel()
+	b, err := Open(ctx, p.Config)
+	if err != nil {
+		return false, AccessError(err), true
+	}
+	defer b.Close()
+	e, err := b.Stat(ctx, rel)
+	if err != nil {
+		return false, AccessError(err), true
+	}
+	healthy, healthErr = CheckFile(ctx, b, e)
+	return healthy, healthErr, true
+}
+
+func (c *Checker) Check(path, mode string) (bool, *integration.HealthCheckError) {
+	if healthy, healthErr, ok := c.checkRemote(path); ok {
+		return healthy, healthErr
+	}
+	return c.local.Check(path, mode)
+}
+
+func (c *Checker) CheckWithConfig(path string, config integration.DetectionConfig) (bool, *integration.HealthCheckError) {
+	if healthy, healthErr, ok := c.checkRemote(path); ok {
+		return healthy, healthErr
+	}
+	return c.local.CheckWithConfig(path, config)
+}
+
+// AnalyzeContent passes remote files: their content can't be analyzed without
+// downloading them.
+func (c *Checker) AnalyzeContent(path string) (bool, *integration.HealthCheckError) {
+	if _, _, ok := c.paths.Lookup(path); ok {
+		return true, nil
+	}
+	return c.local.AnalyzeContent(path)
+}
+
+func (c *Checker) CheckWithConfigDetector(path string, config integration.DetectionConfig) (bool, integration.DetectionMethod, *integration.HealthCheckError) {
+	if healthy, healthErr, ok := c.checkRemote(path); ok {
+		return healthy, DetectionHeader, healthErr
+	}
+	if reporter, ok := c.local.(integration.DetectorReporter); ok {
+		return reporter.CheckWithConfigDetector(path, config)
+	}
+	healthy, healthErr := c.local.CheckWithConfig(path, config)
+	return healthy, config.Method, healthErr
+}
+
+// ValidateHDRMetadata passes remote files, whose metadata can't be read.
+func (c *Checker) ValidateHDRMetadata(path string) (bool, *integration.HealthCheckError) {
+	if _, _, ok := c.paths.Lookup(path); ok {
+		return true, nil
+	}
+	if validator, ok := c.local.(integration.HDRValidator); ok {
+		return validator.ValidateHDRMetadata(path)
+	}
+	return true, nil
+}
+
+// ProbeDuration refuses remote files, which would have to be downloaded.
+func (c *Checker) ProbeDuration(path string) (time.Duration, error) {
+	if _, _, ok := c.paths.Lookup(path); ok {
+		return 0, errors.New("duration of remote files can't be probed")
+	}
+	if prober, ok := c.local.(integration.DurationProber); ok {
+		return prober.ProbeDuration(path)
+	}
+	return 0, errors.New("duration can't be probed")
+}
+
+func (c *Checker) ProbeAudioTracks(path string) ([]integration.AudioTrack, error) {
+	if _, _, ok := c.paths.Lookup(path); ok {
+		return nil, errors.New("audio tracks of remote files can't be probed")
+	}
+	if prober, ok := c.local.(integration.AudioProber); ok {
+		return prober.ProbeAudioTracks(path)
+	}
+	return nil, errors.New("audio tracks can't be probed")
+}
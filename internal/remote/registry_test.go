@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestChecker_RoutesRemoteFiles(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	server := httptest.NewServer(&fakeDAV{files: map[string][]byte{
+		"Show/S01E01.mkv": matroska(100, 100),
+		"Show/S01E02.mkv": matroska(1000, 100),
+	}})
+	defer server.Close()
+
+	testutil.SeedScanPath(db, 1, "/seedbox/tv", "/tv", true, false)
+	testutil.SeedScanPath(db, 2, "/media/movies", "/movies", true, false)
+	if _, err := db.Exec(`UPDATE scan_paths SET remote_url = ?, remote_requests_per_minute = 600 WHERE id = 1`, server.URL+"/dav"); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+
+	local := &testutil.MockHealthChecker{}
+	checker := NewChecker(local, NewRegistry(db))
+
+	if healthy, healthErr := checker.Check("/seedbox/tv/Show/S01E01.mkv", "quick"); !healthy {
+		t.Errorf("Expected the complete episode to pass, got %v", healthErr)
+	}
+	healthy, method, healthErr := checker.CheckWithConfigDetector("/seedbox/tv/Show/S01E02.mkv", integration.DetectionConfig{Method: integration.DetectionFFprobe})
+	if healthy || method != DetectionHeader || healthErr.Type != integration.ErrorTypeCorruptStream {
+		t.Errorf("Expected the truncated episode to fail the header check, got %v %s %v", healthy, method, healthErr)
+	}
+	if _, healthErr := checker.Check("/seedbox/tv/Show/S01E03.mkv", "quick"); healthErr == nil || healthErr.Type != integration.ErrorTypePathNotFound {
+		t.Errorf("Expected a missing remote file to be PathNotFound, got %v", healthErr)
+	}
+	if len(local.Calls) != 0 {
+		t.Fatalf("Expected remote files to bypass the local checker, got %v", local.Calls)
+	}
+
+	checker.Check("/media/movies/Movie.mkv", "quick")
+	if len(local.Calls) != 1 {
+		t.Errorf("Expected local files to use the local checker, got %v", local.Calls)
+	}
+
+	e, ok, err := NewRegistry(db).Stat("/seedbox/tv/Show/S01E01.mkv")
+	if !ok || err != nil || e.Size != int64(len(matroska(100, 100))) {
+		t.Errorf("Stat = %+v, %v, %v", e, ok, err)
+	}
+	if _, ok, _ := NewRegistry(db).Stat("/seedbox/tvshows/x.mkv"); ok {
+		t.Error("Expected a sibling directory not to match the remote path")
+	}
+}
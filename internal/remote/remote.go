@@ -0,0 +1,253 @@
+// Package remote scans libraries that are only reachable over WebDAV or SFTP,
+// e.g. on a seedbox, without a local mount. A backend lists files and reads
+// byte ranges of them, so checks are limited to what a file's header and
+// container structure tell; see Capabilities.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request rate limits of a remote server, shared by all paths on it.
+const (
+	DefaultRequestsPerMinute = 60
+	MaxRequestsPerMinute     = 600
+)
+
+// requestTimeout bounds a single request to the remote server.
+const requestTimeout = 30 * time.Second
+
+var (
+	// ErrNotFound is returned for a file or directory that doesn't exist on the server.
+	ErrNotFound = errors.New("not found on remote server")
+	// ErrAccessDenied is returned when the server rejects the credentials or the request.
+	ErrAccessDenied = errors.New("access denied by remote server")
+	// ErrUnsupportedURL is returned for a URL no backend handles.
+	ErrUnsupportedURL = errors.New("remote URL must start with http://, https:// or sftp://")
+	// ErrRangeUnsupported is returned by a WebDAV server that ignores range
+	// requests; reading a header would download the whole file.
+	ErrRangeUnsupported = errors.New("remote server doesn't support range requests")
+)
+
+// Protocols of the remote backends.
+const (
+	ProtocolWebDAV = "webdav"
+	ProtocolSFTP   = "sftp"
+)
+
+// Config is how to reach a remote scan path.
+type Config struct {
+	// URL is the root of the library: http(s)://host/path for WebDAV,
+	// sftp://host[:port]/path for SFTP.
+	URL      string
+	Username string
+	Password string
+	// HostKeyFingerprint is the SHA256 fingerprint of the SFTP server's host
+	// key, as printed by ssh-keygen -lf. Connections to any other key are refused.
+	HostKeyFingerprint string
+	// RequestsPerMinute caps requests to the server. Zero uses DefaultRequestsPerMinute.
+	RequestsPerMinute int
+}
+
+// Entry is a file or directory on the remote server.
+type Entry struct {
+	Path    string // relative to the root, slash-separated
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// Backend lists and reads files below the root of a remote scan path. Names
+// are relative to the root and slash-separated.
+type Backend interface {
+	// List returns the entries directly in dir; "" is the root.
+	List(ctx context.Context, dir string) ([]Entry, error)
+	Stat(ctx context.Context, name string) (Entry, error)
+	// ReadAt reads len(p) bytes at off. It returns io.EOF past the end of the file.
+	ReadAt(ctx context.Context, name string, p []byte, off int64) (int, error)
+	Close() error
+}
+
+// Capabilities describe what Healarr can do with the files of a scan path.
+// Local paths can do everything; remote paths only see bytes they request.
+type Capabilities struct {
+	Protocol        string   `json:"protocol"`
+	HeaderCheck     bool     `json:"header_check"`     // container header and structure are checked
+	StreamDecode    bool     `json:"stream_decode"`    // streams are decoded (thorough and sampled modes)
+	ContentAnalysis bool     `json:"content_analysis"` // black, frozen and silent detection
+	HDRValidation   bool     `json:"hdr_validation"`
+	AudioTracks     bool     `json:"audio_tracks"`    // replacement audio tracks are compared
+	SizeStability   bool     `json:"size_stability"`  // files still growing are skipped
+	UntrackedFiles  bool     `json:"untracked_files"` // files unknown to the *arr can be quarantined
+	Limitations     []string `json:"limitations"`
+}
+
+// CapabilitiesFor returns what a scan path on protocol can do.
+func CapabilitiesFor(protocol string) Capabilities {
+	return Capabilities{
+		Protocol:    protocol,
+		HeaderCheck: true,
+		Limitations: []string{
+			"Only headers and container structure are checked; streams are not decoded, so mid-file corruption goes unnoticed",
+			"Detection method and mode are ignored",
+			"Black, frozen and silent content, HDR metadata, durations and audio tracks are not checked",
+			"Files still being written are only skipped by age, not by size changes",
+			"Files unknown to the *arr can't be quarantined",
+		},
+	}
+}
+
+// ProtocolOf returns the protocol of a remote URL.
+func ProtocolOf(rawURL string) (string, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "sftp" {
+		return ProtocolSFTP, nil
+	}
+	return ProtocolWebDAV, nil
+}
+
+// ValidateURL checks that rawURL is a remote URL a backend can open.
+func ValidateURL(rawURL string) error {
+	_, err := parseURL(rawURL)
+	return err
+}
+
+func parseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "sftp":
+	default:
+		return nil, ErrUnsupportedURL
+	}
+	if u.Host == "" {
+		return nil, errors.New("remote URL must include a host")
+	}
+	return u, nil
+}
+
+// NormalizeRequestsPerMinute applies the default and the upper bound to a rate limit.
+func NormalizeRequestsPerMinute(n int) int {
+	if n <= 0 {
+		return DefaultRequestsPerMinute
+	}
+	return min(n, MaxRequestsPerMinute)
+}
+
+// Open connects to the remote scan path described by cfg.
+func Open(ctx context.Context, cfg Config) (Backend, error) {
+	u, err := parseURL(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	limit := limiterFor(u.Host, cfg.RequestsPerMinute)
+	if u.Scheme == "sftp" {
+		return dialSFTP(ctx, u, cfg, limit)
+	}
+	return newWebDAV(u, cfg, limit), nil
+}
+
+// Walk calls fn for every file below the root, skipping hidden directories.
+func Walk(ctx context.Context, b Backend, fn func(Entry) error) error {
+	return walkDir(ctx, b, "", fn)
+}
+
+func walkDir(ctx context.Context, b Backend, dir string, fn func(Entry) error) error {
+	entries, err := b.List(ctx, dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !e.IsDir {
+			if err := fn(e); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(path.Base(e.Path), ".") {
+			continue
+		}
+		if err := walkDir(ctx, b, e.Path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limiter spaces out requests to one server.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*limiter{}
+)
+
+// limiterFor returns the limiter of a server, shared by every backend talking
+// to it. The strictest limit configured for the server wins.
+func limiterFor(host string, perMinute int) *limiter {
+	interval := time.Minute / time.Duration(NormalizeRequestsPerMinute(perMinute))
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[host]
+	if !ok {
+		l = &limiter{interval: interval}
+		limiters[host] = l
+	}
+	l.mu.Lock()
+	l.interval = max(l.interval, interval)
+	l.mu.Unlock()
+	return l
+}
+
+// wait blocks until the next request may be sent.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay := time.Until(at); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// joinPath joins a root and a relative name into a slash-separated path.
+func joinPath(root, name string) string {
+	if name == "" {
+		return root
+	}
+	return path.Join(root, name)
+}
@@ -0,0 +1,126 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+
+	"github.com/pkg/sftp"
+
+	"github.com/mescon/Healarr/internal/sftpclient"
+)
+
+// ErrUnknownHostKey is returned when the SFTP server's host key doesn't match
+// the configured fingerprint. The error names the key the server offered.
+var ErrUnknownHostKey = sftpclient.ErrUnknownHostKey
+
+// sftpBackend reads and lists files below root over an SFTP session.
+type sftpBackend struct {
+	client *sftp.Client
+	closer io.Closer
+	root   string
+	limit  *limiter
+}
+
+// dialSFTP connects to the server, checks its host key and starts the SFTP subsystem.
+func dialSFTP(ctx context.Context, u *url.URL, cfg Config, limit *limiter) (*sftpBackend, error) {
+	if err := limit.wait(ctx); err != nil {
+		return nil, err
+	}
+	username, password := cfg.Username, cfg.Password
+	if username == "" && u.User != nil {
+		username = u.User.Username()
+	}
+	if password == "" && u.User != nil {
+		password, _ = u.User.Password()
+	}
+	client, err := sftpclient.Dial(ctx, sftpclient.Config{
+		Addr:               u.Host,
+		Username:           username,
+		Password:           password,
+		HostKeyFingerprint: cfg.HostKeyFingerprint,
+		Timeout:            requestTimeout,
+	})
+	if errors.Is(err, sftpclient.ErrAuthFailed) {
+		return nil, fmt.Errorf("%w: %v", ErrAccessDenied, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSFTPBackend(client.Client, client, u.Path, limit), nil
+}
+
+func newSFTPBackend(client *sftp.Client, closer io.Closer, root string, limit *limiter) *sftpBackend {
+	if root == "" {
+		root = "/"
+	}
+	return &sftpBackend{client: client, closer: closer, root: root, limit: limit}
+}
+
+func (s *sftpBackend) List(ctx context.Context, dir string) ([]Entry, error) {
+	if err := s.limit.wait(ctx); err != nil {
+		return nil, err
+	}
+	infos, err := s.client.ReadDir(joinPath(s.root, dir))
+	if err != nil {
+		return nil, mapSFTPErr(dir, err)
+	}
+	entries := make([]Entry, 0, len(infos))
+	for _, info := range infos {
+		if info.Name() == "." || info.Name() == ".." {
+			continue
+		}
+		entries = append(entries, entryFromInfo(joinPath(dir, info.Name()), info))
+	}
+	return entries, nil
+}
+
+func (s *sftpBackend) Stat(ctx context.Context, name string) (Entry, error) {
+	if err := s.limit.wait(ctx); err != nil {
+		return Entry{}, err
+	}
+	info, err := s.client.Stat(joinPath(s.root, name))
+	if err != nil {
+		return Entry{}, mapSFTPErr(name, err)
+	}
+	return entryFromInfo(name, info), nil
+}
+
+func (s *sftpBackend) ReadAt(ctx context.Context, name string, p []byte, off int64) (int, error) {
+	if err := s.limit.wait(ctx); err != nil {
+		return 0, err
+	}
+	f, err := s.client.Open(joinPath(s.root, name))
+	if err != nil {
+		return 0, mapSFTPErr(name, err)
+	}
+	defer f.Close()
+	n, err := f.ReadAt(p, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, mapSFTPErr(name, err)
+	}
+	return n, err
+}
+
+func (s *sftpBackend) Close() error {
+	return s.closer.Close()
+}
+
+func entryFromInfo(p string, info fs.FileInfo) Entry {
+	return Entry{Path: p, Size: info.Size(), ModTime: info.ModTime(), IsDir: info.IsDir()}
+}
+
+// mapSFTPErr maps missing files and refused access to the package errors.
+func mapSFTPErr(name string, err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("%s: %w", name, ErrNotFound)
+	case errors.Is(err, os.ErrPermission):
+		return fmt.Errorf("%s: %w", name, ErrAccessDenied)
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
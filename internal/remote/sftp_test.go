@@ -0,0 +1,100 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path"
+	"testing"
+
+	"github.com/pkg/sftp"
+)
+
+// newTestSFTP serves files from memory over an in-process SFTP server; the
+// backend's root is /srv.
+func newTestSFTP(t *testing.T, files map[string][]byte) *sftpBackend {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	handlers := sftp.InMemHandler()
+	server := sftp.NewRequestServer(serverConn, handlers)
+	go server.Serve()
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	if err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	for name, content := range files {
+		if err := client.MkdirAll(path.Dir(name)); err != nil {
+			t.Fatal(err)
+		}
+		f, err := client.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(content); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+	return newSFTPBackend(client, client, "/srv", nil)
+}
+
+func TestSFTP_WalkStatRead(t *testing.T) {
+	s := newTestSFTP(t, map[string][]byte{
+		"/srv/Show/S01E01.mkv":   matroska(100, 100),
+		"/srv/Show/.cache/x.mkv": []byte("hidden"),
+		"/srv/Movie.avi":         riff("AVI ", 5000, 100),
+	})
+	ctx := context.Background()
+
+	var walked []Entry
+	if err := Walk(ctx, s, func(e Entry) error {
+		walked = append(walked, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	if len(walked) != 2 || walked[0].Path != "Movie.avi" || walked[1].Path != "Show/S01E01.mkv" {
+		t.Fatalf("Unexpected walk %+v", walked)
+	}
+	if walked[1].Size != int64(len(matroska(100, 100))) || walked[1].ModTime.IsZero() {
+		t.Errorf("Expected size and mtime from the listing, got %+v", walked[1])
+	}
+
+	e, err := s.Stat(ctx, "Show/S01E01.mkv")
+	if err != nil || e.Size != walked[1].Size {
+		t.Fatalf("Stat = %+v, %v", e, err)
+	}
+	if healthy, healthErr := CheckFile(ctx, s, e); !healthy {
+		t.Errorf("Expected the episode to pass, got %v", healthErr)
+	}
+	if healthy, _ := CheckFile(ctx, s, walked[0]); healthy {
+		t.Error("Expected the truncated AVI to fail")
+	}
+
+	if _, err := s.Stat(ctx, "missing.mkv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSFTP_ReadAtLargerThanChunk(t *testing.T) {
+	content := make([]byte, 100<<10)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	s := newTestSFTP(t, map[string][]byte{"/srv/big.mkv": content})
+
+	p := make([]byte, 70<<10)
+	n, err := s.ReadAt(context.Background(), "big.mkv", p, 1000)
+	if err != nil || n != len(p) || p[0] != content[1000] || p[n-1] != content[1000+n-1] {
+		t.Fatalf("ReadAt = %d, %v", n, err)
+	}
+	n, err = s.ReadAt(context.Background(), "big.mkv", p, int64(len(content))-10)
+	if n != 10 || !errors.Is(err, io.EOF) {
+		t.Errorf("Expected 10 bytes and EOF at the end, got %d, %v", n, err)
+	}
+}
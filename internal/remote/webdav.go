@@ -0,0 +1,226 @@
+package remote
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// propfindBody asks for the properties a listing needs.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getcontentlength/><D:getlastmodified/></D:prop></D:propfind>`
+
+// webdav lists directories with PROPFIND and reads files with ranged GETs.
+type webdav struct {
+	base     *url.URL
+	username string
+	password string
+	client   *http.Client
+	limit    *limiter
+}
+
+func newWebDAV(u *url.URL, cfg Config, limit *limiter) *webdav {
+	base := *u
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	return &webdav{
+		base:     &base,
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: requestTimeout},
+		limit:    limit,
+	}
+}
+
+// multistatus is the PROPFIND response.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ContentLength string `xml:"DAV: getcontentlength"`
+				LastModified  string `xml:"DAV: getlastmodified"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func (w *webdav) List(ctx context.Context, dir string) ([]Entry, error) {
+	entries, err := w.propfind(ctx, dir, "1", true)
+	if err != nil {
+		return nil, err
+	}
+	// The directory itself is part of the response
+	listed := entries[:0]
+	for _, e := range entries {
+		if e.Path != strings.Trim(dir, "/") {
+			listed = append(listed, e)
+		}
+	}
+	return listed, nil
+}
+
+func (w *webdav) Stat(ctx context.Context, name string) (Entry, error) {
+	entries, err := w.propfind(ctx, name, "0", false)
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(entries) == 0 {
+		return Entry{}, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+	return entries[0], nil
+}
+
+func (w *webdav) ReadAt(ctx context.Context, name string, p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	resp, err := w.do(ctx, http.MethodGet, name, map[string]string{
+		"Range": fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	case http.StatusOK:
+		return 0, ErrRangeUnsupported
+	default:
+		return 0, statusError(name, resp)
+	}
+	n, err := io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (w *webdav) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// propfind lists name at the given depth. Directories are requested with a
+// trailing slash: servers redirect them otherwise, and the redirect turns the
+// PROPFIND into a GET.
+func (w *webdav) propfind(ctx context.Context, name, depth string, dir bool) ([]Entry, error) {
+	if dir {
+		name += "/"
+	}
+	resp, err := w.do(ctx, "PROPFIND", name, map[string]string{
+		"Depth":        depth,
+		"Content-Type": "application/xml; charset=utf-8",
+	}, strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, statusError(name, resp)
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 32<<20)).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("invalid PROPFIND response for %q: %w", name, err)
+	}
+	entries := make([]Entry, 0, len(ms.Responses))
+	for _, r := range ms.Responses {
+		rel, ok := w.relative(r.Href)
+		if !ok {
+			continue
+		}
+		e := Entry{Path: rel}
+		for _, ps := range r.Propstat {
+			if ps.Status != "" && !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			e.IsDir = e.IsDir || ps.Prop.ResourceType.Collection != nil
+			if size, err := strconv.ParseInt(strings.TrimSpace(ps.Prop.ContentLength), 10, 64); err == nil {
+				e.Size = size
+			}
+			if mtime, err := http.ParseTime(ps.Prop.LastModified); err == nil {
+				e.ModTime = mtime
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// relative turns an href of the response into a name relative to the root.
+func (w *webdav) relative(href string) (string, bool) {
+	if u, err := url.Parse(href); err == nil {
+		href = u.Path
+	}
+	root := w.base.Path
+	if href != root && !strings.HasPrefix(href, root+"/") {
+		return "", false
+	}
+	return strings.Trim(strings.TrimPrefix(href, root), "/"), true
+}
+
+func (w *webdav) do(ctx context.Context, method, name string, header map[string]string, body io.Reader) (*http.Response, error) {
+	if err := w.limit.wait(ctx); err != nil {
+		return nil, err
+	}
+	u := *w.base
+	u.Path = joinPath(w.base.Path, name)
+	if strings.HasSuffix(name, "/") && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// statusError maps an unexpected HTTP status to an error.
+func statusError(name string, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%s: %w", name, ErrNotFound)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s: %w (HTTP %d)", name, ErrAccessDenied, resp.StatusCode)
+	}
+	return fmt.Errorf("%s: unexpected HTTP status %s", name, resp.Status)
+}
@@ -0,0 +1,234 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDAV serves files below /dav with PROPFIND and ranged GETs.
+type fakeDAV struct {
+	files   map[string][]byte // relative path -> content
+	noRange bool              // ignore Range headers like a misconfigured proxy
+	auth    string            // required "user:password", empty for none
+}
+
+func (f *fakeDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.auth != "" {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user+":"+pass != f.auth {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/dav"), "/")
+	switch r.Method {
+	case "PROPFIND":
+		f.propfind(w, r, name)
+	case http.MethodGet:
+		data, ok := f.files[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if f.noRange {
+			_, _ = w.Write(data)
+			return
+		}
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeDAV) propfind(w http.ResponseWriter, r *http.Request, name string) {
+	var responses []string
+	response := func(p string, size int, dir bool) string {
+		href := "/dav/" + p
+		rtype := ""
+		if dir {
+			href += "/"
+			rtype = "<D:collection/>"
+		}
+		return fmt.Sprintf(`<D:response><D:href>%s</D:href><D:propstat><D:prop><D:resourcetype>%s</D:resourcetype>`+
+			`<D:getcontentlength>%d</D:getcontentlength><D:getlastmodified>Mon, 02 Jan 2006 15:04:05 GMT</D:getlastmodified>`+
+			`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`, (&url.URL{Path: href}).EscapedPath(), rtype, size)
+	}
+
+	if data, ok := f.files[name]; ok {
+		responses = append(responses, response(name, len(data), false))
+	} else {
+		prefix := ""
+		if name != "" {
+			prefix = name + "/"
+		}
+		children := map[string]bool{}
+		for p := range f.files {
+			if !strings.HasPrefix(p, prefix) {
+				continue
+			}
+			child, _, nested := strings.Cut(strings.TrimPrefix(p, prefix), "/")
+			children[child] = children[child] || nested
+		}
+		if len(children) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		responses = append(responses, response(name, 0, true))
+		if r.Header.Get("Depth") == "1" {
+			names := make([]string, 0, len(children))
+			for child := range children {
+				names = append(names, child)
+			}
+			sort.Strings(names)
+			for _, child := range names {
+				responses = append(responses, response(prefix+child, len(f.files[prefix+child]), children[child]))
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:">`+strings.Join(responses, "")+`</D:multistatus>`)
+}
+
+func newTestWebDAV(t *testing.T, dav *fakeDAV, cfg Config) *webdav {
+	t.Helper()
+	server := httptest.NewServer(dav)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL + "/dav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newWebDAV(u, cfg, nil)
+}
+
+func TestWebDAV_WalkAndStat(t *testing.T) {
+	dav := &fakeDAV{files: map[string][]byte{
+		"Show/Season 1/Show S01E01.mkv": []byte("episode"),
+		"Show/.thumbs/cover.jpg":        []byte("hidden"),
+		"Movie (2020)/Movie.mp4":        []byte("movie!"),
+	}}
+	b := newTestWebDAV(t, dav, Config{})
+	ctx := context.Background()
+
+	var walked []string
+	if err := Walk(ctx, b, func(e Entry) error {
+		walked = append(walked, e.Path)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	want := []string{"Movie (2020)/Movie.mp4", "Show/Season 1/Show S01E01.mkv"}
+	if strings.Join(walked, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %v, got %v", want, walked)
+	}
+
+	e, err := b.Stat(ctx, "Show/Season 1/Show S01E01.mkv")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if e.Size != 7 || e.IsDir || e.ModTime.IsZero() {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if _, err := b.Stat(ctx, "missing.mkv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWebDAV_ReadAt(t *testing.T) {
+	dav := &fakeDAV{files: map[string][]byte{"a.mkv": []byte("0123456789")}}
+	b := newTestWebDAV(t, dav, Config{})
+	ctx := context.Background()
+
+	p := make([]byte, 4)
+	n, err := b.ReadAt(ctx, "a.mkv", p, 3)
+	if err != nil || string(p[:n]) != "3456" {
+		t.Errorf("Expected 3456, got %q (%v)", p[:n], err)
+	}
+	n, err = b.ReadAt(ctx, "a.mkv", p, 8)
+	if !errors.Is(err, io.EOF) || string(p[:n]) != "89" {
+		t.Errorf("Expected 89 and EOF, got %q (%v)", p[:n], err)
+	}
+	if _, err := b.ReadAt(ctx, "a.mkv", p, 20); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected EOF past the end, got %v", err)
+	}
+
+	dav.noRange = true
+	if _, err := b.ReadAt(ctx, "a.mkv", p, 0); !errors.Is(err, ErrRangeUnsupported) {
+		t.Errorf("Expected ErrRangeUnsupported, got %v", err)
+	}
+}
+
+func TestWebDAV_Auth(t *testing.T) {
+	dav := &fakeDAV{files: map[string][]byte{"a.mkv": []byte("x")}, auth: "seed:box"}
+
+	if _, err := newTestWebDAV(t, dav, Config{Username: "seed", Password: "wrong"}).List(context.Background(), ""); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Expected ErrAccessDenied, got %v", err)
+	}
+	entries, err := newTestWebDAV(t, dav, Config{Username: "seed", Password: "box"}).List(context.Background(), "")
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected one entry, got %v (%v)", entries, err)
+	}
+}
+
+func TestLimiter_SpacesRequests(t *testing.T) {
+	l := &limiter{interval: 50 * time.Millisecond}
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected 3 requests to take at least 100ms, took %v", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.wait(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled wait to fail, got %v", err)
+	}
+}
+
+func TestLimiterFor_StrictestWins(t *testing.T) {
+	host := "limiter-test.invalid"
+	limiterFor(host, 600)
+	l := limiterFor(host, 30)
+	if l != limiterFor(host, 600) {
+		t.Fatal("Expected the limiter to be shared by the host")
+	}
+	if l.interval != 2*time.Second {
+		t.Errorf("Expected the 30/min interval of 2s, got %v", l.interval)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		protocol string
+		wantErr  bool
+	}{
+		{"https://seedbox.example/dav/tv", ProtocolWebDAV, false},
+		{"http://10.0.0.2:8080/", ProtocolWebDAV, false},
+		{"sftp://seedbox.example:2222/home/me/tv", ProtocolSFTP, false},
+		{"ftp://seedbox.example/tv", "", true},
+		{"/mnt/tv", "", true},
+		{"sftp:///tv", "", true},
+	}
+	for _, tt := range tests {
+		protocol, err := ProtocolOf(tt.url)
+		if (err != nil) != tt.wantErr || protocol != tt.protocol {
+			t.Errorf("ProtocolOf(%q) = %q, %v", tt.url, protocol, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/metrics"
+)
+
+// AlertStatus is the current state of one of the recommended alerts.
+type AlertStatus struct {
+	Alert    string `json:"alert"`
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Firing   bool   `json:"firing"`
+	// Value is the failure rate, or the number of open breakers or overdue paths
+	Value float64 `json:"value"`
+	// Targets names what the alert fires for: *arr instances or scan paths
+	Targets []string `json:"targets"`
+}
+
+// scheduledScan is a scheduled scan path and when it last finished a scan.
+type scheduledScan struct {
+	PathID    int64
+	LocalPath string
+	LastAt    time.Time
+}
+
+// EvaluateAlerts evaluates the conditions of the recommended Prometheus alerts
+// from the database and the *arr circuit breakers, for setups without
+// Prometheus. breakers may be nil.
+func EvaluateAlerts(ctx context.Context, database *sql.DB, breakers CircuitBreakerSource, t metrics.AlertThresholds, now time.Time) ([]AlertStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	statuses := make([]AlertStatus, 0, 3)
+	for _, rule := range metrics.AlertRules(t) {
+		status := AlertStatus{Alert: rule.Alert, Severity: rule.Severity, Summary: rule.Summary, Targets: []string{}}
+		var err error
+		switch rule.Alert {
+		case metrics.AlertRemediationFailureRate:
+			err = evaluateFailureRate(ctx, database, t, now, &status)
+		case metrics.AlertArrCircuitBreakerOpen:
+			err = evaluateOpenBreakers(ctx, database, breakers, t, now, &status)
+		case metrics.AlertScanOverdue:
+			err = evaluateOverdueScans(ctx, database, t, now, &status)
+		}
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// evaluateFailureRate compares the remediations that reached max retries to all
+// finished remediations in the window, as healarr_remediations_total counts them.
+func evaluateFailureRate(ctx context.Context, database *sql.DB, t metrics.AlertThresholds, now time.Time, status *AlertStatus) error {
+	var failed, finished int
+	err := database.QueryRowContext(ctx, `
+		SELECT COUNT(CASE WHEN event_type = 'MaxRetriesReached' THEN 1 END), COUNT(*)
+		FROM events
+		WHERE event_type IN ('VerificationSuccess', 'MaxRetriesReached') AND created_at >= ?
+			AND json_extract(event_data, '$.reverification_of') IS NULL
+	`, now.Add(-t.FailureRateWindow).UTC().Format(reportTimeFormat)).Scan(&failed, &finished)
+	if err != nil {
+		return fmt.Errorf("failed to count finished remediations: %w", err)
+	}
+	if finished > 0 {
+		status.Value = float64(failed) / float64(finished)
+	}
+	status.Firing = finished >= t.FailureRateMin && status.Value > t.FailureRate
+	return nil
+}
+
+// evaluateOpenBreakers lists the instances whose circuit breaker has been open
+// or half-open for longer than the threshold.
+func evaluateOpenBreakers(ctx context.Context, database *sql.DB, breakers CircuitBreakerSource, t metrics.AlertThresholds, now time.Time, status *AlertStatus) error {
+	if breakers == nil {
+		return nil
+	}
+	stats := breakers.GetCircuitBreakerStats()
+	if len(stats) == 0 {
+		return nil
+	}
+
+	rows, err := database.QueryContext(ctx, `SELECT id, name FROM arr_instances WHERE deleted_at IS NULL ORDER BY name`)
+	if err != nil {
+		return fmt.Errorf("failed to load *arr instances: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		st, ok := stats[id]
+		if !ok || st.State == integration.CircuitClosed || st.OpenSince.IsZero() || now.Sub(st.OpenSince) < t.BreakerOpenFor {
+			continue
+		}
+		status.Targets = append(status.Targets, name)
+	}
+	status.Value = float64(len(status.Targets))
+	status.Firing = len(status.Targets) > 0
+	return rows.Err()
+}
+
+// evaluateOverdueScans lists the scheduled scan paths without a finished scan
+// within the threshold.
+func evaluateOverdueScans(ctx context.Context, database *sql.DB, t metrics.AlertThresholds, now time.Time, status *AlertStatus) error {
+	scans, err := loadScheduledScans(ctx, database)
+	if err != nil {
+		return err
+	}
+	for _, scan := range scans {
+		if now.Sub(scan.LastAt) > t.ScanOverdueAfter {
+			status.Targets = append(status.Targets, scan.LocalPath)
+		}
+	}
+	status.Value = float64(len(status.Targets))
+	status.Firing = len(status.Targets) > 0
+	return nil
+}
+
+// LastScheduledScans returns when each enabled scan path with an enabled
+// schedule last finished a scan, or was added if it never did. It feeds the
+// healarr_last_scan_completed_timestamp_seconds metric.
+func LastScheduledScans(database *sql.DB) metrics.LastScansFunc {
+	return func(ctx context.Context) (map[int64]time.Time, error) {
+		ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+		defer cancel()
+		scans, err := loadScheduledScans(ctx, database)
+		if err != nil {
+			return nil, err
+		}
+		last := make(map[int64]time.Time, len(scans))
+		for _, scan := range scans {
+			last[scan.PathID] = scan.LastAt
+		}
+		return last, nil
+	}
+}
+
+// loadScheduledScans loads the enabled scan paths with an enabled schedule and
+// when they last finished a scan.
+func loadScheduledScans(ctx context.Context, database *sql.DB) ([]scheduledScan, error) {
+	rows, err := database.QueryContext(ctx, `
+		SELECT sp.id, sp.local_path, CAST(strftime('%s', COALESCE(
+			(SELECT MAX(s.completed_at) FROM scans s WHERE s.path_id = sp.id AND s.status = 'completed'),
+			sp.created_at)) AS INTEGER)
+		FROM scan_paths sp
+		WHERE sp.enabled = 1 AND sp.deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM scan_schedules ss WHERE ss.scan_path_id = sp.id AND ss.enabled = 1)
+		ORDER BY sp.local_path
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheduled scan paths: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []scheduledScan
+	for rows.Next() {
+		var scan scheduledScan
+		var lastAt sql.NullInt64
+		if err := rows.Scan(&scan.PathID, &scan.LocalPath, &lastAt); err != nil {
+			return nil, err
+		}
+		scan.LastAt = time.Unix(lastAt.Int64, 0)
+		scans = append(scans, scan)
+	}
+	return scans, rows.Err()
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/metrics"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// fixedBreakers reports fixed circuit breaker states.
+type fixedBreakers map[int64]integration.CircuitBreakerStats
+
+func (f fixedBreakers) GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats {
+	return f
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	database, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	now := time.Now()
+
+	// 3 of 5 remediations in the last day reached max retries; older ones don't count
+	for i, eventType := range []string{"MaxRetriesReached", "MaxRetriesReached", "MaxRetriesReached", "VerificationSuccess", "VerificationSuccess"} {
+		if _, err := database.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at) VALUES ('corruption', ?, ?, '{}', ?)`,
+			i, eventType, now.Add(-time.Hour).UTC()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := database.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at) VALUES ('corruption', 'old', 'VerificationSuccess', '{}', ?)`,
+		now.Add(-48*time.Hour).UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scheduled paths: /tv scanned recently, /movies 10 days ago, /new never;
+	// /manual isn't scheduled
+	_, err = database.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, enabled, created_at) VALUES
+			(1, '/tv', '/tv', 1, datetime('now', '-30 days')),
+			(2, '/movies', '/movies', 1, datetime('now', '-30 days')),
+			(3, '/new', '/new', 1, datetime('now', '-1 days')),
+			(4, '/manual', '/manual', 1, datetime('now', '-30 days'));
+		INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled) VALUES
+			(1, '0 3 * * *', 1), (2, '0 3 * * *', 1), (3, '0 3 * * *', 1);
+		INSERT INTO scans (path, path_id, status, started_at, completed_at) VALUES
+			('/tv', 1, 'completed', datetime('now', '-1 days'), datetime('now', '-1 days')),
+			('/movies', 2, 'completed', datetime('now', '-10 days'), datetime('now', '-10 days')),
+			('/movies', 2, 'error', datetime('now', '-1 days'), datetime('now', '-1 days'));
+		INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+			(1, 'Sonarr', 'sonarr', 'http://sonarr', 'key'),
+			(2, 'Radarr', 'radarr', 'http://radarr', 'key'),
+			(3, 'Whisparr', 'whisparr-v3', 'http://whisparr', 'key');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	breakers := fixedBreakers{
+		1: {State: integration.CircuitOpen, OpenSince: now.Add(-time.Hour)},
+		2: {State: integration.CircuitOpen, OpenSince: now.Add(-time.Minute)},
+		3: {State: integration.CircuitClosed},
+	}
+
+	alerts, err := EvaluateAlerts(context.Background(), database, breakers, metrics.DefaultAlertThresholds(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 3 {
+		t.Fatalf("got %d alerts, want 3", len(alerts))
+	}
+
+	byName := map[string]AlertStatus{}
+	for _, a := range alerts {
+		byName[a.Alert] = a
+	}
+	if a := byName[metrics.AlertRemediationFailureRate]; !a.Firing || a.Value != 0.6 {
+		t.Errorf("failure rate: %+v", a)
+	}
+	if a := byName[metrics.AlertArrCircuitBreakerOpen]; !a.Firing || len(a.Targets) != 1 || a.Targets[0] != "Sonarr" {
+		t.Errorf("open breakers: %+v", a)
+	}
+	if a := byName[metrics.AlertScanOverdue]; !a.Firing || len(a.Targets) != 1 || a.Targets[0] != "/movies" {
+		t.Errorf("overdue scans: %+v", a)
+	}
+
+	last, err := LastScheduledScans(database)(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last) != 3 {
+		t.Fatalf("last scans: %v", last)
+	}
+	if age := now.Sub(last[2]); age < 239*time.Hour || age > 241*time.Hour {
+		t.Errorf("/movies last scanned %v ago, want 10 days", age)
+	}
+}
+
+func TestEvaluateAlerts_Quiet(t *testing.T) {
+	database, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	// One failure is below the minimum number of finished remediations
+	if _, err := database.Exec(`INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at) VALUES ('corruption', 'c1', 'MaxRetriesReached', '{}', ?)`,
+		time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err := EvaluateAlerts(context.Background(), database, nil, metrics.DefaultAlertThresholds(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range alerts {
+		if a.Firing {
+			t.Errorf("%s is firing: %+v", a.Alert, a)
+		}
+		if a.Targets == nil {
+			t.Errorf("%s: targets should be an empty list", a.Alert)
+		}
+	}
+}
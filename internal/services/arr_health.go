@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ArrHealthSample is the outcome of a single *arr instance health check.
+type ArrHealthSample struct {
+	CheckedAt  string `json:"checked_at"`
+	Reachable  bool   `json:"reachable"`
+	ResponseMs int64  `json:"response_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ArrHealthPeriod aggregates the health checks of one hour or day.
+type ArrHealthPeriod struct {
+	Period        string  `json:"period"`
+	Samples       int     `json:"samples"`
+	UptimePercent float64 `json:"uptime_percent"`
+	// AvgResponseMs only covers checks the instance answered
+	AvgResponseMs float64 `json:"avg_response_ms"`
+}
+
+// ArrHealthHistory summarises an instance's health checks since a point in time.
+type ArrHealthHistory struct {
+	Samples int `json:"samples"`
+	// UptimePercent is nil until the instance has been checked in the window
+	UptimePercent *float64          `json:"uptime_percent"`
+	AvgResponseMs float64           `json:"avg_response_ms"`
+	P95ResponseMs int64             `json:"p95_response_ms"`
+	Latest        *ArrHealthSample  `json:"latest"`
+	Periods       []ArrHealthPeriod `json:"periods"`
+}
+
+// LoadArrHealthHistory returns the uptime and response times of an instance
+// since the given time, per hour or, when daily is set, per day. Latest is the
+// most recent check, even if it is older than since.
+func LoadArrHealthHistory(ctx context.Context, db *sql.DB, instanceID int64, since time.Time, daily bool) (*ArrHealthHistory, error) {
+	history := &ArrHealthHistory{Periods: []ArrHealthPeriod{}}
+	sinceArg := since.UTC().Format("2006-01-02 15:04:05")
+
+	var latest ArrHealthSample
+	var latestErr sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT strftime('%Y-%m-%dT%H:%M:%SZ', checked_at), reachable, response_ms, error FROM arr_health_samples
+		WHERE instance_id = ? ORDER BY checked_at DESC, id DESC LIMIT 1
+	`, instanceID).Scan(&latest.CheckedAt, &latest.Reachable, &latest.ResponseMs, &latestErr)
+	if errors.Is(err, sql.ErrNoRows) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	latest.Error = latestErr.String
+	history.Latest = &latest
+
+	var reachable int
+	var avg sql.NullFloat64
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(reachable), 0), AVG(CASE WHEN reachable THEN response_ms END)
+		FROM arr_health_samples WHERE instance_id = ? AND checked_at >= ?
+	`, instanceID, sinceArg).Scan(&history.Samples, &reachable, &avg); err != nil {
+		return nil, err
+	}
+	if history.Samples == 0 {
+		return history, nil
+	}
+	uptime := 100 * float64(reachable) / float64(history.Samples)
+	history.UptimePercent = &uptime
+	history.AvgResponseMs = avg.Float64
+
+	if reachable > 0 {
+		if err := db.QueryRowContext(ctx, `
+			SELECT response_ms FROM arr_health_samples
+			WHERE instance_id = ? AND checked_at >= ? AND reachable
+			ORDER BY response_ms LIMIT 1 OFFSET ?
+		`, instanceID, sinceArg, (95*reachable+99)/100-1).Scan(&history.P95ResponseMs); err != nil { // nearest rank
+			return nil, err
+		}
+	}
+
+	periodLen := 13 // YYYY-MM-DD HH
+	if daily {
+		periodLen = 10 // YYYY-MM-DD
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT substr(checked_at, 1, %d) AS period, COUNT(*), COALESCE(SUM(reachable), 0),
+			COALESCE(AVG(CASE WHEN reachable THEN response_ms END), 0)
+		FROM arr_health_samples WHERE instance_id = ? AND checked_at >= ?
+		GROUP BY period ORDER BY period ASC
+	`, periodLen), instanceID, sinceArg) // NOSONAR - only a fixed length is formatted in
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p ArrHealthPeriod
+		var up int
+		if err := rows.Scan(&p.Period, &p.Samples, &up, &p.AvgResponseMs); err != nil {
+			return nil, err
+		}
+		p.UptimePercent = 100 * float64(up) / float64(p.Samples)
+		history.Periods = append(history.Periods, p)
+	}
+	return history, rows.Err()
+}
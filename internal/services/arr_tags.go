@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// arrTagQueryTimeout bounds tag lookups and path re-binding.
+const arrTagQueryTimeout = 5 * time.Second
+
+var (
+	// ErrArrTagNotFound is returned when no enabled instance carries a tag.
+	ErrArrTagNotFound = errors.New("no enabled *arr instance has this tag")
+	// ErrArrTagAmbiguous is returned when more than one enabled instance carries a tag.
+	ErrArrTagAmbiguous = errors.New("more than one enabled *arr instance has this tag")
+)
+
+// ArrTagSyncResult summarizes a tag refresh and the re-binding of tag-assigned scan paths.
+type ArrTagSyncResult struct {
+	Instances  int      `json:"instances"`            // instances whose tags were refreshed
+	Failed     []string `json:"failed"`               // instances whose tags could not be fetched
+	Rebound    int      `json:"rebound"`              // scan paths moved to another instance
+	Unresolved []string `json:"unresolved,omitempty"` // scan paths whose tag matches no or several instances
+}
+
+// NormalizeArrTag returns the form tags are stored and matched in. *arr
+// applications lowercase tag labels.
+func NormalizeArrTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// SyncArrTags refreshes the tags cached for each enabled *arr instance, then
+// binds tag-assigned scan paths to the instance carrying their tag. Instances
+// that can't be reached keep their previously cached tags.
+func SyncArrTags(database *sql.DB, arr integration.ArrClient) (*ArrTagSyncResult, error) {
+	instances, err := arr.GetAllInstances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list *arr instances: %w", err)
+	}
+
+	result := &ArrTagSyncResult{Failed: []string{}}
+	for _, inst := range instances {
+		tags, err := arr.GetTags(inst.ID)
+		if err != nil {
+			logger.Warnf("Failed to fetch tags of %s: %v", inst.Name, err)
+			result.Failed = append(result.Failed, inst.Name)
+			continue
+		}
+		if err := StoreArrTags(database, inst.ID, tags); err != nil {
+			return nil, err
+		}
+		result.Instances++
+	}
+
+	result.Rebound, result.Unresolved, err = BindTaggedScanPaths(database)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StoreArrTags caches the tag labels of an instance.
+func StoreArrTags(database *sql.DB, instanceID int64, tags []integration.ArrTag) error {
+	labels := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if label := NormalizeArrTag(tag.Label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecWithRetry(database, "UPDATE arr_instances SET tags = ? WHERE id = ?", string(data), instanceID); err != nil {
+		return fmt.Errorf("failed to store tags of instance %d: %w", instanceID, err)
+	}
+	return nil
+}
+
+// ResolveArrInstanceTag returns the enabled instance whose cached tags include tag.
+func ResolveArrInstanceTag(database *sql.DB, tag string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), arrTagQueryTimeout)
+	defer cancel()
+
+	rows, err := database.QueryContext(ctx, `
+		SELECT id FROM arr_instances
+		WHERE enabled = 1 AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM json_each(arr_instances.tags) WHERE value = ?)
+		LIMIT 2
+	`, NormalizeArrTag(tag))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, ErrArrTagNotFound
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, ErrArrTagAmbiguous
+	}
+}
+
+// BindTaggedScanPaths points every scan path assigned by tag at the instance
+// currently carrying that tag. Paths whose tag resolves to no or several
+// instances keep their current instance and are returned as unresolved.
+func BindTaggedScanPaths(database *sql.DB) (int, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), arrTagQueryTimeout)
+	defer cancel()
+
+	rows, err := database.QueryContext(ctx, `
+		SELECT id, local_path, arr_instance_tag, arr_instance_id FROM scan_paths WHERE arr_instance_tag != '' AND deleted_at IS NULL
+	`)
+	if err != nil {
+		return 0, nil, err
+	}
+	type taggedPath struct {
+		id         int64
+		localPath  string
+		tag        string
+		instanceID sql.NullInt64
+	}
+	var paths []taggedPath
+	for rows.Next() {
+		var p taggedPath
+		if err := rows.Scan(&p.id, &p.localPath, &p.tag, &p.instanceID); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		paths = append(paths, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	rebound := 0
+	var unresolved []string
+	for _, p := range paths {
+		instanceID, err := ResolveArrInstanceTag(database, p.tag)
+		if err != nil {
+			if !errors.Is(err, ErrArrTagNotFound) && !errors.Is(err, ErrArrTagAmbiguous) {
+				return rebound, unresolved, err
+			}
+			logger.Warnf("Scan path %s is bound to tag %q, but %v", p.localPath, p.tag, err)
+			unresolved = append(unresolved, p.localPath)
+			continue
+		}
+		if p.instanceID.Valid && p.instanceID.Int64 == instanceID {
+			continue
+		}
+		if _, err := db.ExecWithRetry(database, "UPDATE scan_paths SET arr_instance_id = ? WHERE id = ?", instanceID, p.id); err != nil {
+			return rebound, unresolved, fmt.Errorf("failed to bind scan path %s: %w", p.localPath, err)
+		}
+		logger.Infof("Scan path %s bound to *arr instance %d by tag %q", p.localPath, instanceID, p.tag)
+		rebound++
+	}
+	return rebound, unresolved, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestSyncArrTags_BindsScanPathsByTag(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	// Instance 1 was deleted and re-created as instance 3; the 4K path still points at the old ID
+	_, err = db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key, enabled) VALUES
+			(2, 'Radarr', 'radarr', 'http://radarr', 'key', 1),
+			(3, 'Radarr 4K', 'radarr', 'http://radarr4k', 'key', 1),
+			(4, 'Sonarr', 'sonarr', 'http://sonarr', 'key', 1);
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, arr_instance_tag) VALUES
+			(1, '/media/movies', '/movies', 2, ''),
+			(2, '/media/movies-4k', '/movies-4k', 1, 'UHD'),
+			(3, '/media/anime', '/anime', 4, 'anime'),
+			(4, '/media/kids', '/kids', 2, 'kids');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	arr := &testutil.MockArrClient{
+		GetAllInstancesFunc: func() ([]*integration.ArrInstanceInfo, error) {
+			return []*integration.ArrInstanceInfo{{ID: 2, Name: "Radarr"}, {ID: 3, Name: "Radarr 4K"}, {ID: 4, Name: "Sonarr"}}, nil
+		},
+		GetTagsFunc: func(instanceID int64) ([]integration.ArrTag, error) {
+			switch instanceID {
+			case 2:
+				return []integration.ArrTag{{ID: 1, Label: "kids"}}, nil
+			case 3:
+				return []integration.ArrTag{{ID: 1, Label: "uhd"}, {ID: 2, Label: "Kids"}}, nil
+			default:
+				return nil, errors.New("connection refused")
+			}
+		},
+	}
+
+	result, err := SyncArrTags(db, arr)
+	if err != nil {
+		t.Fatalf("SyncArrTags failed: %v", err)
+	}
+	if result.Instances != 2 || len(result.Failed) != 1 || result.Failed[0] != "Sonarr" {
+		t.Errorf("Unexpected sync result: %+v", result)
+	}
+	if result.Rebound != 1 {
+		t.Errorf("Expected 1 rebound path, got %d", result.Rebound)
+	}
+	// "anime" is on no instance and "kids" is on two
+	if len(result.Unresolved) != 2 {
+		t.Errorf("Expected 2 unresolved paths, got %v", result.Unresolved)
+	}
+
+	want := map[int64]int64{1: 2, 2: 3, 3: 4, 4: 2}
+	for pathID, instanceID := range want {
+		var got int64
+		if err := db.QueryRow("SELECT arr_instance_id FROM scan_paths WHERE id = ?", pathID).Scan(&got); err != nil {
+			t.Fatalf("Failed to read scan path %d: %v", pathID, err)
+		}
+		if got != instanceID {
+			t.Errorf("Scan path %d: expected instance %d, got %d", pathID, instanceID, got)
+		}
+	}
+
+	var tags string
+	if err := db.QueryRow("SELECT tags FROM arr_instances WHERE id = 3").Scan(&tags); err != nil {
+		t.Fatalf("Failed to read tags: %v", err)
+	}
+	if tags != `["uhd","kids"]` {
+		t.Errorf("Expected normalized tags, got %s", tags)
+	}
+}
+
+func TestResolveArrInstanceTag(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key, enabled, tags) VALUES
+			(1, 'Radarr', 'radarr', 'http://radarr', 'key', 1, '["hd"]'),
+			(2, 'Radarr 4K', 'radarr', 'http://radarr4k', 'key', 1, '["uhd","hdr"]'),
+			(3, 'Old 4K', 'radarr', 'http://old', 'key', 0, '["uhd"]');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	if id, err := ResolveArrInstanceTag(db, " UHD "); err != nil || id != 2 {
+		t.Errorf("Expected instance 2, got %d (%v)", id, err)
+	}
+	if _, err := ResolveArrInstanceTag(db, "anime"); !errors.Is(err, ErrArrTagNotFound) {
+		t.Errorf("Expected ErrArrTagNotFound, got %v", err)
+	}
+
+	if _, err := db.Exec(`UPDATE arr_instances SET tags = '["hd","hdr"]' WHERE id = 1`); err != nil {
+		t.Fatalf("Failed to update tags: %v", err)
+	}
+	if _, err := ResolveArrInstanceTag(db, "hdr"); !errors.Is(err, ErrArrTagAmbiguous) {
+		t.Errorf("Expected ErrArrTagAmbiguous, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// originalAudio is the audio of the file a corruption's first deletion
+// removed, as recorded from the *arr instance's media info. Channels is the
+// channel count of its main track, 0 when unknown.
+type originalAudio struct {
+	Languages []string
+	Channels  int
+}
+
+// known reports whether anything about the original audio was recorded.
+func (a originalAudio) known() bool {
+	return len(a.Languages) > 0 || a.Channels > 0
+}
+
+// loadOriginalAudio returns the audio recorded when a corruption's file was deleted.
+func loadOriginalAudio(ctx context.Context, database *sql.DB, corruptionID string) (originalAudio, error) {
+	var languages sql.NullString
+	var channels sql.NullInt64
+	err := database.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.metadata.audio_languages'), json_extract(event_data, '$.metadata.audio_channels')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = 'DeletionCompleted'
+		ORDER BY id LIMIT 1
+	`, corruptionID).Scan(&languages, &channels)
+	if errors.Is(err, sql.ErrNoRows) {
+		return originalAudio{}, nil
+	}
+	if err != nil {
+		return originalAudio{}, err
+	}
+
+	var audio originalAudio
+	if languages.Valid && languages.String != "" {
+		if err := json.Unmarshal([]byte(languages.String), &audio.Languages); err != nil {
+			return originalAudio{}, fmt.Errorf("invalid audio languages %q: %w", languages.String, err)
+		}
+	}
+	if channels.Valid {
+		audio.Channels = int(channels.Int64)
+	}
+	return audio, nil
+}
+
+// audioShortfall is what a replacement's audio lacks compared to the original.
+type audioShortfall struct {
+	MissingLanguages []string
+	NewLanguages     []string
+	NewChannels      int // channel count of the replacement's widest track
+}
+
+// compareAudio compares a replacement's audio tracks with the original's. A
+// language counts as missing only if there are more missing languages than
+// untagged tracks that could carry them.
+func compareAudio(original originalAudio, tracks []integration.AudioTrack) (audioShortfall, bool) {
+	var shortfall audioShortfall
+	present := map[string]bool{}
+	untagged := 0
+	for _, t := range tracks {
+		if t.Channels > shortfall.NewChannels {
+			shortfall.NewChannels = t.Channels
+		}
+		if t.Language == "" {
+			untagged++
+			continue
+		}
+		if !present[t.Language] {
+			present[t.Language] = true
+			shortfall.NewLanguages = append(shortfall.NewLanguages, t.Language)
+		}
+	}
+
+	for _, lang := range original.Languages {
+		if !present[lang] {
+			shortfall.MissingLanguages = append(shortfall.MissingLanguages, lang)
+		}
+	}
+	if len(shortfall.MissingLanguages) <= untagged {
+		shortfall.MissingLanguages = nil
+	}
+
+	fewerChannels := original.Channels > 0 && shortfall.NewChannels < original.Channels
+	return shortfall, len(shortfall.MissingLanguages) > 0 || fewerChannels
+}
+
+// reason describes the shortfall for notifications and the UI.
+func (s audioShortfall) reason(original originalAudio) string {
+	var parts []string
+	if len(s.MissingLanguages) > 0 {
+		parts = append(parts, "no audio in "+strings.Join(s.MissingLanguages, ", "))
+	}
+	if original.Channels > 0 && s.NewChannels < original.Channels {
+		parts = append(parts, fmt.Sprintf("%d audio channels instead of %d", s.NewChannels, original.Channels))
+	}
+	return "Replacement has " + strings.Join(parts, " and ")
+}
+
+// checkAudioTracks compares the audio of a healthy replacement with the file
+// it replaced when HEALARR_VERIFY_AUDIO_TRACKS is enabled. If a replacement
+// lacks a language or channels the original had, it publishes
+// AudioTrackMissing so the corruption waits for the user instead of
+// resolving, and returns true. Replacements are accepted when the original's
+// audio wasn't recorded or the replacement can't be probed.
+func (v *VerifierService) checkAudioTracks(corruptionID string, filePaths []string) bool {
+	if v.db == nil || !config.Get().VerifyAudioTracks {
+		return false
+	}
+	prober, ok := v.detector.(integration.AudioProber)
+	if !ok {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifierQueryTimeout)
+	defer cancel()
+
+	original, err := loadOriginalAudio(ctx, v.db, corruptionID)
+	if err != nil {
+		logger.Warnf("Failed to load original audio for %s: %v", corruptionID, err)
+		return false
+	}
+	if !original.known() {
+		logger.Debugf("Audio tracks of %s not checked: original audio unknown", corruptionID)
+		return false
+	}
+
+	for _, path := range filePaths {
+		tracks, err := prober.ProbeAudioTracks(path)
+		if err != nil {
+			logger.Debugf("Audio tracks of %s not checked: %v", path, err)
+			continue
+		}
+		shortfall, short := compareAudio(original, tracks)
+		if !short {
+			continue
+		}
+
+		reason := shortfall.reason(original)
+		logger.Warnf("Replacement missing audio track for %s: %s (file: %s)", corruptionID, reason, path)
+		if err := v.eventBus.PublishWithRetry(domain.Event{
+			AggregateID:   corruptionID,
+			AggregateType: "corruption",
+			EventType:     domain.AudioTrackMissing,
+			EventData: map[string]interface{}{
+				"file_path":          path,
+				"missing_languages":  shortfall.MissingLanguages,
+				"original_languages": original.Languages,
+				"new_languages":      shortfall.NewLanguages,
+				"original_channels":  original.Channels,
+				"new_channels":       shortfall.NewChannels,
+				"reason":             reason,
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish AudioTrackMissing event after retries: %v", err)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// audioProbingChecker is a healthy checker whose files have the given audio tracks.
+type audioProbingChecker struct {
+	*testutil.MockHealthChecker
+	tracks []integration.AudioTrack
+}
+
+func (c *audioProbingChecker) ProbeAudioTracks(path string) ([]integration.AudioTrack, error) {
+	return c.tracks, nil
+}
+
+func TestCompareAudio(t *testing.T) {
+	original := originalAudio{Languages: []string{"eng", "jpn"}, Channels: 6}
+	tests := []struct {
+		name        string
+		tracks      []integration.AudioTrack
+		wantShort   bool
+		wantMissing []string
+	}{
+		{"same audio", []integration.AudioTrack{{Language: "jpn", Channels: 6}, {Language: "eng", Channels: 6}}, false, nil},
+		{"extra languages", []integration.AudioTrack{{Language: "eng", Channels: 8}, {Language: "jpn", Channels: 2}, {Language: "deu", Channels: 6}}, false, nil},
+		{"missing language", []integration.AudioTrack{{Language: "eng", Channels: 6}}, true, []string{"jpn"}},
+		{"untagged track may carry it", []integration.AudioTrack{{Language: "eng", Channels: 6}, {Channels: 2}}, false, nil},
+		{"fewer channels", []integration.AudioTrack{{Language: "eng", Channels: 2}, {Language: "jpn", Channels: 2}}, true, nil},
+		{"no audio", nil, true, []string{"eng", "jpn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortfall, short := compareAudio(original, tt.tracks)
+			if short != tt.wantShort {
+				t.Errorf("short = %v, want %v", short, tt.wantShort)
+			}
+			if len(shortfall.MissingLanguages) != len(tt.wantMissing) {
+				t.Fatalf("missing = %v, want %v", shortfall.MissingLanguages, tt.wantMissing)
+			}
+			for i := range tt.wantMissing {
+				if shortfall.MissingLanguages[i] != tt.wantMissing[i] {
+					t.Errorf("missing = %v, want %v", shortfall.MissingLanguages, tt.wantMissing)
+				}
+			}
+		})
+	}
+
+	shortfall, _ := compareAudio(original, []integration.AudioTrack{{Language: "eng", Channels: 2}})
+	if got, want := shortfall.reason(original), "Replacement has no audio in jpn and 2 audio channels instead of 6"; got != want {
+		t.Errorf("reason() = %q, want %q", got, want)
+	}
+}
+
+func TestVerifierService_AudioTracks(t *testing.T) {
+	cfg := config.NewTestConfig()
+	cfg.VerifyAudioTracks = true
+	config.SetForTesting(cfg)
+	defer config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	// seed records a corruption whose deleted file had English and Japanese 5.1 audio
+	seed := func(t *testing.T, id string) {
+		t.Helper()
+		for _, e := range []domain.Event{
+			{EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/media/anime/" + id + ".mkv"}},
+			{EventType: domain.DeletionCompleted, EventData: map[string]interface{}{
+				"media_id": 7,
+				"metadata": map[string]interface{}{"audio_languages": []string{"eng", "jpn"}, "audio_channels": 6},
+			}},
+		} {
+			e.AggregateID, e.AggregateType = id, "corruption"
+			if _, err := testutil.SeedEvent(db, e); err != nil {
+				t.Fatalf("Failed to seed event: %v", err)
+			}
+		}
+	}
+	lastState := func(t *testing.T, id string) domain.EventType {
+		t.Helper()
+		events, err := testutil.GetEventsByAggregate(db, id)
+		if err != nil {
+			t.Fatalf("Failed to load events: %v", err)
+		}
+		return events[len(events)-1].EventType
+	}
+	verify := func(id string, tracks ...integration.AudioTrack) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+		detector := &audioProbingChecker{MockHealthChecker: &testutil.MockHealthChecker{}, tracks: tracks}
+		v := NewVerifierService(eb, detector, nil, nil, db)
+		v.verifyHealthMultiple(id, []string{"/media/anime/" + id + ".new.mkv"})
+	}
+
+	t.Run("replacement without the dub is flagged", func(t *testing.T) {
+		seed(t, "dub")
+		verify("dub", integration.AudioTrack{Language: "jpn", Channels: 6})
+		if got := lastState(t, "dub"); got != domain.AudioTrackMissing {
+			t.Errorf("Expected AudioTrackMissing, got %s", got)
+		}
+	})
+
+	t.Run("replacement with the same audio verifies", func(t *testing.T) {
+		seed(t, "same")
+		verify("same", integration.AudioTrack{Language: "eng", Channels: 6}, integration.AudioTrack{Language: "jpn", Channels: 6})
+		if got := lastState(t, "same"); got != domain.VerificationSuccess {
+			t.Errorf("Expected VerificationSuccess, got %s", got)
+		}
+	})
+
+	t.Run("disabled check verifies", func(t *testing.T) {
+		config.SetForTesting(config.NewTestConfig())
+		seed(t, "disabled")
+		verify("disabled", integration.AudioTrack{Language: "jpn", Channels: 2})
+		if got := lastState(t, "disabled"); got != domain.VerificationSuccess {
+			t.Errorf("Expected VerificationSuccess, got %s", got)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+)
+
+// tagQueryTimeout bounds corruption tag and saved filter queries.
+const tagQueryTimeout = 5 * time.Second
+
+// maxTagLength is the longest tag accepted.
+const maxTagLength = 64
+
+var (
+	// ErrInvalidTag is returned for an empty or over-long tag, or one containing a comma.
+	ErrInvalidTag = errors.New("tags must be 1-64 characters and must not contain commas")
+	// ErrSavedFilterNotFound is returned when a saved filter doesn't exist.
+	ErrSavedFilterNotFound = errors.New("saved filter not found")
+	// ErrSavedFilterExists is returned when another saved filter has the same name.
+	ErrSavedFilterExists = errors.New("a saved filter with this name already exists")
+	// ErrInvalidSavedFilter is returned for a saved filter without a name.
+	ErrInvalidSavedFilter = errors.New("saved filter needs a name")
+)
+
+// TagCount is a tag and the number of corruptions carrying it.
+type TagCount struct {
+	Tag         string `json:"tag"`
+	Corruptions int    `json:"corruptions"`
+}
+
+// SavedFilter is a named corruption list filter. A corruption matches when its
+// state matches any of Statuses, it carries all of Tags and it belongs to any
+// of PathIDs; empty lists match everything.
+type SavedFilter struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Statuses  []string `json:"statuses"`
+	Tags      []string `json:"tags"`
+	PathIDs   []int64  `json:"path_ids"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// TagService manages corruption tags and saved corruption filters.
+type TagService struct {
+	db *sql.DB
+}
+
+// NewTagService creates a new tag service.
+func NewTagService(db *sql.DB) *TagService {
+	return &TagService{db: db}
+}
+
+// NormalizeTags trims and lower-cases tags and drops duplicates. It fails if
+// any tag is empty, longer than 64 characters or contains a comma, which
+// separates tags in query parameters.
+func NormalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > maxTagLength || strings.Contains(tag, ",") {
+			return nil, ErrInvalidTag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// AddTags attaches tags to corruptions. Tags a corruption already has are kept once.
+func (t *TagService) AddTags(corruptionIDs, tags []string) error {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	for _, id := range corruptionIDs {
+		for _, tag := range tags {
+			if _, err := db.ExecWithRetry(t.db, `
+				INSERT OR IGNORE INTO corruption_tags (corruption_id, tag) VALUES (?, ?)
+			`, id, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveTags detaches tags from corruptions.
+func (t *TagService) RemoveTags(corruptionIDs, tags []string) error {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	for _, id := range corruptionIDs {
+		for _, tag := range tags {
+			if _, err := db.ExecWithRetry(t.db, `
+				DELETE FROM corruption_tags WHERE corruption_id = ? AND tag = ?
+			`, id, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteCorruption removes all tags of a deleted corruption.
+func (t *TagService) DeleteCorruption(corruptionID string) error {
+	_, err := db.ExecWithRetry(t.db, `DELETE FROM corruption_tags WHERE corruption_id = ?`, corruptionID)
+	return err
+}
+
+// TagsFor returns the tags of the given corruptions, sorted by name.
+// Corruptions without tags are left out.
+func (t *TagService) TagsFor(ctx context.Context, corruptionIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(corruptionIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(corruptionIDs)), ",")
+	args := make([]interface{}, len(corruptionIDs))
+	for i, id := range corruptionIDs {
+		args[i] = id
+	}
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT corruption_id, tag FROM corruption_tags
+		WHERE corruption_id IN (`+placeholders+`)
+		ORDER BY tag
+	`, args...) // NOSONAR - placeholders only, values are in args
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], tag)
+	}
+	return result, rows.Err()
+}
+
+// ListTags returns every tag in use with the number of corruptions carrying it.
+func (t *TagService) ListTags(ctx context.Context) ([]TagCount, error) {
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT tag, COUNT(*) FROM corruption_tags GROUP BY tag ORDER BY tag
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]TagCount, 0)
+	for rows.Next() {
+		var tc TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Corruptions); err != nil {
+			return nil, err
+		}
+		result = append(result, tc)
+	}
+	return result, rows.Err()
+}
+
+// ListFilters returns all saved filters, sorted by name.
+func (t *TagService) ListFilters() ([]SavedFilter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tagQueryTimeout)
+	defer cancel()
+
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT id, name, statuses, tags, path_ids, created_at, updated_at
+		FROM saved_filters ORDER BY name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]SavedFilter, 0)
+	for rows.Next() {
+		f, err := scanSavedFilter(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *f)
+	}
+	return result, rows.Err()
+}
+
+// GetFilter returns a saved filter.
+func (t *TagService) GetFilter(id int64) (*SavedFilter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tagQueryTimeout)
+	defer cancel()
+
+	row := t.db.QueryRowContext(ctx, `
+		SELECT id, name, statuses, tags, path_ids, created_at, updated_at
+		FROM saved_filters WHERE id = ?
+	`, id)
+	f, err := scanSavedFilter(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavedFilterNotFound
+	}
+	return f, err
+}
+
+// CreateFilter stores a new saved filter and returns its ID.
+func (t *TagService) CreateFilter(f *SavedFilter) (int64, error) {
+	statuses, tags, pathIDs, err := encodeSavedFilter(f)
+	if err != nil {
+		return 0, err
+	}
+	if t.nameTaken(f.Name, 0) {
+		return 0, ErrSavedFilterExists
+	}
+
+	result, err := db.ExecWithRetry(t.db, `
+		INSERT INTO saved_filters (name, statuses, tags, path_ids) VALUES (?, ?, ?, ?)
+	`, f.Name, statuses, tags, pathIDs)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateFilter replaces the name and criteria of a saved filter.
+func (t *TagService) UpdateFilter(id int64, f *SavedFilter) error {
+	statuses, tags, pathIDs, err := encodeSavedFilter(f)
+	if err != nil {
+		return err
+	}
+	if t.nameTaken(f.Name, id) {
+		return ErrSavedFilterExists
+	}
+
+	result, err := db.ExecWithRetry(t.db, `
+		UPDATE saved_filters
+		SET name = ?, statuses = ?, tags = ?, path_ids = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, f.Name, statuses, tags, pathIDs, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrSavedFilterNotFound
+	}
+	return nil
+}
+
+// DeleteFilter removes a saved filter.
+func (t *TagService) DeleteFilter(id int64) error {
+	result, err := db.ExecWithRetry(t.db, `DELETE FROM saved_filters WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrSavedFilterNotFound
+	}
+	return nil
+}
+
+// nameTaken reports whether a saved filter other than exceptID has this name.
+func (t *TagService) nameTaken(name string, exceptID int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), tagQueryTimeout)
+	defer cancel()
+
+	var id int64
+	err := t.db.QueryRowContext(ctx, `SELECT id FROM saved_filters WHERE name = ? AND id != ?`, name, exceptID).Scan(&id)
+	return err == nil
+}
+
+// encodeSavedFilter validates a saved filter and returns its lists as JSON.
+func encodeSavedFilter(f *SavedFilter) (statuses, tags, pathIDs string, err error) {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" {
+		return "", "", "", ErrInvalidSavedFilter
+	}
+	if f.Tags, err = NormalizeTags(f.Tags); err != nil {
+		return "", "", "", err
+	}
+	if f.Statuses == nil {
+		f.Statuses = []string{}
+	}
+	if f.PathIDs == nil {
+		f.PathIDs = []int64{}
+	}
+
+	encoded := make([]string, 3)
+	for i, list := range []interface{}{f.Statuses, f.Tags, f.PathIDs} {
+		b, err := json.Marshal(list)
+		if err != nil {
+			return "", "", "", err
+		}
+		encoded[i] = string(b)
+	}
+	return encoded[0], encoded[1], encoded[2], nil
+}
+
+// scanSavedFilter reads a saved_filters row.
+func scanSavedFilter(row interface{ Scan(...interface{}) error }) (*SavedFilter, error) {
+	var f SavedFilter
+	var statuses, tags, pathIDs string
+	if err := row.Scan(&f.ID, &f.Name, &statuses, &tags, &pathIDs, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	f.Statuses, f.Tags, f.PathIDs = []string{}, []string{}, []int64{}
+	if err := json.Unmarshal([]byte(statuses), &f.Statuses); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tags), &f.Tags); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(pathIDs), &f.PathIDs); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
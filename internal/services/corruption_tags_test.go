@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Disk-Failure ", "disk-failure", "rare"})
+	if err != nil || !reflect.DeepEqual(tags, []string{"disk-failure", "rare"}) {
+		t.Errorf("NormalizeTags() = %v, %v", tags, err)
+	}
+	for _, bad := range [][]string{{""}, {"a,b"}, {string(make([]byte, maxTagLength+1))}} {
+		if _, err := NormalizeTags(bad); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("NormalizeTags(%q) error = %v, want ErrInvalidTag", bad, err)
+		}
+	}
+}
+
+func TestTagService(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	tags := NewTagService(db)
+	ctx := context.Background()
+
+	if err := tags.AddTags([]string{"a", "b"}, []string{"rare", "Disk"}); err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+	if err := tags.AddTags([]string{"a"}, []string{"rare"}); err != nil {
+		t.Fatalf("AddTags() again error = %v", err)
+	}
+	if err := tags.DeleteCorruption("b"); err != nil {
+		t.Fatalf("DeleteCorruption() error = %v", err)
+	}
+	got, err := tags.TagsFor(ctx, []string{"a", "b"})
+	if err != nil || !reflect.DeepEqual(got, map[string][]string{"a": {"disk", "rare"}}) {
+		t.Errorf("TagsFor() = %v, %v", got, err)
+	}
+
+	id, err := tags.CreateFilter(&SavedFilter{Name: " Rare ", Tags: []string{"RARE"}})
+	if err != nil {
+		t.Fatalf("CreateFilter() error = %v", err)
+	}
+	f, err := tags.GetFilter(id)
+	if err != nil || f.Name != "Rare" || !reflect.DeepEqual(f.Tags, []string{"rare"}) || f.Statuses == nil || f.PathIDs == nil {
+		t.Errorf("GetFilter() = %+v, %v", f, err)
+	}
+	if _, err := tags.CreateFilter(&SavedFilter{Name: "  "}); !errors.Is(err, ErrInvalidSavedFilter) {
+		t.Errorf("Expected ErrInvalidSavedFilter, got %v", err)
+	}
+	if err := tags.UpdateFilter(id+1, &SavedFilter{Name: "Other"}); !errors.Is(err, ErrSavedFilterNotFound) {
+		t.Errorf("Expected ErrSavedFilterNotFound, got %v", err)
+	}
+	if err := tags.UpdateFilter(id, &SavedFilter{Name: "Rare"}); err != nil {
+		t.Errorf("Renaming a filter to its own name failed: %v", err)
+	}
+}
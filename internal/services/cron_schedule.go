@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+// ParseSchedule parses a standard cron expression, read in timing's timezone,
+// or the one of a CRON_TZ= prefix, or the scheduler's (see cronLocation).
+func ParseSchedule(expr string, timing domain.ScheduleTiming) (cron.Schedule, error) {
+	if err := timing.Validate(); err != nil {
+		return nil, err
+	}
+	hasPrefix := strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=")
+	if timing.Timezone != "" {
+		if hasPrefix {
+			return nil, fmt.Errorf("cron expression %q already sets a timezone", expr)
+		}
+		expr = "CRON_TZ=" + timing.Timezone + " " + expr
+	}
+
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		// @every runs at fixed intervals, which DST changes don't affect
+		return schedule, nil
+	}
+	if timing.Timezone == "" && !hasPrefix {
+		spec.Location = cronLocation()
+	}
+	timing = timing.WithDefaults()
+	return &dstSchedule{spec: spec, gap: timing.DSTGap, overlap: timing.DSTOverlap}, nil
+}
+
+// dstSchedule applies the DST policies to a cron schedule. Left alone, cron
+// skips times in a gap and runs times in an overlap twice.
+type dstSchedule struct {
+	spec    *cron.SpecSchedule
+	gap     string
+	overlap string
+}
+
+// maxOverlapSkips bounds the repeated times Next skips, in case a schedule
+// only ever fires in overlaps.
+const maxOverlapSkips = 100
+
+// Next returns the next run after t.
+func (s *dstSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < maxOverlapSkips; i++ {
+		next := s.spec.Next(t)
+		if s.gap == domain.DSTGapRun {
+			if shifted, ok := s.gapRun(t, next); ok {
+				return shifted
+			}
+		}
+		if next.IsZero() || s.overlap == domain.DSTOverlapTwice || !repeatedTime(next.In(s.spec.Location)) {
+			return next
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// gapRun returns the run for a time after t that falls into a gap and cron
+// skipped in favour of next, moved to right after the jump.
+func (s *dstSchedule) gapRun(t, next time.Time) (time.Time, bool) {
+	// A fixed offset has no gaps, so cron keeps the skipped wall times there
+	_, offset := t.In(s.spec.Location).Zone()
+	fixed := *s.spec
+	fixed.Location = time.FixedZone("", offset)
+	candidate := fixed.Next(t).In(fixed.Location)
+	if candidate.IsZero() {
+		return time.Time{}, false
+	}
+
+	loc := s.spec.Location
+	wall := time.Date(candidate.Year(), candidate.Month(), candidate.Day(),
+		candidate.Hour(), candidate.Minute(), candidate.Second(), 0, time.UTC)
+	if wallTimeExists(wall, loc) {
+		return time.Time{}, false
+	}
+	// Read the wall time with the offset from before the jump
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	run := wall.Add(-time.Duration(before) * time.Second).In(t.Location())
+	if !run.After(t) || (!next.IsZero() && !run.Before(next)) {
+		return time.Time{}, false
+	}
+	return run, true
+}
+
+// wallTimeExists reports whether the wall time (given in UTC) occurs in loc,
+// checking the offsets of the day before and after.
+func wallTimeExists(wall time.Time, loc *time.Location) bool {
+	for _, probe := range []time.Time{wall.Add(-24 * time.Hour), wall.Add(24 * time.Hour)} {
+		_, offset := probe.In(loc).Zone()
+		if _, actual := wall.Add(-time.Duration(offset) * time.Second).In(loc).Zone(); actual == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// repeatedTime reports whether t is the second occurrence of its wall time,
+// after clocks went back.
+func repeatedTime(t time.Time) bool {
+	_, offset := t.Zone()
+	_, before := t.Add(-24 * time.Hour).Zone()
+	if before <= offset {
+		return false
+	}
+	first := t.Add(-time.Duration(before-offset) * time.Second)
+	_, firstOffset := first.Zone()
+	return firstOffset == before && first.Hour() == t.Hour() && first.Minute() == t.Minute() && first.Second() == t.Second()
+}
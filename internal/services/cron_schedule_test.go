@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+)
+
+func TestParseSchedule_DSTPolicies(t *testing.T) {
+	// New York skips 02:00-03:00 on 2024-03-10 and repeats 01:00-02:00 on 2024-11-03
+	tests := []struct {
+		name   string
+		expr   string
+		timing domain.ScheduleTiming
+		from   string
+		want   []string
+	}{
+		{
+			name:   "gap runs after the jump",
+			expr:   "30 2 * * *",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York"},
+			from:   "2024-03-09T03:00:00-05:00",
+			want:   []string{"2024-03-10T03:30:00-04:00", "2024-03-11T02:30:00-04:00"},
+		},
+		{
+			name:   "gap skipped",
+			expr:   "30 2 * * *",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York", DSTGap: domain.DSTGapSkip},
+			from:   "2024-03-09T03:00:00-05:00",
+			want:   []string{"2024-03-11T02:30:00-04:00"},
+		},
+		{
+			name:   "weekly gap runs after the jump",
+			expr:   "30 2 * * 0",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York"},
+			from:   "2024-03-04T00:00:00-05:00",
+			want:   []string{"2024-03-10T03:30:00-04:00", "2024-03-17T02:30:00-04:00"},
+		},
+		{
+			name:   "hourly runs are not doubled by the gap",
+			expr:   "0 * * * *",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York"},
+			from:   "2024-03-10T00:30:00-05:00",
+			want:   []string{"2024-03-10T01:00:00-05:00", "2024-03-10T03:00:00-04:00", "2024-03-10T04:00:00-04:00"},
+		},
+		{
+			name:   "overlap runs once",
+			expr:   "30 1 * * *",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York"},
+			from:   "2024-11-02T12:00:00-04:00",
+			want:   []string{"2024-11-03T01:30:00-04:00", "2024-11-04T01:30:00-05:00"},
+		},
+		{
+			name:   "overlap runs twice",
+			expr:   "30 1 * * *",
+			timing: domain.ScheduleTiming{Timezone: "America/New_York", DSTOverlap: domain.DSTOverlapTwice},
+			from:   "2024-11-02T12:00:00-04:00",
+			want:   []string{"2024-11-03T01:30:00-04:00", "2024-11-03T01:30:00-05:00", "2024-11-04T01:30:00-05:00"},
+		},
+		{
+			name: "timezone from the expression",
+			expr: "CRON_TZ=Asia/Tokyo 0 6 * * *",
+			from: "2024-01-01T00:00:00Z",
+			want: []string{"2024-01-01T21:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr, tt.timing)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			next, err := time.Parse(time.RFC3339, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				next = schedule.Next(next)
+				if got := next.Format(time.RFC3339); !next.Equal(mustParseRFC3339(t, want)) {
+					t.Errorf("run %d = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		timing domain.ScheduleTiming
+	}{
+		{"cron expression", "not cron", domain.ScheduleTiming{}},
+		{"timezone", "0 3 * * *", domain.ScheduleTiming{Timezone: "Mars/Olympus"}},
+		{"local timezone", "0 3 * * *", domain.ScheduleTiming{Timezone: "Local"}},
+		{"timezone set twice", "CRON_TZ=UTC 0 3 * * *", domain.ScheduleTiming{Timezone: "Europe/Berlin"}},
+		{"gap policy", "0 3 * * *", domain.ScheduleTiming{DSTGap: "later"}},
+		{"overlap policy", "0 3 * * *", domain.ScheduleTiming{DSTOverlap: "never"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchedule(tt.expr, tt.timing); err == nil {
+				t.Error("ParseSchedule() should fail")
+			}
+		})
+	}
+}
+
+func mustParseRFC3339(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dailyWindow is a daily window, in minutes after midnight, that may wrap past midnight.
+type dailyWindow struct {
+	start, end int
+}
+
+// parseDailyWindow parses a window such as "17:00-23:00" or "22:00-06:00".
+func parseDailyWindow(s string) (*dailyWindow, error) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(s, " ", ""), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: expected HH:MM-HH:MM", s)
+	}
+	parse := func(v string) (int, error) {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q: %w", s, err)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	start, err := parse(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parse(to)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid window %q: start and end are equal", s)
+	}
+	return &dailyWindow{start: start, end: end}, nil
+}
+
+// contains reports whether t falls within the window.
+func (p *dailyWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if p.start < p.end {
+		return minute >= p.start && minute < p.end
+	}
+	return minute >= p.start || minute < p.end
+}
+
+// nextStart returns the next time the window opens after t.
+func (p *dailyWindow) nextStart(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), p.start/60, p.start%60, 0, 0, t.Location())
+	if !start.After(t) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDailyWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		spec      string
+		in        []time.Time
+		out       []time.Time
+		nextStart time.Time
+	}{
+		{"17:00-23:00", []time.Time{at(17, 0), at(22, 59)}, []time.Time{at(16, 59), at(23, 0), at(3, 0)}, at(17, 0)},
+		{"22:00 - 06:30", []time.Time{at(23, 0), at(2, 0), at(6, 29)}, []time.Time{at(6, 30), at(12, 0)}, at(22, 0)},
+	}
+	for _, tt := range tests {
+		p, err := parseDailyWindow(tt.spec)
+		if err != nil {
+			t.Fatalf("parseDailyWindow(%q) error = %v", tt.spec, err)
+		}
+		for _, in := range tt.in {
+			if !p.contains(in) {
+				t.Errorf("%q should contain %s", tt.spec, in.Format("15:04"))
+			}
+		}
+		for _, out := range tt.out {
+			if p.contains(out) {
+				t.Errorf("%q should not contain %s", tt.spec, out.Format("15:04"))
+			}
+		}
+		if got := p.nextStart(at(12, 0)); !got.Equal(tt.nextStart) {
+			t.Errorf("%q nextStart = %s, want %s", tt.spec, got, tt.nextStart)
+		}
+	}
+
+	if got := mustParseDailyWindow(t, "17:00-23:00").nextStart(at(18, 0)); !got.Equal(at(17, 0).AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next window to open tomorrow, got %s", got)
+	}
+	for _, spec := range []string{"17:00", "5pm-11pm", "25:00-03:00", "08:00-08:00"} {
+		if _, err := parseDailyWindow(spec); err == nil {
+			t.Errorf("parseDailyWindow(%q) should fail", spec)
+		}
+	}
+}
+
+func mustParseDailyWindow(t *testing.T, spec string) *dailyWindow {
+	t.Helper()
+	p, err := parseDailyWindow(spec)
+	if err != nil {
+		t.Fatalf("parseDailyWindow(%q) error = %v", spec, err)
+	}
+	return p
+}
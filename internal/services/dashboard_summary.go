@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// dashboardResyncInterval is how often the dashboard summary is reloaded from
+// the database, to pick up changes made outside of events such as a summary
+// repair, an event replay or pruned corruptions.
+const dashboardResyncInterval = 10 * time.Minute
+
+// downloadingStates are the states of a corruption whose replacement is being
+// downloaded: grabbed by the *arr and queued, or downloading.
+var downloadingStates = map[domain.EventType]bool{
+	domain.SearchCompleted:  true,
+	domain.DownloadProgress: true,
+}
+
+// attentionStates are the states of a corruption that waits on the user.
+var attentionStates = map[domain.EventType]bool{
+	domain.ImportBlocked:          true,
+	domain.ManuallyRemoved:        true,
+	domain.BudgetApprovalRequired: true,
+	domain.LowConfidenceDetection: true,
+	domain.QualityRegression:      true,
+	domain.AudioTrackMissing:      true,
+	domain.DeletionPlanChanged:    true,
+	domain.MaxRetriesReached:      true,
+}
+
+// PathLastScan is the most recent finished scan of a scan path.
+type PathLastScan struct {
+	PathID           int64  `json:"path_id,omitempty"`
+	Path             string `json:"path"`
+	ScanID           int64  `json:"scan_id"`
+	Status           string `json:"status"`
+	CompletedAt      string `json:"completed_at"`
+	FilesScanned     int    `json:"files_scanned"`
+	CorruptionsFound int    `json:"corruptions_found"`
+}
+
+// DashboardSnapshot is a copy of the dashboard summary.
+type DashboardSnapshot struct {
+	Corruptions     int            `json:"corruptions"`      // all tracked corruptions, ignored ones included
+	States          map[string]int `json:"states"`           // corruptions per current state
+	ActiveDownloads int            `json:"active_downloads"` // replacements grabbed or downloading
+	NeedsAttention  int            `json:"needs_attention"`  // corruptions waiting on the user
+	LastScans       []PathLastScan `json:"last_scans"`       // per path, sorted by path
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// DashboardSummary is the dashboard's read model, kept in memory. It is loaded
+// from the database once and then updated by Project with every stored event,
+// so reading it costs no queries.
+type DashboardSummary struct {
+	db *sql.DB
+
+	mu        sync.RWMutex
+	states    map[string]domain.EventType // current state of each corruption
+	counts    map[domain.EventType]int
+	lastScans []PathLastScan
+	updatedAt time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDashboardSummary creates the dashboard summary and loads it from the database.
+func NewDashboardSummary(database *sql.DB) *DashboardSummary {
+	d := &DashboardSummary{
+		db:     database,
+		states: make(map[string]domain.EventType),
+		counts: make(map[domain.EventType]int),
+		stopCh: make(chan struct{}),
+	}
+	if err := d.Reload(); err != nil {
+		logger.Warnf("Failed to load the dashboard summary: %v", err)
+	}
+	return d
+}
+
+// Start reloads the summary from the database periodically until Stop.
+func (d *DashboardSummary) Start() {
+	go func() {
+		ticker := time.NewTicker(dashboardResyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := d.Reload(); err != nil {
+					logger.Warnf("Failed to reload the dashboard summary: %v", err)
+				}
+			case <-d.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic reload.
+func (d *DashboardSummary) Stop() {
+	d.stopOnce.Do(func() { close(d.stopCh) })
+}
+
+// Reload replaces the summary with the state in the database.
+func (d *DashboardSummary) Reload() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+
+	rows, err := d.db.QueryContext(ctx, `SELECT corruption_id, current_state FROM corruption_summary`)
+	if err != nil {
+		return fmt.Errorf("failed to load corruption states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]domain.EventType)
+	counts := make(map[domain.EventType]int)
+	for rows.Next() {
+		var id, state string
+		if err := rows.Scan(&id, &state); err != nil {
+			return fmt.Errorf("failed to load corruption states: %w", err)
+		}
+		states[id] = domain.EventType(state)
+		counts[domain.EventType(state)]++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load corruption states: %w", err)
+	}
+
+	lastScans, err := loadLastScans(ctx, d.db)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.states = states
+	d.counts = counts
+	d.lastScans = lastScans
+	d.updatedAt = time.Now().UTC()
+	d.mu.Unlock()
+	return nil
+}
+
+// Project applies a stored event to the summary. It is an event bus projector,
+// so events arrive in order, within the transaction that stores them. If that
+// transaction is rolled back, the summary is off until the next reload.
+func (d *DashboardSummary) Project(tx *sql.Tx, event domain.Event) error {
+	switch {
+	case event.AggregateType == "corruption":
+		d.mu.Lock()
+		if prev, ok := d.states[event.AggregateID]; ok {
+			d.counts[prev]--
+			if d.counts[prev] <= 0 {
+				delete(d.counts, prev)
+			}
+		}
+		d.states[event.AggregateID] = event.EventType
+		d.counts[event.EventType]++
+		d.updatedAt = time.Now().UTC()
+		d.mu.Unlock()
+
+	case event.EventType == domain.ScanCompleted:
+		// The scan record is updated before ScanCompleted is published
+		ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+		defer cancel()
+		lastScans, err := loadLastScans(ctx, tx)
+		if err != nil {
+			return err
+		}
+		d.mu.Lock()
+		d.lastScans = lastScans
+		d.updatedAt = time.Now().UTC()
+		d.mu.Unlock()
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the summary.
+func (d *DashboardSummary) Snapshot() DashboardSnapshot {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	snap := DashboardSnapshot{
+		Corruptions: len(d.states),
+		States:      make(map[string]int, len(d.counts)),
+		LastScans:   append([]PathLastScan(nil), d.lastScans...),
+		UpdatedAt:   d.updatedAt,
+	}
+	for state, n := range d.counts {
+		snap.States[string(state)] = n
+		if downloadingStates[state] {
+			snap.ActiveDownloads += n
+		}
+		if attentionStates[state] {
+			snap.NeedsAttention += n
+		}
+	}
+	return snap
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// loadLastScans returns the most recent finished scan of each scan path.
+func loadLastScans(ctx context.Context, q queryer) ([]PathLastScan, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT COALESCE(s.path_id, 0), s.path, s.id, s.status, s.completed_at,
+			COALESCE(s.files_scanned, 0), COALESCE(s.corruptions_found, 0)
+		FROM scans s
+		JOIN (
+			SELECT MAX(id) AS id FROM scans
+			WHERE completed_at IS NOT NULL
+			GROUP BY path
+		) last ON last.id = s.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last scans: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []PathLastScan
+	for rows.Next() {
+		var ls PathLastScan
+		if err := rows.Scan(&ls.PathID, &ls.Path, &ls.ScanID, &ls.Status, &ls.CompletedAt, &ls.FilesScanned, &ls.CorruptionsFound); err != nil {
+			return nil, fmt.Errorf("failed to load last scans: %w", err)
+		}
+		scans = append(scans, ls)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load last scans: %w", err)
+	}
+	sort.Slice(scans, func(i, j int) bool { return scans[i].Path < scans[j].Path })
+	return scans, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestDashboardSummary_LoadAndProject(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at) VALUES
+			('a', '/media/a.mkv', 'CorruptionDetected', datetime('now'), datetime('now')),
+			('b', '/media/b.mkv', 'DownloadProgress', datetime('now'), datetime('now')),
+			('c', '/media/c.mkv', 'ImportBlocked', datetime('now'), datetime('now'))
+	`); err != nil {
+		t.Fatalf("Failed to seed corruptions: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO scans (path, path_id, status, files_scanned, corruptions_found, completed_at) VALUES
+			('/media/movies', 1, 'completed', 10, 1, '2026-04-01 10:00:00'),
+			('/media/movies', 1, 'completed', 12, 0, '2026-04-02 10:00:00'),
+			('/media/tv', 2, 'cancelled', 3, 0, '2026-04-01 12:00:00')
+	`); err != nil {
+		t.Fatalf("Failed to seed scans: %v", err)
+	}
+
+	d := NewDashboardSummary(db)
+	defer d.Stop()
+
+	snap := d.Snapshot()
+	if snap.Corruptions != 3 || snap.ActiveDownloads != 1 || snap.NeedsAttention != 1 {
+		t.Fatalf("Unexpected loaded summary: %+v", snap)
+	}
+	if len(snap.LastScans) != 2 || snap.LastScans[0].Path != "/media/movies" || snap.LastScans[0].FilesScanned != 12 {
+		t.Fatalf("Expected the last scan of each path, got %+v", snap.LastScans)
+	}
+
+	// Events move corruptions between states
+	project := func(event domain.Event) {
+		t.Helper()
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin tx: %v", err)
+		}
+		defer tx.Rollback()
+		if err := d.Project(tx, event); err != nil {
+			t.Fatalf("Project() error = %v", err)
+		}
+	}
+	project(domain.Event{AggregateType: "corruption", AggregateID: "a", EventType: domain.SearchCompleted})
+	project(domain.Event{AggregateType: "corruption", AggregateID: "b", EventType: domain.VerificationSuccess})
+	project(domain.Event{AggregateType: "corruption", AggregateID: "d", EventType: domain.CorruptionDetected})
+
+	snap = d.Snapshot()
+	if snap.Corruptions != 4 || snap.ActiveDownloads != 1 || snap.NeedsAttention != 1 {
+		t.Errorf("Unexpected summary after events: %+v", snap)
+	}
+	if snap.States["CorruptionDetected"] != 1 || snap.States["VerificationSuccess"] != 1 {
+		t.Errorf("Unexpected state counts: %v", snap.States)
+	}
+	if _, ok := snap.States["DownloadProgress"]; ok {
+		t.Errorf("Expected empty states to be dropped, got %v", snap.States)
+	}
+
+	// A finished scan refreshes the last scans
+	if _, err := db.Exec(`INSERT INTO scans (path, path_id, status, files_scanned, completed_at) VALUES ('/media/tv', 2, 'completed', 7, '2026-04-03 09:00:00')`); err != nil {
+		t.Fatalf("Failed to add scan: %v", err)
+	}
+	project(domain.Event{AggregateType: "scan", AggregateID: "s1", EventType: domain.ScanCompleted})
+	snap = d.Snapshot()
+	if len(snap.LastScans) != 2 || snap.LastScans[1].Status != "completed" || snap.LastScans[1].FilesScanned != 7 {
+		t.Errorf("Expected the new scan of /media/tv, got %+v", snap.LastScans)
+	}
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// budgetCheckInterval is how often the remediator re-checks the monthly data
+// budget, so a paused remediator resumes when a new month starts.
+const budgetCheckInterval = time.Minute
+
+// inFlightStates are the corruption states between deleting a file and
+// verifying its replacement. Their download is still to come.
+var inFlightStates = []string{
+	string(domain.DeletionCompleted), string(domain.SearchStarted), string(domain.SearchCompleted),
+	string(domain.DownloadProgress), string(domain.DownloadRejected), string(domain.FileDetected), string(domain.VerificationStarted),
+}
+
+// BandwidthUsage is the data remediation moved in one day or month.
+type BandwidthUsage struct {
+	Period          string `json:"period"`           // YYYY-MM-DD or YYYY-MM (UTC)
+	DeletedBytes    int64  `json:"deleted_bytes"`    // size of the corrupt files deleted
+	DownloadedBytes int64  `json:"downloaded_bytes"` // size of the verified replacements
+	Deletions       int    `json:"deletions"`
+	Replacements    int    `json:"replacements"`
+}
+
+// DataBudgetStatus is the remediation data used in the current month against
+// the monthly budget.
+type DataBudgetStatus struct {
+	Month           string `json:"month"` // YYYY-MM (UTC)
+	DownloadedBytes int64  `json:"downloaded_bytes"`
+	// EstimatedPendingBytes is the size of deleted files whose replacement
+	// hasn't been verified yet, used as an estimate of downloads to come.
+	EstimatedPendingBytes int64 `json:"estimated_pending_bytes"`
+	UsedBytes             int64 `json:"used_bytes"`   // downloaded plus estimated pending
+	BudgetBytes           int64 `json:"budget_bytes"` // 0 when no budget is set
+	Exceeded              bool  `json:"exceeded"`
+}
+
+// deletedSizeSQL is the size of a corruption's deleted file: recorded at
+// deletion, or from detection for deletions made before sizes were recorded.
+const deletedSizeSQL = `COALESCE(
+	json_extract(e.event_data, '$.file_size'),
+	(SELECT json_extract(d.event_data, '$.file_size') FROM events d
+	 WHERE d.aggregate_id = e.aggregate_id AND d.event_type = 'CorruptionDetected' LIMIT 1),
+	0)`
+
+// LoadBandwidthUsage returns the remediation data moved per day, or per month
+// when monthly is set, since the given time. Periods without any deletions or
+// replacements are left out.
+func LoadBandwidthUsage(ctx context.Context, db *sql.DB, monthly bool, since time.Time) ([]BandwidthUsage, error) {
+	periodLen := 10 // YYYY-MM-DD
+	if monthly {
+		periodLen = 7 // YYYY-MM
+	}
+	query := fmt.Sprintf(`
+		SELECT substr(e.created_at, 1, %d) AS period,
+			COALESCE(SUM(CASE WHEN e.event_type = 'DeletionCompleted' THEN %s END), 0),
+			COALESCE(SUM(CASE WHEN e.event_type = 'VerificationSuccess' THEN json_extract(e.event_data, '$.new_file_size') END), 0),
+			COUNT(CASE WHEN e.event_type = 'DeletionCompleted' THEN 1 END),
+			COUNT(CASE WHEN e.event_type = 'VerificationSuccess' THEN 1 END)
+		FROM events e
+		WHERE e.event_type IN ('DeletionCompleted', 'VerificationSuccess')
+		AND substr(e.created_at, 1, 10) >= ?
+		GROUP BY period
+		ORDER BY period ASC
+	`, periodLen, deletedSizeSQL) // NOSONAR - only fixed fragments are formatted in
+
+	rows, err := db.QueryContext(ctx, query, since.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []BandwidthUsage{}
+	for rows.Next() {
+		var u BandwidthUsage
+		if err := rows.Scan(&u.Period, &u.DeletedBytes, &u.DownloadedBytes, &u.Deletions, &u.Replacements); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// LoadDataBudgetStatus returns the remediation data used in the month of now.
+// A budgetBytes of 0 means no budget, so it is never exceeded.
+func LoadDataBudgetStatus(ctx context.Context, db *sql.DB, now time.Time, budgetBytes int64) (DataBudgetStatus, error) {
+	status := DataBudgetStatus{Month: now.UTC().Format("2006-01"), BudgetBytes: budgetBytes}
+
+	err := db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(json_extract(event_data, '$.new_file_size')), 0)
+		FROM events
+		WHERE event_type = 'VerificationSuccess' AND substr(created_at, 1, 7) = ?
+	`, status.Month).Scan(&status.DownloadedBytes)
+	if err != nil {
+		return status, fmt.Errorf("failed to sum downloaded bytes: %w", err)
+	}
+
+	args := make([]interface{}, len(inFlightStates))
+	for i, state := range inFlightStates {
+		args[i] = state
+	}
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(%s), 0)
+		FROM corruption_summary cs
+		JOIN events e ON e.id = (
+			SELECT id FROM events WHERE aggregate_id = cs.corruption_id AND event_type = 'DeletionCompleted'
+			ORDER BY id DESC LIMIT 1)
+		WHERE cs.current_state IN (?%s)
+	`, deletedSizeSQL, repeatPlaceholder(len(inFlightStates)-1)) // NOSONAR - only fixed fragments are formatted in
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&status.EstimatedPendingBytes); err != nil {
+		return status, fmt.Errorf("failed to estimate pending downloads: %w", err)
+	}
+
+	status.UsedBytes = status.DownloadedBytes + status.EstimatedPendingBytes
+	status.Exceeded = budgetBytes > 0 && status.UsedBytes >= budgetBytes
+	return status, nil
+}
+
+// repeatPlaceholder returns n ", ?" placeholders.
+func repeatPlaceholder(n int) string {
+	s := ""
+	for i := 0; i < n; i++ {
+		s += ", ?"
+	}
+	return s
+}
+
+// SetDataBudget sets the monthly remediation data budget in bytes. Once the
+// data downloaded this month plus the estimated size of pending downloads
+// reaches it, remediations wait in the queue until the next month. Zero
+// disables the budget. Must be called before Start.
+func (r *RemediatorService) SetDataBudget(monthlyBytes int64) {
+	r.dataBudget = monthlyBytes
+}
+
+// DataBudget returns the monthly remediation data budget in bytes, 0 if unset.
+func (r *RemediatorService) DataBudget() int64 {
+	return r.dataBudget
+}
+
+// SetDataBudgetAction sets what happens to a new remediation whose estimated
+// replacement would exceed the data budget: config.BudgetActionDefer holds it
+// in the queue until next month, config.BudgetActionApprove stops it until a
+// user approves it. Must be called before Start.
+func (r *RemediatorService) SetDataBudgetAction(action string) {
+	r.budgetAction = action
+}
+
+// DataBudgetAction returns the action for remediations over the data budget.
+func (r *RemediatorService) DataBudgetAction() string {
+	if r.budgetAction == "" {
+		return config.BudgetActionDefer
+	}
+	return r.budgetAction
+}
+
+// DeferredMonth returns the month whose remaining budget a deferred remediation
+// didn't fit in, or "" if no remediation is deferred.
+func (r *RemediatorService) DeferredMonth() string {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+	return r.budgetDeferredMonth
+}
+
+// admitWithinBudget reports whether a new remediation may go ahead. If the
+// size of the corrupt file, as an estimate of its replacement, would take this
+// month's usage past the data budget, the remediation is either deferred to
+// next month or stopped until approved. Approved remediations always go
+// ahead, also while the budget pauses the queue.
+func (r *RemediatorService) admitWithinBudget(corruptionID string, data domain.CorruptionEventData, approved bool) bool {
+	if r.dataBudget <= 0 || r.db == nil || approved {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	now := r.clk.Now()
+	status, err := LoadDataBudgetStatus(ctx, r.db, now, r.dataBudget)
+	if err != nil {
+		logger.Warnf("Failed to check remediation data budget for %s: %v", data.FilePath, err)
+		return true
+	}
+
+	projected := data.FileSize
+	if projected <= 0 {
+		if info, statErr := os.Stat(data.FilePath); statErr == nil {
+			projected = info.Size()
+		}
+	}
+	if status.UsedBytes+projected <= status.BudgetBytes {
+		return true
+	}
+
+	eventData := map[string]interface{}{
+		"file_path":       data.FilePath,
+		"path_id":         data.PathID,
+		"month":           status.Month,
+		"projected_bytes": projected,
+		"used_bytes":      status.UsedBytes,
+		"budget_bytes":    status.BudgetBytes,
+	}
+	reason := fmt.Sprintf("replacement of about %s would exceed the monthly data budget (%s of %s used in %s)",
+		formatBytes(projected), formatBytes(status.UsedBytes), formatBytes(status.BudgetBytes), status.Month)
+
+	eventType := domain.RemediationDeferred
+	if r.DataBudgetAction() == config.BudgetActionApprove {
+		eventType = domain.BudgetApprovalRequired
+		logger.Warnf("Remediation of %s needs approval: %s", data.FilePath, reason)
+	} else {
+		utc := now.UTC()
+		eventData["deferred_until"] = time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		logger.Warnf("Remediation of %s deferred to next month: %s", data.FilePath, reason)
+	}
+	eventData["reason"] = reason
+
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     eventType,
+		EventData:     eventData,
+	}); err != nil {
+		logger.Errorf("Failed to publish %s event: %v", eventType, err)
+	}
+
+	if eventType == domain.BudgetApprovalRequired {
+		return false
+	}
+	r.deferUntilNextMonth(status)
+	return true
+}
+
+// approveOverBudget lets the remediation of a corruption the user approved over
+// the data budget start while the budget pauses the others.
+func (r *RemediatorService) approveOverBudget(corruptionID string) {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+	if r.budgetApproved == nil {
+		r.budgetApproved = make(map[string]bool)
+	}
+	r.budgetApproved[corruptionID] = true
+}
+
+// takeBudgetApproval reports whether the remediation of a corruption was
+// approved over the data budget, and forgets the approval.
+func (r *RemediatorService) takeBudgetApproval(corruptionID string) bool {
+	r.budgetMu.Lock()
+	defer r.budgetMu.Unlock()
+	approved := r.budgetApproved[corruptionID]
+	delete(r.budgetApproved, corruptionID)
+	return approved
+}
+
+// deferUntilNextMonth holds all queued remediations until the month of status
+// is over, even if the budget isn't used up yet.
+func (r *RemediatorService) deferUntilNextMonth(status DataBudgetStatus) {
+	r.budgetMu.Lock()
+	r.budgetDeferredMonth = status.Month
+	r.budgetMu.Unlock()
+
+	if r.throttle.pauseAll() {
+		r.publishDataBudgetEvent(domain.RemediationBudgetExceeded, status, r.throttle.queuedTotal(),
+			fmt.Sprintf("%s of the %s monthly data budget used in %s, not enough left for the next replacement",
+				formatBytes(status.UsedBytes), formatBytes(status.BudgetBytes), status.Month))
+	}
+}
+
+// runBudgetWatch periodically re-checks the data budget.
+func (r *RemediatorService) runBudgetWatch() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.shutdownCh:
+			return
+		case <-ticker.C:
+			r.checkDataBudget()
+		}
+	}
+}
+
+// checkDataBudget pauses remediation when this month's data budget is used up
+// and resumes it once it isn't, e.g. when a new month starts. Remediations
+// deferred to next month keep it paused until the month is over. Approved
+// remediations aren't held by the pause.
+func (r *RemediatorService) checkDataBudget() {
+	if r.dataBudget <= 0 || r.db == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	status, err := LoadDataBudgetStatus(ctx, r.db, r.clk.Now(), r.dataBudget)
+	if err != nil {
+		logger.Warnf("Failed to check remediation data budget: %v", err)
+		return
+	}
+
+	r.budgetMu.Lock()
+	if r.budgetDeferredMonth != "" && r.budgetDeferredMonth != status.Month {
+		r.budgetDeferredMonth = ""
+	}
+	deferred := r.budgetDeferredMonth != ""
+	r.budgetMu.Unlock()
+	if deferred {
+		return
+	}
+
+	if status.Exceeded {
+		if r.throttle.pauseAll() {
+			r.publishDataBudgetEvent(domain.RemediationBudgetExceeded, status, r.throttle.queuedTotal(),
+				fmt.Sprintf("%s of the %s monthly data budget used in %s",
+					formatBytes(status.UsedBytes), formatBytes(status.BudgetBytes), status.Month))
+		}
+		return
+	}
+	if backlog := r.throttle.resumeAll(); backlog >= 0 {
+		r.publishDataBudgetEvent(domain.RemediationBudgetRestored, status, backlog,
+			fmt.Sprintf("%s of the %s monthly data budget used in %s, processing %d queued remediation(s)",
+				formatBytes(status.UsedBytes), formatBytes(status.BudgetBytes), status.Month, backlog))
+	}
+}
+
+func (r *RemediatorService) publishDataBudgetEvent(eventType domain.EventType, status DataBudgetStatus, queued int, reason string) {
+	if eventType == domain.RemediationBudgetExceeded {
+		logger.Warnf("Remediation data budget exceeded: %s - new remediations will be queued", reason)
+	} else {
+		logger.Infof("Remediation data budget available again: %s", reason)
+	}
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateType: "health",
+		AggregateID:   "data_budget",
+		EventType:     eventType,
+		EventData: map[string]interface{}{
+			"month":        status.Month,
+			"used_bytes":   status.UsedBytes,
+			"budget_bytes": status.BudgetBytes,
+			"queued":       queued,
+			"reason":       reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// formatBytes renders a byte count with a decimal unit, e.g. "1.5 GB".
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
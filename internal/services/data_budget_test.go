@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func seedBudgetEvent(t *testing.T, db *sql.DB, aggregateID string, eventType domain.EventType, data map[string]interface{}, at time.Time) {
+	t.Helper()
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal event data: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data, created_at)
+		VALUES ('corruption', ?, ?, ?, ?)
+	`, aggregateID, eventType, dataJSON, at.UTC().Format("2006-01-02 15:04:05")); err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+}
+
+func TestLoadBandwidthUsage(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	day1 := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	seedBudgetEvent(t, db, "a", domain.CorruptionDetected, map[string]interface{}{"file_path": "/a.mkv", "file_size": 900}, day1)
+	seedBudgetEvent(t, db, "a", domain.DeletionCompleted, map[string]interface{}{"media_id": 1, "file_size": 1000}, day1)
+	seedBudgetEvent(t, db, "a", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 1200}, day2)
+	// Deleted before sizes were recorded: falls back to the detected size
+	seedBudgetEvent(t, db, "b", domain.CorruptionDetected, map[string]interface{}{"file_path": "/b.mkv", "file_size": 300}, day2)
+	seedBudgetEvent(t, db, "b", domain.DeletionCompleted, map[string]interface{}{"media_id": 2}, day2)
+
+	daily, err := LoadBandwidthUsage(context.Background(), db, false, day1.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("LoadBandwidthUsage() error = %v", err)
+	}
+	want := []BandwidthUsage{
+		{Period: "2026-03-30", DeletedBytes: 1000, Deletions: 1},
+		{Period: "2026-04-02", DeletedBytes: 300, DownloadedBytes: 1200, Deletions: 1, Replacements: 1},
+	}
+	if len(daily) != len(want) {
+		t.Fatalf("Expected %d days, got %+v", len(want), daily)
+	}
+	for i := range want {
+		if daily[i] != want[i] {
+			t.Errorf("Day %d = %+v, want %+v", i, daily[i], want[i])
+		}
+	}
+
+	monthly, err := LoadBandwidthUsage(context.Background(), db, true, day2)
+	if err != nil {
+		t.Fatalf("LoadBandwidthUsage() error = %v", err)
+	}
+	if len(monthly) != 1 || monthly[0].Period != "2026-04" || monthly[0].DownloadedBytes != 1200 {
+		t.Errorf("Unexpected monthly usage since %s: %+v", day2.Format("2006-01-02"), monthly)
+	}
+}
+
+func TestRemediator_DataBudget(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)
+	seedBudgetEvent(t, db, "done", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 600}, now)
+	// Deleted and searching: its replacement is still to be downloaded
+	seedBudgetEvent(t, db, "pending", domain.DeletionCompleted, map[string]interface{}{"media_id": 1, "file_size": 500}, now)
+	if _, err := db.Exec(`
+		INSERT OR REPLACE INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at)
+		VALUES ('pending', '/pending.mkv', 'SearchCompleted', ?, ?)
+	`, now, now); err != nil {
+		t.Fatalf("Failed to seed corruption summary: %v", err)
+	}
+
+	bus := testutil.NewMockEventBus()
+	clk := testutil.NewMockClockAt(now)
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.clk = clk
+	r.throttle = newRemediationThrottle(RemediationThrottleConfig{}, clk)
+	r.SetDataBudget(1000)
+
+	status, err := LoadDataBudgetStatus(context.Background(), db, now, r.DataBudget())
+	if err != nil {
+		t.Fatalf("LoadDataBudgetStatus() error = %v", err)
+	}
+	if status.DownloadedBytes != 600 || status.EstimatedPendingBytes != 500 || status.UsedBytes != 1100 || !status.Exceeded {
+		t.Fatalf("Unexpected budget status: %+v", status)
+	}
+
+	r.checkDataBudget()
+	r.checkDataBudget()
+	exceeded := bus.GetEvents(domain.RemediationBudgetExceeded)
+	if len(exceeded) != 1 {
+		t.Fatalf("Expected one RemediationBudgetExceeded event, got %d", len(exceeded))
+	}
+	if exceeded[0].EventData["month"] != "2026-04" || exceeded[0].EventData["used_bytes"] != int64(1100) {
+		t.Errorf("Unexpected RemediationBudgetExceeded event: %+v", exceeded[0])
+	}
+
+	// Every instance's remediations wait in the queue
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+	queued := acquireAsync(r.throttle, 7, "a", shutdown)
+	waitQueued(t, r.throttle, 1)
+	expectWaiting(t, queued)
+	if !r.QueueStatus().BudgetPaused {
+		t.Error("Expected queue status to report the budget pause")
+	}
+
+	// A remediation approved over the budget isn't held by the pause
+	r.approveOverBudget("approved")
+	approved := make(chan func(), 1)
+	go func() { approved <- r.acquireSlot("approved", "/media/approved.mkv", 0) }()
+	expectGranted(t, approved)()
+	expectWaiting(t, queued)
+	if r.takeBudgetApproval("approved") {
+		t.Error("The approval should be used up by the remediation it was given for")
+	}
+
+	// A new month only counts what is still pending
+	clk.SetNow(time.Date(2026, 5, 1, 0, 5, 0, 0, time.UTC))
+	r.checkDataBudget()
+	expectGranted(t, queued)
+
+	restored := bus.GetEvents(domain.RemediationBudgetRestored)
+	if len(restored) != 1 {
+		t.Fatalf("Expected one RemediationBudgetRestored event, got %d", len(restored))
+	}
+	if restored[0].EventData["queued"] != 1 {
+		t.Errorf("Expected backlog of 1, got %v", restored[0].EventData["queued"])
+	}
+}
+
+func TestRemediator_DataBudgetDisabled(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+	seedBudgetEvent(t, db, "done", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 5000}, time.Now())
+
+	bus := testutil.NewMockEventBus()
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.checkDataBudget()
+
+	if bus.EventCount(domain.RemediationBudgetExceeded) != 0 || r.QueueStatus().BudgetPaused {
+		t.Error("Remediation should not pause without a data budget")
+	}
+}
+
+func TestRemediator_AdmitWithinBudget(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)
+	seedBudgetEvent(t, db, "done", domain.VerificationSuccess, map[string]interface{}{"new_file_size": 600}, now)
+
+	bus := testutil.NewMockEventBus()
+	clk := testutil.NewMockClockAt(now)
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.clk = clk
+	r.throttle = newRemediationThrottle(RemediationThrottleConfig{}, clk)
+	r.SetDataBudget(1000)
+
+	data := domain.CorruptionEventData{FilePath: "/media/small.mkv", FileSize: 300, PathID: 1}
+	if !r.admitWithinBudget("small", data, false) {
+		t.Fatal("A replacement that fits in the budget should go ahead")
+	}
+
+	// Over the budget with approval required: stopped until approved
+	r.SetDataBudgetAction(config.BudgetActionApprove)
+	data = domain.CorruptionEventData{FilePath: "/media/big.mkv", FileSize: 500, PathID: 1}
+	if r.admitWithinBudget("big", data, false) {
+		t.Fatal("A replacement over the budget should need approval")
+	}
+	approval := bus.GetEvents(domain.BudgetApprovalRequired)
+	if len(approval) != 1 || approval[0].AggregateID != "big" || approval[0].EventData["projected_bytes"] != int64(500) {
+		t.Fatalf("Unexpected BudgetApprovalRequired events: %+v", approval)
+	}
+	if !r.admitWithinBudget("big", data, true) {
+		t.Error("An approved remediation should go ahead")
+	}
+	if r.QueueStatus().BudgetPaused {
+		t.Error("Waiting for approval should not pause the queue")
+	}
+
+	// Over the budget with deferral: queued until next month
+	r.SetDataBudgetAction(config.BudgetActionDefer)
+	if !r.admitWithinBudget("later", data, false) {
+		t.Fatal("A deferred remediation should wait in the queue")
+	}
+	deferred := bus.GetEvents(domain.RemediationDeferred)
+	if len(deferred) != 1 || deferred[0].EventData["deferred_until"] != "2026-05-01T00:00:00Z" {
+		t.Fatalf("Unexpected RemediationDeferred events: %+v", deferred)
+	}
+	if !r.QueueStatus().BudgetPaused || r.DeferredMonth() != "2026-04" {
+		t.Fatalf("Expected the queue to be paused for 2026-04, got paused=%v month=%q", r.QueueStatus().BudgetPaused, r.DeferredMonth())
+	}
+
+	// The budget isn't used up, but the deferral holds until the month is over
+	r.checkDataBudget()
+	if !r.QueueStatus().BudgetPaused {
+		t.Error("Deferral should hold for the rest of the month")
+	}
+	clk.SetNow(time.Date(2026, 5, 1, 0, 5, 0, 0, time.UTC))
+	r.checkDataBudget()
+	if r.QueueStatus().BudgetPaused || r.DeferredMonth() != "" {
+		t.Error("A new month should release deferred remediations")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:             "0 B",
+		999:           "999 B",
+		1500:          "1.5 kB",
+		2_500_000_000: "2.5 GB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
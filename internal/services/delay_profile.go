@@ -0,0 +1,55 @@
+package services
+
+import (
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// deadlineAggregateID is the aggregate ID of a corruption's
+// VerificationDeadlineExtended events. It is kept apart from the corruption's
+// own aggregate so the extension does not become its current state.
+func deadlineAggregateID(corruptionID string) string {
+	return "deadline_" + corruptionID
+}
+
+// extendForDelayProfile extends the verification deadline by the delay the
+// *arr instance waits before grabbing a release for the media, so a
+// replacement held back by a delay profile doesn't time out. The delay of the
+// path's protocol counts, or the longer one when the path accepts either.
+func (v *VerifierService) extendForDelayProfile(state *monitorState) {
+	if v.arrClient == nil || state.mediaID == 0 || state.arrPath == "" {
+		return
+	}
+	profile, err := v.arrClient.GetDelayProfile(state.mediaID, state.arrPath)
+	if err != nil {
+		logger.Debugf("Failed to load delay profile for %s: %v", state.corruptionID, err)
+		return
+	}
+	delay := profile.Delay(state.protocol)
+	if delay <= 0 {
+		return
+	}
+
+	base := state.timeout
+	state.timeout += delay
+	logger.Infof("Extending verification deadline of %s by %s for the delay profile of %s (timeout %s)",
+		state.corruptionID, delay, profile.InstanceName, state.timeout)
+
+	if err := v.eventBus.Publish(domain.Event{
+		AggregateType: "deadline",
+		AggregateID:   deadlineAggregateID(state.corruptionID),
+		EventType:     domain.VerificationDeadlineExtended,
+		EventData: map[string]interface{}{
+			"corruption_id":      state.corruptionID,
+			"file_path":          state.filePath,
+			"instance_name":      profile.InstanceName,
+			"delay_profile_id":   profile.ID,
+			"protocol":           state.protocol,
+			"delay_minutes":      delay.Minutes(),
+			"base_timeout_hours": base.Hours(),
+			"timeout_hours":      state.timeout.Hours(),
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish VerificationDeadlineExtended event for %s: %v", state.corruptionID, err)
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestVerifierService_ExtendForDelayProfile(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	profile := &integration.DelayProfile{
+		ID: 3, InstanceName: "Sonarr", PreferredProtocol: "usenet",
+		EnableUsenet: true, EnableTorrent: true,
+		UsenetDelay: 2 * time.Hour, TorrentDelay: 6 * time.Hour,
+	}
+	var profileErr error
+	arrClient := &testutil.MockArrClient{
+		GetDelayProfileFunc: func(mediaID int64, arrPath string) (*integration.DelayProfile, error) {
+			return profile, profileErr
+		},
+	}
+	verifier := NewVerifierService(eb, nil, nil, arrClient, db)
+
+	extended := make(chan domain.Event, 1)
+	eb.Subscribe(domain.VerificationDeadlineExtended, func(e domain.Event) { extended <- e })
+
+	state := &monitorState{corruptionID: "test-delay", arrPath: "/tv/Show/S01E01.mkv", mediaID: 7, timeout: 72 * time.Hour, protocol: ProtocolUsenet}
+	verifier.extendForDelayProfile(state)
+	if state.timeout != 74*time.Hour {
+		t.Errorf("Expected the usenet delay to extend the timeout to 74h, got %v", state.timeout)
+	}
+
+	select {
+	case e := <-extended:
+		if e.AggregateID != deadlineAggregateID("test-delay") {
+			t.Errorf("Expected the deadline aggregate, got %q", e.AggregateID)
+		}
+		if id, _ := e.GetString("corruption_id"); id != "test-delay" {
+			t.Errorf("Expected corruption_id test-delay, got %q", id)
+		}
+		if minutes, _ := e.GetFloat64("delay_minutes"); minutes != 120 {
+			t.Errorf("Expected delay_minutes 120, got %v", minutes)
+		}
+		if hours, _ := e.GetFloat64("timeout_hours"); hours != 74 {
+			t.Errorf("Expected timeout_hours 74, got %v", hours)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a VerificationDeadlineExtended event")
+	}
+
+	// A path accepting either protocol waits for the longer delay
+	state = &monitorState{corruptionID: "test-delay-any", arrPath: "/tv/Show/S01E01.mkv", mediaID: 7, timeout: 72 * time.Hour, protocol: ProtocolAny}
+	verifier.extendForDelayProfile(state)
+	if state.timeout != 78*time.Hour {
+		t.Errorf("Expected the torrent delay to extend the timeout to 78h, got %v", state.timeout)
+	}
+
+	// Without a delay, or when the profile can't be loaded, the timeout stays
+	for _, tc := range []struct {
+		name    string
+		profile *integration.DelayProfile
+		err     error
+	}{
+		{"no profile", nil, nil},
+		{"no delay", &integration.DelayProfile{EnableUsenet: true, EnableTorrent: true}, nil},
+		{"error", profile, errors.New("connection refused")},
+	} {
+		profile, profileErr = tc.profile, tc.err
+		state = &monitorState{corruptionID: "test-" + tc.name, arrPath: "/tv/Show/S01E01.mkv", mediaID: 7, timeout: 72 * time.Hour}
+		verifier.extendForDelayProfile(state)
+		if state.timeout != 72*time.Hour {
+			t.Errorf("%s: expected the timeout to stay 72h, got %v", tc.name, state.timeout)
+		}
+	}
+}
+
+func TestDelayProfile_Delay(t *testing.T) {
+	p := &integration.DelayProfile{EnableUsenet: true, UsenetDelay: time.Hour, TorrentDelay: 3 * time.Hour}
+	if d := p.Delay("usenet"); d != time.Hour {
+		t.Errorf("Delay(usenet) = %v, want 1h", d)
+	}
+	if d := p.Delay("torrent"); d != 0 {
+		t.Errorf("Delay(torrent) with torrents disabled = %v, want 0", d)
+	}
+	if d := p.Delay(ProtocolAny); d != time.Hour {
+		t.Errorf("Delay(any) = %v, want the enabled protocol's 1h", d)
+	}
+	var none *integration.DelayProfile
+	if d := none.Delay(ProtocolAny); d != 0 {
+		t.Errorf("Delay of no profile = %v, want 0", d)
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+)
+
+// deletedConfigTimeout bounds soft-delete, restore and purge queries.
+const deletedConfigTimeout = 10 * time.Second
+
+var (
+	// ErrScanPathNotFound is returned when a scan path doesn't exist, or isn't
+	// deleted when restoring it.
+	ErrScanPathNotFound = errors.New("scan path not found")
+	// ErrArrInstanceNotFound is returned when an *arr instance doesn't exist, or
+	// isn't deleted when restoring it.
+	ErrArrInstanceNotFound = errors.New("*arr instance not found")
+)
+
+// DeletedScanPath is a soft-deleted scan path with the history that still
+// refers to it.
+type DeletedScanPath struct {
+	ID          int64  `json:"id"`
+	LocalPath   string `json:"local_path"`
+	ArrPath     string `json:"arr_path"`
+	DeletedAt   string `json:"deleted_at"`
+	PurgeAt     string `json:"purge_at,omitempty"`
+	Scans       int    `json:"scans"`
+	Corruptions int    `json:"corruptions"`
+}
+
+// DeletedArrInstance is a soft-deleted *arr instance with the number of scan
+// paths still assigned to it.
+type DeletedArrInstance struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at,omitempty"`
+	ScanPaths int    `json:"scan_paths"`
+}
+
+// DeletedConfig lists the soft-deleted scan paths and *arr instances.
+// RetentionDays is how long they are kept; 0 keeps them until restored.
+type DeletedConfig struct {
+	RetentionDays int                  `json:"retention_days"`
+	ScanPaths     []DeletedScanPath    `json:"scan_paths"`
+	ArrInstances  []DeletedArrInstance `json:"arr_instances"`
+}
+
+// DeletedConfigService soft-deletes, restores and purges scan paths and *arr
+// instances. A deleted row keeps its ID, so scans, schedules and corruptions
+// that reference it come back with it.
+type DeletedConfigService struct {
+	db *sql.DB
+}
+
+// NewDeletedConfigService creates a new deleted config service.
+func NewDeletedConfigService(db *sql.DB) *DeletedConfigService {
+	return &DeletedConfigService{db: db}
+}
+
+// DeleteScanPath marks a scan path as deleted.
+func (d *DeletedConfigService) DeleteScanPath(id int64) error {
+	return d.setDeleted("scan_paths", id, true, ErrScanPathNotFound)
+}
+
+// RestoreScanPath undoes the deletion of a scan path.
+func (d *DeletedConfigService) RestoreScanPath(id int64) error {
+	return d.setDeleted("scan_paths", id, false, ErrScanPathNotFound)
+}
+
+// DeleteArrInstance marks an *arr instance as deleted. Scan paths bound by tag
+// lose the instance until another one carries the tag; others keep pointing
+// at it, so restoring the instance reconnects them.
+func (d *DeletedConfigService) DeleteArrInstance(id int64) error {
+	if err := d.setDeleted("arr_instances", id, true, ErrArrInstanceNotFound); err != nil {
+		return err
+	}
+	_, err := db.ExecWithRetry(d.db, `
+		UPDATE scan_paths SET arr_instance_id = NULL WHERE arr_instance_id = ? AND arr_instance_tag != ''
+	`, id)
+	return err
+}
+
+// RestoreArrInstance undoes the deletion of an *arr instance.
+func (d *DeletedConfigService) RestoreArrInstance(id int64) error {
+	return d.setDeleted("arr_instances", id, false, ErrArrInstanceNotFound)
+}
+
+// setDeleted sets or clears deleted_at of a row, returning notFound unless the
+// row exists in the opposite state.
+func (d *DeletedConfigService) setDeleted(table string, id int64, deleted bool, notFound error) error {
+	query := fmt.Sprintf(`UPDATE %s SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, table)
+	if !deleted {
+		query = fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, table)
+	}
+	result, err := db.ExecWithRetry(d.db, query, id) // NOSONAR - table is one of two constants
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// ListDeleted returns the soft-deleted scan paths and *arr instances, newest
+// deletion first. With a positive retentionDays each entry carries the time
+// it will be purged.
+func (d *DeletedConfigService) ListDeleted(ctx context.Context, retentionDays int) (*DeletedConfig, error) {
+	ctx, cancel := context.WithTimeout(ctx, deletedConfigTimeout)
+	defer cancel()
+
+	result := &DeletedConfig{
+		RetentionDays: retentionDays,
+		ScanPaths:     make([]DeletedScanPath, 0),
+		ArrInstances:  make([]DeletedArrInstance, 0),
+	}
+	modifier := fmt.Sprintf("+%d days", retentionDays)
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT sp.id, sp.local_path, sp.arr_path, sp.deleted_at, datetime(sp.deleted_at, ?),
+			(SELECT COUNT(*) FROM scans WHERE path_id = sp.id),
+			(SELECT COUNT(*) FROM corruption_summary WHERE path_id = sp.id)
+		FROM scan_paths sp
+		WHERE sp.deleted_at IS NOT NULL
+		ORDER BY sp.deleted_at DESC, sp.id DESC
+	`, modifier)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p DeletedScanPath
+		var purgeAt sql.NullString
+		if err := rows.Scan(&p.ID, &p.LocalPath, &p.ArrPath, &p.DeletedAt, &purgeAt, &p.Scans, &p.Corruptions); err != nil {
+			return nil, err
+		}
+		if retentionDays > 0 {
+			p.PurgeAt = purgeAt.String
+		}
+		result.ScanPaths = append(result.ScanPaths, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	instRows, err := d.db.QueryContext(ctx, `
+		SELECT i.id, i.name, i.type, i.url, i.deleted_at, datetime(i.deleted_at, ?),
+			(SELECT COUNT(*) FROM scan_paths WHERE arr_instance_id = i.id AND deleted_at IS NULL)
+		FROM arr_instances i
+		WHERE i.deleted_at IS NOT NULL
+		ORDER BY i.deleted_at DESC, i.id DESC
+	`, modifier)
+	if err != nil {
+		return nil, err
+	}
+	defer instRows.Close()
+	for instRows.Next() {
+		var inst DeletedArrInstance
+		var purgeAt sql.NullString
+		if err := instRows.Scan(&inst.ID, &inst.Name, &inst.Type, &inst.URL, &inst.DeletedAt, &purgeAt, &inst.ScanPaths); err != nil {
+			return nil, err
+		}
+		if retentionDays > 0 {
+			inst.PurgeAt = purgeAt.String
+		}
+		result.ArrInstances = append(result.ArrInstances, inst)
+	}
+	return result, instRows.Err()
+}
+
+// PurgeDeletedScanPathAt permanently removes a deleted scan path with this
+// local path, so a new one can take its place.
+func (d *DeletedConfigService) PurgeDeletedScanPathAt(localPath string) error {
+	var id int64
+	err := d.db.QueryRow(`
+		SELECT id FROM scan_paths WHERE local_path = ? AND deleted_at IS NOT NULL
+	`, localPath).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return d.purgeScanPath(id)
+}
+
+// PurgeExpired permanently removes scan paths and *arr instances deleted more
+// than retentionDays ago and returns how many were removed. Scans of a purged
+// path are kept without their path; its schedules are removed.
+func (d *DeletedConfigService) PurgeExpired(retentionDays int) (int, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	modifier := fmt.Sprintf("-%d days", retentionDays)
+
+	rows, err := d.db.Query(`SELECT id FROM scan_paths WHERE deleted_at < datetime('now', ?)`, modifier)
+	if err != nil {
+		return 0, err
+	}
+	var pathIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pathIDs = append(pathIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range pathIDs {
+		if err := d.purgeScanPath(id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	if _, err := db.ExecWithRetry(d.db, `
+		UPDATE scan_paths SET arr_instance_id = NULL
+		WHERE arr_instance_id IN (SELECT id FROM arr_instances WHERE deleted_at < datetime('now', ?))
+	`, modifier); err != nil {
+		return purged, err
+	}
+	result, err := db.ExecWithRetry(d.db, `DELETE FROM arr_instances WHERE deleted_at < datetime('now', ?)`, modifier)
+	if err != nil {
+		return purged, err
+	}
+	instances, _ := result.RowsAffected()
+	return purged + int(instances), nil
+}
+
+// purgeScanPath permanently removes a scan path and its schedules, detaching
+// its scans and scan summaries first.
+func (d *DeletedConfigService) purgeScanPath(id int64) error {
+	for _, query := range []string{
+		`UPDATE scans SET path_id = NULL WHERE path_id = ?`,
+		`UPDATE scan_summaries SET path_id = NULL WHERE path_id = ?`,
+		`DELETE FROM scan_schedules WHERE scan_path_id = ?`,
+		`DELETE FROM scan_paths WHERE id = ?`,
+	} {
+		if _, err := db.ExecWithRetry(d.db, query, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
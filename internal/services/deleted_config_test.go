@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestDeletedConfigService(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr', 'key')`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/tv', '/tv', 1)`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, arr_instance_tag) VALUES (2, '/media/anime', '/anime', 1, 'anime')`,
+		`INSERT INTO scan_schedules (scan_path_id, cron_expression) VALUES (1, '0 3 * * *')`,
+		`INSERT INTO scans (path, path_id, status) VALUES ('/media/tv', 1, 'completed')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+	if _, err := testutil.SeedEvent(db, domain.Event{
+		AggregateID:   "corruption-1",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/tv/a.mkv", "path_id": 1},
+	}); err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	svc := NewDeletedConfigService(db)
+	ctx := context.Background()
+
+	t.Run("delete and list", func(t *testing.T) {
+		if err := svc.DeleteScanPath(1); err != nil {
+			t.Fatalf("DeleteScanPath() error = %v", err)
+		}
+		if err := svc.DeleteScanPath(1); err != ErrScanPathNotFound {
+			t.Errorf("Expected ErrScanPathNotFound for a deleted path, got %v", err)
+		}
+		if err := svc.DeleteArrInstance(1); err != nil {
+			t.Fatalf("DeleteArrInstance() error = %v", err)
+		}
+
+		deleted, err := svc.ListDeleted(ctx, 30)
+		if err != nil {
+			t.Fatalf("ListDeleted() error = %v", err)
+		}
+		if len(deleted.ScanPaths) != 1 || len(deleted.ArrInstances) != 1 {
+			t.Fatalf("Expected one deleted path and instance, got %+v", deleted)
+		}
+		p := deleted.ScanPaths[0]
+		if p.LocalPath != "/media/tv" || p.Scans != 1 || p.Corruptions != 1 || p.PurgeAt == "" {
+			t.Errorf("Unexpected deleted path: %+v", p)
+		}
+		// Only the tag-bound path lost its instance
+		if inst := deleted.ArrInstances[0]; inst.Name != "Sonarr" || inst.ScanPaths != 0 {
+			t.Errorf("Unexpected deleted instance: %+v", inst)
+		}
+		var instanceID *int64
+		if err := db.QueryRow(`SELECT arr_instance_id FROM scan_paths WHERE id = 2`).Scan(&instanceID); err != nil || instanceID != nil {
+			t.Errorf("Expected the tag-bound path to be unbound, got %v (err %v)", instanceID, err)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		if err := svc.RestoreScanPath(1); err != nil {
+			t.Fatalf("RestoreScanPath() error = %v", err)
+		}
+		if err := svc.RestoreScanPath(1); err != ErrScanPathNotFound {
+			t.Errorf("Expected ErrScanPathNotFound for an active path, got %v", err)
+		}
+		if err := svc.RestoreArrInstance(1); err != nil {
+			t.Fatalf("RestoreArrInstance() error = %v", err)
+		}
+		deleted, _ := svc.ListDeleted(ctx, 0)
+		if len(deleted.ScanPaths) != 0 || len(deleted.ArrInstances) != 0 {
+			t.Errorf("Expected nothing deleted after restoring, got %+v", deleted)
+		}
+	})
+
+	t.Run("purge expired", func(t *testing.T) {
+		if err := svc.DeleteScanPath(1); err != nil {
+			t.Fatalf("DeleteScanPath() error = %v", err)
+		}
+		if n, err := svc.PurgeExpired(30); err != nil || n != 0 {
+			t.Fatalf("Expected nothing purged within retention, got %d (err %v)", n, err)
+		}
+		if _, err := db.Exec(`UPDATE scan_paths SET deleted_at = datetime('now', '-31 days') WHERE id = 1`); err != nil {
+			t.Fatalf("Failed to age deletion: %v", err)
+		}
+		if n, err := svc.PurgeExpired(30); err != nil || n != 1 {
+			t.Fatalf("Expected one purge, got %d (err %v)", n, err)
+		}
+
+		var paths, schedules, orphanedScans int
+		db.QueryRow(`SELECT COUNT(*) FROM scan_paths WHERE id = 1`).Scan(&paths)
+		db.QueryRow(`SELECT COUNT(*) FROM scan_schedules`).Scan(&schedules)
+		db.QueryRow(`SELECT COUNT(*) FROM scans WHERE path_id IS NULL`).Scan(&orphanedScans)
+		if paths != 0 || schedules != 0 || orphanedScans != 1 {
+			t.Errorf("Expected path and schedule gone and the scan kept, got paths=%d schedules=%d scans=%d", paths, schedules, orphanedScans)
+		}
+	})
+
+	t.Run("re-adding purges the deleted path", func(t *testing.T) {
+		if err := svc.DeleteScanPath(2); err != nil {
+			t.Fatalf("DeleteScanPath() error = %v", err)
+		}
+		if err := svc.PurgeDeletedScanPathAt("/media/anime"); err != nil {
+			t.Fatalf("PurgeDeletedScanPathAt() error = %v", err)
+		}
+		if _, err := db.Exec(`INSERT INTO scan_paths (local_path, arr_path) VALUES ('/media/anime', '/anime')`); err != nil {
+			t.Errorf("Expected the local path to be free again: %v", err)
+		}
+	})
+}
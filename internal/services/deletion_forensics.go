@@ -0,0 +1,57 @@
+package services
+
+import (
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// publishDeletionFailed publishes DeletionFailed along with what was found
+// about the failure: the *arr's answer and the state of the file and its
+// folder. The same forensics go into the diagnostics store.
+func (r *RemediatorService) publishDeletionFailed(corruptionID, filePath string, err error) {
+	forensics := integration.InspectDeletionFailure(err, filePath)
+	logger.Infof("Deletion of %s failed: cause=%s retryable=%v arr_status=%d file_exists=%v dir_accessible=%v",
+		filePath, forensics.Cause, forensics.Retryable, forensics.ArrStatus, forensics.File.Exists, forensics.Mount.Accessible)
+	r.recordDeletionForensics(corruptionID, filePath, forensics)
+
+	if pubErr := r.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.DeletionFailed,
+		EventData: map[string]interface{}{
+			"file_path": filePath,
+			"error":     err.Error(),
+			"cause":     forensics.Cause,
+			"retryable": forensics.Retryable,
+			"forensics": forensics,
+		},
+	}); pubErr != nil {
+		logger.Errorf("Failed to publish DeletionFailed event: %v", pubErr)
+	}
+}
+
+// recordDeletionForensics stores the forensics of a failed deletion next to
+// the checker output, served by /api/corruptions/:id/diagnostics.
+func (r *RemediatorService) recordDeletionForensics(corruptionID, filePath string, forensics *integration.DeletionForensics) {
+	if r.db == nil {
+		return
+	}
+	blob, err := integration.EncodeDiagnostics(integration.CheckDiagnostics{
+		ErrorType: "DeletionFailed",
+		Message:   forensics.Error,
+		Runs:      []integration.ToolRun{},
+		Deletion:  forensics,
+	})
+	if err != nil {
+		logger.Warnf("Failed to encode deletion forensics for %s: %v", filePath, err)
+		return
+	}
+	if _, err := db.ExecWithRetry(r.db, `
+		INSERT INTO corruption_diagnostics (corruption_id, file_path, data)
+		VALUES (?, ?, ?)
+	`, corruptionID, filePath, blob); err != nil {
+		logger.Warnf("Failed to store deletion forensics for %s: %v", filePath, err)
+	}
+}
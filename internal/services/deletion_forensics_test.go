@@ -0,0 +1,64 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediatorService_ExecuteRemediation_RecordsDeletionForensics(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	filePath := filepath.Join(t.TempDir(), "S01E01.mkv")
+	mockEventBus := testutil.NewMockEventBus()
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+		PlanDeletionFunc: func(mediaID int64, path string) (*integration.DeletionPlan, error) {
+			return &integration.DeletionPlan{MediaID: mediaID, Files: []integration.ArrFileRecord{{ID: 7, Path: path}}}, nil
+		},
+		DeleteFileFunc: func(mediaID int64, path string) (map[string]interface{}, error) {
+			return nil, &integration.ArrResponseError{
+				Message:    "*arr API returned 500 after 3 attempts",
+				StatusCode: 500,
+				Body:       `{"message": "Access to the path '/tv/Show/S01E01.mkv' is denied."}`,
+			}
+		},
+	}
+
+	remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, db)
+	remediator.executeRemediation("test-id", filePath, "/tv/Show/S01E01.mkv", 1)
+
+	failed := mockEventBus.GetEvents(domain.DeletionFailed)
+	if len(failed) != 1 {
+		t.Fatalf("Expected 1 DeletionFailed event, got %d", len(failed))
+	}
+	if cause, _ := failed[0].GetString("cause"); cause != integration.DeletionCausePermissionDenied {
+		t.Errorf("Expected cause %q, got %q", integration.DeletionCausePermissionDenied, cause)
+	}
+	if failed[0].GetBoolOr("retryable", true) {
+		t.Error("Expected a permission problem not to be retryable")
+	}
+	forensics, ok := failed[0].EventData["forensics"].(*integration.DeletionForensics)
+	if !ok || forensics.ArrStatus != 500 || forensics.ArrResponse == "" || forensics.File.Exists {
+		t.Errorf("Expected the forensics in the event, got %+v", failed[0].EventData["forensics"])
+	}
+
+	var blob []byte
+	if err := db.QueryRow(`SELECT data FROM corruption_diagnostics WHERE corruption_id = ?`, "test-id").Scan(&blob); err != nil {
+		t.Fatalf("Expected stored diagnostics: %v", err)
+	}
+	diag, err := integration.DecodeDiagnostics(blob)
+	if err != nil {
+		t.Fatalf("Failed to decode diagnostics: %v", err)
+	}
+	if diag.ErrorType != "DeletionFailed" || diag.Deletion == nil || diag.Deletion.Cause != integration.DeletionCausePermissionDenied {
+		t.Errorf("Unexpected stored diagnostics: %+v", diag)
+	}
+}
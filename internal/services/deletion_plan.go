@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Actions a deletion plan can take on the corrupt file.
+const (
+	DeletionActionArr        = "delete_via_arr"       // The *arr instance deletes its file record and the file
+	DeletionActionUntracked  = "delete_untracked"     // The *arr doesn't track the file; Healarr deletes it from disk
+	DeletionActionQuarantine = "quarantine_untracked" // The *arr doesn't track the file; Healarr moves it to quarantine
+	DeletionActionNone       = "none"                 // Nothing would be deleted, so the remediation fails
+)
+
+// DeletionPlan is what a remediation is about to delete, worked out before
+// deleting. The deletion only goes ahead while the *arr instance still agrees.
+type DeletionPlan struct {
+	Action     string                      `json:"action"`
+	InstanceID int64                       `json:"instance_id"`
+	MediaID    int64                       `json:"media_id"`
+	ArrFiles   []integration.ArrFileRecord `json:"arr_files"`  // *arr file records to delete
+	DiskPaths  []string                    `json:"disk_paths"` // files that leave the disk, as Healarr sees them
+	DryRun     bool                        `json:"dry_run"`
+	PlannedAt  time.Time                   `json:"planned_at"`
+}
+
+// sameTargets reports whether both plans delete the same files.
+func (p *DeletionPlan) sameTargets(other *DeletionPlan) bool {
+	if p.Action != other.Action || p.MediaID != other.MediaID || len(p.ArrFiles) != len(other.ArrFiles) {
+		return false
+	}
+	for i := range p.ArrFiles {
+		if p.ArrFiles[i] != other.ArrFiles[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// planDeletion asks the *arr instance which of its file records deleting
+// filePath would remove.
+func (r *RemediatorService) planDeletion(filePath, arrPath string) (*DeletionPlan, error) {
+	mediaID, err := r.arrClient.FindMediaByPath(arrPath)
+	if err != nil {
+		return nil, err
+	}
+	arrPlan, err := r.arrClient.PlanDeletion(mediaID, arrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DeletionPlan{
+		InstanceID: arrPlan.InstanceID,
+		MediaID:    mediaID,
+		ArrFiles:   arrPlan.Files,
+		DiskPaths:  []string{},
+		PlannedAt:  r.clk.Now().UTC(),
+	}
+	if plan.ArrFiles == nil {
+		plan.ArrFiles = []integration.ArrFileRecord{}
+	}
+	if len(plan.ArrFiles) > 0 {
+		plan.Action = DeletionActionArr
+		for _, file := range plan.ArrFiles {
+			localPath := file.Path
+			if r.pathMapper != nil {
+				if mapped, err := r.pathMapper.ToLocalPath(file.Path); err == nil {
+					localPath = mapped
+				}
+			}
+			plan.DiskPaths = append(plan.DiskPaths, localPath)
+		}
+		return plan, nil
+	}
+
+	// Files the *arr doesn't track are left to the untracked file fallback
+	plan.Action = DeletionActionNone
+	if _, err := os.Stat(filePath); err == nil {
+		switch r.untrackedFileAction {
+		case config.UntrackedFileDelete:
+			plan.Action = DeletionActionUntracked
+		case config.UntrackedFileQuarantine:
+			plan.Action = DeletionActionQuarantine
+		}
+		if plan.Action != DeletionActionNone {
+			plan.DiskPaths = append(plan.DiskPaths, filePath)
+		}
+	}
+	return plan, nil
+}
+
+// saveDeletionPlan stores the latest deletion plan of a corruption.
+func (r *RemediatorService) saveDeletionPlan(corruptionID string, plan *DeletionPlan) {
+	if r.db == nil {
+		return
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		logger.Errorf("Failed to encode deletion plan for %s: %v", corruptionID, err)
+		return
+	}
+	if _, err := db.ExecWithRetry(r.db, `
+		INSERT INTO corruption_deletion_plans (corruption_id, plan, planned_at) VALUES (?, ?, ?)
+		ON CONFLICT(corruption_id) DO UPDATE SET plan = excluded.plan, planned_at = excluded.planned_at
+	`, corruptionID, string(data), plan.PlannedAt); err != nil {
+		logger.Errorf("Failed to save deletion plan for %s: %v", corruptionID, err)
+	}
+}
+
+// LoadDeletionPlan returns the latest deletion plan of a corruption, or nil
+// if none was made yet.
+func LoadDeletionPlan(ctx context.Context, database *sql.DB, corruptionID string) (*DeletionPlan, error) {
+	var data string
+	err := database.QueryRowContext(ctx, `SELECT plan FROM corruption_deletion_plans WHERE corruption_id = ?`, corruptionID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plan DeletionPlan
+	if err := json.Unmarshal([]byte(data), &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// checkDeletionPlan makes a fresh plan right before deleting and compares it
+// with the one made when the remediation started. If the *arr instance's files
+// changed in between, e.g. the file was upgraded or replaced, it publishes
+// DeletionPlanChanged and returns nil, so nothing unexpected gets deleted.
+func (r *RemediatorService) checkDeletionPlan(corruptionID, filePath, arrPath string, planned *DeletionPlan) *DeletionPlan {
+	current, err := r.planDeletion(filePath, arrPath)
+	if err != nil {
+		logger.Errorf("Failed to re-check deletion plan for %s: %v", filePath, err)
+		r.publishDeletionFailed(corruptionID, filePath, err)
+		return nil
+	}
+	if planned.sameTargets(current) {
+		return current
+	}
+
+	reason := "The *arr instance's files changed since the deletion was planned"
+	logger.Warnf("Not deleting %s: %s (planned %d file(s) of media %d, now %d file(s) of media %d)",
+		filePath, reason, len(planned.ArrFiles), planned.MediaID, len(current.ArrFiles), current.MediaID)
+	if err := r.eventBus.PublishWithRetry(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.DeletionPlanChanged,
+		EventData: map[string]interface{}{
+			"file_path": filePath,
+			"planned":   planned,
+			"current":   current,
+			"reason":    reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish DeletionPlanChanged event after retries: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediatorService_ExecuteRemediation_DeletesPlannedFile(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	mockEventBus := testutil.NewMockEventBus()
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+		PlanDeletionFunc: func(mediaID int64, path string) (*integration.DeletionPlan, error) {
+			return &integration.DeletionPlan{InstanceID: 1, MediaID: mediaID, Files: []integration.ArrFileRecord{
+				{ID: 7, Path: "/tv/Show/S01E01.mkv", Size: 1000},
+			}}, nil
+		},
+	}
+	mockPathMapper := &testutil.MockPathMapper{
+		ToLocalPathFunc: func(arrPath string) (string, error) {
+			return strings.Replace(arrPath, "/tv", "/media/tv", 1), nil
+		},
+	}
+
+	remediator := NewRemediatorService(mockEventBus, mockArrClient, mockPathMapper, db)
+	remediator.executeRemediation("test-id", "/media/tv/Show/S01E01.mkv", "/tv/Show/S01E01.mkv", 1)
+
+	if got := mockArrClient.CallCount("DeleteFile"); got != 1 {
+		t.Fatalf("Expected the file to be deleted once, got %d calls", got)
+	}
+	started := mockEventBus.GetEvents(domain.DeletionStarted)
+	if len(started) != 1 {
+		t.Fatalf("Expected 1 DeletionStarted event, got %d", len(started))
+	}
+	if plan, ok := started[0].EventData["deletion_plan"].(*DeletionPlan); !ok || plan.MediaID != 42 {
+		t.Errorf("Expected DeletionStarted to carry the plan, got %v", started[0].EventData["deletion_plan"])
+	}
+
+	plan, err := LoadDeletionPlan(context.Background(), db, "test-id")
+	if err != nil || plan == nil {
+		t.Fatalf("Expected a stored plan, got %v (err %v)", plan, err)
+	}
+	if plan.Action != DeletionActionArr || len(plan.ArrFiles) != 1 || plan.ArrFiles[0].ID != 7 {
+		t.Errorf("Unexpected plan: %+v", plan)
+	}
+	if len(plan.DiskPaths) != 1 || plan.DiskPaths[0] != "/media/tv/Show/S01E01.mkv" {
+		t.Errorf("Expected the local path on disk, got %v", plan.DiskPaths)
+	}
+}
+
+func TestRemediatorService_ExecuteRemediation_PlanChanged(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	// The file is upgraded in the *arr instance while the remediation waits
+	fileID := int64(7)
+	mockEventBus := testutil.NewMockEventBus()
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+		PlanDeletionFunc: func(mediaID int64, path string) (*integration.DeletionPlan, error) {
+			plan := &integration.DeletionPlan{MediaID: mediaID, Files: []integration.ArrFileRecord{
+				{ID: fileID, Path: "/tv/Show/S01E01.mkv", Size: 1000},
+			}}
+			fileID++
+			return plan, nil
+		},
+	}
+
+	remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, db)
+	remediator.executeRemediation("test-id", "/tv/Show/S01E01.mkv", "/tv/Show/S01E01.mkv", 1)
+
+	if got := mockArrClient.CallCount("DeleteFile"); got != 0 {
+		t.Errorf("Expected nothing to be deleted, got %d DeleteFile calls", got)
+	}
+	if got := mockEventBus.EventCount(domain.DeletionStarted); got != 0 {
+		t.Errorf("Expected no DeletionStarted event, got %d", got)
+	}
+	changed := mockEventBus.GetEvents(domain.DeletionPlanChanged)
+	if len(changed) != 1 {
+		t.Fatalf("Expected 1 DeletionPlanChanged event, got %d", len(changed))
+	}
+	planned, _ := changed[0].EventData["planned"].(*DeletionPlan)
+	current, _ := changed[0].EventData["current"].(*DeletionPlan)
+	if planned == nil || current == nil || planned.ArrFiles[0].ID != 7 || current.ArrFiles[0].ID != 8 {
+		t.Errorf("Expected both plans in the event, got %v and %v", changed[0].EventData["planned"], changed[0].EventData["current"])
+	}
+	// A changed plan isn't retried automatically
+	if got := mockEventBus.EventCount(domain.DeletionFailed); got != 0 {
+		t.Errorf("Expected no DeletionFailed event, got %d", got)
+	}
+}
+
+func TestRemediatorService_ExecuteDryRun_StoresPlan(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+	}
+	remediator := NewRemediatorService(testutil.NewMockEventBus(), mockArrClient, &testutil.MockPathMapper{}, db)
+	remediator.executeDryRun("test-id", "/missing/file.mkv", "/arr/file.mkv")
+
+	plan, err := LoadDeletionPlan(context.Background(), db, "test-id")
+	if err != nil || plan == nil {
+		t.Fatalf("Expected a stored plan, got %v (err %v)", plan, err)
+	}
+	if !plan.DryRun || plan.Action != DeletionActionNone || len(plan.DiskPaths) != 0 {
+		t.Errorf("Unexpected dry-run plan: %+v", plan)
+	}
+	if got := mockArrClient.CallCount("DeleteFile"); got != 0 {
+		t.Errorf("Expected no deletion in a dry run, got %d calls", got)
+	}
+
+	if plan, err := LoadDeletionPlan(context.Background(), db, "other-id"); err != nil || plan != nil {
+		t.Errorf("Expected no plan for another corruption, got %v (err %v)", plan, err)
+	}
+}
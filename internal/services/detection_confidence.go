@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrInvalidMinConfidence is returned for a minimum detection confidence
+// outside 0-100.
+var ErrInvalidMinConfidence = errors.New("min_confidence must be between 0 and 100")
+
+// ValidateMinConfidence checks the minimum detection confidence of a scan
+// path. 0 remediates every finding.
+func ValidateMinConfidence(minConfidence int) error {
+	if minConfidence < 0 || minConfidence > 100 {
+		return ErrInvalidMinConfidence
+	}
+	return nil
+}
+
+// addConfidence adds the confidence of a finding, and the verdicts of the
+// detectors that cross-checked it, to the data of its CorruptionDetected event
+// and flags it as low confidence when it is below the path's minimum.
+func addConfidence(eventData map[string]interface{}, healthErr *integration.HealthCheckError, minConfidence int) {
+	confidence := healthErr.ConfidenceScore()
+	eventData["confidence"] = confidence
+	if len(healthErr.Verdicts) > 0 {
+		eventData["detector_verdicts"] = healthErr.Verdicts
+	}
+	if minConfidence > 0 {
+		eventData["min_confidence"] = minConfidence
+		if confidence < minConfidence {
+			eventData["low_confidence"] = true
+		}
+	}
+}
+
+// requireConfirmation holds a corruption whose detection confidence is below
+// the path's minimum until the user retries it, which confirms the finding,
+// or ignores it.
+func (r *RemediatorService) requireConfirmation(corruptionID string, data domain.CorruptionEventData) {
+	reason := fmt.Sprintf("detection confidence %d%% is below the path's minimum of %d%%", data.Confidence, data.MinConfidence)
+	logger.Infof("Not remediating %s: %s", data.FilePath, reason)
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.LowConfidenceDetection,
+		EventData: map[string]interface{}{
+			"file_path":       data.FilePath,
+			"path_id":         data.PathID,
+			"corruption_type": data.CorruptionType,
+			"confidence":      data.Confidence,
+			"min_confidence":  data.MinConfidence,
+			"reason":          reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish LowConfidenceDetection event: %v", err)
+	}
+}
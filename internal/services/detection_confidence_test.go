@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestAddConfidence(t *testing.T) {
+	herr := &integration.HealthCheckError{Type: integration.ErrorTypeCorruptHeader, Confidence: 55}
+
+	data := map[string]interface{}{}
+	addConfidence(data, herr, 0)
+	if data["confidence"] != 55 {
+		t.Errorf("Expected confidence 55, got %v", data["confidence"])
+	}
+	if _, ok := data["low_confidence"]; ok {
+		t.Error("Without a minimum no finding is low confidence")
+	}
+
+	data = map[string]interface{}{}
+	addConfidence(data, herr, 50)
+	if data["min_confidence"] != 50 || data["low_confidence"] != nil {
+		t.Errorf("A finding above the minimum should not be flagged: %v", data)
+	}
+
+	data = map[string]interface{}{}
+	addConfidence(data, herr, 80)
+	if data["low_confidence"] != true {
+		t.Errorf("A finding below the minimum should be flagged: %v", data)
+	}
+}
+
+func TestValidateMinConfidence(t *testing.T) {
+	for _, v := range []int{0, 1, 100} {
+		if err := ValidateMinConfidence(v); err != nil {
+			t.Errorf("ValidateMinConfidence(%d) error = %v", v, err)
+		}
+	}
+	for _, v := range []int{-1, 101} {
+		if err := ValidateMinConfidence(v); err != ErrInvalidMinConfidence {
+			t.Errorf("ValidateMinConfidence(%d) error = %v, want ErrInvalidMinConfidence", v, err)
+		}
+	}
+}
+
+func TestRemediator_LowConfidence(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	bus := testutil.NewMockEventBus()
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+
+	event := domain.Event{
+		AggregateID:   "low",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData: map[string]interface{}{
+			"file_path":       "/media/tv/show.mkv",
+			"path_id":         int64(1),
+			"corruption_type": integration.ErrorTypeCorruptHeader,
+			"auto_remediate":  true,
+			"confidence":      40,
+			"min_confidence":  70,
+			"low_confidence":  true,
+		},
+	}
+	if r.remediate(event, func() {}) {
+		t.Fatal("A low-confidence finding should not be remediated")
+	}
+
+	held := bus.GetEvents(domain.LowConfidenceDetection)
+	if len(held) != 1 {
+		t.Fatalf("Expected one LowConfidenceDetection event, got %d", len(held))
+	}
+	if held[0].AggregateID != "low" || held[0].EventData["confidence"] != 40 || held[0].EventData["min_confidence"] != 70 {
+		t.Errorf("Unexpected LowConfidenceDetection event: %+v", held[0])
+	}
+	if bus.EventCount(domain.DeletionStarted) != 0 {
+		t.Error("Nothing should be deleted before the finding is confirmed")
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// outageCheckInterval is how often the remediator looks for *arr outages and
+// probes instances that are in detection-only mode.
+const outageCheckInterval = 30 * time.Second
+
+// CircuitBreakerSource reports the circuit breaker state of each *arr instance.
+type CircuitBreakerSource interface {
+	GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats
+}
+
+// SetDetectionOnly enables detection-only mode for *arr outages. Once an
+// instance's circuit breaker has been open for longer than threshold, its paths
+// keep scanning but remediations wait in the queue until the instance recovers.
+// A zero threshold disables it. Must be called before Start.
+func (r *RemediatorService) SetDetectionOnly(breakers CircuitBreakerSource, threshold time.Duration) {
+	r.breakers = breakers
+	r.detectionOnlyAfter = threshold
+}
+
+// runOutageWatch periodically switches instances in and out of detection-only mode.
+func (r *RemediatorService) runOutageWatch() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(outageCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.shutdownCh:
+			return
+		case <-ticker.C:
+			r.probePausedInstances()
+			r.checkOutages()
+		}
+	}
+}
+
+// probePausedInstances sends a health check to every instance in detection-only
+// mode. Nothing else talks to a paused instance, so without a probe its circuit
+// breaker would never see the successes it needs to close.
+func (r *RemediatorService) probePausedInstances() {
+	for _, id := range r.throttle.pausedInstances() {
+		if err := r.arrClient.CheckInstanceHealth(id); err != nil {
+			logger.Debugf("Detection-only: instance %d still unavailable: %v", id, err)
+		}
+	}
+}
+
+// checkOutages pauses remediation for instances whose circuit breaker has been
+// open past the threshold and resumes it for instances whose breaker closed.
+func (r *RemediatorService) checkOutages() {
+	if r.breakers == nil || r.detectionOnlyAfter <= 0 {
+		return
+	}
+
+	now := r.clk.Now()
+	stats := r.breakers.GetCircuitBreakerStats()
+
+	for id, st := range stats {
+		if st.State == integration.CircuitClosed || st.OpenSince.IsZero() {
+			continue
+		}
+		outage := now.Sub(st.OpenSince)
+		if outage < r.detectionOnlyAfter {
+			continue
+		}
+		if r.throttle.pause(id) {
+			r.publishRemediationPaused(id, st.OpenSince, outage)
+		}
+	}
+
+	for _, id := range r.throttle.pausedInstances() {
+		if st, ok := stats[id]; ok && st.State != integration.CircuitClosed {
+			continue
+		}
+		if backlog := r.throttle.resume(id); backlog >= 0 {
+			r.publishRemediationResumed(id, backlog)
+		}
+	}
+}
+
+func (r *RemediatorService) publishRemediationPaused(instanceID int64, since time.Time, outage time.Duration) {
+	name := r.instanceName(instanceID)
+	paths := r.instancePaths(instanceID)
+	logger.Warnf("*arr instance %s unavailable for %s - switching %d path(s) to detection-only, remediations will be queued",
+		name, outage.Round(time.Second), len(paths))
+
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateType: "health",
+		AggregateID:   "instance_" + name,
+		EventType:     domain.RemediationPaused,
+		EventData: map[string]interface{}{
+			"instance_id":    instanceID,
+			"instance_name":  name,
+			"outage_since":   since.UTC().Format(time.RFC3339),
+			"outage_minutes": int(outage.Minutes()),
+			"affected_paths": paths,
+			"queued":         r.throttle.queued(instanceID),
+			"reason":         fmt.Sprintf("%s has been unreachable for %s", name, outage.Round(time.Minute)),
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish RemediationPaused event for %s: %v", name, err)
+	}
+}
+
+func (r *RemediatorService) publishRemediationResumed(instanceID int64, backlog int) {
+	name := r.instanceName(instanceID)
+	paths := r.instancePaths(instanceID)
+	logger.Infof("*arr instance %s recovered - resuming remediation with %d queued item(s)", name, backlog)
+
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateType: "health",
+		AggregateID:   "instance_" + name,
+		EventType:     domain.RemediationResumed,
+		EventData: map[string]interface{}{
+			"instance_id":    instanceID,
+			"instance_name":  name,
+			"affected_paths": paths,
+			"queued":         backlog,
+			"reason":         fmt.Sprintf("%s recovered, processing %d queued remediation(s)", name, backlog),
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish RemediationResumed event for %s: %v", name, err)
+	}
+}
+
+// instanceName returns the display name of an instance, falling back to its ID.
+func (r *RemediatorService) instanceName(instanceID int64) string {
+	if inst, err := r.arrClient.GetInstanceByID(instanceID); err == nil && inst != nil && inst.Name != "" {
+		return inst.Name
+	}
+	return fmt.Sprintf("%d", instanceID)
+}
+
+// instancePaths returns the local scan paths served by an instance.
+func (r *RemediatorService) instancePaths(instanceID int64) []string {
+	paths := []string{}
+	if r.db == nil {
+		return paths
+	}
+	rows, err := r.db.Query(`SELECT local_path FROM scan_paths WHERE arr_instance_id = ? AND deleted_at IS NULL ORDER BY local_path`, instanceID)
+	if err != nil {
+		logger.Debugf("Failed to load scan paths for instance %d: %v", instanceID, err)
+		return paths
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p string
+		if rows.Scan(&p) == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
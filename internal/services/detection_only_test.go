@@ -0,0 +1,128 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+type fakeBreakers struct {
+	mu    sync.Mutex
+	stats map[int64]integration.CircuitBreakerStats
+}
+
+func (f *fakeBreakers) set(id int64, st integration.CircuitBreakerStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats[id] = st
+}
+
+func (f *fakeBreakers) GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[int64]integration.CircuitBreakerStats, len(f.stats))
+	for id, st := range f.stats {
+		out[id] = st
+	}
+	return out
+}
+
+func TestRemediator_DetectionOnlyDuringOutage(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/tv', '/tv', 7)`); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+
+	var probes int
+	arr := &testutil.MockArrClient{
+		GetInstanceByIDFunc: func(id int64) (*integration.ArrInstanceInfo, error) {
+			return &integration.ArrInstanceInfo{ID: id, Name: "Sonarr"}, nil
+		},
+		CheckInstanceHealthFunc: func(int64) error {
+			probes++
+			return nil
+		},
+	}
+	bus := testutil.NewMockEventBus()
+	clk := testutil.NewMockClock()
+	r := NewRemediatorService(bus, arr, &testutil.MockPathMapper{}, db)
+	r.clk = clk
+	r.throttle = newRemediationThrottle(RemediationThrottleConfig{}, clk)
+
+	breakers := &fakeBreakers{stats: map[int64]integration.CircuitBreakerStats{}}
+	r.SetDetectionOnly(breakers, 10*time.Minute)
+
+	// A short outage is left to the circuit breaker
+	breakers.set(7, integration.CircuitBreakerStats{State: integration.CircuitOpen, OpenSince: clk.Now().Add(-5 * time.Minute)})
+	r.checkOutages()
+	if bus.EventCount(domain.RemediationPaused) != 0 {
+		t.Fatal("Remediation should not pause before the threshold")
+	}
+
+	clk.Advance(6 * time.Minute)
+	breakers.set(7, integration.CircuitBreakerStats{State: integration.CircuitHalfOpen, OpenSince: clk.Now().Add(-11 * time.Minute)})
+	r.checkOutages()
+	r.checkOutages()
+	paused := bus.GetEvents(domain.RemediationPaused)
+	if len(paused) != 1 {
+		t.Fatalf("Expected one RemediationPaused event, got %d", len(paused))
+	}
+	if paused[0].EventData["instance_name"] != "Sonarr" || paused[0].AggregateType != "health" {
+		t.Errorf("Unexpected RemediationPaused event: %+v", paused[0])
+	}
+	if paths, _ := paused[0].EventData["affected_paths"].([]string); len(paths) != 1 || paths[0] != "/media/tv" {
+		t.Errorf("Unexpected affected paths: %v", paused[0].EventData["affected_paths"])
+	}
+
+	// Remediations for the instance wait in the queue
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+	queued := acquireAsync(r.throttle, 7, "a", shutdown)
+	waitQueued(t, r.throttle, 1)
+	expectWaiting(t, queued)
+	if status := r.QueueStatus(); len(status.Instances) != 1 || !status.Instances[0].DetectionOnly {
+		t.Errorf("Expected instance in detection-only mode, got %+v", status.Instances)
+	}
+
+	// Other instances are unaffected
+	expectGranted(t, acquireAsync(r.throttle, 8, "b", shutdown))
+
+	r.probePausedInstances()
+	if probes != 1 {
+		t.Errorf("Expected paused instance to be probed once, got %d", probes)
+	}
+
+	breakers.set(7, integration.CircuitBreakerStats{State: integration.CircuitClosed})
+	r.checkOutages()
+	expectGranted(t, queued)
+
+	resumed := bus.GetEvents(domain.RemediationResumed)
+	if len(resumed) != 1 {
+		t.Fatalf("Expected one RemediationResumed event, got %d", len(resumed))
+	}
+	if resumed[0].EventData["queued"] != 1 {
+		t.Errorf("Expected backlog of 1, got %v", resumed[0].EventData["queued"])
+	}
+}
+
+func TestRemediator_DetectionOnlyDisabled(t *testing.T) {
+	bus := testutil.NewMockEventBus()
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, nil)
+	breakers := &fakeBreakers{stats: map[int64]integration.CircuitBreakerStats{
+		1: {State: integration.CircuitOpen, OpenSince: time.Now().Add(-time.Hour)},
+	}}
+	r.SetDetectionOnly(breakers, 0)
+	r.checkOutages()
+
+	if bus.EventCount(domain.RemediationPaused) != 0 || len(r.throttle.pausedInstances()) != 0 {
+		t.Error("Detection-only mode should stay off with a zero threshold")
+	}
+}
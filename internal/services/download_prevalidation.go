@@ -0,0 +1,131 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// maxPrevalidationFiles bounds the files checked per download, so a season
+// pack doesn't hold up the monitor loop for long.
+const maxPrevalidationFiles = 50
+
+// prevalidateDownload checks a completed download in the download client's
+// folder before the *arr imports it. A corrupt download is removed from the
+// client and blocklisted, which makes the *arr search for another release.
+// Returns true when the download was rejected.
+func (v *VerifierService) prevalidateDownload(state *monitorState, item integration.QueueItemInfo) bool {
+	if !config.Get().DownloadPrevalidation || v.detector == nil || item.OutputPath == "" {
+		return false
+	}
+	if item.Status != "completed" || (item.TrackedDownloadState != "importPending" && item.TrackedDownloadState != "importBlocked") {
+		return false
+	}
+	if state.checkedDownloads[item.DownloadID] {
+		return false
+	}
+	if state.checkedDownloads == nil {
+		state.checkedDownloads = make(map[string]bool)
+	}
+	state.checkedDownloads[item.DownloadID] = true
+
+	localPath := mapDownloadPath(item.OutputPath, config.Get().DownloadPathMappings)
+	files, err := downloadMediaFiles(localPath)
+	if err != nil {
+		// The folder may not be mounted into Healarr; the *arr import check still runs
+		logger.Debugf("Skipping pre-import check of %s: %v", localPath, err)
+		return false
+	}
+
+	var failed []string
+	var lastErr *integration.HealthCheckError
+	for _, file := range files {
+		if healthy, herr := v.detector.Check(file, integration.ModeQuick); !healthy && herr != nil && herr.IsTrueCorruption() {
+			failed = append(failed, file)
+			lastErr = herr
+		}
+	}
+	if len(failed) == 0 {
+		logger.Debugf("Pre-import check passed for %s (%d files)", item.Title, len(files))
+		return false
+	}
+
+	logger.Warnf("Download %s for %s is corrupt (%s), blocklisting it", item.Title, state.corruptionID, lastErr.Message)
+	if err := v.arrClient.RemoveFromQueueByPath(state.arrPath, item.ID, true, true); err != nil {
+		logger.Errorf("Failed to blocklist corrupt download %s: %v", item.Title, err)
+		return false
+	}
+
+	if err := v.eventBus.Publish(domain.Event{
+		AggregateID:   state.corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.DownloadRejected,
+		EventData: map[string]interface{}{
+			"file_path":    state.filePath,
+			"error":        lastErr.Message,
+			"title":        item.Title,
+			"download_id":  item.DownloadID,
+			"failed_paths": failed,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish DownloadRejected event: %v", err)
+	}
+	return true
+}
+
+// mapDownloadPath translates a path as the *arr sees it to Healarr's, using
+// the longest matching prefix of the "arr path=local path" mappings.
+func mapDownloadPath(path, mappings string) string {
+	best, bestLocal := "", ""
+	for _, pair := range strings.Split(mappings, ",") {
+		arrPrefix, localPrefix, ok := strings.Cut(pair, "=")
+		arrPrefix = strings.TrimRight(strings.TrimSpace(arrPrefix), "/")
+		if !ok || arrPrefix == "" || len(arrPrefix) <= len(best) {
+			continue
+		}
+		if path == arrPrefix || strings.HasPrefix(path, arrPrefix+"/") {
+			best, bestLocal = arrPrefix, strings.TrimRight(strings.TrimSpace(localPrefix), "/")
+		}
+	}
+	if best == "" {
+		return path
+	}
+	return bestLocal + strings.TrimPrefix(path, best)
+}
+
+// downloadMediaFiles lists the media files of a download, which is either a
+// single file or a folder.
+func downloadMediaFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if isMediaFile(path) {
+			return []string{path}, nil
+		}
+		return nil, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Samples are short cuts the *arr doesn't import, so they don't count
+		if d.IsDir() || !isMediaFile(p) || isHiddenOrTempFile(p) || strings.Contains(strings.ToLower(d.Name()), "sample") {
+			return nil
+		}
+		files = append(files, p)
+		if len(files) >= maxPrevalidationFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestMapDownloadPath(t *testing.T) {
+	mappings := "/downloads=/mnt/downloads, /downloads/tv=/mnt/tv-downloads,/broken"
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/downloads/movies/Film.2020", "/mnt/downloads/movies/Film.2020"},
+		{"/downloads/tv/Show.S01", "/mnt/tv-downloads/Show.S01"},
+		{"/downloads", "/mnt/downloads"},
+		{"/downloads-old/Film", "/downloads-old/Film"},
+		{"/data/Film", "/data/Film"},
+	}
+	for _, tt := range tests {
+		if got := mapDownloadPath(tt.path, mappings); got != tt.want {
+			t.Errorf("mapDownloadPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestVerifierService_PrevalidateDownload(t *testing.T) {
+	cfg := config.NewTestConfig()
+	cfg.DownloadPrevalidation = true
+	config.SetForTesting(cfg)
+	defer config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	dir := t.TempDir()
+	release := filepath.Join(dir, "Show.S01E01.1080p")
+	if err := os.MkdirAll(release, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Show.S01E01.mkv", "Show.S01E01.sample.mkv", "Show.S01E01.nfo"} {
+		if err := os.WriteFile(filepath.Join(release, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var checked []string
+	detector := &testutil.MockHealthChecker{
+		CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+			checked = append(checked, filepath.Base(path))
+			return false, &integration.HealthCheckError{Type: integration.ErrorTypeCorruptStream, Message: "invalid data found"}
+		},
+	}
+	var removed []int64
+	arrClient := &testutil.MockArrClient{
+		RemoveFromQueueByPathFunc: func(arrPath string, queueID int64, removeFromClient, blocklist bool) error {
+			if !removeFromClient || !blocklist {
+				t.Errorf("Expected removal from client with blocklist, got %v/%v", removeFromClient, blocklist)
+			}
+			removed = append(removed, queueID)
+			return nil
+		},
+	}
+	verifier := NewVerifierService(eb, detector, nil, arrClient, db)
+
+	item := integration.QueueItemInfo{
+		ID:                   9,
+		DownloadID:           "abc",
+		Title:                "Show.S01E01.1080p",
+		Status:               "completed",
+		TrackedDownloadState: "importPending",
+		OutputPath:           release,
+	}
+	state := &monitorState{corruptionID: "test-prevalidate", filePath: "/tv/Show/S01E01.mkv", wasInQueue: true}
+
+	if action := verifier.handleQueueItem(state, item); action != monitorContinue {
+		t.Fatalf("Expected monitoring to continue after a rejected download, got %v", action)
+	}
+	if len(checked) != 1 || checked[0] != "Show.S01E01.mkv" {
+		t.Errorf("Expected only the episode to be checked, got %v", checked)
+	}
+	if len(removed) != 1 || removed[0] != 9 {
+		t.Errorf("Expected queue item 9 to be blocklisted, got %v", removed)
+	}
+	if state.wasInQueue {
+		t.Error("Expected the rejected download to be forgotten")
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM events WHERE aggregate_id = ? AND event_type = ?",
+		"test-prevalidate", domain.DownloadRejected).Scan(&count); err != nil {
+		t.Fatalf("Failed to count events: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 DownloadRejected event, got %d", count)
+	}
+
+	// The same download is only checked once
+	verifier.prevalidateDownload(state, item)
+	if len(checked) != 1 {
+		t.Errorf("Expected no second check of the same download, got %v", checked)
+	}
+}
+
+func TestVerifierService_PrevalidateDownload_Skipped(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Film.2020.mkv")
+	if err := os.WriteFile(file, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	completed := integration.QueueItemInfo{DownloadID: "a", Status: "completed", TrackedDownloadState: "importPending", OutputPath: file}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		item    integration.QueueItemInfo
+		herr    *integration.HealthCheckError
+	}{
+		{"disabled", false, completed, &integration.HealthCheckError{Type: integration.ErrorTypeCorruptHeader}},
+		{"still downloading", true, integration.QueueItemInfo{DownloadID: "b", Status: "downloading", TrackedDownloadState: "downloading", OutputPath: file}, &integration.HealthCheckError{Type: integration.ErrorTypeCorruptHeader}},
+		{"folder not mounted", true, integration.QueueItemInfo{DownloadID: "c", Status: "completed", TrackedDownloadState: "importPending", OutputPath: filepath.Join(dir, "missing")}, &integration.HealthCheckError{Type: integration.ErrorTypeCorruptHeader}},
+		{"check could not run", true, completed, &integration.HealthCheckError{Type: integration.ErrorTypeTimeout}},
+		{"healthy", true, completed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewTestConfig()
+			cfg.DownloadPrevalidation = tt.enabled
+			config.SetForTesting(cfg)
+			defer config.SetForTesting(config.NewTestConfig())
+
+			detector := &testutil.MockHealthChecker{
+				CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+					return tt.herr == nil, tt.herr
+				},
+			}
+			arrClient := &testutil.MockArrClient{
+				RemoveFromQueueByPathFunc: func(arrPath string, queueID int64, removeFromClient, blocklist bool) error {
+					t.Error("Expected the download to be left alone")
+					return nil
+				},
+			}
+			verifier := &VerifierService{detector: detector, arrClient: arrClient}
+			if verifier.prevalidateDownload(&monitorState{}, tt.item) {
+				t.Error("Expected the download not to be rejected")
+			}
+		})
+	}
+}
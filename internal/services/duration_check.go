@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Duration check policies, set per scan path.
+const (
+	DurationCheckOff       = "off"       // Durations aren't compared
+	DurationCheckFlag      = "flag"      // A mismatch is recorded as DurationMismatch without remediating it
+	DurationCheckRemediate = "remediate" // A mismatch follows the path's auto-remediate setting
+)
+
+// ErrInvalidDurationCheck is returned for an unknown duration check policy.
+var ErrInvalidDurationCheck = errors.New("duration_check must be one of: off, flag, remediate")
+
+// ValidDurationCheck reports whether policy is a known duration check policy.
+func ValidDurationCheck(policy string) bool {
+	return policy == DurationCheckOff || policy == DurationCheckFlag || policy == DurationCheckRemediate
+}
+
+// Runtimes from the *arr's metadata are rough: TV runtimes often include ad
+// breaks and movies have longer cuts. Only files that run less than half or
+// more than twice as long as expected are flagged, and expectations shorter
+// than minExpectedRuntime aren't compared at all.
+const (
+	minRuntimeRatio    = 0.5
+	maxRuntimeRatio    = 2.0
+	minExpectedRuntime = 5 * time.Minute
+)
+
+// evaluateRuntime compares a file's probed duration with the runtime the *arr
+// expects for it, e.g. a 20-minute file for a 2-hour movie.
+func evaluateRuntime(probed, expected time.Duration) *integration.HealthCheckError {
+	if expected < minExpectedRuntime || probed <= 0 {
+		return nil
+	}
+	ratio := probed.Seconds() / expected.Seconds()
+	if ratio >= minRuntimeRatio && ratio <= maxRuntimeRatio {
+		return nil
+	}
+	return &integration.HealthCheckError{
+		Type: integration.ErrorTypeDurationMismatch,
+		Message: fmt.Sprintf("File runs %s, the *arr expects %s (%.0f%%) - truncated or mislabeled release",
+			probed.Round(time.Second), expected.Round(time.Minute), ratio*100),
+	}
+}
+
+// SetArrClient gives the scanner the *arr instances to look up expected
+// runtimes for the duration check. Without it the check is skipped.
+func (s *ScannerService) SetArrClient(arrClient integration.ArrClient) {
+	s.arrClient = arrClient
+}
+
+// validateRuntime runs the duration check on a file that passed its health
+// check, unless the path's policy is off or the file can't be probed.
+func (s *ScannerService) validateRuntime(sfc *scanFileContext) (bool, *integration.HealthCheckError) {
+	if sfc.detectionConfig.Method == integration.DetectionZeroByte || sfc.remote != nil {
+		return true, nil
+	}
+	return s.checkRuntime(sfc.filePath, sfc.durationCheck)
+}
+
+// checkRuntime compares the duration of a video file with the runtime its
+// *arr instance expects. Anything that can't be looked up passes, since the
+// file itself already checked out healthy.
+func (s *ScannerService) checkRuntime(filePath, policy string) (bool, *integration.HealthCheckError) {
+	prober, ok := s.detector.(integration.DurationProber)
+	if !ok || s.arrClient == nil || policy == "" || policy == DurationCheckOff || getMediaType(filePath) != MediaTypeVideo {
+		return true, nil
+	}
+
+	probed, err := prober.ProbeDuration(filePath)
+	if err != nil {
+		logger.Debugf("Duration check skipped, could not probe %s: %v", filePath, err)
+		return true, nil
+	}
+	arrPath, err := s.pathMapper.ToArrPath(filePath)
+	if err != nil {
+		return true, nil
+	}
+	mediaID, err := s.arrClient.FindMediaByPath(arrPath)
+	if err != nil || mediaID == 0 {
+		return true, nil
+	}
+	expected, err := s.arrClient.GetExpectedRuntime(mediaID, arrPath)
+	if err != nil {
+		logger.Debugf("Duration check skipped, could not get the expected runtime of %s: %v", filePath, err)
+		return true, nil
+	}
+
+	if healthErr := evaluateRuntime(probed, expected); healthErr != nil {
+		return false, healthErr
+	}
+	return true, nil
+}
+
+// durationMismatchRemediates reports whether a DurationMismatch found under
+// the policy may be remediated automatically.
+func durationMismatchRemediates(policy string) bool {
+	return policy == DurationCheckRemediate
+}
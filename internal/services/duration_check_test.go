@@ -0,0 +1,111 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// durationProbingChecker is a healthy checker whose files run for duration.
+type durationProbingChecker struct {
+	*testutil.MockHealthChecker
+	duration time.Duration
+}
+
+func (c *durationProbingChecker) ProbeDuration(path string) (time.Duration, error) {
+	return c.duration, nil
+}
+
+func TestEvaluateRuntime(t *testing.T) {
+	tests := []struct {
+		name     string
+		probed   time.Duration
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"matches", 118 * time.Minute, 120 * time.Minute, false},
+		{"TV runtime with ad breaks", 42 * time.Minute, 60 * time.Minute, false},
+		{"extended cut", 170 * time.Minute, 120 * time.Minute, false},
+		{"truncated movie", 20 * time.Minute, 120 * time.Minute, true},
+		{"mislabeled as episode", 95 * time.Minute, 22 * time.Minute, true},
+		{"unknown runtime", 20 * time.Minute, 0, false},
+		{"runtime too short to compare", 30 * time.Second, 3 * time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthErr := evaluateRuntime(tt.probed, tt.expected)
+			if (healthErr != nil) != tt.wantErr {
+				t.Fatalf("evaluateRuntime(%v, %v) = %v, want error %v", tt.probed, tt.expected, healthErr, tt.wantErr)
+			}
+			if healthErr != nil && healthErr.Type != integration.ErrorTypeDurationMismatch {
+				t.Errorf("Type = %q, want %q", healthErr.Type, integration.ErrorTypeDurationMismatch)
+			}
+		})
+	}
+}
+
+func TestScannerService_ScanFile_DurationMismatch(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	tmpDir := t.TempDir()
+	for _, policy := range []string{DurationCheckOff, DurationCheckFlag, DurationCheckRemediate} {
+		dir := filepath.Join(tmpDir, policy)
+		if _, err := db.Exec(`INSERT INTO scan_paths (local_path, arr_path, enabled, auto_remediate, duration_check) VALUES (?, ?, 1, 1, ?)`,
+			dir, dir, policy); err != nil {
+			t.Fatalf("Failed to insert scan path: %v", err)
+		}
+	}
+
+	checker := &durationProbingChecker{MockHealthChecker: &testutil.MockHealthChecker{}, duration: 20 * time.Minute}
+	scanner := NewScannerService(db, eb, checker, &testutil.MockPathMapper{})
+	scanner.SetArrClient(&testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+		GetExpectedRuntimeFunc: func(mediaID int64, arrPath string) (time.Duration, error) {
+			return 2 * time.Hour, nil
+		},
+	})
+
+	// scan returns whether a CorruptionDetected event was emitted and its auto_remediate
+	scan := func(t *testing.T, policy string) (bool, bool) {
+		t.Helper()
+		file := filepath.Join(tmpDir, policy, "movie.mkv")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("movie"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := scanner.ScanFile(file); err != nil {
+			t.Fatalf("ScanFile() error = %v", err)
+		}
+		var autoRemediate bool
+		err := db.QueryRow(`
+			SELECT json_extract(event_data, '$.auto_remediate') FROM events
+			WHERE event_type = 'CorruptionDetected' AND json_extract(event_data, '$.corruption_type') = ?
+			AND json_extract(event_data, '$.file_path') = ?
+		`, integration.ErrorTypeDurationMismatch, file).Scan(&autoRemediate)
+		return err == nil, autoRemediate
+	}
+
+	if found, _ := scan(t, DurationCheckOff); found {
+		t.Error("policy off should not check durations")
+	}
+	if found, autoRemediate := scan(t, DurationCheckFlag); !found || autoRemediate {
+		t.Errorf("policy flag: found = %v, auto_remediate = %v; want true, false", found, autoRemediate)
+	}
+	if found, autoRemediate := scan(t, DurationCheckRemediate); !found || !autoRemediate {
+		t.Errorf("policy remediate: found = %v, auto_remediate = %v; want true, true", found, autoRemediate)
+	}
+}
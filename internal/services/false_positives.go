@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// fingerprintChunkSize is how much of the start and end of a file is hashed
+// into its fingerprint. Reading the whole file would be far too slow for
+// large media; size plus head and tail is enough to tell a replaced file apart.
+const fingerprintChunkSize = 1 << 20
+
+// falsePositiveQueryTimeout bounds false positive lookups and updates.
+const falsePositiveQueryTimeout = 5 * time.Second
+
+// ErrFalsePositiveNotFound is returned when a false positive record doesn't exist.
+var ErrFalsePositiveNotFound = errors.New("false positive not found")
+
+// FalsePositive is a finding a user marked as wrong.
+type FalsePositive struct {
+	ID               int64   `json:"id"`
+	CorruptionID     string  `json:"corruption_id"`
+	FilePath         string  `json:"file_path"`
+	FileSize         int64   `json:"file_size"`
+	Fingerprint      string  `json:"fingerprint"`
+	CorruptionType   string  `json:"corruption_type"`
+	ErrorDetails     string  `json:"error_details"`
+	Signature        string  `json:"signature"`
+	DetectionTool    string  `json:"detection_tool"`
+	ToolVersion      string  `json:"tool_version"`
+	Note             string  `json:"note"`
+	SuppressedCount  int     `json:"suppressed_count"`
+	LastSuppressedAt *string `json:"last_suppressed_at"`
+	CreatedAt        string  `json:"created_at"`
+
+	// CheckerOutput is the compressed diagnostics blob of the original finding.
+	CheckerOutput []byte `json:"-"`
+}
+
+// FalsePositiveCount is one row of an aggregate false positive breakdown.
+type FalsePositiveCount struct {
+	Key        string `json:"key"`
+	Count      int    `json:"count"`
+	Suppressed int    `json:"suppressed"`
+}
+
+// FalsePositiveFinding groups false positives that share a finding signature.
+type FalsePositiveFinding struct {
+	Signature      string `json:"signature"`
+	CorruptionType string `json:"corruption_type"`
+	SampleError    string `json:"sample_error"`
+	Count          int    `json:"count"`
+	Suppressed     int    `json:"suppressed"`
+}
+
+// FalsePositiveReport aggregates false positives to help tune detection.
+type FalsePositiveReport struct {
+	Total            int                    `json:"total"`
+	Suppressed       int                    `json:"suppressed"`
+	ByCorruptionType []FalsePositiveCount   `json:"by_corruption_type"`
+	ByTool           []FalsePositiveCount   `json:"by_tool"`
+	TopFindings      []FalsePositiveFinding `json:"top_findings"`
+}
+
+// FalsePositiveService records user feedback on wrong findings and answers
+// whether a new finding matches one already marked as false positive.
+type FalsePositiveService struct {
+	db *sql.DB
+}
+
+// NewFalsePositiveService creates a new false positive service.
+func NewFalsePositiveService(db *sql.DB) *FalsePositiveService {
+	return &FalsePositiveService{db: db}
+}
+
+// FileFingerprint returns a content fingerprint of a file: a SHA-256 over its
+// size and its first and last megabyte.
+func FileFingerprint(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	size := info.Size()
+
+	h := sha256.New()
+	var sizeBuf [8]byte
+	binary.BigEndian.PutUint64(sizeBuf[:], uint64(size))
+	h.Write(sizeBuf[:])
+
+	if _, err := io.CopyN(h, f, fingerprintChunkSize); err != nil && err != io.EOF {
+		return "", 0, err
+	}
+	if size > 2*fingerprintChunkSize {
+		if _, err := f.Seek(-fingerprintChunkSize, io.SeekEnd); err != nil {
+			return "", 0, err
+		}
+		if _, err := io.CopyN(h, f, fingerprintChunkSize); err != nil && err != io.EOF {
+			return "", 0, err
+		}
+	} else if size > fingerprintChunkSize {
+		if _, err := io.Copy(h, f); err != nil {
+			return "", 0, err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// FindingSignature identifies a finding independent of where the file lives:
+// the corruption type plus the checker message with the file path removed.
+func FindingSignature(filePath, corruptionType, message string) string {
+	normalized := message
+	if filePath != "" {
+		normalized = strings.ReplaceAll(normalized, filePath, "")
+		normalized = strings.ReplaceAll(normalized, filepath.Base(filePath), "")
+	}
+	normalized = strings.Join(strings.Fields(normalized), " ")
+
+	sum := sha256.Sum256([]byte(corruptionType + "\n" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record stores a false positive and returns its ID.
+func (f *FalsePositiveService) Record(fp *FalsePositive) (int64, error) {
+	if fp.Signature == "" {
+		fp.Signature = FindingSignature(fp.FilePath, fp.CorruptionType, fp.ErrorDetails)
+	}
+	result, err := db.ExecWithRetry(f.db, `
+		INSERT INTO false_positives
+			(corruption_id, file_path, file_size, fingerprint, corruption_type, error_details, signature,
+			 detection_tool, tool_version, checker_output, note)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, fp.CorruptionID, fp.FilePath, fp.FileSize, fp.Fingerprint, fp.CorruptionType, fp.ErrorDetails, fp.Signature,
+		fp.DetectionTool, fp.ToolVersion, fp.CheckerOutput, fp.Note)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// List returns the most recent false positives.
+func (f *FalsePositiveService) List(limit int) ([]FalsePositive, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), falsePositiveQueryTimeout)
+	defer cancel()
+
+	rows, err := f.db.QueryContext(ctx, `
+		SELECT id, corruption_id, file_path, file_size, fingerprint, corruption_type, error_details, signature,
+			detection_tool, tool_version, note, suppressed_count, last_suppressed_at, created_at
+		FROM false_positives ORDER BY id DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]FalsePositive, 0)
+	for rows.Next() {
+		var fp FalsePositive
+		var lastSuppressed sql.NullString
+		if err := rows.Scan(&fp.ID, &fp.CorruptionID, &fp.FilePath, &fp.FileSize, &fp.Fingerprint, &fp.CorruptionType,
+			&fp.ErrorDetails, &fp.Signature, &fp.DetectionTool, &fp.ToolVersion, &fp.Note, &fp.SuppressedCount,
+			&lastSuppressed, &fp.CreatedAt); err != nil {
+			return nil, err
+		}
+		if lastSuppressed.Valid {
+			fp.LastSuppressedAt = &lastSuppressed.String
+		}
+		result = append(result, fp)
+	}
+	return result, rows.Err()
+}
+
+// Delete removes a false positive so matching findings are reported again.
+func (f *FalsePositiveService) Delete(id int64) error {
+	result, err := db.ExecWithRetry(f.db, `DELETE FROM false_positives WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrFalsePositiveNotFound
+	}
+	return nil
+}
+
+// IsSuppressed reports whether a finding matches a recorded false positive for
+// the same file content, and counts the suppression if so.
+func (f *FalsePositiveService) IsSuppressed(filePath, corruptionType, message string) bool {
+	signature := FindingSignature(filePath, corruptionType, message)
+
+	ctx, cancel := context.WithTimeout(context.Background(), falsePositiveQueryTimeout)
+	defer cancel()
+
+	// Cheap check first so clean libraries never pay for fingerprinting
+	var candidates int
+	if err := f.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM false_positives WHERE corruption_type = ? AND signature = ? AND fingerprint != ''
+	`, corruptionType, signature).Scan(&candidates); err != nil || candidates == 0 {
+		return false
+	}
+
+	fingerprint, _, err := FileFingerprint(filePath)
+	if err != nil {
+		logger.Debugf("Could not fingerprint %s for false positive check: %v", filePath, err)
+		return false
+	}
+
+	var id int64
+	err = f.db.QueryRowContext(ctx, `
+		SELECT id FROM false_positives
+		WHERE fingerprint = ? AND corruption_type = ? AND signature = ?
+		ORDER BY id DESC LIMIT 1
+	`, fingerprint, corruptionType, signature).Scan(&id)
+	if err != nil {
+		return false
+	}
+
+	if _, err := db.ExecWithRetry(f.db, `
+		UPDATE false_positives SET suppressed_count = suppressed_count + 1, last_suppressed_at = datetime('now')
+		WHERE id = ?
+	`, id); err != nil {
+		logger.Debugf("Failed to count false positive suppression %d: %v", id, err)
+	}
+	return true
+}
+
+// Report aggregates false positives by corruption type, detection tool and
+// finding signature.
+func (f *FalsePositiveService) Report(topN int) (*FalsePositiveReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), falsePositiveQueryTimeout)
+	defer cancel()
+
+	report := &FalsePositiveReport{
+		ByCorruptionType: []FalsePositiveCount{},
+		ByTool:           []FalsePositiveCount{},
+		TopFindings:      []FalsePositiveFinding{},
+	}
+	if err := f.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(suppressed_count), 0) FROM false_positives
+	`).Scan(&report.Total, &report.Suppressed); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if report.ByCorruptionType, err = f.countBy(ctx, "corruption_type"); err != nil {
+		return nil, err
+	}
+	if report.ByTool, err = f.countBy(ctx, "TRIM(detection_tool || ' ' || tool_version)"); err != nil {
+		return nil, err
+	}
+
+	rows, err := f.db.QueryContext(ctx, `
+		SELECT signature, corruption_type, MAX(error_details), COUNT(*), SUM(suppressed_count)
+		FROM false_positives
+		GROUP BY signature, corruption_type
+		ORDER BY COUNT(*) + SUM(suppressed_count) DESC, signature
+		LIMIT ?
+	`, topN)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var finding FalsePositiveFinding
+		if err := rows.Scan(&finding.Signature, &finding.CorruptionType, &finding.SampleError,
+			&finding.Count, &finding.Suppressed); err != nil {
+			return nil, err
+		}
+		report.TopFindings = append(report.TopFindings, finding)
+	}
+	return report, rows.Err()
+}
+
+// countBy groups false positives by a fixed column expression.
+func (f *FalsePositiveService) countBy(ctx context.Context, expr string) ([]FalsePositiveCount, error) {
+	rows, err := f.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s AS k, COUNT(*), SUM(suppressed_count)
+		FROM false_positives
+		GROUP BY k
+		ORDER BY COUNT(*) DESC, k
+	`, expr))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]FalsePositiveCount, 0)
+	for rows.Next() {
+		var c FalsePositiveCount
+		if err := rows.Scan(&c.Key, &c.Count, &c.Suppressed); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
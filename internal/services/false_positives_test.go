@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func writeFingerprintFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestFileFingerprint(t *testing.T) {
+	dir := t.TempDir()
+
+	small := filepath.Join(dir, "small.mkv")
+	writeFingerprintFile(t, small, []byte("hello"))
+	fp1, size, err := FileFingerprint(small)
+	if err != nil {
+		t.Fatalf("FileFingerprint failed: %v", err)
+	}
+	if size != 5 || len(fp1) != 64 {
+		t.Errorf("Unexpected fingerprint %q size %d", fp1, size)
+	}
+
+	// Same content elsewhere fingerprints the same
+	copyPath := filepath.Join(dir, "copy.mkv")
+	writeFingerprintFile(t, copyPath, []byte("hello"))
+	if fp2, _, _ := FileFingerprint(copyPath); fp2 != fp1 {
+		t.Error("Expected identical content to share a fingerprint")
+	}
+
+	// A change in the tail of a large file changes the fingerprint
+	large := bytes.Repeat([]byte{'a'}, 3*fingerprintChunkSize)
+	largePath := filepath.Join(dir, "large.mkv")
+	writeFingerprintFile(t, largePath, large)
+	before, _, err := FileFingerprint(largePath)
+	if err != nil {
+		t.Fatalf("FileFingerprint failed: %v", err)
+	}
+	large[len(large)-1] = 'b'
+	writeFingerprintFile(t, largePath, large)
+	after, _, _ := FileFingerprint(largePath)
+	if before == after {
+		t.Error("Expected a changed tail to change the fingerprint")
+	}
+
+	if _, _, err := FileFingerprint(filepath.Join(dir, "missing.mkv")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestFindingSignature(t *testing.T) {
+	a := FindingSignature("/media/tv/a.mkv", "CorruptHeader", "/media/tv/a.mkv: moov atom  not found")
+	b := FindingSignature("/other/b.mkv", "CorruptHeader", "/other/b.mkv: moov atom not found")
+	if a != b {
+		t.Error("Expected signature to ignore the file path and whitespace")
+	}
+	if a == FindingSignature("/media/tv/a.mkv", "CorruptStream", "/media/tv/a.mkv: moov atom not found") {
+		t.Error("Expected corruption type to be part of the signature")
+	}
+}
+
+func TestFalsePositiveService_SuppressionAndReport(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	filePath := filepath.Join(t.TempDir(), "movie.mkv")
+	writeFingerprintFile(t, filePath, []byte("original content"))
+	fingerprint, size, err := FileFingerprint(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewFalsePositiveService(db)
+	if svc.IsSuppressed(filePath, "CorruptHeader", "moov atom not found") {
+		t.Error("Nothing recorded yet, expected no suppression")
+	}
+
+	id, err := svc.Record(&FalsePositive{
+		CorruptionID:   "corr-1",
+		FilePath:       filePath,
+		FileSize:       size,
+		Fingerprint:    fingerprint,
+		CorruptionType: "CorruptHeader",
+		ErrorDetails:   "moov atom not found",
+		DetectionTool:  "ffprobe",
+		ToolVersion:    "6.1",
+	})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	if !svc.IsSuppressed(filePath, "CorruptHeader", "moov atom not found") {
+		t.Error("Expected identical finding to be suppressed")
+	}
+	if svc.IsSuppressed(filePath, "CorruptStream", "moov atom not found") {
+		t.Error("Expected a different corruption type not to be suppressed")
+	}
+
+	// Replaced file content is checked again
+	writeFingerprintFile(t, filePath, []byte("replaced content"))
+	if svc.IsSuppressed(filePath, "CorruptHeader", "moov atom not found") {
+		t.Error("Expected a replaced file not to be suppressed")
+	}
+
+	list, err := svc.List(10)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 1 || list[0].SuppressedCount != 1 || list[0].LastSuppressedAt == nil {
+		t.Fatalf("Unexpected list: %+v", list)
+	}
+
+	report, err := svc.Report(5)
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if report.Total != 1 || report.Suppressed != 1 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if len(report.ByTool) != 1 || report.ByTool[0].Key != "ffprobe 6.1" {
+		t.Errorf("Unexpected tool breakdown: %+v", report.ByTool)
+	}
+	if len(report.TopFindings) != 1 || report.TopFindings[0].SampleError != "moov atom not found" {
+		t.Errorf("Unexpected top findings: %+v", report.TopFindings)
+	}
+
+	if err := svc.Delete(id); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := svc.Delete(id); err != ErrFalsePositiveNotFound {
+		t.Errorf("Expected ErrFalsePositiveNotFound, got %v", err)
+	}
+}
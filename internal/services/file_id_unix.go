@@ -0,0 +1,23 @@
+//go:build !windows
+
+package services
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file or directory independent of the path it was
+// reached by.
+type fileID struct {
+	dev, ino uint64
+	path     string // set where device and inode aren't available
+}
+
+// fileIDOf returns the device and inode of info, found at path.
+func fileIDOf(path string, info os.FileInfo) fileID {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)} //nolint:unconvert // the field types differ between platforms
+	}
+	return fileID{path: path}
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileID identifies a file or directory independent of the path it was
+// reached by.
+type fileID struct {
+	path string
+}
+
+// fileIDOf returns the path of info with links resolved; FileInfo carries no
+// file index on Windows.
+func fileIDOf(path string, _ os.FileInfo) fileID {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return fileID{path: real}
+	}
+	return fileID{path: path}
+}
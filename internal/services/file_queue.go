@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// defaultFileQueueMemory is how many file paths a path scan keeps in memory
+// before it spills its file list to the database.
+const defaultFileQueueMemory = 10000
+
+// fileQueue is the list of files of a path scan. Up to limit paths are kept in
+// memory. A longer list is spilled to the scan_file_queue table while the
+// directory is walked and read back a page of limit paths at a time, so memory
+// use stays flat however large the library is.
+type fileQueue struct {
+	db     *sql.DB
+	scanID int64
+	limit  int
+
+	mem     []string // the whole list, or the page starting at memPos once spilled
+	memPos  int
+	spilled int // paths written to scan_file_queue
+	total   int
+}
+
+// newFileQueue returns an empty queue for a scan that spills beyond limit
+// paths. Without a scan record or with a limit of 0 it never spills.
+func newFileQueue(database *sql.DB, scanID int64, limit int) *fileQueue {
+	return &fileQueue{db: database, scanID: scanID, limit: limit}
+}
+
+// memoryFileQueue returns a queue of files that fit in memory.
+func memoryFileQueue(files []string) *fileQueue {
+	return &fileQueue{mem: files, total: len(files)}
+}
+
+// spilledFileQueue returns the queue of an interrupted scan whose file list
+// was spilled to scan_file_queue.
+func spilledFileQueue(database *sql.DB, scanID int64, limit, total int) *fileQueue {
+	if limit <= 0 {
+		limit = defaultFileQueueMemory
+	}
+	return &fileQueue{db: database, scanID: scanID, limit: limit, spilled: total, total: total}
+}
+
+// Len returns the number of files in the queue.
+func (q *fileQueue) Len() int {
+	if q == nil {
+		return 0
+	}
+	return q.total
+}
+
+// Spilled reports whether the file list is stored in scan_file_queue.
+func (q *fileQueue) Spilled() bool {
+	return q != nil && q.spilled > 0
+}
+
+// Files returns the file list if it is kept in memory, nil if it was spilled.
+func (q *fileQueue) Files() []string {
+	if q == nil || q.Spilled() {
+		return nil
+	}
+	return q.mem
+}
+
+// Push appends a file while the directory is walked, spilling the paths in
+// memory once there are limit of them.
+func (q *fileQueue) Push(filePath string) error {
+	q.mem = append(q.mem, filePath)
+	q.total++
+	if q.limit > 0 && q.db != nil && q.scanID > 0 && len(q.mem) >= q.limit {
+		return q.spill()
+	}
+	return nil
+}
+
+// Seal ends the walk. The rest of a spilled list is spilled as well, so the
+// whole list is read back from scan_file_queue in order.
+func (q *fileQueue) Seal() error {
+	if q.Spilled() && len(q.mem) > 0 {
+		return q.spill()
+	}
+	return nil
+}
+
+// spill writes the paths in memory to scan_file_queue in one transaction.
+func (q *fileQueue) spill() error {
+	err := db.NamedTxWithRetry(q.db, "file_queue_spill", func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO scan_file_queue (scan_id, position, file_path) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for i, p := range q.mem {
+			if _, err := stmt.Exec(q.scanID, q.spilled+i, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to spill file queue: %w", err)
+	}
+	q.spilled += len(q.mem)
+	q.memPos = q.spilled
+	q.mem = q.mem[:0]
+	return nil
+}
+
+// At returns the file at position i, loading its page from scan_file_queue if the
+// list was spilled.
+func (q *fileQueue) At(i int) (string, error) {
+	if i < q.memPos || i >= q.memPos+len(q.mem) {
+		if !q.Spilled() {
+			return "", fmt.Errorf("file queue position %d out of range", i)
+		}
+		if err := q.load(i); err != nil {
+			return "", err
+		}
+		if len(q.mem) == 0 {
+			return "", fmt.Errorf("file queue position %d out of range", i)
+		}
+	}
+	return q.mem[i-q.memPos], nil
+}
+
+// load reads the page of the spilled list starting at position from.
+func (q *fileQueue) load(from int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT file_path FROM scan_file_queue
+		WHERE scan_id = ? AND position >= ?
+		ORDER BY position
+		LIMIT ?
+	`, q.scanID, from, q.limit)
+	if err != nil {
+		return fmt.Errorf("failed to read file queue: %w", err)
+	}
+	defer rows.Close()
+
+	q.mem = q.mem[:0]
+	q.memPos = from
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return fmt.Errorf("failed to read file queue: %w", err)
+		}
+		q.mem = append(q.mem, p)
+	}
+	return rows.Err()
+}
+
+// Discard deletes the spilled file list once the scan no longer needs it.
+func (q *fileQueue) Discard() {
+	if q == nil || q.db == nil || q.scanID <= 0 {
+		return
+	}
+	discardFileQueue(q.db, q.scanID)
+}
+
+// discardFileQueue deletes the spilled file list of a scan.
+func discardFileQueue(database *sql.DB, scanID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	if _, err := database.ExecContext(ctx, `DELETE FROM scan_file_queue WHERE scan_id = ?`, scanID); err != nil {
+		logger.Warnf("Failed to delete the file queue of scan %d: %v", scanID, err)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestFileQueue_SpillsBeyondLimit(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	res, err := db.Exec(`INSERT INTO scans (path, status) VALUES ('/media', 'running')`)
+	if err != nil {
+		t.Fatalf("Failed to create scan: %v", err)
+	}
+	scanID, _ := res.LastInsertId()
+
+	q := newFileQueue(db, scanID, 3)
+	for i := 0; i < 8; i++ {
+		if err := q.Push(fmt.Sprintf("/media/%d.mkv", i)); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		if len(q.mem) >= 3 {
+			t.Fatalf("Expected at most 2 paths in memory, got %d", len(q.mem))
+		}
+	}
+	if err := q.Seal(); err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !q.Spilled() || q.Files() != nil || q.Len() != 8 {
+		t.Fatalf("Expected 8 spilled files, got spilled=%v len=%d", q.Spilled(), q.Len())
+	}
+	var stored int
+	db.QueryRow(`SELECT COUNT(*) FROM scan_file_queue WHERE scan_id = ?`, scanID).Scan(&stored)
+	if stored != 8 {
+		t.Errorf("Expected 8 queued rows, got %d", stored)
+	}
+
+	// An interrupted scan reads the list back from where it left off
+	resumed := spilledFileQueue(db, scanID, 3, 8)
+	for i := 5; i < resumed.Len(); i++ {
+		got, err := resumed.At(i)
+		if err != nil || got != fmt.Sprintf("/media/%d.mkv", i) {
+			t.Errorf("At(%d) = %q, %v", i, got, err)
+		}
+	}
+	if _, err := resumed.At(8); err == nil {
+		t.Error("Expected an error past the end of the queue")
+	}
+
+	resumed.Discard()
+	db.QueryRow(`SELECT COUNT(*) FROM scan_file_queue WHERE scan_id = ?`, scanID).Scan(&stored)
+	if stored != 0 {
+		t.Errorf("Expected the queue to be deleted, %d rows left", stored)
+	}
+}
+
+func TestFileQueue_SmallListStaysInMemory(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	q := newFileQueue(db, 1, 10)
+	for _, f := range []string{"/media/a.mkv", "/media/b.mkv"} {
+		if err := q.Push(f); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+	if err := q.Seal(); err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if q.Spilled() || len(q.Files()) != 2 {
+		t.Fatalf("Expected 2 files in memory, got %v", q.Files())
+	}
+	if got, _ := q.At(1); got != "/media/b.mkv" {
+		t.Errorf("At(1) = %q", got)
+	}
+}
+
+func TestScanner_EnumerateSpillsAndRecordsFiles(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("movie%d.mkv", i)), []byte("x"), 0o644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	s := NewScannerService(db, nil, nil, nil)
+	s.SetFileQueueMemory(2)
+	scanDBID := s.recordScanStart(dir, 0, scanPathSettings{})
+	files := newFileQueue(db, scanDBID, s.fileQueueMemory)
+	if err := s.enumerateMediaFiles(dir, mediaFilter{}, SymlinkSkip, files); err != nil {
+		t.Fatalf("enumerateMediaFiles() error = %v", err)
+	}
+	s.recordScanFiles(scanDBID, files)
+
+	var total int
+	var fileList *string
+	if err := db.QueryRow(`SELECT total_files, file_list FROM scans WHERE id = ?`, scanDBID).Scan(&total, &fileList); err != nil {
+		t.Fatalf("Failed to read scan: %v", err)
+	}
+	if total != 5 || fileList != nil {
+		t.Errorf("Expected 5 files and no file_list for a spilled scan, got %d and %v", total, fileList)
+	}
+
+	s.discardScanRecord(scanDBID)
+	var rows int
+	db.QueryRow(`SELECT COUNT(*) FROM scan_file_queue`).Scan(&rows)
+	if rows != 0 {
+		t.Errorf("Expected the discarded scan's queue to be deleted, %d rows left", rows)
+	}
+}
@@ -31,6 +31,13 @@ type HealthMonitorService struct {
 	repeatedFailureCount   int
 	instanceHealthInterval time.Duration
 	arrSyncInterval        time.Duration
+	resolutionSLA          time.Duration // 0 disables SLA tracking
+
+	// pause skips checks that would misread held remediations (nil: never paused)
+	pause *SystemPause
+
+	// reportedDrift is the *arr path each drifted scan path was reported with
+	reportedDrift map[int64]string
 }
 
 // NewHealthMonitorService creates a new health monitoring service
@@ -48,6 +55,7 @@ func NewHealthMonitorService(db *sql.DB, eb *eventbus.EventBus, arrClient integr
 		repeatedFailureCount:   2,
 		instanceHealthInterval: 5 * time.Minute,
 		arrSyncInterval:        30 * time.Minute,
+		reportedDrift:          make(map[int64]string),
 	}
 }
 
@@ -65,6 +73,15 @@ func (h *HealthMonitorService) Start() {
 	logger.Infof("Health monitor started (check interval: %s, stuck threshold: %s, arr sync: %s)", h.checkInterval, h.stuckThreshold, h.arrSyncInterval)
 }
 
+// SetResolutionSLA sets how long a corruption may stay unresolved before
+// SLABreached is raised. Zero disables SLA tracking.
+func (h *HealthMonitorService) SetResolutionSLA(sla time.Duration) {
+	if sla < 0 {
+		sla = 0
+	}
+	h.resolutionSLA = sla
+}
+
 // Shutdown gracefully stops the health monitor
 func (h *HealthMonitorService) Shutdown() {
 	logger.Infof("Health monitor: initiating shutdown...")
@@ -100,8 +117,11 @@ func (h *HealthMonitorService) runHealthChecks() {
 
 // performHealthChecks runs all health checks
 func (h *HealthMonitorService) performHealthChecks() {
-	h.checkStuckRemediations()
+	if !h.pause.Paused() {
+		h.checkStuckRemediations()
+	}
 	h.checkRepeatedFailures()
+	h.checkSLABreaches()
 	h.checkDatabaseHealth()
 }
 
@@ -115,6 +135,7 @@ func (h *HealthMonitorService) checkStuckRemediations() {
 	// 1. CorruptionDetected exists
 	// 2. No VerificationSuccess or MaxRetriesReached
 	// 3. Last event was more than stuckThreshold ago
+	// 4. It isn't waiting for a scheduled re-search, which takes days
 	query := `
 		SELECT
 			e1.aggregate_id,
@@ -129,6 +150,12 @@ func (h *HealthMonitorService) checkStuckRemediations() {
 			WHERE e3.aggregate_id = e1.aggregate_id
 			AND e3.event_type IN ('VerificationSuccess', 'MaxRetriesReached')
 		)
+		AND COALESCE((
+			SELECT e4.event_type FROM events e4
+			WHERE e4.aggregate_id = e1.aggregate_id
+			AND e4.event_type NOT IN ('NotificationSent', 'NotificationFailed')
+			ORDER BY e4.id DESC LIMIT 1
+		), '') != 'ScheduledResearch'
 		GROUP BY e1.aggregate_id
 		HAVING MAX(e2.created_at) < datetime('now', '-' || ? || ' hours')
 	`
@@ -243,6 +270,124 @@ func (h *HealthMonitorService) checkRepeatedFailures() {
 	}
 }
 
+// resolvedStatesSQL lists the states in which a corruption counts as resolved for SLA purposes.
+const resolvedStatesSQL = `'VerificationSuccess', 'CorruptionIgnored'`
+
+// slaAggregateID is the aggregate ID of a corruption's SLABreached event. It is kept
+// apart from the corruption's own aggregate so the breach does not become its current state.
+func slaAggregateID(corruptionID string) string {
+	return "sla_" + corruptionID
+}
+
+// checkSLABreaches raises SLABreached once for every corruption that has stayed
+// unresolved for longer than the configured resolution SLA
+func (h *HealthMonitorService) checkSLABreaches() {
+	if h.db == nil || h.resolutionSLA <= 0 {
+		return
+	}
+
+	// detected_at holds Go's time.Time format; substr keeps the part SQLite can parse
+	query := `
+		SELECT
+			cs.corruption_id,
+			cs.file_path,
+			cs.current_state,
+			cs.detected_at,
+			(julianday('now') - julianday(substr(cs.detected_at, 1, 19))) * 24 as elapsed_hours
+		FROM corruption_summary cs
+		WHERE cs.current_state NOT IN (` + resolvedStatesSQL + `)
+		AND cs.detected_at < datetime('now', '-' || ? || ' seconds')
+		AND NOT EXISTS (
+			SELECT 1 FROM events e
+			WHERE e.aggregate_id = 'sla_' || cs.corruption_id
+			AND e.event_type = 'SLABreached'
+		)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := h.db.QueryContext(ctx, query, int64(h.resolutionSLA.Seconds()))
+	if err != nil {
+		logger.Debugf("Health monitor: failed to check SLA breaches: %v", err)
+		return
+	}
+
+	type breach struct {
+		corruptionID, filePath, currentState, detectedAt string
+		elapsedHours                                     float64
+	}
+	var breaches []breach
+	for rows.Next() {
+		var b breach
+		var filePath sql.NullString
+		var elapsed sql.NullFloat64
+		if rows.Scan(&b.corruptionID, &filePath, &b.currentState, &b.detectedAt, &elapsed) != nil {
+			continue
+		}
+		b.filePath = filePath.String
+		b.elapsedHours = elapsed.Float64
+		breaches = append(breaches, b)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Errorf("Error iterating SLA breaches: %v", err)
+	}
+	rows.Close()
+
+	// Publish after the rows are closed - the event bus writes to the same database
+	slaHours := h.resolutionSLA.Hours()
+	for _, b := range breaches {
+		logger.Warnf("SLA BREACHED: %s unresolved for %.1fh (SLA: %.1fh, state: %s)",
+			b.filePath, b.elapsedHours, slaHours, b.currentState)
+
+		if err := h.eventBus.Publish(domain.Event{
+			AggregateType: "sla",
+			AggregateID:   slaAggregateID(b.corruptionID),
+			EventType:     domain.SLABreached,
+			EventData: map[string]interface{}{
+				"corruption_id": b.corruptionID,
+				"file_path":     b.filePath,
+				"current_state": b.currentState,
+				"detected_at":   b.detectedAt,
+				"elapsed_hours": b.elapsedHours,
+				"sla_hours":     slaHours,
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish SLABreached event for %s: %v", b.corruptionID, err)
+		}
+	}
+}
+
+// SLAStatus summarizes time-to-resolution against the configured SLA
+type SLAStatus struct {
+	SLAHours              float64 `json:"sla_hours"`
+	BreachedUnresolved    int     `json:"breached_unresolved"`     // Unresolved corruptions currently past the SLA
+	BreachesTotal         int     `json:"breaches_total"`          // SLABreached events raised so far
+	OldestUnresolvedHours float64 `json:"oldest_unresolved_hours"` // Age of the oldest unresolved corruption
+}
+
+// GetSLAStatus returns SLA breach counts, or nil if no SLA is configured
+func (h *HealthMonitorService) GetSLAStatus(ctx context.Context) (*SLAStatus, error) {
+	if h.db == nil || h.resolutionSLA <= 0 {
+		return nil, nil
+	}
+
+	status := &SLAStatus{SLAHours: h.resolutionSLA.Hours()}
+	var oldest sql.NullFloat64
+	if err := h.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN detected_at < datetime('now', '-' || ? || ' seconds') THEN 1 END),
+			MAX((julianday('now') - julianday(substr(detected_at, 1, 19))) * 24),
+			(SELECT COUNT(DISTINCT aggregate_id) FROM events WHERE event_type = 'SLABreached')
+		FROM corruption_summary
+		WHERE current_state NOT IN (`+resolvedStatesSQL+`)
+	`, int64(h.resolutionSLA.Seconds())).Scan(&status.BreachedUnresolved, &oldest, &status.BreachesTotal); err != nil {
+		return nil, err
+	}
+	status.OldestUnresolvedHours = oldest.Float64
+	return status, nil
+}
+
 // checkDatabaseHealth checks database connection pool health
 func (h *HealthMonitorService) checkDatabaseHealth() {
 	if h.db == nil {
@@ -318,7 +463,9 @@ func (h *HealthMonitorService) checkInstanceHealth() {
 
 	for _, instance := range instances {
 		// Check instance health using the system status endpoint
+		start := time.Now()
 		err := h.arrClient.CheckInstanceHealth(instance.ID)
+		h.recordHealthSample(instance.ID, time.Since(start), err)
 		if err != nil {
 			logger.Warnf("*arr instance unreachable: %s (%s) - %v", instance.Name, instance.URL, err)
 
@@ -342,6 +489,36 @@ func (h *HealthMonitorService) checkInstanceHealth() {
 	}
 }
 
+// recordHealthSample stores the outcome and response time of an instance
+// health check for the uptime and latency history.
+func (h *HealthMonitorService) recordHealthSample(instanceID int64, elapsed time.Duration, checkErr error) {
+	if h.db == nil {
+		return
+	}
+
+	var errMsg sql.NullString
+	if checkErr != nil {
+		errMsg = sql.NullString{String: checkErr.Error(), Valid: true}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	if _, err := h.db.ExecContext(ctx, `
+		INSERT INTO arr_health_samples (instance_id, reachable, response_ms, error)
+		VALUES (?, ?, ?, ?)
+	`, instanceID, checkErr == nil, elapsed.Milliseconds(), errMsg); err != nil {
+		logger.Debugf("Failed to record health sample for instance %d: %v", instanceID, err)
+	}
+}
+
+// SetInstanceHealthInterval sets how often *arr instances are health checked.
+// Must be called before Start.
+func (h *HealthMonitorService) SetInstanceHealthInterval(interval time.Duration) {
+	if interval > 0 {
+		h.instanceHealthInterval = interval
+	}
+}
+
 // GetHealthStatus returns current health status for API/UI
 func (h *HealthMonitorService) GetHealthStatus() map[string]interface{} {
 	status := make(map[string]interface{})
@@ -386,7 +563,8 @@ func (h *HealthMonitorService) GetHealthStatus() map[string]interface{} {
 	return status
 }
 
-// runArrStateSync periodically syncs in-progress items with arr state
+// runArrStateSync periodically syncs in-progress items with arr state and
+// checks the scan paths against the *arr root folders
 func (h *HealthMonitorService) runArrStateSync() {
 	defer h.wg.Done()
 
@@ -400,6 +578,7 @@ func (h *HealthMonitorService) runArrStateSync() {
 	case <-time.After(5 * time.Minute):
 	}
 	h.syncWithArrState()
+	h.checkMappingDrift()
 
 	for {
 		select {
@@ -407,6 +586,7 @@ func (h *HealthMonitorService) runArrStateSync() {
 			return
 		case <-ticker.C:
 			h.syncWithArrState()
+			h.checkMappingDrift()
 		}
 	}
 }
@@ -516,7 +696,7 @@ func (h *HealthMonitorService) processSyncItem(item arrSyncItem) (synced, exhaus
 }
 
 func (h *HealthMonitorService) syncWithArrState() {
-	if h.db == nil || h.arrClient == nil {
+	if h.db == nil || h.arrClient == nil || h.pause.Paused() {
 		return
 	}
 
@@ -550,13 +730,13 @@ func (h *HealthMonitorService) queryInProgressItems() (*sql.Rows, error) {
 			(
 				SELECT json_extract(e.event_data, '$.media_id')
 				FROM events e
-				WHERE e.aggregate_id = cs.corruption_id
-				AND e.event_type IN ('SearchCompleted', 'SearchStarted')
+				WHERE e.aggregate_id IN (cs.corruption_id, 'media_' || cs.corruption_id)
+				AND e.event_type IN ('SearchCompleted', 'SearchStarted', 'MediaReresolved')
 				ORDER BY e.id DESC
 				LIMIT 1
 			) as media_id
-		FROM corruption_status cs
-		WHERE cs.current_state IN ('DownloadProgress', 'SearchCompleted', 'DownloadStarted')
+		FROM corruption_summary cs
+		WHERE cs.current_state IN ('DownloadProgress', 'DownloadRejected', 'SearchCompleted', 'DownloadStarted')
 		AND cs.last_updated_at < datetime('now', '-1 hours')
 		AND cs.last_updated_at > datetime('now', '-7 days')
 	`
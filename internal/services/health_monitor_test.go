@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -35,6 +36,14 @@ func (m *mockHealthArrClient) DeleteFile(_ int64, _ string) (map[string]interfac
 	return nil, nil
 }
 
+func (m *mockHealthArrClient) PlanDeletion(mediaID int64, _ string) (*integration.DeletionPlan, error) {
+	return &integration.DeletionPlan{MediaID: mediaID}, nil
+}
+
+func (m *mockHealthArrClient) FindSearchMetadata(_ int64, _ string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
 func (m *mockHealthArrClient) GetFilePath(_ int64, _ map[string]interface{}, _ string) (string, error) {
 	return "", nil
 }
@@ -50,6 +59,10 @@ func (m *mockHealthArrClient) TriggerSearch(_ int64, _ string, _ []int64) error
 	return nil
 }
 
+func (m *mockHealthArrClient) RescanMedia(_ int64, _ string) error {
+	return nil
+}
+
 // Instance management
 func (m *mockHealthArrClient) GetAllInstances() ([]*integration.ArrInstanceInfo, error) {
 	if m.instancesErr != nil {
@@ -78,6 +91,10 @@ func (m *mockHealthArrClient) GetRootFolders(_ int64) ([]integration.RootFolder,
 	return nil, nil
 }
 
+func (m *mockHealthArrClient) GetTags(_ int64) ([]integration.ArrTag, error) {
+	return nil, nil
+}
+
 // Queue monitoring
 func (m *mockHealthArrClient) GetQueueForPath(_ string) ([]integration.QueueItemInfo, error) {
 	if m.queueErr != nil {
@@ -112,6 +129,22 @@ func (m *mockHealthArrClient) GetMediaDetails(_ int64, _ string) (*integration.M
 	return nil, nil
 }
 
+func (m *mockHealthArrClient) GetExpectedRuntime(_ int64, _ string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (m *mockHealthArrClient) GetDelayProfile(_ int64, _ string) (*integration.DelayProfile, error) {
+	return nil, nil
+}
+
+func (m *mockHealthArrClient) GetReleases(_ int64, _ string, _ []int64) ([]integration.Release, error) {
+	return nil, nil
+}
+
+func (m *mockHealthArrClient) GrabRelease(_, _ string, _ int64) (*integration.Release, error) {
+	return nil, nil
+}
+
 // =============================================================================
 // NewHealthMonitorService tests
 // =============================================================================
@@ -445,6 +478,46 @@ func TestHealthMonitorService_checkInstanceHealth_UnhealthyInstance(t *testing.T
 	}
 }
 
+func TestHealthMonitorService_checkInstanceHealth_RecordsSamples(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	if err := testutil.SeedArrInstance(db, 1, "Sonarr", "sonarr", "http://localhost:8989", "key"); err != nil {
+		t.Fatalf("Failed to seed instance: %v", err)
+	}
+
+	client := &mockHealthArrClient{
+		instances: []*integration.ArrInstanceInfo{
+			{ID: 1, Name: "Sonarr", Type: "sonarr", URL: "http://localhost:8989"},
+		},
+	}
+	h := NewHealthMonitorService(db, eb, client, 24*time.Hour)
+	h.checkInstanceHealth()
+
+	client.healthCheckErr = errors.New("unreachable: connection refused")
+	h.checkInstanceHealth()
+
+	history, err := LoadArrHealthHistory(context.Background(), db, 1, time.Now().Add(-time.Hour), false)
+	if err != nil {
+		t.Fatalf("LoadArrHealthHistory failed: %v", err)
+	}
+	if history.Samples != 2 {
+		t.Fatalf("Expected 2 samples, got %d", history.Samples)
+	}
+	if history.UptimePercent == nil || *history.UptimePercent != 50 {
+		t.Errorf("Expected 50%% uptime, got %v", history.UptimePercent)
+	}
+	if history.Latest == nil || history.Latest.Reachable || history.Latest.Error != "unreachable: connection refused" {
+		t.Errorf("Expected the failed check as latest sample, got %+v", history.Latest)
+	}
+}
+
 func TestHealthMonitorService_checkInstanceHealth_GetInstancesError(t *testing.T) {
 	db, err := testutil.NewTestDB()
 	if err != nil {
@@ -1699,3 +1772,84 @@ func TestPublishNeedsAttentionForOrphan(t *testing.T) {
 		t.Error("Timed out waiting for SearchExhausted event")
 	}
 }
+
+// =============================================================================
+// Resolution SLA tests
+// =============================================================================
+
+func TestHealthMonitorService_checkSLABreaches(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	h := NewHealthMonitorService(db, eb, nil, 24*time.Hour)
+
+	eventCh := make(chan domain.Event, 10)
+	eb.Subscribe(domain.SLABreached, func(e domain.Event) {
+		eventCh <- e
+	})
+
+	now := time.Now().UTC()
+	for _, c := range []struct {
+		id, state string
+		age       time.Duration
+	}{
+		{"sla-overdue", "SearchStarted", 72 * time.Hour},
+		{"sla-fresh", "CorruptionDetected", time.Hour},
+		{"sla-resolved", "VerificationSuccess", 72 * time.Hour},
+		{"sla-ignored", "CorruptionIgnored", 72 * time.Hour},
+	} {
+		if _, err := db.Exec(`INSERT INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at)
+			VALUES (?, ?, ?, ?, ?)`, c.id, "/media/"+c.id+".mkv", c.state, now.Add(-c.age), now); err != nil {
+			t.Fatalf("Failed to seed corruption: %v", err)
+		}
+	}
+
+	// Disabled by default
+	h.checkSLABreaches()
+	if status, err := h.GetSLAStatus(context.Background()); status != nil || err != nil {
+		t.Errorf("Expected no SLA status while disabled, got %+v (%v)", status, err)
+	}
+
+	h.SetResolutionSLA(48 * time.Hour)
+	h.checkSLABreaches()
+
+	select {
+	case event := <-eventCh:
+		if event.AggregateType != "sla" || event.AggregateID != "sla_sla-overdue" {
+			t.Errorf("Unexpected aggregate %s/%s", event.AggregateType, event.AggregateID)
+		}
+		if event.EventData["corruption_id"] != "sla-overdue" || event.EventData["current_state"] != "SearchStarted" {
+			t.Errorf("Unexpected event data: %v", event.EventData)
+		}
+		if elapsed, _ := event.EventData["elapsed_hours"].(float64); elapsed < 71 || elapsed > 73 {
+			t.Errorf("Expected ~72 elapsed hours, got %v", event.EventData["elapsed_hours"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an SLABreached event")
+	}
+
+	// A breach is only raised once per corruption
+	h.checkSLABreaches()
+	select {
+	case event := <-eventCh:
+		t.Errorf("Unexpected repeat SLABreached event for %s", event.AggregateID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	status, err := h.GetSLAStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetSLAStatus failed: %v", err)
+	}
+	if status.SLAHours != 48 || status.BreachedUnresolved != 1 || status.BreachesTotal != 1 {
+		t.Errorf("Unexpected SLA status: %+v", status)
+	}
+	if status.OldestUnresolvedHours < 71 || status.OldestUnresolvedHours > 73 {
+		t.Errorf("Expected oldest unresolved ~72h, got %v", status.OldestUnresolvedHours)
+	}
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrInvalidMigration is returned for a library migration that can't be done,
+// e.g. to an instance of another type or without scan paths to move.
+var ErrInvalidMigration = errors.New("invalid library migration")
+
+// mediaAggregateID is the aggregate ID of a corruption's MediaReresolved
+// events. It is kept apart from the corruption's own aggregate so the lookup
+// does not become its current state.
+func mediaAggregateID(corruptionID string) string {
+	return "media_" + corruptionID
+}
+
+// LibraryMigrationOptions selects the scan paths moved to another *arr
+// instance.
+type LibraryMigrationOptions struct {
+	FromInstanceID int64
+	ToInstanceID   int64
+	PathIDs        []int64 // empty: every scan path of the source instance
+	DryRun         bool
+}
+
+// MigratedScanPath is a scan path moved to the new instance.
+type MigratedScanPath struct {
+	PathID    int64  `json:"path_id"`
+	LocalPath string `json:"local_path"`
+	ArrPath   string `json:"arr_path"`
+	// UnboundTag is the instance tag the path was bound by; the migration
+	// binds it to the new instance directly, so a tag sync doesn't move it back.
+	UnboundTag string `json:"unbound_tag,omitempty"`
+}
+
+// ReresolvedCorruption is an open corruption whose media is looked up on the
+// new instance. Open corruptions without a recorded media ID look it up when
+// they are remediated and aren't listed.
+type ReresolvedCorruption struct {
+	CorruptionID string `json:"corruption_id"`
+	FilePath     string `json:"file_path"`
+	PathID       int64  `json:"path_id"`
+	State        string `json:"state"`
+	OldMediaID   int64  `json:"old_media_id"`
+	MediaID      int64  `json:"media_id,omitempty"` // 0 until re-resolved
+	Error        string `json:"error,omitempty"`
+}
+
+// LibraryMigration is the outcome of MigrateLibrary.
+type LibraryMigration struct {
+	DryRun       bool                   `json:"dry_run"`
+	FromInstance string                 `json:"from_instance"`
+	ToInstance   string                 `json:"to_instance"`
+	Paths        []MigratedScanPath     `json:"paths"`
+	Corruptions  []ReresolvedCorruption `json:"corruptions"`
+	Reresolved   int                    `json:"reresolved"`
+	Unresolved   int                    `json:"unresolved"`
+}
+
+// migrationInstance is an *arr instance taking part in a migration.
+type migrationInstance struct {
+	id           int64
+	name         string
+	instanceType string
+	enabled      bool
+	deleted      bool
+}
+
+// MigrateLibrary moves scan paths from one *arr instance to another of the
+// same type, e.g. after replacing Sonarr with a new install. The scan paths
+// keep their IDs and with them their corruptions and event history. The media
+// IDs of the old instance mean nothing to the new one, so the open
+// corruptions on the moved paths are looked up again and a MediaReresolved
+// event records the new ID, which remediation uses from then on. Lookups that
+// fail are reported; those corruptions look their media up again when they
+// are next remediated. A dry run reports the paths and corruptions without
+// changing anything or asking the new instance.
+func MigrateLibrary(ctx context.Context, database *sql.DB, eb *eventbus.EventBus, mapper integration.PathMapper, arrClient integration.ArrClient, opts LibraryMigrationOptions) (*LibraryMigration, error) {
+	if opts.FromInstanceID <= 0 || opts.ToInstanceID <= 0 || opts.FromInstanceID == opts.ToInstanceID {
+		return nil, fmt.Errorf("%w: needs two different instances", ErrInvalidMigration)
+	}
+	from, err := loadMigrationInstance(ctx, database, opts.FromInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := loadMigrationInstance(ctx, database, opts.ToInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case to.deleted || !to.enabled:
+		return nil, fmt.Errorf("%w: %s is disabled or deleted", ErrInvalidMigration, to.name)
+	case to.instanceType != from.instanceType:
+		return nil, fmt.Errorf("%w: %s is %s, %s is %s", ErrInvalidMigration,
+			from.name, from.instanceType, to.name, to.instanceType)
+	}
+
+	migration := &LibraryMigration{
+		DryRun:       opts.DryRun,
+		FromInstance: from.name,
+		ToInstance:   to.name,
+	}
+	err = db.TxWithRetry(database, func(tx *sql.Tx) error {
+		paths, err := loadMigratedScanPaths(ctx, tx, from, opts.PathIDs)
+		if err != nil {
+			return err
+		}
+		corruptions, err := loadReresolvedCorruptions(ctx, tx, paths)
+		if err != nil {
+			return err
+		}
+		migration.Paths, migration.Corruptions = paths, corruptions
+		if opts.DryRun {
+			return nil
+		}
+		for _, p := range migration.Paths {
+			if _, err := tx.ExecContext(ctx, `UPDATE scan_paths SET arr_instance_id = ?, arr_instance_tag = '' WHERE id = ?`,
+				to.id, p.PathID); err != nil {
+				return err
+			}
+			// Overridden media IDs are the old instance's too; look them up by the overridden path
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE corruption_mapping_overrides SET media_id = 0, updated_at = CURRENT_TIMESTAMP
+				WHERE media_id > 0 AND (file_path = ? OR instr(file_path, ?) = 1)
+			`, p.LocalPath, strings.TrimRight(p.LocalPath, "/")+"/"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return migration, nil
+	}
+
+	logger.Infof("Migrated %d scan path(s) from %s to %s, re-resolving %d open corruption(s)",
+		len(migration.Paths), from.name, to.name, len(migration.Corruptions))
+	for i := range migration.Corruptions {
+		c := &migration.Corruptions[i]
+		if err := reresolveMedia(ctx, eb, mapper, arrClient, from, to, c); err != nil {
+			c.Error = err.Error()
+			migration.Unresolved++
+			logger.Warnf("Failed to re-resolve media of %s on %s: %v", c.FilePath, to.name, err)
+			continue
+		}
+		migration.Reresolved++
+	}
+	return migration, nil
+}
+
+// loadMigrationInstance loads an instance by ID, deleted or not.
+func loadMigrationInstance(ctx context.Context, database *sql.DB, id int64) (*migrationInstance, error) {
+	instance := &migrationInstance{id: id}
+	err := database.QueryRowContext(ctx, `
+		SELECT name, type, COALESCE(enabled, 0), deleted_at IS NOT NULL FROM arr_instances WHERE id = ?
+	`, id).Scan(&instance.name, &instance.instanceType, &instance.enabled, &instance.deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrArrInstanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// loadMigratedScanPaths loads the scan paths of from, or those of pathIDs,
+// which must all belong to it.
+func loadMigratedScanPaths(ctx context.Context, tx *sql.Tx, from *migrationInstance, pathIDs []int64) ([]MigratedScanPath, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, local_path, arr_path, arr_instance_tag FROM scan_paths
+		WHERE deleted_at IS NULL AND arr_instance_id = ?
+		ORDER BY id
+	`, from.id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wanted := make(map[int64]bool, len(pathIDs))
+	for _, id := range pathIDs {
+		wanted[id] = true
+	}
+	paths := []MigratedScanPath{}
+	for rows.Next() {
+		var p MigratedScanPath
+		if err := rows.Scan(&p.PathID, &p.LocalPath, &p.ArrPath, &p.UnboundTag); err != nil {
+			return nil, err
+		}
+		if len(pathIDs) > 0 && !wanted[p.PathID] {
+			continue
+		}
+		delete(wanted, p.PathID)
+		paths = append(paths, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for _, id := range pathIDs {
+			if wanted[id] {
+				missing = append(missing, fmt.Sprintf("%d", id))
+			}
+		}
+		return nil, fmt.Errorf("%w: scan paths %s are not paths of %s", ErrInvalidMigration, strings.Join(missing, ", "), from.name)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: %s has no scan paths", ErrInvalidMigration, from.name)
+	}
+	return paths, nil
+}
+
+// loadReresolvedCorruptions loads the unresolved corruptions on paths with
+// the media ID they last recorded.
+func loadReresolvedCorruptions(ctx context.Context, tx *sql.Tx, paths []MigratedScanPath) ([]ReresolvedCorruption, error) {
+	// Security: only ? placeholders are concatenated, the IDs are passed as args
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(paths)), ", ")
+	args := make([]interface{}, len(paths))
+	for i, p := range paths {
+		args[i] = p.PathID
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT cs.corruption_id, COALESCE(cs.file_path, ''), cs.path_id, cs.current_state,
+			(
+				SELECT json_extract(e.event_data, '$.media_id')
+				FROM events e
+				WHERE e.aggregate_id IN (cs.corruption_id, 'media_' || cs.corruption_id)
+				AND e.event_type IN ('SearchCompleted', 'SearchStarted', 'DeletionCompleted', 'MediaReresolved')
+				ORDER BY e.id DESC
+				LIMIT 1
+			) as media_id
+		FROM corruption_summary cs
+		WHERE cs.path_id IN (`+placeholders+`)
+		AND cs.current_state NOT IN (`+resolvedStatesSQL+`)
+		ORDER BY cs.detected_at, cs.corruption_id
+	`, args...) // NOSONAR - parameterized query
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	corruptions := []ReresolvedCorruption{}
+	for rows.Next() {
+		var c ReresolvedCorruption
+		var mediaID sql.NullFloat64
+		if err := rows.Scan(&c.CorruptionID, &c.FilePath, &c.PathID, &c.State, &mediaID); err != nil {
+			return nil, err
+		}
+		if !mediaID.Valid || mediaID.Float64 <= 0 {
+			continue
+		}
+		c.OldMediaID = int64(mediaID.Float64)
+		corruptions = append(corruptions, c)
+	}
+	return corruptions, rows.Err()
+}
+
+// reresolveMedia looks the media of a corruption up on the new instance and
+// records it with a MediaReresolved event, together with the episode or album
+// IDs a search on the new instance needs.
+func reresolveMedia(ctx context.Context, eb *eventbus.EventBus, mapper integration.PathMapper, arrClient integration.ArrClient, from, to *migrationInstance, c *ReresolvedCorruption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	arrPath, err := mapper.ToArrPath(c.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to map path: %w", err)
+	}
+	mediaID, err := arrClient.FindMediaByPath(arrPath)
+	if err != nil {
+		return fmt.Errorf("failed to find media: %w", err)
+	}
+	metadata, err := arrClient.FindSearchMetadata(mediaID, arrPath)
+	if err != nil {
+		return fmt.Errorf("failed to find episodes: %w", err)
+	}
+	if err := eb.Publish(domain.Event{
+		AggregateType: "media",
+		AggregateID:   mediaAggregateID(c.CorruptionID),
+		EventType:     domain.MediaReresolved,
+		EventData: map[string]interface{}{
+			"corruption_id":    c.CorruptionID,
+			"file_path":        c.FilePath,
+			"path_id":          c.PathID,
+			"arr_path":         arrPath,
+			"from_instance_id": from.id,
+			"to_instance_id":   to.id,
+			"instance_name":    to.name,
+			"old_media_id":     c.OldMediaID,
+			"media_id":         mediaID,
+			"metadata":         metadata,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to record media: %w", err)
+	}
+	c.MediaID = mediaID
+	return nil
+}
+
+// reresolvedMedia returns the media ID and search metadata a library
+// migration looked up for a corruption after the event with ID afterID, if
+// there are any.
+func reresolvedMedia(ctx context.Context, database *sql.DB, corruptionID string, afterID int64) (int64, map[string]interface{}, bool) {
+	var mediaID sql.NullFloat64
+	var metadataJSON sql.NullString
+	err := database.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.media_id'), json_extract(event_data, '$.metadata')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = 'MediaReresolved' AND id > ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, mediaAggregateID(corruptionID), afterID).Scan(&mediaID, &metadataJSON)
+	if err != nil || !mediaID.Valid || mediaID.Float64 <= 0 {
+		return 0, nil, false
+	}
+	return int64(mediaID.Float64), parseEventMetadata(metadataJSON), true
+}
+
+// parseEventMetadata decodes the metadata object of an event, nil if there is none.
+func parseEventMetadata(metadataJSON sql.NullString) map[string]interface{} {
+	if !metadataJSON.Valid || metadataJSON.String == "" {
+		return nil
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
+		logger.Debugf("Failed to decode event metadata: %v", err)
+		return nil
+	}
+	return metadata
+}
+
+// mediaReresolution is the media of a corruption looked up on a new instance.
+type mediaReresolution struct {
+	mediaID  int64
+	metadata map[string]interface{}
+}
+
+// handleMediaReresolved hands the media ID looked up after a library
+// migration to the download monitor of the corruption, if one is running.
+func (v *VerifierService) handleMediaReresolved(event domain.Event) {
+	corruptionID, _ := event.GetString("corruption_id")
+	mediaID, _ := event.GetInt64("media_id")
+	if corruptionID == "" || mediaID <= 0 {
+		return
+	}
+	v.activeVerifyMu.Lock()
+	_, active := v.activeVerify[corruptionID]
+	v.activeVerifyMu.Unlock()
+	if !active {
+		return
+	}
+	metadata, _ := event.GetMap("metadata")
+	v.reresolvedMu.Lock()
+	v.reresolved[corruptionID] = mediaReresolution{mediaID: mediaID, metadata: metadata}
+	v.reresolvedMu.Unlock()
+}
+
+// applyReresolvedMedia switches a download monitor to the media looked up on
+// the new instance. The episode IDs of the old instance are replaced along
+// with the old media ID.
+func (v *VerifierService) applyReresolvedMedia(state *monitorState) {
+	v.reresolvedMu.Lock()
+	media, ok := v.reresolved[state.corruptionID]
+	delete(v.reresolved, state.corruptionID)
+	v.reresolvedMu.Unlock()
+	if !ok || media.mediaID == state.mediaID {
+		return
+	}
+	logger.Infof("Download monitoring for %s continues with media ID %d on the new instance (was %d)",
+		state.corruptionID, media.mediaID, state.mediaID)
+	state.mediaID = media.mediaID
+	state.metadata = media.metadata
+	state.wasInQueue = false
+	state.apiFailureCount = 0
+}
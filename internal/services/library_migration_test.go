@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// seedMigrationLibrary creates an old and a new Sonarr, a Radarr, two scan
+// paths on the old Sonarr and corruptions on them: one deleted with media 10,
+// one resolved, and one detected without a media ID yet.
+func seedMigrationLibrary(t *testing.T, db *sql.DB) {
+	t.Helper()
+	for _, stmt := range []string{
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+			(1, 'Old Sonarr', 'sonarr', 'http://old:8989', 'key'),
+			(2, 'New Sonarr', 'sonarr', 'http://new:8989', 'key'),
+			(3, 'Radarr', 'radarr', 'http://radarr:7878', 'key')`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, arr_instance_tag) VALUES
+			(1, '/media/tv', '/tv', 1, 'old'), (2, '/media/anime', '/anime', 1, '')`,
+		`INSERT INTO corruption_mapping_overrides (corruption_id, file_path, arr_path, media_id) VALUES
+			('c-open', '/media/tv/Show/S01E01.mkv', '/tv/Show/S01E01.mkv', 10)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+	detected := func(id, path string, pathID int64) domain.Event {
+		return domain.Event{AggregateType: "corruption", AggregateID: id, EventType: domain.CorruptionDetected,
+			EventData: map[string]interface{}{"file_path": path, "path_id": pathID}}
+	}
+	if err := testutil.SeedEvents(db, []domain.Event{
+		detected("c-open", "/media/tv/Show/S01E01.mkv", 1),
+		{AggregateType: "corruption", AggregateID: "c-open", EventType: domain.DeletionCompleted,
+			EventData: map[string]interface{}{"media_id": 10, "metadata": map[string]interface{}{"episode_ids": []int64{100}}}},
+		{AggregateType: "corruption", AggregateID: "c-open", EventType: domain.SearchFailed,
+			EventData: map[string]interface{}{"media_id": 10, "error": "series not found"}},
+		detected("c-done", "/media/tv/Show/S01E02.mkv", 1),
+		{AggregateType: "corruption", AggregateID: "c-done", EventType: domain.SearchCompleted,
+			EventData: map[string]interface{}{"media_id": 10}},
+		{AggregateType: "corruption", AggregateID: "c-done", EventType: domain.VerificationSuccess},
+		detected("c-new", "/media/anime/Show/S01E01.mkv", 2),
+	}); err != nil {
+		t.Fatalf("Failed to seed events: %v", err)
+	}
+}
+
+func TestMigrateLibrary(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	seedMigrationLibrary(t, db)
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	var lookedUp []string
+	arrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) {
+			lookedUp = append(lookedUp, path)
+			return 42, nil
+		},
+		FindSearchMetadataFunc: func(mediaID int64, path string) (map[string]interface{}, error) {
+			return map[string]interface{}{"deleted_path": path, "episode_ids": []int64{420}}, nil
+		},
+	}
+	mapper := &testutil.MockPathMapper{
+		ToArrPathFunc: func(localPath string) (string, error) {
+			return strings.Replace(localPath, "/media/tv", "/tv", 1), nil
+		},
+	}
+	ctx := context.Background()
+	instanceOf := func(pathID int64) (instanceID int64, tag string) {
+		if err := db.QueryRow(`SELECT arr_instance_id, arr_instance_tag FROM scan_paths WHERE id = ?`, pathID).Scan(&instanceID, &tag); err != nil {
+			t.Fatalf("Failed to query scan path: %v", err)
+		}
+		return instanceID, tag
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		migration, err := MigrateLibrary(ctx, db, eb, mapper, arrClient, LibraryMigrationOptions{
+			FromInstanceID: 1, ToInstanceID: 2, DryRun: true,
+		})
+		if err != nil {
+			t.Fatalf("MigrateLibrary() error = %v", err)
+		}
+		if len(migration.Paths) != 2 || len(migration.Corruptions) != 1 {
+			t.Fatalf("Expected 2 paths and 1 corruption, got %+v", migration)
+		}
+		if c := migration.Corruptions[0]; c.CorruptionID != "c-open" || c.OldMediaID != 10 || c.State != "SearchFailed" {
+			t.Errorf("Unexpected corruption %+v", c)
+		}
+		if id, _ := instanceOf(1); id != 1 || len(lookedUp) != 0 {
+			t.Errorf("Expected a dry run to change and look up nothing, instance %d, lookups %v", id, lookedUp)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, opts := range map[string]LibraryMigrationOptions{
+			"same instance":    {FromInstanceID: 1, ToInstanceID: 1},
+			"other type":       {FromInstanceID: 1, ToInstanceID: 3},
+			"foreign path":     {FromInstanceID: 2, ToInstanceID: 1, PathIDs: []int64{1}},
+			"no paths to move": {FromInstanceID: 2, ToInstanceID: 1},
+		} {
+			if _, err := MigrateLibrary(ctx, db, eb, mapper, arrClient, opts); !errors.Is(err, ErrInvalidMigration) {
+				t.Errorf("%s: error = %v, want ErrInvalidMigration", name, err)
+			}
+		}
+		if _, err := MigrateLibrary(ctx, db, eb, mapper, arrClient, LibraryMigrationOptions{FromInstanceID: 1, ToInstanceID: 9}); !errors.Is(err, ErrArrInstanceNotFound) {
+			t.Errorf("Unknown instance: error = %v, want ErrArrInstanceNotFound", err)
+		}
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		reresolved := make(chan domain.Event, 1)
+		eb.Subscribe(domain.MediaReresolved, func(e domain.Event) { reresolved <- e })
+
+		migration, err := MigrateLibrary(ctx, db, eb, mapper, arrClient, LibraryMigrationOptions{
+			FromInstanceID: 1, ToInstanceID: 2, PathIDs: []int64{1},
+		})
+		if err != nil {
+			t.Fatalf("MigrateLibrary() error = %v", err)
+		}
+		if len(migration.Paths) != 1 || migration.Reresolved != 1 || migration.Unresolved != 0 {
+			t.Fatalf("Expected 1 path and 1 re-resolved corruption, got %+v", migration)
+		}
+		if migration.Paths[0].UnboundTag != "old" {
+			t.Errorf("UnboundTag = %q, want old", migration.Paths[0].UnboundTag)
+		}
+		if id, tag := instanceOf(1); id != 2 || tag != "" {
+			t.Errorf("Scan path 1 is on instance %d with tag %q, want 2 without a tag", id, tag)
+		}
+		if id, _ := instanceOf(2); id != 1 {
+			t.Errorf("Expected scan path 2 to stay on instance 1, got %d", id)
+		}
+		if len(lookedUp) != 1 || lookedUp[0] != "/tv/Show/S01E01.mkv" {
+			t.Errorf("Looked up %v, want the *arr path of c-open", lookedUp)
+		}
+
+		select {
+		case e := <-reresolved:
+			if e.AggregateID != mediaAggregateID("c-open") {
+				t.Errorf("Expected the media aggregate, got %q", e.AggregateID)
+			}
+			if mediaID, _ := e.GetInt64("media_id"); mediaID != 42 {
+				t.Errorf("media_id = %d, want 42", mediaID)
+			}
+			if metadata, _ := e.GetMap("metadata"); len(extractEpisodeIDs(metadata)) != 1 {
+				t.Errorf("Expected the episode IDs on the new instance, got %v", metadata)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected a MediaReresolved event")
+		}
+
+		// The corruption keeps its state, and its media ID is the new one
+		var state string
+		if err := db.QueryRow(`SELECT current_state FROM corruption_summary WHERE corruption_id = 'c-open'`).Scan(&state); err != nil {
+			t.Fatalf("Failed to query summary: %v", err)
+		}
+		if state != "SearchFailed" {
+			t.Errorf("current_state = %q, want SearchFailed", state)
+		}
+		var overrideMediaID int64
+		if err := db.QueryRow(`SELECT media_id FROM corruption_mapping_overrides WHERE corruption_id = 'c-open'`).Scan(&overrideMediaID); err != nil {
+			t.Fatalf("Failed to query override: %v", err)
+		}
+		if overrideMediaID != 0 {
+			t.Errorf("Expected the overridden media ID of the old instance to be cleared, got %d", overrideMediaID)
+		}
+
+		// Searches use the episode IDs of the new instance, not those recorded on deletion
+		remediator := &RemediatorService{db: db}
+		deleted, mediaID, metadata := remediator.checkDeletionCompleted("c-open")
+		if episodeIDs := extractEpisodeIDs(metadata); !deleted || mediaID != 42 || len(episodeIDs) != 1 || episodeIDs[0] != 420 {
+			t.Errorf("checkDeletionCompleted() = %v, %d, %v, want true, 42, episode 420", deleted, mediaID, metadata)
+		}
+		verifier := &VerifierService{db: db}
+		if mediaID := verifier.getMediaID("c-open"); mediaID != 42 {
+			t.Errorf("getMediaID() = %d, want 42", mediaID)
+		}
+	})
+}
+
+func TestReresolvedMedia_NewerDeletionWins(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	reresolvedID, err := testutil.SeedEvent(db, domain.Event{AggregateType: "media", AggregateID: mediaAggregateID("c-1"),
+		EventType: domain.MediaReresolved, EventData: map[string]interface{}{"corruption_id": "c-1", "media_id": 42,
+			"metadata": map[string]interface{}{"episode_ids": []int64{420}}}})
+	if err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+	ctx := context.Background()
+	if mediaID, metadata, ok := reresolvedMedia(ctx, db, "c-1", reresolvedID-1); !ok || mediaID != 42 || len(extractEpisodeIDs(metadata)) != 1 {
+		t.Errorf("reresolvedMedia() = %d, %v, %v, want 42, episode 420, true", mediaID, metadata, ok)
+	}
+	// A deletion on the new instance after the migration already has the right IDs
+	if _, _, ok := reresolvedMedia(ctx, db, "c-1", reresolvedID); ok {
+		t.Error("Expected no re-resolved media after a newer deletion")
+	}
+}
+
+func TestVerifierService_ApplyReresolvedMedia(t *testing.T) {
+	verifier := NewVerifierService(nil, nil, nil, nil, nil)
+	state := &monitorState{
+		corruptionID: "c-1", mediaID: 10, wasInQueue: true,
+		metadata: map[string]interface{}{"episode_ids": []int64{100}},
+	}
+
+	// Only running monitors pick up a new media ID
+	verifier.handleMediaReresolved(domain.Event{EventData: map[string]interface{}{"corruption_id": "c-2", "media_id": 7}})
+	verifier.registerVerification("c-1", func() {})
+	verifier.handleMediaReresolved(domain.Event{EventData: map[string]interface{}{"corruption_id": "c-1", "media_id": 42,
+		"metadata": map[string]interface{}{"episode_ids": []int64{420}}}})
+	if len(verifier.reresolved) != 1 {
+		t.Fatalf("Expected only the running monitor to be told, got %v", verifier.reresolved)
+	}
+
+	verifier.applyReresolvedMedia(state)
+	if episodeIDs := extractEpisodeIDs(state.metadata); state.mediaID != 42 || len(episodeIDs) != 1 || episodeIDs[0] != 420 || state.wasInQueue {
+		t.Errorf("Expected the monitor to continue with media 42 and episode 420, got %+v", state)
+	}
+	verifier.applyReresolvedMedia(state)
+	if state.mediaID != 42 || len(verifier.reresolved) != 0 {
+		t.Errorf("Expected the new media ID to be applied once, got %d", state.mediaID)
+	}
+}
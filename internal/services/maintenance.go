@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/mescon/Healarr/internal/clock"
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// MaintenanceStepPurgeDeleted is the maintenance step that purges deleted scan
+// paths and *arr instances past their retention. It runs before the database steps.
+const MaintenanceStepPurgeDeleted = "purge_deleted"
+
+// MaintenanceStepPruneSamples is the maintenance step that removes
+// corrupted-file samples past their retention. It runs when samples are set.
+const MaintenanceStepPruneSamples = "prune_samples"
+
+// Maintenance run triggers.
+const (
+	MaintenanceTriggerScheduled = "scheduled"
+	MaintenanceTriggerManual    = "manual"
+)
+
+// Maintenance run and step states.
+const (
+	MaintenancePending   = "pending"
+	MaintenanceRunning   = "running"
+	MaintenanceCompleted = "completed"
+	MaintenanceSkipped   = "skipped"
+	MaintenanceFailed    = "failed"
+	MaintenanceAborted   = "aborted"
+)
+
+var (
+	// ErrMaintenanceRunning is returned when maintenance is triggered while a run is in progress.
+	ErrMaintenanceRunning = errors.New("maintenance is already running")
+	// ErrMaintenanceNotRunning is returned when aborting while no run is in progress.
+	ErrMaintenanceNotRunning = errors.New("maintenance is not running")
+)
+
+// MaintenanceOptions adjusts a single maintenance run.
+type MaintenanceOptions struct {
+	// SkipVacuum leaves out the incremental vacuum, e.g. during peak hours.
+	SkipVacuum bool `json:"skip_vacuum"`
+}
+
+// MaintenanceStepStatus is the progress of one maintenance step.
+type MaintenanceStepStatus struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Reason     string `json:"reason,omitempty"` // why the step was skipped, failed or aborted
+}
+
+// MaintenanceRun describes a running or finished maintenance run.
+type MaintenanceRun struct {
+	Trigger     string                  `json:"trigger"`
+	Status      string                  `json:"status"`
+	StartedAt   time.Time               `json:"started_at"`
+	FinishedAt  *time.Time              `json:"finished_at,omitempty"`
+	DurationMs  int64                   `json:"duration_ms"` // so far while running
+	CurrentStep string                  `json:"current_step,omitempty"`
+	Progress    int                     `json:"progress"` // percent of steps finished
+	Steps       []MaintenanceStepStatus `json:"steps"`
+}
+
+// MaintenanceStatus is the maintenance schedule with the current and last run.
+type MaintenanceStatus struct {
+	Schedule  string          `json:"schedule"`             // empty when scheduled maintenance is off
+	PeakHours string          `json:"peak_hours,omitempty"` // local time window in which vacuum doesn't run
+	NextRunAt *time.Time      `json:"next_run_at,omitempty"`
+	Current   *MaintenanceRun `json:"current,omitempty"`
+	LastRun   *MaintenanceRun `json:"last_run,omitempty"`
+}
+
+// MaintenanceService runs database maintenance on a cron schedule or on
+// demand, one run at a time, and reports the progress of each step. The
+// incremental vacuum doesn't start during peak hours and is interrupted when
+// they begin.
+type MaintenanceService struct {
+	repo                 *db.Repository
+	deletedConfig        *DeletedConfigService
+	policy               db.RetentionPolicy
+	deletedRetentionDays int
+	clk                  clock.Clock
+	steps                func() []db.MaintenanceStep // replaced in tests
+	// samples are the corrupted-file samples pruned by each run (nil skips the step)
+	samples *SampleExtractor
+
+	cron     *cron.Cron
+	entry    cron.EntryID
+	schedule string
+	peak     *dailyWindow
+	peakSpec string
+
+	mu      sync.Mutex
+	current *MaintenanceRun
+	last    *MaintenanceRun
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewMaintenanceService creates a new MaintenanceService that prunes history
+// according to policy and purges deleted config after deletedRetentionDays.
+func NewMaintenanceService(repo *db.Repository, policy db.RetentionPolicy, deletedRetentionDays int) *MaintenanceService {
+	m := &MaintenanceService{
+		repo:                 repo,
+		deletedConfig:        NewDeletedConfigService(repo.DB),
+		policy:               policy,
+		deletedRetentionDays: deletedRetentionDays,
+		clk:                  clock.NewRealClock(),
+	}
+	m.steps = m.maintenanceSteps
+	return m
+}
+
+// maintenanceSteps returns the steps of a run: purging deleted config and
+// pruning samples, then the database maintenance steps.
+func (m *MaintenanceService) maintenanceSteps() []db.MaintenanceStep {
+	steps := []db.MaintenanceStep{{
+		Name: MaintenanceStepPurgeDeleted,
+		Run:  m.purgeDeleted,
+	}}
+	if m.samples != nil {
+		steps = append(steps, db.MaintenanceStep{Name: MaintenanceStepPruneSamples, Run: m.samples.Prune})
+	}
+	return append(steps, m.repo.MaintenanceSteps(m.policy)...)
+}
+
+// SetSampleExtractor makes every run prune the corrupted-file samples past
+// their retention. Call it before Start.
+func (m *MaintenanceService) SetSampleExtractor(x *SampleExtractor) {
+	m.samples = x
+}
+
+// Start runs maintenance on the given cron schedule, interpreted in the
+// timezone of a CRON_TZ= prefix or else the scheduler's, and keeps the vacuum out of peakHours ("HH:MM-HH:MM").
+// An empty schedule leaves scheduled maintenance off; an empty peakHours
+// lets the vacuum run at any time.
+func (m *MaintenanceService) Start(schedule, peakHours string) error {
+	if peakHours != "" {
+		peak, err := parseDailyWindow(peakHours)
+		if err != nil {
+			return err
+		}
+		m.mu.Lock()
+		m.peak, m.peakSpec = peak, peakHours
+		m.mu.Unlock()
+	}
+	if schedule == "" {
+		return nil
+	}
+	spec, err := ParseSchedule(schedule, domain.ScheduleTiming{})
+	if err != nil {
+		return fmt.Errorf("invalid maintenance schedule %q: %w", schedule, err)
+	}
+	c := cron.New(cron.WithLocation(cronLocation()))
+	entry := c.Schedule(spec, cron.FuncJob(m.runScheduled))
+	m.mu.Lock()
+	m.cron, m.entry, m.schedule = c, entry, schedule
+	m.mu.Unlock()
+	c.Start()
+	return nil
+}
+
+// Stop stops the schedule, aborts a running maintenance run and waits for it to end.
+func (m *MaintenanceService) Stop() {
+	m.mu.Lock()
+	c, cancel, done := m.cron, m.cancel, m.done
+	m.mu.Unlock()
+	if c != nil {
+		c.Stop()
+	}
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (m *MaintenanceService) runScheduled() {
+	_, done, err := m.start(MaintenanceTriggerScheduled, MaintenanceOptions{})
+	if err != nil {
+		logger.Warnf("Skipping scheduled maintenance: %v", err)
+		return
+	}
+	<-done
+}
+
+// Trigger starts a maintenance run in the background and returns its initial
+// state. It returns ErrMaintenanceRunning if a run is already in progress.
+func (m *MaintenanceService) Trigger(opts MaintenanceOptions) (*MaintenanceRun, error) {
+	run, _, err := m.start(MaintenanceTriggerManual, opts)
+	return run, err
+}
+
+// Abort cancels the running maintenance run. The current step is interrupted
+// and the remaining steps are skipped.
+func (m *MaintenanceService) Abort() error {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel == nil {
+		return ErrMaintenanceNotRunning
+	}
+	cancel()
+	return nil
+}
+
+// Wait blocks until the running maintenance run, if any, has finished.
+func (m *MaintenanceService) Wait() {
+	m.mu.Lock()
+	done := m.done
+	m.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// Status returns the schedule and copies of the current and last run.
+func (m *MaintenanceService) Status() MaintenanceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := MaintenanceStatus{
+		Schedule:  m.schedule,
+		PeakHours: m.peakSpec,
+		Current:   m.snapshot(m.current),
+		LastRun:   m.snapshot(m.last),
+	}
+	if m.cron != nil {
+		if next := m.cron.Entry(m.entry).Next; !next.IsZero() {
+			status.NextRunAt = &next
+		}
+	}
+	return status
+}
+
+// snapshot copies a run so it can be read while the run goes on. Must hold m.mu.
+func (m *MaintenanceService) snapshot(run *MaintenanceRun) *MaintenanceRun {
+	if run == nil {
+		return nil
+	}
+	cp := *run
+	cp.Steps = append([]MaintenanceStepStatus(nil), run.Steps...)
+	if cp.FinishedAt == nil {
+		cp.DurationMs = m.clk.Now().Sub(cp.StartedAt).Milliseconds()
+	}
+	return &cp
+}
+
+// start begins a run in the background and returns its initial state and a
+// channel closed when it ends.
+func (m *MaintenanceService) start(trigger string, opts MaintenanceOptions) (*MaintenanceRun, <-chan struct{}, error) {
+	steps := m.steps()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current != nil {
+		return nil, nil, ErrMaintenanceRunning
+	}
+
+	run := &MaintenanceRun{
+		Trigger:   trigger,
+		Status:    MaintenanceRunning,
+		StartedAt: m.clk.Now(),
+		Steps:     make([]MaintenanceStepStatus, len(steps)),
+	}
+	for i, step := range steps {
+		run.Steps[i] = MaintenanceStepStatus{Name: step.Name, Status: MaintenancePending}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	m.current, m.cancel, m.done = run, cancel, done
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		m.run(ctx, run, steps, opts)
+	}()
+	return m.snapshot(run), done, nil
+}
+
+// run executes the steps of a run in order, recording their progress.
+func (m *MaintenanceService) run(ctx context.Context, run *MaintenanceRun, steps []db.MaintenanceStep, opts MaintenanceOptions) {
+	logger.Infof("Starting database maintenance (%s)...", run.Trigger)
+
+	failed := false
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			m.finishStep(run, i, MaintenanceSkipped, 0, "maintenance was aborted")
+			continue
+		}
+
+		stepCtx, cancel := ctx, context.CancelFunc(func() {})
+		if step.Name == db.MaintenanceStepVacuum {
+			if reason := m.vacuumSkipReason(opts); reason != "" {
+				logger.Infof("Skipping incremental vacuum: %s", reason)
+				m.finishStep(run, i, MaintenanceSkipped, 0, reason)
+				continue
+			}
+			if m.peak != nil {
+				stepCtx, cancel = context.WithDeadline(ctx, m.peak.nextStart(m.clk.Now().In(cronLocation())))
+			}
+		}
+
+		m.mu.Lock()
+		run.CurrentStep = step.Name
+		run.Steps[i].Status = MaintenanceRunning
+		m.mu.Unlock()
+
+		started := m.clk.Now()
+		err := step.Run(stepCtx)
+		elapsed := m.clk.Now().Sub(started)
+		deadlineHit := errors.Is(stepCtx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			m.finishStep(run, i, MaintenanceAborted, elapsed, "maintenance was aborted")
+		case deadlineHit:
+			logger.Infof("Incremental vacuum interrupted: peak hours started")
+			m.finishStep(run, i, MaintenanceAborted, elapsed, "peak hours started")
+		case err != nil:
+			failed = true
+			m.finishStep(run, i, MaintenanceFailed, elapsed, err.Error())
+		default:
+			m.finishStep(run, i, MaintenanceCompleted, elapsed, "")
+		}
+	}
+
+	m.mu.Lock()
+	finished := m.clk.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.CurrentStep = ""
+	switch {
+	case ctx.Err() != nil:
+		run.Status = MaintenanceAborted
+	case failed:
+		run.Status = MaintenanceFailed
+	default:
+		run.Status = MaintenanceCompleted
+	}
+	m.last, m.current, m.cancel, m.done = run, nil, nil, nil
+	m.mu.Unlock()
+
+	if run.Status == MaintenanceCompleted {
+		logger.Infof("✓ Database maintenance completed in %s", time.Duration(run.DurationMs)*time.Millisecond)
+	} else {
+		logger.Warnf("Database maintenance %s after %s", run.Status, time.Duration(run.DurationMs)*time.Millisecond)
+	}
+}
+
+// vacuumSkipReason returns why the vacuum shouldn't run now, or "" if it should.
+func (m *MaintenanceService) vacuumSkipReason(opts MaintenanceOptions) string {
+	if opts.SkipVacuum {
+		return "skipped on request"
+	}
+	if m.peak != nil && m.peak.contains(m.clk.Now().In(cronLocation())) {
+		return "peak hours"
+	}
+	return ""
+}
+
+// finishStep records the outcome of a step and updates the run's progress.
+func (m *MaintenanceService) finishStep(run *MaintenanceRun, i int, status string, elapsed time.Duration, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run.Steps[i] = MaintenanceStepStatus{
+		Name:       run.Steps[i].Name,
+		Status:     status,
+		DurationMs: elapsed.Milliseconds(),
+		Reason:     reason,
+	}
+	run.Progress = (i + 1) * 100 / len(run.Steps)
+}
+
+// purgeDeleted purges deleted scan paths and *arr instances past their retention.
+func (m *MaintenanceService) purgeDeleted(context.Context) error {
+	purged, err := m.deletedConfig.PurgeExpired(m.deletedRetentionDays)
+	if err != nil {
+		return fmt.Errorf("purge deleted scan paths and instances: %w", err)
+	}
+	if purged > 0 {
+		logger.Infof("Purged %d deleted scan paths and instances", purged)
+	}
+	return nil
+}
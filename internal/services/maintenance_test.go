@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func newTestMaintenanceService(t *testing.T) *MaintenanceService {
+	t.Helper()
+	sqlDB, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return NewMaintenanceService(&db.Repository{DB: sqlDB}, db.UniformRetention(90), 30)
+}
+
+func TestMaintenanceService_Run(t *testing.T) {
+	m := newTestMaintenanceService(t)
+
+	run, err := m.Trigger(MaintenanceOptions{})
+	if err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if run.Trigger != MaintenanceTriggerManual || run.Status != MaintenanceRunning || len(run.Steps) != 6 {
+		t.Errorf("Unexpected initial run: %+v", run)
+	}
+	m.Wait()
+
+	status := m.Status()
+	if status.Current != nil {
+		t.Errorf("Expected no current run, got %+v", status.Current)
+	}
+	last := status.LastRun
+	if last == nil || last.Status != MaintenanceCompleted || last.Progress != 100 || last.FinishedAt == nil {
+		t.Fatalf("Expected a completed run, got %+v", last)
+	}
+	wantSteps := []string{MaintenanceStepPurgeDeleted, db.MaintenanceStepPrune, db.MaintenanceStepSummary, db.MaintenanceStepVacuum, db.MaintenanceStepAnalyze, db.MaintenanceStepCheckpoint}
+	for i, step := range last.Steps {
+		if step.Name != wantSteps[i] || step.Status != MaintenanceCompleted {
+			t.Errorf("Step %d = %+v, want %s completed", i, step, wantSteps[i])
+		}
+	}
+}
+
+func TestMaintenanceService_SkipVacuum(t *testing.T) {
+	t.Setenv("HEALARR_TZ", "UTC")
+	m := newTestMaintenanceService(t)
+
+	vacuumStatus := func() MaintenanceStepStatus {
+		t.Helper()
+		m.Wait()
+		for _, step := range m.Status().LastRun.Steps {
+			if step.Name == db.MaintenanceStepVacuum {
+				return step
+			}
+		}
+		t.Fatal("Vacuum step missing")
+		return MaintenanceStepStatus{}
+	}
+
+	if _, err := m.Trigger(MaintenanceOptions{SkipVacuum: true}); err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if step := vacuumStatus(); step.Status != MaintenanceSkipped || step.Reason != "skipped on request" {
+		t.Errorf("Expected the vacuum skipped on request, got %+v", step)
+	}
+
+	m.clk = testutil.NewMockClockAt(time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC))
+	if err := m.Start("", "17:00-23:00"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := m.Trigger(MaintenanceOptions{}); err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	if step := vacuumStatus(); step.Status != MaintenanceSkipped || step.Reason != "peak hours" {
+		t.Errorf("Expected the vacuum skipped during peak hours, got %+v", step)
+	}
+	if got := m.Status().PeakHours; got != "17:00-23:00" {
+		t.Errorf("Expected peak hours in the status, got %q", got)
+	}
+}
+
+func TestMaintenanceService_Abort(t *testing.T) {
+	m := newTestMaintenanceService(t)
+
+	started := make(chan struct{})
+	m.steps = func() []db.MaintenanceStep {
+		return []db.MaintenanceStep{
+			{Name: "first", Run: func(context.Context) error { return nil }},
+			{Name: db.MaintenanceStepVacuum, Run: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+			{Name: "last", Run: func(context.Context) error { return errors.New("should not run") }},
+		}
+	}
+
+	if err := m.Abort(); !errors.Is(err, ErrMaintenanceNotRunning) {
+		t.Errorf("Expected ErrMaintenanceNotRunning, got %v", err)
+	}
+	if _, err := m.Trigger(MaintenanceOptions{}); err != nil {
+		t.Fatalf("Trigger() error = %v", err)
+	}
+	<-started
+
+	if _, err := m.Trigger(MaintenanceOptions{}); !errors.Is(err, ErrMaintenanceRunning) {
+		t.Errorf("Expected ErrMaintenanceRunning, got %v", err)
+	}
+	current := m.Status().Current
+	if current == nil || current.CurrentStep != db.MaintenanceStepVacuum || current.Progress != 33 {
+		t.Errorf("Expected the vacuum in progress, got %+v", current)
+	}
+
+	if err := m.Abort(); err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	m.Wait()
+
+	last := m.Status().LastRun
+	if last == nil || last.Status != MaintenanceAborted {
+		t.Fatalf("Expected an aborted run, got %+v", last)
+	}
+	want := []string{MaintenanceCompleted, MaintenanceAborted, MaintenanceSkipped}
+	for i, step := range last.Steps {
+		if step.Status != want[i] {
+			t.Errorf("Step %s = %s, want %s", step.Name, step.Status, want[i])
+		}
+	}
+}
+
+func TestMaintenanceService_StartInvalid(t *testing.T) {
+	m := newTestMaintenanceService(t)
+	if err := m.Start("not a cron", ""); err == nil {
+		t.Error("Expected an error for an invalid schedule")
+	}
+	if err := m.Start("0 3 * * *", "noon"); err == nil {
+		t.Error("Expected an error for invalid peak hours")
+	}
+
+	if err := m.Start("0 3 * * *", ""); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer m.Stop()
+	if status := m.Status(); status.Schedule != "0 3 * * *" || status.NextRunAt == nil {
+		t.Errorf("Expected the schedule and next run in the status, got %+v", status)
+	}
+}
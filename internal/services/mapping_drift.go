@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrInvalidRemap is returned for a remap without absolute, different paths.
+var ErrInvalidRemap = errors.New("remap needs two different absolute *arr paths")
+
+// MappingDrift is a scan path whose *arr path is outside every root folder of
+// its instance, e.g. after the library was moved to another root folder in
+// the *arr. Its files no longer match *arr media until it is remapped.
+type MappingDrift struct {
+	PathID       int64    `json:"path_id"`
+	LocalPath    string   `json:"local_path"`
+	ArrPath      string   `json:"arr_path"`
+	InstanceID   int64    `json:"instance_id"`
+	InstanceName string   `json:"instance_name"`
+	RootFolders  []string `json:"root_folders"`
+	// SuggestedArrPath is the root folder the library probably moved to: the
+	// one no other scan path covers, preferring one with the same folder name.
+	SuggestedArrPath string `json:"suggested_arr_path,omitempty"`
+}
+
+// RemappedScanPath is a scan path whose *arr path RemapScanPaths changed.
+type RemappedScanPath struct {
+	PathID     int64  `json:"path_id"`
+	LocalPath  string `json:"local_path"`
+	OldArrPath string `json:"old_arr_path"`
+	NewArrPath string `json:"new_arr_path"`
+}
+
+// driftScanPath is an enabled scan path with its instance.
+type driftScanPath struct {
+	id           int64
+	localPath    string
+	arrPath      string
+	instanceID   int64
+	instanceName string
+}
+
+// DetectMappingDrift compares the *arr path of every enabled scan path with
+// the root folders of its instance. Instances that can't be reached are
+// skipped; their paths are checked again next time.
+func DetectMappingDrift(ctx context.Context, database *sql.DB, arrClient integration.ArrClient) ([]MappingDrift, error) {
+	paths, err := loadDriftScanPaths(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	byInstance := make(map[int64][]driftScanPath)
+	var instanceIDs []int64
+	for _, p := range paths {
+		if _, ok := byInstance[p.instanceID]; !ok {
+			instanceIDs = append(instanceIDs, p.instanceID)
+		}
+		byInstance[p.instanceID] = append(byInstance[p.instanceID], p)
+	}
+
+	drifts := []MappingDrift{}
+	for _, instanceID := range instanceIDs {
+		folders, err := arrClient.GetRootFolders(instanceID)
+		if err != nil {
+			logger.Debugf("Mapping drift check skipped instance %d: %v", instanceID, err)
+			continue
+		}
+		roots := make([]string, 0, len(folders))
+		for _, f := range folders {
+			roots = append(roots, cleanArrPath(f.Path))
+		}
+		drifts = append(drifts, instanceDrift(byInstance[instanceID], roots)...)
+	}
+	return drifts, nil
+}
+
+// instanceDrift returns the scan paths of one instance outside its roots.
+func instanceDrift(paths []driftScanPath, roots []string) []MappingDrift {
+	if len(roots) == 0 {
+		// An instance without root folders is being set up, not moved
+		return nil
+	}
+	var drifted []driftScanPath
+	var matched []string
+	for _, p := range paths {
+		if overlapsAny(p.arrPath, roots) {
+			matched = append(matched, p.arrPath)
+		} else {
+			drifted = append(drifted, p)
+		}
+	}
+
+	// Root folders no matching scan path covers are where a library moved to
+	var free []string
+	for _, root := range roots {
+		if !overlapsAny(root, matched) {
+			free = append(free, root)
+		}
+	}
+
+	drifts := make([]MappingDrift, 0, len(drifted))
+	for _, p := range drifted {
+		drifts = append(drifts, MappingDrift{
+			PathID:           p.id,
+			LocalPath:        p.localPath,
+			ArrPath:          p.arrPath,
+			InstanceID:       p.instanceID,
+			InstanceName:     p.instanceName,
+			RootFolders:      roots,
+			SuggestedArrPath: suggestRemap(p.arrPath, free),
+		})
+	}
+	return drifts
+}
+
+// suggestRemap picks the root folder a moved *arr path probably went to.
+func suggestRemap(arrPath string, free []string) string {
+	for _, root := range free {
+		if strings.EqualFold(path.Base(root), path.Base(arrPath)) {
+			return root
+		}
+	}
+	if len(free) == 1 {
+		return free[0]
+	}
+	return ""
+}
+
+// overlapsAny reports whether p is inside one of paths or contains one.
+func overlapsAny(p string, paths []string) bool {
+	for _, other := range paths {
+		if arrPathWithin(other, p) || arrPathWithin(p, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// arrPathWithin reports whether p is parent or below it.
+func arrPathWithin(parent, p string) bool {
+	parent = strings.TrimRight(parent, "/")
+	if !strings.HasPrefix(p, parent) {
+		return false
+	}
+	rest := p[len(parent):]
+	return rest == "" || strings.HasPrefix(rest, "/")
+}
+
+// cleanArrPath normalizes an *arr path for comparison. *arr paths use forward
+// slashes even when Healarr runs elsewhere, so path and not filepath is used.
+func cleanArrPath(p string) string {
+	if p == "" {
+		return p
+	}
+	return path.Clean(strings.ReplaceAll(p, "\\", "/"))
+}
+
+// loadDriftScanPaths loads the enabled scan paths bound to an instance.
+func loadDriftScanPaths(ctx context.Context, database *sql.DB) ([]driftScanPath, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := database.QueryContext(ctx, `
+		SELECT sp.id, sp.local_path, sp.arr_path, i.id, i.name
+		FROM scan_paths sp
+		JOIN arr_instances i ON i.id = sp.arr_instance_id
+		WHERE sp.enabled = 1 AND sp.deleted_at IS NULL AND i.enabled = 1 AND i.deleted_at IS NULL
+		ORDER BY sp.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scan paths: %w", err)
+	}
+	defer rows.Close()
+	var paths []driftScanPath
+	for rows.Next() {
+		var p driftScanPath
+		if err := rows.Scan(&p.id, &p.localPath, &p.arrPath, &p.instanceID, &p.instanceName); err != nil {
+			return nil, err
+		}
+		p.arrPath = cleanArrPath(p.arrPath)
+		paths = append(paths, p)
+	}
+	return paths, rows.Err()
+}
+
+// RemapScanPaths replaces the *arr path prefix from with to in the scan paths
+// below from, of one instance if instanceID > 0. The scan paths keep their
+// IDs, so their corruption history, scans and settings stay with them. With
+// dryRun nothing is changed.
+func RemapScanPaths(ctx context.Context, database *sql.DB, instanceID int64, from, to string, dryRun bool) ([]RemappedScanPath, error) {
+	from, to = cleanArrPath(from), cleanArrPath(to)
+	if !path.IsAbs(from) || !path.IsAbs(to) || from == to {
+		return nil, ErrInvalidRemap
+	}
+
+	remapped := []RemappedScanPath{}
+	err := db.TxWithRetry(database, func(tx *sql.Tx) error {
+		remapped = remapped[:0]
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, local_path, arr_path FROM scan_paths
+			WHERE deleted_at IS NULL AND (? = 0 OR arr_instance_id = ?)
+			ORDER BY id
+		`, instanceID, instanceID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var r RemappedScanPath
+			if err := rows.Scan(&r.PathID, &r.LocalPath, &r.OldArrPath); err != nil {
+				rows.Close()
+				return err
+			}
+			current := cleanArrPath(r.OldArrPath)
+			if !arrPathWithin(from, current) {
+				continue
+			}
+			r.NewArrPath = path.Join(to, strings.TrimPrefix(current, strings.TrimRight(from, "/")))
+			remapped = append(remapped, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+		for _, r := range remapped {
+			if _, err := tx.ExecContext(ctx, `UPDATE scan_paths SET arr_path = ? WHERE id = ?`,
+				r.NewArrPath, r.PathID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return remapped, nil
+}
+
+// checkMappingDrift raises MappingDrift once for each scan path whose *arr
+// path no longer matches a root folder, and again if it drifts elsewhere.
+func (h *HealthMonitorService) checkMappingDrift() {
+	if h.arrClient == nil || h.db == nil {
+		return
+	}
+	drifts, err := DetectMappingDrift(context.Background(), h.db, h.arrClient)
+	if err != nil {
+		logger.Errorf("Mapping drift check failed: %v", err)
+		return
+	}
+
+	current := make(map[int64]string, len(drifts))
+	for _, d := range drifts {
+		current[d.PathID] = d.ArrPath
+		if h.reportedDrift[d.PathID] == d.ArrPath {
+			continue
+		}
+		logger.Warnf("Scan path %s maps to %s, which is outside the root folders of %s (%s)",
+			d.LocalPath, d.ArrPath, d.InstanceName, strings.Join(d.RootFolders, ", "))
+		if err := h.eventBus.Publish(domain.Event{
+			AggregateType: "scan_path",
+			AggregateID:   fmt.Sprintf("%d", d.PathID),
+			EventType:     domain.MappingDrift,
+			EventData: map[string]interface{}{
+				"path_id":            d.PathID,
+				"path":               d.LocalPath,
+				"arr_path":           d.ArrPath,
+				"instance_name":      d.InstanceName,
+				"root_folders":       d.RootFolders,
+				"suggested_arr_path": d.SuggestedArrPath,
+				"reason":             mappingDriftReason(d),
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish MappingDrift for %s: %v", d.LocalPath, err)
+			delete(current, d.PathID) // try again next time
+		}
+	}
+	h.reportedDrift = current
+}
+
+// mappingDriftReason describes a drift for notifications.
+func mappingDriftReason(d MappingDrift) string {
+	reason := fmt.Sprintf("%s maps to %s, which is not a root folder of %s", d.LocalPath, d.ArrPath, d.InstanceName)
+	if d.SuggestedArrPath != "" {
+		reason += fmt.Sprintf(" - it may have moved to %s", d.SuggestedArrPath)
+	}
+	return reason
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// seedDriftPaths creates two instances and their scan paths: Sonarr's TV
+// library still maps to /tv although Sonarr moved it to /data/tv.
+func seedDriftPaths(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	stmts := []string{
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (1, 'Sonarr', 'sonarr', 'http://sonarr', 'k')`,
+		`INSERT INTO arr_instances (id, name, type, url, api_key) VALUES (2, 'Radarr', 'radarr', 'http://radarr', 'k')`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/tv', '/tv', 1)`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (2, '/media/anime', '/data/anime', 1)`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (3, '/media/movies', '/movies', 2)`,
+		`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id, enabled) VALUES (4, '/media/old', '/old', 1, 0)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := database.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+	return database
+}
+
+func driftArrClient() *testutil.MockArrClient {
+	return &testutil.MockArrClient{
+		GetRootFoldersFunc: func(instanceID int64) ([]integration.RootFolder, error) {
+			if instanceID == 1 {
+				return []integration.RootFolder{{Path: "/data/tv/"}, {Path: "/data/anime"}}, nil
+			}
+			return nil, errors.New("connection refused")
+		},
+	}
+}
+
+func TestDetectMappingDrift(t *testing.T) {
+	database := seedDriftPaths(t)
+
+	drifts, err := DetectMappingDrift(context.Background(), database, driftArrClient())
+	if err != nil {
+		t.Fatalf("DetectMappingDrift() error = %v", err)
+	}
+	// Disabled paths and unreachable instances are not reported
+	if len(drifts) != 1 {
+		t.Fatalf("DetectMappingDrift() = %+v, want 1 drift", drifts)
+	}
+	d := drifts[0]
+	if d.PathID != 1 || d.ArrPath != "/tv" || d.InstanceName != "Sonarr" {
+		t.Errorf("drift = %+v, want scan path 1 (/tv) of Sonarr", d)
+	}
+	if d.SuggestedArrPath != "/data/tv" {
+		t.Errorf("SuggestedArrPath = %q, want /data/tv", d.SuggestedArrPath)
+	}
+}
+
+func TestSuggestRemap(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrPath string
+		free    []string
+		want    string
+	}{
+		{"same folder name", "/tv", []string{"/data/movies", "/data/TV"}, "/data/TV"},
+		{"only free root", "/series", []string{"/data/shows"}, "/data/shows"},
+		{"ambiguous", "/series", []string{"/a", "/b"}, ""},
+		{"no free root", "/series", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestRemap(tt.arrPath, tt.free); got != tt.want {
+				t.Errorf("suggestRemap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapScanPaths(t *testing.T) {
+	database := seedDriftPaths(t)
+	ctx := context.Background()
+	if _, err := database.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (5, '/media/tv/kids', '/tv/kids', 1)`); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+
+	remapped, err := RemapScanPaths(ctx, database, 1, "/tv/", "/data/tv", true)
+	if err != nil {
+		t.Fatalf("RemapScanPaths(dry run) error = %v", err)
+	}
+	if len(remapped) != 2 || remapped[1].NewArrPath != "/data/tv/kids" {
+		t.Fatalf("RemapScanPaths(dry run) = %+v, want /tv and /tv/kids remapped", remapped)
+	}
+	var arrPath string
+	_ = database.QueryRow(`SELECT arr_path FROM scan_paths WHERE id = 1`).Scan(&arrPath)
+	if arrPath != "/tv" {
+		t.Errorf("dry run changed arr_path to %q", arrPath)
+	}
+
+	if _, err := RemapScanPaths(ctx, database, 1, "/tv", "/data/tv", false); err != nil {
+		t.Fatalf("RemapScanPaths() error = %v", err)
+	}
+	for id, want := range map[int]string{1: "/data/tv", 5: "/data/tv/kids", 3: "/movies"} {
+		_ = database.QueryRow(`SELECT arr_path FROM scan_paths WHERE id = ?`, id).Scan(&arrPath)
+		if arrPath != want {
+			t.Errorf("scan path %d arr_path = %q, want %q", id, arrPath, want)
+		}
+	}
+
+	// A sibling sharing the prefix is not below it
+	if _, err := database.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (6, '/media/tvshows', '/data/tvshows', 1)`); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+	remapped, err = RemapScanPaths(ctx, database, 0, "/data/tv", "/tv", true)
+	if err != nil {
+		t.Fatalf("RemapScanPaths() error = %v", err)
+	}
+	if len(remapped) != 2 {
+		t.Errorf("RemapScanPaths() = %+v, want 2 paths", remapped)
+	}
+}
+
+func TestRemapScanPaths_Invalid(t *testing.T) {
+	database := seedDriftPaths(t)
+	for _, tc := range [][2]string{{"tv", "/data/tv"}, {"/tv", ""}, {"/tv", "/tv/"}} {
+		if _, err := RemapScanPaths(context.Background(), database, 0, tc[0], tc[1], true); !errors.Is(err, ErrInvalidRemap) {
+			t.Errorf("RemapScanPaths(%q, %q) error = %v, want ErrInvalidRemap", tc[0], tc[1], err)
+		}
+	}
+}
+
+func TestHealthMonitorService_checkMappingDrift(t *testing.T) {
+	database := seedDriftPaths(t)
+	eb := eventbus.NewEventBus(database)
+	defer eb.Shutdown()
+
+	published := make(chan domain.Event, 10)
+	eb.Subscribe(domain.MappingDrift, func(e domain.Event) { published <- e })
+
+	h := NewHealthMonitorService(database, eb, driftArrClient(), 24*time.Hour)
+	h.checkMappingDrift()
+	h.checkMappingDrift() // reported once per drift
+
+	select {
+	case e := <-published:
+		if e.AggregateID != "1" {
+			t.Errorf("AggregateID = %q, want 1", e.AggregateID)
+		}
+		if got, _ := e.GetString("suggested_arr_path"); got != "/data/tv" {
+			t.Errorf("suggested_arr_path = %q, want /data/tv", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("MappingDrift was not published")
+	}
+	select {
+	case e := <-published:
+		t.Errorf("MappingDrift published again: %+v", e)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// mappingOverrideQueryTimeout bounds mapping override lookups.
+const mappingOverrideQueryTimeout = 5 * time.Second
+
+var (
+	// ErrEmptyMappingOverride is returned for an override without an *arr path or media ID.
+	ErrEmptyMappingOverride = errors.New("arr_path or media_id is required")
+	// ErrInvalidArrPath is returned for an *arr path that isn't absolute.
+	ErrInvalidArrPath = errors.New("arr_path must be an absolute path")
+	// ErrUnmappedPath is returned for a media ID alone when the file's path can't be mapped.
+	ErrUnmappedPath = errors.New("the file's path can't be mapped to an *arr path; arr_path is required")
+)
+
+// MappingOverride is the path and media the *arr instance knows a
+// corruption's file by, set when the path mappings get it wrong.
+type MappingOverride struct {
+	CorruptionID string `json:"corruption_id"`
+	FilePath     string `json:"file_path"`
+	ArrPath      string `json:"arr_path"`
+	MediaID      int64  `json:"media_id"` // 0 looks the media up by path
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// MappingOverrides stores per-corruption mapping overrides and applies them
+// to the path mapper and *arr client used by remediation. An override
+// applies to the file, so later corruptions of the same file use it too.
+type MappingOverrides struct {
+	db     *sql.DB
+	mapper integration.PathMapper
+}
+
+// NewMappingOverrides creates the mapping overrides on top of mapper.
+func NewMappingOverrides(db *sql.DB, mapper integration.PathMapper) *MappingOverrides {
+	return &MappingOverrides{db: db, mapper: mapper}
+}
+
+// Get returns the override of a corruption, or nil if it has none.
+func (m *MappingOverrides) Get(ctx context.Context, corruptionID string) (*MappingOverride, error) {
+	var o MappingOverride
+	err := m.db.QueryRowContext(ctx, `
+		SELECT corruption_id, file_path, arr_path, media_id, COALESCE(updated_at, '')
+		FROM corruption_mapping_overrides WHERE corruption_id = ?
+	`, corruptionID).Scan(&o.CorruptionID, &o.FilePath, &o.ArrPath, &o.MediaID, &o.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Set overrides the *arr path and/or media ID of the corruption's file at
+// filePath. Without an *arr path the mapped one is stored, so the media ID
+// alone needs a file the path mappings can map.
+func (m *MappingOverrides) Set(ctx context.Context, corruptionID, filePath, arrPath string, mediaID int64) (*MappingOverride, error) {
+	arrPath = strings.TrimSpace(arrPath)
+	if arrPath == "" && mediaID <= 0 {
+		return nil, ErrEmptyMappingOverride
+	}
+	if arrPath != "" && !strings.HasPrefix(arrPath, "/") && !isWindowsPath(arrPath) {
+		return nil, ErrInvalidArrPath
+	}
+	if arrPath == "" {
+		mapped, err := m.MappedPath(filePath)
+		if err != nil {
+			return nil, ErrUnmappedPath
+		}
+		arrPath = mapped
+	}
+	mediaID = max(mediaID, 0)
+
+	_, err := db.ExecWithRetry(m.db, `
+		INSERT INTO corruption_mapping_overrides (corruption_id, file_path, arr_path, media_id, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(corruption_id) DO UPDATE SET
+			file_path = excluded.file_path, arr_path = excluded.arr_path,
+			media_id = excluded.media_id, updated_at = excluded.updated_at
+	`, corruptionID, filePath, arrPath, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Mapping override for %s: %s -> %s (media ID %d)", corruptionID, filePath, arrPath, mediaID)
+	return m.Get(ctx, corruptionID)
+}
+
+// Clear removes the override of a corruption, so the path mappings apply again.
+func (m *MappingOverrides) Clear(corruptionID string) error {
+	_, err := db.ExecWithRetry(m.db, `DELETE FROM corruption_mapping_overrides WHERE corruption_id = ?`, corruptionID)
+	return err
+}
+
+// MappedPath maps filePath through the path mappings alone, ignoring overrides.
+func (m *MappingOverrides) MappedPath(filePath string) (string, error) {
+	return m.mapper.ToArrPath(filePath)
+}
+
+// isWindowsPath reports whether p is an absolute Windows path such as
+// C:\Movies or \\server\share, which *arr instances on Windows report.
+func isWindowsPath(p string) bool {
+	if strings.HasPrefix(p, `\\`) {
+		return true
+	}
+	return len(p) >= 3 && p[1] == ':' && (p[2] == '\\' || p[2] == '/')
+}
+
+// PathMapper returns the path mapper with the overrides applied.
+func (m *MappingOverrides) PathMapper() integration.PathMapper {
+	return &overridePathMapper{PathMapper: m.mapper, overrides: m}
+}
+
+// WrapArrClient returns client with the overridden media IDs applied.
+// Instances are still picked by *arr path, so other methods go straight to
+// client.
+func (m *MappingOverrides) WrapArrClient(client integration.ArrClient) integration.ArrClient {
+	return &overrideArrClient{ArrClient: client, overrides: m}
+}
+
+// lookup returns the first column of the newest override matching query,
+// and whether there is one. Failed lookups fall back to the path mappings.
+func (m *MappingOverrides) lookup(query string, args ...interface{}) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), mappingOverrideQueryTimeout)
+	defer cancel()
+
+	var value string
+	err := m.db.QueryRowContext(ctx, query+" ORDER BY updated_at DESC LIMIT 1", args...).Scan(&value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Debugf("Failed to look up mapping override: %v", err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+// sibling maps arrPath to the local folder of an overridden file in the
+// same *arr folder.
+func (m *MappingOverrides) sibling(arrPath string) (string, bool) {
+	dir, name := path.Split(arrPath)
+	if dir == "" || name == "" {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mappingOverrideQueryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT file_path, arr_path FROM corruption_mapping_overrides
+		WHERE instr(arr_path, ?) = 1
+		ORDER BY updated_at DESC
+	`, dir)
+	if err != nil {
+		logger.Debugf("Failed to look up mapping override: %v", err)
+		return "", false
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var filePath, overridden string
+		if err := rows.Scan(&filePath, &overridden); err != nil {
+			return "", false
+		}
+		if path.Dir(overridden)+"/" == dir {
+			return path.Join(path.Dir(filePath), name), true
+		}
+	}
+	return "", false
+}
+
+// overridePathMapper maps the files with an override to and from their
+// overridden *arr path, and everything else through the path mappings.
+type overridePathMapper struct {
+	integration.PathMapper
+	overrides *MappingOverrides
+}
+
+func (p *overridePathMapper) ToArrPath(localPath string) (string, error) {
+	if arrPath, ok := p.overrides.lookup(`SELECT arr_path FROM corruption_mapping_overrides WHERE file_path = ?`, localPath); ok {
+		return arrPath, nil
+	}
+	return p.PathMapper.ToArrPath(localPath)
+}
+
+// ToLocalPath maps an overridden *arr path back to its file. Other files in
+// the same *arr folder, such as the replacement the *arr instance imports,
+// map to the file's local folder.
+func (p *overridePathMapper) ToLocalPath(arrPath string) (string, error) {
+	if localPath, ok := p.overrides.lookup(`SELECT file_path FROM corruption_mapping_overrides WHERE arr_path = ?`, arrPath); ok {
+		return localPath, nil
+	}
+	if localPath, ok := p.overrides.sibling(arrPath); ok {
+		return localPath, nil
+	}
+	return p.PathMapper.ToLocalPath(arrPath)
+}
+
+// overrideArrClient returns the overridden media ID for an overridden *arr path.
+type overrideArrClient struct {
+	integration.ArrClient
+	overrides *MappingOverrides
+}
+
+func (c *overrideArrClient) FindMediaByPath(path string) (int64, error) {
+	if id, ok := c.overrides.lookup(`SELECT CAST(media_id AS TEXT) FROM corruption_mapping_overrides WHERE arr_path = ? AND media_id > 0`, path); ok {
+		if mediaID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			return mediaID, nil
+		}
+	}
+	return c.ArrClient.FindMediaByPath(path)
+}
+
+// GetCircuitBreakerStats passes through the wrapped client's circuit breaker
+// stats, so detection-only mode and the alerts keep working.
+func (c *overrideArrClient) GetCircuitBreakerStats() map[int64]integration.CircuitBreakerStats {
+	if source, ok := c.ArrClient.(CircuitBreakerSource); ok {
+		return source.GetCircuitBreakerStats()
+	}
+	return nil
+}
+
+// SetRateLimit passes a reloaded rate limit through to the wrapped client.
+func (c *overrideArrClient) SetRateLimit(rps float64, burst int) {
+	if client, ok := c.ArrClient.(interface{ SetRateLimit(float64, int) }); ok {
+		client.SetRateLimit(rps, burst)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestMappingOverrides(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (1, '/media/movies', '/movies')`); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+	mapper, err := integration.NewPathMapper(db)
+	if err != nil {
+		t.Fatalf("NewPathMapper() error = %v", err)
+	}
+	overrides := NewMappingOverrides(db, mapper)
+	ctx := context.Background()
+
+	if _, err := overrides.Set(ctx, "c1", "/media/movies/A/a.mkv", "", 0); err != ErrEmptyMappingOverride {
+		t.Errorf("Expected ErrEmptyMappingOverride, got %v", err)
+	}
+	if _, err := overrides.Set(ctx, "c1", "/media/movies/A/a.mkv", "movies/a.mkv", 0); err != ErrInvalidArrPath {
+		t.Errorf("Expected ErrInvalidArrPath, got %v", err)
+	}
+	if _, err := overrides.Set(ctx, "c2", "/mnt/odd/b.mkv", "", 7); err != ErrUnmappedPath {
+		t.Errorf("Expected ErrUnmappedPath, got %v", err)
+	}
+
+	// A media ID alone keeps the mapped path
+	o, err := overrides.Set(ctx, "c3", "/media/movies/C/c.mkv", "", 9)
+	if err != nil || o.ArrPath != "/movies/C/c.mkv" || o.MediaID != 9 {
+		t.Fatalf("Expected the mapped path with media ID 9, got %+v (err %v)", o, err)
+	}
+
+	o, err = overrides.Set(ctx, "c1", "/media/movies/A/a.mkv", "/data/films/A (2001)/a.mkv", 42)
+	if err != nil || o.ArrPath != "/data/films/A (2001)/a.mkv" || o.MediaID != 42 {
+		t.Fatalf("Set() = %+v, %v", o, err)
+	}
+	if got, _ := overrides.Get(ctx, "c1"); got == nil || got.FilePath != "/media/movies/A/a.mkv" {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	pm := overrides.PathMapper()
+	if got, err := pm.ToArrPath("/media/movies/A/a.mkv"); err != nil || got != "/data/films/A (2001)/a.mkv" {
+		t.Errorf("ToArrPath(overridden) = %q, %v", got, err)
+	}
+	if got, _ := pm.ToArrPath("/media/movies/B/b.mkv"); got != "/movies/B/b.mkv" {
+		t.Errorf("ToArrPath(other) = %q, want the mapped path", got)
+	}
+	if got, _ := pm.ToLocalPath("/data/films/A (2001)/a.mkv"); got != "/media/movies/A/a.mkv" {
+		t.Errorf("ToLocalPath(overridden) = %q", got)
+	}
+	// The replacement the *arr instance imports next to it
+	if got, _ := pm.ToLocalPath("/data/films/A (2001)/a.1080p.mkv"); got != "/media/movies/A/a.1080p.mkv" {
+		t.Errorf("ToLocalPath(sibling) = %q", got)
+	}
+	if _, err := pm.ToLocalPath("/data/films/A (2001)/extras/x.mkv"); err == nil {
+		t.Error("Expected a file in a subfolder not to map through the override")
+	}
+
+	arr := &testutil.MockArrClient{FindMediaByPathFunc: func(string) (int64, error) {
+		return 0, errors.New("no instance found for path")
+	}}
+	client := overrides.WrapArrClient(arr)
+	if id, err := client.FindMediaByPath("/data/films/A (2001)/a.mkv"); err != nil || id != 42 {
+		t.Errorf("FindMediaByPath(overridden) = %d, %v", id, err)
+	}
+	if _, err := client.FindMediaByPath("/movies/B/b.mkv"); err == nil {
+		t.Error("Expected other paths to reach the wrapped client")
+	}
+
+	if err := overrides.Clear("c1"); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if got, _ := pm.ToArrPath("/media/movies/A/a.mkv"); got != "/movies/A/a.mkv" {
+		t.Errorf("ToArrPath() after Clear = %q, want the mapped path", got)
+	}
+	if got, _ := overrides.Get(ctx, "c1"); got != nil {
+		t.Errorf("Get() after Clear = %+v, want nil", got)
+	}
+}
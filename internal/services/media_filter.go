@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/integration"
+)
+
+// bytesPerMB converts the per-path minimum file size to bytes.
+const bytesPerMB = 1 << 20
+
+// mediaFilter decides which files of a scan path are scanned and which are too
+// small to be what they claim to be, e.g. a 1080p movie of 50 MB.
+type mediaFilter struct {
+	// extensions replaces the built-in media extensions; nil uses them
+	extensions map[string]bool
+	// minSize is the smallest plausible file in bytes; 0 disables the check
+	minSize int64
+}
+
+// newMediaFilter builds the filter of a scan path from its stored settings.
+// Invalid extensions are dropped with the rest of the list kept.
+func newMediaFilter(extensions string, minFileSizeMB int64) mediaFilter {
+	f := mediaFilter{minSize: max(minFileSizeMB, 0) * bytesPerMB}
+	if normalized, _ := NormalizeMediaExtensions(extensions); normalized != "" {
+		f.extensions = make(map[string]bool)
+		for _, ext := range strings.Split(normalized, ",") {
+			f.extensions[ext] = true
+		}
+	}
+	return f
+}
+
+// includes reports whether a file with this name is scanned.
+func (f mediaFilter) includes(path string) bool {
+	if f.extensions == nil {
+		return isMediaFile(path)
+	}
+	return f.extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// checkSize flags a file smaller than the minimum as Undersized. Empty files
+// are left to the ZeroByte check.
+func (f mediaFilter) checkSize(size int64) (bool, *integration.HealthCheckError) {
+	if f.minSize <= 0 || size <= 0 || size >= f.minSize {
+		return true, nil
+	}
+	return false, &integration.HealthCheckError{
+		Type:    integration.ErrorTypeUndersized,
+		Message: fmt.Sprintf("File is %.1f MB, below the path's minimum of %d MB", float64(size)/bytesPerMB, f.minSize/bytesPerMB),
+	}
+}
+
+// NormalizeMediaExtensions cleans up a comma- or space-separated list of file
+// extensions: lower case, with a leading dot, without duplicates. An empty
+// result means the built-in media extensions. Entries that aren't a plain
+// extension (".mkv", "mp4") are rejected.
+func NormalizeMediaExtensions(raw string) (string, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';' || r == '\t' || r == '\n'
+	})
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(fields))
+	var invalid []string
+	for _, field := range fields {
+		ext := "." + strings.TrimPrefix(strings.ToLower(field), ".")
+		if !validExtension(ext) {
+			invalid = append(invalid, field)
+			continue
+		}
+		if !seen[ext] {
+			seen[ext] = true
+			normalized = append(normalized, ext)
+		}
+	}
+	var err error
+	if len(invalid) > 0 {
+		err = fmt.Errorf("invalid file extension(s): %s", strings.Join(invalid, ", "))
+	}
+	return strings.Join(normalized, ","), err
+}
+
+func validExtension(ext string) bool {
+	if len(ext) < 2 || len(ext) > 16 {
+		return false
+	}
+	for _, r := range ext[1:] {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+)
+
+func TestNormalizeMediaExtensions(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"mkv", ".mkv", false},
+		{".MKV, mp4;.mkv", ".mkv,.mp4", false},
+		{"mkv .m2ts\tavi", ".mkv,.m2ts,.avi", false},
+		{"mkv, *.mp4, .", ".mkv", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeMediaExtensions(tt.raw)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeMediaExtensions(%q) = %q, %v; want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMediaFilter_Includes(t *testing.T) {
+	builtIn := newMediaFilter("", 0)
+	if !builtIn.includes("/media/movie.mkv") || builtIn.includes("/media/notes.txt") {
+		t.Error("an empty extension list should use the built-in media extensions")
+	}
+
+	custom := newMediaFilter(".mkv,.txt", 0)
+	if !custom.includes("/media/Movie.MKV") || !custom.includes("/media/notes.txt") {
+		t.Error("custom extensions should be matched case-insensitively")
+	}
+	if custom.includes("/media/movie.mp4") {
+		t.Error("extensions outside a custom list should be skipped")
+	}
+}
+
+func TestMediaFilter_CheckSize(t *testing.T) {
+	f := newMediaFilter("", 200)
+
+	if ok, err := f.checkSize(150 * bytesPerMB); ok || err == nil || err.Type != integration.ErrorTypeUndersized {
+		t.Errorf("checkSize(150 MB) = %v, %v; want Undersized", ok, err)
+	}
+	for _, size := range []int64{0, 200 * bytesPerMB, 4 << 30} {
+		if ok, err := f.checkSize(size); !ok || err != nil {
+			t.Errorf("checkSize(%d) = %v, %v; want healthy", size, ok, err)
+		}
+	}
+	if ok, _ := newMediaFilter("", 0).checkSize(1); !ok {
+		t.Error("a minimum of 0 should disable the check")
+	}
+}
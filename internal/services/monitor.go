@@ -60,6 +60,14 @@ func (m *MonitorService) Start() {
 	// Terminal states from VerifierService - user-initiated actions that ended the flow
 	m.eventBus.Subscribe(domain.DownloadIgnored, m.handleNeedsAttention)
 	m.eventBus.Subscribe(domain.ManuallyRemoved, m.handleNeedsAttention)
+	m.eventBus.Subscribe(domain.BudgetApprovalRequired, m.handleNeedsAttention)
+	m.eventBus.Subscribe(domain.LowConfidenceDetection, m.handleNeedsAttention)
+	m.eventBus.Subscribe(domain.QualityRegression, m.handleNeedsAttention)
+	m.eventBus.Subscribe(domain.AudioTrackMissing, m.handleNeedsAttention)
+	m.eventBus.Subscribe(domain.DeletionPlanChanged, m.handleNeedsAttention)
+
+	// Re-searches scheduled before a restart lost their timers
+	m.restoreResearch()
 }
 
 // Stop gracefully shuts down the MonitorService.
@@ -98,6 +106,20 @@ func (m *MonitorService) Stop() {
 func (m *MonitorService) handleFailure(event domain.Event) {
 	corruptionID := event.AggregateID
 
+	// A deletion that failed on permissions or setup fails the same way until
+	// someone fixes it, so it waits for a manual retry
+	if event.EventType == domain.DeletionFailed && !event.GetBoolOr("retryable", true) {
+		cause, _ := event.GetString("cause")
+		logger.Warnf("Not retrying deletion for %s automatically: %s needs fixing first", corruptionID, cause)
+		return
+	}
+
+	// A failed scheduled re-search doesn't use up retries, it waits for the
+	// next one
+	if m.researchAttemptFailed(event) {
+		return
+	}
+
 	// Get retry count and max limit
 	retryCount, maxRetries, err := m.getRetryCount(corruptionID)
 	if err != nil {
@@ -144,10 +166,30 @@ func (m *MonitorService) handleFailure(event domain.Event) {
 	// Exponential backoff: 15m, 30m, 60m
 	delay := time.Duration(math.Pow(2, float64(retryCount))) * 15 * time.Minute
 
+	m.scheduleTimer(corruptionID, delay, func() {
+		if err := m.eventBus.Publish(domain.Event{
+			AggregateID:   corruptionID,
+			AggregateType: "corruption",
+			EventType:     domain.RetryScheduled,
+			EventData: map[string]interface{}{
+				"file_path":      filePath,
+				"path_id":        pathID,
+				"auto_remediate": true, // Retries should always auto-remediate
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish RetryScheduled event for %s: %v", corruptionID, err)
+		}
+	})
+}
+
+// scheduleTimer runs fire after delay, replacing any timer pending for the
+// corruption. Nothing is scheduled once the service stopped, and fire is
+// skipped if it stops before the timer fires.
+func (m *MonitorService) scheduleTimer(corruptionID string, delay time.Duration, fire func()) {
 	// Check if we're shutting down before scheduling
 	m.timerMu.Lock()
+	defer m.timerMu.Unlock()
 	if m.stopped {
-		m.timerMu.Unlock()
 		logger.Debugf("MonitorService stopped, not scheduling retry for %s", corruptionID)
 		return
 	}
@@ -178,21 +220,9 @@ func (m *MonitorService) handleFailure(event domain.Event) {
 		default:
 		}
 
-		if err := m.eventBus.Publish(domain.Event{
-			AggregateID:   corruptionID,
-			AggregateType: "corruption",
-			EventType:     domain.RetryScheduled,
-			EventData: map[string]interface{}{
-				"file_path":      filePath,
-				"path_id":        pathID,
-				"auto_remediate": true, // Retries should always auto-remediate
-			},
-		}); err != nil {
-			logger.Errorf("Failed to publish RetryScheduled event for %s: %v", corruptionID, err)
-		}
+		fire()
 	})
 	m.pendingTimers[corruptionID] = timer
-	m.timerMu.Unlock()
 }
 
 // handleStuckRemediation handles items that have been stuck in progress for too long
@@ -304,7 +334,7 @@ func (m *MonitorService) getRetryCount(corruptionID string) (int, int, error) {
 		SELECT 
 			cs.retry_count,
 			sp.max_retries
-		FROM corruption_status cs
+		FROM corruption_summary cs
 		LEFT JOIN scan_paths sp ON sp.id = cs.path_id
 		WHERE cs.corruption_id = ?
 	`
@@ -349,6 +379,11 @@ func (m *MonitorService) handleNeedsAttention(event domain.Event) {
 
 	case domain.SearchExhausted:
 		reason, _ := event.GetString("reason")
+		if m.scheduleResearch(event) {
+			logger.Infof("No replacement found for %s: %s - searching again later (file: %s)",
+				corruptionID, reason, filePath)
+			return
+		}
 		logger.Warnf("Manual intervention required for %s: search exhausted - %s (file: %s)",
 			corruptionID, reason, filePath)
 
@@ -362,6 +397,37 @@ func (m *MonitorService) handleNeedsAttention(event domain.Event) {
 		logger.Infof("Item closed by user for %s: manually removed - %s (file: %s)",
 			corruptionID, reason, filePath)
 
+	case domain.BudgetApprovalRequired:
+		reason, _ := event.GetString("reason")
+		logger.Warnf("Approval required for %s: %s (file: %s)",
+			corruptionID, reason, filePath)
+
+	case domain.LowConfidenceDetection:
+		reason, _ := event.GetString("reason")
+		logger.Warnf("Confirmation required for %s: %s (file: %s)",
+			corruptionID, reason, filePath)
+
+	case domain.QualityRegression:
+		reason, _ := event.GetString("reason")
+		if event.GetBoolOr("keep_searching", false) {
+			// A VerificationFailed follows, which schedules the retry
+			logger.Infof("Quality regression for %s: %s - searching again (file: %s)",
+				corruptionID, reason, filePath)
+			return
+		}
+		logger.Warnf("Manual intervention required for %s: quality regression - %s (file: %s)",
+			corruptionID, reason, filePath)
+
+	case domain.AudioTrackMissing:
+		reason, _ := event.GetString("reason")
+		logger.Warnf("Manual intervention required for %s: replacement missing audio track - %s (file: %s)",
+			corruptionID, reason, filePath)
+
+	case domain.DeletionPlanChanged:
+		reason, _ := event.GetString("reason")
+		logger.Warnf("Manual intervention required for %s: deletion stopped - %s (file: %s)",
+			corruptionID, reason, filePath)
+
 	default:
 		logger.Warnf("Manual intervention required for %s: %s (file: %s)",
 			corruptionID, event.EventType, filePath)
@@ -10,6 +10,7 @@ import (
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/testutil"
 )
 
@@ -168,6 +169,50 @@ func TestMonitorService_SchedulesRetryWithExponentialBackoff(t *testing.T) {
 	mu.Unlock()
 }
 
+func TestMonitorService_HandleFailure_DeletionNotRetryable(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	testutil.SeedScanPath(db, 1, "/media/movies", "/movies", true, false)
+	testutil.SeedEvent(db, domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   "test-corruption-perm",
+		EventType:     domain.CorruptionDetected,
+		EventData: map[string]interface{}{
+			"file_path": "/movies/Test Movie/movie.mkv",
+			"path_id":   int64(1),
+		},
+	})
+
+	mockClock := testutil.NewMockClock()
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+
+	eb.Publish(domain.Event{
+		AggregateID:   "test-corruption-perm",
+		AggregateType: "corruption",
+		EventType:     domain.DeletionFailed,
+		EventData: map[string]interface{}{
+			"error":     "failed to delete file: 500 Internal Server Error",
+			"cause":     integration.DeletionCausePermissionDenied,
+			"retryable": false,
+		},
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	if got := mockClock.PendingCount(); got != 0 {
+		t.Errorf("Expected no retry for a permission problem, got %d pending timers", got)
+	}
+}
+
 func TestMonitorService_ExponentialBackoffDelays(t *testing.T) {
 	config.SetForTesting(config.NewTestConfig())
 
@@ -1758,7 +1803,7 @@ func TestMonitorService_HandleFailure_TransientDBError_EventuallyFails(t *testin
 
 	monitor.Start()
 
-	// Create a corruption_status entry by inserting into corruption_summary
+	// Create a corruption_summary entry directly
 	// The view also needs events, but we'll close the DB before the context lookup
 	_, err = db.Exec(`INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at)
 		VALUES (?, '/media/test.mkv', 1, 'CorruptionDetected', datetime('now'), datetime('now'))`, corruptionID)
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// pathGroupQueryTimeout bounds scan path group queries.
+const pathGroupQueryTimeout = 5 * time.Second
+
+var (
+	// ErrPathGroupNotFound is returned when a path group doesn't exist.
+	ErrPathGroupNotFound = errors.New("path group not found")
+	// ErrPathGroupExists is returned when another path group has the same name.
+	ErrPathGroupExists = errors.New("a path group with this name already exists")
+	// ErrInvalidPathGroup is returned for a path group without a name.
+	ErrInvalidPathGroup = errors.New("path group needs a name")
+	// ErrPathGroupUnknownPath is returned when a group is given a scan path that doesn't exist.
+	ErrPathGroupUnknownPath = errors.New("scan path not found")
+)
+
+// PathGroup is a named group of scan paths, e.g. "Movies" or "Kids", for
+// actions on all of them at once. A scan path is in at most one group.
+type PathGroup struct {
+	ID                int64   `json:"id"`
+	Name              string  `json:"name"`
+	RemediationPaused bool    `json:"remediation_paused"` // remediations of its paths wait until resumed
+	PathIDs           []int64 `json:"path_ids"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+// PathGroupService manages scan path groups.
+type PathGroupService struct {
+	db *sql.DB
+
+	mu      sync.Mutex
+	changed chan struct{} // closed when a group's pause or members change
+}
+
+// NewPathGroupService creates a new path group service.
+func NewPathGroupService(db *sql.DB) *PathGroupService {
+	return &PathGroupService{db: db, changed: make(chan struct{})}
+}
+
+// List returns all path groups with their scan paths, sorted by name.
+func (g *PathGroupService) List() ([]PathGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pathGroupQueryTimeout)
+	defer cancel()
+
+	rows, err := g.db.QueryContext(ctx, `
+		SELECT id, name, remediation_paused, created_at, updated_at
+		FROM scan_path_groups ORDER BY name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]PathGroup, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var pg PathGroup
+		if err := rows.Scan(&pg.ID, &pg.Name, &pg.RemediationPaused, &pg.CreatedAt, &pg.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		pg.PathIDs = []int64{}
+		index[pg.ID] = len(groups)
+		groups = append(groups, pg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = g.db.QueryContext(ctx, `
+		SELECT id, group_id FROM scan_paths
+		WHERE group_id IS NOT NULL AND deleted_at IS NULL ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pathID, groupID int64
+		if err := rows.Scan(&pathID, &groupID); err != nil {
+			return nil, err
+		}
+		if i, ok := index[groupID]; ok {
+			groups[i].PathIDs = append(groups[i].PathIDs, pathID)
+		}
+	}
+	return groups, rows.Err()
+}
+
+// Get returns a path group with its scan paths.
+func (g *PathGroupService) Get(id int64) (*PathGroup, error) {
+	groups, err := g.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].ID == id {
+			return &groups[i], nil
+		}
+	}
+	return nil, ErrPathGroupNotFound
+}
+
+// Create stores a new path group, moving the given scan paths into it, and
+// returns its ID.
+func (g *PathGroupService) Create(pg *PathGroup) (int64, error) {
+	if err := g.validate(pg, 0); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := db.TxWithRetry(g.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`INSERT INTO scan_path_groups (name, remediation_paused) VALUES (?, ?)`, pg.Name, pg.RemediationPaused)
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		return setGroupPaths(tx, id, pg.PathIDs)
+	})
+	if err != nil {
+		return 0, err
+	}
+	g.notifyChanged()
+	return id, nil
+}
+
+// Update replaces the name, pause and scan paths of a path group. Scan paths
+// in another group move to this one.
+func (g *PathGroupService) Update(id int64, pg *PathGroup) error {
+	if err := g.validate(pg, id); err != nil {
+		return err
+	}
+	err := db.TxWithRetry(g.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE scan_path_groups SET name = ?, remediation_paused = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, pg.Name, pg.RemediationPaused, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrPathGroupNotFound
+		}
+		return setGroupPaths(tx, id, pg.PathIDs)
+	})
+	if err != nil {
+		return err
+	}
+	g.notifyChanged()
+	return nil
+}
+
+// Delete removes a path group. Its scan paths stay, without a group.
+func (g *PathGroupService) Delete(id int64) error {
+	err := db.TxWithRetry(g.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`UPDATE scan_paths SET group_id = NULL WHERE group_id = ?`, id); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`DELETE FROM scan_path_groups WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrPathGroupNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	g.notifyChanged()
+	return nil
+}
+
+// SetRemediationPaused pauses or resumes the remediation of a group's scan
+// paths. While paused, corruptions found in them wait in the queue; scans
+// keep running.
+func (g *PathGroupService) SetRemediationPaused(id int64, paused bool) error {
+	result, err := db.ExecWithRetry(g.db, `
+		UPDATE scan_path_groups SET remediation_paused = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+	`, paused, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrPathGroupNotFound
+	}
+	g.notifyChanged()
+	return nil
+}
+
+// remediationPaused reports whether the remediation of the scan path's group
+// is paused, with the group and a channel that is closed when any group
+// changes. A nil *PathGroupService never pauses.
+func (g *PathGroupService) remediationPaused(pathID int64) (PathGroup, bool, <-chan struct{}) {
+	if g == nil || pathID == 0 {
+		return PathGroup{}, false, nil
+	}
+	g.mu.Lock()
+	changed := g.changed
+	g.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), pathGroupQueryTimeout)
+	defer cancel()
+	var pg PathGroup
+	err := g.db.QueryRowContext(ctx, `
+		SELECT pg.id, pg.name, pg.remediation_paused FROM scan_paths p
+		JOIN scan_path_groups pg ON pg.id = p.group_id
+		WHERE p.id = ?
+	`, pathID).Scan(&pg.ID, &pg.Name, &pg.RemediationPaused)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Debugf("Failed to look up the group of scan path %d: %v", pathID, err)
+		}
+		return PathGroup{}, false, nil
+	}
+	return pg, pg.RemediationPaused, changed
+}
+
+// notifyChanged wakes remediations held by a group pause to check again.
+func (g *PathGroupService) notifyChanged() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// validate trims the name and checks it is set and not taken by another group.
+func (g *PathGroupService) validate(pg *PathGroup, exceptID int64) error {
+	pg.Name = strings.TrimSpace(pg.Name)
+	if pg.Name == "" {
+		return ErrInvalidPathGroup
+	}
+	if pg.PathIDs == nil {
+		pg.PathIDs = []int64{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pathGroupQueryTimeout)
+	defer cancel()
+	var id int64
+	err := g.db.QueryRowContext(ctx, `SELECT id FROM scan_path_groups WHERE name = ? COLLATE NOCASE AND id != ?`, pg.Name, exceptID).Scan(&id)
+	if err == nil {
+		return ErrPathGroupExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// setGroupPaths makes pathIDs the scan paths of a group.
+func setGroupPaths(tx *sql.Tx, groupID int64, pathIDs []int64) error {
+	if _, err := tx.Exec(`UPDATE scan_paths SET group_id = NULL WHERE group_id = ?`, groupID); err != nil {
+		return err
+	}
+	for _, pathID := range pathIDs {
+		result, err := tx.Exec(`UPDATE scan_paths SET group_id = ? WHERE id = ? AND deleted_at IS NULL`, groupID, pathID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %d", ErrPathGroupUnknownPath, pathID)
+		}
+	}
+	return nil
+}
+
+// SetPathGroups holds remediations of scan paths whose group has remediation
+// paused until it is resumed.
+func (r *RemediatorService) SetPathGroups(g *PathGroupService) {
+	r.groups = g
+}
+
+// waitForGroup blocks while remediation of the corruption's path group is
+// paused. Returns false if the remediator shut down while waiting.
+func (r *RemediatorService) waitForGroup(corruptionID, filePath string, pathID int64) bool {
+	announced := false
+	for {
+		group, paused, changed := r.groups.remediationPaused(pathID)
+		if !paused {
+			return true
+		}
+		if !announced {
+			announced = true
+			r.publishGroupDeferred(corruptionID, filePath, pathID, group)
+		}
+		select {
+		case <-changed:
+		case <-r.shutdownCh:
+			return false
+		}
+	}
+}
+
+func (r *RemediatorService) publishGroupDeferred(corruptionID, filePath string, pathID int64, group PathGroup) {
+	reason := fmt.Sprintf("remediation of path group %q is paused", group.Name)
+	logger.Infof("Remediation of %s deferred: %s", filePath, reason)
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.RemediationDeferred,
+		EventData: map[string]interface{}{
+			"file_path": filePath,
+			"path_id":   pathID,
+			"group_id":  group.ID,
+			"reason":    reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish RemediationDeferred event: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func newTestPathGroups(t *testing.T) (*sql.DB, *PathGroupService) {
+	t.Helper()
+	sqlDB, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := sqlDB.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path) VALUES
+			(1, '/media/movies', '/movies'),
+			(2, '/media/tv', '/tv'),
+			(3, '/media/kids', '/kids')
+	`); err != nil {
+		t.Fatalf("Failed to seed scan paths: %v", err)
+	}
+	return sqlDB, NewPathGroupService(sqlDB)
+}
+
+func TestPathGroupService_CRUD(t *testing.T) {
+	_, g := newTestPathGroups(t)
+
+	id, err := g.Create(&PathGroup{Name: " Films ", PathIDs: []int64{1, 2}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	group, err := g.Get(id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if group.Name != "Films" || len(group.PathIDs) != 2 || group.RemediationPaused {
+		t.Errorf("Unexpected group: %+v", group)
+	}
+
+	if _, err := g.Create(&PathGroup{Name: "films"}); !errors.Is(err, ErrPathGroupExists) {
+		t.Errorf("Expected ErrPathGroupExists, got %v", err)
+	}
+	if _, err := g.Create(&PathGroup{Name: "  "}); !errors.Is(err, ErrInvalidPathGroup) {
+		t.Errorf("Expected ErrInvalidPathGroup, got %v", err)
+	}
+	if _, err := g.Create(&PathGroup{Name: "Other", PathIDs: []int64{99}}); !errors.Is(err, ErrPathGroupUnknownPath) {
+		t.Errorf("Expected ErrPathGroupUnknownPath, got %v", err)
+	}
+
+	// Moving a path into another group takes it out of the first
+	kids, err := g.Create(&PathGroup{Name: "Kids", PathIDs: []int64{2, 3}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if group, _ = g.Get(id); len(group.PathIDs) != 1 || group.PathIDs[0] != 1 {
+		t.Errorf("Expected Films to keep only path 1, got %v", group.PathIDs)
+	}
+
+	if err := g.Update(kids, &PathGroup{Name: "Children", PathIDs: []int64{3}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := g.Update(99, &PathGroup{Name: "Missing"}); !errors.Is(err, ErrPathGroupNotFound) {
+		t.Errorf("Expected ErrPathGroupNotFound, got %v", err)
+	}
+
+	groups, err := g.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(groups) != 2 || groups[0].Name != "Children" || groups[1].Name != "Films" {
+		t.Errorf("Expected groups sorted by name, got %+v", groups)
+	}
+
+	if err := g.Delete(id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := g.Delete(id); !errors.Is(err, ErrPathGroupNotFound) {
+		t.Errorf("Expected ErrPathGroupNotFound, got %v", err)
+	}
+	if _, paused, _ := g.remediationPaused(1); paused {
+		t.Error("A path without a group should not be paused")
+	}
+}
+
+func TestRemediator_WaitForGroup(t *testing.T) {
+	db, g := newTestPathGroups(t)
+	id, err := g.Create(&PathGroup{Name: "Kids", PathIDs: []int64{3}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := g.SetRemediationPaused(id, true); err != nil {
+		t.Fatalf("SetRemediationPaused() error = %v", err)
+	}
+
+	bus := testutil.NewMockEventBus()
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.SetPathGroups(g)
+	defer r.Stop()
+
+	if !r.waitForGroup("a", "/media/movies/a.mkv", 1) {
+		t.Fatal("Paths outside the paused group should go ahead")
+	}
+
+	done := make(chan bool, 1)
+	go func() { done <- r.waitForGroup("b", "/media/kids/b.mkv", 3) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(bus.GetEvents(domain.RemediationDeferred)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	deferred := bus.GetEvents(domain.RemediationDeferred)
+	if len(deferred) != 1 || deferred[0].EventData["group_id"] != id {
+		t.Fatalf("Unexpected RemediationDeferred events: %+v", deferred)
+	}
+
+	if err := g.SetRemediationPaused(id, false); err != nil {
+		t.Fatalf("SetRemediationPaused() error = %v", err)
+	}
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Error("Expected the held remediation to go ahead after resume")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Resuming the group should wake the held remediation")
+	}
+}
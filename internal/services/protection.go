@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// protectionQueryTimeout bounds protected item lookups.
+const protectionQueryTimeout = 5 * time.Second
+
+// ErrProtectedItemNotFound is returned when a protected item doesn't exist.
+var ErrProtectedItemNotFound = errors.New("protected item not found")
+
+// ErrInvalidProtectedItem is returned when a protected item names neither a
+// file nor a media item.
+var ErrInvalidProtectedItem = errors.New("protected item needs a file_path or an arr_instance_id and media_id")
+
+// ProtectedItem is a file or a whole movie/series that must never be
+// remediated automatically. Either FilePath or ArrInstanceID and MediaID are set.
+type ProtectedItem struct {
+	ID            int64  `json:"id"`
+	FilePath      string `json:"file_path,omitempty"`
+	ArrInstanceID int64  `json:"arr_instance_id,omitempty"`
+	MediaID       int64  `json:"media_id,omitempty"`
+	Note          string `json:"note"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// ProtectionService manages protected files and media. Protected items are
+// still scanned and reported, but the remediator refuses to touch them.
+type ProtectionService struct {
+	db *sql.DB
+}
+
+// NewProtectionService creates a new protection service.
+func NewProtectionService(db *sql.DB) *ProtectionService {
+	return &ProtectionService{db: db}
+}
+
+// Protect stores a protected item and returns its ID. Protecting something
+// that is already protected returns the existing ID.
+func (p *ProtectionService) Protect(item *ProtectedItem) (int64, error) {
+	item.FilePath = strings.TrimSpace(item.FilePath)
+	if item.FilePath != "" {
+		item.FilePath = filepath.Clean(item.FilePath)
+		item.ArrInstanceID, item.MediaID = 0, 0
+	} else if item.ArrInstanceID <= 0 || item.MediaID <= 0 {
+		return 0, ErrInvalidProtectedItem
+	}
+
+	if id, err := p.find(item); err == nil {
+		return id, nil
+	} else if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	var filePath, instanceID, mediaID interface{}
+	if item.FilePath != "" {
+		filePath = item.FilePath
+	} else {
+		instanceID, mediaID = item.ArrInstanceID, item.MediaID
+	}
+	result, err := db.ExecWithRetry(p.db, `
+		INSERT INTO protected_items (file_path, arr_instance_id, media_id, note) VALUES (?, ?, ?, ?)
+	`, filePath, instanceID, mediaID, item.Note)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// find returns the ID of an existing protection for the same file or media item.
+func (p *ProtectionService) find(item *ProtectedItem) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), protectionQueryTimeout)
+	defer cancel()
+
+	var id int64
+	var err error
+	if item.FilePath != "" {
+		err = p.db.QueryRowContext(ctx, `SELECT id FROM protected_items WHERE file_path = ?`, item.FilePath).Scan(&id)
+	} else {
+		err = p.db.QueryRowContext(ctx, `
+			SELECT id FROM protected_items WHERE file_path IS NULL AND arr_instance_id = ? AND media_id = ?
+		`, item.ArrInstanceID, item.MediaID).Scan(&id)
+	}
+	return id, err
+}
+
+// List returns all protected items, newest first.
+func (p *ProtectionService) List() ([]ProtectedItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), protectionQueryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, file_path, arr_instance_id, media_id, note, created_at
+		FROM protected_items ORDER BY id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]ProtectedItem, 0)
+	for rows.Next() {
+		var item ProtectedItem
+		var filePath sql.NullString
+		var instanceID, mediaID sql.NullInt64
+		if err := rows.Scan(&item.ID, &filePath, &instanceID, &mediaID, &item.Note, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		item.FilePath = filePath.String
+		item.ArrInstanceID = instanceID.Int64
+		item.MediaID = mediaID.Int64
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+// Delete removes a protection so the file or media item can be remediated again.
+func (p *ProtectionService) Delete(id int64) error {
+	result, err := db.ExecWithRetry(p.db, `DELETE FROM protected_items WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrProtectedItemNotFound
+	}
+	return nil
+}
+
+// IsFileProtected reports whether a file is protected. Lookup errors count as
+// protected: refusing a remediation is always the safe choice.
+func (p *ProtectionService) IsFileProtected(filePath string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), protectionQueryTimeout)
+	defer cancel()
+
+	var exists bool
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM protected_items WHERE file_path = ?)
+	`, filepath.Clean(filePath)).Scan(&exists); err != nil {
+		logger.Errorf("Failed to check protection of %s, treating as protected: %v", filePath, err)
+		return true
+	}
+	return exists
+}
+
+// HasMediaProtections reports whether any media item of an *arr instance is
+// protected, so callers can skip the *arr lookup for a media ID when none are.
+func (p *ProtectionService) HasMediaProtections(instanceID int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), protectionQueryTimeout)
+	defer cancel()
+
+	var exists bool
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM protected_items WHERE file_path IS NULL AND arr_instance_id = ?)
+	`, instanceID).Scan(&exists); err != nil {
+		logger.Errorf("Failed to check media protections of instance %d, treating as protected: %v", instanceID, err)
+		return true
+	}
+	return exists
+}
+
+// IsMediaProtected reports whether a movie or series of an *arr instance is
+// protected. Lookup errors count as protected.
+func (p *ProtectionService) IsMediaProtected(instanceID, mediaID int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), protectionQueryTimeout)
+	defer cancel()
+
+	var exists bool
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM protected_items WHERE file_path IS NULL AND arr_instance_id = ? AND media_id = ?)
+	`, instanceID, mediaID).Scan(&exists); err != nil {
+		logger.Errorf("Failed to check protection of media %d on instance %d, treating as protected: %v", mediaID, instanceID, err)
+		return true
+	}
+	return exists
+}
+
+// ProtectedCorruptions returns which of the given corruptions concern a
+// protected file or media item. Media are matched through the media_id
+// recorded in the corruption's events and the *arr instance of its scan path.
+func (p *ProtectionService) ProtectedCorruptions(ctx context.Context, corruptionIDs []string) (map[string]bool, error) {
+	protected := make(map[string]bool)
+	if len(corruptionIDs) == 0 {
+		return protected, nil
+	}
+
+	placeholders := make([]string, len(corruptionIDs))
+	args := make([]interface{}, len(corruptionIDs))
+	for i, id := range corruptionIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	// Security: only ? placeholders are concatenated, the IDs are passed as args
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT d.aggregate_id
+		FROM events d
+		WHERE d.event_type = 'CorruptionDetected'
+		AND d.aggregate_id IN (`+strings.Join(placeholders, ",")+`)
+		AND (
+			json_extract(d.event_data, '$.file_path') IN (SELECT file_path FROM protected_items WHERE file_path IS NOT NULL)
+			OR EXISTS (
+				SELECT 1 FROM protected_items pi
+				JOIN scan_paths sp ON sp.arr_instance_id = pi.arr_instance_id
+				JOIN events e ON e.aggregate_id = d.aggregate_id
+				WHERE pi.file_path IS NULL
+				AND sp.id = json_extract(d.event_data, '$.path_id')
+				AND json_extract(e.event_data, '$.media_id') = pi.media_id
+			)
+		)
+	`, args...) // NOSONAR - parameterized query
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		protected[id] = true
+	}
+	return protected, rows.Err()
+}
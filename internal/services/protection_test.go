@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestProtectionService_ProtectListDelete(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	p := NewProtectionService(db)
+
+	fileID, err := p.Protect(&ProtectedItem{FilePath: "/media/home/wedding.mkv", Note: "irreplaceable"})
+	if err != nil {
+		t.Fatalf("Protect file failed: %v", err)
+	}
+	mediaID, err := p.Protect(&ProtectedItem{ArrInstanceID: 1, MediaID: 42})
+	if err != nil {
+		t.Fatalf("Protect media failed: %v", err)
+	}
+
+	// Protecting again returns the existing item
+	if again, err := p.Protect(&ProtectedItem{FilePath: "/media/home/../home/wedding.mkv"}); err != nil || again != fileID {
+		t.Errorf("Expected existing ID %d, got %d (%v)", fileID, again, err)
+	}
+	if again, err := p.Protect(&ProtectedItem{ArrInstanceID: 1, MediaID: 42}); err != nil || again != mediaID {
+		t.Errorf("Expected existing ID %d, got %d (%v)", mediaID, again, err)
+	}
+
+	if _, err := p.Protect(&ProtectedItem{ArrInstanceID: 1}); err != ErrInvalidProtectedItem {
+		t.Errorf("Expected ErrInvalidProtectedItem, got %v", err)
+	}
+
+	items, err := p.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 protected items, got %d", len(items))
+	}
+	if items[1].FilePath != "/media/home/wedding.mkv" || items[1].Note != "irreplaceable" {
+		t.Errorf("Unexpected file item: %+v", items[1])
+	}
+	if items[0].ArrInstanceID != 1 || items[0].MediaID != 42 {
+		t.Errorf("Unexpected media item: %+v", items[0])
+	}
+
+	if !p.IsFileProtected("/media/home/wedding.mkv") {
+		t.Error("Expected file to be protected")
+	}
+	if p.IsFileProtected("/media/home/other.mkv") {
+		t.Error("Expected other file not to be protected")
+	}
+	if !p.HasMediaProtections(1) || p.HasMediaProtections(2) {
+		t.Error("Expected media protections only on instance 1")
+	}
+	if !p.IsMediaProtected(1, 42) || p.IsMediaProtected(1, 43) || p.IsMediaProtected(2, 42) {
+		t.Error("Expected only media 42 on instance 1 to be protected")
+	}
+
+	if err := p.Delete(fileID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if p.IsFileProtected("/media/home/wedding.mkv") {
+		t.Error("Expected file protection to be removed")
+	}
+	if err := p.Delete(fileID); err != ErrProtectedItemNotFound {
+		t.Errorf("Expected ErrProtectedItemNotFound, got %v", err)
+	}
+}
+
+func TestProtectionService_ProtectedCorruptions(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media/movies', '/movies', 7);
+		INSERT INTO events (aggregate_type, aggregate_id, event_type, event_data) VALUES
+			('corruption', 'file', 'CorruptionDetected', '{"file_path":"/media/movies/A/a.mkv","path_id":1}'),
+			('corruption', 'media', 'CorruptionDetected', '{"file_path":"/media/movies/B/b.mkv","path_id":1}'),
+			('corruption', 'media', 'DeletionStarted', '{"media_id":42}'),
+			('corruption', 'other', 'CorruptionDetected', '{"file_path":"/media/movies/C/c.mkv","path_id":1}'),
+			('corruption', 'other', 'DeletionStarted', '{"media_id":43}');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	p := NewProtectionService(db)
+	if _, err := p.Protect(&ProtectedItem{FilePath: "/media/movies/A/a.mkv"}); err != nil {
+		t.Fatalf("Protect file failed: %v", err)
+	}
+	if _, err := p.Protect(&ProtectedItem{ArrInstanceID: 7, MediaID: 42}); err != nil {
+		t.Fatalf("Protect media failed: %v", err)
+	}
+
+	protected, err := p.ProtectedCorruptions(context.Background(), []string{"file", "media", "other", "missing"})
+	if err != nil {
+		t.Fatalf("ProtectedCorruptions failed: %v", err)
+	}
+	if !protected["file"] || !protected["media"] || protected["other"] || protected["missing"] {
+		t.Errorf("Unexpected protected corruptions: %v", protected)
+	}
+}
+
+func TestRemediatorService_ProtectedItemsAreNotRemediated(t *testing.T) {
+	tests := []struct {
+		name    string
+		protect ProtectedItem
+	}{
+		{"protected_file", ProtectedItem{FilePath: testutil.TestFilePaths.Corrupt}},
+		{"protected_media", ProtectedItem{ArrInstanceID: 3, MediaID: 123}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := testutil.NewTestDB()
+			if err != nil {
+				t.Fatalf("Failed to create test DB: %v", err)
+			}
+			defer db.Close()
+
+			if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, arr_instance_id) VALUES (1, '/media', '/media', 3)`); err != nil {
+				t.Fatalf("Failed to insert scan path: %v", err)
+			}
+			protect := tt.protect
+			if _, err := NewProtectionService(db).Protect(&protect); err != nil {
+				t.Fatalf("Protect failed: %v", err)
+			}
+
+			mockEventBus := testutil.NewMockEventBus()
+			mockArrClient := &testutil.MockArrClient{
+				FindMediaByPathFunc: func(path string) (int64, error) { return 123, nil },
+			}
+			remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, db)
+
+			event := testutil.NewCorruptionEventWithType(
+				testutil.TestFilePaths.Corrupt,
+				integration.ErrorTypeCorruptHeader,
+				testutil.WithAutoRemediate(true),
+				testutil.WithPathID(1),
+			)
+			remediator.handleCorruptionDetected(event)
+			time.Sleep(100 * time.Millisecond)
+
+			if mockArrClient.CallCount("DeleteFile") != 0 {
+				t.Error("DeleteFile must not be called for a protected item")
+			}
+			if n := len(mockEventBus.GetAllEvents()); n != 0 {
+				t.Errorf("Expected no events for a protected item, got %d", n)
+			}
+		})
+	}
+}
+
+func TestRemediatorService_ProtectionAddedWhileQueued(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	mockEventBus := testutil.NewMockEventBus()
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 123, nil },
+	}
+	remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, db)
+
+	if _, err := remediator.protection.Protect(&ProtectedItem{FilePath: testutil.TestFilePaths.Corrupt}); err != nil {
+		t.Fatalf("Protect failed: %v", err)
+	}
+	remediator.executeRemediation("corruption-1", testutil.TestFilePaths.Corrupt, testutil.TestFilePaths.Corrupt, 0)
+
+	if mockArrClient.CallCount("DeleteFile") != 0 {
+		t.Error("DeleteFile must not be called for a protected file")
+	}
+	if mockEventBus.EventCount(domain.DeletionStarted) != 0 {
+		t.Error("DeletionStarted must not be published for a protected file")
+	}
+}
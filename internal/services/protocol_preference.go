@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Download protocol preferences, set per scan path.
+const (
+	ProtocolAny     = "any"     // Replacements may come from usenet or torrents
+	ProtocolUsenet  = "usenet"  // Torrent grabs are blocklisted
+	ProtocolTorrent = "torrent" // Usenet grabs are blocklisted
+)
+
+// ErrInvalidProtocolPreference is returned for an unknown protocol preference.
+var ErrInvalidProtocolPreference = errors.New("protocol_preference must be one of: any, usenet, torrent")
+
+// ValidProtocolPreference reports whether pref is a known protocol preference.
+func ValidProtocolPreference(pref string) bool {
+	return pref == ProtocolAny || pref == ProtocolUsenet || pref == ProtocolTorrent
+}
+
+// getProtocolPreference returns the protocol preference of a scan path, any
+// if it can't be loaded.
+func (v *VerifierService) getProtocolPreference(pathID int64) string {
+	if pathID == 0 || v.db == nil {
+		return ProtocolAny
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifierQueryTimeout)
+	defer cancel()
+
+	var pref sql.NullString
+	if err := v.db.QueryRowContext(ctx, "SELECT protocol_preference FROM scan_paths WHERE id = ?", pathID).Scan(&pref); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Debugf("Failed to load protocol preference of path %d: %v", pathID, err)
+		}
+		return ProtocolAny
+	}
+	if !ValidProtocolPreference(pref.String) {
+		return ProtocolAny
+	}
+	return pref.String
+}
+
+// rejectProtocol removes and blocklists a grab of the protocol the scan path
+// doesn't want, which makes the *arr search for another release. Returns true
+// when the grab was rejected.
+func (v *VerifierService) rejectProtocol(state *monitorState, item integration.QueueItemInfo) bool {
+	if state.protocol == "" || state.protocol == ProtocolAny || item.Protocol == "" || item.Protocol == state.protocol {
+		return false
+	}
+
+	logger.Infof("Download %s for %s uses %s but the path only accepts %s, blocklisting it",
+		item.Title, state.corruptionID, item.Protocol, state.protocol)
+	if err := v.arrClient.RemoveFromQueueByPath(state.arrPath, item.ID, true, true); err != nil {
+		logger.Errorf("Failed to blocklist %s download %s: %v", item.Protocol, item.Title, err)
+		return false
+	}
+
+	if err := v.eventBus.Publish(domain.Event{
+		AggregateID:   state.corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.DownloadRejected,
+		EventData: map[string]interface{}{
+			"file_path":   state.filePath,
+			"error":       fmt.Sprintf("%s downloads are not allowed for this path", item.Protocol),
+			"reason":      "protocol",
+			"protocol":    item.Protocol,
+			"title":       item.Title,
+			"download_id": item.DownloadID,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish DownloadRejected event: %v", err)
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestVerifierService_RejectProtocol(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	testutil.SeedScanPath(db, 1, "/media/tv", "/tv", true, false)
+	if _, err := db.Exec(`UPDATE scan_paths SET protocol_preference = 'usenet' WHERE id = 1`); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+
+	var removed []int64
+	arrClient := &testutil.MockArrClient{
+		RemoveFromQueueByPathFunc: func(arrPath string, queueID int64, removeFromClient, blocklist bool) error {
+			if !removeFromClient || !blocklist {
+				t.Errorf("Expected removal from client with blocklist, got %v/%v", removeFromClient, blocklist)
+			}
+			removed = append(removed, queueID)
+			return nil
+		},
+	}
+	verifier := NewVerifierService(eb, nil, nil, arrClient, db)
+
+	rejected := make(chan domain.Event, 1)
+	eb.Subscribe(domain.DownloadRejected, func(e domain.Event) { rejected <- e })
+
+	state := &monitorState{corruptionID: "test-protocol", filePath: "/tv/Show/S01E01.mkv", protocol: verifier.getProtocolPreference(1)}
+	if state.protocol != ProtocolUsenet {
+		t.Fatalf("Expected the path's preference usenet, got %q", state.protocol)
+	}
+
+	usenet := integration.QueueItemInfo{ID: 1, DownloadID: "nzb", Title: "Show.S01E01.WEB", Protocol: "usenet", Status: "downloading"}
+	if verifier.rejectProtocol(state, usenet) {
+		t.Error("Expected a usenet grab to be accepted")
+	}
+
+	torrent := integration.QueueItemInfo{ID: 2, DownloadID: "btih", Title: "Show.S01E01.WEB", Protocol: "torrent", Status: "downloading"}
+	if action := verifier.handleQueueItem(state, torrent); action != monitorContinue {
+		t.Fatalf("Expected monitoring to continue after a rejected grab, got %v", action)
+	}
+	if len(removed) != 1 || removed[0] != 2 {
+		t.Fatalf("Expected the torrent to be removed and blocklisted, got %v", removed)
+	}
+
+	select {
+	case e := <-rejected:
+		if reason, _ := e.GetString("reason"); reason != "protocol" {
+			t.Errorf("Expected reason protocol, got %q", reason)
+		}
+		if protocol, _ := e.GetString("protocol"); protocol != "torrent" {
+			t.Errorf("Expected protocol torrent, got %q", protocol)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a DownloadRejected event")
+	}
+
+	if verifier.getProtocolPreference(42) != ProtocolAny {
+		t.Error("Expected an unknown path to accept any protocol")
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Quality pin modes, set per scan path and optionally overridden per corruption.
+const (
+	QualityPinOff    = "off"    // Replacements are accepted whatever their quality
+	QualityPinFlag   = "flag"   // A lower resolution is reported as QualityRegression instead of resolving
+	QualityPinSearch = "search" // As flag, and the verification fails so the search is retried
+)
+
+// Sources of an effective quality pin.
+const (
+	QualityPinSourcePath       = "path"
+	QualityPinSourceCorruption = "corruption"
+)
+
+// ErrInvalidQualityPin is returned for an unknown quality pin mode.
+var ErrInvalidQualityPin = errors.New("quality pin must be one of: off, flag, search")
+
+// ValidQualityPin reports whether mode is a known quality pin mode.
+func ValidQualityPin(mode string) bool {
+	return mode == QualityPinOff || mode == QualityPinFlag || mode == QualityPinSearch
+}
+
+// QualityPin is the quality pin that applies to a corruption.
+type QualityPin struct {
+	Mode   string `json:"mode"`
+	Source string `json:"source"` // "corruption" when overridden for the corruption, otherwise "path"
+}
+
+// LoadQualityPin returns the quality pin of a corruption: its own override if
+// set, otherwise the setting of the scan path it was detected in.
+func LoadQualityPin(ctx context.Context, database *sql.DB, corruptionID string) (QualityPin, error) {
+	var override sql.NullString
+	err := database.QueryRowContext(ctx, `SELECT mode FROM corruption_quality_pins WHERE corruption_id = ?`, corruptionID).Scan(&override)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return QualityPin{}, err
+	}
+	if override.Valid {
+		return QualityPin{Mode: override.String, Source: QualityPinSourceCorruption}, nil
+	}
+
+	var pathMode sql.NullString
+	err = database.QueryRowContext(ctx, `
+		SELECT sp.quality_pin
+		FROM scan_paths sp
+		WHERE sp.id = (
+			SELECT json_extract(event_data, '$.path_id') FROM events
+			WHERE aggregate_id = ? AND event_type = 'CorruptionDetected'
+			ORDER BY id LIMIT 1
+		)
+	`, corruptionID).Scan(&pathMode)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return QualityPin{}, err
+	}
+	pin := QualityPin{Mode: QualityPinOff, Source: QualityPinSourcePath}
+	if pathMode.Valid && ValidQualityPin(pathMode.String) {
+		pin.Mode = pathMode.String
+	}
+	return pin, nil
+}
+
+// SetQualityPin overrides the quality pin of a single corruption.
+func SetQualityPin(database *sql.DB, corruptionID, mode string) error {
+	if !ValidQualityPin(mode) {
+		return ErrInvalidQualityPin
+	}
+	_, err := db.ExecWithRetry(database, `
+		INSERT INTO corruption_quality_pins (corruption_id, mode) VALUES (?, ?)
+		ON CONFLICT(corruption_id) DO UPDATE SET mode = excluded.mode
+	`, corruptionID, mode)
+	return err
+}
+
+// ClearQualityPin removes a corruption's override, so its scan path's setting applies again.
+func ClearQualityPin(database *sql.DB, corruptionID string) error {
+	_, err := db.ExecWithRetry(database, `DELETE FROM corruption_quality_pins WHERE corruption_id = ?`, corruptionID)
+	return err
+}
+
+// loadOriginalQuality returns the quality of the file a corruption's first
+// deletion removed, as recorded from the *arr instance. The resolution is 0
+// when it isn't known.
+func loadOriginalQuality(ctx context.Context, database *sql.DB, corruptionID string) (string, int, error) {
+	var name sql.NullString
+	var resolution sql.NullInt64
+	err := database.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.metadata.quality'), json_extract(event_data, '$.metadata.quality_resolution')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = 'DeletionCompleted'
+		ORDER BY id LIMIT 1
+	`, corruptionID).Scan(&name, &resolution)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if resolution.Valid && resolution.Int64 > 0 {
+		return name.String, int(resolution.Int64), nil
+	}
+	return name.String, integration.QualityResolution(name.String), nil
+}
+
+// checkQualityPin compares a healthy replacement with the file it replaced when
+// the corruption's quality is pinned. If the replacement has a lower resolution
+// it publishes QualityRegression - followed, when the search is kept open, by
+// VerificationFailed so the normal retry schedule searches again - and returns
+// true. Replacements whose quality can't be determined are accepted.
+func (v *VerifierService) checkQualityPin(corruptionID string, filePaths []string, meta *VerificationMeta) bool {
+	if v.db == nil || meta == nil || meta.Quality == "" {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifierQueryTimeout)
+	defer cancel()
+
+	pin, err := LoadQualityPin(ctx, v.db, corruptionID)
+	if err != nil {
+		logger.Warnf("Failed to load quality pin for %s: %v", corruptionID, err)
+		return false
+	}
+	if pin.Mode == QualityPinOff {
+		return false
+	}
+
+	originalQuality, originalResolution, err := loadOriginalQuality(ctx, v.db, corruptionID)
+	if err != nil {
+		logger.Warnf("Failed to load original quality for %s: %v", corruptionID, err)
+		return false
+	}
+	newResolution := integration.QualityResolution(meta.Quality)
+	if originalResolution == 0 || newResolution == 0 {
+		logger.Debugf("Quality pin of %s not checked: resolution unknown (original %q, replacement %q)",
+			corruptionID, originalQuality, meta.Quality)
+		return false
+	}
+	if newResolution >= originalResolution {
+		return false
+	}
+
+	keepSearching := pin.Mode == QualityPinSearch
+	reason := fmt.Sprintf("Replacement %s is below the original %s", meta.Quality, originalQuality)
+	logger.Warnf("Quality regression for %s: %s (%dp < %dp)", corruptionID, reason, newResolution, originalResolution)
+
+	if err := v.eventBus.PublishWithRetry(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.QualityRegression,
+		EventData: map[string]interface{}{
+			"file_path":           filePaths[0],
+			"original_quality":    originalQuality,
+			"original_resolution": originalResolution,
+			"new_quality":         meta.Quality,
+			"new_resolution":      newResolution,
+			"keep_searching":      keepSearching,
+			"reason":              reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish QualityRegression event after retries: %v", err)
+	}
+
+	if keepSearching {
+		if err := v.eventBus.PublishWithRetry(domain.Event{
+			AggregateID:   corruptionID,
+			AggregateType: "corruption",
+			EventType:     domain.VerificationFailed,
+			EventData: map[string]interface{}{
+				"error":              "Quality regression: " + reason,
+				"quality_regression": true,
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish VerificationFailed event after retries: %v", err)
+		}
+	}
+	return true
+}
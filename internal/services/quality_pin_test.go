@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestLoadQualityPin(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, quality_pin) VALUES (1, '/media/movies', '/movies', 'flag')`); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+	if _, err := testutil.SeedEvent(db, domain.Event{
+		AggregateID:   "pinned",
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionDetected,
+		EventData:     map[string]interface{}{"file_path": "/media/movies/a.mkv", "path_id": 1},
+	}); err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ctx := context.Background()
+	pin, err := LoadQualityPin(ctx, db, "pinned")
+	if err != nil || pin != (QualityPin{Mode: QualityPinFlag, Source: QualityPinSourcePath}) {
+		t.Fatalf("Expected the path's pin, got %+v (err %v)", pin, err)
+	}
+
+	if err := SetQualityPin(db, "pinned", QualityPinSearch); err != nil {
+		t.Fatalf("SetQualityPin() error = %v", err)
+	}
+	if pin, _ = LoadQualityPin(ctx, db, "pinned"); pin != (QualityPin{Mode: QualityPinSearch, Source: QualityPinSourceCorruption}) {
+		t.Errorf("Expected the corruption's override, got %+v", pin)
+	}
+	if err := SetQualityPin(db, "pinned", "always"); err != ErrInvalidQualityPin {
+		t.Errorf("Expected ErrInvalidQualityPin, got %v", err)
+	}
+
+	if err := ClearQualityPin(db, "pinned"); err != nil {
+		t.Fatalf("ClearQualityPin() error = %v", err)
+	}
+	if pin, _ = LoadQualityPin(ctx, db, "pinned"); pin.Mode != QualityPinFlag {
+		t.Errorf("Expected the path's pin after clearing, got %+v", pin)
+	}
+	if pin, _ = LoadQualityPin(ctx, db, "unknown"); pin.Mode != QualityPinOff {
+		t.Errorf("Expected off for an unknown corruption, got %+v", pin)
+	}
+}
+
+func TestVerifierService_QualityPin(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, quality_pin) VALUES (1, '/media/movies', '/movies', 'flag')`); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+	healthy := &testutil.MockHealthChecker{
+		CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+			return true, nil
+		},
+	}
+
+	// seed records a corruption in path 1 whose deleted file was Bluray-1080p
+	seed := func(t *testing.T, id string) {
+		t.Helper()
+		for _, e := range []domain.Event{
+			{EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/media/movies/" + id + ".mkv", "path_id": 1}},
+			{EventType: domain.DeletionCompleted, EventData: map[string]interface{}{
+				"media_id": 7,
+				"metadata": map[string]interface{}{"quality": "Bluray-1080p", "quality_resolution": 1080},
+			}},
+		} {
+			e.AggregateID, e.AggregateType = id, "corruption"
+			if _, err := testutil.SeedEvent(db, e); err != nil {
+				t.Fatalf("Failed to seed event: %v", err)
+			}
+		}
+	}
+	states := func(t *testing.T, id string) []domain.EventType {
+		t.Helper()
+		events, err := testutil.GetEventsByAggregate(db, id)
+		if err != nil {
+			t.Fatalf("Failed to load events: %v", err)
+		}
+		var types []domain.EventType
+		for _, e := range events[2:] {
+			types = append(types, e.EventType)
+		}
+		return types
+	}
+	verify := func(id, quality string) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+		v := NewVerifierService(eb, healthy, nil, nil, db)
+		v.setVerifyMeta(id, &VerificationMeta{Quality: quality, NewFilePath: "/media/movies/" + id + ".new.mkv"})
+		v.verifyHealthMultiple(id, []string{"/media/movies/" + id + ".new.mkv"})
+	}
+	equal := func(got []domain.EventType, want ...domain.EventType) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("lower resolution is flagged", func(t *testing.T) {
+		seed(t, "flag")
+		verify("flag", "WEBDL-720p")
+		if got := states(t, "flag"); !equal(got, domain.VerificationStarted, domain.QualityRegression) {
+			t.Errorf("Unexpected events: %v", got)
+		}
+	})
+
+	t.Run("search mode fails the verification", func(t *testing.T) {
+		seed(t, "search")
+		if err := SetQualityPin(db, "search", QualityPinSearch); err != nil {
+			t.Fatalf("SetQualityPin() error = %v", err)
+		}
+		verify("search", "HDTV-720p")
+		if got := states(t, "search"); !equal(got, domain.VerificationStarted, domain.QualityRegression, domain.VerificationFailed) {
+			t.Errorf("Unexpected events: %v", got)
+		}
+	})
+
+	t.Run("same or higher resolution verifies", func(t *testing.T) {
+		seed(t, "better")
+		verify("better", "Remux-2160p")
+		if got := states(t, "better"); !equal(got, domain.VerificationStarted, domain.VerificationSuccess) {
+			t.Errorf("Unexpected events: %v", got)
+		}
+	})
+
+	t.Run("unpinned corruption verifies", func(t *testing.T) {
+		seed(t, "unpinned")
+		if err := SetQualityPin(db, "unpinned", QualityPinOff); err != nil {
+			t.Fatalf("SetQualityPin() error = %v", err)
+		}
+		verify("unpinned", "SDTV")
+		if got := states(t, "unpinned"); !equal(got, domain.VerificationStarted, domain.VerificationSuccess) {
+			t.Errorf("Unexpected events: %v", got)
+		}
+	})
+}
@@ -97,6 +97,7 @@ func (r *RecoveryService) Run() {
 // Early remediation states that may need recovery if interrupted
 var earlyRemediationStates = []string{
 	"RemediationQueued",
+	"RemediationDeferred",
 	"DeletionStarted",
 	"DeletionCompleted",
 }
@@ -104,6 +105,7 @@ var earlyRemediationStates = []string{
 // Post-search states that need verification recovery
 var postSearchStates = []string{
 	"DownloadProgress",
+	"DownloadRejected",
 	"SearchCompleted",
 	"SearchStarted",
 	"DownloadStarted",
@@ -151,20 +153,20 @@ func (r *RecoveryService) findStaleItems() ([]staleItem, error) {
 			(
 				SELECT json_extract(e.event_data, '$.media_id')
 				FROM events e
-				WHERE e.aggregate_id = cs.corruption_id
-				AND e.event_type IN ('SearchCompleted', 'SearchStarted', 'DeletionCompleted')
+				WHERE e.aggregate_id IN (cs.corruption_id, 'media_' || cs.corruption_id)
+				AND e.event_type IN ('SearchCompleted', 'SearchStarted', 'DeletionCompleted', 'MediaReresolved')
 				ORDER BY e.id DESC
 				LIMIT 1
 			) as media_id,
 			(
 				SELECT e.event_data
 				FROM events e
-				WHERE e.aggregate_id = cs.corruption_id
-				AND e.event_type = 'DeletionCompleted'
+				WHERE e.aggregate_id IN (cs.corruption_id, 'media_' || cs.corruption_id)
+				AND e.event_type IN ('DeletionCompleted', 'MediaReresolved')
 				ORDER BY e.id DESC
 				LIMIT 1
 			) as deletion_metadata
-		FROM corruption_status cs
+		FROM corruption_summary cs
 		LEFT JOIN scan_paths sp ON sp.id = cs.path_id
 		WHERE cs.current_state IN (` + placeholders + `)
 		AND cs.last_updated_at < ?
@@ -307,6 +309,11 @@ func (r *RecoveryService) recoverEarlyRemediationState(item staleItem) string {
 		logger.Infof("Recovery: %s stuck in RemediationQueued, re-triggering remediation", item.FilePath)
 		return r.emitRetryScheduled(item)
 
+	case "RemediationDeferred":
+		// Deferred over the data budget; re-trigger so the budget is checked again
+		logger.Infof("Recovery: %s deferred over the data budget, re-triggering remediation", item.FilePath)
+		return r.emitRetryScheduled(item)
+
 	case "DeletionStarted":
 		// Deletion was started but we don't know if it completed
 		// Check if file still exists on disk
@@ -31,7 +31,7 @@ func setupRecoveryTestDB(t *testing.T) *sql.DB {
 
 	// Create required tables
 	_, err = db.Exec(`
-		CREATE TABLE corruption_status (
+		CREATE TABLE corruption_summary (
 			corruption_id TEXT PRIMARY KEY,
 			current_state TEXT NOT NULL,
 			file_path TEXT NOT NULL,
@@ -56,7 +56,8 @@ func setupRecoveryTestDB(t *testing.T) *sql.DB {
 			arr_path TEXT NOT NULL,
 			instance_id INTEGER,
 			enabled BOOLEAN DEFAULT 1,
-			max_retries INTEGER DEFAULT 3
+			max_retries INTEGER DEFAULT 3,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 	`)
 	if err != nil {
@@ -138,10 +139,10 @@ func TestFindStaleItems_WithStaleItems(t *testing.T) {
 	eb := eventbus.NewEventBus(db)
 	defer eb.Shutdown()
 
-	// Insert a stale corruption_status record
+	// Insert a stale corruption_summary record
 	oldTime := time.Now().Add(-48 * time.Hour).Format("2006-01-02 15:04:05")
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-1", "DownloadProgress", "/media/test.mkv", 1, oldTime, oldTime)
 	if err != nil {
@@ -174,10 +175,10 @@ func TestFindStaleItems_FreshItemsNotReturned(t *testing.T) {
 	eb := eventbus.NewEventBus(db)
 	defer eb.Shutdown()
 
-	// Insert a fresh corruption_status record (within threshold)
+	// Insert a fresh corruption_summary record (within threshold)
 	recentTime := time.Now().Add(-1 * time.Hour).Format("2006-01-02 15:04:05")
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-2", "DownloadProgress", "/media/fresh.mkv", 1, recentTime, recentTime)
 	if err != nil {
@@ -221,7 +222,7 @@ func TestFindStaleItems_DifferentStates(t *testing.T) {
 
 	for _, tc := range testCases {
 		_, err := db.Exec(`
-			INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+			INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 			VALUES (?, ?, ?, ?, ?, ?)
 		`, tc.id, tc.state, "/media/"+tc.id+".mkv", 1, oldTime, oldTime)
 		if err != nil {
@@ -426,10 +427,10 @@ func TestRun_WithStaleItems(t *testing.T) {
 		}
 	})
 
-	// Insert a stale corruption_status record
+	// Insert a stale corruption_summary record
 	oldTime := time.Now().Add(-48 * time.Hour).Format("2006-01-02 15:04:05")
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-run", "DownloadProgress", "/media/nonexistent.mkv", 1, oldTime, oldTime)
 	if err != nil {
@@ -2199,7 +2200,7 @@ func TestFindStaleItems_AllStateCategories(t *testing.T) {
 
 	for _, ti := range testItems {
 		_, err := db.Exec(`
-			INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, retry_count, last_updated_at, detected_at)
+			INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, retry_count, last_updated_at, detected_at)
 			VALUES (?, ?, ?, ?, ?, ?, ?)
 		`, ti.id, ti.state, "/media/"+ti.id+".mkv", 1, 0, oldTime, oldTime)
 		if err != nil {
@@ -2233,7 +2234,7 @@ func TestFindStaleItems_RFC3339Timestamp(t *testing.T) {
 	// Insert a stale item with RFC3339 timestamp format (the fallback parser path)
 	oldTime := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-rfc3339", "DownloadProgress", "/media/rfc3339.mkv", 1, oldTime, oldTime)
 	if err != nil {
@@ -2266,9 +2267,9 @@ func TestFindStaleItems_NullMediaID(t *testing.T) {
 
 	oldTime := time.Now().Add(-48 * time.Hour).Format("2006-01-02 15:04:05")
 
-	// Insert corruption_status with no corresponding event (so media_id subquery returns NULL)
+	// Insert corruption_summary with no corresponding event (so media_id subquery returns NULL)
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-nomedia", "DownloadProgress", "/media/nomedia.mkv", 1, oldTime, oldTime)
 	if err != nil {
@@ -2298,13 +2299,13 @@ func TestFindStaleItems_InvalidDeletionMetadata(t *testing.T) {
 
 	oldTime := time.Now().Add(-48 * time.Hour).Format("2006-01-02 15:04:05")
 
-	// Insert corruption_status
+	// Insert corruption_summary
 	_, err := db.Exec(`
-		INSERT INTO corruption_status (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
+		INSERT INTO corruption_summary (corruption_id, current_state, file_path, path_id, last_updated_at, detected_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, "test-uuid-badjson", "DeletionCompleted", "/media/badjson.mkv", 1, oldTime, oldTime)
 	if err != nil {
-		t.Fatalf("Failed to insert corruption_status: %v", err)
+		t.Fatalf("Failed to insert corruption_summary: %v", err)
 	}
 
 	// Insert a DeletionCompleted event with a JSON array instead of a JSON object.
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrCorruptionNotFound is returned for a corruption that was never detected.
+var ErrCorruptionNotFound = errors.New("corruption not found")
+
+// releaseTarget is the media item a corruption's replacement is searched for.
+type releaseTarget struct {
+	filePath string
+	arrPath  string
+	pathID   int64
+	mediaID  int64
+	metadata map[string]interface{}
+}
+
+// loadReleaseTarget reads the file, scan path and *arr media item of a
+// corruption from its events. The media item comes from the latest deletion
+// and is looked up by path if the file was never deleted.
+func (r *RemediatorService) loadReleaseTarget(ctx context.Context, corruptionID string) (*releaseTarget, error) {
+	var filePath sql.NullString
+	var pathID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.file_path'), json_extract(event_data, '$.path_id')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = 'CorruptionDetected'
+		ORDER BY id LIMIT 1
+	`, corruptionID).Scan(&filePath, &pathID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && filePath.String == "") {
+		return nil, ErrCorruptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	target := &releaseTarget{filePath: filePath.String, pathID: pathID.Int64}
+
+	// A media ID looked up after a library migration replaces the deleted
+	// one, and the episode IDs of the old instance along with it
+	var mediaID sql.NullInt64
+	var metadataJSON sql.NullString
+	err = r.db.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.media_id'), json_extract(event_data, '$.metadata')
+		FROM events
+		WHERE aggregate_id IN (?, ?) AND event_type IN ('DeletionCompleted', 'MediaReresolved')
+		ORDER BY id DESC LIMIT 1
+	`, corruptionID, mediaAggregateID(corruptionID)).Scan(&mediaID, &metadataJSON)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	target.mediaID = mediaID.Int64
+	if metadataJSON.Valid && metadataJSON.String != "" {
+		if err := json.Unmarshal([]byte(metadataJSON.String), &target.metadata); err != nil {
+			logger.Debugf("Failed to parse deletion metadata of %s: %v", corruptionID, err)
+		}
+	}
+
+	if target.arrPath, err = r.pathMapper.ToArrPath(target.filePath); err != nil {
+		return nil, fmt.Errorf("failed to map path %s: %w", target.filePath, err)
+	}
+	if target.mediaID == 0 {
+		if target.mediaID, err = r.arrClient.FindMediaByPath(target.arrPath); err != nil {
+			return nil, fmt.Errorf("failed to find media: %w", err)
+		}
+	}
+	return target, nil
+}
+
+// SearchReleases runs an interactive search for a corruption's media item and
+// returns the releases the *arr instance's indexers offer, without grabbing any.
+func (r *RemediatorService) SearchReleases(ctx context.Context, corruptionID string) ([]integration.Release, error) {
+	target, err := r.loadReleaseTarget(ctx, corruptionID)
+	if err != nil {
+		return nil, err
+	}
+	return r.arrClient.GetReleases(target.mediaID, target.arrPath, extractEpisodeIDs(target.metadata))
+}
+
+// GrabRelease makes the *arr instance download a release picked from
+// SearchReleases as a corruption's replacement. The grab is recorded as a
+// SearchCompleted event, so the download is tracked and verified like one
+// started by an automatic search.
+func (r *RemediatorService) GrabRelease(ctx context.Context, corruptionID, guid string, indexerID int64) (*integration.Release, error) {
+	target, err := r.loadReleaseTarget(ctx, corruptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := r.arrClient.GrabRelease(target.arrPath, guid, indexerID)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		release = &integration.Release{GUID: guid, IndexerID: indexerID}
+	}
+	logger.Infof("Grabbed release %q for %s", release.Title, target.filePath)
+
+	eventData := r.buildSearchEventData(target.filePath, target.arrPath, target.mediaID, target.pathID, target.metadata, false)
+	eventData["manual_grab"] = true
+	if release.Title != "" {
+		eventData["release_title"] = release.Title
+	}
+	if err := r.eventBus.PublishWithRetry(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.SearchCompleted,
+		EventData:     eventData,
+	}); err != nil {
+		logger.Errorf("Failed to publish SearchCompleted event after retries: %v", err)
+	}
+	return release, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediatorService_Releases(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	for _, e := range []domain.Event{
+		{EventType: domain.CorruptionDetected, EventData: map[string]interface{}{"file_path": "/media/tv/Show/S01E01.mkv", "path_id": 2}},
+		{EventType: domain.DeletionCompleted, EventData: map[string]interface{}{
+			"media_id": 5,
+			"metadata": map[string]interface{}{"episode_ids": []int64{11}},
+		}},
+	} {
+		e.AggregateID, e.AggregateType = "ep", "corruption"
+		if _, err := testutil.SeedEvent(db, e); err != nil {
+			t.Fatalf("Failed to seed event: %v", err)
+		}
+	}
+
+	bus := testutil.NewMockEventBus()
+	arr := &testutil.MockArrClient{
+		GetReleasesFunc: func(mediaID int64, arrPath string, episodeIDs []int64) ([]integration.Release, error) {
+			if mediaID != 5 || len(episodeIDs) != 1 || episodeIDs[0] != 11 {
+				t.Errorf("Unexpected search for media %d, episodes %v", mediaID, episodeIDs)
+			}
+			return []integration.Release{{GUID: "a", IndexerID: 1, Title: "Show.S01E01.1080p"}}, nil
+		},
+		GrabReleaseFunc: func(arrPath, guid string, indexerID int64) (*integration.Release, error) {
+			return &integration.Release{GUID: guid, IndexerID: indexerID, Title: "Show.S01E01.1080p"}, nil
+		},
+	}
+	r := NewRemediatorService(bus, arr, &testutil.MockPathMapper{}, db)
+	ctx := context.Background()
+
+	releases, err := r.SearchReleases(ctx, "ep")
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("SearchReleases() = %v, %v", releases, err)
+	}
+
+	if _, err := r.GrabRelease(ctx, "ep", "a", 1); err != nil {
+		t.Fatalf("GrabRelease() error = %v", err)
+	}
+	events := bus.GetEvents(domain.SearchCompleted)
+	if len(events) != 1 {
+		t.Fatalf("Expected one SearchCompleted event, got %d", len(events))
+	}
+	if title, _ := events[0].GetString("release_title"); title != "Show.S01E01.1080p" || events[0].EventData["manual_grab"] != true {
+		t.Errorf("Unexpected SearchCompleted data: %v", events[0].EventData)
+	}
+	if err := domain.ValidateEventData(domain.SearchCompleted, events[0].EventData); err != nil {
+		t.Errorf("SearchCompleted data is invalid: %v", err)
+	}
+
+	if _, err := r.SearchReleases(ctx, "unknown"); !errors.Is(err, ErrCorruptionNotFound) {
+		t.Errorf("Expected ErrCorruptionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// beginRemediation claims a corruption for a remediation, so the retry timer,
+// the recovery service and a manual retry can't work on it at the same time.
+// Returns nil when a remediation of the corruption is already running;
+// otherwise the caller must call the returned func when it is done.
+func (r *RemediatorService) beginRemediation(corruptionID string) func() {
+	r.inFlightMu.Lock()
+	defer r.inFlightMu.Unlock()
+	if _, busy := r.inFlight[corruptionID]; busy {
+		return nil
+	}
+	if r.inFlight == nil {
+		r.inFlight = make(map[string]struct{})
+	}
+	r.inFlight[corruptionID] = struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.inFlightMu.Lock()
+			delete(r.inFlight, corruptionID)
+			r.inFlightMu.Unlock()
+		})
+	}
+}
+
+// isSuperseded reports whether the corruption moved on since event was
+// stored, e.g. a second retry of the same failure that another retry already
+// handled. Notifications don't count, they don't change the state.
+func (r *RemediatorService) isSuperseded(event domain.Event) bool {
+	if r.db == nil || event.ID == 0 {
+		return false
+	}
+	var newer int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM events
+		WHERE aggregate_id = ? AND id > ? AND event_type NOT IN (?, ?)
+	`, event.AggregateID, event.ID, domain.NotificationSent, domain.NotificationFailed).Scan(&newer); err != nil {
+		logger.Debugf("Failed to check for newer events of %s: %v", event.AggregateID, err)
+		return false
+	}
+	return newer > 0
+}
+
+// isSettled reports whether the corruption was resolved or ignored, e.g. by
+// the user while its remediation waited for a slot.
+func (r *RemediatorService) isSettled(corruptionID string) bool {
+	if r.db == nil {
+		return false
+	}
+	var settled bool
+	if err := r.db.QueryRow(`
+		SELECT event_type IN (`+resolvedStatesSQL+`) FROM events
+		WHERE aggregate_id = ? AND event_type NOT IN (?, ?)
+		ORDER BY id DESC LIMIT 1
+	`, corruptionID, domain.NotificationSent, domain.NotificationFailed).Scan(&settled); err != nil {
+		return false
+	}
+	return settled
+}
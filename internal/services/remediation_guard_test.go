@@ -0,0 +1,154 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediatorService_SingleFlightPerCorruption(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	deleting := make(chan struct{})
+	proceed := make(chan struct{})
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+		DeleteFileFunc: func(mediaID int64, path string) (map[string]interface{}, error) {
+			close(deleting)
+			<-proceed
+			return map[string]interface{}{}, nil
+		},
+	}
+	remediator := NewRemediatorService(testutil.NewMockEventBus(), mockArrClient, &testutil.MockPathMapper{}, db)
+
+	event := testutil.NewCorruptionEventWithType(testutil.TestFilePaths.Movie1, integration.ErrorTypeCorruptHeader, testutil.WithAutoRemediate(true))
+	remediator.handleCorruptionDetected(event)
+	<-deleting
+
+	// The retry timer and a manual retry fire while the remediation runs
+	retry := domain.Event{
+		AggregateID:   event.AggregateID,
+		AggregateType: "corruption",
+		EventType:     domain.RetryScheduled,
+		EventData:     map[string]interface{}{"file_path": testutil.TestFilePaths.Movie1, "auto_remediate": true},
+	}
+	remediator.handleRetry(retry)
+	remediator.handleCorruptionDetected(event)
+
+	close(proceed)
+	remediator.wg.Wait()
+
+	if got := mockArrClient.CallCount("DeleteFile"); got != 1 {
+		t.Errorf("Expected 1 deletion, got %d", got)
+	}
+	if release := remediator.beginRemediation(event.AggregateID); release == nil {
+		t.Error("Expected the corruption to be released after the remediation finished")
+	} else {
+		release()
+	}
+}
+
+func TestRemediatorService_BeginRemediation_ReleaseOnce(t *testing.T) {
+	remediator := NewRemediatorService(testutil.NewMockEventBus(), &testutil.MockArrClient{}, &testutil.MockPathMapper{}, nil)
+
+	release := remediator.beginRemediation("a")
+	if release == nil {
+		t.Fatal("Expected the first claim to succeed")
+	}
+	if remediator.beginRemediation("a") != nil {
+		t.Error("Expected a second claim of the same corruption to fail")
+	}
+	other := remediator.beginRemediation("b")
+	if other == nil {
+		t.Fatal("Expected other corruptions to be claimable")
+	}
+	other()
+
+	release()
+	again := remediator.beginRemediation("a")
+	if again == nil {
+		t.Fatal("Expected the corruption to be claimable after release")
+	}
+	// A stale release must not drop the new claim
+	release()
+	if remediator.beginRemediation("a") != nil {
+		t.Error("Expected the new claim to hold")
+	}
+	again()
+}
+
+func TestRemediatorService_IsSupersededAndSettled(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+	remediator := NewRemediatorService(testutil.NewMockEventBus(), &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+
+	seed := func(eventType domain.EventType) domain.Event {
+		event := domain.Event{AggregateID: "c1", AggregateType: "corruption", EventType: eventType, EventData: map[string]interface{}{}}
+		id, err := testutil.SeedEvent(db, event)
+		if err != nil {
+			t.Fatalf("Failed to seed %s: %v", eventType, err)
+		}
+		event.ID = id
+		return event
+	}
+
+	first := seed(domain.RetryScheduled)
+	seed(domain.NotificationSent)
+	if remediator.isSuperseded(first) {
+		t.Error("Expected notifications not to supersede a retry")
+	}
+	second := seed(domain.RetryScheduled)
+	if !remediator.isSuperseded(first) || remediator.isSuperseded(second) {
+		t.Error("Expected only the older retry to be superseded")
+	}
+	if remediator.isSuperseded(domain.Event{AggregateID: "c1"}) {
+		t.Error("Expected events without an ID never to be superseded")
+	}
+
+	if remediator.isSettled("c1") {
+		t.Error("Expected a pending retry not to be settled")
+	}
+	seed(domain.CorruptionIgnored)
+	seed(domain.NotificationSent)
+	if !remediator.isSettled("c1") {
+		t.Error("Expected an ignored corruption to be settled")
+	}
+	if remediator.isSettled("unknown") {
+		t.Error("Expected an unknown corruption not to be settled")
+	}
+}
+
+func TestRemediatorService_ExecuteRemediation_SkipsSettled(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	// The user ignored the corruption while its remediation waited for a slot
+	if _, err := testutil.SeedEvent(db, domain.Event{AggregateID: "test-id", AggregateType: "corruption", EventType: domain.CorruptionIgnored}); err != nil {
+		t.Fatal(err)
+	}
+	mockEventBus := testutil.NewMockEventBus()
+	mockArrClient := &testutil.MockArrClient{
+		FindMediaByPathFunc: func(path string) (int64, error) { return 42, nil },
+	}
+	remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, db)
+	remediator.executeRemediation("test-id", "/tv/Show/S01E01.mkv", "/tv/Show/S01E01.mkv", 1)
+
+	if got := mockArrClient.CallCount("DeleteFile"); got != 0 {
+		t.Errorf("Expected nothing to be deleted, got %d DeleteFile calls", got)
+	}
+	if got := mockEventBus.EventCount(domain.DeletionStarted); got != 0 {
+		t.Errorf("Expected no DeletionStarted event, got %d", got)
+	}
+}
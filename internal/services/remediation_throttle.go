@@ -0,0 +1,468 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/clock"
+)
+
+// searchThrottleWindow is the sliding window RemediationThrottleConfig.SearchesPerHour applies to.
+const searchThrottleWindow = time.Hour
+
+// RemediationThrottleConfig limits how hard remediation hits each *arr instance.
+// A scan that finds hundreds of corruptions would otherwise fire hundreds of
+// searches at once and get the user banned from their indexers.
+type RemediationThrottleConfig struct {
+	// MaxConcurrent is the number of remediations running at once per instance.
+	// Zero or less uses maxConcurrentRemediations.
+	MaxConcurrent int
+	// SearchesPerHour caps remediations started per instance in any hour. Zero means no limit.
+	SearchesPerHour int
+	// Window is a daily "HH:MM-HH:MM" window, in the scheduler's timezone, in
+	// which remediations start. Outside it they wait in the queue, so replacement
+	// downloads happen off-peak. Empty allows them at any time.
+	Window string
+}
+
+// QueuedRemediation is a remediation waiting for its instance to have capacity.
+type QueuedRemediation struct {
+	CorruptionID string    `json:"corruption_id"`
+	FilePath     string    `json:"file_path"`
+	InstanceID   int64     `json:"instance_id"`
+	Position     int       `json:"position"` // 1-based position in the instance's queue
+	QueuedAt     time.Time `json:"queued_at"`
+}
+
+// InstanceThrottleStatus is the remediation throttle state of one *arr instance.
+type InstanceThrottleStatus struct {
+	InstanceID       int64      `json:"instance_id"`
+	Active           int        `json:"active"`
+	Queued           int        `json:"queued"`
+	SearchesInWindow int        `json:"searches_in_window"`
+	NextSlotAt       *time.Time `json:"next_slot_at,omitempty"` // set while the hourly limit holds the queue back
+	DetectionOnly    bool       `json:"detection_only"`         // remediation paused during an instance outage
+}
+
+// RemediationQueueStatus describes the remediation throttle and everything it holds back.
+type RemediationQueueStatus struct {
+	MaxConcurrent   int                      `json:"max_concurrent"`
+	SearchesPerHour int                      `json:"searches_per_hour"`
+	Window          string                   `json:"window,omitempty"`          // daily window in which remediations start
+	WindowOpensAt   *time.Time               `json:"window_opens_at,omitempty"` // set while outside the window
+	BudgetPaused    bool                     `json:"budget_paused"`             // monthly data budget used up
+	SystemPaused    bool                     `json:"system_paused"`             // all automation paused by the user
+	Instances       []InstanceThrottleStatus `json:"instances"`
+	Queue           []QueuedRemediation      `json:"queue"`
+}
+
+// throttleTicket is one remediation waiting for, or holding, an instance slot.
+type throttleTicket struct {
+	corruptionID string
+	filePath     string
+	queuedAt     time.Time
+	ready        chan struct{}
+	granted      bool
+	overBudget   bool // approved over the data budget, so the budget pause doesn't hold it
+}
+
+// instanceThrottle tracks one instance's slots, search history and FIFO queue.
+type instanceThrottle struct {
+	active   int
+	searches []time.Time // start times within the last searchThrottleWindow, oldest first
+	queue    []*throttleTicket
+	timer    clock.Timer // pending dispatch once the oldest search leaves the window
+	paused   bool        // detection-only: nothing is dispatched until resumed
+}
+
+// remediationThrottle hands out per-instance remediation slots in FIFO order.
+type remediationThrottle struct {
+	mu        sync.Mutex
+	cfg       RemediationThrottleConfig
+	clk       clock.Clock
+	instances map[int64]*instanceThrottle
+	window    *dailyWindow   // nil when remediations may start at any time
+	windowLoc *time.Location // timezone the window is in
+	// budgetPaused holds every instance's queue while the data budget is used up
+	budgetPaused bool
+	// systemPaused holds every instance's queue while automation is paused
+	systemPaused bool
+}
+
+func newRemediationThrottle(cfg RemediationThrottleConfig, clk clock.Clock) *remediationThrottle {
+	return &remediationThrottle{
+		cfg:       cfg,
+		clk:       clk,
+		instances: make(map[int64]*instanceThrottle),
+	}
+}
+
+// setConfig replaces the limits and lets waiting remediations through if they now fit.
+// An invalid window is reported and leaves remediations unrestricted by time.
+func (t *remediationThrottle) setConfig(cfg RemediationThrottleConfig) error {
+	var window *dailyWindow
+	var err error
+	if cfg.Window != "" {
+		if window, err = parseDailyWindow(cfg.Window); err != nil {
+			cfg.Window = ""
+			err = fmt.Errorf("remediation window: %w", err)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cfg = cfg
+	t.window, t.windowLoc = window, cronLocation()
+	for id, inst := range t.instances {
+		// Pending dispatches were timed for the old limits
+		if inst.timer != nil {
+			inst.timer.Stop()
+			inst.timer = nil
+		}
+		t.dispatch(id, inst)
+	}
+	return err
+}
+
+// windowOpensAt returns when the remediation window opens next if now is
+// outside it. Must be called with t.mu held.
+func (t *remediationThrottle) windowOpensAt(now time.Time) (time.Time, bool) {
+	if t.window == nil {
+		return time.Time{}, false
+	}
+	local := now.In(t.windowLoc)
+	if t.window.contains(local) {
+		return time.Time{}, false
+	}
+	return t.window.nextStart(local), true
+}
+
+func (t *remediationThrottle) maxConcurrent() int {
+	if t.cfg.MaxConcurrent <= 0 {
+		return maxConcurrentRemediations
+	}
+	return t.cfg.MaxConcurrent
+}
+
+func (t *remediationThrottle) instance(instanceID int64) *instanceThrottle {
+	inst, ok := t.instances[instanceID]
+	if !ok {
+		inst = &instanceThrottle{}
+		t.instances[instanceID] = inst
+	}
+	return inst
+}
+
+// acquire waits for a slot on the instance. It returns a release function, or
+// nil if shutdownCh closed first. onQueued is called with the queue position
+// if the remediation has to wait. A remediation approved over the data budget
+// isn't held by pauseAll.
+func (t *remediationThrottle) acquire(instanceID int64, corruptionID, filePath string, overBudget bool, shutdownCh <-chan struct{}, onQueued func(position int)) func() {
+	t.mu.Lock()
+	inst := t.instance(instanceID)
+	ticket := &throttleTicket{
+		corruptionID: corruptionID,
+		filePath:     filePath,
+		queuedAt:     t.clk.Now(),
+		ready:        make(chan struct{}),
+		overBudget:   overBudget,
+	}
+	inst.queue = append(inst.queue, ticket)
+	t.dispatch(instanceID, inst)
+	queued := !ticket.granted
+	position := len(inst.queue)
+	t.mu.Unlock()
+
+	if queued && onQueued != nil {
+		onQueued(position)
+	}
+
+	select {
+	case <-ticket.ready:
+		return func() { t.release(instanceID) }
+	case <-shutdownCh:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if ticket.granted {
+			// Granted while shutting down; give the slot straight back
+			inst.active--
+			t.dispatch(instanceID, inst)
+			return nil
+		}
+		for i, queued := range inst.queue {
+			if queued == ticket {
+				inst.queue = append(inst.queue[:i], inst.queue[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+}
+
+func (t *remediationThrottle) release(instanceID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	inst := t.instance(instanceID)
+	if inst.active > 0 {
+		inst.active--
+	}
+	t.dispatch(instanceID, inst)
+}
+
+// dispatch grants slots to queued remediations while the instance has capacity.
+// While the data budget is used up only remediations approved over it go.
+// Must be called with t.mu held.
+func (t *remediationThrottle) dispatch(instanceID int64, inst *instanceThrottle) {
+	if inst.paused || t.systemPaused {
+		return
+	}
+	now := t.clk.Now()
+	inst.pruneSearches(now)
+	if opensAt, closed := t.windowOpensAt(now); closed {
+		if len(inst.queue) > 0 {
+			t.scheduleDispatch(instanceID, inst, opensAt.Sub(now))
+		}
+		return
+	}
+
+	for inst.active < t.maxConcurrent() {
+		next := t.nextTicket(inst)
+		if next < 0 {
+			return
+		}
+		if t.cfg.SearchesPerHour > 0 && len(inst.searches) >= t.cfg.SearchesPerHour {
+			t.scheduleDispatch(instanceID, inst, inst.searches[0].Add(searchThrottleWindow).Sub(now))
+			return
+		}
+		ticket := inst.queue[next]
+		inst.queue = append(inst.queue[:next], inst.queue[next+1:]...)
+		inst.active++
+		inst.searches = append(inst.searches, now)
+		ticket.granted = true
+		close(ticket.ready)
+	}
+}
+
+// nextTicket returns the index of the queued remediation to start next, or -1
+// if none may start. Must be called with t.mu held.
+func (t *remediationThrottle) nextTicket(inst *instanceThrottle) int {
+	for i, ticket := range inst.queue {
+		if !t.budgetPaused || ticket.overBudget {
+			return i
+		}
+	}
+	return -1
+}
+
+// pause holds every remediation for the instance in the queue until resume.
+// It reports whether the instance was running before.
+func (t *remediationThrottle) pause(instanceID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	inst := t.instance(instanceID)
+	if inst.paused {
+		return false
+	}
+	inst.paused = true
+	return true
+}
+
+// resume lets the instance's queued backlog through again. It returns the number
+// of remediations that were waiting, or -1 if the instance was not paused.
+func (t *remediationThrottle) resume(instanceID int64) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	inst := t.instance(instanceID)
+	if !inst.paused {
+		return -1
+	}
+	inst.paused = false
+	backlog := len(inst.queue)
+	t.dispatch(instanceID, inst)
+	return backlog
+}
+
+// pauseAll holds remediations for every instance in the queue until resumeAll,
+// except those approved over the data budget. It reports whether remediation
+// was running before.
+func (t *remediationThrottle) pauseAll() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.budgetPaused {
+		return false
+	}
+	t.budgetPaused = true
+	return true
+}
+
+// resumeAll lets every instance's queued backlog through again, unless an
+// instance is paused on its own. It returns the number of remediations that
+// were waiting, or -1 if remediation was not paused by pauseAll.
+func (t *remediationThrottle) resumeAll() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.budgetPaused {
+		return -1
+	}
+	t.budgetPaused = false
+	backlog := 0
+	for id, inst := range t.instances {
+		backlog += len(inst.queue)
+		t.dispatch(id, inst)
+	}
+	return backlog
+}
+
+// setSystemPaused holds or releases every instance's queue for the global
+// automation pause. Instances paused on their own or by the data budget stay
+// held when it is released.
+func (t *remediationThrottle) setSystemPaused(paused bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.systemPaused = paused
+	if paused {
+		return
+	}
+	for id, inst := range t.instances {
+		t.dispatch(id, inst)
+	}
+}
+
+// queuedTotal returns the number of remediations waiting across all instances.
+func (t *remediationThrottle) queuedTotal() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := 0
+	for _, inst := range t.instances {
+		total += len(inst.queue)
+	}
+	return total
+}
+
+// pausedInstances returns the IDs of instances in detection-only mode.
+func (t *remediationThrottle) pausedInstances() []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var ids []int64
+	for id, inst := range t.instances {
+		if inst.paused {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// queued returns the number of remediations waiting for the instance.
+func (t *remediationThrottle) queued(instanceID int64) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if inst, ok := t.instances[instanceID]; ok {
+		return len(inst.queue)
+	}
+	return 0
+}
+
+// scheduleDispatch retries dispatch once the hourly limit allows another search
+// or the remediation window opens.
+// Must be called with t.mu held.
+func (t *remediationThrottle) scheduleDispatch(instanceID int64, inst *instanceThrottle, wait time.Duration) {
+	if inst.timer != nil {
+		return
+	}
+	inst.timer = t.clk.AfterFunc(wait, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		inst.timer = nil
+		t.dispatch(instanceID, inst)
+	})
+}
+
+// pruneSearches drops search start times that left the window.
+func (inst *instanceThrottle) pruneSearches(now time.Time) {
+	cutoff := now.Add(-searchThrottleWindow)
+	i := 0
+	for i < len(inst.searches) && !inst.searches[i].After(cutoff) {
+		i++
+	}
+	inst.searches = inst.searches[i:]
+}
+
+// stop cancels pending dispatch timers.
+func (t *remediationThrottle) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, inst := range t.instances {
+		if inst.timer != nil {
+			inst.timer.Stop()
+			inst.timer = nil
+		}
+	}
+}
+
+// status returns a snapshot of all instances that have activity and their queues.
+func (t *remediationThrottle) status() RemediationQueueStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clk.Now()
+	status := RemediationQueueStatus{
+		MaxConcurrent:   t.maxConcurrent(),
+		SearchesPerHour: t.cfg.SearchesPerHour,
+		Window:          t.cfg.Window,
+		BudgetPaused:    t.budgetPaused,
+		SystemPaused:    t.systemPaused,
+		Instances:       []InstanceThrottleStatus{},
+		Queue:           []QueuedRemediation{},
+	}
+	opensAt, windowClosed := t.windowOpensAt(now)
+	if windowClosed {
+		status.WindowOpensAt = &opensAt
+	}
+
+	for id, inst := range t.instances {
+		inst.pruneSearches(now)
+		if inst.active == 0 && len(inst.queue) == 0 && len(inst.searches) == 0 && !inst.paused {
+			continue
+		}
+		is := InstanceThrottleStatus{
+			InstanceID:       id,
+			Active:           inst.active,
+			Queued:           len(inst.queue),
+			SearchesInWindow: len(inst.searches),
+			DetectionOnly:    inst.paused,
+		}
+		if len(inst.queue) > 0 && !inst.paused && !t.budgetPaused && !t.systemPaused && !windowClosed && t.cfg.SearchesPerHour > 0 && len(inst.searches) >= t.cfg.SearchesPerHour {
+			next := inst.searches[0].Add(searchThrottleWindow)
+			is.NextSlotAt = &next
+		}
+		status.Instances = append(status.Instances, is)
+
+		for i, ticket := range inst.queue {
+			status.Queue = append(status.Queue, QueuedRemediation{
+				CorruptionID: ticket.corruptionID,
+				FilePath:     ticket.filePath,
+				InstanceID:   id,
+				Position:     i + 1,
+				QueuedAt:     ticket.queuedAt,
+			})
+		}
+	}
+
+	sort.Slice(status.Instances, func(i, j int) bool {
+		return status.Instances[i].InstanceID < status.Instances[j].InstanceID
+	})
+	sort.Slice(status.Queue, func(i, j int) bool {
+		a, b := status.Queue[i], status.Queue[j]
+		if !a.QueuedAt.Equal(b.QueuedAt) {
+			return a.QueuedAt.Before(b.QueuedAt)
+		}
+		if a.InstanceID != b.InstanceID {
+			return a.InstanceID < b.InstanceID
+		}
+		return a.Position < b.Position
+	})
+	return status
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// acquireAsync starts an acquire and returns a channel that yields its release func.
+func acquireAsync(th *remediationThrottle, instanceID int64, id string, shutdownCh chan struct{}) <-chan func() {
+	result := make(chan func(), 1)
+	go func() {
+		result <- th.acquire(instanceID, id, "/media/"+id+".mkv", false, shutdownCh, nil)
+	}()
+	return result
+}
+
+func waitQueued(t *testing.T, th *remediationThrottle, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(th.status().Queue) == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d queued remediations, got %+v", want, th.status().Queue)
+}
+
+func expectGranted(t *testing.T, ch <-chan func()) func() {
+	t.Helper()
+	select {
+	case release := <-ch:
+		if release == nil {
+			t.Fatal("Expected a slot, got nil")
+		}
+		return release
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a slot")
+		return nil
+	}
+}
+
+func expectWaiting(t *testing.T, ch <-chan func()) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatal("Expected remediation to stay queued")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRemediationThrottle_ConcurrencyPerInstance(t *testing.T) {
+	th := newRemediationThrottle(RemediationThrottleConfig{MaxConcurrent: 2}, testutil.NewMockClock())
+	shutdown := make(chan struct{})
+
+	r1 := expectGranted(t, acquireAsync(th, 1, "a", shutdown))
+	expectGranted(t, acquireAsync(th, 1, "b", shutdown))
+	third := acquireAsync(th, 1, "c", shutdown)
+	waitQueued(t, th, 1)
+
+	// Another instance is not affected
+	expectGranted(t, acquireAsync(th, 2, "d", shutdown))
+
+	status := th.status()
+	if len(status.Instances) != 2 || status.Instances[0].Active != 2 || status.Instances[0].Queued != 1 {
+		t.Errorf("Unexpected instance status: %+v", status.Instances)
+	}
+	if status.Queue[0].CorruptionID != "c" || status.Queue[0].Position != 1 || status.Queue[0].InstanceID != 1 {
+		t.Errorf("Unexpected queue: %+v", status.Queue)
+	}
+
+	expectWaiting(t, third)
+	r1()
+	expectGranted(t, third)
+}
+
+func TestRemediationThrottle_SearchesPerHour(t *testing.T) {
+	clk := testutil.NewMockClock()
+	th := newRemediationThrottle(RemediationThrottleConfig{MaxConcurrent: 10, SearchesPerHour: 2}, clk)
+	shutdown := make(chan struct{})
+
+	expectGranted(t, acquireAsync(th, 1, "a", shutdown))()
+	clk.Advance(10 * time.Minute)
+	expectGranted(t, acquireAsync(th, 1, "b", shutdown))()
+
+	// Both slots are free but the hourly budget is spent
+	third := acquireAsync(th, 1, "c", shutdown)
+	waitQueued(t, th, 1)
+	fourth := acquireAsync(th, 1, "d", shutdown)
+	waitQueued(t, th, 2)
+	expectWaiting(t, third)
+
+	status := th.status()
+	if status.Instances[0].NextSlotAt == nil || !status.Instances[0].NextSlotAt.Equal(clk.Now().Add(50*time.Minute)) {
+		t.Errorf("Expected next slot 50 minutes out, got %+v", status.Instances[0])
+	}
+
+	// The first search leaves the window after an hour
+	clk.Advance(50 * time.Minute)
+	expectGranted(t, third)
+	expectWaiting(t, fourth)
+
+	clk.Advance(10 * time.Minute)
+	expectGranted(t, fourth)
+}
+
+func TestRemediationThrottle_ShutdownWhileQueued(t *testing.T) {
+	th := newRemediationThrottle(RemediationThrottleConfig{MaxConcurrent: 1}, testutil.NewMockClock())
+	shutdown := make(chan struct{})
+
+	expectGranted(t, acquireAsync(th, 1, "a", shutdown))
+	queued := acquireAsync(th, 1, "b", shutdown)
+	waitQueued(t, th, 1)
+
+	close(shutdown)
+	select {
+	case release := <-queued:
+		if release != nil {
+			t.Error("Expected nil release after shutdown")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Queued remediation did not abort on shutdown")
+	}
+	if len(th.status().Queue) != 0 {
+		t.Error("Aborted remediation should leave the queue")
+	}
+}
+
+func TestRemediationThrottle_SetConfigReleasesQueue(t *testing.T) {
+	th := newRemediationThrottle(RemediationThrottleConfig{MaxConcurrent: 1}, testutil.NewMockClock())
+	shutdown := make(chan struct{})
+
+	expectGranted(t, acquireAsync(th, 1, "a", shutdown))
+	queued := acquireAsync(th, 1, "b", shutdown)
+	waitQueued(t, th, 1)
+
+	th.setConfig(RemediationThrottleConfig{MaxConcurrent: 2})
+	expectGranted(t, queued)
+
+	if got := newRemediationThrottle(RemediationThrottleConfig{}, testutil.NewMockClock()).status().MaxConcurrent; got != maxConcurrentRemediations {
+		t.Errorf("Expected default max concurrent %d, got %d", maxConcurrentRemediations, got)
+	}
+}
+
+func TestRemediationThrottle_Window(t *testing.T) {
+	t.Setenv("HEALARR_TZ", "UTC")
+	clk := testutil.NewMockClockAt(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC))
+	th := newRemediationThrottle(RemediationThrottleConfig{}, clk)
+	if err := th.setConfig(RemediationThrottleConfig{MaxConcurrent: 5, Window: "01:00-06:00"}); err != nil {
+		t.Fatalf("setConfig error = %v", err)
+	}
+	shutdown := make(chan struct{})
+
+	// Found during the day: waits for the window
+	queued := acquireAsync(th, 1, "a", shutdown)
+	waitQueued(t, th, 1)
+	status := th.status()
+	opens := time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC)
+	if status.Window != "01:00-06:00" || status.WindowOpensAt == nil || !status.WindowOpensAt.Equal(opens) {
+		t.Errorf("Expected the window to open at %s, got %+v", opens, status)
+	}
+
+	clk.Advance(10*time.Hour + 59*time.Minute)
+	expectWaiting(t, queued)
+	clk.Advance(time.Minute)
+	release := expectGranted(t, queued)
+
+	// Inside the window remediations start right away, and the window closing
+	// doesn't interrupt them
+	expectGranted(t, acquireAsync(th, 1, "b", shutdown))
+	if th.status().WindowOpensAt != nil {
+		t.Error("Expected no window_opens_at inside the window")
+	}
+	clk.Advance(5 * time.Hour)
+	release()
+	expectWaiting(t, acquireAsync(th, 1, "c", shutdown))
+}
+
+func TestRemediationThrottle_WindowChange(t *testing.T) {
+	t.Setenv("HEALARR_TZ", "UTC")
+	clk := testutil.NewMockClockAt(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC))
+	th := newRemediationThrottle(RemediationThrottleConfig{}, clk)
+	if err := th.setConfig(RemediationThrottleConfig{Window: "22:00-06:00"}); err != nil {
+		t.Fatalf("setConfig error = %v", err)
+	}
+	shutdown := make(chan struct{})
+
+	queued := acquireAsync(th, 1, "a", shutdown)
+	waitQueued(t, th, 1)
+
+	// An earlier window replaces the pending dispatch
+	if err := th.setConfig(RemediationThrottleConfig{Window: "20:00-06:00"}); err != nil {
+		t.Fatalf("setConfig error = %v", err)
+	}
+	clk.Advance(6 * time.Hour)
+	expectGranted(t, queued)
+
+	// An invalid window is reported and lets remediations run at any time
+	clk.Advance(12 * time.Hour)
+	if err := th.setConfig(RemediationThrottleConfig{Window: "tonight"}); err == nil {
+		t.Error("Expected an error for an invalid window")
+	}
+	expectGranted(t, acquireAsync(th, 1, "b", shutdown))
+	if th.status().Window != "" {
+		t.Errorf("Expected no window, got %q", th.status().Window)
+	}
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/mescon/Healarr/internal/clock"
 	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
@@ -12,22 +15,35 @@ import (
 	"github.com/mescon/Healarr/internal/logger"
 )
 
-// maxConcurrentRemediations limits how many remediations can run simultaneously
-// to avoid overwhelming *arr APIs and download clients
+// maxConcurrentRemediations is the default limit of remediations running
+// simultaneously per *arr instance, to avoid overwhelming *arr APIs and download clients
 const maxConcurrentRemediations = 5
 
-// semaphoreAcquireTimeout is the maximum time to wait for a semaphore slot.
-// This prevents indefinite blocking if all slots are stuck (Issue 5: deadlock prevention).
-// Set to 2 minutes to allow time for HTTP timeouts (30s) plus processing.
-const semaphoreAcquireTimeout = 2 * time.Minute
-
 // RemediatorService handles corruption events by deleting files and triggering searches.
 type RemediatorService struct {
 	eventBus   eventbus.Publisher
 	arrClient  integration.ArrClient
 	pathMapper integration.PathMapper
 	db         *sql.DB
-	semaphore  chan struct{} // limits concurrent remediations
+	throttle   *remediationThrottle // limits concurrent remediations and searches per instance
+	protection *ProtectionService   // files and media that must never be remediated
+	clk        clock.Clock
+	// Detection-only mode during *arr outages
+	breakers           CircuitBreakerSource
+	detectionOnlyAfter time.Duration
+
+	untrackedFileAction string // what to do with files on disk the *arr instance doesn't track
+	quarantineDir       string
+	dataBudget          int64  // monthly remediation data budget in bytes, 0 = unlimited
+	budgetAction        string // "defer" or "approve" for remediations over the budget
+	budgetMu            sync.Mutex
+	budgetDeferredMonth string          // month whose remaining budget a deferred remediation didn't fit in
+	budgetApproved      map[string]bool // corruptions approved over the budget, until they get a slot
+	rolloutMu           sync.Mutex
+	rollout             stagedRollout     // daily remediation limits of new scan paths
+	groups              *PathGroupService // scan path groups whose remediation may be paused
+	inFlightMu          sync.Mutex
+	inFlight            map[string]struct{} // corruptions with a remediation running
 	// Lifecycle management
 	wg         sync.WaitGroup
 	shutdownCh chan struct{}
@@ -37,21 +53,50 @@ type RemediatorService struct {
 
 // NewRemediatorService creates a new RemediatorService with the given dependencies.
 func NewRemediatorService(eb eventbus.Publisher, arr integration.ArrClient, pm integration.PathMapper, db *sql.DB) *RemediatorService {
+	clk := clock.NewRealClock()
 	r := &RemediatorService{
 		eventBus:   eb,
 		arrClient:  arr,
 		pathMapper: pm,
 		db:         db,
-		semaphore:  make(chan struct{}, maxConcurrentRemediations),
+		throttle:   newRemediationThrottle(RemediationThrottleConfig{}, clk),
+		clk:        clk,
+		rollout:    stagedRollout{waiting: make(map[string]bool)},
 		shutdownCh: make(chan struct{}),
 	}
+	if db != nil {
+		r.protection = NewProtectionService(db)
+	}
 	return r
 }
 
+// SetThrottle sets the per-instance concurrency and hourly search limits and
+// the daily remediation window. An invalid window is returned as an error; the
+// limits apply regardless.
+func (r *RemediatorService) SetThrottle(cfg RemediationThrottleConfig) error {
+	return r.throttle.setConfig(cfg)
+}
+
+// QueueStatus returns the remediation throttle state and the remediations
+// waiting for capacity.
+func (r *RemediatorService) QueueStatus() RemediationQueueStatus {
+	return r.throttle.status()
+}
+
 // Start subscribes to corruption and retry events to begin remediation handling.
 func (r *RemediatorService) Start() {
 	r.eventBus.Subscribe(domain.CorruptionDetected, r.handleCorruptionDetected)
 	r.eventBus.Subscribe(domain.RetryScheduled, r.handleRetry)
+
+	if r.breakers != nil && r.detectionOnlyAfter > 0 {
+		r.wg.Add(1)
+		go r.runOutageWatch()
+	}
+	if r.dataBudget > 0 {
+		r.checkDataBudget()
+		r.wg.Add(1)
+		go r.runBudgetWatch()
+	}
 }
 
 // Stop gracefully shuts down the RemediatorService.
@@ -66,6 +111,7 @@ func (r *RemediatorService) Stop() {
 	close(r.shutdownCh)
 	r.mu.Unlock()
 
+	r.throttle.stop()
 	r.wg.Wait()
 	logger.Infof("RemediatorService stopped")
 }
@@ -83,18 +129,32 @@ func (r *RemediatorService) isShuttingDown() bool {
 func (r *RemediatorService) handleRetry(event domain.Event) {
 	corruptionID := event.AggregateID
 
+	release := r.beginRemediation(corruptionID)
+	if release == nil {
+		logger.Infof("Retry for %s: remediation already in progress, skipping", corruptionID)
+		return
+	}
+	if r.isSuperseded(event) {
+		logger.Infof("Retry for %s: already handled by another retry, skipping", corruptionID)
+		release()
+		return
+	}
+
 	// Check if deletion was already completed for this corruption
 	// If so, we skip deletion and go directly to search
 	deletionCompleted, mediaID, metadata := r.checkDeletionCompleted(corruptionID)
 
+	var started bool
 	if deletionCompleted {
 		logger.Infof("Retry for %s: deletion already completed, skipping to search phase", corruptionID)
-		r.retrySearchOnly(event, mediaID, metadata)
-		return
+		started = r.retrySearchOnly(event, mediaID, metadata, release)
+	} else {
+		// Deletion not yet completed - run full remediation flow
+		started = r.remediate(event, release)
+	}
+	if !started {
+		release()
 	}
-
-	// Deletion not yet completed - run full remediation flow
-	r.handleCorruptionDetected(event)
 }
 
 // checkDeletionCompleted checks if a DeletionCompleted event exists for this corruption
@@ -104,18 +164,20 @@ func (r *RemediatorService) checkDeletionCompleted(corruptionID string) (bool, i
 		return false, 0, nil
 	}
 
+	var eventID int64
 	var mediaIDFloat sql.NullFloat64
 	var metadataJSON sql.NullString
 
 	err := r.db.QueryRow(`
 		SELECT
+			id,
 			json_extract(event_data, '$.media_id'),
 			json_extract(event_data, '$.metadata')
 		FROM events
 		WHERE aggregate_id = ? AND event_type = 'DeletionCompleted'
 		ORDER BY created_at DESC
 		LIMIT 1
-	`, corruptionID).Scan(&mediaIDFloat, &metadataJSON)
+	`, corruptionID).Scan(&eventID, &mediaIDFloat, &metadataJSON)
 
 	if err != nil {
 		// No DeletionCompleted event found
@@ -126,20 +188,23 @@ func (r *RemediatorService) checkDeletionCompleted(corruptionID string) (bool, i
 	if mediaIDFloat.Valid {
 		mediaID = int64(mediaIDFloat.Float64)
 	}
-
-	// Parse metadata if available
-	var metadata map[string]interface{}
-	if metadataJSON.Valid && metadataJSON.String != "" {
-		// The metadata is stored as a JSON object, we need to extract it
-		// For simplicity, we'll just return nil and let the search use mediaID
-		metadata = nil
+	metadata := parseEventMetadata(metadataJSON)
+	// The library moved to another instance since the deletion, whose
+	// episode IDs differ as well
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	if reresolvedID, reresolvedMetadata, ok := reresolvedMedia(ctx, r.db, corruptionID, eventID); ok {
+		mediaID, metadata = reresolvedID, reresolvedMetadata
 	}
 
 	return true, mediaID, metadata
 }
 
 // retrySearchOnly triggers a new search without attempting deletion
-func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, metadata map[string]interface{}) {
+// retrySearchOnly searches again for a corruption whose file is already
+// deleted. Returns true when the search was started; it calls release once
+// it is done.
+func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, metadata map[string]interface{}, release func()) bool {
 	corruptionID := event.AggregateID
 
 	// Use type-safe event data parsing
@@ -147,7 +212,7 @@ func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, m
 	if !ok || data.FilePath == "" {
 		logger.Warnf("Invalid retry event data for %s: missing or empty file path", corruptionID)
 		r.publishError(corruptionID, domain.SearchFailed, "missing or empty file_path in retry event")
-		return
+		return false
 	}
 
 	filePath := data.FilePath
@@ -158,7 +223,7 @@ func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, m
 	if err != nil {
 		logger.Errorf("Failed to map path %s during retry: %v", filePath, err)
 		r.publishError(corruptionID, domain.SearchFailed, err.Error())
-		return
+		return false
 	}
 
 	// If we don't have mediaID from previous deletion, look it up
@@ -167,13 +232,14 @@ func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, m
 		if err != nil {
 			logger.Errorf("Failed to find media for retry search %s: %v", arrPath, err)
 			r.publishError(corruptionID, domain.SearchFailed, err.Error())
-			return
+			return false
 		}
 	}
 
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
+		defer release()
 
 		// Check if shutting down before starting work
 		if r.isShuttingDown() {
@@ -181,18 +247,15 @@ func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, m
 			return
 		}
 
-		// Acquire semaphore with timeout to limit concurrent remediations
-		// and prevent indefinite blocking if slots are stuck
-		select {
-		case r.semaphore <- struct{}{}:
-			defer func() { <-r.semaphore }()
-		case <-r.shutdownCh:
-			logger.Debugf("Remediator shutting down while waiting for semaphore for %s", corruptionID)
+		releaseSlot := r.acquireSlot(corruptionID, filePath, pathID)
+		if releaseSlot == nil {
 			return
-		case <-time.After(semaphoreAcquireTimeout):
-			logger.Warnf("Remediator: timeout acquiring semaphore for retry search %s after %v - all slots busy",
-				corruptionID, semaphoreAcquireTimeout)
-			r.publishError(corruptionID, domain.SearchFailed, "remediation queue full, will retry later")
+		}
+		defer releaseSlot()
+
+		// The user may have resolved or ignored it while it waited
+		if r.isSettled(corruptionID) {
+			logger.Infof("Not searching for %s: corruption was settled while queued", corruptionID)
 			return
 		}
 
@@ -234,9 +297,28 @@ func (r *RemediatorService) retrySearchOnly(event domain.Event, mediaID int64, m
 			logger.Errorf("Failed to publish SearchCompleted event after retries: %v", err)
 		}
 	}()
+	return true
 }
 
 func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
+	release := r.beginRemediation(event.AggregateID)
+	if release == nil {
+		logger.Infof("Remediation of %s already in progress, skipping duplicate %s", event.AggregateID, event.EventType)
+		return
+	}
+	if r.isSuperseded(event) {
+		logger.Infof("Corruption %s already moved on from %s, skipping", event.AggregateID, event.EventType)
+		release()
+		return
+	}
+	if !r.remediate(event, release) {
+		release()
+	}
+}
+
+// remediate runs the remediation of a detected corruption. Returns true when
+// a remediation or dry run was started; it calls release once it is done.
+func (r *RemediatorService) remediate(event domain.Event, release func()) bool {
 	corruptionID := event.AggregateID
 
 	// Use type-safe event data parsing
@@ -244,7 +326,7 @@ func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
 	if !ok {
 		logger.Errorf("Missing file_path in event data for corruption %s", corruptionID)
 		r.publishError(corruptionID, domain.DeletionFailed, "missing file_path in event data")
-		return
+		return false
 	}
 
 	// SAFETY CHECK: Verify this is a true corruption, not a recoverable error
@@ -253,7 +335,7 @@ func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
 			data.FilePath, data.CorruptionType)
 		r.publishError(corruptionID, domain.DeletionFailed,
 			"remediation blocked: error type indicates infrastructure issue, not file corruption")
-		return
+		return false
 	}
 
 	logger.Infof("Handling corruption for file: %s", data.FilePath)
@@ -263,7 +345,14 @@ func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
 	if err != nil {
 		logger.Errorf("Failed to map path %s: %v", data.FilePath, err)
 		r.publishError(corruptionID, domain.DeletionFailed, err.Error())
-		return
+		return false
+	}
+
+	// SAFETY CHECK: Protected files and media are reported but never remediated.
+	// No failure event is published so the item isn't picked up for retries.
+	if r.isProtected(data.FilePath, arrPath, data.PathID) {
+		logger.Warnf("PROTECTED: Not remediating %s - file or media item is protected", data.FilePath)
+		return false
 	}
 
 	// Emit queued event
@@ -275,9 +364,15 @@ func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
 		logger.Errorf("Failed to publish RemediationQueued event: %v", err)
 	}
 
+	// Findings the detection isn't sure about wait for the user to confirm them
+	if data.LowConfidence {
+		r.requireConfirmation(corruptionID, data)
+		return false
+	}
+
 	// Check for auto-remediation
 	if !data.AutoRemediate {
-		return
+		return false
 	}
 
 	// Check for global dry-run mode override
@@ -288,16 +383,56 @@ func (r *RemediatorService) handleCorruptionDetected(event domain.Event) {
 		r.wg.Add(1)
 		go func() {
 			defer r.wg.Done()
+			defer release()
 			r.executeDryRun(corruptionID, data.FilePath, arrPath)
 		}()
 	} else {
+		approved := event.GetBoolOr("budget_approved", false)
+		if !r.admitWithinBudget(corruptionID, data, approved) {
+			return false
+		}
+		if approved {
+			r.approveOverBudget(corruptionID)
+		}
 		logger.Infof("Auto-remediation enabled for %s, proceeding immediately", data.FilePath)
 		r.wg.Add(1)
 		go func() {
 			defer r.wg.Done()
+			defer release()
+			if !r.waitForGroup(corruptionID, data.FilePath, data.PathID) {
+				logger.Debugf("Remediator shutting down while %s was held by its path group", corruptionID)
+				return
+			}
+			if !r.waitForRollout(corruptionID, data.FilePath, data.PathID) {
+				logger.Debugf("Remediator shutting down while %s was deferred", corruptionID)
+				return
+			}
 			r.executeRemediation(corruptionID, data.FilePath, arrPath, data.PathID)
 		}()
 	}
+	return true
+}
+
+// isProtected reports whether a file, or the movie or series it belongs to, is
+// protected. The *arr media lookup only happens when the path's instance has
+// protected media.
+func (r *RemediatorService) isProtected(filePath, arrPath string, pathID int64) bool {
+	if r.protection == nil {
+		return false
+	}
+	if r.protection.IsFileProtected(filePath) {
+		return true
+	}
+	instanceID := r.instanceForPath(pathID)
+	if instanceID == 0 || !r.protection.HasMediaProtections(instanceID) {
+		return false
+	}
+	mediaID, err := r.arrClient.FindMediaByPath(arrPath)
+	if err != nil {
+		// Remediation would fail on the same lookup; leave that to executeRemediation
+		return false
+	}
+	return r.protection.IsMediaProtected(instanceID, mediaID)
 }
 
 // isInfrastructureError checks if the error type indicates an infrastructure issue
@@ -306,7 +441,8 @@ func (r *RemediatorService) isInfrastructureError(corruptionType string) bool {
 	switch corruptionType {
 	case integration.ErrorTypeAccessDenied, integration.ErrorTypePathNotFound,
 		integration.ErrorTypeMountLost, integration.ErrorTypeIOError,
-		integration.ErrorTypeTimeout, integration.ErrorTypeInvalidConfig:
+		integration.ErrorTypeTimeout, integration.ErrorTypeInvalidConfig,
+		integration.ErrorTypeToolFailure:
 		return true
 	}
 	return false
@@ -314,16 +450,26 @@ func (r *RemediatorService) isInfrastructureError(corruptionType string) bool {
 
 // executeDryRun simulates the remediation without making changes
 func (r *RemediatorService) executeDryRun(corruptionID, filePath, arrPath string) {
-	mediaID, err := r.arrClient.FindMediaByPath(arrPath)
+	plan, err := r.planDeletion(filePath, arrPath)
 	if err != nil {
-		logger.Infof("[DRY-RUN] Would fail to find media for path %s: %v", arrPath, err)
+		logger.Infof("[DRY-RUN] Would fail to plan deletion for path %s: %v", arrPath, err)
 		return
 	}
+	plan.DryRun = true
+	r.saveDeletionPlan(corruptionID, plan)
+	mediaID := plan.MediaID
+
 	logger.Infof("[DRY-RUN] Would delete file and trigger search:")
 	logger.Infof("[DRY-RUN]   - File: %s", filePath)
 	logger.Infof("[DRY-RUN]   - *arr Path: %s", arrPath)
 	logger.Infof("[DRY-RUN]   - Media ID: %d", mediaID)
-	logger.Infof("[DRY-RUN]   - Action: DELETE file via *arr API, then trigger search")
+	for _, file := range plan.ArrFiles {
+		logger.Infof("[DRY-RUN]   - *arr file record: %d (%s)", file.ID, file.Path)
+	}
+	for _, path := range plan.DiskPaths {
+		logger.Infof("[DRY-RUN]   - Removed from disk: %s", path)
+	}
+	logger.Infof("[DRY-RUN]   - Action: %s, then trigger search", plan.Action)
 	logger.Infof("[DRY-RUN] Set HEALARR_DRY_RUN=false to enable actual remediation")
 
 	// Emit a special event for dry-run completion
@@ -332,15 +478,43 @@ func (r *RemediatorService) executeDryRun(corruptionID, filePath, arrPath string
 		AggregateType: "corruption",
 		EventType:     domain.RemediationQueued, // Stay in queued state
 		EventData: map[string]interface{}{
-			"dry_run":  true,
-			"media_id": mediaID,
-			"message":  "Dry-run mode: remediation simulated but not executed",
+			"dry_run":       true,
+			"media_id":      mediaID,
+			"deletion_plan": plan,
+			"message":       "Dry-run mode: remediation simulated but not executed",
 		},
 	}); err != nil {
 		logger.Errorf("Failed to publish dry-run event: %v", err)
 	}
 }
 
+// acquireSlot waits until the path's *arr instance has capacity for another
+// remediation. Remediations over the limit stay queued (in RemediationQueued
+// state) until a slot frees up. Returns nil if the remediator shut down while waiting.
+func (r *RemediatorService) acquireSlot(corruptionID, filePath string, pathID int64) func() {
+	r.checkDataBudget()
+	instanceID := r.instanceForPath(pathID)
+	release := r.throttle.acquire(instanceID, corruptionID, filePath, r.takeBudgetApproval(corruptionID), r.shutdownCh, func(position int) {
+		logger.Infof("Remediation for %s throttled: queued at position %d for instance %d", filePath, position, instanceID)
+	})
+	if release == nil {
+		logger.Debugf("Remediator shutting down while %s was queued", corruptionID)
+	}
+	return release
+}
+
+// instanceForPath returns the *arr instance a scan path belongs to, or 0 if unknown.
+func (r *RemediatorService) instanceForPath(pathID int64) int64 {
+	if r.db == nil || pathID == 0 {
+		return 0
+	}
+	var instanceID sql.NullInt64
+	if err := r.db.QueryRow(`SELECT arr_instance_id FROM scan_paths WHERE id = ?`, pathID).Scan(&instanceID); err != nil {
+		return 0
+	}
+	return instanceID.Int64
+}
+
 // executeRemediation performs the actual deletion and search trigger
 func (r *RemediatorService) executeRemediation(corruptionID, filePath, arrPath string, pathID int64) {
 	// Check if shutting down before starting work
@@ -349,48 +523,76 @@ func (r *RemediatorService) executeRemediation(corruptionID, filePath, arrPath s
 		return
 	}
 
-	// Acquire semaphore with timeout to limit concurrent remediations
-	// and prevent indefinite blocking if slots are stuck
-	select {
-	case r.semaphore <- struct{}{}:
-		defer func() { <-r.semaphore }()
-	case <-r.shutdownCh:
-		logger.Debugf("Remediator shutting down while waiting for semaphore for %s", corruptionID)
+	// Plan the deletion before waiting for a slot, which can take a while
+	planned, err := r.planDeletion(filePath, arrPath)
+	if err != nil {
+		logger.Errorf("Failed to plan deletion for path %s: %v", arrPath, err)
+		r.publishDeletionFailed(corruptionID, filePath, err)
 		return
-	case <-time.After(semaphoreAcquireTimeout):
-		logger.Warnf("Remediator: timeout acquiring semaphore for %s after %v - all slots busy",
-			corruptionID, semaphoreAcquireTimeout)
-		r.publishError(corruptionID, domain.DeletionFailed, "remediation queue full, will retry later")
+	}
+	r.saveDeletionPlan(corruptionID, planned)
+
+	release := r.acquireSlot(corruptionID, filePath, pathID)
+	if release == nil {
 		return
 	}
+	defer release()
 
-	// Find media first - validates we can proceed before publishing DeletionStarted
-	mediaID, err := r.arrClient.FindMediaByPath(arrPath)
-	if err != nil {
-		logger.Errorf("Failed to find media for path %s: %v", arrPath, err)
-		r.publishError(corruptionID, domain.DeletionFailed, err.Error())
+	// The user may have resolved or ignored it while it waited
+	if r.isSettled(corruptionID) {
+		logger.Infof("Not deleting %s: corruption was settled while queued", filePath)
 		return
 	}
 
+	// Only delete what was planned - validates we can proceed before publishing DeletionStarted
+	plan := r.checkDeletionPlan(corruptionID, filePath, arrPath, planned)
+	if plan == nil {
+		return
+	}
+	r.saveDeletionPlan(corruptionID, plan)
+	mediaID := plan.MediaID
+
+	// Re-check protection right before deleting: it may have been added while queued
+	if r.protection != nil {
+		if r.protection.IsFileProtected(filePath) {
+			logger.Warnf("PROTECTED: Not deleting %s - file was protected while queued", filePath)
+			return
+		}
+		if instanceID := r.instanceForPath(pathID); instanceID != 0 && r.protection.IsMediaProtected(instanceID, mediaID) {
+			logger.Warnf("PROTECTED: Not deleting %s - media %d is protected", filePath, mediaID)
+			return
+		}
+	}
+
 	// Publish deletion started - now that we've validated we can proceed
 	if err := r.eventBus.Publish(domain.Event{
 		AggregateID:   corruptionID,
 		AggregateType: "corruption",
 		EventType:     domain.DeletionStarted,
 		EventData: map[string]interface{}{
-			"file_path": filePath,
-			"arr_path":  arrPath,
-			"media_id":  mediaID,
+			"file_path":     filePath,
+			"arr_path":      arrPath,
+			"media_id":      mediaID,
+			"deletion_plan": plan,
 		},
 	}); err != nil {
 		logger.Errorf("Failed to publish DeletionStarted event: %v", err)
 	}
 
+	// Remember the size for bandwidth accounting before the file is gone
+	var fileSize int64 = -1
+	if info, statErr := os.Stat(filePath); statErr == nil {
+		fileSize = info.Size()
+	}
+
 	// Delete file
 	metadata, err := r.arrClient.DeleteFile(mediaID, arrPath)
+	if err != nil {
+		metadata, err = r.removeUntrackedFile(err, filePath, arrPath, mediaID)
+	}
 	if err != nil {
 		logger.Errorf("Failed to delete file %s: %v", arrPath, err)
-		r.publishError(corruptionID, domain.DeletionFailed, err.Error())
+		r.publishDeletionFailed(corruptionID, filePath, err)
 		return
 	}
 
@@ -399,14 +601,18 @@ func (r *RemediatorService) executeRemediation(corruptionID, filePath, arrPath s
 	// The retry mechanism (via MonitorService) will handle SearchFailed if search fails.
 
 	// Publish deletion completed - critical event, use retry
+	deletionData := map[string]interface{}{
+		"media_id": mediaID,
+		"metadata": metadata,
+	}
+	if fileSize >= 0 {
+		deletionData["file_size"] = fileSize
+	}
 	if err := r.eventBus.PublishWithRetry(domain.Event{
 		AggregateID:   corruptionID,
 		AggregateType: "corruption",
 		EventType:     domain.DeletionCompleted,
-		EventData: map[string]interface{}{
-			"media_id": mediaID,
-			"metadata": metadata,
-		},
+		EventData:     deletionData,
 	}); err != nil {
 		logger.Errorf("Failed to publish DeletionCompleted event after retries: %v", err)
 	}
@@ -524,6 +730,16 @@ func (r *RemediatorService) buildSearchEventData(filePath, arrPath string, media
 	if details.EpisodeTitle != "" {
 		eventData["episode_title"] = details.EpisodeTitle
 	}
+	// External IDs let the remediation export be imported into *arr lists
+	if details.TmdbID > 0 {
+		eventData["tmdb_id"] = details.TmdbID
+	}
+	if details.TvdbID > 0 {
+		eventData["tvdb_id"] = details.TvdbID
+	}
+	if details.ImdbID != "" {
+		eventData["imdb_id"] = details.ImdbID
+	}
 	return eventData
 }
 
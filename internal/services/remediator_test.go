@@ -651,7 +651,7 @@ func TestRemediatorService_RetrySearchOnly(t *testing.T) {
 			EventData:     map[string]interface{}{}, // Missing file_path
 		}
 
-		remediator.retrySearchOnly(event, 0, nil)
+		remediator.retrySearchOnly(event, 0, nil, func() {})
 
 		// Wait for async processing
 		time.Sleep(100 * time.Millisecond)
@@ -688,7 +688,7 @@ func TestRemediatorService_RetrySearchOnly(t *testing.T) {
 			},
 		}
 
-		remediator.retrySearchOnly(event, 0, nil)
+		remediator.retrySearchOnly(event, 0, nil, func() {})
 
 		// Wait for async processing
 		time.Sleep(100 * time.Millisecond)
@@ -725,7 +725,7 @@ func TestRemediatorService_RetrySearchOnly(t *testing.T) {
 		}
 
 		// Pass mediaID=0 to trigger FindMediaByPath lookup
-		remediator.retrySearchOnly(event, 0, nil)
+		remediator.retrySearchOnly(event, 0, nil, func() {})
 
 		// Wait for async processing
 		time.Sleep(200 * time.Millisecond)
@@ -762,7 +762,7 @@ func TestRemediatorService_RetrySearchOnly(t *testing.T) {
 		}
 
 		// Pass mediaID to skip FindMediaByPath
-		remediator.retrySearchOnly(event, 456, nil)
+		remediator.retrySearchOnly(event, 456, nil, func() {})
 
 		// Wait for async processing
 		time.Sleep(200 * time.Millisecond)
@@ -810,7 +810,7 @@ func TestRemediatorService_RetrySearchOnly(t *testing.T) {
 		metadata := map[string]interface{}{
 			"episode_ids": []interface{}{float64(101), float64(102)},
 		}
-		remediator.retrySearchOnly(event, 789, metadata)
+		remediator.retrySearchOnly(event, 789, metadata, func() {})
 
 		// Wait for async processing
 		time.Sleep(200 * time.Millisecond)
@@ -1651,9 +1651,12 @@ func TestRemediatorService_ExecuteRemediation_ShutdownWhileWaitingForSemaphore(t
 	mockEventBus := testutil.NewMockEventBus()
 	mockClient := &testutil.MockArrClient{
 		FindMediaByPathFunc: func(path string) (int64, error) {
+			return 123, nil
+		},
+		DeleteFileFunc: func(mediaID int64, path string) (map[string]interface{}, error) {
 			// Slow response to keep semaphore busy
 			time.Sleep(5 * time.Second)
-			return 123, nil
+			return nil, nil
 		},
 	}
 	remediator := NewRemediatorService(mockEventBus, mockClient, nil, db)
@@ -1758,3 +1761,39 @@ func TestRemediatorService_ExecuteDryRun_PublishesQueuedEvent(t *testing.T) {
 		}
 	}
 }
+
+func TestRemediatorService_ExecuteRemediation_TestModeFaults(t *testing.T) {
+	faults := integration.NewFaultInjector()
+	file := faults.AddSyntheticFile("/media/movies/test.mkv", "/movies/test.mkv")
+	mockArrClient := &testutil.MockArrClient{}
+	arrClient := faults.WrapArrClient(mockArrClient)
+
+	// Forced delete failure fails the remediation like a real *arr error would
+	if err := faults.SetFailure(integration.StageDelete, "disk full", 1); err != nil {
+		t.Fatalf("SetFailure failed: %v", err)
+	}
+	mockEventBus := testutil.NewMockEventBus()
+	remediator := NewRemediatorService(mockEventBus, arrClient, &testutil.MockPathMapper{}, nil)
+	remediator.executeRemediation("synthetic-1", file.LocalPath, file.ArrPath, 0)
+
+	failed := mockEventBus.GetEvents(domain.DeletionFailed)
+	if len(failed) != 1 || failed[0].EventData["error"] != "disk full" {
+		t.Fatalf("Expected DeletionFailed with the injected error, got %v", failed)
+	}
+
+	// Without failures the synthetic file runs through delete and search on fake responses
+	mockEventBus = testutil.NewMockEventBus()
+	remediator = NewRemediatorService(mockEventBus, arrClient, &testutil.MockPathMapper{}, nil)
+	remediator.executeRemediation("synthetic-2", file.LocalPath, file.ArrPath, 0)
+
+	completed := mockEventBus.GetEvents(domain.SearchCompleted)
+	if len(completed) != 1 {
+		t.Fatalf("Expected SearchCompleted, got events %v", mockEventBus.GetAllEvents())
+	}
+	if completed[0].EventData["media_id"] != file.MediaID {
+		t.Errorf("Expected fake media ID %d, got %v", file.MediaID, completed[0].EventData["media_id"])
+	}
+	if len(mockArrClient.Calls) != 0 {
+		t.Errorf("Synthetic file must not reach the *arr client, got %v", mockArrClient.Calls)
+	}
+}
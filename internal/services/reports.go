@@ -0,0 +1,418 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/mescon/Healarr/internal/clock"
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+const (
+	// reportPeriod is the time span a report covers, ending when it is generated.
+	reportPeriod = 7 * 24 * time.Hour
+	// reportTrendWeeks is how many weeks the health score trend goes back, including the report week.
+	reportTrendWeeks = 4
+	// reportTopPaths is how many problem paths a report lists.
+	reportTopPaths = 5
+	// reportRetention is how many reports are kept; older ones are deleted when a new one is stored.
+	reportRetention = 52
+	// reportQueryTimeout bounds the queries that compile a report.
+	reportQueryTimeout = 30 * time.Second
+	// reportTimeFormat matches how timestamps are stored in the database.
+	reportTimeFormat = "2006-01-02 15:04:05"
+)
+
+// ErrReportNotFound is returned when a report doesn't exist.
+var ErrReportNotFound = errors.New("report not found")
+
+// Report is a summary of corruption activity over one week.
+type Report struct {
+	ID          int64     `json:"id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	NewCorruptions int `json:"new_corruptions"`
+	Resolved       int `json:"resolved"`
+	// Failures are corruptions that ended without a replacement in the period
+	// (MaxRetriesReached or SearchExhausted).
+	Failures     int `json:"failures"`
+	ScanFailures int `json:"scan_failures"`
+	// Unresolved is the number of corruptions still open at the end of the period.
+	Unresolved int `json:"unresolved"`
+
+	TopPaths    []ReportPath        `json:"top_paths"`
+	HealthTrend []HealthScoreWeekly `json:"health_trend"` // oldest week first, the report week last
+}
+
+// ReportPath is a scan path with the corruptions detected in it during the report period.
+type ReportPath struct {
+	PathID      int64  `json:"path_id"`
+	LocalPath   string `json:"local_path"`
+	Corruptions int    `json:"corruptions"`
+}
+
+// HealthScoreWeekly is the share of healthy files among those scanned in one week.
+type HealthScoreWeekly struct {
+	WeekStart        time.Time `json:"week_start"`
+	FilesScanned     int       `json:"files_scanned"`
+	CorruptionsFound int       `json:"corruptions_found"`
+	Score            *float64  `json:"score"` // 0-100, nil when nothing was scanned
+}
+
+// ReportService compiles weekly summary reports, stores them and, on a cron
+// schedule, publishes them as ReportGenerated events for the notifier.
+type ReportService struct {
+	db       *sql.DB
+	eventBus eventbus.Publisher
+	clk      clock.Clock
+	cron     *cron.Cron
+	mu       sync.Mutex // serializes report generation
+}
+
+// NewReportService creates a new ReportService.
+func NewReportService(db *sql.DB, eb eventbus.Publisher) *ReportService {
+	return &ReportService{
+		db:       db,
+		eventBus: eb,
+		clk:      clock.NewRealClock(),
+	}
+}
+
+// Start generates and delivers a report on the given cron schedule, interpreted
+// in the timezone of a CRON_TZ= prefix or else the scheduler's. An empty
+// schedule leaves scheduled reports off.
+func (r *ReportService) Start(schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+	spec, err := ParseSchedule(schedule, domain.ScheduleTiming{})
+	if err != nil {
+		return fmt.Errorf("invalid report schedule %q: %w", schedule, err)
+	}
+	r.cron = cron.New(cron.WithLocation(cronLocation()))
+	r.cron.Schedule(spec, cron.FuncJob(r.runScheduled))
+	r.cron.Start()
+	return nil
+}
+
+// Stop stops the report schedule and waits for a running report to finish.
+func (r *ReportService) Stop() {
+	if r.cron != nil {
+		<-r.cron.Stop().Done()
+	}
+}
+
+func (r *ReportService) runScheduled() {
+	if _, err := r.GenerateAndDeliver(context.Background()); err != nil {
+		logger.Errorf("Failed to generate scheduled report: %v", err)
+	}
+}
+
+// GenerateAndDeliver generates a report for the week up to now, stores it and
+// publishes it as a ReportGenerated event.
+func (r *ReportService) GenerateAndDeliver(ctx context.Context) (*Report, error) {
+	report, err := r.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateType: "report",
+		AggregateID:   fmt.Sprintf("report-%d", report.ID),
+		EventType:     domain.ReportGenerated,
+		EventData: map[string]interface{}{
+			"report_id":       report.ID,
+			"period_start":    report.PeriodStart.Format(time.RFC3339),
+			"period_end":      report.PeriodEnd.Format(time.RFC3339),
+			"new_corruptions": report.NewCorruptions,
+			"resolved":        report.Resolved,
+			"failures":        report.Failures,
+			"markdown":        report.Markdown(),
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish ReportGenerated event: %v", err)
+	}
+	logger.Infof("Generated weekly report %d: %d new, %d resolved, %d failed",
+		report.ID, report.NewCorruptions, report.Resolved, report.Failures)
+	return report, nil
+}
+
+// Generate compiles and stores a report for the week up to now.
+func (r *ReportService) Generate(ctx context.Context) (*Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, reportQueryTimeout)
+	defer cancel()
+
+	end := r.clk.Now().UTC().Truncate(time.Second)
+	report, err := r.compile(ctx, end.Add(-reportPeriod), end)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+	result, err := db.ExecWithRetry(r.db, `
+		INSERT INTO reports (period_start, period_end, data, created_at) VALUES (?, ?, ?, ?)
+	`, report.PeriodStart.Format(reportTimeFormat), report.PeriodEnd.Format(reportTimeFormat), string(data), end.Format(reportTimeFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+	if report.ID, err = result.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.ExecWithRetry(r.db, `
+		DELETE FROM reports WHERE id NOT IN (SELECT id FROM reports ORDER BY id DESC LIMIT ?)
+	`, reportRetention); err != nil {
+		logger.Warnf("Failed to prune old reports: %v", err)
+	}
+	return report, nil
+}
+
+// compile gathers the figures of a report over [start, end).
+func (r *ReportService) compile(ctx context.Context, start, end time.Time) (*Report, error) {
+	report := &Report{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		GeneratedAt: end,
+		TopPaths:    []ReportPath{},
+	}
+	from, to := start.Format(reportTimeFormat), end.Format(reportTimeFormat)
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(DISTINCT CASE WHEN event_type = 'CorruptionDetected' THEN aggregate_id END),
+			COUNT(DISTINCT CASE WHEN event_type = 'VerificationSuccess'
+				AND json_extract(event_data, '$.reverification_of') IS NULL THEN aggregate_id END),
+			COUNT(DISTINCT CASE WHEN event_type IN ('MaxRetriesReached', 'SearchExhausted') THEN aggregate_id END)
+		FROM events
+		WHERE aggregate_type = 'corruption' AND created_at >= ? AND created_at < ?
+	`, from, to).Scan(&report.NewCorruptions, &report.Resolved, &report.Failures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count corruption events: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM scans WHERE status = 'failed' AND started_at >= ? AND started_at < ?
+	`, from, to).Scan(&report.ScanFailures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scan failures: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM corruption_summary
+		WHERE current_state NOT IN ('VerificationSuccess', 'MaxRetriesReached', 'CorruptionIgnored')
+	`).Scan(&report.Unresolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unresolved corruptions: %w", err)
+	}
+
+	if report.TopPaths, err = r.topPaths(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.HealthTrend, err = r.healthTrend(ctx, end); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// topPaths returns the scan paths with the most corruptions detected in the period.
+func (r *ReportService) topPaths(ctx context.Context, from, to string) ([]ReportPath, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT sp.id, sp.local_path, COUNT(DISTINCT e.aggregate_id) AS corruptions
+		FROM events e
+		JOIN scan_paths sp ON sp.id = json_extract(e.event_data, '$.path_id')
+		WHERE e.event_type = 'CorruptionDetected' AND e.created_at >= ? AND e.created_at < ?
+		GROUP BY sp.id
+		ORDER BY corruptions DESC, sp.local_path ASC
+		LIMIT ?
+	`, from, to, reportTopPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top problem paths: %w", err)
+	}
+	defer rows.Close()
+
+	paths := []ReportPath{}
+	for rows.Next() {
+		var p ReportPath
+		if err := rows.Scan(&p.PathID, &p.LocalPath, &p.Corruptions); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, rows.Err()
+}
+
+// healthTrend returns the health score of each of the last reportTrendWeeks
+// weeks ending at end, from the scans completed in each week.
+func (r *ReportService) healthTrend(ctx context.Context, end time.Time) ([]HealthScoreWeekly, error) {
+	trend := make([]HealthScoreWeekly, 0, reportTrendWeeks)
+	for i := reportTrendWeeks - 1; i >= 0; i-- {
+		weekEnd := end.Add(-time.Duration(i) * reportPeriod)
+		week := HealthScoreWeekly{WeekStart: weekEnd.Add(-reportPeriod)}
+		err := r.db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(files_scanned), 0), COALESCE(SUM(corruptions_found), 0)
+			FROM scans
+			WHERE status = 'completed' AND completed_at >= ? AND completed_at < ?
+		`, week.WeekStart.Format(reportTimeFormat), weekEnd.Format(reportTimeFormat)).Scan(&week.FilesScanned, &week.CorruptionsFound)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute health score: %w", err)
+		}
+		if week.FilesScanned > 0 {
+			score := 100 * float64(week.FilesScanned-week.CorruptionsFound) / float64(week.FilesScanned)
+			if score < 0 {
+				score = 0
+			}
+			week.Score = &score
+		}
+		trend = append(trend, week)
+	}
+	return trend, nil
+}
+
+// List returns the stored reports, newest first, up to limit.
+func (r *ReportService) List(ctx context.Context, limit int) ([]Report, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, data FROM reports ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []Report{}
+	for rows.Next() {
+		var id int64
+		var data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		var report Report
+		if err := json.Unmarshal([]byte(data), &report); err != nil {
+			logger.Warnf("Skipping unreadable report %d: %v", id, err)
+			continue
+		}
+		report.ID = id
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// Get returns a stored report.
+func (r *ReportService) Get(ctx context.Context, id int64) (*Report, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM reports WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %d: %w", id, err)
+	}
+	report.ID = id
+	return &report, nil
+}
+
+// Markdown renders the report as Markdown, as sent to notification channels.
+func (rep *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Healarr weekly report\n\n%s – %s\n\n",
+		rep.PeriodStart.Format("2006-01-02"), rep.PeriodEnd.Format("2006-01-02"))
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- New corruptions: %d\n", rep.NewCorruptions)
+	fmt.Fprintf(&b, "- Resolved: %d\n", rep.Resolved)
+	fmt.Fprintf(&b, "- Failed remediations: %d\n", rep.Failures)
+	fmt.Fprintf(&b, "- Failed scans: %d\n", rep.ScanFailures)
+	fmt.Fprintf(&b, "- Still unresolved: %d\n", rep.Unresolved)
+
+	b.WriteString("\n## Top problem paths\n\n")
+	if len(rep.TopPaths) == 0 {
+		b.WriteString("No corruptions detected this week.\n")
+	}
+	for _, p := range rep.TopPaths {
+		fmt.Fprintf(&b, "- %s: %d\n", p.LocalPath, p.Corruptions)
+	}
+
+	b.WriteString("\n## Health score trend\n\n")
+	for _, w := range rep.HealthTrend {
+		fmt.Fprintf(&b, "- Week of %s: %s\n", w.WeekStart.Format("2006-01-02"), w.ScoreText())
+	}
+	return b.String()
+}
+
+// ScoreText renders the score with the number of files it is based on.
+func (w HealthScoreWeekly) ScoreText() string {
+	if w.Score == nil {
+		return "no scans"
+	}
+	return fmt.Sprintf("%.1f%% (%d files scanned)", *w.Score, w.FilesScanned)
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Healarr weekly report {{date .PeriodStart}} – {{date .PeriodEnd}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; color: #1e293b; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #e2e8f0; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Healarr weekly report</h1>
+<p>{{date .PeriodStart}} – {{date .PeriodEnd}}</p>
+<h2>Summary</h2>
+<table>
+<tr><td>New corruptions</td><td class="num">{{.NewCorruptions}}</td></tr>
+<tr><td>Resolved</td><td class="num">{{.Resolved}}</td></tr>
+<tr><td>Failed remediations</td><td class="num">{{.Failures}}</td></tr>
+<tr><td>Failed scans</td><td class="num">{{.ScanFailures}}</td></tr>
+<tr><td>Still unresolved</td><td class="num">{{.Unresolved}}</td></tr>
+</table>
+<h2>Top problem paths</h2>
+{{if .TopPaths}}<table>
+<tr><th>Path</th><th>Corruptions</th></tr>
+{{range .TopPaths}}<tr><td>{{.LocalPath}}</td><td class="num">{{.Corruptions}}</td></tr>
+{{end}}</table>{{else}}<p>No corruptions detected this week.</p>{{end}}
+<h2>Health score trend</h2>
+<table>
+<tr><th>Week of</th><th>Health score</th></tr>
+{{range .HealthTrend}}<tr><td>{{date .WeekStart}}</td><td class="num">{{.ScoreText}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HTML renders the report as a standalone HTML page.
+func (rep *Report) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := reportHTMLTemplate.Execute(&buf, rep); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestReportService_GenerateAndDeliver(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 13, 8, 0, 0, 0, time.UTC)
+	ts := func(at time.Time) string { return at.Format("2006-01-02 15:04:05") }
+
+	if _, err := db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path) VALUES (1, '/media/movies', '/movies'), (2, '/media/tv', '/tv')`); err != nil {
+		t.Fatalf("Failed to seed scan paths: %v", err)
+	}
+	inWeek := now.Add(-48 * time.Hour)
+	seedBudgetEvent(t, db, "a", domain.CorruptionDetected, map[string]interface{}{"path_id": 2}, inWeek)
+	seedBudgetEvent(t, db, "b", domain.CorruptionDetected, map[string]interface{}{"path_id": 2}, inWeek)
+	seedBudgetEvent(t, db, "c", domain.CorruptionDetected, map[string]interface{}{"path_id": 1}, inWeek)
+	seedBudgetEvent(t, db, "a", domain.VerificationSuccess, map[string]interface{}{}, inWeek.Add(time.Hour))
+	seedBudgetEvent(t, db, "b", domain.MaxRetriesReached, map[string]interface{}{}, inWeek.Add(time.Hour))
+	// Before the report period: not counted
+	seedBudgetEvent(t, db, "old", domain.CorruptionDetected, map[string]interface{}{"path_id": 1}, now.AddDate(0, 0, -10))
+
+	if _, err := db.Exec(`
+		INSERT OR REPLACE INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at)
+		VALUES ('c', '/media/movies/c.mkv', 'SearchCompleted', ?, ?), ('a', '/media/tv/a.mkv', 'VerificationSuccess', ?, ?)
+	`, ts(inWeek), ts(inWeek), ts(inWeek), ts(inWeek)); err != nil {
+		t.Fatalf("Failed to seed corruption summary: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO scans (path, status, files_scanned, corruptions_found, started_at, completed_at) VALUES
+			('/media/tv', 'completed', 100, 2, ?, ?),
+			('/media/movies', 'failed', 0, 0, ?, NULL),
+			('/media/movies', 'completed', 50, 0, ?, ?)
+	`, ts(inWeek), ts(inWeek), ts(inWeek), ts(now.AddDate(0, 0, -15)), ts(now.AddDate(0, 0, -15))); err != nil {
+		t.Fatalf("Failed to seed scans: %v", err)
+	}
+
+	bus := testutil.NewMockEventBus()
+	r := NewReportService(db, bus)
+	r.clk = testutil.NewMockClockAt(now)
+
+	report, err := r.GenerateAndDeliver(context.Background())
+	if err != nil {
+		t.Fatalf("GenerateAndDeliver() error = %v", err)
+	}
+	// Unresolved: c and the corruption detected before the period
+	if report.NewCorruptions != 3 || report.Resolved != 1 || report.Failures != 1 || report.ScanFailures != 1 || report.Unresolved != 2 {
+		t.Errorf("Unexpected counts: %+v", report)
+	}
+	if len(report.TopPaths) != 2 || report.TopPaths[0].LocalPath != "/media/tv" || report.TopPaths[0].Corruptions != 2 {
+		t.Errorf("Unexpected top paths: %+v", report.TopPaths)
+	}
+	if len(report.HealthTrend) != reportTrendWeeks {
+		t.Fatalf("Expected %d trend weeks, got %d", reportTrendWeeks, len(report.HealthTrend))
+	}
+	if last := report.HealthTrend[reportTrendWeeks-1]; last.Score == nil || *last.Score != 98 {
+		t.Errorf("Expected a score of 98 for the report week, got %+v", last)
+	}
+	if week := report.HealthTrend[2]; week.Score != nil {
+		t.Errorf("Expected no score for a week without scans, got %v", *week.Score)
+	}
+
+	events := bus.GetEvents(domain.ReportGenerated)
+	if len(events) != 1 {
+		t.Fatalf("Expected one ReportGenerated event, got %d", len(events))
+	}
+	markdown, _ := events[0].GetString("markdown")
+	for _, want := range []string{"/media/tv", "98.0%"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, markdown)
+		}
+	}
+
+	stored, err := r.Get(context.Background(), report.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.NewCorruptions != 3 || len(stored.TopPaths) != 2 {
+		t.Errorf("Stored report differs: %+v", stored)
+	}
+	page, err := stored.HTML()
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	if !strings.Contains(page, "/media/movies") {
+		t.Errorf("HTML missing top path:\n%s", page)
+	}
+
+	if _, err := r.Get(context.Background(), report.ID+1); !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("Expected ErrReportNotFound, got %v", err)
+	}
+	list, err := r.List(context.Background(), 10)
+	if err != nil || len(list) != 1 {
+		t.Errorf("List() = %d reports, err %v", len(list), err)
+	}
+}
+
+func TestReportService_Start(t *testing.T) {
+	r := NewReportService(nil, testutil.NewMockEventBus())
+	if err := r.Start(""); err != nil {
+		t.Errorf("Start(\"\") error = %v", err)
+	}
+	if err := r.Start("not a schedule"); err == nil {
+		t.Error("Expected an error for an invalid schedule")
+	}
+	if err := r.Start("0 8 * * 1"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	r.Stop()
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// researchFailedReason is the SearchExhausted reason of a scheduled re-search
+// that found nothing either.
+const researchFailedReason = "research_failed"
+
+// researchableReasons are the SearchExhausted reasons a later search can fix:
+// the *arr found no replacement. A corrupt replacement or a missing *arr
+// instance needs the user.
+var researchableReasons = map[string]bool{
+	"item_vanished":      true,
+	researchFailedReason: true,
+}
+
+// researchPolicy is a scan path's re-search schedule after SearchExhausted.
+type researchPolicy struct {
+	interval time.Duration
+	period   time.Duration
+}
+
+// scheduleResearch schedules the next search for a corruption whose search
+// was exhausted, if its scan path searches again and the re-search period
+// isn't over. Reports whether a search was scheduled.
+func (m *MonitorService) scheduleResearch(event domain.Event) bool {
+	corruptionID := event.AggregateID
+	reason, _ := event.GetString("reason")
+	if !researchableReasons[reason] || m.db == nil {
+		return false
+	}
+
+	filePath, pathID, err := m.getCorruptionContext(corruptionID)
+	if err != nil {
+		logger.Debugf("Failed to get context of %s for re-search: %v", corruptionID, err)
+		return false
+	}
+	policy, ok := m.researchPolicy(pathID)
+	if !ok {
+		return false
+	}
+
+	now := m.clk.Now().UTC()
+	startedAt, attempt := m.lastResearch(corruptionID)
+	if startedAt.IsZero() {
+		startedAt = now
+	}
+	until := startedAt.Add(policy.period)
+	next := now.Add(policy.interval)
+	if next.After(until) {
+		logger.Infof("Re-search period of %s ended %s after %d searches, giving up (file: %s)",
+			corruptionID, until.Format(time.RFC3339), attempt, filePath)
+		return false
+	}
+	attempt++
+
+	if err := m.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.ScheduledResearch,
+		EventData: map[string]interface{}{
+			"file_path":      filePath,
+			"path_id":        pathID,
+			"attempt":        attempt,
+			"next_search_at": next.Format(time.RFC3339),
+			"started_at":     startedAt.Format(time.RFC3339),
+			"until":          until.Format(time.RFC3339),
+			"reason":         reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish ScheduledResearch event for %s: %v", corruptionID, err)
+		return false
+	}
+
+	m.armResearch(corruptionID, filePath, pathID, attempt, policy.interval)
+	return true
+}
+
+// armResearch publishes the RetryScheduled of a scheduled re-search after
+// delay, unless the corruption moved on in the meantime, e.g. the user
+// retried or ignored it.
+func (m *MonitorService) armResearch(corruptionID, filePath string, pathID int64, attempt int, delay time.Duration) {
+	m.scheduleTimer(corruptionID, delay, func() {
+		if state := m.currentState(corruptionID); state != domain.ScheduledResearch {
+			logger.Debugf("Skipping re-search of %s: corruption is now %s", corruptionID, state)
+			return
+		}
+		logger.Infof("Searching again for %s (re-search %d, file: %s)", corruptionID, attempt, filePath)
+		if err := m.eventBus.Publish(domain.Event{
+			AggregateID:   corruptionID,
+			AggregateType: "corruption",
+			EventType:     domain.RetryScheduled,
+			EventData: map[string]interface{}{
+				"file_path":          filePath,
+				"path_id":            pathID,
+				"auto_remediate":     true,
+				"scheduled_research": true,
+				"research_attempt":   attempt,
+			},
+		}); err != nil {
+			logger.Errorf("Failed to publish RetryScheduled event for %s: %v", corruptionID, err)
+		}
+	})
+}
+
+// researchAttemptFailed handles a failure during a scheduled re-search: the
+// search is exhausted again, which schedules the next one. Reports whether
+// the failure belonged to a re-search.
+func (m *MonitorService) researchAttemptFailed(event domain.Event) bool {
+	if m.db == nil {
+		return false
+	}
+	var scheduled sql.NullBool
+	if err := m.db.QueryRow(`
+		SELECT json_extract(event_data, '$.scheduled_research') FROM events
+		WHERE aggregate_id = ? AND event_type = ?
+		ORDER BY id DESC LIMIT 1
+	`, event.AggregateID, domain.RetryScheduled).Scan(&scheduled); err != nil || !scheduled.Bool {
+		return false
+	}
+
+	filePath, pathID, _ := m.getCorruptionContext(event.AggregateID)
+	errMsg, _ := event.GetString("error")
+	if errMsg == "" {
+		errMsg = string(event.EventType)
+	}
+	if err := m.eventBus.Publish(domain.Event{
+		AggregateID:   event.AggregateID,
+		AggregateType: "corruption",
+		EventType:     domain.SearchExhausted,
+		EventData: map[string]interface{}{
+			"file_path": filePath,
+			"path_id":   pathID,
+			"reason":    researchFailedReason,
+			"error":     errMsg,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish SearchExhausted event for %s: %v", event.AggregateID, err)
+	}
+	return true
+}
+
+// restoreResearch re-arms the timers of re-searches scheduled before a
+// restart. Re-searches that are overdue run right away.
+func (m *MonitorService) restoreResearch() {
+	if m.db == nil {
+		return
+	}
+	rows, err := m.db.Query(`
+		SELECT cs.corruption_id,
+			json_extract(e.event_data, '$.file_path'),
+			COALESCE(json_extract(e.event_data, '$.path_id'), 0),
+			COALESCE(json_extract(e.event_data, '$.attempt'), 1),
+			json_extract(e.event_data, '$.next_search_at')
+		FROM corruption_summary cs
+		JOIN events e ON e.id = (
+			SELECT MAX(id) FROM events
+			WHERE aggregate_id = cs.corruption_id AND event_type = ?
+		)
+		WHERE cs.current_state = ?
+	`, domain.ScheduledResearch, domain.ScheduledResearch)
+	if err != nil {
+		logger.Debugf("Failed to load scheduled re-searches: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	now := m.clk.Now()
+	restored := 0
+	for rows.Next() {
+		var corruptionID, nextSearchAt string
+		var filePath sql.NullString
+		var pathID int64
+		var attempt int
+		if err := rows.Scan(&corruptionID, &filePath, &pathID, &attempt, &nextSearchAt); err != nil {
+			logger.Debugf("Failed to read scheduled re-search: %v", err)
+			continue
+		}
+		next, err := time.Parse(time.RFC3339, nextSearchAt)
+		if err != nil {
+			logger.Debugf("Invalid next_search_at %q of %s: %v", nextSearchAt, corruptionID, err)
+			continue
+		}
+		m.armResearch(corruptionID, filePath.String, pathID, attempt, max(0, next.Sub(now)))
+		restored++
+	}
+	if err := rows.Err(); err != nil {
+		logger.Debugf("Error iterating scheduled re-searches: %v", err)
+	}
+	if restored > 0 {
+		logger.Infof("Restored %d scheduled re-searches", restored)
+	}
+}
+
+// researchPolicy loads the re-search schedule of a scan path. ok is false when
+// the path doesn't search again.
+func (m *MonitorService) researchPolicy(pathID int64) (researchPolicy, bool) {
+	var intervalDays, periodDays int
+	if err := m.db.QueryRow(`
+		SELECT research_interval_days, research_period_days FROM scan_paths WHERE id = ?
+	`, pathID).Scan(&intervalDays, &periodDays); err != nil {
+		if err != sql.ErrNoRows {
+			logger.Debugf("Failed to load re-search schedule of path %d: %v", pathID, err)
+		}
+		return researchPolicy{}, false
+	}
+	if intervalDays <= 0 {
+		return researchPolicy{}, false
+	}
+	day := 24 * time.Hour
+	return researchPolicy{
+		interval: time.Duration(intervalDays) * day,
+		period:   time.Duration(periodDays) * day,
+	}, true
+}
+
+// lastResearch returns when the re-search schedule of a corruption started
+// and how many searches it scheduled, zero if none yet.
+func (m *MonitorService) lastResearch(corruptionID string) (time.Time, int) {
+	var startedAt sql.NullString
+	var attempt sql.NullInt64
+	if err := m.db.QueryRow(`
+		SELECT json_extract(event_data, '$.started_at'), json_extract(event_data, '$.attempt')
+		FROM events
+		WHERE aggregate_id = ? AND event_type = ?
+		ORDER BY id DESC LIMIT 1
+	`, corruptionID, domain.ScheduledResearch).Scan(&startedAt, &attempt); err != nil {
+		return time.Time{}, 0
+	}
+	started, err := time.Parse(time.RFC3339, startedAt.String)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return started, int(attempt.Int64)
+}
+
+// currentState returns the latest event of a corruption that isn't a
+// notification.
+func (m *MonitorService) currentState(corruptionID string) domain.EventType {
+	var state string
+	if err := m.db.QueryRow(`
+		SELECT event_type FROM events
+		WHERE aggregate_id = ? AND event_type NOT IN (?, ?)
+		ORDER BY id DESC LIMIT 1
+	`, corruptionID, domain.NotificationSent, domain.NotificationFailed).Scan(&state); err != nil {
+		logger.Debugf("Failed to load state of %s: %v", corruptionID, err)
+		return ""
+	}
+	return domain.EventType(state)
+}
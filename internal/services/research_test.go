@@ -0,0 +1,294 @@
+package services
+
+import (
+	"database/sql"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// setupResearchTest seeds a corruption on a path that searches again every
+// intervalDays days for periodDays days.
+func setupResearchTest(t *testing.T, intervalDays, periodDays int) (*sql.DB, *eventbus.EventBus, string) {
+	t.Helper()
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	eb := eventbus.NewEventBus(db)
+	t.Cleanup(eb.Shutdown)
+
+	testutil.SeedScanPath(db, 1, "/media/movies", "/movies", true, true)
+	if _, err := db.Exec(`UPDATE scan_paths SET research_interval_days = ?, research_period_days = ? WHERE id = 1`,
+		intervalDays, periodDays); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+
+	corruptionID := "rare-movie"
+	testutil.SeedEvent(db, domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   corruptionID,
+		EventType:     domain.CorruptionDetected,
+		EventData: map[string]interface{}{
+			"file_path": "/movies/Rare Movie/movie.mkv",
+			"path_id":   int64(1),
+		},
+	})
+	return db, eb, corruptionID
+}
+
+// collectEvents records the events of the given types published on eb, in
+// the order they were published: subscribers of different event types are
+// called concurrently, so they may record them in another order.
+func collectEvents(eb *eventbus.EventBus, types ...domain.EventType) func() []domain.Event {
+	var mu sync.Mutex
+	var events []domain.Event
+	for _, et := range types {
+		eb.Subscribe(et, func(e domain.Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		})
+	}
+	return func() []domain.Event {
+		mu.Lock()
+		defer mu.Unlock()
+		got := append([]domain.Event(nil), events...)
+		slices.SortFunc(got, func(a, b domain.Event) int { return int(a.ID - b.ID) })
+		return got
+	}
+}
+
+// waitForEvents waits up to a second until events returns n events.
+func waitForEvents(events func() []domain.Event, n int) []domain.Event {
+	deadline := time.Now().Add(time.Second)
+	for len(events()) < n && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return events()
+}
+
+func publishSearchExhausted(t *testing.T, eb *eventbus.EventBus, corruptionID, reason string) {
+	t.Helper()
+	if err := eb.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.SearchExhausted,
+		EventData:     map[string]interface{}{"file_path": "/movies/Rare Movie/movie.mkv", "reason": reason},
+	}); err != nil {
+		t.Fatalf("Failed to publish SearchExhausted: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestMonitorService_SchedulesResearch(t *testing.T) {
+	db, eb, corruptionID := setupResearchTest(t, 7, 30)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockClock := testutil.NewMockClockAt(start)
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+	defer monitor.Stop()
+
+	events := collectEvents(eb, domain.ScheduledResearch, domain.RetryScheduled)
+	publishSearchExhausted(t, eb, corruptionID, "item_vanished")
+
+	got := events()
+	if len(got) != 1 || got[0].EventType != domain.ScheduledResearch {
+		t.Fatalf("Expected one ScheduledResearch, got %+v", got)
+	}
+	next, _ := got[0].GetString("next_search_at")
+	until, _ := got[0].GetString("until")
+	if next != "2024-03-08T12:00:00Z" || until != "2024-03-31T12:00:00Z" {
+		t.Errorf("Expected next search 2024-03-08 until 2024-03-31, got %s until %s", next, until)
+	}
+	if attempt, _ := got[0].GetInt64("attempt"); attempt != 1 {
+		t.Errorf("Expected attempt 1, got %d", attempt)
+	}
+
+	// Nothing happens before the interval is over
+	mockClock.Advance(6 * 24 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	if len(events()) != 1 {
+		t.Fatalf("Expected no re-search before the interval, got %+v", events())
+	}
+
+	mockClock.Advance(24 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	got = events()
+	if len(got) != 2 || got[1].EventType != domain.RetryScheduled {
+		t.Fatalf("Expected a RetryScheduled after the interval, got %+v", got)
+	}
+	if !got[1].GetBoolOr("scheduled_research", false) {
+		t.Error("Expected the retry to be marked as a scheduled re-search")
+	}
+	if pathID, _ := got[1].GetInt64("path_id"); pathID != 1 {
+		t.Errorf("Expected path_id 1, got %d", pathID)
+	}
+}
+
+func TestMonitorService_ResearchFailureSchedulesNext(t *testing.T) {
+	db, eb, corruptionID := setupResearchTest(t, 7, 30)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockClock := testutil.NewMockClockAt(start)
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+	defer monitor.Stop()
+
+	events := collectEvents(eb, domain.ScheduledResearch, domain.SearchExhausted, domain.MaxRetriesReached)
+	publishSearchExhausted(t, eb, corruptionID, "item_vanished")
+	mockClock.Advance(7 * 24 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+
+	// The re-search fails: the search is exhausted again and the next one scheduled
+	if err := eb.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.SearchFailed,
+		EventData:     map[string]interface{}{"error": "no results"},
+	}); err != nil {
+		t.Fatalf("Failed to publish SearchFailed: %v", err)
+	}
+
+	got := waitForEvents(events, 4)
+	if len(got) != 4 {
+		t.Fatalf("Expected SearchExhausted, ScheduledResearch, SearchExhausted, ScheduledResearch, got %+v", got)
+	}
+	if reason, _ := got[2].GetString("reason"); got[2].EventType != domain.SearchExhausted || reason != researchFailedReason {
+		t.Errorf("Expected SearchExhausted with reason %s, got %s %q", researchFailedReason, got[2].EventType, reason)
+	}
+	last := got[3]
+	if last.EventType != domain.ScheduledResearch {
+		t.Fatalf("Expected the next ScheduledResearch, got %s", last.EventType)
+	}
+	if attempt, _ := last.GetInt64("attempt"); attempt != 2 {
+		t.Errorf("Expected attempt 2, got %d", attempt)
+	}
+	if started, _ := last.GetString("started_at"); started != "2024-03-01T12:00:00Z" {
+		t.Errorf("Expected the schedule to keep its start, got %s", started)
+	}
+}
+
+func TestMonitorService_ResearchPeriodOver(t *testing.T) {
+	db, eb, corruptionID := setupResearchTest(t, 7, 10)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockClock := testutil.NewMockClockAt(start)
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+	defer monitor.Stop()
+
+	events := collectEvents(eb, domain.ScheduledResearch)
+	publishSearchExhausted(t, eb, corruptionID, "item_vanished")
+	if len(events()) != 1 {
+		t.Fatalf("Expected the first re-search to be scheduled, got %+v", events())
+	}
+
+	// A second search 14 days after the first exhaustion is past the 10-day period
+	mockClock.Advance(7 * 24 * time.Hour)
+	publishSearchExhausted(t, eb, corruptionID, researchFailedReason)
+	if len(events()) != 1 {
+		t.Errorf("Expected no re-search after the period, got %+v", events())
+	}
+}
+
+func TestMonitorService_ResearchSkipped(t *testing.T) {
+	tests := []struct {
+		name         string
+		intervalDays int
+		reason       string
+	}{
+		{"turned off", 0, "item_vanished"},
+		{"corrupt replacement", 7, "file_corrupt"},
+		{"arr instance gone", 7, "arr_instance_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, eb, corruptionID := setupResearchTest(t, tt.intervalDays, 90)
+			mockClock := testutil.NewMockClock()
+			monitor := NewMonitorService(eb, db, mockClock)
+			monitor.Start()
+			defer monitor.Stop()
+
+			events := collectEvents(eb, domain.ScheduledResearch)
+			publishSearchExhausted(t, eb, corruptionID, tt.reason)
+			if len(events()) != 0 || mockClock.PendingCount() != 0 {
+				t.Errorf("Expected no re-search, got %+v", events())
+			}
+		})
+	}
+}
+
+func TestMonitorService_ResearchSkippedWhenCorruptionMovedOn(t *testing.T) {
+	db, eb, corruptionID := setupResearchTest(t, 7, 30)
+	mockClock := testutil.NewMockClock()
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+	defer monitor.Stop()
+
+	events := collectEvents(eb, domain.RetryScheduled)
+	publishSearchExhausted(t, eb, corruptionID, "item_vanished")
+	if err := eb.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.CorruptionIgnored,
+	}); err != nil {
+		t.Fatalf("Failed to publish CorruptionIgnored: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mockClock.Advance(8 * 24 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	if len(events()) != 0 {
+		t.Errorf("Expected no re-search of an ignored corruption, got %+v", events())
+	}
+}
+
+func TestMonitorService_RestoresResearch(t *testing.T) {
+	db, eb, corruptionID := setupResearchTest(t, 7, 30)
+	testutil.SeedEvent(db, domain.Event{
+		AggregateType: "corruption",
+		AggregateID:   corruptionID,
+		EventType:     domain.ScheduledResearch,
+		EventData: map[string]interface{}{
+			"file_path":      "/movies/Rare Movie/movie.mkv",
+			"path_id":        int64(1),
+			"attempt":        3,
+			"next_search_at": "2024-03-05T12:00:00Z",
+			"started_at":     "2024-02-01T12:00:00Z",
+			"until":          "2024-05-01T12:00:00Z",
+		},
+	})
+
+	if _, err := db.Exec(`INSERT OR REPLACE INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at)
+		VALUES (?, '/movies/Rare Movie/movie.mkv', 1, 'ScheduledResearch', datetime('now'), datetime('now'))`, corruptionID); err != nil {
+		t.Fatalf("Failed to seed corruption_summary: %v", err)
+	}
+
+	mockClock := testutil.NewMockClockAt(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+	events := collectEvents(eb, domain.RetryScheduled)
+	monitor := NewMonitorService(eb, db, mockClock)
+	monitor.Start()
+	defer monitor.Stop()
+
+	if mockClock.PendingCount() != 1 {
+		t.Fatalf("Expected the re-search timer to be restored, got %d timers", mockClock.PendingCount())
+	}
+	mockClock.Advance(24 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+
+	got := events()
+	if len(got) != 1 {
+		t.Fatalf("Expected the restored re-search to run, got %+v", got)
+	}
+	if attempt, _ := got[0].GetInt64("research_attempt"); attempt != 3 {
+		t.Errorf("Expected research_attempt 3, got %d", attempt)
+	}
+}
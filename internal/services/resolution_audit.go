@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/mescon/Healarr/internal/clock"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/metrics"
+)
+
+const (
+	// auditBatchSize is how many replacements an audit reverifies at once, well
+	// below the verifier's concurrency limit so remediation keeps its share.
+	auditBatchSize = 10
+	// auditBatchTimeout is how long an audit waits for a batch to finish
+	// before it moves on.
+	auditBatchTimeout = time.Hour
+	// auditQueryTimeout bounds the audit's queries.
+	auditQueryTimeout = 30 * time.Second
+)
+
+// ErrAuditRunning is returned when an audit is started while one is running.
+var ErrAuditRunning = errors.New("a resolution audit is already running")
+
+// ResolutionAuditRun describes the latest resolution audit.
+type ResolutionAuditRun struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"` // nil while the audit runs
+	// Candidates is how many corruptions were resolved in the audit window
+	Candidates int `json:"candidates"`
+	Selected   int `json:"selected"`
+	Requested  int `json:"requested"`
+	// Skipped counts the selected corruptions that couldn't be reverified, e.g. re-opened meanwhile
+	Skipped int `json:"skipped"`
+}
+
+// ResolutionDurability is the share of audited replacements that were still
+// healthy, over the audit window.
+type ResolutionDurability struct {
+	WindowDays int      `json:"window_days"`
+	Audited    int      `json:"audited"`
+	Healthy    int      `json:"healthy"`
+	Failed     int      `json:"failed"`
+	Ratio      *float64 `json:"ratio"` // 0-1, nil when nothing was audited
+}
+
+// ResolutionAuditService re-checks the replacements of recently resolved
+// corruptions on a cron schedule, to catch storage that corrupts new files
+// again. Each audited corruption is reverified like a manual reverification,
+// marked as an audit, so a failed replacement is remediated again. A passed
+// audit has no side effects beyond the durability it is counted in: no
+// rescan, notification or remediation metric.
+type ResolutionAuditService struct {
+	db       *sql.DB
+	eventBus eventbus.Publisher
+	clk      clock.Clock
+	days     int // resolved within this many days are audited
+	sample   int // corruptions audited per run, 0 for all
+	pause    *SystemPause
+	cron     *cron.Cron
+
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	last    *ResolutionAuditRun
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewResolutionAuditService creates a resolution audit of the corruptions
+// resolved in the last days, auditing a random sample of that many
+// corruptions per run (0 audits all of them).
+func NewResolutionAuditService(db *sql.DB, eb eventbus.Publisher, days, sample int) *ResolutionAuditService {
+	return &ResolutionAuditService{
+		db:           db,
+		eventBus:     eb,
+		clk:          clock.NewRealClock(),
+		days:         max(days, 1),
+		sample:       max(sample, 0),
+		pollInterval: 10 * time.Second,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// SetSystemPause makes scheduled audits skip while automation is paused.
+func (a *ResolutionAuditService) SetSystemPause(p *SystemPause) {
+	a.pause = p
+}
+
+// Start runs the audit on the given cron schedule, interpreted in the timezone
+// of a CRON_TZ= prefix or else the scheduler's. An empty schedule leaves
+// scheduled audits off.
+func (a *ResolutionAuditService) Start(schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+	spec, err := ParseSchedule(schedule, domain.ScheduleTiming{})
+	if err != nil {
+		return fmt.Errorf("invalid resolution audit schedule %q: %w", schedule, err)
+	}
+	a.cron = cron.New(cron.WithLocation(cronLocation()))
+	a.cron.Schedule(spec, cron.FuncJob(a.runScheduled))
+	a.cron.Start()
+	return nil
+}
+
+// Stop stops the schedule and a running audit, and waits for it to finish.
+func (a *ResolutionAuditService) Stop() {
+	if a.cron != nil {
+		<-a.cron.Stop().Done()
+	}
+	a.mu.Lock()
+	select {
+	case <-a.stopCh:
+	default:
+		close(a.stopCh)
+	}
+	a.mu.Unlock()
+	a.wg.Wait()
+}
+
+func (a *ResolutionAuditService) runScheduled() {
+	if a.pause != nil && a.pause.Paused() {
+		logger.Infof("Skipping scheduled resolution audit: automation is paused")
+		return
+	}
+	if _, err := a.Run(context.Background()); err != nil {
+		logger.Errorf("Failed to start scheduled resolution audit: %v", err)
+	}
+}
+
+// LastRun returns the latest audit, or nil if none ran since startup.
+func (a *ResolutionAuditService) LastRun() *ResolutionAuditRun {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.last == nil {
+		return nil
+	}
+	run := *a.last
+	return &run
+}
+
+// Run selects the corruptions to audit and reverifies them in the
+// background, a batch at a time. It returns the audit as started.
+func (a *ResolutionAuditService) Run(ctx context.Context) (*ResolutionAuditRun, error) {
+	a.mu.Lock()
+	if a.running {
+		a.mu.Unlock()
+		return nil, ErrAuditRunning
+	}
+	a.running = true
+	a.mu.Unlock()
+
+	ids, candidates, err := a.selectSample(ctx)
+	if err != nil {
+		a.mu.Lock()
+		a.running = false
+		a.mu.Unlock()
+		return nil, err
+	}
+	run := &ResolutionAuditRun{StartedAt: a.clk.Now().UTC(), Candidates: candidates, Selected: len(ids)}
+	logger.Infof("Resolution audit: reverifying %d of %d corruption(s) resolved in the last %d days", len(ids), candidates, a.days)
+
+	a.mu.Lock()
+	a.last = run
+	a.mu.Unlock()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.audit(ids)
+	}()
+	return a.LastRun(), nil
+}
+
+// selectSample returns the IDs of the corruptions to audit, in random order,
+// and how many were resolved in the window. A corruption counts as resolved
+// when its replacement was verified; earlier audits don't move it into the
+// window again.
+func (a *ResolutionAuditService) selectSample(ctx context.Context) ([]string, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, auditQueryTimeout)
+	defer cancel()
+
+	since := a.clk.Now().Add(-time.Duration(a.days) * 24 * time.Hour).UTC().Format(reportTimeFormat)
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT cs.corruption_id FROM corruption_summary cs
+		WHERE cs.current_state = 'VerificationSuccess'
+		AND (
+			SELECT MAX(e.created_at) FROM events e
+			WHERE e.aggregate_id = cs.corruption_id AND e.event_type = 'VerificationSuccess'
+			AND json_extract(e.event_data, '$.reverification_of') IS NULL
+		) >= ?
+		ORDER BY RANDOM()
+	`, since)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to select resolved corruptions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	candidates := len(ids)
+	if a.sample > 0 && len(ids) > a.sample {
+		ids = ids[:a.sample]
+	}
+	return ids, candidates, nil
+}
+
+// audit reverifies the corruptions a batch at a time, waiting for each batch
+// to finish so the verifier isn't flooded.
+func (a *ResolutionAuditService) audit(ids []string) {
+	defer func() {
+		a.mu.Lock()
+		now := a.clk.Now().UTC()
+		a.last.FinishedAt = &now
+		a.running = false
+		logger.Infof("Resolution audit finished: %d reverified, %d skipped", a.last.Requested, a.last.Skipped)
+		a.mu.Unlock()
+	}()
+
+	for start := 0; start < len(ids); start += auditBatchSize {
+		batch := ids[start:min(start+auditBatchSize, len(ids))]
+		requested := make([]string, 0, len(batch))
+		for _, id := range batch {
+			ctx, cancel := context.WithTimeout(context.Background(), auditQueryTimeout)
+			_, err := RequestReverification(ctx, a.db, a.eventBus, id, true)
+			cancel()
+			a.mu.Lock()
+			if err != nil {
+				a.last.Skipped++
+			} else {
+				a.last.Requested++
+			}
+			a.mu.Unlock()
+			if err != nil {
+				logger.Debugf("Resolution audit: skipping %s: %v", id, err)
+				continue
+			}
+			requested = append(requested, id)
+		}
+		if !a.waitForBatch(requested) {
+			return
+		}
+	}
+}
+
+// waitForBatch waits until the reverifications of ids have finished, the
+// batch timed out, or the service is stopping. Returns false when stopping.
+func (a *ResolutionAuditService) waitForBatch(ids []string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := `SELECT COUNT(*) FROM corruption_summary WHERE current_state IN ('ReverificationRequested', 'VerificationStarted') AND corruption_id IN (` + placeholders + `)` // NOSONAR - placeholders only
+
+	ticker := time.NewTicker(a.pollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(auditBatchTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			return false
+		case <-timeout.C:
+			logger.Warnf("Resolution audit: %d reverification(s) still running after %v, continuing", len(ids), auditBatchTimeout)
+			return true
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), auditQueryTimeout)
+			var pending int
+			err := a.db.QueryRowContext(ctx, query, args...).Scan(&pending)
+			cancel()
+			if err != nil {
+				logger.Debugf("Resolution audit: failed to check reverifications: %v", err)
+				continue
+			}
+			if pending == 0 {
+				return true
+			}
+		}
+	}
+}
+
+// Durability returns the outcomes of the audit reverifications in the audit window.
+func (a *ResolutionAuditService) Durability(ctx context.Context) (*ResolutionDurability, error) {
+	since := a.clk.Now().Add(-time.Duration(a.days) * 24 * time.Hour).UTC().Format(reportTimeFormat)
+	d := &ResolutionDurability{WindowDays: a.days}
+	err := a.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN event_type = 'VerificationSuccess' THEN 1 END),
+			COUNT(CASE WHEN event_type = 'VerificationFailed' THEN 1 END)
+		FROM events
+		WHERE event_type IN ('VerificationSuccess', 'VerificationFailed') AND created_at >= ?
+		AND json_extract(event_data, '$.audit') = 1
+	`, since).Scan(&d.Healthy, &d.Failed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit outcomes: %w", err)
+	}
+	d.Audited = d.Healthy + d.Failed
+	if d.Audited > 0 {
+		ratio := float64(d.Healthy) / float64(d.Audited)
+		d.Ratio = &ratio
+	}
+	return d, nil
+}
+
+// DurabilitySource returns the audit outcomes for the resolution durability gauges.
+func (a *ResolutionAuditService) DurabilitySource() metrics.DurabilityFunc {
+	return func(ctx context.Context) (int, int, error) {
+		d, err := a.Durability(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		return d.Healthy, d.Failed, nil
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestResolutionAudit(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().UTC()
+	old := now.Add(-60 * 24 * time.Hour)
+	if _, err := db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at) VALUES
+			('recent', '/media/a.mkv', 1, 'VerificationSuccess', ?, ?),
+			('old', '/media/b.mkv', 1, 'VerificationSuccess', ?, ?),
+			('open', '/media/c.mkv', 1, 'SearchStarted', ?, ?)
+	`, now, now, old, now, now, now); err != nil {
+		t.Fatalf("Failed to seed summaries: %v", err)
+	}
+	if err := testutil.SeedEvents(db, []domain.Event{
+		{AggregateID: "recent", AggregateType: "corruption", EventType: domain.FileDetected,
+			EventData: map[string]interface{}{"file_path": "/media/a.1080p.mkv"}, CreatedAt: now},
+		{AggregateID: "recent", AggregateType: "corruption", EventType: domain.VerificationSuccess, CreatedAt: now},
+		{AggregateID: "old", AggregateType: "corruption", EventType: domain.VerificationSuccess, CreatedAt: old},
+		// A recent audit of the old one doesn't bring it back into the window
+		{AggregateID: "old", AggregateType: "corruption", EventType: domain.VerificationSuccess,
+			EventData: map[string]interface{}{"reverification_of": 3, "audit": true}, CreatedAt: now},
+	}); err != nil {
+		t.Fatalf("Failed to seed events: %v", err)
+	}
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+	requested := make(chan domain.Event, 4)
+	eb.Subscribe(domain.ReverificationRequested, func(e domain.Event) { requested <- e })
+
+	audit := NewResolutionAuditService(db, eb, 30, 0)
+	audit.pollInterval = 10 * time.Millisecond
+	defer audit.Stop()
+
+	run, err := audit.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if run.Candidates != 1 || run.Selected != 1 {
+		t.Errorf("Expected only the recently resolved corruption, got %+v", run)
+	}
+
+	select {
+	case e := <-requested:
+		if e.AggregateID != "recent" || !e.GetBoolOr("audit", false) || e.GetStringOr("file_path", "") != "/media/a.1080p.mkv" {
+			t.Errorf("Unexpected reverification request %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reverification request")
+	}
+	// The audit waits for the verifier's outcome
+	time.Sleep(50 * time.Millisecond)
+	if audit.LastRun().FinishedAt != nil {
+		t.Fatal("Expected the audit to wait for the reverification")
+	}
+	if err := eb.Publish(domain.Event{AggregateID: "recent", AggregateType: "corruption", EventType: domain.VerificationFailed,
+		EventData: map[string]interface{}{"reverification_of": 2, "audit": true}}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for audit.LastRun().FinishedAt == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if last := audit.LastRun(); last.FinishedAt == nil || last.Requested != 1 {
+		t.Errorf("Expected the audit to finish with 1 reverification, got %+v", last)
+	}
+
+	d, err := audit.Durability(context.Background())
+	if err != nil {
+		t.Fatalf("Durability() error = %v", err)
+	}
+	if d.Audited != 2 || d.Healthy != 1 || d.Failed != 1 || d.Ratio == nil || *d.Ratio != 0.5 {
+		t.Errorf("Durability() = %+v", d)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+)
+
+var (
+	// ErrNotResolved is returned when reverifying a corruption that isn't resolved.
+	ErrNotResolved = errors.New("only resolved corruptions can be reverified")
+	// ErrNoReplacementFile is returned when no replacement file of a corruption is known.
+	ErrNoReplacementFile = errors.New("corruption has no replacement file to verify")
+)
+
+// Reverification is a requested check of a resolved corruption's replacement.
+type Reverification struct {
+	CorruptionID string `json:"corruption_id"`
+	// ReverificationOf is the ID of the VerificationSuccess event it repeats
+	ReverificationOf int64    `json:"reverification_of"`
+	FilePaths        []string `json:"file_paths"`
+}
+
+// RequestReverification publishes a ReverificationRequested event for a
+// resolved corruption, so the verifier checks its replacement again. The
+// corruption is re-opened rather than detected anew: the outcome is published
+// on it, tied to the VerificationSuccess it repeats. Audit reverifications are
+// marked so their outcomes count towards the resolution durability. Returns
+// sql.ErrNoRows for an unknown corruption.
+func RequestReverification(ctx context.Context, db *sql.DB, eb eventbus.Publisher, id string, audit bool) (*Reverification, error) {
+	var state string
+	var summaryPath sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT current_state, file_path FROM corruption_summary WHERE corruption_id = ?
+	`, id).Scan(&state, &summaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if state != string(domain.VerificationSuccess) {
+		return nil, ErrNotResolved
+	}
+
+	r := &Reverification{CorruptionID: id}
+	if err := db.QueryRowContext(ctx, `
+		SELECT id FROM events
+		WHERE aggregate_id = ? AND event_type = 'VerificationSuccess'
+		ORDER BY id DESC LIMIT 1
+	`, id).Scan(&r.ReverificationOf); err != nil {
+		return nil, err
+	}
+
+	if r.FilePaths, err = ReplacementPaths(ctx, db, id); err != nil {
+		return nil, err
+	}
+	if len(r.FilePaths) == 0 && summaryPath.Valid && summaryPath.String != "" {
+		// Replaced in place, e.g. resolved by the *arr sync
+		r.FilePaths = []string{summaryPath.String}
+	}
+	if len(r.FilePaths) == 0 {
+		return nil, ErrNoReplacementFile
+	}
+
+	eventData := map[string]interface{}{
+		"file_path":         r.FilePaths[0],
+		"reverification_of": r.ReverificationOf,
+	}
+	if len(r.FilePaths) > 1 {
+		eventData["file_paths"] = r.FilePaths
+	}
+	if audit {
+		eventData["audit"] = true
+	}
+	if err := eb.Publish(domain.Event{
+		AggregateID:   id,
+		AggregateType: "corruption",
+		EventType:     domain.ReverificationRequested,
+		EventData:     eventData,
+	}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ReplacementPaths returns the local paths of a corruption's latest
+// replacement, as found by the verifier or the recovery of a stale
+// corruption. Returns nil if none was recorded.
+func ReplacementPaths(ctx context.Context, db *sql.DB, id string) ([]string, error) {
+	var filePathsJSON, filePath sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.file_paths'), json_extract(event_data, '$.file_path')
+		FROM events
+		WHERE aggregate_id = ? AND event_type IN ('FileDetected', 'VerificationSuccess', 'ReverificationRequested')
+		AND json_extract(event_data, '$.file_path') IS NOT NULL
+		ORDER BY id DESC LIMIT 1
+	`, id).Scan(&filePathsJSON, &filePath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if filePathsJSON.Valid {
+		var filePaths []string
+		if err := json.Unmarshal([]byte(filePathsJSON.String), &filePaths); err == nil && len(filePaths) > 0 {
+			return filePaths, nil
+		}
+	}
+	if filePath.String == "" {
+		return nil, nil
+	}
+	return []string{filePath.String}, nil
+}
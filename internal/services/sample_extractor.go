@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/db"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Sample extraction methods.
+const (
+	// SampleMethodFFmpeg is a clip remuxed by ffmpeg from shortly before the error
+	SampleMethodFFmpeg = "ffmpeg"
+	// SampleMethodBytes is a raw byte range of the file, like dd would copy
+	SampleMethodBytes = "bytes"
+)
+
+const (
+	// sampleTimeout bounds one extraction, including ffmpeg.
+	sampleTimeout = 2 * time.Minute
+	// sampleLeadIn is how much of the file before the error a clip starts with.
+	sampleLeadIn = 2.0
+	// sampleClipSeconds is the longest clip ffmpeg cuts; MaxBytes usually ends it first.
+	sampleClipSeconds = 30
+)
+
+// ErrSampleNotFound is returned when a corruption has no sample.
+var ErrSampleNotFound = errors.New("no sample for this corruption")
+
+var (
+	// sampleSecondsRe matches the offset of sampled checks, e.g. "at 1234.5s: ..."
+	sampleSecondsRe = regexp.MustCompile(`\bat (\d+(?:\.\d+)?)s\b`)
+	// samplePtsRe matches the timestamps ffmpeg reports with its errors
+	samplePtsRe = regexp.MustCompile(`\bpts_time[:=](\d+(?:\.\d+)?)`)
+	// sampleClockRe matches ffmpeg's "time=00:01:02.50" progress
+	sampleClockRe = regexp.MustCompile(`\btime=(\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	// sampleBytesRe matches byte positions, e.g. "Read error at pos. 123456"
+	sampleBytesRe = regexp.MustCompile(`(?i)\b(?:pos\.?|offset)[:=]?\s*(0x[0-9a-f]+|\d+)\b`)
+	// sampleNameRe matches what may not be part of a sample file name
+	sampleNameRe = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+)
+
+// SampleConfig configures a SampleExtractor.
+type SampleConfig struct {
+	Dir           string // where samples are written
+	FFmpegPath    string // empty copies raw bytes only
+	MaxBytes      int64  // size cap of one sample
+	DirMaxBytes   int64  // size cap of all samples; the oldest go first
+	RetentionDays int    // age cap of samples
+}
+
+// CorruptionSample is a small piece of a corrupt file around its first error.
+type CorruptionSample struct {
+	CorruptionID  string    `json:"corruption_id"`
+	FilePath      string    `json:"file_path"`
+	SamplePath    string    `json:"-"`
+	FileName      string    `json:"file_name"`
+	Method        string    `json:"method"`
+	OffsetSeconds *float64  `json:"offset_seconds,omitempty"`
+	OffsetBytes   *int64    `json:"offset_bytes,omitempty"`
+	SizeBytes     int64     `json:"size_bytes"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// sampleOffset is where a check reported the first error of a file.
+type sampleOffset struct {
+	seconds    float64
+	hasSeconds bool
+	bytes      int64
+	hasBytes   bool
+}
+
+// SampleExtractor copies a few MB around the first error of a corrupt file to
+// a diagnostics directory, so the part a detector tripped over can be shared
+// without the whole file. With a byte position in the checker output the raw
+// bytes around it are copied; with only a timestamp ffmpeg remuxes a clip
+// starting shortly before it; without either the start of the file, where
+// header problems are, is copied. Samples are capped in size and age.
+type SampleExtractor struct {
+	db  *sql.DB
+	cfg SampleConfig
+	mu  sync.Mutex // serializes extraction and pruning
+	now func() time.Time
+	// runFFmpeg runs ffmpeg with args; replaced in tests
+	runFFmpeg func(ctx context.Context, args []string) error
+}
+
+// NewSampleExtractor creates a SampleExtractor writing to cfg.Dir.
+func NewSampleExtractor(database *sql.DB, cfg SampleConfig) *SampleExtractor {
+	x := &SampleExtractor{db: database, cfg: cfg, now: time.Now}
+	x.runFFmpeg = x.execFFmpeg
+	return x
+}
+
+// Extract copies a sample of filePath around the first error in diag,
+// replacing an earlier sample of the corruption, then prunes the samples
+// beyond the directory cap.
+func (x *SampleExtractor) Extract(ctx context.Context, corruptionID, filePath string, diag integration.CheckDiagnostics) (*CorruptionSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, sampleTimeout)
+	defer cancel()
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", filePath, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", filePath)
+	}
+	if err := os.MkdirAll(x.cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create sample directory: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	sample := &CorruptionSample{
+		CorruptionID: corruptionID,
+		FilePath:     filePath,
+		SamplePath:   filepath.Join(x.cfg.Dir, sampleNameRe.ReplaceAllString(corruptionID, "_")+ext),
+		FileName:     strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)) + ".sample" + ext,
+		CreatedAt:    x.now().UTC().Truncate(time.Second),
+	}
+
+	off := parseSampleOffset(diag)
+	switch {
+	case off.hasBytes:
+		err = x.copyBytes(sample, info.Size(), off.bytes-x.cfg.MaxBytes/4)
+	case off.hasSeconds && x.cfg.FFmpegPath != "":
+		if err = x.cutClip(ctx, sample, off.seconds-sampleLeadIn); err != nil {
+			logger.Debugf("ffmpeg could not cut a sample of %s, copying its first bytes: %v", filePath, err)
+			err = x.copyBytes(sample, info.Size(), 0)
+		}
+	default:
+		err = x.copyBytes(sample, info.Size(), 0)
+	}
+	if err != nil {
+		_ = os.Remove(sample.SamplePath)
+		return nil, err
+	}
+
+	if _, err := db.ExecWithRetry(x.db, `
+		INSERT OR REPLACE INTO corruption_samples
+			(corruption_id, file_path, sample_path, method, offset_seconds, offset_bytes, size_bytes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, sample.CorruptionID, sample.FilePath, sample.SamplePath, sample.Method, sample.OffsetSeconds,
+		sample.OffsetBytes, sample.SizeBytes, sample.CreatedAt.Format(time.DateTime)); err != nil {
+		_ = os.Remove(sample.SamplePath)
+		return nil, fmt.Errorf("failed to store sample: %w", err)
+	}
+	logger.Infof("Extracted a %d byte sample of %s (%s)", sample.SizeBytes, filePath, sample.Method)
+
+	if err := x.pruneLocked(ctx, false); err != nil {
+		logger.Warnf("Failed to prune samples: %v", err)
+	}
+	return sample, nil
+}
+
+// copyBytes copies up to MaxBytes of the file from start, which is moved to
+// keep the sample inside the file.
+func (x *SampleExtractor) copyBytes(sample *CorruptionSample, size, start int64) error {
+	if start > size-x.cfg.MaxBytes {
+		start = size - x.cfg.MaxBytes
+	}
+	if start < 0 {
+		start = 0
+	}
+	src, err := os.Open(sample.FilePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if _, err := src.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(sample.SamplePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	n, err := io.CopyN(dst, src, x.cfg.MaxBytes)
+	if cerr := dst.Close(); err == nil || errors.Is(err, io.EOF) {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy sample: %w", err)
+	}
+	sample.Method = SampleMethodBytes
+	sample.OffsetBytes = &start
+	sample.OffsetSeconds = nil
+	sample.SizeBytes = n
+	return nil
+}
+
+// cutClip has ffmpeg remux a clip of up to MaxBytes starting at start seconds.
+func (x *SampleExtractor) cutClip(ctx context.Context, sample *CorruptionSample, start float64) error {
+	if start < 0 {
+		start = 0
+	}
+	args := []string{
+		"-nostdin", "-v", "error", "-y",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-i", sample.FilePath,
+		"-t", strconv.Itoa(sampleClipSeconds),
+		"-map", "0", "-c", "copy",
+		"-fs", strconv.FormatInt(x.cfg.MaxBytes, 10),
+		sample.SamplePath,
+	}
+	if err := x.runFFmpeg(ctx, args); err != nil {
+		return err
+	}
+	info, err := os.Stat(sample.SamplePath)
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return errors.New("ffmpeg wrote an empty clip")
+	}
+	// -fs stops after the packet that crosses the limit
+	size := info.Size()
+	if size > x.cfg.MaxBytes {
+		if err := os.Truncate(sample.SamplePath, x.cfg.MaxBytes); err != nil {
+			return err
+		}
+		size = x.cfg.MaxBytes
+	}
+	sample.Method = SampleMethodFFmpeg
+	sample.OffsetSeconds = &start
+	sample.OffsetBytes = nil
+	sample.SizeBytes = size
+	return nil
+}
+
+// execFFmpeg runs the configured ffmpeg.
+func (x *SampleExtractor) execFFmpeg(ctx context.Context, args []string) error {
+	out, err := exec.CommandContext(ctx, x.cfg.FFmpegPath, args...).CombinedOutput() // #nosec G204 -- configured tool path, arguments built here
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Sample returns the sample of a corruption, or ErrSampleNotFound.
+func (x *SampleExtractor) Sample(ctx context.Context, corruptionID string) (*CorruptionSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	var s CorruptionSample
+	var offsetSeconds sql.NullFloat64
+	var offsetBytes sql.NullInt64
+	err := x.db.QueryRowContext(ctx, `
+		SELECT corruption_id, file_path, sample_path, method, offset_seconds, offset_bytes, size_bytes, created_at
+		FROM corruption_samples WHERE corruption_id = ?
+	`, corruptionID).Scan(&s.CorruptionID, &s.FilePath, &s.SamplePath, &s.Method, &offsetSeconds, &offsetBytes,
+		&s.SizeBytes, &s.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSampleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(s.SamplePath); err != nil {
+		// Removed from the directory by hand
+		return nil, ErrSampleNotFound
+	}
+	if offsetSeconds.Valid {
+		s.OffsetSeconds = &offsetSeconds.Float64
+	}
+	if offsetBytes.Valid {
+		s.OffsetBytes = &offsetBytes.Int64
+	}
+	ext := filepath.Ext(s.SamplePath)
+	s.FileName = strings.TrimSuffix(filepath.Base(s.FilePath), filepath.Ext(s.FilePath)) + ".sample" + ext
+	return &s, nil
+}
+
+// Delete removes the sample of a corruption, if it has one.
+func (x *SampleExtractor) Delete(ctx context.Context, corruptionID string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var samplePath string
+	err := x.db.QueryRowContext(ctx, `SELECT sample_path FROM corruption_samples WHERE corruption_id = ?`,
+		corruptionID).Scan(&samplePath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return x.remove(ctx, corruptionID, samplePath)
+}
+
+// Prune removes the samples older than the retention, then the oldest until
+// the rest fit the directory cap.
+func (x *SampleExtractor) Prune(ctx context.Context) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.pruneLocked(ctx, true)
+}
+
+// pruneLocked prunes by size and, with byAge, by age. The caller holds x.mu.
+func (x *SampleExtractor) pruneLocked(ctx context.Context, byAge bool) error {
+	rows, err := x.db.QueryContext(ctx, `
+		SELECT corruption_id, sample_path, size_bytes, created_at < datetime(?)
+		FROM corruption_samples ORDER BY created_at DESC, rowid DESC
+	`, x.now().UTC().AddDate(0, 0, -x.cfg.RetentionDays).Format(time.DateTime))
+	if err != nil {
+		return err
+	}
+	type stored struct {
+		id, path string
+	}
+	var expired []stored
+	var total int64
+	for rows.Next() {
+		var s stored
+		var size int64
+		var old bool
+		if err := rows.Scan(&s.id, &s.path, &size, &old); err != nil {
+			rows.Close()
+			return err
+		}
+		if byAge && old || total+size > x.cfg.DirMaxBytes {
+			expired = append(expired, s)
+			continue
+		}
+		total += size
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range expired {
+		if err := x.remove(ctx, s.id, s.path); err != nil {
+			return err
+		}
+	}
+	if len(expired) > 0 {
+		logger.Infof("Pruned %d corrupted-file sample(s)", len(expired))
+	}
+	return nil
+}
+
+// remove deletes a sample file and its row. The caller holds x.mu.
+func (x *SampleExtractor) remove(ctx context.Context, corruptionID, samplePath string) error {
+	if err := os.Remove(samplePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove sample %s: %w", samplePath, err)
+	}
+	_, err := x.db.ExecContext(ctx, `DELETE FROM corruption_samples WHERE corruption_id = ?`, corruptionID)
+	return err
+}
+
+// parseSampleOffset finds the first error position in the check message and
+// tool output. Byte positions are more precise than timestamps, so both are
+// collected.
+func parseSampleOffset(diag integration.CheckDiagnostics) sampleOffset {
+	var off sampleOffset
+	texts := []string{diag.Message}
+	for _, run := range diag.Runs {
+		texts = append(texts, run.Stderr, run.Stdout)
+	}
+	for _, text := range texts {
+		if !off.hasSeconds {
+			off.seconds, off.hasSeconds = parseSampleSeconds(text)
+		}
+		if !off.hasBytes {
+			if m := sampleBytesRe.FindStringSubmatch(text); m != nil {
+				if n, err := strconv.ParseInt(m[1], 0, 64); err == nil {
+					off.bytes, off.hasBytes = n, true
+				}
+			}
+		}
+	}
+	return off
+}
+
+// parseSampleSeconds returns the first timestamp in text.
+func parseSampleSeconds(text string) (float64, bool) {
+	for _, re := range []*regexp.Regexp{sampleSecondsRe, samplePtsRe} {
+		if m := re.FindStringSubmatch(text); m != nil {
+			if secs, err := strconv.ParseFloat(m[1], 64); err == nil {
+				return secs, true
+			}
+		}
+	}
+	if m := sampleClockRe.FindStringSubmatch(text); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mins, _ := strconv.Atoi(m[2])
+		secs, _ := strconv.ParseFloat(m[3], 64)
+		return float64(h*3600+mins*60) + secs, true
+	}
+	return 0, false
+}
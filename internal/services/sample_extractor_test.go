@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// newTestSampleExtractor creates an extractor with 1 KB samples and a 2 KB
+// directory, and a 4 KB media file of ascending bytes.
+func newTestSampleExtractor(t *testing.T) (*SampleExtractor, string) {
+	t.Helper()
+	database, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Movie (2020).mkv")
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i / 16)
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	x := NewSampleExtractor(database, SampleConfig{
+		Dir:           filepath.Join(dir, "samples"),
+		FFmpegPath:    "ffmpeg",
+		MaxBytes:      1024,
+		DirMaxBytes:   2048,
+		RetentionDays: 7,
+	})
+	x.runFFmpeg = func(ctx context.Context, args []string) error {
+		return errors.New("ffmpeg not available")
+	}
+	return x, file
+}
+
+func TestParseSampleOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		diag    integration.CheckDiagnostics
+		seconds float64
+		bytes   int64
+		hasSecs bool
+		hasByte bool
+	}{
+		{"sampled check", integration.CheckDiagnostics{Message: "decode errors at 1234.5s: Invalid data"}, 1234.5, 0, true, false},
+		{"ffmpeg clock", integration.CheckDiagnostics{Runs: []integration.ToolRun{{Stderr: "frame=10 time=01:02:03.50 bitrate"}}}, 3723.5, 0, true, false},
+		{"byte position", integration.CheckDiagnostics{Runs: []integration.ToolRun{{Stderr: "Read error at pos. 123456 (0x1e240)"}}}, 0, 123456, false, true},
+		{"hex offset", integration.CheckDiagnostics{Message: "bad element at offset 0x400"}, 0, 1024, false, true},
+		{"none", integration.CheckDiagnostics{Message: "moov atom not found"}, 0, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			off := parseSampleOffset(tt.diag)
+			if off.hasSeconds != tt.hasSecs || off.seconds != tt.seconds {
+				t.Errorf("seconds = %v, %v; want %v, %v", off.seconds, off.hasSeconds, tt.seconds, tt.hasSecs)
+			}
+			if off.hasBytes != tt.hasByte || off.bytes != tt.bytes {
+				t.Errorf("bytes = %v, %v; want %v, %v", off.bytes, off.hasBytes, tt.bytes, tt.hasByte)
+			}
+		})
+	}
+}
+
+func TestSampleExtractor_Extract(t *testing.T) {
+	x, file := newTestSampleExtractor(t)
+	ctx := context.Background()
+	data, _ := os.ReadFile(file)
+
+	t.Run("around byte position", func(t *testing.T) {
+		sample, err := x.Extract(ctx, "c1", file, integration.CheckDiagnostics{Message: "error at pos. 2048"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample.Method != SampleMethodBytes || *sample.OffsetBytes != 1792 || sample.SizeBytes != 1024 {
+			t.Fatalf("unexpected sample: %+v", sample)
+		}
+		got, _ := os.ReadFile(sample.SamplePath)
+		if !bytes.Equal(got, data[1792:2816]) {
+			t.Error("sample holds the wrong bytes")
+		}
+		if sample.FileName != "Movie (2020).sample.mkv" {
+			t.Errorf("FileName = %q", sample.FileName)
+		}
+	})
+
+	t.Run("byte position at the end", func(t *testing.T) {
+		sample, err := x.Extract(ctx, "c2", file, integration.CheckDiagnostics{Message: "error at offset 4000"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *sample.OffsetBytes != 3072 || sample.SizeBytes != 1024 {
+			t.Errorf("unexpected sample: %+v", sample)
+		}
+	})
+
+	t.Run("timestamp falls back to the start without ffmpeg", func(t *testing.T) {
+		sample, err := x.Extract(ctx, "c3", file, integration.CheckDiagnostics{Message: "at 60s: Invalid data"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample.Method != SampleMethodBytes || *sample.OffsetBytes != 0 {
+			t.Errorf("unexpected sample: %+v", sample)
+		}
+	})
+
+	t.Run("timestamp cut by ffmpeg", func(t *testing.T) {
+		var gotArgs []string
+		x.runFFmpeg = func(ctx context.Context, args []string) error {
+			gotArgs = args
+			return os.WriteFile(args[len(args)-1], make([]byte, 1500), 0o644)
+		}
+		sample, err := x.Extract(ctx, "c4", file, integration.CheckDiagnostics{Message: "at 60s: Invalid data"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sample.Method != SampleMethodFFmpeg || *sample.OffsetSeconds != 58 || sample.SizeBytes != 1024 {
+			t.Errorf("unexpected sample: %+v", sample)
+		}
+		if len(gotArgs) < 6 || gotArgs[4] != "-ss" || gotArgs[5] != "58.000" {
+			t.Errorf("unexpected ffmpeg args: %v", gotArgs)
+		}
+	})
+
+	// Four 1 KB samples in a 2 KB directory: the two oldest are gone
+	for _, id := range []string{"c1", "c2"} {
+		if _, err := x.Sample(ctx, id); !errors.Is(err, ErrSampleNotFound) {
+			t.Errorf("sample %s: err = %v, want ErrSampleNotFound", id, err)
+		}
+	}
+	if _, err := x.Sample(ctx, "c4"); err != nil {
+		t.Errorf("newest sample: %v", err)
+	}
+
+	if _, err := x.Extract(ctx, "gone", filepath.Join(filepath.Dir(file), "missing.mkv"), integration.CheckDiagnostics{}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestSampleExtractor_Prune(t *testing.T) {
+	x, file := newTestSampleExtractor(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	x.now = func() time.Time { return now.AddDate(0, 0, -10) }
+	old, err := x.Extract(ctx, "old", file, integration.CheckDiagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.now = func() time.Time { return now }
+	if _, err := x.Extract(ctx, "new", file, integration.CheckDiagnostics{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := x.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(old.SamplePath); !os.IsNotExist(err) {
+		t.Errorf("expired sample file still exists: %v", err)
+	}
+	if _, err := x.Sample(ctx, "old"); !errors.Is(err, ErrSampleNotFound) {
+		t.Errorf("expired sample: err = %v", err)
+	}
+	if _, err := x.Sample(ctx, "new"); err != nil {
+		t.Errorf("recent sample: %v", err)
+	}
+
+	if err := x.Delete(ctx, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := x.Sample(ctx, "new"); !errors.Is(err, ErrSampleNotFound) {
+		t.Errorf("deleted sample: err = %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Timing of the scheduled scan queue.
+const (
+	// scanCollisionWindow is how long the queue collects schedules that fire
+	// together before starting any, so they start in priority order rather
+	// than in the order cron happened to run them.
+	scanCollisionWindow = 2 * time.Second
+
+	// preemptRetryDelay is how long a scan waits before preemption is tried
+	// again when no lower-priority scan could be paused, e.g. because they
+	// were all still enumerating files.
+	preemptRetryDelay = 30 * time.Second
+)
+
+// scanRunner runs and pauses path scans. It is implemented by ScannerService.
+type scanRunner interface {
+	ScanPath(pathID int64, localPath string) error
+	GetActiveScans() []ScanProgressSnapshot
+	PauseScan(scanID string) error
+	ResumeScan(scanID string) error
+}
+
+// queuedScan is a scheduled scan of a scan path.
+type queuedScan struct {
+	scheduleID int
+	pathID     int64
+	localPath  string
+	priority   int
+	queuedAt   time.Time
+
+	// Set while the scan is paused to make room for a higher-priority scan
+	preempted bool
+	scanID    string
+}
+
+// outranks reports whether a should start before b: higher priority first,
+// then the one that has waited longer.
+func (a *queuedScan) outranks(b *queuedScan) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.queuedAt.Before(b.queuedAt)
+}
+
+// scanQueue runs scheduled scans with at most limit running at once. Scans
+// beyond the limit wait and start in priority order; with preemption a
+// higher-priority scan pauses the lowest-priority running one instead of
+// waiting. A path that is already queued or running isn't queued again.
+type scanQueue struct {
+	runner  scanRunner
+	limit   int // 0 runs every scan right away
+	preempt bool
+	window  time.Duration
+
+	mu      sync.Mutex
+	waiting []*queuedScan
+	running []*queuedScan // includes preempted scans
+	timer   *time.Timer
+	stopped bool
+}
+
+func newScanQueue(runner scanRunner) *scanQueue {
+	return &scanQueue{runner: runner, window: scanCollisionWindow}
+}
+
+// configure sets the concurrency limit and preemption.
+func (q *scanQueue) configure(limit int, preempt bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if limit < 0 {
+		limit = 0
+	}
+	q.limit = limit
+	q.preempt = preempt
+}
+
+// submit queues a scan and starts the queued scans after the collision window.
+func (q *scanQueue) submit(scan *queuedScan) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return
+	}
+	if q.has(scan.pathID) {
+		logger.Infof("Scheduled scan of %s skipped: a scan of it is already queued or running", scan.localPath)
+		return
+	}
+
+	scan.queuedAt = time.Now()
+	q.waiting = append(q.waiting, scan)
+	q.schedule(q.window)
+}
+
+// has reports whether a scan of the path is queued or running. Callers must hold q.mu.
+func (q *scanQueue) has(pathID int64) bool {
+	for _, scans := range [][]*queuedScan{q.running, q.waiting} {
+		for _, scan := range scans {
+			if scan.pathID == pathID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schedule dispatches the queue after delay unless a dispatch is pending.
+// Callers must hold q.mu.
+func (q *scanQueue) schedule(delay time.Duration) {
+	if q.timer != nil {
+		return
+	}
+	q.timer = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.timer = nil
+		q.dispatch()
+	})
+}
+
+// stop drops the waiting scans. Running scans stop with the scanner.
+func (q *scanQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.waiting = nil
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+}
+
+// dispatch fills free slots with the highest-ranking preempted and waiting
+// scans, preempting lower-priority scans if enabled. Callers must hold q.mu.
+func (q *scanQueue) dispatch() {
+	if q.stopped {
+		return
+	}
+	sort.SliceStable(q.waiting, func(i, j int) bool { return q.waiting[i].outranks(q.waiting[j]) })
+
+	for {
+		next := q.nextWaiting()
+		if q.limit > 0 && q.active() >= q.limit {
+			if next == nil || !q.preempt {
+				return
+			}
+			if !q.preemptFor(next) {
+				q.schedule(preemptRetryDelay)
+				return
+			}
+			continue
+		}
+
+		if paused := q.bestPreempted(); paused != nil && (next == nil || !next.outranks(paused)) {
+			q.resume(paused)
+			continue
+		}
+		if next == nil {
+			return
+		}
+		q.waiting = q.waiting[1:]
+		q.start(next)
+	}
+}
+
+// nextWaiting returns the highest-ranking waiting scan, or nil.
+func (q *scanQueue) nextWaiting() *queuedScan {
+	if len(q.waiting) == 0 {
+		return nil
+	}
+	return q.waiting[0]
+}
+
+// active counts the running scans that aren't preempted.
+func (q *scanQueue) active() int {
+	n := 0
+	for _, scan := range q.running {
+		if !scan.preempted {
+			n++
+		}
+	}
+	return n
+}
+
+// bestPreempted returns the highest-ranking preempted scan, or nil.
+func (q *scanQueue) bestPreempted() *queuedScan {
+	var best *queuedScan
+	for _, scan := range q.running {
+		if scan.preempted && (best == nil || scan.outranks(best)) {
+			best = scan
+		}
+	}
+	return best
+}
+
+// preemptFor pauses the lowest-priority running scan ranked below next and
+// reports whether one was paused.
+func (q *scanQueue) preemptFor(next *queuedScan) bool {
+	candidates := make([]*queuedScan, 0, len(q.running))
+	for _, scan := range q.running {
+		if !scan.preempted && scan.priority < next.priority {
+			candidates = append(candidates, scan)
+		}
+	}
+	// Lowest priority first; of equal ones the most recently started
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[j].outranks(candidates[i]) })
+
+	active := q.runner.GetActiveScans()
+	for _, victim := range candidates {
+		for _, snapshot := range active {
+			if snapshot.Type != "path" || snapshot.PathID != victim.pathID {
+				continue
+			}
+			if err := q.runner.PauseScan(snapshot.ID); err != nil {
+				logger.Debugf("Could not pause scan of %s for %s: %v", victim.localPath, next.localPath, err)
+				continue
+			}
+			victim.preempted = true
+			victim.scanID = snapshot.ID
+			logger.Infof("Paused scheduled scan of %s (priority %d) for %s (priority %d)",
+				victim.localPath, victim.priority, next.localPath, next.priority)
+			return true
+		}
+	}
+	return false
+}
+
+// resume continues a preempted scan.
+func (q *scanQueue) resume(scan *queuedScan) {
+	scan.preempted = false
+	if err := q.runner.ResumeScan(scan.scanID); err != nil {
+		logger.Debugf("Failed to resume preempted scan of %s: %v", scan.localPath, err)
+		return
+	}
+	logger.Infof("Resumed scheduled scan of %s", scan.localPath)
+}
+
+// start runs a scan in the background and dispatches the queue when it ends.
+func (q *scanQueue) start(scan *queuedScan) {
+	q.running = append(q.running, scan)
+	go func() {
+		logger.Infof("Executing scheduled scan for path: %s (Schedule ID: %d)", scan.localPath, scan.scheduleID)
+		if err := q.runner.ScanPath(scan.pathID, scan.localPath); err != nil {
+			logger.Errorf("Scheduled scan failed for path %s: %v", scan.localPath, err)
+		}
+
+		q.mu.Lock()
+		for i, other := range q.running {
+			if other == scan {
+				q.running = append(q.running[:i], q.running[i+1:]...)
+				break
+			}
+		}
+		q.dispatch()
+		q.mu.Unlock()
+	}()
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScanRunner runs scans until the test finishes them and records the
+// order in which scans start, pause and resume.
+type fakeScanRunner struct {
+	mu      sync.Mutex
+	active  map[int64]chan struct{}
+	events  []string
+	changed chan struct{}
+}
+
+func newFakeScanRunner() *fakeScanRunner {
+	return &fakeScanRunner{active: map[int64]chan struct{}{}, changed: make(chan struct{}, 100)}
+}
+
+func (r *fakeScanRunner) record(event string) {
+	r.events = append(r.events, event)
+	r.changed <- struct{}{}
+}
+
+func (r *fakeScanRunner) ScanPath(pathID int64, localPath string) error {
+	done := make(chan struct{})
+	r.mu.Lock()
+	r.active[pathID] = done
+	r.record("start " + localPath)
+	r.mu.Unlock()
+	<-done
+	return nil
+}
+
+func (r *fakeScanRunner) GetActiveScans() []ScanProgressSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var scans []ScanProgressSnapshot
+	for pathID := range r.active {
+		scans = append(scans, ScanProgressSnapshot{ID: fmt.Sprintf("scan-%d", pathID), Type: "path", PathID: pathID})
+	}
+	return scans
+}
+
+func (r *fakeScanRunner) PauseScan(scanID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record("pause " + scanID)
+	return nil
+}
+
+func (r *fakeScanRunner) ResumeScan(scanID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record("resume " + scanID)
+	return nil
+}
+
+// finish ends the scan of a path.
+func (r *fakeScanRunner) finish(pathID int64) {
+	r.mu.Lock()
+	done := r.active[pathID]
+	delete(r.active, pathID)
+	r.mu.Unlock()
+	close(done)
+}
+
+// waitFor waits until n events were recorded and returns them.
+func (r *fakeScanRunner) waitFor(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		r.mu.Lock()
+		events := append([]string(nil), r.events...)
+		r.mu.Unlock()
+		if len(events) >= n {
+			return events
+		}
+		select {
+		case <-r.changed:
+		case <-deadline:
+			t.Fatalf("Timed out waiting for %d events, got %v", n, events)
+		}
+	}
+}
+
+func assertEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+func newTestScanQueue(limit int, preempt bool) (*scanQueue, *fakeScanRunner) {
+	runner := newFakeScanRunner()
+	q := newScanQueue(runner)
+	q.window = 20 * time.Millisecond
+	q.configure(limit, preempt)
+	return q, runner
+}
+
+func TestScanQueue_PriorityOrder(t *testing.T) {
+	q, runner := newTestScanQueue(1, false)
+	defer q.stop()
+
+	q.submit(&queuedScan{pathID: 1, localPath: "/archive", priority: 0})
+	q.submit(&queuedScan{pathID: 2, localPath: "/movies", priority: 50})
+	q.submit(&queuedScan{pathID: 3, localPath: "/tv", priority: 10})
+	q.submit(&queuedScan{pathID: 2, localPath: "/movies", priority: 50}) // already queued
+
+	assertEvents(t, runner.waitFor(t, 1), "start /movies")
+	runner.finish(2)
+	assertEvents(t, runner.waitFor(t, 2), "start /movies", "start /tv")
+	runner.finish(3)
+	assertEvents(t, runner.waitFor(t, 3), "start /movies", "start /tv", "start /archive")
+	runner.finish(1)
+}
+
+func TestScanQueue_NoLimit(t *testing.T) {
+	q, runner := newTestScanQueue(0, false)
+	defer q.stop()
+
+	q.submit(&queuedScan{pathID: 1, localPath: "/archive", priority: 0})
+	q.submit(&queuedScan{pathID: 2, localPath: "/movies", priority: 50})
+
+	// Both start right away, in no particular order
+	if events := runner.waitFor(t, 2); len(events) != 2 {
+		t.Fatalf("events = %v, want both scans started", events)
+	}
+	runner.finish(1)
+	runner.finish(2)
+}
+
+func TestScanQueue_Preemption(t *testing.T) {
+	q, runner := newTestScanQueue(1, true)
+	defer q.stop()
+
+	q.submit(&queuedScan{pathID: 1, localPath: "/archive", priority: 0})
+	runner.waitFor(t, 1)
+
+	// A higher-priority scan pauses the archive scan, which resumes after it
+	q.submit(&queuedScan{pathID: 2, localPath: "/movies", priority: 50})
+	assertEvents(t, runner.waitFor(t, 3), "start /archive", "pause scan-1", "start /movies")
+
+	// Equal priority doesn't preempt
+	q.submit(&queuedScan{pathID: 3, localPath: "/tv", priority: 50})
+	time.Sleep(50 * time.Millisecond)
+	assertEvents(t, runner.waitFor(t, 3), "start /archive", "pause scan-1", "start /movies")
+
+	runner.finish(2)
+	assertEvents(t, runner.waitFor(t, 4), "start /archive", "pause scan-1", "start /movies", "start /tv")
+	runner.finish(3)
+	assertEvents(t, runner.waitFor(t, 5), "start /archive", "pause scan-1", "start /movies", "start /tv", "resume scan-1")
+	runner.finish(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -14,16 +15,22 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/mescon/Healarr/internal/config"
 	"github.com/mescon/Healarr/internal/db"
 	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/remote"
 )
 
 // scannerQueryTimeout is the maximum time for database queries in scanner service.
 const scannerQueryTimeout = 10 * time.Second
 
+// ErrPathUnscannable is returned by ScanPath when no detection method configured
+// for the path has its tools installed.
+var ErrPathUnscannable = errors.New("scan path is unscannable")
+
 // Default video file extensions to scan
 var defaultVideoExtensions = map[string]bool{
 	".mkv":  true,
@@ -208,6 +215,7 @@ type ScanProgress struct {
 	isPaused        bool               `json:"-"`                    // Track pause state
 	corruptionCount int                `json:"-"`                    // Track corruptions found in this scan for throttling
 	isThrottled     bool               `json:"-"`                    // Whether this scan is being throttled
+	checkpointAt    time.Time          `json:"-"`                    // When the scan's position was last saved
 }
 
 // ScanProgressSnapshot is a read-only copy of ScanProgress suitable for API
@@ -231,6 +239,9 @@ type scanPathConfig struct {
 	LocalPath     string
 	AutoRemediate bool
 	DryRun        bool
+	Filter        mediaFilter
+	DurationCheck string
+	MinConfidence int
 }
 
 // resumeScanConfig holds all parameters needed to resume an interrupted scan
@@ -248,12 +259,34 @@ type resumeScanConfig struct {
 
 // scanFilesConfig holds configuration for the main scan loop
 type scanFilesConfig struct {
-	Files           []string
-	StartIndex      int
+	Files      *fileQueue
+	StartIndex int
+	// Checked holds the files a resumed scan finished after its last
+	// checkpoint, which are skipped
+	Checked         map[string]bool
 	DetectionConfig integration.DetectionConfig
 	AutoRemediate   bool
 	DryRun          bool
 	ScanDBID        int64
+	Stability       fileStabilityConfig
+	Filter          mediaFilter
+	DurationCheck   string
+	MinConfidence   int
+	// Remote is the connection of a remote scan path, nil for local paths
+	Remote *remoteScan
+}
+
+// Defaults for detecting files that are still being written or copied.
+const (
+	defaultMinFileAge        = 2 * time.Minute
+	defaultSizeStabilityWait = 500 * time.Millisecond
+)
+
+// fileStabilityConfig controls how path scans detect files that are still being
+// written (partial rsync, in-progress imports). Zero values use the defaults.
+type fileStabilityConfig struct {
+	MinFileAge        time.Duration // Skip files modified more recently than this; negative disables the check
+	SizeStabilityWait time.Duration // Delay between the two size checks
 }
 
 // Scanner defines the interface for scan operations.
@@ -273,6 +306,7 @@ type Scanner interface {
 // ScannerService manages file scanning operations for corruption detection.
 type ScannerService struct {
 	db          *sql.DB
+	stmts       *db.StmtCache // statements run for every corrupt file
 	eventBus    *eventbus.EventBus
 	detector    integration.HealthChecker
 	pathMapper  integration.PathMapper
@@ -289,21 +323,156 @@ type ScannerService struct {
 	scanPathCache     []scanPathConfig
 	scanPathCacheMu   sync.RWMutex
 	scanPathCacheTime time.Time
+
+	// falsePositives suppresses findings users marked as false positive (nil disables)
+	falsePositives *FalsePositiveService
+
+	// tools gates path scans on the detection tools being installed (nil disables)
+	tools *integration.ToolChecker
+
+	// scanResultsPerPath is how many recent scans per path keep their
+	// scan_files rows (0 keeps all)
+	scanResultsPerPath int
+
+	// hdrMetadataPolicy is what happens to files with broken HDR metadata
+	hdrMetadataPolicy string
+	// consensus is how many detectors must agree on a corruption (empty: no re-check)
+	consensus integration.ConsensusPolicy
+
+	// arrClient looks up expected runtimes for the duration check (nil disables)
+	arrClient integration.ArrClient
+
+	// fileQueueMemory is how many file paths a path scan keeps in memory
+	// before spilling its file list to the database (0 never spills)
+	fileQueueMemory int
+
+	// samples extracts a sample of every corrupt file found (nil disables)
+	samples *SampleExtractor
+
+	// checkpointFiles and checkpointInterval are how often a path scan saves
+	// its position, see SetScanCheckpoint
+	checkpointFiles    int
+	checkpointInterval time.Duration
+	// startedAt is when the service was created; scans still running in the
+	// database from before then were cut off by a crash
+	startedAt time.Time
 }
 
 // NewScannerService creates a new ScannerService with the given dependencies.
-func NewScannerService(db *sql.DB, eb *eventbus.EventBus, detector integration.HealthChecker, pm integration.PathMapper) *ScannerService {
+func NewScannerService(database *sql.DB, eb *eventbus.EventBus, detector integration.HealthChecker, pm integration.PathMapper) *ScannerService {
 	return &ScannerService{
-		db:              db,
+		db:              database,
+		stmts:           db.NewStmtCache(database),
 		eventBus:        eb,
 		detector:        detector,
 		pathMapper:      pm,
 		activeScans:     make(map[string]*ScanProgress),
 		filesInProgress: make(map[string]bool),
 		shutdownCh:      make(chan struct{}),
+		falsePositives:    NewFalsePositiveService(database),
+		hdrMetadataPolicy: config.HDRMetadataFlag,
+		fileQueueMemory:   defaultFileQueueMemory,
+		checkpointFiles:    defaultCheckpointFiles,
+		checkpointInterval: defaultCheckpointInterval,
+		startedAt:          time.Now(),
+	}
+}
+
+// SetToolChecker makes path scans check that the tools their detection profile
+// needs are installed. Scans of paths that can't run are skipped with a
+// ScanSkipped event instead of failing on every file.
+func (s *ScannerService) SetToolChecker(tc *integration.ToolChecker) {
+	s.tools = tc
+}
+
+// SetFalsePositiveSuppression enables or disables skipping findings that match
+// a recorded false positive.
+func (s *ScannerService) SetFalsePositiveSuppression(enabled bool) {
+	if enabled {
+		s.falsePositives = NewFalsePositiveService(s.db)
+	} else {
+		s.falsePositives = nil
+	}
+}
+
+// SetScanResultsRetention sets how many of each path's most recent scans keep
+// their per-file results. Older scans are pruned to their summary when a scan
+// of the path finishes. 0 keeps all file results.
+func (s *ScannerService) SetScanResultsRetention(perPath int) {
+	s.scanResultsPerPath = perPath
+}
+
+// SetHDRMetadataPolicy sets what happens to files that decode cleanly but have
+// missing or inconsistent HDR10/Dolby Vision metadata: config.HDRMetadataFlag
+// records an HDRMetadata corruption without remediating it,
+// config.HDRMetadataRemediate follows the path's auto-remediate setting and
+// config.HDRMetadataOff skips the check.
+func (s *ScannerService) SetHDRMetadataPolicy(policy string) {
+	s.hdrMetadataPolicy = policy
+}
+
+// SetDetectionConsensus sets how many detection tools must find a file
+// corrupt before a scan reports it: config.ConsensusAny, ConsensusMajority or
+// ConsensusAll re-check every corrupt file with the path's other tools,
+// config.ConsensusOff doesn't.
+func (s *ScannerService) SetDetectionConsensus(policy string) {
+	if policy == config.ConsensusOff {
+		policy = ""
+	}
+	s.consensus = integration.ConsensusPolicy(policy)
+}
+
+// SetFileQueueMemory sets how many file paths a path scan keeps in memory.
+// Longer file lists are spilled to the database while the directory is walked
+// and read back in pages of this size. 0 keeps every file list in memory.
+func (s *ScannerService) SetFileQueueMemory(files int) {
+	s.fileQueueMemory = files
+}
+
+// SetSampleExtractor makes the scanner copy a few MB around the first error of
+// every file it finds corrupt, before remediation replaces the file. nil
+// disables it.
+func (s *ScannerService) SetSampleExtractor(x *SampleExtractor) {
+	s.samples = x
+}
+
+// pruneScanFiles deletes the per-file results of finished scans of a path
+// beyond the most recent scanResultsPerPath.
+func (s *ScannerService) pruneScanFiles(pathID int64) {
+	if s.scanResultsPerPath <= 0 || pathID <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM scan_files WHERE scan_id IN (
+			SELECT id FROM scans
+			WHERE path_id = ? AND status NOT IN ('pending', 'running', 'paused')
+			AND id NOT IN (SELECT id FROM scans WHERE path_id = ? ORDER BY id DESC LIMIT ?)
+		)
+	`, pathID, pathID, s.scanResultsPerPath)
+	if err != nil {
+		logger.Warnf("Failed to prune scan file results for path %d: %v", pathID, err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		logger.Debugf("Pruned %d scan file results for path %d", n, pathID)
 	}
 }
 
+// isKnownFalsePositive reports whether a finding was previously marked as a
+// false positive for the same file content.
+func (s *ScannerService) isKnownFalsePositive(filePath string, healthErr *integration.HealthCheckError) bool {
+	if s.falsePositives == nil || healthErr == nil {
+		return false
+	}
+	if s.falsePositives.IsSuppressed(filePath, healthErr.Type, healthErr.Message) {
+		logger.Infof("Suppressing finding previously marked as false positive (%s): %s", healthErr.Type, filePath)
+		return true
+	}
+	return false
+}
+
 // IsFileBeingScanned returns true if the given file is currently being scanned.
 // This can be used by other services (like the verifier) to avoid race conditions.
 func (s *ScannerService) IsFileBeingScanned(localPath string) bool {
@@ -369,17 +538,24 @@ func (s *ScannerService) Shutdown() {
 	logger.Infof("Scanner: shutdown complete")
 }
 
-// ResumeInterruptedScans checks for scans that were interrupted by shutdown and resumes them.
+// ResumeInterruptedScans checks for scans that were interrupted by shutdown,
+// or left running by a crash, and resumes them. Scans stopped before they
+// finished listing their files are marked failed instead.
 func (s *ScannerService) ResumeInterruptedScans() {
 	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
 	defer cancel()
 
+	startedAt := s.startedAt.UTC().Format(time.DateTime)
+	s.failUnlistedScans(ctx, startedAt)
+
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT s.id, s.path_id, s.path, s.total_files, s.current_file_index, s.file_list, s.detection_config, s.auto_remediate, COALESCE(s.dry_run, 0)
 		FROM scans s
-		WHERE s.status = 'interrupted' AND s.file_list IS NOT NULL
+		WHERE (s.status = 'interrupted' OR (s.status = 'running' AND s.started_at < ?))
+			AND s.total_files > 0
+			AND (s.file_list IS NOT NULL OR EXISTS (SELECT 1 FROM scan_file_queue q WHERE q.scan_id = s.id))
 		ORDER BY s.started_at DESC
-	`)
+	`, startedAt)
 	if err != nil {
 		logger.Errorf("Failed to query interrupted scans: %v", err)
 		return
@@ -411,15 +587,17 @@ func (s *ScannerService) ResumeInterruptedScans() {
 			dryRun          bool
 		}
 		var pathID sql.NullInt64
-		var detectionConfigNull sql.NullString
+		var fileListNull, detectionConfigNull sql.NullString
 
-		if err := rows.Scan(&scan.scanDBID, &pathID, &scan.path, &scan.totalFiles, &scan.currentIndex, &scan.fileListJSON, &detectionConfigNull, &scan.autoRemediate, &scan.dryRun); err != nil {
+		if err := rows.Scan(&scan.scanDBID, &pathID, &scan.path, &scan.totalFiles, &scan.currentIndex, &fileListNull, &detectionConfigNull, &scan.autoRemediate, &scan.dryRun); err != nil {
 			logger.Errorf("Failed to scan interrupted scan row: %v", err)
 			continue
 		}
 		if pathID.Valid {
 			scan.pathID = pathID.Int64
 		}
+		// A file list spilled to scan_file_queue isn't stored in file_list
+		scan.fileListJSON = fileListNull.String
 		if detectionConfigNull.Valid {
 			scan.detectionConfig = detectionConfigNull.String
 		}
@@ -448,6 +626,40 @@ func (s *ScannerService) ResumeInterruptedScans() {
 	}
 }
 
+// failUnlistedScans marks the interrupted scans that hadn't finished listing
+// their files as failed, and discards their partial file lists. Resuming one
+// would complete it without the files that were never listed.
+func (s *ScannerService) failUnlistedScans(ctx context.Context, startedAt string) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id FROM scans
+		WHERE (status = 'interrupted' OR (status = 'running' AND started_at < ?))
+			AND COALESCE(total_files, 0) = 0
+	`, startedAt)
+	if err != nil {
+		logger.Errorf("Failed to query unlisted interrupted scans: %v", err)
+		return
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if _, err := s.db.ExecContext(ctx, `
+			UPDATE scans SET status = 'error', error_message = ?, completed_at = datetime('now') WHERE id = ?
+		`, "Interrupted before all files were listed", id); err != nil {
+			logger.Errorf("Failed to mark scan %d as failed: %v", id, err)
+			continue
+		}
+		discardFileQueue(s.db, id)
+		logger.Warnf("Not resuming scan %d: it was interrupted before all files were listed", id)
+	}
+}
+
 // resumeScan continues a previously interrupted scan
 func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -456,11 +668,17 @@ func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 	s.wg.Add(1)
 	defer s.wg.Done()
 
-	// Parse file list
-	var files []string
-	if err := json.Unmarshal([]byte(cfg.FileListJSON), &files); err != nil {
-		logger.Errorf("Failed to parse file list for resumed scan: %v", err)
-		return
+	// Parse file list, or read it back from scan_file_queue if it was spilled
+	var files *fileQueue
+	if cfg.FileListJSON == "" {
+		files = spilledFileQueue(s.db, cfg.ScanDBID, s.fileQueueMemory, cfg.TotalFiles)
+	} else {
+		var list []string
+		if err := json.Unmarshal([]byte(cfg.FileListJSON), &list); err != nil {
+			logger.Errorf("Failed to parse file list for resumed scan: %v", err)
+			return
+		}
+		files = memoryFileQueue(list)
 	}
 
 	// Parse detection config
@@ -496,7 +714,7 @@ func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 		StartTime:   time.Now().Format(time.RFC3339),
 		ScanDBID:    cfg.ScanDBID,
 		pauseChan:   make(chan struct{}),
-		resumeChan:  make(chan struct{}),
+		resumeChan:  make(chan struct{}, 1),
 		isPaused:    false,
 	}
 	progress.cancel = cancel
@@ -527,6 +745,10 @@ func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 		if err != nil {
 			logger.Errorf("Failed to update scan record: %v", err)
 		}
+		if finalStatus != "interrupted" {
+			files.Discard()
+		}
+		s.pruneScanFiles(cfg.PathID)
 
 		s.mu.Lock()
 		delete(s.activeScans, scanID)
@@ -538,6 +760,7 @@ func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 			EventType:     "ScanCompleted",
 			EventData: map[string]interface{}{
 				"scan_id": scanID,
+				"path_id": cfg.PathID,
 				"status":  finalStatus,
 				"resumed": true,
 			},
@@ -549,14 +772,34 @@ func (s *ScannerService) resumeScan(cfg resumeScanConfig) {
 	s.emitProgress(progress)
 	logger.Infof("Resumed scan %s for %s at file %d/%d", scanID, cfg.LocalPath, cfg.StartIndex, cfg.TotalFiles)
 
+	// File age, size, duration and remote settings are not stored with the scan, use the path's current ones
+	settings := s.loadScanPathSettings(cfg.PathID)
+	var rs *remoteScan
+	if settings.Remote != nil {
+		backend, err := remote.Open(ctx, settings.Remote.Config)
+		if err != nil {
+			logger.Errorf("Cannot resume scan of remote path %s: %v", cfg.LocalPath, err)
+			progress.Status = "interrupted"
+			return
+		}
+		defer backend.Close()
+		rs = &remoteScan{backend: backend, path: *settings.Remote}
+	}
+
 	// Continue scanning from where we left off
 	s.scanFiles(ctx, progress, scanFilesConfig{
 		Files:           files,
 		StartIndex:      cfg.StartIndex,
+		Checked:         s.checkedSinceCheckpoint(cfg.ScanDBID),
 		DetectionConfig: detectionConfig,
 		AutoRemediate:   cfg.AutoRemediate,
 		DryRun:          cfg.DryRun,
 		ScanDBID:        cfg.ScanDBID,
+		Stability:       settings.Stability,
+		Filter:          settings.Filter,
+		DurationCheck:   settings.DurationCheck,
+		MinConfidence:   settings.MinConfidence,
+		Remote:          rs,
 	})
 }
 
@@ -619,12 +862,16 @@ func (s *ScannerService) ScanFile(localPath string) error {
 	logger.Infof("Scan started for file: %s (ID: %s)", localPath, scanID)
 
 	// Find scan path config for this file
-	autoRemediate, dryRun, err := s.getScanPathConfig(localPath)
+	pathCfg, err := s.matchScanPathConfig(localPath)
 	if err != nil {
 		// Log warning but proceed with defaults (false, false)
 		// This is important for ops visibility - file scanned without matching path config
 		logger.Warnf("Could not determine scan path config for %s: %v (using defaults: auto_remediate=false, dry_run=false)", localPath, err)
+	} else if !pathCfg.Filter.includes(localPath) {
+		logger.Debugf("Skipping scan for %s - extension not in the path's media extensions", localPath)
+		return nil
 	}
+	autoRemediate, dryRun := pathCfg.AutoRemediate, pathCfg.DryRun
 
 	logger.Infof("Scanning single file: %s", localPath)
 
@@ -639,7 +886,13 @@ func (s *ScannerService) ScanFile(localPath string) error {
 	}
 
 	// Use quick mode for single file scans (called from webhooks)
-	healthy, healthErr := s.detector.Check(localPath, "quick")
+	healthy, healthErr := s.quickCheck(localPath)
+	if healthy {
+		healthy, healthErr = pathCfg.Filter.checkSize(fileSize)
+	}
+	if healthy {
+		healthy, healthErr = s.checkRuntime(localPath, pathCfg.DurationCheck)
+	}
 
 	progress.FilesDone = 1
 	s.emitProgress(progress)
@@ -652,9 +905,16 @@ func (s *ScannerService) ScanFile(localPath string) error {
 			// Don't emit corruption event for recoverable errors
 			return nil
 		}
+		if healthErr.Type == integration.ErrorTypeNoConsensus {
+			logger.Infof("Not reporting corruption of %s: %s", localPath, healthErr.Message)
+			return nil
+		}
 
 		// This is TRUE corruption - emit event for remediation
 		logger.Infof("Corruption detected in file: %s (Type: %s)", localPath, healthErr.Type)
+		if healthErr.Type == integration.ErrorTypeDurationMismatch && !durationMismatchRemediates(pathCfg.DurationCheck) {
+			autoRemediate = false
+		}
 
 		// DEDUPLICATION: Check if this file already has an active corruption record
 		if s.hasActiveCorruption(localPath) {
@@ -662,25 +922,34 @@ func (s *ScannerService) ScanFile(localPath string) error {
 			return nil
 		}
 
+		if s.isKnownFalsePositive(localPath, healthErr) {
+			return nil
+		}
+
+		eventData := map[string]interface{}{
+			"file_path":       localPath,
+			"file_size":       fileSize,
+			"corruption_type": healthErr.Type,
+			"error_details":   healthErr.Message,
+			"media_type":      string(getMediaType(localPath)),
+			"source":          "webhook",
+			"auto_remediate":  autoRemediate,
+			"dry_run":         dryRun,
+		}
+		addConfidence(eventData, healthErr, pathCfg.MinConfidence)
+
 		// Emit event - critical entry point for remediation journey, use retry
+		corruptionID := uuid.New().String()
 		err := s.eventBus.PublishWithRetry(domain.Event{
 			AggregateType: "corruption",
-			AggregateID:   uuid.New().String(),
+			AggregateID:   corruptionID,
 			EventType:     domain.CorruptionDetected,
-			EventData: map[string]interface{}{
-				"file_path":       localPath,
-				"file_size":       fileSize,
-				"corruption_type": healthErr.Type,
-				"error_details":   healthErr.Message,
-				"media_type":      string(getMediaType(localPath)),
-				"source":          "webhook",
-				"auto_remediate":  autoRemediate,
-				"dry_run":         dryRun,
-			},
+			EventData:     eventData,
 		})
 		if err != nil {
 			return err
 		}
+		s.recordCorruptionDiagnostics(corruptionID, localPath, healthErr)
 	}
 	return nil
 }
@@ -694,21 +963,38 @@ type scanPathSettings struct {
 	AutoRemediate   bool
 	DryRun          bool
 	DetectionConfig integration.DetectionConfig
+	Stability       fileStabilityConfig
+	Filter          mediaFilter
+	DurationCheck   string
+	SymlinkPolicy   string
+	// MinConfidence is the detection confidence a corruption needs to be
+	// remediated automatically; 0 remediates every finding
+	MinConfidence int
+	// Remote is set for paths scanned over WebDAV or SFTP
+	Remote *remote.Path
 }
 
 // loadScanPathSettings loads the scan configuration from the database
 func (s *ScannerService) loadScanPathSettings(pathID int64) scanPathSettings {
 	var autoRemediate, dryRun bool
 	var detectionMethod, detectionMode string
-	var detectionArgsJSON sql.NullString
+	var detectionArgsJSON, detectionFallbacksJSON sql.NullString
+	var minFileAgeMinutes, sizeStabilitySeconds sql.NullInt64
+	var mediaExtensions, durationCheck, symlinkPolicy string
+	var minFileSizeMB int64
+	var minConfidence int
 
 	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
 	defer cancel()
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT auto_remediate, dry_run, detection_method, detection_args, detection_mode
+		SELECT auto_remediate, dry_run, detection_method, detection_args, detection_mode, detection_fallbacks,
+			min_file_age_minutes, size_stability_seconds, media_extensions, min_file_size_mb, duration_check, min_confidence,
+			symlink_policy
 		FROM scan_paths WHERE id = ?
-	`, pathID).Scan(&autoRemediate, &dryRun, &detectionMethod, &detectionArgsJSON, &detectionMode)
+	`, pathID).Scan(&autoRemediate, &dryRun, &detectionMethod, &detectionArgsJSON, &detectionMode, &detectionFallbacksJSON,
+		&minFileAgeMinutes, &sizeStabilitySeconds, &mediaExtensions, &minFileSizeMB, &durationCheck, &minConfidence,
+		&symlinkPolicy)
 
 	if err != nil {
 		logger.Errorf("Error querying scan path config: %v", err)
@@ -723,6 +1009,13 @@ func (s *ScannerService) loadScanPathSettings(pathID int64) scanPathSettings {
 		}
 	}
 
+	var remotePath *remote.Path
+	if p, ok, err := remote.LoadPath(ctx, s.db, pathID); err != nil {
+		logger.Errorf("Error loading remote settings of scan path %d: %v", pathID, err)
+	} else if ok {
+		remotePath = &p
+	}
+
 	method := integration.DetectionMethod(detectionMethod)
 	return scanPathSettings{
 		AutoRemediate: autoRemediate,
@@ -731,22 +1024,68 @@ func (s *ScannerService) loadScanPathSettings(pathID int64) scanPathSettings {
 			Method:    method,
 			Args:      detectionArgs,
 			Mode:      detectionMode,
-			Fallbacks: integration.DefaultFallbacksFor(method),
+			Fallbacks: parseDetectionFallbacks(detectionFallbacksJSON, method),
+			// Cross-check findings with the other detectors when the path
+			// only remediates confident ones
+			Confirm: minConfidence > 0,
 		},
+		Stability: parseFileStability(minFileAgeMinutes, sizeStabilitySeconds),
+		Filter:        newMediaFilter(mediaExtensions, minFileSizeMB),
+		DurationCheck: durationCheck,
+		SymlinkPolicy: symlinkPolicy,
+		MinConfidence: minConfidence,
+		Remote:        remotePath,
+	}
+}
+
+// parseFileStability converts the per-path file age settings to a
+// fileStabilityConfig. NULL keeps the defaults; a minimum age of 0 minutes
+// disables the age check.
+func parseFileStability(minFileAgeMinutes, sizeStabilitySeconds sql.NullInt64) fileStabilityConfig {
+	var cfg fileStabilityConfig
+	if minFileAgeMinutes.Valid {
+		if minFileAgeMinutes.Int64 <= 0 {
+			cfg.MinFileAge = -1
+		} else {
+			cfg.MinFileAge = time.Duration(minFileAgeMinutes.Int64) * time.Minute
+		}
+	}
+	if sizeStabilitySeconds.Valid && sizeStabilitySeconds.Int64 > 0 {
+		cfg.SizeStabilityWait = time.Duration(sizeStabilitySeconds.Int64) * time.Second
+	}
+	return cfg
+}
+
+// parseDetectionFallbacks returns the user's preferred fallback order for a
+// scan path. NULL (or unparseable JSON) keeps the built-in chain for the
+// primary method; an explicit empty list disables fallbacks.
+func parseDetectionFallbacks(raw sql.NullString, primary integration.DetectionMethod) []integration.DetectionMethod {
+	if !raw.Valid || raw.String == "" {
+		return integration.DefaultFallbacksFor(primary)
+	}
+	var methods []integration.DetectionMethod
+	if err := json.Unmarshal([]byte(raw.String), &methods); err != nil {
+		logger.Errorf("Error parsing detection fallbacks: %v", err)
+		return integration.DefaultFallbacksFor(primary)
 	}
+	if methods == nil {
+		methods = []integration.DetectionMethod{}
+	}
+	return methods
 }
 
 // walkStats tracks statistics during directory enumeration
 type walkStats struct {
-	files        []string
-	skippedCount int
-	symlinkCount int
+	skippedCount   int
+	symlinkCount   int // symlinks not followed
+	followedCount  int // media files queued through a symlink
+	duplicateCount int // files and directories reached a second time
 }
 
 // classifyEntry determines whether a file should be included as a media file.
 // Uses fs.DirEntry to correctly detect symlinks (unlike os.FileInfo from filepath.Walk).
 // Returns: (isMedia, isSkipped, isSymlink)
-func classifyEntry(filePath string, d fs.DirEntry) (isMedia, isSkipped, isSymlink bool) {
+func classifyEntry(filePath string, d fs.DirEntry, filter mediaFilter) (isMedia, isSkipped, isSymlink bool) {
 	// DirEntry.Type() correctly returns ModeSymlink for symlinks
 	if d.Type()&os.ModeSymlink != 0 {
 		return false, false, true
@@ -757,38 +1096,32 @@ func classifyEntry(filePath string, d fs.DirEntry) (isMedia, isSkipped, isSymlin
 	if isHiddenOrTempFile(filePath) {
 		return false, true, false
 	}
-	if isMediaFile(filePath) {
+	if filter.includes(filePath) {
 		return true, false, false
 	}
 	return false, true, false
 }
 
-// enumerateMediaFiles walks the directory and returns a list of media files.
-// Uses filepath.WalkDir to correctly detect symlinks.
-func (s *ScannerService) enumerateMediaFiles(localPath string) ([]string, error) {
-	stats := walkStats{}
-
-	err := filepath.WalkDir(localPath, func(filePath string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return s.handleWalkError(filePath, err)
-		}
-		isMedia, isSkipped, isSymlink := classifyEntry(filePath, d)
-		switch {
-		case isSymlink:
-			stats.symlinkCount++
-		case isSkipped:
-			stats.skippedCount++
-		case isMedia:
-			stats.files = append(stats.files, filePath)
-		}
-		return nil
-	})
+// enumerateMediaFiles walks the directory and adds the media files the path's
+// filter includes to the queue as they are found. Symlinks are handled by the
+// path's symlink policy.
+func (s *ScannerService) enumerateMediaFiles(localPath string, filter mediaFilter, symlinks string, queue *fileQueue) error {
+	w := newMediaWalker(localPath, symlinks, filter, queue, s.handleWalkError)
+	err := w.walk(localPath)
+	if err == nil {
+		err = queue.Seal()
+	}
 
-	if err == nil && (stats.skippedCount > 0 || stats.symlinkCount > 0) {
-		logger.Debugf("Skipped %d non-media/hidden files and %d symlinks in %s", stats.skippedCount, stats.symlinkCount, localPath)
+	stats := w.stats
+	if err == nil && (stats.skippedCount > 0 || stats.symlinkCount > 0 || stats.duplicateCount > 0) {
+		logger.Debugf("Skipped %d non-media/hidden files, %d symlinks and %d files or directories reached twice in %s",
+			stats.skippedCount, stats.symlinkCount, stats.duplicateCount, localPath)
+	}
+	if err == nil && stats.followedCount > 0 {
+		logger.Debugf("Queued %d media files through symlinks in %s", stats.followedCount, localPath)
 	}
 
-	return stats.files, err
+	return err
 }
 
 // handleWalkError handles errors during file system traversal
@@ -800,14 +1133,9 @@ func (s *ScannerService) handleWalkError(filePath string, err error) error {
 	return err
 }
 
-// recordScanStart inserts the scan record into the database and returns the scan ID
-func (s *ScannerService) recordScanStart(localPath string, pathID int64, files []string, cfg scanPathSettings) int64 {
-	fileListJSON, err := json.Marshal(files)
-	if err != nil {
-		logger.Errorf("Failed to serialize file list: %v", err)
-		fileListJSON = []byte("[]")
-	}
-
+// recordScanStart inserts the scan record into the database and returns the scan ID.
+// The file list is recorded with recordScanFiles once the path is enumerated.
+func (s *ScannerService) recordScanStart(localPath string, pathID int64, cfg scanPathSettings) int64 {
 	detectionConfigJSON, err := json.Marshal(cfg.DetectionConfig)
 	if err != nil {
 		logger.Errorf("Failed to serialize detection config: %v", err)
@@ -818,9 +1146,9 @@ func (s *ScannerService) recordScanStart(localPath string, pathID int64, files [
 	defer cancel()
 
 	result, err := s.db.ExecContext(ctx, `
-		INSERT INTO scans (path, path_id, status, files_scanned, corruptions_found, total_files, current_file_index, file_list, detection_config, auto_remediate, dry_run, started_at)
-		VALUES (?, ?, 'running', 0, 0, ?, 0, ?, ?, ?, ?, datetime('now'))
-	`, localPath, pathID, len(files), string(fileListJSON), string(detectionConfigJSON), cfg.AutoRemediate, cfg.DryRun)
+		INSERT INTO scans (path, path_id, status, files_scanned, corruptions_found, total_files, current_file_index, detection_config, auto_remediate, dry_run, started_at)
+		VALUES (?, ?, 'running', 0, 0, 0, 0, ?, ?, ?, datetime('now'))
+	`, localPath, pathID, string(detectionConfigJSON), cfg.AutoRemediate, cfg.DryRun)
 
 	if err != nil {
 		logger.Errorf("Failed to record scan start: %v", err)
@@ -835,6 +1163,43 @@ func (s *ScannerService) recordScanStart(localPath string, pathID int64, files [
 	return scanDBID
 }
 
+// recordScanFiles stores the number of files of a scan and, unless it was
+// spilled to scan_file_queue, the file list to resume from after a restart.
+func (s *ScannerService) recordScanFiles(scanDBID int64, files *fileQueue) {
+	if scanDBID <= 0 {
+		return
+	}
+	var fileList sql.NullString
+	if !files.Spilled() {
+		fileListJSON, err := json.Marshal(files.Files())
+		if err != nil {
+			logger.Errorf("Failed to serialize file list: %v", err)
+			fileListJSON = []byte("[]")
+		}
+		fileList = sql.NullString{String: string(fileListJSON), Valid: true}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	if _, err := s.db.ExecContext(ctx, `UPDATE scans SET total_files = ?, file_list = ? WHERE id = ?`, files.Len(), fileList, scanDBID); err != nil {
+		logger.Errorf("Failed to record the file list of scan %d: %v", scanDBID, err)
+	}
+}
+
+// discardScanRecord deletes the record of a scan that failed before it
+// started scanning files, along with its spilled file list.
+func (s *ScannerService) discardScanRecord(scanDBID int64) {
+	if scanDBID <= 0 {
+		return
+	}
+	discardFileQueue(s.db, scanDBID)
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM scans WHERE id = ?`, scanDBID); err != nil {
+		logger.Warnf("Failed to delete the record of scan %d: %v", scanDBID, err)
+	}
+}
+
 // handlePathInaccessible reports that a path is not accessible
 func (s *ScannerService) handlePathInaccessible(scanID, localPath string, accessErr error) error {
 	s.mu.Lock()
@@ -875,6 +1240,8 @@ func (s *ScannerService) finalizeScan(scanID string, progress *ScanProgress, sca
 			if err != nil {
 				logger.Errorf("Failed to update scan record: %v", err)
 			}
+			discardFileQueue(s.db, scanDBID)
+			s.pruneScanFiles(progress.PathID)
 		}
 	}
 
@@ -888,6 +1255,7 @@ func (s *ScannerService) finalizeScan(scanID string, progress *ScanProgress, sca
 		EventType:     "ScanCompleted",
 		EventData: map[string]interface{}{
 			"scan_id": scanID,
+			"path_id": progress.PathID,
 			"status":  progress.Status,
 		},
 	}); err != nil {
@@ -895,8 +1263,41 @@ func (s *ScannerService) finalizeScan(scanID string, progress *ScanProgress, sca
 	}
 }
 
+// skipUnscannablePath reports a scan that can't run because none of the
+// detection methods configured for the path have their tools installed.
+func (s *ScannerService) skipUnscannablePath(pathID int64, localPath string, capability integration.ScanCapability) error {
+	reason := fmt.Sprintf("missing detection tools: %s", strings.Join(capability.MissingTools, ", "))
+	logger.Warnf("Skipping scan of %s: %s", localPath, reason)
+
+	missing := make([]interface{}, len(capability.MissingTools))
+	for i, tool := range capability.MissingTools {
+		missing[i] = tool
+	}
+	if err := s.eventBus.Publish(domain.Event{
+		AggregateType: "scan_path",
+		AggregateID:   fmt.Sprintf("%d", pathID),
+		EventType:     domain.ScanSkipped,
+		EventData: map[string]interface{}{
+			"path":          localPath,
+			"path_id":       pathID,
+			"reason":        reason,
+			"missing_tools": missing,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish ScanSkipped event for %s: %v", localPath, err)
+	}
+	return fmt.Errorf("%w: %s", ErrPathUnscannable, reason)
+}
+
 // ScanPath scans all media files in the given directory path for corruption.
 func (s *ScannerService) ScanPath(pathID int64, localPath string) error {
+	// Load configuration
+	cfg := s.loadScanPathSettings(pathID)
+	// Remote paths are checked without detection tools
+	if capability := s.tools.CapabilityFor(cfg.DetectionConfig); !capability.Scannable && cfg.Remote == nil {
+		return s.skipUnscannablePath(pathID, localPath, capability)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -922,7 +1323,7 @@ func (s *ScannerService) ScanPath(pathID int64, localPath string) error {
 		Status:      "enumerating",
 		StartTime:   time.Now().Format(time.RFC3339),
 		pauseChan:   make(chan struct{}),
-		resumeChan:  make(chan struct{}),
+		resumeChan:  make(chan struct{}, 1),
 		isPaused:    false,
 	}
 	progress.cancel = cancel
@@ -933,30 +1334,45 @@ func (s *ScannerService) ScanPath(pathID int64, localPath string) error {
 
 	s.emitProgress(progress)
 
-	// Load configuration
-	cfg := s.loadScanPathSettings(pathID)
 	logger.Infof("Starting scan for path ID %d: %s", pathID, localPath)
 
-	// Pre-flight check
-	if err := s.verifyPathAccessible(localPath); err != nil {
-		logger.Errorf("Pre-flight check failed for path %s: %v - scan aborted", localPath, err)
-		return s.handlePathInaccessible(scanID, localPath, err)
-	}
+	var files *fileQueue
+	var rs *remoteScan
+	var scanDBID int64
+	if cfg.Remote != nil {
+		// Connecting and listing is the pre-flight check of a remote path
+		var list []string
+		var err error
+		if rs, list, err = s.openRemoteScan(ctx, *cfg.Remote, cfg.Filter); err != nil {
+			logger.Errorf("Pre-flight check failed for remote path %s: %v - scan aborted", localPath, err)
+			return s.handlePathInaccessible(scanID, localPath, err)
+		}
+		defer rs.backend.Close()
+		files = memoryFileQueue(list)
+		scanDBID = s.recordScanStart(localPath, pathID, cfg)
+	} else {
+		// Pre-flight check
+		if err := s.verifyPathAccessible(localPath); err != nil {
+			logger.Errorf("Pre-flight check failed for path %s: %v - scan aborted", localPath, err)
+			return s.handlePathInaccessible(scanID, localPath, err)
+		}
 
-	// Enumerate files
-	files, err := s.enumerateMediaFiles(localPath)
-	if err != nil {
-		s.mu.Lock()
-		delete(s.activeScans, scanID)
-		s.mu.Unlock()
-		return err
+		// Enumerate files; the scan record must exist to spill the file list to
+		scanDBID = s.recordScanStart(localPath, pathID, cfg)
+		files = newFileQueue(s.db, scanDBID, s.fileQueueMemory)
+		if err := s.enumerateMediaFiles(localPath, cfg.Filter, cfg.SymlinkPolicy, files); err != nil {
+			s.discardScanRecord(scanDBID)
+			s.mu.Lock()
+			delete(s.activeScans, scanID)
+			s.mu.Unlock()
+			return err
+		}
 	}
 
-	progress.TotalFiles = len(files)
+	progress.TotalFiles = files.Len()
 	progress.Status = "scanning"
 
-	// Record scan start
-	scanDBID := s.recordScanStart(localPath, pathID, files, cfg)
+	s.recordScanFiles(scanDBID, files)
 	progress.ScanDBID = scanDBID
 	s.emitProgress(progress)
 
@@ -970,6 +1386,11 @@ func (s *ScannerService) ScanPath(pathID int64, localPath string) error {
 		AutoRemediate:   cfg.AutoRemediate,
 		DryRun:          cfg.DryRun,
 		ScanDBID:        scanDBID,
+		Stability:       cfg.Stability,
+		Filter:          cfg.Filter,
+		DurationCheck:   cfg.DurationCheck,
+		MinConfidence:   cfg.MinConfidence,
+		Remote:          rs,
 	})
 	return nil
 }
@@ -989,7 +1410,20 @@ type scanFileContext struct {
 	autoRemediate     bool
 	dryRun            bool
 	detectionConfig   integration.DetectionConfig
+	stability         fileStabilityConfig
+	durationCheck     string
+	minConfidence     int
 	activeCorruptions map[string]bool // Preloaded map of file paths with active corruptions
+
+	// Set for files of remote scan paths
+	remote      *remoteScan
+	remoteEntry remote.Entry
+	remoteErr   error // the file couldn't be listed or stat'ed
+
+	// Set once the file went through a health check
+	checked       bool
+	checkDuration time.Duration
+	detectionTool string // tool whose verdict was used, empty if none ran
 }
 
 // scanLoopAction indicates what the scan loop should do after checking state.
@@ -1075,53 +1509,116 @@ func (s *ScannerService) handleScanPause(ctx context.Context, progress *ScanProg
 // shouldSkipRecentlyModified checks if a file was modified too recently and should be skipped.
 // Returns true if file should be skipped (likely still being written).
 func (s *ScannerService) shouldSkipRecentlyModified(sfc *scanFileContext) bool {
-	if time.Since(sfc.fileMtime) < 2*time.Minute {
+	minAge := sfc.stability.MinFileAge
+	if minAge < 0 {
+		return false
+	}
+	if minAge == 0 {
+		minAge = defaultMinFileAge
+	}
+	if time.Since(sfc.fileMtime) < minAge {
 		logger.Infof("Skipping recently modified file (mtime %v ago): %s",
 			time.Since(sfc.fileMtime).Round(time.Second), sfc.filePath)
-		if sfc.scanDBID > 0 {
-			if _, err := db.ExecWithRetry(s.db, `
-				INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size)
-				VALUES (?, ?, 'skipped', 'RecentlyModified', 'File modified within last 2 minutes - likely still being written', ?)
-			`, sfc.scanDBID, sfc.filePath, sfc.fileSize); err != nil {
-				logger.Debugf("Failed to record skipped file (recently modified): %v", err)
-			}
-		}
+		s.recordScanFile(sfc, "skipped", "RecentlyModified",
+			fmt.Sprintf("File modified within last %s - likely still being written", formatMinFileAge(minAge)))
 		return true
 	}
 	return false
 }
 
+// formatMinFileAge renders a minimum file age for skip messages, e.g. "2 minutes".
+func formatMinFileAge(d time.Duration) string {
+	if minutes := int(d / time.Minute); minutes >= 1 && d%time.Minute == 0 {
+		if minutes == 1 {
+			return "1 minute"
+		}
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+	return d.String()
+}
+
 // shouldSkipChangingSize checks if file size is actively changing (download in progress).
-// Returns true if file should be skipped.
+// The file is stat'ed again, and once more after the path's size stability wait
+// if it was modified within that wait; a file left alone for longer won't change
+// during it, so most files don't wait at all.
+// Returns true if file should be skipped, including when shutdown interrupts the wait.
 func (s *ScannerService) shouldSkipChangingSize(sfc *scanFileContext) bool {
-	time.Sleep(500 * time.Millisecond)
+	// A second stat costs a rate-limited request; remote files rely on the age check
+	if sfc.remote != nil {
+		return false
+	}
+	info, err := os.Stat(sfc.filePath)
+	if err != nil {
+		return false
+	}
+	if info.Size() != sfc.fileSize {
+		return s.skipChangingSize(sfc)
+	}
+	wait := sfc.stability.SizeStabilityWait
+	if wait <= 0 {
+		wait = defaultSizeStabilityWait
+	}
+	if time.Since(info.ModTime()) >= wait {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-s.shutdownCh:
+		return true
+	}
 	if info2, err := os.Stat(sfc.filePath); err == nil {
 		if info2.Size() != sfc.fileSize {
-			logger.Infof("Skipping file with changing size (download in progress?): %s", sfc.filePath)
-			if sfc.scanDBID > 0 {
-				if _, err := db.ExecWithRetry(s.db, `
-					INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size)
-					VALUES (?, ?, 'skipped', 'SizeChanging', 'File size changed during scan - active download/copy', ?)
-				`, sfc.scanDBID, sfc.filePath, sfc.fileSize); err != nil {
-					logger.Debugf("Failed to record skipped file (size changing): %v", err)
-				}
-			}
-			return true
+			return s.skipChangingSize(sfc)
 		}
 	}
 	return false
 }
 
+// skipChangingSize records a file skipped by shouldSkipChangingSize.
+func (s *ScannerService) skipChangingSize(sfc *scanFileContext) bool {
+	logger.Infof("Skipping file with changing size (download in progress?): %s", sfc.filePath)
+	s.recordScanFile(sfc, "skipped", "SizeChanging", "File size changed during scan - active download/copy")
+	return true
+}
+
 // recordHealthyFile records a healthy file in the scan_files table.
 func (s *ScannerService) recordHealthyFile(sfc *scanFileContext) {
-	if sfc.scanDBID > 0 {
-		_, err := db.ExecWithRetry(s.db, `
-			INSERT INTO scan_files (scan_id, file_path, status, file_size)
-			VALUES (?, ?, 'healthy', ?)
-		`, sfc.scanDBID, sfc.filePath, sfc.fileSize)
-		if err != nil {
-			logger.Debugf("Failed to record healthy file: %v", err)
-		}
+	s.recordScanFile(sfc, "healthy", "", "")
+}
+
+// recordScanFile records the outcome of one file in the scan_files table.
+// Check duration and tool are stored once the file went through a health check.
+func (s *ScannerService) recordScanFile(sfc *scanFileContext, status, corruptionType, details string) {
+	if sfc.scanDBID <= 0 {
+		return
+	}
+	var durationMs, tool interface{}
+	if sfc.checked {
+		durationMs = sfc.checkDuration.Milliseconds()
+	}
+	if sfc.detectionTool != "" {
+		tool = sfc.detectionTool
+	}
+	if _, err := db.ExecWithRetry(s.db, `
+		INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size, duration_ms, detection_tool)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
+	`, sfc.scanDBID, sfc.filePath, status, corruptionType, details, sfc.fileSize, durationMs, tool); err != nil {
+		logger.Debugf("Failed to record %s file %s: %v", status, sfc.filePath, err)
+	}
+}
+
+// scanFileErrorStatus is the scan_files status of a file whose check hit a
+// recoverable error: "error" when the detector itself failed, "inaccessible"
+// when the file or its mount couldn't be read.
+func scanFileErrorStatus(healthErr *integration.HealthCheckError) string {
+	switch healthErr.Type {
+	case integration.ErrorTypeToolFailure, integration.ErrorTypeTimeout, integration.ErrorTypeInvalidConfig:
+		return "error"
+	default:
+		return "inaccessible"
 	}
 }
 
@@ -1131,16 +1628,8 @@ func (s *ScannerService) handleRecoverableError(progress *ScanProgress, sfc *sca
 	logger.Infof("Recoverable error for file %s (Type: %s): %s - queued for rescan",
 		sfc.filePath, healthErr.Type, healthErr.Message)
 
-	// Record as "inaccessible" not "corrupt"
-	if sfc.scanDBID > 0 {
-		_, err := db.ExecWithRetry(s.db, `
-			INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size)
-			VALUES (?, ?, 'inaccessible', ?, ?, ?)
-		`, sfc.scanDBID, sfc.filePath, healthErr.Type, healthErr.Message, sfc.fileSize)
-		if err != nil {
-			logger.Debugf("Failed to record inaccessible file: %v", err)
-		}
-	}
+	// Record as "inaccessible" or "error", not "corrupt"
+	s.recordScanFile(sfc, scanFileErrorStatus(healthErr), healthErr.Type, healthErr.Message)
 
 	// Queue file for rescan when infrastructure is back
 	s.queueForRescan(sfc.filePath, sfc.pathID, healthErr.Type, healthErr.Message)
@@ -1191,27 +1680,19 @@ func (s *ScannerService) handleTrueCorruption(ctx context.Context, progress *Sca
 	}
 	if hasActive {
 		logger.Infof("Skipping duplicate corruption for file already being processed: %s", sfc.filePath)
-		if sfc.scanDBID > 0 {
-			if _, err := db.ExecWithRetry(s.db, `
-				INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size)
-				VALUES (?, ?, 'skipped', 'AlreadyProcessing', 'File already has active corruption record', ?)
-			`, sfc.scanDBID, sfc.filePath, sfc.fileSize); err != nil {
-				logger.Debugf("Failed to record skipped file (already processing): %v", err)
-			}
-		}
+		s.recordScanFile(sfc, "skipped", "AlreadyProcessing", "File already has active corruption record")
+		return scanSkipToNext
+	}
+
+	// Skip findings the user already marked as false positive for this file
+	if s.isKnownFalsePositive(sfc.filePath, healthErr) {
+		s.recordScanFile(sfc, "skipped", "FalsePositive", "Suppressed known false positive: "+healthErr.Message)
 		return scanSkipToNext
 	}
 
 	// Record corrupt file
+	s.recordScanFile(sfc, "corrupt", healthErr.Type, healthErr.Message)
 	if sfc.scanDBID > 0 {
-		_, err := db.ExecWithRetry(s.db, `
-			INSERT INTO scan_files (scan_id, file_path, status, corruption_type, error_details, file_size)
-			VALUES (?, ?, 'corrupt', ?, ?, ?)
-		`, sfc.scanDBID, sfc.filePath, healthErr.Type, healthErr.Message, sfc.fileSize)
-		if err != nil {
-			logger.Debugf("Failed to record corrupt file: %v", err)
-		}
-
 		// Update corruptions count
 		if _, err := db.ExecWithRetry(s.db, `UPDATE scans SET corruptions_found = corruptions_found + 1 WHERE id = ?`, sfc.scanDBID); err != nil {
 			logger.Warnf("Failed to update corruptions count for scan %d: %v", sfc.scanDBID, err)
@@ -1226,30 +1707,69 @@ func (s *ScannerService) handleTrueCorruption(ctx context.Context, progress *Sca
 		return action
 	}
 
+	// HDR metadata problems are flagged for review unless the policy says to
+	// remediate them, since a re-download often has the same metadata
+	autoRemediate := sfc.autoRemediate
+	if healthErr.Type == integration.ErrorTypeHDRMetadata && s.hdrMetadataPolicy != config.HDRMetadataRemediate {
+		autoRemediate = false
+	}
+	if healthErr.Type == integration.ErrorTypeDurationMismatch && !durationMismatchRemediates(sfc.durationCheck) {
+		autoRemediate = false
+	}
+
+	eventData := map[string]interface{}{
+		"file_path":       sfc.filePath,
+		"file_size":       sfc.fileSize,
+		"path_id":         sfc.pathID,
+		"corruption_type": healthErr.Type,
+		"error_details":   healthErr.Message,
+		"media_type":      string(getMediaType(sfc.filePath)),
+		"auto_remediate":  autoRemediate,
+		"dry_run":         sfc.dryRun,
+		"batch_throttled": progress.isThrottled,
+	}
+	addConfidence(eventData, healthErr, sfc.minConfidence)
+
 	// Emit corruption event for remediation - critical entry point, use retry
+	corruptionID := uuid.New().String()
 	err := s.eventBus.PublishWithRetry(domain.Event{
 		AggregateType: "corruption",
-		AggregateID:   uuid.New().String(),
+		AggregateID:   corruptionID,
 		EventType:     domain.CorruptionDetected,
-		EventData: map[string]interface{}{
-			"file_path":       sfc.filePath,
-			"file_size":       sfc.fileSize,
-			"path_id":         sfc.pathID,
-			"corruption_type": healthErr.Type,
-			"error_details":   healthErr.Message,
-			"media_type":      string(getMediaType(sfc.filePath)),
-			"auto_remediate":  sfc.autoRemediate,
-			"dry_run":         sfc.dryRun,
-			"batch_throttled": progress.isThrottled,
-		},
+		EventData:     eventData,
 	})
 	if err != nil {
 		logger.Errorf("Failed to publish corruption event after retries: %v", err)
+	} else {
+		s.recordCorruptionDiagnostics(corruptionID, sfc.filePath, healthErr)
 	}
 
 	return scanContinue
 }
 
+// recordCorruptionDiagnostics stores the checker output behind a detected
+// corruption as a compressed blob, served by /api/corruptions/:id/diagnostics,
+// and extracts a sample of the file when sample extraction is on.
+func (s *ScannerService) recordCorruptionDiagnostics(corruptionID, filePath string, healthErr *integration.HealthCheckError) {
+	diag := integration.NewCheckDiagnostics(healthErr)
+	if s.samples != nil {
+		if _, err := s.samples.Extract(context.Background(), corruptionID, filePath, diag); err != nil {
+			logger.Warnf("Failed to extract a sample of %s: %v", filePath, err)
+		}
+	}
+	blob, err := integration.EncodeDiagnostics(diag)
+	if err != nil {
+		logger.Warnf("Failed to encode diagnostics for %s: %v", filePath, err)
+		return
+	}
+	if _, err := db.ExecWithRetry(s.db, `
+		INSERT INTO corruption_diagnostics (corruption_id, file_path, data)
+		VALUES (?, ?, ?)
+	`, corruptionID, filePath, blob); err != nil {
+		logger.Warnf("Failed to store diagnostics for %s: %v", filePath, err)
+	}
+}
+
 // applyBatchThrottling applies throttling when many corruptions are found.
 // Returns scanReturn if cancelled during throttle delay, scanContinue otherwise.
 func (s *ScannerService) applyBatchThrottling(ctx context.Context, progress *ScanProgress) scanLoopAction {
@@ -1304,7 +1824,7 @@ func (s *ScannerService) scanFiles(ctx context.Context, progress *ScanProgress,
 	// PERFORMANCE: Preload active corruptions in a single query to avoid N+1 problem
 	activeCorruptions := s.LoadActiveCorruptionsForPath(progress.Path)
 
-	for i := cfg.StartIndex; i < len(cfg.Files); i++ {
+	for i := cfg.StartIndex; i < cfg.Files.Len(); i++ {
 		action := s.processFileInScan(ctx, progress, cfg, i, activeCorruptions)
 		if action == scanReturn {
 			return
@@ -1323,7 +1843,19 @@ func (s *ScannerService) processFileInScan(
 	fileIndex int,
 	activeCorruptions map[string]bool,
 ) scanLoopAction {
-	filePath := cfg.Files[fileIndex]
+	filePath, err := cfg.Files.At(fileIndex)
+	if err != nil {
+		logger.Errorf("Scan of %s aborted: %v", progress.Path, err)
+		progress.Status = "aborted"
+		s.emitProgress(progress)
+		return scanReturn
+	}
+
+	// A resumed scan doesn't check files again that it finished before the crash
+	if cfg.Checked[filePath] {
+		s.markFileProcessed(progress, fileIndex, cfg.ScanDBID)
+		return scanContinue
+	}
 
 	// RACE PREVENTION: Check if file is being scanned by another goroutine (e.g., webhook)
 	// This prevents duplicate scans when a bulk ScanPath and individual ScanFile overlap.
@@ -1345,7 +1877,7 @@ func (s *ScannerService) processFileInScan(
 	}()
 
 	// Check for cancellation or shutdown
-	if s.checkScanCancellation(ctx, progress, progress.Path, fileIndex, len(cfg.Files)) == scanReturn {
+	if s.checkScanCancellation(ctx, progress, progress.Path, fileIndex, cfg.Files.Len()) == scanReturn {
 		return scanReturn
 	}
 
@@ -1362,6 +1894,9 @@ func (s *ScannerService) processFileInScan(
 
 	// Build scan file context
 	sfc := s.buildScanFileContext(filePath, progress.PathID, cfg, activeCorruptions)
+	if cfg.Remote != nil {
+		s.statRemoteFile(ctx, cfg.Remote, sfc)
+	}
 
 	// Process the file and return result
 	return s.checkAndHandleFile(ctx, progress, cfg, fileIndex, sfc)
@@ -1390,6 +1925,9 @@ func (s *ScannerService) buildScanFileContext(
 		autoRemediate:     cfg.AutoRemediate,
 		dryRun:            cfg.DryRun,
 		detectionConfig:   cfg.DetectionConfig,
+		stability:         cfg.Stability,
+		durationCheck:     cfg.DurationCheck,
+		minConfidence:     cfg.MinConfidence,
 		activeCorruptions: activeCorruptions,
 	}
 }
@@ -1415,16 +1953,34 @@ func (s *ScannerService) checkAndHandleFile(
 	}
 
 	// Run health check
-	healthy, healthErr := s.detector.CheckWithConfig(sfc.filePath, cfg.DetectionConfig)
+	start := time.Now()
+	var healthy bool
+	var healthErr *integration.HealthCheckError
+	if sfc.remote != nil {
+		healthy, healthErr = s.checkRemoteFile(ctx, sfc)
+	} else {
+		healthy, healthErr = s.checkFile(sfc)
+	}
 
 	if healthy {
 		// In thorough mode, run content analysis on structurally healthy files
-		if cfg.DetectionConfig.Mode == integration.ModeThorough {
+		if cfg.DetectionConfig.Mode == integration.ModeThorough && sfc.remote == nil {
 			healthy, healthErr = s.detector.AnalyzeContent(sfc.filePath)
+			sfc.checkDuration = time.Since(start)
 			if !healthy {
 				return s.handleHealthCheckResult(ctx, progress, cfg, fileIndex, sfc, healthErr)
 			}
 		}
+		if healthy, healthErr = s.validateHDRMetadata(sfc); !healthy {
+			return s.handleHealthCheckResult(ctx, progress, cfg, fileIndex, sfc, healthErr)
+		}
+		// A file that decodes can still be too small or short to be the real thing
+		if healthy, healthErr = cfg.Filter.checkSize(sfc.fileSize); !healthy {
+			return s.handleHealthCheckResult(ctx, progress, cfg, fileIndex, sfc, healthErr)
+		}
+		if healthy, healthErr = s.validateRuntime(sfc); !healthy {
+			return s.handleHealthCheckResult(ctx, progress, cfg, fileIndex, sfc, healthErr)
+		}
 		s.recordHealthyFile(sfc)
 		s.markFileProcessed(progress, fileIndex, cfg.ScanDBID)
 		return scanContinue
@@ -1434,6 +1990,58 @@ func (s *ScannerService) checkAndHandleFile(
 	return s.handleHealthCheckResult(ctx, progress, cfg, fileIndex, sfc, healthErr)
 }
 
+// validateHDRMetadata checks the HDR10/Dolby Vision metadata of a file that
+// decoded cleanly, unless the policy is off or the detector can't.
+func (s *ScannerService) validateHDRMetadata(sfc *scanFileContext) (bool, *integration.HealthCheckError) {
+	validator, ok := s.detector.(integration.HDRValidator)
+	if !ok || s.hdrMetadataPolicy == config.HDRMetadataOff || sfc.detectionConfig.Method == integration.DetectionZeroByte || sfc.remote != nil {
+		return true, nil
+	}
+	return validator.ValidateHDRMetadata(sfc.filePath)
+}
+
+// checkFile runs the path's detection profile on a file and records how long
+// it took and which tool's verdict was used.
+func (s *ScannerService) checkFile(sfc *scanFileContext) (bool, *integration.HealthCheckError) {
+	start := time.Now()
+	var healthy bool
+	var healthErr *integration.HealthCheckError
+	detectionConfig := sfc.detectionConfig
+	detectionConfig.Consensus = s.consensus
+	method := detectionConfig.Method
+	if reporter, ok := s.detector.(integration.DetectorReporter); ok {
+		healthy, method, healthErr = reporter.CheckWithConfigDetector(sfc.filePath, detectionConfig)
+	} else {
+		healthy, healthErr = s.detector.CheckWithConfig(sfc.filePath, detectionConfig)
+	}
+	sfc.checked = true
+	sfc.checkDuration = time.Since(start)
+	sfc.detectionTool = detectionToolName(method, sfc.detectionConfig.Mode)
+	return healthy, healthErr
+}
+
+// quickCheck runs the quick ffprobe check of webhook scans and pending
+// rescans, re-checked by the other tools if a consensus policy is set.
+func (s *ScannerService) quickCheck(path string) (bool, *integration.HealthCheckError) {
+	if s.consensus == "" {
+		return s.detector.Check(path, integration.ModeQuick)
+	}
+	return s.detector.CheckWithConfig(path, integration.DetectionConfig{
+		Method:    integration.DetectionFFprobe,
+		Mode:      integration.ModeQuick,
+		Consensus: s.consensus,
+	})
+}
+
+// detectionToolName is the tool a detection method runs in the given mode,
+// e.g. ffmpeg for a thorough ffprobe check.
+func detectionToolName(method integration.DetectionMethod, mode string) string {
+	if tools := integration.ToolsForMethod(method, mode); len(tools) > 0 {
+		return tools[0]
+	}
+	return string(method)
+}
+
 // handleHealthCheckResult processes the result of a failed health check.
 func (s *ScannerService) handleHealthCheckResult(
 	ctx context.Context,
@@ -1443,6 +2051,13 @@ func (s *ScannerService) handleHealthCheckResult(
 	sfc *scanFileContext,
 	healthErr *integration.HealthCheckError,
 ) scanLoopAction {
+	// Findings the other detectors overruled are recorded, not remediated
+	if healthErr.Type == integration.ErrorTypeNoConsensus {
+		s.recordScanFile(sfc, "skipped", healthErr.Type, healthErr.Message)
+		s.markFileProcessed(progress, fileIndex, cfg.ScanDBID)
+		return scanContinue
+	}
+
 	// Handle recoverable errors (infrastructure issues)
 	if healthErr.IsRecoverable() {
 		if s.handleRecoverableError(progress, sfc, healthErr) == scanReturn {
@@ -1460,23 +2075,18 @@ func (s *ScannerService) handleHealthCheckResult(
 	return scanContinue
 }
 
-// markFileProcessed increments the file counter and saves progress periodically
+// markFileProcessed increments the file counter and saves progress as often
+// as the checkpoint settings ask, to avoid excessive I/O
 func (s *ScannerService) markFileProcessed(progress *ScanProgress, fileIndex int, scanDBID int64) {
 	// Lock to safely update mutable fields (fixes data race with GetActiveScans/Shutdown)
 	progress.mu.Lock()
 	progress.FilesDone++
 	filesDone := progress.FilesDone
+	due := s.checkpointDue(progress, fileIndex, time.Now())
 	progress.mu.Unlock()
 
-	// Save state to database periodically (every 10 files) to avoid excessive I/O
-	if fileIndex%10 == 0 {
-		if scanDBID > 0 {
-			progressCtx, progressCancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
-			if _, err := s.db.ExecContext(progressCtx, `UPDATE scans SET current_file_index = ?, files_scanned = ? WHERE id = ?`, fileIndex, filesDone, scanDBID); err != nil {
-				logger.Warnf("Failed to save scan progress for scan %d: %v", scanDBID, err)
-			}
-			progressCancel()
-		}
+	if due && scanDBID > 0 {
+		s.saveCheckpoint(scanDBID, fileIndex+1, filesDone)
 	}
 }
 
@@ -1599,12 +2209,13 @@ func (s *ScannerService) ResumeScan(scanID string) error {
 		return nil // Not paused
 	}
 
-	// Signal the scan goroutine to resume
+	// Signal the scan goroutine to resume. resumeChan is buffered so the
+	// signal isn't lost if the scan hasn't reached its pause point yet.
 	select {
 	case scan.resumeChan <- struct{}{}:
 		// Successfully sent resume signal
 	default:
-		// Channel not ready, scan might already be resuming
+		// A resume signal is already pending
 	}
 
 	return nil
@@ -1624,7 +2235,8 @@ func (s *ScannerService) refreshScanPathCache() error {
 	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
 	defer cancel()
 
-	rows, err := s.db.QueryContext(ctx, "SELECT local_path, auto_remediate, COALESCE(dry_run, 0) FROM scan_paths WHERE enabled = 1")
+	rows, err := s.db.QueryContext(ctx, `SELECT local_path, auto_remediate, COALESCE(dry_run, 0), media_extensions, min_file_size_mb, duration_check, min_confidence
+		FROM scan_paths WHERE enabled = 1 AND deleted_at IS NULL`)
 	if err != nil {
 		return err
 	}
@@ -1633,9 +2245,12 @@ func (s *ScannerService) refreshScanPathCache() error {
 	cache := make([]scanPathConfig, 0, 10)
 	for rows.Next() {
 		var cfg scanPathConfig
-		if rows.Scan(&cfg.LocalPath, &cfg.AutoRemediate, &cfg.DryRun) != nil {
+		var mediaExtensions string
+		var minFileSizeMB int64
+		if rows.Scan(&cfg.LocalPath, &cfg.AutoRemediate, &cfg.DryRun, &mediaExtensions, &minFileSizeMB, &cfg.DurationCheck, &cfg.MinConfidence) != nil {
 			continue
 		}
+		cfg.Filter = newMediaFilter(mediaExtensions, minFileSizeMB)
 		cache = append(cache, cfg)
 	}
 
@@ -1660,15 +2275,25 @@ func (s *ScannerService) InvalidateScanPathCache() {
 // Uses cached scan paths to avoid N+1 query problem (was: 1 query per file).
 // Returns auto_remediate, dry_run, and any error.
 func (s *ScannerService) getScanPathConfig(filePath string) (autoRemediate bool, dryRun bool, err error) {
+	cfg, err := s.matchScanPathConfig(filePath)
+	if err != nil {
+		return false, false, err
+	}
+	return cfg.AutoRemediate, cfg.DryRun, nil
+}
+
+// matchScanPathConfig returns the cached configuration of the most specific
+// scan path containing filePath.
+func (s *ScannerService) matchScanPathConfig(filePath string) (scanPathConfig, error) {
 	// Ensure cache is fresh
 	if err := s.refreshScanPathCache(); err != nil {
-		return false, false, err
+		return scanPathConfig{}, err
 	}
 
 	s.scanPathCacheMu.RLock()
 	defer s.scanPathCacheMu.RUnlock()
 
-	var bestMatchLen int
+	var best scanPathConfig
 	found := false
 
 	for _, cfg := range s.scanPathCache {
@@ -1678,10 +2303,8 @@ func (s *ScannerService) getScanPathConfig(filePath string) (autoRemediate bool,
 			remainder := filePath[len(cfg.LocalPath):]
 			// Valid match only if remainder is empty or starts with /
 			if remainder == "" || strings.HasPrefix(remainder, "/") {
-				if len(cfg.LocalPath) > bestMatchLen {
-					bestMatchLen = len(cfg.LocalPath)
-					autoRemediate = cfg.AutoRemediate
-					dryRun = cfg.DryRun
+				if len(cfg.LocalPath) > len(best.LocalPath) {
+					best = cfg
 					found = true
 				}
 			}
@@ -1689,9 +2312,9 @@ func (s *ScannerService) getScanPathConfig(filePath string) (autoRemediate bool,
 	}
 
 	if !found {
-		return false, false, fmt.Errorf("no matching scan path found")
+		return scanPathConfig{}, fmt.Errorf("no matching scan path found")
 	}
-	return autoRemediate, dryRun, nil
+	return best, nil
 }
 
 // verifyPathAccessible performs pre-flight checks to ensure a scan path is accessible
@@ -1761,6 +2384,20 @@ func (s *ScannerService) testFileAccess(path string, entries []os.DirEntry) erro
 	return nil
 }
 
+// activeCorruptionSQL counts the unresolved corruptions of a file detected in
+// the last week. It runs for every file a webhook or scan reports as corrupt.
+const activeCorruptionSQL = `
+	SELECT COUNT(*) FROM events e1
+	WHERE e1.event_type = 'CorruptionDetected'
+	AND json_extract(e1.event_data, '$.file_path') = ?
+	AND e1.created_at > datetime('now', '-7 days')
+	AND NOT EXISTS (
+		SELECT 1 FROM events e2
+		WHERE e2.aggregate_id = e1.aggregate_id
+		AND e2.event_type IN ('VerificationSuccess', 'MaxRetriesReached')
+	)
+`
+
 // hasActiveCorruption checks if a file already has an unresolved corruption record
 // This prevents duplicate processing from webhook replays, overlapping scans, etc.
 func (s *ScannerService) hasActiveCorruption(filePath string) bool {
@@ -1770,17 +2407,15 @@ func (s *ScannerService) hasActiveCorruption(filePath string) bool {
 	defer cancel()
 
 	var count int
-	err := s.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM events e1
-		WHERE e1.event_type = 'CorruptionDetected'
-		AND json_extract(e1.event_data, '$.file_path') = ?
-		AND e1.created_at > datetime('now', '-7 days')
-		AND NOT EXISTS (
-			SELECT 1 FROM events e2
-			WHERE e2.aggregate_id = e1.aggregate_id
-			AND e2.event_type IN ('VerificationSuccess', 'MaxRetriesReached')
-		)
-	`, filePath).Scan(&count)
+	var row *sql.Row
+	start := time.Now()
+	if stmt, prepErr := s.stmts.Get(activeCorruptionSQL); prepErr == nil {
+		row = stmt.QueryRowContext(ctx, filePath)
+	} else {
+		row = s.db.QueryRowContext(ctx, activeCorruptionSQL, filePath)
+	}
+	err := row.Scan(&count)
+	db.ObserveLatency("active_corruption_lookup", start)
 
 	if err != nil {
 		logger.Debugf("Error checking for active corruption: %v", err)
@@ -1978,32 +2613,42 @@ func (s *ScannerService) updateRescanRetry(f pendingRescanFile, healthErr *integ
 
 // emitRescanCorruption emits a corruption event for a rescan that found actual corruption
 func (s *ScannerService) emitRescanCorruption(f pendingRescanFile, healthErr *integration.HealthCheckError) {
-	autoRemediate, dryRun, _ := s.getScanPathConfig(f.FilePath)
+	if s.isKnownFalsePositive(f.FilePath, healthErr) {
+		return
+	}
+
+	pathCfg, _ := s.matchScanPathConfig(f.FilePath)
 
 	var fileSize int64
 	if info, err := os.Stat(f.FilePath); err == nil {
 		fileSize = info.Size()
 	}
 
+	eventData := map[string]interface{}{
+		"file_path":       f.FilePath,
+		"file_size":       fileSize,
+		"path_id":         f.PathID,
+		"corruption_type": healthErr.Type,
+		"error_details":   healthErr.Message,
+		"media_type":      string(getMediaType(f.FilePath)),
+		"source":          "rescan_worker",
+		"auto_remediate":  pathCfg.AutoRemediate,
+		"dry_run":         pathCfg.DryRun,
+	}
+	addConfidence(eventData, healthErr, pathCfg.MinConfidence)
+
 	// Critical entry point for remediation journey, use retry
+	corruptionID := uuid.New().String()
 	if err := s.eventBus.PublishWithRetry(domain.Event{
 		AggregateType: "corruption",
-		AggregateID:   uuid.New().String(),
+		AggregateID:   corruptionID,
 		EventType:     domain.CorruptionDetected,
-		EventData: map[string]interface{}{
-			"file_path":       f.FilePath,
-			"file_size":       fileSize,
-			"path_id":         f.PathID,
-			"corruption_type": healthErr.Type,
-			"error_details":   healthErr.Message,
-			"media_type":      string(getMediaType(f.FilePath)),
-			"source":          "rescan_worker",
-			"auto_remediate":  autoRemediate,
-			"dry_run":         dryRun,
-		},
+		EventData:     eventData,
 	}); err != nil {
 		logger.Errorf("Failed to publish corruption event for rescan after retries: %v", err)
+		return
 	}
+	s.recordCorruptionDiagnostics(corruptionID, f.FilePath, healthErr)
 }
 
 // processPendingRescans checks files that previously had infrastructure errors
@@ -2028,7 +2673,7 @@ func (s *ScannerService) processPendingRescans() {
 		default:
 		}
 
-		healthy, healthErr := s.detector.Check(f.FilePath, "quick")
+		healthy, healthErr := s.quickCheck(f.FilePath)
 
 		if healthy {
 			s.markRescanResolved(f.ID, "healthy")
@@ -2040,6 +2685,11 @@ func (s *ScannerService) processPendingRescans() {
 			s.updateRescanRetry(f, healthErr)
 			continue
 		}
+		if healthErr.Type == integration.ErrorTypeNoConsensus {
+			s.markRescanResolved(f.ID, "no_consensus")
+			logger.Infof("Pending rescan resolved without consensus on corruption: %s (%s)", f.FilePath, healthErr.Message)
+			continue
+		}
 
 		// File is accessible but actually corrupt
 		logger.Infof("Pending rescan revealed corruption: %s (Type: %s)", f.FilePath, healthErr.Type)
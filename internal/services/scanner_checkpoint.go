@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Defaults for how often a path scan saves where it is, so that it can resume
+// after a restart or crash.
+const (
+	defaultCheckpointFiles    = 10
+	defaultCheckpointInterval = 30 * time.Second
+)
+
+// SetScanCheckpoint sets how often a path scan saves its position: after
+// every files files, and after the first file that finishes interval after
+// the previous save. 0 disables either trigger; with both 0 a scan saves
+// every 10 files. A scan resumed after a crash starts at its last save and
+// skips the files whose results were recorded since.
+func (s *ScannerService) SetScanCheckpoint(files int, interval time.Duration) {
+	s.checkpointFiles = files
+	s.checkpointInterval = interval
+}
+
+// checkpointDue reports whether the position of a scan is saved after the
+// file at fileIndex, and if so records now as the time of the save. The
+// caller holds progress.mu.
+func (s *ScannerService) checkpointDue(progress *ScanProgress, fileIndex int, now time.Time) bool {
+	files, interval := s.checkpointFiles, s.checkpointInterval
+	if files <= 0 && interval <= 0 {
+		files = defaultCheckpointFiles
+	}
+	due := files > 0 && fileIndex%files == 0
+	if interval > 0 && now.Sub(progress.checkpointAt) >= interval {
+		due = true
+	}
+	if due {
+		progress.checkpointAt = now
+	}
+	return due
+}
+
+// saveCheckpoint saves the position of a scan: the index of the next file,
+// the files done and the last per-file result recorded so far. Results
+// recorded after it belong to files past nextIndex.
+func (s *ScannerService) saveCheckpoint(scanDBID int64, nextIndex, filesDone int) {
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE scans SET current_file_index = ?, files_scanned = ?,
+			checkpoint_file_id = (SELECT COALESCE(MAX(id), 0) FROM scan_files WHERE scan_id = ?)
+		WHERE id = ?
+	`, nextIndex, filesDone, scanDBID, scanDBID); err != nil {
+		logger.Warnf("Failed to save scan progress for scan %d: %v", scanDBID, err)
+	}
+}
+
+// checkedSinceCheckpoint returns the files of a scan whose results were
+// recorded after its last checkpoint, i.e. the files it finished between the
+// checkpoint and a crash. A resumed scan skips them instead of checking them
+// again. Corrupt files are left out: their result is recorded before their
+// CorruptionDetected event, so a crash in between would lose the corruption.
+// Checking them again raises it, or skips it if the event was stored.
+func (s *ScannerService) checkedSinceCheckpoint(scanDBID int64) map[string]bool {
+	ctx, cancel := context.WithTimeout(context.Background(), scannerQueryTimeout)
+	defer cancel()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT file_path FROM scan_files
+		WHERE scan_id = ? AND status != 'corrupt'
+			AND id > COALESCE((SELECT checkpoint_file_id FROM scans WHERE id = ?), 0)
+	`, scanDBID, scanDBID)
+	if err != nil {
+		logger.Warnf("Failed to load the file results of scan %d, resuming without them: %v", scanDBID, err)
+		return nil
+	}
+	defer rows.Close()
+
+	checked := make(map[string]bool)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			logger.Warnf("Failed to load the file results of scan %d, resuming without them: %v", scanDBID, err)
+			return nil
+		}
+		checked[path] = true
+	}
+	if err := rows.Err(); err != nil {
+		logger.Warnf("Failed to load the file results of scan %d, resuming without them: %v", scanDBID, err)
+		return nil
+	}
+	return checked
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestScannerService_CheckpointDue(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	t.Run("every N files", func(t *testing.T) {
+		scanner := &ScannerService{checkpointFiles: 5}
+		progress := &ScanProgress{}
+		var due []int
+		for i := 0; i < 12; i++ {
+			if scanner.checkpointDue(progress, i, start.Add(time.Duration(i)*time.Hour)) {
+				due = append(due, i)
+			}
+		}
+		if len(due) != 3 || due[0] != 0 || due[1] != 5 || due[2] != 10 {
+			t.Errorf("checkpoints after files %v, want [0 5 10]", due)
+		}
+	})
+
+	t.Run("after an interval", func(t *testing.T) {
+		scanner := &ScannerService{checkpointInterval: time.Minute}
+		progress := &ScanProgress{}
+		if !scanner.checkpointDue(progress, 0, start) {
+			t.Error("Expected the first file to save a checkpoint")
+		}
+		if scanner.checkpointDue(progress, 1, start.Add(30*time.Second)) {
+			t.Error("Expected no checkpoint before the interval passed")
+		}
+		if !scanner.checkpointDue(progress, 2, start.Add(time.Minute)) {
+			t.Error("Expected a checkpoint once the interval passed")
+		}
+		if scanner.checkpointDue(progress, 3, start.Add(90*time.Second)) {
+			t.Error("Expected the interval to restart at the last checkpoint")
+		}
+	})
+
+	t.Run("whichever comes first", func(t *testing.T) {
+		scanner := &ScannerService{checkpointFiles: 100, checkpointInterval: time.Minute}
+		progress := &ScanProgress{}
+		scanner.checkpointDue(progress, 0, start)
+		if !scanner.checkpointDue(progress, 1, start.Add(2*time.Minute)) {
+			t.Error("Expected a slow file to save a checkpoint before 100 files")
+		}
+	})
+
+	t.Run("defaults to every 10 files", func(t *testing.T) {
+		scanner := &ScannerService{}
+		progress := &ScanProgress{}
+		if !scanner.checkpointDue(progress, 10, start) || scanner.checkpointDue(progress, 11, start.Add(time.Hour)) {
+			t.Error("Expected checkpoints every 10 files without settings")
+		}
+	})
+}
+
+func TestScannerService_ResumeSkipsFilesCheckedSinceCheckpoint(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	tmpDir := t.TempDir()
+	var files []string
+	oldTime := time.Now().Add(-10 * time.Minute)
+	for _, name := range []string{"a.mkv", "b.mkv", "c.mkv", "d.mkv"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if err := os.Chtimes(path, oldTime, oldTime); err != nil {
+			t.Fatalf("Failed to set time: %v", err)
+		}
+		files = append(files, path)
+	}
+
+	checked := make(map[string]int)
+	scanner := &ScannerService{
+		db:       db,
+		eventBus: eb,
+		detector: &testutil.MockHealthChecker{
+			CheckWithConfigFunc: func(path string, config integration.DetectionConfig) (bool, *integration.HealthCheckError) {
+				checked[path]++
+				return true, nil
+			},
+		},
+		activeScans:     make(map[string]*ScanProgress),
+		filesInProgress: make(map[string]bool),
+		shutdownCh:      make(chan struct{}),
+		checkpointFiles: 2,
+	}
+
+	result, err := db.Exec(`INSERT INTO scans (path, path_id, status, total_files) VALUES (?, 1, 'running', 4)`, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create scan: %v", err)
+	}
+	scanDBID, _ := result.LastInsertId()
+
+	// The first file is checkpointed, the second is recorded before the crash.
+	// The third is recorded corrupt, but the crash came before its event.
+	sfc := &scanFileContext{scanDBID: scanDBID}
+	sfc.filePath = files[0]
+	scanner.recordHealthyFile(sfc)
+	scanner.saveCheckpoint(scanDBID, 1, 1)
+	sfc.filePath = files[1]
+	scanner.recordHealthyFile(sfc)
+	sfc.filePath = files[2]
+	scanner.recordScanFile(sfc, "corrupt", integration.ErrorTypeCorruptHeader, "invalid header")
+
+	done := scanner.checkedSinceCheckpoint(scanDBID)
+	if len(done) != 1 || !done[files[1]] {
+		t.Fatalf("checkedSinceCheckpoint() = %v, want only %s", done, files[1])
+	}
+
+	progress := &ScanProgress{ID: "resumed", Type: "path", Path: tmpDir, TotalFiles: 4, FilesDone: 1, ScanDBID: scanDBID}
+	scanner.scanFiles(context.Background(), progress, scanFilesConfig{
+		Files:           memoryFileQueue(files),
+		StartIndex:      1,
+		Checked:         done,
+		DetectionConfig: integration.DetectionConfig{Method: integration.DetectionFFprobe, Mode: integration.ModeQuick},
+		ScanDBID:        scanDBID,
+	})
+
+	if checked[files[0]] != 0 || checked[files[1]] != 0 {
+		t.Errorf("Expected the files finished before the crash to be skipped, checked %v", checked)
+	}
+	if checked[files[2]] != 1 || checked[files[3]] != 1 {
+		t.Errorf("Expected the corrupt and remaining files to be checked once, checked %v", checked)
+	}
+	if progress.FilesDone != 4 {
+		t.Errorf("FilesDone = %d, want 4", progress.FilesDone)
+	}
+
+	var index int
+	if err := db.QueryRow(`SELECT current_file_index FROM scans WHERE id = ?`, scanDBID).Scan(&index); err != nil {
+		t.Fatalf("Failed to query scan: %v", err)
+	}
+	if index != 3 {
+		t.Errorf("current_file_index = %d, want 3 (saved after the file at index 2)", index)
+	}
+}
+
+func TestScannerService_ResumeInterruptedScans_AfterCrash(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	scanner := &ScannerService{
+		db:              db,
+		eventBus:        eb,
+		detector:        &testutil.MockHealthChecker{},
+		activeScans:     make(map[string]*ScanProgress),
+		filesInProgress: make(map[string]bool),
+		shutdownCh:      make(chan struct{}),
+		startedAt:       time.Now(),
+	}
+
+	// Left running by a crash before the service started, and one started since
+	var crashedID, currentID int64
+	for _, startedAt := range []string{"datetime('now', '-1 hour')", "datetime('now', '+1 hour')"} {
+		result, err := db.Exec(`
+			INSERT INTO scans (path, path_id, status, total_files, current_file_index, file_list, started_at)
+			VALUES ('/media/movies', 1, 'running', 1, 1, '["/media/movies/a.mkv"]', ` + startedAt + `)
+		`)
+		if err != nil {
+			t.Fatalf("Failed to insert scan: %v", err)
+		}
+		if crashedID == 0 {
+			crashedID, _ = result.LastInsertId()
+		} else {
+			currentID, _ = result.LastInsertId()
+		}
+	}
+	// Left running by a crash while it was still listing files
+	result, err := db.Exec(`
+		INSERT INTO scans (path, path_id, status, total_files, current_file_index, started_at)
+		VALUES ('/media/movies', 1, 'running', 0, 0, datetime('now', '-1 hour'))
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert scan: %v", err)
+	}
+	unlistedID, _ := result.LastInsertId()
+	if _, err := db.Exec(`INSERT INTO scan_file_queue (scan_id, position, file_path) VALUES (?, 0, '/media/movies/a.mkv')`, unlistedID); err != nil {
+		t.Fatalf("Failed to insert file queue: %v", err)
+	}
+
+	scanner.ResumeInterruptedScans()
+
+	statusOf := func(id int64) string {
+		var status string
+		if err := db.QueryRow(`SELECT status FROM scans WHERE id = ?`, id).Scan(&status); err != nil {
+			t.Fatalf("Failed to query scan: %v", err)
+		}
+		return status
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for statusOf(crashedID) != "completed" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := statusOf(crashedID); status != "completed" {
+		t.Errorf("Expected the crashed scan to be resumed and completed, got %q", status)
+	}
+	if status := statusOf(currentID); status != "running" {
+		t.Errorf("Expected the scan started after the service to be left alone, got %q", status)
+	}
+	if status := statusOf(unlistedID); status != "error" {
+		t.Errorf("Expected the scan that hadn't listed its files to fail, got %q", status)
+	}
+	var queued int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM scan_file_queue WHERE scan_id = ?`, unlistedID).Scan(&queued); err != nil {
+		t.Fatalf("Failed to query file queue: %v", err)
+	}
+	if queued != 0 {
+		t.Errorf("Expected the partial file list to be discarded, got %d files", queued)
+	}
+	scanner.wg.Wait()
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/remote"
+)
+
+// remoteScan is the connection a scan of a remote path uses for all its files.
+type remoteScan struct {
+	backend remote.Backend
+	path    remote.Path
+	// entries holds the listed files by local path; empty for resumed scans,
+	// whose files are stat'ed one by one
+	entries map[string]remote.Entry
+}
+
+// openRemoteScan connects to a remote scan path and lists the media files the
+// path's filter includes by their local paths. The caller closes the backend.
+func (s *ScannerService) openRemoteScan(ctx context.Context, p remote.Path, filter mediaFilter) (*remoteScan, []string, error) {
+	backend, err := remote.Open(ctx, p.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	rs := &remoteScan{backend: backend, path: p, entries: make(map[string]remote.Entry)}
+
+	var files []string
+	err = remote.Walk(ctx, backend, func(e remote.Entry) error {
+		localPath := p.Local(e.Path)
+		if isHiddenOrTempFile(localPath) || !filter.includes(localPath) {
+			return nil
+		}
+		files = append(files, localPath)
+		rs.entries[localPath] = e
+		return nil
+	})
+	if err != nil {
+		backend.Close()
+		return nil, nil, err
+	}
+	return rs, files, nil
+}
+
+// statRemoteFile fills in the size and modification time of a remote file,
+// from the listing or, for resumed scans, from the server.
+func (s *ScannerService) statRemoteFile(ctx context.Context, rs *remoteScan, sfc *scanFileContext) {
+	sfc.remote = rs
+	e, ok := rs.entries[sfc.filePath]
+	if !ok {
+		name, _ := rs.path.Rel(sfc.filePath)
+		e, sfc.remoteErr = rs.backend.Stat(ctx, name)
+	}
+	sfc.remoteEntry = e
+	sfc.fileSize = e.Size
+	sfc.fileMtime = e.ModTime
+}
+
+// checkRemoteFile runs the header check on a remote file. The path's detection
+// method doesn't apply: no tool can read the file without a local mount.
+func (s *ScannerService) checkRemoteFile(ctx context.Context, sfc *scanFileContext) (bool, *integration.HealthCheckError) {
+	start := time.Now()
+	var healthy bool
+	var healthErr *integration.HealthCheckError
+	if sfc.remoteErr != nil {
+		healthErr = remote.AccessError(sfc.remoteErr)
+	} else {
+		healthy, healthErr = remote.CheckFile(ctx, sfc.remote.backend, sfc.remoteEntry)
+	}
+	sfc.checked = true
+	sfc.checkDuration = time.Since(start)
+	sfc.detectionTool = string(remote.DetectionHeader)
+	return healthy, healthErr
+}
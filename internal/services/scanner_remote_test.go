@@ -0,0 +1,127 @@
+package services
+
+import (
+	"encoding/binary"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+// testMatroska returns a Matroska header whose Segment claims segmentSize
+// bytes, followed by present bytes of it.
+func testMatroska(segmentSize uint64, present int) []byte {
+	data := []byte{0x1A, 0x45, 0xDF, 0xA3, 0x80, 0x18, 0x53, 0x80, 0x67}
+	data = binary.BigEndian.AppendUint64(data, segmentSize|0x01<<56)
+	return append(data, make([]byte, present)...)
+}
+
+func TestScannerService_ScanPath_Remote(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	// The library is only reachable over WebDAV
+	library := t.TempDir()
+	for name, data := range map[string][]byte{
+		"Show/S01E01.mkv": testMatroska(100, 100),
+		"Show/S01E02.mkv": testMatroska(5000, 100),
+		"Show/notes.txt":  []byte("not media"),
+	} {
+		file := filepath.Join(library, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(&webdav.Handler{Prefix: "/dav", FileSystem: webdav.Dir(library), LockSystem: webdav.NewMemLS()})
+	defer server.Close()
+
+	if err := testutil.SeedScanPath(db, 1, "/seedbox/tv", "/tv", false, false); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE scan_paths SET remote_url = ?, remote_requests_per_minute = 600, min_file_age_minutes = 0 WHERE id = 1`,
+		server.URL+"/dav"); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+
+	detected := make(chan domain.Event, 2)
+	eb.Subscribe(domain.CorruptionDetected, func(e domain.Event) { detected <- e })
+
+	detector := &testutil.MockHealthChecker{}
+	scanner := NewScannerService(db, eb, detector, &testutil.MockPathMapper{})
+	if err := scanner.ScanPath(1, "/seedbox/tv"); err != nil {
+		t.Fatalf("ScanPath failed: %v", err)
+	}
+
+	rows, err := db.Query(`SELECT file_path, status, detection_tool, file_size FROM scan_files ORDER BY file_path`)
+	if err != nil {
+		t.Fatalf("Failed to query scan files: %v", err)
+	}
+	defer rows.Close()
+	var results []string
+	for rows.Next() {
+		var path, status, tool string
+		var size int64
+		if err := rows.Scan(&path, &status, &tool, &size); err != nil {
+			t.Fatal(err)
+		}
+		if tool != "remote_header" || size == 0 {
+			t.Errorf("Expected the remote header check with the listed size, got %s %d for %s", tool, size, path)
+		}
+		results = append(results, path+"="+status)
+	}
+	if len(results) != 2 || results[0] != "/seedbox/tv/Show/S01E01.mkv=healthy" || results[1] != "/seedbox/tv/Show/S01E02.mkv=corrupt" {
+		t.Errorf("Unexpected scan results %v", results)
+	}
+	if len(detector.Calls) != 0 {
+		t.Errorf("Expected no local detector calls, got %v", detector.Calls)
+	}
+
+	event := <-detected
+	if event.EventData["file_path"] != "/seedbox/tv/Show/S01E02.mkv" {
+		t.Errorf("Expected the corruption at the local path, got %v", event.EventData["file_path"])
+	}
+}
+
+func TestScannerService_ScanPath_RemoteUnreachable(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	server := httptest.NewServer(&webdav.Handler{Prefix: "/dav", FileSystem: webdav.Dir(t.TempDir()), LockSystem: webdav.NewMemLS()})
+	server.Close()
+
+	if err := testutil.SeedScanPath(db, 1, "/seedbox/tv", "/tv", false, false); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE scan_paths SET remote_url = ? WHERE id = 1`, server.URL+"/dav"); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+
+	scanner := NewScannerService(db, eb, &testutil.MockHealthChecker{}, &testutil.MockPathMapper{})
+	if err := scanner.ScanPath(1, "/seedbox/tv"); err == nil {
+		t.Fatal("Expected the scan to abort when the server is unreachable")
+	}
+	if len(scanner.GetActiveScans()) != 0 {
+		t.Error("Expected no active scan after the aborted pre-flight check")
+	}
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -923,6 +925,62 @@ func TestScannerService_VerifyPathAccessible(t *testing.T) {
 	})
 }
 
+func TestScannerService_ScanPath_SkipsUnscannablePath(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	mediaDir := t.TempDir()
+	if err := testutil.SeedScanPath(db, 1, mediaDir, "/movies", false, false); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+
+	// No detection tool is installed
+	tools := integration.NewToolCheckerWithPaths("/nonexistent/ffprobe", "/nonexistent/ffmpeg", "/nonexistent/mediainfo", "/nonexistent/HandBrakeCLI")
+	tools.CheckAllTools()
+
+	detector := &testutil.MockHealthChecker{}
+	scanner := NewScannerService(db, eb, detector, &testutil.MockPathMapper{})
+	scanner.SetToolChecker(tools)
+
+	err = scanner.ScanPath(1, mediaDir)
+	if !errors.Is(err, ErrPathUnscannable) {
+		t.Fatalf("Expected ErrPathUnscannable, got %v", err)
+	}
+
+	events, err := testutil.GetEventsByAggregate(db, "1")
+	if err != nil {
+		t.Fatalf("Failed to load events: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != domain.ScanSkipped {
+		t.Fatalf("Expected one ScanSkipped event, got %+v", events)
+	}
+	if missing, _ := events[0].EventData["missing_tools"].([]interface{}); len(missing) != 2 {
+		t.Errorf("Expected ffprobe and its mediainfo fallback to be reported missing, got %v", events[0].EventData["missing_tools"])
+	}
+
+	var scans int
+	if err := db.QueryRow("SELECT COUNT(*) FROM scans").Scan(&scans); err != nil {
+		t.Fatalf("Failed to count scans: %v", err)
+	}
+	if scans != 0 || len(scanner.GetActiveScans()) != 0 {
+		t.Errorf("Skipped scan should not be recorded: scans=%d active=%d", scans, len(scanner.GetActiveScans()))
+	}
+
+	// Zero-byte detection needs no tools, so the path scans again
+	if _, err := db.Exec("UPDATE scan_paths SET detection_method = 'zero_byte' WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+	if err := scanner.ScanPath(1, mediaDir); err != nil {
+		t.Errorf("Expected zero-byte scan to run, got %v", err)
+	}
+}
+
 // =============================================================================
 // Corruption deduplication tests
 // =============================================================================
@@ -1574,6 +1632,143 @@ func TestScannerService_HandleTrueCorruption(t *testing.T) {
 			t.Errorf("Expected 1 corrupt file record, got %d", count)
 		}
 	})
+
+	t.Run("stores checker diagnostics for the corruption", func(t *testing.T) {
+		progress := &ScanProgress{ID: "test-corruption-4", Path: "/media/movies"}
+		sfc := &scanFileContext{
+			filePath:          "/media/movies/diagnosed.mkv",
+			activeCorruptions: make(map[string]bool),
+		}
+		healthErr := &integration.HealthCheckError{
+			Type:    integration.ErrorTypeCorruptHeader,
+			Message: "ffprobe failed: moov atom not found",
+			Diagnostics: []integration.ToolRun{
+				{Tool: "ffprobe", ExitCode: 1, Stderr: "moov atom not found"},
+			},
+		}
+
+		scanner.handleTrueCorruption(context.Background(), progress, sfc, healthErr)
+
+		var corruptionID string
+		var blob []byte
+		if err := db.QueryRow(`SELECT corruption_id, data FROM corruption_diagnostics WHERE file_path = ?`,
+			sfc.filePath).Scan(&corruptionID, &blob); err != nil {
+			t.Fatalf("Expected stored diagnostics: %v", err)
+		}
+		var eventCount int
+		db.QueryRow(`SELECT COUNT(*) FROM events WHERE aggregate_id = ? AND event_type = 'CorruptionDetected'`,
+			corruptionID).Scan(&eventCount)
+		if eventCount != 1 {
+			t.Errorf("Diagnostics should be linked to the CorruptionDetected event, found %d events", eventCount)
+		}
+		diag, err := integration.DecodeDiagnostics(blob)
+		if err != nil {
+			t.Fatalf("DecodeDiagnostics failed: %v", err)
+		}
+		if diag.ErrorType != integration.ErrorTypeCorruptHeader || len(diag.Runs) != 1 || diag.Runs[0].Stderr != "moov atom not found" {
+			t.Errorf("Unexpected diagnostics: %+v", diag)
+		}
+	})
+
+	t.Run("suppresses known false positive", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "false-positive.mkv")
+		if err := os.WriteFile(filePath, []byte("plays fine"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fingerprint, size, err := FileFingerprint(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fps := NewFalsePositiveService(db)
+		if _, err := fps.Record(&FalsePositive{
+			CorruptionID:   "old-corruption",
+			FilePath:       filePath,
+			FileSize:       size,
+			Fingerprint:    fingerprint,
+			CorruptionType: integration.ErrorTypeCorruptStream,
+			ErrorDetails:   "decode error",
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		scanner.SetFalsePositiveSuppression(true)
+		defer scanner.SetFalsePositiveSuppression(false)
+
+		progress := &ScanProgress{ID: "test-corruption-5", Path: "/media/movies"}
+		sfc := &scanFileContext{filePath: filePath, activeCorruptions: make(map[string]bool)}
+		healthErr := &integration.HealthCheckError{Type: integration.ErrorTypeCorruptStream, Message: "decode error"}
+
+		if action := scanner.handleTrueCorruption(context.Background(), progress, sfc, healthErr); action != scanSkipToNext {
+			t.Errorf("Expected scanSkipToNext, got %v", action)
+		}
+		if progress.corruptionCount != 0 {
+			t.Errorf("Suppressed finding should not be counted, got %d", progress.corruptionCount)
+		}
+		var count int
+		db.QueryRow(`SELECT COUNT(*) FROM events WHERE event_type = 'CorruptionDetected' AND event_data LIKE ?`,
+			"%false-positive.mkv%").Scan(&count)
+		if count != 0 {
+			t.Errorf("Expected no CorruptionDetected event, got %d", count)
+		}
+	})
+
+	t.Run("records the verdicts of cross-checking detectors", func(t *testing.T) {
+		progress := &ScanProgress{ID: "test-corruption-verdicts", Path: "/media/movies"}
+		sfc := &scanFileContext{filePath: "/media/movies/verdicts.mkv", activeCorruptions: make(map[string]bool)}
+		healthErr := &integration.HealthCheckError{
+			Type:    integration.ErrorTypeCorruptStream,
+			Message: "decode error",
+			Verdicts: []integration.DetectorVerdict{
+				{Method: integration.DetectionFFprobe, Verdict: integration.VerdictCorrupt, Type: integration.ErrorTypeCorruptStream},
+				{Method: integration.DetectionMediaInfo, Verdict: integration.VerdictHealthy},
+			},
+		}
+		scanner.handleTrueCorruption(context.Background(), progress, sfc, healthErr)
+
+		var verdict string
+		if err := db.QueryRow(`
+			SELECT json_extract(event_data, '$.detector_verdicts[1].verdict') FROM events
+			WHERE event_type = 'CorruptionDetected'
+			AND json_extract(event_data, '$.file_path') = ?
+		`, sfc.filePath).Scan(&verdict); err != nil {
+			t.Fatalf("Expected CorruptionDetected event: %v", err)
+		}
+		if verdict != integration.VerdictHealthy {
+			t.Errorf("Expected MediaInfo's verdict to be recorded, got %q", verdict)
+		}
+	})
+
+	t.Run("HDR metadata problems follow the HDR policy", func(t *testing.T) {
+		for policy, want := range map[string]bool{
+			config.HDRMetadataFlag:      false,
+			config.HDRMetadataRemediate: true,
+		} {
+			scanner.SetHDRMetadataPolicy(policy)
+			progress := &ScanProgress{ID: "test-corruption-hdr-" + policy, Path: "/media/movies"}
+			sfc := &scanFileContext{
+				filePath:          "/media/movies/hdr-" + policy + ".mkv",
+				autoRemediate:     true,
+				activeCorruptions: make(map[string]bool),
+			}
+			healthErr := &integration.HealthCheckError{
+				Type:    integration.ErrorTypeHDRMetadata,
+				Message: "Dolby Vision profile 8 without RPU (dynamic metadata)",
+			}
+			scanner.handleTrueCorruption(context.Background(), progress, sfc, healthErr)
+
+			var autoRemediate bool
+			if err := db.QueryRow(`
+				SELECT json_extract(event_data, '$.auto_remediate') FROM events
+				WHERE event_type = 'CorruptionDetected'
+				AND json_extract(event_data, '$.file_path') = ?
+			`, sfc.filePath).Scan(&autoRemediate); err != nil {
+				t.Fatalf("%s: expected CorruptionDetected event: %v", policy, err)
+			}
+			if autoRemediate != want {
+				t.Errorf("%s: auto_remediate = %v, want %v", policy, autoRemediate, want)
+			}
+		}
+	})
 }
 
 // =============================================================================
@@ -2486,7 +2681,7 @@ func TestScannerService_ScanFiles(t *testing.T) {
 		}
 
 		scanner.scanFiles(ctx, progress, scanFilesConfig{
-			Files:           []string{testFile},
+			Files:           memoryFileQueue([]string{testFile}),
 			StartIndex:      0,
 			DetectionConfig: detectionConfig,
 			AutoRemediate:   true,
@@ -2561,7 +2756,7 @@ func TestScannerService_ScanFiles(t *testing.T) {
 		}
 
 		scanner.scanFiles(ctx, progress, scanFilesConfig{
-			Files:           files,
+			Files:           memoryFileQueue(files),
 			StartIndex:      0,
 			DetectionConfig: detectionConfig,
 			AutoRemediate:   false,
@@ -3416,6 +3611,141 @@ func TestScannerService_HandleRecoverableError_RecordsInDB(t *testing.T) {
 	})
 }
 
+// fallbackHealthChecker reports every file healthy by the mediainfo fallback.
+type fallbackHealthChecker struct {
+	testutil.MockHealthChecker
+}
+
+func (f *fallbackHealthChecker) CheckWithConfigDetector(string, integration.DetectionConfig) (bool, integration.DetectionMethod, *integration.HealthCheckError) {
+	time.Sleep(2 * time.Millisecond)
+	return true, integration.DetectionMediaInfo, nil
+}
+
+func TestScannerService_ScanFileResults(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	testutil.SeedScanPath(db, 1, "/media/movies", "/movies", false, false)
+	result, err := db.Exec(`INSERT INTO scans (path, path_id, status) VALUES ('/media/movies', 1, 'running')`)
+	if err != nil {
+		t.Fatalf("Failed to create scan: %v", err)
+	}
+	scanDBID, _ := result.LastInsertId()
+
+	scanner := NewScannerService(db, eb, &fallbackHealthChecker{}, &testutil.MockPathMapper{})
+	newContext := func(name string) *scanFileContext {
+		return &scanFileContext{
+			filePath:        "/media/movies/" + name,
+			pathID:          1,
+			scanDBID:        scanDBID,
+			detectionConfig: integration.DetectionConfig{Method: integration.DetectionFFprobe, Mode: integration.ModeQuick},
+		}
+	}
+	fileResult := func(name string) (status string, durationMs sql.NullInt64, tool sql.NullString) {
+		t.Helper()
+		err := db.QueryRow(`SELECT status, duration_ms, detection_tool FROM scan_files WHERE file_path = ?`,
+			"/media/movies/"+name).Scan(&status, &durationMs, &tool)
+		if err != nil {
+			t.Fatalf("Failed to query scan file %s: %v", name, err)
+		}
+		return status, durationMs, tool
+	}
+
+	t.Run("records duration and the fallback tool used", func(t *testing.T) {
+		sfc := newContext("healthy.mkv")
+		if healthy, _ := scanner.checkFile(sfc); !healthy {
+			t.Fatal("Expected healthy result")
+		}
+		scanner.recordHealthyFile(sfc)
+
+		status, durationMs, tool := fileResult("healthy.mkv")
+		if status != "healthy" || tool.String != "mediainfo" {
+			t.Errorf("Expected healthy by mediainfo, got %s by %q", status, tool.String)
+		}
+		if !durationMs.Valid || durationMs.Int64 < 2 {
+			t.Errorf("Expected check duration of at least 2ms, got %v", durationMs)
+		}
+	})
+
+	t.Run("detector failures are errors, not inaccessible", func(t *testing.T) {
+		progress := &ScanProgress{ID: "results", Path: "/media/movies"}
+		scanner.handleRecoverableError(progress, newContext("hung.mkv"), &integration.HealthCheckError{
+			Type: integration.ErrorTypeToolFailure, Message: "ffprobe killed after 10m",
+		})
+		scanner.handleRecoverableError(progress, newContext("offline.mkv"), &integration.HealthCheckError{
+			Type: integration.ErrorTypeIOError, Message: "Input/output error",
+		})
+
+		if status, _, _ := fileResult("hung.mkv"); status != "error" {
+			t.Errorf("Expected status 'error' for a tool failure, got %q", status)
+		}
+		status, durationMs, tool := fileResult("offline.mkv")
+		if status != "inaccessible" {
+			t.Errorf("Expected status 'inaccessible' for an I/O error, got %q", status)
+		}
+		if durationMs.Valid || tool.Valid {
+			t.Errorf("Expected no duration or tool without a check, got %v %v", durationMs, tool)
+		}
+	})
+
+	t.Run("keeps results of the most recent scans per path", func(t *testing.T) {
+		if _, err := db.Exec(`UPDATE scans SET status = 'completed'`); err != nil {
+			t.Fatalf("Failed to complete scan: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			result, err := db.Exec(`INSERT INTO scans (path, path_id, status) VALUES ('/media/movies', 1, 'completed')`)
+			if err != nil {
+				t.Fatalf("Failed to create scan: %v", err)
+			}
+			id, _ := result.LastInsertId()
+			scanner.recordScanFile(&scanFileContext{filePath: fmt.Sprintf("/media/movies/%d.mkv", i), scanDBID: id}, "healthy", "", "")
+		}
+
+		scanner.pruneScanFiles(1) // retention not configured: keeps everything
+		var count int
+		db.QueryRow(`SELECT COUNT(*) FROM scan_files`).Scan(&count)
+		if count != 5 {
+			t.Fatalf("Expected 5 scan files before pruning, got %d", count)
+		}
+
+		scanner.SetScanResultsRetention(2)
+		scanner.pruneScanFiles(1)
+		db.QueryRow(`SELECT COUNT(*) FROM scan_files WHERE scan_id = ?`, scanDBID).Scan(&count)
+		if count != 0 {
+			t.Errorf("Expected oldest scan's files to be pruned, %d left", count)
+		}
+		db.QueryRow(`SELECT COUNT(*) FROM scan_files`).Scan(&count)
+		if count != 2 {
+			t.Errorf("Expected files of the 2 most recent scans to be kept, got %d", count)
+		}
+	})
+
+	t.Run("findings without consensus are skipped", func(t *testing.T) {
+		progress := &ScanProgress{ID: "results", Path: "/media/movies"}
+		healthErr := &integration.HealthCheckError{
+			Type:    integration.ErrorTypeNoConsensus,
+			Message: "CorruptHeader found by ffprobe, but mediainfo found the file healthy (consensus: majority)",
+		}
+		cfg := scanFilesConfig{ScanDBID: scanDBID}
+		if action := scanner.handleHealthCheckResult(context.Background(), progress, cfg, 1, newContext("disputed.mkv"), healthErr); action != scanContinue {
+			t.Errorf("Expected scanContinue, got %v", action)
+		}
+
+		if status, _, _ := fileResult("disputed.mkv"); status != "skipped" {
+			t.Errorf("Expected status 'skipped' without consensus, got %q", status)
+		}
+		if progress.corruptionCount != 0 {
+			t.Errorf("Overruled finding should not be counted, got %d", progress.corruptionCount)
+		}
+	})
+}
+
 func TestScannerService_ResumeScan_ParsesDetectionConfig(t *testing.T) {
 	db, err := testutil.NewTestDB()
 	if err != nil {
@@ -3762,7 +4092,7 @@ func TestClassifyEntry(t *testing.T) {
 		entries, _ := os.ReadDir(tmpDir)
 		for _, entry := range entries {
 			if entry.Name() == "movie.mkv" {
-				isMedia, isSkipped, isSymlink := classifyEntry(mediaFile, entry)
+				isMedia, isSkipped, isSymlink := classifyEntry(mediaFile, entry, mediaFilter{})
 				if !isMedia || isSkipped || isSymlink {
 					t.Errorf("classifyEntry(media file) = (%v, %v, %v), want (true, false, false)", isMedia, isSkipped, isSymlink)
 				}
@@ -3780,7 +4110,7 @@ func TestClassifyEntry(t *testing.T) {
 		entries, _ := os.ReadDir(tmpDir)
 		for _, entry := range entries {
 			if entry.Name() == ".hidden.mkv" {
-				isMedia, isSkipped, isSymlink := classifyEntry(hiddenFile, entry)
+				isMedia, isSkipped, isSymlink := classifyEntry(hiddenFile, entry, mediaFilter{})
 				if isMedia || !isSkipped || isSymlink {
 					t.Errorf("classifyEntry(hidden file) = (%v, %v, %v), want (false, true, false)", isMedia, isSkipped, isSymlink)
 				}
@@ -3802,7 +4132,7 @@ func TestClassifyEntry(t *testing.T) {
 		entries, _ := os.ReadDir(tmpDir)
 		for _, entry := range entries {
 			if entry.Name() == "link.mkv" {
-				isMedia, isSkipped, isSymlink := classifyEntry(linkFile, entry)
+				isMedia, isSkipped, isSymlink := classifyEntry(linkFile, entry, mediaFilter{})
 				if isMedia || isSkipped || !isSymlink {
 					t.Errorf("classifyEntry(symlink) = (%v, %v, %v), want (false, false, true)", isMedia, isSkipped, isSymlink)
 				}
@@ -3820,7 +4150,7 @@ func TestClassifyEntry(t *testing.T) {
 		entries, _ := os.ReadDir(tmpDir)
 		for _, entry := range entries {
 			if entry.Name() == "subdir" {
-				isMedia, isSkipped, isSymlink := classifyEntry(subDir, entry)
+				isMedia, isSkipped, isSymlink := classifyEntry(subDir, entry, mediaFilter{})
 				if isMedia || isSkipped || isSymlink {
 					t.Errorf("classifyEntry(directory) = (%v, %v, %v), want (false, false, false)", isMedia, isSkipped, isSymlink)
 				}
@@ -3838,7 +4168,7 @@ func TestClassifyEntry(t *testing.T) {
 		entries, _ := os.ReadDir(tmpDir)
 		for _, entry := range entries {
 			if entry.Name() == "readme.txt" {
-				isMedia, isSkipped, isSymlink := classifyEntry(textFile, entry)
+				isMedia, isSkipped, isSymlink := classifyEntry(textFile, entry, mediaFilter{})
 				if isMedia || !isSkipped || isSymlink {
 					t.Errorf("classifyEntry(non-media file) = (%v, %v, %v), want (false, true, false)", isMedia, isSkipped, isSymlink)
 				}
@@ -4127,8 +4457,9 @@ func TestScannerService_MarkFileProcessed(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to query scan: %v", err)
 		}
-		if currentIndex != 20 {
-			t.Errorf("Expected current_file_index 20, got %d", currentIndex)
+		// The saved index is that of the next file to scan
+		if currentIndex != 21 {
+			t.Errorf("Expected current_file_index 21, got %d", currentIndex)
 		}
 		if filesScanned != 20 {
 			t.Errorf("Expected files_scanned 20, got %d", filesScanned)
@@ -4260,3 +4591,197 @@ func TestScannerService_ContentAnalysis_QuickMode_Skipped(t *testing.T) {
 		t.Error("AnalyzeContent should not be called in quick mode")
 	}
 }
+
+func TestParseDetectionFallbacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     sql.NullString
+		primary integration.DetectionMethod
+		want    []integration.DetectionMethod
+	}{
+		{"null uses built-in chain", sql.NullString{}, integration.DetectionFFprobe, integration.DefaultFallbacksFor(integration.DetectionFFprobe)},
+		{"custom order", sql.NullString{String: `["handbrake","mediainfo"]`, Valid: true}, integration.DetectionFFprobe,
+			[]integration.DetectionMethod{integration.DetectionHandBrake, integration.DetectionMediaInfo}},
+		{"empty list disables fallbacks", sql.NullString{String: `[]`, Valid: true}, integration.DetectionFFprobe, []integration.DetectionMethod{}},
+		{"invalid JSON uses built-in chain", sql.NullString{String: `not json`, Valid: true}, integration.DetectionMediaInfo, integration.DefaultFallbacksFor(integration.DetectionMediaInfo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDetectionFallbacks(tt.raw, tt.primary)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("parseDetectionFallbacks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadScanPathSettings_DetectionFallbacks(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, detection_method, detection_mode, detection_fallbacks)
+		VALUES (1, '/media/tv', '/tv', 'mediainfo', 'quick', '["handbrake"]')`)
+	if err != nil {
+		t.Fatalf("Failed to insert scan path: %v", err)
+	}
+
+	s := &ScannerService{db: db}
+	cfg := s.loadScanPathSettings(1)
+	if cfg.DetectionConfig.Method != integration.DetectionMediaInfo {
+		t.Errorf("Method = %s, want mediainfo", cfg.DetectionConfig.Method)
+	}
+	if len(cfg.DetectionConfig.Fallbacks) != 1 || cfg.DetectionConfig.Fallbacks[0] != integration.DetectionHandBrake {
+		t.Errorf("Fallbacks = %v, want [handbrake]", cfg.DetectionConfig.Fallbacks)
+	}
+}
+
+func TestLoadScanPathSettings_FileStability(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO scan_paths (id, local_path, arr_path, min_file_age_minutes, size_stability_seconds)
+		VALUES (1, '/media/tv', '/tv', 30, 10), (2, '/media/movies', '/movies', 0, 0), (3, '/media/music', '/music', NULL, NULL)`)
+	if err != nil {
+		t.Fatalf("Failed to insert scan paths: %v", err)
+	}
+
+	s := &ScannerService{db: db}
+	tests := []struct {
+		pathID   int64
+		wantAge  time.Duration
+		wantWait time.Duration
+	}{
+		{1, 30 * time.Minute, 10 * time.Second},
+		{2, -1, 0}, // Age check disabled, quick size check
+		{3, 0, 0},  // NULL keeps defaults
+	}
+	for _, tt := range tests {
+		got := s.loadScanPathSettings(tt.pathID).Stability
+		if got.MinFileAge != tt.wantAge || got.SizeStabilityWait != tt.wantWait {
+			t.Errorf("path %d: Stability = %+v, want age %v wait %v", tt.pathID, got, tt.wantAge, tt.wantWait)
+		}
+	}
+}
+
+func TestScannerService_ShouldSkipRecentlyModified_CustomAge(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	result, err := db.Exec(`INSERT INTO scans (path, path_id, status) VALUES ('/media/movies', 1, 'running')`)
+	if err != nil {
+		t.Fatalf("Failed to create scan: %v", err)
+	}
+	scanDBID, _ := result.LastInsertId()
+
+	scanner := &ScannerService{db: db, shutdownCh: make(chan struct{})}
+
+	t.Run("longer minimum age skips older file", func(t *testing.T) {
+		sfc := &scanFileContext{
+			filePath:  "/media/movies/copying.mkv",
+			fileMtime: time.Now().Add(-10 * time.Minute),
+			scanDBID:  scanDBID,
+			stability: fileStabilityConfig{MinFileAge: 30 * time.Minute},
+		}
+		if !scanner.shouldSkipRecentlyModified(sfc) {
+			t.Fatal("Expected file younger than 30 minutes to be skipped")
+		}
+
+		var details string
+		if err := db.QueryRow(`SELECT error_details FROM scan_files WHERE scan_id = ? AND file_path = ?`,
+			scanDBID, sfc.filePath).Scan(&details); err != nil {
+			t.Fatalf("Failed to query: %v", err)
+		}
+		if !strings.Contains(details, "30 minutes") {
+			t.Errorf("error_details = %q, want configured age", details)
+		}
+	})
+
+	t.Run("disabled check never skips", func(t *testing.T) {
+		sfc := &scanFileContext{
+			filePath:  "/media/movies/new.mkv",
+			fileMtime: time.Now(),
+			stability: fileStabilityConfig{MinFileAge: -1},
+		}
+		if scanner.shouldSkipRecentlyModified(sfc) {
+			t.Error("Expected age check to be disabled")
+		}
+	})
+}
+
+func TestScannerService_ShouldSkipChangingSize_InterruptedByShutdown(t *testing.T) {
+	scanner := &ScannerService{shutdownCh: make(chan struct{})}
+	close(scanner.shutdownCh)
+
+	testFile := filepath.Join(t.TempDir(), "new.mkv")
+	if err := os.WriteFile(testFile, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	sfc := &scanFileContext{
+		filePath:  testFile,
+		fileSize:  7,
+		stability: fileStabilityConfig{SizeStabilityWait: time.Hour},
+	}
+
+	start := time.Now()
+	if !scanner.shouldSkipChangingSize(sfc) {
+		t.Error("Expected file to be skipped when shutdown interrupts the wait")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected shutdown to interrupt the size stability wait")
+	}
+}
+
+func TestScannerService_ShouldSkipChangingSize_OnlyWaitsForRecentFiles(t *testing.T) {
+	scanner := &ScannerService{shutdownCh: make(chan struct{})}
+
+	testFile := filepath.Join(t.TempDir(), "old.mkv")
+	if err := os.WriteFile(testFile, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(testFile, old, old); err != nil {
+		t.Fatalf("Failed to age file: %v", err)
+	}
+
+	sfc := &scanFileContext{
+		filePath:  testFile,
+		fileSize:  7,
+		stability: fileStabilityConfig{SizeStabilityWait: time.Hour},
+	}
+	start := time.Now()
+	if scanner.shouldSkipChangingSize(sfc) {
+		t.Error("Expected a file left alone for longer than the wait not to be skipped")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected no size stability wait for a file modified before the window")
+	}
+
+	// A size that changed since the file was listed needs no wait either
+	sfc.fileSize = 3
+	if !scanner.shouldSkipChangingSize(sfc) {
+		t.Error("Expected a file whose size changed since it was listed to be skipped")
+	}
+}
+
+func TestFormatMinFileAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		time.Minute:      "1 minute",
+		2 * time.Minute:  "2 minutes",
+		90 * time.Second: "1m30s",
+	}
+	for d, want := range tests {
+		if got := formatMinFileAge(d); got != want {
+			t.Errorf("formatMinFileAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/robfig/cron/v3"
 
+	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/logger"
 )
 
@@ -24,9 +25,9 @@ type Scheduler interface {
 	Start()
 	Stop()
 	LoadSchedules() error
-	AddSchedule(scanPathID int, cronExpr string) (int64, error)
+	AddSchedule(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error)
 	DeleteSchedule(id int) error
-	UpdateSchedule(id int, cronExpr string, enabled bool) error
+	UpdateSchedule(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error
 	CleanupOrphanedSchedules() (int, error)
 }
 
@@ -37,19 +38,36 @@ type SchedulerService struct {
 	cron    *cron.Cron
 	jobs    map[int]cron.EntryID
 	mu      sync.Mutex
+
+	// queue runs the scans of schedules as they fire (see SetConcurrency)
+	queue *scanQueue
+
+	// pause skips scheduled scans while automation is paused (nil: never paused)
+	pause *SystemPause
 }
 
 // NewSchedulerService creates a new SchedulerService with the given database and scanner.
-// Cron expressions are interpreted in the TZ from $HEALARR_TZ or $TZ, falling back to local time.
+// Cron expressions are interpreted in the schedule's own timezone, else in the
+// TZ from $HEALARR_TZ or $TZ, falling back to local time.
 func NewSchedulerService(db *sql.DB, scanner *ScannerService) *SchedulerService {
 	return &SchedulerService{
 		db:      db,
 		scanner: scanner,
 		cron:    cron.New(cron.WithLocation(cronLocation())),
 		jobs:    make(map[int]cron.EntryID),
+		queue:   newScanQueue(scanner),
 	}
 }
 
+// SetConcurrency limits how many scheduled scans run at once (0 for no
+// limit). Scans of schedules that fire while the limit is reached wait and
+// start in order of their scan path's priority. With preempt, a scan of a
+// higher-priority path pauses the lowest-priority running scan instead of
+// waiting; the paused scan resumes once a slot frees up.
+func (s *SchedulerService) SetConcurrency(limit int, preempt bool) {
+	s.queue.configure(limit, preempt)
+}
+
 // cronLocation picks a timezone for cron schedules. HEALARR_TZ wins if set,
 // then TZ, then local time. Invalid values log a warning and fall back.
 func cronLocation() *time.Location {
@@ -76,9 +94,11 @@ func (s *SchedulerService) Start() {
 	}
 }
 
-// Stop stops the cron engine and all scheduled jobs.
+// Stop stops the cron engine and all scheduled jobs. Scans waiting for a
+// slot are dropped.
 func (s *SchedulerService) Stop() {
 	s.cron.Stop()
+	s.queue.stop()
 }
 
 // LoadSchedules loads all enabled schedules from the database and registers them with cron.
@@ -99,7 +119,7 @@ func (s *SchedulerService) LoadSchedules() error {
 	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
 	defer cancel()
 
-	rows, err := s.db.QueryContext(ctx, "SELECT id, scan_path_id, cron_expression, enabled FROM scan_schedules WHERE enabled = 1")
+	rows, err := s.db.QueryContext(ctx, "SELECT id, scan_path_id, cron_expression, enabled, timezone, dst_gap, dst_overlap FROM scan_schedules WHERE enabled = 1")
 	if err != nil {
 		return fmt.Errorf("failed to query schedules: %w", err)
 	}
@@ -116,7 +136,8 @@ func (s *SchedulerService) LoadSchedules() error {
 		var id, scanPathID int
 		var cronExpr string
 		var enabled bool
-		if err := rows.Scan(&id, &scanPathID, &cronExpr, &enabled); err != nil {
+		var timing domain.ScheduleTiming
+		if err := rows.Scan(&id, &scanPathID, &cronExpr, &enabled, &timing.Timezone, &timing.DSTGap, &timing.DSTOverlap); err != nil {
 			logger.Errorf("Failed to scan schedule row: %v", err)
 			skipped++
 			continue
@@ -125,13 +146,14 @@ func (s *SchedulerService) LoadSchedules() error {
 		logger.Debugf("Scheduler: processing schedule %d (path_id=%d, cron=%s)", id, scanPathID, cronExpr)
 
 		// Pre-validate cron expression before attempting to add job
-		if _, parseErr := cron.ParseStandard(cronExpr); parseErr != nil {
+		schedule, parseErr := ParseSchedule(cronExpr, timing)
+		if parseErr != nil {
 			logger.Errorf("Schedule %d has invalid cron expression '%s': %v - skipping", id, cronExpr, parseErr)
 			skipped++
 			continue
 		}
 
-		if err := s.addJob(id, scanPathID, cronExpr); err != nil {
+		if err := s.addJob(id, scanPathID, schedule); err != nil {
 			logger.Errorf("Failed to add job for schedule %d: %v", id, err)
 			skipped++
 		} else {
@@ -152,14 +174,14 @@ func (s *SchedulerService) LoadSchedules() error {
 	return nil
 }
 
-func (s *SchedulerService) addJob(scheduleID, scanPathID int, cronExpr string) error {
+func (s *SchedulerService) addJob(scheduleID, scanPathID int, schedule cron.Schedule) error {
 	// Use context with timeout for database query
 	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
 	defer cancel()
 
 	// Verify scan path exists
 	var localPath string
-	err := s.db.QueryRowContext(ctx, "SELECT local_path FROM scan_paths WHERE id = ?", scanPathID).Scan(&localPath)
+	err := s.db.QueryRowContext(ctx, "SELECT local_path FROM scan_paths WHERE id = ? AND deleted_at IS NULL", scanPathID).Scan(&localPath)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("scan path %d not found (may have been deleted)", scanPathID)
@@ -169,30 +191,50 @@ func (s *SchedulerService) addJob(scheduleID, scanPathID int, cronExpr string) e
 
 	logger.Debugf("Scheduler: adding cron job for schedule %d (path: %s)", scheduleID, localPath)
 
-	entryID, err := s.cron.AddFunc(cronExpr, func() {
-		logger.Infof("Executing scheduled scan for path: %s (Schedule ID: %d)", localPath, scheduleID)
-		if err := s.scanner.ScanPath(int64(scanPathID), localPath); err != nil {
-			logger.Errorf("Scheduled scan failed for path %s: %v", localPath, err)
+	entryID := s.cron.Schedule(schedule, cron.FuncJob(func() {
+		if s.pause.ScansPaused() {
+			logger.Infof("Scheduler: skipping scheduled scan of %s, automation is paused", localPath)
+			return
 		}
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to register cron job: %w", err)
-	}
+		s.queue.submit(&queuedScan{
+			scheduleID: scheduleID,
+			pathID:     int64(scanPathID),
+			localPath:  localPath,
+			priority:   s.pathPriority(scanPathID),
+		})
+	}))
 
 	s.jobs[scheduleID] = entryID
 	logger.Debugf("Scheduler: successfully registered schedule %d with cron entry %d", scheduleID, entryID)
 	return nil
 }
 
-// AddSchedule creates a new schedule for the given scan path with the specified cron expression.
-func (s *SchedulerService) AddSchedule(scanPathID int, cronExpr string) (int64, error) {
+// pathPriority returns the priority of a scan path when its schedule fires,
+// so priority changes apply without reloading the schedules.
+func (s *SchedulerService) pathPriority(scanPathID int) int {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+
+	var priority int
+	if err := s.db.QueryRowContext(ctx, "SELECT priority FROM scan_paths WHERE id = ?", scanPathID).Scan(&priority); err != nil {
+		logger.Debugf("Scheduler: failed to load priority of scan path %d, using 0: %v", scanPathID, err)
+		return 0
+	}
+	return priority
+}
+
+// AddSchedule creates a new schedule for the given scan path with the specified
+// cron expression, timezone and DST policies.
+func (s *SchedulerService) AddSchedule(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error) {
 	// Validate cron expression
-	if _, err := cron.ParseStandard(cronExpr); err != nil {
+	schedule, err := ParseSchedule(cronExpr, timing)
+	if err != nil {
 		return 0, fmt.Errorf("invalid cron expression: %v", err)
 	}
 
-	res, err := s.db.Exec("INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled) VALUES (?, ?, 1)", scanPathID, cronExpr)
+	timing = timing.WithDefaults()
+	res, err := s.db.Exec("INSERT INTO scan_schedules (scan_path_id, cron_expression, enabled, timezone, dst_gap, dst_overlap) VALUES (?, ?, 1, ?, ?, ?)",
+		scanPathID, cronExpr, timing.Timezone, timing.DSTGap, timing.DSTOverlap)
 	if err != nil {
 		return 0, err
 	}
@@ -204,7 +246,7 @@ func (s *SchedulerService) AddSchedule(scanPathID int, cronExpr string) (int64,
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.addJob(int(id), scanPathID, cronExpr); err != nil {
+	if err := s.addJob(int(id), scanPathID, schedule); err != nil {
 		return id, fmt.Errorf("saved to DB but failed to schedule: %v", err)
 	}
 
@@ -256,11 +298,25 @@ func (s *SchedulerService) CleanupOrphanedSchedules() (int, error) {
 	return int(affected), nil
 }
 
-// UpdateSchedule updates a schedule's cron expression and enabled state.
-func (s *SchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool) error {
-	// Validate cron expression if provided
-	if cronExpr != "" {
-		if _, err := cron.ParseStandard(cronExpr); err != nil {
+// UpdateSchedule updates a schedule's enabled state and, if given, its cron
+// expression and timing.
+func (s *SchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
+	// Validate the new cron expression and timing against what is kept
+	if cronExpr != "" || timing != nil {
+		var currentCron string
+		var current domain.ScheduleTiming
+		err := s.db.QueryRow("SELECT cron_expression, timezone, dst_gap, dst_overlap FROM scan_schedules WHERE id = ?", id).
+			Scan(&currentCron, &current.Timezone, &current.DSTGap, &current.DSTOverlap)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to fetch schedule: %v", err)
+		}
+		if cronExpr != "" {
+			currentCron = cronExpr
+		}
+		if timing != nil {
+			current = *timing
+		}
+		if _, err := ParseSchedule(currentCron, current); err != nil {
 			return fmt.Errorf("invalid cron expression: %v", err)
 		}
 	}
@@ -272,6 +328,11 @@ func (s *SchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool)
 		query += ", cron_expression = ?"
 		args = append(args, cronExpr)
 	}
+	if timing != nil {
+		t := timing.WithDefaults()
+		query += ", timezone = ?, dst_gap = ?, dst_overlap = ?"
+		args = append(args, t.Timezone, t.DSTGap, t.DSTOverlap)
+	}
 	query += " WHERE id = ?"
 	args = append(args, id)
 
@@ -295,12 +356,18 @@ func (s *SchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool)
 		// We need the scan_path_id and current cron expression (if not updated)
 		var scanPathID int
 		var currentCron string
-		err := s.db.QueryRow("SELECT scan_path_id, cron_expression FROM scan_schedules WHERE id = ?", id).Scan(&scanPathID, &currentCron)
+		var current domain.ScheduleTiming
+		err := s.db.QueryRow("SELECT scan_path_id, cron_expression, timezone, dst_gap, dst_overlap FROM scan_schedules WHERE id = ?", id).
+			Scan(&scanPathID, &currentCron, &current.Timezone, &current.DSTGap, &current.DSTOverlap)
 		if err != nil {
 			return fmt.Errorf("failed to fetch updated schedule: %v", err)
 		}
 
-		if err := s.addJob(id, scanPathID, currentCron); err != nil {
+		schedule, err := ParseSchedule(currentCron, current)
+		if err != nil {
+			return fmt.Errorf("invalid cron expression: %v", err)
+		}
+		if err := s.addJob(id, scanPathID, schedule); err != nil {
 			logger.Errorf("Failed to reschedule job %d: %v", id, err)
 		}
 	}
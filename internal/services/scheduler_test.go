@@ -6,6 +6,7 @@ import (
 
 	_ "modernc.org/sqlite" // Register pure-Go SQLite driver for database/sql
 
+	"github.com/mescon/Healarr/internal/domain"
 	"github.com/mescon/Healarr/internal/testutil"
 )
 
@@ -26,6 +27,9 @@ func TestNewSchedulerService(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1,
 			FOREIGN KEY (scan_path_id) REFERENCES scan_paths(id)
 		)
@@ -68,6 +72,9 @@ func TestSchedulerService_LoadSchedules_EmptyDB(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -100,6 +107,9 @@ func TestSchedulerService_LoadSchedules_DisabledSchedules(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -142,12 +152,16 @@ func TestSchedulerService_LoadSchedules_WithValidSchedule(t *testing.T) {
 			id INTEGER PRIMARY KEY,
 			local_path TEXT NOT NULL,
 			arr_path TEXT NOT NULL,
-			enabled BOOLEAN DEFAULT 1
+			enabled BOOLEAN DEFAULT 1,
+			deleted_at TIMESTAMP DEFAULT NULL
 		);
 		CREATE TABLE scan_schedules (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		);
 	`)
@@ -200,6 +214,9 @@ func TestSchedulerService_AddSchedule_InvalidCron(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -209,7 +226,7 @@ func TestSchedulerService_AddSchedule_InvalidCron(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	_, err = s.AddSchedule(1, "invalid cron")
+	_, err = s.AddSchedule(1, "invalid cron", domain.ScheduleTiming{})
 	if err == nil {
 		t.Error("AddSchedule should fail for invalid cron expression")
 	}
@@ -228,6 +245,9 @@ func TestSchedulerService_AddSchedule_ValidCron(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -243,7 +263,7 @@ func TestSchedulerService_AddSchedule_ValidCron(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	id, err := s.AddSchedule(1, "0 0 * * *") // Daily at midnight
+	id, err := s.AddSchedule(1, "0 0 * * *", domain.ScheduleTiming{}) // Daily at midnight
 	if err != nil {
 		t.Errorf("AddSchedule() error = %v", err)
 	}
@@ -278,6 +298,9 @@ func TestSchedulerService_AddSchedule_NonExistentPath(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -288,7 +311,7 @@ func TestSchedulerService_AddSchedule_NonExistentPath(t *testing.T) {
 	s := NewSchedulerService(db, nil)
 
 	// Try to add schedule for non-existent path
-	id, err := s.AddSchedule(999, "0 0 * * *")
+	id, err := s.AddSchedule(999, "0 0 * * *", domain.ScheduleTiming{})
 
 	// Should succeed in saving to DB but fail in addJob
 	// The returned id is valid, but error indicates scheduling failed
@@ -315,6 +338,9 @@ func TestSchedulerService_DeleteSchedule(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -330,7 +356,7 @@ func TestSchedulerService_DeleteSchedule(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	id, err := s.AddSchedule(1, "0 0 * * *")
+	id, err := s.AddSchedule(1, "0 0 * * *", domain.ScheduleTiming{})
 	if err != nil {
 		t.Fatalf("AddSchedule() error = %v", err)
 	}
@@ -367,6 +393,9 @@ func TestSchedulerService_DeleteSchedule_NotFound(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -400,6 +429,9 @@ func TestSchedulerService_UpdateSchedule_InvalidCron(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -409,7 +441,7 @@ func TestSchedulerService_UpdateSchedule_InvalidCron(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	err = s.UpdateSchedule(1, "invalid cron", true)
+	err = s.UpdateSchedule(1, "invalid cron", true, nil)
 	if err == nil {
 		t.Error("UpdateSchedule should fail for invalid cron expression")
 	}
@@ -428,6 +460,9 @@ func TestSchedulerService_UpdateSchedule_DisableSchedule(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -443,7 +478,7 @@ func TestSchedulerService_UpdateSchedule_DisableSchedule(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	id, err := s.AddSchedule(1, "0 0 * * *")
+	id, err := s.AddSchedule(1, "0 0 * * *", domain.ScheduleTiming{})
 	if err != nil {
 		t.Fatalf("AddSchedule() error = %v", err)
 	}
@@ -454,7 +489,7 @@ func TestSchedulerService_UpdateSchedule_DisableSchedule(t *testing.T) {
 	}
 
 	// Disable the schedule
-	err = s.UpdateSchedule(int(id), "", false)
+	err = s.UpdateSchedule(int(id), "", false, nil)
 	if err != nil {
 		t.Errorf("UpdateSchedule() error = %v", err)
 	}
@@ -478,6 +513,9 @@ func TestSchedulerService_UpdateSchedule_ChangeCron(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -493,13 +531,13 @@ func TestSchedulerService_UpdateSchedule_ChangeCron(t *testing.T) {
 
 	s := NewSchedulerService(db, nil)
 
-	id, err := s.AddSchedule(1, "0 0 * * *")
+	id, err := s.AddSchedule(1, "0 0 * * *", domain.ScheduleTiming{})
 	if err != nil {
 		t.Fatalf("AddSchedule() error = %v", err)
 	}
 
 	// Change cron expression
-	err = s.UpdateSchedule(int(id), "0 */2 * * *", true)
+	err = s.UpdateSchedule(int(id), "0 */2 * * *", true, nil)
 	if err != nil {
 		t.Errorf("UpdateSchedule() error = %v", err)
 	}
@@ -532,6 +570,9 @@ func TestSchedulerService_StartStop(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -563,6 +604,9 @@ func TestSchedulerService_CronExpressionValidation(t *testing.T) {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1
 		)
 	`)
@@ -598,7 +642,7 @@ func TestSchedulerService_CronExpressionValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := s.AddSchedule(1, tt.cron)
+			_, err := s.AddSchedule(1, tt.cron, domain.ScheduleTiming{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddSchedule(%q) error = %v, wantErr %v", tt.cron, err, tt.wantErr)
 			}
@@ -623,6 +667,9 @@ func setupSchedulerTestDB(t *testing.T) *sql.DB {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			scan_path_id INTEGER NOT NULL,
 			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
 			enabled BOOLEAN DEFAULT 1,
 			FOREIGN KEY (scan_path_id) REFERENCES scan_paths(id)
 		)
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// ErrInvalidRollout is returned for a negative staged rollout limit or period.
+var ErrInvalidRollout = errors.New("rollout_daily_limit and rollout_days must not be negative")
+
+// ValidateRollout checks the staged rollout settings of a scan path. A daily
+// limit of 0 disables the staged rollout.
+func ValidateRollout(dailyLimit, days int) error {
+	if dailyLimit < 0 || days < 0 {
+		return ErrInvalidRollout
+	}
+	return nil
+}
+
+// stagedRollout counts the remediations each scan path in its break-in period
+// started today.
+type stagedRollout struct {
+	day     time.Time       // start of the day the counts are for
+	started map[int64]int   // remediations started today per path
+	wake    chan struct{}   // closed when deferred remediations should check again
+	waiting map[string]bool // corruptions deferred by the rollout
+}
+
+// RecheckRollout lets remediations deferred by a staged rollout check again,
+// e.g. after a scan path's rollout limit was raised or removed.
+func (r *RemediatorService) RecheckRollout() {
+	r.rolloutMu.Lock()
+	defer r.rolloutMu.Unlock()
+	if r.rollout.wake != nil {
+		close(r.rollout.wake)
+		r.rollout.wake = nil
+	}
+}
+
+// RolloutDeferred returns how many remediations wait for a later day of their
+// path's staged rollout.
+func (r *RemediatorService) RolloutDeferred() int {
+	r.rolloutMu.Lock()
+	defer r.rolloutMu.Unlock()
+	return len(r.rollout.waiting)
+}
+
+// waitForRollout blocks until the corruption's path may start another
+// remediation today. During a path's break-in period at most its daily limit
+// of remediations start per day; the rest are deferred to the next day.
+// Returns false if the remediator shut down while waiting.
+func (r *RemediatorService) waitForRollout(corruptionID, filePath string, pathID int64) bool {
+	announced := false
+	defer func() {
+		if announced {
+			r.rolloutMu.Lock()
+			delete(r.rollout.waiting, corruptionID)
+			r.rolloutMu.Unlock()
+		}
+	}()
+
+	for {
+		limit, retryAt, wake, ok := r.admitRollout(corruptionID, pathID)
+		if ok {
+			return true
+		}
+		if !announced {
+			announced = true
+			r.rolloutMu.Lock()
+			r.rollout.waiting[corruptionID] = true
+			r.rolloutMu.Unlock()
+			r.publishRolloutDeferred(corruptionID, filePath, pathID, limit, retryAt)
+		}
+
+		wait := retryAt.Sub(r.clk.Now())
+		elapsed := make(chan struct{})
+		timer := r.clk.AfterFunc(max(wait, 0), func() { close(elapsed) })
+		select {
+		case <-elapsed:
+		case <-wake:
+			timer.Stop()
+		case <-r.shutdownCh:
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// admitRollout reports whether the corruption may start remediating now.
+// Otherwise it returns the path's daily limit, when to check again and a
+// channel that is closed when the rollout settings change. Corruptions that
+// already started a remediation before, i.e. retries, are never held back.
+func (r *RemediatorService) admitRollout(corruptionID string, pathID int64) (int, time.Time, <-chan struct{}, bool) {
+	now := r.clk.Now()
+	limit, endsAt, active := r.rolloutFor(pathID, now)
+	if !active || r.remediatedBefore(corruptionID) {
+		return 0, time.Time{}, nil, true
+	}
+
+	local := now.In(cronLocation())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+
+	r.rolloutMu.Lock()
+	defer r.rolloutMu.Unlock()
+	if !r.rollout.day.Equal(day) {
+		r.rollout.day = day
+		r.rollout.started = make(map[int64]int)
+	}
+	started, counted := r.rollout.started[pathID]
+	if !counted {
+		started = r.countRolloutStarts(pathID, day)
+	}
+	if started < limit {
+		r.rollout.started[pathID] = started + 1
+		return limit, time.Time{}, nil, true
+	}
+	r.rollout.started[pathID] = started
+
+	retryAt := day.AddDate(0, 0, 1)
+	if endsAt.Before(retryAt) {
+		retryAt = endsAt
+	}
+	if r.rollout.wake == nil {
+		r.rollout.wake = make(chan struct{})
+	}
+	return limit, retryAt, r.rollout.wake, false
+}
+
+// rolloutFor returns the daily remediation limit of a scan path and when its
+// break-in period ends. active is false once the period is over, or if the
+// path has no staged rollout.
+func (r *RemediatorService) rolloutFor(pathID int64, now time.Time) (int, time.Time, bool) {
+	if r.db == nil || pathID == 0 {
+		return 0, time.Time{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+
+	var limit int
+	var endsAt sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT rollout_daily_limit, strftime('%Y-%m-%dT%H:%M:%SZ', created_at, '+' || rollout_days || ' days')
+		FROM scan_paths WHERE id = ?
+	`, pathID).Scan(&limit, &endsAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Warnf("Failed to load staged rollout of path %d: %v", pathID, err)
+		}
+		return 0, time.Time{}, false
+	}
+	end := parseTimestamp(endsAt.String)
+	if limit <= 0 || end.IsZero() || !now.Before(end) {
+		return 0, time.Time{}, false
+	}
+	return limit, end, true
+}
+
+// remediatedBefore reports whether a remediation of the corruption already
+// started deleting its file.
+func (r *RemediatorService) remediatedBefore(corruptionID string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	var one int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT 1 FROM events WHERE aggregate_id = ? AND event_type = 'DeletionStarted' LIMIT 1
+	`, corruptionID).Scan(&one)
+	return err == nil
+}
+
+// countRolloutStarts counts the corruptions of a path whose first remediation
+// started on the given day, so the daily limit holds across restarts.
+func (r *RemediatorService) countRolloutStarts(pathID int64, day time.Time) int {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT MIN(substr(e.created_at, 1, 19)) AS first_started
+			FROM events e
+			JOIN corruption_summary cs ON cs.corruption_id = e.aggregate_id
+			WHERE cs.path_id = ? AND e.event_type = 'DeletionStarted'
+			GROUP BY e.aggregate_id
+		) WHERE first_started >= ?
+	`, pathID, day.UTC().Format("2006-01-02 15:04:05")).Scan(&count)
+	if err != nil {
+		logger.Warnf("Failed to count today's remediations of path %d: %v", pathID, err)
+	}
+	return count
+}
+
+func (r *RemediatorService) publishRolloutDeferred(corruptionID, filePath string, pathID int64, limit int, retryAt time.Time) {
+	reason := fmt.Sprintf("staged rollout of a new scan path: %d remediation(s) per day already started today", limit)
+	logger.Infof("Remediation of %s deferred until %s: %s", filePath, retryAt.Format(time.RFC3339), reason)
+	if err := r.eventBus.Publish(domain.Event{
+		AggregateID:   corruptionID,
+		AggregateType: "corruption",
+		EventType:     domain.RemediationDeferred,
+		EventData: map[string]interface{}{
+			"file_path":           filePath,
+			"path_id":             pathID,
+			"rollout_daily_limit": limit,
+			"deferred_until":      retryAt.UTC().Format(time.RFC3339),
+			"reason":              reason,
+		},
+	}); err != nil {
+		logger.Errorf("Failed to publish RemediationDeferred event: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediator_StagedRollout(t *testing.T) {
+	t.Setenv("HEALARR_TZ", "UTC")
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)
+	added := now.Add(-24 * time.Hour).Format("2006-01-02 15:04:05")
+	if _, err := db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, rollout_daily_limit, rollout_days, created_at) VALUES
+			(1, '/media/new', '/new', 2, 7, ?),
+			(2, '/media/old', '/old', 0, 0, ?)
+	`, added, added); err != nil {
+		t.Fatalf("Failed to seed scan paths: %v", err)
+	}
+	// One remediation of the new path already started today
+	if _, err := db.Exec(`
+		INSERT INTO corruption_summary (corruption_id, file_path, path_id, current_state, detected_at, last_updated_at)
+		VALUES ('earlier', '/media/new/earlier.mkv', 1, 'SearchStarted', ?, ?)
+	`, now, now); err != nil {
+		t.Fatalf("Failed to seed corruption summary: %v", err)
+	}
+	seedBudgetEvent(t, db, "earlier", domain.DeletionStarted, map[string]interface{}{"file_path": "/media/new/earlier.mkv"}, now.Add(-time.Hour))
+
+	bus := testutil.NewMockEventBus()
+	clk := testutil.NewMockClockAt(now)
+	r := NewRemediatorService(bus, &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.clk = clk
+	defer r.Stop()
+
+	if _, _, _, ok := r.admitRollout("a", 1); !ok {
+		t.Fatal("The second remediation of the day should go ahead")
+	}
+	limit, retryAt, _, ok := r.admitRollout("b", 1)
+	if ok {
+		t.Fatal("Expected the daily limit to defer the third remediation")
+	}
+	if limit != 2 || !retryAt.Equal(time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a retry at midnight with limit 2, got %v with limit %d", retryAt, limit)
+	}
+
+	// Retries and paths without a staged rollout aren't limited
+	if _, _, _, ok := r.admitRollout("earlier", 1); !ok {
+		t.Error("A retry should not count against the daily limit")
+	}
+	if _, _, _, ok := r.admitRollout("c", 2); !ok {
+		t.Error("A path without a staged rollout should not be limited")
+	}
+
+	// A deferred remediation goes ahead the next day
+	done := make(chan bool, 1)
+	go func() { done <- r.waitForRollout("b", "/media/new/b.mkv", 1) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for clk.PendingCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	deferred := bus.GetEvents(domain.RemediationDeferred)
+	if len(deferred) != 1 || deferred[0].EventData["deferred_until"] != "2026-04-16T00:00:00Z" || deferred[0].EventData["rollout_daily_limit"] != 2 {
+		t.Fatalf("Unexpected RemediationDeferred events: %+v", deferred)
+	}
+	if r.RolloutDeferred() != 1 {
+		t.Errorf("Expected 1 deferred remediation, got %d", r.RolloutDeferred())
+	}
+
+	clk.Advance(12 * time.Hour)
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("Expected the deferred remediation to go ahead")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the deferred remediation")
+	}
+	if r.RolloutDeferred() != 0 {
+		t.Errorf("Expected no deferred remediations, got %d", r.RolloutDeferred())
+	}
+
+	// The limit is lifted after the break-in period
+	clk.SetNow(now.Add(7 * 24 * time.Hour))
+	for _, id := range []string{"d", "e", "f"} {
+		if _, _, _, ok := r.admitRollout(id, 1); !ok {
+			t.Errorf("Remediation %s should not be limited after the break-in period", id)
+		}
+	}
+}
+
+func TestRemediator_StagedRolloutRecheck(t *testing.T) {
+	t.Setenv("HEALARR_TZ", "UTC")
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)
+	if _, err := db.Exec(`
+		INSERT INTO scan_paths (id, local_path, arr_path, rollout_daily_limit, rollout_days, created_at)
+		VALUES (1, '/media/new', '/new', 1, 7, ?)
+	`, now.Format("2006-01-02 15:04:05")); err != nil {
+		t.Fatalf("Failed to seed scan path: %v", err)
+	}
+
+	clk := testutil.NewMockClockAt(now)
+	r := NewRemediatorService(testutil.NewMockEventBus(), &testutil.MockArrClient{}, &testutil.MockPathMapper{}, db)
+	r.clk = clk
+	defer r.Stop()
+
+	if !r.waitForRollout("a", "/media/new/a.mkv", 1) {
+		t.Fatal("The first remediation should go ahead")
+	}
+	done := make(chan bool, 1)
+	go func() { done <- r.waitForRollout("b", "/media/new/b.mkv", 1) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for r.RolloutDeferred() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Removing the limit lets the deferred remediation go ahead right away
+	if _, err := db.Exec(`UPDATE scan_paths SET rollout_daily_limit = 0 WHERE id = 1`); err != nil {
+		t.Fatalf("Failed to update scan path: %v", err)
+	}
+	r.RecheckRollout()
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("Expected the deferred remediation to go ahead")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the deferred remediation")
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// startupReportSetting is the settings key the report of the last startup is
+// stored under.
+const startupReportSetting = "startup_report"
+
+// Startup component states.
+const (
+	ComponentOK       = "ok"
+	ComponentDegraded = "degraded" // running, but with reduced functionality
+	ComponentFailed   = "failed"
+)
+
+// StartupSetting is a configuration value in effect at startup.
+type StartupSetting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// StartupComponent is the result of initializing a component.
+type StartupComponent struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// StartupReport describes a startup: versions, the configuration in effect,
+// the result of initializing each component, and every warning or error
+// logged while starting. It is logged as it is built and stored when startup
+// completes, so initialization warnings can be looked up after the fact.
+// A nil *StartupReport only logs, for components started again later, e.g.
+// on a configuration reload.
+type StartupReport struct {
+	Version     string             `json:"version"`
+	GoVersion   string             `json:"go_version"`
+	Platform    string             `json:"platform"`
+	Tools       map[string]string  `json:"tools,omitempty"` // detection tool versions, "missing" if not found
+	StartedAt   time.Time          `json:"started_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	Config      []StartupSetting   `json:"config"`
+	Components  []StartupComponent `json:"components"`
+	Warnings    []string           `json:"warnings"`
+
+	mu   sync.Mutex
+	logs chan logger.LogEntry
+	done chan struct{}
+}
+
+// NewStartupReport starts the report of a startup. Warnings and errors logged
+// from now until Complete are added to it.
+func NewStartupReport(version string) *StartupReport {
+	r := &StartupReport{
+		Version:    version,
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		StartedAt:  time.Now().UTC(),
+		Config:     []StartupSetting{},
+		Components: []StartupComponent{},
+		Warnings:   []string{},
+		logs:       logger.Subscribe(),
+		done:       make(chan struct{}),
+	}
+	go r.collectWarnings()
+	return r
+}
+
+func (r *StartupReport) collectWarnings() {
+	defer close(r.done)
+	for entry := range r.logs {
+		if entry.Level == logger.Warn || entry.Level == logger.Error {
+			r.mu.Lock()
+			r.Warnings = append(r.Warnings, entry.Message)
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Setting logs and records a configuration value.
+func (r *StartupReport) Setting(name, format string, v ...interface{}) {
+	value := fmt.Sprintf(format, v...)
+	logger.Infof("  %s: %s", name, value)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Config = append(r.Config, StartupSetting{Name: name, Value: value})
+}
+
+// Component logs and records a component that started, with a short
+// description of what it does.
+func (r *StartupReport) Component(name, detail string) {
+	if detail != "" {
+		logger.Infof("✓ %s (%s)", name, detail)
+	} else {
+		logger.Infof("✓ %s", name)
+	}
+	r.record(name, ComponentOK, detail)
+}
+
+// Degraded logs and records a component that started with reduced
+// functionality, e.g. without configured paths or missing tools.
+func (r *StartupReport) Degraded(name, detail string) {
+	logger.Warnf("⚠ %s: %s", name, detail)
+	r.record(name, ComponentDegraded, detail)
+}
+
+// Failed logs and records a component that failed to start.
+func (r *StartupReport) Failed(name string, err error) {
+	logger.Errorf("%s failed: %v", name, err)
+	r.record(name, ComponentFailed, err.Error())
+}
+
+// Warn logs a warning, which like every warning logged during startup is
+// added to the report. Useful for warnings otherwise only printed to stderr,
+// such as the configuration warnings.
+func (r *StartupReport) Warn(format string, v ...interface{}) {
+	logger.Warnf(format, v...)
+}
+
+// SetTools records the versions of the detection tools.
+func (r *StartupReport) SetTools(tools map[string]*integration.ToolStatus) {
+	if r == nil {
+		return
+	}
+	versions := make(map[string]string, len(tools))
+	for name, status := range tools {
+		switch {
+		case !status.Available:
+			versions[name] = "missing"
+		case status.Version != "":
+			versions[name] = status.Version
+		default:
+			versions[name] = "unknown version"
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Tools = versions
+}
+
+func (r *StartupReport) record(name, status, detail string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Components = append(r.Components, StartupComponent{Name: name, Status: status, Detail: detail})
+}
+
+// Complete stops collecting warnings and stores the report, replacing the
+// report of the previous startup.
+func (r *StartupReport) Complete(db *sql.DB) error {
+	logger.Unsubscribe(r.logs)
+	<-r.done
+
+	r.mu.Lock()
+	now := time.Now().UTC()
+	r.CompletedAt = &now
+	value, err := json.Marshal(r)
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode startup report: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, datetime('now'))
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, startupReportSetting, string(value)); err != nil {
+		return fmt.Errorf("failed to store startup report: %w", err)
+	}
+	return nil
+}
+
+// LoadStartupReport returns the stored report of the last startup, or nil if
+// none was stored yet.
+func LoadStartupReport(db *sql.DB) (*StartupReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	var value string
+	err := db.QueryRowContext(ctx, `SELECT value FROM settings WHERE key = ?`, startupReportSetting).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r StartupReport
+	if err := json.Unmarshal([]byte(value), &r); err != nil {
+		return nil, fmt.Errorf("invalid startup report: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestStartupReport_CompleteAndLoad(t *testing.T) {
+	sqlDB, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if report, err := LoadStartupReport(sqlDB); err != nil || report != nil {
+		t.Fatalf("LoadStartupReport() before any startup = %v, %v", report, err)
+	}
+
+	report := NewStartupReport("v1.2.3")
+	report.Setting("Port", "%s", "3090")
+	report.Component("Event Bus", "")
+	report.Degraded("Path Mapper", "no configured paths")
+	report.Failed("MQTT Publisher", errors.New("connection refused"))
+	report.SetTools(map[string]*integration.ToolStatus{
+		"ffprobe":   {Name: "ffprobe", Available: true, Version: "6.1"},
+		"mediainfo": {Name: "mediainfo"},
+	})
+	logger.Warnf("Failed to open database read pool")
+	logger.Infof("Not a warning")
+	report.Warn("Database path is relative")
+	if err := report.Complete(sqlDB); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	// Warnings logged after startup aren't part of the report
+	logger.Warnf("Logged after startup")
+
+	got, err := LoadStartupReport(sqlDB)
+	if err != nil || got == nil {
+		t.Fatalf("LoadStartupReport() = %v, %v", got, err)
+	}
+	if got.Version != "v1.2.3" || got.GoVersion == "" || got.CompletedAt == nil {
+		t.Errorf("Unexpected report header: %+v", got)
+	}
+	if len(got.Config) != 1 || got.Config[0] != (StartupSetting{Name: "Port", Value: "3090"}) {
+		t.Errorf("Config = %+v", got.Config)
+	}
+	wantStatus := []string{ComponentOK, ComponentDegraded, ComponentFailed}
+	if len(got.Components) != len(wantStatus) {
+		t.Fatalf("Components = %+v", got.Components)
+	}
+	for i, status := range wantStatus {
+		if got.Components[i].Status != status {
+			t.Errorf("Components[%d].Status = %q, want %q", i, got.Components[i].Status, status)
+		}
+	}
+	if got.Components[2].Detail != "connection refused" {
+		t.Errorf("Failed component detail = %q", got.Components[2].Detail)
+	}
+	if got.Tools["ffprobe"] != "6.1" || got.Tools["mediainfo"] != "missing" {
+		t.Errorf("Tools = %v", got.Tools)
+	}
+	want := []string{"⚠ Path Mapper: no configured paths", "MQTT Publisher failed: connection refused", "Failed to open database read pool", "Database path is relative"}
+	if len(got.Warnings) != len(want) {
+		t.Fatalf("Warnings = %q, want %q", got.Warnings, want)
+	}
+	for i := range want {
+		if got.Warnings[i] != want[i] {
+			t.Errorf("Warnings[%d] = %q, want %q", i, got.Warnings[i], want[i])
+		}
+	}
+}
+
+func TestStartupReport_NilOnlyLogs(t *testing.T) {
+	var report *StartupReport
+	report.Setting("Port", "%s", "3090")
+	report.Component("MQTT Publisher", "")
+	report.Failed("MQTT Publisher", errors.New("connection refused"))
+	report.Warn("only logged")
+	report.SetTools(nil)
+}
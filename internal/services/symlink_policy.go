@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Symlink policies, set per scan path.
+const (
+	SymlinkSkip             = "skip"               // Symbolic links are ignored
+	SymlinkFollow           = "follow"             // Linked files and directories are scanned wherever they point
+	SymlinkVerifyTargetOnce = "verify_target_once" // Linked files inside the scan path are checked once, through their target
+)
+
+// ErrInvalidSymlinkPolicy is returned for an unknown symlink policy.
+var ErrInvalidSymlinkPolicy = errors.New("symlink_policy must be one of: skip, follow, verify_target_once")
+
+// ValidSymlinkPolicy reports whether policy is a known symlink policy.
+func ValidSymlinkPolicy(policy string) bool {
+	return policy == SymlinkSkip || policy == SymlinkFollow || policy == SymlinkVerifyTargetOnce
+}
+
+// mediaWalker enumerates the media files of a local scan path, handling
+// symbolic links by the path's policy. Unless links are skipped, every
+// directory and file is identified by its device and inode, so one reached
+// twice, e.g. through a link loop or a link next to its target, is walked
+// and queued once.
+type mediaWalker struct {
+	root     string // the scan path
+	realRoot string // the scan path with links resolved
+	policy   string
+	filter   mediaFilter
+	queue    *fileQueue
+	onError  func(path string, err error) error
+	visited  map[fileID]bool
+	stats    walkStats
+}
+
+// newMediaWalker prepares a walk of root.
+func newMediaWalker(root, policy string, filter mediaFilter, queue *fileQueue, onError func(string, error) error) *mediaWalker {
+	w := &mediaWalker{
+		root:     filepath.Clean(root),
+		policy:   policy,
+		filter:   filter,
+		queue:    queue,
+		onError:  onError,
+		realRoot: filepath.Clean(root),
+	}
+	if policy == SymlinkFollow || policy == SymlinkVerifyTargetOnce {
+		w.visited = make(map[fileID]bool)
+		if real, err := filepath.EvalSymlinks(root); err == nil {
+			w.realRoot = real
+		}
+	}
+	return w
+}
+
+// walk walks dir, which is the scan path or a linked directory below it.
+func (w *mediaWalker) walk(dir string) error {
+	return filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return w.onError(filePath, err)
+		}
+		if d.IsDir() {
+			// A directory already walked is a link loop or a second link to it
+			if w.visited != nil && !w.visitEntry(filePath, d) {
+				w.stats.duplicateCount++
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		isMedia, isSkipped, isSymlink := classifyEntry(filePath, d, w.filter)
+		switch {
+		case isSymlink:
+			return w.symlink(filePath)
+		case isSkipped:
+			w.stats.skippedCount++
+		case isMedia:
+			if w.visited != nil && !w.visitEntry(filePath, d) {
+				w.stats.duplicateCount++
+				return nil
+			}
+			return w.queue.Push(filePath)
+		}
+		return nil
+	})
+}
+
+// symlink handles a symbolic link found during the walk.
+func (w *mediaWalker) symlink(linkPath string) error {
+	switch w.policy {
+	case SymlinkFollow:
+		info, err := os.Stat(linkPath)
+		if err != nil {
+			logger.Debugf("Skipping broken symlink %s: %v", linkPath, err)
+			w.stats.symlinkCount++
+			return nil
+		}
+		if info.IsDir() {
+			// The trailing separator makes WalkDir resolve the link
+			return w.walk(linkPath + string(filepath.Separator))
+		}
+		return w.pushLinked(linkPath, linkPath, info)
+
+	case SymlinkVerifyTargetOnce:
+		target, err := filepath.EvalSymlinks(linkPath)
+		if err != nil {
+			logger.Debugf("Skipping broken symlink %s: %v", linkPath, err)
+			w.stats.symlinkCount++
+			return nil
+		}
+		rel, err := filepath.Rel(w.realRoot, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			logger.Debugf("Skipping symlink %s: its target %s is outside the scan path", linkPath, target)
+			w.stats.symlinkCount++
+			return nil
+		}
+		info, err := os.Stat(target)
+		if err != nil || info.IsDir() {
+			// Linked directories are inside the scan path and walked anyway
+			w.stats.symlinkCount++
+			return nil
+		}
+		// Queued below the scan path as configured, so it maps to the *arr
+		return w.pushLinked(linkPath, filepath.Join(w.root, rel), info)
+
+	default:
+		w.stats.symlinkCount++
+		return nil
+	}
+}
+
+// pushLinked queues the file a link leads to as queuedPath, unless it isn't
+// media or was queued already.
+func (w *mediaWalker) pushLinked(linkPath, queuedPath string, info os.FileInfo) error {
+	if isHiddenOrTempFile(linkPath) || !w.filter.includes(queuedPath) {
+		w.stats.skippedCount++
+		return nil
+	}
+	if !w.visit(queuedPath, info) {
+		w.stats.duplicateCount++
+		return nil
+	}
+	w.stats.followedCount++
+	return w.queue.Push(queuedPath)
+}
+
+// visitEntry marks a walked entry as visited; false if it was already.
+func (w *mediaWalker) visitEntry(path string, d fs.DirEntry) bool {
+	info, err := d.Info()
+	if err != nil {
+		return true
+	}
+	return w.visit(path, info)
+}
+
+// visit marks a file or directory as visited; false if it was already.
+func (w *mediaWalker) visit(path string, info os.FileInfo) bool {
+	id := fileIDOf(path, info)
+	if w.visited[id] {
+		return false
+	}
+	w.visited[id] = true
+	return true
+}
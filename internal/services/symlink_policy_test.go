@@ -0,0 +1,99 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// symlinkTree creates a scan path with a media file, a link to it, a link
+// loop, links to a file and a directory outside the scan path, and a broken
+// link.
+func symlinkTree(t *testing.T) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "media")
+	other := filepath.Join(filepath.Dir(root), "other")
+	for _, dir := range []string{filepath.Join(root, "real"), other} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{filepath.Join(root, "real", "movie.mkv"), filepath.Join(other, "ext.mkv")} {
+		if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		filepath.Join(root, "dup.mkv"):      filepath.Join(root, "real", "movie.mkv"),
+		filepath.Join(root, "real", "loop"): root,
+		filepath.Join(root, "extfile.mkv"):  filepath.Join(other, "ext.mkv"),
+		filepath.Join(root, "outside"):      other,
+		filepath.Join(root, "broken.mkv"):   filepath.Join(root, "missing.mkv"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	return root
+}
+
+func TestEnumerateMediaFiles_SymlinkPolicy(t *testing.T) {
+	root := symlinkTree(t)
+	s := NewScannerService(nil, nil, nil, nil)
+
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{SymlinkSkip, []string{"real/movie.mkv"}},
+		// Each file once, by the path it was reached first; the loop ends
+		{SymlinkFollow, []string{"dup.mkv", "extfile.mkv"}},
+		// Only targets inside the scan path, queued by their real path
+		{SymlinkVerifyTargetOnce, []string{"real/movie.mkv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			files := newFileQueue(nil, 0, 0)
+			if err := s.enumerateMediaFiles(root, mediaFilter{}, tt.policy, files); err != nil {
+				t.Fatalf("enumerateMediaFiles() error = %v", err)
+			}
+			var got []string
+			for _, f := range files.Files() {
+				rel, _ := filepath.Rel(root, f)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumerateMediaFiles_FollowLinkedDirectory(t *testing.T) {
+	root := symlinkTree(t)
+	// Without the link to it, the outside file is reached through the directory
+	if err := os.Remove(filepath.Join(root, "extfile.mkv")); err != nil {
+		t.Fatal(err)
+	}
+	files := newFileQueue(nil, 0, 0)
+	if err := NewScannerService(nil, nil, nil, nil).enumerateMediaFiles(root, mediaFilter{}, SymlinkFollow, files); err != nil {
+		t.Fatalf("enumerateMediaFiles() error = %v", err)
+	}
+	want := []string{filepath.Join(root, "dup.mkv"), filepath.Join(root, "outside", "ext.mkv")}
+	if got := files.Files(); !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestValidSymlinkPolicy(t *testing.T) {
+	for _, policy := range []string{SymlinkSkip, SymlinkFollow, SymlinkVerifyTargetOnce} {
+		if !ValidSymlinkPolicy(policy) {
+			t.Errorf("ValidSymlinkPolicy(%q) = false", policy)
+		}
+	}
+	if ValidSymlinkPolicy("") || ValidSymlinkPolicy("resolve") {
+		t.Error("ValidSymlinkPolicy accepted an unknown policy")
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/clock"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/eventbus"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// systemPauseSetting is the settings key the global pause is stored under, so
+// a restart during maintenance doesn't resume automation.
+const systemPauseSetting = "system_pause"
+
+// ErrInvalidPauseDuration is returned for a negative auto-resume duration.
+var ErrInvalidPauseDuration = errors.New("duration must not be negative")
+
+// SystemPauseStatus describes the global pause of Healarr's automation.
+type SystemPauseStatus struct {
+	Paused        bool       `json:"paused"`
+	Reason        string     `json:"reason,omitempty"`
+	DetectionOnly bool       `json:"detection_only"`       // scheduled scans keep running
+	PausedAt      *time.Time `json:"paused_at,omitempty"`  // when the pause started
+	ResumesAt     *time.Time `json:"resumes_at,omitempty"` // nil: paused until resumed by hand
+}
+
+// SystemPause is the global kill switch for maintenance on the *arr stack or
+// the storage array. While paused, remediations wait in the queue,
+// verification stops polling the *arr instances and scheduled scans are
+// skipped, unless the pause is detection-only. Manual actions still work.
+// A nil *SystemPause is never paused.
+type SystemPause struct {
+	db       *sql.DB
+	eventBus *eventbus.EventBus
+	clk      clock.Clock
+
+	mu        sync.Mutex
+	status    SystemPauseStatus
+	resumed   chan struct{} // closed while not paused
+	timer     clock.Timer   // pending auto-resume
+	listeners []func(paused bool)
+}
+
+// NewSystemPause creates the global pause and restores a pause stored before
+// a restart. A pause whose timer ran out while Healarr was down is lifted.
+func NewSystemPause(db *sql.DB, eb *eventbus.EventBus) *SystemPause {
+	return newSystemPause(db, eb, clock.NewRealClock())
+}
+
+func newSystemPause(db *sql.DB, eb *eventbus.EventBus, clk clock.Clock) *SystemPause {
+	p := &SystemPause{
+		db:       db,
+		eventBus: eb,
+		clk:      clk,
+		resumed:  make(chan struct{}),
+	}
+	close(p.resumed)
+	p.restore()
+	return p
+}
+
+// restore loads the stored pause.
+func (p *SystemPause) restore() {
+	if p.db == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+
+	var value string
+	err := p.db.QueryRowContext(ctx, `SELECT value FROM settings WHERE key = ?`, systemPauseSetting).Scan(&value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Errorf("Failed to load the automation pause: %v", err)
+		}
+		return
+	}
+	var status SystemPauseStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		logger.Errorf("Ignoring unreadable automation pause: %v", err)
+		return
+	}
+	if !status.Paused {
+		return
+	}
+
+	p.mu.Lock()
+	p.status = status
+	p.resumed = make(chan struct{})
+	p.mu.Unlock()
+	logger.Warnf("Automation is paused (%s) - remediation, verification polling and scheduled scans are on hold", describePause(status))
+
+	if status.ResumesAt != nil {
+		p.armTimer(status.ResumesAt.Sub(p.clk.Now()))
+	}
+}
+
+// OnChange registers fn to be called whenever automation is paused or
+// resumed. Must be called before the pause changes, e.g. at startup.
+func (p *SystemPause) OnChange(fn func(paused bool)) {
+	p.mu.Lock()
+	p.listeners = append(p.listeners, fn)
+	paused := p.status.Paused
+	p.mu.Unlock()
+	if paused {
+		fn(true)
+	}
+}
+
+// Status returns the current pause.
+func (p *SystemPause) Status() SystemPauseStatus {
+	if p == nil {
+		return SystemPauseStatus{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// Paused reports whether automation is paused.
+func (p *SystemPause) Paused() bool {
+	return p.Status().Paused
+}
+
+// ScansPaused reports whether scheduled scans are paused, i.e. automation is
+// paused and the pause isn't detection-only.
+func (p *SystemPause) ScansPaused() bool {
+	status := p.Status()
+	return status.Paused && !status.DetectionOnly
+}
+
+// Resumed returns a channel that is closed once automation isn't paused.
+func (p *SystemPause) Resumed() <-chan struct{} {
+	if p == nil {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resumed
+}
+
+// Pause puts automation on hold. With detectionOnly, scheduled scans keep
+// running. A positive duration resumes automation by itself after that long;
+// zero pauses until Resume. Pausing again replaces the reason and timer.
+func (p *SystemPause) Pause(reason string, detectionOnly bool, duration time.Duration) (SystemPauseStatus, error) {
+	if duration < 0 {
+		return SystemPauseStatus{}, ErrInvalidPauseDuration
+	}
+
+	p.mu.Lock()
+	now := p.clk.Now().UTC()
+	status := SystemPauseStatus{Paused: true, Reason: reason, DetectionOnly: detectionOnly, PausedAt: &now}
+	if p.status.Paused {
+		status.PausedAt = p.status.PausedAt
+	}
+	if duration > 0 {
+		resumesAt := now.Add(duration)
+		status.ResumesAt = &resumesAt
+	}
+	if err := p.save(status); err != nil {
+		p.mu.Unlock()
+		return SystemPauseStatus{}, err
+	}
+	wasPaused := p.status.Paused
+	p.status = status
+	if !wasPaused {
+		p.resumed = make(chan struct{})
+	}
+	listeners := p.listeners
+	p.mu.Unlock()
+
+	p.stopTimer()
+	if duration > 0 {
+		p.armTimer(duration)
+	}
+	if !wasPaused {
+		for _, fn := range listeners {
+			fn(true)
+		}
+	}
+
+	logger.Warnf("Automation paused (%s)", describePause(status))
+	eventData := map[string]interface{}{
+		"reason":         reason,
+		"detection_only": detectionOnly,
+	}
+	if status.ResumesAt != nil {
+		eventData["resumes_at"] = status.ResumesAt.Format(time.RFC3339)
+	}
+	p.publish(domain.AutomationPaused, eventData)
+	return status, nil
+}
+
+// Resume lets automation run again: queued remediations start, verifications
+// continue polling and scheduled scans run on their next schedule.
+func (p *SystemPause) Resume() (SystemPauseStatus, error) {
+	return p.resume(false)
+}
+
+func (p *SystemPause) resume(auto bool) (SystemPauseStatus, error) {
+	p.mu.Lock()
+	if !p.status.Paused {
+		p.mu.Unlock()
+		return SystemPauseStatus{}, nil
+	}
+	if err := p.save(SystemPauseStatus{}); err != nil {
+		p.mu.Unlock()
+		return p.Status(), err
+	}
+	reason := p.status.Reason
+	p.status = SystemPauseStatus{}
+	close(p.resumed)
+	listeners := p.listeners
+	p.mu.Unlock()
+
+	p.stopTimer()
+	for _, fn := range listeners {
+		fn(false)
+	}
+
+	if auto {
+		logger.Infof("Automation resumed, the pause timer expired")
+	} else {
+		logger.Infof("Automation resumed")
+	}
+	p.publish(domain.AutomationResumed, map[string]interface{}{
+		"reason":       reason,
+		"auto_resumed": auto,
+	})
+	return SystemPauseStatus{}, nil
+}
+
+// Stop cancels a pending auto-resume. The stored pause still resumes on time
+// after a restart.
+func (p *SystemPause) Stop() {
+	if p != nil {
+		p.stopTimer()
+	}
+}
+
+func (p *SystemPause) armTimer(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timer = p.clk.AfterFunc(max(d, 0), func() {
+		if _, err := p.resume(true); err != nil {
+			logger.Errorf("Failed to resume automation: %v", err)
+		}
+	})
+}
+
+func (p *SystemPause) stopTimer() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+// save stores the pause. Must be called with p.mu held.
+func (p *SystemPause) save(status SystemPauseStatus) error {
+	if p.db == nil {
+		return nil
+	}
+	value, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode automation pause: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, datetime('now'))
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, systemPauseSetting, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to store automation pause: %w", err)
+	}
+	return nil
+}
+
+func (p *SystemPause) publish(eventType domain.EventType, data map[string]interface{}) {
+	if p.eventBus == nil {
+		return
+	}
+	if err := p.eventBus.Publish(domain.Event{
+		AggregateType: "system",
+		AggregateID:   "automation",
+		EventType:     eventType,
+		EventData:     data,
+	}); err != nil {
+		logger.Errorf("Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// describePause summarizes a pause for the log.
+func describePause(status SystemPauseStatus) string {
+	desc := "no reason given"
+	if status.Reason != "" {
+		desc = status.Reason
+	}
+	if status.DetectionOnly {
+		desc += ", scans continue"
+	}
+	if status.ResumesAt != nil {
+		desc += ", resumes at " + status.ResumesAt.Local().Format(time.RFC1123)
+	}
+	return desc
+}
+
+// SetSystemPause holds remediations in the queue while automation is paused.
+func (r *RemediatorService) SetSystemPause(p *SystemPause) {
+	p.OnChange(r.throttle.setSystemPaused)
+}
+
+// SetSystemPause makes verifications stop polling the *arr instances while
+// automation is paused.
+func (v *VerifierService) SetSystemPause(p *SystemPause) {
+	v.pause = p
+}
+
+// waitWhilePaused blocks while automation is paused. It returns how long it
+// waited, so the verification timeout can leave the pause out, and whether
+// the verification was cancelled or Healarr is shutting down.
+func (v *VerifierService) waitWhilePaused(ctx context.Context, corruptionID string) (time.Duration, bool) {
+	if !v.pause.Paused() {
+		return 0, false
+	}
+	logger.Infof("Verifier: automation paused, holding verification of %s", corruptionID)
+	start := time.Now()
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	select {
+	case <-v.pause.Resumed():
+		return time.Since(start), false
+	case <-done:
+		return 0, true
+	case <-v.shutdownCh:
+		return 0, true
+	}
+}
+
+// SetSystemPause skips scheduled scans while automation is paused, unless the
+// pause is detection-only.
+func (s *SchedulerService) SetSystemPause(p *SystemPause) {
+	s.pause = p
+}
+
+// SetSystemPause skips the stuck remediation check and the *arr state sync
+// while automation is paused: held remediations aren't stuck, and *arr
+// instances under maintenance may report missing files.
+func (h *HealthMonitorService) SetSystemPause(p *SystemPause) {
+	h.pause = p
+}
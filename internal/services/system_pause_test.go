@@ -0,0 +1,148 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func newTestSystemPause(t *testing.T) (*sql.DB, *testutil.MockClock, *SystemPause) {
+	t.Helper()
+	sqlDB, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	clk := testutil.NewMockClock()
+	return sqlDB, clk, newSystemPause(sqlDB, nil, clk)
+}
+
+func TestSystemPause_PauseAndResume(t *testing.T) {
+	_, _, p := newTestSystemPause(t)
+
+	var changes []bool
+	p.OnChange(func(paused bool) { changes = append(changes, paused) })
+
+	status, err := p.Pause("NAS maintenance", false, 0)
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if !status.Paused || status.Reason != "NAS maintenance" || status.PausedAt == nil || status.ResumesAt != nil {
+		t.Errorf("Unexpected pause status: %+v", status)
+	}
+	if !p.Paused() || !p.ScansPaused() {
+		t.Error("Expected automation and scans to be paused")
+	}
+	select {
+	case <-p.Resumed():
+		t.Error("Expected Resumed() to block while paused")
+	default:
+	}
+
+	if _, err := p.Resume(); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if p.Paused() {
+		t.Error("Expected automation to be resumed")
+	}
+	select {
+	case <-p.Resumed():
+	default:
+		t.Error("Expected Resumed() to be closed after Resume")
+	}
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("Expected listeners to see pause then resume, got %v", changes)
+	}
+
+	if _, err := p.Pause("", false, -time.Minute); err != ErrInvalidPauseDuration {
+		t.Errorf("Expected ErrInvalidPauseDuration, got %v", err)
+	}
+}
+
+func TestSystemPause_DetectionOnly(t *testing.T) {
+	_, _, p := newTestSystemPause(t)
+
+	if _, err := p.Pause("", true, 0); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if !p.Paused() || p.ScansPaused() {
+		t.Error("Expected a detection-only pause to keep scheduled scans running")
+	}
+}
+
+func TestSystemPause_AutoResume(t *testing.T) {
+	_, clk, p := newTestSystemPause(t)
+
+	status, err := p.Pause("arr upgrade", false, time.Hour)
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if status.ResumesAt == nil || !status.ResumesAt.Equal(clk.Now().UTC().Add(time.Hour)) {
+		t.Errorf("Expected resume in an hour, got %v", status.ResumesAt)
+	}
+
+	clk.Advance(59 * time.Minute)
+	if !p.Paused() {
+		t.Fatal("Expected automation to stay paused before the timer expires")
+	}
+	clk.Advance(time.Minute)
+	if p.Paused() {
+		t.Error("Expected automation to resume when the timer expires")
+	}
+}
+
+func TestSystemPause_RestoredAfterRestart(t *testing.T) {
+	sqlDB, clk, p := newTestSystemPause(t)
+
+	if _, err := p.Pause("array rebuild", true, 2*time.Hour); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	p.Stop()
+
+	restarted := newSystemPause(sqlDB, nil, clk)
+	status := restarted.Status()
+	if !status.Paused || status.Reason != "array rebuild" || !status.DetectionOnly || status.ResumesAt == nil {
+		t.Fatalf("Expected the pause to be restored, got %+v", status)
+	}
+
+	// The timer still resumes automation on time
+	clk.Advance(2 * time.Hour)
+	if restarted.Paused() {
+		t.Error("Expected the restored pause to resume on time")
+	}
+
+	// The resume is stored as well
+	if newSystemPause(sqlDB, nil, clk).Paused() {
+		t.Error("Expected no pause after a resume was stored")
+	}
+}
+
+func TestSystemPause_NilIsNeverPaused(t *testing.T) {
+	var p *SystemPause
+	if p.Paused() || p.ScansPaused() {
+		t.Error("Expected a nil pause to never be paused")
+	}
+	select {
+	case <-p.Resumed():
+	default:
+		t.Error("Expected Resumed() of a nil pause to be closed")
+	}
+}
+
+func TestRemediationThrottle_SystemPause(t *testing.T) {
+	th := newRemediationThrottle(RemediationThrottleConfig{MaxConcurrent: 2}, testutil.NewMockClock())
+	shutdown := make(chan struct{})
+
+	th.setSystemPaused(true)
+	queued := acquireAsync(th, 1, "a", shutdown)
+	waitQueued(t, th, 1)
+	expectWaiting(t, queued)
+	if !th.status().SystemPaused {
+		t.Error("Expected the queue status to report the pause")
+	}
+
+	th.setSystemPaused(false)
+	expectGranted(t, queued)
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// Settings keys of the usage statistics.
+const (
+	telemetryEnabledSetting   = "telemetry_enabled"
+	telemetryInstallIDSetting = "telemetry_install_id"
+	telemetryLastSentSetting  = "telemetry_last_sent"
+)
+
+// telemetrySchemaVersion is bumped whenever fields of TelemetryReport change.
+const telemetrySchemaVersion = 1
+
+// telemetryFirstDelay is how long after startup the first report is sent, so
+// restarts in a crash loop don't send one each.
+const telemetryFirstDelay = time.Hour
+
+// ErrTelemetryDisabled is returned when usage statistics are turned on while
+// the hard off switch or offline mode is set.
+var ErrTelemetryDisabled = errors.New("usage statistics are disabled by HEALARR_TELEMETRY_DISABLED or offline mode")
+
+// TelemetryConfig configures the usage statistics.
+type TelemetryConfig struct {
+	// URL is where reports are posted. Without it nothing is sent.
+	URL string
+	// Interval is the time between reports.
+	Interval time.Duration
+	// Disabled is the hard off switch: nothing is sent and the statistics
+	// can't be turned on.
+	Disabled bool
+	// Version is the Healarr version reported.
+	Version string
+}
+
+// TelemetryStatus describes whether usage statistics are sent.
+type TelemetryStatus struct {
+	Enabled      bool       `json:"enabled"`       // opted in
+	HardDisabled bool       `json:"hard_disabled"` // turned off by the environment
+	Endpoint     string     `json:"endpoint,omitempty"`
+	IntervalDays int        `json:"interval_days"`
+	LastSentAt   *time.Time `json:"last_sent_at,omitempty"`
+}
+
+// TelemetryReport is everything a usage statistics report contains. Counts
+// are bucketed and rates rounded, and no paths, names, URLs or keys are
+// included. The install ID is random and replaced when the statistics are
+// turned off, so reports can't be linked across opt-ins.
+type TelemetryReport struct {
+	SchemaVersion int    `json:"schema_version"`
+	InstallID     string `json:"install_id"`
+	Version       string `json:"version"`
+	OS            string `json:"os"`
+	Arch          string `json:"arch"`
+
+	ScanPaths        string            `json:"scan_paths"`        // bucket, e.g. "2-5"
+	LibraryFiles     string            `json:"library_files"`     // bucket of files found by the last scans
+	RemotePaths      bool              `json:"remote_paths"`      // any SFTP/WebDAV scan paths
+	DetectionMethods map[string]int    `json:"detection_methods"` // scan paths per method
+	ArrInstances     map[string]int    `json:"arr_instances"`     // instances per type
+	Tools            map[string]string `json:"tools"`             // version of each available tool
+
+	Corruptions string `json:"corruptions"` // bucket of corruptions ever detected
+	// RemediationSuccessRate is the share of finished remediations that
+	// succeeded, rounded to 5%; nil before any finished
+	RemediationSuccessRate *int `json:"remediation_success_rate"`
+}
+
+// TelemetryService sends anonymous usage statistics, only after the user
+// opted in. Preview shows exactly what would be sent, whether opted in or not.
+type TelemetryService struct {
+	db     *sql.DB
+	cfg    TelemetryConfig
+	tools  *integration.ToolChecker
+	client *http.Client
+
+	mu       sync.Mutex
+	enabled  bool
+	lastSent *time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTelemetryService creates the usage statistics service and loads whether
+// the user opted in.
+func NewTelemetryService(database *sql.DB, cfg TelemetryConfig, tools *integration.ToolChecker) *TelemetryService {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 7 * 24 * time.Hour
+	}
+	t := &TelemetryService{
+		db:     database,
+		cfg:    cfg,
+		tools:  tools,
+		client: &http.Client{Timeout: 30 * time.Second},
+		stopCh: make(chan struct{}),
+	}
+	t.enabled = !cfg.Disabled && t.loadSetting(telemetryEnabledSetting) == "true"
+	if ts := t.loadSetting(telemetryLastSentSetting); ts != "" {
+		if sent, err := time.Parse(time.RFC3339, ts); err == nil {
+			t.lastSent = &sent
+		}
+	}
+	return t
+}
+
+// Status returns whether usage statistics are sent.
+func (t *TelemetryService) Status() TelemetryStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TelemetryStatus{
+		Enabled:      t.enabled,
+		HardDisabled: t.cfg.Disabled,
+		Endpoint:     t.cfg.URL,
+		IntervalDays: int(t.cfg.Interval / (24 * time.Hour)),
+		LastSentAt:   t.lastSent,
+	}
+}
+
+// SetEnabled opts in to or out of usage statistics. Opting out replaces the
+// install ID.
+func (t *TelemetryService) SetEnabled(enabled bool) (TelemetryStatus, error) {
+	if enabled && t.cfg.Disabled {
+		return t.Status(), ErrTelemetryDisabled
+	}
+	if err := t.saveSetting(telemetryEnabledSetting, strconv.FormatBool(enabled)); err != nil {
+		return t.Status(), err
+	}
+	if !enabled {
+		if err := t.deleteSetting(telemetryInstallIDSetting); err != nil {
+			return t.Status(), err
+		}
+	}
+	t.mu.Lock()
+	t.enabled = enabled
+	t.mu.Unlock()
+	if enabled {
+		logger.Infof("Anonymous usage statistics turned on")
+	} else {
+		logger.Infof("Anonymous usage statistics turned off")
+	}
+	return t.Status(), nil
+}
+
+// Start sends a report every interval while opted in, until Stop.
+func (t *TelemetryService) Start() {
+	if t.cfg.Disabled {
+		logger.Infof("Anonymous usage statistics are disabled")
+		return
+	}
+	go func() {
+		delay := telemetryFirstDelay
+		for {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-t.stopCh:
+				timer.Stop()
+				return
+			}
+			delay = t.cfg.Interval
+			if !t.due() {
+				continue
+			}
+			if err := t.Send(context.Background()); err != nil {
+				logger.Warnf("Failed to send anonymous usage statistics: %v", err)
+			}
+		}
+	}()
+}
+
+// Stop ends the reporting loop.
+func (t *TelemetryService) Stop() {
+	t.stopOnce.Do(func() { close(t.stopCh) })
+}
+
+// due reports whether a report should be sent now.
+func (t *TelemetryService) due() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.enabled || t.cfg.Disabled || t.cfg.URL == "" {
+		return false
+	}
+	return t.lastSent == nil || time.Now().Sub(*t.lastSent) >= t.cfg.Interval-time.Hour
+}
+
+// Send posts a report to the configured endpoint. It does nothing unless the
+// user opted in.
+func (t *TelemetryService) Send(ctx context.Context) error {
+	t.mu.Lock()
+	enabled := t.enabled && !t.cfg.Disabled
+	t.mu.Unlock()
+	if !enabled || t.cfg.URL == "" {
+		return nil
+	}
+
+	report, err := t.Preview(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Healarr/"+t.cfg.Version)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+
+	now := time.Now().UTC()
+	if err := t.saveSetting(telemetryLastSentSetting, now.Format(time.RFC3339)); err != nil {
+		logger.Warnf("Failed to store when usage statistics were sent: %v", err)
+	}
+	t.mu.Lock()
+	t.lastSent = &now
+	t.mu.Unlock()
+	logger.Infof("Sent anonymous usage statistics")
+	return nil
+}
+
+// Preview compiles the report that would be sent now.
+func (t *TelemetryService) Preview(ctx context.Context) (*TelemetryReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbQueryTimeout)
+	defer cancel()
+
+	report := &TelemetryReport{
+		SchemaVersion:    telemetrySchemaVersion,
+		InstallID:        t.installID(),
+		Version:          t.cfg.Version,
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		DetectionMethods: make(map[string]int),
+		ArrInstances:     make(map[string]int),
+		Tools:            make(map[string]string),
+	}
+
+	var paths, remote int
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT detection_method, remote_url != '' FROM scan_paths WHERE deleted_at IS NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scan paths: %w", err)
+	}
+	for rows.Next() {
+		var method string
+		var isRemote bool
+		if err := rows.Scan(&method, &isRemote); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to count scan paths: %w", err)
+		}
+		paths++
+		if isRemote {
+			remote++
+		}
+		report.DetectionMethods[method]++
+	}
+	rows.Close()
+	report.ScanPaths = sizeBucket(paths)
+	report.RemotePaths = remote > 0
+
+	rows, err = t.db.QueryContext(ctx, `SELECT type, COUNT(*) FROM arr_instances WHERE deleted_at IS NULL GROUP BY type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count *arr instances: %w", err)
+	}
+	for rows.Next() {
+		var arrType string
+		var n int
+		if err := rows.Scan(&arrType, &n); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to count *arr instances: %w", err)
+		}
+		report.ArrInstances[arrType] = n
+	}
+	rows.Close()
+
+	// Files found by the last finished scan of each path
+	var files int
+	if err := t.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(s.total_files), 0) FROM scans s
+		JOIN (SELECT MAX(id) AS id FROM scans WHERE status = 'completed' GROUP BY path) last ON last.id = s.id
+	`).Scan(&files); err != nil {
+		return nil, fmt.Errorf("failed to count library files: %w", err)
+	}
+	report.LibraryFiles = sizeBucket(files)
+
+	var corruptions, resolved, failed int
+	if err := t.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(CASE WHEN current_state = 'VerificationSuccess' THEN 1 END),
+			COUNT(CASE WHEN current_state IN ('MaxRetriesReached', 'SearchExhausted') THEN 1 END)
+		FROM corruption_summary WHERE current_state != 'CorruptionIgnored'
+	`).Scan(&corruptions, &resolved, &failed); err != nil {
+		return nil, fmt.Errorf("failed to count corruptions: %w", err)
+	}
+	report.Corruptions = sizeBucket(corruptions)
+	if finished := resolved + failed; finished > 0 {
+		rate := (resolved*100/finished + 2) / 5 * 5
+		report.RemediationSuccessRate = &rate
+	}
+
+	if t.tools != nil {
+		for name, status := range t.tools.GetToolStatus() {
+			if status.Available {
+				report.Tools[name] = status.Version
+			}
+		}
+	}
+	return report, nil
+}
+
+// installID returns the random ID of this install, creating it on first use.
+// Previews of an install that isn't opted in don't store one.
+func (t *TelemetryService) installID() string {
+	if id := t.loadSetting(telemetryInstallIDSetting); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	t.mu.Lock()
+	enabled := t.enabled
+	t.mu.Unlock()
+	if enabled {
+		if err := t.saveSetting(telemetryInstallIDSetting, id); err != nil {
+			logger.Warnf("Failed to store the usage statistics install ID: %v", err)
+		}
+	}
+	return id
+}
+
+// sizeBucket hides exact counts behind ranges.
+func sizeBucket(n int) string {
+	switch {
+	case n == 0:
+		return "0"
+	case n == 1:
+		return "1"
+	case n <= 5:
+		return "2-5"
+	case n <= 20:
+		return "6-20"
+	case n <= 100:
+		return "21-100"
+	case n <= 1000:
+		return "101-1k"
+	case n <= 10000:
+		return "1k-10k"
+	case n <= 100000:
+		return "10k-100k"
+	case n <= 1000000:
+		return "100k-1M"
+	default:
+		return ">1M"
+	}
+}
+
+func (t *TelemetryService) loadSetting(key string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	var value string
+	err := t.db.QueryRowContext(ctx, `SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Warnf("Failed to load setting %s: %v", key, err)
+	}
+	return value
+}
+
+func (t *TelemetryService) saveSetting(key, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	if _, err := t.db.ExecContext(ctx, `
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, datetime('now'))
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, key, value); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+func (t *TelemetryService) deleteSetting(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	if _, err := t.db.ExecContext(ctx, `DELETE FROM settings WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestTelemetry_Preview(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO arr_instances (id, name, type, url, api_key) VALUES
+			(1, 'Sonarr', 'sonarr', 'http://sonarr:8989', 'secret'),
+			(2, 'Radarr', 'radarr', 'http://radarr:7878', 'secret'),
+			(3, 'Radarr 4K', 'radarr', 'http://radarr4k:7878', 'secret');
+		INSERT INTO scan_paths (id, local_path, arr_path, detection_method, remote_url) VALUES
+			(1, '/media/tv', '/tv', 'ffprobe', ''),
+			(2, '/media/movies', '/movies', 'mediainfo', 'sftp://nas/movies');
+		INSERT INTO scans (path, path_id, status, total_files) VALUES
+			('/media/tv', 1, 'completed', 100),
+			('/media/tv', 1, 'completed', 1200),
+			('/media/movies', 2, 'completed', 300),
+			('/media/movies', 2, 'running', 9000);
+		INSERT INTO corruption_summary (corruption_id, file_path, current_state, detected_at, last_updated_at) VALUES
+			('a', '/media/tv/a.mkv', 'VerificationSuccess', datetime('now'), datetime('now')),
+			('b', '/media/tv/b.mkv', 'VerificationSuccess', datetime('now'), datetime('now')),
+			('c', '/media/tv/c.mkv', 'MaxRetriesReached', datetime('now'), datetime('now')),
+			('d', '/media/tv/d.mkv', 'SearchStarted', datetime('now'), datetime('now')),
+			('e', '/media/tv/e.mkv', 'CorruptionIgnored', datetime('now'), datetime('now'));
+	`); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+
+	tel := NewTelemetryService(db, TelemetryConfig{Version: "v1.2.3"}, nil)
+	report, err := tel.Preview(context.Background())
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if report.Version != "v1.2.3" || report.InstallID == "" {
+		t.Errorf("Unexpected report header: %+v", report)
+	}
+	if report.ScanPaths != "2-5" || !report.RemotePaths {
+		t.Errorf("ScanPaths = %q, RemotePaths = %v", report.ScanPaths, report.RemotePaths)
+	}
+	if report.DetectionMethods["ffprobe"] != 1 || report.DetectionMethods["mediainfo"] != 1 {
+		t.Errorf("DetectionMethods = %v", report.DetectionMethods)
+	}
+	if report.ArrInstances["sonarr"] != 1 || report.ArrInstances["radarr"] != 2 {
+		t.Errorf("ArrInstances = %v", report.ArrInstances)
+	}
+	if report.LibraryFiles != "1k-10k" {
+		t.Errorf("LibraryFiles = %q, want the last completed scans (1500) bucketed", report.LibraryFiles)
+	}
+	if report.Corruptions != "2-5" {
+		t.Errorf("Corruptions = %q", report.Corruptions)
+	}
+	if report.RemediationSuccessRate == nil || *report.RemediationSuccessRate != 65 {
+		t.Errorf("RemediationSuccessRate = %v, want 2 of 3 rounded to 65", report.RemediationSuccessRate)
+	}
+
+	// Nothing identifying may leak into the report
+	body, _ := json.Marshal(report)
+	for _, secret := range []string{"/media", "sonarr:8989", "secret", "nas", "Radarr 4K"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("Report contains %q: %s", secret, body)
+		}
+	}
+
+	// Previews before opting in don't store an install ID
+	if id := tel.loadSetting(telemetryInstallIDSetting); id != "" {
+		t.Errorf("Expected no stored install ID, got %q", id)
+	}
+}
+
+func TestTelemetry_OptIn(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	var received []TelemetryReport
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report TelemetryReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("Failed to decode report: %v", err)
+		}
+		received = append(received, report)
+	}))
+	defer srv.Close()
+
+	tel := NewTelemetryService(db, TelemetryConfig{URL: srv.URL}, nil)
+	if err := tel.Send(context.Background()); err != nil || len(received) != 0 {
+		t.Fatalf("Expected nothing sent before opting in, got %d (err %v)", len(received), err)
+	}
+
+	if _, err := tel.SetEnabled(true); err != nil {
+		t.Fatalf("SetEnabled(true) error = %v", err)
+	}
+	if err := tel.Send(context.Background()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(received))
+	}
+	firstID := received[0].InstallID
+	if tel.Status().LastSentAt == nil || tel.due() {
+		t.Errorf("Expected the report to be recorded as sent: %+v", tel.Status())
+	}
+
+	// Reloaded from the settings
+	reloaded := NewTelemetryService(db, TelemetryConfig{URL: srv.URL}, nil)
+	if !reloaded.Status().Enabled || reloaded.Status().LastSentAt == nil {
+		t.Errorf("Expected opt-in to persist: %+v", reloaded.Status())
+	}
+
+	// Opting out and in again gets a new install ID
+	if _, err := tel.SetEnabled(false); err != nil {
+		t.Fatalf("SetEnabled(false) error = %v", err)
+	}
+	if _, err := tel.SetEnabled(true); err != nil {
+		t.Fatalf("SetEnabled(true) error = %v", err)
+	}
+	if err := tel.Send(context.Background()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(received) != 2 || received[1].InstallID == firstID {
+		t.Errorf("Expected a new install ID after opting out, got %v", received)
+	}
+}
+
+func TestTelemetry_HardOff(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test db: %v", err)
+	}
+	defer db.Close()
+
+	sent := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { sent = true }))
+	defer srv.Close()
+
+	// Opted in before the hard off switch was set
+	if _, err := NewTelemetryService(db, TelemetryConfig{URL: srv.URL}, nil).SetEnabled(true); err != nil {
+		t.Fatalf("SetEnabled(true) error = %v", err)
+	}
+
+	tel := NewTelemetryService(db, TelemetryConfig{URL: srv.URL, Disabled: true}, nil)
+	if status := tel.Status(); status.Enabled || !status.HardDisabled {
+		t.Errorf("Expected the hard off switch to win: %+v", status)
+	}
+	if _, err := tel.SetEnabled(true); !errors.Is(err, ErrTelemetryDisabled) {
+		t.Errorf("SetEnabled(true) error = %v, want ErrTelemetryDisabled", err)
+	}
+	if err := tel.Send(context.Background()); err != nil || sent {
+		t.Errorf("Expected nothing sent, sent = %v (err %v)", sent, err)
+	}
+}
+
+func TestSizeBucket(t *testing.T) {
+	tests := map[int]string{0: "0", 1: "1", 5: "2-5", 6: "6-20", 100: "21-100", 101: "101-1k", 50000: "10k-100k", 2000000: ">1M"}
+	for n, want := range tests {
+		if got := sizeBucket(n); got != want {
+			t.Errorf("sizeBucket(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// SetUntrackedFileAction sets what happens to a corrupt file that exists on
+// disk but isn't tracked by its *arr instance: config.UntrackedFileNone fails
+// the remediation, config.UntrackedFileDelete deletes the file and
+// config.UntrackedFileQuarantine moves it into quarantineDir. Must be called
+// before Start.
+func (r *RemediatorService) SetUntrackedFileAction(action, quarantineDir string) {
+	r.untrackedFileAction = action
+	r.quarantineDir = quarantineDir
+}
+
+// removeUntrackedFile handles a DeleteFile failure for a file the *arr instance
+// doesn't track. With a fallback configured, the file is deleted or quarantined
+// directly and the media item is rescanned, so remediation can go on to search
+// for a replacement. Returns the metadata for that search, or the original
+// error when there is no fallback or it failed.
+func (r *RemediatorService) removeUntrackedFile(deleteErr error, filePath, arrPath string, mediaID int64) (map[string]interface{}, error) {
+	var untracked *integration.FileNotInArrError
+	if !errors.As(deleteErr, &untracked) {
+		return nil, deleteErr
+	}
+
+	metadata := untracked.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	switch r.untrackedFileAction {
+	case config.UntrackedFileDelete:
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w (direct deletion failed: %v)", deleteErr, err)
+		}
+		logger.Infof("Deleted untracked file %s directly from disk", filePath)
+		metadata["untracked_file_action"] = "deleted"
+	case config.UntrackedFileQuarantine:
+		dest, err := quarantineFile(filePath, r.quarantineDir)
+		if err != nil {
+			return nil, fmt.Errorf("%w (quarantine failed: %v)", deleteErr, err)
+		}
+		logger.Infof("Moved untracked file %s to quarantine: %s", filePath, dest)
+		metadata["untracked_file_action"] = "quarantined"
+		metadata["quarantine_path"] = dest
+	default:
+		return nil, deleteErr
+	}
+
+	// Let the *arr instance notice the file is gone before searching for a replacement
+	if err := r.arrClient.RescanMedia(mediaID, arrPath); err != nil {
+		logger.Warnf("Failed to rescan media %d after removing untracked file %s: %v", mediaID, filePath, err)
+	}
+	return metadata, nil
+}
+
+// quarantineFile moves a file into dir under a timestamped name, so files with
+// the same name never overwrite each other. Returns the new path.
+func quarantineFile(filePath, dir string) (string, error) {
+	if dir == "" {
+		return "", errors.New("no quarantine directory configured")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(dir, fmt.Sprintf("%s_%s", time.Now().Format("20060102-150405"), filepath.Base(filePath)))
+	if err := os.Rename(filePath, dest); err == nil {
+		return dest, nil
+	}
+
+	// Rename fails across filesystems; fall back to copy and delete
+	if err := copyFile(filePath, dest); err != nil {
+		os.Remove(dest)
+		return "", err
+	}
+	if err := os.Remove(filePath); err != nil {
+		os.Remove(dest)
+		return "", err
+	}
+	return dest, nil
+}
+
+// copyFile copies src to a new file at dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) // #nosec G304 -- src is a scanned media file
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640) // #nosec G304 -- dst is inside the quarantine directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
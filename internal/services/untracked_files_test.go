@@ -0,0 +1,87 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mescon/Healarr/internal/config"
+	"github.com/mescon/Healarr/internal/domain"
+	"github.com/mescon/Healarr/internal/integration"
+	"github.com/mescon/Healarr/internal/testutil"
+)
+
+func TestRemediatorService_UntrackedFileFallback(t *testing.T) {
+	tests := []struct {
+		name            string
+		action          string
+		wantRemoved     bool
+		wantQuarantined bool
+	}{
+		{"none_fails_remediation", config.UntrackedFileNone, false, false},
+		{"delete_removes_file", config.UntrackedFileDelete, true, false},
+		{"quarantine_moves_file", config.UntrackedFileQuarantine, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := filepath.Join(dir, "Movie (2020).mkv")
+			if err := os.WriteFile(filePath, []byte("corrupt"), 0600); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			quarantineDir := filepath.Join(dir, "quarantine")
+
+			mockEventBus := testutil.NewMockEventBus()
+			mockArrClient := &testutil.MockArrClient{
+				FindMediaByPathFunc: func(path string) (int64, error) { return 123, nil },
+				DeleteFileFunc: func(mediaID int64, path string) (map[string]interface{}, error) {
+					return nil, &integration.FileNotInArrError{
+						ArrType:  "radarr",
+						Path:     path,
+						Metadata: map[string]interface{}{"deleted_path": path, "movie_id": mediaID},
+					}
+				},
+			}
+			remediator := NewRemediatorService(mockEventBus, mockArrClient, &testutil.MockPathMapper{}, nil)
+			remediator.SetUntrackedFileAction(tt.action, quarantineDir)
+
+			remediator.executeRemediation("corruption-1", filePath, filePath, 0)
+
+			_, statErr := os.Stat(filePath)
+			if removed := os.IsNotExist(statErr); removed != tt.wantRemoved {
+				t.Errorf("File removed = %v, want %v", removed, tt.wantRemoved)
+			}
+
+			quarantined, _ := filepath.Glob(filepath.Join(quarantineDir, "*_Movie (2020).mkv"))
+			if (len(quarantined) == 1) != tt.wantQuarantined {
+				t.Errorf("Quarantined files = %v, want quarantined %v", quarantined, tt.wantQuarantined)
+			}
+
+			if !tt.wantRemoved {
+				if mockEventBus.EventCount(domain.DeletionFailed) != 1 {
+					t.Error("Expected DeletionFailed without a fallback")
+				}
+				if mockArrClient.CallCount("TriggerSearch") != 0 {
+					t.Error("TriggerSearch must not be called when the file stays on disk")
+				}
+				return
+			}
+
+			if mockArrClient.CallCount("RescanMedia") != 1 {
+				t.Error("Expected the media item to be rescanned")
+			}
+			if mockArrClient.CallCount("TriggerSearch") != 1 {
+				t.Error("Expected a search for a replacement")
+			}
+			completed := mockEventBus.GetEvents(domain.DeletionCompleted)
+			if len(completed) != 1 {
+				t.Fatalf("Expected 1 DeletionCompleted event, got %d", len(completed))
+			}
+			metadata, _ := completed[0].EventData["metadata"].(map[string]interface{})
+			if metadata["untracked_file_action"] == nil {
+				t.Errorf("Expected untracked_file_action in metadata, got %v", metadata)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/mescon/Healarr/internal/eventbus"
 	"github.com/mescon/Healarr/internal/integration"
 	"github.com/mescon/Healarr/internal/logger"
+	"github.com/mescon/Healarr/internal/remote"
 )
 
 // verifierQueryTimeout is the maximum time for database queries in verifier service.
@@ -40,6 +41,11 @@ const historyRetryInterval = 10 * time.Second
 // historyRetryMaxAttempts is the maximum number of history API retries (12 * 10s = 2 min).
 const historyRetryMaxAttempts = 12
 
+// progressHeartbeat is how often a DownloadProgress event is re-emitted for an active
+// download whose status and percentage have not changed, so the ETA shown in the UI
+// stays current for slow downloads.
+const progressHeartbeat = 2 * time.Minute
+
 // VerificationMeta stores quality/release info captured from history for VerificationSuccess events.
 type VerificationMeta struct {
 	Quality        string
@@ -59,6 +65,12 @@ type VerifierService struct {
 	arrClient  integration.ArrClient
 	db         *sql.DB
 
+	// remotePaths finds replacement files of remote scan paths (nil: all paths are local)
+	remotePaths *remote.Registry
+
+	// pause holds download monitoring while automation is paused (nil: never paused)
+	pause *SystemPause
+
 	// Graceful shutdown support
 	shutdownCh chan struct{}
 	wg         sync.WaitGroup
@@ -77,6 +89,11 @@ type VerifierService struct {
 	// Active verification cancellation - prevents multiple goroutines per corruption (BUG-2 fix)
 	activeVerifyMu sync.Mutex
 	activeVerify   map[string]context.CancelFunc // corruptionID -> cancel function
+
+	// Media looked up on a new instance after a library migration, picked up
+	// by the download monitor of the corruption on its next poll
+	reresolvedMu sync.Mutex
+	reresolved   map[string]mediaReresolution // corruptionID -> media on the new instance
 }
 
 // NewVerifierService creates a new VerifierService with the given dependencies.
@@ -92,7 +109,25 @@ func NewVerifierService(eb *eventbus.EventBus, detector integration.HealthChecke
 		lastState:    make(map[string]string),
 		verifyMeta:   make(map[string]*VerificationMeta),
 		activeVerify: make(map[string]context.CancelFunc),
+		reresolved:   make(map[string]mediaReresolution),
+	}
+}
+
+// SetRemotePaths lets the verifier find replacement files on remote scan paths.
+func (v *VerifierService) SetRemotePaths(paths *remote.Registry) {
+	v.remotePaths = paths
+}
+
+// statFile returns the size of a local or remote file and whether it exists.
+func (v *VerifierService) statFile(localPath string) (int64, bool) {
+	if e, isRemote, err := v.remotePaths.Stat(localPath); isRemote {
+		return e.Size, err == nil
+	}
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return 0, false
 	}
+	return info.Size(), true
 }
 
 // setLastState updates the last known state for a corruption (thread-safe)
@@ -163,6 +198,10 @@ func (v *VerifierService) unregisterVerification(corruptionID string) {
 	v.activeVerifyMu.Lock()
 	delete(v.activeVerify, corruptionID)
 	v.activeVerifyMu.Unlock()
+
+	v.reresolvedMu.Lock()
+	delete(v.reresolved, corruptionID)
+	v.reresolvedMu.Unlock()
 }
 
 // queueAction represents the result of processing a queue item.
@@ -295,6 +334,13 @@ func buildProgressEventData(item integration.QueueItemInfo, currentStatus, warni
 		"added_at":             item.AddedAt,
 	}
 
+	// Seconds until the download client expects to finish, for a countdown in the UI
+	if eta, err := time.Parse(time.RFC3339, item.EstimatedCompletion); err == nil {
+		if secs := int64(time.Until(eta).Seconds()); secs > 0 {
+			eventData["eta_seconds"] = secs
+		}
+	}
+
 	if warningMsg != "" {
 		eventData["warning"] = true
 		eventData["warning_message"] = warningMsg
@@ -336,7 +382,7 @@ func (v *VerifierService) convertAndVerifyPaths(arrPaths []string) []string {
 		if mapErr != nil {
 			localPath = p
 		}
-		if _, statErr := os.Stat(localPath); statErr == nil {
+		if _, exists := v.statFile(localPath); exists {
 			existingPaths = append(existingPaths, localPath)
 		}
 	}
@@ -380,8 +426,8 @@ func (v *VerifierService) storeImportMetadata(corruptionID string, existingPaths
 	}
 	if len(existingPaths) == 1 {
 		meta.NewFilePath = existingPaths[0]
-		if fi, err := os.Stat(existingPaths[0]); err == nil {
-			meta.NewFileSize = fi.Size()
+		if size, exists := v.statFile(existingPaths[0]); exists {
+			meta.NewFileSize = size
 		}
 		logger.Infof("Import detected for %s via history: %s", corruptionID, existingPaths[0])
 	} else {
@@ -466,6 +512,8 @@ func (v *VerifierService) getDurationMetrics(corruptionID string) (int64, int64)
 // Start subscribes to events and begins the verification service.
 func (v *VerifierService) Start() {
 	v.eventBus.Subscribe(domain.SearchCompleted, v.handleSearchCompleted)
+	v.eventBus.Subscribe(domain.ReverificationRequested, v.handleReverificationRequested)
+	v.eventBus.Subscribe(domain.MediaReresolved, v.handleMediaReresolved)
 }
 
 // Shutdown gracefully stops all verification goroutines
@@ -536,6 +584,38 @@ func (v *VerifierService) handleSearchCompleted(event domain.Event) {
 	})
 }
 
+// handleReverificationRequested checks the replacement of a resolved
+// corruption again. The outcome is published on the same corruption and
+// refers to the VerificationSuccess it repeats; a failure is retried like any
+// other failed verification. A pass has no side effects: the media isn't
+// rescanned, and neither notifications nor metrics count it as a repair.
+func (v *VerifierService) handleReverificationRequested(event domain.Event) {
+	corruptionID := event.AggregateID
+
+	filePaths, ok := event.GetStringSlice("file_paths")
+	if !ok || len(filePaths) == 0 {
+		filePath, ok := event.GetString("file_path")
+		if !ok || filePath == "" {
+			logger.Errorf("Missing file_path in ReverificationRequested event for %s", corruptionID)
+			return
+		}
+		filePaths = []string{filePath}
+	}
+	link := map[string]interface{}{"reverification_of": event.GetInt64Or("reverification_of", 0)}
+	if event.GetBoolOr("audit", false) {
+		link["audit"] = true
+	}
+
+	v.cancelExistingVerification(corruptionID)
+	ctx, cancel := context.WithCancel(context.Background())
+	v.registerVerification(corruptionID, cancel)
+
+	logger.Infof("Reverifying %d file(s) of resolved corruption %s", len(filePaths), corruptionID)
+	v.startVerificationWithSemaphore(ctx, corruptionID, func(context.Context) {
+		v.verifyFiles(corruptionID, filePaths, link)
+	})
+}
+
 // startVerificationWithSemaphore launches a verification goroutine with concurrency limiting.
 // This prevents resource exhaustion when processing many corruptions simultaneously.
 // The context is used for cancellation when a new verification starts for the same corruptionID.
@@ -590,8 +670,13 @@ type monitorState struct {
 	attempt         int
 	lastStatus      string
 	lastProgress    float64
+	lastProgressAt  time.Time // When the last DownloadProgress event was emitted
 	wasInQueue      bool
 	apiFailureCount int // Track consecutive API failures for ManuallyRemoved detection
+	// checkedDownloads holds the download IDs already checked before import
+	checkedDownloads map[string]bool
+	// protocol is the download protocol the scan path accepts
+	protocol string
 }
 
 // monitorAction represents actions from monitoring steps
@@ -614,6 +699,16 @@ func (v *VerifierService) handleQueueItem(state *monitorState, item integration.
 		return monitorStop
 	}
 
+	// Catch a grab of the wrong protocol, or a corrupt download before the
+	// *arr imports it
+	if v.rejectProtocol(state, item) || v.prevalidateDownload(state, item) {
+		// The *arr searches again, so keep watching for the next grab
+		state.wasInQueue = false
+		state.lastStatus = ""
+		state.lastProgress = 0
+		return monitorContinue
+	}
+
 	// Handle importBlocked state
 	v.handleQueueItemBlocked(state.corruptionID, state.filePath, item)
 
@@ -624,11 +719,15 @@ func (v *VerifierService) handleQueueItem(state *monitorState, item integration.
 			state.corruptionID, item.TrackedDownloadStatus, item.TrackedDownloadState, warningMsg)
 	}
 
-	if currentStatus != state.lastStatus || int(item.Progress) != int(state.lastProgress) {
-		logger.Infof("Download progress for %s: %s (%.1f%%) - %s",
-			state.corruptionID, currentStatus, item.Progress, item.TimeLeft)
+	changed := currentStatus != state.lastStatus || int(item.Progress) != int(state.lastProgress)
+	if changed || time.Since(state.lastProgressAt) >= progressHeartbeat {
+		if changed {
+			logger.Infof("Download progress for %s: %s (%.1f%%) - %s",
+				state.corruptionID, currentStatus, item.Progress, item.TimeLeft)
+		}
 		state.lastStatus = currentStatus
 		state.lastProgress = item.Progress
+		state.lastProgressAt = time.Now()
 
 		eventData := buildProgressEventData(item, currentStatus, warningMsg)
 		if err := v.eventBus.Publish(domain.Event{
@@ -779,7 +878,9 @@ func (v *VerifierService) monitorDownloadProgress(ctx context.Context, corruptio
 		pollInterval: cfg.VerificationInterval,
 		timeout:      v.getVerificationTimeout(pathID),
 		startTime:    time.Now(),
+		protocol:     v.getProtocolPreference(pathID),
 	}
+	v.extendForDelayProfile(state)
 
 	logger.Infof("Starting download monitoring for corruption %s (media ID: %d)", corruptionID, mediaID)
 
@@ -805,6 +906,13 @@ func (v *VerifierService) executeMonitorIteration(ctx context.Context, state *mo
 		return monitorStop
 	}
 
+	paused, stop := v.waitWhilePaused(ctx, state.corruptionID)
+	if stop {
+		return monitorStop
+	}
+	state.startTime = state.startTime.Add(paused)
+	v.applyReresolvedMedia(state)
+
 	elapsed := time.Since(state.startTime)
 	if elapsed > state.timeout {
 		v.publishDownloadTimeout(state.corruptionID, elapsed, state.attempt, state.lastStatus)
@@ -1010,6 +1118,10 @@ func (v *VerifierService) getHistoryWithRetry(arrPath string, mediaID int64, lim
 		if err == nil {
 			return historyItems, nil
 		}
+		if errors.Is(err, integration.ErrRequestDeferred) {
+			// Retrying won't help before the request budget resets
+			return nil, err
+		}
 
 		lastErr = err
 		if attempt < maxRetries-1 {
@@ -1052,6 +1164,12 @@ func (v *VerifierService) pollForFileWithBackoff(ctx context.Context, corruption
 			return
 		}
 
+		paused, stop := v.waitWhilePaused(ctx, corruptionID)
+		if stop {
+			return
+		}
+		startTime = startTime.Add(paused)
+
 		elapsed := time.Since(startTime)
 		if elapsed > timeout {
 			v.publishDownloadTimeout(corruptionID, elapsed, attempt, "")
@@ -1210,10 +1328,15 @@ func (v *VerifierService) emitFilesDetected(corruptionID string, filePaths []str
 	v.verifyHealthMultiple(corruptionID, filePaths)
 }
 
-// verifyFilesHealth checks all files and returns failed paths and last error.
-func (v *VerifierService) verifyFilesHealth(filePaths []string) (failedPaths []string, lastError string) {
+// verifyFilesHealth checks all files and returns failed paths, the last error
+// and the deepest check whose verdict was used.
+func (v *VerifierService) verifyFilesHealth(filePaths []string) (failedPaths []string, lastError, depth string) {
+	depth = integration.ModeQuick
 	for _, filePath := range filePaths {
-		healthy, err := v.detector.Check(filePath, "thorough")
+		healthy, err, fileDepth := v.checkReplacement(filePath)
+		if fileDepth == integration.ModeThorough {
+			depth = fileDepth
+		}
 		if healthy {
 			continue
 		}
@@ -1223,13 +1346,41 @@ func (v *VerifierService) verifyFilesHealth(filePaths []string) (failedPaths []s
 		} else {
 			lastError = "unknown error"
 		}
-		logger.Infof("Verification failed for %s: %s", filePath, lastError)
+		logger.Infof("Verification failed for %s (%s check): %s", filePath, fileDepth, lastError)
+	}
+	return failedPaths, lastError, depth
+}
+
+// checkReplacement verifies a replacement with a quick check first. When the
+// quick check can't tell, e.g. the tool timed out or only part of the file
+// could be read, it escalates to a thorough decode before the file is declared
+// bad. Returns the depth of the check whose verdict counts.
+func (v *VerifierService) checkReplacement(filePath string) (bool, *integration.HealthCheckError, string) {
+	healthy, healthErr := v.detector.Check(filePath, integration.ModeQuick)
+	if healthy || !inconclusiveCheck(healthErr) {
+		return healthy, healthErr, integration.ModeQuick
+	}
+	logger.Infof("Quick verification of %s was inconclusive (%s), escalating to a thorough check", filePath, healthErr.Message)
+	healthy, healthErr = v.detector.Check(filePath, integration.ModeThorough)
+	return healthy, healthErr, integration.ModeThorough
+}
+
+// inconclusiveCheck reports whether a failed check says nothing about the
+// file itself, so a deeper check may still find it healthy.
+func inconclusiveCheck(healthErr *integration.HealthCheckError) bool {
+	if healthErr == nil {
+		return true
+	}
+	switch healthErr.Type {
+	case integration.ErrorTypeTimeout, integration.ErrorTypeToolFailure, integration.ErrorTypeIOError:
+		return true
+	default:
+		return false
 	}
-	return failedPaths, lastError
 }
 
 // buildSuccessEventData builds event data for a successful verification.
-func (v *VerifierService) buildSuccessEventData(corruptionID string, fileCount int) map[string]interface{} {
+func (v *VerifierService) buildSuccessEventData(corruptionID string, fileCount int, meta *VerificationMeta) map[string]interface{} {
 	eventData := map[string]interface{}{"verified_count": fileCount}
 
 	totalDuration, downloadDuration := v.getDurationMetrics(corruptionID)
@@ -1240,13 +1391,75 @@ func (v *VerifierService) buildSuccessEventData(corruptionID string, fileCount i
 		eventData["download_duration_seconds"] = downloadDuration
 	}
 
-	enrichVerificationEventData(eventData, v.getVerifyMeta(corruptionID))
+	enrichVerificationEventData(eventData, meta)
 	return eventData
 }
 
+// rescanVerifiedMedia asks the *arr instance to rescan the media item of a verified
+// replacement, so its file info (quality, mediainfo) is current without waiting for
+// the next scheduled refresh. The outcome is recorded in the VerificationSuccess
+// event data; a failed rescan doesn't affect the verification result.
+func (v *VerifierService) rescanVerifiedMedia(corruptionID, filePath string, eventData map[string]interface{}) {
+	if v.arrClient == nil || v.pathMapper == nil {
+		return
+	}
+
+	mediaID := v.getMediaID(corruptionID)
+	if mediaID == 0 {
+		return
+	}
+
+	arrPath, err := v.pathMapper.ToArrPath(filePath)
+	if err != nil {
+		eventData["rescan_error"] = err.Error()
+		logger.Warnf("Skipping rescan for %s: failed to map path %s: %v", corruptionID, filePath, err)
+		return
+	}
+
+	if err := v.arrClient.RescanMedia(mediaID, arrPath); err != nil {
+		eventData["rescan_error"] = err.Error()
+		logger.Warnf("Failed to rescan media %d after verifying %s: %v", mediaID, corruptionID, err)
+		return
+	}
+	eventData["rescan_triggered"] = true
+	logger.Debugf("Triggered rescan of media %d after verifying %s", mediaID, corruptionID)
+}
+
+// getMediaID returns the media ID recorded by the latest remediation event of a
+// corruption or looked up after a library migration, or 0 if there is none.
+func (v *VerifierService) getMediaID(corruptionID string) int64 {
+	if v.db == nil {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifierQueryTimeout)
+	defer cancel()
+
+	var mediaID sql.NullFloat64
+	err := v.db.QueryRowContext(ctx, `
+		SELECT json_extract(event_data, '$.media_id')
+		FROM events
+		WHERE aggregate_id IN (?, ?)
+		AND event_type IN ('SearchCompleted', 'SearchStarted', 'DeletionCompleted', 'MediaReresolved')
+		ORDER BY id DESC
+		LIMIT 1
+	`, corruptionID, mediaAggregateID(corruptionID)).Scan(&mediaID)
+	if err != nil || !mediaID.Valid {
+		return 0
+	}
+	return int64(mediaID.Float64)
+}
+
 // verifyHealthMultiple verifies the health of one or more files.
 // All files must be healthy for verification to succeed.
 func (v *VerifierService) verifyHealthMultiple(corruptionID string, filePaths []string) {
+	v.verifyFiles(corruptionID, filePaths, nil)
+}
+
+// verifyFiles verifies the files of a replacement and publishes the outcome.
+// link is added to the VerificationSuccess or VerificationFailed event, e.g. to
+// tie a reverification to the verification it repeats.
+func (v *VerifierService) verifyFiles(corruptionID string, filePaths []string, link map[string]interface{}) {
 	if err := v.eventBus.Publish(domain.Event{
 		AggregateID:   corruptionID,
 		AggregateType: "corruption",
@@ -1255,11 +1468,26 @@ func (v *VerifierService) verifyHealthMultiple(corruptionID string, filePaths []
 		logger.Errorf("Failed to publish VerificationStarted event: %v", err)
 	}
 
-	failedPaths, lastError := v.verifyFilesHealth(filePaths)
+	failedPaths, lastError, depth := v.verifyFilesHealth(filePaths)
+	meta := v.getVerifyMeta(corruptionID)
 	v.clearVerifyMeta(corruptionID)
 
 	if len(failedPaths) == 0 {
-		eventData := v.buildSuccessEventData(corruptionID, len(filePaths))
+		if v.checkQualityPin(corruptionID, filePaths, meta) {
+			return
+		}
+		if v.checkAudioTracks(corruptionID, filePaths) {
+			return
+		}
+		eventData := v.buildSuccessEventData(corruptionID, len(filePaths), meta)
+		eventData["verification_depth"] = depth
+		for k, val := range link {
+			eventData[k] = val
+		}
+		// The *arr instance was rescanned when the replacement was first verified
+		if _, reverification := link["reverification_of"]; !reverification {
+			v.rescanVerifiedMedia(corruptionID, filePaths[0], eventData)
+		}
 		// Terminal state event - critical, use retry
 		if err := v.eventBus.PublishWithRetry(domain.Event{
 			AggregateID:   corruptionID,
@@ -1272,17 +1500,22 @@ func (v *VerifierService) verifyHealthMultiple(corruptionID string, filePaths []
 		return
 	}
 
+	failedData := map[string]interface{}{
+		"error":              lastError,
+		"failed_paths":       failedPaths,
+		"failed_count":       len(failedPaths),
+		"total_count":        len(filePaths),
+		"verification_depth": depth,
+	}
+	for k, val := range link {
+		failedData[k] = val
+	}
 	// Terminal state event - critical, use retry
 	if err := v.eventBus.PublishWithRetry(domain.Event{
 		AggregateID:   corruptionID,
 		AggregateType: "corruption",
 		EventType:     domain.VerificationFailed,
-		EventData: map[string]interface{}{
-			"error":        lastError,
-			"failed_paths": failedPaths,
-			"failed_count": len(failedPaths),
-			"total_count":  len(filePaths),
-		},
+		EventData:     failedData,
 	}); err != nil {
 		logger.Errorf("Failed to publish VerificationFailed event after retries: %v", err)
 	}
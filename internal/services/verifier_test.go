@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -262,6 +265,251 @@ func TestVerifierService_VerifyHealthMultiple(t *testing.T) {
 	})
 }
 
+func TestVerifierService_VerificationEscalation(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	// checker returns the quick and thorough verdicts and records the modes run
+	checker := func(quick, thorough *integration.HealthCheckError, modes *[]string) *testutil.MockHealthChecker {
+		return &testutil.MockHealthChecker{
+			CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+				*modes = append(*modes, mode)
+				verdict := quick
+				if mode == integration.ModeThorough {
+					verdict = thorough
+				}
+				return verdict == nil, verdict
+			},
+		}
+	}
+	timeout := &integration.HealthCheckError{Type: integration.ErrorTypeTimeout, Message: "ffprobe timed out"}
+	corrupt := &integration.HealthCheckError{Type: integration.ErrorTypeCorruptStream, Message: "decode error"}
+
+	tests := []struct {
+		name      string
+		quick     *integration.HealthCheckError
+		thorough  *integration.HealthCheckError
+		wantModes []string
+		wantEvent domain.EventType
+		wantDepth string
+	}{
+		{"quick check passes", nil, nil, []string{"quick"}, domain.VerificationSuccess, "quick"},
+		{"quick check finds corruption", corrupt, nil, []string{"quick"}, domain.VerificationFailed, "quick"},
+		{"inconclusive quick check passes thorough", timeout, nil, []string{"quick", "thorough"}, domain.VerificationSuccess, "thorough"},
+		{"inconclusive quick check fails thorough", timeout, corrupt, []string{"quick", "thorough"}, domain.VerificationFailed, "thorough"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eb := eventbus.NewEventBus(db)
+			defer eb.Shutdown()
+
+			var modes []string
+			verifier := NewVerifierService(eb, checker(tt.quick, tt.thorough, &modes), nil, nil, db)
+			corruptionID := fmt.Sprintf("escalation-%d", i)
+			verifier.verifyHealthMultiple(corruptionID, []string{"/media/movies/film.mkv"})
+
+			if strings.Join(modes, ",") != strings.Join(tt.wantModes, ",") {
+				t.Errorf("checks run = %v, want %v", modes, tt.wantModes)
+			}
+			events, err := testutil.GetEventsByAggregate(db, corruptionID)
+			if err != nil {
+				t.Fatalf("Failed to load events: %v", err)
+			}
+			last := events[len(events)-1]
+			if last.EventType != tt.wantEvent {
+				t.Fatalf("event = %s, want %s", last.EventType, tt.wantEvent)
+			}
+			if depth := last.EventData["verification_depth"]; depth != tt.wantDepth {
+				t.Errorf("verification_depth = %v, want %s", depth, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestVerifierService_Reverification(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	var checked []string
+	detector := &testutil.MockHealthChecker{
+		CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+			checked = append(checked, path)
+			if path == "/media/tv/e02.mkv" {
+				return false, &integration.HealthCheckError{Type: integration.ErrorTypeCorruptStream, Message: "decode error"}
+			}
+			return true, nil
+		},
+	}
+	verifier := NewVerifierService(eb, detector, nil, nil, db)
+
+	verifier.handleReverificationRequested(domain.Event{
+		AggregateID:   "reverify-1",
+		AggregateType: "corruption",
+		EventType:     domain.ReverificationRequested,
+		EventData: map[string]interface{}{
+			"file_path":         "/media/tv/e01.mkv",
+			"file_paths":        []string{"/media/tv/e01.mkv", "/media/tv/e02.mkv"},
+			"reverification_of": int64(42),
+		},
+	})
+	verifier.wg.Wait()
+
+	if strings.Join(checked, ",") != "/media/tv/e01.mkv,/media/tv/e02.mkv" {
+		t.Errorf("checked = %v, want both replacement files", checked)
+	}
+	events, err := testutil.GetEventsByAggregate(db, "reverify-1")
+	if err != nil {
+		t.Fatalf("Failed to load events: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.EventType != domain.VerificationFailed {
+		t.Fatalf("event = %s, want %s", last.EventType, domain.VerificationFailed)
+	}
+	if id, _ := last.GetInt64("reverification_of"); id != 42 {
+		t.Errorf("reverification_of = %v, want 42", last.EventData["reverification_of"])
+	}
+}
+
+func TestVerifierService_RescanAfterVerification(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	healthy := &testutil.MockHealthChecker{
+		CheckFunc: func(path, mode string) (bool, *integration.HealthCheckError) {
+			return true, nil
+		},
+	}
+
+	successData := func(t *testing.T, corruptionID string) map[string]interface{} {
+		t.Helper()
+		events, err := testutil.GetEventsByAggregate(db, corruptionID)
+		if err != nil {
+			t.Fatalf("Failed to load events: %v", err)
+		}
+		for _, e := range events {
+			if e.EventType == domain.VerificationSuccess {
+				return e.EventData
+			}
+		}
+		t.Fatalf("No VerificationSuccess event for %s", corruptionID)
+		return nil
+	}
+
+	t.Run("records triggered rescan", func(t *testing.T) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+
+		if _, err := testutil.SeedEvent(db, domain.Event{
+			AggregateID:   "rescan-1",
+			AggregateType: "corruption",
+			EventType:     domain.SearchCompleted,
+			EventData:     map[string]interface{}{"file_path": "/media/movies/film.mkv", "media_id": 42},
+		}); err != nil {
+			t.Fatalf("Failed to seed event: %v", err)
+		}
+
+		var rescanned []int64
+		mockArr := &testutil.MockArrClient{
+			RescanMediaFunc: func(mediaID int64, path string) error {
+				rescanned = append(rescanned, mediaID)
+				return nil
+			},
+		}
+		verifier := NewVerifierService(eb, healthy, &testutil.MockPathMapper{}, mockArr, db)
+		verifier.verifyHealthMultiple("rescan-1", []string{"/media/movies/film.mkv"})
+
+		if len(rescanned) != 1 || rescanned[0] != 42 {
+			t.Fatalf("Expected media 42 to be rescanned once, got %v", rescanned)
+		}
+		if data := successData(t, "rescan-1"); data["rescan_triggered"] != true {
+			t.Errorf("Expected rescan_triggered in event data, got %v", data)
+		}
+	})
+
+	t.Run("rescan failure still verifies", func(t *testing.T) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+
+		if _, err := testutil.SeedEvent(db, domain.Event{
+			AggregateID:   "rescan-2",
+			AggregateType: "corruption",
+			EventType:     domain.SearchCompleted,
+			EventData:     map[string]interface{}{"file_path": "/media/movies/film.mkv", "media_id": 43},
+		}); err != nil {
+			t.Fatalf("Failed to seed event: %v", err)
+		}
+
+		mockArr := &testutil.MockArrClient{
+			RescanMediaFunc: func(mediaID int64, path string) error {
+				return errors.New("connection refused")
+			},
+		}
+		verifier := NewVerifierService(eb, healthy, &testutil.MockPathMapper{}, mockArr, db)
+		verifier.verifyHealthMultiple("rescan-2", []string{"/media/movies/film.mkv"})
+
+		data := successData(t, "rescan-2")
+		if data["rescan_error"] != "connection refused" || data["rescan_triggered"] != nil {
+			t.Errorf("Expected rescan_error in event data, got %v", data)
+		}
+	})
+
+	t.Run("reverification skips rescan", func(t *testing.T) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+
+		if _, err := testutil.SeedEvent(db, domain.Event{
+			AggregateID:   "rescan-4",
+			AggregateType: "corruption",
+			EventType:     domain.SearchCompleted,
+			EventData:     map[string]interface{}{"file_path": "/media/movies/film.mkv", "media_id": 44},
+		}); err != nil {
+			t.Fatalf("Failed to seed event: %v", err)
+		}
+
+		mockArr := &testutil.MockArrClient{}
+		verifier := NewVerifierService(eb, healthy, &testutil.MockPathMapper{}, mockArr, db)
+		verifier.verifyFiles("rescan-4", []string{"/media/movies/film.mkv"}, map[string]interface{}{"reverification_of": int64(42)})
+
+		if mockArr.CallCount("RescanMedia") != 0 {
+			t.Error("Expected no rescan when reverifying")
+		}
+		if data := successData(t, "rescan-4"); data["reverification_of"] == nil {
+			t.Errorf("Expected the reverification link in event data, got %v", data)
+		}
+	})
+
+	t.Run("no media ID skips rescan", func(t *testing.T) {
+		eb := eventbus.NewEventBus(db)
+		defer eb.Shutdown()
+
+		mockArr := &testutil.MockArrClient{}
+		verifier := NewVerifierService(eb, healthy, &testutil.MockPathMapper{}, mockArr, db)
+		verifier.verifyHealthMultiple("rescan-3", []string{"/media/movies/film.mkv"})
+
+		if mockArr.CallCount("RescanMedia") != 0 {
+			t.Error("Expected no rescan without a media ID")
+		}
+	})
+}
+
 func TestVerifierService_EmitFilesDetected(t *testing.T) {
 	config.SetForTesting(config.NewTestConfig())
 
@@ -3141,3 +3389,78 @@ func TestVerifierService_StartVerificationWithSemaphore(t *testing.T) {
 		}
 	})
 }
+
+func TestVerifierService_HandleQueueItem_ProgressHeartbeat(t *testing.T) {
+	config.SetForTesting(config.NewTestConfig())
+
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	eb := eventbus.NewEventBus(db)
+	defer eb.Shutdown()
+
+	verifier := NewVerifierService(eb, nil, nil, &testutil.MockArrClient{}, db)
+	item := integration.QueueItemInfo{
+		ID:                   7,
+		TrackedDownloadState: "downloading",
+		Progress:             42.3,
+		Indexer:              "NZBgeek",
+		DownloadClient:       "SABnzbd",
+		EstimatedCompletion:  time.Now().Add(10 * time.Minute).UTC().Format(time.RFC3339),
+	}
+	state := &monitorState{corruptionID: "test-heartbeat"}
+
+	countProgress := func() int {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM events WHERE aggregate_id = ? AND event_type = ?",
+			"test-heartbeat", domain.DownloadProgress).Scan(&count); err != nil {
+			t.Fatalf("Failed to count events: %v", err)
+		}
+		return count
+	}
+
+	verifier.handleQueueItem(state, item)
+	if got := countProgress(); got != 1 {
+		t.Fatalf("Expected first poll to emit progress, got %d events", got)
+	}
+
+	// Unchanged download within the heartbeat window stays quiet
+	verifier.handleQueueItem(state, item)
+	if got := countProgress(); got != 1 {
+		t.Errorf("Expected no new event for unchanged progress, got %d events", got)
+	}
+
+	// After the heartbeat interval the same progress is re-emitted
+	state.lastProgressAt = time.Now().Add(-progressHeartbeat)
+	verifier.handleQueueItem(state, item)
+	if got := countProgress(); got != 2 {
+		t.Errorf("Expected heartbeat progress event, got %d events", got)
+	}
+
+	var raw string
+	if err := db.QueryRow("SELECT event_data FROM events WHERE aggregate_id = ? ORDER BY id DESC LIMIT 1", "test-heartbeat").Scan(&raw); err != nil {
+		t.Fatalf("Failed to load event: %v", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("Failed to decode event data: %v", err)
+	}
+	if data["indexer"] != "NZBgeek" || data["download_client"] != "SABnzbd" || data["progress"] != 42.3 {
+		t.Errorf("Unexpected progress data: %v", data)
+	}
+	if eta, _ := data["eta_seconds"].(float64); eta <= 0 || eta > 600 {
+		t.Errorf("Expected eta_seconds within 10 minutes, got %v", data["eta_seconds"])
+	}
+}
+
+func TestBuildProgressEventData_NoETA(t *testing.T) {
+	for _, completion := range []string{"", "not-a-time", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)} {
+		data := buildProgressEventData(integration.QueueItemInfo{EstimatedCompletion: completion}, "downloading", "")
+		if _, ok := data["eta_seconds"]; ok {
+			t.Errorf("Expected no eta_seconds for completion %q", completion)
+		}
+	}
+}
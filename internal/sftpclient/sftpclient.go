@@ -0,0 +1,114 @@
+// Package sftpclient connects to SFTP servers for backup targets and remote
+// scan paths. A server is trusted by the SHA256 fingerprint of its host key
+// alone, as printed by ssh-keygen -lf; there is no known_hosts file.
+package sftpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// ErrUnknownHostKey is returned when the server's host key doesn't match
+	// the configured fingerprint. The error names the key the server offered.
+	ErrUnknownHostKey = errors.New("SFTP host key not trusted")
+	// ErrAuthFailed is returned when the server rejects the credentials.
+	ErrAuthFailed = errors.New("SFTP authentication failed")
+)
+
+// DefaultTimeout bounds connecting when Config.Timeout is zero.
+const DefaultTimeout = 30 * time.Second
+
+// Config is how to reach an SFTP server.
+type Config struct {
+	// Addr is host[:port]; the port defaults to 22.
+	Addr     string
+	Username string
+	Password string
+	// PrivateKey is a PEM private key, used instead of Password when set.
+	PrivateKey string
+	// HostKeyFingerprint is the SHA256 fingerprint of the server's host key,
+	// with or without the SHA256: prefix.
+	HostKeyFingerprint string
+	Timeout            time.Duration
+}
+
+// Client is an SFTP session over its own SSH connection.
+type Client struct {
+	*sftp.Client
+	conn *ssh.Client
+}
+
+// Dial connects to the server, checks its host key and starts the SFTP subsystem.
+func Dial(ctx context.Context, cfg Config) (*Client, error) {
+	auth := []ssh.AuthMethod{ssh.Password(cfg.Password)}
+	if cfg.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	addr := cfg.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback(cfg.HostKeyFingerprint),
+		Timeout:         timeout,
+	})
+	if err != nil {
+		netConn.Close()
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return nil, fmt.Errorf("%w: %v", ErrAuthFailed, err)
+		}
+		return nil, err
+	}
+	conn := ssh.NewClient(sshConn, chans, reqs)
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SFTP subsystem unavailable: %w", err)
+	}
+	return &Client{Client: client, conn: conn}, nil
+}
+
+// Close ends the SFTP session and its SSH connection.
+func (c *Client) Close() error {
+	err := c.Client.Close()
+	if connErr := c.conn.Close(); err == nil {
+		err = connErr
+	}
+	return err
+}
+
+// hostKeyCallback accepts only the host key with the given SHA256 fingerprint.
+func hostKeyCallback(fingerprint string) ssh.HostKeyCallback {
+	want := strings.TrimPrefix(strings.TrimSpace(fingerprint), "SHA256:")
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		got := ssh.FingerprintSHA256(key)
+		if want == "" || strings.TrimPrefix(got, "SHA256:") != want {
+			return fmt.Errorf("%w: server offered %s %s", ErrUnknownHostKey, key.Type(), got)
+		}
+		return nil
+	}
+}
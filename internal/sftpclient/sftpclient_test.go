@@ -0,0 +1,36 @@
+package sftpclient
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestHostKeyCallback(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := ssh.FingerprintSHA256(key)
+
+	for _, trusted := range []string{fingerprint, strings.TrimPrefix(fingerprint, "SHA256:"), " " + fingerprint + "\n"} {
+		if err := hostKeyCallback(trusted)("seedbox:22", nil, key); err != nil {
+			t.Errorf("Expected %q to be trusted, got %v", trusted, err)
+		}
+	}
+	// Without a pinned key the error tells the user which one to pin
+	for _, untrusted := range []string{"", "SHA256:AAAA"} {
+		err := hostKeyCallback(untrusted)("seedbox:22", nil, key)
+		if !errors.Is(err, ErrUnknownHostKey) || !strings.Contains(err.Error(), fingerprint) {
+			t.Errorf("Expected %q to be refused naming the offered key, got %v", untrusted, err)
+		}
+	}
+}
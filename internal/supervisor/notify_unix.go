@@ -0,0 +1,29 @@
+//go:build !windows
+
+package supervisor
+
+import (
+	"net"
+	"os"
+)
+
+// Notify sends state to the socket in NOTIFY_SOCKET. It returns false without
+// an error when Healarr isn't running under a service manager that listens.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+
+	// A leading @ names an abstract socket, which net resolves itself
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
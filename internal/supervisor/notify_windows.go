@@ -0,0 +1,9 @@
+//go:build windows
+
+package supervisor
+
+// Notify does nothing on Windows, where the service control manager learns
+// about the service state through the service handler instead.
+func Notify(state string) (bool, error) {
+	return false, nil
+}
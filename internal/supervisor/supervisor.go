@@ -0,0 +1,77 @@
+// Package supervisor tells the service manager Healarr runs under about its
+// state: systemd's sd_notify protocol on Linux (READY, STOPPING and watchdog
+// keep-alives), nothing elsewhere. Without a service manager every call is a
+// no-op, so callers don't need to check how Healarr was started.
+package supervisor
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mescon/Healarr/internal/logger"
+)
+
+// States sent with Notify.
+const (
+	// StateReady tells the service manager that startup has finished.
+	StateReady = "READY=1"
+	// StateStopping tells the service manager that a graceful shutdown has begun.
+	StateStopping = "STOPPING=1"
+	// StateWatchdog is the keep-alive that resets the watchdog timer.
+	StateWatchdog = "WATCHDOG=1"
+)
+
+// WatchdogInterval returns how often the service manager expects a watchdog
+// keep-alive, or 0 if the watchdog is off. It is derived from WATCHDOG_USEC,
+// which only applies to this process when WATCHDOG_PID is unset or matches.
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// StartWatchdog sends a keep-alive every half watchdog interval while check
+// succeeds, so the service manager restarts Healarr when it hangs. check gets
+// a quarter of the interval to finish. It returns a function that stops the
+// keep-alives; without a watchdog it does nothing.
+func StartWatchdog(check func(ctx context.Context) error) (stop func()) {
+	interval := WatchdogInterval()
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval/4)
+				err := check(ctx)
+				cancel()
+				if err != nil {
+					logger.Warnf("Watchdog health check failed, withholding keep-alive: %v", err)
+					continue
+				}
+				if _, err := Notify(StateWatchdog); err != nil {
+					logger.Debugf("Failed to send watchdog keep-alive: %v", err)
+				}
+			}
+		}
+	}()
+	logger.Infof("✓ Service watchdog enabled (timeout %s)", interval)
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,118 @@
+//go:build !windows
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listen creates a notify socket and points NOTIFY_SOCKET at it.
+func listen(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn, timeout time.Duration) (string, error) {
+	t.Helper()
+	buf := make([]byte, 256)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(timeout)))
+	n, err := conn.Read(buf)
+	return string(buf[:n]), err
+}
+
+func TestNotify(t *testing.T) {
+	t.Run("no socket", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "")
+		sent, err := Notify(StateReady)
+		assert.NoError(t, err)
+		assert.False(t, sent)
+	})
+
+	t.Run("sends state", func(t *testing.T) {
+		conn := listen(t)
+		sent, err := Notify(StateReady)
+		require.NoError(t, err)
+		assert.True(t, sent)
+
+		msg, err := receive(t, conn, time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, StateReady, msg)
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+		sent, err := Notify(StateReady)
+		assert.Error(t, err)
+		assert.False(t, sent)
+	})
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		usec string
+		pid  string
+		want time.Duration
+	}{
+		{"unset", "", "", 0},
+		{"invalid", "soon", "", 0},
+		{"set", "30000000", "", 30 * time.Second},
+		{"this process", "30000000", strconv.Itoa(os.Getpid()), 30 * time.Second},
+		{"other process", "30000000", "1", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+			assert.Equal(t, tt.want, WatchdogInterval())
+		})
+	}
+}
+
+func TestStartWatchdog(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("WATCHDOG_USEC", "")
+		stop := StartWatchdog(func(context.Context) error { return nil })
+		stop()
+	})
+
+	t.Run("pings while healthy", func(t *testing.T) {
+		conn := listen(t)
+		t.Setenv("WATCHDOG_USEC", "100000")
+		t.Setenv("WATCHDOG_PID", "")
+
+		stop := StartWatchdog(func(context.Context) error { return nil })
+		defer stop()
+
+		msg, err := receive(t, conn, time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, StateWatchdog, msg)
+	})
+
+	t.Run("withholds pings when unhealthy", func(t *testing.T) {
+		conn := listen(t)
+		t.Setenv("WATCHDOG_USEC", "100000")
+		t.Setenv("WATCHDOG_PID", "")
+
+		stop := StartWatchdog(func(context.Context) error { return errors.New("database locked") })
+		defer stop()
+
+		_, err := receive(t, conn, 300*time.Millisecond)
+		assert.Error(t, err)
+	})
+}
@@ -190,10 +190,12 @@ type MockArrClient struct {
 	GetFilePathFunc                     func(mediaID int64, metadata map[string]interface{}, referencePath string) (string, error)
 	GetAllFilePathsFunc                 func(mediaID int64, metadata map[string]interface{}, referencePath string) ([]string, error)
 	TriggerSearchFunc                   func(mediaID int64, path string, episodeIDs []int64) error
+	RescanMediaFunc                     func(mediaID int64, path string) error
 	GetAllInstancesFunc                 func() ([]*integration.ArrInstanceInfo, error)
 	GetInstanceByIDFunc                 func(id int64) (*integration.ArrInstanceInfo, error)
 	CheckInstanceHealthFunc             func(instanceID int64) error
 	GetRootFoldersFunc                  func(instanceID int64) ([]integration.RootFolder, error)
+	GetTagsFunc                         func(instanceID int64) ([]integration.ArrTag, error)
 	GetQueueForPathFunc                 func(arrPath string) ([]integration.QueueItemInfo, error)
 	FindQueueItemsByMediaIDForPathFunc  func(arrPath string, mediaID int64) ([]integration.QueueItemInfo, error)
 	GetDownloadStatusForPathFunc        func(arrPath, downloadID string) (status string, progress float64, errMsg string, err error)
@@ -201,6 +203,12 @@ type MockArrClient struct {
 	RemoveFromQueueByPathFunc           func(arrPath string, queueID int64, removeFromClient, blocklist bool) error
 	RefreshMonitoredDownloadsByPathFunc func(arrPath string) error
 	GetMediaDetailsFunc                 func(mediaID int64, arrPath string) (*integration.MediaDetails, error)
+	GetExpectedRuntimeFunc              func(mediaID int64, arrPath string) (time.Duration, error)
+	GetDelayProfileFunc                 func(mediaID int64, arrPath string) (*integration.DelayProfile, error)
+	GetReleasesFunc                     func(mediaID int64, arrPath string, episodeIDs []int64) ([]integration.Release, error)
+	GrabReleaseFunc                     func(arrPath, guid string, indexerID int64) (*integration.Release, error)
+	PlanDeletionFunc                    func(mediaID int64, path string) (*integration.DeletionPlan, error)
+	FindSearchMetadataFunc              func(mediaID int64, path string) (map[string]interface{}, error)
 
 	// Call tracking for assertions
 	mu    sync.Mutex
@@ -255,6 +263,22 @@ func (m *MockArrClient) DeleteFile(mediaID int64, path string) (map[string]inter
 	return nil, nil
 }
 
+func (m *MockArrClient) PlanDeletion(mediaID int64, path string) (*integration.DeletionPlan, error) {
+	m.recordCall("PlanDeletion", mediaID, path)
+	if m.PlanDeletionFunc != nil {
+		return m.PlanDeletionFunc(mediaID, path)
+	}
+	return &integration.DeletionPlan{MediaID: mediaID, Files: []integration.ArrFileRecord{}}, nil
+}
+
+func (m *MockArrClient) FindSearchMetadata(mediaID int64, path string) (map[string]interface{}, error) {
+	m.recordCall("FindSearchMetadata", mediaID, path)
+	if m.FindSearchMetadataFunc != nil {
+		return m.FindSearchMetadataFunc(mediaID, path)
+	}
+	return nil, nil
+}
+
 func (m *MockArrClient) GetFilePath(mediaID int64, metadata map[string]interface{}, referencePath string) (string, error) {
 	m.recordCall("GetFilePath", mediaID, metadata, referencePath)
 	if m.GetFilePathFunc != nil {
@@ -279,6 +303,14 @@ func (m *MockArrClient) TriggerSearch(mediaID int64, path string, episodeIDs []i
 	return nil
 }
 
+func (m *MockArrClient) RescanMedia(mediaID int64, path string) error {
+	m.recordCall("RescanMedia", mediaID, path)
+	if m.RescanMediaFunc != nil {
+		return m.RescanMediaFunc(mediaID, path)
+	}
+	return nil
+}
+
 func (m *MockArrClient) GetAllInstances() ([]*integration.ArrInstanceInfo, error) {
 	m.recordCall("GetAllInstances")
 	if m.GetAllInstancesFunc != nil {
@@ -311,6 +343,14 @@ func (m *MockArrClient) GetRootFolders(instanceID int64) ([]integration.RootFold
 	return nil, nil
 }
 
+func (m *MockArrClient) GetTags(instanceID int64) ([]integration.ArrTag, error) {
+	m.recordCall("GetTags", instanceID)
+	if m.GetTagsFunc != nil {
+		return m.GetTagsFunc(instanceID)
+	}
+	return nil, nil
+}
+
 func (m *MockArrClient) GetQueueForPath(arrPath string) ([]integration.QueueItemInfo, error) {
 	m.recordCall("GetQueueForPath", arrPath)
 	if m.GetQueueForPathFunc != nil {
@@ -367,6 +407,38 @@ func (m *MockArrClient) GetMediaDetails(mediaID int64, arrPath string) (*integra
 	return nil, nil
 }
 
+func (m *MockArrClient) GetExpectedRuntime(mediaID int64, arrPath string) (time.Duration, error) {
+	m.recordCall("GetExpectedRuntime", mediaID, arrPath)
+	if m.GetExpectedRuntimeFunc != nil {
+		return m.GetExpectedRuntimeFunc(mediaID, arrPath)
+	}
+	return 0, nil
+}
+
+func (m *MockArrClient) GetDelayProfile(mediaID int64, arrPath string) (*integration.DelayProfile, error) {
+	m.recordCall("GetDelayProfile", mediaID, arrPath)
+	if m.GetDelayProfileFunc != nil {
+		return m.GetDelayProfileFunc(mediaID, arrPath)
+	}
+	return nil, nil
+}
+
+func (m *MockArrClient) GetReleases(mediaID int64, arrPath string, episodeIDs []int64) ([]integration.Release, error) {
+	m.recordCall("GetReleases", mediaID, arrPath, episodeIDs)
+	if m.GetReleasesFunc != nil {
+		return m.GetReleasesFunc(mediaID, arrPath, episodeIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockArrClient) GrabRelease(arrPath, guid string, indexerID int64) (*integration.Release, error) {
+	m.recordCall("GrabRelease", arrPath, guid, indexerID)
+	if m.GrabReleaseFunc != nil {
+		return m.GrabReleaseFunc(arrPath, guid, indexerID)
+	}
+	return nil, nil
+}
+
 // SetHistoryHasImport configures the mock to return history indicating an import occurred.
 func (m *MockArrClient) SetHistoryHasImport(hasImport bool) {
 	if hasImport {
@@ -520,7 +592,13 @@ func NewMockEventBus() *MockEventBus {
 }
 
 // Publish stores the event and notifies subscribers synchronously.
+// Like the real bus in strict mode, it rejects events whose payload does not
+// match the schema of their type, so malformed events fail the test.
 func (m *MockEventBus) Publish(event domain.Event) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	m.PublishedEvents = append(m.PublishedEvents, event)
 	subscribers := m.Subscribers[event.EventType]
@@ -717,9 +795,9 @@ type MockSchedulerService struct {
 	StartFunc                    func()
 	StopFunc                     func()
 	LoadSchedulesFunc            func() error
-	AddScheduleFunc              func(scanPathID int, cronExpr string) (int64, error)
+	AddScheduleFunc              func(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error)
 	DeleteScheduleFunc           func(id int) error
-	UpdateScheduleFunc           func(id int, cronExpr string, enabled bool) error
+	UpdateScheduleFunc           func(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error
 	CleanupOrphanedSchedulesFunc func() (int, error)
 
 	mu    sync.Mutex
@@ -774,10 +852,10 @@ func (m *MockSchedulerService) LoadSchedules() error {
 	return nil
 }
 
-func (m *MockSchedulerService) AddSchedule(scanPathID int, cronExpr string) (int64, error) {
-	m.recordCall("AddSchedule", scanPathID, cronExpr)
+func (m *MockSchedulerService) AddSchedule(scanPathID int, cronExpr string, timing domain.ScheduleTiming) (int64, error) {
+	m.recordCall("AddSchedule", scanPathID, cronExpr, timing)
 	if m.AddScheduleFunc != nil {
-		return m.AddScheduleFunc(scanPathID, cronExpr)
+		return m.AddScheduleFunc(scanPathID, cronExpr, timing)
 	}
 	return 1, nil // Return default ID
 }
@@ -790,10 +868,10 @@ func (m *MockSchedulerService) DeleteSchedule(id int) error {
 	return nil
 }
 
-func (m *MockSchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool) error {
-	m.recordCall("UpdateSchedule", id, cronExpr, enabled)
+func (m *MockSchedulerService) UpdateSchedule(id int, cronExpr string, enabled bool, timing *domain.ScheduleTiming) error {
+	m.recordCall("UpdateSchedule", id, cronExpr, enabled, timing)
 	if m.UpdateScheduleFunc != nil {
-		return m.UpdateScheduleFunc(id, cronExpr, enabled)
+		return m.UpdateScheduleFunc(id, cronExpr, enabled, timing)
 	}
 	return nil
 }
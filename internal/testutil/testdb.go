@@ -62,7 +62,7 @@ func initializeSchema(db *sql.DB) error {
 	if err != nil {
 		return fmt.Errorf("failed to create aggregate index: %w", err)
 	}
-	_, err = db.Exec(`CREATE INDEX idx_event_type ON events(event_type)`)
+	_, err = db.Exec(`CREATE INDEX idx_events_type_created_at ON events(event_type, created_at)`)
 	if err != nil {
 		return fmt.Errorf("failed to create event_type index: %w", err)
 	}
@@ -83,13 +83,67 @@ func initializeSchema(db *sql.DB) error {
 			detection_mode TEXT NOT NULL DEFAULT 'quick',
 			max_retries INTEGER DEFAULT 3,
 			verification_timeout_hours INTEGER DEFAULT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			detection_fallbacks TEXT DEFAULT NULL,
+			min_file_age_minutes INTEGER DEFAULT 2,
+			size_stability_seconds INTEGER DEFAULT 0,
+			arr_instance_tag TEXT NOT NULL DEFAULT '',
+			quality_pin TEXT NOT NULL DEFAULT 'off',
+			priority INTEGER NOT NULL DEFAULT 0,
+			research_interval_days INTEGER NOT NULL DEFAULT 0,
+			research_period_days INTEGER NOT NULL DEFAULT 90,
+			protocol_preference TEXT NOT NULL DEFAULT 'any',
+			remote_url TEXT NOT NULL DEFAULT '',
+			remote_username TEXT NOT NULL DEFAULT '',
+			remote_password TEXT NOT NULL DEFAULT '',
+			remote_host_key_fingerprint TEXT NOT NULL DEFAULT '',
+			remote_requests_per_minute INTEGER NOT NULL DEFAULT 60,
+			media_extensions TEXT NOT NULL DEFAULT '',
+			min_file_size_mb INTEGER NOT NULL DEFAULT 0,
+			duration_check TEXT NOT NULL DEFAULT 'off',
+			symlink_policy TEXT NOT NULL DEFAULT 'skip',
+			rollout_daily_limit INTEGER NOT NULL DEFAULT 0,
+			rollout_days INTEGER NOT NULL DEFAULT 0,
+			min_confidence INTEGER NOT NULL DEFAULT 0,
+			group_id INTEGER,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create scan_paths table: %w", err)
 	}
 
+	// Create scan_path_groups table (migration 035)
+	_, err = db.Exec(`
+		CREATE TABLE scan_path_groups (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			remediation_paused BOOLEAN NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create scan_path_groups table: %w", err)
+	}
+
+	// Create scan_schedules table
+	_, err = db.Exec(`
+		CREATE TABLE scan_schedules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			scan_path_id INTEGER NOT NULL,
+			cron_expression TEXT NOT NULL,
+			timezone TEXT NOT NULL DEFAULT '',
+			dst_gap TEXT NOT NULL DEFAULT 'run',
+			dst_overlap TEXT NOT NULL DEFAULT 'once',
+			enabled BOOLEAN DEFAULT 1,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create scan_schedules table: %w", err)
+	}
+
 	// Create scans table
 	_, err = db.Exec(`
 		CREATE TABLE scans (
@@ -101,6 +155,7 @@ func initializeSchema(db *sql.DB) error {
 			corruptions_found INTEGER DEFAULT 0,
 			total_files INTEGER DEFAULT 0,
 			current_file_index INTEGER DEFAULT 0,
+			checkpoint_file_id INTEGER,
 			file_list TEXT,
 			detection_config TEXT,
 			auto_remediate INTEGER DEFAULT 0,
@@ -124,13 +179,28 @@ func initializeSchema(db *sql.DB) error {
 			corruption_type TEXT,
 			error_details TEXT,
 			file_size INTEGER,
-			scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			duration_ms INTEGER,
+			detection_tool TEXT
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create scan_files table: %w", err)
 	}
 
+	// Create scan_file_queue table
+	_, err = db.Exec(`
+		CREATE TABLE scan_file_queue (
+			scan_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			file_path TEXT NOT NULL,
+			PRIMARY KEY (scan_id, position)
+		) WITHOUT ROWID
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create scan_file_queue table: %w", err)
+	}
+
 	// Create pending_rescans table
 	_, err = db.Exec(`
 		CREATE TABLE pending_rescans (
@@ -174,34 +244,234 @@ func initializeSchema(db *sql.DB) error {
 			url TEXT NOT NULL,
 			api_key TEXT NOT NULL,
 			enabled BOOLEAN DEFAULT 1,
+			tags TEXT NOT NULL DEFAULT '[]',
+			daily_request_budget INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP DEFAULT NULL
 		)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to create arr_instances table: %w", err)
 	}
 
-	// Create corruption_summary table (migration 004) - used by some tests
+	if err := CreateCorruptionSummary(db); err != nil {
+		return err
+	}
+
+	// Create corruption_diagnostics table (migration 010)
 	_, err = db.Exec(`
-		CREATE TABLE corruption_summary (
-			corruption_id TEXT PRIMARY KEY,
+		CREATE TABLE corruption_diagnostics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			corruption_id TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			data BLOB NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_diagnostics table: %w", err)
+	}
+
+	// Create false_positives table (migration 011)
+	_, err = db.Exec(`
+		CREATE TABLE false_positives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			corruption_id TEXT NOT NULL,
 			file_path TEXT NOT NULL,
+			file_size INTEGER NOT NULL DEFAULT 0,
+			fingerprint TEXT NOT NULL DEFAULT '',
+			corruption_type TEXT NOT NULL DEFAULT '',
+			error_details TEXT NOT NULL DEFAULT '',
+			signature TEXT NOT NULL,
+			detection_tool TEXT NOT NULL DEFAULT '',
+			tool_version TEXT NOT NULL DEFAULT '',
+			checker_output BLOB,
+			note TEXT NOT NULL DEFAULT '',
+			suppressed_count INTEGER NOT NULL DEFAULT 0,
+			last_suppressed_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create false_positives table: %w", err)
+	}
+
+	// Create protected_items table (migration 013)
+	_, err = db.Exec(`
+		CREATE TABLE protected_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			file_path TEXT,
+			arr_instance_id INTEGER,
+			media_id INTEGER,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			CHECK (file_path IS NOT NULL OR (arr_instance_id IS NOT NULL AND media_id IS NOT NULL))
+		);
+		CREATE UNIQUE INDEX idx_protected_items_file_path ON protected_items(file_path) WHERE file_path IS NOT NULL;
+		CREATE UNIQUE INDEX idx_protected_items_media ON protected_items(arr_instance_id, media_id) WHERE file_path IS NULL;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create protected_items table: %w", err)
+	}
+
+	// Create reports table (migration 016)
+	_, err = db.Exec(`
+		CREATE TABLE reports (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			period_start TIMESTAMP NOT NULL,
+			period_end TIMESTAMP NOT NULL,
+			data TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create reports table: %w", err)
+	}
+
+	// Create corruption_quality_pins table (migration 017)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_quality_pins (
+			corruption_id TEXT PRIMARY KEY,
+			mode TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_quality_pins table: %w", err)
+	}
+
+	// Create corruption_deletion_plans table (migration 025)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_deletion_plans (
+			corruption_id TEXT PRIMARY KEY,
+			plan TEXT NOT NULL,
+			planned_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_deletion_plans table: %w", err)
+	}
+
+	// Create corruption_tags and saved_filters tables (migration 018)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_tags (
+			corruption_id TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (corruption_id, tag)
+		);
+		CREATE TABLE saved_filters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			statuses TEXT NOT NULL DEFAULT '[]',
+			tags TEXT NOT NULL DEFAULT '[]',
+			path_ids TEXT NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption tag tables: %w", err)
+	}
+
+	// Create corruption_links table (migration 036)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_links (
+			corruption_id TEXT NOT NULL,
+			relation TEXT NOT NULL,
+			link_key TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (corruption_id, relation, link_key)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_links table: %w", err)
+	}
+
+	// Create arr_request_usage table (migration 037)
+	_, err = db.Exec(`
+		CREATE TABLE arr_request_usage (
+			instance_id INTEGER NOT NULL REFERENCES arr_instances(id) ON DELETE CASCADE,
+			day TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 0,
+			deferred INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (instance_id, day)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create arr_request_usage table: %w", err)
+	}
+
+	// Create scan_summaries table (migration 041)
+	_, err = db.Exec(`
+		CREATE TABLE scan_summaries (
+			scan_id INTEGER PRIMARY KEY,
+			path TEXT NOT NULL,
 			path_id INTEGER,
-			current_state TEXT NOT NULL,
-			retry_count INTEGER DEFAULT 0,
-			corruption_type TEXT,
-			last_error TEXT,
-			detected_at TIMESTAMP NOT NULL,
-			last_updated_at TIMESTAMP NOT NULL
+			status TEXT NOT NULL,
+			files_scanned INTEGER NOT NULL DEFAULT 0,
+			total_files INTEGER NOT NULL DEFAULT 0,
+			corruptions_found INTEGER NOT NULL DEFAULT 0,
+			started_at TIMESTAMP,
+			completed_at TIMESTAMP
 		)
 	`)
 	if err != nil {
-		return fmt.Errorf("failed to create corruption_summary table: %w", err)
+		return fmt.Errorf("failed to create scan_summaries table: %w", err)
+	}
+
+	// Create corruption_mapping_overrides table (migration 042)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_mapping_overrides (
+			corruption_id TEXT PRIMARY KEY,
+			file_path TEXT NOT NULL,
+			arr_path TEXT NOT NULL,
+			media_id INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_mapping_overrides table: %w", err)
+	}
+
+	// Create corruption_samples table (migration 039)
+	_, err = db.Exec(`
+		CREATE TABLE corruption_samples (
+			corruption_id TEXT PRIMARY KEY,
+			file_path TEXT NOT NULL,
+			sample_path TEXT NOT NULL,
+			method TEXT NOT NULL,
+			offset_seconds REAL,
+			offset_bytes INTEGER,
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_samples table: %w", err)
+	}
+
+	// Create arr_health_samples table (migration 020)
+	_, err = db.Exec(`
+		CREATE TABLE arr_health_samples (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			instance_id INTEGER NOT NULL,
+			checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			reachable BOOLEAN NOT NULL,
+			response_ms INTEGER NOT NULL DEFAULT 0,
+			error TEXT,
+			FOREIGN KEY (instance_id) REFERENCES arr_instances(id) ON DELETE CASCADE
+		);
+		CREATE INDEX idx_arr_health_samples_instance ON arr_health_samples(instance_id, checked_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create arr_health_samples table: %w", err)
 	}
 
-	// Create corruption_status view (reads from events table for legacy compatibility)
-	// Most existing tests insert events and expect the view to reflect those changes
+	// Create corruption_status view, which computes the summaries from the
+	// events to rebuild and check corruption_summary
 	_, err = db.Exec(`
 		CREATE VIEW corruption_status AS
 		SELECT
@@ -220,8 +490,17 @@ func initializeSchema(db *sql.DB) error {
 			 WHERE e7.aggregate_id = e.aggregate_id
 			 AND e7.event_type = 'CorruptionDetected'
 			 LIMIT 1) as path_id,
-			(SELECT last_error FROM corruption_summary cs WHERE cs.corruption_id = e.aggregate_id LIMIT 1) as last_error,
-			(SELECT corruption_type FROM corruption_summary cs WHERE cs.corruption_id = e.aggregate_id LIMIT 1) as corruption_type,
+			(SELECT json_extract(event_data, '$.error') FROM events e5
+			 WHERE e5.aggregate_id = e.aggregate_id
+			 ORDER BY id DESC LIMIT 1) as last_error,
+			(SELECT json_extract(event_data, '$.corruption_type') FROM events e6
+			 WHERE e6.aggregate_id = e.aggregate_id
+			 AND e6.event_type = 'CorruptionDetected'
+			 LIMIT 1) as corruption_type,
+			COALESCE((SELECT json_extract(event_data, '$.media_type') FROM events e8
+			 WHERE e8.aggregate_id = e.aggregate_id
+			 AND e8.event_type = 'CorruptionDetected'
+			 LIMIT 1), 'video') as media_type,
 			MIN(created_at) as detected_at,
 			MAX(created_at) as last_updated_at
 		FROM events e
@@ -235,6 +514,64 @@ func initializeSchema(db *sql.DB) error {
 	return nil
 }
 
+// CreateCorruptionSummary creates the corruption_summary table (migrations 004,
+// 005) with a trigger that keeps it up to date. Tests insert events directly
+// rather than through the event bus, so the trigger stands in for its
+// corruption_summary projector.
+func CreateCorruptionSummary(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE corruption_summary (
+			corruption_id TEXT PRIMARY KEY,
+			file_path TEXT,
+			path_id INTEGER,
+			current_state TEXT NOT NULL,
+			retry_count INTEGER DEFAULT 0,
+			corruption_type TEXT,
+			media_type TEXT DEFAULT 'video',
+			last_error TEXT,
+			detected_at TIMESTAMP,
+			last_updated_at TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_summary table: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TRIGGER trg_project_corruption_summary
+		AFTER INSERT ON events
+		WHEN NEW.aggregate_type = 'corruption'
+		BEGIN
+			INSERT INTO corruption_summary (corruption_id, current_state, retry_count, file_path, path_id,
+				last_error, corruption_type, media_type, detected_at, last_updated_at)
+			VALUES (
+				NEW.aggregate_id, NEW.event_type,
+				CASE WHEN NEW.event_type LIKE '%Failed' THEN 1 ELSE 0 END,
+				CASE WHEN NEW.event_type = 'CorruptionDetected' THEN json_extract(NEW.event_data, '$.file_path') END,
+				CASE WHEN NEW.event_type = 'CorruptionDetected' THEN json_extract(NEW.event_data, '$.path_id') END,
+				json_extract(NEW.event_data, '$.error'),
+				CASE WHEN NEW.event_type = 'CorruptionDetected' THEN json_extract(NEW.event_data, '$.corruption_type') END,
+				COALESCE(CASE WHEN NEW.event_type = 'CorruptionDetected' THEN json_extract(NEW.event_data, '$.media_type') END, 'video'),
+				NEW.created_at, NEW.created_at
+			)
+			ON CONFLICT(corruption_id) DO UPDATE SET
+				current_state = excluded.current_state,
+				retry_count = corruption_summary.retry_count + excluded.retry_count,
+				file_path = COALESCE(excluded.file_path, corruption_summary.file_path),
+				path_id = COALESCE(excluded.path_id, corruption_summary.path_id),
+				last_error = excluded.last_error,
+				corruption_type = COALESCE(excluded.corruption_type, corruption_summary.corruption_type),
+				media_type = CASE WHEN excluded.current_state = 'CorruptionDetected'
+					THEN excluded.media_type ELSE corruption_summary.media_type END,
+				last_updated_at = excluded.last_updated_at;
+		END
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create corruption_summary trigger: %w", err)
+	}
+	return nil
+}
+
 // SeedEvent inserts a single event into the test database.
 func SeedEvent(db *sql.DB, event domain.Event) (int64, error) {
 	eventDataJSON, err := json.Marshal(event.EventData)
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExternalReference is a web asset loading something from another host.
+type ExternalReference struct {
+	File string `json:"file"`
+	URL  string `json:"url"`
+}
+
+// Patterns for loads a browser performs by itself when the UI opens. Plain
+// links and strings that merely contain a URL (placeholders, XML namespaces)
+// are not loads and are ignored.
+var (
+	htmlExternalRef = regexp.MustCompile(`(?i)<(?:script|link|img|iframe|source|video|audio)\b[^>]*?\s(?:src|href)\s*=\s*["']?((?:https?:)?//[^"'\s>]+)`)
+	cssExternalRef  = regexp.MustCompile(`(?i)(?:url\(\s*["']?|@import\s+["'])((?:https?:)?//[^"')\s]+)`)
+	jsExternalRef   = regexp.MustCompile("(?:import\\(|fetch\\(|new WebSocket\\(|new EventSource\\()\\s*[\"'`]((?:https?|wss?)://[^\"'`\\s]+)")
+)
+
+// FindExternalReferences walks fsys and returns every external resource the
+// HTML, CSS and JavaScript files in it load, sorted by file. An empty result
+// means the UI works without internet access.
+func FindExternalReferences(fsys fs.FS) ([]ExternalReference, error) {
+	refs := []ExternalReference{}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		var pattern *regexp.Regexp
+		switch strings.ToLower(path.Ext(name)) {
+		case ".html", ".htm":
+			pattern = htmlExternalRef
+		case ".css":
+			pattern = cssExternalRef
+		case ".js", ".mjs":
+			pattern = jsExternalRef
+		default:
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		for _, match := range pattern.FindAllSubmatch(data, -1) {
+			refs = append(refs, ExternalReference{File: name, URL: string(match[1])})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].File < refs[j].File })
+	return refs, nil
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindExternalReferences(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html": &fstest.MapFile{Data: []byte(`<!doctype html>
+<html><head>
+<link rel="stylesheet" href="https://fonts.googleapis.com/css2?family=Inter">
+<script type="module" src="/assets/index.js"></script>
+</head><body><a href="https://github.com/mescon/Healarr">GitHub</a></body></html>`)},
+		"assets/index.css": &fstest.MapFile{Data: []byte(`@import "//cdn.example.com/reset.css";
+.logo { background: url("/icons/logo.svg"); }
+.hero { background: url(https://images.example.com/hero.png); }`)},
+		"assets/index.js": &fstest.MapFile{Data: []byte(`const ns = "http://www.w3.org/2000/svg";
+const placeholder = 'https://discord.com/api/webhooks/...';
+fetch("/api/health");
+import("https://esm.sh/lodash");`)},
+		"icons/logo.svg": &fstest.MapFile{Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><image href="https://example.com/x.png"/></svg>`)},
+	}
+
+	refs, err := FindExternalReferences(fsys)
+	if err != nil {
+		t.Fatalf("FindExternalReferences() error = %v", err)
+	}
+
+	want := []ExternalReference{
+		{File: "assets/index.css", URL: "//cdn.example.com/reset.css"},
+		{File: "assets/index.css", URL: "https://images.example.com/hero.png"},
+		{File: "assets/index.js", URL: "https://esm.sh/lodash"},
+		{File: "index.html", URL: "https://fonts.googleapis.com/css2?family=Inter"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("FindExternalReferences() = %v, want %v", refs, want)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("refs[%d] = %v, want %v", i, refs[i], want[i])
+		}
+	}
+}
+
+func TestFindExternalReferences_SelfContained(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html": &fstest.MapFile{Data: []byte(`<script type="module" src="./assets/index.js"></script>`)},
+	}
+
+	refs, err := FindExternalReferences(fsys)
+	if err != nil {
+		t.Fatalf("FindExternalReferences() error = %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("FindExternalReferences() = %v, want none", refs)
+	}
+}